@@ -10,35 +10,37 @@ Includes excitatory, leak, inhibition, and dynamic potassium channels.
 */
 package chans
 
+import "github.com/emer/leabra/v2/fmath"
+
 //go:generate core generate -add-types
 
 // Chans are ion channels used in computing point-neuron activation function
 type Chans struct {
 
 	// excitatory sodium (Na) AMPA channels activated by synaptic glutamate
-	E float32
+	E fmath.Float
 
 	// constant leak (potassium, K+) channels -- determines resting potential (typically higher than resting potential of K)
-	L float32
+	L fmath.Float
 
 	// inhibitory chloride (Cl-) channels activated by synaptic GABA
-	I float32
+	I fmath.Float
 
 	// gated / active potassium channels -- typically hyperpolarizing relative to leak / rest
-	K float32
+	K fmath.Float
 }
 
 // SetAll sets all the values
-func (ch *Chans) SetAll(e, l, i, k float32) {
+func (ch *Chans) SetAll(e, l, i, k fmath.Float) {
 	ch.E, ch.L, ch.I, ch.K = e, l, i, k
 }
 
 // SetFromOtherMinus sets all the values from other Chans minus given value
-func (ch *Chans) SetFromOtherMinus(oth Chans, minus float32) {
+func (ch *Chans) SetFromOtherMinus(oth Chans, minus fmath.Float) {
 	ch.E, ch.L, ch.I, ch.K = oth.E-minus, oth.L-minus, oth.I-minus, oth.K-minus
 }
 
 // SetFromMinusOther sets all the values from given value minus other Chans
-func (ch *Chans) SetFromMinusOther(minus float32, oth Chans) {
+func (ch *Chans) SetFromMinusOther(minus fmath.Float, oth Chans) {
 	ch.E, ch.L, ch.I, ch.K = minus-oth.E, minus-oth.L, minus-oth.I, minus-oth.K
 }
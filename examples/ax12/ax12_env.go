@@ -0,0 +1,206 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// Stims are the 1-2-AX stimuli, in the order of the Input units.
+var Stims = []string{"1", "2", "A", "B", "C", "X", "Y", "Z"}
+
+const (
+	stimA = 2
+	stimX = 5
+)
+
+// AX12Env implements the 1-2-AX task: an outer loop starts with a 1 or 2
+// context digit, followed by a random number of inner loops of a letter
+// pair: A, B, or C followed by X, Y, or Z.  The target (R) response is
+// for X after A in context 1, and Y after B in context 2, and all other
+// stimuli require the non-target (L) response.
+type AX12Env struct {
+	// name of this environment
+	Name string
+
+	// maximum number of inner loops (letter pairs) per outer loop
+	NInner int
+
+	// probability that an inner loop is the target sequence for the context
+	PTarget float32
+
+	// value for reward, based on whether model output = target
+	RewVal float32
+
+	// value for non-reward
+	NoRewVal float32
+
+	// current outer context: 0 for 1, 1 for 2
+	Ctx int
+
+	// position in the sequence: 0 = context digit, 1 = first letter, 2 = second letter
+	Pos int
+
+	// number of inner loops remaining in the current outer loop
+	NLeft int
+
+	// current stimulus, as an index into Stims
+	Stim int
+
+	// first letter of the current inner loop
+	Prev int
+
+	// true if the correct response is the target (R)
+	Target bool
+
+	// stimulus input pattern
+	Input tensor.Float64
+
+	// output pattern of what to respond: L, R
+	Output tensor.Float64
+
+	// reward value
+	Reward tensor.Float64
+
+	// trial is the step counter within epoch
+	Trial env.Counter `display:"inline"`
+
+	// Rand is the random number stream for generating the sequence,
+	// which is seeded by the sim at the start of each run.
+	Rand randx.SysRand `display:"-" json:"-"`
+}
+
+func (ev *AX12Env) Label() string { return ev.Name }
+
+// Config initializes env with given max number of inner loops
+func (ev *AX12Env) Config(nInner int) {
+	ev.NInner = nInner
+	ev.Input.SetShape([]int{len(Stims)})
+	ev.Output.SetShape([]int{2})
+	ev.Reward.SetShape([]int{1})
+	if ev.PTarget == 0 {
+		ev.PTarget = 0.5
+	}
+	if ev.RewVal == 0 {
+		ev.RewVal = 1
+	}
+}
+
+func (ev *AX12Env) State(element string) tensor.Tensor {
+	switch element {
+	case "Input":
+		return &ev.Input
+	case "Output":
+		return &ev.Output
+	case "Rew":
+		return &ev.Reward
+	}
+	return nil
+}
+
+func (ev *AX12Env) Actions() env.Elements {
+	return nil
+}
+
+// String returns the current state as a string
+func (ev *AX12Env) String() string {
+	resp := "L"
+	if ev.Target {
+		resp = "R"
+	}
+	seq := Stims[ev.Stim]
+	if ev.Stim >= stimX {
+		seq = Stims[ev.Prev] + seq
+	}
+	return fmt.Sprintf("%s_%s_%s", Stims[ev.Ctx], seq, resp)
+}
+
+func (ev *AX12Env) Init(run int) {
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Pos = 0
+	ev.NLeft = 0
+}
+
+// RewTrial returns true if the current trial is the second letter of an
+// inner loop, which is rewarded for a correct response.
+func (ev *AX12Env) RewTrial() bool {
+	return ev.Stim >= stimX
+}
+
+// SetState sets the input, output states
+func (ev *AX12Env) SetState() {
+	ev.Input.SetZeros()
+	ev.Input.Values[ev.Stim] = 1
+	ev.Output.SetZeros()
+	if ev.Target {
+		ev.Output.Values[1] = 1
+	} else {
+		ev.Output.Values[0] = 1
+	}
+}
+
+// SetReward sets reward based on network's output
+func (ev *AX12Env) SetReward(netout int) bool {
+	rw := (netout == 1) == ev.Target
+	if rw {
+		ev.Reward.Values[0] = float64(ev.RewVal)
+	} else {
+		ev.Reward.Values[0] = float64(ev.NoRewVal)
+	}
+	return rw
+}
+
+// StepAX12 generates the next stimulus in the sequence.
+func (ev *AX12Env) StepAX12() {
+	ev.Target = false
+	switch ev.Pos {
+	case 0:
+		ev.Ctx = ev.Rand.Intn(2)
+		ev.Stim = ev.Ctx
+		ev.NLeft = 1 + ev.Rand.Intn(ev.NInner)
+		ev.Pos = 1
+	case 1:
+		if ev.Rand.Float32() < ev.PTarget {
+			ev.Stim = stimA + ev.Ctx
+		} else {
+			ev.Stim = stimA + ev.Rand.Intn(3)
+		}
+		ev.Prev = ev.Stim
+		ev.Pos = 2
+	case 2:
+		if ev.Prev == stimA+ev.Ctx && ev.Rand.Float32() < ev.PTarget {
+			ev.Stim = stimX + ev.Ctx
+		} else {
+			ev.Stim = stimX + ev.Rand.Intn(3)
+		}
+		ev.Target = ev.Prev == stimA+ev.Ctx && ev.Stim == stimX+ev.Ctx
+		ev.NLeft--
+		ev.Pos = 1
+		if ev.NLeft <= 0 {
+			ev.Pos = 0
+		}
+	}
+	ev.SetState()
+}
+
+func (ev *AX12Env) Step() bool {
+	ev.StepAX12()
+	ev.Trial.Incr()
+	return true
+}
+
+func (ev *AX12Env) Action(element string, input tensor.Tensor) {
+	// nop
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*AX12Env)(nil)
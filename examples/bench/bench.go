@@ -159,7 +159,7 @@ func TrainNet(net *leabra.Network, pats, epcLog *table.Table, epcs int) {
 			}
 			net.DWt()
 			net.WtFromDWt()
-			outCosDiff += outLay.CosDiff.Cos
+			outCosDiff += float32(outLay.CosDiff.Cos)
 			pSSE, pAvgSSE := outLay.MSE(0.5)
 			sse += pSSE
 			avgSSE += pAvgSSE
@@ -197,6 +197,7 @@ func main() {
 	var epochs int
 	var pats int
 	var units int
+	var threads int
 
 	flag.Usage = func() {
 		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
@@ -207,6 +208,7 @@ func main() {
 	flag.IntVar(&epochs, "epochs", 2, "number of epochs to run")
 	flag.IntVar(&pats, "pats", 10, "number of patterns per epoch")
 	flag.IntVar(&units, "units", 100, "number of units per layer -- uses NxN where N = sqrt(units)")
+	flag.IntVar(&threads, "threads", 1, "number of parallel threads for the Cycle computation -- 0 = number of processors")
 	flag.BoolVar(&Silent, "silent", false, "only report the final time")
 	flag.Parse()
 
@@ -215,6 +217,10 @@ func main() {
 	}
 
 	Net = leabra.NewNetwork("Bench")
+	Net.SetNThreads(threads)
+	if !Silent {
+		fmt.Printf("Using %v threads\n", Net.NThreads)
+	}
 	ConfigNet(Net, units)
 
 	Pats = &table.Table{}
@@ -0,0 +1,1023 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// cls runs a complementary learning systems (CLS) model, where a fast
+// hippocampus learns new items each simulated day, and a slow neocortex
+// learns them from the interleaved offline replay of all the items stored
+// in the hippocampus, during sleep at the end of each day, with the
+// consolidation of the memories measured over days.
+package main
+
+//go:generate core generate -add-types
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/enums"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/plot/plotcore"
+	"cogentcore.org/core/tensor/table"
+	"cogentcore.org/core/tree"
+	"github.com/emer/emergent/v2/egui"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/netview"
+	"github.com/emer/emergent/v2/params"
+	"github.com/emer/emergent/v2/patgen"
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+func main() {
+	sim := &Sim{}
+	sim.New()
+	sim.ConfigAll()
+	if sim.Config.GUI {
+		sim.RunGUI()
+	} else {
+		sim.RunNoGUI()
+	}
+}
+
+// ParamSets is the default set of parameters for the hippocampus --
+// Base is always applied, and others can be optionally selected to apply
+// on top of that
+var ParamSets = params.Sets{
+	"Base": {
+		{Sel: "Path", Desc: "keeping default params for generic prjns",
+			Params: params.Params{
+				"Path.Learn.Momentum.On": "true",
+				"Path.Learn.Norm.On":     "true",
+				"Path.Learn.WtBal.On":    "false",
+			}},
+		{Sel: ".EcCa1Path", Desc: "encoder projections -- no norm, moment",
+			Params: params.Params{
+				"Path.Learn.Lrate":        "0.04",
+				"Path.Learn.Momentum.On":  "false",
+				"Path.Learn.Norm.On":      "false",
+				"Path.Learn.WtBal.On":     "true",
+				"Path.Learn.XCal.SetLLrn": "false", // using bcm now, better
+			}},
+		{Sel: ".HippoCHL", Desc: "hippo CHL projections -- no norm, moment, but YES wtbal = sig better",
+			Params: params.Params{
+				"Path.CHL.Hebb":          "0.05",
+				"Path.Learn.Lrate":       "0.2",
+				"Path.Learn.Momentum.On": "false",
+				"Path.Learn.Norm.On":     "false",
+				"Path.Learn.WtBal.On":    "true",
+			}},
+		{Sel: ".PPath", Desc: "perforant path, new Dg error-driven EcCa1Path prjns",
+			Params: params.Params{
+				"Path.Learn.Momentum.On": "false",
+				"Path.Learn.Norm.On":     "false",
+				"Path.Learn.WtBal.On":    "true",
+				"Path.Learn.Lrate":       "0.15",
+			}},
+		{Sel: "#CA1ToECout", Desc: "extra strong from CA1 to ECout",
+			Params: params.Params{
+				"Path.WtScale.Abs": "4.0",
+			}},
+		{Sel: "#InputToECin", Desc: "one-to-one input to EC",
+			Params: params.Params{
+				"Path.Learn.Learn": "false",
+				"Path.WtInit.Mean": "0.8",
+				"Path.WtInit.Var":  "0.0",
+			}},
+		{Sel: "#ECoutToECin", Desc: "one-to-one out to in",
+			Params: params.Params{
+				"Path.Learn.Learn": "false",
+				"Path.WtInit.Mean": "0.9",
+				"Path.WtInit.Var":  "0.01",
+				"Path.WtScale.Rel": "0.5",
+			}},
+		{Sel: "#DGToCA3", Desc: "Mossy fibers: strong, non-learning",
+			Params: params.Params{
+				"Path.Learn.Learn": "false",
+				"Path.WtInit.Mean": "0.9",
+				"Path.WtInit.Var":  "0.01",
+				"Path.WtScale.Rel": "4",
+			}},
+		{Sel: "#CA3ToCA3", Desc: "CA3 recurrent cons",
+			Params: params.Params{
+				"Path.WtScale.Rel": "0.1",
+				"Path.Learn.Lrate": "0.1",
+			}},
+		{Sel: "#ECinToDG", Desc: "DG learning is surprisingly critical: maxed out fast, hebbian works best",
+			Params: params.Params{
+				"Path.Learn.Learn":       "true",
+				"Path.CHL.Hebb":          ".5",
+				"Path.CHL.SAvgCor":       "0.1",
+				"Path.CHL.MinusQ1":       "true",
+				"Path.Learn.Lrate":       "0.4",
+				"Path.Learn.Momentum.On": "false",
+				"Path.Learn.Norm.On":     "false",
+				"Path.Learn.WtBal.On":    "true",
+			}},
+		{Sel: "#CA3ToCA1", Desc: "Schaffer collaterals -- slower, less hebb",
+			Params: params.Params{
+				"Path.CHL.Hebb":          "0.01",
+				"Path.CHL.SAvgCor":       "0.4",
+				"Path.Learn.Lrate":       "0.1",
+				"Path.Learn.Momentum.On": "false",
+				"Path.Learn.Norm.On":     "false",
+				"Path.Learn.WtBal.On":    "true",
+			}},
+		{Sel: ".EC", Desc: "all EC layers: only pools, no layer-level",
+			Params: params.Params{
+				"Layer.Act.Gbar.L":        ".1",
+				"Layer.Inhib.ActAvg.Init": "0.2",
+				"Layer.Inhib.Layer.On":    "false",
+				"Layer.Inhib.Pool.Gi":     "2.0",
+				"Layer.Inhib.Pool.On":     "true",
+			}},
+		{Sel: "#DG", Desc: "very sparse = high inibhition",
+			Params: params.Params{
+				"Layer.Inhib.ActAvg.Init": "0.01",
+				"Layer.Inhib.Layer.Gi":    "3.8",
+			}},
+		{Sel: "#CA3", Desc: "sparse = high inibhition",
+			Params: params.Params{
+				"Layer.Inhib.ActAvg.Init": "0.02",
+				"Layer.Inhib.Layer.Gi":    "2.8",
+			}},
+		{Sel: "#CA1", Desc: "CA1 only Pools",
+			Params: params.Params{
+				"Layer.Inhib.ActAvg.Init": "0.1",
+				"Layer.Inhib.Layer.On":    "false",
+				"Layer.Inhib.Pool.Gi":     "2.4",
+				"Layer.Inhib.Pool.On":     "true",
+			}},
+	},
+}
+
+// CortexParams are the parameters of the neocortex, which learns slowly,
+// so that it integrates over the replay of many items.
+var CortexParams = params.Sheet{
+	{Sel: "Path", Desc: "slow learning",
+		Params: params.Params{
+			"Path.Learn.Lrate": "0.02",
+		}},
+	{Sel: ".BackPath", Desc: "weaker top-down",
+		Params: params.Params{
+			"Path.WtScale.Rel": "0.3",
+		}},
+	{Sel: "Layer", Desc: "sparse hidden",
+		Params: params.Params{
+			"Layer.Inhib.ActAvg.Init": "0.15",
+		}},
+	{Sel: ".EC", Desc: "EC pattern layers: only pools, as in the hippocampus",
+		Params: params.Params{
+			"Layer.Inhib.ActAvg.Init": "0.2",
+			"Layer.Inhib.Layer.On":    "false",
+			"Layer.Inhib.Pool.Gi":     "2.0",
+			"Layer.Inhib.Pool.On":     "true",
+		}},
+}
+
+// LogConfig has config parameters related to logging data
+type LogConfig struct {
+
+	// if true, save final weights after each run
+	SaveWeights bool
+
+	// if true, save train epoch log to file, as .epc.tsv typically
+	Epoch bool `default:"true" nest:"+"`
+
+	// if true, save run log to file, as .run.tsv typically
+	Run bool `default:"true" nest:"+"`
+
+	// if true, save train trial log to file, as .trl.tsv typically. May be large.
+	Trial bool `default:"false" nest:"+"`
+
+	// if true, save testing epoch log to file, as .tst_epc.tsv typically.
+	TestEpoch bool `default:"true" nest:"+"`
+
+	// if true, save testing trial log to file, as .tst_trl.tsv typically. May be large.
+	TestTrial bool `default:"false" nest:"+"`
+
+	// if true, save the consolidation metrics of each run to a
+	// _consol.tsv file (see leabra.ConsolMetrics).
+	Consol bool `default:"true"`
+
+	// file format of the log files: LogParquet saves compressed .parquet
+	// files, which are much smaller and faster to load for analysis
+	// than .tsv files (see leabra.LogFiles).
+	Format leabra.LogFormats
+
+	// number of rows per Parquet row group, which are buffered before
+	// writing them to the file (100 if 0).
+	RowGroup int
+}
+
+// Config has config parameters related to running the sim,
+// which can be set from a TOML or YAML config file and command-line
+// args (see leabra.Config), so that batch jobs are fully specified.
+type Config struct {
+
+	// specify include files here, and after configuration,
+	// it contains list of include files added.
+	Includes []string
+
+	// open the GUI -- does not automatically run -- if false,
+	// then runs automatically and quits.
+	GUI bool `default:"true"`
+
+	// Extra Param Sheet name(s) to use (space separated if multiple).
+	// must be valid name as listed in compiled-in params or loaded params
+	Sheet string
+
+	// extra tag to add to file names and logs saved from this run
+	Tag string
+
+	// user note -- describe the run params etc -- like a git commit message for the run
+	Note string
+
+	// data logging related configuration options
+	Log LogConfig `display:"add-fields"`
+
+	// total number of runs to do when running Train
+	NRuns int `default:"5" min:"1"`
+
+	// CLS has the number of simulated days, the epochs of hippocampal
+	// training on the new items of each day, and the number of replay
+	// trials training the cortex in the sleep of each day.
+	CLS leabra.CLSParams `display:"inline"`
+
+	// NItems is the number of new items learned each day.
+	NItems int `default:"10" min:"1"`
+
+	// Replay has the parameters for the offline hippocampal replay trials.
+	Replay leabra.ReplayParams `display:"inline"`
+
+	// Theta has the theta-phase modulation parameters for the hippocampus,
+	// which are copied to the network in ConfigNet.
+	Theta leabra.ThetaPhaseParams `display:"inline"`
+
+	// MemScore has the memory threshold criterion for the Mem stat.
+	MemScore leabra.MemScoreParams `display:"inline"`
+
+	// Readout is the readout transform applied to the ECout activity
+	// and target, and the Input cue, for computing memory stats.
+	Readout leabra.ReadoutParams `display:"inline"`
+}
+
+func (cfg *Config) IncludesPtr() *[]string { return &cfg.Includes }
+
+// Sim encapsulates the entire simulation model, and we define all the
+// functionality as methods on this struct.  This structure keeps all relevant
+// state information organized and available without having to pass everything around
+// as arguments to methods, and provides the core GUI interface (note the view tags
+// for the fields which provide hints to how things should be displayed).
+type Sim struct {
+
+	// simulation configuration parameters -- set by .toml config file and / or args
+	Config Config `new-window:"+"`
+
+	// the hippocampus network, which learns the new items of each day
+	Net *leabra.Network `new-window:"+" display:"no-inline"`
+
+	// the neocortex network, which learns from the hippocampal replay,
+	// with the same Input and Output shapes as ECout in the hippocampus.
+	Cortex *leabra.Network `new-window:"+" display:"no-inline"`
+
+	// references to the hippocampus layers
+	Layers leabra.HipLayers `display:"-"`
+
+	// all parameter management
+	Params emer.NetParams `display:"add-fields"`
+
+	// contains looper control loops for running sim
+	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
+
+	// log files for the nogui runs
+	LogFiles leabra.LogFiles `display:"-"`
+
+	// contains computed statistic values
+	Stats estats.Stats `new-window:"+"`
+
+	// Contains all the logs and information about the logs.'
+	Logs elog.Logs `new-window:"+"`
+
+	// TrainAll has the training items of all the days, in order,
+	// with NItems items for each day.
+	TrainAll *table.Table `new-window:"+" display:"no-inline"`
+
+	// TestAll has the testing items of all the days, which have
+	// the B pools of the items to recall empty in the Input.
+	TestAll *table.Table `new-window:"+" display:"no-inline"`
+
+	// StoredCA3 has the CA3 activity states recorded on each training
+	// trial, which cue the replay in the sleep of each day.
+	StoredCA3 leabra.NearestPatterns `display:"-"`
+
+	// Consol has the consolidation metrics of the current run over days.
+	Consol leabra.ConsolMetrics `display:"-"`
+
+	// Environments
+	Envs env.Envs `new-window:"+" display:"no-inline"`
+
+	// leabra timing parameters and state
+	Context leabra.Context `new-window:"+"`
+
+	// netview update parameters
+	ViewUpdate netview.ViewUpdate `display:"add-fields"`
+
+	// manages all the gui elements
+	GUI egui.GUI `display:"-"`
+
+	// the view of the Cortex network
+	CortexView *netview.NetView `display:"-"`
+
+	// a list of random seeds to use for each run
+	RandSeeds randx.Seeds `display:"-"`
+}
+
+// New creates new blank elements and initializes defaults
+func (ss *Sim) New() {
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
+	ss.Net = leabra.NewNetwork("Hip")
+	ss.Cortex = leabra.NewNetwork("Cortex")
+	ss.Params.Config(ParamSets, ss.Config.Sheet, ss.Config.Tag, ss.Net)
+	ss.Stats.Init()
+	ss.TrainAll = &table.Table{}
+	ss.TestAll = &table.Table{}
+	ss.RandSeeds.Init(100) // max 100 runs
+	ss.InitRandSeed(0)
+	ss.Context.Defaults()
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// 		Configs
+
+// Config configures all the elements using the standard functions
+func (ss *Sim) ConfigAll() {
+	ss.ConfigPats()
+	ss.ConfigEnv()
+	ss.ConfigNet(ss.Net)
+	ss.ConfigCortex(ss.Cortex)
+	ss.ConfigLogs()
+	ss.ConfigLoops()
+}
+
+func (ss *Sim) ConfigEnv() {
+	// Can be called multiple times -- don't re-create
+	var trn, tst *env.FixedTable
+	if len(ss.Envs) == 0 {
+		trn = &env.FixedTable{}
+		tst = &env.FixedTable{}
+	} else {
+		trn = ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+		tst = ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	}
+
+	// note: names must be standard here!
+	trn.Name = etime.Train.String()
+	trn.Config(ss.DayItems(ss.TrainAll, 0, 1))
+	trn.Validate()
+
+	tst.Name = etime.Test.String()
+	tst.Config(ss.DayItems(ss.TestAll, 0, 1))
+	tst.Sequential = true
+	tst.Validate()
+
+	trn.Init(0)
+	tst.Init(0)
+
+	// note: names must be in place when adding
+	ss.Envs.Add(trn, tst)
+}
+
+// DayItems returns an IndexView of the items of the given table
+// for n days starting at the given day.
+func (ss *Sim) DayItems(dt *table.Table, day, n int) *table.IndexView {
+	ni := ss.Config.NItems
+	ix := table.NewIndexView(dt)
+	ix.Indexes = ix.Indexes[min(day*ni, dt.Rows):min((day+n)*ni, dt.Rows)]
+	return ix
+}
+
+func (ss *Sim) ConfigNet(net *leabra.Network) {
+	net.SetRandSeed(ss.RandSeeds[0]) // init new separate random seed, using run = 0
+
+	in := net.AddLayer4D("Input", 6, 2, 3, 4, leabra.InputLayer)
+	ecin := net.AddLayer4D("ECin", 6, 2, 3, 4, leabra.SuperLayer)
+	ecout := net.AddLayer4D("ECout", 6, 2, 3, 4, leabra.TargetLayer) // clamped in plus phase
+	ca1 := net.AddLayer4D("CA1", 6, 2, 4, 10, leabra.SuperLayer)
+	dg := net.AddLayer2D("DG", 25, 25, leabra.SuperLayer)
+	ca3 := net.AddLayer2D("CA3", 30, 10, leabra.SuperLayer)
+
+	ecin.AddClass("EC")
+	ecout.AddClass("EC")
+
+	onetoone := paths.NewOneToOne()
+	pool1to1 := paths.NewPoolOneToOne()
+	full := paths.NewFull()
+
+	net.ConnectLayers(in, ecin, onetoone, leabra.ForwardPath)
+	net.ConnectLayers(ecout, ecin, onetoone, leabra.BackPath)
+
+	// EC <-> CA1 encoder pathways
+	net.ConnectLayers(ecin, ca1, pool1to1, leabra.EcCa1Path)
+	net.ConnectLayers(ca1, ecout, pool1to1, leabra.EcCa1Path)
+	net.ConnectLayers(ecout, ca1, pool1to1, leabra.EcCa1Path)
+
+	// Perforant pathway
+	ppath := paths.NewUniformRand()
+	ppath.PCon = 0.25
+
+	net.ConnectLayers(ecin, dg, ppath, leabra.CHLPath).AddClass("HippoCHL")
+
+	net.ConnectLayers(ecin, ca3, ppath, leabra.EcCa1Path).AddClass("PPath")
+	net.ConnectLayers(ca3, ca3, full, leabra.EcCa1Path).AddClass("PPath")
+
+	// Mossy fibers
+	mossy := paths.NewUniformRand()
+	mossy.PCon = 0.02
+	net.ConnectLayers(dg, ca3, mossy, leabra.CHLPath).AddClass("HippoCHL")
+
+	// Schafer collaterals
+	net.ConnectLayers(ca3, ca1, full, leabra.CHLPath).AddClass("HippoCHL")
+
+	ecin.PlaceRightOf(in, 2)
+	ecout.PlaceRightOf(ecin, 2)
+	dg.PlaceAbove(in)
+	ca3.PlaceAbove(dg)
+	ca1.PlaceRightOf(ca3, 2)
+
+	net.Build()
+	errors.Log(net.LayerRefs(&ss.Layers))
+	net.Defaults()
+	net.Theta = ss.Config.Theta
+	ss.ApplyParams()
+	net.InitWeights()
+}
+
+// ConfigCortex configures the neocortex network that learns from the
+// hippocampal replay, sharing the EC representation of the hippocampus:
+// its Input and Output layers have the same shape as ECout, with the
+// Input receiving the cue pools of the replayed ECout patterns, and the
+// Output learning to recall the full patterns.
+func (ss *Sim) ConfigCortex(net *leabra.Network) {
+	net.SetRandSeed(ss.RandSeeds[0])
+	in := net.AddLayer4D("Input", 6, 2, 3, 4, leabra.InputLayer)
+	hid := net.AddLayer2D("Hidden", 15, 15, leabra.SuperLayer)
+	out := net.AddLayer4D("Output", 6, 2, 3, 4, leabra.TargetLayer)
+	in.AddClass("EC")
+	out.AddClass("EC")
+	full := paths.NewFull()
+	net.ConnectLayers(in, hid, full, leabra.ForwardPath)
+	net.BidirConnectLayers(hid, out, full)
+	hid.PlaceRightOf(in, 2)
+	out.PlaceRightOf(hid, 2)
+	net.Build()
+	net.Defaults()
+	ss.ApplyParams()
+	net.InitWeights()
+}
+
+// ApplyParams applies the params to the hippocampus, and the
+// CortexParams to the cortex, once it has been built.
+func (ss *Sim) ApplyParams() {
+	ss.Params.Network = ss.Net
+	ss.Params.SetAll()
+	if ss.Cortex.NumLayers() > 0 {
+		_, err := ss.Cortex.ApplyParams(&CortexParams, false)
+		errors.Log(err)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// 	    Init, utils
+
+// Init restarts the run, and initializes everything, including network weights
+// and resets the epoch log table
+func (ss *Sim) Init() {
+	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // in case user interactively changes tag
+	ss.Loops.ResetCounters()
+
+	ss.GUI.StopNow = false
+	ss.ApplyParams()
+	ss.NewRun()
+	ss.ViewUpdate.RecordSyns()
+	ss.ViewUpdate.Update()
+}
+
+func (ss *Sim) TestInit() {
+	ss.Loops.InitMode(etime.Test)
+	tst := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	tst.Init(0)
+}
+
+// InitRandSeed initializes the random seed based on current training run number
+func (ss *Sim) InitRandSeed(run int) {
+	rand.Seed(ss.RandSeeds[run])
+	ss.RandSeeds.Set(run)
+	ss.RandSeeds.Set(run, &ss.Net.Rand)
+	patgen.NewRand(ss.RandSeeds[run])
+}
+
+// ConfigLoops configures the control loops: Training, Testing
+func (ss *Sim) ConfigLoops() {
+	ls := looper.NewStacks()
+
+	cp := &ss.Config.CLS
+	trls := ss.Config.NItems
+	ttrls := ss.TestAll.Rows
+
+	ls.AddStack(etime.Train).AddTime(etime.Run, ss.Config.NRuns).AddTime(etime.Epoch, cp.NEpochs()).AddTime(etime.Trial, trls).AddTime(etime.Cycle, 100)
+
+	ls.AddStack(etime.Test).AddTime(etime.Epoch, 1).AddTime(etime.Trial, ttrls).AddTime(etime.Cycle, 100)
+
+	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
+	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+	ss.Net.ConfigLoopsHip(&ss.Context, ls)
+
+	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
+	ls.Stacks[etime.Test].OnInit.Add("Init", func() { ss.TestInit() })
+
+	for _, st := range ls.Stacks {
+		st.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
+			ss.ApplyInputs()
+		})
+	}
+
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
+
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
+		ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
+		leabra.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWeights, ctrString, ss.Stats.String("RunName"))
+	})
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveConsol", ss.SaveConsol)
+
+	// the cortex is tested on each item after the hippocampus
+	ls.Loop(etime.Test, etime.Trial).OnEnd.Add("TestCortex", ss.TestCortex)
+
+	// sleep and test at the end of each day, then start the next day
+	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnEnd.Add("EndDay", func() {
+		if cp.SleepEpoch(trainEpoch.Counter.Cur) {
+			ss.EndDay(cp.Day(trainEpoch.Counter.Cur))
+		}
+	})
+
+	/////////////////////////////////////////////
+	// Logging
+
+	ls.AddOnEndToAll("Log", func(mode, time enums.Enum) {
+		ss.Log(mode.(etime.Modes), time.(etime.Times))
+	})
+	leabra.LooperResetLogBelow(ls, &ss.Logs)
+
+	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
+	leabra.LooperUpdatePlots(ls, &ss.GUI)
+
+	ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+
+	ss.Loops = ls
+	fmt.Println(ls.DocString())
+}
+
+// ApplyInputs applies input patterns from given environment.
+// It is good practice to have this be a separate method with appropriate
+// args so that it can be used for various different contexts
+// (training, testing, etc).
+func (ss *Sim) ApplyInputs() {
+	ctx := &ss.Context
+	net := ss.Net
+	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
+	ecout := ss.Layers.ECout
+	if ctx.Mode == etime.Train {
+		ecout.Type = leabra.TargetLayer // clamp a plus phase during testing
+	} else {
+		ecout.Type = leabra.CompareLayer // don't clamp
+	}
+	ecout.UpdateExtFlags() // call this after updating type
+	ev.Step()
+	// note: must save env state for logging / stats due to data parallel re-use of same env
+	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
+	errors.Log(net.ApplyEnvInputs(ev))
+}
+
+// NewRun intializes a new run of the model, using the TrainEnv.Run counter
+// for the new run value, with new items for each day.
+func (ss *Sim) NewRun() {
+	ctx := &ss.Context
+	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
+	ss.ConfigPats()
+	ss.ConfigEnv()
+	ctx.Reset()
+	ctx.Mode = etime.Train
+	ss.Net.InitWeights()
+	ss.Cortex.InitWeights()
+	ss.StoredCA3.Reset()
+	ss.Consol.Init()
+	ss.Logs.MiscTables["Consol"] = ss.Consol.Table
+	ss.InitStats()
+	ss.StatCounters()
+	ss.Logs.ResetLog(etime.Train, etime.Epoch)
+	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+}
+
+// RunTestAll tests the hippocampus and cortex on all the items learned
+// up to the end of the given day.
+func (ss *Sim) RunTestAll(day int) {
+	tst := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	tst.Config(ss.DayItems(ss.TestAll, 0, day+1))
+	ss.Loops.Loop(etime.Test, etime.Trial).Counter.Max = tst.Table.Len()
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+}
+
+// EndDay ends the given day with the sleep phase (Sleep), then tests the
+// hippocampus and cortex on all the items learned so far, recording the
+// consolidation metrics for each day's items, and configures the training
+// environment with the new items of the next day.
+func (ss *Sim) EndDay(day int) {
+	ss.Sleep()
+	ss.RunTestAll(day)
+	ss.ConsolStats(day)
+	if day+1 < ss.Config.CLS.Days {
+		trn := ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+		trn.Config(ss.DayItems(ss.TrainAll, day+1, 1))
+	}
+	if ss.CortexView != nil {
+		ss.CortexView.GoUpdateView()
+	}
+}
+
+// Sleep runs the CLS.Replays offline hippocampal replay trials of the
+// sleep phase, each cued by the CA3 pattern of an item chosen at random
+// from all the items stored so far, and trains the cortex on the replayed
+// ECout patterns (ReplayToCortex), so that the cortex learns the items
+// of all the days interleaved with each other.
+func (ss *Sim) Sleep() {
+	if ss.StoredCA3.Len() == 0 {
+		return
+	}
+	ctx := leabra.NewContext()
+	ctx.Mode = etime.Test
+	rnd := randx.NewGlobalRand()
+	in := ss.Cortex.LayerByName("Input")
+	out := ss.Cortex.LayerByName("Output")
+	mask := ss.cueMask()
+	var ecout []float32
+	for range ss.Config.CLS.Replays {
+		pi := rnd.Intn(ss.StoredCA3.Len())
+		ss.Net.ReplayToCortex(ctx, &ss.Config.Replay, ss.StoredCA3.Pats[pi], ss.Cortex, in, out, mask, &ecout)
+	}
+}
+
+// TestCortex tests the cortex on the current test item, recording the
+// MemCorrel of its output as the CortexMemCorrel stat.
+func (ss *Sim) TestCortex() {
+	ev := ss.Envs.ByMode(etime.Test).(*env.FixedTable)
+	ctx := leabra.NewContext()
+	ctx.Mode = etime.Test
+	in := ss.Cortex.LayerByName("Input")
+	out := ss.Cortex.LayerByName("Output")
+	mc := ss.Cortex.MemCorrelTrial(ctx, in, out, ev.State("Input"), ev.State("ECout"))
+	ss.Stats.SetFloat("CortexMemCorrel", float64(mc))
+}
+
+// cueMask returns a mask of the units in the ECout patterns that are
+// part of the cue, i.e., not in the pools that are empty in the
+// test inputs, which are the ones to be recalled.
+func (ss *Sim) cueMask() []bool {
+	inp := ss.TestAll.Tensor("Input", 0)
+	nu := inp.Len()
+	npl := inp.Shape().DimSize(2) * inp.Shape().DimSize(3)
+	mask := make([]bool, nu)
+	for st := 0; st < nu; st += npl {
+		on := false
+		for i := st; i < st+npl; i++ {
+			on = on || inp.Float1D(i) > 0
+		}
+		for i := st; i < st+npl; i++ {
+			mask[i] = on
+		}
+	}
+	return mask
+}
+
+/////////////////////////////////////////////////////////////////////////
+//   Pats
+
+// ConfigPats generates the items of each day: each item has A pools
+// (the first 4) and B pools (the next 4), and the context pools (the
+// last 4) of its day, which are the same for all the items of the day,
+// with a few bits flipped for each item.  The test items have empty
+// B pools in the Input, as the cue to recall them.
+func (ss *Sim) ConfigPats() {
+	ecY, ecX := 6, 2 // pools
+	plY, plX := 3, 4 // units per pool
+	nday := ss.Config.CLS.Days
+	nitm := ss.Config.NItems
+	npats := nday * nitm
+	pctAct := float32(.25)
+	minDiff := float32(.3)
+	nOn := patgen.NFromPct(pctAct, plY*plX)
+	ctxtflip := patgen.NFromPct(0.2, nOn)
+
+	voc := patgen.Vocab{}
+	patgen.AddVocabEmpty(voc, "empty", npats, plY, plX)
+	ctxt, _ := patgen.AddVocabPermutedBinary(voc, "ctxt", nday, plY, plX, pctAct, minDiff)
+	var trnPools, tstPools []string
+	for _, ab := range []string{"A", "B"} {
+		for i := range 4 {
+			nm := fmt.Sprintf("%s%d", ab, i+1)
+			patgen.AddVocabPermutedBinary(voc, nm, npats, plY, plX, pctAct, minDiff)
+			trnPools = append(trnPools, nm)
+			if ab == "A" {
+				tstPools = append(tstPools, nm)
+			} else {
+				tstPools = append(tstPools, "empty")
+			}
+		}
+	}
+	for i := range 4 {
+		nm := fmt.Sprintf("ctxt%d", i+1)
+		tsr, _ := patgen.AddVocabEmpty(voc, nm, npats, plY, plX)
+		for pi := range npats {
+			tsr.SubSpace([]int{pi}).CopyFrom(ctxt.SubSpace([]int{pi / nitm}))
+		}
+		patgen.FlipBitsRows(tsr, ctxtflip, ctxtflip, 1, 0)
+		trnPools = append(trnPools, nm)
+		tstPools = append(tstPools, nm)
+	}
+
+	patgen.InitPats(ss.TrainAll, "TrainAll", "All Training Patterns", "Input", "ECout", npats, ecY, ecX, plY, plX)
+	patgen.MixPats(ss.TrainAll, voc, "Input", trnPools)
+	patgen.MixPats(ss.TrainAll, voc, "ECout", trnPools)
+
+	patgen.InitPats(ss.TestAll, "TestAll", "All Testing Patterns", "Input", "ECout", npats, ecY, ecX, plY, plX)
+	patgen.MixPats(ss.TestAll, voc, "Input", tstPools)
+	patgen.MixPats(ss.TestAll, voc, "ECout", trnPools)
+
+	for pi := range npats {
+		nm := fmt.Sprintf("d%d_%d", pi/nitm, pi%nitm)
+		ss.TrainAll.SetString("Name", pi, nm)
+		ss.TestAll.SetString("Name", pi, nm)
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// 		Stats
+
+// InitStats initializes all the statistics.
+// called at start of new run
+func (ss *Sim) InitStats() {
+	ss.Stats.SetString("TrialName", "")
+	ss.Stats.SetFloat("Mem", 0.0)
+	ss.Stats.SetFloat("MemCorrel", 0.0)
+	ss.Stats.SetFloat("CortexMemCorrel", 0.0)
+	ss.Stats.SetInt("Day", 0)
+	for _, st := range []string{"HipMem", "CortexMem", "Consol", "Retention", "OldCortexMem"} {
+		ss.Stats.SetFloat(st, math.NaN())
+	}
+}
+
+// StatCounters saves current counters to Stats, so they are available for logging etc
+// Also saves a string rep of them for ViewUpdate.Text
+func (ss *Sim) StatCounters() {
+	ctx := &ss.Context
+	mode := ctx.Mode
+	ss.Loops.Stacks[mode].CountersToStats(&ss.Stats)
+	// always use training epoch..
+	trnEpc := ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+	ss.Stats.SetInt("Epoch", trnEpc)
+	ss.Stats.SetInt("Day", ss.Config.CLS.Day(trnEpc))
+	ss.Stats.SetInt("Cycle", int(ctx.Cycle))
+}
+
+func (ss *Sim) NetViewCounters(tm etime.Times) {
+	if ss.ViewUpdate.View == nil {
+		return
+	}
+	if tm == etime.Trial {
+		ss.TrialStats() // get trial stats for current di
+	}
+	ss.StatCounters()
+	ss.ViewUpdate.Text = ss.Stats.Print([]string{"Run", "Day", "Epoch", "Trial", "TrialName", "Cycle"})
+}
+
+// TrialStats computes the memory stats of the hippocampus: Mem and
+// MemCorrel of the ECout recall, and records the CA3 activity of each
+// training trial, for cueing the replay.
+func (ss *Sim) TrialStats() {
+	mode := ss.Loops.Mode.(etime.Modes)
+	test := mode == etime.Test
+	ms := leabra.MemStats(ss.Layers.ECout, ss.Layers.Input, &ss.Config.Readout, ss.Config.MemScore.Thr, ss.Stats.String("TrialName"), test)
+	ss.Stats.SetFloat("Mem", ms["Mem"])
+	ss.Stats.SetFloat("MemCorrel", ms["MemCorrel"])
+	if mode == etime.Train {
+		var ca3Act []float32
+		ss.Layers.CA3.UnitValues(&ca3Act, "ActM", 0)
+		ss.StoredCA3.Set(ss.Stats.String("TrialName"), ca3Act)
+	}
+}
+
+// ConsolStats adds the mean hippocampal (MemCorrel) and cortical
+// (CortexMemCorrel) memory of the items of each day, from the test
+// trial log, to the consolidation metrics for the given day, and sets
+// the stats for the day from them.
+func (ss *Sim) ConsolStats(day int) {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	hip := make([]float64, day+1)
+	ctx := make([]float64, day+1)
+	n := make([]int, day+1)
+	for ri := range dt.Rows {
+		set, _, _ := strings.Cut(dt.StringValue("TrialName", ri), "_")
+		var d int
+		if _, err := fmt.Sscanf(set, "d%d", &d); err != nil || d > day {
+			continue
+		}
+		hip[d] += dt.Float("MemCorrel", ri)
+		ctx[d] += dt.Float("CortexMemCorrel", ri)
+		n[d]++
+	}
+	for d := range day + 1 {
+		if n[d] == 0 {
+			continue
+		}
+		ss.Consol.Add(day, fmt.Sprintf("d%d", d), hip[d]/float64(n[d]), ctx[d]/float64(n[d]))
+	}
+	ss.Consol.DayStats(day).SetStats(&ss.Stats)
+	if plt := ss.GUI.Plots[etime.ScopeKey("Consol")]; plt != nil {
+		plt.SetTable(ss.Consol.Table)
+		plt.GoUpdatePlot()
+	}
+}
+
+// SaveConsol saves the consolidation metrics of the run
+// to a _consol.tsv file, in nogui runs.
+func (ss *Sim) SaveConsol() {
+	if ss.Config.GUI || !ss.Config.Log.Consol || ss.Consol.Table == nil {
+		return
+	}
+	ctrString := ss.Stats.PrintValues([]string{"Run"}, []string{"%03d"}, "_")
+	fnm := elog.LogFilename("consol", ss.Net.Name, ss.Stats.String("RunName")+"_"+ctrString)
+	errors.Log(ss.Consol.Table.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// 		Logging
+
+func (ss *Sim) ConfigLogs() {
+	leabra.LogAddStdItems(&ss.Logs, &ss.Stats, ss.Params.RunName(0))
+
+	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Epoch, "Day")
+	ss.Logs.AddStatAggItem("Mem", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("MemCorrel", etime.Run, etime.Epoch, etime.Trial)
+	cmc := ss.Logs.AddItem(&elog.Item{
+		Name: "CortexMemCorrel",
+		Type: reflect.Float64,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Test, etime.Trial): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.Stats.Float("CortexMemCorrel"))
+			}}})
+	ss.Logs.AddStdAggs(cmc, etime.Test, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatFloatNoAggItem(etime.Train, etime.Epoch, "HipMem", "CortexMem", "Consol", "Retention", "OldCortexMem")
+	ss.Logs.AddStatFloatNoAggItem(etime.Train, etime.Run, "HipMem", "CortexMem", "Consol", "Retention", "OldCortexMem")
+	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
+
+	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "TargetLayer")
+
+	ss.Logs.PlotItems("HipMem", "CortexMem", "Consol", "MemCorrel")
+
+	leabra.LogConfigStdTables(&ss.Logs, &ss.Stats, ss.Net, etime.Scope(etime.Test, etime.Cycle))
+	ss.Logs.SetMeta(etime.Train, etime.Run, "Type", "Bar")
+	ss.Logs.SetMeta(etime.Train, etime.Epoch, "MemCorrel:On", "-")
+	ss.Logs.SetMeta(etime.Train, etime.Epoch, "Mem:On", "-")
+}
+
+// Log is the main logging function, handles special things for different scopes
+func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
+	ctx := &ss.Context
+	if mode != etime.Analyze {
+		ctx.Mode = mode // Also set specifically in a Loop callback.
+	}
+	dt := ss.Logs.Table(mode, time)
+	if dt == nil {
+		return
+	}
+	row := dt.Rows
+
+	switch {
+	case time == etime.Cycle:
+		return
+	case time == etime.Trial:
+		ss.TrialStats()
+		ss.StatCounters()
+	}
+
+	ss.LogFiles.LogRow(&ss.Logs, mode, time, row) // also logs to file, etc
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////
+// 		Gui
+
+// ConfigGUI configures the Cogent Core GUI interface for this simulation.
+func (ss *Sim) ConfigGUI() {
+	title := "Complementary Learning Systems"
+	ss.GUI.MakeBody(ss, "cls", title, `runs a complementary learning systems model, where a fast hippocampus learns new items each day, and a slow neocortex learns from their interleaved replay during sleep. See <a href="https://github.com/emer/leabra/blob/main/examples/cls/README.md">README.md on GitHub</a>.</p>`)
+	ss.GUI.CycleUpdateInterval = 10
+
+	nv := ss.GUI.AddNetView("Network")
+	nv.Options.Raster.Max = 100
+	nv.Options.MaxRecs = 300
+	nv.SetNet(ss.Net)
+	ss.ViewUpdate.Config(nv, etime.Phase, etime.Phase)
+	ss.GUI.ViewUpdate = &ss.ViewUpdate
+
+	ss.CortexView = ss.GUI.AddNetView("Cortex")
+	ss.CortexView.SetNet(ss.Cortex)
+
+	ss.GUI.AddPlots(title, &ss.Logs)
+
+	stnm := "Consol"
+	dt := ss.Logs.MiscTable(stnm)
+	bcp, _ := ss.GUI.Tabs.NewTab(stnm + " Plot")
+	plt := plotcore.NewSubPlot(bcp)
+	ss.GUI.Plots[etime.ScopeKey(stnm)] = plt
+	plt.Options.Title = "Consolidation over days"
+	plt.Options.XAxis = "Day"
+	plt.Options.Legend = "Set"
+	plt.SetTable(dt)
+
+	ss.GUI.FinalizeGUI(false)
+}
+
+func (ss *Sim) MakeToolbar(p *tree.Plan) {
+	ss.GUI.AddLooperCtrl(p, ss.Loops)
+
+	tree.Add(p, func(w *core.Separator) {})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
+		Icon:    icons.Reset,
+		Tooltip: "Reset the accumulated log of all Runs, which are tagged with the ParamSet used",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.Logs.ResetLog(etime.Train, etime.Run)
+			ss.GUI.UpdatePlot(etime.Train, etime.Run)
+		},
+	})
+	////////////////////////////////////////////////
+	tree.Add(p, func(w *core.Separator) {})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
+		Icon:    icons.Add,
+		Tooltip: "Generate a new initial random seed to get different results.  By default, Init re-establishes the same initial seed every time.",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.RandSeeds.NewSeeds()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "README",
+		Icon:    icons.FileMarkdown,
+		Tooltip: "Opens your browser on the README file that contains instructions for how to run this model.",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			core.TheApp.OpenURL("https://github.com/emer/leabra/blob/main/examples/cls/README.md")
+		},
+	})
+}
+
+func (ss *Sim) RunGUI() {
+	ss.Init()
+	ss.ConfigGUI()
+	ss.GUI.Body.RunMainWindow()
+}
+
+func (ss *Sim) RunNoGUI() {
+	if ss.Config.Note != "" {
+		fmt.Printf("Note: %s\n", ss.Config.Note)
+	}
+	if ss.Config.Log.SaveWeights {
+		fmt.Printf("Saving final weights per run\n")
+	}
+	runName := ss.Params.RunName(0)
+	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
+	netName := ss.Net.Name
+
+	ss.LogFiles.Format = ss.Config.Log.Format
+	ss.LogFiles.RowGroup = ss.Config.Log.RowGroup
+	ss.LogFiles.SetStdLogFiles(&ss.Logs, &ss.Config.Log, netName, runName)
+
+	ss.Init()
+
+	fmt.Printf("Running %d Runs\n", ss.Config.NRuns)
+	ss.Loops.Run(etime.Train)
+
+	ss.LogFiles.CloseLogFiles(&ss.Logs)
+}
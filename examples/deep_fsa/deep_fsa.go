@@ -3,7 +3,8 @@
 // license that can be found in the LICENSE file.
 
 // deep_fsa runs a DeepLeabra network on the classic Reber grammar
-// finite state automaton problem.
+// finite state automaton problem, or on the embedded Reber grammar
+// or a random Reber-like grammar.
 package main
 
 //go:generate core generate -add-types
@@ -20,7 +21,6 @@ import (
 	"cogentcore.org/core/math32/vecint"
 	"cogentcore.org/core/tensor/table"
 	"cogentcore.org/core/tree"
-	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -112,6 +112,20 @@ type ParamConfig struct {
 	// size of hidden layer -- can use emer.LaySize for 4D layers
 	Hidden2Size vecint.Vector2i `default:"{'X':7,'Y':7}" nest:"+"`
 
+	// CtxtLag is the proportion of the prior temporal context that is
+	// retained in the HiddenCT layer at each trial, for context spanning
+	// multiple trials, e.g., for the Embedded grammar (see leabra.CtxtParams).
+	CtxtLag float32
+
+	// LearnCtxtLag learns the context lag of each HiddenCT neuron,
+	// starting from CtxtLag.
+	LearnCtxtLag bool
+
+	// if true, the prediction error of the HiddenP pulvinar layer
+	// modulates the gain of the Hidden layer on the next trial, as
+	// predictive attention (see leabra.AttnParams).
+	Attn bool
+
 	// Extra Param Sheet name(s) to use (space separated if multiple).
 	// must be valid name as listed in compiled-in params or loaded params
 	Sheet string
@@ -136,6 +150,23 @@ type ParamConfig struct {
 	Good bool `nest:"+"`
 }
 
+// EnvConfig has config parameters related to the environment
+type EnvConfig struct {
+
+	// Grammar is the finite state grammar that generates the sequences:
+	// Reber for the standard Reber grammar, Embedded for the embedded
+	// Reber grammar, with long-distance dependencies, or Random for
+	// a random Reber-like grammar with RandomStates internal states.
+	Grammar string `default:"Reber"`
+
+	// RandomStates is the number of internal states of the Random grammar.
+	RandomStates int `default:"6" min:"2"`
+
+	// RandomSeed is the random seed for generating the Random grammar,
+	// which is the same for all runs.
+	RandomSeed int64 `default:"1"`
+}
+
 // RunConfig has config parameters related to running the sim
 type RunConfig struct {
 	// starting run number, which determines the random seed.
@@ -217,6 +248,9 @@ type Config struct {
 	// parameter related configuration options
 	Params ParamConfig `display:"add-fields"`
 
+	// environment related configuration options
+	Env EnvConfig `display:"add-fields"`
+
 	// sim running related configuration options
 	Run RunConfig `display:"add-fields"`
 
@@ -251,6 +285,9 @@ type Sim struct {
 	// Contains all the logs and information about the logs.'
 	Logs elog.Logs `new-window:"+"`
 
+	// the files that the logs are saved to in nogui runs
+	LogFiles leabra.LogFiles `display:"-"`
+
 	// the training patterns to use
 	Patterns *table.Table `new-window:"+" display:"no-inline"`
 
@@ -272,7 +309,7 @@ type Sim struct {
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
-	econfig.Config(&ss.Config, "config.toml")
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
 	ss.Config.InputNames = []string{"B", "T", "S", "X", "V", "P", "E"}
 	ss.Net = leabra.NewNetwork("RA25")
 	ss.Params.Config(ParamSets, ss.Config.Params.Sheet, ss.Config.Params.Tag, ss.Net)
@@ -320,11 +357,11 @@ func (ss *Sim) ConfigEnv() {
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
 	trn.Seq.Max = 25 // 25 sequences per epoch training
-	trn.TMatReber()
+	ss.SetGrammar(trn)
 
 	tst.Name = etime.Test.String()
 	tst.Seq.Max = 10
-	tst.TMatReber() // todo: random
+	ss.SetGrammar(tst)
 
 	trn.Init(0)
 	tst.Init(0)
@@ -333,6 +370,19 @@ func (ss *Sim) ConfigEnv() {
 	ss.Envs.Add(trn, tst)
 }
 
+// SetGrammar sets the transition matrix of the given env
+// to the Config.Env.Grammar.
+func (ss *Sim) SetGrammar(ev *FSAEnv) {
+	switch ss.Config.Env.Grammar {
+	case "Embedded":
+		ev.TMatEmbeddedReber()
+	case "Random":
+		ev.TMatRandom(ss.Config.Env.RandomStates, randx.NewSysRand(ss.Config.Env.RandomSeed))
+	default:
+		ev.TMatReber()
+	}
+}
+
 func (ss *Sim) ConfigNet(net *leabra.Network) {
 	net.SetRandSeed(ss.RandSeeds[0]) // init new separate random seed, using run = 0
 
@@ -341,6 +391,9 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 
 	hidp.Shape.CopyShape(&in.Shape)
 	hidp.Drivers.Add("Input")
+	if ss.Config.Params.Attn {
+		hidp.AddSendAttn(hid.Name)
+	}
 
 	trg := net.AddLayer2D("Targets", 1, 7, leabra.InputLayer) // just for visualization
 
@@ -367,6 +420,8 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 
 	net.Build()
 	net.Defaults()
+	hidct.Ctxt.Lag = leabra.Float(ss.Config.Params.CtxtLag)
+	hidct.Ctxt.Learn = ss.Config.Params.LearnCtxtLag
 	ss.ApplyParams()
 	net.InitWeights()
 }
@@ -402,6 +457,10 @@ func (ss *Sim) Init() {
 func (ss *Sim) InitRandSeed(run int) {
 	ss.RandSeeds.Set(run)
 	ss.RandSeeds.Set(run, &ss.Net.Rand)
+	if len(ss.Envs) > 0 { // separate env streams, so runs do not depend on other rand use
+		ss.Envs.ByMode(etime.Train).(*FSAEnv).Rand.NewRand(ss.RandSeeds[run])
+		ss.Envs.ByMode(etime.Test).(*FSAEnv).Rand.NewRand(ss.RandSeeds[run] + 1)
+	}
 }
 
 // ConfigLoops configures the control loops: Training, Testing
@@ -435,18 +494,6 @@ func (ss *Sim) ConfigLoops() {
 
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
-	// Train stop early condition
-	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
-		// This is calculated in TrialStats
-		stopNz := ss.Config.Run.NZero
-		if stopNz <= 0 {
-			stopNz = 2
-		}
-		curNZero := ss.Stats.Int("NZero")
-		stop := curNZero >= stopNz
-		return stop
-	})
-
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
 	trainEpoch.OnStart.Add("TestAtInterval", func() {
@@ -474,6 +521,8 @@ func (ss *Sim) ConfigLoops() {
 		ss.Log(mode.(etime.Modes), time.(etime.Times))
 	})
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
+	// FirstZero, LastZero, NZero reset per run, RunStats, and early stopping
+	leabra.LooperErrStats(ls, &ss.Logs, func() int { return ss.Config.Run.NZero })
 
 	ls.Loop(etime.Train, etime.Trial).OnEnd.Add("LogAnalyze", func() {
 		trnEpc := ls.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
@@ -482,10 +531,6 @@ func (ss *Sim) ConfigLoops() {
 		}
 	})
 
-	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
-		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
-	})
-
 	// Save weights to file, to look at later
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
 		ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
@@ -577,7 +622,6 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("UnitErr", 0.0)
 	ss.Stats.SetFloat("CorSim", 0.0)
 	ss.Stats.SetString("TrialName", "")
-	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -642,11 +686,7 @@ func (ss *Sim) TrialStats() {
 // 		Logging
 
 func (ss *Sim) ConfigLogs() {
-	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
-
-	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
+	leabra.LogAddStdItems(&ss.Logs, &ss.Stats, ss.Params.RunName(0))
 
 	ss.Logs.AddStatAggItem("CorSim", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("UnitErr", etime.Run, etime.Epoch, etime.Trial)
@@ -666,13 +706,7 @@ func (ss *Sim) ConfigLogs() {
 
 	ss.Logs.PlotItems("CorSim", "PctCor", "FirstZero", "LastZero")
 
-	ss.Logs.CreateTables()
-	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	// don't plot certain combinations we don't use
-	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Run)
-	// note: Analyze not plotted by default
-	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+	leabra.LogConfigStdTables(&ss.Logs, &ss.Stats, ss.Net)
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -695,7 +729,7 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 		ss.StatCounters()
 	}
 
-	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+	ss.LogFiles.LogRow(&ss.Logs, mode, time, row) // also logs to file, etc
 }
 
 //////// GUI
@@ -770,11 +804,7 @@ func (ss *Sim) RunNoGUI() {
 	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
 	netName := ss.Net.Name
 
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
+	ss.LogFiles.SetStdLogFiles(&ss.Logs, &ss.Config.Log, netName, runName)
 
 	netdata := ss.Config.Log.NetData
 	if netdata {
@@ -797,7 +827,7 @@ func (ss *Sim) RunNoGUI() {
 
 	ss.Loops.Run(etime.Train)
 
-	ss.Logs.CloseLogFiles()
+	ss.LogFiles.CloseLogFiles(&ss.Logs)
 
 	if netdata {
 		ss.GUI.SaveNetData(ss.Stats.String("RunName"))
@@ -47,6 +47,10 @@ type FSAEnv struct {
 
 	// trial is the step counter within sequence - how many steps taken within current sequence -- it resets to 0 at start of each sequence
 	Trial env.Counter `display:"inline"`
+
+	// Rand is the random number stream for generating the sequence,
+	// which is seeded by the sim at the start of each run.
+	Rand randx.SysRand `display:"-" json:"-"`
 }
 
 func (ev *FSAEnv) Label() string { return ev.Name }
@@ -71,18 +75,72 @@ func (ev *FSAEnv) SetTMat(fm, to int, p float64, lbl string) {
 // TMatReber sets the transition matrix to the standard Reber grammar FSA
 func (ev *FSAEnv) TMatReber() {
 	ev.InitTMat(8)
-	ev.SetTMat(0, 1, 1, "B")   // 0 = start
-	ev.SetTMat(1, 2, 0.5, "T") // 1 = state 0 in usu diagram (+1 for all states)
-	ev.SetTMat(1, 3, 0.5, "P")
-	ev.SetTMat(2, 2, 0.5, "S")
-	ev.SetTMat(2, 4, 0.5, "X")
-	ev.SetTMat(3, 3, 0.5, "T")
-	ev.SetTMat(3, 5, 0.5, "V")
-	ev.SetTMat(4, 6, 0.5, "S")
-	ev.SetTMat(4, 3, 0.5, "X")
-	ev.SetTMat(5, 6, 0.5, "V")
-	ev.SetTMat(5, 4, 0.5, "P")
-	ev.SetTMat(6, 7, 1, "E") // 7 = end
+	ev.SetReber(0, 7) // 7 = end
+	ev.Init(0)
+}
+
+// SetReber sets the transitions of the standard Reber grammar FSA
+// in states off to off+6 of the transition matrix, with the final E
+// transition going to the given exit state.
+func (ev *FSAEnv) SetReber(off, exit int) {
+	ev.SetTMat(off, off+1, 1, "B")     // off = start
+	ev.SetTMat(off+1, off+2, 0.5, "T") // off+1 = state 0 in usu diagram (+1 for all states)
+	ev.SetTMat(off+1, off+3, 0.5, "P")
+	ev.SetTMat(off+2, off+2, 0.5, "S")
+	ev.SetTMat(off+2, off+4, 0.5, "X")
+	ev.SetTMat(off+3, off+3, 0.5, "T")
+	ev.SetTMat(off+3, off+5, 0.5, "V")
+	ev.SetTMat(off+4, off+6, 0.5, "S")
+	ev.SetTMat(off+4, off+3, 0.5, "X")
+	ev.SetTMat(off+5, off+6, 0.5, "V")
+	ev.SetTMat(off+5, off+4, 0.5, "P")
+	ev.SetTMat(off+6, exit, 1, "E")
+}
+
+// TMatEmbeddedReber sets the transition matrix to the embedded Reber
+// grammar FSA (Cleeremans et al., 1989), where a B is followed by a T
+// or a P, then a full Reber grammar sequence, and then the same T or P,
+// and an E, so that predicting the penultimate letter requires
+// maintaining the second one over the embedded sequence, which is a
+// long-distance dependency that is not visible in the recent letters.
+func (ev *FSAEnv) TMatEmbeddedReber() {
+	ev.InitTMat(20)
+	ev.SetTMat(0, 1, 1, "B") // 0 = start
+	ev.SetTMat(1, 2, 0.5, "T")
+	ev.SetTMat(1, 9, 0.5, "P")
+	ev.SetReber(2, 16) // T branch
+	ev.SetReber(9, 17) // P branch
+	ev.SetTMat(16, 18, 1, "T")
+	ev.SetTMat(17, 18, 1, "P")
+	ev.SetTMat(18, 19, 1, "E") // 19 = end
+	ev.Init(0)
+}
+
+// TMatRandom sets the transition matrix to a random Reber-like grammar
+// FSA with the given number of internal states, using the given random
+// number generator.  As in the Reber grammar, a B leads to the first
+// internal state, and each internal state has a 50% random branching to
+// the next one (the last one going to the exit state, which goes to the
+// end with an E) and to a random internal state (possibly itself),
+// with the T, S, X, V, P labels distributed over the transitions so that
+// each appears at several points in the grammar, making them ambiguous.
+func (ev *FSAEnv) TMatRandom(nint int, rnd randx.Rand) {
+	nint = max(nint, 2)
+	lbls := []string{"T", "S", "X", "V", "P"}
+	randx.PermuteStrings(lbls, rnd)
+	exit := nint + 1
+	ev.InitTMat(nint + 3)
+	ev.SetTMat(0, 1, 1, "B") // 0 = start
+	for st := 1; st <= nint; st++ {
+		to := 1 + rnd.Intn(nint)
+		for to == st+1 {
+			to = 1 + rnd.Intn(nint)
+		}
+		li := 2 * (st - 1) // successive labels differ
+		ev.SetTMat(st, st+1, 0.5, lbls[li%len(lbls)])
+		ev.SetTMat(st, to, 0.5, lbls[(li+1)%len(lbls)])
+	}
+	ev.SetTMat(exit, exit+1, 1, "E") // exit+1 = end
 	ev.Init(0)
 }
 
@@ -132,7 +190,7 @@ func (ev *FSAEnv) NextState() {
 	ri := ev.AState.Cur * nst
 	ps := ev.TMat.Values[ri : ri+nst]
 	ls := ev.Labels.Values[ri : ri+nst]
-	nxt := randx.PChoose64(ps) // next state chosen at random
+	nxt := randx.PChoose64(ps, &ev.Rand) // next state chosen at random
 	ev.NextStates.Set1D(0, nxt)
 	ev.NextLabels.Set1D(0, ls[nxt])
 	idx := 1
@@ -0,0 +1,176 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// consolopt searches for the consolidation schedule of the hip example
+// (see leabra.ConsolSchedule) that best protects the AB items from
+// retroactive interference while learning the AC items, within a budget
+// of extra replay and interleaved training trials per run, and reports
+// the best schedule.  Each schedule is evaluated by running the hip sim
+// as a separate process, optionally in parallel, with the loss:
+//
+//	(1 - AB) + ACWeight * (1 - AC)
+//
+// averaged over the runs, where AB and AC are the memory scores at the
+// end of the run, of the hippocampus (TstABMem, TstACMem) or of the
+// Cortex trained by the replay (CortexABCorrel, CortexACCorrel).
+// The default search space is the number of replays per replay epoch,
+// and the Schedule Start, Every, OldFrac and Interleave, which can be
+// replaced by -param flags.  See package search for the search modes.
+//
+// Usage:
+//
+//	consolopt [flags] -- <hip command and args>
+//
+// For example:
+//
+//	consolopt -budget 200 -mem Cortex -evals 40 -par 4 -- ./hip -nogui -NRuns=3 -Tag={tag} {args}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// listFlag is a repeatable string flag.
+type listFlag []string
+
+func (lf *listFlag) String() string     { return strings.Join(*lf, " ") }
+func (lf *listFlag) Set(s string) error { *lf = append(*lf, s); return nil }
+
+// summary is the mean of the run log stats for one schedule.
+type summary struct {
+	Loss, AB, AC, Forget, Cost float64
+}
+
+func main() {
+	var params listFlag
+	sr := &search.Search{}
+	sr.Defaults()
+	var mem, dir, out, mode string
+	var budget, maxReplays, par int
+	var acWt float64
+	var keep bool
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] -- <hip command and args, with {tag} and {args}>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Var(&params, "param", "schedule parameter to search, as -Config.Arg=Min,Max[,log|int], replacing the default space -- repeat for each parameter")
+	flag.StringVar(&mem, "mem", "Cortex", "memory scores to optimize: Hip or Cortex")
+	flag.IntVar(&budget, "budget", 200, "budget of extra replay and interleaved trials per run (Schedule.Budget) -- 0 for no limit")
+	flag.IntVar(&maxReplays, "max-replays", 20, "maximum number of replays per replay epoch, for the default space")
+	flag.Float64Var(&acWt, "acwt", 1, "weight of the AC memory loss, relative to the AB memory loss")
+	flag.StringVar(&mode, "mode", search.Bayes.String(), "search mode: NelderMead, Bayes, Random, Grid or CMAES")
+	flag.IntVar(&sr.MaxEvals, "evals", sr.MaxEvals, "maximum number of schedules to evaluate")
+	flag.IntVar(&par, "par", 1, "number of sim processes to run in parallel")
+	flag.IntVar(&sr.GridN, "gridn", sr.GridN, "number of values of each parameter for Grid mode")
+	flag.Int64Var(&sr.Seed, "seed", 1, "random seed for the search")
+	flag.StringVar(&dir, "dir", "consolopt_runs", "directory for the run directories")
+	flag.BoolVar(&keep, "keep", false, "keep the run directories")
+	flag.StringVar(&out, "out", "consolopt_evals.tsv", "file to save the table of all the evaluations -- none if empty")
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	var abCol, acCol, forgetCol string
+	switch mem {
+	case "Hip":
+		abCol, acCol, forgetCol = "TstABMem", "TstACMem", "ABForget"
+	case "Cortex":
+		abCol, acCol, forgetCol = "CortexABCorrel", "CortexACCorrel", "CortexABForget"
+	default:
+		fail(fmt.Errorf("-mem must be Hip or Cortex, not %q", mem))
+	}
+	md, err := search.ParseMode(mode)
+	if err != nil {
+		fail(err)
+	}
+	sr.Mode = md
+	if len(params) == 0 {
+		params = listFlag{
+			fmt.Sprintf("-NReplays=0,%d,int", maxReplays),
+			"-Schedule.Start=-1,4,int",
+			"-Schedule.Every=1,4,int",
+			"-Schedule.OldFrac=0,1",
+			"-Schedule.Interleave=0,1",
+		}
+	}
+	for _, ps := range params {
+		pr, err := search.ParseParam(ps)
+		if err != nil {
+			fail(err)
+		}
+		if !pr.IsArg() {
+			fail(fmt.Errorf("schedule parameters must be config args starting with -: %s", ps))
+		}
+		sr.Space = append(sr.Space, pr)
+	}
+
+	cmd := slices.Clone(flag.Args())
+	cmd = append(cmd, fmt.Sprintf("-Schedule.Budget=%d", budget))
+	if !slices.Contains(cmd, "{args}") {
+		cmd = append(cmd, "{args}")
+	}
+	sc := search.NewSweepCommand(dir, cmd...)
+	sc.Keep = keep
+
+	var mu sync.Mutex
+	sums := map[string]summary{}
+	obj := func(vals []float64) (float64, error) {
+		var sm summary
+		loss, err := sc.LogObjective(sr.Space, func(dt *table.Table) (float64, error) {
+			for _, col := range []string{abCol, acCol, forgetCol, "ConsolCost"} {
+				if _, err := dt.ColumnByName(col); err != nil {
+					return 0, err
+				}
+			}
+			n := float64(dt.Rows)
+			for row := range dt.Rows {
+				sm.AB += dt.Float(abCol, row) / n
+				sm.AC += dt.Float(acCol, row) / n
+				sm.Forget += dt.Float(forgetCol, row) / n
+				sm.Cost += dt.Float("ConsolCost", row) / n
+			}
+			sm.Loss = (1 - sm.AB) + acWt*(1-sm.AC)
+			return sm.Loss, nil
+		})(vals)
+		if err == nil {
+			mu.Lock()
+			sums[fmt.Sprint(vals)] = sm
+			mu.Unlock()
+		}
+		return loss, err
+	}
+
+	best, err := sr.MinimizeParallel(obj, par)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if out != "" {
+		if err := sr.EvalsTable().SaveCSV(core.Filename(out), table.Tab, table.Headers); err != nil {
+			fail(err)
+		}
+		fmt.Printf("evaluations saved in: %s\n", out)
+	}
+	if len(sr.Evals) == 0 {
+		os.Exit(1)
+	}
+	sm := sums[fmt.Sprint(best.Values)]
+	fmt.Printf("best schedule in %d evaluations, with loss %.4g:\n", len(sr.Evals), best.Loss)
+	fmt.Printf("  %s -Schedule.Budget=%d\n", strings.Join(sr.Space.Args(best.Values), " "), budget)
+	fmt.Printf("  %s: %.4g  %s: %.4g  %s: %.4g  ConsolCost: %.4g\n", abCol, sm.AB, acCol, sm.AC, forgetCol, sm.Forget, sm.Cost)
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -13,18 +13,21 @@ import (
 	"math"
 	"math/rand"
 	"reflect"
+	"slices"
 	"strings"
 
 	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/mpi"
 	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/plot/plotcore"
+	"cogentcore.org/core/tensor"
 	"cogentcore.org/core/tensor/stats/split"
 	"cogentcore.org/core/tensor/table"
 	"cogentcore.org/core/tree"
-	"github.com/emer/emergent/v2/econfig"
+	"github.com/emer/emergent/v2/actrf"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -46,7 +49,12 @@ func main() {
 	sim := &Sim{}
 	sim.New()
 	sim.ConfigAll()
-	sim.RunGUI()
+	if sim.Config.GUI {
+		sim.RunGUI()
+	} else {
+		sim.RunNoGUI()
+	}
+	sim.MPI.Finalize()
 }
 
 // ParamSets is the default set of parameters -- Base is always applied, and others can be optionally
@@ -158,10 +166,112 @@ var ParamSets = params.Sets{
 				"Layer.Inhib.Pool.On":     "true",
 			}},
 	},
+	"STP": {
+		{Sel: "#DGToCA3", Desc: "facilitating mossy fibers: low release probability, slow facilitation decay",
+			Params: params.Params{
+				"Path.STP.On":   "true",
+				"Path.STP.U0":   "0.1",
+				"Path.STP.TauF": "400",
+				"Path.STP.TauD": "50",
+			}},
+		{Sel: "#CA3ToCA3", Desc: "depressing recurrent collaterals: high release probability",
+			Params: params.Params{
+				"Path.STP.On":   "true",
+				"Path.STP.U0":   "0.5",
+				"Path.STP.TauF": "20",
+				"Path.STP.TauD": "200",
+			}},
+	},
+	"MossyWeak": {
+		{Sel: "#DGToCA3", Desc: "weaker mossy fibers, e.g., later in training with ParamSched",
+			Params: params.Params{
+				"Path.WtScale.Rel": "2",
+			}},
+	},
+	"EWC": {
+		{Sel: "#CA3ToCA1", Desc: "elastic weight consolidation of the AB learning, consolidated at the switch to AC",
+			Params: params.Params{
+				"Path.EWC.On":     "true",
+				"Path.EWC.Lambda": "5",
+			}},
+		{Sel: ".EcCa1Path", Desc: "elastic weight consolidation of the AB learning, consolidated at the switch to AC",
+			Params: params.Params{
+				"Path.EWC.On":     "true",
+				"Path.EWC.Lambda": "5",
+			}},
+	},
+}
+
+// LogConfig has config parameters related to logging data
+type LogConfig struct {
+
+	// if true, save final weights after each run
+	SaveWeights bool
+
+	// if true, save train epoch log to file, as .epc.tsv typically
+	Epoch bool `default:"true" nest:"+"`
+
+	// if true, save run log to file, as .run.tsv typically
+	Run bool `default:"true" nest:"+"`
+
+	// if true, save train trial log to file, as .trl.tsv typically. May be large.
+	Trial bool `default:"false" nest:"+"`
+
+	// if true, save testing epoch log to file, as .tst_epc.tsv typically.
+	TestEpoch bool `default:"true" nest:"+"`
+
+	// if true, save testing trial log to file, as .tst_trl.tsv typically. May be large.
+	TestTrial bool `default:"false" nest:"+"`
+
+	// file format of the log files: LogParquet saves compressed .parquet
+	// files, which are much smaller and faster to load for analysis
+	// than .tsv files (see leabra.LogFiles).
+	Format leabra.LogFormats
+
+	// number of rows per Parquet row group, which are buffered before
+	// writing them to the file (100 if 0).
+	RowGroup int
+
+	// if true, record the DG, CA3 and CA1 activity at every cycle of the
+	// test trials, and its similarity to the final state of the trial
+	// (temporal RSA, see leabra.CycleTrajectory), in the CycleRSA misc
+	// table, which is saved for the last test epoch of each run to a
+	// _cyc_rsa.tsv file in nogui runs.
+	CycleRSA bool
+
+	// if true, compute the activity-based receptive fields of the DG and
+	// CA3 units over the Input patterns of each test epoch, and the weight
+	// projections of the DG and CA3 units back to ECin (see ActRFs and
+	// WtRFs), for analyzing what the units encode, shown in the GUI.
+	RFs bool
 }
 
-// Config has config parameters related to running the sim
+// Config has config parameters related to running the sim,
+// which can be set from a TOML or YAML config file and command-line
+// args (see leabra.Config), so that batch jobs are fully specified.
 type Config struct {
+
+	// specify include files here, and after configuration,
+	// it contains list of include files added.
+	Includes []string
+
+	// open the GUI -- does not automatically run -- if false,
+	// then runs automatically and quits.
+	GUI bool `default:"true"`
+
+	// Extra Param Sheet name(s) to use (space separated if multiple).
+	// must be valid name as listed in compiled-in params or loaded params
+	Sheet string
+
+	// extra tag to add to file names and logs saved from this run
+	Tag string
+
+	// user note -- describe the run params etc -- like a git commit message for the run
+	Note string
+
+	// data logging related configuration options
+	Log LogConfig `display:"add-fields"`
+
 	// total number of runs to do when running Train
 	NRuns int `default:"10" min:"1"`
 
@@ -177,8 +287,119 @@ type Config struct {
 
 	// StopMem is the threshold for stopping learning.
 	StopMem float32 `default:"1"`
+
+	// StopAtSwitch stops running at the end of the training epoch on
+	// which training switches from AB to AC, using the Stepper, to
+	// examine the network at the switch in the GUI.
+	StopAtSwitch bool
+
+	// MemScore has the memory threshold criterion and selects the
+	// alternative memory scores that are computed and reported side by side
+	// in the test logs: MemCorrel, AB/ACDPrime, and AB/ACROC vs. lures.
+	MemScore leabra.MemScoreParams `display:"inline"`
+
+	// Readout is the readout transform applied to the ECout activity
+	// and target, and the Input cue, for computing memory stats.
+	Readout leabra.ReadoutParams `display:"inline"`
+
+	// Theta has the theta-phase modulation parameters for the hippocampus,
+	// which are copied to the network in ConfigNet.
+	Theta leabra.ThetaPhaseParams `display:"inline"`
+
+	// NReplays is the number of offline hippocampal replay trials run
+	// after each training epoch (on the replay epochs of the Schedule),
+	// with the replayed ECout patterns used to train the Cortex network,
+	// for simulating systems consolidation.  0 = no replay.
+	NReplays int `default:"0" min:"0"`
+
+	// Replay has the parameters for the offline hippocampal replay trials.
+	Replay leabra.ReplayParams `display:"inline"`
+
+	// Schedule is the consolidation schedule: the epochs on which replay
+	// is run, the mix of AB and AC items that cue it, the proportion of AB
+	// training items interleaved with the AC items, and the budget of
+	// these extra trials per run (see leabra.ConsolSchedule).
+	Schedule leabra.ConsolSchedule `display:"inline"`
+
+	// Curriculum has the stages of training on the AB and AC items
+	// (TrainAB and TrainAC tables), which can be interleaved and spaced.
+	// If it has no stages, the standard AB then AC stages are used,
+	// switching to AC when ABMem >= StopMem, or after NEpochs / 2 + 1
+	// epochs (see leabra.Curriculum).
+	Curriculum leabra.Curriculum
+
+	// ParamSched is the schedule of params changes over training, with
+	// the params sheets applied from given epochs of each run, e.g., the
+	// MossyWeak sheet from epoch 10 (see leabra.ParamSched).
+	ParamSched leabra.ParamSched
+
+	// Spatial uses patterns generated by the Spatial environment from
+	// trajectories through a 2D arena, instead of the random AB-AC patterns:
+	// AB has grid and place cell patterns along a trajectory, AC has the
+	// same grid patterns with the place cells remapped, and the lures are
+	// from a novel arena.  Test inputs have only the grid cells, as a cue
+	// for recalling the place cells.
+	Spatial bool
+
+	// SpatialTrials is the number of positions along the trajectory
+	// in the Spatial patterns.
+	SpatialTrials int `default:"10" min:"1"`
+
+	// NWay uses N-way associations, e.g., A-B-C triplets for AB and A-D-E
+	// for AC, instead of the paired associates, with any-cue testing, where
+	// each association is tested with every combination of NWay.NCue cue
+	// elements, and the recall of each response element is accumulated
+	// by cue and response in the NWayRecall table (see leabra.NWayAssoc).
+	NWay leabra.NWayAssoc `display:"inline"`
+
+	// TransInf uses transitive inference patterns, where AB has the
+	// premise pairs of a hierarchy of items (A > B > ... > F) and AC those
+	// of a second hierarchy (G > ... > L), with the winner of each pair to
+	// be recalled, and the test tables have all the pairs, including the
+	// inference probes between non-adjacent items.  The accuracy of the
+	// choice of the winner is in the TICorrect stat, and the symbolic
+	// distance effect is computed from the test trial log into the TIDist
+	// table and the TIDistSlope stat (see leabra.TransInf).
+	TransInf leabra.TransInf `display:"inline"`
+
+	// DriftCtxt uses drifting context patterns, which change gradually
+	// from one trial to the next (see leabra.DriftingContext), with the AC
+	// context continuing to drift from the end of AB, instead of independent
+	// bit flips from a prototype.  The training trials are then presented
+	// sequentially, in the order of the drift.
+	DriftCtxt bool
+
+	// CtxtDrift is the proportion of active context bits that are flipped
+	// on each trial, for DriftCtxt.
+	CtxtDrift float32 `default:"0.2" min:"0" max:"1"`
+
+	// TopoPPath uses topographic connectivity for the ECin -> DG and CA3
+	// perforant pathway (leabra.GaussTopo), with the probability of a
+	// connection and its weight scale falling off as a Gaussian of the
+	// distance between the EC unit and the position of the receiving unit
+	// mapped onto the EC, instead of uniform random connectivity.
+	TopoPPath bool
+
+	// Cue degrades the test cues (Input patterns) by removing a random
+	// 1 - Frac proportion of their active bits, in addition to the empty
+	// recall pool, optionally corrupting them with noise, for testing
+	// pattern completion from partial cues (see leabra.PartialCue).
+	Cue leabra.PartialCue `display:"inline"`
+
+	// CueFracs, if set, tests each item with cues degraded at each of these
+	// Cue fractions, with a _cue<percent> suffix on the TrialName, for a
+	// parametric pattern completion curve in each test epoch.
+	CueFracs []float32
+
+	// MPI uses MPI message passing for data-parallel training, with the
+	// training trials divided across procs running identical copies of
+	// the sim, and weight changes summed across procs.
+	// Requires building with -tags mpi, and running with mpirun.
+	MPI bool
 }
 
+func (cfg *Config) IncludesPtr() *[]string { return &cfg.Includes }
+
 // Sim encapsulates the entire simulation model, and we define all the
 // functionality as methods on this struct.  This structure keeps all relevant
 // state information organized and available without having to pass everything around
@@ -192,12 +413,27 @@ type Sim struct {
 	// the network -- click to view / edit parameters for layers, paths, etc
 	Net *leabra.Network `new-window:"+" display:"no-inline"`
 
+	// Cortex is a neocortical network trained on the patterns replayed
+	// by the hippocampus, when Config.NReplays > 0, mapping the cue
+	// (without the recall pools) onto the full replayed pattern.
+	Cortex *leabra.Network `new-window:"+" display:"no-inline"`
+
+	// references to the layers used in the sim, set in ConfigNet
+	Layers leabra.HipLayers `display:"-"`
+
 	// all parameter management
 	Params emer.NetParams `display:"add-fields"`
 
 	// contains looper control loops for running sim
 	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
 
+	// steps the Loops at the standard grains (Cycle, Quarter, Phase,
+	// AlphaCycle, Trial, Epoch, Run), with conditional stops
+	Stepper *leabra.Stepper `new-window:"+" display:"no-inline"`
+
+	// the log files in the Config.Log.Format
+	LogFiles leabra.LogFiles `display:"-"`
+
 	// contains computed statistic values
 	Stats estats.Stats `new-window:"+"`
 
@@ -234,6 +470,40 @@ type Sim struct {
 	// all training patterns -- for pretrain
 	TrainAll *table.Table `new-window:"+" display:"-"`
 
+	// Spatial generates grid and place cell EC patterns from trajectories
+	// through a 2D arena, when Config.Spatial is on.
+	Spatial leabra.SpatialEnv `display:"-"`
+
+	// StoredECout has the ECout training patterns, for finding the one
+	// nearest to the ECout recall on each trial.
+	StoredECout leabra.NearestPatterns `display:"-"`
+
+	// StoredCA3 has the CA3 activity states recorded on each training
+	// trial, by trial name, for finding the one nearest to the CA3 state
+	// on each trial.
+	StoredCA3 leabra.NearestPatterns `display:"-"`
+
+	// StoredCA1 has the CA1 activity states recorded on each training
+	// trial, by trial name, for decoding the CA1 retrieval dynamics.
+	StoredCA1 leabra.NearestPatterns `display:"-"`
+
+	// NWayRecall has the cue/response accounting of the recall of each
+	// response element on the test trials, when Config.NWay is on.
+	NWayRecall leabra.NWayRecall `display:"-"`
+
+	// RetrievalDyn decode the CA3 and CA1 retrieval dynamics at every
+	// cycle of each test trial, relative to the StoredCA3 and StoredCA1
+	// patterns, averaged by trial type into the RetrievalDyn misc table.
+	RetrievalDyn []*leabra.RetrievalDynamics `display:"-"`
+
+	// CycleTraj records the DG, CA3 and CA1 activity at every cycle of
+	// each test trial, when Config.Log.CycleRSA is on.
+	CycleTraj *leabra.CycleTrajectory `display:"-"`
+
+	// WtRFs are the weight projection receptive fields of the WtRFs specs,
+	// when Config.Log.RFs is on, with the activity-based ones in Stats.ActRFs.
+	WtRFs actrf.RFs `display:"-"`
+
 	// Environments
 	Envs env.Envs `new-window:"+" display:"no-inline"`
 
@@ -248,17 +518,27 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// MPI data-parallel training state, if Config.MPI is on
+	MPI leabra.MPI `display:"-"`
 }
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
 	// ss.Config.Defaults()
-	econfig.Config(&ss.Config, "config.toml")
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
+	errors.Log(ss.MPI.Init(ss.Config.MPI))
 	// ss.Config.Hip.EC5Clamp = true      // must be true in hip.go to have a target layer
 	// ss.Config.Hip.EC5ClampTest = false // key to be off for cmp stats on completion region
 
 	ss.Net = leabra.NewNetwork("Hip")
-	ss.Params.Config(ParamSets, "", "", ss.Net)
+	ss.Cortex = leabra.NewNetwork("Cortex")
+	ss.RetrievalDyn = []*leabra.RetrievalDynamics{
+		leabra.NewRetrievalDynamics("CA3", &ss.StoredCA3),
+		leabra.NewRetrievalDynamics("CA1", &ss.StoredCA1),
+	}
+	ss.CycleTraj = leabra.NewCycleTrajectory("DG", "CA3", "CA1")
+	ss.Params.Config(ParamSets, ss.Config.Sheet, ss.Config.Tag, ss.Net)
 	ss.Stats.Init()
 	ss.Stats.SetInt("Expt", 0)
 
@@ -271,6 +551,8 @@ func (ss *Sim) New() {
 	ss.TestLure = &table.Table{}
 	ss.TrainAll = &table.Table{}
 	ss.TestAll = &table.Table{}
+	ss.Spatial.Defaults()
+	ss.Spatial.Speed = 0.15 // spread out the positions across trials
 	ss.PretrainMode = false
 
 	ss.RandSeeds.Init(100) // max 100 runs
@@ -287,6 +569,8 @@ func (ss *Sim) ConfigAll() {
 	// ss.ConfigPatterns()
 	ss.ConfigEnv()
 	ss.ConfigNet(ss.Net)
+	ss.ConfigCortex(ss.Cortex)
+	ss.ConfigRFs()
 	ss.ConfigLogs()
 	ss.ConfigLoops()
 }
@@ -304,12 +588,23 @@ func (ss *Sim) ConfigEnv() {
 
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
-	trn.Config(table.NewIndexView(ss.TrainAB))
-	trn.Validate()
+	cu := &ss.Config.Curriculum
+	if len(cu.Stages) == 0 {
+		cu.Stages = []leabra.CurriculumStage{
+			{Name: "AB", Tables: []string{"TrainAB"}, Stat: "ABMem", Criterion: float64(ss.Config.StopMem), Epochs: ss.Config.NEpochs/2 + 1},
+			{Name: "AC", Tables: []string{"TrainAC"}},
+		}
+	}
+	cu.AddTable("TrainAB", ss.TrainAB)
+	cu.AddTable("TrainAC", ss.TrainAC)
+	cu.Init()
+	ss.ConfigTrainEnv(trn)
+	trn.Sequential = ss.Config.DriftCtxt
 
 	tst.Name = etime.Test.String()
 	tst.Config(table.NewIndexView(ss.TestAll))
 	tst.Sequential = true
+	tst.GroupCol = "Cue" // NWay cue elements
 	tst.Validate()
 
 	trn.Init(0)
@@ -319,6 +614,25 @@ func (ss *Sim) ConfigEnv() {
 	ss.Envs.Add(trn, tst)
 }
 
+// ConfigTrainEnv configures the training environment with the items
+// of the current stage of the Curriculum for the next epoch, restricted
+// to this proc's shard of the trials if running MPI, and sets the
+// number of training trials per epoch accordingly.
+func (ss *Sim) ConfigTrainEnv(trn *env.FixedTable) {
+	errors.Log(ss.Config.Curriculum.ConfigEnv(trn, nil, ss.MPI.ShardIndexView))
+	if ss.Loops != nil {
+		ss.Loops.Loop(etime.Train, etime.Trial).Counter.Max = trn.Table.Len()
+	}
+}
+
+// TrainIndexView returns an IndexView of the given training patterns,
+// restricted to this proc's shard of the trials if running MPI.
+func (ss *Sim) TrainIndexView(dt *table.Table) *table.IndexView {
+	ix := table.NewIndexView(dt)
+	errors.Log(ss.MPI.ShardIndexView(ix))
+	return ix
+}
+
 func (ss *Sim) ConfigNet(net *leabra.Network) {
 	net.SetRandSeed(ss.RandSeeds[0]) // init new separate random seed, using run = 0
 
@@ -328,6 +642,7 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	ca1 := net.AddLayer4D("CA1", 6, 2, 4, 10, leabra.SuperLayer)
 	dg := net.AddLayer2D("DG", 25, 25, leabra.SuperLayer)
 	ca3 := net.AddLayer2D("CA3", 30, 10, leabra.SuperLayer)
+	nov := net.AddNoveltyLayer("Novelty", "ECin", "ECout")
 
 	ecin.AddClass("EC")
 	ecout.AddClass("EC")
@@ -345,8 +660,18 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	net.ConnectLayers(ecout, ca1, pool1to1, leabra.EcCa1Path)
 
 	// Perforant pathway
-	ppath := paths.NewUniformRand()
-	ppath.PCon = 0.25
+	var ppath paths.Pattern
+	if ss.Config.TopoPPath {
+		gt := leabra.NewGaussTopo()
+		gt.Sigma = 0.3
+		gt.PCon = 0.5 // about the same overall 0.25 connectivity as uniform
+		gt.TopoWeights = true
+		ppath = gt
+	} else {
+		up := paths.NewUniformRand()
+		up.PCon = 0.25
+		ppath = up
+	}
 
 	net.ConnectLayers(ecin, dg, ppath, leabra.CHLPath).AddClass("HippoCHL")
 
@@ -366,6 +691,7 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	dg.PlaceAbove(in)
 	ca3.PlaceAbove(dg)
 	ca1.PlaceRightOf(ca3, 2)
+	nov.PlaceRightOf(ecout, 2)
 
 	in.Doc = "Input represents cortical processing areas for different sensory modalities, semantic categories, etc, organized into pools. It is pre-compressed in this model, to simplify and allow one-to-one projections into the EC."
 
@@ -380,12 +706,32 @@ func (ss *Sim) ConfigNet(net *leabra.Network) {
 	dg.Doc = "Dentate Gyruns, which receives broad inputs from ECin and has highly sparse, pattern separated representations, which drive more separated representations in CA3"
 
 	net.Build()
+	errors.Log(net.LayerRefs(&ss.Layers))
 	net.Defaults()
+	net.Theta = ss.Config.Theta
 	ss.ApplyParams()
 	net.InitWeights()
 	net.InitTopoScales()
 }
 
+// ConfigCortex configures the neocortical network that learns from
+// hippocampal replay, with the same Input and Output shapes as Input
+// and ECout in the hippocampus.
+func (ss *Sim) ConfigCortex(net *leabra.Network) {
+	net.SetRandSeed(ss.RandSeeds[0])
+	in := net.AddLayer4D("Input", 6, 2, 3, 4, leabra.InputLayer)
+	hid := net.AddLayer2D("Hidden", 15, 15, leabra.SuperLayer)
+	out := net.AddLayer4D("Output", 6, 2, 3, 4, leabra.TargetLayer)
+	full := paths.NewFull()
+	net.ConnectLayers(in, hid, full, leabra.ForwardPath)
+	net.BidirConnectLayers(hid, out, full)
+	hid.PlaceRightOf(in, 2)
+	out.PlaceRightOf(hid, 2)
+	net.Build()
+	net.Defaults()
+	net.InitWeights()
+}
+
 func (ss *Sim) ApplyParams() {
 	ss.Params.Network = ss.Net
 	ss.Params.SetAll()
@@ -425,7 +771,7 @@ func (ss *Sim) InitRandSeed(run int) {
 func (ss *Sim) ConfigLoops() {
 	ls := looper.NewStacks()
 
-	trls := ss.TrainAB.Rows
+	trls := ss.TrainAB.Rows / ss.MPI.Size()
 	ttrls := ss.TestAll.Rows
 
 	ls.AddStack(etime.Train).AddTime(etime.Run, ss.Config.NRuns).AddTime(etime.Epoch, ss.Config.NEpochs).AddTime(etime.Trial, trls).AddTime(etime.Cycle, 100)
@@ -434,7 +780,9 @@ func (ss *Sim) ConfigLoops() {
 
 	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
 	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+	ss.MPI.ConfigLoops(ls, ss.Net, &ss.ViewUpdate)
 	ss.Net.ConfigLoopsHip(&ss.Context, ls)
+	leabra.LooperParamSched(ls, ss.Net, &ss.Context, &ss.Config.ParamSched, &ss.Params)
 
 	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
 	ls.Stacks[etime.Test].OnInit.Add("Init", func() { ss.TestInit() })
@@ -447,6 +795,11 @@ func (ss *Sim) ConfigLoops() {
 
 	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
+		ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
+		leabra.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWeights, ctrString, ss.Stats.String("RunName"))
+	})
+
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunDone", func() {
 		if ss.Stats.Int("Run") >= ss.Config.NRuns-1 {
 			ss.RunStats()
@@ -457,23 +810,22 @@ func (ss *Sim) ConfigLoops() {
 
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnEnd.Add("Consolidate", func() {
+		if ss.Config.Schedule.ReplayEpoch(trainEpoch.Counter.Cur, ss.ACStartEpoch()) {
+			ss.Consolidate()
+		}
+	})
 	trainEpoch.OnEnd.Add("TestAtInterval", func() {
 		if (ss.Config.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.TestInterval == 0) {
 			// Note the +1 so that it doesn't occur at the 0th timestep.
 			ss.RunTestAll()
-
-			// switch to AC
-			trn := ss.Envs.ByMode(etime.Train).(*env.FixedTable)
-			tstEpcLog := ss.Logs.Tables[etime.Scope(etime.Test, etime.Epoch)]
-			epc := ss.Stats.Int("Epoch")
-			abMem := float32(tstEpcLog.Table.Float("ABMem", epc))
-			if (trn.Table.Table.MetaData["name"] == "TrainAB") && (abMem >= ss.Config.StopMem || epc >= ss.Config.NEpochs/2) {
-				ss.Stats.SetInt("FirstPerfect", epc)
-				trn.Config(table.NewIndexView(ss.TrainAC))
-				trn.Validate()
-			}
 		}
 	})
+	trainEpoch.OnEnd.Add("Curriculum", func() {
+		tested := (ss.Config.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.TestInterval == 0)
+		ss.AdvanceCurriculum(tested)
+	})
+	trainEpoch.OnEnd.Add("Interleave", ss.Interleave)
 
 	// early stop
 	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("ACMemStop", func() bool {
@@ -481,8 +833,62 @@ func (ss *Sim) ConfigLoops() {
 		tstEpcLog := ss.Logs.Tables[etime.Scope(etime.Test, etime.Epoch)]
 		acMem := float32(tstEpcLog.Table.Float("ACMem", ss.Stats.Int("Epoch")))
 		stop := acMem >= ss.Config.StopMem
-		return stop
+		return stop && ss.Config.Curriculum.IsLast()
+	})
+	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("CurriculumDone", ss.Config.Curriculum.Done)
+
+	// retrieval dynamics during testing
+	tstEpoch := ls.Loop(etime.Test, etime.Epoch)
+	tstTrial := ls.Loop(etime.Test, etime.Trial)
+	tstEpoch.OnStart.Add("ResetRetrievalDyn", func() {
+		for _, rd := range ss.RetrievalDyn {
+			rd.Reset()
+		}
+	})
+	tstTrial.OnStart.Add("StartRetrievalDyn", func() {
+		for _, rd := range ss.RetrievalDyn {
+			rd.StartTrial(ss.Stats.String("TrialName"))
+		}
+	})
+	ls.Loop(etime.Test, etime.Cycle).OnEnd.Add("RecordRetrievalDyn", func() {
+		for _, rd := range ss.RetrievalDyn {
+			rd.RecordCycle(ss.Net)
+		}
+	})
+	tstTrial.OnEnd.Add("EndRetrievalDyn", func() {
+		ttype, _, _ := strings.Cut(ss.Stats.String("TrialName"), "_")
+		for _, rd := range ss.RetrievalDyn {
+			rd.EndTrial(ttype)
+		}
 	})
+	tstEpoch.OnEnd.Add("RetrievalDynTable", ss.RetrievalDynTable)
+	if ss.Config.Log.CycleRSA {
+		tstEpoch.OnStart.Add("ResetCycleRSA", func() {
+			ss.Logs.MiscTables["CycleRSA"] = table.NewTable()
+		})
+		tstTrial.OnStart.Add("StartCycleTraj", ss.CycleTraj.StartTrial)
+		ls.Loop(etime.Test, etime.Cycle).OnEnd.Add("RecordCycleTraj", func() {
+			ss.CycleTraj.RecordCycle(ss.Net)
+		})
+		tstTrial.OnEnd.Add("CycleRSA", func() {
+			ss.CycleTraj.AddToTable(ss.Logs.MiscTable("CycleRSA"), ss.Stats.String("TrialName"))
+		})
+		ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveCycleRSA", ss.SaveCycleRSA)
+	}
+	if ss.Config.Log.RFs {
+		tstEpoch.OnStart.Add("ResetActRFs", ss.Stats.ActRFs.Reset)
+		tstTrial.OnEnd.Add("UpdateActRFs", func() {
+			ss.Stats.UpdateActRFs(ss.Net, "ActM", 0.01, 0)
+		})
+		tstEpoch.OnEnd.Add("RFs", ss.UpdateRFs)
+	}
+	tstEpoch.OnEnd.Add("PatSepStats", ss.PatSepStats)
+	tstEpoch.OnStart.Add("ResetNWayRecall", ss.NWayRecall.Reset)
+	tstEpoch.OnEnd.Add("NWayRecall", func() {
+		ss.Stats.SetFloat("NWayRecall", ss.NWayRecall.Mean())
+		ss.Logs.MiscTables["NWayRecall"] = ss.NWayRecall.Table
+	})
+	tstEpoch.OnEnd.Add("TransInfStats", ss.TransInfStats)
 
 	/////////////////////////////////////////////
 	// Logging
@@ -502,6 +908,11 @@ func (ss *Sim) ConfigLoops() {
 	ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
 	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
 
+	ss.Stepper = leabra.NewStepper(ls, &ss.Context, 75)
+	ss.Stepper.AddStopCond("StopAtSwitch", leabra.StepEpoch, func() bool {
+		return ss.Config.StopAtSwitch && ls.Mode == etime.Train && ss.Stats.Int("FirstPerfect") == trainEpoch.Counter.Cur
+	})
+
 	ss.Loops = ls
 	fmt.Println(ls.DocString())
 }
@@ -514,25 +925,18 @@ func (ss *Sim) ApplyInputs() {
 	ctx := &ss.Context
 	net := ss.Net
 	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
-	ecout := net.LayerByName("ECout")
+	ecout := ss.Layers.ECout
 	if ctx.Mode == etime.Train {
 		ecout.Type = leabra.TargetLayer // clamp a plus phase during testing
 	} else {
 		ecout.Type = leabra.CompareLayer // don't clamp
 	}
 	ecout.UpdateExtFlags() // call this after updating type
-	net.InitExt()
-	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
 	ev.Step()
 	// note: must save env state for logging / stats due to data parallel re-use of same env
 	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
-	for _, lnm := range lays {
-		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
-		if pats != nil {
-			ly.ApplyExt(pats)
-		}
-	}
+	ss.Stats.SetString("Cue", ev.GroupName.Cur)
+	errors.Log(net.ApplyEnvInputs(ev))
 }
 
 // NewRun intializes a new run of the model, using the TrainEnv.Run counter
@@ -545,6 +949,9 @@ func (ss *Sim) NewRun() {
 	ctx.Reset()
 	ctx.Mode = etime.Train
 	ss.Net.InitWeights()
+	ss.Cortex.InitWeights()
+	ss.StoredCA3.Reset()
+	ss.StoredCA1.Reset()
 	ss.InitStats()
 	ss.StatCounters()
 	ss.Logs.ResetLog(etime.Train, etime.Epoch)
@@ -575,16 +982,206 @@ func (ss *Sim) OpenPatAsset(dt *table.Table, fnm, name, desc string) error {
 }
 
 func (ss *Sim) OpenPatterns() {
-	ss.OpenPatAsset(ss.TrainAB, "train_ab.tsv", "TrainAB", "AB Training Patterns")
-	ss.OpenPatAsset(ss.TrainAC, "train_ac.tsv", "TrainAC", "AC Training Patterns")
-	ss.OpenPatAsset(ss.TestAB, "test_ab.tsv", "TestAB", "AB Testing Patterns")
-	ss.OpenPatAsset(ss.TestAC, "test_ac.tsv", "TestAC", "AC Testing Patterns")
-	ss.OpenPatAsset(ss.TestLure, "test_lure.tsv", "TestLure", "Lure Testing Patterns")
+	if ss.Config.Spatial {
+		ss.SpatialPatterns()
+	} else if ss.Config.NWay.On {
+		ss.NWayPatterns()
+	} else if ss.Config.TransInf.On {
+		ss.TransInfPatterns()
+	} else {
+		ss.OpenPatAsset(ss.TrainAB, "train_ab.tsv", "TrainAB", "AB Training Patterns")
+		ss.OpenPatAsset(ss.TrainAC, "train_ac.tsv", "TrainAC", "AC Training Patterns")
+		ss.OpenPatAsset(ss.TestAB, "test_ab.tsv", "TestAB", "AB Testing Patterns")
+		ss.OpenPatAsset(ss.TestAC, "test_ac.tsv", "TestAC", "AC Testing Patterns")
+		ss.OpenPatAsset(ss.TestLure, "test_lure.tsv", "TestLure", "Lure Testing Patterns")
+	}
 
 	ss.TestAll = ss.TestAB.Clone()
 	ss.TestAll.SetMetaData("name", "TestAll")
 	ss.TestAll.AppendRows(ss.TestAC)
 	ss.TestAll.AppendRows(ss.TestLure)
+	ss.ConfigCues()
+
+	ss.StoredECout.Reset()
+	errors.Log(ss.StoredECout.SetFromTable(ss.TrainAB, "Name", "ECout"))
+	errors.Log(ss.StoredECout.SetFromTable(ss.TrainAC, "Name", "ECout"))
+}
+
+// ConfigCues degrades the TestAll cues according to Config.Cue,
+// or at each of the Config.CueFracs if set.
+func (ss *Sim) ConfigCues() {
+	cue := &ss.Config.Cue
+	rnd := randx.NewSysRand(ss.RandSeeds[0])
+	if len(ss.Config.CueFracs) > 0 {
+		st, err := cue.PartialCueSeries(ss.TestAll, "Input", ss.Config.CueFracs, rnd)
+		if errors.Log(err) == nil {
+			ss.TestAll = st
+		}
+		return
+	}
+	if cue.Frac < 1 {
+		errors.Log(cue.CueTable(ss.TestAll, "Input", rnd))
+	}
+}
+
+// SpatialPatterns generates the patterns from trajectories through
+// the Spatial arena: the AB and AC patterns are at the same positions,
+// with the place cells remapped for AC, so the same grid cell cue must
+// recall different place cells, and the lures are along a different
+// trajectory in a novel arena, with both grid and place cells remapped.
+func (ss *Sim) SpatialPatterns() {
+	ev := &ss.Spatial
+	ev.Name = "Spatial"
+	ev.Config()
+	ev.Init(0)
+	n := ss.Config.SpatialTrials
+	traj := ev.Walk(n)
+	ev.PatsTable(ss.TrainAB, "ab", traj, false)
+	ev.PatsTable(ss.TestAB, "ab", traj, true)
+	ev.RemapPlace()
+	ev.PatsTable(ss.TrainAC, "ac", traj, false)
+	ev.PatsTable(ss.TestAC, "ac", traj, true)
+	ev.RemapGrid()
+	ev.RemapPlace()
+	ev.Init(0)
+	ev.PatsTable(ss.TestLure, "lure", ev.Walk(n), true)
+
+	pats := []struct {
+		dt         *table.Table
+		name, desc string
+	}{
+		{ss.TrainAB, "TrainAB", "AB Spatial Training Patterns"},
+		{ss.TrainAC, "TrainAC", "AC Spatial Training Patterns"},
+		{ss.TestAB, "TestAB", "AB Spatial Testing Patterns"},
+		{ss.TestAC, "TestAC", "AC Spatial Testing Patterns"},
+		{ss.TestLure, "TestLure", "Lure Spatial Testing Patterns"},
+	}
+	for _, pt := range pats {
+		pt.dt.SetMetaData("name", pt.name)
+		pt.dt.SetMetaData("desc", pt.desc)
+		for i := 1; i < pt.dt.NumColumns(); i++ {
+			pt.dt.Columns[i].SetMetaData("grid-fill", "0.9")
+		}
+	}
+}
+
+// NWayPatterns generates the N-way association patterns for
+// Config.NWay: AB has A-B-C (for NWay = 3) associations, and AC has
+// A-D-E, sharing the A element as in the AB-AC paradigm, each with its
+// own context, and the lures have novel lA-lB-lC associations.
+// The lists keep the ab, ac and lure names, so the memory stats of
+// each list are computed as for the paired associates.
+func (ss *Sim) NWayPatterns() {
+	na := &ss.Config.NWay
+	patgen.NewRand(ss.RandSeeds[0]) // separate stream, for reproducible patterns
+	shape := []int{6, 2, 3, 4}
+	elems := func(prefix string, st, n int) []string {
+		els := make([]string, n)
+		for i := range els {
+			els[i] = prefix + string(rune('A'+st+i))
+		}
+		return els
+	}
+	ab := elems("", 0, na.NWay)
+	ac := append([]string{"A"}, elems("", na.NWay, na.NWay-1)...)
+	lure := elems("l", 0, na.NWay)
+	nctxt := max(shape[0]*shape[1]-na.NWay*na.ElemPools, 0)
+
+	voc := patgen.Vocab{}
+	ss.PoolVocab = voc
+	errors.Log(na.AddVocab(voc, shape[2], shape[3], ab...))
+	errors.Log(na.AddVocab(voc, shape[2], shape[3], ac...))
+	errors.Log(na.AddVocab(voc, shape[2], shape[3], lure...))
+	for _, ctxt := range []string{"ctxtAB", "ctxtAC", "ctxtLure"} {
+		errors.Log(na.AddCtxtVocab(voc, ctxt, nctxt, shape[2], shape[3]))
+	}
+	pats := []struct {
+		dt         *table.Table
+		name, desc string
+	}{
+		{ss.TrainAB, "TrainAB", "AB N-way Training Patterns"},
+		{ss.TrainAC, "TrainAC", "AC N-way Training Patterns"},
+		{ss.TestAB, "TestAB", "AB N-way Testing Patterns"},
+		{ss.TestAC, "TestAC", "AC N-way Testing Patterns"},
+		{ss.TestLure, "TestLure", "Lure N-way Testing Patterns"},
+	}
+	for _, pt := range pats {
+		pt.dt.SetMetaData("name", pt.name)
+		pt.dt.SetMetaData("desc", pt.desc)
+	}
+	errors.Log(na.Tables(voc, "ab", ab, "ctxtAB", shape, ss.TrainAB, ss.TestAB))
+	errors.Log(na.Tables(voc, "ac", ac, "ctxtAC", shape, ss.TrainAC, ss.TestAC))
+	errors.Log(na.Tables(voc, "lure", lure, "ctxtLure", shape, table.NewTable(), ss.TestLure))
+	for _, pt := range pats {
+		for i := 1; i < pt.dt.NumColumns(); i++ {
+			pt.dt.Columns[i].SetMetaData("grid-fill", "0.9")
+		}
+	}
+}
+
+// TransInfPatterns generates the transitive inference patterns for
+// Config.TransInf: AB has the premise pairs of the A > B > ... hierarchy,
+// and AC those of a second hierarchy starting at the next letter, each
+// with its own context, and the lures have all the pairs of a third,
+// untrained hierarchy.  The test tables have all the pairs of each
+// hierarchy, with the winner to be recalled.
+func (ss *Sim) TransInfPatterns() {
+	ti := &ss.Config.TransInf
+	patgen.NewRand(ss.RandSeeds[0]) // separate stream, for reproducible patterns
+	shape := []int{6, 2, 3, 4}
+	nctxt := max(shape[0]*shape[1]-3*ti.ItemPools, 0)
+	lists := []struct {
+		list, ctxt  string
+		first       rune
+		train, test *table.Table
+	}{
+		{"ab", "ctxtAB", 'A', ss.TrainAB, ss.TestAB},
+		{"ac", "ctxtAC", 'A' + rune(ti.NItems), ss.TrainAC, ss.TestAC},
+		{"lure", "ctxtLure", 'A' + rune(2*ti.NItems), nil, ss.TestLure},
+	}
+	voc := patgen.Vocab{}
+	ss.PoolVocab = voc
+	for _, ls := range lists {
+		errors.Log(ti.AddVocab(voc, ls.list, ti.Items(ls.first), shape[2], shape[3]))
+		errors.Log(ti.AddCtxtVocab(voc, ls.ctxt, nctxt, shape[2], shape[3]))
+	}
+	pats := []struct {
+		dt         *table.Table
+		name, desc string
+	}{
+		{ss.TrainAB, "TrainAB", "AB Transitive Inference Premise Patterns"},
+		{ss.TrainAC, "TrainAC", "AC Transitive Inference Premise Patterns"},
+		{ss.TestAB, "TestAB", "AB Transitive Inference Testing Patterns"},
+		{ss.TestAC, "TestAC", "AC Transitive Inference Testing Patterns"},
+		{ss.TestLure, "TestLure", "Lure Transitive Inference Testing Patterns"},
+	}
+	for _, pt := range pats {
+		pt.dt.SetMetaData("name", pt.name)
+		pt.dt.SetMetaData("desc", pt.desc)
+	}
+	for _, ls := range lists {
+		errors.Log(ti.Tables(voc, ls.list, ls.ctxt, shape, ls.train, ls.test))
+	}
+	for _, pt := range pats {
+		for i := 1; i < pt.dt.NumColumns(); i++ {
+			pt.dt.Columns[i].SetMetaData("grid-fill", "0.9")
+		}
+	}
+}
+
+// TransInfStats computes the symbolic distance effect from the test
+// trial log, for Config.TransInf, into the TIDist table and the
+// TIDistSlope stat.
+func (ss *Sim) TransInfStats() {
+	if !ss.Config.TransInf.On {
+		return
+	}
+	sd, err := ss.Config.TransInf.SymbolicDistance(ss.Logs.Table(etime.Test, etime.Trial), "TrialName", "TICorrect")
+	if errors.Log(err) != nil {
+		return
+	}
+	ss.Stats.SetFloat("TIDistSlope", leabra.DistSlope(sd, "Inner"))
+	ss.Logs.MiscTables["TIDist"] = sd
 }
 
 func (ss *Sim) ConfigPats() {
@@ -607,14 +1204,25 @@ func (ss *Sim) ConfigPats() {
 	patgen.AddVocabPermutedBinary(ss.PoolVocab, "lB", npats, plY, plX, pctAct, minDiff)
 	patgen.AddVocabPermutedBinary(ss.PoolVocab, "ctxt", 3, plY, plX, pctAct, minDiff) // totally diff
 
-	for i := 0; i < (ecY-1)*ecX*3; i++ { // 12 contexts! 1: 1 row of stimuli pats; 3: 3 diff ctxt bases
-		list := i / ((ecY - 1) * ecX)
+	nctxt := (ecY - 1) * ecX
+	for i := 0; i < nctxt*3; i++ { // 12 contexts! 1: 1 row of stimuli pats; 3: 3 diff ctxt bases
+		list := i / nctxt
 		ctxtNm := fmt.Sprintf("ctxt%d", i+1)
 		tsr, _ := patgen.AddVocabRepeat(ss.PoolVocab, ctxtNm, npats, "ctxt", list)
 		patgen.FlipBitsRows(tsr, ctxtflip, ctxtflip, 1, 0)
-		//todo: also support drifting
-		//solution 2: drift based on last trial (will require sequential learning)
-		//patgen.VocabDrift(ss.PoolVocab, ss.NFlipBits, "ctxt"+strconv.Itoa(i+1))
+	}
+	if ss.Config.DriftCtxt {
+		// drift based on the last trial: each context pool drifts across
+		// trials, with AC continuing from the end of AB, and lures separate.
+		dc := &leabra.DriftingContext{Rate: ss.Config.CtxtDrift}
+		for j := 0; j < nctxt; j++ {
+			ab, ac, lure := fmt.Sprintf("ctxt%d", j+1), fmt.Sprintf("ctxt%d", j+1+nctxt), fmt.Sprintf("ctxt%d", j+1+2*nctxt)
+			errors.Log(dc.Start(ss.PoolVocab, ab, ab, 0))
+			dc.AddVocab(ss.PoolVocab, ab, ab, npats)
+			dc.AddVocab(ss.PoolVocab, ac, ab, npats)
+			errors.Log(dc.Start(ss.PoolVocab, lure, lure, 0))
+			dc.AddVocab(ss.PoolVocab, lure, lure, npats)
+		}
 	}
 
 	patgen.InitPats(ss.TrainAB, "TrainAB", "TrainAB Pats", "Input", "ECout", npats, ecY, ecX, plY, plX)
@@ -651,6 +1259,7 @@ func (ss *Sim) ConfigPats() {
 	ss.TestAll.AppendRows(ss.TestAC)
 	ss.TestAll.MetaData["name"] = "TestAll"
 	ss.TestAll.MetaData["desc"] = "All Testing Patterns"
+	ss.ConfigCues()
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////
@@ -667,7 +1276,26 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("ACMem", 0.0)
 	ss.Stats.SetFloat("LureMem", 0.0)
 	ss.Stats.SetFloat("Mem", 0.0)
+	ss.Stats.SetFloat("MemCorrel", 0.0)
+	ss.Stats.SetFloat("Novelty", 0.0)
+	ss.Stats.SetString("NearestECout", "")
+	ss.Stats.SetFloat("NearestECoutCos", 0.0)
+	ss.Stats.SetString("NearestCA3", "")
+	ss.Stats.SetFloat("NearestCA3Cos", 0.0)
+	ss.Stats.SetFloat("Intrusion", 0.0)
+	ss.Stats.SetFloat("CortexABCorrel", 0.0)
+	ss.Stats.SetFloat("CortexACCorrel", 0.0)
+	ss.Stats.SetFloat("DGOrthog", 0.0)
+	ss.Stats.SetFloat("CA3Orthog", 0.0)
+	ss.Stats.SetFloat("Completion", 0.0)
+	ss.Stats.SetFloat("NWayRecall", 0.0)
+	ss.Stats.SetFloat("TICorrect", 0.0)
+	ss.Stats.SetFloat("TIDistSlope", 0.0)
+	ss.Stats.SetString("Cue", "")
 	ss.Stats.SetInt("FirstPerfect", -1) // first epoch at when AB Mem is perfect
+	ss.Stats.SetFloat("SwitchABMem", math.NaN())
+	ss.Stats.SetFloat("SwitchCortexAB", math.NaN())
+	ss.Stats.SetInt("ConsolCost", 0)
 
 	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
@@ -704,93 +1332,302 @@ func (ss *Sim) TrialStats() {
 	ss.MemStats(ss.Loops.Mode.(etime.Modes))
 }
 
-// MemStats computes ActM vs. Target on ECout with binary counts
+// MemStats computes ActM vs. Target on ECout with binary counts,
+// using the Config.Readout transform to binarize the values.
 // must be called at end of 3rd quarter so that Target values are
 // for the entire full pattern as opposed to the plus-phase target
 // values clamped from ECin activations
 func (ss *Sim) MemStats(mode etime.Modes) {
-	memthr := 0.34 // ss.Config.Mod.MemThr
-	ecout := ss.Net.LayerByName("ECout")
-	inp := ss.Net.LayerByName("Input") // note: must be input b/c ECin can be active
-	_ = inp
-	nn := ecout.Shape.Len()
-	actThr := float32(0.5)
-	trgOnWasOffAll := 0.0 // all units
-	trgOnWasOffCmp := 0.0 // only those that required completion, missing in ECin
-	trgOffWasOn := 0.0    // should have been off
-	cmpN := 0.0           // completion target
-	trgOnN := 0.0
-	trgOffN := 0.0
-	actMi, _ := ecout.UnitVarIndex("ActM")
-	targi, _ := ecout.UnitVarIndex("Targ")
-
-	ss.Stats.SetFloat("ABMem", math.NaN())
-	ss.Stats.SetFloat("ACMem", math.NaN())
-	ss.Stats.SetFloat("LureMem", math.NaN())
+	ro := &ss.Config.Readout
+	ecout := ss.Layers.ECout
+	trialnm := ss.Stats.String("TrialName")
+	test := mode == etime.Test
+	leabra.MemStats(ecout, ss.Layers.Input, ro, ss.Config.MemScore.Thr, trialnm, test).SetStats(&ss.Stats)
+
+	var actm, trg []float32
+	ecout.UnitValuesReadout(&actm, "ActM", ro, 0)
+	ecout.UnitValuesReadout(&trg, "Targ", ro, 0) // full pattern target
+	plUnits := ecout.Shape.DimSize(2) * ecout.Shape.DimSize(3)
+	ss.Stats.SetFloat("TICorrect", math.NaN())
+	if ti := &ss.Config.TransInf; ti.On && test {
+		ss.Stats.SetFloat("TICorrect", float64(ti.Correct(trialnm, actm, trg, plUnits)))
+		if ti.Dist(trialnm) > 1 { // transitive inference probes are not in the AB or AC memory stats
+			ss.Stats.SetFloat("ABMem", math.NaN())
+			ss.Stats.SetFloat("ACMem", math.NaN())
+			ss.Stats.SetFloat("LureMem", math.NaN())
+		}
+	}
+	if list, _, _ := strings.Cut(trialnm, "_"); ss.Config.NWay.On && test && list != "lure" {
+		cue := ss.Stats.String("Cue")
+		ss.NWayRecall.Add(cue, ss.Config.NWay.Recall(list, cue, actm, trg, plUnits, ss.Config.MemScore.Thr))
+	}
 
+	ecout.UnitValues(&actm, "ActM", 0)
+	ss.Stats.SetFloat("Novelty", float64(ss.Net.LayerByName("Novelty").Neurons[0].ActM))
+	ss.NearestStats(mode, actm)
+}
+
+// NearestStats finds the stored training patterns nearest to the given
+// ECout activity and the CA3 activity, recording the CA3 activity for
+// each training trial.  Test trials are classified as intrusions if the
+// nearest ECout pattern is the same item from the other list, e.g.,
+// ac_3 when cued with ab_3.
+func (ss *Sim) NearestStats(mode etime.Modes, ecAct []float32) {
+	var ca3Act, ca1Act []float32
+	ss.Layers.CA3.UnitValues(&ca3Act, "ActM", 0)
 	trialnm := ss.Stats.String("TrialName")
-	isAB := strings.Contains(trialnm, "ab")
-	isAC := strings.Contains(trialnm, "ac")
-
-	for ni := 0; ni < nn; ni++ {
-		actm := ecout.UnitValue1D(actMi, ni, 0)
-		trg := ecout.UnitValue1D(targi, ni, 0) // full pattern target
-		inact := inp.UnitValue1D(actMi, ni, 0)
-		if trg < actThr { // trgOff
-			trgOffN += 1
-			if actm > actThr {
-				trgOffWasOn += 1
-			}
-		} else { // trgOn
-			trgOnN += 1
-			if inact < actThr { // missing in ECin -- completion target
-				cmpN += 1
-				if actm < actThr {
-					trgOnWasOffAll += 1
-					trgOnWasOffCmp += 1
-				}
-			} else {
-				if actm < actThr {
-					trgOnWasOffAll += 1
-				}
-			}
+	if mode == etime.Train {
+		ss.StoredCA3.Set(trialnm, ca3Act)
+		ss.Layers.CA1.UnitValues(&ca1Act, "ActM", 0)
+		ss.StoredCA1.Set(trialnm, ca1Act)
+	}
+	ec := ss.StoredECout.Nearest1(ecAct)
+	ca3 := ss.StoredCA3.Nearest1(ca3Act)
+	ss.Stats.SetString("NearestECout", ec.Name)
+	ss.Stats.SetFloat("NearestECoutCos", float64(ec.Cos))
+	ss.Stats.SetString("NearestCA3", ca3.Name)
+	ss.Stats.SetFloat("NearestCA3Cos", float64(ca3.Cos))
+	ss.Stats.SetFloat("Intrusion", math.NaN())
+	list, item, ok := strings.Cut(trialnm, "_")
+	if mode == etime.Test && ok && (list == "ab" || list == "ac") {
+		nlist, nitem, _ := strings.Cut(ec.Name, "_")
+		intr := 0.0
+		if nitem == item && nlist != list {
+			intr = 1
+		}
+		ss.Stats.SetFloat("Intrusion", intr)
+	}
+}
+
+// cueMask returns a mask of the units in the ECout patterns that are
+// part of the cue, i.e., not in the pools that are empty in the
+// test inputs, which are the ones to be recalled.
+func (ss *Sim) cueMask() []bool {
+	inp := ss.TestAB.Tensor("Input", 0)
+	nu := inp.Len()
+	npl := inp.Shape().DimSize(2) * inp.Shape().DimSize(3)
+	mask := make([]bool, nu)
+	for st := 0; st < nu; st += npl {
+		on := false
+		for i := st; i < st+npl; i++ {
+			on = on || inp.Float1D(i) > 0
 		}
+		for i := st; i < st+npl; i++ {
+			mask[i] = on
+		}
+	}
+	return mask
+}
+
+// ACStartEpoch returns the first epoch of training on the AC items,
+// or -1 if training has not yet switched from AB to AC.
+func (ss *Sim) ACStartEpoch() int {
+	fp := ss.Stats.Int("FirstPerfect")
+	if fp < 0 {
+		return -1
 	}
-	trgOnWasOffAll /= trgOnN
-	trgOffWasOn /= trgOffN
-	if mode == etime.Train { // no compare
-		if trgOnWasOffAll < memthr && trgOffWasOn < memthr {
-			ss.Stats.SetFloat("Mem", 1)
-		} else {
-			ss.Stats.SetFloat("Mem", 0)
+	return fp + 1
+}
+
+// Consolidate runs Config.NReplays offline hippocampal replay trials
+// (fewer if the Schedule Budget runs out), each cued by a CA3 pattern
+// stored during training, chosen according to the Schedule OldFrac mix
+// of AB and AC items, and trains the Cortex network to produce the
+// replayed ECout pattern from its cue pools.  Then the Cortex is tested
+// on the AB and AC test items, recording the mean MemCorrel of its output
+// as CortexABCorrel and CortexACCorrel.
+func (ss *Sim) Consolidate() {
+	sch := &ss.Config.Schedule
+	cost := ss.Stats.Int("ConsolCost")
+	nrep := sch.Allow(ss.Config.NReplays, cost)
+	if nrep <= 0 || ss.StoredCA3.Len() == 0 {
+		return
+	}
+	ss.Stats.SetInt("ConsolCost", cost+nrep)
+	old := make([]bool, ss.StoredCA3.Len())
+	for i, nm := range ss.StoredCA3.Names {
+		old[i] = strings.HasPrefix(nm, "ab")
+	}
+	rnd := randx.NewGlobalRand()
+	ctx := leabra.NewContext()
+	ctx.Mode = etime.Test
+	in := ss.Cortex.LayerByName("Input")
+	out := ss.Cortex.LayerByName("Output")
+	mask := ss.cueMask()
+	var ecout []float32
+	for range nrep {
+		pi := sch.ReplayCue(old, rnd)
+		ss.Net.ReplayToCortex(ctx, &ss.Config.Replay, ss.StoredCA3.Pats[pi], ss.Cortex, in, out, mask, &ecout)
+	}
+	for _, ab := range []string{"AB", "AC"} {
+		dt := ss.TestAB
+		if ab == "AC" {
+			dt = ss.TestAC
 		}
-	} else { // test
-		if cmpN > 0 { // should be
-			trgOnWasOffCmp /= cmpN
+		sum := 0.0
+		for ri := range dt.Rows {
+			sum += float64(ss.Cortex.MemCorrelTrial(ctx, in, out, dt.Tensor("Input", ri), dt.Tensor("ECout", ri)))
 		}
-		mem := 0.0
-		if trgOnWasOffCmp < memthr && trgOffWasOn < memthr {
-			mem = 1.0
+		ss.Stats.SetFloat("Cortex"+ab+"Correl", sum/float64(max(dt.Rows, 1)))
+	}
+}
+
+// AdvanceCurriculum counts the training epoch in the Curriculum, which
+// advances to its next stage when the stage criterion is reached on the
+// stats of the test epoch, if tested, or after the stage epochs.  At the
+// end of the first stage (the switch from AB to AC), it records the
+// switch stats and consolidates EWC.  It then configures the training
+// environment for the next epoch, which has new random interleaved items
+// in each epoch of interleaved stages.
+func (ss *Sim) AdvanceCurriculum(tested bool) {
+	cu := &ss.Config.Curriculum
+	tstEpcLog := ss.Logs.Tables[etime.Scope(etime.Test, etime.Epoch)]
+	stat := func(name string) float64 {
+		if !tested || tstEpcLog.Table.Rows == 0 {
+			return math.NaN()
 		}
-		ss.Stats.SetFloat("Mem", mem)
-		switch {
-		case isAB:
-			ss.Stats.SetFloat("ABMem", mem)
-		case isAC:
-			ss.Stats.SetFloat("ACMem", mem)
-		default:
-			ss.Stats.SetFloat("LureMem", mem)
+		return tstEpcLog.Table.Float(name, tstEpcLog.Table.Rows-1)
+	}
+	prev := cu.Stage
+	adv := cu.EpochEnd(stat)
+	if adv && prev == 0 {
+		ss.Stats.SetInt("FirstPerfect", ss.Stats.Int("Epoch"))
+		ss.Stats.SetFloat("SwitchABMem", stat("ABMem"))
+		ss.Stats.SetFloat("SwitchCortexAB", ss.Stats.Float("CortexABCorrel"))
+		ss.Net.ConsolidateEWC() // only paths with EWC.On
+	}
+	if adv || len(cu.Current().Tables) > 1 {
+		ss.ConfigTrainEnv(ss.Envs.ByMode(etime.Train).(*env.FixedTable))
+	}
+}
+
+// Interleave sets the training environment for the next epoch of AC
+// training to interleave a random Config.Schedule.Interleave proportion
+// of the AB training items with the AC items (fewer if the Schedule
+// Budget runs out), counting them in the ConsolCost.
+func (ss *Sim) Interleave() {
+	sch := &ss.Config.Schedule
+	if sch.Interleave <= 0 || ss.ACStartEpoch() < 0 || !slices.Equal(ss.Config.Curriculum.Current().Tables, []string{"TrainAC"}) {
+		return
+	}
+	trn := ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+	cost := ss.Stats.Int("ConsolCost")
+	nab := ss.TrainAB.Rows
+	nint := sch.Allow(sch.NInterleave(nab), cost)
+	if nint <= 0 {
+		if trn.Table.Table != ss.TrainAC {
+			trn.Config(ss.TrainIndexView(ss.TrainAC))
+			trn.Validate()
 		}
+		return
+	}
+	ss.Stats.SetInt("ConsolCost", cost+nint)
+	dt := ss.TrainAB.Clone()
+	dt.AppendRows(ss.TrainAC)
+	dt.SetMetaData("name", "TrainInterleave")
+	ix := table.NewIndexView(dt)
+	ix.Indexes = ix.Indexes[:0]
+	for i := range ss.TrainAC.Rows {
+		ix.Indexes = append(ix.Indexes, nab+i)
+	}
+	ix.Indexes = append(ix.Indexes, rand.Perm(nab)[:nint]...)
+	errors.Log(ss.MPI.ShardIndexView(ix))
+	trn.Config(ix)
+	trn.Validate()
+}
+
+// RetrievalDynTable updates the RetrievalDyn misc table with the mean
+// retrieval dynamics curves for each layer and trial type, from the
+// current test epoch.
+func (ss *Sim) RetrievalDynTable() {
+	dt := table.NewTable()
+	for _, rd := range ss.RetrievalDyn {
+		rd.AddToTable(dt, true)
+	}
+	dt.SetMetaData("XAxis", "Cycle")
+	ss.Logs.MiscTables["RetrievalDyn"] = dt
+	if plt, ok := ss.GUI.Plots[etime.ScopeKey("RetrievalDyn")]; ok {
+		plt.SetTable(dt)
+		plt.GoUpdatePlot()
+	}
+}
+
+// ActRFs are the specs of the activity-based receptive fields computed
+// when Config.Log.RFs is on, as "Layer:Source", i.e., the Input patterns
+// weighted by the activity of each DG and CA3 unit (see estats.InitActRFs).
+var ActRFs = []string{"DG:Input", "CA3:Input"}
+
+// WtRFs are the specs of the weight projection receptive fields computed
+// when Config.Log.RFs is on, as the layers from the receiving layer back
+// to the source layer, including the DG to CA3 mossy fibers for the CA3
+// (see leabra.Network.WeightRFs).
+var WtRFs = []string{"DG:ECin", "CA3:DG:ECin"}
+
+// ConfigRFs configures the ActRFs and WtRFs receptive fields,
+// if Config.Log.RFs is on.
+func (ss *Sim) ConfigRFs() {
+	if !ss.Config.Log.RFs {
+		return
+	}
+	ss.Stats.InitActRFs(ss.Net, ActRFs, "ActM")
+	errors.Log(ss.Net.WeightRFs(&ss.WtRFs, WtRFs...))
+}
 
+// UpdateRFs computes the ActRFs over the current test epoch, and the WtRFs
+// for the current weights, and updates their display in the GUI.
+func (ss *Sim) UpdateRFs() {
+	ss.Stats.ActRFsAvgNorm()
+	errors.Log(ss.Net.WeightRFs(&ss.WtRFs, WtRFs...))
+	if ss.Config.GUI {
+		ss.GUI.ViewActRFs(&ss.Stats.ActRFs)
+		ss.GUI.ViewActRFs(&ss.WtRFs)
+	}
+}
+
+// SaveCycleRSA saves the CycleRSA misc table of the last test epoch
+// to a _cyc_rsa.tsv file for the current run, in nogui runs.
+func (ss *Sim) SaveCycleRSA() {
+	if ss.Config.GUI || ss.MPI.Rank() != 0 {
+		return
+	}
+	dt := ss.Logs.MiscTable("CycleRSA")
+	if dt.Rows == 0 {
+		return
 	}
-	ss.Stats.SetFloat("TrgOnWasOffAll", trgOnWasOffAll)
-	ss.Stats.SetFloat("TrgOnWasOffCmp", trgOnWasOffCmp)
-	ss.Stats.SetFloat("TrgOffWasOn", trgOffWasOn)
+	ctrString := ss.Stats.PrintValues([]string{"Run"}, []string{"%03d"}, "_")
+	fnm := elog.LogFilename("cyc_rsa", ss.Net.Name, ss.Stats.String("RunName")+"_"+ctrString)
+	errors.Log(dt.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+}
 
+// PatSepStats computes the pattern separation of the ECin activity
+// patterns in the DG and CA3, and the pattern completion of the full
+// ECout target from the partial ECin cue, over the test trials in the
+// current test epoch.  The pairwise and per-trial results are in the
+// PatSep and PatComp misc tables, and the summary DGOrthog, CA3Orthog
+// and Completion stats are logged in the test epoch log.
+func (ss *Sim) PatSepStats() {
+	dt := ss.Logs.Table(etime.Test, etime.Trial)
+	col := func(nm string) tensor.Tensor {
+		return errors.Log1(dt.ColumnByName(nm))
+	}
+	names := col("TrialName")
+	ecin := col("ECin_ActM")
+	sep := table.NewTable()
+	for _, lnm := range []string{"DG", "CA3"} {
+		leabra.AddPatternSeparation(sep, lnm, ecin, col(lnm+"_ActM"), names)
+		ss.Stats.SetFloat(lnm+"Orthog", leabra.OrthogIndex(sep, lnm).Index)
+	}
+	comp := leabra.PatternCompletion("ECout", ecin, col("ECout_ActM"), col("ECout_Act"), names)
+	ss.Stats.SetFloat("Completion", leabra.PatternCompletionStats(comp, "").Completion)
+	ss.Logs.MiscTables["PatSep"] = sep
+	ss.Logs.MiscTables["PatComp"] = comp
 }
 
 func (ss *Sim) RunStats() {
 	dt := ss.Logs.Table(etime.Train, etime.Run)
+	if dt.Rows < 2 { // quantiles need at least 2 runs
+		return
+	}
 	runix := table.NewIndexView(dt)
 	spl := split.GroupBy(runix, "Expt")
 	split.DescColumn(spl, "TstABMem")
@@ -810,15 +1647,49 @@ func (ss *Sim) RunStats() {
 	st.SetMetaData("TstABMem:Min:On", "+")
 	st.SetMetaData("TstABMem:Count:On", "-")
 
-	plt.SetTable(st)
-	plt.GoUpdatePlot()
+	if plt != nil { // no GUI
+		plt.SetTable(st)
+		plt.GoUpdatePlot()
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
 func (ss *Sim) AddLogItems() {
-	itemNames := []string{"TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem", "ABMem", "ACMem", "LureMem"}
+	ms := &ss.Config.MemScore
+	itemNames := []string{"TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem", "ABMem", "ACMem", "LureMem", "Intrusion", "DGOrthog", "CA3Orthog", "Completion", "NWayRecall", "TICorrect", "TIDistSlope"}
+	if ms.Correl || ms.DPrime || ms.ROC {
+		ss.Logs.AddStatAggItem("MemCorrel", etime.Run, etime.Epoch, etime.Trial)
+		if ms.Correl {
+			itemNames = append(itemNames, "MemCorrel")
+		}
+	}
+	for _, ab := range []string{"AB", "AC"} {
+		prefix := strings.ToLower(ab)
+		if ms.DPrime {
+			ss.Logs.AddItem(&elog.Item{
+				Name: ab + "DPrime",
+				Type: reflect.Float64,
+				Plot: true,
+				Write: elog.WriteMap{
+					etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+						ctx.SetFloat64(leabra.MemDiscrim(ss.Logs.Table(etime.Test, etime.Trial), prefix, false))
+					}}})
+			itemNames = append(itemNames, ab+"DPrime")
+		}
+		if ms.ROC {
+			ss.Logs.AddItem(&elog.Item{
+				Name: ab + "ROC",
+				Type: reflect.Float64,
+				Plot: true,
+				Write: elog.WriteMap{
+					etime.Scope(etime.Test, etime.Epoch): func(ctx *elog.Context) {
+						ctx.SetFloat64(leabra.MemDiscrim(ss.Logs.Table(etime.Test, etime.Trial), prefix, true))
+					}}})
+			itemNames = append(itemNames, ab+"ROC")
+		}
+	}
 	for _, st := range itemNames {
 		stnm := st
 		tonm := "Tst" + st
@@ -837,12 +1708,8 @@ func (ss *Sim) AddLogItems() {
 }
 
 func (ss *Sim) ConfigLogs() {
-	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
-
-	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
+	leabra.LogAddStdItems(&ss.Logs, &ss.Stats, ss.Params.RunName(0))
 	ss.Logs.AddStatIntNoAggItem(etime.AllModes, etime.AllTimes, "Expt")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
 
 	ss.Logs.AddStatAggItem("TrgOnWasOffAll", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("TrgOnWasOffCmp", etime.Run, etime.Epoch, etime.Trial)
@@ -851,7 +1718,36 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.AddStatAggItem("ACMem", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("LureMem", etime.Run, etime.Epoch, etime.Trial)
 	ss.Logs.AddStatAggItem("Mem", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("Novelty", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatStringItem(etime.Test, etime.Trial, "NearestECout", "NearestCA3")
+	ss.Logs.AddStatAggItem("NearestECoutCos", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("NearestCA3Cos", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("Intrusion", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("TICorrect", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatFloatNoAggItem(etime.Train, etime.Epoch, "CortexABCorrel", "CortexACCorrel")
+	ss.Logs.AddStatFloatNoAggItem(etime.Test, etime.Epoch, "DGOrthog", "CA3Orthog", "Completion", "NWayRecall", "TIDistSlope")
 	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Run, "FirstPerfect")
+	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Epoch, "FirstPerfect") // AB to AC switch, for runcmp -align
+	ss.Logs.AddStatFloatNoAggItem(etime.Train, etime.Run, "CortexABCorrel", "CortexACCorrel")
+	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Epoch, "ConsolCost")
+	ss.Logs.AddStatIntNoAggItem(etime.Train, etime.Run, "ConsolCost")
+	leabra.LogAddCurriculumItem(&ss.Logs, &ss.Config.Curriculum, etime.Train, etime.Epoch)
+	leabra.LogAddParamSchedItem(&ss.Logs, &ss.Config.ParamSched, etime.Train, etime.Epoch)
+	// retroactive interference: drop in AB memory from the switch to AC to the end of the run
+	ss.Logs.AddItem(&elog.Item{
+		Name: "ABForget",
+		Type: reflect.Float64,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Run): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.Stats.Float("SwitchABMem") - ctx.ItemFloat(etime.Test, etime.Epoch, "ABMem"))
+			}}})
+	ss.Logs.AddItem(&elog.Item{
+		Name: "CortexABForget",
+		Type: reflect.Float64,
+		Write: elog.WriteMap{
+			etime.Scope(etime.Train, etime.Run): func(ctx *elog.Context) {
+				ctx.SetFloat64(ss.Stats.Float("SwitchCortexAB") - ss.Stats.Float("CortexABCorrel"))
+			}}})
 
 	// ss.Logs.AddCopyFromFloatItems(etime.Train, etime.Epoch, etime.Test, etime.Epoch, "Tst", "PhaseDiff", "UnitErr", "PctCor", "PctErr", "TrgOnWasOffAll", "TrgOnWasOffCmp", "TrgOffWasOn", "Mem")
 	ss.AddLogItems()
@@ -860,18 +1756,21 @@ func (ss *Sim) ConfigLogs() {
 
 	layers := ss.Net.LayersByType(leabra.SuperLayer, leabra.CTLayer, leabra.TargetLayer)
 	leabra.LogAddDiagnosticItems(&ss.Logs, layers, etime.Train, etime.Epoch, etime.Trial)
+	leabra.LogAddLearnProgressItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch)
+	leabra.LogAddPathStatsItems(&ss.Logs, ss.Net, []string{"ECinToDG", "ECinToCA3", "CA3ToCA3", "DGToCA3", "CA3ToCA1"}, etime.Train, etime.Run, etime.Epoch)
 	leabra.LogInputLayer(&ss.Logs, ss.Net, etime.Train)
 
 	// leabra.LogAddPCAItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "TargetLayer")
 	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "TargetLayer")
+	ss.Logs.AddLayerTensorItems(ss.Net, "ActM", etime.Test, etime.Trial, "SuperLayer") // for PatSepStats
 
 	ss.Logs.PlotItems("ABMem", "ACMem", "LureMem")
 
 	// ss.Logs.PlotItems("TrgOnWasOffAll", "TrgOnWasOffCmp", "ABMem", "ACMem", "TstTrgOnWasOffAll", "TstTrgOnWasOffCmp", "TstMem", "TstABMem", "TstACMem")
 
-	ss.Logs.CreateTables()
+	leabra.LogConfigStdTables(&ss.Logs, &ss.Stats, ss.Net, etime.Scope(etime.Test, etime.Cycle))
 	ss.Logs.SetMeta(etime.Train, etime.Run, "TrgOnWasOffAll:On", "-")
 	ss.Logs.SetMeta(etime.Train, etime.Run, "TrgOnWasOffCmp:On", "-")
 	ss.Logs.SetMeta(etime.Train, etime.Run, "ABMem:On", "-")
@@ -889,13 +1788,6 @@ func (ss *Sim) ConfigLogs() {
 	ss.Logs.SetMeta(etime.Train, etime.Epoch, "Mem:On", "+")
 	ss.Logs.SetMeta(etime.Train, etime.Epoch, "TrgOnWasOffAll:On", "+")
 	ss.Logs.SetMeta(etime.Train, etime.Epoch, "TrgOffWasOn:On", "+")
-	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	// don't plot certain combinations we don't use
-	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Run)
-	// note: Analyze not plotted by default
-	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -916,11 +1808,13 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	case time == etime.Trial:
 		ss.TrialStats()
 		ss.StatCounters()
-		ss.Logs.LogRow(mode, time, row)
+		ss.LogFiles.LogRow(&ss.Logs, mode, time, row)
 		return // don't do reg below
+	case mode == etime.Train && time == etime.Epoch:
+		ss.MPI.GatherTableRows(&ss.Logs, mode, etime.Trial) // all trials across procs
 	}
 
-	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+	ss.LogFiles.LogRow(&ss.Logs, mode, time, row) // also logs to file, etc
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////
@@ -953,11 +1847,26 @@ func (ss *Sim) ConfigGUI() {
 	plt.Options.XAxis = "RunName"
 	plt.SetTable(dt)
 
+	stnm = "RetrievalDyn"
+	dt = ss.Logs.MiscTable(stnm)
+	bcp, _ = ss.GUI.Tabs.NewTab(stnm + " Plot")
+	plt = plotcore.NewSubPlot(bcp)
+	ss.GUI.Plots[etime.ScopeKey(stnm)] = plt
+	plt.Options.Title = "Retrieval Dynamics"
+	plt.Options.XAxis = "Cycle"
+	plt.SetTable(dt)
+
+	if ss.Config.Log.RFs {
+		ss.GUI.AddActRFGridTabs(&ss.Stats.ActRFs)
+		ss.GUI.AddActRFGridTabs(&ss.WtRFs)
+	}
+
 	ss.GUI.FinalizeGUI(false)
 }
 
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
+	ss.Stepper.AddToolbar(p, &ss.GUI)
 
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
@@ -994,3 +1903,28 @@ func (ss *Sim) RunGUI() {
 	ss.ConfigGUI()
 	ss.GUI.Body.RunMainWindow()
 }
+
+func (ss *Sim) RunNoGUI() {
+	if ss.Config.Note != "" {
+		mpi.Printf("Note: %s\n", ss.Config.Note)
+	}
+	if ss.Config.Log.SaveWeights {
+		mpi.Printf("Saving final weights per run\n")
+	}
+	runName := ss.Params.RunName(0)
+	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
+	netName := ss.Net.Name
+
+	ss.LogFiles.Format = ss.Config.Log.Format
+	ss.LogFiles.RowGroup = ss.Config.Log.RowGroup
+	if ss.MPI.Rank() == 0 { // all procs have the same logs
+		ss.LogFiles.SetStdLogFiles(&ss.Logs, &ss.Config.Log, netName, runName)
+	}
+
+	ss.Init()
+
+	mpi.Printf("Running %d Runs\n", ss.Config.NRuns)
+	ss.Loops.Run(etime.Train)
+
+	ss.LogFiles.CloseLogFiles(&ss.Logs)
+}
@@ -2651,7 +2651,9 @@ var InnerLoopParams = []string{"List100", "List125", "List150", "List175", "List
 
 //var InnerLoopParams = []string{"List020", "List040", "List060", "List080", "List100"}
 
-// TwoFactorRun runs outer-loop crossed with inner-loop params
+// TwoFactorRun runs outer-loop crossed with inner-loop params.
+// See search.Sweep for a general N-factor sweep, run as separate processes,
+// and search.Search (and the optimize command) for optimizing the params.
 func (ss *Sim) TwoFactorRun() {
 	tag := ss.Tag
 	usetag := tag
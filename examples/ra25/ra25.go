@@ -12,9 +12,14 @@ package main
 
 import (
 	"embed"
+	"fmt"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
+	"time"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/mpi"
 	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/core"
@@ -23,9 +28,10 @@ import (
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/math32/vecint"
 	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/stats/stats"
 	"cogentcore.org/core/tensor/table"
+	"cogentcore.org/core/tensor/tensorcore"
 	"cogentcore.org/core/tree"
-	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -52,6 +58,7 @@ func main() {
 	} else {
 		sim.RunNoGUI()
 	}
+	sim.MPI.Finalize()
 }
 
 // ParamSets is the default set of parameters.
@@ -100,6 +107,33 @@ var ParamSets = params.Sets{
 				"Path.Learn.WtBal.On": "true",
 			}},
 	},
+	"LrateCosine": {
+		{Sel: "Path", Desc: "warmup then cosine decay of the learning rate over the epochs",
+			Params: params.Params{
+				"Path.Learn.LrateSched.Type":   "LrateCosine",
+				"Path.Learn.LrateSched.Warmup": "5",
+				"Path.Learn.LrateSched.Epochs": "100",
+				"Path.Learn.LrateSched.Min":    "0.1",
+			}},
+	},
+	"LrateStep": {
+		{Sel: "Path", Desc: "halve the learning rate at given epochs",
+			Params: params.Params{
+				"Path.Learn.LrateSched.Type":   "LrateStep",
+				"Path.Learn.LrateSched.Steps":  "[25, 50]",
+				"Path.Learn.LrateSched.Factor": "0.5",
+			}},
+	},
+	"ClampSched": {
+		{Sel: "#Output", Desc: "hard clamp targets, then anneal to weak soft clamping",
+			Params: params.Params{
+				"Layer.Act.ClampSched.On":         "true",
+				"Layer.Act.ClampSched.HardEpochs": "10",
+				"Layer.Act.ClampSched.Epochs":     "50",
+				"Layer.Act.ClampSched.Start":      "1",
+				"Layer.Act.ClampSched.Min":        "0.2",
+			}},
+	},
 }
 
 // ParamConfig has config parameters related to sim params
@@ -168,6 +202,75 @@ type RunConfig struct {
 	// if non-empty, is the name of weights file to load at start
 	// of first run, for testing.
 	StartWts string
+
+	// if > 0, stops running at the end of each training trial with an
+	// error (TrlErr), on or after this epoch, using the Stepper, to
+	// examine the errors in the GUI.  0 = off.
+	StopOnErrAfter int `min:"0"`
+
+	// breakpoints on the state of the network (see leabra.Breakpoint),
+	// checked at the end of each trial using the Stepper, e.g.,
+	// "Hidden1.Act.Max > 0.95" or "*.Wt NaN".
+	Breakpoints []string
+
+	// checking for non-finite (NaN or Inf) values in the network state
+	// (see leabra.FiniteParams), e.g., -Run.Finite.Level FiniteAll
+	// -Run.Finite.Panic to stop a cluster run as soon as one appears.
+	Finite leabra.FiniteParams `display:"add-fields"`
+
+	// how often (in epochs) to save a checkpoint of the full sim state
+	// (weights, counters, env, stats, logs), to allow resuming an
+	// interrupted run.  0 = no checkpoints.
+	CheckpointInterval int `default:"0"`
+
+	// if non-empty, is the name of a checkpoint file to resume from,
+	// as saved with CheckpointInterval.
+	Resume string
+
+	// if non-empty, is a glob pattern of weights files (e.g., the final
+	// weights saved from multiple runs) to evaluate as an ensemble on the
+	// test patterns, along with the average of their weights, which is
+	// saved as the consensus weights, instead of training.
+	Ensemble string
+
+	// if non-empty, is the name of a weights file to load and test with
+	// each layer and pathway ablated in turn (see leabra.AblationSweep),
+	// saving the contribution table, instead of training.
+	Ablate string
+
+	// if non-empty, is the name of a weights file to load and compute
+	// input saliency maps for each of the test patterns, by occluding
+	// each input unit in turn (see leabra.SaliencyMaps), instead of training.
+	Saliency string
+
+	// if non-empty, is the name of a weights file to load and compute
+	// the contribution of each layer to the output for each of the test
+	// patterns, by perturbing its settled activity (see
+	// leabra.SensitivityProbe), saving the contribution table,
+	// instead of training.
+	Sensitivity string
+
+	// if non-empty, is the address (e.g., localhost:8080) to serve the
+	// remote control API on in nogui mode (see leabra.Server), for running
+	// the sim from other programs, instead of training.  The weights are
+	// saved and opened in the current directory.
+	Server string
+
+	// retention interval testing at the end of each training run, with
+	// delay epochs of no input, noise, or interfering list training before
+	// each delayed test (see leabra.RetentionParams), recording the
+	// forgetting curves across the delays.
+	Retention leabra.RetentionParams `display:"add-fields"`
+
+	// if true, cache the settled state of testing trials, and reuse it
+	// when the weights and inputs are unchanged, to speed up testing.
+	TestCache bool
+
+	// use MPI message passing for data-parallel training, with the
+	// training trials divided across procs running identical copies of
+	// the sim, and weight changes summed across procs.
+	// Requires building with -tags mpi, and running with mpirun.
+	MPI bool
 }
 
 // LogConfig has config parameters related to logging data
@@ -194,6 +297,67 @@ type LogConfig struct {
 	// if true, save network activation etc data from testing trials,
 	// for later viewing in netview.
 	NetData bool
+
+	// if true, record the network state at the end of every training and
+	// testing trial in nogui runs, saved in segment files of NetRecRecs
+	// records (see leabra.NetRecorder), for offline playback with PlayNet.
+	NetRecord bool
+
+	// number of records per NetRecord segment file.
+	NetRecRecs int `default:"500"`
+
+	// if non-empty, is a glob pattern of NetRecord segment files
+	// (*.netdata.json.gz) to open in the NetView of the GUI at startup,
+	// for offline playback of a nogui run.
+	PlayNet string
+
+	// names of the layers whose activity to record at every cycle of
+	// training and testing in nogui runs, as a spike raster / activity
+	// heatmap (see leabra.RasterRecorder), saved to a .raster.gz file
+	// for viewing after the run with PlayRaster.
+	Raster []string
+
+	// record the Raster layers only every this many cycles.
+	RasterEvery int `default:"1" min:"1"`
+
+	// if non-empty, is a .raster.gz file saved by a Raster recording
+	// to open in a grid tab for each layer in the GUI at startup,
+	// showing the activity of each unit (columns) over time (rows).
+	PlayRaster string
+
+	// if non-empty, the time scale of training (e.g., Epoch or Trial)
+	// at which to save snapshots of the network in nogui runs, rendered
+	// as in the NetView (see leabra.NetSnapshot), as image files in a
+	// _snapshots directory, for visualization without the GUI.
+	Snapshot string
+
+	// save a Snapshot only every this many iterations of its time scale.
+	SnapshotEvery int `default:"1" min:"1"`
+
+	// the variable to render in the Snapshots, e.g., Act, or r.Wt
+	// for the weights received by the SnapshotUnit, as in the NetView.
+	SnapshotVar string `default:"Act"`
+
+	// the unit whose pathways are rendered for a synaptic SnapshotVar,
+	// as the layer name and unit index, e.g., Hidden1:12.
+	SnapshotUnit string
+
+	// the image format of the Snapshots: png or svg.
+	SnapshotFormat string `default:"png"`
+
+	// additional stats to log, as leabra.LogSpec text, e.g.,
+	// "ActMAvg at Trial,Epoch for Hidden1,Hidden2 in Train plot".
+	Stats []string
+
+	// if true, save the provenance of the run (code version, args, seeds,
+	// params, config, network; see leabra.Provenance) in nogui runs,
+	// as a _provenance.json file next to the log files.
+	Provenance bool `default:"true"`
+
+	// if true, export the network architecture (layers and pathways;
+	// see leabra.NetGraph) in nogui runs, as a GraphViz .dot file and
+	// a _graph.json file, for documenting and diffing architectures.
+	Graph bool
 }
 
 // Config is a standard Sim config -- use as a starting point.
@@ -241,21 +405,37 @@ type Sim struct {
 	// contains looper control loops for running sim
 	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
 
+	// steps the Loops at the standard grains (Cycle, Quarter, Phase,
+	// AlphaCycle, Trial, Epoch, Run), with conditional stops
+	Stepper *leabra.Stepper `new-window:"+" display:"no-inline"`
+
 	// contains computed statistic values
 	Stats estats.Stats `new-window:"+"`
 
 	// Contains all the logs and information about the logs.'
 	Logs elog.Logs `new-window:"+"`
 
+	// the files that the logs are saved to in nogui runs
+	LogFiles leabra.LogFiles `display:"-"`
+
 	// the training patterns to use
 	Patterns *table.Table `new-window:"+" display:"no-inline"`
 
+	// the interfering list patterns for Config.Run.Retention
+	Interfere *table.Table `display:"-"`
+
+	// the forgetting curves measured with Config.Run.Retention
+	Retention leabra.RetentionCurve `display:"-"`
+
 	// Environments
 	Envs env.Envs `new-window:"+" display:"no-inline"`
 
 	// leabra timing parameters and state
 	Context leabra.Context `new-window:"+"`
 
+	// cache of settled testing trial states
+	TestCache leabra.TestCache `display:"-"`
+
 	// netview update parameters
 	ViewUpdate netview.ViewUpdate `display:"add-fields"`
 
@@ -264,11 +444,25 @@ type Sim struct {
 
 	// a list of random seeds to use for each run
 	RandSeeds randx.Seeds `display:"-"`
+
+	// MPI data-parallel training state, if Config.Run.MPI is on
+	MPI leabra.MPI `display:"-"`
+
+	// records the network state in nogui runs, if Config.Log.NetRecord is on
+	NetRecorder leabra.NetRecorder `display:"-"`
+
+	// records the activity of the Config.Log.Raster layers at every
+	// cycle in nogui runs
+	Raster leabra.RasterRecorder `display:"-"`
+
+	// renders the Config.Log.Snapshot images of the network in nogui runs
+	Snapshot leabra.NetSnapshot `display:"-"`
 }
 
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
-	econfig.Config(&ss.Config, "config.toml")
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
+	errors.Log(ss.MPI.Init(ss.Config.Run.MPI))
 	ss.Net = leabra.NewNetwork("RA25")
 	ss.Params.Config(ParamSets, ss.Config.Params.Sheet, ss.Config.Params.Tag, ss.Net)
 	ss.Stats.Init()
@@ -285,32 +479,39 @@ func (ss *Sim) New() {
 func (ss *Sim) ConfigAll() {
 	// ss.ConfigPatterns()
 	ss.OpenPatterns()
+	if ss.Config.Run.Retention.On && ss.Config.Run.Retention.Type == leabra.RetainInterfere {
+		ss.ConfigInterfere()
+	}
 	ss.ConfigEnv()
 	ss.ConfigNet(ss.Net)
+	ss.Net.Finite = ss.Config.Run.Finite
 	ss.ConfigLogs()
 	ss.ConfigLoops()
 	if ss.Config.Params.SaveAll {
 		ss.Config.Params.SaveAll = false
 		ss.Net.SaveParamsSnapshot(&ss.Params.Params, &ss.Config, ss.Config.Params.Good)
+		leabra.SaveEnvsReport(core.Filename(filepath.Join(leabra.ParamsSnapshotDir(ss.Config.Params.Good), "params_envs.txt")), ss.Envs.ByMode(etime.Train), ss.Envs.ByMode(etime.Test))
 		os.Exit(0)
 	}
 }
 
 func (ss *Sim) ConfigEnv() {
 	// Can be called multiple times -- don't re-create
-	var trn, tst *env.FixedTable
+	var trn *leabra.ShardEnv
+	var tst *env.FixedTable
 	if len(ss.Envs) == 0 {
-		trn = &env.FixedTable{}
+		trn = &leabra.ShardEnv{}
 		tst = &env.FixedTable{}
 	} else {
-		trn = ss.Envs.ByMode(etime.Train).(*env.FixedTable)
+		trn = ss.Envs.ByMode(etime.Train).(*leabra.ShardEnv)
 		tst = ss.Envs.ByMode(etime.Test).(*env.FixedTable)
 	}
 
 	// note: names must be standard here!
 	trn.Name = etime.Train.String()
-	trn.Config(table.NewIndexView(ss.Patterns))
-	trn.Validate()
+	trn.Table = table.NewIndexView(ss.Patterns)
+	trn.Seed = ss.RandSeeds[0]             // same on all procs
+	errors.Log(ss.MPI.ConfigShardEnv(trn)) // this proc's trials in each epoch, if MPI
 
 	tst.Name = etime.Test.String()
 	tst.Config(table.NewIndexView(ss.Patterns))
@@ -403,7 +604,7 @@ func (ss *Sim) InitRandSeed(run int) {
 func (ss *Sim) ConfigLoops() {
 	ls := looper.NewStacks()
 
-	trls := ss.Config.Run.NTrials
+	trls := ss.Config.Run.NTrials / ss.MPI.Size()
 
 	ls.AddStack(etime.Train).
 		AddTime(etime.Run, ss.Config.Run.NRuns).
@@ -418,6 +619,8 @@ func (ss *Sim) ConfigLoops() {
 
 	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
 	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+	leabra.LooperLrateSched(ls, ss.Net)                                    // Path.Learn.LrateSched
+	ss.MPI.ConfigLoops(ls, ss.Net, &ss.ViewUpdate)
 
 	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
 
@@ -428,19 +631,10 @@ func (ss *Sim) ConfigLoops() {
 		})
 	}
 
-	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
+	ss.TestCache.On = ss.Config.Run.TestCache
+	leabra.LooperTestCache(ls, ss.Net, &ss.Context, &ss.TestCache)
 
-	// Train stop early condition
-	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
-		// This is calculated in TrialStats
-		stopNz := ss.Config.Run.NZero
-		if stopNz <= 0 {
-			stopNz = 2
-		}
-		curNZero := ss.Stats.Int("NZero")
-		stop := curNZero >= stopNz
-		return stop
-	})
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
 
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
@@ -469,6 +663,8 @@ func (ss *Sim) ConfigLoops() {
 		ss.Log(mode.(etime.Modes), time.(etime.Times))
 	})
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
+	// FirstZero, LastZero, NZero reset per run, RunStats, and early stopping
+	leabra.LooperErrStats(ls, &ss.Logs, func() int { return ss.Config.Run.NZero })
 
 	ls.Loop(etime.Train, etime.Trial).OnEnd.Add("LogAnalyze", func() {
 		trnEpc := ls.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
@@ -477,16 +673,26 @@ func (ss *Sim) ConfigLoops() {
 		}
 	})
 
-	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
-		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
-	})
-
 	// Save weights to file, to look at later
 	ls.Loop(etime.Train, etime.Run).OnEnd.Add("SaveWeights", func() {
 		ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
 		leabra.SaveWeightsIfConfigSet(ss.Net, ss.Config.Log.SaveWeights, ctrString, ss.Stats.String("RunName"))
 	})
 
+	// Retention intervals after the final weights are saved
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("Retention", func() {
+		ss.RunRetention()
+	})
+
+	// Save checkpoint at end of epoch, after everything else
+	ls.Loop(etime.Train, etime.Epoch).OnEnd.Add("Checkpoint", func() {
+		trnEpc := ls.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+		if ss.Config.Run.CheckpointInterval <= 0 || (trnEpc+1)%ss.Config.Run.CheckpointInterval != 0 {
+			return
+		}
+		ss.SaveCheckpoint()
+	})
+
 	////////////////////////////////////////////
 	// GUI
 
@@ -496,6 +702,28 @@ func (ss *Sim) ConfigLoops() {
 				ss.GUI.NetDataRecord(ss.ViewUpdate.Text)
 			})
 		}
+		if ss.Config.Log.NetRecord && ss.MPI.Rank() == 0 {
+			leabra.LooperRecordNet(ls, &ss.NetRecorder, etime.Trial, func() string {
+				ss.StatCounters()
+				return ss.Stats.Print([]string{"Run", "Epoch", "Trial", "TrialName", "Cycle", "UnitErr", "TrlErr", "CorSim"})
+			})
+		}
+		if len(ss.Config.Log.Raster) > 0 && ss.MPI.Rank() == 0 {
+			leabra.LooperRecordRaster(ls, &ss.Raster, func() string {
+				return ss.Stats.String("TrialName")
+			})
+		}
+		if ss.Config.Log.Snapshot != "" && ss.MPI.Rank() == 0 {
+			var tm etime.Times
+			if err := tm.SetString(ss.Config.Log.Snapshot); err != nil {
+				mpi.Println(err)
+			} else {
+				leabra.LooperNetSnapshot(ls, &ss.Snapshot, etime.Train, tm, ss.Config.Log.SnapshotEvery, func() string {
+					ss.StatCounters()
+					return ss.Stats.Print([]string{"Run", "Epoch", "Trial", "TrialName", "Cycle", "UnitErr", "TrlErr", "CorSim"})
+				})
+			}
+		}
 	} else {
 		leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
 		leabra.LooperUpdatePlots(ls, &ss.GUI)
@@ -506,6 +734,16 @@ func (ss *Sim) ConfigLoops() {
 	if ss.Config.Debug {
 		mpi.Println(ls.DocString())
 	}
+	ss.Stepper = leabra.NewStepper(ls, &ss.Context, 75)
+	ss.Stepper.AddStopCond("StopOnErr", leabra.StepTrial, func() bool {
+		after := ss.Config.Run.StopOnErrAfter
+		return after > 0 && ls.Mode == etime.Train && trainEpoch.Counter.Cur >= after && ss.Stats.Float("TrlErr") > 0
+	})
+	for _, bp := range ss.Config.Run.Breakpoints {
+		_, err := ss.Stepper.AddBreakpoint(ss.Net, bp, leabra.StepTrial)
+		errors.Log(err)
+	}
+
 	ss.Loops = ls
 }
 
@@ -516,18 +754,15 @@ func (ss *Sim) ConfigLoops() {
 func (ss *Sim) ApplyInputs() {
 	ctx := &ss.Context
 	net := ss.Net
-	ev := ss.Envs.ByMode(ctx.Mode).(*env.FixedTable)
+	ev := ss.Envs.ByMode(ctx.Mode)
 	ev.Step()
-	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
-	net.InitExt()
-	ss.Stats.SetString("TrialName", ev.TrialName.Cur)
-	for _, lnm := range lays {
-		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
-		if pats != nil {
-			ly.ApplyExt(pats)
-		}
+	switch ev := ev.(type) {
+	case *leabra.ShardEnv:
+		ss.Stats.SetString("TrialName", ev.TrialName.Cur)
+	case *env.FixedTable:
+		ss.Stats.SetString("TrialName", ev.TrialName.Cur)
 	}
+	errors.Log(net.ApplyEnvInputs(ev))
 }
 
 // NewRun intializes a new run of the model, using the TrainEnv.Run counter
@@ -535,7 +770,7 @@ func (ss *Sim) ApplyInputs() {
 func (ss *Sim) NewRun() {
 	ctx := &ss.Context
 	ss.InitRandSeed(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
-	ss.Envs.ByMode(etime.Train).Init(0)
+	ss.Envs.ByMode(etime.Train).Init(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
 	ss.Envs.ByMode(etime.Test).Init(0)
 	ctx.Reset()
 	ctx.Mode = etime.Train
@@ -546,6 +781,28 @@ func (ss *Sim) NewRun() {
 	ss.Logs.ResetLog(etime.Test, etime.Epoch)
 }
 
+// SaveCheckpoint saves a checkpoint of the full sim state, which can be
+// resumed from using LoadCheckpoint.  Only for 0 rank MPI: saving does not
+// change the state, and all ranks resume from the rank 0 state, which is
+// the same on all ranks, as they use the same random seeds.
+func (ss *Sim) SaveCheckpoint() {
+	if mpi.WorldRank() > 0 {
+		return
+	}
+	ctrString := ss.Stats.PrintValues([]string{"Run", "Epoch"}, []string{"%03d", "%05d"}, "_")
+	fnm := leabra.CheckpointFilename(ss.Net, ctrString, ss.Stats.String("RunName"))
+	mpi.Printf("Saving Checkpoint to: %s\n", fnm)
+	errors.Log(leabra.SaveCheckpoint(fnm, ss.Net, ss.Loops, etime.Epoch, ss.Envs, &ss.Logs, &ss.Stats))
+}
+
+// LoadCheckpoint loads a checkpoint of the full sim state, saved by
+// SaveCheckpoint.  It must be called after NewRun.
+func (ss *Sim) LoadCheckpoint(fnm string) {
+	mpi.Printf("Resuming from Checkpoint: %s\n", fnm)
+	_, err := leabra.LoadCheckpoint(fnm, ss.Net, ss.Loops, ss.Envs, &ss.Logs, &ss.Stats)
+	errors.Log(err)
+}
+
 // TestAll runs through the full set of testing items
 func (ss *Sim) TestAll() {
 	ss.Envs.ByMode(etime.Test).Init(0)
@@ -553,6 +810,176 @@ func (ss *Sim) TestAll() {
 	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
 }
 
+// EvalEnsemble evaluates the ensemble of weights files matching the
+// Config.Run.Ensemble pattern on the test patterns, along with their
+// average weights, which are saved as the consensus weights.
+func (ss *Sim) EvalEnsemble() {
+	files, err := filepath.Glob(ss.Config.Run.Ensemble)
+	if errors.Log(err) != nil {
+		return
+	}
+	if len(files) == 0 {
+		mpi.Printf("No weights files match Ensemble: %s\n", ss.Config.Run.Ensemble)
+		return
+	}
+	fns := make([]core.Filename, len(files))
+	for i, fn := range files {
+		fns[i] = core.Filename(fn)
+	}
+	ss.NewRun()
+	ee, err := leabra.EvalWeightsEnsemble(ss.Net, fns, func(net *leabra.Network) float64 {
+		ss.TestAll()
+		dt := ss.Logs.Table(etime.Test, etime.Epoch)
+		return dt.Float("PctCor", dt.Rows-1)
+	})
+	if errors.Log(err) != nil {
+		return
+	}
+	for i, fn := range ee.Files {
+		mpi.Printf("%s\tPctCor: %g\n", fn, ee.Members[i])
+	}
+	mpi.Printf("%s\n", ee)
+	fnm := ss.Net.Name + "_" + ss.Stats.String("RunName") + "_ensemble.wts.gz"
+	mpi.Printf("Saving average weights to: %s\n", fnm)
+	errors.Log(ss.Net.SaveWeightsJSON(core.Filename(fnm)))
+}
+
+// Ablate tests the weights in the Config.Run.Ablate file with each layer
+// and pathway ablated in turn, and saves the resulting contribution table.
+func (ss *Sim) Ablate() {
+	ss.NewRun()
+	if errors.Log(ss.Net.OpenWeights(core.Filename(ss.Config.Run.Ablate))) != nil {
+		return
+	}
+	as := &leabra.AblationSweep{Stats: []string{"PctCor", "UnitErr", "CorSim"}}
+	dt := as.Run(ss.Net, func(net *leabra.Network) map[string]float64 {
+		ss.TestAll()
+		lt := ss.Logs.Table(etime.Test, etime.Epoch)
+		stats := make(map[string]float64)
+		for _, st := range as.Stats {
+			stats[st] = lt.Float(st, lt.Rows-1)
+		}
+		return stats
+	})
+	mpi.Printf("%s", as)
+	fnm := ss.Net.Name + "_" + ss.Stats.String("RunName") + "_ablation.tsv"
+	mpi.Printf("Saving ablation table to: %s\n", fnm)
+	errors.Log(dt.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+}
+
+// Saliency computes the input saliency maps for each test pattern with the
+// weights in the Config.Run.Saliency file, as the decrease in the cosine of
+// the Output with the target when each input unit is occluded, and saves
+// the table of maps.
+func (ss *Sim) Saliency() {
+	ss.NewRun()
+	if errors.Log(ss.Net.OpenWeights(core.Filename(ss.Config.Run.Saliency))) != nil {
+		return
+	}
+	ctx := &ss.Context
+	ctx.Mode = etime.Test
+	in := ss.Net.LayerByName("Input")
+	out := ss.Net.LayerByName("Output")
+	dt := ss.Patterns
+	sm := &leabra.SaliencyMaps{}
+	for row := range dt.Rows {
+		inp := dt.Tensor("Input", row)
+		trg := dt.Tensor("Output", row)
+		sm.Add(dt.StringValue("Name", row), inp, ss.Net.SaliencyMinusRun(ctx, in, out, trg))
+	}
+	ctx.Mode = etime.Train
+	fnm := ss.Net.Name + "_" + ss.Stats.String("RunName") + "_saliency.tsv"
+	mpi.Printf("Saving saliency maps to: %s\n", fnm)
+	errors.Log(sm.Table.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+}
+
+// RunRetention runs the Config.Run.Retention intervals at the end of
+// a training run, testing all the patterns after each delay, and records
+// the test stats in the Retention forgetting curves.
+func (ss *Sim) RunRetention() {
+	rp := &ss.Config.Run.Retention
+	if !rp.On {
+		return
+	}
+	if ss.Retention.Table == nil {
+		ss.Retention.Reset("PctCor", "UnitErr", "CorSim")
+		ss.Logs.MiscTables["Retention"] = ss.Retention.Table
+	}
+	run := ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur
+	rnd := randx.NewSysRand(ss.RandSeeds[run])
+	lt := ss.Logs.Table(etime.Test, etime.Epoch)
+	errors.Log(rp.Run(ss.Net, ss.Config.Run.NTrials, ss.Interfere, rnd, func(delay int) {
+		ss.TestAll()
+		errors.Log(ss.Retention.Record(run, delay, lt))
+	}))
+	dt := ss.Retention.Table
+	for row := max(dt.Rows-len(rp.DelayEpochs())-1, 0); row < dt.Rows; row++ {
+		mpi.Printf("Run: %d\tDelay: %d\tPctCor: %g\tCorSim: %g\n", run, int(dt.Float("Delay", row)), dt.Float("PctCor", row), dt.Float("CorSim", row))
+	}
+}
+
+// Sensitivity computes the contribution of each layer to the Output for
+// each test pattern with the weights in the Config.Run.Sensitivity file,
+// as the change in the cosine of the Output with the target when the
+// settled minus phase activity of the layer is perturbed, and saves the
+// contribution table.
+func (ss *Sim) Sensitivity() {
+	ss.NewRun()
+	if errors.Log(ss.Net.OpenWeights(core.Filename(ss.Config.Run.Sensitivity))) != nil {
+		return
+	}
+	ctx := &ss.Context
+	ctx.Mode = etime.Test
+	in := ss.Net.LayerByName("Input")
+	out := ss.Net.LayerByName("Output")
+	cos := func(net *leabra.Network) float64 {
+		var ab, aa, bb leabra.Float
+		for ni := range out.Neurons {
+			nrn := &out.Neurons[ni]
+			ab += nrn.Act * nrn.Targ
+			aa += nrn.Act * nrn.Act
+			bb += nrn.Targ * nrn.Targ
+		}
+		if aa == 0 || bb == 0 {
+			return 0
+		}
+		return float64(ab) / math.Sqrt(float64(aa*bb))
+	}
+	dt := ss.Patterns
+	sp := &leabra.SensitivityProbe{}
+	sp.Defaults()
+	for row := range dt.Rows {
+		ss.Net.InitExt()
+		in.ApplyExt(dt.Tensor("Input", row))
+		out.ApplyExt(dt.Tensor("Output", row))
+		ss.Net.AlphaCycInit(false)
+		ctx.AlphaCycStart()
+		for qtr := range 3 {
+			for range ctx.CycPerQtr {
+				ss.Net.Cycle(ctx)
+				ctx.CycleInc()
+			}
+			if qtr < 2 { // probe before MinusPhase clamps targets
+				ss.Net.QuarterFinal(ctx)
+				ctx.QuarterInc()
+			}
+		}
+		sp.Probe(ss.Net, ctx, dt.StringValue("Name", row), cos)
+	}
+	ctx.Mode = etime.Train
+	mpi.Printf("%s", sp)
+	fnm := ss.Net.Name + "_" + ss.Stats.String("RunName") + "_sensitivity.tsv"
+	mpi.Printf("Saving sensitivity table to: %s\n", fnm)
+	errors.Log(sp.Results.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+}
+
+// Serve serves the remote control API (see leabra.Server) on the
+// Config.Run.Server address, until the server fails.
+func (ss *Sim) Serve() {
+	sv := &leabra.Server{Net: ss.Net, Params: &ss.Params, Ctx: &ss.Context, Loops: ss.Loops, Stats: &ss.Stats, Init: ss.Init, Update: ss.StatCounters}
+	errors.Log(sv.ListenAndServe(ss.Config.Run.Server))
+}
+
 /////////////////////////////////////////////////////////////////////////
 //   Patterns
 
@@ -565,11 +992,33 @@ func (ss *Sim) ConfigPatterns() {
 	dt.AddFloat32TensorColumn("Output", []int{5, 5}, "Y", "X")
 	dt.SetNumRows(25)
 
+	patgen.NewRand(ss.RandSeeds[0]) // separate stream, for reproducible patterns
 	patgen.PermutedBinaryMinDiff(dt.Columns[1].(*tensor.Float32), 6, 1, 0, 3)
 	patgen.PermutedBinaryMinDiff(dt.Columns[2].(*tensor.Float32), 6, 1, 0, 3)
 	dt.SaveCSV("random_5x5_25_gen.tsv", table.Tab, table.Headers)
 }
 
+// ConfigInterfere configures a list of random patterns, with the same
+// shape as the training patterns, for the interfering list training
+// of the Config.Run.Retention RetainInterfere delays.
+func (ss *Sim) ConfigInterfere() {
+	dt := table.NewTable()
+	dt.SetMetaData("name", "InterferePatterns")
+	dt.SetMetaData("desc", "Interfering list patterns")
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{5, 5}, "Y", "X")
+	dt.AddFloat32TensorColumn("Output", []int{5, 5}, "Y", "X")
+	dt.SetNumRows(ss.Patterns.Rows)
+	for i := range dt.Rows {
+		dt.SetString("Name", i, fmt.Sprintf("interfere_%d", i))
+	}
+
+	patgen.NewRand(ss.RandSeeds[1]) // different from the training patterns
+	patgen.PermutedBinaryMinDiff(dt.Columns[1].(*tensor.Float32), 6, 1, 0, 3)
+	patgen.PermutedBinaryMinDiff(dt.Columns[2].(*tensor.Float32), 6, 1, 0, 3)
+	ss.Interfere = dt
+}
+
 func (ss *Sim) OpenPatterns() {
 	dt := ss.Patterns
 	dt.SetMetaData("name", "TrainPatterns")
@@ -589,7 +1038,6 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("UnitErr", 0.0)
 	ss.Stats.SetFloat("CorSim", 0.0)
 	ss.Stats.SetString("TrialName", "")
-	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -622,30 +1070,18 @@ func (ss *Sim) NetViewCounters(tm etime.Times) {
 func (ss *Sim) TrialStats() {
 	out := ss.Net.LayerByName("Output")
 
-	ss.Stats.SetFloat("CorSim", float64(out.CosDiff.Cos))
-
-	sse, avgsse := out.MSE(0.5) // 0.5 = per-unit tolerance -- right side of .5
-	ss.Stats.SetFloat("SSE", sse)
-	ss.Stats.SetFloat("AvgSSE", avgsse)
-	if sse > 0 {
-		ss.Stats.SetFloat("TrlErr", 1)
-	} else {
-		ss.Stats.SetFloat("TrlErr", 0)
-	}
+	leabra.ErrStats(out, 0.5).SetStats(&ss.Stats) // 0.5 = per-unit tolerance -- right side of .5
 }
 
 //////////////////////////////////////////////////////////////////////////////
 // 		Logging
 
 func (ss *Sim) ConfigLogs() {
-	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
+	leabra.LogAddStdItems(&ss.Logs, &ss.Stats, ss.Params.RunName(0))
 
-	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
-
-	ss.Logs.AddStatAggItem("CorSim", etime.Run, etime.Epoch, etime.Trial)
-	ss.Logs.AddStatAggItem("UnitErr", etime.Run, etime.Epoch, etime.Trial)
+	leabra.AddLogSpecs(&ss.Logs,
+		&leabra.LogSpec{Name: "CorSim", Mode: etime.AllModes, Times: []etime.Times{etime.Run, etime.Epoch, etime.Trial}, Agg: stats.Mean, Plot: true},
+		&leabra.LogSpec{Name: "UnitErr", Mode: etime.AllModes, Times: []etime.Times{etime.Run, etime.Epoch, etime.Trial}, Agg: stats.Mean})
 	ss.Logs.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddCopyFromFloatItems(etime.Train, []etime.Times{etime.Epoch, etime.Run}, etime.Test, etime.Epoch, "Tst", "CorSim", "UnitErr", "PctCor", "PctErr")
@@ -654,21 +1090,18 @@ func (ss *Sim) ConfigLogs() {
 
 	layers := ss.Net.LayersByType(leabra.SuperLayer, leabra.CTLayer, leabra.TargetLayer)
 	leabra.LogAddDiagnosticItems(&ss.Logs, layers, etime.Train, etime.Epoch, etime.Trial)
+	leabra.LogAddLearnProgressItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch)
 	leabra.LogInputLayer(&ss.Logs, ss.Net, etime.Train)
 
 	leabra.LogAddPCAItems(&ss.Logs, ss.Net, etime.Train, etime.Run, etime.Epoch, etime.Trial)
 
 	ss.Logs.AddLayerTensorItems(ss.Net, "Act", etime.Test, etime.Trial, "InputLayer", "TargetLayer")
 
-	ss.Logs.PlotItems("CorSim", "PctCor", "FirstZero", "LastZero")
+	errors.Log1(leabra.AddLogSpecStrings(&ss.Logs, ss.Config.Log.Stats...))
+
+	ss.Logs.PlotItems("PctCor", "FirstZero", "LastZero")
 
-	ss.Logs.CreateTables()
-	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	// don't plot certain combinations we don't use
-	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Run)
-	// note: Analyze not plotted by default
-	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+	leabra.LogConfigStdTables(&ss.Logs, &ss.Stats, ss.Net)
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -689,9 +1122,11 @@ func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
 	case time == etime.Trial:
 		ss.TrialStats()
 		ss.StatCounters()
+	case mode == etime.Train && time == etime.Epoch:
+		ss.MPI.GatherTableRows(&ss.Logs, mode, etime.Trial) // all trials across procs
 	}
 
-	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+	ss.LogFiles.LogRow(&ss.Logs, mode, time, row) // also logs to file, etc
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////
@@ -712,14 +1147,46 @@ func (ss *Sim) ConfigGUI() {
 	nv.SceneXYZ().Camera.Pose.Pos.Set(0, 1, 2.75) // more "head on" than default which is more "top down"
 	nv.SceneXYZ().Camera.LookAt(math32.Vec3(0, 0, 0), math32.Vec3(0, 1, 0))
 
+	if ss.Config.Log.PlayNet != "" {
+		if err := leabra.OpenNetRecord(nv, ss.Config.Log.PlayNet); err != nil {
+			mpi.Println(err)
+		} else {
+			mpi.Printf("Playing back NetView records from: %s\n", ss.Config.Log.PlayNet)
+		}
+	}
+
 	ss.GUI.AddPlots(title, &ss.Logs)
 
+	if ss.Config.Log.PlayRaster != "" {
+		if rs, err := leabra.OpenRaster(core.Filename(ss.Config.Log.PlayRaster)); err != nil {
+			mpi.Println(err)
+		} else {
+			for _, lnm := range rs.Layers {
+				tf, _ := ss.GUI.Tabs.NewTab(lnm + " Raster")
+				tensorcore.NewTensorGrid(tf).SetTensor(rs.Data[lnm])
+			}
+		}
+	}
+
 	ss.GUI.FinalizeGUI(false)
 }
 
 func (ss *Sim) MakeToolbar(p *tree.Plan) {
 	ss.GUI.AddLooperCtrl(p, ss.Loops)
+	ss.Stepper.AddToolbar(p, &ss.GUI)
 
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Update Params",
+		Icon:    icons.Update,
+		Tooltip: "Makes the params edited in the Net take effect immediately when stopped during a run, at a quarter or trial boundary, by updating the state that depends on them (see leabra.Network.UpdateParamsLive)",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if !ss.Context.AtQuarterBoundary() {
+				core.MessageSnackbar(ss.GUI.Body, "Params can only be updated at a quarter or trial boundary")
+				return
+			}
+			ss.Net.UpdateParamsLive()
+		},
+	})
 	////////////////////////////////////////////////
 	tree.Add(p, func(w *core.Separator) {})
 	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
@@ -768,20 +1235,70 @@ func (ss *Sim) RunNoGUI() {
 	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
 	netName := ss.Net.Name
 
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Trial, etime.Train, etime.Trial, "trl", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Epoch, etime.Train, etime.Epoch, "epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.Run, etime.Train, etime.Run, "run", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestEpoch, etime.Test, etime.Epoch, "tst_epc", netName, runName)
-	elog.SetLogFile(&ss.Logs, ss.Config.Log.TestTrial, etime.Test, etime.Trial, "tst_trl", netName, runName)
+	if ss.MPI.Rank() == 0 { // all procs have the same logs
+		ss.LogFiles.SetStdLogFiles(&ss.Logs, &ss.Config.Log, netName, runName)
+	}
 
 	netdata := ss.Config.Log.NetData
 	if netdata {
 		mpi.Printf("Saving NetView data from testing\n")
 		ss.GUI.InitNetData(ss.Net, 200)
 	}
+	if ss.Config.Log.NetRecord && ss.MPI.Rank() == 0 {
+		ss.NetRecorder.MaxRecs = ss.Config.Log.NetRecRecs
+		ss.NetRecorder.Init(ss.Net, netName+"_"+runName+"_netrec")
+		mpi.Printf("Recording NetView data to: %s_*.netdata.json.gz\n", ss.NetRecorder.File)
+	}
+	if len(ss.Config.Log.Raster) > 0 && ss.MPI.Rank() == 0 {
+		ss.Raster.Defaults()
+		ss.Raster.Layers = ss.Config.Log.Raster
+		ss.Raster.Every = ss.Config.Log.RasterEvery
+		if err := ss.Raster.Init(ss.Net, netName+"_"+runName+".raster.gz"); err != nil {
+			mpi.Println(err)
+		} else {
+			mpi.Printf("Recording activity raster to: %s\n", ss.Raster.File)
+		}
+	}
+	if ss.Config.Log.Snapshot != "" && ss.MPI.Rank() == 0 {
+		ss.Snapshot.Defaults()
+		ss.Snapshot.Var = ss.Config.Log.SnapshotVar
+		ss.Snapshot.Unit = ss.Config.Log.SnapshotUnit
+		ss.Snapshot.Format = ss.Config.Log.SnapshotFormat
+		if err := ss.Snapshot.Init(ss.Net, netName+"_"+runName+"_snapshots"); err != nil {
+			mpi.Println(err)
+		} else {
+			mpi.Printf("Saving network snapshots to: %s\n", ss.Snapshot.Dir)
+		}
+	}
 
 	ss.Init()
 
+	if ss.Config.Run.Ensemble != "" {
+		ss.EvalEnsemble()
+		ss.LogFiles.CloseLogFiles(&ss.Logs)
+		return
+	}
+	if ss.Config.Run.Ablate != "" {
+		ss.Ablate()
+		ss.LogFiles.CloseLogFiles(&ss.Logs)
+		return
+	}
+	if ss.Config.Run.Saliency != "" {
+		ss.Saliency()
+		ss.LogFiles.CloseLogFiles(&ss.Logs)
+		return
+	}
+	if ss.Config.Run.Sensitivity != "" {
+		ss.Sensitivity()
+		ss.LogFiles.CloseLogFiles(&ss.Logs)
+		return
+	}
+	if ss.Config.Run.Server != "" {
+		ss.Serve()
+		ss.LogFiles.CloseLogFiles(&ss.Logs)
+		return
+	}
+
 	mpi.Printf("Running %d Runs starting at %d\n", ss.Config.Run.NRuns, ss.Config.Run.Run)
 	ss.Loops.Loop(etime.Train, etime.Run).Counter.SetCurMaxPlusN(ss.Config.Run.Run, ss.Config.Run.NRuns)
 
@@ -791,13 +1308,58 @@ func (ss *Sim) RunNoGUI() {
 		mpi.Printf("Starting with initial weights from: %s\n", ss.Config.Run.StartWts)
 	}
 
+	if ss.Config.Run.Resume != "" {
+		resumed := false
+		ss.Loops.Loop(etime.Train, etime.Run).OnStart.Add("Resume", func() {
+			if !resumed { // only on the first run
+				resumed = true
+				ss.LoadCheckpoint(ss.Config.Run.Resume)
+			}
+		})
+	}
+
 	mpi.Printf("Set NThreads to: %d\n", ss.Net.NThreads)
 
+	var prov *leabra.Provenance
+	provFile := leabra.ProvenanceFilename(netName, runName)
+	if ss.Config.Log.Provenance && ss.MPI.Rank() == 0 {
+		run := ss.Config.Run.Run
+		prov = ss.Net.NewProvenance(&ss.Params, &ss.Config, run, ss.RandSeeds[run:min(run+ss.Config.Run.NRuns, len(ss.RandSeeds))]...)
+		mpi.Printf("Saving provenance to: %s\n", provFile)
+		errors.Log(prov.Save(provFile))
+	}
+	if ss.Config.Log.Graph && ss.MPI.Rank() == 0 {
+		mpi.Printf("Saving network graph to: %s.dot, %s_graph.json\n", netName, netName)
+		errors.Log(ss.Net.ExportGraph(core.Filename(netName + ".dot")))
+		errors.Log(ss.Net.ExportGraph(core.Filename(netName + "_graph.json")))
+	}
+
 	ss.Loops.Run(etime.Train)
 
-	ss.Logs.CloseLogFiles()
+	ss.LogFiles.CloseLogFiles(&ss.Logs)
+
+	if prov != nil {
+		prov.End = time.Now()
+		errors.Log(prov.Save(provFile))
+	}
+
+	if ss.Config.Run.Retention.On && ss.MPI.Rank() == 0 {
+		fnm := netName + "_" + runName + "_retention.tsv"
+		mpi.Printf("Saving retention curves to: %s\n", fnm)
+		errors.Log(ss.Retention.Table.SaveCSV(core.Filename(fnm), table.Tab, table.Headers))
+	}
 
 	if netdata {
 		ss.GUI.SaveNetData(ss.Stats.String("RunName"))
 	}
+	if ss.Config.Log.NetRecord && ss.MPI.Rank() == 0 {
+		if err := ss.NetRecorder.Save(); err != nil {
+			mpi.Println(err)
+		}
+	}
+	if len(ss.Config.Log.Raster) > 0 && ss.MPI.Rank() == 0 {
+		if err := ss.Raster.Close(); err != nil {
+			mpi.Println(err)
+		}
+	}
 }
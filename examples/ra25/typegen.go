@@ -8,10 +8,10 @@ import (
 
 var _ = types.AddType(&types.Type{Name: "main.ParamConfig", IDName: "param-config", Doc: "ParamConfig has config parameters related to sim params", Fields: []types.Field{{Name: "Network", Doc: "network parameters"}, {Name: "Hidden1Size", Doc: "size of hidden layer -- can use emer.LaySize for 4D layers"}, {Name: "Hidden2Size", Doc: "size of hidden layer -- can use emer.LaySize for 4D layers"}, {Name: "Sheet", Doc: "Extra Param Sheet name(s) to use (space separated if multiple).\nmust be valid name as listed in compiled-in params or loaded params"}, {Name: "Tag", Doc: "extra tag to add to file names and logs saved from this run"}, {Name: "Note", Doc: "user note -- describe the run params etc -- like a git commit message for the run"}, {Name: "File", Doc: "Name of the JSON file to input saved parameters from."}, {Name: "SaveAll", Doc: "Save a snapshot of all current param and config settings\nin a directory named params_<datestamp> (or _good if Good is true), then quit.\nUseful for comparing to later changes and seeing multiple views of current params."}, {Name: "Good", Doc: "For SaveAll, save to params_good for a known good params state.\nThis can be done prior to making a new release after all tests are passing.\nadd results to git to provide a full diff record of all params over time."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.RunConfig", IDName: "run-config", Doc: "RunConfig has config parameters related to running the sim", Fields: []types.Field{{Name: "Run", Doc: "starting run number, which determines the random seed.\nruns counts from there, can do all runs in parallel by launching\nseparate jobs with each run, runs = 1."}, {Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "NTrials", Doc: "total number of trials per epoch.  Should be an even multiple of NData."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "PCAInterval", Doc: "how frequently (in epochs) to compute PCA on hidden representations\nto measure variance?"}, {Name: "StartWts", Doc: "if non-empty, is the name of weights file to load at start\nof first run, for testing."}}})
+var _ = types.AddType(&types.Type{Name: "main.RunConfig", IDName: "run-config", Doc: "RunConfig has config parameters related to running the sim", Fields: []types.Field{{Name: "Run", Doc: "starting run number, which determines the random seed.\nruns counts from there, can do all runs in parallel by launching\nseparate jobs with each run, runs = 1."}, {Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NZero", Doc: "stop run after this number of perfect, zero-error epochs."}, {Name: "NTrials", Doc: "total number of trials per epoch.  Should be an even multiple of NData."}, {Name: "TestInterval", Doc: "how often to run through all the test patterns, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "PCAInterval", Doc: "how frequently (in epochs) to compute PCA on hidden representations\nto measure variance?"}, {Name: "StartWts", Doc: "if non-empty, is the name of weights file to load at start\nof first run, for testing."}, {Name: "StopOnErrAfter", Doc: "if > 0, stops running at the end of each training trial with an\nerror (TrlErr), on or after this epoch, using the Stepper, to\nexamine the errors in the GUI.  0 = off."}, {Name: "Breakpoints", Doc: "breakpoints on the state of the network (see leabra.Breakpoint),\nchecked at the end of each trial using the Stepper, e.g.,\n\"Hidden1.Act.Max > 0.95\" or \"*.Wt NaN\"."}, {Name: "Finite", Doc: "checking for non-finite (NaN or Inf) values in the network state\n(see leabra.FiniteParams), e.g., -Run.Finite.Level FiniteAll\n-Run.Finite.Panic to stop a cluster run as soon as one appears."}, {Name: "CheckpointInterval", Doc: "how often (in epochs) to save a checkpoint of the full sim state\n(weights, counters, env, stats, logs), to allow resuming an\ninterrupted run.  0 = no checkpoints."}, {Name: "Resume", Doc: "if non-empty, is the name of a checkpoint file to resume from,\nas saved with CheckpointInterval."}, {Name: "Ensemble", Doc: "if non-empty, is a glob pattern of weights files (e.g., the final\nweights saved from multiple runs) to evaluate as an ensemble on the\ntest patterns, along with the average of their weights, which is\nsaved as the consensus weights, instead of training."}, {Name: "Ablate", Doc: "if non-empty, is the name of a weights file to load and test with\neach layer and pathway ablated in turn (see leabra.AblationSweep),\nsaving the contribution table, instead of training."}, {Name: "Saliency", Doc: "if non-empty, is the name of a weights file to load and compute\ninput saliency maps for each of the test patterns, by occluding\neach input unit in turn (see leabra.SaliencyMaps), instead of training."}, {Name: "Sensitivity", Doc: "if non-empty, is the name of a weights file to load and compute\nthe contribution of each layer to the output for each of the test\npatterns, by perturbing its settled activity (see\nleabra.SensitivityProbe), saving the contribution table,\ninstead of training."}, {Name: "Server", Doc: "if non-empty, is the address (e.g., localhost:8080) to serve the\nremote control API on in nogui mode (see leabra.Server), for running\nthe sim from other programs, instead of training.  The weights are\nsaved and opened in the current directory."}, {Name: "Retention", Doc: "retention interval testing at the end of each training run, with\ndelay epochs of no input, noise, or interfering list training before\neach delayed test (see leabra.RetentionParams), recording the\nforgetting curves across the delays."}, {Name: "TestCache", Doc: "if true, cache the settled state of testing trials, and reuse it\nwhen the weights and inputs are unchanged, to speed up testing."}, {Name: "MPI", Doc: "use MPI message passing for data-parallel training, with the\ntraining trials divided across procs running identical copies of\nthe sim, and weight changes summed across procs.\nRequires building with -tags mpi, and running with mpirun."}}})
 
-var _ = types.AddType(&types.Type{Name: "main.LogConfig", IDName: "log-config", Doc: "LogConfig has config parameters related to logging data", Fields: []types.Field{{Name: "SaveWeights", Doc: "if true, save final weights after each run"}, {Name: "Epoch", Doc: "if true, save train epoch log to file, as .epc.tsv typically"}, {Name: "Run", Doc: "if true, save run log to file, as .run.tsv typically"}, {Name: "Trial", Doc: "if true, save train trial log to file, as .trl.tsv typically. May be large."}, {Name: "TestEpoch", Doc: "if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."}, {Name: "TestTrial", Doc: "if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."}, {Name: "NetData", Doc: "if true, save network activation etc data from testing trials,\nfor later viewing in netview."}}})
+var _ = types.AddType(&types.Type{Name: "main.LogConfig", IDName: "log-config", Doc: "LogConfig has config parameters related to logging data", Fields: []types.Field{{Name: "SaveWeights", Doc: "if true, save final weights after each run"}, {Name: "Epoch", Doc: "if true, save train epoch log to file, as .epc.tsv typically"}, {Name: "Run", Doc: "if true, save run log to file, as .run.tsv typically"}, {Name: "Trial", Doc: "if true, save train trial log to file, as .trl.tsv typically. May be large."}, {Name: "TestEpoch", Doc: "if true, save testing epoch log to file, as .tst_epc.tsv typically.  In general it is better to copy testing items over to the training epoch log and record there."}, {Name: "TestTrial", Doc: "if true, save testing trial log to file, as .tst_trl.tsv typically. May be large."}, {Name: "NetData", Doc: "if true, save network activation etc data from testing trials,\nfor later viewing in netview."}, {Name: "NetRecord", Doc: "if true, record the network state at the end of every training and\ntesting trial in nogui runs, saved in segment files of NetRecRecs\nrecords (see leabra.NetRecorder), for offline playback with PlayNet."}, {Name: "NetRecRecs", Doc: "number of records per NetRecord segment file."}, {Name: "PlayNet", Doc: "if non-empty, is a glob pattern of NetRecord segment files\n(*.netdata.json.gz) to open in the NetView of the GUI at startup,\nfor offline playback of a nogui run."}, {Name: "Raster", Doc: "names of the layers whose activity to record at every cycle of\ntraining and testing in nogui runs, as a spike raster / activity\nheatmap (see leabra.RasterRecorder), saved to a .raster.gz file\nfor viewing after the run with PlayRaster."}, {Name: "RasterEvery", Doc: "record the Raster layers only every this many cycles."}, {Name: "PlayRaster", Doc: "if non-empty, is a .raster.gz file saved by a Raster recording\nto open in a grid tab for each layer in the GUI at startup,\nshowing the activity of each unit (columns) over time (rows)."}, {Name: "Snapshot", Doc: "if non-empty, the time scale of training (e.g., Epoch or Trial)\nat which to save snapshots of the network in nogui runs, rendered\nas in the NetView (see leabra.NetSnapshot), as image files in a\n_snapshots directory, for visualization without the GUI."}, {Name: "SnapshotEvery", Doc: "save a Snapshot only every this many iterations of its time scale."}, {Name: "SnapshotVar", Doc: "the variable to render in the Snapshots, e.g., Act, or r.Wt\nfor the weights received by the SnapshotUnit, as in the NetView."}, {Name: "SnapshotUnit", Doc: "the unit whose pathways are rendered for a synaptic SnapshotVar,\nas the layer name and unit index, e.g., Hidden1:12."}, {Name: "SnapshotFormat", Doc: "the image format of the Snapshots: png or svg."}, {Name: "Stats", Doc: "additional stats to log, as leabra.LogSpec text, e.g.,\n\"ActMAvg at Trial,Epoch for Hidden1,Hidden2 in Train plot\"."}, {Name: "Provenance", Doc: "if true, save the provenance of the run (code version, args, seeds,\nparams, config, network; see leabra.Provenance) in nogui runs,\nas a _provenance.json file next to the log files."}, {Name: "Graph", Doc: "if true, export the network architecture (layers and pathways;\nsee leabra.NetGraph) in nogui runs, as a GraphViz .dot file and\na _graph.json file, for documenting and diffing architectures."}}})
 
 var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config is a standard Sim config -- use as a starting point.", Fields: []types.Field{{Name: "Includes", Doc: "specify include files here, and after configuration,\nit contains list of include files added."}, {Name: "GUI", Doc: "open the GUI -- does not automatically run -- if false,\nthen runs automatically and quits."}, {Name: "Debug", Doc: "log debugging information"}, {Name: "Params", Doc: "parameter related configuration options"}, {Name: "Run", Doc: "sim running related configuration options"}, {Name: "Log", Doc: "data logging related configuration options"}}})
 
-var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Config", Doc: "simulation configuration parameters -- set by .toml config file and / or args"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Patterns", Doc: "the training patterns to use"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Config", Doc: "simulation configuration parameters -- set by .toml config file and / or args"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stepper", Doc: "steps the Loops at the standard grains (Cycle, Quarter, Phase,\nAlphaCycle, Trial, Epoch, Run), with conditional stops"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "LogFiles", Doc: "the files that the logs are saved to in nogui runs"}, {Name: "Patterns", Doc: "the training patterns to use"}, {Name: "Interfere", Doc: "the interfering list patterns for Config.Run.Retention"}, {Name: "Retention", Doc: "the forgetting curves measured with Config.Run.Retention"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "TestCache", Doc: "cache of settled testing trial states"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}, {Name: "MPI", Doc: "MPI data-parallel training state, if Config.Run.MPI is on"}, {Name: "NetRecorder", Doc: "records the network state in nogui runs, if Config.Log.NetRecord is on"}, {Name: "Raster", Doc: "records the activity of the Config.Log.Raster layers at every\ncycle in nogui runs"}, {Name: "Snapshot", Doc: "renders the Config.Log.Snapshot images of the network in nogui runs"}}})
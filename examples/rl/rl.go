@@ -0,0 +1,684 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// rl illustrates reinforcement learning in stepping environments
+// wrapped with leabra.GymEnv: an n-armed bandit learned with the
+// Rescorla-Wagner (RW) reward prediction layers, and a gridworld
+// navigation task learned with the temporal differences (TD) layers.
+// The Action layer is an actor that learns to choose the actions,
+// with its learning rate modulated by the dopamine reward prediction
+// error from the critic.
+package main
+
+//go:generate core generate -add-types
+
+import (
+	"slices"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/mpi"
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/enums"
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tree"
+	"github.com/emer/emergent/v2/egui"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/netview"
+	"github.com/emer/emergent/v2/params"
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+func main() {
+	sim := &Sim{}
+	sim.New()
+	sim.ConfigAll()
+	if sim.Config.GUI {
+		sim.RunGUI()
+	} else {
+		sim.RunNoGUI()
+	}
+}
+
+// ParamSets is the default set of parameters.
+// Base is always applied, and the set for the Env type
+// (Bandit or Grid) is applied on top of that.
+var ParamSets = params.Sets{
+	"Base": {
+		{Sel: "Path", Desc: "no extra learning factors",
+			Params: params.Params{
+				"Path.Learn.Norm.On":     "false",
+				"Path.Learn.Momentum.On": "false",
+				"Path.Learn.WtBal.On":    "false",
+			}},
+		{Sel: "Layer", Desc: "no decay",
+			Params: params.Params{
+				"Layer.Act.Init.Decay": "0",
+			}},
+		{Sel: "#Action", Desc: "one action",
+			Params: params.Params{
+				"Layer.Inhib.Layer.Gi":     "2",
+				"Layer.Inhib.ActAvg.Init":  "0.25",
+				"Layer.Inhib.ActAvg.Fixed": "true",
+			}},
+		{Sel: "#ObsToAction", Desc: "actor learning, modulated by dopamine",
+			Params: params.Params{
+				"Path.Learn.Lrate": "0.3",
+			}},
+	},
+	"Bandit": {
+		{Sel: ".RWPath", Desc: "reward prediction",
+			Params: params.Params{
+				"Path.Learn.Lrate": "0.1",
+				"Path.WtInit.Mean": "0",
+				"Path.WtInit.Var":  "0",
+				"Path.WtInit.Sym":  "false",
+			}},
+		{Sel: "#RWPred", Desc: "full range of predictions",
+			Params: params.Params{
+				"Layer.RW.PredRange.Min": "0",
+				"Layer.RW.PredRange.Max": "1",
+			}},
+	},
+	"Grid": {
+		{Sel: ".TDPredPath", Desc: "value prediction",
+			Params: params.Params{
+				"Path.Learn.Lrate": "0.1",
+				"Path.WtInit.Mean": "0",
+				"Path.WtInit.Var":  "0",
+				"Path.WtInit.Sym":  "false",
+			}},
+	},
+}
+
+// EnvTypes are the types of environments.
+type EnvTypes int32 //enums:enum
+
+const (
+	// Bandit is an n-armed bandit, learned with the RW layers.
+	Bandit EnvTypes = iota
+
+	// Grid is a gridworld navigation task, learned with the TD layers.
+	Grid
+)
+
+// Config has config parameters related to running the sim
+type Config struct {
+
+	// Env is the type of environment.
+	Env EnvTypes
+
+	// open the GUI -- does not automatically run -- if false,
+	// then runs automatically and quits.
+	GUI bool `default:"true"`
+
+	// total number of runs to do when running Train
+	NRuns int `default:"5" min:"1"`
+
+	// total number of epochs per run
+	NEpochs int `default:"150"`
+
+	// total number of trials (environment steps) per epoch
+	NTrials int `default:"100"`
+
+	// how often to test the greedy policy, in terms of training epochs.
+	// can use 0 or -1 for no testing.
+	TestInterval int `default:"5"`
+
+	// Epsilon is the probability of choosing a random action during
+	// training, instead of the action most active in the Action layer.
+	Epsilon float32 `default:"0.2"`
+
+	// Bandit has the parameters of the Bandit, including drifting
+	// reward probabilities and probabilistic reversals.
+	Bandit leabra.BanditParams `display:"add-fields"`
+
+	// GridSize is the number of locations on each side of the Grid.
+	GridSize int `default:"4"`
+
+	// GridMaxSteps is the maximum number of steps in a Grid episode.
+	GridMaxSteps int `default:"30"`
+
+	// if true, save train epoch log to file
+	SaveEpochLog bool
+}
+
+// Sim encapsulates the entire simulation model, and we define all the
+// functionality as methods on this struct.  This structure keeps all relevant
+// state information organized and available without having to pass everything around
+// as arguments to methods, and provides the core GUI interface (note the view tags
+// for the fields which provide hints to how things should be displayed).
+type Sim struct {
+
+	// Config contains misc configuration parameters for running the sim
+	Config Config `new-window:"+" display:"no-inline"`
+
+	// the network -- click to view / edit parameters for layers, paths, etc
+	Net *leabra.Network `new-window:"+" display:"no-inline"`
+
+	// network parameter management
+	Params emer.NetParams `display:"add-fields"`
+
+	// contains looper control loops for running sim
+	Loops *looper.Stacks `new-window:"+" display:"no-inline"`
+
+	// contains computed statistic values
+	Stats estats.Stats `new-window:"+"`
+
+	// Contains all the logs and information about the logs.'
+	Logs elog.Logs `new-window:"+"`
+
+	// Environments
+	Envs env.Envs `new-window:"+" display:"no-inline"`
+
+	// leabra timing parameters and state
+	Context leabra.Context `new-window:"+"`
+
+	// netview update parameters
+	ViewUpdate netview.ViewUpdate `display:"add-fields"`
+
+	// manages all the gui elements
+	GUI egui.GUI `display:"-"`
+
+	// a list of random seeds to use for each run
+	RandSeeds randx.Seeds `display:"-"`
+}
+
+// New creates new blank elements and initializes defaults
+func (ss *Sim) New() {
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
+	ss.Net = leabra.NewNetwork("RL")
+	ss.Params.Config(ParamSets, ss.Config.Env.String(), "", ss.Net)
+	ss.Stats.Init()
+	ss.RandSeeds.Init(100) // max 100 runs
+	ss.InitRandSeed(0)
+	ss.Context.Defaults()
+}
+
+//////////////////////////////////////////////////////////////////////////////
+// 		Configs
+
+// ConfigAll configures all the elements using the standard functions
+func (ss *Sim) ConfigAll() {
+	ss.ConfigEnv()
+	ss.ConfigNet(ss.Net)
+	ss.ConfigLogs()
+	ss.ConfigLoops()
+}
+
+// NewGym returns a new GymStepper of the configured type.
+func (ss *Sim) NewGym() leabra.GymStepper {
+	if ss.Config.Env == Grid {
+		return &GridEnv{Size: ss.Config.GridSize, MaxSteps: ss.Config.GridMaxSteps}
+	}
+	bd := &leabra.BanditEnv{Params: ss.Config.Bandit}
+	bd.Config(randx.NewSysRand(ss.RandSeeds[0]))
+	return bd
+}
+
+func (ss *Sim) ConfigEnv() {
+	// Can be called multiple times -- don't re-create
+	var trn, tst *leabra.GymEnv
+	if len(ss.Envs) == 0 {
+		trn = &leabra.GymEnv{}
+		tst = &leabra.GymEnv{}
+	} else {
+		trn = ss.Envs.ByMode(etime.Train).(*leabra.GymEnv)
+		tst = ss.Envs.ByMode(etime.Test).(*leabra.GymEnv)
+	}
+
+	// note: names must be standard here!
+	trn.Name = etime.Train.String()
+	trn.Gym = ss.NewGym()
+	trn.Seed = ss.RandSeeds[0]
+
+	tst.Name = etime.Test.String()
+	tst.Gym = ss.NewGym()
+	tst.Seed = ss.RandSeeds[0] + 1
+	if bd, ok := tst.Gym.(*leabra.BanditEnv); ok { // test probabilities do not change
+		bd.Params.Drift = 0
+		bd.Params.ReverseInterval = 0
+	}
+
+	trn.Init(0)
+	tst.Init(0)
+
+	// note: names must be in place when adding
+	ss.Envs.Add(trn, tst)
+}
+
+func (ss *Sim) ConfigNet(net *leabra.Network) {
+	net.SetRandSeed(ss.RandSeeds[0]) // init new separate random seed, using run = 0
+
+	ev := ss.Envs.ByMode(etime.Train).(*leabra.GymEnv)
+	obsShape := ev.Gym.Observation().Shape().Sizes
+	full := paths.NewFull()
+
+	obs := net.AddLayer2D("Obs", obsShape[0], obsShape[1], leabra.InputLayer)
+	act := net.AddLayer2D("Action", 1, ev.Gym.NumActions(), leabra.TargetLayer)
+	net.ConnectLayers(obs, act, full, leabra.ForwardPath)
+	act.PlaceRightOf(obs, 2)
+
+	if ss.Config.Env == Grid {
+		// State is the observation in the minus phase, and the next
+		// observation in the plus phase, for V(t+1) in the TD Pred layer
+		state := net.AddLayer2D("State", obsShape[0], obsShape[1], leabra.InputLayer)
+		rew, rp, _, td := net.AddTDLayers("", 2)
+		net.ConnectLayers(state, rp, full, leabra.TDPredPath)
+		td.AddSendTo(rp.Name)
+		state.PlaceBehind(obs, 2)
+		rew.PlaceRightOf(act, 2)
+	} else {
+		rew, rp, da := net.AddRWLayers("", 2)
+		net.ConnectLayers(obs, rp, full, leabra.RWPath)
+		da.AddSendTo(rp.Name)
+		rew.PlaceRightOf(act, 2)
+	}
+
+	net.Build()
+	net.Defaults()
+	ss.ApplyParams()
+	net.InitWeights()
+}
+
+func (ss *Sim) ApplyParams() {
+	if ss.Loops != nil {
+		trn := ss.Loops.Stacks[etime.Train]
+		trn.Loops[etime.Run].Counter.Max = ss.Config.NRuns
+		trn.Loops[etime.Epoch].Counter.Max = ss.Config.NEpochs
+	}
+	ss.Params.SetAll()
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// 	    Init, utils
+
+// Init restarts the run, and initializes everything, including network weights
+// and resets the epoch log table
+func (ss *Sim) Init() {
+	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // in case user interactively changes tag
+	ss.Loops.ResetCounters()
+	ss.InitRandSeed(0)
+	ss.GUI.StopNow = false
+	ss.ApplyParams()
+	ss.NewRun()
+	ss.ViewUpdate.RecordSyns()
+	ss.ViewUpdate.Update()
+}
+
+// InitRandSeed initializes the random seed based on current training run number
+func (ss *Sim) InitRandSeed(run int) {
+	ss.RandSeeds.Set(run)
+	ss.RandSeeds.Set(run, &ss.Net.Rand)
+}
+
+// ConfigLoops configures the control loops: Training, Testing
+func (ss *Sim) ConfigLoops() {
+	ls := looper.NewStacks()
+
+	trls := ss.Config.NTrials
+
+	ls.AddStack(etime.Train).
+		AddTime(etime.Run, ss.Config.NRuns).
+		AddTime(etime.Epoch, ss.Config.NEpochs).
+		AddTime(etime.Trial, trls).
+		AddTime(etime.Cycle, 100)
+
+	ls.AddStack(etime.Test).
+		AddTime(etime.Epoch, 1).
+		AddTime(etime.Trial, trls).
+		AddTime(etime.Cycle, 100)
+
+	leabra.LooperStdPhases(ls, &ss.Context, ss.Net, 75, 99)                // plus phase timing
+	leabra.LooperSimCycleAndLearn(ls, ss.Net, &ss.Context, &ss.ViewUpdate) // std algo code
+
+	ls.Stacks[etime.Train].OnInit.Add("Init", func() { ss.Init() })
+
+	for m := range ls.Stacks {
+		stack := ls.Stacks[m]
+		stack.Loops[etime.Trial].OnStart.Add("ApplyInputs", func() {
+			ss.ApplyInputs()
+		})
+		cyc := stack.Loops[etime.Cycle]
+		plus := cyc.EventByName("MinusPhase:End")
+		plus.OnEvent.InsertBefore("MinusPhase:End", "TakeAction", func() bool {
+			ss.TakeAction(m == etime.Train)
+			return true
+		})
+	}
+
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("NewRun", ss.NewRun)
+
+	ls.Loop(etime.Train, etime.Trial).OnEnd.InsertBefore("UpdateWeights", "ActorLrate", func() bool {
+		ss.ActorLrate()
+		return true
+	})
+
+	// Add Testing
+	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnStart.Add("TestAtInterval", func() {
+		if (ss.Config.TestInterval > 0) && ((trainEpoch.Counter.Cur+1)%ss.Config.TestInterval == 0) {
+			// Note the +1 so that it doesn't occur at the 0th timestep.
+			ss.TestAll()
+		}
+	})
+
+	/////////////////////////////////////////////
+	// Logging
+
+	ls.AddOnEndToAll("Log", func(mode, time enums.Enum) {
+		ss.Log(mode.(etime.Modes), time.(etime.Times))
+	})
+	leabra.LooperResetLogBelow(ls, &ss.Logs)
+
+	////////////////////////////////////////////
+	// GUI
+
+	leabra.LooperUpdateNetView(ls, &ss.ViewUpdate, ss.Net, ss.NetViewCounters)
+	leabra.LooperUpdatePlots(ls, &ss.GUI)
+	ls.Stacks[etime.Train].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+	ls.Stacks[etime.Test].OnInit.Add("GUI-Init", func() { ss.GUI.UpdateWindow() })
+
+	ss.Loops = ls
+}
+
+// ApplyInputs steps the environment and applies the current
+// observation to the Obs (and State) input layers.
+func (ss *Sim) ApplyInputs() {
+	ctx := &ss.Context
+	net := ss.Net
+	ev := ss.Envs.ByMode(ctx.Mode).(*leabra.GymEnv)
+	ev.Step()
+	net.InitExt()
+	net.LayerByName("Obs").ApplyExt(ev.State("Obs"))
+	if ss.Config.Env == Grid {
+		net.LayerByName("State").ApplyExt(ev.State("Obs"))
+	}
+}
+
+// TakeAction chooses the action from the minus phase activity of the
+// Action layer, or a random action with probability Epsilon if training,
+// and performs it in the environment.  The action is then the target
+// of the Action layer in the plus phase, and the reward is applied to
+// the Rew layer, along with the next observation to the State layer
+// for TD.  Called at the end of the minus phase.
+func (ss *Sim) TakeAction(train bool) {
+	ctx := &ss.Context
+	net := ss.Net
+	ev := ss.Envs.ByMode(ctx.Mode).(*leabra.GymEnv)
+	act := net.LayerByName("Action")
+	action := int(act.Pools[0].Inhib.Act.MaxIndex)
+	if train && randx.BoolP32(ss.Config.Epsilon, &ev.Rand) {
+		action = ev.Rand.Intn(ev.Gym.NumActions())
+	}
+	ev.TakeAction(action)
+	// target is the minus phase activity, with the chosen action fully active,
+	// so that only the chosen action is reinforced or punished
+	trg := tensor.NewFloat32([]int{1, len(act.Neurons)})
+	for ni := range act.Neurons {
+		trg.Values[ni] = float32(act.Neurons[ni].Act)
+	}
+	trg.Values[action] = 1
+	act.ApplyExt(trg)
+	net.LayerByName("Rew").ApplyExt(ev.State("Rew"))
+	if ss.Config.Env == Grid {
+		net.LayerByName("State").ApplyExt(ev.State("NextObs"))
+	}
+}
+
+// ActorLrate sets the learning rate of the Action layer to be
+// proportional to the dopamine reward prediction error, so that the
+// chosen action is strengthened when it is better than expected, and
+// weakened when it is worse.  In the Grid, there is no value estimate
+// for the state at the start of each episode, so the actor does not
+// learn on the first step.  Called before the weights are updated.
+func (ss *Sim) ActorLrate() {
+	ev := ss.Envs.ByMode(etime.Train).(*leabra.GymEnv)
+	da := ss.DA()
+	if ss.Config.Env == Grid && ev.EpisodeStep.Cur == 0 {
+		da = 0
+	}
+	ss.Net.LayerByName("Action").LrateMult(da)
+}
+
+// DA returns the dopamine reward prediction error
+// from the RW DA or TD layer.
+func (ss *Sim) DA() leabra.Float {
+	if ss.Config.Env == Grid {
+		return ss.Net.LayerByName("TD").Neurons[0].Act
+	}
+	return ss.Net.LayerByName("DA").Neurons[0].Act
+}
+
+// NewRun intializes a new run of the model, using the TrainEnv.Run counter
+// for the new run value
+func (ss *Sim) NewRun() {
+	ctx := &ss.Context
+	run := ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur
+	ss.InitRandSeed(run)
+	trn := ss.Envs.ByMode(etime.Train).(*leabra.GymEnv)
+	tst := ss.Envs.ByMode(etime.Test).(*leabra.GymEnv)
+	if bd, ok := trn.Gym.(*leabra.BanditEnv); ok { // new arm probabilities for each run
+		bd.Config(randx.NewSysRand(ss.RandSeeds[run]))
+		ss.CopyBanditProbs()
+	}
+	trn.Init(run)
+	tst.Init(run)
+	ctx.Reset()
+	ctx.Mode = etime.Train
+	ss.Net.InitWeights()
+	ss.InitStats()
+	ss.StatCounters()
+	ss.Logs.ResetLog(etime.Train, etime.Epoch)
+	ss.Logs.ResetLog(etime.Test, etime.Epoch)
+}
+
+// CopyBanditProbs copies the current reward probabilities of the
+// training Bandit to the testing one, as they can change over training.
+func (ss *Sim) CopyBanditProbs() {
+	trn, ok := ss.Envs.ByMode(etime.Train).(*leabra.GymEnv).Gym.(*leabra.BanditEnv)
+	if !ok {
+		return
+	}
+	tst := ss.Envs.ByMode(etime.Test).(*leabra.GymEnv).Gym.(*leabra.BanditEnv)
+	tst.Probs = slices.Clone(trn.Probs)
+}
+
+// TestAll runs the greedy policy for one epoch, without learning.
+func (ss *Sim) TestAll() {
+	ss.CopyBanditProbs()
+	ss.Envs.ByMode(etime.Test).Init(ss.Loops.Loop(etime.Train, etime.Run).Counter.Cur)
+	ss.Loops.ResetAndRun(etime.Test)
+	ss.Loops.Mode = etime.Train // Important to reset Mode back to Train because this is called from within the Train Run.
+}
+
+////////////////////////////////////////////////////////////////////////
+// 		Stats
+
+// InitStats initializes all the statistics.
+// called at start of new run
+func (ss *Sim) InitStats() {
+	ss.Stats.SetFloat("Reward", 0.0)
+	ss.Stats.SetFloat("DA", 0.0)
+	ss.Stats.SetFloat("Best", 0.0)
+	ss.Stats.SetFloat("EpSteps", 0.0)
+}
+
+// StatCounters saves current counters to Stats, so they are available for logging etc
+// Also saves a string rep of them for ViewUpdate.Text
+func (ss *Sim) StatCounters() {
+	ctx := &ss.Context
+	mode := ctx.Mode
+	ss.Loops.Stacks[mode].CountersToStats(&ss.Stats)
+	// always use training epoch..
+	trnEpc := ss.Loops.Stacks[etime.Train].Loops[etime.Epoch].Counter.Cur
+	ss.Stats.SetInt("Epoch", trnEpc)
+	ss.Stats.SetInt("Cycle", int(ctx.Cycle))
+}
+
+func (ss *Sim) NetViewCounters(tm etime.Times) {
+	if ss.ViewUpdate.View == nil {
+		return
+	}
+	if tm == etime.Trial {
+		ss.TrialStats() // get trial stats for current di
+	}
+	ss.StatCounters()
+	ss.ViewUpdate.Text = ss.Stats.Print([]string{"Run", "Epoch", "Trial", "Cycle", "Reward", "DA"})
+}
+
+// TrialStats computes the trial-level statistics.
+// Aggregation is done directly from log data.
+func (ss *Sim) TrialStats() {
+	ev := ss.Envs.ByMode(ss.Context.Mode).(*leabra.GymEnv)
+	ss.Stats.SetFloat32("Reward", ev.Reward)
+	ss.Stats.SetFloat32("DA", float32(ss.DA()))
+	ss.Stats.SetFloat("EpSteps", float64(ev.LastEpisodeSteps))
+	if bd, ok := ev.Gym.(*leabra.BanditEnv); ok {
+		best := 0.0
+		if ev.Act == bd.Best() {
+			best = 1
+		}
+		ss.Stats.SetFloat("Best", best)
+	}
+}
+
+//////////////////////////////////////////////////////////////////////
+// 		Logging
+
+func (ss *Sim) ConfigLogs() {
+	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
+
+	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
+	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
+
+	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
+
+	ss.Logs.AddStatAggItem("Reward", etime.Run, etime.Epoch, etime.Trial)
+	ss.Logs.AddStatAggItem("DA", etime.Run, etime.Epoch, etime.Trial)
+	if ss.Config.Env == Grid {
+		ss.Logs.AddStatAggItem("EpSteps", etime.Run, etime.Epoch, etime.Trial)
+		ss.Logs.PlotItems("Reward", "EpSteps")
+	} else {
+		ss.Logs.AddStatAggItem("Best", etime.Run, etime.Epoch, etime.Trial)
+		ss.Logs.PlotItems("Reward", "Best")
+	}
+
+	ss.Logs.CreateTables()
+	ss.Logs.SetContext(&ss.Stats, ss.Net)
+	// don't plot certain combinations we don't use
+	ss.Logs.NoPlot(etime.Train, etime.Cycle)
+	ss.Logs.NoPlot(etime.Test, etime.Cycle)
+	ss.Logs.NoPlot(etime.Test, etime.Run)
+	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+}
+
+// Log is the main logging function, handles special things for different scopes
+func (ss *Sim) Log(mode etime.Modes, time etime.Times) {
+	ctx := &ss.Context
+	if mode != etime.Analyze {
+		ctx.Mode = mode // Also set specifically in a Loop callback.
+	}
+	dt := ss.Logs.Table(mode, time)
+	if dt == nil {
+		return
+	}
+	row := dt.Rows
+
+	switch {
+	case time == etime.Cycle:
+		return
+	case time == etime.Trial:
+		ss.TrialStats()
+		ss.StatCounters()
+	}
+
+	ss.Logs.LogRow(mode, time, row) // also logs to file, etc
+}
+
+//////////////////////////////////////////////////////////////////////
+// 		GUI
+
+// ConfigGUI configures the Cogent Core GUI interface for this simulation.
+func (ss *Sim) ConfigGUI() {
+	title := "RL"
+	ss.GUI.MakeBody(ss, "rl", title, `rl illustrates reinforcement learning in stepping environments wrapped with leabra.GymEnv: an n-armed bandit learned with the Rescorla-Wagner (RW) layers, and a gridworld learned with the temporal differences (TD) layers. See <a href="https://github.com/emer/leabra/blob/main/examples/rl/README.md">README.md on GitHub</a>.</p>`)
+	ss.GUI.CycleUpdateInterval = 10
+
+	nv := ss.GUI.AddNetView("Network")
+	nv.Options.MaxRecs = 300
+	nv.SetNet(ss.Net)
+	ss.ViewUpdate.Config(nv, etime.AlphaCycle, etime.AlphaCycle)
+	ss.GUI.ViewUpdate = &ss.ViewUpdate
+	nv.Current()
+
+	ss.GUI.AddPlots(title, &ss.Logs)
+
+	ss.GUI.FinalizeGUI(false)
+}
+
+func (ss *Sim) MakeToolbar(p *tree.Plan) {
+	ss.GUI.AddLooperCtrl(p, ss.Loops)
+
+	tree.Add(p, func(w *core.Separator) {})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "Reset RunLog",
+		Icon:    icons.Reset,
+		Tooltip: "Reset the accumulated log of all Runs, which are tagged with the ParamSet used",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.Logs.ResetLog(etime.Train, etime.Run)
+			ss.GUI.UpdatePlot(etime.Train, etime.Run)
+		},
+	})
+	////////////////////////////////////////////////
+	tree.Add(p, func(w *core.Separator) {})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "New Seed",
+		Icon:    icons.Add,
+		Tooltip: "Generate a new initial random seed to get different results.  By default, Init re-establishes the same initial seed every time.",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			ss.RandSeeds.NewSeeds()
+		},
+	})
+	ss.GUI.AddToolbarItem(p, egui.ToolbarItem{Label: "README",
+		Icon:    icons.FileMarkdown,
+		Tooltip: "Opens your browser on the README file that contains instructions for how to run this model.",
+		Active:  egui.ActiveAlways,
+		Func: func() {
+			core.TheApp.OpenURL("https://github.com/emer/leabra/blob/main/examples/rl/README.md")
+		},
+	})
+}
+
+func (ss *Sim) RunGUI() {
+	ss.Init()
+	ss.ConfigGUI()
+	ss.GUI.Body.RunMainWindow()
+}
+
+func (ss *Sim) RunNoGUI() {
+	runName := ss.Params.RunName(0)
+	ss.Stats.SetString("RunName", runName) // used for naming logs, stats, etc
+	netName := ss.Net.Name
+
+	elog.SetLogFile(&ss.Logs, ss.Config.SaveEpochLog, etime.Train, etime.Epoch, "epc", netName, runName)
+
+	ss.Init()
+
+	mpi.Printf("Running %d Runs of %s\n", ss.Config.NRuns, ss.Config.Env)
+	ss.Loops.Run(etime.Train)
+
+	ss.Logs.CloseLogFiles()
+	dt := ss.Logs.Table(etime.Train, etime.Run)
+	for row := range dt.Rows {
+		mpi.Printf("Run: %d\tReward: %g\n", row, errors.Log1(dt.ColumnByName("Reward")).Float1D(row))
+	}
+}
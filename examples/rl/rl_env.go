@@ -0,0 +1,94 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/leabra/v2/leabra"
+)
+
+// GridEnv is a gridworld navigation task: each episode starts at a
+// random location in a square grid, and the actions move up, down,
+// left or right, staying in place at the walls, until the goal in
+// the upper right corner is reached, which is rewarded with 1, or
+// MaxSteps have been taken.  There is no reward for the other steps,
+// so shorter paths are only favored by the TD discounting.
+// The observation is a localist map of the location.
+type GridEnv struct {
+
+	// Size is the number of locations on each side of the grid.
+	Size int
+
+	// MaxSteps is the maximum number of steps in an episode.
+	MaxSteps int
+
+	// Pos is the current X, Y location.
+	Pos [2]int
+
+	// number of steps in the current episode
+	steps int
+
+	// reward for the last step
+	reward float32
+
+	// observation
+	obs tensor.Float32
+}
+
+// Goal returns the X, Y location of the goal.
+func (ev *GridEnv) Goal() [2]int {
+	return [2]int{ev.Size - 1, ev.Size - 1}
+}
+
+// MinSteps returns the minimum number of steps to the goal from
+// the current location.
+func (ev *GridEnv) MinSteps() int {
+	g := ev.Goal()
+	return g[0] - ev.Pos[0] + g[1] - ev.Pos[1]
+}
+
+func (ev *GridEnv) Reset(rnd randx.Rand) {
+	ev.obs.SetShape([]int{ev.Size, ev.Size})
+	for {
+		ev.Pos = [2]int{rnd.Intn(ev.Size), rnd.Intn(ev.Size)}
+		if ev.Pos != ev.Goal() {
+			break
+		}
+	}
+	ev.steps = 0
+	ev.reward = 0
+	ev.render()
+}
+
+func (ev *GridEnv) Step(action int) bool {
+	deltas := [][2]int{{0, 1}, {0, -1}, {-1, 0}, {1, 0}} // up, down, left, right
+	for d := range 2 {
+		ev.Pos[d] = min(max(ev.Pos[d]+deltas[action][d], 0), ev.Size-1)
+	}
+	ev.steps++
+	ev.render()
+	ev.reward = 0
+	if ev.Pos == ev.Goal() {
+		ev.reward = 1
+		return true
+	}
+	return ev.steps >= ev.MaxSteps
+}
+
+// render renders the current location in the observation.
+func (ev *GridEnv) render() {
+	ev.obs.SetZeros()
+	ev.obs.Set([]int{ev.Pos[1], ev.Pos[0]}, 1)
+}
+
+func (ev *GridEnv) Observation() tensor.Tensor { return &ev.obs }
+
+func (ev *GridEnv) Reward() float32 { return ev.reward }
+
+func (ev *GridEnv) NumActions() int { return 4 }
+
+// Compile-time check that implements GymStepper interface
+var _ leabra.GymStepper = (*GridEnv)(nil)
@@ -0,0 +1,15 @@
+// Code generated by "core generate -add-types"; DO NOT EDIT.
+
+package main
+
+import (
+	"cogentcore.org/core/types"
+)
+
+var _ = types.AddType(&types.Type{Name: "main.EnvTypes", IDName: "env-types", Doc: "EnvTypes are the types of environments."})
+
+var _ = types.AddType(&types.Type{Name: "main.Config", IDName: "config", Doc: "Config has config parameters related to running the sim", Fields: []types.Field{{Name: "Env", Doc: "Env is the type of environment."}, {Name: "GUI", Doc: "open the GUI -- does not automatically run -- if false,\nthen runs automatically and quits."}, {Name: "NRuns", Doc: "total number of runs to do when running Train"}, {Name: "NEpochs", Doc: "total number of epochs per run"}, {Name: "NTrials", Doc: "total number of trials (environment steps) per epoch"}, {Name: "TestInterval", Doc: "how often to test the greedy policy, in terms of training epochs.\ncan use 0 or -1 for no testing."}, {Name: "Epsilon", Doc: "Epsilon is the probability of choosing a random action during\ntraining, instead of the action most active in the Action layer."}, {Name: "Bandit", Doc: "Bandit has the parameters of the Bandit, including drifting\nreward probabilities and probabilistic reversals."}, {Name: "GridSize", Doc: "GridSize is the number of locations on each side of the Grid."}, {Name: "GridMaxSteps", Doc: "GridMaxSteps is the maximum number of steps in a Grid episode."}, {Name: "SaveEpochLog", Doc: "if true, save train epoch log to file"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.Sim", IDName: "sim", Doc: "Sim encapsulates the entire simulation model, and we define all the\nfunctionality as methods on this struct.  This structure keeps all relevant\nstate information organized and available without having to pass everything around\nas arguments to methods, and provides the core GUI interface (note the view tags\nfor the fields which provide hints to how things should be displayed).", Fields: []types.Field{{Name: "Config", Doc: "Config contains misc configuration parameters for running the sim"}, {Name: "Net", Doc: "the network -- click to view / edit parameters for layers, paths, etc"}, {Name: "Params", Doc: "network parameter management"}, {Name: "Loops", Doc: "contains looper control loops for running sim"}, {Name: "Stats", Doc: "contains computed statistic values"}, {Name: "Logs", Doc: "Contains all the logs and information about the logs.'"}, {Name: "Envs", Doc: "Environments"}, {Name: "Context", Doc: "leabra timing parameters and state"}, {Name: "ViewUpdate", Doc: "netview update parameters"}, {Name: "GUI", Doc: "manages all the gui elements"}, {Name: "RandSeeds", Doc: "a list of random seeds to use for each run"}}})
+
+var _ = types.AddType(&types.Type{Name: "main.GridEnv", IDName: "grid-env", Doc: "GridEnv is a gridworld navigation task: each episode starts at a\nrandom location in a square grid, and the actions move up, down,\nleft or right, staying in place at the walls, until the goal in\nthe upper right corner is reached, which is rewarded with 1, or\nMaxSteps have been taken.  There is no reward for the other steps,\nso shorter paths are only favored by the TD discounting.\nThe observation is a localist map of the location.", Fields: []types.Field{{Name: "Size", Doc: "Size is the number of locations on each side of the grid."}, {Name: "MaxSteps", Doc: "MaxSteps is the maximum number of steps in an episode."}, {Name: "Pos", Doc: "Pos is the current X, Y location."}}})
@@ -14,14 +14,15 @@ package main
 
 import (
 	"fmt"
+	"slices"
 
+	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/core"
 	"cogentcore.org/core/enums"
 	"cogentcore.org/core/icons"
 	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tree"
-	"github.com/emer/emergent/v2/econfig"
 	"github.com/emer/emergent/v2/egui"
 	"github.com/emer/emergent/v2/elog"
 	"github.com/emer/emergent/v2/emer"
@@ -203,6 +204,12 @@ type Config struct {
 	// how often to run through all the test patterns, in terms of training epochs.
 	// can use 0 or -1 for no testing.
 	TestInterval int `default:"-1"`
+
+	// DaTrace is an optional CSV file of empirical DA time courses, one row
+	// per training trial within an epoch (see leabra.DaTrace.ReadCSV), which
+	// are injected as the SNc dopamine signal during training, instead of
+	// the computed RW dopamine.
+	DaTrace core.Filename
 }
 
 // Sim encapsulates the entire simulation model, and we define all the
@@ -218,6 +225,10 @@ type Sim struct {
 	// DipDaGain is the strength of dopamine dips: 1 default -- reduce to siulate D2 agonists
 	DipDaGain float32
 
+	// TonicDA is the tonic dopamine level relative to normal: 0 default -- reduce for PD OFF (depletion), increase for PD ON (medication).
+	// It modulates the excitability of the Matrix layers, in addition to the phasic DA learning effects of Burst and Dip gains.
+	TonicDA float32
+
 	// Config contains misc configuration parameters for running the sim
 	Config Config `new-window:"+" display:"no-inline"`
 
@@ -255,7 +266,7 @@ type Sim struct {
 // New creates new blank elements and initializes defaults
 func (ss *Sim) New() {
 	ss.Defaults()
-	econfig.Config(&ss.Config, "config.toml")
+	leabra.Config(&ss.Config, "config.toml", "config.yaml")
 	ss.Net = leabra.NewNetwork("SIR")
 	ss.Params.Config(ParamSets, "", "", ss.Net)
 	ss.Stats.Init()
@@ -268,6 +279,7 @@ func (ss *Sim) New() {
 func (ss *Sim) Defaults() {
 	ss.BurstDaGain = 1
 	ss.DipDaGain = 1
+	ss.TonicDA = 0
 }
 
 //////////////////////////////////////////////////////////////////////////////
@@ -386,10 +398,20 @@ func (ss *Sim) ApplyParams() {
 	matg := ss.Net.LayerByName("MatrixGo")
 	matn := ss.Net.LayerByName("MatrixNoGo")
 
-	matg.Matrix.BurstGain = ss.BurstDaGain
-	matg.Matrix.DipGain = ss.DipDaGain
-	matn.Matrix.BurstGain = ss.BurstDaGain
-	matn.Matrix.DipGain = ss.DipDaGain
+	matg.Matrix.BurstGain = leabra.Float(ss.BurstDaGain)
+	matg.Matrix.DipGain = leabra.Float(ss.DipDaGain)
+	matn.Matrix.BurstGain = leabra.Float(ss.BurstDaGain)
+	matn.Matrix.DipGain = leabra.Float(ss.DipDaGain)
+	matg.DaMod.Gain = leabra.DaTonic
+	matn.DaMod.Gain = leabra.DaTonic
+	ss.Net.LayerByName("SNc").DaMod.Tonic = leabra.Float(ss.TonicDA)
+
+	if ss.Config.DaTrace != "" {
+		dt := &leabra.DaTrace{}
+		if errors.Log(dt.OpenCSV(ss.Config.DaTrace)) == nil {
+			ss.Net.LayerByName("SNc").SetDaTrace(dt)
+		}
+	}
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -413,6 +435,10 @@ func (ss *Sim) Init() {
 func (ss *Sim) InitRandSeed(run int) {
 	ss.RandSeeds.Set(run)
 	ss.RandSeeds.Set(run, &ss.Net.Rand)
+	if len(ss.Envs) > 0 { // separate env streams, so runs do not depend on other rand use
+		ss.Envs.ByMode(etime.Train).(*SIREnv).Rand.NewRand(ss.RandSeeds[run])
+		ss.Envs.ByMode(etime.Test).(*SIREnv).Rand.NewRand(ss.RandSeeds[run] + 1)
+	}
 }
 
 // ConfigLoops configures the control loops: Training, Testing
@@ -461,18 +487,6 @@ func (ss *Sim) ConfigLoops() {
 		return true
 	})
 
-	// Train stop early condition
-	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
-		// This is calculated in TrialStats
-		stopNz := ss.Config.NZero
-		if stopNz <= 0 {
-			stopNz = 2
-		}
-		curNZero := ss.Stats.Int("NZero")
-		stop := curNZero >= stopNz
-		return stop
-	})
-
 	// Add Testing
 	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
 	trainEpoch.OnStart.Add("TestAtInterval", func() {
@@ -492,9 +506,8 @@ func (ss *Sim) ConfigLoops() {
 		ss.Log(mode.(etime.Modes), time.(etime.Times))
 	})
 	leabra.LooperResetLogBelow(ls, &ss.Logs)
-	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
-		ss.Logs.RunStats("PctCor", "FirstZero", "LastZero")
-	})
+	// FirstZero, LastZero, NZero reset per run, RunStats, and early stopping
+	leabra.LooperErrStats(ls, &ss.Logs, func() int { return ss.Config.NZero })
 
 	////////////////////////////////////////////
 	// GUI
@@ -517,19 +530,15 @@ func (ss *Sim) ApplyInputs() {
 	ev := ss.Envs.ByMode(ctx.Mode).(*SIREnv)
 	ev.Step()
 
-	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
-	net.InitExt()
 	ss.Stats.SetString("TrialName", ev.String())
-	for _, lnm := range lays {
-		if lnm == "Rew" {
-			continue
-		}
-		ly := ss.Net.LayerByName(lnm)
-		pats := ev.State(ly.Name)
-		if pats != nil {
-			ly.ApplyExt(pats)
-		}
+	if ctx.Mode == etime.Train {
+		net.SetDaTraceTrial(ev.Trial.Cur)
+	} else {
+		net.SetDaTraceTrial(-1)
 	}
+	lays := net.LayersByType(leabra.InputLayer, leabra.TargetLayer)
+	lays = slices.DeleteFunc(lays, func(lnm string) bool { return lnm == "Rew" }) // applied in ApplyReward
+	errors.Log(net.ApplyEnvInputs(ev, lays...))
 }
 
 // ApplyReward computes reward based on network output and applies it.
@@ -586,7 +595,6 @@ func (ss *Sim) InitStats() {
 	ss.Stats.SetFloat("AbsDA", 0.0)
 	ss.Stats.SetFloat("RewPred", 0.0)
 	ss.Stats.SetString("TrialName", "")
-	ss.Logs.InitErrStats() // inits TrlErr, FirstZero, LastZero, NZero
 }
 
 // StatCounters saves current counters to Stats, so they are available for logging etc
@@ -617,37 +625,26 @@ func (ss *Sim) NetViewCounters(tm etime.Times) {
 // TrialStats computes the trial-level statistics.
 // Aggregation is done directly from log data.
 func (ss *Sim) TrialStats() {
-	params := fmt.Sprintf("burst: %g, dip: %g", ss.BurstDaGain, ss.DipDaGain)
+	params := fmt.Sprintf("burst: %g, dip: %g, tonic: %g", ss.BurstDaGain, ss.DipDaGain, ss.TonicDA)
 	ss.Stats.SetString("RunName", params)
 
 	out := ss.Net.LayerByName("Output")
 
-	sse, avgsse := out.MSE(0.5) // 0.5 = per-unit tolerance -- right side of .5
-	ss.Stats.SetFloat("SSE", sse)
-	ss.Stats.SetFloat("AvgSSE", avgsse)
-	if sse > 0 {
-		ss.Stats.SetFloat("TrlErr", 1)
-	} else {
-		ss.Stats.SetFloat("TrlErr", 0)
-	}
+	leabra.ErrStats(out, 0.5).SetStats(&ss.Stats) // 0.5 = per-unit tolerance -- right side of .5
 
 	snc := ss.Net.LayerByName("SNc")
-	ss.Stats.SetFloat32("DA", snc.Neurons[0].Act)
-	ss.Stats.SetFloat32("AbsDA", math32.Abs(snc.Neurons[0].Act))
+	ss.Stats.SetFloat32("DA", float32(snc.Neurons[0].Act))
+	ss.Stats.SetFloat32("AbsDA", math32.Abs(float32(snc.Neurons[0].Act)))
 	rp := ss.Net.LayerByName("RWPred")
-	ss.Stats.SetFloat32("RewPred", rp.Neurons[0].Act)
+	ss.Stats.SetFloat32("RewPred", float32(rp.Neurons[0].Act))
 }
 
 //////////////////////////////////////////////////////////////////////
 // 		Logging
 
 func (ss *Sim) ConfigLogs() {
-	ss.Stats.SetString("RunName", ss.Params.RunName(0)) // used for naming logs, stats, etc
-
-	ss.Logs.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
+	leabra.LogAddStdItems(&ss.Logs, &ss.Stats, ss.Params.RunName(0))
 	ss.Logs.AddStatIntNoAggItem(etime.AllModes, etime.AllTimes, "Expt")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
-	ss.Logs.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
 
 	ss.Logs.AddPerTrlMSec("PerTrlMSec", etime.Run, etime.Epoch, etime.Trial)
 
@@ -661,14 +658,7 @@ func (ss *Sim) ConfigLogs() {
 
 	ss.Logs.PlotItems("PctErr", "AbsDA", "RewPred")
 
-	ss.Logs.CreateTables()
-	ss.Logs.SetContext(&ss.Stats, ss.Net)
-	// don't plot certain combinations we don't use
-	ss.Logs.NoPlot(etime.Train, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Cycle)
-	ss.Logs.NoPlot(etime.Test, etime.Trial)
-	ss.Logs.NoPlot(etime.Test, etime.Run)
-	ss.Logs.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+	leabra.LogConfigStdTables(&ss.Logs, &ss.Stats, ss.Net, etime.Scope(etime.Test, etime.Cycle), etime.Scope(etime.Test, etime.Trial))
 }
 
 // Log is the main logging function, handles special things for different scopes
@@ -6,8 +6,8 @@ package main
 
 import (
 	"fmt"
-	"math/rand"
 
+	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/emergent/v2/env"
 	"github.com/emer/emergent/v2/etime"
@@ -64,6 +64,10 @@ type SIREnv struct {
 
 	// trial is the step counter within epoch
 	Trial env.Counter `display:"inline"`
+
+	// Rand is the random number stream for generating the sequence,
+	// which is seeded by the sim at the start of each run.
+	Rand randx.SysRand `display:"-" json:"-"`
 }
 
 func (ev *SIREnv) Label() string { return ev.Name }
@@ -143,7 +147,7 @@ func (ev *SIREnv) SetReward(netout int) bool {
 // Step the SIR task
 func (ev *SIREnv) StepSIR() {
 	for {
-		ev.Act = Actions(rand.Intn(int(ActionsN)))
+		ev.Act = Actions(ev.Rand.Intn(int(ActionsN)))
 		if ev.Act == Store1 && ev.Maint1 >= 0 { // already full
 			continue
 		}
@@ -158,7 +162,7 @@ func (ev *SIREnv) StepSIR() {
 		}
 		break
 	}
-	ev.Stim = rand.Intn(ev.NStim)
+	ev.Stim = ev.Rand.Intn(ev.NStim)
 	switch ev.Act {
 	case Store1:
 		ev.Maint1 = ev.Stim
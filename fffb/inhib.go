@@ -4,31 +4,31 @@
 
 package fffb
 
-import "cogentcore.org/core/math32/minmax"
+import "github.com/emer/leabra/v2/fmath"
 
 // Inhib contains state values for computed FFFB inhibition
 type Inhib struct {
 
 	// computed feedforward inhibition
-	FFi float32
+	FFi fmath.Float
 
 	// computed feedback inhibition (total)
-	FBi float32
+	FBi fmath.Float
 
 	// overall value of the inhibition -- this is what is added into the unit Gi inhibition level (along with any synaptic unit-driven inhibition)
-	Gi float32
+	Gi fmath.Float
 
 	// original value of the inhibition (before pool or other effects)
-	GiOrig float32
+	GiOrig fmath.Float
 
 	// for pools, this is the layer-level inhibition that is MAX'd with the pool-level inhibition to produce the net inhibition
-	LayGi float32
+	LayGi fmath.Float
 
 	// average and max Ge excitatory conductance values, which drive FF inhibition
-	Ge minmax.AvgMax32
+	Ge fmath.AvgMax
 
 	// average and max Act activation values, which drive FB inhibition
-	Act minmax.AvgMax32
+	Act fmath.AvgMax
 }
 
 func (fi *Inhib) Init() {
@@ -47,7 +47,7 @@ func (fi *Inhib) Zero() {
 }
 
 // Decay reduces inhibition values by given decay proportion
-func (fi *Inhib) Decay(decay float32) {
+func (fi *Inhib) Decay(decay fmath.Float) {
 	fi.Ge.Max -= decay * fi.Ge.Max
 	fi.Ge.Avg -= decay * fi.Ge.Avg
 	fi.Act.Max -= decay * fi.Act.Max
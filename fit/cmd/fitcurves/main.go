@@ -0,0 +1,112 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// fitcurves fits sim parameters to empirical behavioral curves, running
+// the sim as a separate process for each evaluation, and reports the
+// fitted parameters with their uncertainty across seeds.
+// See package fit for details.
+//
+// Usage:
+//
+//	fitcurves [flags] <curves.csv> -- <sim command and args>
+//
+// For example:
+//
+//	fitcurves -param '#Hidden1:Layer.Inhib.Layer.Gi=1.2,2.4' -map PctCor=PctCor \
+//	  curves.csv -- ./ra25 -nogui -Run.NRuns=1 -Run.Run={seed} -Params.Network={params}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/fit"
+	"github.com/emer/leabra/v2/search"
+)
+
+// listFlag is a repeatable string flag.
+type listFlag []string
+
+func (lf *listFlag) String() string     { return strings.Join(*lf, " ") }
+func (lf *listFlag) Set(s string) error { *lf = append(*lf, s); return nil }
+
+func main() {
+	var params, maps listFlag
+	ft := &fit.Fitter{}
+	ft.Defaults()
+	var dir, out, mode string
+	var keep bool
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] <curves.csv> -- <sim command and args, with {seed} and {params}>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Var(&params, "param", "parameter to fit, as Selector:Path=Min,Max[,log] -- repeat for each parameter")
+	flag.Var(&maps, "map", "measure in the curves file to the model log column, as Measure=Column -- default is the same name")
+	flag.StringVar(&ft.XCol, "x", ft.XCol, "model log column corresponding to the curves X values")
+	flag.Float64Var(&ft.XScale, "xscale", ft.XScale, "model X = xoffset + xscale * curves X")
+	flag.Float64Var(&ft.XOffset, "xoffset", ft.XOffset, "model X = xoffset + xscale * curves X")
+	flag.IntVar(&ft.NSeeds, "seeds", ft.NSeeds, "number of seeds to repeat the fit for")
+	flag.Int64Var(&ft.Seed, "seed", ft.Seed, "first seed")
+	flag.IntVar(&ft.Search.MaxEvals, "evals", ft.Search.MaxEvals, "maximum number of runs per seed")
+	flag.StringVar(&mode, "mode", ft.Search.Mode.String(), "search mode: NelderMead, Bayes (Bayesian optimization, for expensive sims), Random, Grid or CMAES")
+	flag.StringVar(&dir, "dir", "fit_runs", "directory for the run directories")
+	flag.BoolVar(&keep, "keep", false, "keep the run directories")
+	flag.StringVar(&out, "out", "", "file to save the table of fitted parameters per seed -- none if empty")
+	flag.Parse()
+	if flag.NArg() < 2 || len(params) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	md, err := search.ParseMode(mode)
+	if err != nil {
+		fail(err)
+	}
+	ft.Search.Mode = md
+	for _, ps := range params {
+		pr, err := search.ParseParam(ps)
+		if err != nil {
+			fail(err)
+		}
+		ft.Search.Space = append(ft.Search.Space, pr)
+	}
+	ft.Columns = map[string]string{}
+	for _, ms := range maps {
+		nm, col, ok := strings.Cut(ms, "=")
+		if !ok {
+			fail(fmt.Errorf("-map must be of the form Measure=Column: %s", ms))
+		}
+		ft.Columns[nm] = col
+	}
+	data, err := fit.OpenCurves(flag.Arg(0))
+	if err != nil {
+		fail(err)
+	}
+	ft.Data = data
+	cr := fit.NewCommandRunner(dir, flag.Args()[1:]...)
+	cr.Keep = keep
+	ft.Run = cr.Run
+	rs, err := ft.Fit()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if rs == nil {
+			os.Exit(1)
+		}
+	}
+	rs.WriteText(os.Stdout)
+	if out != "" {
+		if err := rs.Table().SaveCSV(core.Filename(out), table.Tab, table.Headers); err != nil {
+			fail(err)
+		}
+		fmt.Printf("\nfitted parameters saved in: %s\n", out)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,192 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// SESuffix is the suffix on the name of a column in a curves file
+// that has the standard errors for the measure with the name before it,
+// e.g., Mem_SE for Mem.
+const SESuffix = "_SE"
+
+// Curves are empirical behavioral curves: the values of one or more
+// measures (e.g., conditioned response rate or memory performance) as a
+// function of a common X value (e.g., the training session).
+type Curves struct {
+
+	// XName is the name of the X variable, e.g., Session.
+	XName string
+
+	// X are the X values, one per point.
+	X []float64
+
+	// Names are the names of the measures.
+	Names []string
+
+	// Y are the values of each measure at each X, NaN if missing.
+	Y [][]float64
+
+	// SE are the standard errors of each measure at each X,
+	// which weight the loss by 1 / SE^2, or nil if not present for a
+	// measure.
+	SE [][]float64
+}
+
+// OpenCurves opens the curves from the given file, which is
+// comma-separated, or tab-separated if it has a .tsv extension.
+// See ReadCurves for the format.
+func OpenCurves(filename string) (*Curves, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sep := ','
+	if strings.HasSuffix(filename, ".tsv") {
+		sep = '\t'
+	}
+	return ReadCurves(f, sep)
+}
+
+// ReadCurves reads the curves from the given separated values.  The first
+// line is a header with the column names, and each subsequent line is one
+// point.  The first column is the X value (e.g., Session), and the other
+// columns are the measures, except for columns named with the SESuffix
+// (e.g., Mem_SE), which have the standard errors for the measure of the
+// same name.  Empty or NaN cells are missing values.
+func ReadCurves(r io.Reader, sep rune) (*Curves, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = sep
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	recs, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(recs) < 2 || len(recs[0]) < 2 {
+		return nil, fmt.Errorf("fit.ReadCurves: need a header and at least one point, with an X and a measure column")
+	}
+	hdr := recs[0]
+	cv := &Curves{XName: strings.TrimSpace(hdr[0])}
+	mcol := map[string]int{}
+	for ci, nm := range hdr[1:] {
+		nm = strings.TrimSpace(nm)
+		if !strings.HasSuffix(nm, SESuffix) {
+			mcol[nm] = ci + 1
+			cv.Names = append(cv.Names, nm)
+		}
+	}
+	secol := make([]int, len(cv.Names))
+	for mi, nm := range cv.Names {
+		secol[mi] = slices.IndexFunc(hdr, func(h string) bool { return strings.TrimSpace(h) == nm+SESuffix })
+	}
+	cv.Y = make([][]float64, len(cv.Names))
+	cv.SE = make([][]float64, len(cv.Names))
+	cell := func(rec []string, ci int) (float64, error) {
+		if ci < 0 || ci >= len(rec) || strings.TrimSpace(rec[ci]) == "" {
+			return math.NaN(), nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(rec[ci]), 64)
+	}
+	for ri, rec := range recs[1:] {
+		x, err := cell(rec, 0)
+		if err != nil || math.IsNaN(x) {
+			return nil, fmt.Errorf("fit.ReadCurves: line %d: invalid %s value", ri+2, cv.XName)
+		}
+		cv.X = append(cv.X, x)
+		for mi, nm := range cv.Names {
+			y, err := cell(rec, mcol[nm])
+			if err != nil {
+				return nil, fmt.Errorf("fit.ReadCurves: line %d: %s: %w", ri+2, nm, err)
+			}
+			cv.Y[mi] = append(cv.Y[mi], y)
+			if secol[mi] < 0 {
+				continue
+			}
+			se, err := cell(rec, secol[mi])
+			if err != nil {
+				return nil, fmt.Errorf("fit.ReadCurves: line %d: %s: %w", ri+2, nm+SESuffix, err)
+			}
+			cv.SE[mi] = append(cv.SE[mi], se)
+		}
+	}
+	return cv, nil
+}
+
+// Index returns the index of the measure with the given name, or -1.
+func (cv *Curves) Index(name string) int {
+	return slices.Index(cv.Names, name)
+}
+
+// modelCurve is the mean value of a column of a model log at each
+// (sorted, unique) X value.
+type modelCurve struct {
+	x, y []float64
+}
+
+// newModelCurve returns the modelCurve for the given column of the log,
+// as a function of the given X column, averaging the values at the same
+// X, e.g., across multiple runs, and skipping missing (NaN) values.
+func newModelCurve(dt *table.Table, xcol, ycol string) (*modelCurve, error) {
+	xc, err := dt.ColumnByName(xcol)
+	if err != nil {
+		return nil, err
+	}
+	yc, err := dt.ColumnByName(ycol)
+	if err != nil {
+		return nil, err
+	}
+	sum := map[float64]float64{}
+	n := map[float64]int{}
+	for ri := range dt.Rows {
+		x := xc.Float1D(ri)
+		y := yc.Float1D(ri)
+		if math.IsNaN(x) || math.IsNaN(y) {
+			continue
+		}
+		sum[x] += y
+		n[x]++
+	}
+	mc := &modelCurve{}
+	for x := range sum {
+		mc.x = append(mc.x, x)
+	}
+	sort.Float64s(mc.x)
+	for _, x := range mc.x {
+		mc.y = append(mc.y, sum[x]/float64(n[x]))
+	}
+	return mc, nil
+}
+
+// value returns the model value at the given X, linearly interpolated,
+// and extended as the first or last value outside the range of the
+// model, e.g., for runs that stopped early at criterion.
+// Returns NaN if the curve is empty.
+func (mc *modelCurve) value(x float64) float64 {
+	n := len(mc.x)
+	switch {
+	case n == 0:
+		return math.NaN()
+	case x <= mc.x[0]:
+		return mc.y[0]
+	case x >= mc.x[n-1]:
+		return mc.y[n-1]
+	}
+	i := sort.SearchFloat64s(mc.x, x) // mc.x[i-1] < x <= mc.x[i]
+	f := (x - mc.x[i-1]) / (mc.x[i] - mc.x[i-1])
+	return mc.y[i-1] + f*(mc.y[i]-mc.y[i-1])
+}
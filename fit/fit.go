@@ -0,0 +1,300 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package fit fits simulation parameters to empirical behavioral curves,
+e.g., the per-session conditioned response rate or memory performance
+from an experiment, loaded as Curves from a CSV file.
+
+A Fitter defines the loss of a model run as the (standard error weighted)
+mean squared difference between the empirical curves and the
+corresponding columns of the run's epoch log, and minimizes the loss over
+a search.Space of parameters using the search package.  The fit is
+repeated for multiple random seeds, each of which is used for all of the
+runs within one fit, and the spread of the fitted parameters across
+seeds provides the uncertainty of the estimates.
+
+The sims are run by a RunFunc, which can run them in-process, or as
+separate processes using a CommandRunner.  The fitcurves command in
+cmd/fitcurves provides a command-line interface using a CommandRunner.
+*/
+package fit
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// RunFunc runs the model with the given parameters (a map from params
+// Selector:Path keys to values, see search.Space.Map) and random seed,
+// returning the resulting log with the columns to fit, typically the
+// training epoch log.
+type RunFunc func(pars map[string]any, seed int64) (*table.Table, error)
+
+// Fitter fits parameters to empirical Curves.
+type Fitter struct {
+
+	// Data are the empirical curves to fit.
+	Data *Curves
+
+	// Columns maps the names of the measures in Data to the names of
+	// the corresponding columns in the model log, if different.
+	Columns map[string]string
+
+	// XCol is the name of the column in the model log corresponding
+	// to the X values of the Data, e.g., Epoch.
+	XCol string
+
+	// XScale and XOffset convert the Data X values into model XCol
+	// values, as XOffset + XScale * X, e.g., the number of epochs
+	// per session.
+	XScale, XOffset float64
+
+	// Search has the parameter Space to search and the search options.
+	Search search.Search
+
+	// Run runs the model.
+	Run RunFunc
+
+	// NSeeds is the number of random seeds to repeat the fit for.
+	NSeeds int
+
+	// Seed is the first random seed, with subsequent seeds incrementing by 1.
+	Seed int64
+}
+
+// NewFitter returns a new Fitter for the given data, parameter
+// space and run function, with default settings.
+func NewFitter(data *Curves, space search.Space, run RunFunc) *Fitter {
+	ft := &Fitter{Data: data, Run: run}
+	ft.Defaults()
+	ft.Search.Space = space
+	return ft
+}
+
+func (ft *Fitter) Defaults() {
+	ft.XCol = "Epoch"
+	ft.XScale = 1
+	ft.NSeeds = 5
+	ft.Seed = 1
+	ft.Search.Defaults()
+}
+
+// Column returns the model log column for the given measure.
+func (ft *Fitter) Column(name string) string {
+	if col, ok := ft.Columns[name]; ok {
+		return col
+	}
+	return name
+}
+
+// Loss returns the loss of the given model log relative to the Data,
+// as the mean squared difference over all of the points, weighted by
+// 1 / SE^2 if the Data has standard errors for a measure.
+// Points with a missing or non-positive SE are skipped.
+func (ft *Fitter) Loss(model *table.Table) (float64, error) {
+	var sum, wsum float64
+	var errs []error
+	for mi, nm := range ft.Data.Names {
+		mc, err := newModelCurve(model, ft.XCol, ft.Column(nm))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for pi, x := range ft.Data.X {
+			y := ft.Data.Y[mi][pi]
+			if math.IsNaN(y) {
+				continue
+			}
+			w := 1.0
+			if ft.Data.SE[mi] != nil {
+				se := ft.Data.SE[mi][pi]
+				if !(se > 0) {
+					continue
+				}
+				w = 1 / (se * se)
+			}
+			d := mc.value(ft.XOffset+ft.XScale*x) - y
+			sum += w * d * d
+			wsum += w
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return math.NaN(), fmt.Errorf("fit.Loss: %w", err)
+	}
+	if wsum == 0 {
+		return math.NaN(), errors.New("fit.Loss: no data points to fit")
+	}
+	return sum / wsum, nil
+}
+
+// Fit runs the search for each of the NSeeds seeds, and returns the
+// Result with the fitted parameters for each seed, and their mean
+// and uncertainty across seeds.
+func (ft *Fitter) Fit() (*Result, error) {
+	if ft.Data == nil || ft.Run == nil {
+		return nil, errors.New("fit.Fit: Data and Run must be set")
+	}
+	if err := ft.Search.Space.Check(); err != nil {
+		return nil, err
+	}
+	nseeds := max(ft.NSeeds, 1)
+	rs := &Result{Space: ft.Search.Space}
+	var errs []error
+	for si := range nseeds {
+		seed := ft.Seed + int64(si)
+		sr := ft.Search
+		sr.Seed = seed
+		best, err := sr.Minimize(func(vals []float64) (float64, error) {
+			model, err := ft.Run(sr.Space.Map(vals), seed)
+			if err != nil {
+				return math.NaN(), err
+			}
+			return ft.Loss(model)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("seed %d: %w", seed, err))
+		}
+		if math.IsInf(best.Loss, 1) {
+			continue
+		}
+		rs.Seeds = append(rs.Seeds, seed)
+		rs.Fits = append(rs.Fits, best)
+		rs.NEvals = append(rs.NEvals, len(sr.Evals))
+	}
+	if len(rs.Fits) == 0 {
+		return nil, fmt.Errorf("fit.Fit: no successful fits: %w", errors.Join(errs...))
+	}
+	rs.estimate()
+	return rs, errors.Join(errs...)
+}
+
+// Estimate is the estimate of a fitted value across seeds.
+type Estimate struct {
+
+	// Name of the value.
+	Name string
+
+	// Mean across seeds.
+	Mean float64
+
+	// SD is the standard deviation across seeds, which is the
+	// uncertainty of the value obtained from one fit.
+	SD float64
+
+	// SE is the standard error of the Mean (SD / sqrt(N)).
+	SE float64
+}
+
+// Result is the result of a Fit.
+type Result struct {
+
+	// Space is the parameter space that was searched.
+	Space search.Space
+
+	// Seeds are the random seeds of the successful fits.
+	Seeds []int64
+
+	// Fits are the best fitting parameters and loss for each seed.
+	Fits []search.Eval
+
+	// NEvals are the number of evaluations (runs) for each seed.
+	NEvals []int
+
+	// Params are the estimates of each parameter across seeds.
+	Params []Estimate
+
+	// Loss is the estimate of the best loss across seeds.
+	Loss Estimate
+}
+
+// estimate computes the Params and Loss estimates from the Fits.
+func (rs *Result) estimate() {
+	vals := make([]float64, len(rs.Fits))
+	est := func(name string, val func(fi int) float64) Estimate {
+		for fi := range rs.Fits {
+			vals[fi] = val(fi)
+		}
+		return newEstimate(name, vals)
+	}
+	rs.Params = make([]Estimate, len(rs.Space))
+	for pi := range rs.Space {
+		rs.Params[pi] = est(rs.Space[pi].Key, func(fi int) float64 { return rs.Fits[fi].Values[pi] })
+	}
+	rs.Loss = est("Loss", func(fi int) float64 { return rs.Fits[fi].Loss })
+}
+
+// newEstimate returns the Estimate for the given values.
+func newEstimate(name string, vals []float64) Estimate {
+	es := Estimate{Name: name}
+	n := float64(len(vals))
+	for _, v := range vals {
+		es.Mean += v
+	}
+	es.Mean /= n
+	if n < 2 {
+		es.SD, es.SE = math.NaN(), math.NaN()
+		return es
+	}
+	for _, v := range vals {
+		d := v - es.Mean
+		es.SD += d * d
+	}
+	es.SD = math.Sqrt(es.SD / (n - 1))
+	es.SE = es.SD / math.Sqrt(n)
+	return es
+}
+
+// Best returns the fit with the lowest loss across seeds.
+func (rs *Result) Best() search.Eval {
+	bi := 0
+	for fi := range rs.Fits {
+		if rs.Fits[fi].Loss < rs.Fits[bi].Loss {
+			bi = fi
+		}
+	}
+	return rs.Fits[bi]
+}
+
+// Table returns a table with one row per seed, with the Seed, NEvals,
+// Loss and fitted value of each parameter (named by key).
+func (rs *Result) Table() *table.Table {
+	dt := table.NewTable()
+	dt.AddIntColumn("Seed")
+	dt.AddIntColumn("NEvals")
+	dt.AddFloat64Column("Loss")
+	for _, pr := range rs.Space {
+		dt.AddFloat64Column(pr.Key)
+	}
+	dt.SetNumRows(len(rs.Fits))
+	for fi, ev := range rs.Fits {
+		dt.SetFloat("Seed", fi, float64(rs.Seeds[fi]))
+		dt.SetFloat("NEvals", fi, float64(rs.NEvals[fi]))
+		dt.SetFloat("Loss", fi, ev.Loss)
+		for pi, pr := range rs.Space {
+			dt.SetFloat(pr.Key, fi, ev.Values[pi])
+		}
+	}
+	return dt
+}
+
+// WriteText writes a text report of the fitted parameters, with their
+// mean, SD and SE across seeds, and the fit for each seed.
+func (rs *Result) WriteText(w io.Writer) {
+	g := func(v float64) string { return strconv.FormatFloat(v, 'g', 4, 64) }
+	fmt.Fprintf(w, "Fitted parameters across %d seeds:\n", len(rs.Fits))
+	for _, es := range append(rs.Params, rs.Loss) {
+		fmt.Fprintf(w, "  %-40s %10s  SD: %-10s SE: %s\n", es.Name, g(es.Mean), g(es.SD), g(es.SE))
+	}
+	fmt.Fprintf(w, "\nPer seed:\n")
+	for fi, ev := range rs.Fits {
+		fmt.Fprintf(w, "  Seed: %d  Evals: %d  Loss: %s  Params: %s\n", rs.Seeds[fi], rs.NEvals[fi], g(ev.Loss), rs.Space.TOML(ev.Values))
+	}
+}
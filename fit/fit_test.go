@@ -0,0 +1,61 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// curveLog returns an epoch log with PctCor = 1 - exp(-rate * Epoch)
+// for epochs 0-19, plus the given offset.
+func curveLog(rate, off float64) *table.Table {
+	dt := table.NewTable()
+	dt.AddIntColumn("Epoch")
+	dt.AddFloat64Column("PctCor")
+	dt.SetNumRows(20)
+	for epc := range 20 {
+		dt.SetFloat("Epoch", epc, float64(epc))
+		dt.SetFloat("PctCor", epc, 1-math.Exp(-rate*float64(epc))+off)
+	}
+	return dt
+}
+
+func TestFit(t *testing.T) {
+	csv := "Session,Mem,Mem_SE\n1,0.3934693,0.05\n2,0.6321206,0.05\n3,0.7768698,0.05\n4,0.8646647,0.05\n"
+	data, err := ReadCurves(strings.NewReader(csv), ',')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data.Names) != 1 || data.Names[0] != "Mem" || len(data.SE[0]) != 4 {
+		t.Fatalf("ReadCurves: %+v", data)
+	}
+	sp := search.Space{{Key: "Layer:Layer.Learn.Rate", Min: 0.05, Max: 1, Log: true}}
+	ft := NewFitter(data, sp, func(pars map[string]any, seed int64) (*table.Table, error) {
+		return curveLog(pars["Layer:Layer.Learn.Rate"].(float64), 0.01*float64(seed-2)), nil
+	})
+	ft.Columns = map[string]string{"Mem": "PctCor"}
+	ft.XScale = 2 // 2 epochs per session: data generated with rate 0.25
+	ft.NSeeds = 3
+	ft.Search.MaxEvals = 100
+	ft.Search.Tol = 1e-12
+	if loss, err := ft.Loss(curveLog(0.25, 0)); err != nil || loss > 1e-12 {
+		t.Errorf("Loss: %g %v", loss, err)
+	}
+	rs, err := ft.Fit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rs.Fits) != 3 || math.Abs(rs.Params[0].Mean-0.25) > 0.01 || !(rs.Params[0].SD > 0) {
+		t.Errorf("Fit: %+v", rs.Params)
+	}
+	if dt := rs.Table(); dt.Rows != 3 {
+		t.Errorf("Table: %d rows, not 3", dt.Rows)
+	}
+}
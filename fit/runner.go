@@ -0,0 +1,127 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package fit
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// CommandRunner runs a sim as a separate process for each evaluation,
+// in its own directory, and reads the resulting epoch log, providing
+// a RunFunc.  The sim must run without the GUI and save its epoch log,
+// e.g., for the ra25 example:
+//
+//	ra25 -nogui -Run.NRuns=1 -Run.Run={seed} -Params.Network={params}
+type CommandRunner struct {
+
+	// Command is the sim executable and its arguments, where the
+	// string {seed} is replaced by the random seed (passed as the
+	// starting run number in the example above, which determines the
+	// random seed), and {params} by the parameters as a TOML inline
+	// table (see search.Space.TOML), as used for the Params.Network
+	// config of the example sims.
+	Command []string
+
+	// Dir is the directory in which the run directories are made,
+	// named run_<n>.
+	Dir string
+
+	// LogSuffix is the suffix of the log file to read from the run
+	// directory, excluding testing epoch logs (*_tst_epc.tsv).
+	LogSuffix string
+
+	// Keep keeps the run directories, which are otherwise removed
+	// after reading the log.
+	Keep bool
+
+	// NRuns is the number of runs so far, used for naming the
+	// run directories.
+	NRuns int
+}
+
+// NewCommandRunner returns a new CommandRunner for the given command,
+// making the run directories in given dir.
+func NewCommandRunner(dir string, command ...string) *CommandRunner {
+	return &CommandRunner{Command: command, Dir: dir, LogSuffix: "_epc.tsv"}
+}
+
+// Run runs the command with the given parameters and seed,
+// returning the log.  It is a RunFunc.
+func (cr *CommandRunner) Run(pars map[string]any, seed int64) (*table.Table, error) {
+	if len(cr.Command) == 0 {
+		return nil, fmt.Errorf("fit.CommandRunner: no Command")
+	}
+	rdir := filepath.Join(cr.Dir, fmt.Sprintf("run_%d", cr.NRuns))
+	cr.NRuns++
+	if err := os.MkdirAll(rdir, 0755); err != nil {
+		return nil, err
+	}
+	if !cr.Keep {
+		defer os.RemoveAll(rdir)
+	}
+	ptoml := mapTOML(pars)
+	args := make([]string, len(cr.Command))
+	for i, a := range cr.Command {
+		a = strings.ReplaceAll(a, "{seed}", strconv.FormatInt(seed, 10))
+		args[i] = strings.ReplaceAll(a, "{params}", ptoml)
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = rdir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("fit.CommandRunner: %s: %w\n%s", strings.Join(args, " "), err, out.String())
+	}
+	ents, err := os.ReadDir(rdir)
+	if err != nil {
+		return nil, err
+	}
+	var logs []string
+	for _, ent := range ents {
+		nm := ent.Name()
+		if !ent.IsDir() && strings.HasSuffix(nm, cr.LogSuffix) && !strings.HasSuffix(nm, "_tst"+cr.LogSuffix) {
+			logs = append(logs, filepath.Join(rdir, nm))
+		}
+	}
+	if len(logs) == 0 {
+		return nil, fmt.Errorf("fit.CommandRunner: no *%s log file saved by: %s", cr.LogSuffix, strings.Join(args, " "))
+	}
+	slices.Sort(logs)
+	dt := table.NewTable()
+	if err := dt.OpenCSV(core.Filename(logs[0]), table.Tab); err != nil {
+		return nil, err
+	}
+	return dt, nil
+}
+
+// mapTOML returns the parameter map as a TOML inline table,
+// in sorted key order.
+func mapTOML(pars map[string]any) string {
+	var sp search.Space
+	var vals []float64
+	keys := make([]string, 0, len(pars))
+	for k := range pars {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		v, _ := pars[k].(float64)
+		sp = append(sp, search.Param{Key: k})
+		vals = append(vals, v)
+	}
+	return sp.TOML(vals)
+}
@@ -0,0 +1,42 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !leabra64
+
+package fmath
+
+import (
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/math32/minmax"
+)
+
+// Float is the floating point type used in the engine.
+type Float = float32
+
+// Range is a min / max range of Float values.
+type Range = minmax.F32
+
+// AvgMax holds average and max statistics of Float values.
+type AvgMax = minmax.AvgMax32
+
+// Size is the number of bytes in the Float type.
+const Size = 4
+
+// math functions on Float, from math32
+
+func Abs(x Float) Float            { return math32.Abs(x) }
+func Cos(x Float) Float            { return math32.Cos(x) }
+func Exp(x Float) Float            { return math32.Exp(x) }
+func FastExp(x Float) Float        { return math32.FastExp(x) }
+func Inf(sign int) Float           { return math32.Inf(sign) }
+func IsInf(x Float, sign int) bool { return math32.IsInf(x, sign) }
+func IsNaN(x Float) bool           { return math32.IsNaN(x) }
+func Log(x Float) Float            { return math32.Log(x) }
+func Max(x, y Float) Float         { return math32.Max(x, y) }
+func Min(x, y Float) Float         { return math32.Min(x, y) }
+func NaN() Float                   { return math32.NaN() }
+func Pow(x, y Float) Float         { return math32.Pow(x, y) }
+func Round(x Float) Float          { return math32.Round(x) }
+func Sin(x Float) Float            { return math32.Sin(x) }
+func Sqrt(x Float) Float           { return math32.Sqrt(x) }
@@ -0,0 +1,105 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build leabra64
+
+package fmath
+
+import (
+	"fmt"
+	"math"
+
+	"cogentcore.org/core/math32/minmax"
+)
+
+// Float is the floating point type used in the engine.
+type Float = float64
+
+// Range is a min / max range of Float values.
+type Range = minmax.F64
+
+// AvgMax holds average and max statistics of Float values,
+// with the same fields and methods as minmax.AvgMax32.
+type AvgMax struct {
+	Avg Float
+	Max Float
+
+	// sum for computing average
+	Sum Float
+
+	// index of max item
+	MaxIndex int32
+
+	// number of items in sum
+	N int32
+}
+
+// Init initializes prior to new updates
+func (am *AvgMax) Init() {
+	am.Avg = 0
+	am.Sum = 0
+	am.N = 0
+	am.Max = -math.MaxFloat64
+	am.MaxIndex = -1
+}
+
+// UpdateValue updates stats from given value
+func (am *AvgMax) UpdateValue(val Float, idx int32) {
+	am.Sum += val
+	am.N++
+	if val > am.Max {
+		am.Max = val
+		am.MaxIndex = idx
+	}
+}
+
+// UpdateFromOther updates these values from other AvgMax values
+func (am *AvgMax) UpdateFromOther(oSum, oMax Float, oN, oMaxIndex int32) {
+	am.Sum += oSum
+	am.N += oN
+	if oMax > am.Max {
+		am.Max = oMax
+		am.MaxIndex = oMaxIndex
+	}
+}
+
+// CalcAvg computes the average given the current Sum and N values
+func (am *AvgMax) CalcAvg() {
+	if am.N > 0 {
+		am.Avg = am.Sum / Float(am.N)
+	} else {
+		am.Avg = am.Sum
+		am.Max = am.Avg // prevents Max from being -MaxFloat..
+	}
+}
+
+func (am *AvgMax) String() string {
+	return fmt.Sprintf("{Avg: %g, Max: %g, Sum: %g, MaxIndex: %d, N: %d}", am.Avg, am.Max, am.Sum, am.MaxIndex, am.N)
+}
+
+// CopyFrom copies from other AvgMax
+func (am *AvgMax) CopyFrom(oth *AvgMax) {
+	*am = *oth
+}
+
+// Size is the number of bytes in the Float type.
+const Size = 8
+
+// math functions on Float, from the standard math package
+
+func Abs(x Float) Float            { return math.Abs(x) }
+func Cos(x Float) Float            { return math.Cos(x) }
+func Exp(x Float) Float            { return math.Exp(x) }
+func FastExp(x Float) Float        { return math.Exp(x) }
+func Inf(sign int) Float           { return math.Inf(sign) }
+func IsInf(x Float, sign int) bool { return math.IsInf(x, sign) }
+func IsNaN(x Float) bool           { return math.IsNaN(x) }
+func Log(x Float) Float            { return math.Log(x) }
+func Max(x, y Float) Float         { return math.Max(x, y) }
+func Min(x, y Float) Float         { return math.Min(x, y) }
+func NaN() Float                   { return math.NaN() }
+func Pow(x, y Float) Float         { return math.Pow(x, y) }
+func Round(x Float) Float          { return math.Round(x) }
+func Sin(x Float) Float            { return math.Sin(x) }
+func Sqrt(x Float) Float           { return math.Sqrt(x) }
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package fmath provides the floating point type, Float, and the math
+functions on it, used for all of the state and parameters of the leabra
+engine (the leabra, chans, fffb, knadapt and nxx1 packages).
+
+Float is float32 by default, using the cogentcore math32 functions.
+Building with the leabra64 build tag makes it float64, using the
+standard library math functions, to run the entire engine in double
+precision for verification, e.g., cross-checking optimized or GPU
+versions of the computations against higher-precision results:
+
+	go test -tags leabra64 ./leabra
+
+The only intended difference in the computations between the two modes
+is the precision, except that FastExp is the exact Exp in float64 mode.
+Values exchanged with the rest of the emergent framework (layer unit
+values, tensors, the JSON weights) remain float32, and are converted
+at that boundary.  The binary weights format records the size of the
+weight values, and either size can be read in either mode
+(see leabra.ConvertWeightsBinary).
+*/
+package fmath
+
+// Bits is the number of bits in the Float type: 32 or 64.
+const Bits = 8 * Size
+
+// Clamp returns x clamped to the range a to b.
+func Clamp(x, a, b Float) Float {
+	if x < a {
+		return a
+	}
+	if x > b {
+		return b
+	}
+	return x
+}
@@ -19,6 +19,8 @@ This package supports both spiking and rate-coded activations.
 */
 package knadapt
 
+import "github.com/emer/leabra/v2/fmath"
+
 //go:generate core generate -add-types
 
 // Chan describes one channel type of sodium-gated adaptation, with a specific
@@ -29,16 +31,16 @@ type Chan struct {
 	On bool
 
 	// Rise rate of fast time-scale adaptation as function of Na concentration -- directly multiplies -- 1/rise = tau for rise rate
-	Rise float32
+	Rise fmath.Float
 
 	// Maximum potential conductance of fast K channels -- divide nA biological value by 10 for the normalized units here
-	Max float32
+	Max fmath.Float
 
 	// time constant in cycles for decay of adaptation, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life)
-	Tau float32
+	Tau fmath.Float
 
 	// 1/Tau rate constant
-	Dt float32 `display:"-"`
+	Dt fmath.Float `display:"-"`
 }
 
 func (ka *Chan) Defaults() {
@@ -63,7 +65,7 @@ func (ka *Chan) ShouldDisplay(field string) bool {
 }
 
 // GcFromSpike updates the KNa conductance based on spike or not
-func (ka *Chan) GcFromSpike(gKNa *float32, spike bool) {
+func (ka *Chan) GcFromSpike(gKNa *fmath.Float, spike bool) {
 	if ka.On {
 		if spike {
 			*gKNa += ka.Rise * (ka.Max - *gKNa)
@@ -77,7 +79,7 @@ func (ka *Chan) GcFromSpike(gKNa *float32, spike bool) {
 
 // GcFromRate updates the KNa conductance based on rate-coded activation.
 // act should already have the compensatory rate multiplier prior to calling.
-func (ka *Chan) GcFromRate(gKNa *float32, act float32) {
+func (ka *Chan) GcFromRate(gKNa *fmath.Float, act fmath.Float) {
 	if ka.On {
 		*gKNa += act*ka.Rise*(ka.Max-*gKNa) - (ka.Dt * *gKNa)
 	} else {
@@ -94,7 +96,7 @@ type Params struct {
 	On bool
 
 	// extra multiplier for rate-coded activations on rise factors -- adjust to match discrete spiking
-	Rate float32 `default:"0.8"`
+	Rate fmath.Float `default:"0.8"`
 
 	// fast time-scale adaptation
 	Fast Chan `display:"inline"`
@@ -139,14 +141,14 @@ func (ka *Params) ShouldDisplay(field string) bool {
 }
 
 // GcFromSpike updates all time scales of KNa adaptation from spiking
-func (ka *Params) GcFromSpike(gKNaF, gKNaM, gKNaS *float32, spike bool) {
+func (ka *Params) GcFromSpike(gKNaF, gKNaM, gKNaS *fmath.Float, spike bool) {
 	ka.Fast.GcFromSpike(gKNaF, spike)
 	ka.Med.GcFromSpike(gKNaM, spike)
 	ka.Slow.GcFromSpike(gKNaS, spike)
 }
 
 // GcFromRate updates all time scales of KNa adaptation from rate code activation
-func (ka *Params) GcFromRate(gKNaF, gKNaM, gKNaS *float32, act float32) {
+func (ka *Params) GcFromRate(gKNaF, gKNaM, gKNaS *fmath.Float, act fmath.Float) {
 	act *= ka.Rate
 	ka.Fast.GcFromRate(gKNaF, act)
 	ka.Med.GcFromRate(gKNaM, act)
@@ -0,0 +1,167 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// AblationSweep systematically repeats a test battery with each layer
+// and each pathway of the network ablated in turn, using the lesion API
+// (LesionUnits and LesionSyns), to determine the contribution of each
+// to the test stats, i.e., "what breaks when X is off".
+// Each ablation is reversed before the next one, so the network is left
+// in its original state.
+type AblationSweep struct {
+
+	// Layers are the names of the layers to ablate.
+	// If empty, all layers that are not Off are ablated.
+	Layers []string
+
+	// Paths are the names of the pathways to ablate.
+	// If empty, all pathways that are not Off are ablated.
+	Paths []string
+
+	// NoLayers skips the ablation of layers.
+	NoLayers bool
+
+	// NoPaths skips the ablation of pathways.
+	NoPaths bool
+
+	// Stats are the names of the stats returned by the test function,
+	// in order, set from the stats of the intact network if empty.
+	Stats []string
+
+	// Results is the contribution table, with one row for the intact
+	// network followed by one row per ablation, with Ablation (name of
+	// the layer or pathway, or Intact) and Kind (Layer, Path or Intact)
+	// columns, a column for each stat, and a <stat>_Diff column with the
+	// difference from the intact network.
+	Results *table.Table
+}
+
+// Run runs the sweep on the given network, using the given test function,
+// which typically runs a full set of testing trials, and returns a map of
+// stat values (e.g., PctCor, UnitErr).  Results has the resulting table.
+func (as *AblationSweep) Run(net *Network, test func(net *Network) map[string]float64) *table.Table {
+	intact := test(net)
+	if len(as.Stats) == 0 {
+		for nm := range intact {
+			as.Stats = append(as.Stats, nm)
+		}
+		slices.Sort(as.Stats)
+	}
+	dt := table.NewTable()
+	dt.AddStringColumn("Ablation")
+	dt.AddStringColumn("Kind")
+	for _, st := range as.Stats {
+		dt.AddFloat64Column(st)
+		dt.AddFloat64Column(st + "_Diff")
+	}
+	as.Results = dt
+	as.addRow("Intact", "Intact", intact, intact)
+
+	if !as.NoLayers {
+		for _, ly := range as.layers(net) {
+			if ly.NLesionedUnits() == len(ly.Neurons) {
+				continue
+			}
+			idxs := ly.LesionUnits(1)
+			as.addRow(ly.Name, "Layer", test(net), intact)
+			ly.UnLesionUnits(idxs...)
+		}
+	}
+	if !as.NoPaths {
+		for _, pt := range as.paths(net) {
+			if pt.NLesionedSyns() > 0 {
+				// UnLesionSyns would also reverse the existing lesions
+				fmt.Printf("leabra.AblationSweep: skipping pathway %s, which already has lesioned synapses\n", pt.Name)
+				continue
+			}
+			pt.LesionSyns(1)
+			as.addRow(pt.Name, "Path", test(net), intact)
+			pt.UnLesionSyns()
+		}
+	}
+	return dt
+}
+
+// layers returns the layers to ablate.
+func (as *AblationSweep) layers(net *Network) []*Layer {
+	var lys []*Layer
+	if len(as.Layers) == 0 {
+		for _, ly := range net.Layers {
+			if !ly.Off {
+				lys = append(lys, ly)
+			}
+		}
+		return lys
+	}
+	for _, lnm := range as.Layers {
+		ly := net.LayerByName(lnm)
+		if ly == nil {
+			fmt.Printf("leabra.AblationSweep: layer named %s not found\n", lnm)
+			continue
+		}
+		lys = append(lys, ly)
+	}
+	return lys
+}
+
+// paths returns the pathways to ablate.
+func (as *AblationSweep) paths(net *Network) []*Path {
+	var pts []*Path
+	for _, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			if len(as.Paths) == 0 {
+				if !pt.Off && !ly.Off && !pt.Send.Off {
+					pts = append(pts, pt)
+				}
+			} else if slices.Contains(as.Paths, pt.Name) {
+				pts = append(pts, pt)
+			}
+		}
+	}
+	return pts
+}
+
+// addRow adds a row to the Results for given ablation.
+func (as *AblationSweep) addRow(name, kind string, stats, intact map[string]float64) {
+	dt := as.Results
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetString("Ablation", row, name)
+	dt.SetString("Kind", row, kind)
+	for _, st := range as.Stats {
+		dt.SetFloat(st, row, stats[st])
+		dt.SetFloat(st+"_Diff", row, stats[st]-intact[st])
+	}
+}
+
+// String returns the Results as a text table, one row per ablation.
+func (as *AblationSweep) String() string {
+	dt := as.Results
+	if dt == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("Ablation\tKind")
+	for _, st := range as.Stats {
+		fmt.Fprintf(&b, "\t%s\t%s_Diff", st, st)
+	}
+	b.WriteString("\n")
+	for row := range dt.Rows {
+		fmt.Fprintf(&b, "%s\t%s", dt.StringValue("Ablation", row), dt.StringValue("Kind", row))
+		for _, st := range as.Stats {
+			fmt.Fprintf(&b, "\t%.4g\t%.4g", dt.Float(st, row), dt.Float(st+"_Diff", row))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
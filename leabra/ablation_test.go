@@ -0,0 +1,35 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestAblationSweep(t *testing.T) {
+	net := NewNetwork("Ablation")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	pt := hid.RecvPaths[0]
+	as := &AblationSweep{}
+	dt := as.Run(net, func(net *Network) map[string]float64 {
+		return map[string]float64{"NOff": float64(hid.NLesionedUnits()), "NSyns": float64(pt.NLesionedSyns())}
+	})
+	if dt.Rows != 4 {
+		t.Fatalf("AblationSweep: %d rows, not 4", dt.Rows)
+	}
+	CmprFloats([]float32{float32(dt.Float("NOff", 2)), float32(dt.Float("NOff_Diff", 2)), float32(dt.Float("NSyns", 3))}, []float32{4, 4, 16}, "AblationSweep", t)
+	if dt.StringValue("Ablation", 3) != pt.Name || hid.NLesionedUnits() != 0 || pt.NLesionedSyns() != 0 {
+		t.Errorf("AblationSweep: ablations not reversed")
+	}
+}
@@ -6,9 +6,8 @@ package leabra
 
 import (
 	"cogentcore.org/core/base/randx"
-	"cogentcore.org/core/math32"
-	"cogentcore.org/core/math32/minmax"
 	"github.com/emer/leabra/v2/chans"
+	"github.com/emer/leabra/v2/fmath"
 	"github.com/emer/leabra/v2/knadapt"
 	"github.com/emer/leabra/v2/nxx1"
 )
@@ -42,15 +41,21 @@ type ActParams struct {
 	// how external inputs drive neural activations
 	Clamp ClampParams `display:"inline"`
 
+	// curriculum over training epochs from hard to increasingly weak soft clamping, typically for the plus phase targets of a TargetLayer
+	ClampSched ClampSchedParams `display:"inline"`
+
 	// how, where, when, and how much noise to add to activations
 	Noise ActNoiseParams `display:"inline"`
 
 	// range for Vm membrane potential -- by default
-	VmRange minmax.F32 `display:"inline"`
+	VmRange fmath.Range `display:"inline"`
 
 	// sodium-gated potassium channel adaptation parameters -- activates an inhibitory leak-like current as a function of neural activity (firing = Na influx) at three different time-scales (M-type = fast, Slick = medium, Slack = slow)
 	KNa knadapt.Params `display:"no-inline"`
 
+	// slow after-hyperpolarization (sAHP) adaptation parameters -- activates a slowly decaying potassium current as a function of neural activity, which persists across trials, producing firing-rate adaptation with repeated presentations
+	AHP AHPParams `display:"no-inline"`
+
 	// Erev - Act.Thr for each channel -- used in computing GeThrFromG among others
 	ErevSubThr chans.Chans `edit:"-" display:"-" json:"-" xml:"-"`
 
@@ -66,9 +71,11 @@ func (ac *ActParams) Defaults() {
 	ac.Gbar.SetAll(1.0, 0.1, 1.0, 1.0)
 	ac.Erev.SetAll(1.0, 0.3, 0.25, 0.25)
 	ac.Clamp.Defaults()
+	ac.ClampSched.Defaults()
 	ac.VmRange.Max = 2.0
 	ac.KNa.Defaults()
 	ac.KNa.On = false
+	ac.AHP.Defaults()
 	ac.Noise.Defaults()
 	ac.Update()
 }
@@ -83,8 +90,10 @@ func (ac *ActParams) Update() {
 	ac.Init.Update()
 	ac.Dt.Update()
 	ac.Clamp.Update()
+	ac.ClampSched.Update()
 	ac.Noise.Update()
 	ac.KNa.Update()
+	ac.AHP.Update()
 }
 
 ///////////////////////////////////////////////////////////////////////
@@ -103,7 +112,7 @@ func (ac *ActParams) InitGInc(nrn *Neuron) {
 
 // DecayState decays the activation state toward initial values in proportion to given decay parameter
 // Called with ac.Init.Decay by Layer during AlphaCycInit
-func (ac *ActParams) DecayState(nrn *Neuron, decay float32) {
+func (ac *ActParams) DecayState(nrn *Neuron, decay Float) {
 	if decay > 0 { // no-op for most, but not all..
 		nrn.Act -= decay * (nrn.Act - ac.Init.Act)
 		nrn.Ge -= decay * (nrn.Ge - ac.Init.Ge)
@@ -129,6 +138,7 @@ func (ac *ActParams) InitActs(nrn *Neuron) {
 	nrn.GknaFast = 0
 	nrn.GknaMed = 0
 	nrn.GknaSlow = 0
+	nrn.Gahp = 0
 	nrn.GiSelf = 0
 	nrn.GiSyn = 0
 	nrn.Inet = 0
@@ -140,6 +150,8 @@ func (ac *ActParams) InitActs(nrn *Neuron) {
 	nrn.ISI = -1
 	nrn.ISIAvg = -1
 	nrn.CtxtGe = 0
+	nrn.CtxtDif = 0
+	nrn.CtxtDLag = 0
 	nrn.ActG = 0
 	nrn.DALrn = 0
 	nrn.Shunt = 0
@@ -169,7 +181,13 @@ func (ac *ActParams) InitActQs(nrn *Neuron) {
 
 // GeFromRaw integrates Ge excitatory conductance from GeRaw value
 // (can add other terms to geRaw prior to calling this)
-func (ac *ActParams) GeFromRaw(nrn *Neuron, geRaw float32) {
+func (ac *ActParams) GeFromRaw(nrn *Neuron, geRaw Float) {
+	ac.GeFromRawRand(nrn, geRaw, nil)
+}
+
+// GeFromRawRand is GeFromRaw using given random number generator
+// for the noise, or the global generator if nil.
+func (ac *ActParams) GeFromRawRand(nrn *Neuron, geRaw Float, rnd randx.Rand) {
 	if !ac.Clamp.Hard && nrn.HasFlag(NeurHasExt) {
 		if ac.Clamp.Avg {
 			geRaw = ac.Clamp.AvgGe(nrn.Ext, geRaw)
@@ -177,11 +195,12 @@ func (ac *ActParams) GeFromRaw(nrn *Neuron, geRaw float32) {
 			geRaw += nrn.Ext * ac.Clamp.Gain
 		}
 	}
+	geRaw += nrn.Excit // learned intrinsic excitability, see LearnNeurParams.Excit
 
 	ac.Dt.GFromRaw(geRaw, &nrn.Ge)
 	// first place noise is required -- generate here!
 	if ac.Noise.Type != NoNoise && !ac.Noise.Fixed && ac.Noise.Dist != randx.Mean {
-		nrn.Noise = float32(ac.Noise.Gen())
+		nrn.Noise = ac.Noise.GenRand(rnd)
 	}
 	if ac.Noise.Type == GeNoise {
 		nrn.Ge += nrn.Noise
@@ -190,13 +209,13 @@ func (ac *ActParams) GeFromRaw(nrn *Neuron, geRaw float32) {
 
 // GiFromRaw integrates GiSyn inhibitory synaptic conductance from GiRaw value
 // (can add other terms to geRaw prior to calling this)
-func (ac *ActParams) GiFromRaw(nrn *Neuron, giRaw float32) {
+func (ac *ActParams) GiFromRaw(nrn *Neuron, giRaw Float) {
 	ac.Dt.GFromRaw(giRaw, &nrn.GiSyn)
-	nrn.GiSyn = math32.Max(nrn.GiSyn, 0) // negative inhib G doesn't make any sense
+	nrn.GiSyn = fmath.Max(nrn.GiSyn, 0) // negative inhib G doesn't make any sense
 }
 
 // InetFromG computes net current from conductances and Vm
-func (ac *ActParams) InetFromG(vm, ge, gi, gk float32) float32 {
+func (ac *ActParams) InetFromG(vm, ge, gi, gk Float) Float {
 	return ge*(ac.Erev.E-vm) + ac.Gbar.L*(ac.Erev.L-vm) + gi*(ac.Erev.I-vm) + gk*(ac.Erev.K-vm)
 }
 
@@ -218,13 +237,13 @@ func (ac *ActParams) VmFromG(nrn *Neuron) {
 
 // GeThrFromG computes the threshold for Ge based on all other conductances,
 // including Gk.  This is used for computing the adapted Act value.
-func (ac *ActParams) GeThrFromG(nrn *Neuron) float32 {
+func (ac *ActParams) GeThrFromG(nrn *Neuron) Float {
 	return ((ac.Gbar.I*nrn.Gi*ac.ErevSubThr.I + ac.Gbar.L*ac.ErevSubThr.L + ac.Gbar.K*nrn.Gk*ac.ErevSubThr.K) / ac.ThrSubErev.E)
 }
 
 // GeThrFromGnoK computes the threshold for Ge based on other conductances,
 // excluding Gk.  This is used for computing the non-adapted ActLrn value.
-func (ac *ActParams) GeThrFromGnoK(nrn *Neuron) float32 {
+func (ac *ActParams) GeThrFromGnoK(nrn *Neuron) Float {
 	return ((ac.Gbar.I*nrn.Gi*ac.ErevSubThr.I + ac.Gbar.L*ac.ErevSubThr.L) / ac.ThrSubErev.E)
 }
 
@@ -234,7 +253,7 @@ func (ac *ActParams) ActFromG(nrn *Neuron) {
 		ac.HardClamp(nrn)
 		return
 	}
-	var nwAct, nwActLrn float32
+	var nwAct, nwActLrn Float
 	if nrn.Act < ac.XX1.VmActThr && nrn.Vm <= ac.XX1.Thr {
 		// note: this is quite important -- if you directly use the gelin
 		// the whole time, then units are active right away -- need Vm dynamics to
@@ -260,9 +279,17 @@ func (ac *ActParams) ActFromG(nrn *Neuron) {
 	nwActLrn = nrn.ActLrn + ac.Dt.VmDt*(nwActLrn-nrn.ActLrn)
 	nrn.ActLrn = nwActLrn
 
-	if ac.KNa.On {
-		ac.KNa.GcFromRate(&nrn.GknaFast, &nrn.GknaMed, &nrn.GknaSlow, nrn.Act)
-		nrn.Gk = nrn.GknaFast + nrn.GknaMed + nrn.GknaSlow
+	if ac.KNa.On || ac.AHP.On {
+		var gk Float
+		if ac.KNa.On {
+			ac.KNa.GcFromRate(&nrn.GknaFast, &nrn.GknaMed, &nrn.GknaSlow, nrn.Act)
+			gk = nrn.GknaFast + nrn.GknaMed + nrn.GknaSlow
+		}
+		if ac.AHP.On {
+			ac.AHP.GFromAct(&nrn.Gahp, nrn.Act)
+			gk += ac.AHP.Gk(nrn.Gahp)
+		}
+		nrn.Gk = gk
 	}
 }
 
@@ -293,10 +320,10 @@ func (ac *ActParams) HardClamp(nrn *Neuron) {
 type OptThreshParams struct {
 
 	// don't send activation when act <= send -- greatly speeds processing
-	Send float32 `default:"0.1"`
+	Send Float `default:"0.1"`
 
 	// don't send activation changes until they exceed this threshold: only for when LeabraNetwork::send_delta is on!
-	Delta float32 `default:"0.005"`
+	Delta Float `default:"0.005"`
 }
 
 func (ot *OptThreshParams) Update() {
@@ -315,16 +342,16 @@ func (ot *OptThreshParams) Defaults() {
 type ActInitParams struct {
 
 	// proportion to decay activation state toward initial values at start of every trial
-	Decay float32 `default:"0,1" max:"1" min:"0"`
+	Decay Float `default:"0,1" max:"1" min:"0"`
 
 	// initial membrane potential -- see e_rev.l for the resting potential (typically .3) -- often works better to have a somewhat elevated initial membrane potential relative to that
-	Vm float32 `default:"0.4"`
+	Vm Float `default:"0.4"`
 
 	// initial activation value -- typically 0
-	Act float32 `default:"0"`
+	Act Float `default:"0"`
 
 	// baseline level of excitatory conductance (net input) -- Ge is initialized to this value, and it is added in as a constant background level of excitatory input -- captures all the other inputs not represented in the model, and intrinsic excitability, etc
-	Ge float32 `default:"0"`
+	Ge Float `default:"0"`
 }
 
 func (ai *ActInitParams) Update() {
@@ -344,25 +371,25 @@ func (ai *ActInitParams) Defaults() {
 type DtParams struct {
 
 	// overall rate constant for numerical integration, for all equations at the unit level -- all time constants are specified in millisecond units, with one cycle = 1 msec -- if you instead want to make one cycle = 2 msec, you can do this globally by setting this integ value to 2 (etc).  However, stability issues will likely arise if you go too high.  For improved numerical stability, you may even need to reduce this value to 0.5 or possibly even lower (typically however this is not necessary).  MUST also coordinate this with network.time_inc variable to ensure that global network.time reflects simulated time accurately
-	Integ float32 `default:"1,0.5" min:"0"`
+	Integ Float `default:"1,0.5" min:"0"`
 
 	// membrane potential and rate-code activation time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life) -- reflects the capacitance of the neuron in principle -- biological default for AdEx spiking model C = 281 pF = 2.81 normalized -- for rate-code activation, this also determines how fast to integrate computed activation values over time
-	VmTau float32 `default:"3.3" min:"1"`
+	VmTau Float `default:"3.3" min:"1"`
 
 	// time constant for integrating synaptic conductances, in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life) -- this is important for damping oscillations -- generally reflects time constants associated with synaptic channels which are not modeled in the most abstract rate code models (set to 1 for detailed spiking models with more realistic synaptic currents) -- larger values (e.g., 3) can be important for models with higher conductances that otherwise might be more prone to oscillation.
-	GTau float32 `default:"1.4,3,5" min:"1"`
+	GTau Float `default:"1.4,3,5" min:"1"`
 
 	// for integrating activation average (ActAvg), time constant in trials (roughly, how long it takes for value to change significantly) -- used mostly for visualization and tracking *hog* units
-	AvgTau float32 `default:"200"`
+	AvgTau Float `default:"200"`
 
 	// nominal rate = Integ / tau
-	VmDt float32 `display:"-" json:"-" xml:"-"`
+	VmDt Float `display:"-" json:"-" xml:"-"`
 
 	// rate = Integ / tau
-	GDt float32 `display:"-" json:"-" xml:"-"`
+	GDt Float `display:"-" json:"-" xml:"-"`
 
 	// rate = 1 / tau
-	AvgDt float32 `display:"-" json:"-" xml:"-"`
+	AvgDt Float `display:"-" json:"-" xml:"-"`
 }
 
 func (dp *DtParams) Update() {
@@ -379,7 +406,7 @@ func (dp *DtParams) Defaults() {
 	dp.Update()
 }
 
-func (dp *DtParams) GFromRaw(geRaw float32, ge *float32) {
+func (dp *DtParams) GFromRaw(geRaw Float, ge *Float) {
 	*ge += dp.GDt * (geRaw - *ge)
 }
 
@@ -424,6 +451,15 @@ type ActNoiseParams struct {
 func (an *ActNoiseParams) Update() {
 }
 
+// GenRand generates a noise value using given random number generator,
+// or the global generator if nil.
+func (an *ActNoiseParams) GenRand(rnd randx.Rand) Float {
+	if rnd == nil {
+		return Float(an.Gen())
+	}
+	return Float(an.Gen(rnd))
+}
+
 func (an *ActNoiseParams) Defaults() {
 	an.Fixed = true
 }
@@ -438,16 +474,16 @@ type ClampParams struct {
 	Hard bool `default:"true"`
 
 	// range of external input activation values allowed -- Max is .95 by default due to saturating nature of rate code activation function
-	Range minmax.F32
+	Range fmath.Range
 
 	// soft clamp gain factor (Ge += Gain * Ext)
-	Gain float32 `default:"0.02:0.5"`
+	Gain Float `default:"0.02:0.5"`
 
 	// compute soft clamp as the average of current and target netins, not the sum -- prevents some of the main effect problems associated with adding external inputs
 	Avg bool
 
 	// gain factor for averaging the Ge -- clamp value Ext contributes with AvgGain and current Ge as (1-AvgGain)
-	AvgGain float32 `default:"0.2"`
+	AvgGain Float `default:"0.2"`
 }
 
 func (cp *ClampParams) Update() {
@@ -475,7 +511,7 @@ func (cp *ClampParams) ShouldDisplay(field string) bool {
 }
 
 // AvgGe computes Avg-based Ge clamping value if using that option.
-func (cp *ClampParams) AvgGe(ext, ge float32) float32 {
+func (cp *ClampParams) AvgGe(ext, ge Float) Float {
 	return cp.AvgGain*cp.Gain*ext + (1-cp.AvgGain)*ge
 }
 
@@ -483,12 +519,22 @@ func (cp *ClampParams) AvgGe(ext, ge float32) float32 {
 //  WtInitParams
 
 // WtInitParams are weight initialization parameters -- basically the
-// random distribution parameters but also Symmetry flag
+// random distribution parameters but also Symmetry flag, and the
+// weight initialization Scheme (see WtInitSchemes).
 type WtInitParams struct {
 	randx.RandParams
 
 	// symmetrize the weight values with those in reciprocal pathway -- typically true for bidirectional excitatory connections
 	Sym bool
+
+	// name of the weight initialization scheme, in WtInitSchemes: Rand (default if empty), LogNormal, Sparse, Orthogonal, or any registered with RegisterWtInit
+	Scheme string
+
+	// for the Sparse scheme, the proportion of synapses with non-zero weights
+	SparseP Float `default:"0.5" min:"0" max:"1"`
+
+	// for the Sparse scheme, multiply SparseP by the synapse Scale, e.g., from the topographic weights of the pathway pattern (see Network.InitTopoScales)
+	SparseTopo bool
 }
 
 func (wp *WtInitParams) Defaults() {
@@ -496,6 +542,7 @@ func (wp *WtInitParams) Defaults() {
 	wp.Var = 0.25
 	wp.Dist = randx.Uniform
 	wp.Sym = true
+	wp.SparseP = 0.5
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -506,10 +553,10 @@ func (wp *WtInitParams) Defaults() {
 type WtScaleParams struct {
 
 	// absolute scaling, which is not subject to normalization: directly multiplies weight values
-	Abs float32 `default:"1" min:"0"`
+	Abs Float `default:"1" min:"0"`
 
 	// relative scaling that shifts balance between different pathways -- this is subject to normalization across all other pathways into unit
-	Rel float32 `min:"0"`
+	Rel Float `min:"0"`
 }
 
 func (ws *WtScaleParams) Defaults() {
@@ -526,26 +573,26 @@ func (ws *WtScaleParams) Update() {
 // to add to the average expected number of active connections to receive,
 // for purposes of computing scaling factors with partial connectivity
 // For 25% layer activity, binomial SEM = sqrt(p(1-p)) = .43, so 3x = 1.3 so 2 is a reasonable default.
-func (ws *WtScaleParams) SLayActScale(savg, snu, ncon float32) float32 {
-	ncon = math32.Max(ncon, 1) // path Avg can be < 1 in some cases
+func (ws *WtScaleParams) SLayActScale(savg, snu, ncon Float) Float {
+	ncon = fmath.Max(ncon, 1) // path Avg can be < 1 in some cases
 	semExtra := 2
-	slayActN := int(math32.Round(savg * snu)) // sending layer actual # active
+	slayActN := int(fmath.Round(savg * snu)) // sending layer actual # active
 	slayActN = max(slayActN, 1)
-	var sc float32
+	var sc Float
 	if ncon == snu {
-		sc = 1 / float32(slayActN)
+		sc = 1 / Float(slayActN)
 	} else {
-		maxActN := int(math32.Min(ncon, float32(slayActN))) // max number we could get
-		avgActN := int(math32.Round(savg * ncon))           // recv average actual # active if uniform
+		maxActN := int(fmath.Min(ncon, Float(slayActN))) // max number we could get
+		avgActN := int(fmath.Round(savg * ncon))         // recv average actual # active if uniform
 		avgActN = max(avgActN, 1)
 		expActN := avgActN + semExtra // expected
 		expActN = min(expActN, maxActN)
-		sc = 1 / float32(expActN)
+		sc = 1 / Float(expActN)
 	}
 	return sc
 }
 
 // FullScale returns full scaling factor, which is product of Abs * Rel * SLayActScale
-func (ws *WtScaleParams) FullScale(savg, snu, ncon float32) float32 {
+func (ws *WtScaleParams) FullScale(savg, snu, ncon Float) Float {
 	return ws.Abs * ws.Rel * ws.SLayActScale(savg, snu, ncon)
 }
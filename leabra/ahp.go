@@ -0,0 +1,118 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"github.com/emer/leabra/v2/fmath"
+)
+
+// AHPParams are the parameters for a slow after-hyperpolarization (sAHP)
+// potassium conductance, which builds up with the activity of the neuron
+// and decays slowly, over many trials, producing firing-rate adaptation
+// (accommodation) with repeated presentations of the same input, e.g.,
+// for repetition suppression in recognition memory and priming.
+// The Gahp neuron variable is the proportion of open channels, and
+// Gbar * Gahp is added to the Gk potassium conductance, along with any
+// KNa adaptation.  Unlike the rest of the activation state, Gahp is not
+// decayed by Init.Decay at the start of each trial, so adaptation carries
+// over from one trial to the next: instead, the ITI sets the number of
+// cycles of decay for an inter-trial interval, and Network.DecayAHP
+// can be called for longer delays.  It is reset by InitActs.
+type AHPParams struct {
+
+	// use the slow AHP adaptation conductance
+	On bool
+
+	// maximal conductance of the sAHP channels, added to Gk (which is multiplied by Gbar.K), with Gahp being the proportion of open channels
+	Gbar Float `default:"0.5" min:"0"`
+
+	// rate of opening of the sAHP channels per cycle as a function of the rate-code activation (or 1 for a spike in spiking neurons): Gahp += Rise * Act * (1 - Gahp)
+	Rise Float `default:"0.002" min:"0" max:"1"`
+
+	// time constant in cycles (msec) for the closing of the sAHP channels -- values of seconds produce adaptation that lasts across many trials
+	Tau Float `default:"2000" min:"1"`
+
+	// number of cycles of decay of Gahp at the start of each trial (in AlphaCycInit), for an inter-trial interval between presentations, in addition to the decay during the trial
+	ITI Float `default:"0" min:"0"`
+
+	// rate = 1 / tau
+	Dt Float `display:"-" json:"-" xml:"-"`
+}
+
+func (ap *AHPParams) Update() {
+	ap.Dt = 1 / ap.Tau
+}
+
+func (ap *AHPParams) Defaults() {
+	ap.On = false
+	ap.Gbar = 0.5
+	ap.Rise = 0.002
+	ap.Tau = 2000
+	ap.ITI = 0
+	ap.Update()
+}
+
+func (ap *AHPParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return ap.On
+	}
+}
+
+// GFromAct updates the proportion of open sAHP channels gahp
+// for one cycle with the given rate-code activation.
+func (ap *AHPParams) GFromAct(gahp *Float, act Float) {
+	*gahp += ap.Rise*act*(1-*gahp) - ap.Dt**gahp
+}
+
+// GFromSpike updates the proportion of open sAHP channels gahp
+// for one cycle with the given spike.
+func (ap *AHPParams) GFromSpike(gahp *Float, spike bool) {
+	if spike {
+		*gahp += ap.Rise * (1 - *gahp)
+	}
+	*gahp -= ap.Dt * *gahp
+}
+
+// Gk returns the sAHP contribution to the Gk conductance for given gahp.
+func (ap *AHPParams) Gk(gahp Float) Float {
+	return ap.Gbar * gahp
+}
+
+// Decay decays the proportion of open sAHP channels gahp for given
+// number of cycles with no activity.
+func (ap *AHPParams) Decay(gahp *Float, cycles Float) {
+	*gahp *= fmath.Exp(-cycles * ap.Dt)
+}
+
+// DecayAHP decays the slow AHP adaptation conductance Gahp of the
+// neurons for given number of cycles with no activity, if Act.AHP.On,
+// e.g., for a delay between blocks of trials.
+func (ly *Layer) DecayAHP(cycles Float) {
+	if !ly.Act.AHP.On || cycles <= 0 {
+		return
+	}
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		ly.Act.AHP.Decay(&nrn.Gahp, cycles)
+	}
+}
+
+// DecayAHP decays the slow AHP adaptation conductance Gahp of the
+// neurons in all the layers with Act.AHP.On for given number of cycles
+// with no activity, e.g., for a delay between study and test.
+func (nt *Network) DecayAHP(cycles Float) {
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		ly.DecayAHP(cycles)
+	}
+}
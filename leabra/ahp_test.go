@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestAHP(t *testing.T) {
+	net := NewNetwork("AHP")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	inpat := tensor.NewFloat32([]int{4, 4})
+	for i := range 4 {
+		inpat.SetFloat1D(i*5, 1)
+	}
+	// trial returns the mean hidden activation after one trial of the input
+	trial := func() float64 {
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt(inpat)
+		net.AlphaCycInit(true)
+		ctx.AlphaCycStart()
+		for range 100 {
+			net.Cycle(ctx)
+			ctx.CycleInc()
+		}
+		var sum float64
+		for ni := range hid.Neurons {
+			sum += float64(hid.Neurons[ni].Act)
+		}
+		return sum / float64(len(hid.Neurons))
+	}
+	acts := func(on bool) []float64 {
+		hid.Act.AHP.On = on
+		hid.Act.AHP.Rise = 0.01
+		net.UpdateParams()
+		net.InitWeights()
+		var as []float64
+		for range 5 {
+			as = append(as, trial())
+		}
+		return as
+	}
+	base := acts(false)
+	if math.Abs(base[4]-base[0]) > 1e-4 {
+		t.Errorf("activity changed across trials without AHP: %v", base)
+	}
+	adapt := acts(true)
+	if adapt[0] >= base[0] || adapt[4] > 0.8*adapt[0] {
+		t.Errorf("activity should adapt across trials with AHP: %v vs. %v", adapt, base)
+	}
+	if gahp := hid.UnitValue("Gahp", []int{0, 0}, 0); !(gahp > 0) {
+		t.Errorf("Gahp neuron var: %g", gahp)
+	}
+	net.DecayAHP(1e5)
+	if rec := trial(); math.Abs(rec-adapt[0]) > 0.01 {
+		t.Errorf("activity should recover after DecayAHP: %g vs. %g", rec, adapt[0])
+	}
+	net.InitActs()
+	if hid.Neurons[0].Gahp != 0 {
+		t.Errorf("InitActs should reset Gahp: %g", hid.Neurons[0].Gahp)
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+)
+
+// ApplyExtChecked applies external input pattern to the layer using ApplyExt,
+// after checking that the number of values in the pattern matches the
+// number of neurons in the layer, and that its shape matches the layer
+// shape if it has the same number of dimensions, returning an error if not.
+// Patterns with a different number of dimensions (e.g., 2D onto 4D) are
+// applied as in ApplyExt.
+func (ly *Layer) ApplyExtChecked(ext tensor.Tensor) error {
+	esz := ext.Shape().Sizes
+	if ext.Len() != len(ly.Neurons) || (len(esz) == ly.Shape.NumDims() && !slices.Equal(esz, ly.Shape.Sizes)) {
+		return fmt.Errorf("leabra.ApplyExt: layer %s has %d neurons (shape %v), but pattern has %d values (shape %v)", ly.Name, len(ly.Neurons), ly.Shape.Sizes, ext.Len(), esz)
+	}
+	ly.ApplyExt(ext)
+	return nil
+}
+
+// ApplyEnvInputs applies the env.State for each of the given layer names
+// to the corresponding layers, after first calling InitExt to clear any
+// existing inputs.  If no layer names are passed, all of the Input and
+// Target layers are used.  Layers for which the env returns a nil State
+// are skipped.  Returns an error for any layer name that is not found,
+// and for any mismatch between the shape of a State pattern and its
+// layer.  This replaces the standard per-layer loop in ApplyInputs.
+func (nt *Network) ApplyEnvInputs(ev env.Env, lays ...string) error {
+	if len(lays) == 0 {
+		lays = nt.LayersByType(InputLayer, TargetLayer)
+	}
+	nt.InitExt()
+	var errs []error
+	for _, lnm := range lays {
+		ly := nt.LayerByName(lnm)
+		if ly == nil {
+			errs = append(errs, fmt.Errorf("leabra.ApplyEnvInputs: layer %s not found", lnm))
+			continue
+		}
+		pats := ev.State(ly.Name)
+		if pats == nil {
+			continue
+		}
+		if err := ly.ApplyExtChecked(pats); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ApplyTableRow applies the patterns in given row of the table to the
+// layers of the network, after first calling InitExt to clear any
+// existing inputs.  Each tensor-valued column is applied to the layer with
+// the same name, or the layer given for the column name in the optional
+// colToLayer map.  Columns without a corresponding layer are skipped,
+// except for those explicitly named in colToLayer, which is an error.
+// Returns an error for any mismatch between the shape of a pattern and
+// its layer (see ApplyExtChecked).
+func (nt *Network) ApplyTableRow(dt *table.Table, row int, colToLayer map[string]string) error {
+	if err := dt.IsValidRow(row); err != nil {
+		return err
+	}
+	nt.InitExt()
+	var errs []error
+	for ci, cl := range dt.Columns {
+		if cl.NumDims() == 1 || cl.IsString() {
+			continue
+		}
+		cnm := dt.ColumnNames[ci]
+		lnm, mapped := colToLayer[cnm]
+		if !mapped {
+			lnm = cnm
+		}
+		ely, err := nt.EmerLayerByName(lnm)
+		if err != nil {
+			if mapped {
+				errs = append(errs, fmt.Errorf("leabra.ApplyTableRow: column %s: %w", cnm, err))
+			}
+			continue
+		}
+		if err := ely.(*Layer).ApplyExtChecked(dt.TensorIndex(ci, row)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
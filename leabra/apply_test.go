@@ -0,0 +1,68 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestApplyErrors(t *testing.T) {
+	net := NewNetwork("Apply")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	out := net.AddLayer2D("Output", 4, 1, TargetLayer)
+	net.ConnectLayers(in, out, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{2, 2})
+	dt.AddFloat32TensorColumn("Output", []int{2, 2})
+	dt.SetNumRows(1)
+	dt.Columns[1].SetFloat1D(0, 1)
+	ev := &env.FixedTable{Name: "Test"}
+	ev.Config(table.NewIndexView(dt))
+	ev.Init(0)
+	ev.Step()
+
+	err := net.ApplyEnvInputs(ev, "Input", "Inptu")
+	if err == nil || !strings.Contains(err.Error(), "layer Inptu not found") {
+		t.Errorf("ApplyEnvInputs unknown layer: got %v", err)
+	}
+	if in.Neurons[0].Ext != 1 {
+		t.Error("ApplyEnvInputs did not apply Input after unknown layer")
+	}
+	err = net.ApplyEnvInputs(ev)
+	if err == nil || !strings.Contains(err.Error(), "layer Output has 4 neurons (shape [4 1]), but pattern has 4 values (shape [2 2])") {
+		t.Errorf("ApplyEnvInputs shape mismatch: got %v", err)
+	}
+	if out.Neurons[0].Ext != 0 {
+		t.Error("ApplyEnvInputs applied mismatched Output pattern")
+	}
+
+	err = net.ApplyTableRow(dt, 0, nil)
+	if err == nil || !strings.Contains(err.Error(), "layer Output") {
+		t.Errorf("ApplyTableRow shape mismatch: got %v", err)
+	}
+	err = net.ApplyTableRow(dt, 0, map[string]string{"Output": "Outptu"})
+	if err == nil || !strings.Contains(err.Error(), "column Output") {
+		t.Errorf("ApplyTableRow unknown layer: got %v", err)
+	}
+	dt.AddFloat32TensorColumn("Big", []int{3, 3})
+	err = net.ApplyTableRow(dt, 0, map[string]string{"Output": "Input", "Big": "Output"})
+	if err == nil || !strings.Contains(err.Error(), "pattern has 9 values") {
+		t.Errorf("ApplyTableRow size mismatch: got %v", err)
+	}
+	if err := net.ApplyTableRow(dt, 1, nil); err == nil {
+		t.Error("ApplyTableRow: expected invalid row error")
+	}
+}
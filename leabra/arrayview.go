@@ -0,0 +1,110 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"unsafe"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+// ArrayView describes a contiguous array of values in Go memory, for
+// access without copying from other languages through the Python
+// bindings, e.g., as a NumPy array view of the layer unit values
+// (Layer.UnitValuesView, with one copy into the view buffer) or of the
+// synapse values (Path.SynValuesView, with no copy):
+//
+//	import ctypes, numpy as np
+//	def as_numpy(v):
+//	    buf = (ctypes.c_char * (v.Len * v.Size)).from_address(v.Addr)
+//	    return np.frombuffer(buf, dtype=v.DType).reshape(tuple(v.Shape))
+//
+// The NumPy array is only valid while the view and the network exist,
+// and for synapses, until the network is rebuilt.  Updating the view
+// again (e.g., after each trial) updates the values in place, at the
+// same address, so the NumPy array does not need to be re-created.
+type ArrayView struct {
+
+	// Addr is the memory address of the first value.
+	Addr uintptr
+
+	// Len is the number of values.
+	Len int
+
+	// Size is the number of bytes per value: 4 or 8.
+	Size int
+
+	// DType is the NumPy dtype of the values: float32 or float64.
+	DType string
+
+	// Shape is the shape of the values, e.g., the layer shape.
+	Shape []int
+
+	// buf is the buffer for values copied into the view.
+	buf []float32
+
+	// src is the source slice of the values, which keeps it alive.
+	src any
+}
+
+// set sets the view to the given values, with the given shape.
+func (av *ArrayView) set(addr uintptr, n, size int, shape []int) {
+	av.Addr, av.Len, av.Size = addr, n, size
+	av.DType = fmt.Sprintf("float%d", size*8)
+	av.Shape = append(av.Shape[:0], shape...)
+}
+
+// UnitValuesView sets the given view to the values of the given variable
+// for each unit in the layer, for the given data parallel index, with the
+// shape of the layer.  The values are copied into a float32 buffer owned
+// by the view, which is re-used if the same size.
+func (ly *Layer) UnitValuesView(av *ArrayView, varNm string, di int) error {
+	nn := len(ly.Neurons)
+	if nn == 0 {
+		return fmt.Errorf("leabra.UnitValuesView: layer %s has no units", ly.Name)
+	}
+	if len(av.buf) != nn {
+		av.buf = make([]float32, nn)
+	}
+	vals := av.buf
+	if err := ly.UnitValues(&vals, varNm, di); err != nil {
+		return err
+	}
+	av.src = nil
+	av.set(uintptr(unsafe.Pointer(&av.buf[0])), nn, 4, ly.Shape.Sizes)
+	return nil
+}
+
+// SynValuesView sets the given view to the values of the given variable
+// for each synapse in the pathway, in the natural (sending unit) order
+// of the synapses, as for SynValues, without copying: the view has the
+// actual synapse values, which are float64 in leabra64 builds, and
+// setting them sets the synapse values.  The STPu and STPx values, which
+// are stored per sending neuron, are copied into a float32 buffer.
+func (pt *Path) SynValuesView(av *ArrayView, varNm string) error {
+	vidx, err := pt.SynVarIndex(varNm)
+	if err != nil {
+		return err
+	}
+	ns := pt.Syns.Len()
+	if ns == 0 {
+		return fmt.Errorf("leabra.SynValuesView: pathway %s has no synapses", pt.Name)
+	}
+	vals := pt.Syns.Values(vidx)
+	if len(vals) != ns { // not stored per synapse (e.g., STPu): copy
+		if len(av.buf) != ns {
+			av.buf = make([]float32, ns)
+		}
+		pt.SynValues(&av.buf, varNm)
+		av.src = nil
+		av.set(uintptr(unsafe.Pointer(&av.buf[0])), ns, 4, []int{ns})
+		return nil
+	}
+	av.buf = nil
+	av.src = vals
+	av.set(uintptr(unsafe.Pointer(&vals[0])), len(vals), fmath.Size, []int{len(vals)})
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+func TestArrayView(t *testing.T) {
+	net := MakeTestNet(t)
+	hid := net.LayerByName("Hidden")
+	for i := range hid.Neurons {
+		hid.Neurons[i].Act = Float(i) * 0.25
+	}
+	var av ArrayView
+	if err := hid.UnitValuesView(&av, "Act", 0); err != nil {
+		t.Fatal(err)
+	}
+	addr := av.Addr
+	if av.Len != 4 || av.Size != 4 || av.DType != "float32" || !slices.Equal(av.Shape, []int{4, 1}) || !slices.Equal(av.buf, []float32{0, 0.25, 0.5, 0.75}) {
+		t.Errorf("unit view wrong: %+v", av)
+	}
+	hid.Neurons[0].Act = 1
+	hid.UnitValuesView(&av, "Act", 0)
+	if av.Addr != addr || av.buf[0] != 1 {
+		t.Errorf("unit view not updated in place")
+	}
+	if err := hid.UnitValuesView(&av, "Nope", 0); err == nil {
+		t.Error("no error for invalid variable")
+	}
+
+	pti, _ := hid.RecvPathBySendName("Input")
+	pt := pti.(*Path)
+	if err := pt.SynValuesView(&av, "Wt"); err != nil {
+		t.Fatal(err)
+	}
+	if av.Addr != uintptr(unsafe.Pointer(&pt.Syns.Wt[0])) || av.Len != 4 || av.Size != fmath.Size || av.DType != fmt.Sprintf("float%d", fmath.Bits) || av.buf != nil {
+		t.Errorf("synapse view is not the weights: %+v", av)
+	}
+}
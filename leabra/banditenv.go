@@ -0,0 +1,175 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+)
+
+// BanditParams are the parameters for an n-armed bandit task
+// (see BanditEnv), where each arm is rewarded with its own probability,
+// which can be stationary, drift over time, or be reversed at regular
+// intervals, as in probabilistic reversal learning.
+type BanditParams struct {
+
+	// NArms is the number of arms.
+	NArms int `default:"4" min:"1"`
+
+	// Probs are the initial reward probabilities of the arms.
+	// If empty, they are evenly spaced between 0 and 1 (exclusive),
+	// in a new random order for each run.
+	Probs []float32
+
+	// Drift is the standard deviation of the Gaussian random walk of
+	// the reward probability of each arm after each pull, clipped to
+	// the 0-1 range.  0 = stationary probabilities.
+	Drift float32 `min:"0"`
+
+	// ReverseInterval is the number of pulls after which the reward
+	// probabilities are reversed (see BanditEnv.Reverse), for
+	// probabilistic reversal learning.  0 = no reversals.
+	ReverseInterval int `min:"0"`
+}
+
+func (bp *BanditParams) Update() {
+}
+
+func (bp *BanditParams) Defaults() {
+	bp.NArms = 4
+}
+
+// ReversalDefaults sets the parameters for a standard probabilistic
+// reversal learning task: two arms rewarded with probabilities .8 and .2,
+// which are reversed every 50 pulls.
+func (bp *BanditParams) ReversalDefaults() {
+	bp.NArms = 2
+	bp.Probs = []float32{0.8, 0.2}
+	bp.Drift = 0
+	bp.ReverseInterval = 50
+}
+
+// BanditEnv is an n-armed bandit GymStepper, to be wrapped by GymEnv:
+// each episode is a single step, in which the action pulls one of the
+// arms, which is rewarded with the current probability for that arm.
+// The observation is a single constant unit.  The probabilities are
+// set by Config for each run, and then change according to the Drift
+// and ReverseInterval of the Params.
+type BanditEnv struct {
+
+	// Params are the parameters of the task.
+	Params BanditParams `display:"inline"`
+
+	// Probs are the current reward probabilities of the arms.
+	Probs []float32 `edit:"-"`
+
+	// Pulls is the number of pulls since Config.
+	Pulls int `edit:"-"`
+
+	// reward for the last pull
+	reward float32
+
+	// observation
+	obs tensor.Float32
+
+	// random number stream for the current episode
+	rnd randx.Rand
+}
+
+// Config sets the initial reward probabilities from the Params,
+// using the given random number stream to order the default
+// probabilities, which should be called at the start of each run.
+func (ev *BanditEnv) Config(rnd randx.Rand) {
+	bp := &ev.Params
+	if len(bp.Probs) > 0 {
+		bp.NArms = len(bp.Probs)
+		ev.Probs = slices.Clone(bp.Probs)
+	} else {
+		ev.Probs = make([]float32, bp.NArms)
+		for i, pi := range rnd.Perm(bp.NArms) {
+			ev.Probs[i] = float32(pi+1) / float32(bp.NArms+1)
+		}
+	}
+	ev.Pulls = 0
+}
+
+// Best returns the arm with the highest current reward probability.
+func (ev *BanditEnv) Best() int {
+	best := 0
+	for i, p := range ev.Probs {
+		if p > ev.Probs[best] {
+			best = i
+		}
+	}
+	return best
+}
+
+// Reverse reverses the reward probabilities by rank, so that the
+// best arm gets the probability of the worst arm and vice-versa,
+// the second best gets that of the second worst, and so on.
+func (ev *BanditEnv) Reverse() {
+	order := make([]int, len(ev.Probs))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		switch {
+		case ev.Probs[a] < ev.Probs[b]:
+			return -1
+		case ev.Probs[a] > ev.Probs[b]:
+			return 1
+		}
+		return 0
+	})
+	n := len(order)
+	for i := range n / 2 {
+		lo, hi := order[i], order[n-1-i]
+		ev.Probs[lo], ev.Probs[hi] = ev.Probs[hi], ev.Probs[lo]
+	}
+}
+
+func (ev *BanditEnv) Reset(rnd randx.Rand) {
+	ev.rnd = rnd
+	if len(ev.Probs) == 0 {
+		ev.Config(rnd)
+	}
+	ev.obs.SetShape([]int{1, 1})
+	ev.obs.Values[0] = 1
+	ev.reward = 0
+}
+
+func (ev *BanditEnv) Step(action int) bool {
+	ev.reward = 0
+	if randx.BoolP32(ev.Probs[action], ev.rnd) {
+		ev.reward = 1
+	}
+	ev.Pulls++
+	if bp := &ev.Params; bp.Drift > 0 {
+		for i, p := range ev.Probs {
+			ev.Probs[i] = min(max(p+bp.Drift*float32(ev.rnd.NormFloat64()), 0), 1)
+		}
+	}
+	if ri := ev.Params.ReverseInterval; ri > 0 && ev.Pulls%ri == 0 {
+		ev.Reverse()
+	}
+	return true
+}
+
+func (ev *BanditEnv) Observation() tensor.Tensor { return &ev.obs }
+
+func (ev *BanditEnv) Reward() float32 { return ev.reward }
+
+func (ev *BanditEnv) NumActions() int { return len(ev.Probs) }
+
+func (ev *BanditEnv) EnvDesc() string {
+	bp := &ev.Params
+	return fmt.Sprintf("Bandit: NArms: %d\tProbs: %v\tDrift: %g\tReverseInterval: %d", bp.NArms, bp.Probs, bp.Drift, bp.ReverseInterval)
+}
+
+// Compile-time check that implements GymStepper interface
+var _ GymStepper = (*BanditEnv)(nil)
@@ -0,0 +1,48 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+)
+
+func TestBanditEnv(t *testing.T) {
+	bd := &BanditEnv{}
+	bd.Params.Defaults()
+	rnd := randx.NewSysRand(1)
+	bd.Config(rnd)
+	if len(bd.Probs) != 4 || bd.Probs[bd.Best()] != 0.8 {
+		t.Fatalf("default probs: %v", bd.Probs)
+	}
+	bd.Params.ReversalDefaults()
+	bd.Params.ReverseInterval = 10
+	bd.Config(rnd)
+	ev := &GymEnv{Name: "Train", Gym: bd}
+	ev.Init(0)
+	for i := range 25 {
+		if best := bd.Best(); (i/10)%2 != best {
+			t.Errorf("pull %d: best arm %d with reversals", i, best)
+		}
+		ev.Step()
+		ev.TakeAction(0)
+	}
+	if bd.Pulls != 25 || bd.Probs[0] != 0.8 || bd.Probs[1] != 0.2 {
+		t.Errorf("after 2 reversals: pulls %d probs %v", bd.Pulls, bd.Probs)
+	}
+
+	bd.Params = BanditParams{Probs: []float32{0.5, 0.5}, Drift: 0.1}
+	bd.Config(rnd)
+	for range 20 {
+		ev.Step()
+		ev.TakeAction(1)
+	}
+	for _, p := range bd.Probs {
+		if p == 0.5 || p < 0 || p > 1 {
+			t.Errorf("drifting probs: %v", bd.Probs)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// BreakAggs are the ways of aggregating a variable across the units
+// or synapses of a layer or pathway, for a Breakpoint.
+type BreakAggs int32 //enums:enum
+
+const (
+	// BreakAvg is the average.
+	BreakAvg BreakAggs = iota
+
+	// BreakMax is the maximum.
+	BreakMax
+
+	// BreakMin is the minimum.
+	BreakMin
+
+	// BreakSum is the sum.
+	BreakSum
+)
+
+// BreakOps are the comparison operators of a Breakpoint.
+type BreakOps int32 //enums:enum
+
+const (
+	// BreakGreater is true if the value is > the threshold Value.
+	BreakGreater BreakOps = iota
+
+	// BreakGreaterEq is true if the value is >= the threshold Value.
+	BreakGreaterEq
+
+	// BreakLess is true if the value is < the threshold Value.
+	BreakLess
+
+	// BreakLessEq is true if the value is <= the threshold Value.
+	BreakLessEq
+
+	// BreakNaN is true if any value is NaN or infinite,
+	// regardless of the aggregation and threshold.
+	BreakNaN
+)
+
+// breakOpStrings are the spec strings of the BreakOps, in order.
+var breakOpStrings = []string{">", ">=", "<", "<=", "NaN"}
+
+// Breakpoint is a condition on the state of the network, for stopping
+// the training at a given grain with Stepper.AddBreakpoint, e.g., when
+// any weight becomes NaN, or the average activity of a layer exceeds
+// a threshold.  It is specified as a string (see ParseBreakpoint):
+//
+//	<Layer or Path or *>.<Var>[.<Agg>] <Op> [<Value>]
+//
+// where Var is a neuron variable (NeuronVars) of the units of a layer,
+// or a synapse variable (SynapseVars) of the synapses of a pathway
+// (or of all the Recv pathways of a layer), * is all layers, Agg is
+// Avg (default), Max, Min or Sum, and Op is >, >=, <, <= or NaN,
+// e.g., "Hidden.Act.Avg > 0.9", "DA.Act.Min < -0.5", "*.Wt NaN".
+type Breakpoint struct {
+
+	// Name is the name of the layer or pathway, or * for all layers.
+	Name string
+
+	// Var is the neuron or synapse variable.
+	Var string
+
+	// Agg is the aggregation of the variable across units or synapses.
+	Agg BreakAggs
+
+	// Op is the comparison operator.
+	Op BreakOps
+
+	// Value is the threshold for the comparison.
+	Value float32
+
+	// Last is the last aggregated value, for reporting.
+	Last float32 `edit:"-"`
+
+	// layers are the layers for neuron variables.
+	layers []*Layer
+
+	// paths are the pathways for synapse variables.
+	paths []*Path
+
+	// vidx is the index of the variable.
+	vidx int
+}
+
+// ParseBreakpoint parses the given breakpoint spec string
+// (see Breakpoint), returning an error if it is invalid.
+func ParseBreakpoint(spec string) (*Breakpoint, error) {
+	flds := strings.Fields(spec)
+	if len(flds) < 2 || len(flds) > 3 {
+		return nil, fmt.Errorf("leabra.ParseBreakpoint: %q is not <Layer>.<Var>[.<Agg>] <Op> [<Value>]", spec)
+	}
+	bp := &Breakpoint{}
+	nms := strings.Split(flds[0], ".")
+	if len(nms) < 2 || len(nms) > 3 || nms[0] == "" || nms[1] == "" {
+		return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: %q is not <Layer>.<Var>[.<Agg>]", spec, flds[0])
+	}
+	bp.Name, bp.Var = nms[0], nms[1]
+	if len(nms) == 3 {
+		if err := bp.Agg.SetString("Break" + nms[2]); err != nil {
+			return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: invalid aggregation %q: must be Avg, Max, Min or Sum", spec, nms[2])
+		}
+	}
+	op := -1
+	for i, os := range breakOpStrings {
+		if flds[1] == os {
+			op = i
+		}
+	}
+	if op < 0 {
+		return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: invalid operator %q: must be one of %v", spec, flds[1], breakOpStrings)
+	}
+	bp.Op = BreakOps(op)
+	if bp.Op == BreakNaN {
+		if len(flds) != 2 {
+			return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: NaN does not take a value", spec)
+		}
+		return bp, nil
+	}
+	if len(flds) != 3 {
+		return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: missing value", spec)
+	}
+	v, err := strconv.ParseFloat(flds[2], 32)
+	if err != nil {
+		return nil, fmt.Errorf("leabra.ParseBreakpoint: %q: invalid value: %w", spec, err)
+	}
+	bp.Value = float32(v)
+	return bp, nil
+}
+
+// String returns the spec string of the breakpoint.
+func (bp *Breakpoint) String() string {
+	s := fmt.Sprintf("%s.%s.%s %s", bp.Name, bp.Var, strings.TrimPrefix(bp.Agg.String(), "Break"), breakOpStrings[bp.Op])
+	if bp.Op != BreakNaN {
+		s += fmt.Sprintf(" %g", bp.Value)
+	}
+	return s
+}
+
+// Config looks up the layers or pathways and the variable of the
+// breakpoint in the given network, returning an error if not found.
+func (bp *Breakpoint) Config(net *Network) error {
+	bp.layers, bp.paths = nil, nil
+	if bp.Name == "*" {
+		for _, ly := range net.Layers {
+			if !ly.Off {
+				bp.layers = append(bp.layers, ly)
+			}
+		}
+	} else if ly := net.LayerByName(bp.Name); ly != nil {
+		bp.layers = []*Layer{ly}
+	} else {
+		for _, ly := range net.Layers {
+			for _, pt := range ly.RecvPaths {
+				if pt.Name == bp.Name {
+					bp.paths = []*Path{pt}
+				}
+			}
+		}
+		if len(bp.paths) == 0 {
+			return fmt.Errorf("leabra.Breakpoint %q: layer or pathway %q not found", bp.String(), bp.Name)
+		}
+	}
+	if vi, err := NeuronVarIndexByName(bp.Var); err == nil && len(bp.layers) > 0 {
+		bp.vidx = vi
+		return nil
+	}
+	vi, err := SynapseVarByName(bp.Var)
+	if err != nil {
+		return fmt.Errorf("leabra.Breakpoint %q: %q is not a neuron or synapse variable", bp.String(), bp.Var)
+	}
+	bp.vidx = vi
+	if len(bp.layers) > 0 {
+		for _, ly := range bp.layers {
+			for _, pt := range ly.RecvPaths {
+				if !pt.Off {
+					bp.paths = append(bp.paths, pt)
+				}
+			}
+		}
+		bp.layers = nil
+	}
+	return nil
+}
+
+// Eval returns true if the breakpoint condition is true for the current
+// state of the network, after Config, recording the aggregated value in Last.
+func (bp *Breakpoint) Eval() bool {
+	sum, mx, mn, n := 0.0, math.Inf(-1), math.Inf(1), 0
+	nan := false
+	add := func(v float32) {
+		fv := float64(v)
+		if !IsFinite(fv) {
+			nan = true
+			return
+		}
+		sum += fv
+		mx = max(mx, fv)
+		mn = min(mn, fv)
+		n++
+	}
+	for _, ly := range bp.layers {
+		for ni := range ly.Neurons {
+			add(ly.UnitValue1D(bp.vidx, ni, 0))
+		}
+	}
+	for _, pt := range bp.paths {
+		for si := range pt.Syns.Len() {
+			add(pt.SynValue1D(bp.vidx, si))
+		}
+	}
+	if bp.Op == BreakNaN {
+		bp.Last = 0
+		if nan {
+			bp.Last = 1
+		}
+		return nan
+	}
+	if n == 0 {
+		bp.Last = float32(math.NaN())
+		return false
+	}
+	var v float64
+	switch bp.Agg {
+	case BreakAvg:
+		v = sum / float64(n)
+	case BreakMax:
+		v = mx
+	case BreakMin:
+		v = mn
+	case BreakSum:
+		v = sum
+	}
+	bp.Last = float32(v)
+	switch bp.Op {
+	case BreakGreater:
+		return bp.Last > bp.Value
+	case BreakGreaterEq:
+		return bp.Last >= bp.Value
+	case BreakLess:
+		return bp.Last < bp.Value
+	case BreakLessEq:
+		return bp.Last <= bp.Value
+	}
+	return false
+}
+
+// AddBreakpoint adds a stop condition on the state of the given network
+// with the given breakpoint spec (see Breakpoint), checked at the end of
+// each grain, named by the spec.  Returns the breakpoint, with the Last
+// value, or an error if the spec is invalid for the network.
+func (st *Stepper) AddBreakpoint(net *Network, spec string, grain StepGrains) (*Breakpoint, error) {
+	bp, err := ParseBreakpoint(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := bp.Config(net); err != nil {
+		return nil, err
+	}
+	st.AddStopCond(spec, grain, bp.Eval)
+	return bp, nil
+}
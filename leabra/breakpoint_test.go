@@ -0,0 +1,72 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+func TestBreakpoint(t *testing.T) {
+	for _, spec := range []string{"Hidden.Act", "Hidden > 1", "Hidden.Act.Med > 1", "Hidden.Act >> 1", "Hidden.Act NaN 1", "Hidden.Act > x"} {
+		if _, err := ParseBreakpoint(spec); err == nil {
+			t.Errorf("ParseBreakpoint(%q): expected error", spec)
+		}
+	}
+	bp, err := ParseBreakpoint("Hidden.Act.Max >= 0.5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bp.String() != "Hidden.Act.Max >= 0.5" {
+		t.Errorf("String: got %q", bp.String())
+	}
+
+	net := MakeTestNet(t)
+	for _, spec := range []string{"Nope.Act > 0", "Hidden.Nope > 0", "HiddenToOutput.Act > 0"} {
+		bp, _ := ParseBreakpoint(spec)
+		if err := bp.Config(net); err == nil {
+			t.Errorf("Config(%q): expected error", spec)
+		}
+	}
+	hid := net.LayerByName("Hidden")
+	ls := looper.NewStacks()
+	ls.AddStack(etime.Train).AddTime(etime.Epoch, 2).AddTime(etime.Trial, 4).AddTime(etime.Cycle, 10)
+	cyc := ls.Loop(etime.Train, etime.Cycle)
+	trl := ls.Loop(etime.Train, etime.Trial)
+	cyc.OnStart.Add("Act", func() {
+		for i := range hid.Neurons {
+			hid.Neurons[i].Act = Float(trl.Counter.Cur) * 0.1
+		}
+	})
+	ls.Init()
+	st := NewStepper(ls, NewContext(), 8)
+	avg, err := st.AddBreakpoint(net, "Hidden.Act > 0.25", StepTrial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	st.Run()
+	if st.StoppedBy != "Hidden.Act > 0.25" || trl.Counter.Cur != 0 || math32.Abs(avg.Last-0.3) > 1.0e-6 {
+		t.Errorf("Act.Avg: got StoppedBy %q trial %d Last %g", st.StoppedBy, trl.Counter.Cur, avg.Last)
+	}
+	st.RemoveStopCond("Hidden.Act > 0.25")
+
+	nan, err := st.AddBreakpoint(net, "*.Wt NaN", StepCycle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cyc.OnStart.Add("NaN", func() {
+		if trl.Counter.Cur == 2 && cyc.Counter.Cur == 4 {
+			pt := net.LayerByName("Output").RecvPaths[0]
+			pt.Syns.Wt[0] = Float(math32.NaN())
+		}
+	})
+	st.Run()
+	if st.StoppedBy != "*.Wt NaN" || nan.Last != 1 || trl.Counter.Cur != 2 || cyc.Counter.Cur != 5 {
+		t.Errorf("Wt NaN: got StoppedBy %q Last %g trial %d cycle %d", st.StoppedBy, nan.Last, trl.Counter.Cur, cyc.Counter.Cur)
+	}
+}
@@ -0,0 +1,523 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"archive/zip"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"time"
+	"unsafe"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/enums"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// Checkpoint has the state of a simulation run, other than the network
+// weights, state and log tables, that is needed to resume the run after an
+// interruption.  It is saved as checkpoint.json in the checkpoint archive
+// written by SaveCheckpoint, along with the weights, the full state of the
+// neurons, pools and synapses (see checkpointNetState), and the logs.
+type Checkpoint struct {
+
+	// time when the checkpoint was saved
+	Time time.Time
+
+	// looper stack mode that was running when saved, e.g., Train
+	Mode string
+
+	// looper counter values for each time scale in the Mode stack,
+	// at the point where the run is to be resumed
+	Counters map[string]int
+
+	// state of each environment, by env name: the values of all
+	// env.Counter fields, the Order permutation, if present,
+	// and the state of all randx.SysRand random number streams
+	Envs map[string]map[string]json.RawMessage
+
+	// estats Ints values
+	Ints map[string]int
+
+	// estats Floats values
+	Floats map[string]float64
+
+	// estats Strings values
+	Strings map[string]string
+}
+
+// checkpoint archive file names
+const (
+	checkpointStateFile   = "checkpoint.json"
+	checkpointWeightsFile = "weights.wts"
+	checkpointNetFile     = "netstate.gob"
+	checkpointLogsDir     = "logs/"
+)
+
+// SaveCheckpoint saves a checkpoint archive (zip format) to filename,
+// containing the network weights and full neuron, pool and synapse state
+// (including running averages and momentum, which are not in the weights),
+// the looper counters of the current
+// looper mode stack, the env counters, the stats values, and all log tables.
+// It should be called from the OnEnd functions of the given time scale
+// (e.g., Epoch), as the last such function: the counters are saved so that
+// the run resumes at the start of the next iteration at that time scale.
+// The state of the random number streams of the network, its layers and
+// the envs is saved (see randState) without changing them, so saving does
+// not affect the run, and the resumed run is identical to the uninterrupted
+// one.  The global math/rand stream can not be saved, so it must not be used
+// by anything that needs to be resumed exactly: e.g., env.FixedTable uses it
+// for its permuted Order (unless Sequential), so use ShardEnv instead.
+// Any of ls, envs, lg, st can be nil to skip that part of the state.
+func SaveCheckpoint(filename string, net *Network, ls *looper.Stacks, tm enums.Enum, envs env.Envs, lg *elog.Logs, st *estats.Stats) error {
+	cp := &Checkpoint{Time: time.Now()}
+	if ls != nil {
+		cp.Mode = ls.Mode.String()
+		cp.Counters = checkpointCounters(ls, tm)
+	}
+	if envs != nil {
+		cp.Envs = make(map[string]map[string]json.RawMessage, len(envs))
+		for nm, ev := range envs {
+			es, err := checkpointEnvState(ev)
+			if err != nil {
+				return err
+			}
+			cp.Envs[nm] = es
+		}
+	}
+	if st != nil {
+		cp.Ints = st.Ints
+		cp.Floats = st.Floats
+		cp.Strings = st.Strings
+	}
+
+	fp, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	zw := zip.NewWriter(fp)
+
+	w, err := zw.Create(checkpointStateFile)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(cp); err != nil {
+		return err
+	}
+
+	w, err = zw.Create(checkpointWeightsFile)
+	if err != nil {
+		return err
+	}
+	if err := net.WriteWeightsJSON(w); err != nil {
+		return err
+	}
+
+	w, err = zw.Create(checkpointNetFile)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(w).Encode(newCheckpointNetState(net)); err != nil {
+		return err
+	}
+
+	if lg != nil {
+		for sk, lt := range lg.Tables {
+			if lt.Table == nil {
+				continue
+			}
+			w, err = zw.Create(checkpointLogsDir + string(sk) + ".tsv")
+			if err != nil {
+				return err
+			}
+			// no headers, so that reading uses the existing log columns
+			if err := lt.Table.WriteCSV(w, table.Tab, table.NoHeaders); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// LoadCheckpoint loads a checkpoint archive saved by SaveCheckpoint
+// from filename, restoring the network weights, the looper counters,
+// the network state, the env counters, the stats values, all log tables,
+// and the state of the random number streams.
+// It should be called after any initialization of the run has been done
+// (e.g., in an OnStart function added after NewRun at the Run level),
+// so that initialization does not overwrite the restored state.
+// Any of ls, envs, lg, st can be nil to skip that part of the state.
+func LoadCheckpoint(filename string, net *Network, ls *looper.Stacks, envs env.Envs, lg *elog.Logs, st *estats.Stats) (*Checkpoint, error) {
+	zr, err := zip.OpenReader(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	cp := &Checkpoint{}
+	if err := checkpointReadFile(&zr.Reader, checkpointStateFile, func(r io.Reader) error {
+		return json.NewDecoder(r).Decode(cp)
+	}); err != nil {
+		return nil, err
+	}
+	if err := checkpointReadFile(&zr.Reader, checkpointWeightsFile, net.ReadWeightsJSON); err != nil {
+		return nil, err
+	}
+	if err := checkpointReadFile(&zr.Reader, checkpointNetFile, func(r io.Reader) error {
+		ns := &checkpointNetState{}
+		if err := gob.NewDecoder(r).Decode(ns); err != nil {
+			return err
+		}
+		return ns.restore(net)
+	}); err != nil {
+		return nil, err
+	}
+
+	if ls != nil && cp.Mode != "" {
+		for md, stk := range ls.Stacks {
+			if md.String() != cp.Mode {
+				continue
+			}
+			for tm, lp := range stk.Loops {
+				if ctr, ok := cp.Counters[tm.String()]; ok {
+					lp.Counter.Cur = ctr
+				}
+			}
+		}
+	}
+	if envs != nil {
+		for nm, es := range cp.Envs {
+			ev, ok := envs[nm]
+			if !ok {
+				continue
+			}
+			if err := checkpointSetEnvState(ev, es); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if st != nil {
+		for k, v := range cp.Ints {
+			st.SetInt(k, v)
+		}
+		for k, v := range cp.Floats {
+			st.SetFloat(k, v)
+		}
+		for k, v := range cp.Strings {
+			st.SetString(k, v)
+		}
+	}
+	if lg != nil {
+		for sk, lt := range lg.Tables {
+			if lt.Table == nil {
+				continue
+			}
+			lt.Table.SetNumRows(0)
+			fn := checkpointLogsDir + string(sk) + ".tsv"
+			err := checkpointReadFile(&zr.Reader, fn, func(r io.Reader) error {
+				return lt.Table.ReadCSV(r, table.Tab)
+			})
+			if err != nil {
+				return nil, err
+			}
+			lt.ResetIndexViews()
+		}
+	}
+	return cp, nil
+}
+
+// checkpointCounters returns the looper counters of the current mode
+// stack, advanced to the start of the next iteration of given time scale.
+func checkpointCounters(ls *looper.Stacks, tm enums.Enum) map[string]int {
+	stk := ls.Stacks[ls.Mode]
+	ctrs := make(map[string]int, len(stk.Order))
+	below := false
+	for _, t := range stk.Order {
+		ctr := &stk.Loops[t].Counter
+		switch {
+		case below:
+			ctrs[t.String()] = 0
+		case t == tm:
+			ctrs[t.String()] = ctr.Cur + ctr.Inc
+			below = true
+		default:
+			ctrs[t.String()] = ctr.Cur
+		}
+	}
+	return ctrs
+}
+
+// randState is the state of a random number stream created with
+// randx.SysRand.NewRand, i.e., rand.New(rand.NewSource(seed)), which is
+// saved in the checkpoint so that a resumed run continues the stream from
+// the same point.  math/rand does not provide any way to get or set the
+// state of its source, so it is accessed directly as an rngSource,
+// which is fixed as part of the Go 1 compatibility of math/rand.
+type randState struct {
+	Tap  int
+	Feed int
+	Vec  []int64
+}
+
+// rngSource has the same layout as the math/rand rngSource.
+type rngSource struct {
+	tap  int
+	feed int
+	vec  [607]int64
+}
+
+// rngSourceOf returns the rngSource of r, or nil if r is the global
+// stream or does not have an rngSource.
+func rngSourceOf(r *randx.SysRand) *rngSource {
+	if r.Rand == nil {
+		return nil
+	}
+	src := reflect.ValueOf(r.Rand).Elem().FieldByName("src")
+	if !src.IsValid() || src.IsNil() {
+		return nil
+	}
+	sp := src.Elem()
+	if sp.Type().String() != "*rand.rngSource" || sp.Type().Elem().Size() != unsafe.Sizeof(rngSource{}) {
+		return nil
+	}
+	return (*rngSource)(sp.UnsafePointer())
+}
+
+// newRandState returns the state of r, or nil if it can not be saved
+// (e.g., it is the global stream).
+func newRandState(r *randx.SysRand) *randState {
+	rs := rngSourceOf(r)
+	if rs == nil {
+		return nil
+	}
+	return &randState{Tap: rs.tap, Feed: rs.feed, Vec: slices.Clone(rs.vec[:])}
+}
+
+// restore sets r to a new stream with the state, which is a no-op
+// if the state was not saved (nil).
+func (st *randState) restore(r *randx.SysRand) error {
+	if st == nil {
+		return nil
+	}
+	r.NewRand(0)
+	rs := rngSourceOf(r)
+	if rs == nil || len(st.Vec) != len(rs.vec) {
+		return errors.New("checkpoint: can not restore the state of a random number stream")
+	}
+	rs.tap, rs.feed = st.Tap, st.Feed
+	copy(rs.vec[:], st.Vec)
+	return nil
+}
+
+// checkpointNetState is the full state of the neurons, pools and synapses
+// of a network, which is saved in the checkpoint in addition to the weights,
+// so that the running averages, momentum and other learning state that
+// is not in the weights (and the weights at full precision) are restored.
+type checkpointNetState struct {
+
+	// state of the network Rand stream
+	Rand *randState
+
+	// BurstSeed of the network
+	BurstSeed int64
+
+	// state of each layer, in order
+	Layers []checkpointLayerState
+}
+
+// checkpointLayerState is the state of one layer.
+type checkpointLayerState struct {
+	Name    string
+	Neurons []Neuron
+	Pools   []Pool
+	CosDiff CosDiffStats
+
+	// state of the Rand and GateRands streams
+	Rand      *randState
+	GateRands []*randState
+
+	// synapses, GeRaw and short-term plasticity state of each
+	// receiving pathway, in order
+	Syns    []Synapses
+	GeRaw   [][]Float
+	STPu    [][]Float
+	STPx    [][]Float
+	STPSent [][]Float
+}
+
+// newCheckpointNetState returns the current state of the network.
+func newCheckpointNetState(net *Network) *checkpointNetState {
+	ns := &checkpointNetState{Rand: newRandState(&net.Rand), BurstSeed: net.BurstSeed}
+	ns.Layers = make([]checkpointLayerState, len(net.Layers))
+	for li, ly := range net.Layers {
+		ls := &ns.Layers[li]
+		ls.Name = ly.Name
+		ls.Neurons = ly.Neurons
+		ls.Pools = ly.Pools
+		ls.CosDiff = ly.CosDiff
+		ls.Rand = newRandState(&ly.Rand)
+		for gi := range ly.GateRands {
+			ls.GateRands = append(ls.GateRands, newRandState(&ly.GateRands[gi]))
+		}
+		for _, pt := range ly.RecvPaths {
+			ls.Syns = append(ls.Syns, pt.Syns)
+			ls.GeRaw = append(ls.GeRaw, pt.GeRaw)
+			ls.STPu = append(ls.STPu, pt.STPu)
+			ls.STPx = append(ls.STPx, pt.STPx)
+			ls.STPSent = append(ls.STPSent, pt.STPSent)
+		}
+	}
+	return ns
+}
+
+// restore restores the state to the network, which must have the same
+// structure as the one it was saved from.
+func (ns *checkpointNetState) restore(net *Network) error {
+	if len(ns.Layers) != len(net.Layers) {
+		return fmt.Errorf("checkpoint: number of layers in state %d != network %d", len(ns.Layers), len(net.Layers))
+	}
+	for li, ly := range net.Layers {
+		ls := &ns.Layers[li]
+		if ls.Name != ly.Name || len(ls.Neurons) != len(ly.Neurons) || len(ls.Pools) != len(ly.Pools) || len(ls.Syns) != len(ly.RecvPaths) || len(ls.GateRands) != len(ly.GateRands) {
+			return fmt.Errorf("checkpoint: state of layer %s does not match the network", ly.Name)
+		}
+		for pi, pt := range ly.RecvPaths {
+			if ls.Syns[pi].Len() != pt.Syns.Len() || len(ls.GeRaw[pi]) != len(pt.GeRaw) {
+				return fmt.Errorf("checkpoint: state of pathway %s does not match the network", pt.Name)
+			}
+		}
+	}
+	if err := ns.Rand.restore(&net.Rand); err != nil {
+		return err
+	}
+	net.BurstSeed = ns.BurstSeed
+	for li, ly := range net.Layers {
+		ls := &ns.Layers[li]
+		copy(ly.Neurons, ls.Neurons)
+		copy(ly.Pools, ls.Pools)
+		ly.CosDiff = ls.CosDiff
+		if err := ls.Rand.restore(&ly.Rand); err != nil {
+			return err
+		}
+		for gi, gs := range ls.GateRands {
+			if err := gs.restore(&ly.GateRands[gi]); err != nil {
+				return err
+			}
+		}
+		for pi, pt := range ly.RecvPaths {
+			svs := ls.Syns[pi].vars()
+			for vi, vp := range pt.Syns.vars() {
+				if vp != nil {
+					*vp = slices.Clone(*svs[vi]) // EWC vars may not be allocated
+				}
+			}
+			copy(pt.GeRaw, ls.GeRaw[pi])
+			pt.STPu = slices.Clone(ls.STPu[pi])
+			pt.STPx = slices.Clone(ls.STPx[pi])
+			pt.STPSent = slices.Clone(ls.STPSent[pi])
+		}
+	}
+	return nil
+}
+
+// checkpointReadFile calls fun on the contents of named file in the archive.
+func checkpointReadFile(zr *zip.Reader, name string, fun func(r io.Reader) error) error {
+	f, err := zr.Open(name)
+	if err != nil {
+		return fmt.Errorf("checkpoint: %w", err)
+	}
+	defer f.Close()
+	return fun(f)
+}
+
+// checkpointEnvFields returns the checkpointed fields of env,
+// which are all the env.Counter and randx.SysRand fields,
+// and the Order permutation.
+func checkpointEnvFields(ev env.Env) map[string]reflect.Value {
+	fv := reflect.ValueOf(ev)
+	if fv.Kind() != reflect.Pointer || fv.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	sv := fv.Elem()
+	st := sv.Type()
+	ctrType := reflect.TypeOf(env.Counter{})
+	randType := reflect.TypeOf(randx.SysRand{})
+	flds := make(map[string]reflect.Value)
+	for i := 0; i < st.NumField(); i++ {
+		sf := st.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if sf.Type == ctrType || sf.Type == randType || (sf.Name == "Order" && sf.Type == reflect.TypeOf([]int{})) {
+			flds[sf.Name] = sv.Field(i)
+		}
+	}
+	return flds
+}
+
+// checkpointEnvState returns the checkpointed state of env.
+func checkpointEnvState(ev env.Env) (map[string]json.RawMessage, error) {
+	es := make(map[string]json.RawMessage)
+	for nm, fv := range checkpointEnvFields(ev) {
+		v := fv.Interface()
+		if r, ok := fv.Addr().Interface().(*randx.SysRand); ok {
+			rs := newRandState(r)
+			if rs == nil {
+				continue
+			}
+			v = rs
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		es[nm] = b
+	}
+	return es, nil
+}
+
+// checkpointSetEnvState restores the checkpointed state of env.
+func checkpointSetEnvState(ev env.Env, es map[string]json.RawMessage) error {
+	for nm, fv := range checkpointEnvFields(ev) {
+		b, ok := es[nm]
+		if !ok {
+			continue
+		}
+		if r, ok := fv.Addr().Interface().(*randx.SysRand); ok {
+			rs := &randState{}
+			if err := json.Unmarshal(b, rs); err != nil {
+				return fmt.Errorf("checkpoint: env %s field %s: %w", ev.Label(), nm, err)
+			}
+			if err := rs.restore(r); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := json.Unmarshal(b, fv.Addr().Interface()); err != nil {
+			return fmt.Errorf("checkpoint: env %s field %s: %w", ev.Label(), nm, err)
+		}
+	}
+	return nil
+}
+
+// CheckpointFilename returns a checkpoint file name, using the network name,
+// the given RunName string identifying tag, parameters and starting run,
+// and the counter string.
+func CheckpointFilename(net *Network, ctrString, runName string) string {
+	return net.Name + "_" + runName + "_" + ctrString + ".ckpt.zip"
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// newCheckpointNet returns a network that uses the layer random streams
+// for dropout and noise injection during training.
+func newCheckpointNet(t *testing.T) *Network {
+	net := NewNetwork("Checkpoint")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 4, TargetLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.BidirConnectLayers(hid, out, paths.NewFull())
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	in.Dropout.On = true
+	in.Dropout.P = 0.2
+	var qtrs Quarters
+	qtrs.SetFlag(true, Q1, Q2, Q3, Q4)
+	hid.InjectNoise(ActNoise, 0.01, qtrs)
+	net.SetRandSeed(42)
+	net.InitWeights()
+	return net
+}
+
+func TestCheckpointResume(t *testing.T) {
+	inpat := tensor.NewFloat32([]int{4, 4})
+	outpat := tensor.NewFloat32([]int{2, 4})
+	// train runs n training trials on random input and output patterns,
+	// drawn from the network Rand.
+	train := func(net *Network, n int) []bool {
+		ctx := NewContext()
+		in, out := net.LayerByName("Input"), net.LayerByName("Output")
+		var drop []bool
+		for range n {
+			for i := range inpat.Len() {
+				inpat.SetFloat1D(i, float64(net.Rand.Intn(2)))
+			}
+			for i := range outpat.Len() {
+				outpat.SetFloat1D(i, float64(net.Rand.Intn(2)))
+			}
+			net.InitExt()
+			in.ApplyExt(inpat)
+			out.ApplyExt(outpat)
+			net.AlphaCycle(ctx, true)
+			for ni := range in.Neurons {
+				drop = append(drop, in.Neurons[ni].HasFlag(NeurDropout))
+			}
+		}
+		return drop
+	}
+
+	straight := newCheckpointNet(t)
+	train(straight, 5)
+	fn := filepath.Join(t.TempDir(), "test.ckpt.zip")
+	if err := SaveCheckpoint(fn, straight, nil, etime.Epoch, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	sdrop := train(straight, 5)
+
+	resumed := newCheckpointNet(t)
+	train(resumed, 2) // different state, to be overwritten by the checkpoint
+	if _, err := LoadCheckpoint(fn, resumed, nil, nil, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	rdrop := train(resumed, 5)
+
+	if !slices.Equal(rdrop, sdrop) {
+		t.Errorf("resumed run dropout differs from the straight run")
+	}
+	if sw, rw := straight.WtsFingerprint(), resumed.WtsFingerprint(); sw != rw {
+		t.Errorf("resumed run weights differ from the straight run: %x vs. %x", rw, sw)
+	}
+
+	// saving must not change the run
+	unsaved := newCheckpointNet(t)
+	train(unsaved, 5)
+	if udrop := train(unsaved, 5); !slices.Equal(udrop, sdrop) {
+		t.Errorf("saving a checkpoint changed the dropout of the run")
+	}
+	if sw, uw := straight.WtsFingerprint(), unsaved.WtsFingerprint(); sw != uw {
+		t.Errorf("saving a checkpoint changed the weights of the run: %x vs. %x", sw, uw)
+	}
+}
+
+func TestCheckpointResumeEnv(t *testing.T) {
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.SetNumRows(8)
+	for row := range 8 {
+		dt.SetString("Name", row, fmt.Sprintf("p%d", row))
+	}
+	newEnv := func() *ShardEnv {
+		ev := &ShardEnv{Name: "Train", Seed: 3}
+		ev.Config(table.NewIndexView(dt), 0, 1)
+		return ev
+	}
+	// trials returns the names of the next n trials.
+	trials := func(ev *ShardEnv, n int) []string {
+		var nms []string
+		for range n {
+			ev.Step()
+			nms = append(nms, ev.TrialName.Cur)
+		}
+		return nms
+	}
+
+	net := newCheckpointNet(t)
+	straight := newEnv()
+	trials(straight, 12) // save in the middle of the second epoch
+	fn := filepath.Join(t.TempDir(), "env.ckpt.zip")
+	if err := SaveCheckpoint(fn, net, nil, etime.Epoch, env.Envs{"Train": straight}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	strls := trials(straight, 20)
+
+	resumed := newEnv()
+	trials(resumed, 3)
+	if _, err := LoadCheckpoint(fn, net, nil, env.Envs{"Train": resumed}, nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if rtrls := trials(resumed, 20); !slices.Equal(rtrls, strls) {
+		t.Errorf("resumed trial order differs from the straight run:\n%v\n%v", rtrls, strls)
+	}
+	if resumed.Epoch.Cur != straight.Epoch.Cur {
+		t.Errorf("resumed Epoch %d != straight %d", resumed.Epoch.Cur, straight.Epoch.Cur)
+	}
+}
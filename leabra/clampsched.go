@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+// ClampSchedParams are the parameters for a clamping strength curriculum,
+// typically for the plus phase targets of a TargetLayer, which starts
+// with full hard clamping (teacher forcing) for HardEpochs, and then
+// switches to soft clamping, with a clamp Gain that anneals linearly
+// from Start to Min over the following Epochs, so that the layer
+// activity is increasingly driven by the network itself.
+// When On, it sets the Act.Clamp Hard and Gain params of the layer
+// as a function of the training epoch, which is advanced along with
+// the learning rate schedules by Network.EpochInc or SetLrateEpoch,
+// which can be called automatically by LooperLrateSched.
+type ClampSchedParams struct {
+
+	// whether to use the clamping schedule, which then determines Act.Clamp.Hard and Gain
+	On bool
+
+	// number of epochs of full hard clamping at the start of training, before switching to soft clamping
+	HardEpochs int `default:"10" min:"0"`
+
+	// number of epochs over which the soft clamp Gain anneals from Start to Min, after the HardEpochs
+	Epochs int `default:"100" min:"0"`
+
+	// soft clamp Gain at the start of soft clamping, after the HardEpochs
+	Start Float `default:"1" min:"0"`
+
+	// soft clamp Gain at the end of the Epochs, which is used from then on
+	Min Float `default:"0.2" min:"0"`
+}
+
+func (cs *ClampSchedParams) Update() {
+}
+
+func (cs *ClampSchedParams) Defaults() {
+	cs.HardEpochs = 10
+	cs.Epochs = 100
+	cs.Start = 1
+	cs.Min = 0.2
+}
+
+func (cs *ClampSchedParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return cs.On
+	}
+}
+
+// Clamp returns whether to hard clamp, and otherwise the soft clamp
+// gain, for the given training epoch, starting at 0.
+func (cs *ClampSchedParams) Clamp(epoch int) (hard bool, gain Float) {
+	if epoch < cs.HardEpochs {
+		return true, cs.Start
+	}
+	ep := epoch - cs.HardEpochs
+	if cs.Epochs <= 0 || ep >= cs.Epochs {
+		return false, cs.Min
+	}
+	return false, cs.Start + (cs.Min-cs.Start)*Float(ep)/Float(cs.Epochs)
+}
+
+// ClampSched sets the Act.Clamp Hard and Gain params of the layer
+// according to its clamping schedule (Act.ClampSched) for the given
+// training epoch, if the schedule is on.
+func (ly *Layer) ClampSched(epoch int) {
+	if !ly.Act.ClampSched.On {
+		return
+	}
+	ly.Act.Clamp.Hard, ly.Act.Clamp.Gain = ly.Act.ClampSched.Clamp(epoch)
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/params"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestClampSched(t *testing.T) {
+	cs := &ClampSchedParams{}
+	cs.Defaults()
+	cs.HardEpochs = 2
+	cs.Epochs = 4
+	cs.Start = 1
+	cs.Min = 0.2
+	for ep, want := range []Float{1, 1, 1, 0.8, 0.6, 0.4, 0.2, 0.2} {
+		hard, gain := cs.Clamp(ep)
+		if hard != (ep < 2) || math.Abs(float64(gain-want)) > 1e-6 {
+			t.Errorf("epoch %d: got hard %v gain %v, want %v %v", ep, hard, gain, ep < 2, want)
+		}
+	}
+
+	net := NewNetwork("ClampSched")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.ConnectLayers(hid, out, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.ApplyParams(&params.Sheet{
+		{Sel: "#Output", Desc: "clamp schedule",
+			Params: params.Params{
+				"Layer.Act.ClampSched.On":         "true",
+				"Layer.Act.ClampSched.HardEpochs": "1",
+				"Layer.Act.ClampSched.Epochs":     "2",
+				"Layer.Act.ClampSched.Start":      "0.6",
+				"Layer.Act.ClampSched.Min":        "0.2",
+			}},
+	}, false)
+	net.InitWeights()
+	pat := tensor.NewFloat32([]int{2, 2})
+	pat.Values[0] = 1
+	actP := func() []float32 {
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt(pat)
+		out.ApplyExt(pat)
+		net.AlphaCycInit(true)
+		ctx.AlphaCycStart()
+		for range 4 {
+			for range ctx.CycPerQtr {
+				net.Cycle(ctx)
+				ctx.CycleInc()
+			}
+			net.QuarterFinal(ctx)
+			ctx.QuarterInc()
+		}
+		var acts []float32
+		out.UnitValues(&acts, "ActP", 0)
+		return acts
+	}
+	for ep, want := range []Float{0.6, 0.6, 0.4, 0.2} {
+		if ep > 0 {
+			net.EpochInc()
+		}
+		if out.Act.Clamp.Hard != (ep < 1) || math.Abs(float64(out.Act.Clamp.Gain-want)) > 1e-6 {
+			t.Errorf("epoch %d: got Hard %v Gain %v", ep, out.Act.Clamp.Hard, out.Act.Clamp.Gain)
+		}
+		if !in.Act.Clamp.Hard || hid.Act.Clamp.Gain != 0.2 {
+			t.Errorf("epoch %d: unscheduled layer clamping changed", ep)
+		}
+		acts := actP()
+		if hard := acts[0] >= 0.95-1e-6 && acts[1] == 0; hard != (ep < 1) {
+			t.Errorf("epoch %d: hard clamped %v, got ActP %v", ep, hard, acts)
+		}
+	}
+	net.InitWeights()
+	if !out.Act.Clamp.Hard {
+		t.Errorf("InitWeights: clamp schedule not reset to hard clamping")
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// CLSParams are the params for complementary learning systems (CLS)
+// training over simulated days, where a fast hippocampal network learns
+// the new items of each day in an awake phase, and a slow neocortical
+// network learns from the offline replay of all the items stored in the
+// hippocampus so far, interleaved, in the sleep phase at the end of the
+// day (see Network.ReplayToCortex).  The consolidation of the items of
+// each day is then measured over the following days by ConsolMetrics.
+type CLSParams struct {
+
+	// Days is the number of simulated days, each with new items.
+	Days int `default:"5" min:"1"`
+
+	// AwakeEpochs is the number of epochs of hippocampal training
+	// on the new items in the awake phase of each day.
+	AwakeEpochs int `default:"3" min:"1"`
+
+	// Replays is the number of replay trials in the sleep phase
+	// of each day, each training the cortex.
+	Replays int `default:"100" min:"0"`
+}
+
+func (cp *CLSParams) Defaults() {
+	cp.Days = 5
+	cp.AwakeEpochs = 3
+	cp.Replays = 100
+}
+
+func (cp *CLSParams) Update() {
+}
+
+// NEpochs returns the total number of training epochs over all the days.
+func (cp *CLSParams) NEpochs() int {
+	return cp.Days * max(cp.AwakeEpochs, 1)
+}
+
+// Day returns the day of the given training epoch.
+func (cp *CLSParams) Day(epoch int) int {
+	return epoch / max(cp.AwakeEpochs, 1)
+}
+
+// SleepEpoch returns true if the given training epoch is the last one
+// of the awake phase of its day, so the sleep phase follows it.
+func (cp *CLSParams) SleepEpoch(epoch int) bool {
+	return (epoch+1)%max(cp.AwakeEpochs, 1) == 0
+}
+
+// ReplayToCortex runs one offline replay trial in this hippocampal
+// network (HipReplay), cued by the given cue pattern (e.g., a CA3
+// pattern stored in training, or spontaneous if nil), and trains the
+// given cortex network on the replayed ECout pattern, which is returned
+// in ecout.  The cortex shares the EC representation with the
+// hippocampus: the replayed pattern is the target of its out layer,
+// and the units of the pattern in the given mask (e.g., the pools of
+// the cue, all if nil) are the input to its in layer, so that the
+// cortex learns to recall the patterns from their cues, as the
+// hippocampus does.  The in and out layers must have the shape of ECout.
+func (net *Network) ReplayToCortex(ctx *Context, rp *ReplayParams, cue []float32, cortex *Network, in, out *Layer, mask []bool, ecout *[]float32) {
+	net.HipReplay(ctx, rp, cue, ecout)
+	inp := make([]float32, len(*ecout))
+	for i, v := range *ecout {
+		if mask == nil || (i < len(mask) && mask[i]) {
+			inp[i] = v
+		}
+	}
+	cortex.InitExt()
+	in.ApplyExt1D32(inp)
+	out.ApplyExt1D32(*ecout)
+	cortex.AlphaCycle(ctx, true)
+}
+
+// MemCorrelTrial runs one test trial of this network, without learning,
+// with the given input pattern applied to the in layer and the target
+// pattern to the out layer, and returns the MemCorrel of the ActM
+// activity of the out layer with its target, e.g., for testing the
+// recall of a cortex trained by ReplayToCortex.
+func (net *Network) MemCorrelTrial(ctx *Context, in, out *Layer, input, target tensor.Tensor) float32 {
+	var act, trg []float32
+	net.InitExt()
+	in.ApplyExt(input)
+	out.ApplyExt(target)
+	net.AlphaCycle(ctx, false)
+	out.UnitValues(&act, "ActM", 0)
+	out.UnitValues(&trg, "Targ", 0)
+	return MemCorrel(act, trg)
+}
+
+// ConsolMetrics records the systems consolidation of memories over the
+// days of a CLS simulation (see CLSParams): at the end of each day, the
+// memory of each set of items (e.g., the items learned on each day) in
+// the hippocampus (HipMem) and in the cortex (CortexMem) is added, e.g.,
+// as the mean MemCorrel of their recall, from which it computes:
+//   - Age: the number of days since the set was first added.
+//   - Consol: the consolidation index, CortexMem / max(HipMem, CortexMem),
+//     i.e., the proportion of the memory that no longer depends on the
+//     hippocampus (NaN if there is no memory).
+//   - Retention: the memory of the system, max(HipMem, CortexMem),
+//     relative to that when the set was first added.
+//
+// Plotting CortexMem by Age on the last day gives the temporal gradient
+// of the retrograde amnesia that would follow a hippocampal lesion.
+type ConsolMetrics struct {
+
+	// Table has a row for each set on each day, with the Day, Set, Age,
+	// HipMem, CortexMem, Consol and Retention columns.
+	Table *table.Table
+
+	// first has the row of the first day of each set.
+	first map[string]int
+}
+
+// Init initializes the metrics for a new run, with an empty Table.
+func (cm *ConsolMetrics) Init() {
+	dt := table.NewTable()
+	dt.SetMetaData("name", "Consol")
+	dt.SetMetaData("desc", "systems consolidation metrics over days")
+	dt.AddIntColumn("Day")
+	dt.AddStringColumn("Set")
+	dt.AddIntColumn("Age")
+	dt.AddFloat64Column("HipMem")
+	dt.AddFloat64Column("CortexMem")
+	dt.AddFloat64Column("Consol")
+	dt.AddFloat64Column("Retention")
+	cm.Table = dt
+	cm.first = make(map[string]int)
+}
+
+// Add adds the hippocampal and cortical memory of the given set of
+// items at the end of the given day.
+func (cm *ConsolMetrics) Add(day int, set string, hipMem, cortexMem float64) {
+	if cm.Table == nil {
+		cm.Init()
+	}
+	dt := cm.Table
+	row := dt.Rows
+	fr, ok := cm.first[set]
+	if !ok {
+		fr = row
+		cm.first[set] = row
+	}
+	mem := max(hipMem, cortexMem)
+	consol := math.NaN()
+	if mem > 0 {
+		consol = max(cortexMem, 0) / mem
+	}
+	ret := math.NaN()
+	if fr == row {
+		ret = 1
+	} else if m0 := max(dt.Float("HipMem", fr), dt.Float("CortexMem", fr)); m0 > 0 {
+		ret = mem / m0
+	}
+	dt.SetNumRows(row + 1)
+	dt.SetFloat("Day", row, float64(day))
+	dt.SetString("Set", row, set)
+	dt.SetFloat("Age", row, float64(day)-dt.Float("Day", fr))
+	dt.SetFloat("HipMem", row, hipMem)
+	dt.SetFloat("CortexMem", row, cortexMem)
+	dt.SetFloat("Consol", row, consol)
+	dt.SetFloat("Retention", row, ret)
+}
+
+// DayStats returns the means of the HipMem, CortexMem, Consol and
+// Retention metrics over the sets added on the given day, ignoring NaN,
+// and the CortexMem of the oldest set (OldCortexMem).
+func (cm *ConsolMetrics) DayStats(day int) StatValues {
+	names := []string{"HipMem", "CortexMem", "Consol", "Retention"}
+	sums := make([]float64, len(names))
+	ns := make([]int, len(names))
+	sv := StatValues{"OldCortexMem": math.NaN()}
+	if cm.Table == nil {
+		cm.Init()
+	}
+	dt := cm.Table
+	oldAge := -1.0
+	for ri := range dt.Rows {
+		if int(dt.Float("Day", ri)) != day {
+			continue
+		}
+		for i, nm := range names {
+			if v := dt.Float(nm, ri); !math.IsNaN(v) {
+				sums[i] += v
+				ns[i]++
+			}
+		}
+		if age := dt.Float("Age", ri); age > oldAge {
+			oldAge = age
+			sv["OldCortexMem"] = dt.Float("CortexMem", ri)
+		}
+	}
+	for i, nm := range names {
+		sv[nm] = math.NaN()
+		if ns[i] > 0 {
+			sv[nm] = sums[i] / float64(ns[i])
+		}
+	}
+	return sv
+}
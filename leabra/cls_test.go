@@ -0,0 +1,47 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConsolMetrics(t *testing.T) {
+	cp := &CLSParams{}
+	cp.Defaults()
+	cp.Days = 3
+	cp.AwakeEpochs = 2
+	if cp.NEpochs() != 6 || cp.Day(3) != 1 || cp.SleepEpoch(2) || !cp.SleepEpoch(3) {
+		t.Errorf("CLSParams epochs: %d day %d", cp.NEpochs(), cp.Day(3))
+	}
+
+	cm := &ConsolMetrics{}
+	cm.Init()
+	cm.Add(0, "d0", 0.8, 0.2)
+	cm.Add(1, "d0", 0.4, 0.6)
+	cm.Add(1, "d1", 0.9, 0)
+	cm.Add(1, "d2", 0, 0)
+	dt := cm.Table
+	if dt.Rows != 4 {
+		t.Fatalf("rows: %d", dt.Rows)
+	}
+	if v := dt.Float("Consol", 0); math.Abs(v-0.25) > 1e-6 {
+		t.Errorf("Consol day 0: %g", v)
+	}
+	if v := dt.Float("Consol", 1); math.Abs(v-1) > 1e-6 {
+		t.Errorf("Consol day 1: %g", v)
+	}
+	if v := dt.Float("Retention", 1); math.Abs(v-0.75) > 1e-6 {
+		t.Errorf("Retention: %g", v)
+	}
+	if dt.Float("Age", 1) != 1 || dt.Float("Age", 2) != 0 || !math.IsNaN(dt.Float("Consol", 3)) {
+		t.Error("Age or NaN Consol")
+	}
+	sv := cm.DayStats(1)
+	if math.Abs(sv["Consol"]-0.5) > 1e-6 || math.Abs(sv["CortexMem"]-0.2) > 1e-6 || math.Abs(sv["OldCortexMem"]-0.6) > 1e-6 {
+		t.Errorf("DayStats: %v", sv)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"cogentcore.org/core/base/fsx"
+	"cogentcore.org/core/base/iox/tomlx"
+	"cogentcore.org/core/base/iox/yamlx"
+	"cogentcore.org/core/base/mpi"
+	"cogentcore.org/core/base/reflectx"
+	"github.com/emer/emergent/v2/econfig"
+)
+
+// Config sets the sim config struct from config files and command-line
+// args, as in econfig.Config, with TOML or YAML config files, so that batch
+// jobs can be fully specified by a config file.  The steps are:
+//   - Apply any `default:` field tag default values.
+//   - Open the config file given by the -config or -cfg arg, or the first
+//     of the default files that exists, on the econfig.IncludePaths.
+//     Files with a .yaml or .yml extension are YAML, and others are TOML.
+//     Any Includes in the config file are opened first, so the config file
+//     overrides the settings in its includes.
+//   - Set values from the command-line args, with field names as the flag
+//     names, and a . separator for sub-fields (e.g., -Run.NRuns 10), which
+//     override the config file.
+//
+// Field names in YAML files use the same (case-insensitive) names as TOML.
+// Returns the args and any errors.  Also processes -help to print usage.
+func Config(cfg any, defaultFile ...string) ([]string, error) {
+	file := configArgFile(os.Args[1:])
+	if file == "" {
+		for _, fn := range defaultFile {
+			if len(fsx.FindFilesOnPaths(econfig.IncludePaths, fn)) > 0 {
+				file = fn
+				break
+			}
+		}
+	}
+	if !isYAMLConfig(file) {
+		return econfig.Config(cfg, defaultFile...)
+	}
+	var errs []error
+	if err := econfig.SetFromDefaults(cfg); err != nil {
+		errs = append(errs, err)
+	}
+	if err := openConfigIncludes(cfg, file); err != nil {
+		errs = append(errs, err)
+	}
+	econfig.ConfigFile = file
+	args := os.Args[1:]
+	nonFlags, err := econfig.SetFromArgs(cfg, args)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	econfig.NonFlagArgs = nonFlags
+	if econfig.Help {
+		mpi.Println(econfig.Usage(cfg))
+		os.Exit(0)
+	}
+	return args, errors.Join(errs...)
+}
+
+// OpenConfig opens the config settings from the given TOML or YAML file
+// (according to the extension), looking on the econfig.IncludePaths
+// for relative file names.
+func OpenConfig(cfg any, file string) error {
+	files := []string{file}
+	if !filepath.IsAbs(file) {
+		files = fsx.FindFilesOnPaths(econfig.IncludePaths, file)
+	}
+	if len(files) == 0 {
+		return errors.New("leabra.OpenConfig: config file not found: " + file)
+	}
+	if !isYAMLConfig(file) {
+		return tomlx.Open(cfg, files[0])
+	}
+	// YAML is converted to TOML, for the same case-insensitive field names
+	var m map[string]any
+	if err := yamlx.Open(&m, files[0]); err != nil {
+		return err
+	}
+	b, err := tomlx.WriteBytes(m)
+	if err != nil {
+		return err
+	}
+	return tomlx.ReadBytes(cfg, b)
+}
+
+// openConfigIncludes opens the includes of the config file (if the config
+// has Includes), deepest first, followed by the config file itself.
+func openConfigIncludes(cfg any, file string) error {
+	if err := OpenConfig(cfg, file); err != nil {
+		return err
+	}
+	incsObj, ok := cfg.(econfig.Includeser)
+	if !ok {
+		return nil
+	}
+	var errs []error
+	incs, err := configIncludesStack(incsObj, nil)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if len(incs) == 0 {
+		return errors.Join(errs...)
+	}
+	for i := len(incs) - 1; i >= 0; i-- {
+		if err := OpenConfig(cfg, incs[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := OpenConfig(cfg, file); err != nil { // reopen to override includes
+		errs = append(errs, err)
+	}
+	*incsObj.IncludesPtr() = incs
+	return errors.Join(errs...)
+}
+
+// configIncludesStack returns the stack of include files in the natural
+// order in which they are encountered, as in econfig.IncludesStack,
+// opening the includes as TOML or YAML files, without altering cfg.
+func configIncludesStack(cfg econfig.Includeser, includes []string) ([]string, error) {
+	incs := *cfg.IncludesPtr()
+	for i := len(incs) - 1; i >= 0; i-- {
+		includes = append(includes, incs[i]) // reverse order so later overwrite earlier
+	}
+	var errs []error
+	for _, inc := range incs {
+		clone := reflect.New(reflectx.NonPointerType(reflect.TypeOf(cfg))).Interface().(econfig.Includeser)
+		if err := OpenConfig(clone, inc); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		var err error
+		if includes, err = configIncludesStack(clone, includes); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return includes, errors.Join(errs...)
+}
+
+// configArgFile returns the config file named by the -config or -cfg arg.
+func configArgFile(args []string) string {
+	for i, a := range args {
+		nm := strings.TrimLeft(a, "-")
+		if a == nm {
+			continue
+		}
+		val := ""
+		if k, v, ok := strings.Cut(nm, "="); ok {
+			nm, val = k, v
+		} else if i+1 < len(args) {
+			val = args[i+1]
+		}
+		if nm == "config" || nm == "cfg" {
+			return val
+		}
+	}
+	return ""
+}
+
+// isYAMLConfig returns true if the file has a YAML extension.
+func isYAMLConfig(file string) bool {
+	ext := strings.ToLower(filepath.Ext(file))
+	return ext == ".yaml" || ext == ".yml"
+}
@@ -0,0 +1,40 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testRunConfig struct {
+	NRuns   int
+	Lrate   float32
+	Enabled bool
+}
+
+type testConfig struct {
+	Includes []string
+	Tag      string
+	Run      testRunConfig
+}
+
+func (cfg *testConfig) IncludesPtr() *[]string { return &cfg.Includes }
+
+func TestOpenConfigYAML(t *testing.T) {
+	dir := t.TempDir()
+	base := filepath.Join(dir, "base.toml")
+	os.WriteFile(base, []byte("Tag = \"base\"\n[Run]\nNRuns = 3\nLrate = 0.1\n"), 0666)
+	fn := filepath.Join(dir, "batch.yaml")
+	os.WriteFile(fn, []byte("Includes: ["+base+"]\nTag: batch\nRun:\n  Lrate: 0.02\n  Enabled: true\n"), 0666)
+	cfg := &testConfig{}
+	if err := openConfigIncludes(cfg, fn); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Tag != "batch" || cfg.Run.NRuns != 3 || cfg.Run.Lrate != 0.02 || !cfg.Run.Enabled {
+		t.Errorf("OpenConfig YAML: got %+v", cfg)
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+
+	"cogentcore.org/core/base/randx"
+)
+
+// ConsolSchedule is a systems consolidation schedule, for simulations
+// where new (e.g., AC) items are learned after old (e.g., AB) items:
+// it specifies when offline hippocampal replay trials are run
+// (see Network.HipReplay), the mix of old and new items that cue the
+// replay, and the proportion of old training items interleaved with the
+// new items during their training, all within a Budget of extra trials
+// per run, so that different schedules can be compared (and searched,
+// e.g., with the consolopt command of the hip example) for how well they
+// protect the old items from retroactive interference.  The number of
+// replay trials per replay epoch is set separately by the sim.
+type ConsolSchedule struct {
+
+	// Start is the number of epochs after the switch to the new items
+	// at which replay starts, or -1 to replay from the start of training.
+	Start int `default:"-1" min:"-1"`
+
+	// Every is the interval in epochs between replay epochs,
+	// counting from the Start.
+	Every int `default:"1" min:"1"`
+
+	// OldFrac is the proportion of replay trials that are cued by old
+	// items, with the others cued by new items, when both have been
+	// stored.  If < 0, the cues are chosen uniformly over all the stored
+	// items.
+	OldFrac float32 `default:"-1" max:"1"`
+
+	// Interleave is the proportion of the old training items that are
+	// interleaved with the new items in each epoch of their training,
+	// chosen at random in each epoch.
+	Interleave float32 `min:"0" max:"1"`
+
+	// Budget is the maximum total number of extra trials per run,
+	// counting both the replay trials and the interleaved old training
+	// trials, after which there is no more replay or interleaving.
+	// 0 = no limit.
+	Budget int `min:"0"`
+}
+
+func (cs *ConsolSchedule) Defaults() {
+	cs.Start = -1
+	cs.Every = 1
+	cs.OldFrac = -1
+}
+
+func (cs *ConsolSchedule) Update() {
+}
+
+// ReplayEpoch returns true if replay should be run at the end of the given
+// training epoch, where newEpoch is the first epoch of training on the
+// new items, or -1 if training has not yet switched to them.
+func (cs *ConsolSchedule) ReplayEpoch(epoch, newEpoch int) bool {
+	every := max(cs.Every, 1)
+	if cs.Start < 0 {
+		return epoch%every == 0
+	}
+	if newEpoch < 0 {
+		return false
+	}
+	rel := epoch - newEpoch - cs.Start
+	return rel >= 0 && rel%every == 0
+}
+
+// NInterleave returns the number of old training items to interleave
+// in each epoch of training on the new items, out of nOld.
+func (cs *ConsolSchedule) NInterleave(nOld int) int {
+	n := int(math.Round(float64(cs.Interleave) * float64(nOld)))
+	return min(max(n, 0), nOld)
+}
+
+// Allow returns the number of the n requested extra trials that are
+// allowed within the Budget, given the number already used in this run.
+func (cs *ConsolSchedule) Allow(n, used int) int {
+	if cs.Budget <= 0 {
+		return n
+	}
+	return min(n, max(cs.Budget-used, 0))
+}
+
+// ReplayCue returns the index of the stored item to use as the cue for
+// a replay trial, according to OldFrac, where old indicates which of the
+// stored items are old, using the given random number generator.
+// It returns -1 if there are no stored items.
+func (cs *ConsolSchedule) ReplayCue(old []bool, rnd randx.Rand) int {
+	n := len(old)
+	if n == 0 {
+		return -1
+	}
+	if cs.OldFrac < 0 {
+		return rnd.Intn(n)
+	}
+	wantOld := rnd.Float32() < cs.OldFrac
+	var idxs []int
+	for i, o := range old {
+		if o == wantOld {
+			idxs = append(idxs, i)
+		}
+	}
+	if len(idxs) == 0 { // only one kind stored so far
+		return rnd.Intn(n)
+	}
+	return idxs[rnd.Intn(len(idxs))]
+}
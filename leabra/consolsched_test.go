@@ -0,0 +1,59 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+)
+
+func TestConsolSchedule(t *testing.T) {
+	cs := &ConsolSchedule{}
+	cs.Defaults()
+	if !cs.ReplayEpoch(0, -1) || !cs.ReplayEpoch(3, 2) {
+		t.Errorf("Start -1 should replay every epoch")
+	}
+	cs.Start, cs.Every = 1, 2
+	for ep, want := range []bool{false, false, false, false, true, false, true} {
+		if got := cs.ReplayEpoch(ep, 3); got != want {
+			t.Errorf("ReplayEpoch(%d, 3): %v", ep, got)
+		}
+	}
+	if cs.ReplayEpoch(5, -1) {
+		t.Errorf("no replay before the switch with Start >= 0")
+	}
+	cs.Interleave = 0.3
+	if n := cs.NInterleave(10); n != 3 {
+		t.Errorf("NInterleave: %d", n)
+	}
+	if n := cs.Allow(10, 95); n != 10 {
+		t.Errorf("Allow without Budget: %d", n)
+	}
+	cs.Budget = 100
+	if n := cs.Allow(10, 95); n != 5 || cs.Allow(10, 120) != 0 {
+		t.Errorf("Allow with Budget: %d", n)
+	}
+	old := []bool{true, true, false, false, false}
+	rnd := randx.NewSysRand(1)
+	cs.OldFrac = 1
+	for range 20 {
+		if ci := cs.ReplayCue(old, rnd); !old[ci] {
+			t.Fatalf("OldFrac 1 cue: %d", ci)
+		}
+	}
+	cs.OldFrac = 0
+	for range 20 {
+		if ci := cs.ReplayCue(old, rnd); old[ci] {
+			t.Fatalf("OldFrac 0 cue: %d", ci)
+		}
+	}
+	if ci := cs.ReplayCue(old[:2], rnd); ci < 0 || ci > 1 {
+		t.Errorf("only old stored: %d", ci)
+	}
+	if ci := cs.ReplayCue(nil, rnd); ci != -1 {
+		t.Errorf("no stored: %d", ci)
+	}
+}
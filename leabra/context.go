@@ -11,7 +11,7 @@ type Context struct {
 
 	// accumulated amount of time the network has been running,
 	// in simulation-time (not real world time), in seconds.
-	Time float32
+	Time Float
 
 	// cycle counter: number of iterations of activation updating
 	// (settling) on the current alpha-cycle (100 msec / 10 Hz) trial.
@@ -24,6 +24,12 @@ type Context struct {
 	// in simulation time.
 	CycleTot int
 
+	// total number of alpha-cycle trials started since it was last
+	// reset, incremented by AlphaCycStart, so the current trial is
+	// AlphaCycTot - 1 (see AlphaCyc).  Used for the burst schedules
+	// across trials (see BurstParams).
+	AlphaCycTot int
+
 	// current gamma-frequency (25 msec / 40 Hz) quarter of alpha-cycle
 	// (100 msec / 10 Hz) trial being processed.
 	// Due to 0-based indexing, the first quarter is 0, second is 1, etc.
@@ -34,7 +40,7 @@ type Context struct {
 	PlusPhase bool
 
 	// amount of time to increment per cycle.
-	TimePerCyc float32 `default:"0.001"`
+	TimePerCyc Float `default:"0.001"`
 
 	// number of cycles per quarter to run: 25 = standard 100 msec alpha-cycle.
 	CycPerQtr int `default:"25"`
@@ -61,6 +67,7 @@ func (tm *Context) Reset() {
 	tm.Time = 0
 	tm.Cycle = 0
 	tm.CycleTot = 0
+	tm.AlphaCycTot = 0
 	tm.Quarter = 0
 	tm.PlusPhase = false
 	if tm.CycPerQtr == 0 {
@@ -73,6 +80,13 @@ func (tm *Context) AlphaCycStart() {
 	tm.Cycle = 0
 	tm.Quarter = 0
 	tm.PlusPhase = false
+	tm.AlphaCycTot++
+}
+
+// AlphaCyc returns the index of the current alpha-cycle trial
+// since the counters were last reset, starting at 0.
+func (tm *Context) AlphaCyc() int {
+	return max(tm.AlphaCycTot-1, 0)
 }
 
 // CycleInc increments at the cycle level
@@ -0,0 +1,234 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// CurriculumStage is a stage of training in a Curriculum, on the items of
+// one or more tables, which lasts until a criterion is reached on a stat,
+// or for a number of epochs, e.g., AB training until the AB items are
+// remembered.
+type CurriculumStage struct {
+
+	// Name is the name of the stage, e.g., AB, which is logged.
+	Name string
+
+	// Tables are the names of the tables of training items of the stage,
+	// as added to the Curriculum by AddTable: all the items of the first
+	// table are trained in every epoch, interleaved with a random
+	// Interleave proportion of the items of the other tables, which are
+	// resampled in every epoch.
+	Tables []string
+
+	// Interleave is the proportion of the items of the other Tables
+	// (after the first) that are interleaved in each epoch.
+	Interleave float32 `min:"0" max:"1"`
+
+	// Stat is the name of the stat compared with the Criterion at the end
+	// of each epoch, advancing to the next stage when it is >= Criterion.
+	// If empty, the stage lasts for its Epochs.
+	Stat string
+
+	// Criterion is the value of the Stat at which the stage is done.
+	Criterion float64
+
+	// Epochs is the maximum number of epochs of the stage, after which
+	// it is done even if the Criterion has not been reached.
+	// 0 = no maximum, so the last stage continues until the end of the run.
+	Epochs int `min:"0"`
+}
+
+// Curriculum is a sequence of training stages, each on the items of one
+// or more tables, e.g., AB then AC training for the classic AB-AC
+// interference paradigm, or interleaved (several tables per stage) and
+// spaced (alternating stages) schedules.  Each stage advances to the next
+// when its criterion is reached on a stat, or after its number of epochs.
+// The sim adds the tables with AddTable, calls Init at the start of each
+// run, EpochEnd at the end of each training epoch, and ConfigEnv to set
+// the training environment to the items of the current stage.
+type Curriculum struct {
+
+	// Stages are the stages of training, in order.
+	Stages []CurriculumStage
+
+	// Stage is the index of the current stage.
+	Stage int `edit:"-"`
+
+	// StageEpochs is the number of epochs trained in the current stage.
+	StageEpochs int `edit:"-"`
+
+	// EpochStage is the name of the stage of the last epoch counted by
+	// EpochEnd, i.e., the stage trained in that epoch, for logging.
+	EpochStage string `edit:"-"`
+
+	// tables are the tables of training items, by name.
+	tables map[string]*table.Table
+
+	// mixed are the combined tables of the interleaved stages, by index.
+	mixed map[int]*table.Table
+}
+
+// AddTable adds the given table of training items, for the stages
+// with the given name in their Tables.
+func (cu *Curriculum) AddTable(name string, dt *table.Table) {
+	if cu.tables == nil {
+		cu.tables = make(map[string]*table.Table)
+	}
+	cu.tables[name] = dt
+	cu.mixed = nil
+}
+
+// Init starts the curriculum again from the first stage,
+// e.g., at the start of each run.
+func (cu *Curriculum) Init() {
+	cu.Stage = 0
+	cu.StageEpochs = 0
+	cu.EpochStage = ""
+}
+
+// Current returns the current stage, or nil if there are no stages.
+func (cu *Curriculum) Current() *CurriculumStage {
+	if cu.Stage >= len(cu.Stages) {
+		return nil
+	}
+	return &cu.Stages[cu.Stage]
+}
+
+// StageName returns the name of the current stage.
+func (cu *Curriculum) StageName() string {
+	if st := cu.Current(); st != nil {
+		return st.Name
+	}
+	return ""
+}
+
+// IsLast returns true if the current stage is the last one.
+func (cu *Curriculum) IsLast() bool {
+	return cu.Stage >= len(cu.Stages)-1
+}
+
+// Done returns true if the last stage has completed its Epochs,
+// so the run can stop.
+func (cu *Curriculum) Done() bool {
+	st := cu.Current()
+	return st != nil && cu.IsLast() && st.Epochs > 0 && cu.StageEpochs >= st.Epochs
+}
+
+// EpochEnd counts an epoch of training in the current stage, and advances
+// to the next stage if the current one is done, given the function that
+// returns the value of the stage Stat (NaN if not available, e.g., if not
+// tested in this epoch).  The last stage is never advanced beyond.
+// Returns true if it advanced.
+func (cu *Curriculum) EpochEnd(stat func(name string) float64) bool {
+	st := cu.Current()
+	if st == nil {
+		return false
+	}
+	cu.StageEpochs++
+	cu.EpochStage = st.Name
+	if cu.IsLast() {
+		return false
+	}
+	done := st.Epochs > 0 && cu.StageEpochs >= st.Epochs
+	if !done && st.Stat != "" && stat != nil {
+		v := stat(st.Stat)
+		done = !math.IsNaN(v) && v >= st.Criterion
+	}
+	if !done {
+		return false
+	}
+	cu.Stage++
+	cu.StageEpochs = 0
+	return true
+}
+
+// IndexView returns the training items of the current stage for one epoch:
+// all the items of its first table, followed by its Interleave proportion
+// of the items of its other tables, chosen with the given random number
+// generator (the global one if nil).
+func (cu *Curriculum) IndexView(rnd randx.Rand) (*table.IndexView, error) {
+	st := cu.Current()
+	if st == nil || len(st.Tables) == 0 {
+		return nil, fmt.Errorf("leabra.Curriculum: stage %d has no tables", cu.Stage)
+	}
+	var dts []*table.Table
+	for _, tnm := range st.Tables {
+		dt, ok := cu.tables[tnm]
+		if !ok {
+			return nil, fmt.Errorf("leabra.Curriculum: stage %s table not found: %s", st.Name, tnm)
+		}
+		dts = append(dts, dt)
+	}
+	if len(dts) == 1 {
+		return table.NewIndexView(dts[0]), nil
+	}
+	if cu.mixed == nil {
+		cu.mixed = make(map[int]*table.Table)
+	}
+	dt, ok := cu.mixed[cu.Stage]
+	if !ok {
+		dt = dts[0].Clone()
+		for _, odt := range dts[1:] {
+			dt.AppendRows(odt)
+		}
+		dt.SetMetaData("name", "Train"+st.Name)
+		cu.mixed[cu.Stage] = dt
+	}
+	nfirst := dts[0].Rows
+	others := make([]int, dt.Rows-nfirst)
+	for i := range others {
+		others[i] = nfirst + i
+	}
+	if rnd != nil {
+		randx.PermuteInts(others, rnd)
+	} else {
+		randx.PermuteInts(others)
+	}
+	nint := min(max(int(math.Round(float64(st.Interleave)*float64(len(others)))), 0), len(others))
+	ix := table.NewIndexView(dt)
+	ix.Indexes = ix.Indexes[:nfirst]
+	ix.Indexes = append(ix.Indexes, others[:nint]...)
+	return ix, nil
+}
+
+// ConfigEnv configures the given training environment with the items of
+// the current stage (IndexView), restricted by the given shard function
+// if non-nil (e.g., to the shard of this proc for MPI).
+func (cu *Curriculum) ConfigEnv(ev *env.FixedTable, rnd randx.Rand, shard func(ix *table.IndexView) error) error {
+	ix, err := cu.IndexView(rnd)
+	if err != nil {
+		return err
+	}
+	if shard != nil {
+		if err := shard(ix); err != nil {
+			return err
+		}
+	}
+	ev.Config(ix)
+	return ev.Validate()
+}
+
+// LogAddCurriculumItem adds a Stage item to the given logs at the given
+// mode and time, with the name of the curriculum stage trained in the
+// last epoch (EpochStage).
+func LogAddCurriculumItem(lg *elog.Logs, cu *Curriculum, mode etime.Modes, time etime.Times) {
+	lg.AddItem(&elog.Item{
+		Name: "Stage",
+		Type: reflect.String,
+		Write: elog.WriteMap{
+			etime.Scope(mode, time): func(ctx *elog.Context) {
+				ctx.SetString(cu.EpochStage)
+			}}})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+)
+
+func TestCurriculum(t *testing.T) {
+	items := func(prefix string, n int) *table.Table {
+		dt := table.NewTable()
+		dt.AddStringColumn("Name")
+		dt.SetNumRows(n)
+		for ri := range n {
+			dt.Columns[0].SetString1D(ri, fmt.Sprintf("%s_%d", prefix, ri))
+		}
+		return dt
+	}
+	cu := &Curriculum{Stages: []CurriculumStage{
+		{Name: "AB", Tables: []string{"AB"}, Stat: "ABMem", Criterion: 1, Epochs: 3},
+		{Name: "AC", Tables: []string{"AC", "AB"}, Interleave: 0.5, Epochs: 2},
+		{Name: "AB2", Tables: []string{"AB"}, Epochs: 1},
+	}}
+	cu.AddTable("AB", items("ab", 4))
+	cu.AddTable("AC", items("ac", 2))
+	cu.Init()
+	mem := 0.0
+	stat := func(name string) float64 { return mem }
+
+	names := func() []string {
+		t.Helper()
+		ev := &env.FixedTable{}
+		if err := cu.ConfigEnv(ev, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		var nms []string
+		for ri := range ev.Table.Len() {
+			nms = append(nms, ev.Table.Table.StringValue("Name", ev.Table.Indexes[ri]))
+		}
+		return nms
+	}
+	if nms := names(); len(nms) != 4 || nms[0] != "ab_0" {
+		t.Errorf("AB stage items: %v", nms)
+	}
+	if cu.EpochEnd(stat) || cu.EpochStage != "AB" {
+		t.Error("advanced before criterion")
+	}
+	mem = 1
+	if !cu.EpochEnd(stat) || cu.StageName() != "AC" || cu.EpochStage != "AB" {
+		t.Errorf("not advanced at criterion: stage %s", cu.StageName())
+	}
+	nms := names()
+	if len(nms) != 4 || nms[0] != "ac_0" || nms[1] != "ac_1" || !strings.HasPrefix(nms[2], "ab_") || nms[2] == nms[3] {
+		t.Errorf("interleaved AC stage items: %v", nms)
+	}
+	if cu.EpochEnd(nil) || !cu.EpochEnd(nil) || cu.StageName() != "AB2" || !cu.IsLast() || cu.Done() {
+		t.Errorf("not advanced after epochs: stage %s", cu.StageName())
+	}
+	if cu.EpochEnd(nil) || !cu.Done() {
+		t.Error("last stage not done after epochs")
+	}
+	cu.Init()
+	if cu.StageName() != "AB" || cu.StageEpochs != 0 {
+		t.Error("Init did not restart")
+	}
+	cu.Stages[0].Tables = []string{"Nope"}
+	if _, err := cu.IndexView(nil); err == nil {
+		t.Error("no error for unknown table")
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strconv"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/stats/metric"
+	"cogentcore.org/core/tensor/stats/simat"
+	"cogentcore.org/core/tensor/table"
+)
+
+// CycleTrajectory records the representational trajectory of a set of
+// layers within a trial, for temporal representational similarity
+// analysis (RSA): the state of each layer at every cycle is recorded
+// into a circular buffer tensor, and the time-resolved similarity of
+// each cycle's state to a reference state is computed, by default the
+// state at the last cycle recorded (i.e., the final settled state), or
+// a given reference pattern (e.g., the stored training pattern, see
+// SetRef).  Call StartTrial at the start of each trial, RecordCycle
+// after each cycle, and then Similarity, AddToTable or SimMat at the
+// end of the trial.
+type CycleTrajectory struct {
+
+	// Layers are the names of the layers to record.
+	Layers []string
+
+	// Var is the neuron variable to record (Act by default).
+	Var string
+
+	// Metric is the similarity metric (Correlation by default).
+	Metric metric.StdMetrics
+
+	// Cycles is the size of the circular buffer, i.e., the number of
+	// most recent cycles that are kept, 100 (a trial) by default.
+	Cycles int
+
+	// States are the recorded states of each layer, as a
+	// [Cycles][units] circular buffer tensor.
+	States map[string]*tensor.Float32
+
+	// Refs are the reference states of each layer set by SetRef,
+	// which are used instead of the last cycle state if present.
+	Refs map[string][]float32
+
+	// N is the number of cycles in the buffer,
+	// which is at most Cycles.
+	N int
+
+	// Total is the total number of cycles recorded since StartTrial.
+	Total int
+
+	// acts are the unit values for the current cycle.
+	acts []float32
+}
+
+// NewCycleTrajectory returns a new CycleTrajectory for the given layers,
+// recording the Act variable into a buffer of 100 cycles, compared
+// using the Correlation metric.
+func NewCycleTrajectory(layers ...string) *CycleTrajectory {
+	return &CycleTrajectory{Layers: layers, Var: "Act", Metric: metric.Correlation, Cycles: 100}
+}
+
+// StartTrial starts recording a new trial, clearing the buffer and
+// the reference states.
+func (ct *CycleTrajectory) StartTrial() {
+	ct.N = 0
+	ct.Total = 0
+	ct.Refs = nil
+}
+
+// RecordCycle records the current state of the layers
+// in the given network.
+func (ct *CycleTrajectory) RecordCycle(net *Network) {
+	if ct.States == nil {
+		ct.States = make(map[string]*tensor.Float32)
+	}
+	ncyc := max(ct.Cycles, 1)
+	bi := ct.Total % ncyc
+	for _, lnm := range ct.Layers {
+		ly := net.LayerByName(lnm)
+		if ly == nil {
+			continue
+		}
+		ly.UnitValues(&ct.acts, ct.Var, 0)
+		ct.acts = ct.acts[:len(ly.Neurons)] // not shrunk for smaller layers
+		st := ct.States[lnm]
+		if st == nil || st.DimSize(0) != ncyc || st.DimSize(1) != len(ct.acts) {
+			st = tensor.NewFloat32([]int{ncyc, len(ct.acts)}, "Cycle", "Unit")
+			ct.States[lnm] = st
+		}
+		copy(st.Values[bi*len(ct.acts):(bi+1)*len(ct.acts)], ct.acts)
+	}
+	ct.Total++
+	ct.N = min(ct.N+1, ncyc)
+}
+
+// Cycle returns the cycle within the trial of the given index
+// in the buffer, from 0 for the oldest to N-1 for the last one.
+func (ct *CycleTrajectory) Cycle(idx int) int {
+	return ct.Total - ct.N + idx
+}
+
+// State returns the recorded state of the given layer at the given
+// index in the buffer, from 0 for the oldest to N-1 for the last one,
+// or nil if not recorded.
+func (ct *CycleTrajectory) State(layer string, idx int) []float32 {
+	st := ct.States[layer]
+	if st == nil || idx < 0 || idx >= ct.N {
+		return nil
+	}
+	nu := st.DimSize(1)
+	bi := ct.Cycle(idx) % st.DimSize(0)
+	return st.Values[bi*nu : (bi+1)*nu]
+}
+
+// SetRef sets the reference state for the given layer to a copy of
+// the given pattern, for the current trial.
+func (ct *CycleTrajectory) SetRef(layer string, ref []float32) {
+	if ct.Refs == nil {
+		ct.Refs = make(map[string][]float32)
+	}
+	ct.Refs[layer] = append([]float32(nil), ref...)
+}
+
+// Ref returns the reference state for the given layer:
+// the one set by SetRef, or else the last recorded state.
+func (ct *CycleTrajectory) Ref(layer string) []float32 {
+	if ref, ok := ct.Refs[layer]; ok {
+		return ref
+	}
+	return ct.State(layer, ct.N-1)
+}
+
+// Similarity returns the similarity of the state of the given layer
+// at each cycle in the buffer to its reference state (see Ref),
+// using the Metric.
+func (ct *CycleTrajectory) Similarity(layer string) []float64 {
+	ref := ct.Ref(layer)
+	if ref == nil {
+		return nil
+	}
+	mfun := metric.StdFunc32(ct.Metric)
+	sim := make([]float64, ct.N)
+	for i := range ct.N {
+		sim[i] = float64(mfun(ct.State(layer, i), ref))
+	}
+	return sim
+}
+
+// AddToTable adds a row for each cycle in the buffer to the given
+// table, with the given trial name, the Cycle, and the Similarity
+// of each layer in a column named by the layer, adding the columns
+// if not already present, so that the trajectories of multiple
+// trials can be accumulated in one table.
+func (ct *CycleTrajectory) AddToTable(dt *table.Table, trialName string) {
+	if _, err := dt.ColumnByName("TrialName"); err != nil {
+		dt.AddStringColumn("TrialName")
+	}
+	if _, err := dt.ColumnByName("Cycle"); err != nil {
+		dt.AddIntColumn("Cycle")
+	}
+	for _, lnm := range ct.Layers {
+		if _, err := dt.ColumnByName(lnm); err != nil {
+			dt.AddFloat64Column(lnm)
+		}
+	}
+	row := dt.Rows
+	dt.SetNumRows(row + ct.N)
+	for i := range ct.N {
+		dt.SetString("TrialName", row+i, trialName)
+		dt.SetFloat("Cycle", row+i, float64(ct.Cycle(i)))
+	}
+	for _, lnm := range ct.Layers {
+		for i, s := range ct.Similarity(lnm) {
+			dt.SetFloat(lnm, row+i, s)
+		}
+	}
+}
+
+// SimMat returns the cycle-by-cycle similarity matrix of the states of
+// the given layer in the buffer, using the Metric, with the rows and
+// columns labeled by cycle every 10 cycles.
+func (ct *CycleTrajectory) SimMat(layer string) *simat.SimMat {
+	smat := simat.NewSimMat()
+	smat.Init()
+	smat.Mat.SetShape([]int{ct.N, ct.N})
+	mfun := metric.StdFunc32(ct.Metric)
+	labels := make([]string, ct.N)
+	for a := range ct.N {
+		as := ct.State(layer, a)
+		for b := range a + 1 {
+			s := float64(mfun(as, ct.State(layer, b)))
+			smat.Mat.SetFloat([]int{a, b}, s)
+			smat.Mat.SetFloat([]int{b, a}, s)
+		}
+		if cyc := ct.Cycle(a); cyc%10 == 0 {
+			labels[a] = strconv.Itoa(cyc)
+		}
+	}
+	smat.Mat.SetMetaData("name", layer+"_"+ct.Var)
+	smat.Rows = labels
+	smat.Columns = labels
+	return smat
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor/stats/metric"
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestCycleTrajectory(t *testing.T) {
+	net := MakeTestNet(t)
+	hid := net.LayerByName("Hidden")
+	ct := NewCycleTrajectory("Hidden", "Output")
+	ct.Metric = metric.Cosine
+	ct.Cycles = 3
+	ct.StartTrial()
+	for cyc := range 5 {
+		for i := range hid.Neurons {
+			hid.Neurons[i].Act = 0
+		}
+		hid.Neurons[cyc%4].Act = 1
+		hid.Neurons[3].Act = 1
+		ct.RecordCycle(net)
+	}
+	if ct.N != 3 || ct.Total != 5 || ct.Cycle(0) != 2 {
+		t.Fatalf("N = %d, Total = %d, Cycle(0) = %d: not the last 3 of 5 cycles", ct.N, ct.Total, ct.Cycle(0))
+	}
+	if st := ct.State("Hidden", 0); !slices.Equal(st, []float32{0, 0, 1, 1}) {
+		t.Errorf("oldest state = %v, not cycle 2", st)
+	}
+	if st := ct.State("Hidden", 2); !slices.Equal(st, []float32{1, 0, 0, 1}) {
+		t.Errorf("last state = %v, not cycle 4", st)
+	}
+	sim := ct.Similarity("Hidden")
+	if len(sim) != 3 || math.Abs(sim[0]-0.5) > 1e-6 || math.Abs(sim[1]-math.Sqrt(0.5)) > 1e-6 || math.Abs(sim[2]-1) > 1e-6 {
+		t.Errorf("similarity to the final state = %v, not [0.5 0.707 1]", sim)
+	}
+	ct.SetRef("Hidden", []float32{0, 0, 1, 0})
+	if sim = ct.Similarity("Hidden"); math.Abs(sim[0]-math.Sqrt(0.5)) > 1e-6 || sim[2] != 0 {
+		t.Errorf("similarity to the reference = %v", sim)
+	}
+
+	dt := table.NewTable()
+	ct.AddToTable(dt, "ab_0")
+	ct.AddToTable(dt, "ab_1")
+	if dt.Rows != 6 || dt.StringValue("TrialName", 3) != "ab_1" || dt.Float("Cycle", 5) != 4 || math.Abs(dt.Float("Hidden", 0)-math.Sqrt(0.5)) > 1e-6 {
+		t.Errorf("table wrong: %d rows", dt.Rows)
+	}
+	if _, err := dt.ColumnByName("Output"); err != nil {
+		t.Error(err)
+	}
+
+	sm := ct.SimMat("Hidden")
+	if sm.Mat.DimSize(0) != 3 || math.Abs(sm.Mat.Float([]int{1, 1})-1) > 1e-6 || math.Abs(sm.Mat.Float([]int{0, 2})-0.5) > 1e-6 || sm.Rows[0] != "" {
+		t.Errorf("sim mat wrong: %v %v", sm.Mat, sm.Rows)
+	}
+
+	ct.StartTrial()
+	if ct.N != 0 || ct.Refs != nil || ct.Similarity("Hidden") != nil {
+		t.Errorf("StartTrial did not clear the trial")
+	}
+
+	// a large layer followed by a smaller one must each record their own units
+	net = NewNetwork("Traj")
+	big := net.AddLayer2D("Big", 8, 8, SuperLayer)
+	small := net.AddLayer2D("Small", 2, 2, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	ct = NewCycleTrajectory("Big", "Small")
+	ct.StartTrial()
+	for cyc := range 2 {
+		for i := range big.Neurons {
+			big.Neurons[i].Act = 1
+		}
+		for i := range small.Neurons {
+			small.Neurons[i].Act = Float(i + cyc)
+		}
+		ct.RecordCycle(net)
+	}
+	if nu := ct.States["Small"].DimSize(1); nu != 4 {
+		t.Errorf("Small layer states have %d units, not 4", nu)
+	}
+	if st := ct.State("Small", 1); !slices.Equal(st, []float32{1, 2, 3, 4}) {
+		t.Errorf("Small layer last state = %v, not [1 2 3 4]", st)
+	}
+	if st := ct.State("Big", 1); len(st) != 64 || st[63] != 1 {
+		t.Errorf("Big layer last state has %d units", len(st))
+	}
+}
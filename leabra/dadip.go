@@ -0,0 +1,102 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import "github.com/emer/leabra/v2/fmath"
+
+// DaDipParams are the parameters for the negative (dip) component of the
+// phasic dopamine sent by a dopamine layer, which can have different
+// dynamics than bursts, as in the pauses in VTA / SNc firing driven by
+// the lateral habenula (LHb) and RMTg for aversive and disappointing
+// outcomes.  Dips have their own gain, a floor reflecting the limited
+// range of firing rate pauses below the tonic baseline, and adaptation
+// over repeated dips.  Dips can also be sent as a separate channel
+// (like a VTAn negative-valence population) to distinct layers,
+// for modeling asymmetries in aversive vs. appetitive learning.
+type DaDipParams struct {
+
+	// On enables the dip-specific dynamics.  Otherwise, the
+	// negative dopamine is sent as computed.
+	On bool
+
+	// Gain is the multiplier on negative dopamine values.
+	Gain Float `default:"1" min:"0"`
+
+	// Floor is the minimum (most negative) dopamine value for dips,
+	// after applying Gain and adaptation.
+	Floor Float `default:"-1" max:"0"`
+
+	// AdaptRate is the rate at which repeated dips adapt (habituate):
+	// after each trial, the adaptation increases by AdaptRate times the
+	// dip magnitude (times 1 - adaptation), and dips are multiplied by
+	// 1 - adaptation.  0 = no adaptation.
+	AdaptRate Float `default:"0" min:"0" max:"1"`
+
+	// AdaptDecay is the rate at which the adaptation decays
+	// back toward 0 after each trial.
+	AdaptDecay Float `default:"0.1" min:"0" max:"1"`
+
+	// SendTo is a list of layers that receive the dips as a separate
+	// negative dopamine channel.  If non-empty, the dips are only sent
+	// to these layers, and the layer's SendTo layers receive only the
+	// positive bursts (0 for dips).
+	SendTo LayerNames
+}
+
+func (dp *DaDipParams) Defaults() {
+	dp.Gain = 1
+	dp.Floor = -1
+	dp.AdaptDecay = 0.1
+}
+
+func (dp *DaDipParams) Update() {
+}
+
+// DA returns the dopamine value for given raw dopamine value,
+// applying the Gain, adaptation and Floor for dips.
+func (dp *DaDipParams) DA(da, adapt Float) Float {
+	if !dp.On || da >= 0 {
+		return da
+	}
+	return fmath.Max(dp.Gain*(1-adapt)*da, dp.Floor)
+}
+
+// Adapt returns the new adaptation value from the current one,
+// given the raw dopamine value on the trial.
+func (dp *DaDipParams) Adapt(adapt, da Float) Float {
+	if da < 0 {
+		adapt += dp.AdaptRate * -da * (1 - adapt)
+	}
+	adapt -= dp.AdaptDecay * adapt
+	return fmath.Clamp(adapt, 0, 1)
+}
+
+// SendDaDip sends the dopamine value to the SendTo layers, and to the
+// separate DaDip.SendTo layers if DaDip.On, with the dips only going
+// to the DaDip.SendTo layers and the bursts only going to SendTo,
+// along with the tonic dopamine level.
+func (ly *Layer) SendDaDip(da Float) {
+	if !ly.DaDip.On || len(ly.DaDip.SendTo) == 0 {
+		ly.SendDA(da)
+		return
+	}
+	ly.SendDA(fmath.Max(da, 0))
+	for _, lnm := range ly.DaDip.SendTo {
+		tly := ly.Network.LayerByName(lnm)
+		if tly != nil {
+			tly.NeuroMod.DA = fmath.Min(da, 0)
+			tly.NeuroMod.DATonic = ly.DaMod.Tonic
+		}
+	}
+}
+
+// UpdateDaDipAdapt updates the dip adaptation at the end of the trial,
+// from the raw dopamine activity of the layer.
+func (ly *Layer) UpdateDaDipAdapt() {
+	if !ly.DaDip.On || ly.DaDip.AdaptRate == 0 {
+		return
+	}
+	ly.DaDipAdapt = ly.DaDip.Adapt(ly.DaDipAdapt, ly.Neurons[0].Act)
+}
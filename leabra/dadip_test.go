@@ -0,0 +1,37 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestDaDip(t *testing.T) {
+	dp := &DaDipParams{}
+	dp.Defaults()
+	dp.On = true
+	dp.Gain = 2
+	dp.Floor = -0.8
+	dp.AdaptRate = 0.5
+	CmprFloats([]float32{float32(dp.DA(0.5, 0)), float32(dp.DA(-0.2, 0)), float32(dp.DA(-0.6, 0)), float32(dp.DA(-0.2, 0.5))}, []float32{0.5, -0.4, -0.8, -0.2}, "DaDip DA", t)
+	// adapt: 0 + 0.5 * 0.4 * 1 = 0.2, then decay: 0.2 - 0.1 * 0.2 = 0.18
+	CmprFloats([]float32{float32(dp.Adapt(0, -0.4)), float32(dp.Adapt(0.5, 0.3))}, []float32{0.18, 0.45}, "DaDip Adapt", t)
+
+	net := NewNetwork("DaDip")
+	da := net.AddClampDaLayer("DA")
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	neg := net.AddLayer2D("Negative", 2, 2, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	da.SendTo.Add(hid.Name)
+	da.DaDip.On = true
+	da.DaDip.SendTo.Add(neg.Name)
+	da.SendDaDip(-0.3)
+	CmprFloats([]float32{float32(hid.NeuroMod.DA), float32(neg.NeuroMod.DA)}, []float32{0, -0.3}, "DaDip SendTo dip", t)
+	da.SendDaDip(0.3)
+	CmprFloats([]float32{float32(hid.NeuroMod.DA), float32(neg.NeuroMod.DA)}, []float32{0.3, 0}, "DaDip SendTo burst", t)
+}
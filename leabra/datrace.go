@@ -0,0 +1,262 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/core"
+)
+
+//////// DaTrace
+
+// DaTrace is a set of externally supplied dopamine (DA) time courses,
+// one per trial, e.g., derived from fiber photometry recordings,
+// which can be injected as the output of a dopamine layer
+// (ClampDaLayer, RWDaLayer, TDDaLayer) in place of its computed value,
+// so that the components downstream of DA (e.g., Matrix, RWPred)
+// learn from the empirical DA signal.  See [DaInjectParams].
+type DaTrace struct {
+
+	// Names are the names of the trials, which can be used to select
+	// the trace for a trial (see TrialIndex).
+	Names []string
+
+	// Values are the DA time course samples for each trial, which are
+	// evenly spaced across the injection window (see DaInjectParams),
+	// and linearly interpolated between samples.  A trial with a single
+	// sample has a constant DA value across the window.
+	Values [][]Float
+}
+
+// NTrials returns the number of trials in the trace.
+func (dt *DaTrace) NTrials() int {
+	return len(dt.Values)
+}
+
+// Add adds a trial with the given name and DA time course samples.
+func (dt *DaTrace) Add(name string, vals ...Float) {
+	dt.Names = append(dt.Names, name)
+	dt.Values = append(dt.Values, vals)
+}
+
+// TrialIndex returns the index of the first trial with the given name,
+// or -1 if not found.
+func (dt *DaTrace) TrialIndex(name string) int {
+	for i, nm := range dt.Names {
+		if nm == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// Value returns the DA value for the given trial at the given
+// normalized position (0-1) within the time course, linearly
+// interpolated between samples.  Returns 0 if the trial is out of range
+// or has no samples.
+func (dt *DaTrace) Value(trial int, pos Float) Float {
+	if trial < 0 || trial >= len(dt.Values) {
+		return 0
+	}
+	vals := dt.Values[trial]
+	n := len(vals)
+	switch {
+	case n == 0:
+		return 0
+	case n == 1 || pos <= 0:
+		return vals[0]
+	case pos >= 1:
+		return vals[n-1]
+	}
+	fi := pos * Float(n-1)
+	i := int(fi)
+	if i >= n-1 {
+		return vals[n-1]
+	}
+	f := fi - Float(i)
+	return vals[i] + f*(vals[i+1]-vals[i])
+}
+
+// OpenCSV opens the trial time courses from the given comma-separated
+// values file (see ReadCSV).
+func (dt *DaTrace) OpenCSV(filename core.Filename) error {
+	f, err := os.Open(string(filename))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return dt.ReadCSV(f)
+}
+
+// ReadCSV reads the trial time courses from comma-separated values,
+// replacing any existing trials.  The first line is a header, and each
+// subsequent line is one trial, with the DA samples in order.
+// If the first header column is "Name" or "Trial", that column has the
+// trial names, and otherwise the trials are named by their index.
+// Trailing empty cells are ignored, so trials can have different numbers
+// of samples.
+func (dt *DaTrace) ReadCSV(r io.Reader) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	recs, err := cr.ReadAll()
+	if err != nil {
+		return err
+	}
+	dt.Names = nil
+	dt.Values = nil
+	if len(recs) == 0 {
+		return nil
+	}
+	hdr := recs[0]
+	hasName := len(hdr) > 0 && (strings.EqualFold(hdr[0], "Name") || strings.EqualFold(hdr[0], "Trial"))
+	for ri, rec := range recs[1:] {
+		name := strconv.Itoa(ri)
+		if hasName {
+			if len(rec) == 0 {
+				continue
+			}
+			name = rec[0]
+			rec = rec[1:]
+		}
+		for len(rec) > 0 && strings.TrimSpace(rec[len(rec)-1]) == "" {
+			rec = rec[:len(rec)-1]
+		}
+		vals := make([]Float, len(rec))
+		for ci, cs := range rec {
+			v, err := strconv.ParseFloat(strings.TrimSpace(cs), 64)
+			if err != nil {
+				return fmt.Errorf("DaTrace.ReadCSV: trial %s: %w", name, err)
+			}
+			vals[ci] = Float(v)
+		}
+		dt.Add(name, vals...)
+	}
+	return nil
+}
+
+//////// DaInject
+
+// DaInjectParams control the injection of an empirical [DaTrace] as the
+// activity of a dopamine layer (ClampDaLayer, RWDaLayer, TDDaLayer),
+// which is then sent as DA to its SendTo layers, instead of the DA
+// value computed by the layer.  The trace is set by SetDaTrace, and the
+// trial within the trace by SetDaTraceTrial, typically at the start of
+// each trial.
+type DaInjectParams struct {
+
+	// On injects the DaTrace DA values for the current trial,
+	// if the layer has a DaTrace and the trial is within it.
+	On bool
+
+	// StartCyc is the cycle within the trial at which the trace starts,
+	// prior to which DA is 0.
+	StartCyc int
+
+	// NCyc is the number of cycles spanned by the trace samples,
+	// after which DA is 0.  If 0, the trace extends to the end of the
+	// trial (4 quarters).
+	NCyc int
+
+	// Gain multiplies the trace values, e.g., to convert
+	// photometry units into the range of model DA values.
+	Gain Float `default:"1"`
+
+	// Offset is added to the trace values after Gain,
+	// e.g., to subtract a baseline.
+	Offset Float
+}
+
+func (di *DaInjectParams) Defaults() {
+	di.Gain = 1
+}
+
+func (di *DaInjectParams) Update() {
+}
+
+// Value returns the DA value from the given trace for the given trial
+// at the given cycle within the trial, with cycles per quarter cpq.
+func (di *DaInjectParams) Value(dt *DaTrace, trial, cyc, cpq int) Float {
+	ncyc := di.NCyc
+	if ncyc <= 0 {
+		ncyc = 4*cpq - di.StartCyc
+	}
+	c := cyc - di.StartCyc
+	if c < 0 || c >= ncyc {
+		return 0
+	}
+	pos := Float(0)
+	if ncyc > 1 {
+		pos = Float(c) / Float(ncyc-1)
+	}
+	return di.Gain*dt.Value(trial, pos) + di.Offset
+}
+
+// IsDaLayer returns true if the layer is a dopamine layer type that
+// sends its activity as DA.
+func (ly *Layer) IsDaLayer() bool {
+	return ly.Type == ClampDaLayer || ly.Type == RWDaLayer || ly.Type == TDDaLayer
+}
+
+// SetDaTrace sets the empirical DA trace to inject as the activity of
+// this dopamine layer, and turns DaInject.On.  The trial is set to 0.
+func (ly *Layer) SetDaTrace(dt *DaTrace) {
+	ly.DaTrace = dt
+	ly.DaTraceTrial = 0
+	ly.DaInject.On = true
+}
+
+// SetDaTraceTrial sets the trial within the DaTrace to inject.
+// A trial outside of the trace (e.g., -1) uses the DA computed
+// by the layer instead.
+func (ly *Layer) SetDaTraceTrial(trial int) {
+	ly.DaTraceTrial = trial
+}
+
+// DaInjectActive returns true if the layer is injecting the DaTrace.
+func (ly *Layer) DaInjectActive() bool {
+	return ly.DaInject.On && ly.DaTrace != nil && ly.DaTraceTrial >= 0 && ly.DaTraceTrial < ly.DaTrace.NTrials() && ly.IsDaLayer()
+}
+
+// ActFromDaTrace sets the activity of the layer from the DaTrace
+// for the current trial and cycle.
+func (ly *Layer) ActFromDaTrace(ctx *Context) {
+	da := ly.DaInject.Value(ly.DaTrace, ly.DaTraceTrial, ctx.Cycle, ctx.CycPerQtr)
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		nrn.Act = da
+		ly.Learn.AvgsFromAct(nrn)
+	}
+}
+
+// SetDaTraceTrial sets the trial within the DaTrace for all layers
+// that have one.  See Layer.SetDaTraceTrial.
+func (nt *Network) SetDaTraceTrial(trial int) {
+	for _, ly := range nt.Layers {
+		if ly.DaTrace != nil {
+			ly.SetDaTraceTrial(trial)
+		}
+	}
+}
+
+// SetDaTraceTrialName sets the trial within the DaTrace for all layers
+// that have one, to the trial with the given name, or -1 if not found,
+// in which case the computed DA is used.
+func (nt *Network) SetDaTraceTrialName(name string) {
+	for _, ly := range nt.Layers {
+		if ly.DaTrace != nil {
+			ly.SetDaTraceTrial(ly.DaTrace.TrialIndex(name))
+		}
+	}
+}
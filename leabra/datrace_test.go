@@ -0,0 +1,48 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDaTrace(t *testing.T) {
+	dt := &DaTrace{}
+	err := dt.ReadCSV(strings.NewReader("Trial,t0,t1,t2\nA,0,1,0\nB,-0.5,,\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dt.NTrials() != 2 || dt.TrialIndex("B") != 1 || len(dt.Values[1]) != 1 {
+		t.Fatalf("ReadCSV: %v %v", dt.Names, dt.Values)
+	}
+	CmprFloats([]float32{float32(dt.Value(0, 0.25)), float32(dt.Value(1, 0.7))}, []float32{0.5, -0.5}, "DaTrace Value", t)
+
+	net := NewNetwork("DaTrace")
+	_, _, da := net.AddRWLayers("", 2)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	da.SendTo.Add(hid.Name)
+	da.SetDaTrace(dt)
+	net.InitWeights()
+	ctx := NewContext()
+	net.SetDaTraceTrialName("A")
+	net.AlphaCycInit(true)
+	ctx.AlphaCycStart()
+	for cyc := 0; cyc < 50; cyc++ {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	// cycle 49 of 100 is just before the peak at the middle of the trial
+	CmprFloats([]float32{float32(da.Neurons[0].Act), float32(hid.NeuroMod.DA)}, []float32{0.989899, 0.989899}, "DaTrace DA", t)
+	net.SetDaTraceTrial(-1)
+	net.Cycle(ctx)
+	if hid.NeuroMod.DA != 0 {
+		t.Errorf("DaTrace: computed DA not used: %g", hid.NeuroMod.DA)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import "github.com/emer/leabra/v2/fmath"
+
+//////// Attention from Pulvinar prediction errors
+
+// AttnParams are the parameters for the attentional modulation of the
+// gain of other layers (typically SuperLayers) by the magnitude of the
+// prediction error in a Pulvinar (TRC) layer, i.e., the mean absolute
+// difference between its plus phase (outcome) and minus phase
+// (prediction) activations, as in predictive attention models where
+// surprising (poorly predicted) inputs attract attention.  At the end of
+// each trial, the prediction error of the Pulvinar layer is sent to its
+// SendAttn layers, where it multiplies the excitatory input of their units
+// by the gain 1 + Gain * PredErr (up to Max) on the next trial.
+// If several Pulvinar layers send to the same layer, the largest gain is used.
+type AttnParams struct {
+
+	// Gain is the multiplier on the prediction error for the increase in
+	// the gain of the SendAttn layers, i.e., gain = 1 + Gain * PredErr.
+	Gain Float `default:"1" min:"0"`
+
+	// Max is the maximum gain.
+	Max Float `default:"2" min:"1"`
+
+	// Tau is the time constant, in trials, for integrating the prediction
+	// error over trials, so that attention persists after a surprise.
+	// 1 = the error of the last trial only.
+	Tau Float `default:"1" min:"1"`
+
+	// Pools modulates the gain of each pool of the SendAttn layers by
+	// the prediction error of the corresponding pool of this layer,
+	// for topographic (e.g., spatial) attention, if both are 4D with
+	// the same number of pools.  Otherwise, all the units of the SendAttn
+	// layers are modulated by the prediction error of the whole layer.
+	Pools bool
+
+	// Dt is the rate = 1 / Tau.
+	Dt Float `display:"-" json:"-" xml:"-"`
+}
+
+func (ap *AttnParams) Defaults() {
+	ap.Gain = 1
+	ap.Max = 2
+	ap.Tau = 1
+	ap.Update()
+}
+
+func (ap *AttnParams) Update() {
+	ap.Dt = 1 / fmath.Max(ap.Tau, 1)
+}
+
+// AttnFromPredErr returns the attentional modulation of the gain,
+// above 1, for the given prediction error.
+func (ap *AttnParams) AttnFromPredErr(err Float) Float {
+	return fmath.Clamp(ap.Gain*err, 0, ap.Max-1)
+}
+
+// AddSendAttn adds given layer name(s) to the list of those whose gain
+// is modulated by the prediction error of this Pulvinar layer.
+func (ly *Layer) AddSendAttn(laynm ...string) {
+	ly.SendAttn.Add(laynm...)
+}
+
+// AttnGain returns the attentional gain on the excitatory input of
+// the units in the given pool (0 for the whole layer), which is 1
+// if the layer does not receive attention from Pulvinar layers.
+func (ly *Layer) AttnGain(pi int) Float {
+	return 1 + ly.Pools[pi].Attn
+}
+
+// PredErrFromActs computes the prediction error in each pool of a
+// Pulvinar layer, at the end of the plus phase, as the mean absolute
+// difference between the plus and minus phase activations, integrated
+// over trials with Attn.Tau.
+func (ly *Layer) PredErrFromActs() {
+	for pi := range ly.Pools {
+		pl := &ly.Pools[pi]
+		sum := Float(0)
+		n := 0
+		for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
+			nrn := &ly.Neurons[ni]
+			if nrn.IsOff() {
+				continue
+			}
+			sum += fmath.Abs(nrn.ActP - nrn.ActM)
+			n++
+		}
+		err := Float(0)
+		if n > 0 {
+			err = sum / Float(n)
+		}
+		pl.PredErr += ly.Attn.Dt * (err - pl.PredErr)
+	}
+}
+
+// SendAttn sends the attentional modulation of the gain of the SendAttn
+// layers of all the Pulvinar layers from their prediction errors, at the
+// end of the plus phase, to modulate their excitatory inputs on the next
+// trial.  It is called by Network.QuarterFinal.
+func (nt *Network) SendAttn(ctx *Context) {
+	if !ctx.PlusPhase {
+		return
+	}
+	var sends []*Layer
+	for _, ly := range nt.Layers {
+		if ly.Off || ly.Type != PulvinarLayer || len(ly.SendAttn) == 0 {
+			continue
+		}
+		ly.PredErrFromActs()
+		sends = append(sends, ly)
+		for _, lnm := range ly.SendAttn {
+			if tly := nt.LayerByName(lnm); tly != nil {
+				for pi := range tly.Pools {
+					tly.Pools[pi].Attn = 0
+				}
+			}
+		}
+	}
+	for _, ly := range sends {
+		for _, lnm := range ly.SendAttn {
+			tly := nt.LayerByName(lnm)
+			if tly == nil {
+				continue
+			}
+			pools := ly.Attn.Pools && ly.Is4D() && tly.Is4D() && len(ly.Pools) == len(tly.Pools)
+			for pi := range tly.Pools {
+				spi := 0
+				if pools {
+					spi = pi
+				}
+				pl := &tly.Pools[pi]
+				pl.Attn = fmath.Max(pl.Attn, ly.Attn.AttnFromPredErr(ly.Pools[spi].PredErr))
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestDeepAttn(t *testing.T) {
+	net := NewNetwork("Attn")
+	in := net.AddLayer4D("Input", 2, 1, 2, 2, InputLayer)
+	hid, _, hidp := net.AddDeep4D("Hidden", 2, 1, 2, 2)
+	hidp.Drivers.Add(in.Name)
+	hidp.AddSendAttn(hid.Name)
+	net.Build()
+	net.Defaults()
+	hidp.Attn.Gain = 2
+	hidp.Attn.Pools = true
+	net.InitWeights()
+	ctx := NewContext()
+
+	// prediction error of .25 in the first pool and .5 in the second
+	for ni := range hidp.Neurons {
+		nrn := &hidp.Neurons[ni]
+		nrn.ActM = 0.5
+		nrn.ActP = 0.75
+		if ni >= 4 {
+			nrn.ActP = 0
+		}
+	}
+	net.SendAttn(ctx)
+	if hid.Pools[1].Attn != 0 {
+		t.Error("attention sent outside of the plus phase")
+	}
+	ctx.PlusPhase = true
+	net.SendAttn(ctx)
+	CmprFloats([]float32{float32(hidp.Pools[1].PredErr), float32(hidp.Pools[2].PredErr), float32(hid.AttnGain(1)), float32(hid.AttnGain(2))},
+		[]float32{0.25, 0.5, 1.5, 2}, "Attn pools", t)
+	if v := hid.UnitValue("AttnGain", []int{1, 0, 0, 0}, 0); v != 2 {
+		t.Errorf("AttnGain unit var: %g", v)
+	}
+
+	for _, ni := range []int{0, 4} {
+		hid.Neurons[ni].GeRaw = 0.4
+		hid.Neurons[ni].Ge = 0
+	}
+	hid.GFromIncNeur(ctx)
+	if g1, g2 := hid.Neurons[0].Ge, hid.Neurons[4].Ge; !(g2 > g1 && g1 > 0) {
+		t.Errorf("Attn gain not applied to Ge: %g %g", g1, g2)
+	}
+
+	// layer-level modulation, capped at Max
+	hidp.Attn.Pools = false
+	hidp.Attn.Tau = 2
+	hidp.Attn.Update()
+	net.SendAttn(ctx)
+	CmprFloats([]float32{float32(hidp.Pools[0].PredErr), float32(hid.AttnGain(1)), float32(hid.AttnGain(2))},
+		[]float32{0.375, 1.75, 1.75}, "Attn layer", t)
+	hidp.Attn.Gain = 10
+	net.SendAttn(ctx)
+	if hid.AttnGain(1) != hidp.Attn.Max {
+		t.Errorf("Attn gain not capped at Max: %g", hid.AttnGain(1))
+	}
+	net.InitActs()
+	if hid.AttnGain(1) != 1 || hidp.Pools[0].PredErr != 0 {
+		t.Error("InitActs did not reset attention")
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import "fmt"
+
+//////// Leaky temporal context in CT layers
+
+// CtxtParams are the parameters for the integration of the temporal context
+// CtxtGe in CT layers, at the end of each burst (see CtxtFromGe).
+// By default, the new context fully replaces the prior one, so the context
+// only spans one alpha trial (or burst period).  With a lag, the context
+// is a leaky integration of the bursts over trials:
+// CtxtGe = lag * CtxtGe + (1 - lag) * new, so that it spans about
+// 1 / (1 - lag) bursts, as needed for sequence learning (e.g., serial recall).
+// The lag can be learned for each neuron (the CtxtLag neuron variable),
+// by a delta rule on the error of the neuron (ActP - ActM) with respect to
+// the lag, which is the difference between the prior and the new context
+// (CtxtDif), so that neurons develop a range of temporal context spans.
+// The learned lags are saved and loaded with the weights.
+type CtxtParams struct {
+
+	// Lag is the proportion of the prior context that is retained at each
+	// context update, 0 = full replacement by the new context.
+	// It is the initial value of the lag of each neuron if Learn.
+	Lag Float `default:"0" min:"0" max:"1"`
+
+	// Learn learns the lag of each neuron (CtxtLag), in DWt.
+	Learn bool
+
+	// Lrate is the learning rate for the lag.
+	Lrate Float `default:"0.01" min:"0"`
+
+	// Max is the maximum learned lag.
+	Max Float `default:"0.95" min:"0" max:"1"`
+}
+
+func (cp *CtxtParams) Defaults() {
+	cp.Lag = 0
+	cp.Lrate = 0.01
+	cp.Max = 0.95
+}
+
+func (cp *CtxtParams) Update() {
+}
+
+func (cp *CtxtParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "Lrate", "Max":
+		return cp.Learn
+	}
+	return true
+}
+
+// CtxtFromNew returns the new context value from the prior one
+// and the new context input, with the given lag.
+func (cp *CtxtParams) CtxtFromNew(prv, nw, lag Float) Float {
+	return lag*prv + (1-lag)*nw
+}
+
+// CtxtLag returns the effective context lag for the given neuron:
+// its learned CtxtLag if Ctxt.Learn, else Ctxt.Lag.
+func (ly *Layer) CtxtLag(nrn *Neuron) Float {
+	if ly.Ctxt.Learn {
+		return nrn.CtxtLag
+	}
+	return ly.Ctxt.Lag
+}
+
+// InitCtxtLag sets the learned context lag CtxtLag of the neurons
+// to the initial Ctxt.Lag.  Called by InitWeights.
+func (ly *Layer) InitCtxtLag() {
+	for ni := range ly.Neurons {
+		ly.Neurons[ni].CtxtLag = ly.Ctxt.Lag
+	}
+}
+
+// DWtCtxtLag updates the learned context lag CtxtLag of the neurons
+// of a CT layer from their error with respect to it (CtxtDLag), as
+// computed at the last context update, if Ctxt.Learn.  Called by DWt.
+func (ly *Layer) DWtCtxtLag() {
+	if ly.Type != CTLayer || !ly.Ctxt.Learn {
+		return
+	}
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		lag := nrn.CtxtLag + ly.Ctxt.Lrate*nrn.CtxtDLag
+		nrn.CtxtLag = min(max(lag, 0), ly.Ctxt.Max)
+		nrn.CtxtDLag = 0
+	}
+}
+
+// HasCtxtLag returns true if the layer is a CT layer learning its
+// context lags, in which case they are saved with the weights.
+func (ly *Layer) HasCtxtLag() bool {
+	return ly.Type == CTLayer && ly.Ctxt.Learn
+}
+
+// setCtxtLag sets the CtxtLag values of the neurons from the
+// given saved values, or to the initial Ctxt.Lag if nil.
+func (ly *Layer) setCtxtLag(vals []float32) error {
+	if vals == nil {
+		ly.InitCtxtLag()
+		return nil
+	}
+	if len(vals) != len(ly.Neurons) {
+		return fmt.Errorf("leabra.Layer.SetWeights: layer %s: number of CtxtLag values %d != number of neurons %d", ly.Name, len(vals), len(ly.Neurons))
+	}
+	for ni := range ly.Neurons {
+		ly.Neurons[ni].CtxtLag = Float(vals[ni])
+	}
+	return nil
+}
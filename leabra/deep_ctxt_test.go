@@ -0,0 +1,90 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestCtxtLag(t *testing.T) {
+	net := NewNetwork("CtxtLag")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid, ct, hidp := net.AddDeep2D("Hidden", 4, 4)
+	hidp.Drivers.Add(in.Name)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.Build()
+	net.Defaults()
+	ct.Ctxt.Lag = 0.5
+	net.InitWeights()
+	ctx := NewContext()
+	pats := [][]float32{
+		{1, 0, 0, 1, 0, 1, 1, 0, 1, 0, 0, 1, 0, 1, 1, 0},
+		{0, 1, 1, 0, 1, 0, 0, 1, 0, 1, 1, 0, 1, 0, 0, 1},
+	}
+	prv := make([]Float, len(ct.Neurons))
+	for trial := range 4 {
+		net.InitExt()
+		in.ApplyExt1D32(pats[trial%2])
+		net.AlphaCycle(ctx, true)
+		for ni := range ct.Neurons {
+			nrn := &ct.Neurons[ni]
+			nw := prv[ni] - nrn.CtxtDif
+			if want := 0.5*prv[ni] + 0.5*nw; math.Abs(float64(nrn.CtxtGe-want)) > 1e-5 {
+				t.Errorf("trial %d: CtxtGe[%d] = %g, want %g", trial, ni, nrn.CtxtGe, want)
+			}
+			prv[ni] = nrn.CtxtGe
+		}
+	}
+
+	ct.Ctxt.Learn = true
+	ct.Ctxt.Lrate = 0.1
+	net.InitWeights()
+	if ct.Neurons[0].CtxtLag != 0.5 {
+		t.Errorf("InitWeights should set CtxtLag to Lag: %g", ct.Neurons[0].CtxtLag)
+	}
+	for trial := range 10 {
+		net.InitExt()
+		in.ApplyExt1D32(pats[trial%2])
+		net.AlphaCycle(ctx, true)
+	}
+	nchg := 0
+	want := make([]Float, len(ct.Neurons))
+	for ni := range ct.Neurons {
+		lag := ct.Neurons[ni].CtxtLag
+		if lag < 0 || lag > ct.Ctxt.Max {
+			t.Errorf("CtxtLag[%d] = %g out of range", ni, lag)
+		}
+		if lag != 0.5 {
+			nchg++
+		}
+		want[ni] = lag
+	}
+	if nchg == 0 {
+		t.Error("CtxtLag not learned")
+	}
+	var jb, bb bytes.Buffer
+	if err := net.WriteWeightsJSON(&jb); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.WriteWeightsBinary(&bb); err != nil {
+		t.Fatal(err)
+	}
+	for _, rd := range []func() error{func() error { return net.ReadWeightsJSON(&jb) }, func() error { return net.ReadWeightsBinary(&bb) }} {
+		net.InitWeights()
+		if err := rd(); err != nil {
+			t.Fatal(err)
+		}
+		for ni := range ct.Neurons {
+			if math.Abs(float64(ct.Neurons[ni].CtxtLag-want[ni])) > 1e-6 {
+				t.Errorf("CtxtLag[%d] = %g, want %g", ni, ct.Neurons[ni].CtxtLag, want[ni])
+				break
+			}
+		}
+	}
+}
@@ -9,7 +9,7 @@ import (
 	"log"
 	"math"
 
-	"cogentcore.org/core/math32"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 // BurstParams determine how the 5IB Burst activation is computed from
@@ -20,19 +20,70 @@ type BurstParams struct {
 	BurstQtr Quarters
 
 	// Relative component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  This is the distance between the average and maximum activation values within layer (e.g., 0 = average, 1 = max).  Overall effective threshold is MAX of relative and absolute thresholds.
-	ThrRel float32 `max:"1" default:"0.1,0.2,0.5"`
+	ThrRel Float `max:"1" default:"0.1,0.2,0.5"`
 
 	// Absolute component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  Overall effective threshold is MAX of relative and absolute thresholds.
-	ThrAbs float32 `min:"0" max:"1" default:"0.1,0.2,0.5"`
+	ThrAbs Float `min:"0" max:"1" default:"0.1,0.2,0.5"`
+
+	// Trials is the period of the burst schedule across alpha-cycle trials: bursting in the BurstQtr quarters only occurs on one out of every Trials trials (at TrialOff), e.g., 2 = every other trial, for slower predictive learning timescales.  On the other trials, there is no Burst updating, no CtxtGe updating in CT layers, and no driver inputs to Pulvinar layers.  The Super, CT and Pulvinar layers of a deep circuit must all use the same schedule.
+	Trials int `default:"1" min:"1"`
+
+	// TrialOff is the trial within each period of Trials on which bursting occurs (0 to Trials-1), based on the Context AlphaCyc trial counter, to align the schedule with other processes over multiple trials.
+	TrialOff int `min:"0"`
+
+	// Prob is the probability of bursting on each trial of the schedule, for probabilistic bursting.  The random draw for each trial is shared by all the layers of the network (see Network.BurstRand), so that layers with the same Prob burst on the same trials.
+	Prob Float `default:"1" min:"0" max:"1"`
 }
 
 func (db *BurstParams) Defaults() {
 	db.BurstQtr.SetFlag(true, Q4)
 	db.ThrRel = 0.1
 	db.ThrAbs = 0.1
+	db.Trials = 1
+	db.Prob = 1
 }
 
 func (db *BurstParams) Update() {
+	db.Trials = max(db.Trials, 1)
+}
+
+func (db *BurstParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "TrialOff":
+		return db.Trials > 1
+	}
+	return true
+}
+
+// OnSchedule returns true if the given alpha-cycle trial is on the
+// periodic burst schedule of Trials and TrialOff (not including Prob).
+func (db *BurstParams) OnSchedule(trial int) bool {
+	if db.Trials <= 1 {
+		return true
+	}
+	return trial%db.Trials == db.TrialOff%db.Trials
+}
+
+// BurstTrial returns true if bursting occurs on the current alpha-cycle
+// trial, according to the Burst schedule parameters (Trials, TrialOff
+// and Prob), which is always the case for the default parameters.
+func (ly *Layer) BurstTrial(ctx *Context) bool {
+	trial := ctx.AlphaCyc()
+	if !ly.Burst.OnSchedule(trial) {
+		return false
+	}
+	if ly.Burst.Prob >= 1 {
+		return true
+	}
+	return ly.Network.BurstRand(trial) < ly.Burst.Prob
+}
+
+// BurstRand returns the random number (0-1) for the probabilistic
+// bursting on the given alpha-cycle trial (see BurstParams.Prob),
+// which is the same for all the layers, and determined by the
+// BurstSeed set from the network Rand in InitWeights.
+func (nt *Network) BurstRand(trial int) Float {
+	return Float(randStreamSeed(nt.BurstSeed, "Burst", trial)) / Float(math.MaxInt64)
 }
 
 ////////  Burst -- computed in CyclePost
@@ -42,9 +93,13 @@ func (ly *Layer) BurstPrv(ctx *Context) {
 	if !ly.Burst.BurstQtr.HasNext(ctx.Quarter) {
 		return
 	}
+	if ctx.Quarter < 3 && !ly.BurstTrial(ctx) {
+		return
+	}
 	// if will be updating next quarter, save just prior
 	// this logic works for all cases, but e.g., BurstPrv doesn't update
-	// until end of minus phase for Q4 BurstQtr
+	// until end of minus phase for Q4 BurstQtr.
+	// Wrapping around to Q1 of the next trial does not know its schedule.
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		nrn.BurstPrv = nrn.Burst
@@ -54,20 +109,20 @@ func (ly *Layer) BurstPrv(ctx *Context) {
 // BurstFromAct updates Burst layer 5IB bursting value from current Act
 // (superficial activation), subject to thresholding.
 func (ly *Layer) BurstFromAct(ctx *Context) {
-	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) {
+	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) || !ly.BurstTrial(ctx) {
 		return
 	}
 	lpl := &ly.Pools[0]
 	actMax := lpl.Inhib.Act.Max
 	actAvg := lpl.Inhib.Act.Avg
 	thr := actAvg + ly.Burst.ThrRel*(actMax-actAvg)
-	thr = math32.Max(thr, ly.Burst.ThrAbs)
+	thr = fmath.Max(thr, ly.Burst.ThrAbs)
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
 		}
-		burst := float32(0)
+		burst := Float(0)
 		if nrn.Act > thr {
 			burst = nrn.Act
 		}
@@ -90,7 +145,7 @@ func (ly *Layer) BurstAsAct(ctx *Context) {
 // This must be called at the end of the Burst quarter for this layer.
 // Satisfies the CtxtSender interface.
 func (ly *Layer) SendCtxtGe(ctx *Context) {
-	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) {
+	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) || !ly.BurstTrial(ctx) {
 		return
 	}
 	for ni := range ly.Neurons {
@@ -115,7 +170,12 @@ func (ly *Layer) SendCtxtGe(ctx *Context) {
 // CTGFromInc integrates new synaptic conductances from increments
 // sent during last SendGDelta.
 func (ly *Layer) CTGFromInc(ctx *Context) {
-	for ni := range ly.Neurons {
+	ly.ctGFromIncRange(0, len(ly.Neurons))
+}
+
+// ctGFromIncRange is CTGFromInc for the given range of neurons.
+func (ly *Layer) ctGFromIncRange(st, ed int) {
+	for ni := st; ni < ed; ni++ {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
@@ -127,18 +187,24 @@ func (ly *Layer) CTGFromInc(ctx *Context) {
 }
 
 // CtxtFromGe integrates new CtxtGe excitatory conductance from pathways,
-// and computes overall Ctxt value, only on Deep layers.
+// and computes overall Ctxt value, only on Deep layers, integrating the
+// prior context according to the Ctxt lag (see CtxtParams).
 // This must be called at the end of the DeepBurst quarter for this layer,
 // after SendCtxtGe.
 func (ly *Layer) CtxtFromGe(ctx *Context) {
 	if ly.Type != CTLayer {
 		return
 	}
-	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) {
+	if !ly.Burst.BurstQtr.HasFlag(ctx.Quarter) || !ly.BurstTrial(ctx) {
 		return
 	}
 	for ni := range ly.Neurons {
-		ly.Neurons[ni].CtxtGe = 0
+		nrn := &ly.Neurons[ni]
+		if ly.Ctxt.Learn { // error wrt the lag of the current context
+			nrn.CtxtDLag = (nrn.ActP - nrn.ActM) * nrn.CtxtDif
+		}
+		nrn.CtxtDif = nrn.CtxtGe // prior context, until the new is integrated
+		nrn.CtxtGe = 0
 	}
 	for _, pt := range ly.RecvPaths {
 		if pt.Off {
@@ -149,6 +215,12 @@ func (ly *Layer) CtxtFromGe(ctx *Context) {
 		}
 		pt.RecvCtxtGeInc()
 	}
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		prv, nw := nrn.CtxtDif, nrn.CtxtGe
+		nrn.CtxtGe = ly.Ctxt.CtxtFromNew(prv, nw, ly.CtxtLag(nrn))
+		nrn.CtxtDif = prv - nw
+	}
 }
 
 //////// Pulvinar
@@ -187,28 +259,28 @@ type PulvinarParams struct {
 	BurstQtr Quarters
 
 	// multiplier on driver input strength, multiplies activation of driver layer
-	DriveScale float32 `default:"0.3" min:"0.0"`
+	DriveScale Float `default:"0.3" min:"0.0"`
 
 	// Level of Max driver layer activation at which the predictive non-burst inputs are fully inhibited.  Computationally, it is essential that driver inputs inhibit effect of predictive non-driver (CTLayer) inputs, so that the plus phase is not always just the minus phase plus something extra (the error will never go to zero then).  When max driver act input exceeds this value, predictive non-driver inputs are fully suppressed.  If there is only weak burst input however, then the predictive inputs remain and this critically prevents the network from learning to turn activation off, which is difficult and severely degrades learning.
-	MaxInhib float32 `default:"0.6" min:"0.01"`
+	MaxInhib Float `default:"0.6" min:"0.01"`
 
 	// Do not treat the pools in this layer as topographically organized relative to driver inputs -- all drivers compress down to give same input to all pools
 	NoTopo bool
 
 	// proportion of average across driver pools that is combined with Max to provide some graded tie-breaker signal -- especially important for large pool downsampling, e.g., when doing NoTopo
-	AvgMix float32 `min:"0" max:"1"`
+	AvgMix Float `min:"0" max:"1"`
 
 	// Apply threshold to driver burst input for computing plus-phase activations -- above BinThr, then Act = BinOn, below = BinOff.  This is beneficial for layers with weaker graded activations, such as V1 or other perceptual inputs.
 	Binarize bool
 
 	// Threshold for binarizing in terms of sending Burst activation
-	BinThr float32
+	BinThr Float
 
 	// Resulting driver Ge value for units above threshold -- lower value around 0.3 or so seems best (DriveScale is NOT applied -- generally same range as that).
-	BinOn float32 `default:"0.3"`
+	BinOn Float `default:"0.3"`
 
 	// Resulting driver Ge value for units below threshold -- typically 0.
-	BinOff float32 `default:"0"`
+	BinOff Float `default:"0"`
 }
 
 func (tp *PulvinarParams) Update() {
@@ -234,7 +306,7 @@ func (tp *PulvinarParams) ShouldDisplay(field string) bool {
 
 // DriveGe returns effective excitatory conductance to use for given driver
 // input Burst activation
-func (tp *PulvinarParams) DriveGe(act float32) float32 {
+func (tp *PulvinarParams) DriveGe(act Float) Float {
 	if tp.Binarize {
 		if act >= tp.BinThr {
 			return tp.BinOn
@@ -247,7 +319,7 @@ func (tp *PulvinarParams) DriveGe(act float32) float32 {
 }
 
 // GeFromMaxAvg returns the drive Ge value as function of max and average
-func (tp *PulvinarParams) GeFromMaxAvg(max, avg float32) float32 {
+func (tp *PulvinarParams) GeFromMaxAvg(max, avg Float) Float {
 	deff := (1-tp.AvgMix)*max + tp.AvgMix*avg
 	return tp.DriveGe(deff)
 }
@@ -301,8 +373,8 @@ func (ly *Layer) SetDriverOffs() error {
 	return err
 }
 
-func DriveAct(dni int, dly *Layer, issuper bool) float32 {
-	act := float32(0)
+func DriveAct(dni int, dly *Layer, issuper bool) Float {
+	act := Float(0)
 	if issuper {
 		act = dly.Neurons[dni].Burst
 	} else {
@@ -317,7 +389,7 @@ func DriveAct(dni int, dly *Layer, issuper bool) float32 {
 
 // SetDriverNeuron sets the driver activation for given Neuron,
 // based on given Ge driving value (use DriveFromMaxAvg) from driver layer (Burst or Act)
-func (ly *Layer) SetDriverNeuron(tni int, drvGe, drvInhib float32) {
+func (ly *Layer) SetDriverNeuron(tni int, drvGe, drvInhib Float) {
 	if tni >= len(ly.Neurons) {
 		return
 	}
@@ -343,7 +415,7 @@ func (ly *Layer) SetDriverActs() {
 		}
 		issuper := dly.Type == SuperLayer
 		drvMax := dly.Pools[0].Inhib.Act.Max
-		drvInhib := math32.Min(1, drvMax/ly.Pulvinar.MaxInhib)
+		drvInhib := fmath.Min(1, drvMax/ly.Pulvinar.MaxInhib)
 
 		if dly.Is2D() {
 			if ly.Is2D() {
@@ -371,15 +443,15 @@ func (ly *Layer) SetDriverActs() {
 			dnun := duxn * duyn
 			if ly.Is2D() {
 				for dni := 0; dni < dnun; dni++ {
-					max := float32(0)
-					avg := float32(0)
+					max := Float(0)
+					avg := Float(0)
 					avgn := 0
 					for py := 0; py < dpyn; py++ {
 						for px := 0; px < dpxn; px++ {
 							pi := (py*dpxn + px)
 							pni := pi*dnun + dni
 							act := DriveAct(pni, dly, issuper)
-							max = math32.Max(max, act)
+							max = fmath.Max(max, act)
 							pmax := dly.Pools[1+pi].Inhib.Act.Max
 							if pmax > 0.5 {
 								avg += act
@@ -388,22 +460,22 @@ func (ly *Layer) SetDriverActs() {
 						}
 					}
 					if avgn > 0 {
-						avg /= float32(avgn)
+						avg /= Float(avgn)
 					}
 					tni := drv.Off + dni
 					ly.SetDriverNeuron(tni, ly.Pulvinar.GeFromMaxAvg(max, avg), drvInhib)
 				}
 			} else if ly.Pulvinar.NoTopo { // ly is 4D
 				for dni := 0; dni < dnun; dni++ {
-					max := float32(0)
-					avg := float32(0)
+					max := Float(0)
+					avg := Float(0)
 					avgn := 0
 					for py := 0; py < dpyn; py++ {
 						for px := 0; px < dpxn; px++ {
 							pi := (py*dpxn + px)
 							pni := pi*dnun + dni
 							act := DriveAct(pni, dly, issuper)
-							max = math32.Max(max, act)
+							max = fmath.Max(max, act)
 							pmax := dly.Pools[1+pi].Inhib.Act.Max
 							if pmax > 0.5 {
 								avg += act
@@ -412,7 +484,7 @@ func (ly *Layer) SetDriverActs() {
 						}
 					}
 					if avgn > 0 {
-						avg /= float32(avgn)
+						avg /= Float(avgn)
 					}
 					drvGe := ly.Pulvinar.GeFromMaxAvg(max, avg)
 					tni := drv.Off + dni
@@ -434,15 +506,15 @@ func (ly *Layer) SetDriverActs() {
 						edpx := int(math.Round(float64(px+1) * pxr))
 						pni := (py*pxn + px) * nun
 						for dni := 0; dni < dnun; dni++ {
-							max := float32(0)
-							avg := float32(0)
+							max := Float(0)
+							avg := Float(0)
 							avgn := 0
 							for dpy := sdpy; dpy < edpy; dpy++ {
 								for dpx := sdpx; dpx < edpx; dpx++ {
 									pi := (dpy*dpxn + dpx)
 									dpni := pi*dnun + dni
 									act := DriveAct(dpni, dly, issuper)
-									max = math32.Max(max, act)
+									max = fmath.Max(max, act)
 									pmax := dly.Pools[1+pi].Inhib.Act.Max
 									if pmax > 0.5 {
 										avg += act
@@ -451,7 +523,7 @@ func (ly *Layer) SetDriverActs() {
 								}
 							}
 							if avgn > 0 {
-								avg /= float32(avgn)
+								avg /= Float(avgn)
 							}
 							tni := pni + drv.Off + dni
 							ly.SetDriverNeuron(tni, ly.Pulvinar.GeFromMaxAvg(max, avg), drvInhib)
@@ -0,0 +1,63 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestBurstSchedule(t *testing.T) {
+	net := NewNetwork("Burst")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid, ct, hidp := net.AddDeep2D("Hidden", 4, 4)
+	hidp.Drivers.Add(in.Name)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.Build()
+	net.Defaults()
+	for _, ly := range []*Layer{hid, ct, hidp} {
+		ly.Burst.Trials = 2
+		ly.Burst.TrialOff = 1
+	}
+	net.InitWeights()
+	ctx := NewContext()
+	pat := []float32{1, 0, 0, 1, 0, 1, 1, 0, 1, 0, 0, 1, 0, 1, 1, 0}
+	prvCtxt := Float(0)
+	for trial := range 4 {
+		net.InitExt()
+		in.ApplyExt1D32(pat)
+		net.AlphaCycle(ctx, true)
+		burst, ctxt := Float(0), Float(0)
+		for ni := range hid.Neurons {
+			burst += hid.Neurons[ni].Burst
+			ctxt += ct.Neurons[ni].CtxtGe
+		}
+		// CtxtGe is maintained until the next burst
+		if on := trial%2 == 1; (burst > 0) != on || (ctxt != prvCtxt) != on {
+			t.Errorf("trial %d: burst on: %v, Burst: %g, CtxtGe: %g", trial, on, burst, ctxt)
+		}
+		prvCtxt = ctxt
+	}
+
+	hid.Burst.Trials = 1
+	hid.Burst.Prob = 0.5
+	ct.Burst.Trials = 1
+	ct.Burst.Prob = 0.5
+	n := 0
+	for trial := range 1000 {
+		ctx.AlphaCycTot = trial + 1
+		on := hid.BurstTrial(ctx)
+		if on != ct.BurstTrial(ctx) {
+			t.Errorf("trial %d: probabilistic bursting differs between layers", trial)
+		}
+		if on {
+			n++
+		}
+	}
+	if n < 400 || n > 600 {
+		t.Errorf("probabilistic bursting on %d out of 1000 trials", n)
+	}
+}
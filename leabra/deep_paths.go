@@ -4,10 +4,6 @@
 
 package leabra
 
-import (
-	"cogentcore.org/core/math32"
-)
-
 func (pt *Path) CTCtxtDefaults() {
 	if pt.FromSuper {
 		pt.Learn.Learn = false
@@ -18,16 +14,16 @@ func (pt *Path) CTCtxtDefaults() {
 
 // SendCtxtGe sends the full Burst activation from sending neuron index si,
 // to integrate CtxtGe excitatory conductance on receivers
-func (pt *Path) SendCtxtGe(si int, dburst float32) {
+func (pt *Path) SendCtxtGe(si int, dburst Float) {
 	scdb := dburst * pt.GScale
 	nc := pt.SConN[si]
 	st := pt.SConIndexSt[si]
-	syns := pt.Syns[st : st+nc]
-	scons := pt.SConIndex[st : st+nc]
-	for ci := range syns {
-		ri := scons[ci]
-		pt.CtxtGeInc[ri] += scdb * syns[ci].Wt
+	wts := pt.Syns.Wt[st : st+nc]
+	if pt.sconDense[si] {
+		axpy(pt.CtxtGeInc[pt.SConIndex[st]:], scdb, wts)
+		return
 	}
+	axpyIndex(pt.CtxtGeInc, pt.SConIndex[st:st+nc], scdb, wts)
 }
 
 // RecvCtxtGeInc increments the receiver's CtxtGe from that of all the pathways
@@ -43,53 +39,27 @@ func (pt *Path) RecvCtxtGeInc() {
 func (pt *Path) DWtCTCtxt() {
 	slay := pt.Send
 	issuper := pt.Send.Type == SuperLayer
-	rlay := pt.Recv
+	ra := &pt.recvAvgs
+	ra.gather(pt)
 	for si := range slay.Neurons {
-		sact := float32(0)
+		sact := Float(0)
 		if issuper {
 			sact = slay.Neurons[si].BurstPrv
 		} else {
 			sact = slay.Neurons[si].ActQ0
 		}
 		nc := int(pt.SConN[si])
+		if nc == 0 {
+			continue
+		}
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
-		for ci := range syns {
-			sy := &syns[ci]
-			ri := scons[ci]
-			rn := &rlay.Neurons[ri]
-			// following line should be ONLY diff: sact for *both* short and medium *sender*
-			// activations, which are first two args:
-			err, bcm := pt.Learn.CHLdWt(sact, sact, rn.AvgSLrn, rn.AvgM, rn.AvgL)
-
-			bcm *= pt.Learn.XCal.LongLrate(rn.AvgLLrn)
-			err *= pt.Learn.XCal.MLrn
-			dwt := bcm + err
-			norm := float32(1)
-			if pt.Learn.Norm.On {
-				norm = pt.Learn.Norm.NormFromAbsDWt(&sy.Norm, math32.Abs(dwt))
-			}
-			if pt.Learn.Momentum.On {
-				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&sy.Moment, dwt)
-			} else {
-				dwt *= norm
-			}
-			sy.DWt += pt.Learn.Lrate * dwt
-		}
-		// aggregate max DWtNorm over sending synapses
-		if pt.Learn.Norm.On {
-			maxNorm := float32(0)
-			for ci := range syns {
-				sy := &syns[ci]
-				if sy.Norm > maxNorm {
-					maxNorm = sy.Norm
-				}
-			}
-			for ci := range syns {
-				sy := &syns[ci]
-				sy.Norm = maxNorm
-			}
+		r0 := int(scons[0])
+		if pt.sconDense[si] {
+			scons = nil
 		}
+		// following line should be ONLY diff: sact for *both* short and medium *sender*
+		// activations, which are first two args:
+		pt.dwtXCal(sact, sact, 1, 1, ra, r0, scons, pt.Syns.DWt[st:st+nc], pt.Syns.Norm[st:st+nc], pt.Syns.Moment[st:st+nc])
 	}
 }
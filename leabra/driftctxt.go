@@ -0,0 +1,115 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+// DriftingContext generates context patterns that drift gradually across
+// trials, for hippocampus models of temporal context, where items learned
+// close together in time share more of their context than items learned
+// far apart.  Each context is a named stream, starting from a pattern in a
+// patgen.Vocab, and on each trial a proportion Rate of its active bits are
+// turned off and the same number of inactive bits turned on, so the number
+// of active bits stays constant.  Unlike patgen.AddVocabDrift, the state of
+// each stream carries over across the vocabulary items generated from it,
+// so that a later list (e.g., AC) continues drifting from the last trial of
+// an earlier one (e.g., AB).  Because the drift is based on the last trial,
+// the patterns must be presented sequentially in training (e.g., with
+// env.FixedTable Sequential), to preserve the temporal order.
+type DriftingContext struct {
+
+	// Rate is the proportion of active bits that are flipped on
+	// each trial, relative to the previous trial.  Fractional numbers
+	// of bits are carried over to subsequent trials.
+	Rate float32 `default:"0.1" min:"0" max:"1"`
+
+	// streams are the current states of the context streams, by name.
+	streams map[string]*driftStream
+}
+
+// driftStream is the state of one drifting context stream.
+type driftStream struct {
+
+	// cur is the current pattern.
+	cur *tensor.Float32
+
+	// started is true once the first pattern has been generated.
+	started bool
+
+	// rmdr is the remainder of fractional bits to flip.
+	rmdr float64
+}
+
+func (dc *DriftingContext) Defaults() {
+	dc.Rate = 0.1
+}
+
+// Reset removes all of the context streams.
+func (dc *DriftingContext) Reset() {
+	dc.streams = nil
+}
+
+// Start starts (or restarts) the named context stream from the given row of
+// the copyFrom item in the vocabulary.  The first pattern generated for the
+// stream is the starting pattern itself, with drift on subsequent trials.
+func (dc *DriftingContext) Start(mp patgen.Vocab, stream, copyFrom string, copyRow int) error {
+	cp, err := mp.ByName(copyFrom)
+	if err != nil {
+		return err
+	}
+	if copyRow < 0 || copyRow >= cp.DimSize(0) {
+		return fmt.Errorf("leabra.DriftingContext: row %d out of range for %s", copyRow, copyFrom)
+	}
+	if dc.streams == nil {
+		dc.streams = make(map[string]*driftStream)
+	}
+	cur := cp.SubSpace([]int{copyRow}).Clone().(*tensor.Float32)
+	dc.streams[stream] = &driftStream{cur: cur}
+	return nil
+}
+
+// Step advances the named context stream by one trial of drift,
+// returning the new current pattern, which must not be modified.
+func (dc *DriftingContext) Step(stream string) (*tensor.Float32, error) {
+	ds, ok := dc.streams[stream]
+	if !ok {
+		return nil, fmt.Errorf("leabra.DriftingContext: stream %s has not been started", stream)
+	}
+	if !ds.started {
+		ds.started = true
+		return ds.cur, nil
+	}
+	drift := float64(patgen.NOnInTensor(ds.cur)) * float64(dc.Rate)
+	nDrift := math.Round(drift + ds.rmdr)
+	ds.rmdr += drift - nDrift
+	if n := int(nDrift); n > 0 {
+		patgen.FlipBits(ds.cur, n, n, 1, 0)
+	}
+	return ds.cur, nil
+}
+
+// AddVocab adds a vocabulary item with the given name and number of rows,
+// with the successive patterns of the named context stream, continuing
+// from its current state.
+func (dc *DriftingContext) AddVocab(mp patgen.Vocab, name, stream string, rows int) (*tensor.Float32, error) {
+	ds, ok := dc.streams[stream]
+	if !ok {
+		return nil, fmt.Errorf("leabra.DriftingContext: stream %s has not been started", stream)
+	}
+	shp := append([]int{rows}, ds.cur.Shape().Sizes...)
+	tsr := tensor.NewFloat32(shp)
+	for i := range rows {
+		pat, _ := dc.Step(stream)
+		tsr.SubSpace([]int{i}).CopyFrom(pat)
+	}
+	mp[name] = tsr
+	return tsr, nil
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+func TestDriftingContext(t *testing.T) {
+	vocab := patgen.Vocab{}
+	patgen.AddVocabPermutedBinary(vocab, "ctxt", 1, 5, 4, 0.5, 0)
+	dc := &DriftingContext{Rate: 0.1}
+	if err := dc.Start(vocab, "s", "ctxt", 0); err != nil {
+		t.Fatal(err)
+	}
+	ab, err := dc.AddVocab(vocab, "ab", "s", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ac, _ := dc.AddVocab(vocab, "ac", "s", 5)
+	proto := vocab["ctxt"].SubSpace([]int{0})
+	diff := func(a, b tensor.Tensor) int {
+		n := 0
+		for i := range a.Len() {
+			if a.Float1D(i) != b.Float1D(i) {
+				n++
+			}
+		}
+		return n
+	}
+	// 10 active bits at rate 0.1 flip 1 on and 1 off per trial
+	if d := diff(ab.SubSpace([]int{0}), proto); d != 0 {
+		t.Errorf("DriftingContext: first row differs from start by %d", d)
+	}
+	if d := diff(ab.SubSpace([]int{1}), ab.SubSpace([]int{0})); d != 2 {
+		t.Errorf("DriftingContext: drift per trial %d, not 2", d)
+	}
+	if d := diff(ac.SubSpace([]int{0}), ab.SubSpace([]int{4})); d != 2 {
+		t.Errorf("DriftingContext: AC does not continue from AB: %d", d)
+	}
+	if n := patgen.NOnInTensor(ac.SubSpace([]int{4}).(*tensor.Float32)); n != 10 {
+		t.Errorf("DriftingContext: %d active bits, not 10", n)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+// DropoutParams are parameters for training-time dropout of units, which
+// silences a random subset of the units in the layer for each alpha
+// trial, as a regularizer that prevents the network from relying on any
+// specific set of units.  The dropped units are chosen in AlphaCycInit,
+// only when it is called for training (updtActAvg = true), so that
+// dropout is automatically disabled during testing, and they are flagged
+// with NeurDropout and have their activity held at 0 for the trial.
+// If Rescale, the activity sent by the remaining units is scaled up by
+// 1 / (1 - P) (inverted dropout), so that the expected net input to the
+// receiving layers is the same as without dropout.
+type DropoutParams struct {
+
+	// use dropout of units in this layer during training
+	On bool
+
+	// probability of dropping each unit for an alpha trial
+	P Float `default:"0.2" min:"0" max:"1"`
+
+	// scale the sending activity of the units that are not dropped by 1 / (1 - P), to preserve the expected net input to the receiving layers
+	Rescale bool `default:"true"`
+
+	// Active is true when units have been dropped for the current trial,
+	// i.e., On and the trial is a training trial.
+	Active bool `display:"-"`
+}
+
+func (dp *DropoutParams) Update() {
+}
+
+func (dp *DropoutParams) Defaults() {
+	dp.P = 0.2
+	dp.Rescale = true
+}
+
+func (dp *DropoutParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return dp.On
+	}
+}
+
+// SendScale returns the scaling factor for the activity sent by the
+// units that are not dropped: 1 / (1 - P) if Active and Rescale, else 1.
+func (dp *DropoutParams) SendScale() Float {
+	if !dp.Active || !dp.Rescale || dp.P >= 1 {
+		return 1
+	}
+	return 1 / (1 - dp.P)
+}
+
+// DropoutInit selects the units to drop for the coming alpha trial, if
+// Dropout.On and train is true, flagging them with NeurDropout and
+// zeroing their activity, and otherwise clears any dropout flags.
+// Called by AlphaCycInit, so that it is disabled during testing.
+func (ly *Layer) DropoutInit(train bool) {
+	dp := &ly.Dropout
+	dp.Active = dp.On && train && dp.P > 0
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		nrn.SetFlag(false, NeurDropout)
+		if !dp.Active || nrn.IsOff() {
+			continue
+		}
+		if ly.Rand.Float32() < float32(dp.P) {
+			nrn.SetFlag(true, NeurDropout)
+			nrn.Act = 0
+		}
+	}
+}
+
+// NDropped returns the number of units dropped for the current trial.
+func (ly *Layer) NDropped() int {
+	n := 0
+	for ni := range ly.Neurons {
+		if ly.Neurons[ni].HasFlag(NeurDropout) {
+			n++
+		}
+	}
+	return n
+}
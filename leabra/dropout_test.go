@@ -0,0 +1,78 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestDropout(t *testing.T) {
+	net := NewNetwork("Dropout")
+	in := net.AddLayer2D("Input", 10, 10, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	inpat := tensor.NewFloat32([]int{10, 10})
+	for i := range inpat.Len() {
+		inpat.SetFloat1D(i, 1)
+	}
+	// trial runs one trial and returns the mean hidden GeRaw
+	trial := func(train bool) float64 {
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt(inpat)
+		net.AlphaCycInit(train)
+		ctx.AlphaCycStart()
+		for range 20 {
+			net.Cycle(ctx)
+			ctx.CycleInc()
+		}
+		var sum float64
+		for ni := range hid.Neurons {
+			sum += float64(hid.Neurons[ni].GeRaw)
+		}
+		return sum / float64(len(hid.Neurons))
+	}
+	base := trial(true)
+
+	in.Dropout.On = true
+	in.Dropout.P = 0.5
+	ge := trial(true)
+	nd := in.NDropped()
+	if nd < 30 || nd > 70 {
+		t.Errorf("number of dropped units out of 100 with P = .5: %d", nd)
+	}
+	for ni := range in.Neurons {
+		nrn := &in.Neurons[ni]
+		if nrn.HasFlag(NeurDropout) && nrn.Act != 0 {
+			t.Errorf("dropped unit %d has Act: %g", ni, nrn.Act)
+		}
+	}
+	if r := ge / base; r < 0.75 || r > 1.25 {
+		t.Errorf("rescaled net input should be close to that without dropout: %g vs. %g", ge, base)
+	}
+
+	in.Dropout.Rescale = false
+	ge = trial(true)
+	if r := ge / base; r < 0.3 || r > 0.7 {
+		t.Errorf("net input without rescaling should be about half of that without dropout: %g vs. %g", ge, base)
+	}
+
+	ge = trial(false)
+	if nd := in.NDropped(); nd != 0 || in.Dropout.Active {
+		t.Errorf("dropout should be disabled in testing: %d dropped", nd)
+	}
+	if math.Abs(ge-base) > 1e-4 {
+		t.Errorf("net input in testing should be the same as without dropout: %g vs. %g", ge, base)
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+// EmbedNorms are the ways of normalizing the values of an external
+// embedding vector (e.g., word embeddings or CNN features), which can have
+// any range, into the 0-1 range of activations, for EmbedParams.
+type EmbedNorms int32 //enums:enum
+
+const (
+	// EmbedMinMax rescales the values of each vector so that its minimum
+	// is 0 and its maximum is 1.
+	EmbedMinMax EmbedNorms = iota
+
+	// EmbedPosNeg represents each value with two units, one for the
+	// positive part in the first half of the layer and one for the negative
+	// part in the second half, scaled by the maximum absolute value of the
+	// vector, so that 0 is no activity and the sign is preserved.
+	// The layer must have twice as many units as the (fitted) vector.
+	EmbedPosNeg
+
+	// EmbedSigmoid passes each value times Gain through a sigmoid,
+	// so that 0 maps to 0.5.
+	EmbedSigmoid
+
+	// EmbedClip multiplies each value by Gain and clips the result to
+	// the 0-1 range, e.g., for non-negative (ReLU) CNN features.
+	EmbedClip
+)
+
+// EmbedFits are the ways of fitting an external embedding vector
+// to the number of units in a layer, for EmbedParams.
+type EmbedFits int32 //enums:enum
+
+const (
+	// EmbedExact requires the vector to have exactly as many values
+	// as the layer has units (or half as many for EmbedPosNeg).
+	EmbedExact EmbedFits = iota
+
+	// EmbedPad pads the vector with zeros if it is shorter than the layer,
+	// or truncates it if it is longer.
+	EmbedPad
+
+	// EmbedResample averages blocks of consecutive values if the vector
+	// is longer than the layer, or repeats values (nearest neighbor)
+	// if it is shorter.
+	EmbedResample
+)
+
+// EmbedParams are the parameters for adapting external embedding vectors,
+// such as word embeddings or CNN features, to the activations of a layer,
+// so that they can serve as fixed, precomputed representations for
+// a leabra model (see Layer.ApplyEmbedding and Embeddings.AddColumn).
+// The vector is first fit to the size of the layer, and then normalized
+// into the 0-1 range.  The layer should be an InputLayer with hard clamping
+// (the default), so that its activity is set directly from the embedding,
+// and it has no receiving pathways to learn.
+type EmbedParams struct {
+
+	// how to normalize the vector values into the 0-1 range of activations
+	Norm EmbedNorms
+
+	// how to fit the vector to the number of units in the layer
+	Fit EmbedFits
+
+	// multiplier on the vector values for the EmbedSigmoid and EmbedClip norms
+	Gain float32 `default:"1"`
+}
+
+func (ep *EmbedParams) Defaults() {
+	ep.Norm = EmbedMinMax
+	ep.Fit = EmbedExact
+	ep.Gain = 1
+}
+
+// Adapt returns the given embedding vector adapted to n units,
+// according to the Fit and Norm parameters.  Returns an error if the
+// vector cannot be fit to n units.
+func (ep *EmbedParams) Adapt(vec []float32, n int) ([]float32, error) {
+	nv := n
+	if ep.Norm == EmbedPosNeg {
+		if n%2 != 0 {
+			return nil, fmt.Errorf("leabra.EmbedParams: EmbedPosNeg requires an even number of units, not %d", n)
+		}
+		nv = n / 2
+	}
+	fv, err := ep.fit(vec, nv)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float32, n)
+	switch ep.Norm {
+	case EmbedMinMax:
+		mn, mx := float32(math.Inf(1)), float32(math.Inf(-1))
+		for _, v := range fv {
+			mn = min(mn, v)
+			mx = max(mx, v)
+		}
+		if mx > mn {
+			for i, v := range fv {
+				out[i] = (v - mn) / (mx - mn)
+			}
+		}
+	case EmbedPosNeg:
+		var mx float32
+		for _, v := range fv {
+			mx = max(mx, float32(math.Abs(float64(v))))
+		}
+		if mx > 0 {
+			for i, v := range fv {
+				if v > 0 {
+					out[i] = v / mx
+				} else {
+					out[nv+i] = -v / mx
+				}
+			}
+		}
+	case EmbedSigmoid:
+		for i, v := range fv {
+			out[i] = 1 / (1 + float32(math.Exp(float64(-ep.Gain*v))))
+		}
+	case EmbedClip:
+		for i, v := range fv {
+			out[i] = min(max(ep.Gain*v, 0), 1)
+		}
+	}
+	return out, nil
+}
+
+// fit returns the given vector fit to n values according to Fit.
+func (ep *EmbedParams) fit(vec []float32, n int) ([]float32, error) {
+	m := len(vec)
+	if m == n {
+		return vec, nil
+	}
+	out := make([]float32, n)
+	switch ep.Fit {
+	case EmbedPad:
+		copy(out, vec)
+	case EmbedResample:
+		if m == 0 {
+			break
+		}
+		for i := range out {
+			st := i * m / n
+			ed := max((i+1)*m/n, st+1)
+			var sum float32
+			for _, v := range vec[st:ed] {
+				sum += v
+			}
+			out[i] = sum / float32(ed-st)
+		}
+	default:
+		return nil, fmt.Errorf("leabra.EmbedParams: embedding has %d values, but %d are needed for EmbedExact", m, n)
+	}
+	return out, nil
+}
+
+// ApplyEmbedding applies the given external embedding vector (e.g., a word
+// embedding or CNN features) as the external input to the layer, adapted
+// to the number of neurons in the layer by the given EmbedParams.
+// The layer should be an InputLayer (see EmbedParams).  Returns an error
+// if the vector cannot be fit to the layer.
+func (ly *Layer) ApplyEmbedding(vec []float32, ep *EmbedParams) error {
+	ext, err := ep.Adapt(vec, len(ly.Neurons))
+	if err != nil {
+		return fmt.Errorf("leabra.ApplyEmbedding: layer %s: %w", ly.Name, err)
+	}
+	ly.ApplyExt1D32(ext)
+	return nil
+}
+
+// Embeddings is a set of named external embedding vectors of the same
+// dimensionality, such as word embeddings (word2vec, GloVe) or the
+// features of a CNN for a set of images, to use as fixed input
+// representations (see EmbedParams).
+type Embeddings struct {
+
+	// Names are the names of the items, e.g., the words.
+	Names []string
+
+	// Vectors are the embedding vectors of the items, in the same order as Names.
+	Vectors [][]float32
+
+	// index of each name
+	index map[string]int
+}
+
+// Dim returns the dimensionality of the vectors, or 0 if there are none.
+func (em *Embeddings) Dim() int {
+	if len(em.Vectors) == 0 {
+		return 0
+	}
+	return len(em.Vectors[0])
+}
+
+// Add adds the given named vector, which must have the same
+// dimensionality as any existing ones, replacing any existing
+// vector with the same name.
+func (em *Embeddings) Add(name string, vec []float32) error {
+	if d := em.Dim(); d > 0 && len(vec) != d {
+		return fmt.Errorf("leabra.Embeddings: vector for %q has %d values, but the others have %d", name, len(vec), d)
+	}
+	em.initIndex()
+	if i, ok := em.index[name]; ok {
+		em.Vectors[i] = vec
+		return nil
+	}
+	em.index[name] = len(em.Names)
+	em.Names = append(em.Names, name)
+	em.Vectors = append(em.Vectors, vec)
+	return nil
+}
+
+// initIndex builds the index of the names if needed, e.g.,
+// after the Names have been set directly.
+func (em *Embeddings) initIndex() {
+	if em.index != nil && len(em.index) == len(em.Names) {
+		return
+	}
+	em.index = make(map[string]int, len(em.Names))
+	for i, nm := range em.Names {
+		em.index[nm] = i
+	}
+}
+
+// Vector returns the vector for the given name, and false if not found.
+func (em *Embeddings) Vector(name string) ([]float32, bool) {
+	em.initIndex()
+	i, ok := em.index[name]
+	if !ok {
+		return nil, false
+	}
+	return em.Vectors[i], true
+}
+
+// ReadEmbeddings reads embeddings in the standard text format used by
+// word2vec and GloVe: one item per line, with its name followed by the
+// space-separated values of its vector.  An initial word2vec header line
+// with the number of items and the dimensionality is skipped.
+func ReadEmbeddings(r io.Reader) (*Embeddings, error) {
+	em := &Embeddings{}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for ln := 1; sc.Scan(); ln++ {
+		fs := strings.Fields(sc.Text())
+		if len(fs) == 0 {
+			continue
+		}
+		if ln == 1 && len(fs) == 2 {
+			_, err0 := strconv.Atoi(fs[0])
+			_, err1 := strconv.Atoi(fs[1])
+			if err0 == nil && err1 == nil {
+				continue
+			}
+		}
+		vec := make([]float32, len(fs)-1)
+		for i, f := range fs[1:] {
+			v, err := strconv.ParseFloat(f, 32)
+			if err != nil {
+				return nil, fmt.Errorf("leabra.ReadEmbeddings: line %d: %w", ln, err)
+			}
+			vec[i] = float32(v)
+		}
+		if err := em.Add(fs[0], vec); err != nil {
+			return nil, fmt.Errorf("leabra.ReadEmbeddings: line %d: %w", ln, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("leabra.ReadEmbeddings: %w", err)
+	}
+	return em, nil
+}
+
+// OpenEmbeddings opens embeddings from the given file in the text format
+// read by ReadEmbeddings, which is decompressed if it ends in .gz.
+func OpenEmbeddings(filename core.Filename) (*Embeddings, error) {
+	fp, err := os.Open(string(filename))
+	if err != nil {
+		return nil, errors.Log(err)
+	}
+	defer fp.Close()
+	var r io.Reader = fp
+	if strings.HasSuffix(string(filename), ".gz") {
+		gz, err := gzip.NewReader(fp)
+		if err != nil {
+			return nil, errors.Log(err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return ReadEmbeddings(r)
+}
+
+// EmbeddingsFromTable returns the embeddings in the given columns of
+// the table, with the item names in the nameCol string column and the
+// vectors in the vecCol tensor column, e.g., for CNN features saved
+// for a set of images.
+func EmbeddingsFromTable(dt *table.Table, nameCol, vecCol string) (*Embeddings, error) {
+	if _, err := dt.ColumnByName(nameCol); err != nil {
+		return nil, fmt.Errorf("leabra.EmbeddingsFromTable: %w", err)
+	}
+	vc, err := dt.ColumnByName(vecCol)
+	if err != nil {
+		return nil, fmt.Errorf("leabra.EmbeddingsFromTable: %w", err)
+	}
+	em := &Embeddings{}
+	for row := range dt.Rows {
+		cell := vc.SubSpace([]int{row})
+		vec := make([]float32, cell.Len())
+		for i := range vec {
+			vec[i] = float32(cell.Float1D(i))
+		}
+		if err := em.Add(dt.StringValue(nameCol, row), vec); err != nil {
+			return nil, fmt.Errorf("leabra.EmbeddingsFromTable: %w", err)
+		}
+	}
+	return em, nil
+}
+
+// AddColumn adds a float32 tensor column named col to the given table with
+// cells of the given shape (e.g., that of the input layer), set to the
+// embedding vector of the item named in the nameCol column of each row,
+// adapted to the cell size by the given EmbedParams.  This precomputes the
+// fixed input patterns for an env that presents the rows of the table
+// (e.g., env.FixedTable), so that they are applied to the layer of the
+// same name as the column like any other input.  Returns an error listing
+// any item names without an embedding, whose cells are left at 0.
+func (em *Embeddings) AddColumn(dt *table.Table, nameCol, col string, shape []int, ep *EmbedParams) error {
+	if _, err := dt.ColumnByName(nameCol); err != nil {
+		return fmt.Errorf("leabra.Embeddings.AddColumn: %w", err)
+	}
+	tc := dt.AddFloat32TensorColumn(col, shape)
+	n := 1
+	for _, sz := range shape {
+		n *= sz
+	}
+	var missing []string
+	for row := range dt.Rows {
+		name := dt.StringValue(nameCol, row)
+		vec, ok := em.Vector(name)
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		ext, err := ep.Adapt(vec, n)
+		if err != nil {
+			return fmt.Errorf("leabra.Embeddings.AddColumn: %q: %w", name, err)
+		}
+		copy(tc.Values[row*n:(row+1)*n], ext)
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("leabra.Embeddings.AddColumn: no embedding for: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestEmbed(t *testing.T) {
+	em, err := ReadEmbeddings(strings.NewReader("3 4\ncat 1 -1 0.5 0\ndog -2 2 0 1\nfish 0 0 0 4\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(em.Names) != 3 || em.Dim() != 4 {
+		t.Fatalf("got %d embeddings of dim %d, want 3 of dim 4", len(em.Names), em.Dim())
+	}
+	ep := &EmbedParams{}
+	ep.Defaults()
+	cat, _ := em.Vector("cat")
+	ext, err := ep.Adapt(cat, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []float32{1, 0, 0.75, 0.5}; !slices.Equal(ext, want) {
+		t.Errorf("MinMax: got %v, want %v", ext, want)
+	}
+	if _, err := ep.Adapt(cat, 6); err == nil {
+		t.Error("EmbedExact: expected size mismatch error")
+	}
+	ep.Norm = EmbedPosNeg
+	ext, _ = ep.Adapt(cat, 8)
+	if want := []float32{1, 0, 0.5, 0, 0, 1, 0, 0}; !slices.Equal(ext, want) {
+		t.Errorf("PosNeg: got %v, want %v", ext, want)
+	}
+	ep.Norm = EmbedClip
+	ep.Fit = EmbedResample
+	fish, _ := em.Vector("fish")
+	ext, _ = ep.Adapt(fish, 2)
+	if want := []float32{0, 1}; !slices.Equal(ext, want) {
+		t.Errorf("Resample down: got %v, want %v", ext, want)
+	}
+	ep.Gain = 0.25
+	ext, _ = ep.Adapt(fish, 8)
+	if want := []float32{0, 0, 0, 0, 0, 0, 1, 1}; !slices.Equal(ext, want) {
+		t.Errorf("Resample up: got %v, want %v", ext, want)
+	}
+
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.SetNumRows(2)
+	dt.SetString("Name", 0, "dog")
+	dt.SetString("Name", 1, "cat")
+	ep.Defaults()
+	if err := em.AddColumn(dt, "Name", "Input", []int{2, 2}, ep); err != nil {
+		t.Fatal(err)
+	}
+	net := NewNetwork("Embed")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	if err := net.ApplyTableRow(dt, 1, nil); err != nil {
+		t.Fatal(err)
+	}
+	for ni, want := range []Float{1, 0, 0.75, 0.5} {
+		if got := in.Neurons[ni].Ext; got != want {
+			t.Errorf("table row Ext[%d]: got %v, want %v", ni, got, want)
+		}
+	}
+	net.InitExt()
+	if err := in.ApplyEmbedding(fish, ep); err != nil {
+		t.Fatal(err)
+	}
+	if got := in.Neurons[3].Ext; got != 1 {
+		t.Errorf("ApplyEmbedding Ext[3]: got %v, want 1", got)
+	}
+	dt.SetString("Name", 1, "bird")
+	if err := em.AddColumn(dt, "Name", "Input2", []int{2, 2}, ep); err == nil || !strings.Contains(err.Error(), "bird") {
+		t.Errorf("expected missing embedding error for bird, got %v", err)
+	}
+}
@@ -49,6 +49,307 @@ func (i *ActNoiseType) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "ActNoiseType")
 }
 
+var _BreakAggsValues = []BreakAggs{0, 1, 2, 3}
+
+// BreakAggsN is the highest valid value for type BreakAggs, plus one.
+const BreakAggsN BreakAggs = 4
+
+var _BreakAggsValueMap = map[string]BreakAggs{`BreakAvg`: 0, `BreakMax`: 1, `BreakMin`: 2, `BreakSum`: 3}
+
+var _BreakAggsDescMap = map[BreakAggs]string{0: `BreakAvg is the average.`, 1: `BreakMax is the maximum.`, 2: `BreakMin is the minimum.`, 3: `BreakSum is the sum.`}
+
+var _BreakAggsMap = map[BreakAggs]string{0: `BreakAvg`, 1: `BreakMax`, 2: `BreakMin`, 3: `BreakSum`}
+
+// String returns the string representation of this BreakAggs value.
+func (i BreakAggs) String() string { return enums.String(i, _BreakAggsMap) }
+
+// SetString sets the BreakAggs value from its string representation,
+// and returns an error if the string is invalid.
+func (i *BreakAggs) SetString(s string) error {
+	return enums.SetString(i, s, _BreakAggsValueMap, "BreakAggs")
+}
+
+// Int64 returns the BreakAggs value as an int64.
+func (i BreakAggs) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the BreakAggs value from an int64.
+func (i *BreakAggs) SetInt64(in int64) { *i = BreakAggs(in) }
+
+// Desc returns the description of the BreakAggs value.
+func (i BreakAggs) Desc() string { return enums.Desc(i, _BreakAggsDescMap) }
+
+// BreakAggsValues returns all possible values for the type BreakAggs.
+func BreakAggsValues() []BreakAggs { return _BreakAggsValues }
+
+// Values returns all possible values for the type BreakAggs.
+func (i BreakAggs) Values() []enums.Enum { return enums.Values(_BreakAggsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i BreakAggs) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *BreakAggs) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "BreakAggs")
+}
+
+var _EmbedFitsValues = []EmbedFits{0, 1, 2}
+
+// EmbedFitsN is the highest valid value for type EmbedFits, plus one.
+const EmbedFitsN EmbedFits = 3
+
+var _EmbedFitsValueMap = map[string]EmbedFits{`EmbedExact`: 0, `EmbedPad`: 1, `EmbedResample`: 2}
+
+var _EmbedFitsDescMap = map[EmbedFits]string{0: `EmbedExact requires the vector to have exactly as many values as the layer has units (or half as many for EmbedPosNeg).`, 1: `EmbedPad pads the vector with zeros if it is shorter than the layer, or truncates it if it is longer.`, 2: `EmbedResample averages blocks of consecutive values if the vector is longer than the layer, or repeats values (nearest neighbor) if it is shorter.`}
+
+var _EmbedFitsMap = map[EmbedFits]string{0: `EmbedExact`, 1: `EmbedPad`, 2: `EmbedResample`}
+
+// String returns the string representation of this EmbedFits value.
+func (i EmbedFits) String() string { return enums.String(i, _EmbedFitsMap) }
+
+// SetString sets the EmbedFits value from its string representation,
+// and returns an error if the string is invalid.
+func (i *EmbedFits) SetString(s string) error {
+	return enums.SetString(i, s, _EmbedFitsValueMap, "EmbedFits")
+}
+
+// Int64 returns the EmbedFits value as an int64.
+func (i EmbedFits) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the EmbedFits value from an int64.
+func (i *EmbedFits) SetInt64(in int64) { *i = EmbedFits(in) }
+
+// Desc returns the description of the EmbedFits value.
+func (i EmbedFits) Desc() string { return enums.Desc(i, _EmbedFitsDescMap) }
+
+// EmbedFitsValues returns all possible values for the type EmbedFits.
+func EmbedFitsValues() []EmbedFits { return _EmbedFitsValues }
+
+// Values returns all possible values for the type EmbedFits.
+func (i EmbedFits) Values() []enums.Enum { return enums.Values(_EmbedFitsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i EmbedFits) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *EmbedFits) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "EmbedFits")
+}
+
+var _EmbedNormsValues = []EmbedNorms{0, 1, 2, 3}
+
+// EmbedNormsN is the highest valid value for type EmbedNorms, plus one.
+const EmbedNormsN EmbedNorms = 4
+
+var _EmbedNormsValueMap = map[string]EmbedNorms{`EmbedMinMax`: 0, `EmbedPosNeg`: 1, `EmbedSigmoid`: 2, `EmbedClip`: 3}
+
+var _EmbedNormsDescMap = map[EmbedNorms]string{0: `EmbedMinMax rescales the values of each vector so that its minimum is 0 and its maximum is 1.`, 1: `EmbedPosNeg represents each value with two units, one for the positive part in the first half of the layer and one for the negative part in the second half, scaled by the maximum absolute value of the vector, so that 0 is no activity and the sign is preserved. The layer must have twice as many units as the (fitted) vector.`, 2: `EmbedSigmoid passes each value times Gain through a sigmoid, so that 0 maps to 0.5.`, 3: `EmbedClip multiplies each value by Gain and clips the result to the 0-1 range, e.g., for non-negative (ReLU) CNN features.`}
+
+var _EmbedNormsMap = map[EmbedNorms]string{0: `EmbedMinMax`, 1: `EmbedPosNeg`, 2: `EmbedSigmoid`, 3: `EmbedClip`}
+
+// String returns the string representation of this EmbedNorms value.
+func (i EmbedNorms) String() string { return enums.String(i, _EmbedNormsMap) }
+
+// SetString sets the EmbedNorms value from its string representation,
+// and returns an error if the string is invalid.
+func (i *EmbedNorms) SetString(s string) error {
+	return enums.SetString(i, s, _EmbedNormsValueMap, "EmbedNorms")
+}
+
+// Int64 returns the EmbedNorms value as an int64.
+func (i EmbedNorms) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the EmbedNorms value from an int64.
+func (i *EmbedNorms) SetInt64(in int64) { *i = EmbedNorms(in) }
+
+// Desc returns the description of the EmbedNorms value.
+func (i EmbedNorms) Desc() string { return enums.Desc(i, _EmbedNormsDescMap) }
+
+// EmbedNormsValues returns all possible values for the type EmbedNorms.
+func EmbedNormsValues() []EmbedNorms { return _EmbedNormsValues }
+
+// Values returns all possible values for the type EmbedNorms.
+func (i EmbedNorms) Values() []enums.Enum { return enums.Values(_EmbedNormsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i EmbedNorms) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *EmbedNorms) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "EmbedNorms")
+}
+
+var _FiniteLevelsValues = []FiniteLevels{0, 1, 2}
+
+// FiniteLevelsN is the highest valid value for type FiniteLevels, plus one.
+const FiniteLevelsN FiniteLevels = 3
+
+var _FiniteLevelsValueMap = map[string]FiniteLevels{`FiniteOff`: 0, `FiniteNeurons`: 1, `FiniteAll`: 2}
+
+var _FiniteLevelsDescMap = map[FiniteLevels]string{0: `FiniteOff does not check.`, 1: `FiniteNeurons checks the neuron variables (NeuronVars) of all layers, including the layer neuromodulators.`, 2: `FiniteAll checks the neuron variables, and the synapse variables (SynapseVars) of all pathways.`}
+
+var _FiniteLevelsMap = map[FiniteLevels]string{0: `FiniteOff`, 1: `FiniteNeurons`, 2: `FiniteAll`}
+
+// String returns the string representation of this FiniteLevels value.
+func (i FiniteLevels) String() string { return enums.String(i, _FiniteLevelsMap) }
+
+// SetString sets the FiniteLevels value from its string representation,
+// and returns an error if the string is invalid.
+func (i *FiniteLevels) SetString(s string) error {
+	return enums.SetString(i, s, _FiniteLevelsValueMap, "FiniteLevels")
+}
+
+// Int64 returns the FiniteLevels value as an int64.
+func (i FiniteLevels) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the FiniteLevels value from an int64.
+func (i *FiniteLevels) SetInt64(in int64) { *i = FiniteLevels(in) }
+
+// Desc returns the description of the FiniteLevels value.
+func (i FiniteLevels) Desc() string { return enums.Desc(i, _FiniteLevelsDescMap) }
+
+// FiniteLevelsValues returns all possible values for the type FiniteLevels.
+func FiniteLevelsValues() []FiniteLevels { return _FiniteLevelsValues }
+
+// Values returns all possible values for the type FiniteLevels.
+func (i FiniteLevels) Values() []enums.Enum { return enums.Values(_FiniteLevelsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i FiniteLevels) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *FiniteLevels) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "FiniteLevels")
+}
+
+var _LogFormatsValues = []LogFormats{0, 1}
+
+// LogFormatsN is the highest valid value for type LogFormats, plus one.
+const LogFormatsN LogFormats = 2
+
+var _LogFormatsValueMap = map[string]LogFormats{`LogTSV`: 0, `LogParquet`: 1}
+
+var _LogFormatsDescMap = map[LogFormats]string{0: `LogTSV writes tab-separated values (.tsv) files, with elog.Logs.SetLogFile.`, 1: `LogParquet writes compressed Apache Parquet (.parquet) files, with a ParquetWriter.`}
+
+var _LogFormatsMap = map[LogFormats]string{0: `LogTSV`, 1: `LogParquet`}
+
+// String returns the string representation of this LogFormats value.
+func (i LogFormats) String() string { return enums.String(i, _LogFormatsMap) }
+
+// SetString sets the LogFormats value from its string representation,
+// and returns an error if the string is invalid.
+func (i *LogFormats) SetString(s string) error {
+	return enums.SetString(i, s, _LogFormatsValueMap, "LogFormats")
+}
+
+// Int64 returns the LogFormats value as an int64.
+func (i LogFormats) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the LogFormats value from an int64.
+func (i *LogFormats) SetInt64(in int64) { *i = LogFormats(in) }
+
+// Desc returns the description of the LogFormats value.
+func (i LogFormats) Desc() string { return enums.Desc(i, _LogFormatsDescMap) }
+
+// LogFormatsValues returns all possible values for the type LogFormats.
+func LogFormatsValues() []LogFormats { return _LogFormatsValues }
+
+// Values returns all possible values for the type LogFormats.
+func (i LogFormats) Values() []enums.Enum { return enums.Values(_LogFormatsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i LogFormats) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *LogFormats) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "LogFormats")
+}
+
+var _LrateSchedsValues = []LrateScheds{0, 1, 2, 3}
+
+// LrateSchedsN is the highest valid value for type LrateScheds, plus one.
+const LrateSchedsN LrateScheds = 4
+
+var _LrateSchedsValueMap = map[string]LrateScheds{`LrateConstant`: 0, `LrateStep`: 1, `LrateExp`: 2, `LrateCosine`: 3}
+
+var _LrateSchedsDescMap = map[LrateScheds]string{0: `LrateConstant keeps the learning rate at its initial value (after any Warmup).`, 1: `LrateStep multiplies the learning rate by Factor at each of the Steps epochs.`, 2: `LrateExp multiplies the learning rate by Factor every epoch after the Warmup.`, 3: `LrateCosine decays the learning rate from its initial value to Min times that value over the epochs from the end of the Warmup to Epochs, following half a cosine cycle.`}
+
+var _LrateSchedsMap = map[LrateScheds]string{0: `LrateConstant`, 1: `LrateStep`, 2: `LrateExp`, 3: `LrateCosine`}
+
+// String returns the string representation of this LrateScheds value.
+func (i LrateScheds) String() string { return enums.String(i, _LrateSchedsMap) }
+
+// SetString sets the LrateScheds value from its string representation,
+// and returns an error if the string is invalid.
+func (i *LrateScheds) SetString(s string) error {
+	return enums.SetString(i, s, _LrateSchedsValueMap, "LrateScheds")
+}
+
+// Int64 returns the LrateScheds value as an int64.
+func (i LrateScheds) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the LrateScheds value from an int64.
+func (i *LrateScheds) SetInt64(in int64) { *i = LrateScheds(in) }
+
+// Desc returns the description of the LrateScheds value.
+func (i LrateScheds) Desc() string { return enums.Desc(i, _LrateSchedsDescMap) }
+
+// LrateSchedsValues returns all possible values for the type LrateScheds.
+func LrateSchedsValues() []LrateScheds { return _LrateSchedsValues }
+
+// Values returns all possible values for the type LrateScheds.
+func (i LrateScheds) Values() []enums.Enum { return enums.Values(_LrateSchedsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i LrateScheds) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *LrateScheds) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "LrateScheds")
+}
+
+var _PavlovSchedulesValues = []PavlovSchedules{0, 1, 2}
+
+// PavlovSchedulesN is the highest valid value for type PavlovSchedules, plus one.
+const PavlovSchedulesN PavlovSchedules = 3
+
+var _PavlovSchedulesValueMap = map[string]PavlovSchedules{`PavlovAcquisition`: 0, `PavlovExtinction`: 1, `PavlovBlocking`: 2}
+
+var _PavlovSchedulesDescMap = map[PavlovSchedules]string{0: `PavlovAcquisition is AcqTrials of A+, followed by a test of A.`, 1: `PavlovExtinction is AcqTrials of A+, followed by ExtTrials of A-, and a test of A.`, 2: `PavlovBlocking is AcqTrials of A+, followed by BlockTrials each of AB+ and CD+ (alternating), and tests of B and D, where learning about B is blocked by the prediction of the reward by A, relative to the control D, which is learned together with the novel C.`}
+
+var _PavlovSchedulesMap = map[PavlovSchedules]string{0: `PavlovAcquisition`, 1: `PavlovExtinction`, 2: `PavlovBlocking`}
+
+// String returns the string representation of this PavlovSchedules value.
+func (i PavlovSchedules) String() string { return enums.String(i, _PavlovSchedulesMap) }
+
+// SetString sets the PavlovSchedules value from its string representation,
+// and returns an error if the string is invalid.
+func (i *PavlovSchedules) SetString(s string) error {
+	return enums.SetString(i, s, _PavlovSchedulesValueMap, "PavlovSchedules")
+}
+
+// Int64 returns the PavlovSchedules value as an int64.
+func (i PavlovSchedules) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the PavlovSchedules value from an int64.
+func (i *PavlovSchedules) SetInt64(in int64) { *i = PavlovSchedules(in) }
+
+// Desc returns the description of the PavlovSchedules value.
+func (i PavlovSchedules) Desc() string { return enums.Desc(i, _PavlovSchedulesDescMap) }
+
+// PavlovSchedulesValues returns all possible values for the type PavlovSchedules.
+func PavlovSchedulesValues() []PavlovSchedules { return _PavlovSchedulesValues }
+
+// Values returns all possible values for the type PavlovSchedules.
+func (i PavlovSchedules) Values() []enums.Enum { return enums.Values(_PavlovSchedulesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i PavlovSchedules) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *PavlovSchedules) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "PavlovSchedules")
+}
+
 var _QuartersValues = []Quarters{0, 1, 2, 3}
 
 // QuartersN is the highest valid value for type Quarters, plus one.
@@ -106,16 +407,16 @@ func (i Quarters) MarshalText() ([]byte, error) { return []byte(i.String()), nil
 // UnmarshalText implements the [encoding.TextUnmarshaler] interface.
 func (i *Quarters) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Quarters") }
 
-var _LayerTypesValues = []LayerTypes{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18}
+var _LayerTypesValues = []LayerTypes{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
 
 // LayerTypesN is the highest valid value for type LayerTypes, plus one.
-const LayerTypesN LayerTypes = 19
+const LayerTypesN LayerTypes = 21
 
-var _LayerTypesValueMap = map[string]LayerTypes{`SuperLayer`: 0, `InputLayer`: 1, `TargetLayer`: 2, `CompareLayer`: 3, `CTLayer`: 4, `PulvinarLayer`: 5, `TRNLayer`: 6, `ClampDaLayer`: 7, `RWPredLayer`: 8, `RWDaLayer`: 9, `TDPredLayer`: 10, `TDIntegLayer`: 11, `TDDaLayer`: 12, `MatrixLayer`: 13, `GPeLayer`: 14, `GPiThalLayer`: 15, `CINLayer`: 16, `PFCLayer`: 17, `PFCDeepLayer`: 18}
+var _LayerTypesValueMap = map[string]LayerTypes{`SuperLayer`: 0, `InputLayer`: 1, `TargetLayer`: 2, `CompareLayer`: 3, `CTLayer`: 4, `PulvinarLayer`: 5, `TRNLayer`: 6, `ClampDaLayer`: 7, `RWPredLayer`: 8, `RWDaLayer`: 9, `TDPredLayer`: 10, `TDIntegLayer`: 11, `TDDaLayer`: 12, `MatrixLayer`: 13, `GPeLayer`: 14, `GPiThalLayer`: 15, `CINLayer`: 16, `PFCLayer`: 17, `PFCDeepLayer`: 18, `NoveltyLayer`: 19, `ValueLayer`: 20}
 
-var _LayerTypesDescMap = map[LayerTypes]string{0: `Super is a superficial cortical layer (lamina 2-3-4) which does not receive direct input or targets. In more generic models, it should be used as a Hidden layer, and maps onto the Hidden type in LayerTypes.`, 1: `Input is a layer that receives direct external input in its Ext inputs. Biologically, it can be a primary sensory layer, or a thalamic layer.`, 2: `Target is a layer that receives direct external target inputs used for driving plus-phase learning. Simple target layers are generally not used in more biological models, which instead use predictive learning via Pulvinar or related mechanisms.`, 3: `Compare is a layer that receives external comparison inputs, which drive statistics but do NOT drive activation or learning directly. It is rarely used in axon.`, 4: `CT are layer 6 corticothalamic projecting neurons, which drive &#34;top down&#34; predictions in Pulvinar layers. They maintain information over time via stronger NMDA channels and use maintained prior state information to generate predictions about current states forming on Super layers that then drive PT (5IB) bursting activity, which are the plus-phase drivers of Pulvinar activity.`, 5: `Pulvinar are thalamic relay cell neurons in the higher-order Pulvinar nucleus of the thalamus, and functionally isomorphic neurons in the MD thalamus, and potentially other areas. These cells alternately reflect predictions driven by CT pathways, and actual outcomes driven by 5IB Burst activity from corresponding PT or Super layer neurons that provide strong driving inputs.`, 6: `TRNLayer is thalamic reticular nucleus layer for inhibitory competition within the thalamus.`, 7: `ClampDaLayer is an Input layer that just sends its activity as the dopamine signal.`, 8: `RWPredLayer computes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). Activity is computed as linear function of excitatory conductance (which can be negative -- there are no constraints). Use with [RWPath] which does simple delta-rule learning on minus-plus.`, 9: `RWDaLayer computes a dopamine (DA) signal based on a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). It computes difference between r(t) and [RWPredLayer] values. r(t) is accessed directly from a Rew layer -- if no external input then no DA is computed -- critical for effective use of RW only for PV cases. RWPred prediction is also accessed directly from Rew layer to avoid any issues.`, 10: `TDPredLayer is the temporal differences reward prediction layer. It represents estimated value V(t) in the minus phase, and computes estimated V(t+1) based on its learned weights in plus phase. Use [TDPredPath] for DA modulated learning.`, 11: `TDIntegLayer is the temporal differences reward integration layer. It represents estimated value V(t) in the minus phase, and estimated V(t+1) + r(t) in the plus phase. It computes r(t) from (typically fixed) weights from a reward layer, and directly accesses values from [TDPredLayer].`, 12: `TDDaLayer computes a dopamine (DA) signal as the temporal difference (TD) between the [TDIntegLayer[] activations in the minus and plus phase.`, 13: `MatrixLayer represents the dorsal matrisome MSN&#39;s that are the main Go / NoGo gating units in BG driving updating of PFC WM in PBWM. D1R = Go, D2R = NoGo, and outer 4D Pool X dimension determines GateTypes per MaintN (Maint on the left up to MaintN, Out on the right after)`, 14: `GPeLayer is a Globus pallidus external layer, a key region of the basal ganglia. It does not require any additional mechanisms beyond the SuperLayer.`, 15: `GPiThalLayer represents the combined Winner-Take-All dynamic of GPi (SNr) and Thalamus. It is the final arbiter of gating in the BG, weighing Go (direct) and NoGo (indirect) inputs from MatrixLayers (indirectly via GPe layer in case of NoGo). Use 4D structure for this so it matches 4D structure in Matrix layers`, 16: `CINLayer (cholinergic interneuron) reads reward signals from named source layer(s) and sends the Max absolute value of that activity as the positively rectified non-prediction-discounted reward signal computed by CINs, and sent as an acetylcholine (ACh) signal. To handle positive-only reward signals, need to include both a reward prediction and reward outcome layer.`, 17: `PFCLayer is a prefrontal cortex layer, either superficial or output. See [PFCDeepLayer] for the deep maintenance layer.`, 18: `PFCDeepLayer is a prefrontal cortex deep maintenance layer.`}
+var _LayerTypesDescMap = map[LayerTypes]string{0: `Super is a superficial cortical layer (lamina 2-3-4) which does not receive direct input or targets. In more generic models, it should be used as a Hidden layer, and maps onto the Hidden type in LayerTypes.`, 1: `Input is a layer that receives direct external input in its Ext inputs. Biologically, it can be a primary sensory layer, or a thalamic layer.`, 2: `Target is a layer that receives direct external target inputs used for driving plus-phase learning. Simple target layers are generally not used in more biological models, which instead use predictive learning via Pulvinar or related mechanisms.`, 3: `Compare is a layer that receives external comparison inputs, which drive statistics but do NOT drive activation or learning directly. It is rarely used in axon.`, 4: `CT are layer 6 corticothalamic projecting neurons, which drive &#34;top down&#34; predictions in Pulvinar layers. They maintain information over time via stronger NMDA channels and use maintained prior state information to generate predictions about current states forming on Super layers that then drive PT (5IB) bursting activity, which are the plus-phase drivers of Pulvinar activity.`, 5: `Pulvinar are thalamic relay cell neurons in the higher-order Pulvinar nucleus of the thalamus, and functionally isomorphic neurons in the MD thalamus, and potentially other areas. These cells alternately reflect predictions driven by CT pathways, and actual outcomes driven by 5IB Burst activity from corresponding PT or Super layer neurons that provide strong driving inputs.`, 6: `TRNLayer is thalamic reticular nucleus layer for inhibitory competition within the thalamus.`, 7: `ClampDaLayer is an Input layer that just sends its activity as the dopamine signal.`, 8: `RWPredLayer computes reward prediction for a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). Activity is computed as linear function of excitatory conductance (which can be negative -- there are no constraints). Use with [RWPath] which does simple delta-rule learning on minus-plus.`, 9: `RWDaLayer computes a dopamine (DA) signal based on a simple Rescorla-Wagner learning dynamic (i.e., PV learning in the PVLV framework). It computes difference between r(t) and [RWPredLayer] values. r(t) is accessed directly from a Rew layer -- if no external input then no DA is computed -- critical for effective use of RW only for PV cases. RWPred prediction is also accessed directly from Rew layer to avoid any issues.`, 10: `TDPredLayer is the temporal differences reward prediction layer. It represents estimated value V(t) in the minus phase, and computes estimated V(t+1) based on its learned weights in plus phase. Use [TDPredPath] for DA modulated learning.`, 11: `TDIntegLayer is the temporal differences reward integration layer. It represents estimated value V(t) in the minus phase, and estimated V(t+1) + r(t) in the plus phase. It computes r(t) from (typically fixed) weights from a reward layer, and directly accesses values from [TDPredLayer].`, 12: `TDDaLayer computes a dopamine (DA) signal as the temporal difference (TD) between the [TDIntegLayer[] activations in the minus and plus phase.`, 13: `MatrixLayer represents the dorsal matrisome MSN&#39;s that are the main Go / NoGo gating units in BG driving updating of PFC WM in PBWM. D1R = Go, D2R = NoGo, and outer 4D Pool X dimension determines GateTypes per MaintN (Maint on the left up to MaintN, Out on the right after)`, 14: `GPeLayer is a Globus pallidus external layer, a key region of the basal ganglia. It does not require any additional mechanisms beyond the SuperLayer.`, 15: `GPiThalLayer represents the combined Winner-Take-All dynamic of GPi (SNr) and Thalamus. It is the final arbiter of gating in the BG, weighing Go (direct) and NoGo (indirect) inputs from MatrixLayers (indirectly via GPe layer in case of NoGo). Use 4D structure for this so it matches 4D structure in Matrix layers`, 16: `CINLayer (cholinergic interneuron) reads reward signals from named source layer(s) and sends the Max absolute value of that activity as the positively rectified non-prediction-discounted reward signal computed by CINs, and sent as an acetylcholine (ACh) signal. To handle positive-only reward signals, need to include both a reward prediction and reward outcome layer.`, 17: `PFCLayer is a prefrontal cortex layer, either superficial or output. See [PFCDeepLayer] for the deep maintenance layer.`, 18: `PFCDeepLayer is a prefrontal cortex deep maintenance layer.`, 19: `NoveltyLayer computes a novelty signal from the mismatch between a hippocampal input layer (e.g., ECin) and its reconstruction driven by recall (e.g., ECout), and sends it as ACh and / or DA to its SendTo layers. See [NoveltyParams].`, 20: `ValueLayer learns an estimate of the state value (OFC-like) or effort cost (ACC-like) from the dopamine it receives, typically the TD error, via [ValuePath] pathways, and sends the estimate to its SendTo Matrix layers to bias Go vs. NoGo gating. See [ValueParams].`}
 
-var _LayerTypesMap = map[LayerTypes]string{0: `SuperLayer`, 1: `InputLayer`, 2: `TargetLayer`, 3: `CompareLayer`, 4: `CTLayer`, 5: `PulvinarLayer`, 6: `TRNLayer`, 7: `ClampDaLayer`, 8: `RWPredLayer`, 9: `RWDaLayer`, 10: `TDPredLayer`, 11: `TDIntegLayer`, 12: `TDDaLayer`, 13: `MatrixLayer`, 14: `GPeLayer`, 15: `GPiThalLayer`, 16: `CINLayer`, 17: `PFCLayer`, 18: `PFCDeepLayer`}
+var _LayerTypesMap = map[LayerTypes]string{0: `SuperLayer`, 1: `InputLayer`, 2: `TargetLayer`, 3: `CompareLayer`, 4: `CTLayer`, 5: `PulvinarLayer`, 6: `TRNLayer`, 7: `ClampDaLayer`, 8: `RWPredLayer`, 9: `RWDaLayer`, 10: `TDPredLayer`, 11: `TDIntegLayer`, 12: `TDDaLayer`, 13: `MatrixLayer`, 14: `GPeLayer`, 15: `GPiThalLayer`, 16: `CINLayer`, 17: `PFCLayer`, 18: `PFCDeepLayer`, 19: `NoveltyLayer`, 20: `ValueLayer`}
 
 // String returns the string representation of this LayerTypes value.
 func (i LayerTypes) String() string { return enums.String(i, _LayerTypesMap) }
@@ -149,6 +450,47 @@ func (i *LayerTypes) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "LayerTypes")
 }
 
+var _DaCompsValues = []DaComps{0, 1, 2, 3}
+
+// DaCompsN is the highest valid value for type DaComps, plus one.
+const DaCompsN DaComps = 4
+
+var _DaCompsValueMap = map[string]DaComps{`DaPhasic`: 0, `DaTonic`: 1, `DaTotal`: 2, `DaNoComp`: 3}
+
+var _DaCompsDescMap = map[DaComps]string{0: `DaPhasic is the phasic dopamine, NeuroMod.DA, reflecting the reward prediction error.`, 1: `DaTonic is the tonic dopamine level, NeuroMod.DATonic.`, 2: `DaTotal is the sum of the phasic and tonic dopamine.`, 3: `DaNoComp is no dopamine, i.e., no modulation.`}
+
+var _DaCompsMap = map[DaComps]string{0: `DaPhasic`, 1: `DaTonic`, 2: `DaTotal`, 3: `DaNoComp`}
+
+// String returns the string representation of this DaComps value.
+func (i DaComps) String() string { return enums.String(i, _DaCompsMap) }
+
+// SetString sets the DaComps value from its string representation,
+// and returns an error if the string is invalid.
+func (i *DaComps) SetString(s string) error {
+	return enums.SetString(i, s, _DaCompsValueMap, "DaComps")
+}
+
+// Int64 returns the DaComps value as an int64.
+func (i DaComps) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the DaComps value from an int64.
+func (i *DaComps) SetInt64(in int64) { *i = DaComps(in) }
+
+// Desc returns the description of the DaComps value.
+func (i DaComps) Desc() string { return enums.Desc(i, _DaCompsDescMap) }
+
+// DaCompsValues returns all possible values for the type DaComps.
+func DaCompsValues() []DaComps { return _DaCompsValues }
+
+// Values returns all possible values for the type DaComps.
+func (i DaComps) Values() []enums.Enum { return enums.Values(_DaCompsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i DaComps) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *DaComps) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "DaComps") }
+
 var _DaReceptorsValues = []DaReceptors{0, 1}
 
 // DaReceptorsN is the highest valid value for type DaReceptors, plus one.
@@ -192,6 +534,92 @@ func (i *DaReceptors) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "DaReceptors")
 }
 
+var _RetentionTypesValues = []RetentionTypes{0, 1, 2}
+
+// RetentionTypesN is the highest valid value for type RetentionTypes, plus one.
+const RetentionTypesN RetentionTypes = 3
+
+var _RetentionTypesValueMap = map[string]RetentionTypes{`RetainNoInput`: 0, `RetainNoise`: 1, `RetainInterfere`: 2}
+
+var _RetentionTypesDescMap = map[RetentionTypes]string{0: `RetainNoInput runs the delay trials with no external input, so that only the spontaneous activity of the network drives any learning.`, 1: `RetainNoise runs the delay trials with random binary noise patterns as the input and target patterns, with PctAct active units.`, 2: `RetainInterfere runs the delay trials on the rows of an interfering list of patterns, i.e., retroactive interference from new learning.`}
+
+var _RetentionTypesMap = map[RetentionTypes]string{0: `RetainNoInput`, 1: `RetainNoise`, 2: `RetainInterfere`}
+
+// String returns the string representation of this RetentionTypes value.
+func (i RetentionTypes) String() string { return enums.String(i, _RetentionTypesMap) }
+
+// SetString sets the RetentionTypes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *RetentionTypes) SetString(s string) error {
+	return enums.SetString(i, s, _RetentionTypesValueMap, "RetentionTypes")
+}
+
+// Int64 returns the RetentionTypes value as an int64.
+func (i RetentionTypes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the RetentionTypes value from an int64.
+func (i *RetentionTypes) SetInt64(in int64) { *i = RetentionTypes(in) }
+
+// Desc returns the description of the RetentionTypes value.
+func (i RetentionTypes) Desc() string { return enums.Desc(i, _RetentionTypesDescMap) }
+
+// RetentionTypesValues returns all possible values for the type RetentionTypes.
+func RetentionTypesValues() []RetentionTypes { return _RetentionTypesValues }
+
+// Values returns all possible values for the type RetentionTypes.
+func (i RetentionTypes) Values() []enums.Enum { return enums.Values(_RetentionTypesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i RetentionTypes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *RetentionTypes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "RetentionTypes")
+}
+
+var _StepGrainsValues = []StepGrains{0, 1, 2, 3, 4, 5, 6}
+
+// StepGrainsN is the highest valid value for type StepGrains, plus one.
+const StepGrainsN StepGrains = 7
+
+var _StepGrainsValueMap = map[string]StepGrains{`StepCycle`: 0, `StepQuarter`: 1, `StepPhase`: 2, `StepAlphaCycle`: 3, `StepTrial`: 4, `StepEpoch`: 5, `StepRun`: 6}
+
+var _StepGrainsDescMap = map[StepGrains]string{0: `StepCycle steps one cycle.`, 1: `StepQuarter steps to the end of the current quarter (Context.CycPerQtr cycles).`, 2: `StepPhase steps to the end of the current minus or plus phase, which ends at the Stepper PlusStart cycle, or at the end of the alpha cycle, respectively.`, 3: `StepAlphaCycle steps to the end of the current alpha cycle, i.e., the end of the Cycle loop.`, 4: `StepTrial steps to the end of the current trial.`, 5: `StepEpoch steps to the end of the current epoch.`, 6: `StepRun steps to the end of the current run.`}
+
+var _StepGrainsMap = map[StepGrains]string{0: `StepCycle`, 1: `StepQuarter`, 2: `StepPhase`, 3: `StepAlphaCycle`, 4: `StepTrial`, 5: `StepEpoch`, 6: `StepRun`}
+
+// String returns the string representation of this StepGrains value.
+func (i StepGrains) String() string { return enums.String(i, _StepGrainsMap) }
+
+// SetString sets the StepGrains value from its string representation,
+// and returns an error if the string is invalid.
+func (i *StepGrains) SetString(s string) error {
+	return enums.SetString(i, s, _StepGrainsValueMap, "StepGrains")
+}
+
+// Int64 returns the StepGrains value as an int64.
+func (i StepGrains) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the StepGrains value from an int64.
+func (i *StepGrains) SetInt64(in int64) { *i = StepGrains(in) }
+
+// Desc returns the description of the StepGrains value.
+func (i StepGrains) Desc() string { return enums.Desc(i, _StepGrainsDescMap) }
+
+// StepGrainsValues returns all possible values for the type StepGrains.
+func StepGrainsValues() []StepGrains { return _StepGrainsValues }
+
+// Values returns all possible values for the type StepGrains.
+func (i StepGrains) Values() []enums.Enum { return enums.Values(_StepGrainsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i StepGrains) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *StepGrains) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "StepGrains")
+}
+
 var _ValencesValues = []Valences{0, 1}
 
 // ValencesN is the highest valid value for type Valences, plus one.
@@ -233,16 +661,16 @@ func (i Valences) MarshalText() ([]byte, error) { return []byte(i.String()), nil
 // UnmarshalText implements the [encoding.TextUnmarshaler] interface.
 func (i *Valences) UnmarshalText(text []byte) error { return enums.UnmarshalText(i, text, "Valences") }
 
-var _NeurFlagsValues = []NeurFlags{0, 1, 2, 3}
+var _NeurFlagsValues = []NeurFlags{0, 1, 2, 3, 4}
 
 // NeurFlagsN is the highest valid value for type NeurFlags, plus one.
-const NeurFlagsN NeurFlags = 4
+const NeurFlagsN NeurFlags = 5
 
-var _NeurFlagsValueMap = map[string]NeurFlags{`NeurOff`: 0, `NeurHasExt`: 1, `NeurHasTarg`: 2, `NeurHasCmpr`: 3}
+var _NeurFlagsValueMap = map[string]NeurFlags{`NeurOff`: 0, `NeurHasExt`: 1, `NeurHasTarg`: 2, `NeurHasCmpr`: 3, `NeurDropout`: 4}
 
-var _NeurFlagsDescMap = map[NeurFlags]string{0: `NeurOff flag indicates that this neuron has been turned off (i.e., lesioned)`, 1: `NeurHasExt means the neuron has external input in its Ext field`, 2: `NeurHasTarg means the neuron has external target input in its Targ field`, 3: `NeurHasCmpr means the neuron has external comparison input in its Targ field -- used for computing comparison statistics but does not drive neural activity ever`}
+var _NeurFlagsDescMap = map[NeurFlags]string{0: `NeurOff flag indicates that this neuron has been turned off (i.e., lesioned)`, 1: `NeurHasExt means the neuron has external input in its Ext field`, 2: `NeurHasTarg means the neuron has external target input in its Targ field`, 3: `NeurHasCmpr means the neuron has external comparison input in its Targ field -- used for computing comparison statistics but does not drive neural activity ever`, 4: `NeurDropout means the neuron has been dropped (silenced) for the current training trial, by Layer.Dropout`}
 
-var _NeurFlagsMap = map[NeurFlags]string{0: `NeurOff`, 1: `NeurHasExt`, 2: `NeurHasTarg`, 3: `NeurHasCmpr`}
+var _NeurFlagsMap = map[NeurFlags]string{0: `NeurOff`, 1: `NeurHasExt`, 2: `NeurHasTarg`, 3: `NeurHasCmpr`, 4: `NeurDropout`}
 
 // String returns the string representation of this NeurFlags value.
 func (i NeurFlags) String() string { return enums.BitFlagString(i, _NeurFlagsValues) }
@@ -292,16 +720,16 @@ func (i *NeurFlags) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "NeurFlags")
 }
 
-var _PathTypesValues = []PathTypes{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}
+var _PathTypesValues = []PathTypes{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
 
 // PathTypesN is the highest valid value for type PathTypes, plus one.
-const PathTypesN PathTypes = 12
+const PathTypesN PathTypes = 13
 
-var _PathTypesValueMap = map[string]PathTypes{`ForwardPath`: 0, `BackPath`: 1, `LateralPath`: 2, `InhibPath`: 3, `CTCtxtPath`: 4, `CHLPath`: 5, `EcCa1Path`: 6, `RWPath`: 7, `TDPredPath`: 8, `MatrixPath`: 9, `GPiThalPath`: 10, `DaHebbPath`: 11}
+var _PathTypesValueMap = map[string]PathTypes{`ForwardPath`: 0, `BackPath`: 1, `LateralPath`: 2, `InhibPath`: 3, `CTCtxtPath`: 4, `CHLPath`: 5, `EcCa1Path`: 6, `RWPath`: 7, `TDPredPath`: 8, `MatrixPath`: 9, `GPiThalPath`: 10, `DaHebbPath`: 11, `ValuePath`: 12}
 
-var _PathTypesDescMap = map[PathTypes]string{0: `Forward is a feedforward, bottom-up pathway from sensory inputs to higher layers`, 1: `Back is a feedback, top-down pathway from higher layers back to lower layers`, 2: `Lateral is a lateral pathway within the same layer / area`, 3: `Inhib is an inhibitory pathway that drives inhibitory synaptic conductances instead of the default excitatory ones.`, 4: `CTCtxt are pathways from Superficial layers to CT layers that send Burst activations drive updating of CtxtGe excitatory conductance, at end of plus (51B Bursting) phase. Biologically, this pathway comes from the PT layer 5IB neurons, but it is simpler to use the Super neurons directly, and PT are optional for most network types. These pathways also use a special learning rule that takes into account the temporal delays in the activation states. Can also add self context from CT for deeper temporal context.`, 5: `CHLPath implements Contrastive Hebbian Learning.`, 6: `EcCa1Path implements special learning for EC &lt;-&gt; CA1 pathways in the hippocampus to perform error-driven learning of this encoder pathway according to the ThetaPhase algorithm. uses Contrastive Hebbian Learning (CHL) on ActP - ActQ1 Q1: ECin -&gt; CA1 -&gt; ECout : ActQ1 = minus phase for auto-encoder Q2, 3: CA3 -&gt; CA1 -&gt; ECout : ActM = minus phase for recall Q4: ECin -&gt; CA1, ECin -&gt; ECout : ActP = plus phase for everything`, 7: `RWPath does dopamine-modulated learning for reward prediction: Da * Send.Act Use in RWPredLayer typically to generate reward predictions. Has no weight bounds or limits on sign etc.`, 8: `TDPredPath does dopamine-modulated learning for reward prediction: DWt = Da * Send.ActQ0 (activity on *previous* timestep) Use in TDPredLayer typically to generate reward predictions. Has no weight bounds or limits on sign etc.`, 9: `MatrixPath does dopamine-modulated, gated trace learning, for Matrix learning in PBWM context.`, 10: `GPiThalPath accumulates per-path raw conductance that is needed for separately weighting NoGo vs. Go inputs.`, 11: `DaHebbPath does dopamine-modulated Hebbian learning -- i.e., the 3-factor learning rule: Da * Recv.Act * Send.Act`}
+var _PathTypesDescMap = map[PathTypes]string{0: `Forward is a feedforward, bottom-up pathway from sensory inputs to higher layers`, 1: `Back is a feedback, top-down pathway from higher layers back to lower layers`, 2: `Lateral is a lateral pathway within the same layer / area`, 3: `Inhib is an inhibitory pathway that drives inhibitory synaptic conductances instead of the default excitatory ones.`, 4: `CTCtxt are pathways from Superficial layers to CT layers that send Burst activations drive updating of CtxtGe excitatory conductance, at end of plus (51B Bursting) phase. Biologically, this pathway comes from the PT layer 5IB neurons, but it is simpler to use the Super neurons directly, and PT are optional for most network types. These pathways also use a special learning rule that takes into account the temporal delays in the activation states. Can also add self context from CT for deeper temporal context.`, 5: `CHLPath implements Contrastive Hebbian Learning.`, 6: `EcCa1Path implements special learning for EC &lt;-&gt; CA1 pathways in the hippocampus to perform error-driven learning of this encoder pathway according to the ThetaPhase algorithm. uses Contrastive Hebbian Learning (CHL) on ActP - ActQ1 Q1: ECin -&gt; CA1 -&gt; ECout : ActQ1 = minus phase for auto-encoder Q2, 3: CA3 -&gt; CA1 -&gt; ECout : ActM = minus phase for recall Q4: ECin -&gt; CA1, ECin -&gt; ECout : ActP = plus phase for everything`, 7: `RWPath does dopamine-modulated learning for reward prediction: Da * Send.Act Use in RWPredLayer typically to generate reward predictions. Has no weight bounds or limits on sign etc.`, 8: `TDPredPath does dopamine-modulated learning for reward prediction: DWt = Da * Send.ActQ0 (activity on *previous* timestep) Use in TDPredLayer typically to generate reward predictions. Has no weight bounds or limits on sign etc.`, 9: `MatrixPath does dopamine-modulated, gated trace learning, for Matrix learning in PBWM context.`, 10: `GPiThalPath accumulates per-path raw conductance that is needed for separately weighting NoGo vs. Go inputs.`, 11: `DaHebbPath does dopamine-modulated Hebbian learning -- i.e., the 3-factor learning rule: Da * Recv.Act * Send.Act`, 12: `ValuePath does dopamine-modulated learning for a [ValueLayer]: DWt = Da * Send.ActQ0 (or Send.Act), with the sign of Da reversed for EffortCost layers. Has no weight bounds or limits on sign etc.`}
 
-var _PathTypesMap = map[PathTypes]string{0: `ForwardPath`, 1: `BackPath`, 2: `LateralPath`, 3: `InhibPath`, 4: `CTCtxtPath`, 5: `CHLPath`, 6: `EcCa1Path`, 7: `RWPath`, 8: `TDPredPath`, 9: `MatrixPath`, 10: `GPiThalPath`, 11: `DaHebbPath`}
+var _PathTypesMap = map[PathTypes]string{0: `ForwardPath`, 1: `BackPath`, 2: `LateralPath`, 3: `InhibPath`, 4: `CTCtxtPath`, 5: `CHLPath`, 6: `EcCa1Path`, 7: `RWPath`, 8: `TDPredPath`, 9: `MatrixPath`, 10: `GPiThalPath`, 11: `DaHebbPath`, 12: `ValuePath`}
 
 // String returns the string representation of this PathTypes value.
 func (i PathTypes) String() string { return enums.String(i, _PathTypesMap) }
@@ -377,3 +805,218 @@ func (i GateTypes) MarshalText() ([]byte, error) { return []byte(i.String()), ni
 func (i *GateTypes) UnmarshalText(text []byte) error {
 	return enums.UnmarshalText(i, text, "GateTypes")
 }
+
+var _StripeRolesValues = []StripeRoles{0, 1, 2}
+
+// StripeRolesN is the highest valid value for type StripeRoles, plus one.
+const StripeRolesN StripeRoles = 3
+
+var _StripeRolesValueMap = map[string]StripeRoles{`MaintStripe`: 0, `OutStripe`: 1, `InputStripe`: 2}
+
+var _StripeRolesDescMap = map[StripeRoles]string{0: `MaintStripe stripes are gated into robust active maintenance, which persists until the next gating or up to MaxMaint.`, 1: `OutStripe stripes are output gated, driving transient deep layer activation in the first quarter only, as in the PFCout layer.`, 2: `InputStripe stripes are input gated, updating the deep layer from the current super layer activity for one gating interval only, without sustained maintenance.`}
+
+var _StripeRolesMap = map[StripeRoles]string{0: `MaintStripe`, 1: `OutStripe`, 2: `InputStripe`}
+
+// String returns the string representation of this StripeRoles value.
+func (i StripeRoles) String() string { return enums.String(i, _StripeRolesMap) }
+
+// SetString sets the StripeRoles value from its string representation,
+// and returns an error if the string is invalid.
+func (i *StripeRoles) SetString(s string) error {
+	return enums.SetString(i, s, _StripeRolesValueMap, "StripeRoles")
+}
+
+// Int64 returns the StripeRoles value as an int64.
+func (i StripeRoles) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the StripeRoles value from an int64.
+func (i *StripeRoles) SetInt64(in int64) { *i = StripeRoles(in) }
+
+// Desc returns the description of the StripeRoles value.
+func (i StripeRoles) Desc() string { return enums.Desc(i, _StripeRolesDescMap) }
+
+// StripeRolesValues returns all possible values for the type StripeRoles.
+func StripeRolesValues() []StripeRoles { return _StripeRolesValues }
+
+// Values returns all possible values for the type StripeRoles.
+func (i StripeRoles) Values() []enums.Enum { return enums.Values(_StripeRolesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i StripeRoles) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *StripeRoles) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "StripeRoles")
+}
+
+var _ReadoutTypesValues = []ReadoutTypes{0, 1, 2, 3, 4}
+
+// ReadoutTypesN is the highest valid value for type ReadoutTypes, plus one.
+const ReadoutTypesN ReadoutTypes = 5
+
+var _ReadoutTypesValueMap = map[string]ReadoutTypes{`RawReadout`: 0, `ThreshReadout`: 1, `TopKReadout`: 2, `MaxNormReadout`: 3, `SumNormReadout`: 4}
+
+var _ReadoutTypesDescMap = map[ReadoutTypes]string{0: `RawReadout returns the unit values without any transform.`, 1: `ThreshReadout binarizes the unit values: 1 if the value is &gt; Thr, else 0.`, 2: `TopKReadout binarizes the unit values by setting the K units with the highest values to 1, and the rest to 0. Only units with values &gt; Thr can be set to 1.`, 3: `MaxNormReadout normalizes the unit values by dividing by the max value, so the max is 1. Values &lt;= Thr are set to 0 prior to normalizing.`, 4: `SumNormReadout normalizes the unit values by dividing by their sum, so they sum to 1. Values &lt;= Thr are set to 0 prior to normalizing.`}
+
+var _ReadoutTypesMap = map[ReadoutTypes]string{0: `RawReadout`, 1: `ThreshReadout`, 2: `TopKReadout`, 3: `MaxNormReadout`, 4: `SumNormReadout`}
+
+// String returns the string representation of this ReadoutTypes value.
+func (i ReadoutTypes) String() string { return enums.String(i, _ReadoutTypesMap) }
+
+// SetString sets the ReadoutTypes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ReadoutTypes) SetString(s string) error {
+	return enums.SetString(i, s, _ReadoutTypesValueMap, "ReadoutTypes")
+}
+
+// Int64 returns the ReadoutTypes value as an int64.
+func (i ReadoutTypes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ReadoutTypes value from an int64.
+func (i *ReadoutTypes) SetInt64(in int64) { *i = ReadoutTypes(in) }
+
+// Desc returns the description of the ReadoutTypes value.
+func (i ReadoutTypes) Desc() string { return enums.Desc(i, _ReadoutTypesDescMap) }
+
+// ReadoutTypesValues returns all possible values for the type ReadoutTypes.
+func ReadoutTypesValues() []ReadoutTypes { return _ReadoutTypesValues }
+
+// Values returns all possible values for the type ReadoutTypes.
+func (i ReadoutTypes) Values() []enums.Enum { return enums.Values(_ReadoutTypesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ReadoutTypes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ReadoutTypes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ReadoutTypes")
+}
+
+var _SaliencyModesValues = []SaliencyModes{0, 1}
+
+// SaliencyModesN is the highest valid value for type SaliencyModes, plus one.
+const SaliencyModesN SaliencyModes = 2
+
+var _SaliencyModesValueMap = map[string]SaliencyModes{`SaliencyOcclude`: 0, `SaliencyFlip`: 1}
+
+var _SaliencyModesDescMap = map[SaliencyModes]string{0: `SaliencyOcclude sets the input value to 0, so only active inputs can have an effect.`, 1: `SaliencyFlip sets the input value v to 1 - v, so that turning on inactive inputs can also have an effect.`}
+
+var _SaliencyModesMap = map[SaliencyModes]string{0: `SaliencyOcclude`, 1: `SaliencyFlip`}
+
+// String returns the string representation of this SaliencyModes value.
+func (i SaliencyModes) String() string { return enums.String(i, _SaliencyModesMap) }
+
+// SetString sets the SaliencyModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *SaliencyModes) SetString(s string) error {
+	return enums.SetString(i, s, _SaliencyModesValueMap, "SaliencyModes")
+}
+
+// Int64 returns the SaliencyModes value as an int64.
+func (i SaliencyModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the SaliencyModes value from an int64.
+func (i *SaliencyModes) SetInt64(in int64) { *i = SaliencyModes(in) }
+
+// Desc returns the description of the SaliencyModes value.
+func (i SaliencyModes) Desc() string { return enums.Desc(i, _SaliencyModesDescMap) }
+
+// SaliencyModesValues returns all possible values for the type SaliencyModes.
+func SaliencyModesValues() []SaliencyModes { return _SaliencyModesValues }
+
+// Values returns all possible values for the type SaliencyModes.
+func (i SaliencyModes) Values() []enums.Enum { return enums.Values(_SaliencyModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i SaliencyModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *SaliencyModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "SaliencyModes")
+}
+
+var _UpsampleModesValues = []UpsampleModes{0, 1}
+
+// UpsampleModesN is the highest valid value for type UpsampleModes, plus one.
+const UpsampleModesN UpsampleModes = 2
+
+var _UpsampleModesValueMap = map[string]UpsampleModes{`UpsampleScale`: 0, `UpsampleTile`: 1}
+
+var _UpsampleModesDescMap = map[UpsampleModes]string{0: `UpsampleScale maps each unit to the unit at the proportionally corresponding position along each dimension of the smaller layer, which stretches the small layer over the large one (nearest neighbor interpolation), so that topographic organization is preserved.`, 1: `UpsampleTile maps each unit to the unit at its position modulo the size of the smaller layer along each dimension, which tiles copies of the small layer over the large one, e.g., to replicate the pools of a trained hippocampal model into more pools.`}
+
+var _UpsampleModesMap = map[UpsampleModes]string{0: `UpsampleScale`, 1: `UpsampleTile`}
+
+// String returns the string representation of this UpsampleModes value.
+func (i UpsampleModes) String() string { return enums.String(i, _UpsampleModesMap) }
+
+// SetString sets the UpsampleModes value from its string representation,
+// and returns an error if the string is invalid.
+func (i *UpsampleModes) SetString(s string) error {
+	return enums.SetString(i, s, _UpsampleModesValueMap, "UpsampleModes")
+}
+
+// Int64 returns the UpsampleModes value as an int64.
+func (i UpsampleModes) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the UpsampleModes value from an int64.
+func (i *UpsampleModes) SetInt64(in int64) { *i = UpsampleModes(in) }
+
+// Desc returns the description of the UpsampleModes value.
+func (i UpsampleModes) Desc() string { return enums.Desc(i, _UpsampleModesDescMap) }
+
+// UpsampleModesValues returns all possible values for the type UpsampleModes.
+func UpsampleModesValues() []UpsampleModes { return _UpsampleModesValues }
+
+// Values returns all possible values for the type UpsampleModes.
+func (i UpsampleModes) Values() []enums.Enum { return enums.Values(_UpsampleModesValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i UpsampleModes) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *UpsampleModes) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "UpsampleModes")
+}
+
+var _ValueKindsValues = []ValueKinds{0, 1}
+
+// ValueKindsN is the highest valid value for type ValueKinds, plus one.
+const ValueKindsN ValueKinds = 2
+
+var _ValueKindsValueMap = map[string]ValueKinds{`StateValue`: 0, `EffortCost`: 1}
+
+var _ValueKindsDescMap = map[ValueKinds]string{0: `StateValue is the expected reward value of the current state, as in the orbitofrontal cortex (OFC), which is learned from positive dopamine, and biases Matrix gating toward Go.`, 1: `EffortCost is the expected effort or other cost of the current state, as in the anterior cingulate cortex (ACC), which is learned from negative dopamine (i.e., dopamine with the sign reversed), and biases Matrix gating toward NoGo.`}
+
+var _ValueKindsMap = map[ValueKinds]string{0: `StateValue`, 1: `EffortCost`}
+
+// String returns the string representation of this ValueKinds value.
+func (i ValueKinds) String() string { return enums.String(i, _ValueKindsMap) }
+
+// SetString sets the ValueKinds value from its string representation,
+// and returns an error if the string is invalid.
+func (i *ValueKinds) SetString(s string) error {
+	return enums.SetString(i, s, _ValueKindsValueMap, "ValueKinds")
+}
+
+// Int64 returns the ValueKinds value as an int64.
+func (i ValueKinds) Int64() int64 { return int64(i) }
+
+// SetInt64 sets the ValueKinds value from an int64.
+func (i *ValueKinds) SetInt64(in int64) { *i = ValueKinds(in) }
+
+// Desc returns the description of the ValueKinds value.
+func (i ValueKinds) Desc() string { return enums.Desc(i, _ValueKindsDescMap) }
+
+// ValueKindsValues returns all possible values for the type ValueKinds.
+func ValueKindsValues() []ValueKinds { return _ValueKindsValues }
+
+// Values returns all possible values for the type ValueKinds.
+func (i ValueKinds) Values() []enums.Enum { return enums.Values(_ValueKindsValues) }
+
+// MarshalText implements the [encoding.TextMarshaler] interface.
+func (i ValueKinds) MarshalText() ([]byte, error) { return []byte(i.String()), nil }
+
+// UnmarshalText implements the [encoding.TextUnmarshaler] interface.
+func (i *ValueKinds) UnmarshalText(text []byte) error {
+	return enums.UnmarshalText(i, text, "ValueKinds")
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+)
+
+// EnvDescriber is an optional interface for environments that can
+// describe their training data, for EnvsReport.
+type EnvDescriber interface {
+
+	// EnvDesc returns a one-line description of the data
+	// presented by the environment.
+	EnvDesc() string
+}
+
+// EnvsReport returns a listing of the given environments, with the
+// label and type of each, and a description of the data: from EnvDesc
+// if the environment is an EnvDescriber, or else the number of patterns
+// and columns of an env.FixedTable.
+func EnvsReport(envs ...env.Env) string {
+	str := ""
+	for _, ev := range envs {
+		desc := ""
+		switch et := ev.(type) {
+		case EnvDescriber:
+			desc = et.EnvDesc()
+		case *env.FixedTable:
+			desc = tableDesc(et.Table, et.Sequential)
+		}
+		str += fmt.Sprintf("%15s\t%T\t%s\n", ev.Label(), ev, desc)
+	}
+	return str
+}
+
+// tableDesc returns a description of the patterns in the given table
+// view, with the number of rows, and the name and shape of each column.
+func tableDesc(iv *table.IndexView, sequential bool) string {
+	if iv == nil || iv.Table == nil {
+		return ""
+	}
+	dt := iv.Table
+	var cols []string
+	for ci, col := range dt.Columns {
+		cols = append(cols, fmt.Sprintf("%s %v", dt.ColumnNames[ci], col.Shape().Sizes[1:]))
+	}
+	return fmt.Sprintf("Patterns: %d\tSequential: %v\tColumns: %s", iv.Len(), sequential, strings.Join(cols, ", "))
+}
+
+// SaveEnvsReport saves the listing of the given environments
+// (see EnvsReport) to given file, e.g., params_envs.txt in the
+// ParamsSnapshotDir, for the runcmp model card.
+func SaveEnvsReport(filename core.Filename, envs ...env.Env) error {
+	str := EnvsReport(envs...)
+	return errors.Log(os.WriteFile(string(filename), []byte(str), 0666))
+}
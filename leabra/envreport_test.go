@@ -0,0 +1,45 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestArchEnvsReport(t *testing.T) {
+	net := NewNetwork("Arch")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	out := net.AddLayer2D("Output", 1, 3, TargetLayer)
+	net.ConnectLayers(in, out, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	ar := net.ArchReport()
+	for _, want := range []string{"Input\tInputLayer\tShape: [2 2]\tNeurons: 4", "InputToOutput\tFrom: Input\tForwardPath\tFull\tSyns: 12", "Layers: 2\tNeurons: 7\tSyns: 12"} {
+		if !strings.Contains(ar, want) {
+			t.Errorf("ArchReport missing %q:\n%s", want, ar)
+		}
+	}
+
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{2, 2})
+	dt.SetNumRows(3)
+	fe := &env.FixedTable{Name: "Test"}
+	fe.Config(table.NewIndexView(dt))
+	pe := &PavlovEnv{Name: "Pavlov"}
+	pe.Defaults()
+	er := EnvsReport(fe, pe)
+	for _, want := range []string{"Test\t*env.FixedTable\tPatterns: 3\tSequential: false\tColumns: Name [], Input [2 2]", "Pavlov\t*leabra.PavlovEnv\tPavlov: Schedule: PavlovAcquisition\tTrials: 21"} {
+		if !strings.Contains(er, want) {
+			t.Errorf("EnvsReport missing %q:\n%s", want, er)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+// EWCParams are the parameters for elastic weight consolidation (EWC),
+// which protects the synapses that were important for previously learned
+// items from catastrophic interference when learning new items (e.g., AC
+// after AB), as a cortical alternative to the hippocampal solution.
+// While learning, each synapse accumulates an estimate of its importance
+// in the ImpAcc synapse variable, from the magnitude (or squared magnitude,
+// for a Fisher-like estimate) of its weight changes.  ConsolidateEWC
+// (e.g., at the end of learning the old items) adds the accumulated
+// importance to the Imp synapse variable, and records the current linear
+// weights as the consolidated values LWtCons.  From then on, the weight
+// of each synapse is pulled back toward its consolidated value in
+// proportion to Lambda * Imp, penalizing changes to the important ones.
+type EWCParams struct {
+
+	// use elastic weight consolidation on this pathway
+	On bool
+
+	// strength of the penalty on the change of the linear weights from their consolidated values, as a multiplier on the learning rate times the importance Imp: the pull per weight update is at most the full difference
+	Lambda Float `default:"5" min:"0"`
+
+	// accumulate the squared weight changes as the importance (Fisher-like), instead of their absolute values
+	Fisher bool
+
+	// normalize the importance accumulated since the last consolidation by its maximum over the synapses of the pathway when it is consolidated, so Imp is 0-1 per consolidation, independent of the amount of learning
+	Norm bool `default:"true"`
+
+	// proportion of the previously consolidated importance retained at each consolidation (online EWC): 1 = sum over all consolidations, 0 = only the last one
+	Gamma Float `default:"1" min:"0" max:"1"`
+}
+
+func (ep *EWCParams) Update() {
+}
+
+func (ep *EWCParams) Defaults() {
+	ep.On = false
+	ep.Lambda = 5
+	ep.Fisher = false
+	ep.Norm = true
+	ep.Gamma = 1
+}
+
+func (ep *EWCParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return ep.On
+	}
+}
+
+// ImpFromDWt returns the importance accumulated for the given weight change.
+func (ep *EWCParams) ImpFromDWt(dwt Float) Float {
+	if ep.Fisher {
+		return dwt * dwt
+	}
+	if dwt < 0 {
+		return -dwt
+	}
+	return dwt
+}
+
+// InitEWC resets the importance of the synapses to 0, and their
+// consolidated weights to the current linear weights, allocating these
+// synapse variables if EWC.On, or freeing them otherwise.
+// Called by InitWeights.
+func (pt *Path) InitEWC() {
+	sy := &pt.Syns
+	if !pt.EWC.On {
+		sy.setEWCLen(0)
+		return
+	}
+	if len(sy.Imp) != sy.Len() {
+		sy.setEWCLen(sy.Len())
+	}
+	for si := range sy.Imp {
+		sy.Imp[si] = 0
+		sy.ImpAcc[si] = 0
+		sy.LWtCons[si] = sy.LWt[si]
+	}
+}
+
+// DWtEWC accumulates the importance of the synapses from their current
+// weight changes, and adds the consolidation penalty to them, if EWC.On.
+// Called by WtFromDWt.
+func (pt *Path) DWtEWC() {
+	ep := &pt.EWC
+	if !ep.On {
+		return
+	}
+	sy := &pt.Syns
+	if len(sy.Imp) != sy.Len() { // turned on after InitWeights
+		pt.InitEWC()
+	}
+	pen := pt.Learn.Lrate * ep.Lambda
+	for si, dwt := range sy.DWt {
+		sy.ImpAcc[si] += ep.ImpFromDWt(dwt)
+		if imp := sy.Imp[si]; imp > 0 {
+			sy.DWt[si] -= min(pen*imp, 1) * (sy.LWt[si] - sy.LWtCons[si])
+		}
+	}
+}
+
+// ConsolidateEWC adds the importance accumulated since the last
+// consolidation (ImpAcc) to the importance Imp of the synapses, after
+// decaying it by EWC.Gamma, resets ImpAcc, and records the current linear
+// weights as the consolidated weights LWtCons, if EWC.On.
+func (pt *Path) ConsolidateEWC() {
+	ep := &pt.EWC
+	if !ep.On {
+		return
+	}
+	sy := &pt.Syns
+	if len(sy.Imp) != sy.Len() {
+		pt.InitEWC()
+	}
+	norm := Float(1)
+	if ep.Norm {
+		var mx Float
+		for _, acc := range sy.ImpAcc {
+			mx = max(mx, acc)
+		}
+		if mx > 0 {
+			norm = 1 / mx
+		}
+	}
+	for si := range sy.Imp {
+		sy.Imp[si] = ep.Gamma*sy.Imp[si] + norm*sy.ImpAcc[si]
+		sy.ImpAcc[si] = 0
+		sy.LWtCons[si] = sy.LWt[si]
+	}
+}
+
+// ConsolidateEWC consolidates the weights of all the pathways with
+// EWC.On (see Path.ConsolidateEWC), e.g., at the end of learning
+// one set of items, before learning the next.
+func (nt *Network) ConsolidateEWC() {
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for _, pt := range ly.RecvPaths {
+			if pt.Off {
+				continue
+			}
+			pt.ConsolidateEWC()
+		}
+	}
+}
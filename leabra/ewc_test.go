@@ -0,0 +1,133 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
+)
+
+func TestEWC(t *testing.T) {
+	drift := func(lambda Float) (Float, Float) {
+		net := NewNetwork("EWC")
+		in := net.AddLayer2D("Input", 4, 4, InputLayer)
+		out := net.AddLayer2D("Output", 4, 4, TargetLayer)
+		pt := net.ConnectLayers(in, out, paths.NewFull(), ForwardPath)
+		net.SetRandSeed(1)
+		if err := net.Build(); err != nil {
+			t.Fatal(err)
+		}
+		net.Defaults()
+		pt.EWC.On = true
+		pt.EWC.Lambda = lambda
+		net.InitWeights()
+		inpat := tensor.NewFloat32([]int{4, 4})
+		outA := tensor.NewFloat32([]int{4, 4})
+		outB := tensor.NewFloat32([]int{4, 4})
+		for i := range 4 {
+			inpat.SetFloat1D(i*5, 1)
+			outA.SetFloat1D(i, 1)
+			outB.SetFloat1D(15-i, 1)
+		}
+		ctx := NewContext()
+		train := func(outpat tensor.Tensor) {
+			for range 5 {
+				net.InitExt()
+				in.ApplyExt(inpat)
+				out.ApplyExt(outpat)
+				net.AlphaCycInit(true)
+				ctx.AlphaCycStart()
+				for range 4 {
+					for range ctx.CycPerQtr {
+						net.Cycle(ctx)
+						ctx.CycleInc()
+					}
+					net.QuarterFinal(ctx)
+					ctx.QuarterInc()
+				}
+				net.DWt()
+				net.WtFromDWt()
+			}
+		}
+		train(outA)
+		sy := &pt.Syns
+		var accMax Float
+		for _, acc := range sy.ImpAcc {
+			accMax = max(accMax, acc)
+		}
+		if accMax == 0 {
+			t.Fatal("no importance accumulated")
+		}
+		net.ConsolidateEWC()
+		var impMax Float
+		for si := range sy.Imp {
+			impMax = max(impMax, sy.Imp[si])
+			if sy.ImpAcc[si] != 0 || sy.LWtCons[si] != sy.LWt[si] {
+				t.Fatalf("ConsolidateEWC: ImpAcc %g LWtCons %g LWt %g", sy.ImpAcc[si], sy.LWtCons[si], sy.LWt[si])
+			}
+		}
+		train(outB)
+		var sum Float
+		for si := range sy.LWt {
+			sum += sy.Imp[si] * fmath.Abs(sy.LWt[si]-sy.LWtCons[si])
+		}
+		return sum, impMax
+	}
+	free, impMax := drift(0)
+	if math.Abs(float64(impMax-1)) > 1e-6 {
+		t.Errorf("normalized max Imp: got %g, want 1", impMax)
+	}
+	prot, _ := drift(25)
+	if prot >= 0.5*free {
+		t.Errorf("EWC should protect important weights: drift %g vs. %g without", prot, free)
+	}
+}
+
+func TestEWCAlloc(t *testing.T) {
+	net := NewNetwork("EWC")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+	pt := net.ConnectLayers(in, out, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	sy := &pt.Syns
+	if sy.Imp != nil || sy.ImpAcc != nil || sy.LWtCons != nil {
+		t.Error("EWC vars allocated without EWC.On")
+	}
+	if v := pt.SynValue("LWtCons", 0, 0); v != 0 {
+		t.Errorf("LWtCons without EWC.On: got %g, want 0", v)
+	}
+	vals := []float32{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	pt.SynValues(&vals, "Imp")
+	for _, v := range vals {
+		if v != 0 {
+			t.Fatalf("Imp values without EWC.On: %v", vals)
+		}
+	}
+	if err := pt.SetSynValue("Imp", 0, 0, 1); err != nil {
+		t.Error(err)
+	}
+
+	pt.EWC.On = true // turned on after InitWeights
+	pt.DWtEWC()
+	if len(sy.Imp) != sy.Len() || len(sy.LWtCons) != sy.Len() {
+		t.Fatalf("EWC vars not allocated by DWtEWC: %d %d", len(sy.Imp), len(sy.LWtCons))
+	}
+	if v, lw := pt.SynValue("LWtCons", 1, 2), pt.SynValue("LWt", 1, 2); v != lw {
+		t.Errorf("LWtCons: got %g, want LWt %g", v, lw)
+	}
+	pt.EWC.On = false
+	net.InitWeights()
+	if sy.Imp != nil {
+		t.Error("EWC vars not freed by InitWeights without EWC.On")
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExcitParams are the parameters for intrinsic excitability plasticity:
+// a learned per-neuron excitability bias (the Excit neuron variable),
+// which is added to the raw excitatory conductance of the neuron, and is
+// adjusted at the end of each trial (in DWt) as a function of the
+// plus-phase activation ActP relative to a target activity Targ.
+// If Homeo, the changes are homeostatic, increasing the excitability of
+// neurons that are less active than Targ, and decreasing it for those that
+// are more active.  Otherwise, the excitability of the neurons that are
+// more active than Targ is increased, as in the CREB-dependent excitability
+// thought to allocate memories to recently active neurons (engrams), with
+// Decay returning it to 0 over trials.  Excit is reset by InitWeights,
+// and saved and loaded with the weights.  It continues to be applied when
+// On is false, which only turns off its learning.
+type ExcitParams struct {
+
+	// learn the intrinsic excitability of each neuron
+	On bool
+
+	// homeostatic changes: increase the excitability of neurons that are less active than Targ, and decrease it for those that are more active -- otherwise, increase the excitability of neurons that are more active than Targ (activity-dependent memory allocation)
+	Homeo bool `default:"true"`
+
+	// learning rate for the change in excitability per trial, in units of raw excitatory conductance
+	Lrate Float `default:"0.01" min:"0"`
+
+	// target plus-phase activation (ActP), relative to which excitability is changed
+	Targ Float `default:"0.15" min:"0" max:"1"`
+
+	// proportion of the excitability decayed back toward 0 per trial, for transient activity-dependent excitability
+	Decay Float `default:"0" min:"0" max:"1"`
+
+	// minimum excitability value
+	Min Float `default:"-0.5"`
+
+	// maximum excitability value
+	Max Float `default:"0.5"`
+}
+
+func (ep *ExcitParams) Update() {
+}
+
+func (ep *ExcitParams) Defaults() {
+	ep.On = false
+	ep.Homeo = true
+	ep.Lrate = 0.01
+	ep.Targ = 0.15
+	ep.Decay = 0
+	ep.Min = -0.5
+	ep.Max = 0.5
+}
+
+func (ep *ExcitParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return ep.On
+	}
+}
+
+// ExcitFromAct updates the excitability excit for one trial
+// with the given plus-phase activation.
+func (ep *ExcitParams) ExcitFromAct(excit *Float, actP Float) {
+	del := actP - ep.Targ
+	if ep.Homeo {
+		del = -del
+	}
+	ex := *excit + ep.Lrate*del - ep.Decay**excit
+	*excit = min(max(ex, ep.Min), ep.Max)
+}
+
+// InitExcit sets the learned intrinsic excitability Excit of
+// the neurons to 0.  Called by InitWeights.
+func (ly *Layer) InitExcit() {
+	for ni := range ly.Neurons {
+		ly.Neurons[ni].Excit = 0
+	}
+}
+
+// DWtExcit updates the learned intrinsic excitability Excit of the
+// neurons from their ActP, if Learn.Excit.On.  Called by DWt.
+func (ly *Layer) DWtExcit() {
+	if !ly.Learn.Excit.On {
+		return
+	}
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		ly.Learn.Excit.ExcitFromAct(&nrn.Excit, nrn.ActP)
+	}
+}
+
+// HasExcit returns true if the layer is learning the intrinsic
+// excitability, or any neuron has a non-zero Excit value,
+// in which case it is saved with the weights.
+func (ly *Layer) HasExcit() bool {
+	if ly.Learn.Excit.On {
+		return true
+	}
+	for ni := range ly.Neurons {
+		if ly.Neurons[ni].Excit != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// neuronValuesString returns the given values of the neurons (e.g., Excit)
+// as a space-separated string, for the binary weights metadata.
+func (ly *Layer) neuronValuesString(val func(nrn *Neuron) Float) string {
+	var b strings.Builder
+	for ni := range ly.Neurons {
+		if ni > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString(strconv.FormatFloat(float64(val(&ly.Neurons[ni])), 'g', -1, 64))
+	}
+	return b.String()
+}
+
+// setExcit sets the Excit values of the neurons from the
+// given saved values, or to 0 if nil.
+func (ly *Layer) setExcit(vals []float32) error {
+	if vals == nil {
+		ly.InitExcit()
+		return nil
+	}
+	if len(vals) != len(ly.Neurons) {
+		return fmt.Errorf("leabra.Layer.SetWeights: layer %s: number of Excit values %d != number of neurons %d", ly.Name, len(vals), len(ly.Neurons))
+	}
+	for ni := range ly.Neurons {
+		ly.Neurons[ni].Excit = Float(vals[ni])
+	}
+	return nil
+}
+
+// parseNeuronValues parses the space-separated values of the given
+// neuron variable saved by neuronValuesString.
+func parseNeuronValues(varnm, s string) ([]float32, error) {
+	fs := strings.Fields(s)
+	vals := make([]float32, len(fs))
+	for i, f := range fs {
+		v, err := strconv.ParseFloat(f, 32)
+		if err != nil {
+			return nil, fmt.Errorf("leabra: invalid %s value: %w", varnm, err)
+		}
+		vals[i] = float32(v)
+	}
+	return vals, nil
+}
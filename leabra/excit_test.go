@@ -0,0 +1,105 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestExcit(t *testing.T) {
+	net := NewNetwork("Excit")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	ex := &hid.Learn.Excit
+	ex.On = true
+	ex.Homeo = false
+	ex.Targ = 0
+	ex.Lrate = 0.05
+	net.InitWeights()
+	inpat := tensor.NewFloat32([]int{4, 4})
+	for i := range 4 {
+		inpat.SetFloat1D(i*5, 1)
+	}
+	ctx := NewContext()
+	for range 3 {
+		net.InitExt()
+		in.ApplyExt(inpat)
+		net.AlphaCycInit(true)
+		ctx.AlphaCycStart()
+		for range 4 {
+			for range ctx.CycPerQtr {
+				net.Cycle(ctx)
+				ctx.CycleInc()
+			}
+			net.QuarterFinal(ctx)
+			ctx.QuarterInc()
+		}
+		net.DWt()
+	}
+	nact, actMin, inactMax := 0, Float(1), Float(0)
+	for ni := range hid.Neurons {
+		nrn := &hid.Neurons[ni]
+		if nrn.ActP > 0.5 {
+			nact++
+			actMin = min(actMin, nrn.Excit)
+		} else {
+			inactMax = max(inactMax, nrn.Excit)
+		}
+	}
+	if nact == 0 || actMin <= 0.05 || inactMax >= actMin/2 {
+		t.Errorf("active neurons should become more excitable: %d active, min Excit %g vs. inactive max %g", nact, actMin, inactMax)
+	}
+	want := make([]Float, len(hid.Neurons))
+	for ni := range hid.Neurons {
+		want[ni] = hid.Neurons[ni].Excit
+	}
+	check := func(nm string) {
+		for ni := range hid.Neurons {
+			if math.Abs(float64(hid.Neurons[ni].Excit-want[ni])) > 1e-6 {
+				t.Errorf("%s: Excit[%d] = %g, want %g", nm, ni, hid.Neurons[ni].Excit, want[ni])
+				return
+			}
+		}
+	}
+	var jb, bb bytes.Buffer
+	if err := net.WriteWeightsJSON(&jb); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.WriteWeightsBinary(&bb); err != nil {
+		t.Fatal(err)
+	}
+	net.InitWeights()
+	if !hid.HasExcit() || hid.Neurons[0].Excit != 0 {
+		t.Errorf("InitWeights should reset Excit: %g", hid.Neurons[0].Excit)
+	}
+	if err := net.ReadWeightsJSON(&jb); err != nil {
+		t.Fatal(err)
+	}
+	check("JSON")
+	net.InitWeights()
+	if err := net.ReadWeightsBinary(&bb); err != nil {
+		t.Fatal(err)
+	}
+	check("binary")
+
+	ex.Homeo = true
+	ex.Targ = 0.15
+	ex.Lrate = 0.01
+	excit := Float(0)
+	ex.ExcitFromAct(&excit, 0.05)
+	if math.Abs(float64(excit-0.001)) > 1e-6 {
+		t.Errorf("homeostatic Excit should increase for low activity: %g", excit)
+	}
+}
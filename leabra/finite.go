@@ -0,0 +1,162 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"log"
+	"math"
+)
+
+// FiniteLevels are the levels of checking for non-finite (NaN or Inf)
+// values in the state of the network, with Network.CheckFinite.
+type FiniteLevels int32 //enums:enum
+
+const (
+	// FiniteOff does not check.
+	FiniteOff FiniteLevels = iota
+
+	// FiniteNeurons checks the neuron variables (NeuronVars) of all layers,
+	// including the layer neuromodulators.
+	FiniteNeurons
+
+	// FiniteAll checks the neuron variables, and the synapse variables
+	// (SynapseVars) of all pathways.
+	FiniteAll
+)
+
+// FiniteParams are the parameters for the automatic checking for
+// non-finite (NaN or Inf) values in the state of the network, which
+// otherwise silently propagate through the network, e.g., wasting
+// long cluster runs.  The first one detected is logged, or it panics.
+type FiniteParams struct {
+
+	// Level is the level of checking at the end of each WtFromDWt,
+	// i.e., at the end of each training trial.
+	Level FiniteLevels
+
+	// Quarter also checks the neuron variables at the end of each
+	// quarter (QuarterFinal), to determine more precisely where a
+	// non-finite value first appears, if Level is not FiniteOff.
+	Quarter bool
+
+	// Panic panics with the non-finite value detected, to stop the
+	// run as early as possible, instead of logging the first one.
+	Panic bool
+
+	// First is the first non-finite value detected since InitWeights,
+	// or nil if none.
+	First *NonFinite `display:"-"`
+}
+
+// NonFinite records a non-finite (NaN or Inf) value in the state of
+// the network, as detected by Network.CheckFinite.
+type NonFinite struct {
+
+	// Layer is the name of the layer of the neuron, or the receiving
+	// layer of the pathway.
+	Layer string
+
+	// Path is the name of the pathway of the synapse,
+	// empty for a neuron variable.
+	Path string
+
+	// Var is the name of the neuron or synapse variable.
+	Var string
+
+	// Index is the index of the neuron in the layer,
+	// or of the synapse in the pathway.
+	Index int
+
+	// Value is the non-finite value.
+	Value float64
+
+	// Where is the point of the computation at which it was detected,
+	// e.g., "QuarterFinal: quarter 1 cycle 49".
+	Where string
+}
+
+// Error returns a description of the non-finite value.
+func (nf *NonFinite) Error() string {
+	s := fmt.Sprintf("leabra: non-finite value %g in layer %s", nf.Value, nf.Layer)
+	if nf.Path != "" {
+		s += fmt.Sprintf(" path %s synapse %d", nf.Path, nf.Index)
+	} else {
+		s += fmt.Sprintf(" neuron %d", nf.Index)
+	}
+	s += " variable " + nf.Var
+	if nf.Where != "" {
+		s += ", detected at " + nf.Where
+	}
+	return s
+}
+
+// IsFinite returns true if the value is not NaN or Inf.
+func IsFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
+
+// CheckFinite scans the state of the network at the given level for
+// non-finite (NaN or Inf) values, returning the first one found in the
+// order of the layers (neurons before synapses), or nil if none.
+func (nt *Network) CheckFinite(level FiniteLevels) *NonFinite {
+	if level == FiniteOff {
+		return nil
+	}
+	nvars := len(NeuronVars)
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for ni := range ly.Neurons {
+			for vi := range nvars {
+				v := float64(ly.UnitValue1D(vi, ni, 0))
+				if !IsFinite(v) {
+					return &NonFinite{Layer: ly.Name, Var: NeuronVars[vi], Index: ni, Value: v}
+				}
+			}
+		}
+	}
+	if level < FiniteAll {
+		return nil
+	}
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for _, pt := range ly.RecvPaths {
+			if pt.Off {
+				continue
+			}
+			for vi, vnm := range SynapseVars {
+				for si, fv := range pt.Syns.Values(vi) {
+					v := float64(fv)
+					if !IsFinite(v) {
+						return &NonFinite{Layer: ly.Name, Path: pt.Name, Var: vnm, Index: si, Value: v}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// finiteCheck does the automatic checking for non-finite values at the
+// given level, according to the Finite params, with the given point
+// of the computation at which it is done.
+func (nt *Network) finiteCheck(level FiniteLevels, where string) {
+	nf := nt.CheckFinite(level)
+	if nf == nil {
+		return
+	}
+	nf.Where = where
+	if nt.Finite.Panic {
+		panic(nf.Error())
+	}
+	if nt.Finite.First == nil {
+		nt.Finite.First = nf
+		log.Println(nf.Error())
+	}
+}
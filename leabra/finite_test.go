@@ -0,0 +1,60 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestCheckFinite(t *testing.T) {
+	net := MakeTestNet(t)
+	if nf := net.CheckFinite(FiniteAll); nf != nil {
+		t.Errorf("initial: %v", nf)
+	}
+	hid := net.LayerByName("Hidden")
+	hid.Neurons[2].Ge = Float(math32.NaN())
+	nf := net.CheckFinite(FiniteNeurons)
+	if nf == nil || nf.Layer != "Hidden" || nf.Path != "" || nf.Var != "Ge" || nf.Index != 2 {
+		t.Fatalf("neurons: got %v", nf)
+	}
+	hid.Neurons[2].Ge = 0
+
+	pt := hid.RecvPaths[1]
+	pt.Syns.DWt[1] = Float(math32.Inf(1))
+	if nf := net.CheckFinite(FiniteNeurons); nf != nil {
+		t.Errorf("neurons with non-finite synapse: %v", nf)
+	}
+	nf = net.CheckFinite(FiniteAll)
+	if nf == nil || nf.Path != pt.Name || nf.Var != "DWt" || nf.Index != 1 {
+		t.Fatalf("synapses: got %v", nf)
+	}
+
+	pt.Syns.DWt[1] = 0
+	pt.Syns.Norm[1] = Float(math32.NaN())
+	net.Finite.Level = FiniteAll
+	net.Finite.Panic = true
+	func() {
+		defer func() {
+			msg, _ := recover().(string)
+			if !strings.Contains(msg, "path "+pt.Name) || !strings.Contains(msg, "detected at WtFromDWt") {
+				t.Errorf("panic: got %q", msg)
+			}
+		}()
+		net.WtFromDWt()
+	}()
+
+	net.Finite.Panic = false
+	net.WtFromDWt()
+	if net.Finite.First == nil || net.Finite.First.Path != pt.Name {
+		t.Errorf("First: got %v", net.Finite.First)
+	}
+	net.InitWeights()
+	if net.Finite.First != nil || net.CheckFinite(FiniteAll) != nil {
+		t.Errorf("InitWeights: got %v", net.Finite.First)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/core"
+)
+
+// NetGraph is a machine-readable description of the architecture of a
+// network, as a graph of its layers and pathways, for documenting
+// architectures and diffing them automatically (see Network.Graph).
+// It is written as JSON by WriteJSON, or as a GraphViz DOT digraph by
+// WriteDOT, e.g., for rendering with: dot -Tsvg net.dot > net.svg.
+// Layers and pathways that are Off are not included.
+type NetGraph struct {
+
+	// Name is the name of the network.
+	Name string
+
+	// Layers are the layers, in the order of the network.
+	Layers []GraphLayer
+
+	// Paths are the pathways, in the order of the receiving layers
+	// and of their receiving pathways.
+	Paths []GraphPath
+}
+
+// GraphLayer describes a layer in a NetGraph.
+type GraphLayer struct {
+
+	// Name is the name of the layer.
+	Name string
+
+	// Type is the layer type, e.g., SuperLayer.
+	Type string
+
+	// Class are the space-separated classes of the layer,
+	// used for selecting parameters.
+	Class string `json:",omitempty"`
+
+	// Shape is the shape of the layer.
+	Shape []int
+
+	// NNeurons is the number of neurons.
+	NNeurons int
+}
+
+// GraphPath describes a pathway in a NetGraph.
+type GraphPath struct {
+
+	// Name is the name of the pathway.
+	Name string
+
+	// Send is the name of the sending layer.
+	Send string
+
+	// Recv is the name of the receiving layer.
+	Recv string
+
+	// Type is the pathway type, e.g., ForwardPath.
+	Type string
+
+	// Class are the space-separated classes of the pathway,
+	// used for selecting parameters.
+	Class string `json:",omitempty"`
+
+	// Pattern is the name of the connectivity pattern, e.g., Full.
+	Pattern string
+
+	// Abs is the absolute weight scale (WtScale.Abs).
+	Abs float32
+
+	// Rel is the relative weight scale (WtScale.Rel).
+	Rel float32
+
+	// NSyns is the number of synapses.
+	NSyns int
+}
+
+// Graph returns the NetGraph description of the architecture of
+// the network, which must be built.
+func (nt *Network) Graph() *NetGraph {
+	ng := &NetGraph{Name: nt.Name}
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		ng.Layers = append(ng.Layers, GraphLayer{Name: ly.Name, Type: ly.Type.String(), Class: ly.Class, Shape: ly.Shape.Sizes, NNeurons: len(ly.Neurons)})
+		for _, pt := range ly.RecvPaths {
+			if pt.Off || pt.Send.Off {
+				continue
+			}
+			pat := ""
+			if pt.Pattern != nil {
+				pat = pt.Pattern.Name()
+			}
+			ng.Paths = append(ng.Paths, GraphPath{Name: pt.Name, Send: pt.Send.Name, Recv: ly.Name, Type: pt.Type.String(), Class: pt.Class, Pattern: pat, Abs: float32(pt.WtScale.Abs), Rel: float32(pt.WtScale.Rel), NSyns: pt.Syns.Len()})
+		}
+	}
+	return ng
+}
+
+// WriteJSON writes the graph as indented JSON.
+func (ng *NetGraph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(ng)
+}
+
+// WriteDOT writes the graph as a GraphViz DOT digraph, from the bottom
+// (input) to the top, with the type, shape and class of each layer, and
+// the pattern, class and scale of each pathway, with back pathways dashed.
+func (ng *NetGraph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "digraph %q {\n", ng.Name)
+	fmt.Fprintf(bw, "\trankdir=BT;\n\tnode [shape=box];\n")
+	for _, ly := range ng.Layers {
+		lbl := fmt.Sprintf("%s\n%s %v", ly.Name, ly.Type, ly.Shape)
+		if ly.Class != "" {
+			lbl += "\n" + ly.Class
+		}
+		fmt.Fprintf(bw, "\t%q [label=%q];\n", ly.Name, lbl)
+	}
+	for _, pt := range ng.Paths {
+		lbl := fmt.Sprintf("%s\nabs=%g rel=%g", pt.Pattern, pt.Abs, pt.Rel)
+		if pt.Class != "" {
+			lbl += "\n" + pt.Class
+		}
+		attrs := fmt.Sprintf("label=%q", lbl)
+		if pt.Type == BackPath.String() {
+			attrs += ", style=dashed"
+		}
+		fmt.Fprintf(bw, "\t%q -> %q [%s];\n", pt.Send, pt.Recv, attrs)
+	}
+	fmt.Fprintf(bw, "}\n")
+	return bw.Flush()
+}
+
+// ExportGraph saves the NetGraph description of the architecture of the
+// network to the given file, as a GraphViz DOT digraph if the file has
+// a .dot or .gv extension, and otherwise as JSON.
+func (nt *Network) ExportGraph(filename core.Filename) error {
+	fp, err := os.Create(string(filename))
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	ng := nt.Graph()
+	switch strings.ToLower(filepath.Ext(string(filename))) {
+	case ".dot", ".gv":
+		return ng.WriteDOT(fp)
+	default:
+		return ng.WriteJSON(fp)
+	}
+}
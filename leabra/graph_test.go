@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/core"
+)
+
+func TestExportGraph(t *testing.T) {
+	net := MakeTestNet(t)
+	ng := net.Graph()
+	if len(ng.Layers) != 3 || len(ng.Paths) != 3 {
+		t.Fatalf("graph has %d layers, %d paths, not 3, 3", len(ng.Layers), len(ng.Paths))
+	}
+	if ly := ng.Layers[1]; ly.Name != "Hidden" || ly.Type != "SuperLayer" || !slices.Equal(ly.Shape, []int{4, 1}) || ly.NNeurons != 4 {
+		t.Errorf("layer wrong: %+v", ly)
+	}
+	if pt := ng.Paths[0]; pt.Send != "Input" || pt.Recv != "Hidden" || pt.Type != "ForwardPath" || pt.Pattern != "OneToOne" || pt.NSyns != 4 {
+		t.Errorf("path wrong: %+v", pt)
+	}
+
+	dir := t.TempDir()
+	jfn := filepath.Join(dir, "net.json")
+	if err := net.ExportGraph(core.Filename(jfn)); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(jfn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rg NetGraph
+	if err := json.Unmarshal(b, &rg); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(&rg, ng) {
+		t.Errorf("JSON graph differs:\n%s", b)
+	}
+
+	dfn := filepath.Join(dir, "net.dot")
+	if err := net.ExportGraph(core.Filename(dfn)); err != nil {
+		t.Fatal(err)
+	}
+	b, err = os.ReadFile(dfn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := string(b)
+	if !strings.HasPrefix(dot, `digraph "TestNet" {`) || !strings.Contains(dot, `"Hidden" [label="Hidden\nSuperLayer [4 1]"];`) {
+		t.Errorf("DOT layers wrong:\n%s", dot)
+	}
+	if !strings.Contains(dot, `"Input" -> "Hidden" [label="OneToOne\nabs=1 rel=1"];`) || !strings.Contains(dot, `"Output" -> "Hidden" [label="OneToOne\nabs=1 rel=`) || !strings.Contains(dot, "style=dashed];") {
+		t.Errorf("DOT paths wrong:\n%s", dot)
+	}
+}
@@ -0,0 +1,219 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// GymStepper is the interface for a stepping reinforcement learning
+// environment in the style of OpenAI Gym, with discrete actions,
+// which can be wrapped as an env.Env with GymEnv.
+type GymStepper interface {
+
+	// Reset starts a new episode, using the given random number
+	// stream for any randomness in the episode.
+	Reset(rnd randx.Rand)
+
+	// Step performs the given action, advancing to the next
+	// observation and reward, and returns true if the episode is done.
+	Step(action int) bool
+
+	// Observation returns the current observation.
+	Observation() tensor.Tensor
+
+	// Reward returns the reward received for the last Step.
+	Reward() float32
+
+	// NumActions returns the number of discrete actions.
+	NumActions() int
+}
+
+// GymEnv is an env.Env that wraps a GymStepper, providing its
+// observations, rewards and actions as tensor states, so that it can
+// be used with the RW and TD reward layers, with the action chosen
+// by the network on each trial.  On each Step, the current observation
+// becomes the Obs state, or a new episode is started if the last action
+// ended the episode.  The network then chooses an action, which is
+// performed with Action("Action", ...) or TakeAction, after which the
+// reward for the action is the Rew state, and the resulting observation
+// is the NextObs state (all zeros at the end of an episode), which can
+// be presented in the plus phase for TD learning.
+type GymEnv struct {
+
+	// name of this environment, usually Train vs. Test.
+	Name string
+
+	// Gym is the wrapped stepping environment.
+	Gym GymStepper
+
+	// Seed is the seed for the Rand stream of this environment,
+	// which is seeded with Seed + run in Init.
+	Seed int64
+
+	// Rand is the random number stream for this environment,
+	// which is passed to the Gym Reset for each episode.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// Trial is the total number of steps in the run.
+	Trial env.Counter `display:"inline"`
+
+	// EpisodeStep is the step within the current episode.
+	EpisodeStep env.Counter `display:"inline"`
+
+	// Episode is the number of completed episodes.
+	Episode env.Counter `display:"inline"`
+
+	// Act is the action taken on the current step, -1 if none yet.
+	Act int `edit:"-"`
+
+	// Reward is the reward for the action taken on the current step.
+	Reward float32 `edit:"-"`
+
+	// Done is true if the action taken on the current step ended the episode.
+	Done bool `edit:"-"`
+
+	// EpisodeReward is the total reward accumulated in the current episode.
+	EpisodeReward float32 `edit:"-"`
+
+	// LastEpisodeReward is the total reward of the last completed episode.
+	LastEpisodeReward float32 `edit:"-"`
+
+	// LastEpisodeSteps is the number of steps of the last completed episode.
+	LastEpisodeSteps int `edit:"-"`
+
+	// Obs is the observation for the current step.
+	Obs tensor.Tensor `display:"-"`
+
+	// NextObs is the observation resulting from the action.
+	NextObs tensor.Tensor `display:"-"`
+
+	// Rew is the Reward as a 1x1 tensor.
+	Rew tensor.Float32 `display:"-"`
+
+	// ActionPat is a localist pattern of the Act.
+	ActionPat tensor.Float32 `display:"-"`
+}
+
+func (ev *GymEnv) Label() string { return ev.Name }
+
+// EnvDesc returns the description of the Gym if it is an EnvDescriber,
+// or else the number of actions.
+func (ev *GymEnv) EnvDesc() string {
+	if ev.Gym == nil {
+		return ""
+	}
+	if ed, ok := ev.Gym.(EnvDescriber); ok {
+		return ed.EnvDesc()
+	}
+	return fmt.Sprintf("Actions: %d", ev.Gym.NumActions())
+}
+
+// Init starts the first episode, with the Rand stream seeded by Seed + run.
+func (ev *GymEnv) Init(run int) {
+	ev.Rand.NewRand(ev.Seed + int64(run))
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Episode.Scale = etime.Epoch
+	ev.Episode.Init()
+	ev.Rew.SetShape([]int{1, 1})
+	ev.ActionPat.SetShape([]int{1, ev.Gym.NumActions()})
+	ev.LastEpisodeReward = 0
+	ev.LastEpisodeSteps = 0
+	ev.Done = false
+	ev.newEpisode()
+}
+
+// newEpisode resets the Gym for a new episode.
+func (ev *GymEnv) newEpisode() {
+	ev.Gym.Reset(&ev.Rand)
+	ev.EpisodeStep.Scale = etime.Trial
+	ev.EpisodeStep.Init()
+	ev.EpisodeStep.Cur = -1
+	ev.EpisodeReward = 0
+}
+
+// Step advances to the next step, starting a new episode if the
+// action on the last step ended the episode.  The Obs state is the
+// current observation, and the action, reward and next observation
+// are cleared until an action is taken.
+func (ev *GymEnv) Step() bool {
+	if ev.Done {
+		ev.LastEpisodeReward = ev.EpisodeReward
+		ev.LastEpisodeSteps = ev.EpisodeStep.Cur + 1
+		ev.Episode.Incr()
+		ev.newEpisode()
+	}
+	ev.Trial.Incr()
+	ev.EpisodeStep.Incr()
+	ev.Obs = ev.Gym.Observation().Clone()
+	ev.Act = -1
+	ev.Reward = 0
+	ev.Done = false
+	ev.NextObs = nil
+	ev.Rew.SetZeros()
+	ev.ActionPat.SetZeros()
+	return true
+}
+
+// TakeAction performs the given action in the Gym, recording the
+// resulting Reward, Done state, and NextObs, which is all zeros
+// if the episode is done.  Only the first action on each step is
+// performed.
+func (ev *GymEnv) TakeAction(action int) {
+	if ev.Act >= 0 {
+		return
+	}
+	ev.Act = action
+	ev.ActionPat.SetFloat1D(action, 1)
+	ev.Done = ev.Gym.Step(action)
+	ev.Reward = ev.Gym.Reward()
+	ev.Rew.SetFloat1D(0, float64(ev.Reward))
+	ev.EpisodeReward += ev.Reward
+	ev.NextObs = ev.Gym.Observation().Clone()
+	if ev.Done {
+		ev.NextObs.SetZeros()
+	}
+}
+
+// State returns the given state: Obs, NextObs (once an action has been
+// taken), Rew and Action (the localist action pattern).
+func (ev *GymEnv) State(element string) tensor.Tensor {
+	switch element {
+	case "Obs":
+		return ev.Obs
+	case "NextObs":
+		return ev.NextObs
+	case "Rew":
+		return &ev.Rew
+	case "Action":
+		return &ev.ActionPat
+	}
+	return nil
+}
+
+// Action performs the action with the maximum value in the
+// given input, for the Action element (see TakeAction).
+func (ev *GymEnv) Action(element string, input tensor.Tensor) {
+	if element != "Action" {
+		return
+	}
+	mx := 0
+	for i := range input.Len() {
+		if input.Float1D(i) > input.Float1D(mx) {
+			mx = i
+		}
+	}
+	ev.TakeAction(mx)
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*GymEnv)(nil)
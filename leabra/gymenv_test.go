@@ -0,0 +1,98 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/stats/stats"
+)
+
+// testGym is a 1D corridor GymStepper: action 1 moves right and
+// action 0 stays, with a reward of 1 at the end, which ends the episode.
+type testGym struct {
+	pos int
+	obs tensor.Float32
+	rew float32
+}
+
+func (tg *testGym) Reset(rnd randx.Rand) {
+	tg.pos = 0
+	tg.obs.SetShape([]int{1, 3})
+	tg.render()
+}
+
+func (tg *testGym) Step(action int) bool {
+	tg.pos += action
+	tg.render()
+	tg.rew = 0
+	if tg.pos == 2 {
+		tg.rew = 1
+		return true
+	}
+	return false
+}
+
+func (tg *testGym) render() {
+	tg.obs.SetZeros()
+	tg.obs.Values[tg.pos] = 1
+}
+
+func (tg *testGym) Observation() tensor.Tensor { return &tg.obs }
+func (tg *testGym) Reward() float32            { return tg.rew }
+func (tg *testGym) NumActions() int            { return 2 }
+
+func TestGymEnv(t *testing.T) {
+	ev := &GymEnv{Name: "Train", Gym: &testGym{}}
+	ev.Init(0)
+	if ev.Gym.Observation().Len() != 3 {
+		t.Fatalf("observation not set after Init")
+	}
+	// right, stay, right: done on the 3rd step
+	actions := []int{1, 0, 1}
+	for i, a := range actions {
+		ev.Step()
+		if ev.Trial.Cur != i || ev.EpisodeStep.Cur != i {
+			t.Errorf("step %d: Trial %d EpisodeStep %d", i, ev.Trial.Cur, ev.EpisodeStep.Cur)
+		}
+		if ev.State("NextObs") != nil {
+			t.Errorf("step %d: NextObs before action", i)
+		}
+		act := tensor.NewFloat32([]int{1, 2})
+		act.Values[a] = 0.8
+		ev.Action("Action", act)
+		ev.TakeAction(1 - a) // ignored: only the first action is taken
+		if ev.Act != a || ev.State("Action").Float1D(a) != 1 {
+			t.Errorf("step %d: action %d, want %d", i, ev.Act, a)
+		}
+		done := i == len(actions)-1
+		if ev.Done != done {
+			t.Errorf("step %d: Done %v, want %v", i, ev.Done, done)
+		}
+		if done {
+			if ev.State("Rew").Float1D(0) != 1 {
+				t.Errorf("no reward at the end of the episode")
+			}
+			if stats.Sum32(ev.State("NextObs").(*tensor.Float32).Values) != 0 {
+				t.Errorf("NextObs not zero at the end of the episode")
+			}
+		} else if ev.Reward != 0 || ev.State("NextObs").Float1D(ev.Gym.(*testGym).pos) != 1 {
+			t.Errorf("step %d: Reward %g or NextObs wrong", i, ev.Reward)
+		}
+	}
+	// new episode
+	ev.Step()
+	if ev.Episode.Cur != 1 || ev.EpisodeStep.Cur != 0 || ev.Trial.Cur != 3 {
+		t.Errorf("new episode: Episode %d EpisodeStep %d Trial %d", ev.Episode.Cur, ev.EpisodeStep.Cur, ev.Trial.Cur)
+	}
+	if ev.LastEpisodeSteps != 3 || ev.LastEpisodeReward != 1 {
+		t.Errorf("last episode: steps %d reward %g, want 3, 1", ev.LastEpisodeSteps, ev.LastEpisodeReward)
+	}
+	if ev.State("Obs").Float1D(0) != 1 || ev.Act != -1 || ev.Reward != 0 {
+		t.Errorf("new episode not reset")
+	}
+}
@@ -6,9 +6,9 @@ package leabra
 
 import (
 	"cogentcore.org/core/base/errors"
-	"cogentcore.org/core/math32"
 	"github.com/emer/emergent/v2/etime"
 	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 // Contrastive Hebbian Learning (CHL) parameters
@@ -18,19 +18,19 @@ type CHLParams struct {
 	On bool
 
 	// amount of hebbian learning (should be relatively small, can be effective at .0001)
-	Hebb float32 `default:"0.001" min:"0" max:"1"`
+	Hebb Float `default:"0.001" min:"0" max:"1"`
 
 	// amount of error driven learning, automatically computed to be 1-Hebb
-	Err float32 `default:"0.999" min:"0" max:"1" edit:"-"`
+	Err Float `default:"0.999" min:"0" max:"1" edit:"-"`
 
 	// if true, use ActQ1 as the minus phase -- otherwise ActM
 	MinusQ1 bool
 
 	// proportion of correction to apply to sending average activation for hebbian learning component (0=none, 1=all, .5=half, etc)
-	SAvgCor float32 `default:"0.4:0.8" min:"0" max:"1"`
+	SAvgCor Float `default:"0.4:0.8" min:"0" max:"1"`
 
 	// threshold of sending average activation below which learning does not occur (prevents learning when there is no input)
-	SAvgThr float32 `default:"0.001" min:"0"`
+	SAvgThr Float `default:"0.001" min:"0"`
 }
 
 func (ch *CHLParams) Defaults() {
@@ -46,7 +46,7 @@ func (ch *CHLParams) Update() {
 }
 
 // MinusAct returns the minus-phase activation to use based on settings (ActM vs. ActQ1)
-func (ch *CHLParams) MinusAct(actM, actQ1 float32) float32 {
+func (ch *CHLParams) MinusAct(actM, actQ1 Float) Float {
 	if ch.MinusQ1 {
 		return actQ1
 	}
@@ -54,7 +54,7 @@ func (ch *CHLParams) MinusAct(actM, actQ1 float32) float32 {
 }
 
 // HebbDWt computes the hebbian DWt value from sending, recv acts, savgCor, and linear Wt
-func (ch *CHLParams) HebbDWt(sact, ract, savgCor, linWt float32) float32 {
+func (ch *CHLParams) HebbDWt(sact, ract, savgCor, linWt Float) Float {
 	return ract * (sact*(savgCor-linWt) - (1-sact)*linWt)
 }
 
@@ -62,7 +62,7 @@ func (ch *CHLParams) HebbDWt(sact, ract, savgCor, linWt float32) float32 {
 // recv acts in both phases, and linear Wt, which is used
 // for soft weight bounding (always applied here, separate from hebbian
 // which has its own soft weight bounding dynamic).
-func (ch *CHLParams) ErrDWt(sactP, sactM, ractP, ractM, linWt float32) float32 {
+func (ch *CHLParams) ErrDWt(sactP, sactM, ractP, ractM, linWt Float) Float {
 	err := (ractP * sactP) - (ractM * sactM)
 	if err > 0 {
 		err *= (1 - linWt)
@@ -73,7 +73,7 @@ func (ch *CHLParams) ErrDWt(sactP, sactM, ractP, ractM, linWt float32) float32 {
 }
 
 // DWt computes the overall dwt from hebbian and error terms
-func (ch *CHLParams) DWt(hebb, err float32) float32 {
+func (ch *CHLParams) DWt(hebb, err Float) Float {
 	return ch.Hebb*hebb + ch.Err*err
 }
 
@@ -85,9 +85,9 @@ func (pt *Path) CHLDefaults() {
 
 // SAvgCor computes the sending average activation, corrected according to the SAvgCor
 // correction factor (typically makes layer appear more sparse than it is)
-func (pt *Path) SAvgCor(slay *Layer) float32 {
+func (pt *Path) SAvgCor(slay *Layer) Float {
 	savg := .5 + pt.CHL.SAvgCor*(slay.Pools[0].ActAvg.ActPAvgEff-0.5)
-	savg = math32.Max(pt.CHL.SAvgThr, savg) // keep this computed value within bounds
+	savg = fmath.Max(pt.CHL.SAvgThr, savg) // keep this computed value within bounds
 	return 0.5 / savg
 }
 
@@ -98,50 +98,47 @@ func (pt *Path) DWtCHL() {
 	if slay.Pools[0].ActP.Avg < pt.CHL.SAvgThr { // inactive, no learn
 		return
 	}
+	enc, rec := pt.ThetaLrates()
+	mMult := rec // ActM minus phase is retrieval
+	if pt.CHL.MinusQ1 {
+		mMult = enc
+	}
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
+		norms := pt.Syns.Norm[st : st+nc]
+		moments := pt.Syns.Moment[st : st+nc]
+		lwts := pt.Syns.LWt[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
-		snActM := pt.CHL.MinusAct(sn.ActM, sn.ActQ1)
+		snActM := pt.CHL.MinusAct(sn.ActM, sn.ActQ1) * mMult
 
 		savgCor := pt.SAvgCor(slay)
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			ri := scons[ci]
 			rn := &rlay.Neurons[ri]
 			rnActM := pt.CHL.MinusAct(rn.ActM, rn.ActQ1)
 
-			hebb := pt.CHL.HebbDWt(sn.ActP, rn.ActP, savgCor, sy.LWt)
-			err := pt.CHL.ErrDWt(sn.ActP, snActM, rn.ActP, rnActM, sy.LWt)
+			hebb := pt.CHL.HebbDWt(sn.ActP, rn.ActP, savgCor, lwts[ci])
+			err := pt.CHL.ErrDWt(enc*sn.ActP, snActM, rn.ActP, rnActM, lwts[ci])
 
 			dwt := pt.CHL.DWt(hebb, err)
-			norm := float32(1)
+			norm := Float(1)
 			if pt.Learn.Norm.On {
-				norm = pt.Learn.Norm.NormFromAbsDWt(&sy.Norm, math32.Abs(dwt))
+				norm = pt.Learn.Norm.NormFromAbsDWt(&norms[ci], fmath.Abs(dwt))
 			}
 			if pt.Learn.Momentum.On {
-				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&sy.Moment, dwt)
+				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&moments[ci], dwt)
 			} else {
 				dwt *= norm
 			}
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 		// aggregate max DWtNorm over sending synapses
 		if pt.Learn.Norm.On {
-			maxNorm := float32(0)
-			for ci := range syns {
-				sy := &syns[ci]
-				if sy.Norm > maxNorm {
-					maxNorm = sy.Norm
-				}
-			}
-			for ci := range syns {
-				sy := &syns[ci]
-				sy.Norm = maxNorm
-			}
+			setValue(norms, maxValue(norms))
 		}
 	}
 }
@@ -157,96 +154,231 @@ func (pt *Path) EcCa1Defaults() {
 func (pt *Path) DWtEcCa1() {
 	slay := pt.Send
 	rlay := pt.Recv
+	enc, _ := pt.ThetaLrates()
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
+		norms := pt.Syns.Norm[st : st+nc]
+		moments := pt.Syns.Moment[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			ri := scons[ci]
 			rn := &rlay.Neurons[ri]
 
-			err := (sn.ActP * rn.ActP) - (sn.ActQ1 * rn.ActQ1)
+			err := enc * ((sn.ActP * rn.ActP) - (sn.ActQ1 * rn.ActQ1)) // both encoding
 			bcm := pt.Learn.BCMdWt(sn.AvgSLrn, rn.AvgSLrn, rn.AvgL)
 			bcm *= pt.Learn.XCal.LongLrate(rn.AvgLLrn)
 			err *= pt.Learn.XCal.MLrn
 			dwt := bcm + err
 
-			norm := float32(1)
+			norm := Float(1)
 			if pt.Learn.Norm.On {
-				norm = pt.Learn.Norm.NormFromAbsDWt(&sy.Norm, math32.Abs(dwt))
+				norm = pt.Learn.Norm.NormFromAbsDWt(&norms[ci], fmath.Abs(dwt))
 			}
 			if pt.Learn.Momentum.On {
-				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&sy.Moment, dwt)
+				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&moments[ci], dwt)
 			} else {
 				dwt *= norm
 			}
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 		// aggregate max DWtNorm over sending synapses
 		if pt.Learn.Norm.On {
-			maxNorm := float32(0)
-			for ci := range syns {
-				sy := &syns[ci]
-				if sy.Norm > maxNorm {
-					maxNorm = sy.Norm
-				}
-			}
-			for ci := range syns {
-				sy := &syns[ci]
-				sy.Norm = maxNorm
-			}
+			setValue(norms, maxValue(norms))
 		}
 	}
 }
 
-// ConfigLoopsHip configures the hippocampal looper and should be included in ConfigLoops
-// in model to make sure hip loops is configured correctly.
-// see hip.go for an instance of implementation of this function.
-func (net *Network) ConfigLoopsHip(ctx *Context, ls *looper.Stacks) {
-	var tmpValues []float32
-	ecout := net.LayerByName("ECout")
-	ecin := net.LayerByName("ECin")
+// ThetaPhaseParams are the theta-phase modulation parameters for the
+// hippocampus, which switch the drive of CA1 between ECin (encoding)
+// and CA3 (recall) across the quarters of the alpha cycle, and reduce
+// the strength of the DG -> CA3 mossy fibers during the first quarter.
+// These are applied by HipThetaPhase, which is called at the start
+// of the relevant quarters by ConfigLoopsHip.
+type ThetaPhaseParams struct {
+
+	// ThetaLow is the WtScale.Abs of the CA1 pathway that is not
+	// currently driving CA1: CA3 -> CA1 in the first and fourth quarters,
+	// and ECin -> CA1 in the second and third quarters.
+	ThetaLow Float `default:"0" min:"0" max:"1"`
+
+	// MossyDel is the amount subtracted from the MossyRel WtScale.Rel
+	// of the DG -> CA3 mossy fiber pathway during the first quarter,
+	// so CA3 is driven more by ECin (floored at 0).
+	MossyDel Float `default:"4" min:"0"`
+
+	// MossyDelTest is the amount subtracted from the MossyRel WtScale.Rel
+	// of the DG -> CA3 mossy fiber pathway after the first quarter during
+	// testing, so that recall is less driven by the DG (floored at 0).
+	MossyDelTest Float `default:"3" min:"0"`
+
+	// MossyRel is the base WtScale.Rel of the DG -> CA3 mossy fiber
+	// pathway, as set by params, which is recorded by ConfigLoopsHip,
+	// or at the start of the first alpha cycle if not set.
+	MossyRel Float `edit:"-"`
+
+	// Lrates are the learning rate multipliers for the encoding vs.
+	// retrieval quarters of the theta cycle, for each named pathway,
+	// for testing encoding / retrieval tradeoffs in learning.
+	// Pathways that are not listed learn normally.  Both Encode and
+	// Recall must be set for each entry, as there are no defaults.
+	Lrates []ThetaLrateParams
+}
+
+func (tp *ThetaPhaseParams) Defaults() {
+	tp.ThetaLow = 0
+	tp.MossyDel = 4
+	tp.MossyDelTest = 3
+}
+
+func (tp *ThetaPhaseParams) Update() {
+}
+
+// PathLrates returns the Encode and Recall learning rate multipliers
+// for the pathway of given name, which are 1 if it is not in Lrates.
+func (tp *ThetaPhaseParams) PathLrates(name string) (enc, rec Float) {
+	for i := range tp.Lrates {
+		if tl := &tp.Lrates[i]; tl.Path == name {
+			return tl.Encode, tl.Recall
+		}
+	}
+	return 1, 1
+}
+
+// MossyScale returns the mossy fiber WtScale.Rel for given quarter
+// (0-3) of the alpha cycle, and whether testing.
+func (tp *ThetaPhaseParams) MossyScale(qtr int, test bool) Float {
+	del := Float(0)
+	switch {
+	case qtr == 0:
+		del = tp.MossyDel
+	case test:
+		del = tp.MossyDelTest
+	}
+	return max(tp.MossyRel-del, 0)
+}
+
+// ThetaLrateParams are the learning rate multipliers for one pathway
+// during the encoding vs. retrieval quarters of the theta cycle.
+// The error-driven learning contrasts the activity coproducts at the
+// end of different quarters, so each term is multiplied according to
+// the quarter it reflects: the plus phase (fourth quarter) and the
+// ActQ1 minus phase of EcCa1Path and CHL MinusQ1 are encoding, where CA1
+// is driven by ECin, while the ActM (or AvgM) minus phase at the end of
+// the third quarter is retrieval, where CA1 is driven by CA3.  Thus,
+// Encode > Recall favors strengthening of the encoded patterns, and
+// Recall > Encode favors weakening of the recalled patterns.
+type ThetaLrateParams struct {
+
+	// Path is the name of the pathway, e.g., CA3ToCA1.
+	Path string
+
+	// Encode multiplies the encoding quarter activity coproducts.
+	Encode Float `default:"1" min:"0"`
+
+	// Recall multiplies the retrieval quarter activity coproducts.
+	Recall Float `default:"1" min:"0"`
+}
+
+func (tl *ThetaLrateParams) Defaults() {
+	tl.Encode = 1
+	tl.Recall = 1
+}
+
+// ThetaLrates returns the Encode and Recall learning rate multipliers
+// for this pathway from the Network Theta.Lrates, which are 1 if the
+// pathway is not listed there.
+func (pt *Path) ThetaLrates() (enc, rec Float) {
+	return pt.Recv.Network.Theta.PathLrates(pt.Name)
+}
+
+// HipThetaPhase applies the Theta theta-phase modulation of the
+// hippocampal pathways for the start of the given quarter (0-3)
+// of the alpha cycle, for the standard ECin, ECout, CA1, CA3 and DG
+// layers. Only quarters 0, 1 and 3 have changes:
+//   - Q1: CA1 is driven by ECin, and DG -> CA3 is weaker (MossyDel).
+//   - Q2, Q3: CA1 is driven by CA3 recall, and DG -> CA3 is at full
+//     strength for training, or reduced by MossyDelTest for testing.
+//   - Q4 (plus phase): CA1 is driven by ECin, and the ECin activity
+//     is clamped onto ECout as the target, for training.
+//
+// This is called automatically by the events added in ConfigLoopsHip,
+// and can be called directly for sims that do not use the looper.
+func (net *Network) HipThetaPhase(ctx *Context, qtr int) {
 	ca1 := net.LayerByName("CA1")
 	ca3 := net.LayerByName("CA3")
 	ca1FromECin := errors.Log1(ca1.RecvPathBySendName("ECin")).(*Path)
 	ca1FromCa3 := errors.Log1(ca1.RecvPathBySendName("CA3")).(*Path)
 	ca3FromDg := errors.Log1(ca3.RecvPathBySendName("DG")).(*Path)
+	tp := &net.Theta
+	switch qtr {
+	case 0:
+		if tp.MossyRel == 0 {
+			tp.MossyRel = ca3FromDg.WtScale.Rel
+		}
+		ca1FromECin.WtScale.Abs = 1
+		ca1FromCa3.WtScale.Abs = tp.ThetaLow
+		ca3FromDg.WtScale.Rel = tp.MossyScale(qtr, ctx.Mode == etime.Test)
+	case 1:
+		ca1FromECin.WtScale.Abs = tp.ThetaLow
+		ca1FromCa3.WtScale.Abs = 1
+		ca3FromDg.WtScale.Rel = tp.MossyScale(qtr, ctx.Mode == etime.Test)
+	case 3:
+		ca1FromECin.WtScale.Abs = 1
+		ca1FromCa3.WtScale.Abs = tp.ThetaLow
+		if ctx.Mode == etime.Train {
+			var tmpValues []float32
+			net.LayerByName("ECin").UnitValues(&tmpValues, "Act", 0)
+			net.LayerByName("ECout").ApplyExt1D32(tmpValues)
+		}
+	default:
+		return
+	}
+	net.GScaleFromAvgAct()
+	net.InitGInc()
+}
 
-	dgPjScale := ca3FromDg.WtScale.Rel
+// hipMossyPath returns the DG -> CA3 mossy fiber pathway, if the network
+// has a hippocampus with the Theta.MossyRel recorded, and otherwise nil.
+func (net *Network) hipMossyPath() *Path {
+	if net.Theta.MossyRel == 0 {
+		return nil
+	}
+	ca3 := net.LayerByName("CA3")
+	if ca3 == nil {
+		return nil
+	}
+	pt, err := ca3.RecvPathBySendName("DG")
+	if err != nil {
+		return nil
+	}
+	return pt.(*Path)
+}
+
+// ConfigLoopsHip configures the hippocampal looper and should be included in ConfigLoops
+// in model to make sure hip loops is configured correctly.
+// It adds events that call HipThetaPhase at the start of the relevant
+// quarters, using the network Theta parameters, and records the current
+// DG -> CA3 WtScale.Rel as the Theta.MossyRel, so params must already
+// be applied. See hip.go for an instance of implementation of this function.
+func (net *Network) ConfigLoopsHip(ctx *Context, ls *looper.Stacks) {
+	ca3 := net.LayerByName("CA3")
+	ca3FromDg := errors.Log1(ca3.RecvPathBySendName("DG")).(*Path)
+	net.Theta.MossyRel = ca3FromDg.WtScale.Rel
 
 	ls.AddEventAllModes(etime.Cycle, "HipMinusPhase:Start", 0, func() {
-		ca1FromECin.WtScale.Abs = 1
-		ca1FromCa3.WtScale.Abs = 0
-		ca3FromDg.WtScale.Rel = 0
-		net.GScaleFromAvgAct()
-		net.InitGInc()
+		net.HipThetaPhase(ctx, 0)
 	})
 	ls.AddEventAllModes(etime.Cycle, "Hip:Quarter1", 25, func() {
-		ca1FromECin.WtScale.Abs = 0
-		ca1FromCa3.WtScale.Abs = 1
-		if ctx.Mode == etime.Test {
-			ca3FromDg.WtScale.Rel = 1 // weaker
-		} else {
-			ca3FromDg.WtScale.Rel = dgPjScale
-		}
-		net.GScaleFromAvgAct()
-		net.InitGInc()
+		net.HipThetaPhase(ctx, 1)
 	})
 	for _, st := range ls.Stacks {
 		ev := st.Loops[etime.Cycle].EventByCounter(75)
 		ev.OnEvent.Prepend("HipPlusPhase:Start", func() bool {
-			ca1FromECin.WtScale.Abs = 1
-			ca1FromCa3.WtScale.Abs = 0
-			if ctx.Mode == etime.Train {
-				ecin.UnitValues(&tmpValues, "Act", 0)
-				ecout.ApplyExt1D32(tmpValues)
-			}
-			net.GScaleFromAvgAct()
-			net.InitGInc()
+			net.HipThetaPhase(ctx, 3)
 			return true
 		})
 	}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/math32/vecint"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// PFCToECinPath returns a PoolRect pathway that connects all of the units
+// in the sending pfc layer into the pools of the receiving ECin layer
+// starting at the ctxtStart pool position, for ctxtN pools
+// (0 = all remaining pools).  ctxtStart and ctxtN are in X, Y order.
+func PFCToECinPath(pfc *Layer, ctxtStart, ctxtN vecint.Vector2i) *paths.PoolRect {
+	pt := paths.NewPoolRect()
+	pt.Wrap = false
+	pt.Scale.SetScalar(0) // all recv pools get the same full set of sending pools
+	if pfc.Is4D() {
+		pt.Size.Set(pfc.Shape.DimSize(1), pfc.Shape.DimSize(0))
+	}
+	pt.RecvStart = ctxtStart
+	pt.RecvN = ctxtN
+	return pt
+}
+
+// ConnectPFCToECin connects a PBWM PFC deep layer (typically the
+// maintenance deep layer, e.g., PFCmntD) into the context pools of
+// the hippocampal ECin layer, so that the task context maintained in
+// PFC can shape hippocampal encoding and retrieval.
+// The context pools start at the ctxtStart pool position in ECin,
+// for ctxtN pools (0 = all remaining pools), in X, Y order.
+// The pathway has the class PFCToECin, and its WtScale.Rel should
+// typically be set relatively low, so that PFC modulates rather than
+// overrides the cortical inputs to ECin.
+// If the layers are already connected by a PFCToECin pathway, its
+// context pools are updated and it is returned, instead of adding
+// a duplicate pathway (the network must be re-built after that).
+func (nt *Network) ConnectPFCToECin(pfcD, ecin *Layer, ctxtStart, ctxtN vecint.Vector2i) *Path {
+	for _, pt := range ecin.RecvPaths {
+		if pt.Send == pfcD && slices.Contains(strings.Fields(pt.Class), "PFCToECin") {
+			pt.Pattern = PFCToECinPath(pfcD, ctxtStart, ctxtN)
+			return pt
+		}
+	}
+	pt := nt.ConnectLayers(pfcD, ecin, PFCToECinPath(pfcD, ctxtStart, ctxtN), ForwardPath)
+	pt.AddClass("PFCToECin")
+	pt.Doc = "Prefrontal cortex (PFC) maintained task context, projecting into the context pools of the entorhinal cortex input (ECin), providing context-dependent memory encoding and retrieval"
+	return pt
+}
+
+// ConnectPFCMaintToECin connects the PFC maintenance deep layer created by
+// AddPBWM or AddPFC with given prefix (prefix+"mntD", prefix defaults to PFC)
+// into the context pools of the named ECin layer.
+// See ConnectPFCToECin for details.
+func (nt *Network) ConnectPFCMaintToECin(prefix, ecinName string, ctxtStart, ctxtN vecint.Vector2i) (*Path, error) {
+	if prefix == "" {
+		prefix = "PFC"
+	}
+	pfcD, err := nt.EmerLayerByName(prefix + "mntD")
+	if err != nil {
+		return nil, fmt.Errorf("ConnectPFCMaintToECin: %w", err)
+	}
+	ecin, err := nt.EmerLayerByName(ecinName)
+	if err != nil {
+		return nil, fmt.Errorf("ConnectPFCMaintToECin: %w", err)
+	}
+	return nt.ConnectPFCToECin(pfcD.(*Layer), ecin.(*Layer), ctxtStart, ctxtN), nil
+}
@@ -0,0 +1,68 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/math32/vecint"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestConnectPFCToECin(t *testing.T) {
+	net := NewNetwork("HipPFC")
+	ecin := net.AddLayer4D("ECin", 4, 2, 2, 2, SuperLayer)
+	_, _, _, _, _, _, pfcD, _, _ := net.AddPBWM("", 1, 2, 0, 1, 3, 2, 2)
+
+	ctxtStart := vecint.Vector2i{X: 0, Y: 2}
+	pt, err := net.ConnectPFCMaintToECin("", "ECin", ctxtStart, vecint.Vector2i{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pt.Send != pfcD || pt.Recv != ecin || pt.Type != ForwardPath || !slices.Contains(strings.Fields(pt.Class), "PFCToECin") {
+		t.Errorf("pathway: %s -> %s type %v class %q", pt.Send.Name, pt.Recv.Name, pt.Type, pt.Class)
+	}
+	pr, ok := pt.Pattern.(*paths.PoolRect)
+	if !ok {
+		t.Fatalf("pattern is %T, want *paths.PoolRect", pt.Pattern)
+	}
+	if pr.RecvStart != ctxtStart || pr.RecvN != (vecint.Vector2i{}) || pr.Size != (vecint.Vector2i{X: 2, Y: 1}) || pr.Wrap {
+		t.Errorf("PoolRect: RecvStart %v RecvN %v Size %v Wrap %v", pr.RecvStart, pr.RecvN, pr.Size, pr.Wrap)
+	}
+
+	// connecting again updates the existing pathway instead of adding a duplicate
+	ctxtStart.Y = 3
+	npt := net.ConnectPFCToECin(pfcD, ecin, ctxtStart, vecint.Vector2i{})
+	if npt != pt || len(ecin.RecvPaths) != 1 || len(pfcD.SendPaths) != 1 {
+		t.Errorf("duplicate pathways: ECin has %d recv paths, %s has %d send paths", len(ecin.RecvPaths), pfcD.Name, len(pfcD.SendPaths))
+	}
+	if pr = pt.Pattern.(*paths.PoolRect); pr.RecvStart != ctxtStart {
+		t.Errorf("PoolRect not updated: RecvStart %v", pr.RecvStart)
+	}
+
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	// only the context pools receive from all of the PFC units
+	npool := ecin.Shape.DimSize(2) * ecin.Shape.DimSize(3)
+	for ri := range ecin.Neurons {
+		want := 0
+		if ri/npool/ecin.Shape.DimSize(1) >= ctxtStart.Y {
+			want = len(pfcD.Neurons)
+		}
+		if n := int(pt.RConN[ri]); n != want {
+			t.Errorf("ECin unit %d: %d connections, want %d", ri, n, want)
+		}
+	}
+
+	if _, err := net.ConnectPFCMaintToECin("Nope", "ECin", ctxtStart, vecint.Vector2i{}); err == nil {
+		t.Error("ConnectPFCMaintToECin: expected error for missing PFC layer")
+	}
+	if _, err := net.ConnectPFCMaintToECin("", "Nope", ctxtStart, vecint.Vector2i{}); err == nil {
+		t.Error("ConnectPFCMaintToECin: expected error for missing ECin layer")
+	}
+}
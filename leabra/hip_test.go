@@ -0,0 +1,65 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestThetaLrates(t *testing.T) {
+	net := NewNetwork("ThetaLrates")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	hid2 := net.AddLayer2D("Hidden2", 2, 2, SuperLayer)
+	chl := net.ConnectLayers(in, hid, paths.NewFull(), CHLPath)
+	xcal := net.ConnectLayers(in, hid2, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	xcal.Learn.Norm.On = false
+	xcal.Learn.Momentum.On = false
+	net.InitWeights()
+	// plus phase (encoding) activity is greater than the minus phase (retrieval)
+	for _, ly := range []*Layer{in, hid, hid2} {
+		for ni := range ly.Neurons {
+			nrn := &ly.Neurons[ni]
+			nrn.ActP, nrn.AvgS, nrn.AvgSLrn = 0.8, 0.8, 0.8
+			nrn.ActM, nrn.ActQ1, nrn.AvgM = 0.5, 0.5, 0.5
+		}
+		ly.Pools[0].ActP.Avg = 0.8
+	}
+	sumDWt := func(pt *Path) float64 {
+		clear(pt.Syns.DWt)
+		pt.DWt()
+		var sum float64
+		for _, dw := range pt.Syns.DWt {
+			sum += float64(dw)
+		}
+		return sum
+	}
+	for _, pt := range []*Path{chl, xcal} {
+		net.Theta.Lrates = nil
+		base := sumDWt(pt)
+		if base <= 0 {
+			t.Errorf("%s: DWt should be positive: %g", pt.Name, base)
+		}
+		net.Theta.Lrates = []ThetaLrateParams{{Path: pt.Name, Encode: 1, Recall: 1}}
+		if dw := sumDWt(pt); math.Abs(dw-base) > 1e-6 {
+			t.Errorf("%s: DWt with unit multipliers: %g, want %g", pt.Name, dw, base)
+		}
+		net.Theta.Lrates[0].Encode = 0.25 // retrieval dominates
+		if dw := sumDWt(pt); dw >= 0 {
+			t.Errorf("%s: DWt with weak encoding should be negative: %g", pt.Name, dw)
+		}
+		net.Theta.Lrates[0] = ThetaLrateParams{Path: "Other", Encode: 0.25, Recall: 1}
+		if dw := sumDWt(pt); math.Abs(dw-base) > 1e-6 {
+			t.Errorf("%s: DWt with other pathway multipliers: %g, want %g", pt.Name, dw, base)
+		}
+	}
+}
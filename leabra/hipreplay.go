@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"cogentcore.org/core/base/randx"
+)
+
+// ReplayParams are the parameters for offline hippocampal replay,
+// where CA3 is driven by a partial cue from a stored CA3 pattern,
+// or by spontaneous random activity, with no cortical (ECin) input,
+// and the resulting pattern completion in CA3 drives recall in ECout
+// via CA1.  The ECout activity can then be used as a training signal
+// for a neocortical network, for systems consolidation simulations.
+// See Network.HipReplay.
+type ReplayParams struct {
+
+	// CueFrac is the proportion of the active units in the cue pattern
+	// that are clamped on CA3, as a partial cue for pattern completion.
+	CueFrac float32 `default:"0.25" min:"0" max:"1"`
+
+	// CueThr is the threshold, relative to the maximum value in the cue
+	// pattern, for units to be considered active in the cue.
+	CueThr float32 `default:"0.5" min:"0" max:"1"`
+
+	// SpontPct is the proportion of CA3 units that are randomly clamped
+	// on for spontaneous replay, when there is no cue pattern.
+	SpontPct float32 `default:"0.01" min:"0" max:"1"`
+
+	// CueCycles is the number of cycles that the cue is clamped on CA3,
+	// after which CA3 is free to settle into a completed pattern.
+	// With the standard hippocampus params, CA3 activity dies out if the
+	// cue is released too early, once many items have been stored.
+	CueCycles int `default:"40" min:"1"`
+
+	// Cycles is the total number of cycles to settle for each replay.
+	Cycles int `default:"75" min:"1"`
+}
+
+func (rp *ReplayParams) Defaults() {
+	rp.CueFrac = 0.25
+	rp.CueThr = 0.5
+	rp.SpontPct = 0.01
+	rp.CueCycles = 40
+	rp.Cycles = 75
+}
+
+func (rp *ReplayParams) Update() {
+}
+
+// CueUnits returns the indexes of the units to clamp on CA3 for given
+// cue pattern (e.g., a CA3 activity pattern recorded during training),
+// as a random CueFrac proportion of its active units, or a random
+// SpontPct proportion of nUnits if cue is nil.  At least one unit is
+// returned if there are any active units.  The units are chosen using
+// the given random number generator.
+func (rp *ReplayParams) CueUnits(cue []float32, nUnits int, rnd randx.Rand) []int {
+	if cue == nil {
+		n := max(int(rp.SpontPct*float32(nUnits)+0.5), 1)
+		return rnd.Perm(nUnits)[:min(n, nUnits)]
+	}
+	mx := float32(0)
+	for _, v := range cue {
+		mx = max(mx, v)
+	}
+	if mx <= 0 {
+		return nil
+	}
+	thr := rp.CueThr * mx
+	var on []int
+	for i, v := range cue[:min(len(cue), nUnits)] {
+		if v > thr {
+			on = append(on, i)
+		}
+	}
+	randx.PermuteInts(on, rnd)
+	n := max(int(rp.CueFrac*float32(len(on))+0.5), 1)
+	return on[:min(n, len(on))]
+}
+
+// HipReplay runs one offline replay trial in a hippocampal network with
+// the standard ECout, CA1, CA3 layers (as in the hip example), where CA3 is driven by
+// a partial cue from the given cue pattern (or spontaneous activity if nil),
+// as specified in ReplayParams, with all external inputs (including ECin)
+// turned off, and CA1 driven by CA3 as in the recall phase of HipThetaPhase.
+// The resulting ECout activity is returned in ecout, which can be used as
+// a training pattern for a neocortical network.  There is no learning in
+// the hippocampal network, and the activity state is left as it is at
+// the end of replay, so call this between regular trials.
+func (net *Network) HipReplay(ctx *Context, rp *ReplayParams, cue []float32, ecout *[]float32) {
+	ca3 := net.LayerByName("CA3")
+	net.InitExt()
+	net.AlphaCycInit(false)
+	ctx.AlphaCycStart()
+	net.HipThetaPhase(ctx, 1)
+	clear, set, _ := ca3.ApplyExtFlags()
+	for _, ni := range rp.CueUnits(cue, len(ca3.Neurons), &ca3.Rand) {
+		ca3.ApplyExtValue(ni, 1, clear, set, false)
+	}
+	for cyc := range rp.Cycles {
+		if cyc == rp.CueCycles {
+			ca3.InitExt()
+		}
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	ca3.InitExt()
+	net.LayerByName("ECout").UnitValues(ecout, "Act", 0)
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+)
+
+func TestCueUnits(t *testing.T) {
+	rp := &ReplayParams{}
+	rp.Defaults()
+	rnd := randx.NewSysRand(1)
+
+	cue := make([]float32, 20)
+	for _, i := range []int{1, 3, 4, 8, 9, 12, 15, 19} {
+		cue[i] = 0.9
+	}
+	cue[5] = 0.4 // below CueThr * max
+	tests := []struct {
+		name    string
+		cue     []float32
+		nUnits  int
+		cueFrac float32
+		n       int
+		from    []int
+	}{
+		{"cue frac", cue, 20, 0.25, 2, []int{1, 3, 4, 8, 9, 12, 15, 19}},
+		{"all", cue, 20, 1, 8, []int{1, 3, 4, 8, 9, 12, 15, 19}},
+		{"at least one", cue, 20, 0, 1, []int{1, 3, 4, 8, 9, 12, 15, 19}},
+		{"n units", cue, 10, 1, 5, []int{1, 3, 4, 8, 9}},
+		{"zero cue", make([]float32, 20), 20, 0.25, 0, nil},
+		{"spontaneous", nil, 200, 0.25, 2, nil},
+	}
+	for _, tt := range tests {
+		rp.CueFrac = tt.cueFrac
+		units := rp.CueUnits(tt.cue, tt.nUnits, rnd)
+		if len(units) != tt.n {
+			t.Errorf("%s: got %d units %v, want %d", tt.name, len(units), units, tt.n)
+		}
+		seen := map[int]bool{}
+		for _, ui := range units {
+			if seen[ui] || ui < 0 || ui >= tt.nUnits || (tt.from != nil && !slices.Contains(tt.from, ui)) {
+				t.Errorf("%s: invalid unit %d in %v", tt.name, ui, units)
+			}
+			seen[ui] = true
+		}
+	}
+
+	// the units are a random subset
+	rp.CueFrac = 0.25
+	first := rp.CueUnits(cue, 20, rnd)
+	differ := false
+	for range 10 {
+		if !slices.Equal(rp.CueUnits(cue, 20, rnd), first) {
+			differ = true
+		}
+	}
+	if !differ {
+		t.Errorf("CueUnits always chose the same units: %v", first)
+	}
+}
+
+func TestHipReplay(t *testing.T) {
+	net := makeHipNet(1)
+	ctx := NewContext()
+	for range 2 {
+		net.AlphaCycle(ctx, true)
+	}
+	net.AlphaCycle(ctx, false)
+	ca3 := net.LayerByName("CA3")
+	var cue []float32
+	ca3.UnitValues(&cue, "Act", 0)
+
+	// learnState returns the weights, weight changes and long-term
+	// average activities of the network.
+	learnState := func() (uint64, []Float, []Float) {
+		var dwts, avgs []Float
+		for _, ly := range net.Layers {
+			for ni := range ly.Neurons {
+				avgs = append(avgs, ly.Neurons[ni].AvgL, ly.Neurons[ni].AvgLLrn)
+			}
+			for pi := range ly.Pools {
+				avgs = append(avgs, ly.Pools[pi].ActAvg.ActPAvg)
+			}
+			for _, pt := range ly.RecvPaths {
+				dwts = append(dwts, pt.Syns.DWt...)
+			}
+		}
+		return net.WtsFingerprint(), dwts, avgs
+	}
+	wts, dwts, avgs := learnState()
+
+	rp := &ReplayParams{}
+	rp.Defaults()
+	var ecout []float32
+	net.HipReplay(ctx, rp, cue, &ecout)
+	if len(ecout) != len(net.LayerByName("ECout").Neurons) {
+		t.Fatalf("ecout has %d values", len(ecout))
+	}
+	if slices.Max(ecout) <= 0 {
+		t.Error("replay produced no ECout activity")
+	}
+	for ni := range ca3.Neurons {
+		if ca3.Neurons[ni].HasFlag(NeurHasExt) {
+			t.Fatal("CA3 cue still clamped after replay")
+		}
+	}
+	for _, ly := range net.Layers {
+		if ly.Type == InputLayer && slices.ContainsFunc(ly.Neurons, func(nrn Neuron) bool { return nrn.Ext != 0 }) {
+			t.Errorf("input layer %s has external input during replay", ly.Name)
+		}
+	}
+	rwts, rdwts, ravgs := learnState()
+	if rwts != wts {
+		t.Error("replay changed the weights")
+	}
+	if !slices.Equal(rdwts, dwts) {
+		t.Error("replay changed the weight changes (DWt)")
+	}
+	if !slices.Equal(ravgs, avgs) {
+		t.Error("replay changed the long-term average activities")
+	}
+
+	net.HipReplay(ctx, rp, nil, &ecout) // spontaneous
+	if w, _, _ := learnState(); w != wts {
+		t.Error("spontaneous replay changed the weights")
+	}
+}
@@ -50,13 +50,13 @@ type SelfInhibParams struct {
 	On bool
 
 	// strength of individual neuron self feedback inhibition -- can produce proportional activation behavior in individual units for specialized cases (e.g., scalar val or BG units), but not so good for typical hidden layers
-	Gi float32 `default:"0.4"`
+	Gi Float `default:"0.4"`
 
 	// time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life) for integrating unit self feedback inhibitory values -- prevents oscillations that otherwise occur -- relatively rapid 1.4 typically works, but may need to go longer if oscillations are a problem
-	Tau float32 `default:"1.4"`
+	Tau Float `default:"1.4"`
 
 	// rate = 1 / tau
-	Dt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	Dt Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 func (si *SelfInhibParams) Update() {
@@ -80,7 +80,7 @@ func (si *SelfInhibParams) ShouldDisplay(field string) bool {
 }
 
 // Inhib updates the self inhibition value based on current unit activation
-func (si *SelfInhibParams) Inhib(self *float32, act float32) {
+func (si *SelfInhibParams) Inhib(self *Float, act Float) {
 	if si.On {
 		*self += si.Dt * (si.Gi*act - *self)
 	} else {
@@ -98,7 +98,7 @@ func (si *SelfInhibParams) Inhib(self *float32, act float32) {
 type ActAvgParams struct {
 
 	// initial estimated average activity level in the layer (see also UseFirst option -- if that is off then it is used as a starting point for running average actual activity level, ActMAvg and ActPAvg) -- ActPAvg is used primarily for automatic netinput scaling, to balance out layers that have different activity levels -- thus it is important that init be relatively accurate -- good idea to update from recorded ActPAvg levels
-	Init float32 `min:"0"`
+	Init Float `min:"0"`
 
 	// if true, then the Init value is used as a constant for ActPAvgEff (the effective value used for netinput rescaling), instead of using the actual running average activation
 	Fixed bool `default:"false"`
@@ -110,13 +110,13 @@ type ActAvgParams struct {
 	UseFirst bool `default:"true"`
 
 	// time constant in trials for integrating time-average values at the layer level -- used for computing Pool.ActAvg.ActsMAvg, ActsPAvg
-	Tau float32 `default:"100" min:"1"`
+	Tau Float `default:"100" min:"1"`
 
 	// adjustment multiplier on the computed ActPAvg value that is used to compute ActPAvgEff, which is actually used for netinput rescaling -- if based on connectivity patterns or other factors the actual running-average value is resulting in netinputs that are too high or low, then this can be used to adjust the effective average activity value -- reducing the average activity with a factor < 1 will increase netinput scaling (stronger net inputs from layers that receive from this layer), and vice-versa for increasing (decreases net inputs)
-	Adjust float32 `default:"1"`
+	Adjust Float `default:"1"`
 
 	// rate = 1 / tau
-	Dt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	Dt Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 func (aa *ActAvgParams) Update() {
@@ -143,7 +143,7 @@ func (aa *ActAvgParams) ShouldDisplay(field string) bool {
 }
 
 // EffInit returns the initial value applied during InitWeights for the AvgPAvgEff effective layer activity
-func (aa *ActAvgParams) EffInit() float32 {
+func (aa *ActAvgParams) EffInit() Float {
 	if aa.Fixed {
 		return aa.Init
 	}
@@ -151,7 +151,7 @@ func (aa *ActAvgParams) EffInit() float32 {
 }
 
 // AvgFromAct updates the running-average activation given average activity level in layer
-func (aa *ActAvgParams) AvgFromAct(avg *float32, act float32) {
+func (aa *ActAvgParams) AvgFromAct(avg *Float, act Float) {
 	if act < 0.0001 {
 		return
 	}
@@ -163,7 +163,7 @@ func (aa *ActAvgParams) AvgFromAct(avg *float32, act float32) {
 }
 
 // EffFromAvg updates the effective value from the running-average value
-func (aa *ActAvgParams) EffFromAvg(eff *float32, avg float32) {
+func (aa *ActAvgParams) EffFromAvg(eff *Float, avg Float) {
 	if aa.Fixed {
 		*eff = aa.Init
 	} else {
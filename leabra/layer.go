@@ -6,12 +6,11 @@ package leabra
 
 import (
 	"log"
-	"math/rand"
 
 	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/enums"
-	"cogentcore.org/core/math32"
 	"cogentcore.org/core/tensor"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -34,6 +33,8 @@ func (ly *Layer) InitWeights() {
 		pl.ActAvg.ActPAvgEff = ly.Inhib.ActAvg.EffInit()
 	}
 	ly.InitActAvg()
+	ly.InitExcit()
+	ly.InitCtxtLag()
 	ly.InitActs()
 	ly.CosDiff.Init()
 	ly.SetDriverOffs()
@@ -62,6 +63,8 @@ func (ly *Layer) InitActs() {
 		pl.ActP.Init()
 	}
 	ly.NeuroMod.Init()
+	ly.ValueIn.Init()
+	ly.DaDipAdapt = 0
 }
 
 // UpdateActAvgEff updates the effective ActAvg.ActPAvgEff value used in netinput
@@ -147,7 +150,7 @@ func (ly *Layer) ApplyExt(ext tensor.Tensor) {
 // ApplyExtVal applies given external value to given neuron
 // using clearMask, setMask, and toTarg from ApplyExtFlags.
 // Also saves Val in Exts for potential use by GPU.
-func (ly *Layer) ApplyExtValue(lni int, val float32, clear, set []enums.BitFlag, toTarg bool) {
+func (ly *Layer) ApplyExtValue(lni int, val Float, clear, set []enums.BitFlag, toTarg bool) {
 	nrn := &ly.Neurons[lni]
 	if nrn.IsOff() {
 		return
@@ -169,7 +172,7 @@ func (ly *Layer) ApplyExt2D(ext tensor.Tensor) {
 	for y := 0; y < ymx; y++ {
 		for x := 0; x < xmx; x++ {
 			idx := []int{y, x}
-			vl := float32(ext.Float(idx))
+			vl := Float(ext.Float(idx))
 			i := ly.Shape.Offset(idx)
 			ly.ApplyExtValue(i, vl, clear, set, toTarg)
 		}
@@ -186,7 +189,7 @@ func (ly *Layer) ApplyExt2Dto4D(ext tensor.Tensor) {
 	for y := 0; y < ymx; y++ {
 		for x := 0; x < xmx; x++ {
 			idx := []int{y, x}
-			vl := float32(ext.Float(idx))
+			vl := Float(ext.Float(idx))
 			ui := tensor.Projection2DIndex(&ly.Shape, false, y, x)
 			ly.ApplyExtValue(ui, vl, clear, set, toTarg)
 		}
@@ -205,7 +208,7 @@ func (ly *Layer) ApplyExt4D(ext tensor.Tensor) {
 			for yn := 0; yn < ynmx; yn++ {
 				for xn := 0; xn < xnmx; xn++ {
 					idx := []int{yp, xp, yn, xn}
-					vl := float32(ext.Float(idx))
+					vl := Float(ext.Float(idx))
 					i := ly.Shape.Offset(idx)
 					ly.ApplyExtValue(i, vl, clear, set, toTarg)
 				}
@@ -221,7 +224,7 @@ func (ly *Layer) ApplyExt1DTsr(ext tensor.Tensor) {
 	clear, set, toTarg := ly.ApplyExtFlags()
 	mx := min(ext.Len(), len(ly.Neurons))
 	for i := 0; i < mx; i++ {
-		vl := float32(ext.Float1D(i))
+		vl := Float(ext.Float1D(i))
 		ly.ApplyExtValue(i, vl, clear, set, toTarg)
 	}
 }
@@ -233,7 +236,7 @@ func (ly *Layer) ApplyExt1D(ext []float64) {
 	clear, set, toTarg := ly.ApplyExtFlags()
 	mx := min(len(ext), len(ly.Neurons))
 	for i := 0; i < mx; i++ {
-		vl := float32(ext[i])
+		vl := Float(ext[i])
 		ly.ApplyExtValue(i, vl, clear, set, toTarg)
 	}
 }
@@ -248,7 +251,7 @@ func (ly *Layer) ApplyExt1D32(ext []float32) {
 	clear, set, toTarg := ly.ApplyExtFlags()
 	mx := min(len(ext), len(ly.Neurons))
 	for i := 0; i < mx; i++ {
-		vl := ext[i]
+		vl := Float(ext[i])
 		ly.ApplyExtValue(i, vl, clear, set, toTarg)
 	}
 }
@@ -315,10 +318,12 @@ func (ly *Layer) AlphaCycInit(updtActAvg bool) {
 		ly.GenNoise()
 	}
 	ly.DecayState(ly.Act.Init.Decay)
+	ly.DecayAHP(ly.Act.AHP.ITI)
 	ly.InitGInc()
 	if ly.Act.Clamp.Hard && ly.Type == InputLayer {
 		ly.HardClamp()
 	}
+	ly.DropoutInit(updtActAvg)
 }
 
 // AvgLFromAvgM updates AvgL long-term running average activation that drives BCM Hebbian learning
@@ -341,8 +346,8 @@ func (ly *Layer) AvgLFromAvgM() {
 // coming into the units to achieve a general target of around .5 to 1
 // for the integrated Ge value.
 func (ly *Layer) GScaleFromAvgAct() {
-	totGeRel := float32(0)
-	totGiRel := float32(0)
+	totGeRel := Float(0)
+	totGiRel := Float(0)
 	for _, pt := range ly.RecvPaths {
 		if pt.Off {
 			continue
@@ -351,8 +356,8 @@ func (ly *Layer) GScaleFromAvgAct() {
 		slpl := &slay.Pools[0]
 		savg := slpl.ActAvg.ActPAvgEff
 		snu := len(slay.Neurons)
-		ncon := pt.RConNAvgMax.Avg
-		pt.GScale = pt.WtScale.FullScale(savg, float32(snu), ncon)
+		ncon := Float(pt.RConNAvgMax.Avg)
+		pt.GScale = pt.WtScale.FullScale(savg, Float(snu), ncon)
 		// reverting this change: if you want to eliminate a path, set the Off flag
 		// if you want to negate it but keep the relative factor in the denominator
 		// then set the scale to 0.
@@ -386,13 +391,13 @@ func (ly *Layer) GScaleFromAvgAct() {
 func (ly *Layer) GenNoise() {
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
-		nrn.Noise = float32(ly.Act.Noise.Gen())
+		nrn.Noise = ly.Act.Noise.GenRand(ly.noiseRand(nrn))
 	}
 }
 
 // DecayState decays activation state by given proportion (default is on ly.Act.Init.Decay).
 // This does *not* call InitGInc -- must call that separately at start of AlphaCyc
-func (ly *Layer) DecayState(decay float32) {
+func (ly *Layer) DecayState(decay Float) {
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
@@ -404,11 +409,16 @@ func (ly *Layer) DecayState(decay float32) {
 		pl := &ly.Pools[pi]
 		pl.Inhib.Decay(decay)
 	}
+	for _, sp := range ly.SendPaths {
+		if sp.STP.On {
+			sp.DecaySTP(decay)
+		}
+	}
 }
 
 // DecayStatePool decays activation state by given proportion
 // in given pool index (sub pools start at 1).
-func (ly *Layer) DecayStatePool(pool int, decay float32) {
+func (ly *Layer) DecayStatePool(pool int, decay Float) {
 	pl := &ly.Pools[pool]
 	for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
 		nrn := &ly.Neurons[ni]
@@ -457,33 +467,81 @@ func (ly *Layer) InitGInc() {
 }
 
 // SendGDelta sends change in activation since last sent, to increment recv
-// synaptic conductances G, if above thresholds
+// synaptic conductances G, if above thresholds.  The activation is scaled
+// by Dropout.SendScale, to compensate for any units dropped in training.
 func (ly *Layer) SendGDelta(ctx *Context) {
+	scale := ly.Dropout.SendScale()
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
 		}
-		if nrn.Act > ly.Act.OptThresh.Send {
-			delta := nrn.Act - nrn.ActSent
-			if math32.Abs(delta) > ly.Act.OptThresh.Delta {
-				for _, sp := range ly.SendPaths {
-					if sp.Off {
-						continue
-					}
-					sp.SendGDelta(ni, delta)
-				}
-				nrn.ActSent = nrn.Act
-			}
-		} else if nrn.ActSent > ly.Act.OptThresh.Send {
-			delta := -nrn.ActSent // un-send the last above-threshold activation to get back to 0
-			for _, sp := range ly.SendPaths {
-				if sp.Off {
-					continue
-				}
-				sp.SendGDelta(ni, delta)
+		delta, sent, send := ly.sendDelta(nrn, scale)
+		if !send {
+			continue
+		}
+		for _, sp := range ly.SendPaths {
+			if sp.Off || sp.STP.On {
+				continue
 			}
-			nrn.ActSent = 0
+			sp.SendGDelta(ni, delta)
+		}
+		nrn.ActSent = sent
+	}
+	for _, sp := range ly.SendPaths {
+		if !sp.Off && sp.STP.On {
+			sp.SendGDeltaSTP()
+		}
+	}
+}
+
+// sendDelta returns the change in activation to send for given neuron,
+// with its activation scaled by scale, and the new ActSent value after
+// sending it, with send = false if it is below the thresholds.
+func (ly *Layer) sendDelta(nrn *Neuron, scale Float) (delta, sent Float, send bool) {
+	act := nrn.Act * scale
+	if act > ly.Act.OptThresh.Send {
+		delta = act - nrn.ActSent
+		if fmath.Abs(delta) > ly.Act.OptThresh.Delta {
+			return delta, act, true
+		}
+	} else if nrn.ActSent > ly.Act.OptThresh.Send {
+		return -nrn.ActSent, 0, true // un-send the last above-threshold activation to get back to 0
+	}
+	return 0, 0, false
+}
+
+// sendGDeltaPath does SendGDelta for the given sending pathway only,
+// without updating ActSent, which is done by sendGDeltaActSent after
+// all pathways have been sent, so the pathways can be sent in parallel.
+func (ly *Layer) sendGDeltaPath(sp *Path) {
+	if sp.STP.On {
+		sp.SendGDeltaSTP()
+		return
+	}
+	scale := ly.Dropout.SendScale()
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		if delta, _, send := ly.sendDelta(nrn, scale); send {
+			sp.SendGDelta(ni, delta)
+		}
+	}
+}
+
+// sendGDeltaActSent updates ActSent for the given range of neurons,
+// after sendGDeltaPath has been called for all sending pathways.
+func (ly *Layer) sendGDeltaActSent(st, ed int) {
+	scale := ly.Dropout.SendScale()
+	for ni := st; ni < ed; ni++ {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		if _, sent, send := ly.sendDelta(nrn, scale); send {
+			nrn.ActSent = sent
 		}
 	}
 }
@@ -495,7 +553,7 @@ func (ly *Layer) GFromInc(ctx *Context) {
 	case CTLayer:
 		ly.CTGFromInc(ctx)
 	case PulvinarLayer:
-		if ly.Pulvinar.DriversOff || !ly.Pulvinar.BurstQtr.HasFlag(ctx.Quarter) {
+		if ly.Pulvinar.DriversOff || !ly.Pulvinar.BurstQtr.HasFlag(ctx.Quarter) || !ly.BurstTrial(ctx) {
 			ly.GFromIncNeur(ctx)
 		} else {
 			ly.SetDriverActs()
@@ -509,6 +567,22 @@ func (ly *Layer) GFromInc(ctx *Context) {
 	}
 }
 
+// gFromIncRange is GFromInc for the given range of neurons,
+// for parallelSafe layers.
+func (ly *Layer) gFromIncRange(ctx *Context, st, ed int) {
+	for _, pt := range ly.RecvPaths {
+		if pt.Off {
+			continue
+		}
+		pt.recvGIncRange(st, ed)
+	}
+	if ly.Type == CTLayer {
+		ly.ctGFromIncRange(st, ed)
+		return
+	}
+	ly.gFromIncNeurRange(st, ed)
+}
+
 // RecvGInc calls RecvGInc on receiving pathways to collect Neuron-level G*Inc values.
 // This is called by GFromInc overall method, but separated out for cases that need to
 // do something different.
@@ -524,13 +598,21 @@ func (ly *Layer) RecvGInc(ctx *Context) {
 // GFromIncNeur is the neuron-level code for GFromInc that integrates overall Ge, Gi values
 // from their G*Raw accumulators.
 func (ly *Layer) GFromIncNeur(ctx *Context) {
-	for ni := range ly.Neurons {
+	ly.gFromIncNeurRange(0, len(ly.Neurons))
+}
+
+// gFromIncNeurRange is GFromIncNeur for the given range of neurons.
+func (ly *Layer) gFromIncNeurRange(st, ed int) {
+	daGain := ly.DaGain()
+	geBias := ly.MatrixValueGe()
+	for ni := st; ni < ed; ni++ {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
 		}
+		gain := daGain * ly.AttnGain(int(nrn.SubPool))
 		// note: each step broken out here so other variants can add extra terms to Raw
-		ly.Act.GeFromRaw(nrn, nrn.GeRaw)
+		ly.Act.GeFromRawRand(nrn, gain*nrn.GeRaw+geBias, ly.noiseRand(nrn))
 		ly.Act.GiFromRaw(nrn, nrn.GiRaw)
 	}
 }
@@ -538,30 +620,40 @@ func (ly *Layer) GFromIncNeur(ctx *Context) {
 // AvgMaxGe computes the average and max Ge stats, used in inhibition
 func (ly *Layer) AvgMaxGe(ctx *Context) {
 	for pi := range ly.Pools {
-		pl := &ly.Pools[pi]
-		pl.Inhib.Ge.Init()
-		for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
-			nrn := &ly.Neurons[ni]
-			if nrn.IsOff() {
-				continue
-			}
-			pl.Inhib.Ge.UpdateValue(nrn.Ge, int32(ni))
+		ly.Pools[pi].avgMaxGe(ly)
+	}
+}
+
+// avgMaxGe computes the average and max Ge stats of the neurons
+// in the pool of the given layer.
+func (pl *Pool) avgMaxGe(ly *Layer) {
+	pl.Inhib.Ge.Init()
+	for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
 		}
-		pl.Inhib.Ge.CalcAvg()
+		pl.Inhib.Ge.UpdateValue(nrn.Ge, int32(ni))
 	}
+	pl.Inhib.Ge.CalcAvg()
 }
 
 // InhibFromGeAct computes inhibition Gi from Ge and Act averages within relevant Pools
 func (ly *Layer) InhibFromGeAct(ctx *Context) {
-	lpl := &ly.Pools[0]
-	ly.Inhib.Layer.Inhib(&lpl.Inhib)
-	ly.PoolInhibFromGeAct(ctx)
+	ly.inhibFromGeActPools(ctx)
 	ly.InhibFromPool(ctx)
 	if ly.Type == MatrixLayer {
 		ly.MatrixOutAChInhib(ctx)
 	}
 }
 
+// inhibFromGeActPools computes the layer and pool level inhibition Gi
+// from Ge and Act averages, which InhibFromPool applies to the neurons.
+func (ly *Layer) inhibFromGeActPools(ctx *Context) {
+	ly.Inhib.Layer.Inhib(&ly.Pools[0].Inhib)
+	ly.PoolInhibFromGeAct(ctx)
+}
+
 // PoolInhibFromGeAct computes inhibition Gi from Ge and Act averages within relevant Pools
 func (ly *Layer) PoolInhibFromGeAct(ctx *Context) {
 	np := len(ly.Pools)
@@ -575,9 +667,9 @@ func (ly *Layer) PoolInhibFromGeAct(ctx *Context) {
 		ly.Inhib.Pool.Inhib(&pl.Inhib)
 		if lyInhib {
 			pl.Inhib.LayGi = lpl.Inhib.Gi
-			pl.Inhib.Gi = math32.Max(pl.Inhib.Gi, lpl.Inhib.Gi) // pool is max of layer
+			pl.Inhib.Gi = fmath.Max(pl.Inhib.Gi, lpl.Inhib.Gi) // pool is max of layer
 		} else {
-			lpl.Inhib.Gi = math32.Max(pl.Inhib.Gi, lpl.Inhib.Gi) // update layer from pool
+			lpl.Inhib.Gi = fmath.Max(pl.Inhib.Gi, lpl.Inhib.Gi) // update layer from pool
 		}
 	}
 	if !lyInhib {
@@ -587,7 +679,12 @@ func (ly *Layer) PoolInhibFromGeAct(ctx *Context) {
 
 // InhibFromPool computes inhibition Gi from Pool-level aggregated inhibition, including self and syn
 func (ly *Layer) InhibFromPool(ctx *Context) {
-	for ni := range ly.Neurons {
+	ly.inhibFromPoolRange(0, len(ly.Neurons))
+}
+
+// inhibFromPoolRange is InhibFromPool for the given range of neurons.
+func (ly *Layer) inhibFromPoolRange(st, ed int) {
+	for ni := st; ni < ed; ni++ {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
@@ -601,6 +698,10 @@ func (ly *Layer) InhibFromPool(ctx *Context) {
 // ActFromG computes rate-code activation from Ge, Gi, Gl conductances
 // and updates learning running-average activations from that Act
 func (ly *Layer) ActFromG(ctx *Context) {
+	if ly.DaInjectActive() {
+		ly.ActFromDaTrace(ctx)
+		return
+	}
 	switch ly.Type {
 	case RWDaLayer:
 		ly.ActFromGRWDa(ctx)
@@ -620,38 +721,65 @@ func (ly *Layer) ActFromG(ctx *Context) {
 	case CINLayer:
 		ly.ActFromGCIN(ctx)
 		return
+	case NoveltyLayer:
+		ly.ActFromGNovelty(ctx)
+		return
+	case ValueLayer:
+		ly.ActFromGValue(ctx)
+		return
 	}
-	for ni := range ly.Neurons {
+	ly.actFromGRange(ctx, 0, len(ly.Neurons))
+	switch ly.Type {
+	case MatrixLayer:
+		ly.DaAChFromLay(ctx)
+	case PFCDeepLayer:
+		ly.PFCDeepGating(ctx)
+	}
+}
+
+// actFromGRange is the standard neuron-level code of ActFromG
+// for the given range of neurons.
+func (ly *Layer) actFromGRange(ctx *Context, st, ed int) {
+	noise := ly.NoiseInject.Active(ctx.Quarter)
+	for ni := st; ni < ed; ni++ {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
 		}
+		if noise {
+			ly.NoiseInject.InjectConductance(nrn, &ly.Rand)
+		}
 		ly.Act.VmFromG(nrn)
 		ly.Act.ActFromG(nrn)
+		if noise {
+			ly.NoiseInject.InjectAct(nrn, &ly.Rand)
+		}
+		if nrn.HasFlag(NeurDropout) {
+			nrn.Act = 0
+		}
 		ly.Learn.AvgsFromAct(nrn)
 	}
-	switch ly.Type {
-	case MatrixLayer:
-		ly.DaAChFromLay(ctx)
-	case PFCDeepLayer:
-		ly.PFCDeepGating(ctx)
-	}
 }
 
 // AvgMaxAct computes the average and max Act stats, used in inhibition
 func (ly *Layer) AvgMaxAct(ctx *Context) {
 	for pi := range ly.Pools {
-		pl := &ly.Pools[pi]
-		pl.Inhib.Act.Init()
-		for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
-			nrn := &ly.Neurons[ni]
-			if nrn.IsOff() {
-				continue
-			}
-			pl.Inhib.Act.UpdateValue(nrn.Act, int32(ni))
+		ly.Pools[pi].avgMaxAct(ly)
+	}
+}
+
+// avgMaxAct computes the average and max Act stats of the neurons
+// in the pool of the given layer.
+func (pl *Pool) avgMaxAct(ly *Layer) {
+	pl.Inhib.Act.Init()
+	for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
 		}
-		pl.Inhib.Act.CalcAvg()
+		pl.Inhib.Act.UpdateValue(nrn.Act, int32(ni))
 	}
+	pl.Inhib.Act.CalcAvg()
 }
 
 // CyclePost is called at end of Cycle, for misc updates after new Act
@@ -671,6 +799,10 @@ func (ly *Layer) CyclePost(ctx *Context) {
 		ly.SendDaFromAct(ctx)
 	case CINLayer:
 		ly.SendAChFromAct(ctx)
+	case NoveltyLayer:
+		ly.SendNoveltyFromAct(ctx)
+	case ValueLayer:
+		ly.SendValueFromAct(ctx)
 	}
 }
 
@@ -698,6 +830,9 @@ func (ly *Layer) QuarterFinal(ctx *Context) {
 		ly.UpdateGateCnt(ctx)
 		ly.DeepMaint(ctx)
 	}
+	if ctx.Quarter == 3 && ly.IsDaLayer() {
+		ly.UpdateDaDipAdapt()
+	}
 	if ctx.Quarter == 1 {
 		ly.Quarter2DWt()
 	}
@@ -751,7 +886,7 @@ func (ly *Layer) PlusPhase(ctx *Context) {
 		}
 		nrn.ActP = nrn.Act
 		nrn.ActDif = nrn.ActP - nrn.ActM
-		nrn.ActAvg += ly.Act.Dt.AvgDt * (nrn.Act - nrn.ActAvg)
+		nrn.ActAvg = flushTiny(nrn.ActAvg + ly.Act.Dt.AvgDt*(nrn.Act-nrn.ActAvg))
 	}
 	ly.CosDiffFromActs()
 }
@@ -762,9 +897,9 @@ func (ly *Layer) CosDiffFromActs() {
 	lpl := &ly.Pools[0]
 	avgM := lpl.ActM.Avg
 	avgP := lpl.ActP.Avg
-	cosv := float32(0)
-	ssm := float32(0)
-	ssp := float32(0)
+	cosv := Float(0)
+	ssm := Float(0)
+	ssp := Float(0)
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
@@ -777,7 +912,7 @@ func (ly *Layer) CosDiffFromActs() {
 		ssp += ap * ap
 	}
 
-	dist := math32.Sqrt(ssm * ssp)
+	dist := fmath.Sqrt(ssm * ssp)
 	if dist != 0 {
 		cosv /= dist
 	}
@@ -811,6 +946,8 @@ func (ly *Layer) IsTarget() bool {
 
 // DWt computes the weight change (learning) -- calls DWt method on sending pathways
 func (ly *Layer) DWt() {
+	ly.DWtExcit()
+	ly.DWtCtxtLag()
 	for _, pt := range ly.SendPaths {
 		if pt.Off {
 			continue
@@ -862,9 +999,20 @@ func (ly *Layer) WtBalFromWt() {
 	}
 }
 
+// SynScale applies homeostatic synaptic scaling to the receiving
+// pathways with Learn.SynScale on.
+func (ly *Layer) SynScale() {
+	for _, pt := range ly.RecvPaths {
+		if pt.Off {
+			continue
+		}
+		pt.SynScale()
+	}
+}
+
 // LrateMult sets the new Lrate parameter for Paths to LrateInit * mult.
 // Useful for implementing learning rate schedules.
-func (ly *Layer) LrateMult(mult float32) {
+func (ly *Layer) LrateMult(mult Float) {
 	for _, pt := range ly.RecvPaths {
 		// if p.Off { // keep all sync'd
 		// 	continue
@@ -887,7 +1035,7 @@ func (ly *Layer) CostEst() (neur, syn, tot int) {
 	neur = len(ly.Neurons) * perNeur
 	syn = 0
 	for _, pt := range ly.SendPaths {
-		ns := len(pt.Syns)
+		ns := pt.Syns.Len()
 		syn += ns
 	}
 	tot = neur + syn
@@ -903,7 +1051,7 @@ func (ly *Layer) CostEst() (neur, syn, tot int) {
 // over the layer, in terms of ActP - ActM (valid even on non-target layers FWIW).
 // Uses the given tolerance per-unit to count an error at all
 // (e.g., .5 = activity just has to be on the right side of .5).
-func (ly *Layer) MSE(tol float32) (sse, mse float64) {
+func (ly *Layer) MSE(tol Float) (sse, mse float64) {
 	nn := len(ly.Neurons)
 	if nn == 0 {
 		return 0, 0
@@ -914,13 +1062,13 @@ func (ly *Layer) MSE(tol float32) (sse, mse float64) {
 		if nrn.IsOff() {
 			continue
 		}
-		var d float32
+		var d Float
 		if ly.Type == CompareLayer {
 			d = nrn.Targ - nrn.ActM
 		} else {
 			d = nrn.ActP - nrn.ActM
 		}
-		if math32.Abs(d) < tol {
+		if fmath.Abs(d) < tol {
 			continue
 		}
 		sse += float64(d * d)
@@ -933,7 +1081,7 @@ func (ly *Layer) MSE(tol float32) (sse, mse float64) {
 // Uses the given tolerance per-unit to count an error at all
 // (e.g., .5 = activity just has to be on the right side of .5).
 // Use this in Python which only allows single return values.
-func (ly *Layer) SSE(tol float32) float64 {
+func (ly *Layer) SSE(tol Float) float64 {
 	sse, _ := ly.MSE(tol)
 	return sse
 }
@@ -952,7 +1100,7 @@ func (ly *Layer) UnLesionNeurons() {
 // LesionNeurons lesions (sets the Off flag) for given proportion (0-1) of neurons in layer
 // returns number of neurons lesioned.  Emits error if prop > 1 as indication that percent
 // might have been passed
-func (ly *Layer) LesionNeurons(prop float32) int {
+func (ly *Layer) LesionNeurons(prop Float) int {
 	ly.UnLesionNeurons()
 	if prop > 1 {
 		log.Printf("LesionNeurons got a proportion > 1 -- must be 0-1 as *proportion* (not percent) of neurons to lesion: %v\n", prop)
@@ -962,8 +1110,8 @@ func (ly *Layer) LesionNeurons(prop float32) int {
 	if nn == 0 {
 		return 0
 	}
-	p := rand.Perm(nn)
-	nl := int(prop * float32(nn))
+	p := ly.Rand.Perm(nn)
+	nl := int(prop * Float(nn))
 	for i := 0; i < nl; i++ {
 		nrn := &ly.Neurons[p[i]]
 		nrn.SetFlag(true, NeurOff)
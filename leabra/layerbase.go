@@ -5,6 +5,7 @@
 package leabra
 
 import (
+	"cogentcore.org/core/math32"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -15,7 +16,7 @@ import (
 
 	"cogentcore.org/core/base/errors"
 	"cogentcore.org/core/base/num"
-	"cogentcore.org/core/math32"
+	"cogentcore.org/core/base/randx"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/emergent/v2/emer"
 	"github.com/emer/emergent/v2/weights"
@@ -48,10 +49,27 @@ type Layer struct {
 	// Learning parameters and methods that operate at the neuron level.
 	Learn LearnNeurParams `display:"add-fields"`
 
+	// NoiseInject has parameters for injecting noise into activity during
+	// specified quarters, for lesion experiments; see InjectNoise.
+	NoiseInject NoiseInjectParams `display:"inline"`
+
+	// Dropout has parameters for randomly silencing units during
+	// training trials, as a regularizer; see DropoutInit.
+	Dropout DropoutParams `display:"inline"`
+
+	// PopCode has parameters for encoding continuous values as population
+	// codes over the units; see ApplyValues and DecodeValues.
+	PopCode PopCodeParams `display:"inline"`
+
 	// Burst has parameters for computing Burst from act, in Superficial layers
-	// (but also needed in Deep layers for deep self connections).
+	// (but also needed in Deep layers for deep self connections), and the
+	// burst schedule across trials, also used by Pulvinar layers.
 	Burst BurstParams `display:"inline"`
 
+	// Ctxt has parameters for the integration of the temporal context
+	// over bursts in CT layers, with a configurable or learned lag.
+	Ctxt CtxtParams `display:"inline"`
+
 	// Pulvinar has parameters for computing Pulvinar plus-phase (outcome)
 	// activations based on Burst activation from corresponding driver neuron.
 	Pulvinar PulvinarParams `display:"inline"`
@@ -60,6 +78,10 @@ type Layer struct {
 	// inputs to this layer.
 	Drivers Drivers
 
+	// Attn has parameters for the attentional modulation of the gain of
+	// the SendAttn layers by the prediction error of a Pulvinar layer.
+	Attn AttnParams `display:"inline"`
+
 	// RW are Rescorla-Wagner RL learning parameters.
 	RW RWParams `display:"inline"`
 
@@ -85,6 +107,24 @@ type Layer struct {
 	// PFC Maintenance parameters
 	PFCMaint PFCMaintParams `display:"inline"`
 
+	// Novelty has the parameters for the NoveltyLayer mismatch signal.
+	Novelty NoveltyParams `display:"inline"`
+
+	// DaInject has the parameters for injecting an empirical DaTrace
+	// as the DA output of a dopamine layer.
+	DaInject DaInjectParams `display:"inline"`
+
+	// DaMod has the parameters for the tonic vs. phasic components of
+	// dopamine, sent by dopamine layers and used by receiving layers.
+	DaMod DaModParams `display:"inline"`
+
+	// DaDip has the parameters for the negative (dip) component of
+	// dopamine sent by a dopamine layer.
+	DaDip DaDipParams `display:"inline"`
+
+	// Value has the parameters for a ValueLayer.
+	Value ValueParams `display:"inline"`
+
 	// PFCDyns dynamic behavior parameters -- provides deterministic control over PFC maintenance dynamics -- the rows of PFC units (along Y axis) behave according to corresponding index of Dyns (inner loop is Super Y axis, outer is Dyn types) -- ensure Y dim has even multiple of len(Dyns)
 	PFCDyns PFCDyns
 
@@ -104,9 +144,42 @@ type Layer struct {
 	// NeuroMod is the neuromodulatory neurotransmitter state for this layer.
 	NeuroMod NeuroMod `read-only:"+" display:"inline"`
 
+	// ValueIn are the value and cost estimates received from ValueLayers.
+	ValueIn ValueInputs `read-only:"+" display:"inline"`
+
 	// SendTo is a list of layers that this layer sends special signals to,
 	// which could be dopamine, gating signals, depending on the layer type.
 	SendTo LayerNames
+
+	// SendAttn is a list of layers (typically SuperLayers) whose gain is
+	// modulated by the prediction error of this Pulvinar layer (see Attn).
+	SendAttn LayerNames
+
+	// GateRands are the random number streams for the gating noise in
+	// each pool of Matrix and GPiThal layers, if PBWM.GateNoise.On.
+	GateRands []randx.SysRand `display:"-" json:"-"`
+
+	// GateSeeds are the seeds that GateRands started from, in pool order.
+	GateSeeds []int64 `display:"-"`
+
+	// Rand is the random number stream for this layer, used for the
+	// initial weights of its sending pathways, activation noise (other
+	// than GateRands), noise injection, dropout and lesions.  It is seeded in
+	// Network.InitWeights from the network Rand and the layer name,
+	// so it does not depend on any other use of random numbers.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// DaTrace is an empirical DA trace injected as the activity of
+	// a dopamine layer, if DaInject.On.  See SetDaTrace.
+	DaTrace *DaTrace `display:"-" json:"-"`
+
+	// DaTraceTrial is the trial within DaTrace that is injected,
+	// or -1 (or any other trial outside the trace) to use the computed DA.  See SetDaTraceTrial.
+	DaTraceTrial int `display:"-" json:"-"`
+
+	// DaDipAdapt is the adaptation of dopamine dips over trials,
+	// for a dopamine layer with DaDip.AdaptRate > 0.
+	DaDipAdapt Float `read-only:"+"`
 }
 
 // emer.Layer interface methods
@@ -123,8 +196,13 @@ func (ly *Layer) Defaults() {
 	ly.Act.Defaults()
 	ly.Inhib.Defaults()
 	ly.Learn.Defaults()
+	ly.NoiseInject.Defaults()
+	ly.Dropout.Defaults()
+	ly.PopCode.Defaults()
 	ly.Burst.Defaults()
+	ly.Ctxt.Defaults()
 	ly.Pulvinar.Defaults()
+	ly.Attn.Defaults()
 	ly.RW.Defaults()
 	ly.TD.Defaults()
 	ly.Matrix.Defaults()
@@ -133,6 +211,11 @@ func (ly *Layer) Defaults() {
 	ly.CIN.Defaults()
 	ly.PFCGate.Defaults()
 	ly.PFCMaint.Defaults()
+	ly.Novelty.Defaults()
+	ly.DaInject.Defaults()
+	ly.DaMod.Defaults()
+	ly.DaDip.Defaults()
+	ly.Value.Defaults()
 	ly.Inhib.Layer.On = true
 	for _, pt := range ly.RecvPaths {
 		pt.Defaults()
@@ -162,8 +245,13 @@ func (ly *Layer) UpdateParams() {
 	ly.Act.Update()
 	ly.Inhib.Update()
 	ly.Learn.Update()
+	ly.NoiseInject.Update()
+	ly.Dropout.Update()
+	ly.PopCode.Update()
 	ly.Burst.Update()
+	ly.Ctxt.Update()
 	ly.Pulvinar.Update()
+	ly.Attn.Update()
 	ly.RW.Update()
 	ly.TD.Update()
 	ly.Matrix.Update()
@@ -172,6 +260,11 @@ func (ly *Layer) UpdateParams() {
 	ly.CIN.Update()
 	ly.PFCGate.Update()
 	ly.PFCMaint.Update()
+	ly.Novelty.Update()
+	ly.DaInject.Update()
+	ly.DaMod.Update()
+	ly.DaDip.Update()
+	ly.Value.Update()
 	for _, pt := range ly.RecvPaths {
 		pt.UpdateParams()
 	}
@@ -181,8 +274,10 @@ func (ly *Layer) ShouldDisplay(field string) bool {
 	isPBWM := ly.Type == MatrixLayer || ly.Type == GPiThalLayer || ly.Type == CINLayer || ly.Type == PFCLayer || ly.Type == PFCDeepLayer
 	switch field {
 	case "Burst":
-		return ly.Type == SuperLayer || ly.Type == CTLayer
-	case "Pulvinar", "Drivers":
+		return ly.Type == SuperLayer || ly.Type == CTLayer || ly.Type == PulvinarLayer
+	case "Ctxt":
+		return ly.Type == CTLayer
+	case "Pulvinar", "Drivers", "Attn", "SendAttn":
 		return ly.Type == PulvinarLayer
 	case "RW":
 		return ly.Type == RWPredLayer || ly.Type == RWDaLayer
@@ -191,7 +286,7 @@ func (ly *Layer) ShouldDisplay(field string) bool {
 	case "PBWM":
 		return isPBWM
 	case "SendTo":
-		return ly.Type == GPiThalLayer || ly.Type == ClampDaLayer || ly.Type == RWDaLayer || ly.Type == TDDaLayer || ly.Type == CINLayer
+		return ly.Type == GPiThalLayer || ly.Type == ClampDaLayer || ly.Type == RWDaLayer || ly.Type == TDDaLayer || ly.Type == CINLayer || ly.Type == NoveltyLayer || ly.Type == ValueLayer
 	case "Matrix":
 		return ly.Type == MatrixLayer
 	case "GPiGate":
@@ -200,6 +295,14 @@ func (ly *Layer) ShouldDisplay(field string) bool {
 		return ly.Type == CINLayer
 	case "PFCGate", "PFCMaint":
 		return ly.Type == PFCLayer || ly.Type == PFCDeepLayer
+	case "Novelty":
+		return ly.Type == NoveltyLayer
+	case "Value":
+		return ly.Type == ValueLayer
+	case "ValueIn":
+		return ly.Type == MatrixLayer
+	case "DaInject", "DaDip":
+		return ly.IsDaLayer()
 	case "PFCDyns":
 		return ly.Type == PFCDeepLayer
 	default:
@@ -289,20 +392,26 @@ func (ly *Layer) UnitValue1D(varIndex int, idx int, di int) float32 {
 	if varIndex >= da {
 		switch varIndex - da {
 		case 0:
-			return ly.NeuroMod.DA
+			return float32(ly.NeuroMod.DA)
 		case 1:
-			return ly.NeuroMod.ACh
+			return float32(ly.NeuroMod.ACh)
 		case 2:
-			return ly.NeuroMod.SE
+			return float32(ly.NeuroMod.SE)
 		case 3:
-			return ly.Pools[nrn.SubPool].Gate.Act
+			return float32(ly.Pools[nrn.SubPool].Gate.Act)
 		case 4:
 			return num.FromBool[float32](ly.Pools[nrn.SubPool].Gate.Now)
 		case 5:
 			return float32(ly.Pools[nrn.SubPool].Gate.Cnt)
+		case 6:
+			return float32(ly.NeuroMod.DATonic)
+		case 7:
+			return float32(ly.Pools[nrn.SubPool].PredErr)
+		case 8:
+			return float32(ly.AttnGain(int(nrn.SubPool)))
 		}
 	}
-	return nrn.VarByIndex(varIndex)
+	return float32(nrn.VarByIndex(varIndex))
 }
 
 // UnitValues fills in values of given variable name on unit,
@@ -608,6 +717,10 @@ func (ly *Layer) Build() error {
 	if err != nil {
 		return errors.Log(err)
 	}
+	err = ly.SendAttn.Validate(ly.Network)
+	if err != nil {
+		return errors.Log(err)
+	}
 	return nil
 }
 
@@ -618,7 +731,14 @@ func (ly *Layer) WriteWeightsJSON(w io.Writer, depth int) {
 	ly.MetaData = make(map[string]string)
 	ly.MetaData["ActMAvg"] = fmt.Sprintf("%g", ly.Pools[0].ActAvg.ActMAvg)
 	ly.MetaData["ActPAvg"] = fmt.Sprintf("%g", ly.Pools[0].ActAvg.ActPAvg)
-	ly.LayerBase.WriteWeightsJSONBase(w, depth)
+	var vars []string
+	if ly.HasExcit() {
+		vars = append(vars, "Excit")
+	}
+	if ly.HasCtxtLag() {
+		vars = append(vars, "CtxtLag")
+	}
+	ly.LayerBase.WriteWeightsJSONBase(w, depth, vars...)
 }
 
 // SetWeights sets the weights for this layer from weights.Layer decoded values
@@ -629,15 +749,35 @@ func (ly *Layer) SetWeights(lw *weights.Layer) error {
 	if lw.MetaData != nil {
 		if am, ok := lw.MetaData["ActMAvg"]; ok {
 			pv, _ := strconv.ParseFloat(am, 32)
-			ly.Pools[0].ActAvg.ActMAvg = float32(pv)
+			ly.Pools[0].ActAvg.ActMAvg = Float(pv)
 		}
 		if ap, ok := lw.MetaData["ActPAvg"]; ok {
 			pv, _ := strconv.ParseFloat(ap, 32)
 			pl := &ly.Pools[0]
-			pl.ActAvg.ActPAvg = float32(pv)
+			pl.ActAvg.ActPAvg = Float(pv)
 			ly.Inhib.ActAvg.EffFromAvg(&pl.ActAvg.ActPAvgEff, pl.ActAvg.ActPAvg)
 		}
 	}
+	excit := lw.Units["Excit"]
+	if es, ok := lw.MetaData["Excit"]; ok && excit == nil { // binary weights
+		var err error
+		if excit, err = parseNeuronValues("Excit", es); err != nil {
+			return err
+		}
+	}
+	if err := ly.setExcit(excit); err != nil {
+		return err
+	}
+	lags := lw.Units["CtxtLag"]
+	if ls, ok := lw.MetaData["CtxtLag"]; ok && lags == nil {
+		var err error
+		if lags, err = parseNeuronValues("CtxtLag", ls); err != nil {
+			return err
+		}
+	}
+	if err := ly.setCtxtLag(lags); err != nil {
+		return err
+	}
 	var err error
 	rpts := ly.RecvPaths
 	if len(lw.Paths) == len(rpts) { // this is essential if multiple paths from same layer
@@ -677,11 +817,11 @@ func (ly *Layer) VarRange(varNm string) (min, max float32, err error) {
 		return
 	}
 
-	v0 := ly.Neurons[0].VarByIndex(vidx)
+	v0 := float32(ly.Neurons[0].VarByIndex(vidx))
 	min = v0
 	max = v0
 	for i := 1; i < sz; i++ {
-		vl := ly.Neurons[i].VarByIndex(vidx)
+		vl := float32(ly.Neurons[i].VarByIndex(vidx))
 		if vl < min {
 			min = vl
 		}
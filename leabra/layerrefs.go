@@ -0,0 +1,225 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Layer references provide stable handles to layers, so that sims can
+// look up the layers they use once, with validation of all the names,
+// instead of repeated LayerByName string lookups that fail at the point
+// of use on a typo.  A struct of *Layer fields (e.g., HipLayers,
+// PBWMLayers, or one defined by the sim) is filled in by LayerRefs,
+// and layer aliases allow layers to be referred to by alternative names,
+// including the old name of a layer renamed with RenameLayer.
+
+// HipLayers are references to the layers of a standard hippocampus
+// model, as used in the hip examples, which can be obtained from a
+// network using its standard layer names with Network.LayerRefs.
+type HipLayers struct {
+	Input *Layer
+	ECin  *Layer
+	ECout *Layer
+	CA1   *Layer
+	DG    *Layer
+	CA3   *Layer
+}
+
+// PBWMLayers are references to the layers created by AddPBWMLayers.
+// The PFC out layers are nil if there are no output stripes.
+type PBWMLayers struct {
+	MatrixGo   *Layer
+	MatrixNoGo *Layer
+	GPe        *Layer
+	GPiThal    *Layer
+	CIN        *Layer
+	PFCmnt     *Layer
+	PFCmntD    *Layer
+	PFCout     *Layer
+	PFCoutD    *Layer
+}
+
+// AddPBWMLayers adds a DorsalBG and PFC with given params, as in AddPBWM,
+// returning references to the layers.
+func (nt *Network) AddPBWMLayers(prefix string, nY, nMaint, nOut, nNeurBgY, nNeurBgX, nNeurPfcY, nNeurPfcX int) *PBWMLayers {
+	pl := &PBWMLayers{}
+	pl.MatrixGo, pl.MatrixNoGo, pl.GPe, pl.GPiThal, pl.CIN, pl.PFCmnt, pl.PFCmntD, pl.PFCout, pl.PFCoutD = nt.AddPBWM(prefix, nY, nMaint, nOut, nNeurBgY, nNeurBgX, nNeurPfcY, nNeurPfcX)
+	return pl
+}
+
+// LayerRefs sets the *Layer fields of the given struct pointer to the
+// layers in the network with the same name as the field, or the name
+// given in a `layer:"name"` field tag, which can be an alias,
+// returning an error listing all of the names that were not found,
+// for which the field is set to nil.  Fields with a `layer:"-"` tag
+// and fields of other types are skipped, and embedded structs
+// (e.g., HipLayers) are filled in the same way.
+func (nt *Network) LayerRefs(refs any) error {
+	rv := reflect.ValueOf(refs)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("leabra.LayerRefs: refs must be a pointer to a struct, not %T", refs)
+	}
+	sv := rv.Elem()
+	st := sv.Type()
+	lyType := reflect.TypeOf((*Layer)(nil))
+	var errs []error
+	for i := range st.NumField() {
+		fld := st.Field(i)
+		if fld.Anonymous && fld.Type.Kind() == reflect.Struct {
+			errs = append(errs, nt.LayerRefs(sv.Field(i).Addr().Interface()))
+			continue
+		}
+		if !fld.IsExported() || fld.Type != lyType {
+			continue
+		}
+		name := fld.Name
+		if tag, ok := fld.Tag.Lookup("layer"); ok {
+			if tag == "-" {
+				continue
+			}
+			name = tag
+		}
+		ly := nt.LayerByName(name)
+		if ly == nil {
+			errs = append(errs, fmt.Errorf("leabra.LayerRefs: layer named %s for field %s not found in network %s", name, fld.Name, nt.Name))
+		}
+		sv.Field(i).Set(reflect.ValueOf(ly))
+	}
+	return errors.Join(errs...)
+}
+
+// CheckLayerNames returns an error listing all of the given
+// layer names (or aliases) that are not found in the network.
+func (nt *Network) CheckLayerNames(names ...string) error {
+	var errs []error
+	for _, nm := range names {
+		if nt.LayerByName(nm) == nil {
+			errs = append(errs, fmt.Errorf("leabra.CheckLayerNames: layer named %s not found in network %s", nm, nt.Name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddLayerAlias adds an alternative name for the given named layer,
+// which can then be used in LayerByName and LayerRefs.  Returns an error
+// if the layer is not found, or the alias is already the name of a layer.
+func (nt *Network) AddLayerAlias(alias, name string) error {
+	ly := nt.LayerByName(name)
+	if ly == nil {
+		return fmt.Errorf("leabra.AddLayerAlias: layer named %s not found", name)
+	}
+	if _, err := nt.EmerLayerByName(alias); err == nil {
+		return fmt.Errorf("leabra.AddLayerAlias: alias %s is already the name of a layer", alias)
+	}
+	if nt.LayerAliases == nil {
+		nt.LayerAliases = make(map[string]string)
+	}
+	nt.LayerAliases[alias] = ly.Name
+	return nil
+}
+
+// RenameLayer renames the layer named oldName to newName, updating all
+// of the references to the layer by name within the network: pathway
+// names, the relative position (Pos.Other), SendTo, DaDip.SendTo and SendAttn
+// lists, Drivers, the RW, TD and Novelty layer names, CIN.RewLays,
+// and any aliases.  If keepAlias is true,
+// oldName is kept as an alias for the layer, so that existing lookups
+// by the old name continue to work.
+// Params selecting the layer by name (#Name) must be updated and
+// re-applied by the caller.
+func (nt *Network) RenameLayer(oldName, newName string, keepAlias bool) error {
+	ly := nt.LayerByName(oldName)
+	if ly == nil {
+		return fmt.Errorf("leabra.RenameLayer: layer named %s not found", oldName)
+	}
+	oldName = ly.Name // in case oldName was an alias
+	if other := nt.LayerByName(newName); other != nil && other != ly {
+		return fmt.Errorf("leabra.RenameLayer: layer named %s already exists", newName)
+	}
+	ly.Name = newName
+	for _, pt := range ly.RecvPaths {
+		pt.Name = pt.Send.Name + "To" + pt.Recv.Name
+	}
+	for _, pt := range ly.SendPaths {
+		pt.Name = pt.Send.Name + "To" + pt.Recv.Name
+	}
+	nt.layerNameRefs(func(ref *string) {
+		if *ref == oldName {
+			*ref = newName
+		}
+	})
+	for alias, nm := range nt.LayerAliases {
+		if nm == oldName {
+			nt.LayerAliases[alias] = newName
+		}
+	}
+	delete(nt.LayerAliases, newName)
+	nt.MakeLayerMaps()
+	if keepAlias {
+		return nt.AddLayerAlias(oldName, newName)
+	}
+	return nil
+}
+
+// ValidateLayerRefs returns an error listing all of the references to
+// layers by name within the network (the relative position Pos.Other,
+// SendTo, DaDip.SendTo and SendAttn lists, Drivers, the RW, TD and Novelty
+// layer names, CIN.RewLays) that are not found in the network.
+// Empty names are ignored.
+func (nt *Network) ValidateLayerRefs() error {
+	var errs []error
+	for _, ly := range nt.Layers {
+		nt.layerNameRefsOf(ly, func(ref *string) {
+			if *ref != "" && nt.LayerByName(*ref) == nil {
+				errs = append(errs, fmt.Errorf("leabra.ValidateLayerRefs: layer %s refers to layer named %s, which is not found", ly.Name, *ref))
+			}
+		})
+	}
+	return errors.Join(errs...)
+}
+
+// layerNameRefs calls fun on each reference to a layer by name
+// within all the layers in the network.
+func (nt *Network) layerNameRefs(fun func(ref *string)) {
+	for _, ly := range nt.Layers {
+		nt.layerNameRefsOf(ly, fun)
+	}
+}
+
+// layerNameRefsOf calls fun on each reference to a layer by name
+// within the given layer.
+func (nt *Network) layerNameRefsOf(ly *Layer, fun func(ref *string)) {
+	fun(&ly.Pos.Other)
+	for i := range ly.SendTo {
+		fun(&ly.SendTo[i])
+	}
+	for i := range ly.DaDip.SendTo {
+		fun(&ly.DaDip.SendTo[i])
+	}
+	for i := range ly.SendAttn {
+		fun(&ly.SendAttn[i])
+	}
+	for i := range ly.CIN.RewLays {
+		fun(&ly.CIN.RewLays[i])
+	}
+	for _, drv := range ly.Drivers {
+		fun(&drv.Driver)
+	}
+	switch ly.Type {
+	case RWDaLayer:
+		fun(&ly.RW.RewLay)
+		fun(&ly.RW.PredLay)
+	case TDIntegLayer:
+		fun(&ly.TD.PredLay)
+	case TDDaLayer:
+		fun(&ly.TD.IntegLay)
+	case NoveltyLayer:
+		fun(&ly.Novelty.InLay)
+		fun(&ly.Novelty.ReconLay)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+// pathNames returns the names of the receiving pathways of all the layers.
+func pathNames(net *Network) []string {
+	var nms []string
+	for _, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			nms = append(nms, pt.Name)
+		}
+	}
+	return nms
+}
+
+func TestRenameLayer(t *testing.T) {
+	net := NewNetwork("Refs")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.BidirConnectLayers(hid, out, paths.NewFull())
+	nov := net.AddNoveltyLayer("Novelty", "Hidden", "Output")
+	out.Pos.Other = "Hidden"
+	in.SendTo = []string{"Hidden", "Output"}
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AddLayerAlias("Hid", "Hidden"); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.AddLayerAlias("Output", "Hidden"); err == nil {
+		t.Error("AddLayerAlias: expected error for alias that is a layer name")
+	}
+	if err := net.AddLayerAlias("Nope", "Nada"); err == nil {
+		t.Error("AddLayerAlias: expected error for unknown layer")
+	}
+	if ly := net.LayerByName("Hid"); ly != hid {
+		t.Fatalf("LayerByName alias: got %v", ly)
+	}
+
+	// rename by alias, without keeping the old name
+	if err := net.RenameLayer("Hid", "Hidden2", false); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"InputToHidden2", "OutputToHidden2", "Hidden2ToOutput"}
+	if got := pathNames(net); !slices.Equal(got, want) {
+		t.Errorf("pathway names: got %v, want %v", got, want)
+	}
+	if hid.Name != "Hidden2" || net.LayerByName("Hidden") != nil || net.LayerByName("Hidden2") != hid {
+		t.Errorf("layer lookup after rename: Name %s, old name found %v", hid.Name, net.LayerByName("Hidden") != nil)
+	}
+	if net.LayerAliases["Hid"] != "Hidden2" || net.LayerByName("Hid") != hid {
+		t.Errorf("alias not retargeted: %v", net.LayerAliases)
+	}
+	if nov.Novelty.InLay != "Hidden2" || nov.Novelty.ReconLay != "Output" || out.Pos.Other != "Hidden2" || !slices.Equal(in.SendTo, []string{"Hidden2", "Output"}) {
+		t.Errorf("refs not renamed: Novelty %s %s, Pos.Other %s, SendTo %v", nov.Novelty.InLay, nov.Novelty.ReconLay, out.Pos.Other, in.SendTo)
+	}
+	if err := net.ValidateLayerRefs(); err != nil {
+		t.Error(err)
+	}
+
+	// rename keeping the old name as an alias
+	if err := net.RenameLayer("Hidden2", "CA3", true); err != nil {
+		t.Fatal(err)
+	}
+	if net.LayerByName("Hidden2") != hid || net.LayerByName("Hid") != hid || net.LayerByName("CA3") != hid {
+		t.Errorf("lookups after rename with keepAlias: %v", net.LayerAliases)
+	}
+	if net.LayerAliases["Hidden2"] != "CA3" || net.LayerAliases["Hid"] != "CA3" {
+		t.Errorf("aliases after rename with keepAlias: %v", net.LayerAliases)
+	}
+	want = []string{"InputToCA3", "OutputToCA3", "CA3ToOutput"}
+	if got := pathNames(net); !slices.Equal(got, want) {
+		t.Errorf("pathway names: got %v, want %v", got, want)
+	}
+
+	// renaming to one of its own aliases removes the alias
+	if err := net.RenameLayer("CA3", "Hid", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := net.LayerAliases["Hid"]; ok || hid.Name != "Hid" {
+		t.Errorf("alias with the new name not removed: %v", net.LayerAliases)
+	}
+
+	if err := net.RenameLayer("Nope", "Nada", false); err == nil {
+		t.Error("RenameLayer: expected error for unknown layer")
+	}
+	if err := net.RenameLayer("Hid", "Output", false); err == nil {
+		t.Error("RenameLayer: expected error for existing name")
+	}
+}
+
+func TestValidateLayerRefs(t *testing.T) {
+	net := NewNetwork("Refs")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	net.AddNoveltyLayer("Novelty", "Input", "Recon")
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	in.SendTo = []string{"Hidden", "Hiden", ""} // Build checks SendTo
+
+	err := net.ValidateLayerRefs()
+	if err == nil {
+		t.Fatal("ValidateLayerRefs: expected errors for dangling refs")
+	}
+	msg := err.Error()
+	for _, nm := range []string{"layer Input refers to layer named Hiden", "layer Novelty refers to layer named Recon"} {
+		if !strings.Contains(msg, nm) {
+			t.Errorf("ValidateLayerRefs error missing %q: %s", nm, msg)
+		}
+	}
+	if n := strings.Count(msg, "\n") + 1; n != 2 {
+		t.Errorf("ValidateLayerRefs: got %d errors, want 2: %s", n, msg)
+	}
+
+	var refs HipLayers
+	err = net.LayerRefs(&refs)
+	if refs.Input != in || refs.CA3 != nil || err == nil || !strings.Contains(err.Error(), "layer named CA3 for field CA3") {
+		t.Errorf("LayerRefs: Input %v CA3 %v err %v", refs.Input, refs.CA3, err)
+	}
+}
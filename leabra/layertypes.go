@@ -132,4 +132,21 @@ const (
 
 	// PFCDeepLayer is a prefrontal cortex deep maintenance layer.
 	PFCDeepLayer
+
+	///////// Hippocampus
+
+	// NoveltyLayer computes a novelty signal from the mismatch between
+	// a hippocampal input layer (e.g., ECin) and its reconstruction driven
+	// by recall (e.g., ECout), and sends it as ACh and / or DA to
+	// its SendTo layers.  See [NoveltyParams].
+	NoveltyLayer
+
+	///////// Value
+
+	// ValueLayer learns an estimate of the state value (OFC-like) or
+	// effort cost (ACC-like) from the dopamine it receives, typically the
+	// TD error, via [ValuePath] pathways, and sends the estimate to
+	// its SendTo Matrix layers to bias Go vs. NoGo gating.
+	// See [ValueParams].
+	ValueLayer
 )
@@ -4,9 +4,9 @@
 
 package leabra
 
-import (
-	"cogentcore.org/core/math32"
-)
+import "github.com/emer/leabra/v2/fmath"
+
+import ()
 
 ///////////////////////////////////////////////////////////////////////
 //  learn.go contains the learning params and functions for leabra
@@ -23,18 +23,23 @@ type LearnNeurParams struct {
 
 	// parameters for computing cosine diff between minus and plus phase
 	CosDiff CosDiffParams `display:"inline"`
+
+	// parameters for learning the intrinsic excitability of each neuron
+	Excit ExcitParams `display:"inline"`
 }
 
 func (ln *LearnNeurParams) Update() {
 	ln.ActAvg.Update()
 	ln.AvgL.Update()
 	ln.CosDiff.Update()
+	ln.Excit.Update()
 }
 
 func (ln *LearnNeurParams) Defaults() {
 	ln.ActAvg.Defaults()
 	ln.AvgL.Defaults()
 	ln.CosDiff.Defaults()
+	ln.Excit.Defaults()
 }
 
 // InitActAvg initializes the running-average activation values that drive learning.
@@ -70,10 +75,13 @@ type LearnSynParams struct {
 	Learn bool
 
 	// current effective learning rate (multiplies DWt values, determining rate of change of weights)
-	Lrate float32
+	Lrate Float
 
-	// initial learning rate -- this is set from Lrate in UpdateParams, which is called when Params are updated, and used in LrateMult to compute a new learning rate for learning rate schedules.
-	LrateInit float32
+	// initial learning rate -- this is set from Lrate in UpdateParams, which is called when Params are updated (unless Lrate was set by LrateMult), and used in LrateMult to compute a new learning rate for learning rate schedules.
+	LrateInit Float
+
+	// learning rate schedule, which sets Lrate from LrateInit as a function of the training epoch
+	LrateSched LrateSchedParams `display:"inline"`
 
 	// parameters for the XCal learning rule
 	XCal XCalParams `display:"inline"`
@@ -89,30 +97,38 @@ type LearnSynParams struct {
 
 	// parameters for balancing strength of weight increases vs. decreases
 	WtBal WtBalParams `display:"inline"`
+
+	// parameters for slow homeostatic synaptic scaling of the weights
+	// of each receiving neuron toward a target average weight or activity
+	SynScale SynScaleParams `display:"inline"`
 }
 
 func (ls *LearnSynParams) Update() {
+	ls.LrateSched.Update()
 	ls.XCal.Update()
 	ls.WtSig.Update()
 	ls.Norm.Update()
 	ls.Momentum.Update()
 	ls.WtBal.Update()
+	ls.SynScale.Update()
 }
 
 func (ls *LearnSynParams) Defaults() {
 	ls.Learn = true
 	ls.Lrate = 0.04
 	ls.LrateInit = ls.Lrate
+	ls.LrateSched.Defaults()
 	ls.XCal.Defaults()
 	ls.WtSig.Defaults()
 	ls.Norm.Defaults()
 	ls.Momentum.Defaults()
 	ls.WtBal.Defaults()
+	ls.SynScale.Defaults()
 }
 
 func (ls *LearnSynParams) ShouldDisplay(field string) bool {
 	switch field {
-	case "Lrate", "LrateInit", "XCal", "WtSig", "Norm", "Momentum", "WtBal":
+	case "Lrate", "LrateInit", "LrateSched", "XCal", "WtSig", "Norm", "Momentum", "WtBal", "SynScale":
 		return ls.Learn
 	default:
 		return true
@@ -134,7 +150,7 @@ func (ls *LearnSynParams) WtFromLWt(syn *Synapse) {
 
 // CHLdWt returns the error-driven and BCM Hebbian weight change components for the
 // temporally eXtended Contrastive Attractor Learning (XCAL), CHL version
-func (ls *LearnSynParams) CHLdWt(suAvgSLrn, suAvgM, ruAvgSLrn, ruAvgM, ruAvgL float32) (err, bcm float32) {
+func (ls *LearnSynParams) CHLdWt(suAvgSLrn, suAvgM, ruAvgSLrn, ruAvgM, ruAvgL Float) (err, bcm Float) {
 	srs := suAvgSLrn * ruAvgSLrn
 	srm := suAvgM * ruAvgM
 	bcm = ls.XCal.DWt(srs, ruAvgL)
@@ -144,7 +160,7 @@ func (ls *LearnSynParams) CHLdWt(suAvgSLrn, suAvgM, ruAvgSLrn, ruAvgM, ruAvgL fl
 
 // BCMdWt returns the BCM Hebbian weight change for AvgSLrn vs. AvgL
 // long-term average floating activation on the receiver.
-func (ls *LearnSynParams) BCMdWt(suAvgSLrn, ruAvgSLrn, ruAvgL float32) float32 {
+func (ls *LearnSynParams) BCMdWt(suAvgSLrn, ruAvgSLrn, ruAvgL Float) Float {
 	srs := suAvgSLrn * ruAvgSLrn
 	return ls.XCal.DWt(srs, ruAvgL)
 }
@@ -152,7 +168,7 @@ func (ls *LearnSynParams) BCMdWt(suAvgSLrn, ruAvgSLrn, ruAvgL float32) float32 {
 // WtFromDWt updates the synaptic weights from accumulated weight changes
 // wbInc and wbDec are the weight balance factors, wt is the sigmoidal contrast-enhanced
 // weight and lwt is the linear weight value
-func (ls *LearnSynParams) WtFromDWt(wbInc, wbDec float32, dwt, wt, lwt *float32, scale float32) {
+func (ls *LearnSynParams) WtFromDWt(wbInc, wbDec Float, dwt, wt, lwt *Float, scale Float) {
 	if *dwt == 0 {
 		return
 	}
@@ -184,38 +200,38 @@ func (ls *LearnSynParams) WtFromDWt(wbInc, wbDec float32, dwt, wt, lwt *float32,
 type LrnActAvgParams struct {
 
 	// time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the super-short time-scale avg_ss value -- this is provides a pre-integration step before integrating into the avg_s short time scale -- it is particularly important for spiking -- in general 4 is the largest value without starting to impair learning, but a value of 7 can be combined with m_in_s = 0 with somewhat worse results
-	SSTau float32 `default:"2,4,7"  min:"1"`
+	SSTau Float `default:"2,4,7"  min:"1"`
 
 	// time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the short time-scale avg_s value from the super-short avg_ss value (cascade mode) -- avg_s represents the plus phase learning signal that reflects the most recent past information
-	STau float32 `default:"2" min:"1"`
+	STau Float `default:"2" min:"1"`
 
 	// time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the medium time-scale avg_m value from the short avg_s value (cascade mode) -- avg_m represents the minus phase learning signal that reflects the expectation representation prior to experiencing the outcome (in addition to the outcome) -- the default value of 10 generally cannot be exceeded without impairing learning
-	MTau float32 `default:"10" min:"1"`
+	MTau Float `default:"10" min:"1"`
 
 	// how much of the medium term average activation to mix in with the short (plus phase) to compute the Neuron AvgSLrn variable that is used for the unit's short-term average in learning. This is important to ensure that when unit turns off in plus phase (short time scale), enough medium-phase trace remains so that learning signal doesn't just go all the way to 0, at which point no learning would take place -- typically need faster time constant for updating S such that this trace of the M signal is lost -- can set SSTau=7 and set this to 0 but learning is generally somewhat worse
-	LrnM float32 `default:"0.1,0" min:"0" max:"1"`
+	LrnM Float `default:"0.1,0" min:"0" max:"1"`
 
 	// initial value for average
-	Init float32 `default:"0.15" min:"0" max:"1"`
+	Init Float `default:"0.15" min:"0" max:"1"`
 
 	// rate = 1 / tau
-	SSDt float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	SSDt Float `display:"-" json:"-" xml:"-" edit:"-"`
 
 	// rate = 1 / tau
-	SDt float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	SDt Float `display:"-" json:"-" xml:"-" edit:"-"`
 
 	// rate = 1 / tau
-	MDt float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	MDt Float `display:"-" json:"-" xml:"-" edit:"-"`
 
 	// 1-LrnM
-	LrnS float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	LrnS Float `display:"-" json:"-" xml:"-" edit:"-"`
 }
 
 // AvgsFromAct computes averages based on current act
-func (aa *LrnActAvgParams) AvgsFromAct(ruAct float32, avgSS, avgS, avgM, avgSLrn *float32) {
-	*avgSS += aa.SSDt * (ruAct - *avgSS)
-	*avgS += aa.SDt * (*avgSS - *avgS)
-	*avgM += aa.MDt * (*avgS - *avgM)
+func (aa *LrnActAvgParams) AvgsFromAct(ruAct Float, avgSS, avgS, avgM, avgSLrn *Float) {
+	*avgSS = flushTiny(*avgSS + aa.SSDt*(ruAct-*avgSS))
+	*avgS = flushTiny(*avgS + aa.SDt*(*avgSS-*avgS))
+	*avgM = flushTiny(*avgM + aa.MDt*(*avgS-*avgM))
 
 	*avgSLrn = aa.LrnS**avgS + aa.LrnM**avgM
 }
@@ -246,34 +262,34 @@ func (aa *LrnActAvgParams) Defaults() {
 type AvgLParams struct {
 
 	// initial AvgL value at start of training
-	Init float32 `default:"0.4" min:"0" max:"1"`
+	Init Float `default:"0.4" min:"0" max:"1"`
 
 	// gain multiplier on activation used in computing the running average AvgL value that is the key floating threshold in the BCM Hebbian learning rule -- when using the DELTA_FF_FB learning rule, it should generally be 2x what it was before with the old XCAL_CHL rule, i.e., default of 5 instead of 2.5 -- it is a good idea to experiment with this parameter a bit -- the default is on the high-side, so typically reducing a bit from initial default is a good direction
-	Gain float32 `default:"1.5,2,2.5,3,4,5" min:"0"`
+	Gain Float `default:"1.5,2,2.5,3,4,5" min:"0"`
 
 	// miniumum AvgL value -- running average cannot go lower than this value even when it otherwise would due to inactivity -- default value is generally good and typically does not need to be changed
-	Min float32 `default:"0.2" min:"0"`
+	Min Float `default:"0.2" min:"0"`
 
 	// time constant for updating the running average AvgL -- AvgL moves toward gain*act with this time constant on every alpha-cycle - longer time constants can also work fine, but the default of 10 allows for quicker reaction to beneficial weight changes
-	Tau float32 `default:"10" min:"1"`
+	Tau Float `default:"10" min:"1"`
 
 	// maximum AvgLLrn value, which is amount of learning driven by AvgL factor -- when AvgL is at its maximum value (i.e., gain, as act does not exceed 1), then AvgLLrn will be at this maximum value -- by default, strong amounts of this homeostatic Hebbian form of learning can be used when the receiving unit is highly active -- this will then tend to bring down the average activity of units -- the default of 0.5, in combination with the err_mod flag, works well for most models -- use around 0.0004 for a single fixed value (with err_mod flag off)
-	LrnMax float32 `default:"0.5" min:"0"`
+	LrnMax Float `default:"0.5" min:"0"`
 
 	// miniumum AvgLLrn value (amount of learning driven by AvgL factor) -- if AvgL is at its minimum value, then AvgLLrn will be at this minimum value -- neurons that are not overly active may not need to increase the contrast of their weights as much -- use around 0.0004 for a single fixed value (with err_mod flag off)
-	LrnMin float32 `default:"0.0001,0.0004" min:"0"`
+	LrnMin Float `default:"0.0001,0.0004" min:"0"`
 
 	// modulate amount learning by normalized level of error within layer
 	ErrMod bool `default:"true"`
 
 	// minimum modulation value for ErrMod-- ensures a minimum amount of self-organizing learning even for network / layers that have a very small level of error signal
-	ModMin float32 `default:"0.01"`
+	ModMin Float `default:"0.01"`
 
 	// rate = 1 / tau
-	Dt float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	Dt Float `display:"-" json:"-" xml:"-" edit:"-"`
 
 	// (LrnMax - LrnMin) / (Gain - Min)
-	LrnFact float32 `display:"-" json:"-" xml:"-" edit:"-"`
+	LrnFact Float `display:"-" json:"-" xml:"-" edit:"-"`
 }
 
 func (al *AvgLParams) ShouldDisplay(field string) bool {
@@ -287,7 +303,7 @@ func (al *AvgLParams) ShouldDisplay(field string) bool {
 
 // AvgLFromAvgM computes long-term average activation value, and learning factor, from given
 // medium-scale running average activation avgM
-func (al *AvgLParams) AvgLFromAvgM(avgM float32, avgL, lrn *float32) {
+func (al *AvgLParams) AvgLFromAvgM(avgM Float, avgL, lrn *Float) {
 	*avgL += al.Dt * (al.Gain*avgM - *avgL)
 	if *avgL < al.Min {
 		*avgL = al.Min
@@ -296,12 +312,12 @@ func (al *AvgLParams) AvgLFromAvgM(avgM float32, avgL, lrn *float32) {
 }
 
 // ErrModFromLayErr computes AvgLLrn multiplier from layer cosine diff avg statistic
-func (al *AvgLParams) ErrModFromLayErr(layCosDiffAvg float32) float32 {
-	lmod := float32(1)
+func (al *AvgLParams) ErrModFromLayErr(layCosDiffAvg Float) Float {
+	lmod := Float(1)
 	if !al.ErrMod {
 		return lmod
 	}
-	lmod *= math32.Max(layCosDiffAvg, al.ModMin)
+	lmod *= fmath.Max(layCosDiffAvg, al.ModMin)
 	return lmod
 }
 
@@ -330,13 +346,13 @@ func (al *AvgLParams) Defaults() {
 type CosDiffParams struct {
 
 	// time constant in alpha-cycles (roughly how long significant change takes, 1.4 x half-life) for computing running average CosDiff value for the layer, CosDiffAvg = cosine difference between ActM and ActP -- this is an important statistic for how much phase-based difference there is between phases in this layer -- it is used in standard X_COS_DIFF modulation of l_mix in LeabraConSpec, and for modulating learning rate as a function of predictability in the DeepLeabra predictive auto-encoder learning -- running average variance also computed with this: cos_diff_var
-	Tau float32 `default:"100" min:"1"`
+	Tau Float `default:"100" min:"1"`
 
 	// rate constant = 1 / Tau
-	Dt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	Dt Float `edit:"-" display:"-" json:"-" xml:"-"`
 
 	// complement of rate constant = 1 - Dt
-	DtC float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	DtC Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 func (cd *CosDiffParams) Update() {
@@ -350,7 +366,7 @@ func (cd *CosDiffParams) Defaults() {
 }
 
 // AvgVarFromCos updates the average and variance from current cosine diff value
-func (cd *CosDiffParams) AvgVarFromCos(avg, vr *float32, cos float32) {
+func (cd *CosDiffParams) AvgVarFromCos(avg, vr *Float, cos Float) {
 	if *avg == 0 { // first time -- set
 		*avg = cos
 		*vr = 0
@@ -363,17 +379,17 @@ func (cd *CosDiffParams) AvgVarFromCos(avg, vr *float32, cos float32) {
 		if *vr == 0 {
 			*vr = 2 * cd.DtC * del * incr
 		} else {
-			*vr = cd.DtC * (*vr + del*incr)
+			*vr = flushTiny(cd.DtC * (*vr + del*incr))
 		}
 	}
 }
 
 // LrateMod computes learning rate modulation based on cos diff vals
-// func (cd *CosDiffParams) LrateMod(cos, avg, vr float32) float32 {
+// func (cd *CosDiffParams) LrateMod(cos, avg, vr Float) Float {
 // 	if vr <= 0 {
 // 		return 1
 // 	}
-// 	zval := (cos - avg) / math32.Sqrt(vr) // stdev = sqrt of var
+// 	zval := (cos - avg) / fmath.Sqrt(vr) // stdev = sqrt of var
 // 	// z-normal value is starting point for learning rate factor
 // 	//    if zval < lrmod_z_thr {
 // 	// 	return 0
@@ -388,19 +404,19 @@ func (cd *CosDiffParams) AvgVarFromCos(avg, vr *float32, cos float32) {
 type CosDiffStats struct {
 
 	// cosine (normalized dot product) activation difference between ActP and ActM on this alpha-cycle for this layer -- computed by CosDiffFromActs at end of QuarterFinal for quarter = 3
-	Cos float32
+	Cos Float
 
 	// running average of cosine (normalized dot product) difference between ActP and ActM -- computed with CosDiff.Tau time constant in QuarterFinal, and used for modulating BCM Hebbian learning (see AvgLrn) and overall learning rate
-	Avg float32
+	Avg Float
 
 	// running variance of cosine (normalized dot product) difference between ActP and ActM -- computed with CosDiff.Tau time constant in QuarterFinal, used for modulating overall learning rate
-	Var float32
+	Var Float
 
 	// 1 - Avg and 0 for non-Hidden layers
-	AvgLrn float32
+	AvgLrn Float
 
 	// 1 - AvgLrn and 0 for non-Hidden layers -- this is the value of Avg used for AvgLParams ErrMod modulation of the AvgLLrn factor if enabled
-	ModAvgLLrn float32
+	ModAvgLLrn Float
 }
 
 func (cd *CosDiffStats) Init() {
@@ -419,25 +435,25 @@ func (cd *CosDiffStats) Init() {
 type XCalParams struct {
 
 	// multiplier on learning based on the medium-term floating average threshold which produces error-driven learning -- this is typically 1 when error-driven learning is being used, and 0 when pure Hebbian learning is used. The long-term floating average threshold is provided by the receiving unit
-	MLrn float32 `default:"1" min:"0"`
+	MLrn Float `default:"1" min:"0"`
 
 	// if true, set a fixed AvgLLrn weighting factor that determines how much of the long-term floating average threshold (i.e., BCM, Hebbian) component of learning is used -- this is useful for setting a fully Hebbian learning connection, e.g., by setting MLrn = 0 and LLrn = 1. If false, then the receiving unit's AvgLLrn factor is used, which dynamically modulates the amount of the long-term component as a function of how active overall it is
 	SetLLrn bool `default:"false"`
 
 	// fixed l_lrn weighting factor that determines how much of the long-term floating average threshold (i.e., BCM, Hebbian) component of learning is used -- this is useful for setting a fully Hebbian learning connection, e.g., by setting MLrn = 0 and LLrn = 1.
-	LLrn float32
+	LLrn Float
 
 	// proportional point within LTD range where magnitude reverses to go back down to zero at zero -- err-driven svm component does better with smaller values, and BCM-like mvl component does better with larger values -- 0.1 is a compromise
-	DRev float32 `default:"0.1" min:"0" max:"0.99"`
+	DRev Float `default:"0.1" min:"0" max:"0.99"`
 
 	// minimum LTD threshold value below which no weight change occurs -- this is now *relative* to the threshold
-	DThr float32 `default:"0.0001,0.01" min:"0"`
+	DThr Float `default:"0.0001,0.01" min:"0"`
 
 	// xcal learning threshold -- don't learn when sending unit activation is below this value in both phases -- due to the nature of the learning function being 0 when the sr coproduct is 0, it should not affect learning in any substantial way -- nonstandard learning algorithms that have different properties should ignore it
-	LrnThr float32 `default:"0.01"`
+	LrnThr Float `default:"0.01"`
 
 	// -(1-DRev)/DRev -- multiplication factor in learning rule -- builds in the minus sign!
-	DRevRatio float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	DRevRatio Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 func (xc *XCalParams) Update() {
@@ -468,8 +484,8 @@ func (xc *XCalParams) ShouldDisplay(field string) bool {
 }
 
 // DWt is the XCAL function for weight change -- the "check mark" function -- no DGain, no ThrPMin
-func (xc *XCalParams) DWt(srval, thrP float32) float32 {
-	var dwt float32
+func (xc *XCalParams) DWt(srval, thrP Float) Float {
+	var dwt Float
 	if srval < xc.DThr {
 		dwt = 0
 	} else if srval > thrP*xc.DRev {
@@ -481,7 +497,7 @@ func (xc *XCalParams) DWt(srval, thrP float32) float32 {
 }
 
 // LongLrate returns the learning rate for long-term floating average component (BCM)
-func (xc *XCalParams) LongLrate(avgLLrn float32) float32 {
+func (xc *XCalParams) LongLrate(avgLLrn Float) Float {
 	if xc.SetLLrn {
 		return xc.LLrn
 	}
@@ -495,10 +511,10 @@ func (xc *XCalParams) LongLrate(avgLLrn float32) float32 {
 type WtSigParams struct {
 
 	// gain (contrast, sharpness) of the weight contrast function (1 = linear)
-	Gain float32 `default:"1,6" min:"0"`
+	Gain Float `default:"1,6" min:"0"`
 
 	// offset of the function (1=centered at .5, >1=higher, <1=lower) -- 1 is standard for XCAL
-	Off float32 `default:"1" min:"0"`
+	Off Float `default:"1" min:"0"`
 
 	// apply exponential soft bounding to the weight changes
 	SoftBound bool `default:"true"`
@@ -514,18 +530,18 @@ func (ws *WtSigParams) Defaults() {
 }
 
 // SigFun is the sigmoid function for value w in 0-1 range, with gain and offset params
-func SigFun(w, gain, off float32) float32 {
+func SigFun(w, gain, off Float) Float {
 	if w <= 0 {
 		return 0
 	}
 	if w >= 1 {
 		return 1
 	}
-	return (1 / (1 + math32.Pow((off*(1-w))/w, gain)))
+	return (1 / (1 + fmath.Pow((off*(1-w))/w, gain)))
 }
 
 // SigFun61 is the sigmoid function for value w in 0-1 range, with default gain = 6, offset = 1 params
-func SigFun61(w float32) float32 {
+func SigFun61(w Float) Float {
 	if w <= 0 {
 		return 0
 	}
@@ -537,30 +553,30 @@ func SigFun61(w float32) float32 {
 }
 
 // SigInvFun is the inverse of the sigmoid function
-func SigInvFun(w, gain, off float32) float32 {
+func SigInvFun(w, gain, off Float) Float {
 	if w <= 0 {
 		return 0
 	}
 	if w >= 1 {
 		return 1
 	}
-	return 1.0 / (1.0 + math32.Pow((1.0-w)/w, 1/gain)/off)
+	return 1.0 / (1.0 + fmath.Pow((1.0-w)/w, 1/gain)/off)
 }
 
 // SigInvFun61 is the inverse of the sigmoid function, with default gain = 6, offset = 1 params
-func SigInvFun61(w float32) float32 {
+func SigInvFun61(w Float) Float {
 	if w <= 0 {
 		return 0
 	}
 	if w >= 1 {
 		return 1
 	}
-	rval := 1.0 / (1.0 + math32.Pow((1.0-w)/w, 1.0/6.0))
+	rval := 1.0 / (1.0 + fmath.Pow((1.0-w)/w, 1.0/6.0))
 	return rval
 }
 
 // SigFromLinWt returns sigmoidal contrast-enhanced weight from linear weight
-func (ws *WtSigParams) SigFromLinWt(lw float32) float32 {
+func (ws *WtSigParams) SigFromLinWt(lw Float) Float {
 	if ws.Gain == 1 && ws.Off == 1 {
 		return lw
 	}
@@ -571,7 +587,7 @@ func (ws *WtSigParams) SigFromLinWt(lw float32) float32 {
 }
 
 // LinFromSigWt returns linear weight from sigmoidal contrast-enhanced weight
-func (ws *WtSigParams) LinFromSigWt(sw float32) float32 {
+func (ws *WtSigParams) LinFromSigWt(sw Float) Float {
 	if ws.Gain == 1 && ws.Off == 1 {
 		return sw
 	}
@@ -594,33 +610,33 @@ type DWtNormParams struct {
 	On bool `default:"true"`
 
 	// time constant for decay of dwnorm factor -- generally should be long-ish, between 1000-10000 -- integration rate factor is 1/tau
-	DecayTau float32 `min:"1" default:"1000,10000"`
+	DecayTau Float `min:"1" default:"1000,10000"`
 
 	// minimum effective value of the normalization factor -- provides a lower bound to how much normalization can be applied
-	NormMin float32 `min:"0" default:"0.001"`
+	NormMin Float `min:"0" default:"0.001"`
 
 	// overall learning rate multiplier to compensate for changes due to use of normalization -- allows for a common master learning rate to be used between different conditions -- 0.1 for synapse-level, maybe higher for other levels
-	LrComp float32 `min:"0" default:"0.15"`
+	LrComp Float `min:"0" default:"0.15"`
 
 	// record the avg, max values of err, bcm hebbian, and overall dwt change per con group and per pathway
 	Stats bool `default:"false"`
 
 	// rate constant of decay = 1 / decay_tau
-	DecayDt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	DecayDt Float `edit:"-" display:"-" json:"-" xml:"-"`
 
 	// complement rate constant of decay = 1 - (1 / decay_tau)
-	DecayDtC float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	DecayDtC Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 // DWtNormParams updates the dwnorm running max_abs, slowly decaying value
 // jumps up to max(abs_dwt) and slowly decays
 // returns the effective normalization factor, as a multiplier, including lrate comp
-func (dn *DWtNormParams) NormFromAbsDWt(norm *float32, absDwt float32) float32 {
-	*norm = math32.Max(dn.DecayDtC**norm, absDwt)
+func (dn *DWtNormParams) NormFromAbsDWt(norm *Float, absDwt Float) Float {
+	*norm = flushTiny(fmath.Max(dn.DecayDtC**norm, absDwt))
 	if *norm == 0 {
 		return 1
 	}
-	return dn.LrComp / math32.Max(*norm, dn.NormMin)
+	return dn.LrComp / fmath.Max(*norm, dn.NormMin)
 }
 
 func (dn *DWtNormParams) Update() {
@@ -657,22 +673,22 @@ type MomentumParams struct {
 	On bool `default:"true"`
 
 	// time constant factor for integration of momentum -- 1/tau is dt (e.g., .1), and 1-1/tau (e.g., .95 or .9) is traditional momentum time-integration factor
-	MTau float32 `min:"1" default:"10"`
+	MTau Float `min:"1" default:"10"`
 
 	// overall learning rate multiplier to compensate for changes due to JUST momentum without normalization -- allows for a common master learning rate to be used between different conditions -- generally should use .1 to compensate for just momentum itself
-	LrComp float32 `min:"0" default:"0.1"`
+	LrComp Float `min:"0" default:"0.1"`
 
 	// rate constant of momentum integration = 1 / m_tau
-	MDt float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	MDt Float `edit:"-" display:"-" json:"-" xml:"-"`
 
 	// complement rate constant of momentum integration = 1 - (1 / m_tau)
-	MDtC float32 `edit:"-" display:"-" json:"-" xml:"-"`
+	MDtC Float `edit:"-" display:"-" json:"-" xml:"-"`
 }
 
 // MomentFromDWt updates synaptic moment variable based on dwt weight change value
 // and returns new momentum factor * LrComp
-func (mp *MomentumParams) MomentFromDWt(moment *float32, dwt float32) float32 {
-	*moment = mp.MDtC**moment + dwt
+func (mp *MomentumParams) MomentFromDWt(moment *Float, dwt Float) Float {
+	*moment = flushTiny(mp.MDtC**moment + dwt)
 	return mp.LrComp * *moment
 }
 
@@ -713,19 +729,19 @@ type WtBalParams struct {
 	Targs bool
 
 	// threshold on weight value for inclusion into the weight average that is then subject to the further HiThr threshold for then driving a change in weight balance -- this AvgThr allows only stronger weights to contribute so that weakening of lower weights does not dilute sensitivity to number and strength of strong weights
-	AvgThr float32 `default:"0.25"`
+	AvgThr Float `default:"0.25"`
 
 	// high threshold on weight average (subject to AvgThr) before it drives changes in weight increase vs. decrease factors
-	HiThr float32 `default:"0.4"`
+	HiThr Float `default:"0.4"`
 
 	// gain multiplier applied to above-HiThr thresholded weight averages -- higher values turn weight increases down more rapidly as the weights become more imbalanced
-	HiGain float32 `default:"4"`
+	HiGain Float `default:"4"`
 
 	// low threshold on weight average (subject to AvgThr) before it drives changes in weight increase vs. decrease factors
-	LoThr float32 `default:"0.4"`
+	LoThr Float `default:"0.4"`
 
 	// gain multiplier applied to below-lo_thr thresholded weight averages -- higher values turn weight increases up more rapidly as the weights become more imbalanced -- generally beneficial but sometimes not -- worth experimenting with either 6 or 0
-	LoGain float32 `default:"6,0"`
+	LoGain Float `default:"6,0"`
 }
 
 func (wb *WtBalParams) Update() {
@@ -751,7 +767,7 @@ func (wb *WtBalParams) ShouldDisplay(field string) bool {
 
 // WtBal computes weight balance factors for increase and decrease based on extent
 // to which weights and average act exceed thresholds
-func (wb *WtBalParams) WtBal(wbAvg float32) (fact, inc, dec float32) {
+func (wb *WtBalParams) WtBal(wbAvg Float) (fact, inc, dec Float) {
 	inc = 1
 	dec = 1
 	if wbAvg < wb.LoThr {
@@ -769,6 +785,73 @@ func (wb *WtBalParams) WtBal(wbAvg float32) (fact, inc, dec float32) {
 	return fact, inc, dec
 }
 
+//////////////////////////////////////////////////////////////////////////////////////
+//  SynScaleParams
+
+// SynScaleParams are homeostatic synaptic scaling params: slowly scales
+// all the linear weights (LWt) of each receiving neuron multiplicatively,
+// toward a target average weight across its connections, or a target
+// long-term average activity (ActAvg) of the neuron, which preserves the
+// relative weights learned by XCAL / CHL while preventing the weights from
+// saturating over long developmental or continual learning runs.
+// The scaling is applied every Interval weight updates, or when
+// Network.SynScale is called, e.g., at the end of each epoch.
+type SynScaleParams struct {
+
+	// perform homeostatic synaptic scaling
+	On bool
+
+	// scale toward a target long-term average activity (ActAvg) of the receiving neuron, instead of a target average weight: weights are scaled down for neurons that are more active than the target, and up for less active ones
+	ByAct bool
+
+	// target average linear weight (LWt) across the connections of each receiving neuron, if not ByAct
+	WtTarg Float `default:"0.5" min:"0" max:"1"`
+
+	// target long-term average activity (ActAvg) of each receiving neuron, if ByAct -- 0 = use the expected layer activity, Inhib.ActAvg.Init
+	ActTarg Float `min:"0"`
+
+	// rate of scaling: proportion of the relative deviation from target by which the weights are scaled on each update, which is also the maximum proportional change per update
+	Rate Float `default:"0.05" min:"0" max:"1"`
+
+	// number of weight updates (trials) between scaling updates -- 0 = only when Network.SynScale is called, e.g., at the end of each epoch
+	Interval int `min:"0"`
+}
+
+func (ss *SynScaleParams) Update() {
+}
+
+func (ss *SynScaleParams) Defaults() {
+	ss.On = false
+	ss.WtTarg = 0.5
+	ss.ActTarg = 0
+	ss.Rate = 0.05
+	ss.Interval = 0
+}
+
+func (ss *SynScaleParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "ByAct", "Rate", "Interval":
+		return ss.On
+	case "WtTarg":
+		return ss.On && !ss.ByAct
+	case "ActTarg":
+		return ss.On && ss.ByAct
+	default:
+		return true
+	}
+}
+
+// Factor returns the multiplicative scaling factor for the weights of a
+// receiving neuron with the given value (average weight or activity)
+// relative to the given target value.
+func (ss *SynScaleParams) Factor(val, targ Float) Float {
+	if targ <= 0 {
+		return 1
+	}
+	f := ss.Rate * (targ - val) / targ
+	return 1 + min(max(f, -ss.Rate), ss.Rate)
+}
+
 /*
   /////////////////////////////////////
   // CtLeabraXCAL code
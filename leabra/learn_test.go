@@ -5,9 +5,11 @@
 package leabra
 
 import (
+	"math"
 	"testing"
 
-	"cogentcore.org/core/math32"
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 func TestXCal(t *testing.T) {
@@ -17,17 +19,171 @@ func TestXCal(t *testing.T) {
 	// note: these values have been validated against emergent v8.5.6 svn 11492 in
 	// demo/leabra/basic_leabra_test.proj, TestLearn program
 
-	tstSr := []float32{.01, .02, .03, .04, .05, .1, .2, .3, .4, .5, .6, .7, .8}
-	tstThrp := []float32{.1, .1, .1, .1, .1, .1, .2, .2, .2, .2, .2, .3, .3}
-	cory := []float32{-0.089999996, -0.08, -0.07, -0.060000002, -0.05, 0, 0, 0.10000001, 0.2, 0.3, 0.40000004, 0.39999998, 0.5}
-	ny := make([]float32, len(tstSr))
+	tstSr := []Float{.01, .02, .03, .04, .05, .1, .2, .3, .4, .5, .6, .7, .8}
+	tstThrp := []Float{.1, .1, .1, .1, .1, .1, .2, .2, .2, .2, .2, .3, .3}
+	cory := []Float{-0.089999996, -0.08, -0.07, -0.060000002, -0.05, 0, 0, 0.10000001, 0.2, 0.3, 0.40000004, 0.39999998, 0.5}
+	ny := make([]Float, len(tstSr))
 
 	for i := range tstSr {
 		ny[i] = xcal.DWt(tstSr[i], tstThrp[i])
-		dif := math32.Abs(ny[i] - cory[i])
+		dif := fmath.Abs(ny[i] - cory[i])
 		if dif > difTol { // allow for small numerical diffs
 			t.Errorf("XCal err: i: %v, Sr: %v, thrP: %v, got: %v, cor y: %v, dif: %v\n", i, tstSr[i], tstThrp[i], ny[i], cory[i], dif)
 		}
 	}
 	// fmt.Printf("ny vals: %v\n", ny)
 }
+
+// isSubnormal returns true if x is a non-zero float32 subnormal value.
+func isSubnormal(x Float) bool {
+	return x != 0 && fmath.Abs(x) < 1.1754944e-38
+}
+
+// TestRunningAvgsLongRun checks the long-run behavior of the running
+// averages: no subnormal values when decaying to 0, and no drift from
+// the fixed point with constant inputs, over millions of updates.
+func TestRunningAvgsLongRun(t *testing.T) {
+	const nUpdates = 2_000_000
+	la := LrnActAvgParams{}
+	la.Defaults()
+	for _, act := range []Float{0, 0.3, 1} {
+		ss, s, m, slrn := la.Init, la.Init, la.Init, Float(0)
+		for i := 0; i < nUpdates; i++ {
+			la.AvgsFromAct(act, &ss, &s, &m, &slrn)
+			if isSubnormal(ss) || isSubnormal(s) || isSubnormal(m) {
+				t.Fatalf("act: %g subnormal avgs at update %d: %g %g %g", act, i, ss, s, m)
+			}
+		}
+		for _, v := range []Float{ss, s, m, slrn} {
+			if fmath.Abs(v-act) > 1.0e-6 { // float32 rounding at fixed point
+				t.Errorf("AvgsFromAct drift: act: %g got: %g", act, v)
+			}
+		}
+	}
+
+	al := AvgLParams{}
+	al.Defaults()
+	for _, avgM := range []Float{0, 0.1, 0.3} {
+		avgL, lrn := al.Init, Float(0)
+		for i := 0; i < nUpdates; i++ {
+			al.AvgLFromAvgM(avgM, &avgL, &lrn)
+		}
+		trg := max(al.Gain*avgM, al.Min)
+		if fmath.Abs(avgL-trg) > 1.0e-6 || lrn < 0 {
+			t.Errorf("AvgL drift: avgM: %g got: %g lrn: %g, trg: %g", avgM, avgL, lrn, trg)
+		}
+	}
+
+	cd := CosDiffParams{}
+	cd.Defaults()
+	avg, vr := Float(0), Float(0)
+	for i := 0; i < nUpdates; i++ {
+		cd.AvgVarFromCos(&avg, &vr, 0.8)
+		if isSubnormal(vr) {
+			t.Fatalf("CosDiff subnormal var at update %d: %g", i, vr)
+		}
+	}
+	if fmath.Abs(avg-0.8) > 1.0e-5 || vr < 0 || vr > 1.0e-6 {
+		t.Errorf("CosDiff constant drift: avg: %g var: %g", avg, vr)
+	}
+	avg, vr = 0, 0
+	for i := 0; i < nUpdates; i++ {
+		cos := Float(0.7)
+		if i%2 == 0 {
+			cos = 0.9
+		}
+		cd.AvgVarFromCos(&avg, &vr, cos)
+	}
+	if fmath.Abs(avg-0.8) > 0.002 || fmath.Abs(vr-0.01) > 0.001 {
+		t.Errorf("CosDiff alternating drift: avg: %g var: %g", avg, vr)
+	}
+
+	mp := MomentumParams{}
+	mp.Defaults()
+	dn := DWtNormParams{}
+	dn.Defaults()
+	moment, norm := Float(0.5), Float(0.5)
+	for i := 0; i < nUpdates; i++ {
+		mp.MomentFromDWt(&moment, 0)
+		dn.NormFromAbsDWt(&norm, 0)
+		if isSubnormal(moment) || isSubnormal(norm) {
+			t.Fatalf("subnormal moment, norm at update %d: %g %g", i, moment, norm)
+		}
+	}
+	if moment != 0 || norm != 0 {
+		t.Errorf("moment, norm should decay to 0: %g %g", moment, norm)
+	}
+}
+
+func TestSynScale(t *testing.T) {
+	net := NewNetwork("SynScale")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	pt := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	meanLWt := func(ri int) float64 {
+		st, nc := int(pt.RConIndexSt[ri]), int(pt.RConN[ri])
+		sum := 0.0
+		for _, rsi := range pt.RSynIndex[st : st+nc] {
+			sum += float64(pt.Syns.LWt[rsi])
+		}
+		return sum / float64(nc)
+	}
+	for si := range pt.Syns.LWt {
+		pt.Syns.LWt[si] = 0.9
+		pt.Syns.Wt[si] = pt.Learn.WtSig.SigFromLinWt(0.9)
+	}
+	ss := &pt.Learn.SynScale
+	net.SynScale()
+	if m := meanLWt(0); math.Abs(m-0.9) > 1e-6 {
+		t.Errorf("SynScale off changed weights: %g", m)
+	}
+	ss.On = true
+	prev := 0.9
+	for range 20 {
+		net.SynScale()
+		m := meanLWt(0)
+		if m >= prev || m < 0.5 {
+			t.Fatalf("weight scaling should approach 0.5 from above: %g -> %g", prev, m)
+		}
+		prev = m
+	}
+	if prev > 0.75 {
+		t.Errorf("weight scaling too slow: %g", prev)
+	}
+	if w, lw := pt.Syns.Wt[0], pt.Syns.LWt[0]; math.Abs(float64(w-pt.Learn.WtSig.SigFromLinWt(lw))) > 1e-6 {
+		t.Errorf("Wt %g not in sync with LWt %g", w, lw)
+	}
+
+	// ByAct: scale down the weights of the over-active unit 1, up for unit 2
+	ss.ByAct = true
+	ss.ActTarg = 0.2
+	hid.Neurons[0].ActAvg = 0.2
+	hid.Neurons[1].ActAvg = 0.6
+	hid.Neurons[2].ActAvg = 0.1
+	m0, m1, m2 := meanLWt(0), meanLWt(1), meanLWt(2)
+	net.SynScale()
+	if meanLWt(0) != m0 || meanLWt(1) >= m1 || meanLWt(2) <= m2 {
+		t.Errorf("ByAct scaling: %g -> %g, %g -> %g, %g -> %g", m0, meanLWt(0), m1, meanLWt(1), m2, meanLWt(2))
+	}
+	if f := ss.Factor(0.6, 0.2); math.Abs(float64(f)-(1-float64(ss.Rate))) > 1e-6 {
+		t.Errorf("Factor should be limited to 1 - Rate: %g", f)
+	}
+
+	// Interval: applied every 2 weight updates
+	ss.ByAct = false
+	ss.Interval = 2
+	m1 = meanLWt(1)
+	net.WtFromDWt()
+	if meanLWt(1) != m1 {
+		t.Errorf("scaled before Interval")
+	}
+	net.WtFromDWt()
+	if meanLWt(1) == m1 {
+		t.Errorf("not scaled at Interval")
+	}
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"reflect"
+
+	"cogentcore.org/core/math32/minmax"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// LearnSatTol is the tolerance for a linear weight (LWt) to be counted
+// as being at its 0 or 1 bound, in the LearnProgress SatFrac statistic.
+var LearnSatTol = Float(0.01)
+
+// LearnProgress has learning progress statistics for a pathway, computed
+// over the weight updates since the last call to ComputeLearnProgress
+// (e.g., over an epoch), for detecting stalled or runaway learning.
+// The norms are root-mean-square values per synapse, so that pathways of
+// different sizes are comparable.  Accumulation happens in
+// Network.WtFromDWt when Network.RecLearnProgress is set, which is done
+// automatically by LogAddLearnProgressItems.
+type LearnProgress struct {
+
+	// DWtNorm is the L2 norm of the DWt weight changes per update, averaged
+	// over updates as root-mean-square, divided by sqrt(number of synapses).
+	DWtNorm Float
+
+	// WtDeltaNorm is the L2 norm of the net change in Wt over the updates,
+	// from the weights prior to the first update, divided by
+	// sqrt(number of synapses).
+	WtDeltaNorm Float
+
+	// SatFrac is the fraction of synapses whose linear weight LWt is
+	// within LearnSatTol of its 0 or 1 bound, at the time of computing.
+	SatFrac Float
+
+	// NUpdates is the number of weight updates the stats were computed over.
+	NUpdates int
+
+	// dwtSS is the accumulated sum of squared DWt values.
+	dwtSS float64
+
+	// nUpdates is the number of accumulated weight updates.
+	nUpdates int
+
+	// wt0 are the weights prior to the first accumulated update.
+	wt0 []Float
+}
+
+// AccumLearnProgress accumulates the current DWt values into the
+// LearnProgress stats, recording the current weights if this is the
+// first update.  Must be called prior to WtFromDWt.
+func (pt *Path) AccumLearnProgress() {
+	lp := &pt.LearnProg
+	if lp.nUpdates == 0 {
+		lp.wt0 = append(lp.wt0[:0], pt.Syns.Wt...)
+	}
+	ss := Float(0)
+	for _, dw := range pt.Syns.DWt {
+		ss += dw * dw
+	}
+	lp.dwtSS += float64(ss)
+	lp.nUpdates++
+}
+
+// ComputeLearnProgress computes the LearnProgress stats from the updates
+// accumulated since the last call, and resets the accumulation.
+func (pt *Path) ComputeLearnProgress() {
+	lp := &pt.LearnProg
+	ns := pt.Syns.Len()
+	lp.NUpdates = lp.nUpdates
+	lp.DWtNorm, lp.WtDeltaNorm, lp.SatFrac = 0, 0, 0
+	if ns == 0 {
+		return
+	}
+	if lp.nUpdates > 0 {
+		lp.DWtNorm = Float(math.Sqrt(lp.dwtSS / float64(lp.nUpdates*ns)))
+	}
+	if lp.nUpdates > 0 && len(lp.wt0) == ns {
+		ss := Float(0)
+		for i, wt := range pt.Syns.Wt {
+			d := wt - lp.wt0[i]
+			ss += d * d
+		}
+		lp.WtDeltaNorm = Float(math.Sqrt(float64(ss) / float64(ns)))
+	}
+	nsat := 0
+	for _, lwt := range pt.Syns.LWt {
+		if lwt <= LearnSatTol || lwt >= 1-LearnSatTol {
+			nsat++
+		}
+	}
+	lp.SatFrac = Float(nsat) / Float(ns)
+	lp.dwtSS = 0
+	lp.nUpdates = 0
+	lp.wt0 = lp.wt0[:0]
+}
+
+// AccumLearnProgress accumulates the LearnProgress stats for all
+// learning pathways, prior to WtFromDWt.
+func (nt *Network) AccumLearnProgress() {
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for _, pt := range ly.RecvPaths {
+			if pt.Off || !pt.Learn.Learn {
+				continue
+			}
+			pt.AccumLearnProgress()
+		}
+	}
+}
+
+// ComputeLearnProgress computes the LearnProgress stats for all
+// learning pathways, and resets the accumulation.
+func (nt *Network) ComputeLearnProgress() {
+	for _, ly := range nt.Layers {
+		for _, pt := range ly.RecvPaths {
+			pt.ComputeLearnProgress()
+		}
+	}
+}
+
+// LogAddLearnProgressItems adds the LearnProgress DWtNorm, WtDeltaNorm
+// and SatFrac stats for each learning pathway to the given logs at
+// the given mode, across the given time levels, in higher to lower order,
+// e.g., Run, Epoch, with names <path>_DWtNorm etc, and turns on
+// Network.RecLearnProgress.  The stats are computed at the lowest time
+// level over the weight updates since the last time the log was written,
+// and aggregated over the higher times.
+func LogAddLearnProgressItems(lg *elog.Logs, net *Network, mode etime.Modes, times ...etime.Times) {
+	net.RecLearnProgress = true
+	ntimes := len(times)
+	for _, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			if !pt.Learn.Learn {
+				continue
+			}
+			cpt := pt
+			itm := lg.AddItem(&elog.Item{
+				Name:  cpt.Name + "_DWtNorm",
+				Type:  reflect.Float64,
+				Range: minmax.F32{Max: 0.01},
+				Write: elog.WriteMap{
+					etime.Scope(mode, times[ntimes-1]): func(ctx *elog.Context) {
+						cpt.ComputeLearnProgress()
+						ctx.SetFloat64(float64(cpt.LearnProg.DWtNorm))
+					}}})
+			lg.AddStdAggs(itm, mode, times...)
+
+			itm = lg.AddItem(&elog.Item{
+				Name:  cpt.Name + "_WtDeltaNorm",
+				Type:  reflect.Float64,
+				Range: minmax.F32{Max: 0.1},
+				Write: elog.WriteMap{
+					etime.Scope(mode, times[ntimes-1]): func(ctx *elog.Context) {
+						ctx.SetFloat64(float64(cpt.LearnProg.WtDeltaNorm))
+					}}})
+			lg.AddStdAggs(itm, mode, times...)
+
+			itm = lg.AddItem(&elog.Item{
+				Name:   cpt.Name + "_SatFrac",
+				Type:   reflect.Float64,
+				FixMax: true,
+				Range:  minmax.F32{Max: 1},
+				Write: elog.WriteMap{
+					etime.Scope(mode, times[ntimes-1]): func(ctx *elog.Context) {
+						ctx.SetFloat64(float64(cpt.LearnProg.SatFrac))
+					}}})
+			lg.AddStdAggs(itm, mode, times...)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestLearnProgress(t *testing.T) {
+	testNet := MakeTestNet(t)
+	testNet.RecLearnProgress = true
+	fmIn := testNet.LayerByName("Hidden").RecvPaths[0]
+	setValue(fmIn.Syns.DWt, 0.1)
+	testNet.WtFromDWt()
+	testNet.ComputeLearnProgress()
+	lp := &fmIn.LearnProg
+	if lp.NUpdates != 1 {
+		t.Errorf("NUpdates: got %d, not 1", lp.NUpdates)
+	}
+	CmprFloats([]float32{float32(lp.DWtNorm), float32(lp.SatFrac)}, []float32{0.1, 0}, "learn progress", t)
+	if lp.WtDeltaNorm <= 0 {
+		t.Errorf("WtDeltaNorm should be > 0: %g", lp.WtDeltaNorm)
+	}
+	testNet.ComputeLearnProgress()
+	if lp.NUpdates != 0 || lp.DWtNorm != 0 || lp.WtDeltaNorm != 0 {
+		t.Errorf("learn progress not reset: %+v", *lp)
+	}
+}
@@ -0,0 +1,305 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/base/randx"
+)
+
+// LesionRecord is a record of one lesion, noise injection, or reversal
+// operation performed on a network, stored in order in Network.LesionLog,
+// to document the manipulations done in a given experiment.
+type LesionRecord struct {
+
+	// the operation, e.g., LesionUnits, UnLesionUnits, LesionSyns,
+	// UnLesionSyns, InjectNoise, ClearNoise, PruneUnits
+	Op string
+
+	// name of the layer, or the pathway for synapse operations
+	Name string
+
+	// proportion of units or synapses, for random lesions,
+	// or the noise variance for InjectNoise, or the threshold for PruneUnits
+	Prop Float
+
+	// number of units or synapses affected
+	N int
+
+	// additional description of the operation
+	Desc string
+}
+
+// String returns a one-line description of the record.
+func (lr *LesionRecord) String() string {
+	s := fmt.Sprintf("%s %s: N: %d  Prop: %g", lr.Op, lr.Name, lr.N, lr.Prop)
+	if lr.Desc != "" {
+		s += "  " + lr.Desc
+	}
+	return s
+}
+
+// AddLesionRecord adds a record to the network LesionLog.
+func (nt *Network) AddLesionRecord(op, name string, prop Float, n int, desc string) {
+	nt.LesionLog = append(nt.LesionLog, LesionRecord{Op: op, Name: name, Prop: prop, N: n, Desc: desc})
+}
+
+// LesionReport returns a report of all the operations in the LesionLog,
+// one per line.
+func (nt *Network) LesionReport() string {
+	var b strings.Builder
+	for i := range nt.LesionLog {
+		b.WriteString(nt.LesionLog[i].String())
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// addLesionRecord adds a record to the network LesionLog, if the
+// network has been set.
+func (ly *Layer) addLesionRecord(op string, prop Float, n int, desc string) {
+	if ly.Network != nil {
+		ly.Network.AddLesionRecord(op, ly.Name, prop, n, desc)
+	}
+}
+
+// LesionUnits lesions (sets the Off flag) for given proportion (0-1)
+// of the neurons in the layer that are not already lesioned, selected
+// at random, in addition to any existing lesions, so that graded damage
+// can be accumulated over time.  Returns the indexes of the neurons
+// lesioned, and records the operation in the network LesionLog.
+// Use UnLesionUnits to reverse.
+func (ly *Layer) LesionUnits(prop Float) []int {
+	if prop < 0 || prop > 1 {
+		fmt.Printf("leabra.LesionUnits: layer %s proportion must be 0-1 (not percent): %g\n", ly.Name, prop)
+		return nil
+	}
+	var on []int
+	for ni := range ly.Neurons {
+		if !ly.Neurons[ni].IsOff() {
+			on = append(on, ni)
+		}
+	}
+	nl := int(prop * Float(len(ly.Neurons)))
+	nl = min(nl, len(on))
+	p := ly.Rand.Perm(len(on))
+	idxs := make([]int, nl)
+	for i := range nl {
+		idxs[i] = on[p[i]]
+	}
+	slices.Sort(idxs)
+	ly.lesionUnits(idxs)
+	ly.addLesionRecord("LesionUnits", prop, nl, "")
+	return idxs
+}
+
+// LesionUnitIndexes lesions (sets the Off flag) for the neurons with given
+// indexes, and records the operation in the network LesionLog.
+// Use UnLesionUnits to reverse.
+func (ly *Layer) LesionUnitIndexes(idxs ...int) error {
+	nn := len(ly.Neurons)
+	for _, ni := range idxs {
+		if ni < 0 || ni >= nn {
+			return fmt.Errorf("leabra.LesionUnitIndexes: layer %s index %d out of range for %d neurons", ly.Name, ni, nn)
+		}
+	}
+	ly.lesionUnits(idxs)
+	ly.addLesionRecord("LesionUnits", 0, len(idxs), fmt.Sprintf("Indexes: %v", idxs))
+	return nil
+}
+
+// lesionUnits sets the Off flag and resets the activation state
+// of the given neurons.
+func (ly *Layer) lesionUnits(idxs []int) {
+	for _, ni := range idxs {
+		nrn := &ly.Neurons[ni]
+		ly.Act.InitActs(nrn)
+		nrn.SetFlag(true, NeurOff)
+	}
+}
+
+// UnLesionUnits unlesions (clears the Off flag) for the neurons with
+// given indexes, or all neurons in the layer if none are given,
+// and records the operation in the network LesionLog.
+func (ly *Layer) UnLesionUnits(idxs ...int) {
+	if len(idxs) == 0 {
+		ly.UnLesionNeurons()
+		ly.addLesionRecord("UnLesionUnits", 1, len(ly.Neurons), "")
+		return
+	}
+	for _, ni := range idxs {
+		if ni >= 0 && ni < len(ly.Neurons) {
+			ly.Neurons[ni].SetFlag(false, NeurOff)
+		}
+	}
+	ly.addLesionRecord("UnLesionUnits", 0, len(idxs), fmt.Sprintf("Indexes: %v", idxs))
+}
+
+// NLesionedUnits returns the number of lesioned (Off) neurons in the layer.
+func (ly *Layer) NLesionedUnits() int {
+	n := 0
+	for ni := range ly.Neurons {
+		if ly.Neurons[ni].IsOff() {
+			n++
+		}
+	}
+	return n
+}
+
+// LesionSyns lesions given proportion (0-1) of the synapses in the pathway
+// that are not already lesioned, selected at random, in addition to any
+// existing lesions.  Lesioned synapses have their Scale set to 0, so the
+// effective weight is 0 while learning continues on the underlying
+// linear weight, and the original Scale is saved for UnLesionSyns.
+// Note that InitWeights resets all synapse Scale values, and thus
+// reverses all synapse lesions.  Returns the number of synapses lesioned,
+// and records the operation in the network LesionLog.
+func (pt *Path) LesionSyns(prop Float) int {
+	if prop < 0 || prop > 1 {
+		fmt.Printf("leabra.LesionSyns: pathway %s proportion must be 0-1 (not percent): %g\n", pt.Name, prop)
+		return 0
+	}
+	if pt.lesionScales == nil {
+		pt.lesionScales = make(map[int]Float)
+	}
+	var on []int
+	for si := range pt.Syns.Len() {
+		if _, les := pt.lesionScales[si]; !les {
+			on = append(on, si)
+		}
+	}
+	nl := min(int(prop*Float(pt.Syns.Len())), len(on))
+	p := pt.Send.Rand.Perm(len(on))
+	for i := range nl {
+		si := on[p[i]]
+		pt.lesionScales[si] = pt.Syns.Scale[si]
+		pt.Syns.Scale[si] = 0
+		pt.Syns.Wt[si] = 0
+	}
+	if rl := pt.Recv; rl.Network != nil {
+		rl.Network.AddLesionRecord("LesionSyns", pt.Name, prop, nl, "")
+	}
+	return nl
+}
+
+// UnLesionSyns restores all the synapses lesioned by LesionSyns,
+// and records the operation in the network LesionLog.
+func (pt *Path) UnLesionSyns() {
+	for si, sc := range pt.lesionScales {
+		pt.Syns.Scale[si] = sc
+		pt.WtFromLWt(si)
+	}
+	n := len(pt.lesionScales)
+	pt.lesionScales = nil
+	if rl := pt.Recv; rl.Network != nil && n > 0 {
+		rl.Network.AddLesionRecord("UnLesionSyns", pt.Name, 0, n, "")
+	}
+}
+
+// NLesionedSyns returns the number of synapses lesioned by LesionSyns.
+func (pt *Path) NLesionedSyns() int {
+	return len(pt.lesionScales)
+}
+
+// UnLesionAll reverses all unit and synapse lesions and noise injection
+// in the network, and records these operations in the LesionLog.
+func (nt *Network) UnLesionAll() {
+	for _, ly := range nt.Layers {
+		if ly.NLesionedUnits() > 0 {
+			ly.UnLesionUnits()
+		}
+		if ly.NoiseInject.On {
+			ly.ClearNoise()
+		}
+		for _, pt := range ly.RecvPaths {
+			pt.UnLesionSyns()
+		}
+	}
+}
+
+//////////////////////////////////////////////////////////////////////////////////////
+//  NoiseInjectParams
+
+// NoiseInjectParams are parameters for injecting random noise into
+// neural activity, on top of any standard Act.Noise, during specified
+// quarters of the alpha cycle (e.g., only the minus or plus phase),
+// to simulate graded damage or neuromodulatory disruption.
+// Noise is generated anew on every cycle for each neuron.
+// Use Layer.InjectNoise and ClearNoise to record in the LesionLog.
+type NoiseInjectParams struct {
+	randx.RandParams
+
+	// whether noise injection is active
+	On bool
+
+	// where to add the noise: VmNoise, GeNoise, or ActNoise
+	Type ActNoiseType `default:"ActNoise"`
+
+	// quarters in which noise is injected: Q1, Q2, Q3 for the minus phase
+	// and Q4 for the plus phase. Note: this is a bitflag and must be
+	// accessed using its Set / Has etc routines.
+	Qtrs Quarters
+}
+
+func (ni *NoiseInjectParams) Update() {
+}
+
+func (ni *NoiseInjectParams) Defaults() {
+	ni.Dist = randx.Gaussian
+	ni.Type = ActNoise
+	ni.Qtrs.SetFlag(true, Q1, Q2, Q3, Q4)
+}
+
+// Active returns true if noise should be injected in the given quarter.
+func (ni *NoiseInjectParams) Active(qtr Quarters) bool {
+	return ni.On && ni.Qtrs.HasFlag(qtr)
+}
+
+// InjectConductance adds noise to the neuron's Ge or Vm, prior to
+// updating the activation, if Type is GeNoise or VmNoise,
+// using the given random number generator.
+func (ni *NoiseInjectParams) InjectConductance(nrn *Neuron, rnd randx.Rand) {
+	switch ni.Type {
+	case GeNoise:
+		nrn.Ge += Float(ni.Gen(rnd))
+	case VmNoise:
+		nrn.Vm += Float(ni.Gen(rnd))
+	}
+}
+
+// InjectAct adds noise to the neuron's Act, after updating the
+// activation, if Type is ActNoise, keeping the activation positive,
+// using the given random number generator.
+func (ni *NoiseInjectParams) InjectAct(nrn *Neuron, rnd randx.Rand) {
+	if ni.Type != ActNoise {
+		return
+	}
+	nrn.Act = max(nrn.Act+Float(ni.Gen(rnd)), 0)
+}
+
+// InjectNoise turns on noise injection for this layer, with given type
+// of noise, Gaussian variance, and quarters in which it is injected,
+// and records the operation in the network LesionLog.
+// Use ClearNoise to reverse.
+func (ly *Layer) InjectNoise(typ ActNoiseType, vr Float, qtrs Quarters) {
+	ni := &ly.NoiseInject
+	ni.On = true
+	ni.Type = typ
+	ni.Dist = randx.Gaussian
+	ni.Mean = 0
+	ni.Var = float64(vr)
+	ni.Qtrs = qtrs
+	ly.addLesionRecord("InjectNoise", vr, len(ly.Neurons), fmt.Sprintf("Type: %s  Qtrs: %s", typ, qtrs))
+}
+
+// ClearNoise turns off noise injection for this layer,
+// and records the operation in the network LesionLog.
+func (ly *Layer) ClearNoise() {
+	ly.NoiseInject.On = false
+	ly.addLesionRecord("ClearNoise", 0, len(ly.Neurons), "")
+}
@@ -0,0 +1,165 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// newLesionNet returns a network with a 10x10 hidden layer receiving
+// a full pathway from the input, with given random seed.
+func newLesionNet(t *testing.T, seed int64) (net *Network, in, hid *Layer) {
+	net = NewNetwork("Lesion")
+	in = net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid = net.AddLayer2D("Hidden", 10, 10, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.SetRandSeed(seed)
+	net.InitWeights()
+	return
+}
+
+func TestLesionUnits(t *testing.T) {
+	net, _, hid := newLesionNet(t, 1)
+	idxs := hid.LesionUnits(0.25)
+	if len(idxs) != 25 || hid.NLesionedUnits() != 25 {
+		t.Fatalf("lesioned %d (%d) units, not exactly 25 of 100", len(idxs), hid.NLesionedUnits())
+	}
+	more := hid.LesionUnits(0.25)
+	if hid.NLesionedUnits() != 50 {
+		t.Errorf("accumulated lesions: %d units, not 50", hid.NLesionedUnits())
+	}
+	for _, ni := range more {
+		if slices.Contains(idxs, ni) {
+			t.Errorf("unit %d lesioned twice", ni)
+		}
+	}
+
+	_, _, hid2 := newLesionNet(t, 1)
+	if idxs2 := hid2.LesionUnits(0.25); !slices.Equal(idxs2, idxs) {
+		t.Errorf("lesions not reproducible with the same seed:\n%v\n%v", idxs2, idxs)
+	}
+	_, _, hid3 := newLesionNet(t, 2)
+	if idxs3 := hid3.LesionUnits(0.25); slices.Equal(idxs3, idxs) {
+		t.Errorf("lesions the same with a different seed: %v", idxs3)
+	}
+
+	hid.UnLesionUnits(more...)
+	if hid.NLesionedUnits() != 25 {
+		t.Errorf("after unlesioning: %d units lesioned, not 25", hid.NLesionedUnits())
+	}
+	net.UnLesionAll()
+	if hid.NLesionedUnits() != 0 {
+		t.Errorf("after UnLesionAll: %d units lesioned", hid.NLesionedUnits())
+	}
+	if n := len(net.LesionLog); n != 4 {
+		t.Errorf("LesionLog has %d records, not 4:\n%s", n, net.LesionReport())
+	}
+}
+
+func TestLesionSyns(t *testing.T) {
+	_, _, hid := newLesionNet(t, 1)
+	pt := hid.RecvPaths[0]
+	wts := slices.Clone(pt.Syns.Wt)
+	lwts := slices.Clone(pt.Syns.LWt)
+	if n := pt.LesionSyns(0.3); n != 480 || pt.NLesionedSyns() != 480 {
+		t.Fatalf("lesioned %d synapses, not exactly 480 of 1600", n)
+	}
+	nz := 0
+	for _, wt := range pt.Syns.Wt {
+		if wt == 0 {
+			nz++
+		}
+	}
+	if nz != 480 {
+		t.Errorf("%d synapses with 0 weight, not 480", nz)
+	}
+
+	_, _, hid2 := newLesionNet(t, 1)
+	pt2 := hid2.RecvPaths[0]
+	pt2.LesionSyns(0.3)
+	if !slices.Equal(pt2.Syns.Wt, pt.Syns.Wt) {
+		t.Errorf("synapse lesions not reproducible with the same seed")
+	}
+
+	pt.UnLesionSyns()
+	if pt.NLesionedSyns() != 0 {
+		t.Errorf("%d synapses still lesioned", pt.NLesionedSyns())
+	}
+	for si, wt := range pt.Syns.Wt {
+		if math.Abs(float64(wt-wts[si])) > 1e-6 || pt.Syns.LWt[si] != lwts[si] {
+			t.Fatalf("synapse %d not restored: Wt %g vs. %g", si, wt, wts[si])
+		}
+	}
+}
+
+func TestNoiseInject(t *testing.T) {
+	net, in, hid := newLesionNet(t, 1)
+	inpat := tensor.NewFloat32([]int{4, 4})
+	for i := range 8 {
+		inpat.SetFloat1D(i*2, 1)
+	}
+	// trial runs a testing trial and returns the hidden Ge and Act values
+	// at every cycle of each quarter.
+	trial := func() (ge, act [4][]Float) {
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt(inpat)
+		net.AlphaCycInit(false)
+		ctx.AlphaCycStart()
+		for qtr := range 4 {
+			ctx.PlusPhase = qtr == 3
+			for range ctx.CycPerQtr {
+				net.Cycle(ctx)
+				ctx.CycleInc()
+				for ni := range hid.Neurons {
+					nrn := &hid.Neurons[ni]
+					ge[qtr] = append(ge[qtr], nrn.Ge)
+					act[qtr] = append(act[qtr], nrn.Act)
+				}
+			}
+			net.QuarterFinal(ctx)
+			if qtr < 3 {
+				ctx.QuarterInc()
+			}
+		}
+		return
+	}
+	geRef, actRef := trial()
+
+	for _, typ := range []ActNoiseType{GeNoise, ActNoise} {
+		for _, nqtr := range []Quarters{Q2, Q4} {
+			var qtrs Quarters
+			qtrs.SetFlag(true, nqtr)
+			hid.InjectNoise(typ, 0.01, qtrs)
+			ge, act := trial()
+			hid.ClearNoise()
+			vals, ref := act, actRef
+			if typ == GeNoise {
+				vals, ref = ge, geRef
+			}
+			for qtr := range Quarters(4) {
+				same := slices.Equal(vals[qtr], ref[qtr])
+				switch {
+				case qtr < nqtr && !same:
+					t.Errorf("%s in %s: changed %s before the noise", typ, nqtr, qtr)
+				case qtr == nqtr && same:
+					t.Errorf("%s in %s: no noise in %s", typ, nqtr, qtr)
+				}
+			}
+		}
+	}
+	if ge, act := trial(); !slices.Equal(ge[3], geRef[3]) || !slices.Equal(act[3], actRef[3]) {
+		t.Errorf("noise not cleared by ClearNoise")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/params"
+)
+
+// AtQuarterBoundary returns true if the context is at the boundary
+// between quarters (including the start and end of the trial), i.e.,
+// no cycles of the current quarter have been run yet.
+func (tm *Context) AtQuarterBoundary() bool {
+	return tm.CycPerQtr <= 0 || tm.Cycle%tm.CycPerQtr == 0
+}
+
+// UpdateParamsLive updates all the state that depends on the params,
+// after they have been changed during a run: the derived params
+// (UpdateParams), the scaling of the synaptic input conductances
+// (GScaleFromAvgAct), and the accumulated conductances (InitGInc),
+// which otherwise keep the values from the prior params until the
+// start of the next trial.
+func (nt *Network) UpdateParamsLive() {
+	nt.UpdateParams()
+	nt.GScaleFromAvgAct()
+	nt.InitGInc()
+}
+
+// ApplyParamsLive applies the given params sheet to the network while a
+// run is in progress, e.g., paused in the GUI, or from the remote control
+// Server, and then updates the state that depends on the params
+// (UpdateParamsLive).  It returns an error without applying anything if
+// the context is not at a quarter or trial boundary, as otherwise the
+// current quarter would mix the prior and new params.  For a hippocampus,
+// setting the DG -> CA3 mossy fiber WtScale.Rel sets the Theta.MossyRel
+// that it is modulated from by HipThetaPhase.
+// Returns true if any params were set, and error if there were any errors.
+func (nt *Network) ApplyParamsLive(ctx *Context, pars *params.Sheet, setMsg bool) (bool, error) {
+	if !ctx.AtQuarterBoundary() {
+		return false, fmt.Errorf("leabra.ApplyParamsLive: cycle %d is not at a quarter boundary", ctx.Cycle)
+	}
+	// the mossy fiber WtScale.Rel is set by HipThetaPhase from the
+	// Theta.MossyRel recorded from the params, which must be updated
+	// if the sheet sets it, as detected by setting it to -1 first.
+	mossy := nt.hipMossyPath()
+	var rel Float
+	if mossy != nil {
+		rel = mossy.WtScale.Rel
+		mossy.WtScale.Rel = -1
+	}
+	applied, err := nt.ApplyParams(pars, setMsg)
+	if mossy != nil {
+		if mossy.WtScale.Rel >= 0 {
+			nt.Theta.MossyRel = mossy.WtScale.Rel
+			rel = nt.Theta.MossyScale(int(ctx.Quarter), ctx.Mode == etime.Test)
+		}
+		mossy.WtScale.Rel = rel
+	}
+	if applied {
+		nt.UpdateParamsLive()
+	}
+	return applied, err
+}
+
+// SetParamLive sets a single param of the layer or pathway with the given
+// name while a run is in progress, as for ApplyParamsLive, where path is
+// the param path as in a params sheet, e.g., Layer.Inhib.Layer.Gi or
+// Path.Learn.Lrate, and val is the string value.
+func (nt *Network) SetParamLive(ctx *Context, name, path, val string) error {
+	sh := params.Sheet{{Sel: "#" + name, Desc: "SetParamLive", Params: params.Params{path: val}}}
+	applied, err := nt.ApplyParamsLive(ctx, &sh, false)
+	if err != nil {
+		return err
+	}
+	if !applied {
+		return fmt.Errorf("leabra.SetParamLive: no layer or pathway named %s has param %s", name, path)
+	}
+	return nil
+}
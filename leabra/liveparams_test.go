@@ -0,0 +1,41 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/params"
+)
+
+func TestApplyParamsLive(t *testing.T) {
+	net := MakeTestNet(t)
+	hid := net.LayerByName("Hidden")
+	pti, _ := hid.RecvPathBySendName("Input")
+	pt := pti.(*Path)
+	ctx := NewContext()
+	net.InitActs()
+	ctx.Cycle = 10
+	if err := net.SetParamLive(ctx, "Hidden", "Layer.Inhib.Layer.Gi", "2.5"); err == nil || hid.Inhib.Layer.Gi == 2.5 {
+		t.Error("params applied within a quarter")
+	}
+
+	ctx.Cycle = 25
+	gs := pt.GScale
+	pt.GInc[0] = 1
+	if err := net.SetParamLive(ctx, pt.Name, "Path.WtScale.Abs", "2"); err != nil {
+		t.Fatal(err)
+	}
+	if pt.WtScale.Abs != 2 || pt.GScale != 2*gs || pt.GInc[0] != 0 {
+		t.Errorf("dependent state not updated: GScale %g (was %g), GInc %g", pt.GScale, gs, pt.GInc[0])
+	}
+	sh := params.Sheet{{Sel: "Layer", Params: params.Params{"Layer.Inhib.Layer.Gi": "2.5"}}}
+	if applied, err := net.ApplyParamsLive(ctx, &sh, false); !applied || err != nil || hid.Inhib.Layer.Gi != 2.5 {
+		t.Errorf("sheet not applied: %v %v", applied, err)
+	}
+	if err := net.SetParamLive(ctx, "Nope", "Layer.Inhib.Layer.Gi", "1"); err == nil {
+		t.Error("no error for unknown layer")
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// LogWriter streams the rows of a log table to a file, as each row is
+// logged, as an alternative to the TSV files written by elog.Logs.
+type LogWriter interface {
+
+	// WriteRow writes the given row of the given table.
+	WriteRow(dt *table.Table, row int) error
+
+	// Close writes any buffered rows and closes the file.
+	Close() error
+}
+
+// LogFormats are the file formats for the log files of LogFiles.
+type LogFormats int32 //enums:enum
+
+const (
+	// LogTSV writes tab-separated values (.tsv) files,
+	// with elog.Logs.SetLogFile.
+	LogTSV LogFormats = iota
+
+	// LogParquet writes compressed Apache Parquet (.parquet) files,
+	// with a ParquetWriter.
+	LogParquet
+)
+
+// LogFiles manages the files that the log tables of elog.Logs are
+// saved to, in the given Format, with the same file names as
+// elog.SetLogFile other than the extension.  For the TSV format,
+// elog.Logs writes the files itself, while for other formats the
+// rows are written to a LogWriter by WriteLastRow, which must be
+// called after each Logs.LogRow (e.g., in the Log method of the sim).
+type LogFiles struct {
+
+	// Format is the file format.
+	Format LogFormats
+
+	// RowGroup is the number of rows per row group for the Parquet
+	// format, which are buffered before writing them to the file,
+	// so that at most this many rows are lost if the run crashes.
+	// Uses 100 if 0.
+	RowGroup int
+
+	// Writers are the writers for each scope of the log tables.
+	Writers map[etime.ScopeKey]LogWriter `display:"-"`
+}
+
+// SetLogFile sets the log file for given mode and time, using given
+// logName (extension), netName and runName, if the configOn flag is set,
+// as in elog.SetLogFile.
+func (lf *LogFiles) SetLogFile(logs *elog.Logs, configOn bool, mode etime.Modes, time etime.Times, logName, netName, runName string) {
+	if !configOn {
+		return
+	}
+	if lf.Format == LogTSV {
+		elog.SetLogFile(logs, configOn, mode, time, logName, netName, runName)
+		return
+	}
+	fnm := strings.TrimSuffix(elog.LogFilename(logName, netName, runName), ".tsv") + ".parquet"
+	if elog.LogDir != "" {
+		fnm = filepath.Join(elog.LogDir, fnm)
+	}
+	sk := etime.Scope(mode, time)
+	if ow := lf.Writers[sk]; ow != nil {
+		ow.Close()
+	}
+	pw, err := NewParquetWriter(fnm, lf.RowGroup)
+	if errors.Log(err) != nil {
+		return
+	}
+	if lf.Writers == nil {
+		lf.Writers = make(map[etime.ScopeKey]LogWriter)
+	}
+	lf.Writers[sk] = pw
+	fmt.Printf("Saving log to: %s\n", fnm)
+}
+
+// WriteLastRow writes the last row of the log table for given mode and
+// time to its LogWriter, if any, which must be called after Logs.LogRow.
+func (lf *LogFiles) WriteLastRow(logs *elog.Logs, mode etime.Modes, time etime.Times) {
+	lw := lf.Writers[etime.Scope(mode, time)]
+	if lw == nil {
+		return
+	}
+	dt := logs.Table(mode, time)
+	if dt == nil || dt.Rows == 0 {
+		return
+	}
+	errors.Log(lw.WriteRow(dt, dt.Rows-1))
+}
+
+// CloseLogFiles closes all the log files, including those of the logs.
+func (lf *LogFiles) CloseLogFiles(logs *elog.Logs) {
+	logs.CloseLogFiles()
+	for sk, lw := range lf.Writers {
+		errors.Log(lw.Close())
+		delete(lf.Writers, sk)
+	}
+}
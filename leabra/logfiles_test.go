@@ -0,0 +1,82 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+)
+
+func TestParquetLogFiles(t *testing.T) {
+	elog.LogDir = t.TempDir()
+	defer func() { elog.LogDir = "" }()
+	net := MakeTestNet(t)
+	var lg elog.Logs
+	var st estats.Stats
+	st.Init()
+	lg.AddItem(&elog.Item{Name: "Err", Type: reflect.Float64, Write: elog.WriteMap{
+		etime.Scope(etime.Train, etime.Trial): func(ctx *elog.Context) { ctx.SetStatFloat("Err") }}})
+	lg.AddItem(&elog.Item{Name: "TrialName", Type: reflect.String, Write: elog.WriteMap{
+		etime.Scope(etime.Train, etime.Trial): func(ctx *elog.Context) { ctx.SetStatString("TrialName") }}})
+	lg.CreateTables()
+	lg.SetContext(&st, net)
+	lf := &LogFiles{Format: LogParquet, RowGroup: 2}
+	lf.SetLogFile(&lg, true, etime.Train, etime.Trial, "trl", "Net", "Run")
+	fnm := filepath.Join(elog.LogDir, "Net_Run_trl.parquet")
+
+	// checks that the file is a complete Parquet file, returning its size
+	valid := func() int {
+		t.Helper()
+		b, err := os.ReadFile(fnm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := len(b)
+		if n < 12 || string(b[:4]) != "PAR1" || string(b[n-4:]) != "PAR1" || int(binary.LittleEndian.Uint32(b[n-8:])) > n-12 {
+			t.Fatalf("not a valid Parquet file: %d bytes", n)
+		}
+		return n
+	}
+	sizes := []int{}
+	for i := range 5 {
+		st.SetFloat("Err", float64(i))
+		st.SetString("TrialName", fmt.Sprintf("trial_%d", i))
+		lg.Log(etime.Train, etime.Trial)
+		lf.WriteLastRow(&lg, etime.Train, etime.Trial)
+		sizes = append(sizes, valid())
+	}
+	if sizes[1] <= sizes[0] || sizes[2] != sizes[1] || sizes[3] <= sizes[2] || sizes[4] != sizes[3] {
+		t.Errorf("row groups not flushed every 2 rows: sizes %v", sizes)
+	}
+	lf.CloseLogFiles(&lg)
+	if valid() <= sizes[4] || len(lf.Writers) != 0 {
+		t.Errorf("last row not written on close")
+	}
+
+	pw, err := NewParquetWriter(filepath.Join(elog.LogDir, "raw.parquet"), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pw.Uncompressed = true
+	dt := lg.Table(etime.Train, etime.Trial)
+	for r := range dt.Rows {
+		pw.WriteRow(dt, r)
+	}
+	pw.Close()
+	b, _ := os.ReadFile(filepath.Join(elog.LogDir, "raw.parquet"))
+	if !bytes.Contains(b, []byte("trial_4")) || !bytes.Contains(b, binary.LittleEndian.AppendUint64(nil, math.Float64bits(4))) {
+		t.Errorf("uncompressed values not found")
+	}
+}
@@ -60,7 +60,7 @@ func LogAddDiagnosticItems(lg *elog.Logs, layerNames []string, mode etime.Modes,
 			Write: elog.WriteMap{
 				etime.Scope(mode, times[ntimes-1]): func(ctx *elog.Context) {
 					ly := ctx.Layer(clnm).(*Layer)
-					ctx.SetFloat32(ly.Pools[0].ActAvg.ActMAvg)
+					ctx.SetFloat64(float64(ly.Pools[0].ActAvg.ActMAvg))
 				}}})
 		lg.AddStdAggs(itm, mode, times...)
 
@@ -72,7 +72,7 @@ func LogAddDiagnosticItems(lg *elog.Logs, layerNames []string, mode etime.Modes,
 			Write: elog.WriteMap{
 				etime.Scope(mode, times[ntimes-1]): func(ctx *elog.Context) {
 					ly := ctx.Layer(clnm).(*Layer)
-					ctx.SetFloat32(ly.Pools[0].ActM.Max)
+					ctx.SetFloat64(float64(ly.Pools[0].ActM.Max))
 				}}})
 		lg.AddStdAggs(itm, mode, times...)
 
@@ -83,7 +83,7 @@ func LogAddDiagnosticItems(lg *elog.Logs, layerNames []string, mode etime.Modes,
 			Write: elog.WriteMap{
 				etime.Scope(etime.Train, times[ntimes-1]): func(ctx *elog.Context) {
 					ly := ctx.Layer(clnm).(*Layer)
-					ctx.SetFloat32(ly.CosDiff.Cos)
+					ctx.SetFloat64(float64(ly.CosDiff.Cos))
 				}}})
 		lg.AddStdAggs(itm, mode, times...)
 	}
@@ -102,7 +102,7 @@ func LogInputLayer(lg *elog.Logs, net *Network, mode etime.Modes) {
 			Write: elog.WriteMap{
 				etime.Scope(etime.Train, etime.Epoch): func(ctx *elog.Context) {
 					ly := ctx.Layer(clnm).(*Layer)
-					ctx.SetFloat32(ly.Pools[0].ActM.Max)
+					ctx.SetFloat64(float64(ly.Pools[0].ActM.Max))
 				}}})
 	}
 }
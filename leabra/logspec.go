@@ -0,0 +1,240 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor/stats/stats"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// LogSpec is a declarative specification of a statistic to log,
+// which AddLogSpecs turns into elog items that compute the stat at
+// the lowest time scale, and aggregate it at each higher time scale,
+// so that the log tables and plots do not need to be wired by hand.
+// ParseLogSpec parses a LogSpec from a one-line text form, e.g.:
+//
+//	CorSim at Trial,Epoch,Run agg Mean plot
+//	ActMAvg at Trial,Epoch for Hidden1,Hidden2 in Train
+type LogSpec struct {
+
+	// Name is the name of the stat.  If Layers is empty, it is the name
+	// of a float stat in the estats.Stats of the log context, set by the
+	// sim at the lowest time scale, and is the item name.  Otherwise,
+	// it is a layer stat (see LogLayerStats), or the name of a unit
+	// variable, which is averaged over the units of each layer, and
+	// the item name is Layer_Name.
+	Name string
+
+	// Layers are the layers to log the layer stat for.
+	Layers []string
+
+	// Mode is the eval mode to log in, AllModes for all of them.
+	Mode etime.Modes
+
+	// Times are the time scales to log at, in higher to lower order
+	// (e.g., Run, Epoch, Trial), as for the other log item functions.
+	// The stat is computed at the lowest one.
+	Times []etime.Times
+
+	// Agg is the stat used to aggregate over the rows of the next lower
+	// time scale.  For the Run and Condition scales, it is computed over
+	// the last 5 rows, as in elog.AddStdAggs.
+	Agg stats.Stats
+
+	// Plot turns on plotting of the items.
+	Plot bool
+
+	// Range is the fixed plot range, if Max > Min; otherwise the
+	// plot min is fixed at 0 and the max floats.
+	Range minmax.F32
+}
+
+// LogLayerStats are the named layer stats available for LogSpec,
+// in addition to the unit variables.
+var LogLayerStats = map[string]func(ly *Layer) float64{
+	"ActMAvg": func(ly *Layer) float64 { return float64(ly.Pools[0].ActAvg.ActMAvg) },
+	"ActPAvg": func(ly *Layer) float64 { return float64(ly.Pools[0].ActAvg.ActPAvg) },
+	"ActMMax": func(ly *Layer) float64 { return float64(ly.Pools[0].ActM.Max) },
+	"CosDiff": func(ly *Layer) float64 { return float64(ly.CosDiff.Cos) },
+	"Gi":      func(ly *Layer) float64 { return float64(ly.Pools[0].Inhib.Gi) },
+}
+
+// ParseLogSpec parses a LogSpec from the text form:
+//
+//	<Name> [at <Times>] [for <Layers>] [in <Mode>] [agg <Stat>] [plot]
+//
+// where lists are comma-separated, and times can be given in any
+// order.  The defaults are: Trial,Epoch,Run times, AllModes, and Mean.
+func ParseLogSpec(s string) (*LogSpec, error) {
+	fs := strings.Fields(s)
+	if len(fs) == 0 {
+		return nil, fmt.Errorf("leabra.ParseLogSpec: empty spec")
+	}
+	ls := &LogSpec{Name: fs[0], Mode: etime.AllModes, Agg: stats.Mean}
+	for i := 1; i < len(fs); i++ {
+		kw := fs[i]
+		if kw == "plot" {
+			ls.Plot = true
+			continue
+		}
+		if i+1 >= len(fs) {
+			return nil, fmt.Errorf("leabra.ParseLogSpec: %q: missing value for %q", s, kw)
+		}
+		i++
+		val := fs[i]
+		switch kw {
+		case "at":
+			for _, ts := range strings.Split(val, ",") {
+				var tm etime.Times
+				if err := tm.SetString(ts); err != nil {
+					return nil, fmt.Errorf("leabra.ParseLogSpec: %q: %w", s, err)
+				}
+				ls.Times = append(ls.Times, tm)
+			}
+		case "for":
+			ls.Layers = strings.Split(val, ",")
+		case "in":
+			if err := ls.Mode.SetString(val); err != nil {
+				return nil, fmt.Errorf("leabra.ParseLogSpec: %q: %w", s, err)
+			}
+		case "agg":
+			if err := ls.Agg.SetString(val); err != nil {
+				return nil, fmt.Errorf("leabra.ParseLogSpec: %q: %w", s, err)
+			}
+		default:
+			return nil, fmt.Errorf("leabra.ParseLogSpec: %q: unknown keyword %q", s, kw)
+		}
+	}
+	if len(ls.Times) == 0 {
+		ls.Times = []etime.Times{etime.Run, etime.Epoch, etime.Trial}
+	}
+	slices.SortFunc(ls.Times, func(a, b etime.Times) int { return int(b) - int(a) })
+	return ls, nil
+}
+
+// ItemNames returns the names of the log items for the spec.
+func (ls *LogSpec) ItemNames() []string {
+	if len(ls.Layers) == 0 {
+		return []string{ls.Name}
+	}
+	nms := make([]string, len(ls.Layers))
+	for i, lnm := range ls.Layers {
+		nms[i] = lnm + "_" + ls.Name
+	}
+	return nms
+}
+
+// AddLogSpecs adds the log items for the given specs to the logs,
+// returning the items.  This must be called before CreateTables.
+func AddLogSpecs(lg *elog.Logs, specs ...*LogSpec) []*elog.Item {
+	var itms []*elog.Item
+	for _, ls := range specs {
+		if len(ls.Times) == 0 {
+			continue
+		}
+		if len(ls.Layers) == 0 {
+			itms = append(itms, ls.addItem(lg, ls.Name, func(ctx *elog.Context) float64 {
+				return ctx.Stats.Float(ls.Name)
+			}))
+			continue
+		}
+		for i, lnm := range ls.Layers {
+			lnm := lnm // closure
+			val := func(ctx *elog.Context) float64 {
+				return logLayerStat(ctx.Layer(lnm).(*Layer), ls.Name)
+			}
+			itms = append(itms, ls.addItem(lg, ls.ItemNames()[i], val))
+		}
+	}
+	return itms
+}
+
+// AddLogSpecStrings parses the given specs with ParseLogSpec,
+// and adds their log items with AddLogSpecs.
+func AddLogSpecStrings(lg *elog.Logs, specs ...string) ([]*elog.Item, error) {
+	lss := make([]*LogSpec, len(specs))
+	for i, s := range specs {
+		ls, err := ParseLogSpec(s)
+		if err != nil {
+			return nil, err
+		}
+		lss[i] = ls
+	}
+	return AddLogSpecs(lg, lss...), nil
+}
+
+// addItem adds the log item with the given name, computing the given
+// value at the lowest time scale, and aggregating at the higher ones.
+func (ls *LogSpec) addItem(lg *elog.Logs, name string, val func(ctx *elog.Context) float64) *elog.Item {
+	ntimes := len(ls.Times)
+	itm := &elog.Item{
+		Name:   name,
+		Type:   reflect.Float64,
+		Plot:   ls.Plot,
+		FixMin: true,
+		Range:  minmax.F32{Max: 1},
+		Write: elog.WriteMap{
+			etime.Scope(ls.Mode, ls.Times[ntimes-1]): func(ctx *elog.Context) {
+				ctx.SetFloat64(val(ctx))
+			}}}
+	if ls.Range.Max > ls.Range.Min {
+		itm.Range = ls.Range
+		itm.FixMax = true
+	}
+	for i := ntimes - 2; i >= 0; i-- {
+		tm, lower := ls.Times[i], ls.Times[i+1]
+		if tm == etime.Run || tm == etime.Condition {
+			itm.Write[etime.Scope(ls.Mode, tm)] = func(ctx *elog.Context) {
+				ix := ctx.LastNRows(ctx.Mode, lower, 5)
+				vals, err := stats.StatColumn(ix, ctx.Item.Name, ls.Agg)
+				if err != nil || len(vals) == 0 {
+					ctx.SetFloat64(math.NaN())
+					return
+				}
+				ctx.SetFloat64(vals[0])
+			}
+		} else {
+			itm.Write[etime.Scope(ls.Mode, tm)] = func(ctx *elog.Context) {
+				ctx.SetAgg(ctx.Mode, lower, ls.Agg)
+			}
+		}
+	}
+	lg.AddItem(itm)
+	return itm
+}
+
+// logLayerStat returns the named layer stat (see LogLayerStats),
+// or the average of the unit variable of that name over the units
+// in the layer, for the first data parallel index.
+func logLayerStat(ly *Layer, name string) float64 {
+	if sf, ok := LogLayerStats[name]; ok {
+		return sf(ly)
+	}
+	vi, err := ly.UnitVarIndex(name)
+	if err != nil {
+		return math.NaN()
+	}
+	sum, n := 0.0, 0
+	for ni := range ly.Neurons {
+		v := ly.UnitValue1D(vi, ni, 0)
+		if math.IsNaN(float64(v)) {
+			continue
+		}
+		sum += float64(v)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
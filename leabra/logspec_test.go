@@ -0,0 +1,61 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor/stats/stats"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestLogSpec(t *testing.T) {
+	ls, err := ParseLogSpec("Act at Epoch,Trial for Hidden in Train agg Max plot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ls.Mode != etime.Train || ls.Agg != stats.Max || !ls.Plot || len(ls.Times) != 2 || ls.Times[0] != etime.Epoch {
+		t.Fatalf("ParseLogSpec: %+v", ls)
+	}
+	if _, err := ParseLogSpec("Act at Trial agg"); err == nil {
+		t.Error("ParseLogSpec: expected missing value error")
+	}
+
+	net := NewNetwork("LogSpec")
+	in := net.AddLayer2D("Input", 1, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 1, 2, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	var lg elog.Logs
+	var st estats.Stats
+	st.Init()
+	AddLogSpecs(&lg, ls, &LogSpec{Name: "Err", Mode: etime.Train, Times: []etime.Times{etime.Epoch, etime.Trial}, Agg: stats.Mean})
+	lg.CreateTables()
+	lg.SetContext(&st, net)
+	for i := range 3 {
+		hid.Neurons[0].Act = Float(i) * 0.1
+		hid.Neurons[1].Act = 0
+		st.SetFloat("Err", float64(i))
+		lg.Log(etime.Train, etime.Trial)
+	}
+	lg.Log(etime.Train, etime.Epoch)
+	ep := lg.Table(etime.Train, etime.Epoch)
+	if v := ep.Float("Hidden_Act", 0); math.Abs(v-0.1) > 1.0e-6 { // max of mean act 0, .05, .1
+		t.Errorf("Hidden_Act epoch: %g, not 0.1", v)
+	}
+	if v := ep.Float("Err", 0); v != 1 {
+		t.Errorf("Err epoch: %g, not 1", v)
+	}
+	if itm, _ := lg.ItemByName("Hidden_Act"); !itm.Plot {
+		t.Error("Hidden_Act not plotted")
+	}
+}
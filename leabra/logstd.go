@@ -0,0 +1,104 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"reflect"
+
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// The standard logs of the sims are configured in three parts, which
+// otherwise are repeated nearly verbatim in every sim:
+//
+//   - LogAddStdItems adds the counter and name items at the start
+//     of ConfigLogs, followed by the stats of the sim, declared with
+//     LogSpec items (AddLogSpecs) or the elog Add*Item methods, which
+//     aggregate from the lower scopes.
+//   - LogConfigStdTables creates the tables from the items (schema),
+//     at the end of ConfigLogs.
+//   - LogFiles.SetStdLogFiles sets the standard log files at the start
+//     of RunNoGUI, from the flags of the log config of the sim, and
+//     LogFiles.LogRow logs each row and writes it to the files.
+
+// LogFileSpec specifies a standard log file, for LogFiles.SetStdLogFiles.
+type LogFileSpec struct {
+
+	// Flag is the name of the bool field in the log config of the sim
+	// that turns on saving this log to a file.
+	Flag string
+
+	// Mode is the eval mode of the log table.
+	Mode etime.Modes
+
+	// Time is the time scale of the log table.
+	Time etime.Times
+
+	// Name is the name of the log, which is the file extension
+	// before .tsv, e.g., epc for _epc.tsv.
+	Name string
+}
+
+// StdLogFiles are the standard log files of the sims.
+var StdLogFiles = []LogFileSpec{
+	{"Trial", etime.Train, etime.Trial, "trl"},
+	{"Epoch", etime.Train, etime.Epoch, "epc"},
+	{"Run", etime.Train, etime.Run, "run"},
+	{"TestEpoch", etime.Test, etime.Epoch, "tst_epc"},
+	{"TestTrial", etime.Test, etime.Trial, "tst_trl"},
+}
+
+// LogAddStdItems adds the standard items at the start of the logs:
+// the counters for the Run, Epoch, Trial, and Cycle time scales, the
+// RunName stat for all scopes, which is set to the given runName, and
+// the TrialName stat at the Trial scope, which is typically set from
+// the env in StatCounters.
+func LogAddStdItems(lg *elog.Logs, stats *estats.Stats, runName string) {
+	stats.SetString("RunName", runName) // used for naming logs, stats, etc
+	lg.AddCounterItems(etime.Run, etime.Epoch, etime.Trial, etime.Cycle)
+	lg.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
+	lg.AddStatStringItem(etime.AllModes, etime.Trial, "TrialName")
+}
+
+// LogConfigStdTables creates the log tables from the items added to the
+// logs, and sets the context with the given stats and network.  It turns
+// off plotting of the Train Cycle and Test Run logs, which are not used,
+// and of any additional given scopes, e.g., etime.Scope(etime.Test,
+// etime.Cycle), and sets the legend of the Train Run plot to RunName.
+func LogConfigStdTables(lg *elog.Logs, stats *estats.Stats, net *Network, noPlot ...etime.ScopeKey) {
+	lg.CreateTables()
+	lg.SetContext(stats, net)
+	lg.NoPlot(etime.Train, etime.Cycle)
+	lg.NoPlot(etime.Test, etime.Run)
+	for _, sk := range noPlot {
+		lg.NoPlotScope(sk)
+	}
+	// note: Analyze not plotted by default
+	lg.SetMeta(etime.Train, etime.Run, "LegendCol", "RunName")
+}
+
+// SetStdLogFiles sets the StdLogFiles for the given network and run
+// names, for each one whose Flag is a true bool field in the given
+// log config struct (or pointer to it), e.g., ss.Config.Log.
+func (lf *LogFiles) SetStdLogFiles(logs *elog.Logs, cfg any, netName, runName string) {
+	cv := reflect.Indirect(reflect.ValueOf(cfg))
+	for _, fs := range StdLogFiles {
+		fv := cv.FieldByName(fs.Flag)
+		if !fv.IsValid() || fv.Kind() != reflect.Bool {
+			continue
+		}
+		lf.SetLogFile(logs, fv.Bool(), fs.Mode, fs.Time, fs.Name, netName, runName)
+	}
+}
+
+// LogRow logs the given row of the log for the given mode and time,
+// computing the values of all the items, and writes it to the log file,
+// if any (see WriteLastRow).
+func (lf *LogFiles) LogRow(logs *elog.Logs, mode etime.Modes, time etime.Times, row int) {
+	logs.LogRow(mode, time, row)
+	lf.WriteLastRow(logs, mode, time)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/stats/stats"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+)
+
+func TestStdLogs(t *testing.T) {
+	elog.LogDir = t.TempDir()
+	defer func() { elog.LogDir = "" }()
+	net := MakeTestNet(t)
+	var lg elog.Logs
+	var st estats.Stats
+	st.Init()
+	for _, nm := range []string{"Run", "Epoch", "Trial", "Cycle"} {
+		st.SetInt(nm, 0)
+	}
+	LogAddStdItems(&lg, &st, "Run")
+	AddLogSpecs(&lg, &LogSpec{Name: "Err", Mode: etime.Train, Times: []etime.Times{etime.Epoch, etime.Trial}, Agg: stats.Mean})
+	LogConfigStdTables(&lg, &st, net, etime.Scope(etime.Train, etime.Trial))
+	for _, nm := range []string{"Run", "Epoch", "Trial", "RunName", "TrialName", "Err"} {
+		if _, err := lg.Table(etime.Train, etime.Trial).ColumnIndex(nm); err != nil {
+			t.Errorf("column %s not in the Train Trial log", nm)
+		}
+	}
+	if lg.TableDetails(etime.Train, etime.Trial).Meta["Plot"] != "false" {
+		t.Errorf("Train Trial log is plotted")
+	}
+
+	cfg := struct {
+		Trial bool
+		Epoch bool
+		Run   int // not a flag
+	}{Epoch: true}
+	lf := &LogFiles{}
+	lf.SetStdLogFiles(&lg, &cfg, "Net", st.String("RunName"))
+	for i := range 4 {
+		st.SetFloat("Err", float64(i))
+		st.SetString("TrialName", fmt.Sprintf("trial_%d", i))
+		lf.LogRow(&lg, etime.Train, etime.Trial, i)
+	}
+	lf.LogRow(&lg, etime.Train, etime.Epoch, 0)
+	if v := lg.Table(etime.Train, etime.Epoch).Float("Err", 0); v != 1.5 {
+		t.Errorf("epoch Err = %g, not the mean of the trials 1.5", v)
+	}
+	lf.CloseLogFiles(&lg)
+	b, err := os.ReadFile(filepath.Join(elog.LogDir, "Net_Run_epc.tsv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "Err") || !strings.Contains(string(b), "1.5") {
+		t.Errorf("epoch log file missing values:\n%s", b)
+	}
+	if _, err := os.Stat(filepath.Join(elog.LogDir, "Net_Run_trl.tsv")); err == nil {
+		t.Errorf("trial log file saved with Trial off")
+	}
+}
@@ -112,6 +112,34 @@ func LooperResetLogBelow(ls *looper.Stacks, logs *elog.Logs, except ...etime.Tim
 	}
 }
 
+// LooperErrStats does the bookkeeping for the FirstZero, LastZero and NZero
+// stats computed by the log items of elog.AddErrStatAggItems, which
+// aggregate the trial error stat to the PctErr and PctCor at the epoch
+// and run levels.  It resets them at the start of each training run
+// (elog.InitErrStats), so the sim does not need to, computes the RunStats
+// of PctCor, FirstZero and LastZero at the end of each run, and, if nZero
+// is non-nil, stops the training epoch loop after nZero() epochs in a row
+// with no errors (2 if it returns <= 0).  Call it after adding the
+// functions that write the logs, as RunStats uses the last run log row.
+func LooperErrStats(ls *looper.Stacks, logs *elog.Logs, nZero func() int) {
+	ls.Loop(etime.Train, etime.Run).OnStart.Add("InitErrStats", func() {
+		logs.InitErrStats()
+	})
+	ls.Loop(etime.Train, etime.Run).OnEnd.Add("RunStats", func() {
+		logs.RunStats("PctCor", "FirstZero", "LastZero")
+	})
+	if nZero == nil {
+		return
+	}
+	ls.Loop(etime.Train, etime.Epoch).IsDone.AddBool("NZeroStop", func() bool {
+		stopNz := nZero()
+		if stopNz <= 0 {
+			stopNz = 2
+		}
+		return logs.Context.Stats.Int("NZero") >= stopNz
+	})
+}
+
 // LooperUpdateNetView adds netview update calls at each time level
 func LooperUpdateNetView(ls *looper.Stacks, viewupdt *netview.ViewUpdate, net *Network, ctrUpdateFunc func(tm etime.Times)) {
 	for m, stack := range ls.Stacks {
@@ -0,0 +1,61 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/enums"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+func TestLooperErrStats(t *testing.T) {
+	var lg elog.Logs
+	var st estats.Stats
+	st.Init()
+	st.SetString("RunName", "Test")
+	lg.AddStatStringItem(etime.AllModes, etime.AllTimes, "RunName")
+	lg.AddErrStatAggItems("TrlErr", etime.Run, etime.Epoch, etime.Trial)
+	lg.CreateTables()
+	lg.SetContext(&st, nil)
+
+	ls := looper.NewStacks()
+	ls.AddStack(etime.Train).AddTime(etime.Run, 2).AddTime(etime.Epoch, 10).AddTime(etime.Trial, 3)
+	run := ls.Loop(etime.Train, etime.Run)
+	epc := ls.Loop(etime.Train, etime.Epoch)
+	ls.Loop(etime.Train, etime.Trial).OnStart.Add("TrlErr", func() {
+		st.SetInt("Epoch", epc.Counter.Cur)
+		err := 0.0
+		if epc.Counter.Cur < 2+run.Counter.Cur { // errors until epoch 2 in run 0, 3 in run 1
+			err = 1
+		}
+		st.SetFloat("TrlErr", err)
+	})
+	ls.AddOnEndToAll("Log", func(mode, time enums.Enum) {
+		lg.Log(mode.(etime.Modes), time.(etime.Times))
+	})
+	LooperResetLogBelow(ls, &lg)
+	LooperErrStats(ls, &lg, func() int { return 2 })
+	ls.Run(etime.Train)
+
+	rl := lg.Table(etime.Train, etime.Run)
+	if rl.Rows != 2 {
+		t.Fatalf("run log rows: %d, not 2", rl.Rows)
+	}
+	for r, first := range []float64{2, 3} {
+		if v := rl.Float("FirstZero", r); v != first {
+			t.Errorf("run %d FirstZero: %g, not %g", r, v, first)
+		}
+		if v := rl.Float("LastZero", r); v != first+1 { // stopped after 2 zero epochs
+			t.Errorf("run %d LastZero: %g, not %g", r, v, first+1)
+		}
+	}
+	if rs := lg.MiscTables["RunStats"]; rs == nil || rs.Float("FirstZero:Mean", 0) != 2.5 {
+		t.Errorf("RunStats not computed over both runs: %v", rs)
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// LrateScheds are the types of learning rate schedules,
+// for LrateSchedParams.
+type LrateScheds int32 //enums:enum
+
+const (
+	// LrateConstant keeps the learning rate at its initial value
+	// (after any Warmup).
+	LrateConstant LrateScheds = iota
+
+	// LrateStep multiplies the learning rate by Factor at each of
+	// the Steps epochs.
+	LrateStep
+
+	// LrateExp multiplies the learning rate by Factor every epoch
+	// after the Warmup.
+	LrateExp
+
+	// LrateCosine decays the learning rate from its initial value to
+	// Min times that value over the epochs from the end of the Warmup
+	// to Epochs, following half a cosine cycle.
+	LrateCosine
+)
+
+// LrateSchedParams are the parameters for a learning rate schedule,
+// which sets the learning rate Lrate of a pathway as a function of the
+// training epoch, as a multiplier on the initial learning rate LrateInit
+// (set from the Lrate params), including an optional linear warmup from
+// WarmupStart times the initial rate over the first Warmup epochs.
+// It is set per pathway, or network-wide with a Path params selector,
+// and is advanced by Network.EpochInc or SetLrateEpoch, which can be
+// called automatically by LooperLrateSched.  Pathways with a LrateConstant
+// schedule and no Warmup are not affected, so that sims can still set
+// their learning rates directly, e.g., with LrateMult.
+type LrateSchedParams struct {
+
+	// type of schedule
+	Type LrateScheds
+
+	// number of epochs of linear warmup at the start of training, from WarmupStart times the initial learning rate up to the initial learning rate -- 0 = no warmup
+	Warmup int `min:"0"`
+
+	// multiplier on the initial learning rate at the start of the Warmup
+	WarmupStart Float `default:"0.1" min:"0"`
+
+	// epochs at which the learning rate is multiplied by Factor, for LrateStep, e.g., [50, 100] in params
+	Steps []int
+
+	// multiplier on the learning rate at each step for LrateStep, or every epoch for LrateExp
+	Factor Float `default:"0.5" min:"0"`
+
+	// last epoch of the LrateCosine schedule, after which the learning rate stays at Min times the initial rate
+	Epochs int `default:"100" min:"1"`
+
+	// minimum multiplier on the initial learning rate at the end of the LrateCosine schedule
+	Min Float `default:"0" min:"0"`
+}
+
+func (ls *LrateSchedParams) Update() {
+}
+
+func (ls *LrateSchedParams) Defaults() {
+	ls.Type = LrateConstant
+	ls.Warmup = 0
+	ls.WarmupStart = 0.1
+	ls.Steps = nil
+	ls.Factor = 0.5
+	ls.Epochs = 100
+	ls.Min = 0
+}
+
+func (ls *LrateSchedParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "Steps":
+		return ls.Type == LrateStep
+	case "Factor":
+		return ls.Type == LrateStep || ls.Type == LrateExp
+	case "Epochs", "Min":
+		return ls.Type == LrateCosine
+	case "WarmupStart":
+		return ls.Warmup > 0
+	default:
+		return true
+	}
+}
+
+// IsOn returns true if the schedule changes the learning rate,
+// i.e., it is not LrateConstant or has a Warmup.
+func (ls *LrateSchedParams) IsOn() bool {
+	return ls.Type != LrateConstant || ls.Warmup > 0
+}
+
+// Mult returns the multiplier on the initial learning rate
+// for the given training epoch, starting at 0.
+func (ls *LrateSchedParams) Mult(epoch int) Float {
+	if epoch < ls.Warmup {
+		return ls.WarmupStart + (1-ls.WarmupStart)*Float(epoch)/Float(ls.Warmup)
+	}
+	switch ls.Type {
+	case LrateStep:
+		mult := Float(1)
+		for _, st := range ls.Steps {
+			if epoch >= st {
+				mult *= ls.Factor
+			}
+		}
+		return mult
+	case LrateExp:
+		return Float(math.Pow(float64(ls.Factor), float64(epoch-ls.Warmup)))
+	case LrateCosine:
+		n := ls.Epochs - ls.Warmup
+		if n <= 0 || epoch >= ls.Epochs {
+			return ls.Min
+		}
+		prog := float64(epoch-ls.Warmup) / float64(n)
+		return ls.Min + (1-ls.Min)*Float(0.5*(1+math.Cos(math.Pi*prog)))
+	}
+	return 1
+}
+
+// LrateSched sets the learning rate of the pathway according to its
+// learning rate schedule (Learn.LrateSched) for the given training epoch,
+// if the schedule is on.
+func (pt *Path) LrateSched(epoch int) {
+	if !pt.Learn.LrateSched.IsOn() {
+		return
+	}
+	pt.LrateMult(pt.Learn.LrateSched.Mult(epoch))
+}
+
+// SetLrateEpoch sets the current training epoch for the learning rate
+// schedules of the pathways (Learn.LrateSched), and sets their
+// learning rates accordingly, e.g., when resuming from a checkpoint.
+// It also sets the clamping of layers with a clamping schedule
+// (Act.ClampSched) for the epoch.
+func (nt *Network) SetLrateEpoch(epoch int) {
+	nt.LrateEpoch = epoch
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		ly.ClampSched(epoch)
+		for _, pt := range ly.RecvPaths {
+			if pt.Off {
+				continue
+			}
+			pt.LrateSched(epoch)
+		}
+	}
+}
+
+// EpochInc increments the current training epoch for the learning rate
+// schedules of the pathways (Learn.LrateSched), and sets their learning
+// rates accordingly.  Call at the end of each training epoch, if not
+// using LooperLrateSched.  The epoch is reset to 0 by InitWeights.
+func (nt *Network) EpochInc() {
+	nt.SetLrateEpoch(nt.LrateEpoch + 1)
+}
+
+// LooperLrateSched adds a function at the start of each training epoch
+// that sets the learning rates of the pathways from their learning rate
+// schedules (Learn.LrateSched) for the current epoch counter,
+// using SetLrateEpoch, which also applies any layer clamping
+// schedules (Act.ClampSched).
+func LooperLrateSched(ls *looper.Stacks, net *Network) {
+	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnStart.Add("LrateSched", func() {
+		net.SetLrateEpoch(trainEpoch.Counter.Cur)
+	})
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/emergent/v2/params"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestLrateSched(t *testing.T) {
+	ls := &LrateSchedParams{}
+	ls.Defaults()
+	if ls.IsOn() || ls.Mult(10) != 1 {
+		t.Errorf("LrateConstant: IsOn %v Mult %v", ls.IsOn(), ls.Mult(10))
+	}
+	ls.Warmup = 4
+	ls.WarmupStart = 0.2
+	for ep, want := range []Float{0.2, 0.4, 0.6, 0.8, 1, 1} {
+		if got := ls.Mult(ep); math.Abs(float64(got-want)) > 1e-6 {
+			t.Errorf("warmup epoch %d: got %v, want %v", ep, got, want)
+		}
+	}
+	ls.Warmup = 0
+	ls.Type = LrateExp
+	ls.Factor = 0.5
+	if got := ls.Mult(3); got != 0.125 {
+		t.Errorf("LrateExp: got %v, want 0.125", got)
+	}
+	ls.Type = LrateCosine
+	ls.Epochs = 10
+	ls.Min = 0.2
+	for ep, want := range map[int]Float{0: 1, 5: 0.6, 10: 0.2, 20: 0.2} {
+		if got := ls.Mult(ep); math.Abs(float64(got-want)) > 1e-6 {
+			t.Errorf("LrateCosine epoch %d: got %v, want %v", ep, got, want)
+		}
+	}
+
+	net := NewNetwork("LrateSched")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+	p1 := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	p2 := net.ConnectLayers(hid, out, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.ApplyParams(&params.Sheet{
+		{Sel: "#HiddenToOutput", Desc: "step schedule",
+			Params: params.Params{
+				"Path.Learn.LrateSched.Type":  "LrateStep",
+				"Path.Learn.LrateSched.Steps": "[2, 4]",
+			}},
+	}, false)
+	net.InitWeights()
+	lr0 := p2.Learn.LrateInit
+	for ep, want := range []Float{1, 1, 0.5, 0.5, 0.25} {
+		if ep > 0 {
+			net.EpochInc()
+		}
+		if got := p2.Learn.Lrate; math.Abs(float64(got-want*lr0)) > 1e-6 {
+			t.Errorf("epoch %d: got Lrate %v, want %v", ep, got, want*lr0)
+		}
+		if p1.Learn.Lrate != p1.Learn.LrateInit {
+			t.Errorf("epoch %d: unscheduled path Lrate changed to %v", ep, p1.Learn.Lrate)
+		}
+	}
+	net.InitWeights()
+	if net.LrateEpoch != 0 || p2.Learn.Lrate != lr0 {
+		t.Errorf("InitWeights: got LrateEpoch %d Lrate %v", net.LrateEpoch, p2.Learn.Lrate)
+	}
+}
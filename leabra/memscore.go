@@ -0,0 +1,130 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+
+	"cogentcore.org/core/tensor/stats/metric"
+)
+
+// MemScoreParams select the memory scoring functions to compute,
+// in addition to the standard binary memory criterion,
+// which is based on ReadoutMemStats with the Thr threshold.
+// The alternative scores are based on the continuous correlation
+// between the actual activity and the target pattern on each trial
+// (see MemCorrel), which is then compared between studied items and
+// novel lure items over a set of test trials, in terms of d-prime
+// (MemDPrime) and the area under the ROC curve (MemROCArea).
+type MemScoreParams struct {
+
+	// threshold on the proportion of incorrect units (target on but was off,
+	// or target off but was on) for a trial to count as remembered,
+	// for the binary memory criterion
+	Thr float32 `default:"0.34"`
+
+	// compute the correlation between the actual activity and target
+	// pattern on each trial, as a continuous measure of memory
+	Correl bool `default:"true"`
+
+	// compute the d-prime discriminability of correlation scores for
+	// studied items vs. lures, over the test trials
+	DPrime bool `default:"true"`
+
+	// compute the area under the ROC curve of correlation scores for
+	// studied items vs. lures, over the test trials
+	ROC bool `default:"true"`
+}
+
+func (ms *MemScoreParams) Update() {
+}
+
+func (ms *MemScoreParams) Defaults() {
+	ms.Thr = 0.34
+	ms.Correl = true
+	ms.DPrime = true
+	ms.ROC = true
+	ms.Update()
+}
+
+// MemCorrel returns the correlation between the actual activity and
+// the target pattern values, as a continuous measure of memory recall,
+// which does not depend on any thresholds.  Returns 0 if either pattern
+// has no variance (e.g., no activity).
+func MemCorrel(act, trg []float32) float32 {
+	r := metric.Correlation32(act, trg)
+	if math.IsNaN(float64(r)) {
+		return 0
+	}
+	return r
+}
+
+// MemDPrimeMax is the maximum magnitude of the d-prime returned by
+// MemDPrime, which would otherwise be infinite when there is no
+// variance in the scores but a difference in means.
+const MemDPrimeMax = 5
+
+// MemDPrime returns the d-prime discriminability of the scores for
+// studied (target) items vs. the scores for lure items: the difference
+// in means divided by the square root of the average of the variances,
+// clamped to +/- MemDPrimeMax, which is returned when there is no
+// variance but a difference in means.  Returns NaN if either set of
+// scores is empty, and 0 if there is no variance and no difference in means.
+func MemDPrime(tgt, lure []float64) float64 {
+	if len(tgt) == 0 || len(lure) == 0 {
+		return math.NaN()
+	}
+	tm, tv := memMeanVar(tgt)
+	lm, lv := memMeanVar(lure)
+	sd := math.Sqrt(0.5 * (tv + lv))
+	if sd == 0 {
+		switch {
+		case tm > lm:
+			return MemDPrimeMax
+		case tm < lm:
+			return -MemDPrimeMax
+		}
+		return 0
+	}
+	return min(max((tm-lm)/sd, -MemDPrimeMax), MemDPrimeMax)
+}
+
+// MemROCArea returns the area under the ROC (receiver operating
+// characteristic) curve for discriminating studied (target) items from
+// lure items based on their scores, which is the probability that a
+// randomly chosen target has a higher score than a randomly chosen lure
+// (with ties counting 1/2).  0.5 is chance and 1 is perfect discrimination.
+// Returns NaN if either set of scores is empty.
+func MemROCArea(tgt, lure []float64) float64 {
+	if len(tgt) == 0 || len(lure) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, t := range tgt {
+		for _, l := range lure {
+			switch {
+			case t > l:
+				sum += 1
+			case t == l:
+				sum += 0.5
+			}
+		}
+	}
+	return sum / float64(len(tgt)*len(lure))
+}
+
+// memMeanVar returns the mean and (population) variance of vals.
+func memMeanVar(vals []float64) (mean, vr float64) {
+	for _, v := range vals {
+		mean += v
+	}
+	mean /= float64(len(vals))
+	for _, v := range vals {
+		d := v - mean
+		vr += d * d
+	}
+	vr /= float64(len(vals))
+	return
+}
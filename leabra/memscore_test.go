@@ -0,0 +1,57 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMemCorrel(t *testing.T) {
+	tests := []struct {
+		name     string
+		act, trg []float32
+		want     float32
+	}{
+		{"same", []float32{0, 1, 0, 1}, []float32{0, 1, 0, 1}, 1},
+		{"opposite", []float32{1, 0, 1, 0}, []float32{0, 1, 0, 1}, -1},
+		{"no activity", []float32{0, 0, 0, 0}, []float32{0, 1, 0, 1}, 0},
+		{"constant target", []float32{0, 1, 0, 1}, []float32{1, 1, 1, 1}, 0},
+	}
+	for _, tt := range tests {
+		if got := MemCorrel(tt.act, tt.trg); math.Abs(float64(got-tt.want)) > 1e-6 {
+			t.Errorf("%s: MemCorrel = %g, want %g", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMemDPrimeROC(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name        string
+		tgt, lure   []float64
+		dp, rocArea float64
+	}{
+		{"empty targets", nil, []float64{0.1}, nan, nan},
+		{"empty lures", []float64{0.9}, nil, nan, nan},
+		{"all ties", []float64{0.5, 0.5}, []float64{0.5, 0.5}, 0, 0.5},
+		{"no variance, targets higher", []float64{0.9, 0.9}, []float64{0.1, 0.1}, MemDPrimeMax, 1},
+		{"no variance, lures higher", []float64{0.1}, []float64{0.9, 0.9}, -MemDPrimeMax, 0},
+		{"large separation", []float64{1, 1.01}, []float64{0, 0.01}, MemDPrimeMax, 1},
+		{"overlap", []float64{1, 3}, []float64{0, 2}, 1, 0.75},
+		{"partial ties", []float64{1, 2}, []float64{1, 0}, 2, 0.875},
+	}
+	same := func(a, b float64) bool {
+		return (math.IsNaN(a) && math.IsNaN(b)) || math.Abs(a-b) < 1e-9
+	}
+	for _, tt := range tests {
+		if got := MemDPrime(tt.tgt, tt.lure); !same(got, tt.dp) {
+			t.Errorf("%s: MemDPrime = %g, want %g", tt.name, got, tt.dp)
+		}
+		if got := MemROCArea(tt.tgt, tt.lure); !same(got, tt.rocArea) {
+			t.Errorf("%s: MemROCArea = %g, want %g", tt.name, got, tt.rocArea)
+		}
+	}
+}
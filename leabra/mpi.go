@@ -0,0 +1,192 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/mpi"
+	"cogentcore.org/core/tensor/table"
+	"cogentcore.org/core/tensor/tensormpi"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/netview"
+)
+
+// MPI manages data-parallel training over MPI (message passing interface),
+// where each proc runs an identical copy of the same sim on a different
+// shard of the training trials, and the DWt weight changes are summed
+// across all procs (AllReduce) prior to updating the weights, so that
+// the weights remain synchronized.  All procs must start with the
+// same random seed.  MPI is only actually available when built with
+// the mpi or mpich build tag, and otherwise On remains false and all
+// of the methods are no-ops, so sims can use it unconditionally.
+// Typical usage in a sim:
+//   - MPI.Init(ss.Config.Run.MPI) at the start of main, and
+//     MPI.Finalize() at the end.
+//   - MPI.ConfigShardEnv on a ShardEnv training env, in ConfigEnv,
+//     which presents a different random shard of the trials to each
+//     proc in each epoch (or MPI.ShardIndexView on the training env
+//     table, for a fixed shard).
+//   - MPI.ConfigLoops after LooperSimCycleAndLearn, in ConfigLoops.
+//   - MPI.GatherTableRows on the trial log prior to computing epoch stats.
+type MPI struct {
+
+	// whether MPI is on: set by Init when MPI is available and requested
+	On bool `edit:"-"`
+
+	// communicator for all of the procs
+	Comm *mpi.Comm `display:"-"`
+
+	// buffer of all the DWt values for this proc
+	AllDWts []Float `display:"-"`
+
+	// buffer of the DWt values summed across all procs
+	SumDWts []Float `display:"-"`
+}
+
+// Init initializes MPI if on is true, creating the communicator
+// for all the procs.  Returns an error if MPI could not be initialized.
+func (pm *MPI) Init(on bool) error {
+	if !on {
+		return nil
+	}
+	mpi.Init()
+	comm, err := mpi.NewComm(nil) // use all procs
+	if err != nil {
+		return fmt.Errorf("leabra.MPI.Init: could not create communicator: %w", err)
+	}
+	pm.Comm = comm
+	pm.On = mpi.IsOn()
+	if pm.On {
+		mpi.Printf("MPI running on %d procs\n", mpi.WorldSize())
+	}
+	return nil
+}
+
+// Finalize shuts down MPI, if on.  Must be called at the end of the sim.
+func (pm *MPI) Finalize() {
+	if pm.On {
+		mpi.Finalize()
+	}
+}
+
+// Rank returns the rank of this proc, which is 0 if MPI is not on.
+func (pm *MPI) Rank() int {
+	if !pm.On {
+		return 0
+	}
+	return pm.Comm.Rank()
+}
+
+// Size returns the number of procs, which is 1 if MPI is not on.
+func (pm *MPI) Size() int {
+	if !pm.On {
+		return 1
+	}
+	return pm.Comm.Size()
+}
+
+// SyncDWts sums the DWt weight changes across all procs and sets the
+// summed values back into the network, so that WtFromDWt results in the
+// same weights on all procs.
+func (pm *MPI) SyncDWts(net *Network) error {
+	if !pm.On {
+		return nil
+	}
+	nwts := len(pm.AllDWts)
+	if net.CollectDWts(&pm.AllDWts, nwts) {
+		pm.SumDWts = make([]Float, len(pm.AllDWts))
+	}
+	var err error
+	switch sum := any(pm.SumDWts).(type) { // Float depends on leabra64 build tag
+	case []float32:
+		err = pm.Comm.AllReduceF32(mpi.OpSum, sum, any(pm.AllDWts).([]float32))
+	case []float64:
+		err = pm.Comm.AllReduceF64(mpi.OpSum, sum, any(pm.AllDWts).([]float64))
+	}
+	if err != nil {
+		return err
+	}
+	net.SetDWts(pm.SumDWts)
+	return nil
+}
+
+// WtFromDWt updates the weights from the DWt weight changes
+// summed across all procs.
+func (pm *MPI) WtFromDWt(net *Network) {
+	errors.Log(pm.SyncDWts(net))
+	net.WtFromDWt()
+}
+
+// ConfigLoops replaces the UpdateWeights function added to the Train
+// trial loop by LooperSimCycleAndLearn with one that sums the DWt weight
+// changes across procs prior to WtFromDWt, if MPI is on.  Can pass a
+// trial-level time scale to use instead of the default etime.Trial.
+func (pm *MPI) ConfigLoops(ls *looper.Stacks, net *Network, viewupdt *netview.ViewUpdate, trial ...etime.Times) {
+	if !pm.On {
+		return
+	}
+	trl := etime.Trial
+	if len(trial) > 0 {
+		trl = trial[0]
+	}
+	ttrl := ls.Loop(etime.Train, trl)
+	if ttrl == nil {
+		return
+	}
+	errors.Log(ttrl.OnEnd.Replace("UpdateWeights", func() bool {
+		net.DWt()
+		if viewupdt.IsViewingSynapse() {
+			viewupdt.RecordSyns()
+		}
+		pm.WtFromDWt(net)
+		return true
+	}))
+}
+
+// ShardIndexView restricts the given IndexView of trials to the
+// contiguous subset (shard) of the trials to be processed by this proc,
+// if MPI is on, for use in the training environment.  The number of trials
+// should be an even multiple of the number of procs.
+func (pm *MPI) ShardIndexView(ix *table.IndexView) error {
+	if !pm.On {
+		return nil
+	}
+	st, ed, err := tensormpi.AllocN(ix.Len())
+	if err != nil {
+		return err
+	}
+	ix.Indexes = ix.Indexes[st:ed]
+	return nil
+}
+
+// ConfigShardEnv configures the given ShardEnv to present the shard of
+// the trials for this proc, with Shard = Rank and NShards = Size, which
+// are 0 and 1 if MPI is not on, and initializes it.  The env Seed must be
+// the same on all procs.  Returns an error if there are fewer trials than
+// procs.  The number of trials per epoch for each proc is NTrials.
+func (pm *MPI) ConfigShardEnv(ev *ShardEnv) error {
+	ev.Shard = pm.Rank()
+	ev.NShards = pm.Size()
+	if err := ev.Validate(); err != nil {
+		return err
+	}
+	ev.Init(0)
+	return nil
+}
+
+// GatherTableRows gathers the rows of the given log table from all the
+// procs into the log table on each proc, if MPI is on, so that the stats
+// computed from it (e.g., epoch stats from the trial log) reflect
+// all the trials across procs.
+func (pm *MPI) GatherTableRows(logs *elog.Logs, mode etime.Modes, time etime.Times) {
+	if !pm.On {
+		return
+	}
+	logs.MPIGatherTableRows(mode, time, pm.Comm)
+}
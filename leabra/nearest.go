@@ -0,0 +1,194 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor/table"
+)
+
+// NearestPatterns is a set of named stored patterns, e.g., the training
+// target patterns, or the layer activity states recorded during training,
+// which can be searched for the patterns nearest to a given pattern
+// in terms of cosine similarity, e.g., to identify which stored pattern
+// a network actually recalled on a test trial, and thus classify
+// intrusion errors.  The patterns are stored normalized to unit length,
+// so each comparison is a single dot product.
+type NearestPatterns struct {
+
+	// Names are the names of the stored patterns.
+	Names []string
+
+	// Pats are the stored patterns, normalized to unit length
+	// (all zeros if the pattern has no non-zero values).
+	Pats [][]float32
+
+	// index maps names to indexes in Names, Pats.
+	index map[string]int
+}
+
+// PatternMatch is one result of a NearestPatterns search.
+type PatternMatch struct {
+
+	// Name of the matching stored pattern.
+	Name string
+
+	// Index of the matching stored pattern.
+	Index int
+
+	// Cos is the cosine similarity between the pattern and the stored pattern.
+	Cos float32
+}
+
+// Reset removes all of the stored patterns.
+func (np *NearestPatterns) Reset() {
+	np.Names = nil
+	np.Pats = nil
+	np.index = nil
+}
+
+// Len returns the number of stored patterns.
+func (np *NearestPatterns) Len() int {
+	return len(np.Names)
+}
+
+// Set stores a copy of the given pattern under the given name,
+// replacing any existing pattern with the same name.
+func (np *NearestPatterns) Set(name string, pat []float32) {
+	if np.index == nil {
+		np.index = make(map[string]int)
+	}
+	pi, has := np.index[name]
+	if !has {
+		pi = len(np.Names)
+		np.index[name] = pi
+		np.Names = append(np.Names, name)
+		np.Pats = append(np.Pats, nil)
+	}
+	np.Pats[pi] = append(np.Pats[pi][:0], pat...)
+	nearestNormalize(np.Pats[pi])
+}
+
+// SetFromTable sets the patterns from the given pattern column of
+// the given table, using the names in the given name column,
+// e.g., the Name and ECout columns of the training patterns.
+func (np *NearestPatterns) SetFromTable(dt *table.Table, nameCol, patCol string) error {
+	if _, err := dt.ColumnByName(nameCol); err != nil {
+		return fmt.Errorf("leabra.NearestPatterns.SetFromTable: %w", err)
+	}
+	if _, err := dt.ColumnByName(patCol); err != nil {
+		return fmt.Errorf("leabra.NearestPatterns.SetFromTable: %w", err)
+	}
+	var pat []float32
+	for ri := range dt.Rows {
+		tsr := dt.Tensor(patCol, ri)
+		n := tsr.Len()
+		pat = pat[:0]
+		for i := range n {
+			pat = append(pat, float32(tsr.Float1D(i)))
+		}
+		np.Set(dt.StringValue(nameCol, ri), pat)
+	}
+	return nil
+}
+
+// Nearest returns the k stored patterns with the highest cosine
+// similarity to the given pattern, in order of decreasing similarity,
+// skipping any stored patterns that have a different length.
+func (np *NearestPatterns) Nearest(pat []float32, k int) []PatternMatch {
+	if k <= 0 {
+		return nil
+	}
+	mag := float32(0)
+	for _, v := range pat {
+		mag += v * v
+	}
+	if mag > 0 {
+		mag = 1 / math32.Sqrt(mag)
+	}
+	var ms []PatternMatch
+	for pi, sp := range np.Pats {
+		if len(sp) != len(pat) {
+			continue
+		}
+		dot := float32(0)
+		for i, v := range pat {
+			dot += v * sp[i]
+		}
+		cos := dot * mag
+		if len(ms) == k && cos <= ms[k-1].Cos {
+			continue
+		}
+		if len(ms) < k {
+			ms = append(ms, PatternMatch{})
+		}
+		j := len(ms) - 1
+		for ; j > 0 && ms[j-1].Cos < cos; j-- {
+			ms[j] = ms[j-1]
+		}
+		ms[j] = PatternMatch{Name: np.Names[pi], Index: pi, Cos: cos}
+	}
+	return ms
+}
+
+// Index returns the index of the stored pattern with given name, or -1 if none.
+func (np *NearestPatterns) Index(name string) int {
+	if pi, has := np.index[name]; has {
+		return pi
+	}
+	return -1
+}
+
+// CosAll sets cos to the cosine similarity of the given pattern to
+// each of the stored patterns, in order (0 for patterns of a
+// different length).
+func (np *NearestPatterns) CosAll(pat []float32, cos *[]float32) {
+	*cos = append((*cos)[:0], make([]float32, len(np.Pats))...)
+	mag := float32(0)
+	for _, v := range pat {
+		mag += v * v
+	}
+	if mag == 0 {
+		return
+	}
+	mag = 1 / math32.Sqrt(mag)
+	for pi, sp := range np.Pats {
+		if len(sp) != len(pat) {
+			continue
+		}
+		dot := float32(0)
+		for i, v := range pat {
+			dot += v * sp[i]
+		}
+		(*cos)[pi] = dot * mag
+	}
+}
+
+// Nearest1 returns the stored pattern with the highest cosine similarity
+// to the given pattern, with Index = -1 if there are no stored patterns.
+func (np *NearestPatterns) Nearest1(pat []float32) PatternMatch {
+	ms := np.Nearest(pat, 1)
+	if len(ms) == 0 {
+		return PatternMatch{Index: -1}
+	}
+	return ms[0]
+}
+
+// nearestNormalize normalizes the pattern to unit length, if non-zero.
+func nearestNormalize(pat []float32) {
+	mag := float32(0)
+	for _, v := range pat {
+		mag += v * v
+	}
+	if mag == 0 {
+		return
+	}
+	mag = 1 / math32.Sqrt(mag)
+	for i := range pat {
+		pat[i] *= mag
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestNearestPatterns(t *testing.T) {
+	np := &NearestPatterns{}
+	if m := np.Nearest1([]float32{1, 0}); m.Index != -1 {
+		t.Errorf("Nearest1 with no patterns: %+v", m)
+	}
+	np.Set("a", []float32{1, 0, 0, 0})
+	np.Set("b", []float32{1, 1, 0, 0})
+	np.Set("c", []float32{0, 0, 2, 2})
+	np.Set("d", []float32{2, 2, 0, 0}) // ties with b
+	np.Set("short", []float32{1, 1})
+	if np.Len() != 5 || np.Index("c") != 2 || np.Index("nope") != -1 {
+		t.Fatalf("Len %d, Index c %d", np.Len(), np.Index("c"))
+	}
+	r2 := float32(math32.Sqrt2 / 2)
+	CmprFloats(np.Pats[2], []float32{0, 0, r2, r2}, "normalized c", t)
+
+	tests := []struct {
+		name  string
+		pat   []float32
+		k     int
+		names []string
+		cos   []float32
+	}{
+		{"exact", []float32{0, 0, 1, 1}, 1, []string{"c"}, []float32{1}},
+		{"ties in order", []float32{3, 3, 0, 0}, 3, []string{"b", "d", "a"}, []float32{1, 1, r2}},
+		{"ties after best", []float32{1, 0, 0, 0}, 3, []string{"a", "b", "d"}, []float32{1, r2, r2}},
+		{"k > n", []float32{0, 0, 0, 1}, 10, []string{"c", "a", "b", "d"}, []float32{r2, 0, 0, 0}},
+		{"zero pattern", []float32{0, 0, 0, 0}, 2, []string{"a", "b"}, []float32{0, 0}},
+		{"k = 0", []float32{1, 0, 0, 0}, 0, nil, nil},
+	}
+	for _, tt := range tests {
+		ms := np.Nearest(tt.pat, tt.k)
+		if len(ms) != len(tt.names) {
+			t.Errorf("%s: got %d matches %+v, want %v", tt.name, len(ms), ms, tt.names)
+			continue
+		}
+		for i, m := range ms {
+			if m.Name != tt.names[i] || m.Index != np.Index(m.Name) || math32.Abs(m.Cos-tt.cos[i]) > difTol {
+				t.Errorf("%s: match %d = %+v, want %s %g", tt.name, i, m, tt.names[i], tt.cos[i])
+			}
+		}
+	}
+	if m := np.Nearest1([]float32{0, 1}); m.Name != "short" || math32.Abs(m.Cos-r2) > difTol {
+		t.Errorf("Nearest1 short: %+v", m)
+	}
+
+	var cos []float32
+	np.CosAll([]float32{1, 1, 1, 1}, &cos)
+	CmprFloats(cos, []float32{0.5, r2, r2, r2, 0}, "CosAll", t)
+	if len(cos) != 5 {
+		t.Errorf("CosAll len: %d", len(cos))
+	}
+	np.CosAll([]float32{0, 0, 0, 0}, &cos)
+	CmprFloats(cos, []float32{0, 0, 0, 0, 0}, "CosAll zero", t)
+
+	np.Set("a", []float32{0, 0, 0, 5}) // replace
+	if np.Len() != 5 {
+		t.Errorf("Set replace: Len %d", np.Len())
+	}
+	if m := np.Nearest1([]float32{0, 0, 0, 1}); m.Name != "a" || m.Index != 0 {
+		t.Errorf("Nearest1 after replace: %+v", m)
+	}
+
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Pat", []int{2})
+	dt.SetNumRows(2)
+	dt.SetString("Name", 0, "x")
+	dt.SetString("Name", 1, "y")
+	dt.Columns[1].SetFloat1D(1, 1)
+	dt.Columns[1].SetFloat1D(2, 1)
+	np.Reset()
+	if err := np.SetFromTable(dt, "Name", "Pat"); err != nil {
+		t.Fatal(err)
+	}
+	if m := np.Nearest1([]float32{1, 0}); np.Len() != 2 || m.Name != "y" {
+		t.Errorf("SetFromTable: Len %d, nearest %+v", np.Len(), m)
+	}
+	if err := np.SetFromTable(dt, "Name", "Nope"); err == nil {
+		t.Error("SetFromTable: expected error for missing column")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/netview"
+)
+
+// NetRecorder records the NetView data (the values of all the unit
+// variables, per update) during headless (nogui) runs, e.g., on a cluster,
+// saving it to files for playback in the NetView later (see OpenNetRecord).
+// Unlike the fixed-size ring buffer of the NetView, which only has the
+// most recent records, the recording is saved in segment files of MaxRecs
+// records each, so an entire session can be recorded with bounded memory.
+// Synaptic values are not recorded.
+type NetRecorder struct {
+
+	// File is the base file name for the segment files, which are saved
+	// as File_<seg>.netdata.json.gz, with seg starting at 000.
+	File string
+
+	// MaxRecs is the maximum number of records per segment file.
+	MaxRecs int `default:"500"`
+
+	// Data is the NetView data for the current segment.
+	Data netview.NetData `display:"-"`
+
+	// Files are the names of the segment files saved so far.
+	Files []string `edit:"-"`
+
+	// net is the network being recorded.
+	net *Network
+}
+
+func (nr *NetRecorder) Defaults() {
+	nr.MaxRecs = 500
+}
+
+// Init initializes the recorder for the given network, saving segment
+// files with the given base file name.
+func (nr *NetRecorder) Init(net *Network, file string) {
+	if nr.MaxRecs <= 0 {
+		nr.Defaults()
+	}
+	nr.net = net
+	nr.File = file
+	nr.Files = nil
+	nr.Data.Init(net, nr.MaxRecs, true, net.MaxParallelData())
+}
+
+// Record records the current state of the network, with the given counters
+// string, which is displayed at the bottom of the NetView.  When the segment
+// is full, it is saved to the next segment file, and a new segment started.
+func (nr *NetRecorder) Record(counters string) error {
+	if nr.net == nil {
+		return errors.New("leabra.NetRecorder: Init has not been called")
+	}
+	nr.Data.Record(counters, -1, nr.MaxRecs)
+	if nr.Data.Ring.Len < nr.MaxRecs {
+		return nil
+	}
+	return nr.Save()
+}
+
+// Save saves the records in the current segment, if any, to the next
+// segment file, and starts a new segment.  It must be called at the end
+// of the run to save the last, partial, segment.
+func (nr *NetRecorder) Save() error {
+	if nr.Data.Ring.Len == 0 {
+		return nil
+	}
+	fnm := fmt.Sprintf("%s_%03d.netdata.json.gz", nr.File, len(nr.Files))
+	err := nr.Data.SaveJSON(core.Filename(fnm))
+	nr.Files = append(nr.Files, fnm)
+	nr.Data.Ring.Reset()
+	nr.Data.RastCtr = 0
+	clear(nr.Data.RasterMap)
+	return err
+}
+
+// LooperRecordNet adds a function at the end of the loops at the given time
+// level (e.g., etime.Trial) in all modes, to record the network state with the
+// given NetRecorder, with the counters string returned by the given function.
+func LooperRecordNet(ls *looper.Stacks, nr *NetRecorder, time etime.Times, ctrs func() string) {
+	for m := range ls.Stacks {
+		lp := ls.Loop(m, time)
+		if lp == nil {
+			continue
+		}
+		lp.OnEnd.Add("NetRecord", func() {
+			errors.Log(nr.Record(ctrs()))
+		})
+	}
+}
+
+// OpenNetRecord opens the given NetRecorder segment files (or any NetView
+// data files saved for the same network), in order, into the given NetView,
+// for offline playback, as one sequence of records that can be stepped
+// through with the NetView record controls.  The files can be given as
+// glob patterns, which are expanded and sorted.  The NetView must already
+// be configured with the same network (SetNet), and the current NetView
+// data is replaced.
+func OpenNetRecord(nv *netview.NetView, files ...string) error {
+	if nv.Net == nil {
+		return errors.New("leabra.OpenNetRecord: NetView has no network")
+	}
+	var fnms []string
+	for _, f := range files {
+		ms, err := filepath.Glob(f)
+		if err != nil {
+			return err
+		}
+		if len(ms) == 0 {
+			return fmt.Errorf("leabra.OpenNetRecord: no files match: %s", f)
+		}
+		slices.Sort(ms)
+		fnms = append(fnms, ms...)
+	}
+	var segs []*netview.NetData
+	for _, fnm := range fnms {
+		sd := &netview.NetData{Net: nv.Net}
+		if err := sd.OpenJSON(core.Filename(fnm)); err != nil {
+			return fmt.Errorf("leabra.OpenNetRecord: %s: %w", fnm, err)
+		}
+		segs = append(segs, sd)
+	}
+	nv.DataMu.Lock()
+	err := mergeNetData(&nv.Data, nv.Net, segs)
+	nv.DataMu.Unlock()
+	if err != nil {
+		return err
+	}
+	nv.RecNo = 0
+	nv.UpdateView()
+	return nil
+}
+
+// mergeNetData sets the given NetView data to the records of all the
+// given segments, in order, without synaptic data.
+func mergeNetData(nd *netview.NetData, net emer.Network, segs []*netview.NetData) error {
+	total := 0
+	for _, sd := range segs {
+		total += sd.Ring.Len
+	}
+	if total == 0 {
+		return errors.New("leabra.OpenNetRecord: no records")
+	}
+	nd.Init(net, total, true, net.MaxParallelData())
+	vlen := len(nd.UnVars)
+	maxData := nd.MaxData
+	di := 0
+	for _, sd := range segs {
+		if !slices.Equal(sd.UnVars, nd.UnVars) || sd.MaxData != maxData {
+			return errors.New("leabra.OpenNetRecord: recorded variables do not match the network")
+		}
+		for ri := range sd.Ring.Len {
+			si := sd.RecIndex(ri)
+			for lnm, ld := range nd.LayData {
+				sld, ok := sd.LayData[lnm]
+				if !ok || sld.NUnits != ld.NUnits {
+					return fmt.Errorf("leabra.OpenNetRecord: layer %s does not match the network", lnm)
+				}
+				nvu := vlen * maxData * ld.NUnits
+				copy(ld.Data[di*nvu:(di+1)*nvu], sld.Data[si*nvu:(si+1)*nvu])
+			}
+			copy(nd.UnMinPer[di*vlen:(di+1)*vlen], sd.UnMinPer[si*vlen:(si+1)*vlen])
+			copy(nd.UnMaxPer[di*vlen:(di+1)*vlen], sd.UnMaxPer[si*vlen:(si+1)*vlen])
+			nd.Counters[di] = sd.Counters[si]
+			nd.RasterCtrs[di] = di
+			nd.RasterMap[di] = di
+			di++
+		}
+	}
+	nd.Ring.Len = total
+	nd.UpdateUnVarRange()
+	return nil
+}
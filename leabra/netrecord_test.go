@@ -0,0 +1,62 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/netview"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestNetRecorder(t *testing.T) {
+	net := NewNetwork("NetRec")
+	in := net.AddLayer2D("Input", 1, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 1, 2, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	nr := &NetRecorder{MaxRecs: 3}
+	nr.Init(net, filepath.Join(t.TempDir(), "netrec"))
+	for i := range 5 {
+		hid.Neurons[1].Act = Float(i)
+		if err := nr.Record(fmt.Sprintf("Trial: %d", i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := nr.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if len(nr.Files) != 2 {
+		t.Fatalf("NetRecorder: %d files, not 2", len(nr.Files))
+	}
+	var segs []*netview.NetData
+	for _, fnm := range nr.Files {
+		sd := &netview.NetData{Net: net}
+		if err := sd.OpenJSON(core.Filename(fnm)); err != nil {
+			t.Fatal(err)
+		}
+		segs = append(segs, sd)
+	}
+	nd := &netview.NetData{}
+	if err := mergeNetData(nd, net, segs); err != nil {
+		t.Fatal(err)
+	}
+	if nd.Ring.Len != 5 || nd.CounterRec(3) != "Trial: 3" {
+		t.Errorf("mergeNetData: %d records, counter 3: %s", nd.Ring.Len, nd.CounterRec(3))
+	}
+	for i := range 5 {
+		v, _ := nd.UnitValue("Hidden", "Act", 1, i, 0)
+		if v != float32(i) {
+			t.Errorf("mergeNetData: record %d Act: %g", i, v)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"cogentcore.org/core/base/datasize"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 ///////////////////////////////////////////////////////////////////////////
@@ -48,6 +49,7 @@ func (nt *Network) AlphaCycInit(updtActAvg bool) {
 // * Average and Max Act stats
 // This basic version doesn't use the time info, but more specialized types do, and we
 // want to keep a consistent API for end-user code.
+// The layers are computed in parallel using NThreads goroutines (see SetNThreads).
 func (nt *Network) Cycle(ctx *Context) {
 	nt.SendGDelta(ctx) // also does integ
 	nt.AvgMaxGe(ctx)
@@ -64,58 +66,28 @@ func (nt *Network) Cycle(ctx *Context) {
 // SendGeDelta sends change in activation since last sent, if above thresholds
 // and integrates sent deltas into GeRaw and time-integrated Ge values
 func (nt *Network) SendGDelta(ctx *Context) {
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.SendGDelta(ctx)
-	}
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.GFromInc(ctx)
-	}
+	nt.sendGDelta(ctx)
+	nt.neuronFun(func(ly *Layer) { ly.GFromInc(ctx) }, nil, func(ly *Layer, st, ed int) { ly.gFromIncRange(ctx, st, ed) })
 }
 
 // AvgMaxGe computes the average and max Ge stats, used in inhibition
 func (nt *Network) AvgMaxGe(ctx *Context) {
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.AvgMaxGe(ctx)
-	}
+	nt.poolFun(func(ly *Layer, pi int) { ly.Pools[pi].avgMaxGe(ly) })
 }
 
 // InhibiFromGeAct computes inhibition Gi from Ge and Act stats within relevant Pools
 func (nt *Network) InhibFromGeAct(ctx *Context) {
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.InhibFromGeAct(ctx)
-	}
+	nt.neuronFun(func(ly *Layer) { ly.InhibFromGeAct(ctx) }, func(ly *Layer) { ly.inhibFromGeActPools(ctx) }, func(ly *Layer, st, ed int) { ly.inhibFromPoolRange(st, ed) })
 }
 
 // ActFromG computes rate-code activation from Ge, Gi, Gl conductances
 func (nt *Network) ActFromG(ctx *Context) {
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.ActFromG(ctx)
-	}
+	nt.neuronFun(func(ly *Layer) { ly.ActFromG(ctx) }, nil, func(ly *Layer, st, ed int) { ly.actFromGRange(ctx, st, ed) })
 }
 
 // AvgMaxGe computes the average and max Ge stats, used in inhibition
 func (nt *Network) AvgMaxAct(ctx *Context) {
-	for _, ly := range nt.Layers {
-		if ly.Off {
-			continue
-		}
-		ly.AvgMaxAct(ctx)
-	}
+	nt.poolFun(func(ly *Layer, pi int) { ly.Pools[pi].avgMaxAct(ly) })
 }
 
 // CyclePost is called at end of Cycle, for misc updates after new Act
@@ -145,6 +117,39 @@ func (nt *Network) QuarterFinal(ctx *Context) {
 		}
 		ly.CtxtFromGe(ctx)
 	}
+	nt.SendAttn(ctx)
+	if nt.Finite.Quarter && nt.Finite.Level != FiniteOff {
+		nt.finiteCheck(FiniteNeurons, fmt.Sprintf("QuarterFinal: quarter %d cycle %d", ctx.Quarter, ctx.Cycle))
+	}
+}
+
+// AlphaCycle runs one full alpha cycle trial of 4 quarters, with the
+// plus phase in the last quarter, as done by LooperStdPhases and
+// LooperSimCycleAndLearn, for use outside of the looper, e.g., for
+// training an auxiliary network on patterns generated by another.
+// External inputs must already be applied.  If train, the weights are
+// updated from the weight changes at the end.
+func (nt *Network) AlphaCycle(ctx *Context, train bool) {
+	nt.AlphaCycInit(train)
+	ctx.AlphaCycStart()
+	ctx.PlusPhase = false
+	for qtr := range 4 {
+		if qtr == 3 {
+			ctx.PlusPhase = true
+		}
+		for range ctx.CycPerQtr {
+			nt.Cycle(ctx)
+			ctx.CycleInc()
+		}
+		nt.QuarterFinal(ctx)
+		if qtr < 3 {
+			ctx.QuarterInc()
+		}
+	}
+	if train {
+		nt.DWt()
+		nt.WtFromDWt()
+	}
 }
 
 // MinusPhase is called at the end of the minus phase (quarter 3), to record state.
@@ -182,8 +187,12 @@ func (nt *Network) DWt() {
 }
 
 // WtFromDWt updates the weights from delta-weight changes.
-// Also calls WtBalFromWt every WtBalInterval times
+// Also calls WtBalFromWt every WtBalInterval times.
+// If RecLearnProgress, accumulates LearnProgress stats first.
 func (nt *Network) WtFromDWt() {
+	if nt.RecLearnProgress {
+		nt.AccumLearnProgress()
+	}
 	for _, ly := range nt.Layers {
 		if ly.Off {
 			continue
@@ -202,11 +211,26 @@ func (nt *Network) WtFromDWt() {
 			ly.WtBalFromWt()
 		}
 	}
+	if nt.Finite.Level != FiniteOff {
+		nt.finiteCheck(nt.Finite.Level, "WtFromDWt")
+	}
+}
+
+// SynScale applies homeostatic synaptic scaling to all the pathways
+// with Learn.SynScale on, e.g., at the end of each training epoch,
+// for those with an Interval of 0.
+func (nt *Network) SynScale() {
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		ly.SynScale()
+	}
 }
 
 // LrateMult sets the new Lrate parameter for Paths to LrateInit * mult.
 // Useful for implementing learning rate schedules.
-func (nt *Network) LrateMult(mult float32) {
+func (nt *Network) LrateMult(mult Float) {
 	for _, ly := range nt.Layers {
 		// if ly.Off { // keep all sync'd
 		// 	continue
@@ -223,6 +247,8 @@ func (nt *Network) LrateMult(mult float32) {
 // state values (e.g., layer running average activations etc).
 func (nt *Network) InitWeights() {
 	nt.WtBalCtr = 0
+	nt.Finite.First = nil
+	nt.InitRand()
 	for _, ly := range nt.Layers {
 		if ly.Off {
 			continue
@@ -235,11 +261,13 @@ func (nt *Network) InitWeights() {
 		}
 		ly.InitWtSym()
 	}
+	nt.InitGateNoise()
+	nt.SetLrateEpoch(0)
 }
 
 // InitTopoScales initializes synapse-specific scale parameters from
 // path types that support them, with flags set to support it,
-// includes: paths.PoolTile paths.Circle.
+// includes: paths.PoolTile paths.Circle GaussTopo Ring.
 // call before InitWeights if using Topo wts.
 func (nt *Network) InitTopoScales() {
 	scales := &tensor.Float32{}
@@ -266,6 +294,16 @@ func (nt *Network) InitTopoScales() {
 					continue
 				}
 				pt.SetScalesFunc(ptn.GaussWts)
+			case *GaussTopo:
+				if !ptn.TopoWeights {
+					continue
+				}
+				pt.SetScalesFunc(ptn.GaussWts)
+			case *Ring:
+				if !ptn.TopoWeights {
+					continue
+				}
+				pt.SetScalesFunc(ptn.GaussWts)
 			}
 		}
 	}
@@ -275,7 +313,7 @@ func (nt *Network) InitTopoScales() {
 // e.g., 1 = decay completely, and 0 = decay not at all
 // This is called automatically in AlphaCycInit, but is avail
 // here for ad-hoc decay cases.
-func (nt *Network) DecayState(decay float32) {
+func (nt *Network) DecayState(decay Float) {
 	for _, ly := range nt.Layers {
 		if ly.Off {
 			continue
@@ -377,25 +415,22 @@ func (nt *Network) UnLesionNeurons() {
 // in which case the method returns true so that the actual length of
 // dwts can be passed next time around.
 // Used for MPI sharing of weight changes across processors.
-func (nt *Network) CollectDWts(dwts *[]float32, nwts int) bool {
+func (nt *Network) CollectDWts(dwts *[]Float, nwts int) bool {
 	idx := 0
 	made := false
 	if *dwts == nil {
 		// todo: if nil, compute right size right away
-		*dwts = make([]float32, 0, nwts)
+		*dwts = make([]Float, 0, nwts)
 		made = true
 	}
 	for _, ly := range nt.Layers {
 		for _, pt := range ly.SendPaths {
-			ns := len(pt.Syns)
+			ns := pt.Syns.Len()
 			nsz := idx + ns
 			if len(*dwts) < nsz {
-				*dwts = append(*dwts, make([]float32, nsz-len(*dwts))...)
-			}
-			for j := range pt.Syns {
-				sy := &(pt.Syns[j])
-				(*dwts)[idx+j] = sy.DWt
+				*dwts = append(*dwts, make([]Float, nsz-len(*dwts))...)
 			}
+			copy((*dwts)[idx:nsz], pt.Syns.DWt)
 			idx += ns
 		}
 	}
@@ -404,15 +439,12 @@ func (nt *Network) CollectDWts(dwts *[]float32, nwts int) bool {
 
 // SetDWts sets the DWt weight changes from given array of floats,
 // which must be correct size.
-func (nt *Network) SetDWts(dwts []float32) {
+func (nt *Network) SetDWts(dwts []Float) {
 	idx := 0
 	for _, ly := range nt.Layers {
 		for _, pt := range ly.SendPaths {
-			ns := len(pt.Syns)
-			for j := range pt.Syns {
-				sy := &(pt.Syns[j])
-				sy.DWt = dwts[idx+j]
-			}
+			ns := pt.Syns.Len()
+			copy(pt.Syns.DWt, dwts[idx:idx+ns])
 			idx += ns
 		}
 	}
@@ -436,9 +468,9 @@ func (nt *Network) SizeReport() string {
 		neurMem += nmem
 		fmt.Fprintf(&b, "%14s:\t Neurons: %d\t NeurMem: %v \t Sends To:\n", ly.Name, nn, (datasize.Size)(nmem).String())
 		for _, pt := range ly.SendPaths {
-			ns := len(pt.Syns)
+			ns := pt.Syns.Len()
 			syn += ns
-			pmem := ns*int(unsafe.Sizeof(Synapse{})) + len(pt.GInc)*4 + len(pt.WbRecv)*int(unsafe.Sizeof(WtBalRecvPath{}))
+			pmem := ns*int(unsafe.Sizeof(Synapse{})) + len(pt.GInc)*fmath.Size + len(pt.WbRecv)*int(unsafe.Sizeof(WtBalRecvPath{}))
 			synMem += pmem
 			fmt.Fprintf(&b, "\t%14s:\t Syns: %d\t SynnMem: %v\n", pt.Recv.Name, ns, (datasize.Size)(pmem).String())
 		}
@@ -28,7 +28,9 @@ type Network struct {
 	// list of layers
 	Layers []*Layer
 
-	// number of parallel threads (go routines) to use.
+	// number of parallel threads (go routines) to use for computing
+	// the Cycle-level updates, which are automatically distributed across
+	// them: defaults to 1 (serial). Use SetNThreads to set.
 	NThreads int `edit:"-"`
 
 	// how frequently to update the weight balance average
@@ -37,6 +39,39 @@ type Network struct {
 
 	// counter for how long it has been since last WtBal.
 	WtBalCtr int `edit:"-"`
+
+	// LesionLog records all the lesion, noise injection and reversal
+	// operations performed on the network, in order.  See LesionReport.
+	LesionLog []LesionRecord `display:"-"`
+
+	// LayerAliases maps alternative names to layer names,
+	// for use in LayerByName.  See AddLayerAlias.
+	LayerAliases map[string]string `display:"-"`
+
+	// Theta has the hippocampal theta-phase modulation parameters,
+	// used by HipThetaPhase and ConfigLoopsHip.
+	Theta ThetaPhaseParams `display:"inline"`
+
+	// Finite has the parameters for the automatic checking for
+	// non-finite (NaN or Inf) values in the state of the network.
+	Finite FiniteParams `display:"inline"`
+
+	// RecLearnProgress accumulates the per-pathway LearnProgress stats
+	// in WtFromDWt, which is turned on by LogAddLearnProgressItems.
+	RecLearnProgress bool
+
+	// LrateEpoch is the current training epoch for the learning rate
+	// schedules of the pathways (Learn.LrateSched), which is advanced by
+	// EpochInc or SetLrateEpoch, and reset to 0 by InitWeights.
+	LrateEpoch int `edit:"-"`
+
+	// BurstSeed is the seed for the random numbers of the probabilistic
+	// bursting of deep layers (see BurstRand), set from the network Rand
+	// in InitWeights.
+	BurstSeed int64 `edit:"-"`
+
+	// parTasks is a scratch list of tasks for parallel computation.
+	parTasks []parTask
 }
 
 func (nt *Network) NumLayers() int               { return len(nt.Layers) }
@@ -52,11 +87,17 @@ func NewNetwork(name string) *Network {
 	return net
 }
 
-// LayerByName returns a layer by looking it up by name in the layer map
-// (nil if not found).
+// LayerByName returns a layer by looking it up by name in the layer map,
+// or in the LayerAliases if not found by name (nil if not found).
 func (nt *Network) LayerByName(name string) *Layer {
-	ely, _ := nt.EmerLayerByName(name)
-	return ely.(*Layer)
+	ely, err := nt.EmerLayerByName(name)
+	if err != nil {
+		if nm, ok := nt.LayerAliases[name]; ok {
+			ely, _ = nt.EmerLayerByName(nm)
+		}
+	}
+	ly, _ := ely.(*Layer)
+	return ly
 }
 
 // LayersByType returns a list of layer names by given layer type(s).
@@ -81,17 +122,25 @@ func (nt *Network) KeyPathParams() string {
 	return nt.AllPathScales()
 }
 
+// ParamsSnapshotDir returns the directory used by SaveParamsSnapshot:
+// `params_good` if good = true, or else `params_2006_01_02`
+// (year, month, day) for the current date.
+func ParamsSnapshotDir(good bool) string {
+	if good {
+		return "params_good"
+	}
+	return "params_" + time.Now().Format("2006_01_02")
+}
+
 // SaveParamsSnapshot saves various views of current parameters
 // to either `params_good` if good = true (for current good reference params)
-// or `params_2006_01_02` (year, month, day) datestamp,
+// or `params_2006_01_02` (year, month, day) datestamp (see ParamsSnapshotDir),
 // providing a snapshot of the simulation params for easy diffs and later reference.
-// Also saves current Config and Params state.
+// Also saves current Config and Params state, the network architecture
+// (see ArchReport), and the seeds of any per-stripe gating noise
+// (see AllGateSeeds).
 func (nt *Network) SaveParamsSnapshot(pars *params.Sets, cfg any, good bool) error {
-	date := time.Now().Format("2006_01_02")
-	if good {
-		date = "good"
-	}
-	dir := "params_" + date
+	dir := ParamsSnapshotDir(good)
 	err := os.Mkdir(dir, 0775)
 	if err != nil {
 		log.Println(err) // notify but OK if it exists
@@ -102,9 +151,22 @@ func (nt *Network) SaveParamsSnapshot(pars *params.Sets, cfg any, good bool) err
 	nt.SaveNonDefaultParams(core.Filename(filepath.Join(dir, "params_nondef.txt")))
 	nt.SaveAllLayerInhibs(core.Filename(filepath.Join(dir, "params_layers.txt")))
 	nt.SaveAllPathScales(core.Filename(filepath.Join(dir, "params_paths.txt")))
+	nt.SaveAllGateSeeds(core.Filename(filepath.Join(dir, "params_gate_seeds.txt")))
+	nt.SaveArchReport(core.Filename(filepath.Join(dir, "params_network.txt")))
 	return nil
 }
 
+// SaveArchReport saves the listing of the network architecture
+// (see ArchReport) to given file.
+func (nt *Network) SaveArchReport(filename core.Filename) error {
+	str := nt.ArchReport()
+	err := os.WriteFile(string(filename), []byte(str), 0666)
+	if err != nil {
+		log.Println(err)
+	}
+	return err
+}
+
 // SaveAllLayerInhibs saves list of all layer Inhibition parameters to given file
 func (nt *Network) SaveAllLayerInhibs(filename core.Filename) error {
 	str := nt.AllLayerInhibs()
@@ -181,10 +243,41 @@ func (nt *Network) AllPathScales() string {
 	return str
 }
 
+// ArchReport returns a listing of the network architecture: the type,
+// shape and number of neurons of each layer, and the sending layer,
+// type and connectivity pattern of each of its Recv pathways,
+// followed by the total numbers of neurons and synapses.
+func (nt *Network) ArchReport() string {
+	str := ""
+	nly, neur, syn := 0, 0, 0
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		nly++
+		neur += len(ly.Neurons)
+		str += fmt.Sprintf("%15s\t%s\tShape: %v\tNeurons: %d\n", ly.Name, ly.Type, ly.Shape.Sizes, len(ly.Neurons))
+		for _, pt := range ly.RecvPaths {
+			if pt.Off {
+				continue
+			}
+			syn += pt.Syns.Len()
+			pat := ""
+			if pt.Pattern != nil {
+				pat = pt.Pattern.Name()
+			}
+			str += fmt.Sprintf("\t%15s\tFrom: %s\t%s\t%s\tSyns: %d\n", pt.Name, pt.Send.Name, pt.Type, pat, pt.Syns.Len())
+		}
+	}
+	str += fmt.Sprintf("\nTotal:\tLayers: %d\tNeurons: %d\tSyns: %d\n", nly, neur, syn)
+	return str
+}
+
 // Defaults sets all the default parameters for all layers and pathways
 func (nt *Network) Defaults() {
 	nt.WtBalInterval = 10
 	nt.WtBalCtr = 0
+	nt.Theta.Defaults()
 	for li, ly := range nt.Layers {
 		ly.Defaults()
 		ly.Index = li
@@ -342,6 +435,7 @@ func (nt *Network) LateralConnectLayer(lay *Layer, pat paths.Pattern) *Path {
 // and patterns of interconnectivity
 func (nt *Network) Build() error {
 	nt.MakeLayerMaps()
+	nt.SetPathRandSeeds()
 	var errs []error
 	for li, ly := range nt.Layers {
 		ly.Index = li
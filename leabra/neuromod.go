@@ -49,18 +49,20 @@ func (ln *LayerNames) Validate(net *Network) error {
 	return errors.Join(errs...)
 }
 
-// SendDA sends dopamine to SendTo list of layers.
-func (ly *Layer) SendDA(da float32) {
+// SendDA sends phasic dopamine to SendTo list of layers,
+// along with the tonic dopamine level from DaMod.Tonic.
+func (ly *Layer) SendDA(da Float) {
 	for _, lnm := range ly.SendTo {
 		tly := ly.Network.LayerByName(lnm)
 		if tly != nil {
 			tly.NeuroMod.DA = da
+			tly.NeuroMod.DATonic = ly.DaMod.Tonic
 		}
 	}
 }
 
 // SendACh sends ACh to SendTo list of layers.
-func (ly *Layer) SendACh(ach float32) {
+func (ly *Layer) SendACh(ach Float) {
 	for _, lnm := range ly.SendTo {
 		tly := ly.Network.LayerByName(lnm)
 		if tly != nil {
@@ -82,36 +84,136 @@ func (ly *Layer) ClampDaDefaults() {
 
 // SendDaFromAct is called in SendMods to send activity as DA.
 func (ly *Layer) SendDaFromAct(ctx *Context) {
-	act := ly.Neurons[0].Act
+	act := ly.DaDip.DA(ly.Neurons[0].Act, ly.DaDipAdapt)
 	ly.NeuroMod.DA = act
-	ly.SendDA(act)
+	ly.NeuroMod.DATonic = ly.DaMod.Tonic
+	ly.SendDaDip(act)
 }
 
 // NeuroMod are the neuromodulatory neurotransmitters, at the layer level.
 type NeuroMod struct {
 
-	// DA is dopamine, which primarily modulates learning, and also excitability,
+	// DA is phasic dopamine, which primarily modulates learning, and also excitability,
 	// and reflects the reward prediction error (RPE).
-	DA float32
+	DA Float
+
+	// DATonic is the tonic dopamine level, relative to the normal level of 0,
+	// which changes slowly and reflects e.g., dopamine depletion (negative)
+	// or dopaminergic medication (positive).  It is sent along with the
+	// phasic DA from the DaMod.Tonic of the sending dopamine layer.
+	DATonic Float
 
 	// ACh is acetylcholine, which modulates excitability and also learning,
 	// and reflects salience, i.e., reward (without discount by prediction) and
 	// learned CS onset.
-	ACh float32
+	ACh Float
 
 	// SE is serotonin, which is a longer timescale neuromodulator with many
 	// different effects. Currently not implemented, but here for future expansion.
-	SE float32
+	SE Float
 }
 
 func (nm *NeuroMod) Init() {
 	nm.DA = 0
+	nm.DATonic = 0
 	nm.ACh = 0
 	nm.SE = 0
 }
 
+// DaValue returns the given component of the dopamine signal.
+func (nm *NeuroMod) DaValue(comp DaComps) Float {
+	switch comp {
+	case DaPhasic:
+		return nm.DA
+	case DaTonic:
+		return nm.DATonic
+	case DaTotal:
+		return nm.DA + nm.DATonic
+	}
+	return 0
+}
+
+// DaModParams are the parameters for the tonic and phasic components
+// of dopamine, including the tonic level sent by a dopamine layer, and
+// which component modulates the learning and the excitability (gain)
+// of a receiving layer.  The defaults reproduce the standard behavior,
+// where only phasic DA drives learning and there is no gain effect.
+// Tonic DA manipulations (e.g., medication on vs. off states) can be
+// simulated by setting the Tonic level on the dopamine layer, and
+// Gain and GainFactor on the receiving layers.
+type DaModParams struct {
+
+	// Tonic is the tonic dopamine level sent by this layer, if it is a
+	// dopamine layer, along with the phasic DA computed by the layer.
+	// 0 is the normal level, negative is depleted (e.g., Parkinson's off
+	// medication), and positive is elevated (e.g., on medication).
+	Tonic Float
+
+	// Learn is the component of the received dopamine that drives learning,
+	// e.g., in the Matrix, RW and TD pathways.
+	Learn DaComps
+
+	// Gain is the component of the received dopamine that modulates the
+	// excitability of the layer, by multiplying the excitatory net input.
+	Gain DaComps
+
+	// GainFactor is the strength of the modulation of excitability by
+	// the Gain component of dopamine, with the net input multiplied by
+	// 1 + GainFactor * DA for D1R, and 1 - GainFactor * DA for D2R
+	// layers (see PBWM.DaR), clipped to be non-negative.
+	GainFactor Float `default:"0.5" min:"0"`
+}
+
+func (dm *DaModParams) Defaults() {
+	dm.Learn = DaPhasic
+	dm.Gain = DaNoComp
+	dm.GainFactor = 0.5
+}
+
+func (dm *DaModParams) Update() {
+}
+
+// DaLearn returns the dopamine value used for learning,
+// according to the DaMod.Learn component.
+func (ly *Layer) DaLearn() Float {
+	return ly.NeuroMod.DaValue(ly.DaMod.Learn)
+}
+
+// DaGain returns the multiplier on the excitatory net input from the
+// dopamine component given by DaMod.Gain, which is 1 if there is no
+// dopamine gain modulation.
+func (ly *Layer) DaGain() Float {
+	if ly.DaMod.Gain == DaNoComp || ly.DaMod.GainFactor == 0 {
+		return 1
+	}
+	da := ly.NeuroMod.DaValue(ly.DaMod.Gain)
+	if ly.PBWM.DaR == D2R {
+		da = -da
+	}
+	return max(1+ly.DaMod.GainFactor*da, 0)
+}
+
 //////// Enums
 
+// DaComps are the components of the dopamine signal, used to select
+// which modulates learning vs. excitability in DaModParams.
+type DaComps int32 //enums:enum
+
+const (
+	// DaPhasic is the phasic dopamine, NeuroMod.DA, reflecting
+	// the reward prediction error.
+	DaPhasic DaComps = iota
+
+	// DaTonic is the tonic dopamine level, NeuroMod.DATonic.
+	DaTonic
+
+	// DaTotal is the sum of the phasic and tonic dopamine.
+	DaTotal
+
+	// DaNoComp is no dopamine, i.e., no modulation.
+	DaNoComp
+)
+
 // DaReceptors for D1R and D2R dopamine receptors
 type DaReceptors int32 //enums:enum
 
@@ -0,0 +1,38 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestDaTonic(t *testing.T) {
+	net := NewNetwork("DaTonic")
+	_, _, da := net.AddRWLayers("", 2)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	da.SendTo.Add(hid.Name)
+	da.DaMod.Tonic = -0.4
+	net.InitWeights()
+	ctx := NewContext()
+	net.AlphaCycInit(true)
+	ctx.AlphaCycStart()
+	net.Cycle(ctx)
+	if hid.NeuroMod.DATonic != -0.4 {
+		t.Errorf("DaTonic: tonic DA not sent: %g", hid.NeuroMod.DATonic)
+	}
+	if hid.DaGain() != 1 || hid.DaLearn() != hid.NeuroMod.DA {
+		t.Errorf("DaTonic: default gain: %g or learn: %g not standard", hid.DaGain(), hid.DaLearn())
+	}
+	hid.NeuroMod.DA = 0.2
+	hid.DaMod.Gain = DaTonic
+	hid.DaMod.Learn = DaTotal
+	CmprFloats([]float32{float32(hid.DaGain()), float32(hid.DaLearn())}, []float32{0.8, -0.2}, "DaTonic gain, learn", t)
+	hid.PBWM.DaR = D2R
+	CmprFloats([]float32{float32(hid.DaGain())}, []float32{1.2}, "DaTonic D2R gain", t)
+}
@@ -10,19 +10,24 @@ import (
 	"unsafe"
 
 	"cogentcore.org/core/enums"
-	"cogentcore.org/core/math32"
 	"cogentcore.org/core/types"
 	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/leabra/v2/fmath"
 )
 
+// Float is the floating point type of all the state and parameters,
+// which is float32 by default, and float64 when built with the
+// leabra64 build tag, for verification (see the fmath package).
+type Float = fmath.Float
+
 // NeuronVarStart is the byte offset of fields in the Neuron structure
-// where the float32 named variables start.
-// Note: all non-float32 infrastructure variables must be at the start!
-const NeuronVarStart = 12
+// where the Float named variables start.
+// Note: all non-Float infrastructure variables must be at the start!
+const NeuronVarStart = int(unsafe.Offsetof(Neuron{}.Act))
 
 // leabra.Neuron holds all of the neuron (unit) level variables -- this is the most basic version with
 // rate-code only and no optional features at all.
-// All variables accessible via Unit interface must be float32 and start at the top, in contiguous order
+// All variables accessible via Unit interface must be Float and start at the top, in contiguous order
 type Neuron struct {
 
 	// bit flags for binary state variables
@@ -35,152 +40,168 @@ type Neuron struct {
 	// Act
 
 	// rate-coded activation value reflecting final output of neuron communicated to other neurons, typically in range 0-1.  This value includes adaptation and synaptic depression / facilitation effects which produce temporal contrast (see ActLrn for version without this).  For rate-code activation, this is noisy-x-over-x-plus-one (NXX1) function; for discrete spiking it is computed from the inverse of the inter-spike interval (ISI), and Spike reflects the discrete spikes.
-	Act float32
+	Act Float
 
 	// total excitatory synaptic conductance -- the net excitatory input to the neuron -- does *not* include Gbar.E
-	Ge float32
+	Ge Float
 
 	// total inhibitory synaptic conductance -- the net inhibitory input to the neuron -- does *not* include Gbar.I
-	Gi float32
+	Gi Float
 
 	// total potassium conductance, typically reflecting sodium-gated potassium currents involved in adaptation effects -- does *not* include Gbar.K
-	Gk float32
+	Gk Float
 
 	// net current produced by all channels -- drives update of Vm
-	Inet float32
+	Inet Float
 
 	// membrane potential -- integrates Inet current over time
-	Vm float32
+	Vm Float
 
 	// noise value added to unit (ActNoiseParams determines distribution, and when / where it is added)
-	Noise float32
+	Noise Float
 
 	// whether neuron has spiked or not (0 or 1), for discrete spiking neurons.
-	Spike float32
+	Spike Float
 
 	// target value: drives learning to produce this activation value
-	Targ float32
+	Targ Float
 
 	// external input: drives activation of unit from outside influences (e.g., sensory input)
-	Ext float32
+	Ext Float
 
 	////////////////////////////
 	// Learn
 
 	// super-short time-scale average of ActLrn activation -- provides the lowest-level time integration -- for spiking this integrates over spikes before subsequent averaging, and it is also useful for rate-code to provide a longer time integral overall
-	AvgSS float32
+	AvgSS Float
 
 	// short time-scale average of ActLrn activation -- tracks the most recent activation states (integrates over AvgSS values), and represents the plus phase for learning in XCAL algorithms
-	AvgS float32
+	AvgS Float
 
 	// medium time-scale average of ActLrn activation -- integrates over AvgS values, and represents the minus phase for learning in XCAL algorithms
-	AvgM float32
+	AvgM Float
 
 	// long time-scale average of medium-time scale (trial level) activation, used for the BCM-style floating threshold in XCAL
-	AvgL float32
+	AvgL Float
 
 	// how much to learn based on the long-term floating threshold (AvgL) for BCM-style Hebbian learning -- is modulated by level of AvgL itself (stronger Hebbian as average activation goes higher) and optionally the average amount of error experienced in the layer (to retain a common proportionality with the level of error-driven learning across layers)
-	AvgLLrn float32
+	AvgLLrn Float
 
 	// short time-scale activation average that is actually used for learning -- typically includes a small contribution from AvgM in addition to mostly AvgS, as determined by LrnActAvgParams.LrnM -- important to ensure that when unit turns off in plus phase (short time scale), enough medium-phase trace remains so that learning signal doesn't just go all the way to 0, at which point no learning would take place
-	AvgSLrn float32
+	AvgSLrn Float
 
 	// learning activation value, reflecting *dendritic* activity that is not affected by synaptic depression or adapdation channels which are located near the axon hillock.  This is the what drives the Avg* values that drive learning. Computationally, neurons strongly discount the signals sent to other neurons to provide temporal contrast, but need to learn based on a more stable reflection of their overall inputs in the dendrites.
-	ActLrn float32
+	ActLrn Float
 
 	////////////////////////////
 	// Phase
 
 	// the activation state at end of third quarter, which is the traditional posterior-cortical minus phase activation
-	ActM float32
+	ActM Float
 
 	// the activation state at end of fourth quarter, which is the traditional posterior-cortical plus_phase activation
-	ActP float32
+	ActP Float
 
 	// ActP - ActM -- difference between plus and minus phase acts -- reflects the individual error gradient for this neuron in standard error-driven learning terms
-	ActDif float32
+	ActDif Float
 
 	// delta activation: change in Act from one cycle to next -- can be useful to track where changes are taking place
-	ActDel float32
+	ActDel Float
 
 	// the activation state at start of current alpha cycle (same as the state at end of previous cycle)
-	ActQ0 float32
+	ActQ0 Float
 
 	// the activation state at end of first quarter of current alpha cycle
-	ActQ1 float32
+	ActQ1 Float
 
 	// the activation state at end of second quarter of current alpha cycle
-	ActQ2 float32
+	ActQ2 Float
 
 	// average activation (of final plus phase activation state) over long time intervals (time constant = DtPars.AvgTau -- typically 200) -- useful for finding hog units and seeing overall distribution of activation
-	ActAvg float32
+	ActAvg Float
 
 	// 5IB bursting activation value, computed by thresholding regular activation
-	Burst float32
+	Burst Float
 
 	// previous bursting activation -- used for context-based learning
-	BurstPrv float32
+	BurstPrv Float
 
 	////////////////////////////
 	// Gmisc
 
 	// aggregated synaptic inhibition (from Inhib pathways) -- time integral of GiRaw -- this is added with computed FFFB inhibition to get the full inhibition in Gi
-	GiSyn float32
+	GiSyn Float
 
 	// total amount of self-inhibition -- time-integrated to avoid oscillations
-	GiSelf float32
+	GiSelf Float
 
 	// last activation value sent (only send when diff is over threshold)
-	ActSent float32
+	ActSent Float
 
 	// raw excitatory conductance (net input) received from sending units (send delta's are added to this value)
-	GeRaw float32
+	GeRaw Float
 
 	// raw inhibitory conductance (net input) received from sending units (send delta's are added to this value)
-	GiRaw float32
+	GiRaw Float
 
 	// conductance of sodium-gated potassium channel (KNa) fast dynamics (M-type) -- produces accommodation / adaptation of firing
-	GknaFast float32
+	GknaFast Float
 
 	// conductance of sodium-gated potassium channel (KNa) medium dynamics (Slick) -- produces accommodation / adaptation of firing
-	GknaMed float32
+	GknaMed Float
 
 	// conductance of sodium-gated potassium channel (KNa) slow dynamics (Slack) -- produces accommodation / adaptation of firing
-	GknaSlow float32
+	GknaSlow Float
+
+	// proportion of open slow after-hyperpolarization (sAHP) potassium channels, which builds up with activity and decays slowly across trials -- produces firing-rate adaptation with repeated presentations (see Act.AHP)
+	Gahp Float
+
+	// learned intrinsic excitability of the neuron, added to the raw excitatory conductance -- adapted as a function of activity by Learn.Excit, and saved with the weights
+	Excit Float
 
 	// current inter-spike-interval -- counts up since last spike.  Starts at -1 when initialized.
-	ISI float32
+	ISI Float
 
 	// average inter-spike-interval -- average time interval between spikes.  Starts at -1 when initialized, and goes to -2 after first spike, and is only valid after the second spike post-initialization.
-	ISIAvg float32
+	ISIAvg Float
 
 	// CtxtGe is context (temporally delayed) excitatory conducances.
-	CtxtGe float32
+	CtxtGe Float
+
+	// CtxtLag is the learned proportion of the prior context retained at each context update in CT layers -- see Ctxt params, saved with the weights
+	CtxtLag Float
+
+	// CtxtDif is the difference between the prior context and the new context input at the last context update, which is the derivative of CtxtGe with respect to the lag
+	CtxtDif Float
+
+	// CtxtDLag is the error of the neuron with respect to its context lag, for learning CtxtLag in DWt
+	CtxtDLag Float
 
 	////////// Special algorithm vars: RL, PBWM
 
 	// gating activation -- the activity value when gating occurred in this pool.
-	ActG float32
+	ActG Float
 
 	// per-neuron effective learning dopamine value -- gain modulated and sign reversed for D2R
-	DALrn float32
+	DALrn Float
 
 	// shunting input received from Patch neurons (in reality flows through SNc DA pathways)
-	Shunt float32
+	Shunt Float
 
 	// maintenance value for Deep layers = sending act at time of gating
-	Maint float32
+	Maint Float
 
 	// maintenance excitatory conductance value for Deep layers
-	MaintGe float32
+	MaintGe Float
 }
 
 var NeuronVars = []string{
 	"Act", "Ge", "Gi", "Gk", "Inet", "Vm", "Noise", "Spike", "Targ", "Ext",
 	"AvgSS", "AvgS", "AvgM", "AvgL", "AvgLLrn", "AvgSLrn", "ActLrn",
 	"ActM", "ActP", "ActDif", "ActDel", "ActQ0", "ActQ1", "ActQ2", "ActAvg", "Burst", "BurstPrv",
-	"GiSyn", "GiSelf", "ActSent", "GeRaw", "GiRaw", "GknaFast", "GknaMed", "GknaSlow", "ISI", "ISIAvg", "CtxtGe",
-	"ActG", "DALrn", "Shunt", "Maint", "MaintGe", "DA", "ACh", "SE", "GateAct", "GateNow", "GateCnt"}
+	"GiSyn", "GiSelf", "ActSent", "GeRaw", "GiRaw", "GknaFast", "GknaMed", "GknaSlow", "Gahp", "Excit", "ISI", "ISIAvg", "CtxtGe", "CtxtLag", "CtxtDif", "CtxtDLag",
+	"ActG", "DALrn", "Shunt", "Maint", "MaintGe", "DA", "ACh", "SE", "GateAct", "GateNow", "GateCnt", "DATonic",
+	"PredErr", "AttnGain"}
 
 var NeuronVarsMap map[string]int
 
@@ -236,9 +257,14 @@ var NeuronVarProps = map[string]string{
 	"GknaFast": `cat:"Gmisc"`,
 	"GknaMed":  `cat:"Gmisc"`,
 	"GknaSlow": `cat:"Gmisc"`,
+	"Gahp":     `cat:"Gmisc"`,
+	"Excit":    `cat:"Learn"`,
 	"ISI":      `cat:"Gmisc"`,
 	"ISIAvg":   `cat:"Gmisc"`,
 	"CtxtGe":   `cat:"Gmisc"`,
+	"CtxtLag":  `cat:"Learn"`,
+	"CtxtDif":  `cat:"Gmisc"`,
+	"CtxtDLag": `cat:"Learn"`,
 
 	"ActG":    `cat:"PBWM"`,
 	"DALrn":   `cat:"PBWM"`,
@@ -251,6 +277,11 @@ var NeuronVarProps = map[string]string{
 	"GateAct": `cat:"PBWM"`,
 	"GateNow": `cat:"PBWM"`,
 	"GateCnt": `cat:"PBWM"`,
+	"DATonic": `cat:"PBWM"`,
+
+	// deep attention vars
+	"PredErr":  `cat:"Phase"`,
+	"AttnGain": `cat:"Act" min:"0" max:"2"`,
 }
 
 func init() {
@@ -279,16 +310,16 @@ func NeuronVarIndexByName(varNm string) (int, error) {
 }
 
 // VarByIndex returns variable using index (0 = first variable in NeuronVars list)
-func (nrn *Neuron) VarByIndex(idx int) float32 {
-	fv := (*float32)(unsafe.Pointer(uintptr(unsafe.Pointer(nrn)) + uintptr(NeuronVarStart+4*idx)))
+func (nrn *Neuron) VarByIndex(idx int) Float {
+	fv := (*Float)(unsafe.Pointer(uintptr(unsafe.Pointer(nrn)) + uintptr(NeuronVarStart+fmath.Size*idx)))
 	return *fv
 }
 
 // VarByName returns variable by name, or error
-func (nrn *Neuron) VarByName(varNm string) (float32, error) {
+func (nrn *Neuron) VarByName(varNm string) (Float, error) {
 	i, err := NeuronVarIndexByName(varNm)
 	if err != nil {
-		return math32.NaN(), err
+		return fmath.NaN(), err
 	}
 	return nrn.VarByIndex(i), nil
 }
@@ -323,4 +354,8 @@ const (
 	// NeurHasCmpr means the neuron has external comparison input in its Targ field -- used for computing
 	// comparison statistics but does not drive neural activity ever
 	NeurHasCmpr
+
+	// NeurDropout means the neuron has been dropped (silenced) for the
+	// current training trial, by Layer.Dropout
+	NeurDropout
 )
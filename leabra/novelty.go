@@ -0,0 +1,150 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import "github.com/emer/leabra/v2/fmath"
+
+//////// NoveltyLayer
+
+// NoveltyParams are the parameters for a [NoveltyLayer], which computes
+// a novelty (mismatch) signal from the divergence between the activity
+// of a hippocampal input layer (e.g., ECin) and the reconstruction of
+// that input driven by CA3 -> CA1 recall (e.g., ECout), as
+// 1 - cosine of the two activity patterns.  Familiar inputs are
+// accurately reconstructed and produce low novelty, while novel inputs
+// produce a large mismatch.  The novelty value is the activation of the
+// layer, which is sent as ACh and / or DA to the SendTo layers, and
+// can also drive a [CINLayer] by including it in the CIN.RewLays.
+type NoveltyParams struct {
+
+	// InLay is the name of the input layer, e.g., ECin,
+	// whose activity is compared with the reconstruction.
+	InLay string
+
+	// ReconLay is the name of the layer with the reconstructed input
+	// driven by hippocampal recall, e.g., ECout, which must have the same
+	// number of units as InLay.
+	ReconLay string
+
+	// StartCyc is the cycle within the trial at which to start computing
+	// novelty, prior to which it is 0.  The default of 25 starts after
+	// the first quarter, when the CA3 -> CA1 recall drives ECout in the
+	// standard hippocampal model.  Novelty is held at its final minus
+	// phase value during the plus phase, when the reconstruction layer
+	// is typically clamped to the input.
+	StartCyc int `default:"25"`
+
+	// Thr is the threshold on 1 - cosine below which the input is
+	// considered familiar, with the novelty value renormalized
+	// to the 0-1 range above this threshold.
+	Thr Float `default:"0.1" min:"0" max:"1"`
+
+	// Gain is the multiplier on the novelty value, which is
+	// then clipped to the 0-1 range.
+	Gain Float `default:"1" min:"0"`
+
+	// SendACh sends the novelty value as ACh to the SendTo layers.
+	SendACh bool `default:"true"`
+
+	// SendDA sends the novelty value as DA to the SendTo layers.
+	SendDA bool
+}
+
+func (nv *NoveltyParams) Defaults() {
+	nv.StartCyc = 25
+	nv.Thr = 0.1
+	nv.Gain = 1
+	nv.SendACh = true
+}
+
+func (nv *NoveltyParams) Update() {
+}
+
+// NoveltyFromMismatch returns the novelty value from the
+// mismatch value (1 - cosine) using the Thr and Gain parameters.
+func (nv *NoveltyParams) NoveltyFromMismatch(mis Float) Float {
+	if mis <= nv.Thr {
+		return 0
+	}
+	if nv.Thr < 1 {
+		mis = (mis - nv.Thr) / (1 - nv.Thr)
+	}
+	return fmath.Clamp(nv.Gain*mis, 0, 1)
+}
+
+// AddNoveltyLayer adds a NoveltyLayer, with a single neuron, which computes
+// novelty from the mismatch between the given input layer (e.g., ECin)
+// and reconstruction layer (e.g., ECout).  Add the layers to receive the
+// ACh and / or DA novelty signal to its SendTo list.
+func (nt *Network) AddNoveltyLayer(name, inLay, reconLay string) *Layer {
+	ly := nt.AddLayer2D(name, 1, 1, NoveltyLayer)
+	ly.Novelty.InLay = inLay
+	ly.Novelty.ReconLay = reconLay
+	ly.Doc = "Novelty computes a novelty signal from the mismatch between the hippocampal input and its reconstruction from recall, sent as ACh and / or DA to drive novelty-gated encoding."
+	return ly
+}
+
+// NoveltyMismatch returns the mismatch between the Act activity of the
+// Novelty.InLay and Novelty.ReconLay layers, as 1 - cosine.
+// Returns 0 if the input layer has no activity, and 1 if the input
+// is active but the reconstruction has no activity.
+func (ly *Layer) NoveltyMismatch() Float {
+	il := ly.Network.LayerByName(ly.Novelty.InLay)
+	rl := ly.Network.LayerByName(ly.Novelty.ReconLay)
+	if il == nil || rl == nil {
+		return 0
+	}
+	n := min(len(il.Neurons), len(rl.Neurons))
+	var ab, aa, bb Float
+	for ni := range n {
+		in := &il.Neurons[ni]
+		rn := &rl.Neurons[ni]
+		if in.IsOff() || rn.IsOff() {
+			continue
+		}
+		ab += in.Act * rn.Act
+		aa += in.Act * in.Act
+		bb += rn.Act * rn.Act
+	}
+	if aa == 0 {
+		return 0
+	}
+	if bb == 0 {
+		return 1
+	}
+	return 1 - ab/fmath.Sqrt(aa*bb)
+}
+
+func (ly *Layer) ActFromGNovelty(ctx *Context) {
+	nov := Float(0)
+	switch {
+	case ctx.PlusPhase:
+		nov = ly.Neurons[0].ActM
+	case ctx.Cycle >= ly.Novelty.StartCyc:
+		nov = ly.Novelty.NoveltyFromMismatch(ly.NoveltyMismatch())
+	}
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		nrn.Act = nov
+		ly.Learn.AvgsFromAct(nrn)
+	}
+}
+
+// SendNoveltyFromAct sends the novelty activity in the first unit
+// as ACh and / or DA, according to the Novelty params.
+func (ly *Layer) SendNoveltyFromAct(ctx *Context) {
+	act := ly.Neurons[0].Act
+	if ly.Novelty.SendACh {
+		ly.NeuroMod.ACh = act
+		ly.SendACh(act)
+	}
+	if ly.Novelty.SendDA {
+		ly.NeuroMod.DA = act
+		ly.SendDA(act)
+	}
+}
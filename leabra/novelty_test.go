@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestNoveltyMismatch(t *testing.T) {
+	net := NewNetwork("Novelty")
+	in := net.AddLayer2D("ECin", 1, 4, InputLayer)
+	rc := net.AddLayer2D("ECout", 1, 4, TargetLayer)
+	nov := net.AddNoveltyLayer("Novelty", "ECin", "ECout")
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		in, rc []float32
+		mis    float32
+	}{
+		{"identical", []float32{1, 0.5, 0, 0}, []float32{1, 0.5, 0, 0}, 0},
+		{"scaled", []float32{1, 1, 0, 0}, []float32{0.5, 0.5, 0, 0}, 0},
+		{"disjoint", []float32{1, 1, 0, 0}, []float32{0, 0, 1, 1}, 1},
+		{"partial", []float32{1, 1, 0, 0}, []float32{1, 0, 0, 0}, 1 - math32.Sqrt2/2},
+		{"zero input", []float32{0, 0, 0, 0}, []float32{1, 1, 0, 0}, 0},
+		{"zero recon", []float32{1, 1, 0, 0}, []float32{0, 0, 0, 0}, 1},
+		{"all zero", []float32{0, 0, 0, 0}, []float32{0, 0, 0, 0}, 0},
+	}
+	for _, tt := range tests {
+		for ni := range in.Neurons {
+			in.Neurons[ni].Act = tt.in[ni]
+			rc.Neurons[ni].Act = tt.rc[ni]
+		}
+		mis := nov.NoveltyMismatch()
+		if math32.IsNaN(mis) || math32.Abs(mis-tt.mis) > difTol {
+			t.Errorf("%s: NoveltyMismatch = %g, want %g", tt.name, mis, tt.mis)
+		}
+	}
+
+	nov.Novelty.ReconLay = "Nope"
+	if mis := nov.NoveltyMismatch(); mis != 0 {
+		t.Errorf("missing recon layer: NoveltyMismatch = %g, want 0", mis)
+	}
+}
+
+func TestNoveltyFromMismatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		thr, gain float32
+		mis, nov  float32
+	}{
+		{"identical", 0.1, 1, 0, 0},
+		{"at thr", 0.1, 1, 0.1, 0},
+		{"renormalized", 0.2, 1, 0.6, 0.5},
+		{"disjoint", 0.1, 1, 1, 1},
+		{"gain", 0.2, 2, 0.4, 0.5},
+		{"gain clipped", 0.2, 4, 0.6, 1},
+		{"no thr", 0, 1, 0.3, 0.3},
+		{"thr 1", 1, 1, 1, 0},
+	}
+	for _, tt := range tests {
+		nv := &NoveltyParams{}
+		nv.Defaults()
+		nv.Thr = tt.thr
+		nv.Gain = tt.gain
+		if nov := nv.NoveltyFromMismatch(tt.mis); math32.IsNaN(nov) || math32.Abs(nov-tt.nov) > difTol {
+			t.Errorf("%s: NoveltyFromMismatch(%g) = %g, want %g", tt.name, tt.mis, nov, tt.nov)
+		}
+	}
+}
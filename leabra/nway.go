@@ -0,0 +1,348 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+// NWayAssoc generates the training and testing pattern tables for lists
+// of N-way associations, e.g., A-B-C triplets, generalizing the paired
+// associates of the AB-AC paradigm, for relational memory paradigms on
+// the hippocampus model.  Each element of an association has its own
+// random pattern in ElemPools pools of the EC layout, in order, and the
+// remaining pools have the context of the list.  The training table has
+// the full associations, and the testing table has each association cued
+// by each combination of NCue of its elements (any-cue testing), with the
+// other elements empty in the Input, and the full association in ECout.
+// The test table has Cue and Resp columns with the names of the cue and
+// response elements of each row, joined by +, for the cue/response
+// accounting of the recall of each response element (see Recall and
+// NWayRecall).  Lists can share elements, e.g., A-B-C and A-D-E,
+// for interference paradigms analogous to AB-AC.
+type NWayAssoc struct {
+
+	// use N-way associations
+	On bool
+
+	// number of elements in each association
+	NWay int `default:"3" min:"2"`
+
+	// number of elements given as the cue on test trials: each association is tested with every combination of NCue of its elements
+	NCue int `default:"1" min:"1"`
+
+	// number of associations in each list
+	NPats int `default:"10" min:"1"`
+
+	// number of pools of the EC layout for each element
+	ElemPools int `default:"3" min:"1"`
+
+	// proportion of active units in each element pool
+	PctAct float32 `default:"0.25" min:"0" max:"1"`
+
+	// minimum proportion of different active units between the patterns of an element
+	MinDiff float32 `default:"0.3" min:"0" max:"1"`
+
+	// proportion of the active units of the context prototype flipped for each association
+	CtxtFlip float32 `default:"0.2" min:"0" max:"1"`
+
+	// element names of each list, recorded by Tables
+	lists map[string][]string
+}
+
+func (na *NWayAssoc) Update() {
+}
+
+func (na *NWayAssoc) Defaults() {
+	na.NWay = 3
+	na.NCue = 1
+	na.NPats = 10
+	na.ElemPools = 3
+	na.PctAct = 0.25
+	na.MinDiff = 0.3
+	na.CtxtFlip = 0.2
+}
+
+func (na *NWayAssoc) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return na.On
+	}
+}
+
+// AddVocab adds NPats random patterns for each pool of each of the given
+// elements (e.g., "A", "B", "C") to the vocabulary, with the given pool
+// shape, as <elem><pool> entries, along with an "empty" entry.
+// Elements already in the vocabulary (e.g., shared between lists) are
+// not regenerated.  The patterns are added even if the MinDiff constraint
+// cannot be met, in which case the errors are returned.
+func (na *NWayAssoc) AddVocab(voc patgen.Vocab, poolY, poolX int, elems ...string) error {
+	if _, ok := voc["empty"]; !ok {
+		if _, err := patgen.AddVocabEmpty(voc, "empty", na.NPats, poolY, poolX); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for _, el := range elems {
+		for pi := range na.ElemPools {
+			nm := fmt.Sprintf("%s%d", el, pi)
+			if _, ok := voc[nm]; ok {
+				continue
+			}
+			if _, err := patgen.AddVocabPermutedBinary(voc, nm, na.NPats, poolY, poolX, na.PctAct, na.MinDiff); err != nil {
+				errs = append(errs, fmt.Errorf("leabra.NWayAssoc: %s: %w", nm, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// AddCtxtVocab adds the context patterns for a list to the vocabulary,
+// for the given number of pools with the given shape, as <ctxt><pool>
+// entries, each of which has a random prototype with CtxtFlip of its
+// active units flipped independently for each of the NPats associations.
+func (na *NWayAssoc) AddCtxtVocab(voc patgen.Vocab, ctxt string, nPools, poolY, poolX int) error {
+	if nPools <= 0 {
+		return nil
+	}
+	proto := ctxt + "Proto"
+	_, err := patgen.AddVocabPermutedBinary(voc, proto, nPools, poolY, poolX, na.PctAct, na.MinDiff)
+	flip := patgen.NFromPct(na.CtxtFlip, patgen.NFromPct(na.PctAct, poolY*poolX))
+	for pi := range nPools {
+		tsr, rerr := patgen.AddVocabRepeat(voc, fmt.Sprintf("%s%d", ctxt, pi), na.NPats, proto, pi)
+		if rerr != nil {
+			return rerr
+		}
+		patgen.FlipBitsRows(tsr, flip, flip, 1, 0)
+	}
+	return err
+}
+
+// Tables configures the given training and testing tables for a list of
+// N-way associations of the given elements, with the given context, from
+// the vocabulary (see AddVocab and AddCtxtVocab), with Input and ECout
+// columns of the given 4D shape (pools Y, X, units Y, X).  The rows are
+// named <list>_<index>, and the test rows are in blocks of NPats for each
+// combination of cue elements.  If test is nil, only the training table
+// is configured.
+func (na *NWayAssoc) Tables(voc patgen.Vocab, list string, elems []string, ctxt string, shape []int, train, test *table.Table) error {
+	if len(elems) != na.NWay {
+		return fmt.Errorf("leabra.NWayAssoc: list %s has %d elements, not NWay = %d", list, len(elems), na.NWay)
+	}
+	if na.NCue < 1 || na.NCue >= na.NWay {
+		return fmt.Errorf("leabra.NWayAssoc: NCue = %d must be between 1 and NWay - 1 = %d", na.NCue, na.NWay-1)
+	}
+	npools := shape[0] * shape[1]
+	nctxt := npools - na.NWay*na.ElemPools
+	if nctxt < 0 {
+		return fmt.Errorf("leabra.NWayAssoc: %d elements of %d pools do not fit in the %d pools", na.NWay, na.ElemPools, npools)
+	}
+	full := na.poolSource(elems, nil, ctxt, nctxt)
+	name := func(dt *table.Table, row int) {
+		dt.SetString("Name", row, fmt.Sprintf("%s_%d", list, row%na.NPats))
+	}
+
+	patgen.InitPats(train, train.MetaData["name"], train.MetaData["desc"], "Input", "ECout", na.NPats, shape[0], shape[1], shape[2], shape[3])
+	for _, col := range []string{"Input", "ECout"} {
+		if err := patgen.MixPats(train, voc, col, full); err != nil {
+			return err
+		}
+	}
+	for row := range train.Rows {
+		name(train, row)
+	}
+	if na.lists == nil {
+		na.lists = make(map[string][]string)
+	}
+	na.lists[list] = elems
+	if test == nil {
+		return nil
+	}
+
+	cues := na.cueSets()
+	patgen.InitPats(test, test.MetaData["name"], test.MetaData["desc"], "Input", "ECout", na.NPats*len(cues), shape[0], shape[1], shape[2], shape[3])
+	test.AddStringColumn("Cue")
+	test.AddStringColumn("Resp")
+	test.SetNumRows(na.NPats * len(cues))
+	for ci, cue := range cues {
+		st := ci * na.NPats
+		if err := patgen.MixPatsN(test, voc, "Input", na.poolSource(elems, cue, ctxt, nctxt), st, 0, na.NPats); err != nil {
+			return err
+		}
+		if err := patgen.MixPatsN(test, voc, "ECout", full, st, 0, na.NPats); err != nil {
+			return err
+		}
+		cnm, rnm := na.cueNames(elems, cue)
+		for row := st; row < st+na.NPats; row++ {
+			name(test, row)
+			test.SetString("Cue", row, cnm)
+			test.SetString("Resp", row, rnm)
+		}
+	}
+	return nil
+}
+
+// Elems returns the names of the elements of the given list,
+// as configured by Tables.
+func (na *NWayAssoc) Elems(list string) []string {
+	return na.lists[list]
+}
+
+// Recall returns the recall of each response element (not in the cue)
+// of a test trial for the given list and cue (the Cue column of the test
+// table), by element name, as 1 if the given (binarized) activity matches
+// the target over the units of the element, within the given threshold
+// on the proportion of incorrect units (see ReadoutMemStats), else 0.
+// poolUnits is the number of units per pool.
+func (na *NWayAssoc) Recall(list, cue string, act, trg []float32, poolUnits int, thr float32) map[string]float32 {
+	elems := na.lists[list]
+	cued := strings.Split(cue, "+")
+	nu := na.ElemPools * poolUnits
+	rec := make(map[string]float32)
+	for ei, el := range elems {
+		st := ei * nu
+		if slices.Contains(cued, el) || st+nu > len(act) || st+nu > len(trg) {
+			continue
+		}
+		var ms ReadoutMemStats
+		ms.Compute(act[st:st+nu], trg[st:st+nu], nil)
+		rec[el] = ms.Mem(thr, false)
+	}
+	return rec
+}
+
+// poolSource returns the vocabulary names for each pool of the given
+// elements and context, with the elements not in the cue empty,
+// or all elements if cue is nil.
+func (na *NWayAssoc) poolSource(elems []string, cue []int, ctxt string, nctxt int) []string {
+	var src []string
+	for ei, el := range elems {
+		on := cue == nil || slices.Contains(cue, ei)
+		for pi := range na.ElemPools {
+			if on {
+				src = append(src, fmt.Sprintf("%s%d", el, pi))
+			} else {
+				src = append(src, "empty")
+			}
+		}
+	}
+	for pi := range nctxt {
+		src = append(src, fmt.Sprintf("%s%d", ctxt, pi))
+	}
+	return src
+}
+
+// cueSets returns all the combinations of NCue element indexes.
+func (na *NWayAssoc) cueSets() [][]int {
+	var sets [][]int
+	var add func(st int, cur []int)
+	add = func(st int, cur []int) {
+		if len(cur) == na.NCue {
+			sets = append(sets, append([]int(nil), cur...))
+			return
+		}
+		for ei := st; ei < na.NWay; ei++ {
+			add(ei+1, append(cur, ei))
+		}
+	}
+	add(0, nil)
+	return sets
+}
+
+// cueNames returns the names of the cue and response elements,
+// joined by +.
+func (na *NWayAssoc) cueNames(elems []string, cue []int) (cnm, rnm string) {
+	var cs, rs []string
+	for ei, el := range elems {
+		if slices.Contains(cue, ei) {
+			cs = append(cs, el)
+		} else {
+			rs = append(rs, el)
+		}
+	}
+	return strings.Join(cs, "+"), strings.Join(rs, "+")
+}
+
+// NWayRecall accumulates the cue/response accounting of the recall of
+// each response element over the test trials of N-way associations
+// (see NWayAssoc.Recall), in a Table with one row per cue and response
+// element: Cue, Resp, N (number of trials), NRecall (number recalled),
+// and Recall (proportion recalled).
+type NWayRecall struct {
+
+	// Table is the accounting table.
+	Table *table.Table
+
+	// rows are the table rows by cue and response
+	rows map[string]int
+}
+
+// Reset resets the accounting, e.g., at the start of a test epoch.
+func (nr *NWayRecall) Reset() {
+	dt := table.NewTable()
+	dt.SetMetaData("name", "NWayRecall")
+	dt.AddStringColumn("Cue")
+	dt.AddStringColumn("Resp")
+	dt.AddIntColumn("N")
+	dt.AddIntColumn("NRecall")
+	dt.AddFloat64Column("Recall")
+	nr.Table = dt
+	nr.rows = make(map[string]int)
+}
+
+// Add adds the recall of each response element for a test trial
+// with the given cue.
+func (nr *NWayRecall) Add(cue string, recall map[string]float32) {
+	if nr.Table == nil {
+		nr.Reset()
+	}
+	dt := nr.Table
+	rsps := make([]string, 0, len(recall))
+	for rsp := range recall {
+		rsps = append(rsps, rsp)
+	}
+	slices.Sort(rsps)
+	for _, rsp := range rsps {
+		key := cue + "\t" + rsp
+		row, ok := nr.rows[key]
+		if !ok {
+			row = dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetString("Cue", row, cue)
+			dt.SetString("Resp", row, rsp)
+			nr.rows[key] = row
+		}
+		n := dt.Float("N", row) + 1
+		nrec := dt.Float("NRecall", row) + float64(recall[rsp])
+		dt.SetFloat("N", row, n)
+		dt.SetFloat("NRecall", row, nrec)
+		dt.SetFloat("Recall", row, nrec/n)
+	}
+}
+
+// Mean returns the mean proportion recalled over all the trials
+// and response elements, or 0 if none.
+func (nr *NWayRecall) Mean() float64 {
+	if nr.Table == nil {
+		return 0
+	}
+	var n, nrec float64
+	for row := range nr.Table.Rows {
+		n += nr.Table.Float("N", row)
+		nrec += nr.Table.Float("NRecall", row)
+	}
+	if n == 0 {
+		return 0
+	}
+	return nrec / n
+}
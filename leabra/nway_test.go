@@ -0,0 +1,79 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+func TestNWayAssoc(t *testing.T) {
+	patgen.NewRand(10)
+	na := &NWayAssoc{}
+	na.Defaults()
+	na.NPats = 4
+	na.ElemPools = 1
+	shape := []int{2, 2, 3, 4}
+	voc := patgen.Vocab{}
+	if err := na.AddVocab(voc, 3, 4, "A", "B", "C", "D", "E"); err != nil {
+		t.Fatal(err)
+	}
+	for _, ctxt := range []string{"ctxtAB", "ctxtAC"} {
+		if err := na.AddCtxtVocab(voc, ctxt, 1, 3, 4); err != nil {
+			t.Fatal(err)
+		}
+	}
+	trnAB, tstAB := table.NewTable(), table.NewTable()
+	trnAC, tstAC := table.NewTable(), table.NewTable()
+	if err := na.Tables(voc, "ab", []string{"A", "B", "C"}, "ctxtAB", shape, trnAB, tstAB); err != nil {
+		t.Fatal(err)
+	}
+	if err := na.Tables(voc, "ac", []string{"A", "D", "E"}, "ctxtAC", shape, trnAC, tstAC); err != nil {
+		t.Fatal(err)
+	}
+	if trnAB.Rows != 4 || tstAB.Rows != 12 || tstAB.StringValue("Name", 5) != "ab_1" {
+		t.Fatalf("NWayAssoc: %d train rows, %d test rows, name %s", trnAB.Rows, tstAB.Rows, tstAB.StringValue("Name", 5))
+	}
+	if tstAB.StringValue("Cue", 5) != "B" || tstAB.StringValue("Resp", 5) != "A+C" {
+		t.Errorf("NWayAssoc: Cue %s Resp %s", tstAB.StringValue("Cue", 5), tstAB.StringValue("Resp", 5))
+	}
+	poolSum := func(tsr tensor.Tensor, pi int) float64 {
+		s := 0.0
+		for i := pi * 12; i < (pi+1)*12; i++ {
+			s += tsr.Float1D(i)
+		}
+		return s
+	}
+	in, out := tstAB.Tensor("Input", 5), tstAB.Tensor("ECout", 5)
+	if poolSum(in, 0) != 0 || poolSum(in, 1) != 3 || poolSum(in, 2) != 0 || poolSum(in, 3) != 3 || poolSum(out, 0) != 3 || poolSum(out, 2) != 3 {
+		t.Errorf("NWayAssoc: cue pools not correct")
+	}
+	// A is shared between the lists
+	for i := range 12 {
+		if trnAB.Tensor("ECout", 2).Float1D(i) != trnAC.Tensor("ECout", 2).Float1D(i) {
+			t.Fatalf("NWayAssoc: shared element A differs between lists")
+		}
+	}
+	trg := tstAC.Tensor("ECout", 0).(*tensor.Float32).Values
+	act := slices.Clone(trg)
+	for i := 12; i < 24; i++ { // D recalled wrong
+		act[i] = 1 - act[i]
+	}
+	rec := na.Recall("ac", tstAC.StringValue("Cue", 0), act, trg, 12, 0.34)
+	if len(rec) != 2 || rec["D"] != 0 || rec["E"] != 1 {
+		t.Errorf("NWayAssoc Recall: %v", rec)
+	}
+	nr := &NWayRecall{}
+	nr.Reset()
+	nr.Add("A", rec)
+	nr.Add("A", map[string]float32{"D": 1, "E": 1})
+	if nr.Table.Rows != 2 || nr.Table.Float("Recall", 0) != 0.5 || nr.Table.Float("N", 1) != 2 || nr.Mean() != 0.75 {
+		t.Errorf("NWayRecall: rows %d, mean %g", nr.Table.Rows, nr.Mean())
+	}
+}
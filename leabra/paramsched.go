@@ -0,0 +1,127 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// ParamSchedStep is a scheduled change of params in a ParamSched:
+// a params sheet that is applied from the given epoch of each run,
+// starting at the given run.
+type ParamSchedStep struct {
+
+	// Run is the first run in which the step applies.
+	Run int
+
+	// Epoch is the training epoch of each run from which the step applies.
+	Epoch int
+
+	// Sheet is the name of the params sheet to apply,
+	// in the params sets of the sim.
+	Sheet string
+}
+
+// ParamSched is a schedule of params changes over training (a curriculum
+// of params), e.g., reducing the mossy fiber strength after epoch 10,
+// which are applied automatically at the start of the training epochs
+// by LooperParamSched, instead of editing the sim code for each such
+// manipulation.  When the steps in effect change, the base params
+// (Base and the ExtraSheets) are applied again, followed by the sheets
+// of the steps in effect, in order, using ApplyParamsLive, so the params
+// are always those of the steps in effect, including at the start of
+// each run, and when resuming from a checkpoint.
+type ParamSched struct {
+
+	// Steps are the scheduled params changes.
+	Steps []ParamSchedStep
+
+	// Current are the sheets of the steps in effect, space separated,
+	// as last applied by Update, for logging.
+	Current string `edit:"-"`
+}
+
+// Active returns the sheets of the steps in effect at the given
+// run and epoch, space separated.
+func (ps *ParamSched) Active(run, epoch int) string {
+	var sheets []string
+	for _, st := range ps.Steps {
+		if run >= st.Run && epoch >= st.Epoch {
+			sheets = append(sheets, st.Sheet)
+		}
+	}
+	return strings.Join(sheets, " ")
+}
+
+// Update applies the params of the steps in effect at the given run
+// and epoch, if they differ from the Current ones, after the base
+// params (Base and the ExtraSheets) of the given params, using
+// ApplyParamsLive.  Returns true if the params were applied.
+func (ps *ParamSched) Update(net *Network, ctx *Context, pars *emer.NetParams, run, epoch int) (bool, error) {
+	act := ps.Active(run, epoch)
+	if act == ps.Current {
+		return false, nil
+	}
+	sheets := []string{"Base"}
+	if pars.ExtraSheets != "Base" {
+		sheets = append(sheets, strings.Fields(pars.ExtraSheets)...)
+	}
+	sheets = append(sheets, strings.Fields(act)...)
+	var errs []error
+	for _, snm := range sheets {
+		sh, err := pars.Params.SheetByName(snm)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, err := net.ApplyParamsLive(ctx, sh, pars.SetMsg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	ps.Current = act
+	return true, errors.Join(errs...)
+}
+
+// LooperParamSched adds a function at the start of each training epoch
+// that applies the params of the given schedule for the current run and
+// epoch counters (ParamSched.Update), printing a message when they change.
+// It should be added before LooperLrateSched, which sets the learning
+// rates from their base params.
+func LooperParamSched(ls *looper.Stacks, net *Network, ctx *Context, ps *ParamSched, pars *emer.NetParams) {
+	trainRun := ls.Loop(etime.Train, etime.Run)
+	trainEpoch := ls.Loop(etime.Train, etime.Epoch)
+	trainEpoch.OnStart.Add("ParamSched", func() {
+		run := 0
+		if trainRun != nil {
+			run = trainRun.Counter.Cur
+		}
+		applied, err := ps.Update(net, ctx, pars, run, trainEpoch.Counter.Cur)
+		errors.Log(err)
+		if applied {
+			fmt.Printf("ParamSched: Run %d Epoch %d: Base %s\n", run, trainEpoch.Counter.Cur, ps.Current)
+		}
+	})
+}
+
+// LogAddParamSchedItem adds a ParamSched item to the given logs at the
+// given mode and time, with the sheets of the steps of the given schedule
+// in effect (ParamSched.Current).
+func LogAddParamSchedItem(lg *elog.Logs, ps *ParamSched, mode etime.Modes, time etime.Times) {
+	lg.AddItem(&elog.Item{
+		Name: "ParamSched",
+		Type: reflect.String,
+		Write: elog.WriteMap{
+			etime.Scope(mode, time): func(ctx *elog.Context) {
+				ctx.SetString(ps.Current)
+			}}})
+}
@@ -0,0 +1,71 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/params"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestParamSched(t *testing.T) {
+	net := MakeTestNet(t)
+	pars := &emer.NetParams{Params: ParamSets, Network: net}
+	pars.SetAll()
+	pt := net.LayerByName("Hidden").RecvPaths[0]
+	ps := &ParamSched{Steps: []ParamSchedStep{{Epoch: 2, Sheet: "NormOn"}, {Run: 1, Sheet: "MomentOn"}}}
+	ctx := NewContext()
+
+	update := func(run, epoch int, wantApplied bool, wantCur string, wantNorm, wantMoment bool) {
+		t.Helper()
+		applied, err := ps.Update(net, ctx, pars, run, epoch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if applied != wantApplied || ps.Current != wantCur || pt.Learn.Norm.On != wantNorm || pt.Learn.Momentum.On != wantMoment {
+			t.Errorf("run %d epoch %d: applied %v current %q norm %v moment %v", run, epoch, applied, ps.Current, pt.Learn.Norm.On, pt.Learn.Momentum.On)
+		}
+	}
+	update(0, 0, false, "", false, false)
+	update(0, 2, true, "NormOn", true, false)
+	update(0, 3, false, "NormOn", true, false)
+	update(1, 0, true, "MomentOn", false, true)
+	update(1, 5, true, "NormOn MomentOn", true, true)
+	update(0, 0, true, "", false, false)
+
+	ps.Steps = append(ps.Steps, ParamSchedStep{Epoch: 1, Sheet: "Nope"})
+	if _, err := ps.Update(net, ctx, pars, 0, 1); err == nil {
+		t.Error("no error for unknown sheet")
+	}
+}
+
+func TestApplyParamsLiveMossy(t *testing.T) {
+	net := NewNetwork("HipNet")
+	dg := net.AddLayer2D("DG", 2, 2, SuperLayer)
+	ca3 := net.AddLayer2D("CA3", 2, 2, SuperLayer)
+	net.ConnectLayers(dg, ca3, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	mossy := ca3.RecvPaths[0]
+	mossy.WtScale.Rel = 4
+	net.Theta.MossyRel = 4
+	ctx := NewContext()
+
+	sh := params.Sheet{{Sel: "Layer", Params: params.Params{"Layer.Inhib.Layer.Gi": "2"}}}
+	net.ApplyParamsLive(ctx, &sh, false)
+	if net.Theta.MossyRel != 4 || mossy.WtScale.Rel != 4 {
+		t.Errorf("mossy changed by other params: MossyRel %g Rel %g", net.Theta.MossyRel, mossy.WtScale.Rel)
+	}
+	ctx.Quarter = 1
+	sh = params.Sheet{{Sel: "#DGToCA3", Params: params.Params{"Path.WtScale.Rel": "6"}}}
+	net.ApplyParamsLive(ctx, &sh, false)
+	if net.Theta.MossyRel != 6 || mossy.WtScale.Rel != net.Theta.MossyScale(1, false) {
+		t.Errorf("mossy not updated: MossyRel %g Rel %g", net.Theta.MossyRel, mossy.WtScale.Rel)
+	}
+}
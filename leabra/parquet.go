@@ -0,0 +1,392 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"reflect"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// ParquetWriter streams the rows of a table to an Apache Parquet file,
+// which is much smaller and faster to load in analysis tools (pandas,
+// polars, arrow, duckdb, R) than a TSV file for large logs.  The rows are
+// buffered and written as a row group every RowGroup rows, after which
+// the file footer is rewritten, so that the file is always a valid Parquet
+// file with all the rows up to the last row group, in case the run crashes.
+// The schema is determined from the columns of the table at the first row:
+// float64 columns are DOUBLE, float32 are FLOAT, integer and bool columns
+// are INT64, and string columns are UTF8 strings, and the cells of
+// tensor columns are written as separate columns named Name[i], in row-major
+// order.  Values are PLAIN encoded, and compressed with gzip by default.
+type ParquetWriter struct {
+
+	// RowGroup is the number of rows per row group, i.e., the number of
+	// rows that are buffered before writing them to the file.
+	RowGroup int
+
+	// Uncompressed does not compress the data, which is faster to write.
+	Uncompressed bool
+
+	// file is the open file.
+	file *os.File
+
+	// cols are the leaf columns of the schema.
+	cols []*parquetColumn
+
+	// nbuf is the number of rows buffered in the columns.
+	nbuf int
+
+	// groups is the encoded metadata of each row group written.
+	groups [][]byte
+
+	// nrows is the number of rows written in row groups.
+	nrows int64
+
+	// end is the end of the row group data in the file,
+	// at which the footer is written.
+	end int64
+}
+
+// parquetColumn is a leaf column of a ParquetWriter.
+type parquetColumn struct {
+
+	// name of the column.
+	name string
+
+	// parquet physical type.
+	ptype int32
+
+	// column index in the table.
+	col int
+
+	// cell index within the column, for tensor columns.
+	cell int
+
+	// buf has the PLAIN encoded values of the buffered rows.
+	buf bytes.Buffer
+}
+
+// Parquet physical types, encodings and codecs.
+const (
+	parquetInt64     = 2
+	parquetFloat     = 4
+	parquetDouble    = 5
+	parquetByteArray = 6
+	parquetPlain     = 0
+	parquetRLE       = 3
+	parquetGzip      = 2
+	parquetUTF8      = 0
+)
+
+// parquetMagic is the magic number at the start and end of a Parquet file.
+const parquetMagic = "PAR1"
+
+// NewParquetWriter creates a new Parquet file with the given name,
+// to write table rows to with WriteRow, with rowGroup rows per row group
+// (100 if <= 0).  Close must be called at the end.
+func NewParquetWriter(filename string, rowGroup int) (*ParquetWriter, error) {
+	f, err := os.Create(filename)
+	if err != nil {
+		return nil, err
+	}
+	if rowGroup <= 0 {
+		rowGroup = 100
+	}
+	pw := &ParquetWriter{RowGroup: rowGroup, file: f, end: int64(len(parquetMagic))}
+	if _, err := f.WriteString(parquetMagic); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return pw, nil
+}
+
+// WriteRow writes the given row of the given table, which must have the
+// same columns as the first row written.  The row is buffered until
+// there are RowGroup rows, which are then written to the file.
+func (pw *ParquetWriter) WriteRow(dt *table.Table, row int) error {
+	if pw.file == nil {
+		return fmt.Errorf("leabra.ParquetWriter: file is closed")
+	}
+	if pw.cols == nil {
+		pw.configColumns(dt)
+		if err := pw.writeFooter(); err != nil {
+			return err
+		}
+	}
+	for _, pc := range pw.cols {
+		if pc.col >= len(dt.Columns) {
+			return fmt.Errorf("leabra.ParquetWriter: column %s not found in table", pc.name)
+		}
+		tsr := dt.Columns[pc.col]
+		_, csz := tsr.RowCellSize()
+		idx := row*csz + pc.cell
+		if idx >= tsr.Len() {
+			return fmt.Errorf("leabra.ParquetWriter: row %d of column %s out of range", row, pc.name)
+		}
+		switch pc.ptype {
+		case parquetByteArray:
+			s := tsr.String1D(idx)
+			binary.Write(&pc.buf, binary.LittleEndian, uint32(len(s)))
+			pc.buf.WriteString(s)
+		case parquetDouble:
+			binary.Write(&pc.buf, binary.LittleEndian, tsr.Float1D(idx))
+		case parquetFloat:
+			binary.Write(&pc.buf, binary.LittleEndian, float32(tsr.Float1D(idx)))
+		case parquetInt64:
+			binary.Write(&pc.buf, binary.LittleEndian, int64(tsr.Float1D(idx)))
+		}
+	}
+	pw.nbuf++
+	if pw.nbuf >= pw.RowGroup {
+		return pw.Flush()
+	}
+	return nil
+}
+
+// configColumns configures the leaf columns from the columns of the table.
+func (pw *ParquetWriter) configColumns(dt *table.Table) {
+	for ci, tsr := range dt.Columns {
+		var pt int32
+		switch tsr.DataType() {
+		case reflect.String:
+			pt = parquetByteArray
+		case reflect.Float64:
+			pt = parquetDouble
+		case reflect.Float32:
+			pt = parquetFloat
+		default:
+			pt = parquetInt64
+		}
+		nm := dt.ColumnNames[ci]
+		_, csz := tsr.RowCellSize()
+		if tsr.NumDims() == 1 {
+			pw.cols = append(pw.cols, &parquetColumn{name: nm, ptype: pt, col: ci})
+			continue
+		}
+		for i := range csz {
+			pw.cols = append(pw.cols, &parquetColumn{name: fmt.Sprintf("%s[%d]", nm, i), ptype: pt, col: ci, cell: i})
+		}
+	}
+}
+
+// Flush writes the buffered rows to the file as a row group,
+// and rewrites the footer.
+func (pw *ParquetWriter) Flush() error {
+	if pw.file == nil || pw.nbuf == 0 {
+		return nil
+	}
+	var data bytes.Buffer
+	gp := &thriftWriter{}
+	gp.list(1, thriftStruct, len(pw.cols))
+	totSize := int64(0)
+	for _, pc := range pw.cols {
+		raw := pc.buf.Bytes()
+		page := raw
+		if !pw.Uncompressed {
+			var zb bytes.Buffer
+			zw := gzip.NewWriter(&zb)
+			zw.Write(raw)
+			zw.Close()
+			page = zb.Bytes()
+		}
+		hdr := &thriftWriter{}
+		hdr.i32(1, 0) // DATA_PAGE
+		hdr.i32(2, int32(len(raw)))
+		hdr.i32(3, int32(len(page)))
+		hdr.begin(5) // DataPageHeader
+		hdr.i32(1, int32(pw.nbuf))
+		hdr.i32(2, parquetPlain)
+		hdr.i32(3, parquetRLE)
+		hdr.i32(4, parquetRLE)
+		hdr.end()
+		hdr.end()
+		off := pw.end + int64(data.Len())
+		data.Write(hdr.Bytes())
+		data.Write(page)
+		usize := int64(hdr.Len() + len(raw))
+		csize := int64(hdr.Len() + len(page))
+		totSize += usize
+
+		gp.beginElem() // ColumnChunk
+		gp.i64(2, off)
+		gp.begin(3) // ColumnMetaData
+		gp.i32(1, pc.ptype)
+		gp.list(2, thriftI32, 2)
+		gp.zigzag(parquetPlain)
+		gp.zigzag(parquetRLE)
+		gp.list(3, thriftBinary, 1)
+		gp.binary(pc.name)
+		codec := int32(parquetGzip)
+		if pw.Uncompressed {
+			codec = 0
+		}
+		gp.i32(4, codec)
+		gp.i64(5, int64(pw.nbuf))
+		gp.i64(6, usize)
+		gp.i64(7, csize)
+		gp.i64(9, off)
+		gp.end()
+		gp.end()
+		pc.buf.Reset()
+	}
+	gp.i64(2, totSize)
+	gp.i64(3, int64(pw.nbuf))
+	gp.end()
+	if _, err := pw.file.WriteAt(data.Bytes(), pw.end); err != nil {
+		return err
+	}
+	pw.end += int64(data.Len())
+	pw.groups = append(pw.groups, gp.Bytes())
+	pw.nrows += int64(pw.nbuf)
+	pw.nbuf = 0
+	return pw.writeFooter()
+}
+
+// writeFooter writes the file metadata footer at the end of the row groups.
+func (pw *ParquetWriter) writeFooter() error {
+	md := &thriftWriter{}
+	md.i32(1, 1) // version
+	md.list(2, thriftStruct, len(pw.cols)+1)
+	md.beginElem()
+	md.str(4, "schema")
+	md.i32(5, int32(len(pw.cols)))
+	md.end()
+	for _, pc := range pw.cols {
+		md.beginElem()
+		md.i32(1, pc.ptype)
+		md.i32(3, 0) // REQUIRED
+		md.str(4, pc.name)
+		if pc.ptype == parquetByteArray {
+			md.i32(6, parquetUTF8)
+		}
+		md.end()
+	}
+	md.i64(3, pw.nrows)
+	md.list(4, thriftStruct, len(pw.groups))
+	for _, gp := range pw.groups {
+		md.Write(gp)
+	}
+	md.str(6, "github.com/emer/leabra/v2 ParquetWriter")
+	md.end()
+	binary.Write(md, binary.LittleEndian, uint32(md.Len()))
+	md.WriteString(parquetMagic)
+	b := md.Bytes()
+	if _, err := pw.file.WriteAt(b, pw.end); err != nil {
+		return err
+	}
+	if err := pw.file.Truncate(pw.end + int64(len(b))); err != nil {
+		return err
+	}
+	return pw.file.Sync()
+}
+
+// Close writes any buffered rows and closes the file.
+func (pw *ParquetWriter) Close() error {
+	if pw.file == nil {
+		return nil
+	}
+	err := pw.Flush()
+	if cerr := pw.file.Close(); err == nil {
+		err = cerr
+	}
+	pw.file = nil
+	return err
+}
+
+// Thrift compact protocol types, for the Parquet metadata.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// thriftWriter encodes structs in the Thrift compact protocol,
+// as used for the Parquet metadata.
+type thriftWriter struct {
+	bytes.Buffer
+
+	// last is the id of the last field in the current struct.
+	last int16
+
+	// stack is the last field ids of the enclosing structs.
+	stack []int16
+}
+
+func (tw *thriftWriter) uvarint(v uint64) {
+	tw.Write(binary.AppendUvarint(nil, v))
+}
+
+func (tw *thriftWriter) zigzag(v int64) {
+	tw.uvarint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (tw *thriftWriter) field(typ byte, id int16) {
+	if d := id - tw.last; d > 0 && d <= 15 {
+		tw.WriteByte(byte(d)<<4 | typ)
+	} else {
+		tw.WriteByte(typ)
+		tw.zigzag(int64(id))
+	}
+	tw.last = id
+}
+
+func (tw *thriftWriter) i32(id int16, v int32) {
+	tw.field(thriftI32, id)
+	tw.zigzag(int64(v))
+}
+
+func (tw *thriftWriter) i64(id int16, v int64) {
+	tw.field(thriftI64, id)
+	tw.zigzag(v)
+}
+
+func (tw *thriftWriter) binary(s string) {
+	tw.uvarint(uint64(len(s)))
+	tw.WriteString(s)
+}
+
+func (tw *thriftWriter) str(id int16, s string) {
+	tw.field(thriftBinary, id)
+	tw.binary(s)
+}
+
+func (tw *thriftWriter) list(id int16, elem byte, n int) {
+	tw.field(thriftList, id)
+	if n < 15 {
+		tw.WriteByte(byte(n)<<4 | elem)
+	} else {
+		tw.WriteByte(0xF0 | elem)
+		tw.uvarint(uint64(n))
+	}
+}
+
+// begin begins a struct field.
+func (tw *thriftWriter) begin(id int16) {
+	tw.field(thriftStruct, id)
+	tw.beginElem()
+}
+
+// beginElem begins a struct element of a list.
+func (tw *thriftWriter) beginElem() {
+	tw.stack = append(tw.stack, tw.last)
+	tw.last = 0
+}
+
+// end ends the current struct.
+func (tw *thriftWriter) end() {
+	tw.WriteByte(0)
+	if n := len(tw.stack); n > 0 {
+		tw.last = tw.stack[n-1]
+		tw.stack = tw.stack[:n-1]
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// PartialCue generates degraded test cues from full patterns, for
+// parametric pattern completion curves, by removing a proportion of the
+// active bits chosen at random over the whole pattern, instead of deleting
+// whole pools, and optionally corrupting the cue with the same number of
+// randomly chosen inactive bits turned on (noise).  A bit is active if its
+// value is > 0, and keeps its value if retained.
+type PartialCue struct {
+
+	// Frac is the proportion of the active bits of the full pattern that
+	// are retained in the cue: 1 = the full pattern.
+	Frac float32 `default:"1" min:"0" max:"1"`
+
+	// Corrupt turns on randomly chosen inactive bits in place of the
+	// removed ones, keeping the number of active bits the same,
+	// so that the cue is noise-corrupted instead of just partial.
+	Corrupt bool
+
+	// OnVal is the value for the bits turned on by Corrupt.
+	OnVal float32 `default:"1"`
+}
+
+func (pc *PartialCue) Defaults() {
+	pc.Frac = 1
+	pc.OnVal = 1
+}
+
+// Cue degrades the given pattern in place, using the given random number
+// generator (the global one if nil), returning the number of bits removed.
+func (pc *PartialCue) Cue(pat tensor.Tensor, rnd randx.Rand) int {
+	var ons, offs []int
+	for i := range pat.Len() {
+		if pat.Float1D(i) > 0 {
+			ons = append(ons, i)
+		} else {
+			offs = append(offs, i)
+		}
+	}
+	nrm := int(math.Round(float64(len(ons)) * float64(1-pc.Frac)))
+	nrm = min(max(nrm, 0), len(ons))
+	if nrm == 0 {
+		return 0
+	}
+	if rnd == nil {
+		rnd = randx.NewGlobalRand()
+	}
+	randx.PermuteInts(ons, rnd)
+	for _, i := range ons[:nrm] {
+		pat.SetFloat1D(i, 0)
+	}
+	if pc.Corrupt {
+		randx.PermuteInts(offs, rnd)
+		for _, i := range offs[:min(nrm, len(offs))] {
+			pat.SetFloat1D(i, float64(pc.OnVal))
+		}
+	}
+	return nrm
+}
+
+// CueTable degrades the patterns in the given column of each row of the
+// table in place (see Cue), with a different random choice of bits for
+// each row.
+func (pc *PartialCue) CueTable(dt *table.Table, col string, rnd randx.Rand) error {
+	cl, err := dt.ColumnByName(col)
+	if err != nil {
+		return fmt.Errorf("leabra.PartialCue: %w", err)
+	}
+	for ri := range dt.Rows {
+		pc.Cue(cl.SubSpace([]int{ri}), rnd)
+	}
+	return nil
+}
+
+// PartialCueSeries returns a table with a copy of the rows of the given
+// table for each of the given cue fractions, with the patterns in the
+// given column degraded with that Frac (and the Corrupt setting of pc),
+// for measuring a pattern completion curve as a function of the cue
+// fraction in one test epoch.  The names in the Name column (if present)
+// have a _cue<percent> suffix added, e.g., ab_1_cue50, and a CueFrac
+// column is added with the fraction, for grouping the results.
+func (pc *PartialCue) PartialCueSeries(dt *table.Table, col string, fracs []float32, rnd randx.Rand) (*table.Table, error) {
+	if _, err := dt.ColumnByName(col); err != nil {
+		return nil, fmt.Errorf("leabra.PartialCue: %w", err)
+	}
+	st := dt.Clone()
+	st.SetNumRows(0)
+	st.AddFloat32Column("CueFrac")
+	nmCol, _ := dt.ColumnByName("Name")
+	npc := *pc
+	for _, fr := range fracs {
+		npc.Frac = fr
+		ft := dt.Clone()
+		if err := npc.CueTable(ft, col, rnd); err != nil {
+			return nil, err
+		}
+		if nmCol != nil {
+			fnm, _ := ft.ColumnByName("Name")
+			for ri := range ft.Rows {
+				fnm.SetString1D(ri, fmt.Sprintf("%s_cue%d", nmCol.String1D(ri), int(math.Round(100*float64(fr)))))
+			}
+		}
+		fcol := ft.AddFloat32Column("CueFrac")
+		for ri := range ft.Rows {
+			fcol.SetFloat1D(ri, float64(fr))
+		}
+		st.AppendRows(ft)
+	}
+	return st, nil
+}
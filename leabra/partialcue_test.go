@@ -0,0 +1,80 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestPartialCue(t *testing.T) {
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{4, 5})
+	dt.SetNumRows(2)
+	for ri := range 2 {
+		dt.Columns[0].SetString1D(ri, fmt.Sprintf("ab_%d", ri))
+		for i := range 10 {
+			dt.Columns[1].SetFloat1D(ri*20+2*i+ri, 1)
+		}
+	}
+	nOn := func(tsr tensor.Tensor) int {
+		n := 0
+		for i := range tsr.Len() {
+			if tsr.Float1D(i) > 0 {
+				n++
+			}
+		}
+		return n
+	}
+	rnd := randx.NewSysRand(1)
+	pc := &PartialCue{}
+	pc.Defaults()
+	pat := dt.Columns[1].SubSpace([]int{0}).Clone()
+	if n := pc.Cue(pat, rnd); n != 0 || nOn(pat) != 10 {
+		t.Errorf("Frac 1 removed %d bits", n)
+	}
+	pc.Frac = 0.7
+	if n := pc.Cue(pat, rnd); n != 3 || nOn(pat) != 7 {
+		t.Errorf("Frac 0.7: removed %d, on: %d", n, nOn(pat))
+	}
+	for i := range pat.Len() {
+		if pat.Float1D(i) > 0 && dt.Columns[1].Float1D(i) == 0 {
+			t.Errorf("partial cue has a bit not in the full pattern: %d", i)
+		}
+	}
+	pc.Frac, pc.Corrupt = 0.5, true
+	pat = dt.Columns[1].SubSpace([]int{0}).Clone()
+	pc.Cue(pat, rnd)
+	noise := 0
+	for i := range pat.Len() {
+		if pat.Float1D(i) > 0 && dt.Columns[1].Float1D(i) == 0 {
+			noise++
+		}
+	}
+	if nOn(pat) != 10 || noise != 5 {
+		t.Errorf("Corrupt: on: %d, noise: %d", nOn(pat), noise)
+	}
+
+	pc.Corrupt = false
+	st, err := pc.PartialCueSeries(dt, "Input", []float32{1, 0.5}, rnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Rows != 4 || st.Columns[0].String1D(3) != "ab_1_cue50" {
+		t.Fatalf("series rows: %d, name: %s", st.Rows, st.Columns[0].String1D(3))
+	}
+	fc, _ := st.ColumnByName("CueFrac")
+	if fc.Float1D(0) != 1 || fc.Float1D(3) != 0.5 || nOn(st.Columns[1].SubSpace([]int{3})) != 5 || nOn(st.Columns[1].SubSpace([]int{1})) != 10 {
+		t.Errorf("series CueFrac or cue bits wrong")
+	}
+	if _, err := pc.PartialCueSeries(dt, "NoSuch", []float32{1}, rnd); err == nil {
+		t.Error("expected error for missing column")
+	}
+}
@@ -5,8 +5,10 @@
 package leabra
 
 import (
-	"cogentcore.org/core/math32"
+	"log"
+
 	"cogentcore.org/core/tensor"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 // note: path.go contains algorithm methods; pathbase.go has infrastructure.
@@ -48,9 +50,8 @@ func (pt *Path) SetScalesRPool(scales tensor.Tensor) {
 					for ci := 0; ci < nc; ci++ {
 						// si := int(pj.RConIndex[st+ci]) // could verify coords etc
 						rsi := pt.RSynIndex[st+ci]
-						sy := &pt.Syns[rsi]
 						sc := scales.Float1D(scst + ci)
-						sy.Scale = float32(sc)
+						pt.Syns.Scale[rsi] = Float(sc)
 					}
 				}
 			}
@@ -72,9 +73,8 @@ func (pt *Path) SetWtsFunc(wtFun func(si, ri int, send, recv *tensor.Shape) floa
 			si := int(pt.RConIndex[st+ci])
 			wt := wtFun(si, ri, ssh, rsh)
 			rsi := pt.RSynIndex[st+ci]
-			sy := &pt.Syns[rsi]
-			sy.Wt = wt * sy.Scale
-			pt.Learn.LWtFromWt(sy)
+			pt.Syns.Wt[rsi] = Float(wt) * pt.Syns.Scale[rsi]
+			pt.LWtFromWt(int(rsi))
 		}
 	}
 }
@@ -93,45 +93,75 @@ func (pt *Path) SetScalesFunc(scaleFun func(si, ri int, send, recv *tensor.Shape
 			si := int(pt.RConIndex[st+ci])
 			sc := scaleFun(si, ri, ssh, rsh)
 			rsi := pt.RSynIndex[st+ci]
-			sy := &pt.Syns[rsi]
-			sy.Scale = sc
+			pt.Syns.Scale[rsi] = Float(sc)
 		}
 	}
 }
 
 // InitWeightsSyn initializes weight values based on WtInit randomness parameters
-// for an individual synapse.
+// for an individual synapse, at given synapse index.
 // It also updates the linear weight value based on the sigmoidal weight value.
-func (pt *Path) InitWeightsSyn(syn *Synapse) {
-	if syn.Scale == 0 {
-		syn.Scale = 1
+func (pt *Path) InitWeightsSyn(syni int) {
+	pt.InitWeightsSynValue(syni, Float(pt.WtInit.Gen(&pt.Send.Rand)))
+}
+
+// InitWeightsSynValue initializes the weight of the synapse at given index
+// to the given initial value (prior to Scale), clipped to the 0-1 range,
+// and the linear weight value based on it, and clears the learning state.
+func (pt *Path) InitWeightsSynValue(syni int, wt Float) {
+	sy := &pt.Syns
+	if sy.Scale[syni] == 0 {
+		sy.Scale[syni] = 1
 	}
-	syn.Wt = float32(pt.WtInit.Gen())
 	// enforce normalized weight range -- required for most uses and if not
 	// then a new type of path should be used:
-	if syn.Wt < 0 {
-		syn.Wt = 0
+	if wt < 0 {
+		wt = 0
 	}
-	if syn.Wt > 1 {
-		syn.Wt = 1
+	if wt > 1 {
+		wt = 1
 	}
-	syn.LWt = pt.Learn.WtSig.LinFromSigWt(syn.Wt)
-	syn.Wt *= syn.Scale // note: scale comes after so LWt is always "pure" non-scaled value
-	syn.DWt = 0
-	syn.Norm = 0
-	syn.Moment = 0
+	sy.LWt[syni] = pt.Learn.WtSig.LinFromSigWt(wt)
+	sy.Wt[syni] = wt * sy.Scale[syni] // note: scale comes after so LWt is always "pure" non-scaled value
+	sy.DWt[syni] = 0
+	sy.Norm[syni] = 0
+	sy.Moment[syni] = 0
+}
+
+// LWtFromWt updates the linear weight value based on the current
+// effective Wt value, for given synapse index.
+func (pt *Path) LWtFromWt(syni int) {
+	sy := &pt.Syns
+	sy.LWt[syni] = pt.Learn.WtSig.LinFromSigWt(sy.Wt[syni] / sy.Scale[syni]) // must factor out scale too!
+}
+
+// WtFromLWt updates the effective weight value based on the current
+// linear weight LWt value, for given synapse index.
+func (pt *Path) WtFromLWt(syni int) {
+	sy := &pt.Syns
+	sy.Wt[syni] = sy.Scale[syni] * pt.Learn.WtSig.SigFromLinWt(sy.LWt[syni])
 }
 
-// InitWeights initializes weight values according to Learn.WtInit params
+// InitWeights initializes weight values according to the WtInit params,
+// using the WtInit.Scheme weight initialization scheme.
 func (pt *Path) InitWeights() {
-	for si := range pt.Syns {
-		sy := &pt.Syns[si]
-		pt.InitWeightsSyn(sy)
+	wi, err := pt.WtInit.Initer()
+	if err != nil {
+		log.Printf("leabra.Path %s: %v: using Rand\n", pt.Name, err)
+		wi = WtInitSchemes["Rand"]
+	}
+	wts := make([]Float, pt.Syns.Len())
+	wi.InitWeights(pt, wts, &pt.Send.Rand)
+	for si, wt := range wts {
+		pt.InitWeightsSynValue(si, wt)
 	}
 	for wi := range pt.WbRecv {
 		wb := &pt.WbRecv[wi]
 		wb.Init()
 	}
+	pt.synScaleCtr = 0
+	pt.InitSTP()
+	pt.InitEWC()
 	pt.InitGInc()
 	pt.ClearTrace()
 }
@@ -145,8 +175,8 @@ func (pt *Path) InitWtSym(rpt *Path) {
 		nc := pt.SConN[si]
 		st := pt.SConIndexSt[si]
 		for ci := int32(0); ci < nc; ci++ {
-			sy := &pt.Syns[st+ci]
-			ri := pt.SConIndex[st+ci]
+			syi := st + ci
+			ri := pt.SConIndex[syi]
 			// now we need to find the reciprocal synapse on rpt!
 			// look in ri for sending connections
 			rsi := ri
@@ -166,7 +196,7 @@ func (pt *Path) InitWtSym(rpt *Path) {
 			// start at index proportional to si relative to rist
 			up := int32(0)
 			if ried > rist {
-				up = int32(float32(rsnc) * float32(si-rist) / float32(ried-rist))
+				up = int32(Float(rsnc) * Float(si-rist) / Float(ried-rist))
 			}
 			dn := up - 1
 
@@ -177,10 +207,9 @@ func (pt *Path) InitWtSym(rpt *Path) {
 					rrii := rsst + up
 					rri := rpt.SConIndex[rrii]
 					if rri == si {
-						rsy := &rpt.Syns[rrii]
-						rsy.Wt = sy.Wt
-						rsy.LWt = sy.LWt
-						rsy.Scale = sy.Scale
+						rpt.Syns.Wt[rrii] = pt.Syns.Wt[syi]
+						rpt.Syns.LWt[rrii] = pt.Syns.LWt[syi]
+						rpt.Syns.Scale[rrii] = pt.Syns.Scale[syi]
 						// note: if we support SymFromTop then can have option to go other way
 						break
 					}
@@ -191,10 +220,9 @@ func (pt *Path) InitWtSym(rpt *Path) {
 					rrii := rsst + dn
 					rri := rpt.SConIndex[rrii]
 					if rri == si {
-						rsy := &rpt.Syns[rrii]
-						rsy.Wt = sy.Wt
-						rsy.LWt = sy.LWt
-						rsy.Scale = sy.Scale
+						rpt.Syns.Wt[rrii] = pt.Syns.Wt[syi]
+						rpt.Syns.LWt[rrii] = pt.Syns.LWt[syi]
+						rpt.Syns.Scale[rrii] = pt.Syns.Scale[syi]
 						// note: if we support SymFromTop then can have option to go other way
 						break
 					}
@@ -216,6 +244,9 @@ func (pt *Path) InitGInc() {
 		pt.CtxtGeInc[ri] = 0
 		pt.GeRaw[ri] = 0
 	}
+	for si := range pt.STPSent {
+		pt.STPSent[si] = 0
+	}
 }
 
 //////////////////////////////////////////////////////////////////////////////////////
@@ -223,35 +254,40 @@ func (pt *Path) InitGInc() {
 
 // SendGDelta sends the delta-activation from sending neuron index si,
 // to integrate synaptic conductances on receivers
-func (pt *Path) SendGDelta(si int, delta float32) {
+func (pt *Path) SendGDelta(si int, delta Float) {
 	if pt.Type == CTCtxtPath {
 		return
 	}
 	scdel := delta * pt.GScale
 	nc := pt.SConN[si]
 	st := pt.SConIndexSt[si]
-	syns := pt.Syns[st : st+nc]
-	scons := pt.SConIndex[st : st+nc]
-	for ci := range syns {
-		ri := scons[ci]
-		pt.GInc[ri] += scdel * syns[ci].Wt
+	wts := pt.Syns.Wt[st : st+nc]
+	if pt.sconDense[si] {
+		axpy(pt.GInc[pt.SConIndex[st]:], scdel, wts)
+		return
 	}
+	axpyIndex(pt.GInc, pt.SConIndex[st:st+nc], scdel, wts)
 }
 
 // RecvGInc increments the receiver's GeRaw or GiRaw from that of all the pathways.
 func (pt *Path) RecvGInc() {
+	pt.recvGIncRange(0, len(pt.Recv.Neurons))
+}
+
+// recvGIncRange is RecvGInc for the given range of receiving neurons.
+func (pt *Path) recvGIncRange(st, ed int) {
 	rlay := pt.Recv
 	switch pt.Type {
 	case CTCtxtPath:
 		// nop
 	case InhibPath:
-		for ri := range rlay.Neurons {
+		for ri := st; ri < ed; ri++ {
 			rn := &rlay.Neurons[ri]
 			rn.GiRaw += pt.GInc[ri]
 			pt.GInc[ri] = 0
 		}
 	case GPiThalPath:
-		for ri := range rlay.Neurons {
+		for ri := st; ri < ed; ri++ {
 			rn := &rlay.Neurons[ri]
 			ginc := pt.GInc[ri]
 			pt.GeRaw[ri] += ginc
@@ -259,7 +295,7 @@ func (pt *Path) RecvGInc() {
 			pt.GInc[ri] = 0
 		}
 	default:
-		for ri := range rlay.Neurons {
+		for ri := st; ri < ed; ri++ {
 			rn := &rlay.Neurons[ri]
 			rn.GeRaw += pt.GInc[ri]
 			pt.GInc[ri] = 0
@@ -290,6 +326,8 @@ func (pt *Path) DWt() {
 		pt.DWtTDPred()
 	case pt.Type == DaHebbPath:
 		pt.DWtDaHebb()
+	case pt.Type == ValuePath:
+		pt.DWtValue()
 	default:
 		pt.DWtStd()
 	}
@@ -298,50 +336,67 @@ func (pt *Path) DWt() {
 // DWt computes the weight change (learning) -- on sending pathways
 func (pt *Path) DWtStd() {
 	slay := pt.Send
-	rlay := pt.Recv
+	ra := &pt.recvAvgs
+	ra.gather(pt)
+	enc, rec := pt.ThetaLrates()
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		if sn.AvgS < pt.Learn.XCal.LrnThr && sn.AvgM < pt.Learn.XCal.LrnThr {
 			continue
 		}
 		nc := int(pt.SConN[si])
+		if nc == 0 {
+			continue
+		}
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
-		for ci := range syns {
-			sy := &syns[ci]
-			ri := scons[ci]
-			rn := &rlay.Neurons[ri]
-			err, bcm := pt.Learn.CHLdWt(sn.AvgSLrn, sn.AvgM, rn.AvgSLrn, rn.AvgM, rn.AvgL)
-
-			bcm *= pt.Learn.XCal.LongLrate(rn.AvgLLrn)
-			err *= pt.Learn.XCal.MLrn
-			dwt := bcm + err
-			norm := float32(1)
-			if pt.Learn.Norm.On {
-				norm = pt.Learn.Norm.NormFromAbsDWt(&sy.Norm, math32.Abs(dwt))
-			}
-			if pt.Learn.Momentum.On {
-				dwt = norm * pt.Learn.Momentum.MomentFromDWt(&sy.Moment, dwt)
-			} else {
-				dwt *= norm
-			}
-			sy.DWt += pt.Learn.Lrate * dwt
+		r0 := int(scons[0])
+		if pt.sconDense[si] {
+			scons = nil
 		}
-		// aggregate max DWtNorm over sending synapses
-		if pt.Learn.Norm.On {
-			maxNorm := float32(0)
-			for ci := range syns {
-				sy := &syns[ci]
-				if sy.Norm > maxNorm {
-					maxNorm = sy.Norm
-				}
-			}
-			for ci := range syns {
-				sy := &syns[ci]
-				sy.Norm = maxNorm
-			}
+		pt.dwtXCal(sn.AvgSLrn, sn.AvgM, enc, rec, ra, r0, scons, pt.Syns.DWt[st:st+nc], pt.Syns.Norm[st:st+nc], pt.Syns.Moment[st:st+nc])
+	}
+}
+
+// dwtXCal is the XCAL DWt inner loop over the synapses of one sending
+// neuron, with the given sending neuron learning averages.  If scons is
+// nil, the receiving neuron indexes are contiguous starting at r0, else
+// they are given by scons.  enc and rec multiply the short (plus phase)
+// and medium (minus phase) coproducts of the error-driven term, for the
+// encoding and retrieval quarters of the theta cycle (see ThetaLrateParams).
+func (pt *Path) dwtXCal(suAvgSLrn, suAvgM, enc, rec Float, ra *recvLearnAvgs, r0 int, scons []int32, dwts, norms, moments []Float) {
+	ls := &pt.Learn
+	theta := enc != 1 || rec != 1
+	n := len(dwts)
+	norms = norms[:n]
+	moments = moments[:n]
+	for ci := range dwts {
+		ri := r0 + ci
+		if scons != nil {
+			ri = int(scons[ci])
+		}
+		err, bcm := ls.CHLdWt(suAvgSLrn, suAvgM, ra.AvgSLrn[ri], ra.AvgM[ri], ra.AvgL[ri])
+		if theta {
+			err = ls.XCal.DWt(enc*suAvgSLrn*ra.AvgSLrn[ri], rec*suAvgM*ra.AvgM[ri])
 		}
+
+		bcm *= ra.LongLrate[ri]
+		err *= ls.XCal.MLrn
+		dwt := bcm + err
+		norm := Float(1)
+		if ls.Norm.On {
+			norm = ls.Norm.NormFromAbsDWt(&norms[ci], fmath.Abs(dwt))
+		}
+		if ls.Momentum.On {
+			dwt = norm * ls.Momentum.MomentFromDWt(&moments[ci], dwt)
+		} else {
+			dwt *= norm
+		}
+		dwts[ci] += ls.Lrate * dwt
+	}
+	// aggregate max DWtNorm over sending synapses
+	if ls.Norm.On {
+		setValue(norms, maxValue(norms))
 	}
 }
 
@@ -351,21 +406,29 @@ func (pt *Path) WtFromDWt() {
 		return
 	}
 	switch pt.Type {
-	case RWPath, TDPredPath:
+	case RWPath, TDPredPath, ValuePath:
 		pt.WtFromDWtLinear()
 		return
 	}
+	pt.DWtEWC()
+	sy := &pt.Syns
+	dwts, wts, lwts, scales := sy.DWt, sy.Wt[:len(sy.DWt)], sy.LWt[:len(sy.DWt)], sy.Scale[:len(sy.DWt)]
 	if pt.Learn.WtBal.On {
-		for si := range pt.Syns {
-			sy := &pt.Syns[si]
+		for si := range dwts {
 			ri := pt.SConIndex[si]
 			wb := &pt.WbRecv[ri]
-			pt.Learn.WtFromDWt(wb.Inc, wb.Dec, &sy.DWt, &sy.Wt, &sy.LWt, sy.Scale)
+			pt.Learn.WtFromDWt(wb.Inc, wb.Dec, &dwts[si], &wts[si], &lwts[si], scales[si])
 		}
 	} else {
-		for si := range pt.Syns {
-			sy := &pt.Syns[si]
-			pt.Learn.WtFromDWt(1, 1, &sy.DWt, &sy.Wt, &sy.LWt, sy.Scale)
+		for si := range dwts {
+			pt.Learn.WtFromDWt(1, 1, &dwts[si], &wts[si], &lwts[si], scales[si])
+		}
+	}
+	if ss := &pt.Learn.SynScale; ss.On && ss.Interval > 0 {
+		pt.synScaleCtr++
+		if pt.synScaleCtr >= ss.Interval {
+			pt.synScaleCtr = 0
+			pt.SynScale()
 		}
 	}
 }
@@ -373,12 +436,13 @@ func (pt *Path) WtFromDWt() {
 // WtFromDWtLinear updates the synaptic weight values from delta-weight
 // changes, with no constraints or limits
 func (pt *Path) WtFromDWtLinear() {
-	for si := range pt.Syns {
-		sy := &pt.Syns[si]
-		if sy.DWt != 0 {
-			sy.Wt += sy.DWt // straight update, no limits or anything
-			sy.LWt = sy.Wt
-			sy.DWt = 0
+	sy := &pt.Syns
+	dwts, wts, lwts := sy.DWt, sy.Wt[:len(sy.DWt)], sy.LWt[:len(sy.DWt)]
+	for si, dwt := range dwts {
+		if dwt != 0 {
+			wts[si] += dwt // straight update, no limits or anything
+			lwts[si] = wts[si]
+			dwts[si] = 0
 		}
 	}
 }
@@ -401,18 +465,17 @@ func (pt *Path) WtBalFromWt() {
 		wb := &pt.WbRecv[ri]
 		st := int(pt.RConIndexSt[ri])
 		rsidxs := pt.RSynIndex[st : st+nc]
-		sumWt := float32(0)
+		sumWt := Float(0)
 		sumN := 0
 		for ci := range rsidxs {
-			rsi := rsidxs[ci]
-			sy := &pt.Syns[rsi]
-			if sy.Wt >= pt.Learn.WtBal.AvgThr {
-				sumWt += sy.Wt
+			wt := pt.Syns.Wt[rsidxs[ci]]
+			if wt >= pt.Learn.WtBal.AvgThr {
+				sumWt += wt
 				sumN++
 			}
 		}
 		if sumN > 0 {
-			sumWt /= float32(sumN)
+			sumWt /= Float(sumN)
 		} else {
 			sumWt = 0
 		}
@@ -421,10 +484,55 @@ func (pt *Path) WtBalFromWt() {
 	}
 }
 
+// SynScale applies homeostatic synaptic scaling (Learn.SynScale) to the
+// weights of each receiving neuron, multiplying its linear weights by
+// a factor that moves its average weight or activity toward the target.
+func (pt *Path) SynScale() {
+	ss := &pt.Learn.SynScale
+	if !pt.Learn.Learn || !ss.On {
+		return
+	}
+	rlay := pt.Recv
+	actTarg := ss.ActTarg
+	if actTarg == 0 {
+		actTarg = rlay.Inhib.ActAvg.Init
+	}
+	sy := &pt.Syns
+	for ri := range rlay.Neurons {
+		nc := int(pt.RConN[ri])
+		if nc < 1 || rlay.Neurons[ri].IsOff() {
+			continue
+		}
+		st := int(pt.RConIndexSt[ri])
+		rsidxs := pt.RSynIndex[st : st+nc]
+		var f Float
+		if ss.ByAct {
+			f = ss.Factor(rlay.Neurons[ri].ActAvg, actTarg)
+		} else {
+			sum := Float(0)
+			for _, rsi := range rsidxs {
+				sum += sy.LWt[rsi]
+			}
+			f = ss.Factor(sum/Float(nc), ss.WtTarg)
+		}
+		if f == 1 {
+			continue
+		}
+		for _, rsi := range rsidxs {
+			lw := min(max(sy.LWt[rsi]*f, 0), 1)
+			sy.LWt[rsi] = lw
+			sy.Wt[rsi] = sy.Scale[rsi] * pt.Learn.WtSig.SigFromLinWt(lw)
+		}
+	}
+}
+
 // LrateMult sets the new Lrate parameter for Paths to LrateInit * mult.
 // Useful for implementing learning rate schedules.
-func (pt *Path) LrateMult(mult float32) {
+// LrateInit is not changed by UpdateParams while Lrate keeps this value.
+func (pt *Path) LrateMult(mult Float) {
 	pt.Learn.Lrate = pt.Learn.LrateInit * mult
+	pt.lrateSet = pt.Learn.Lrate
+	pt.lrateMult = true
 }
 
 ///////////////////////////////////////////////////////////////////////
@@ -435,16 +543,16 @@ func (pt *Path) LrateMult(mult float32) {
 type WtBalRecvPath struct {
 
 	// average of effective weight values that exceed WtBal.AvgThr across given Recv Neuron's connections for given Path
-	Avg float32
+	Avg Float
 
 	// overall weight balance factor that drives changes in WbInc vs. WbDec via a sigmoidal function -- this is the net strength of weight balance changes
-	Fact float32
+	Fact Float
 
 	// weight balance increment factor -- extra multiplier to add to weight increases to maintain overall weight balance
-	Inc float32
+	Inc Float
 
 	// weight balance decrement factor -- extra multiplier to add to weight decreases to maintain overall weight balance
-	Dec float32
+	Dec Float
 }
 
 func (wb *WtBalRecvPath) Init() {
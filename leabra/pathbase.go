@@ -5,6 +5,7 @@
 package leabra
 
 import (
+	"cogentcore.org/core/math32"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,7 +15,6 @@ import (
 	"strings"
 
 	"cogentcore.org/core/base/indent"
-	"cogentcore.org/core/math32"
 	"cogentcore.org/core/math32/minmax"
 	"cogentcore.org/core/tensor"
 	"github.com/emer/emergent/v2/emer"
@@ -60,29 +60,62 @@ type Path struct {
 	// special parameters for matrix trace learning
 	Trace TraceParams `display:"inline"`
 
+	// STP has the parameters for short-term synaptic plasticity
+	// (depression and facilitation) of the sending efficacy.
+	STP STPParams `display:"inline"`
+
+	// EWC has the parameters for elastic weight consolidation,
+	// protecting the synapses important for previous learning.
+	EWC EWCParams `display:"inline"`
+
 	// synaptic state values, ordered by the sending layer
 	// units which owns them -- one-to-one with SConIndex array.
-	Syns []Synapse
+	// Stored in structure-of-arrays form, with a slice per variable.
+	Syns Synapses `display:"-"`
+
+	// original Scale values of synapses lesioned by LesionSyns,
+	// by synapse index, for restoring in UnLesionSyns.
+	lesionScales map[int]Float
 
 	// scaling factor for integrating synaptic input conductances (G's).
 	// computed in AlphaCycInit, incorporates running-average activity levels.
-	GScale float32
+	GScale Float
 
 	// local per-recv unit increment accumulator for synaptic
 	// conductance from sending units. goes to either GeRaw or GiRaw
 	// on neuron depending on pathway type.
-	GInc []float32
+	GInc []Float
 
 	// CtxtGeInc is local per-recv unit accumulator for Ctxt excitatory
 	// conductance from sending units, Not a delta, the full value.
-	CtxtGeInc []float32
+	CtxtGeInc []Float
 
 	// per-recv, per-path raw excitatory input, for GPiThalPath.
-	GeRaw []float32
+	GeRaw []Float
+
+	// STPu is the short-term plasticity release probability of each
+	// sending neuron, shared by all of its synapses, only when STP.On.
+	STPu []Float `display:"-"`
+
+	// STPx is the short-term plasticity proportion of available resources
+	// of each sending neuron, shared by all of its synapses, only when STP.On.
+	STPx []Float `display:"-"`
+
+	// STPSent is the last activation times STP efficacy sent by each
+	// sending neuron, for delta-coding the sending when STP.On.
+	STPSent []Float `display:"-"`
+
+	// LearnProg has learning progress statistics, when
+	// Network.RecLearnProgress is on.  See LogAddLearnProgressItems.
+	LearnProg LearnProgress `edit:"-" display:"inline"`
 
 	// weight balance state variables for this pathway, one per recv neuron.
 	WbRecv []WtBalRecvPath
 
+	// synScaleCtr counts the weight updates since the last
+	// Learn.SynScale update, for its Interval.
+	synScaleCtr int
+
 	// number of recv connections for each neuron in the receiving layer,
 	// as a flat list.
 	RConN []int32 `display:"-"`
@@ -122,6 +155,22 @@ type Path struct {
 	// outer loop (each start is in ConIndexSt), and then
 	// by the sending layer's units within that.
 	SConIndex []int32 `display:"-"`
+
+	// sconDense is true for each sending neuron whose receiving neuron
+	// indexes in SConIndex are contiguous, enabling dense inner loops.
+	sconDense []bool
+
+	// recvAvgs are the receiving neuron learning averages
+	// gathered for computing DWt.
+	recvAvgs recvLearnAvgs
+
+	// lrateSet is the Lrate last set by LrateMult, if lrateMult,
+	// which UpdateParams does not copy to LrateInit, so that
+	// learning rate schedules keep their initial learning rate.
+	lrateSet Float
+
+	// lrateMult is true if Lrate has been set by LrateMult.
+	lrateMult bool
 }
 
 // emer.Path interface
@@ -138,6 +187,8 @@ func (pt *Path) Defaults() {
 	pt.Learn.Defaults()
 	pt.CHL.Defaults()
 	pt.Trace.Defaults()
+	pt.STP.Defaults()
+	pt.EWC.Defaults()
 	pt.GScale = 1
 	pt.DefaultsForType()
 }
@@ -156,6 +207,8 @@ func (pt *Path) DefaultsForType() {
 		pt.MatrixDefaults()
 	case DaHebbPath:
 		pt.DaHebbDefaults()
+	case ValuePath:
+		pt.ValueDefaults()
 	}
 }
 
@@ -163,12 +216,16 @@ func (pt *Path) DefaultsForType() {
 func (pt *Path) UpdateParams() {
 	pt.WtScale.Update()
 	pt.Learn.Update()
-	pt.Learn.LrateInit = pt.Learn.Lrate
+	if !pt.lrateMult || pt.Learn.Lrate != pt.lrateSet {
+		pt.Learn.LrateInit = pt.Learn.Lrate
+	}
 	if pt.Type == CHLPath && pt.CHL.On {
 		pt.Learn.WtSig.SoftBound = false
 	}
 	pt.CHL.Update()
 	pt.Trace.Update()
+	pt.STP.Update()
+	pt.EWC.Update()
 }
 
 func (pt *Path) ShouldDisplay(field string) bool {
@@ -192,6 +249,14 @@ func (pt *Path) AllParams() string {
 	str += "WtScale: {\n " + JsonToParams(b)
 	b, _ = json.MarshalIndent(&pt.Learn, "", " ")
 	str += "Learn: {\n " + strings.Replace(JsonToParams(b), " XCal: {", "\n  XCal: {", -1)
+	if pt.STP.On {
+		b, _ = json.MarshalIndent(&pt.STP, "", " ")
+		str += "STP: {\n " + JsonToParams(b)
+	}
+	if pt.EWC.On {
+		b, _ = json.MarshalIndent(&pt.EWC, "", " ")
+		str += "EWC: {\n " + JsonToParams(b)
+	}
 	return str
 }
 
@@ -237,7 +302,7 @@ func (pt *Path) SynVarNum() int {
 // This is the max idx for SynValue1D
 // and the number of vals set by SynValues.
 func (pt *Path) NumSyns() int {
-	return len(pt.Syns)
+	return pt.Syns.Len()
 }
 
 // SynVal1D returns value of given variable index (from SynVarIndex)
@@ -245,14 +310,19 @@ func (pt *Path) NumSyns() int {
 // This is the core synapse var access method used by other methods,
 // so it is the only one that needs to be updated for derived layer types.
 func (pt *Path) SynValue1D(varIndex int, synIndex int) float32 {
-	if synIndex < 0 || synIndex >= len(pt.Syns) {
+	if synIndex < 0 || synIndex >= pt.Syns.Len() {
 		return math32.NaN()
 	}
 	if varIndex < 0 || varIndex >= pt.SynVarNum() {
 		return math32.NaN()
 	}
-	sy := &pt.Syns[synIndex]
-	return sy.VarByIndex(varIndex)
+	if svs, ok := pt.stpValues(varIndex); ok {
+		if len(svs) == 0 {
+			return float32(pt.stpRest(varIndex))
+		}
+		return float32(svs[pt.synSendIndex(synIndex)])
+	}
+	return float32(pt.Syns.VarByIndex(varIndex, synIndex))
 }
 
 // SynValues sets values of given variable name for each synapse,
@@ -264,14 +334,32 @@ func (pt *Path) SynValues(vals *[]float32, varNm string) error {
 	if err != nil {
 		return err
 	}
-	ns := len(pt.Syns)
+	ns := pt.Syns.Len()
 	if *vals == nil || cap(*vals) < ns {
 		*vals = make([]float32, ns)
 	} else if len(*vals) < ns {
 		*vals = (*vals)[0:ns]
 	}
-	for i := range pt.Syns {
-		(*vals)[i] = pt.SynValue1D(vidx, i)
+	if svs, ok := pt.stpValues(vidx); ok {
+		for si, nc := range pt.SConN {
+			v := pt.stpRest(vidx)
+			if len(svs) > 0 {
+				v = svs[si]
+			}
+			st := pt.SConIndexSt[si]
+			for i := range nc {
+				(*vals)[st+i] = float32(v)
+			}
+		}
+		return nil
+	}
+	svs := pt.Syns.Values(vidx)
+	if len(svs) == 0 { // not allocated, e.g., EWC vars when not EWC.On
+		clear((*vals)[:ns])
+		return nil
+	}
+	for i, v := range svs {
+		(*vals)[i] = float32(v)
 	}
 	return nil
 }
@@ -297,13 +385,18 @@ func (pt *Path) SetSynValue(varNm string, sidx, ridx int, val float32) error {
 		return err
 	}
 	synIndex := pt.SynIndex(sidx, ridx)
-	if synIndex < 0 || synIndex >= len(pt.Syns) {
+	if synIndex < 0 || synIndex >= pt.Syns.Len() {
 		return err
 	}
-	sy := &pt.Syns[synIndex]
-	sy.SetVarByIndex(vidx, val)
+	if svs, ok := pt.stpValues(vidx); ok {
+		if len(svs) > 0 {
+			svs[sidx] = Float(val)
+		}
+		return nil
+	}
+	pt.Syns.SetVarByIndex(vidx, synIndex, Float(val))
 	if varNm == "Wt" {
-		pt.Learn.LWtFromWt(sy)
+		pt.LWtFromWt(synIndex)
 	}
 	return nil
 }
@@ -360,8 +453,7 @@ func (pt *Path) WriteWeightsJSON(w io.Writer, depth int) {
 		w.Write([]byte("\"Wt\": [ "))
 		for ci := 0; ci < nc; ci++ {
 			rsi := pt.RSynIndex[st+ci]
-			sy := &pt.Syns[rsi]
-			w.Write([]byte(strconv.FormatFloat(float64(sy.Wt), 'g', weights.Prec, 32)))
+			w.Write([]byte(strconv.FormatFloat(float64(pt.Syns.Wt[rsi]), 'g', weights.Prec, 32)))
 			if ci == nc-1 {
 				w.Write([]byte(" "))
 			} else {
@@ -390,7 +482,7 @@ func (pt *Path) SetWeights(pw *weights.Path) error {
 	if pw.MetaData != nil {
 		if gs, ok := pw.MetaData["GScale"]; ok {
 			pv, _ := strconv.ParseFloat(gs, 32)
-			pt.GScale = float32(pv)
+			pt.GScale = Float(pv)
 		}
 	}
 	var err error
@@ -428,6 +520,9 @@ func (pt *Path) Validate(logmsg bool) error {
 	if pt.Send == nil {
 		emsg += "Send is nil; "
 	}
+	if _, err := pt.WtInit.Initer(); err != nil {
+		emsg += err.Error() + "; "
+	}
 	if emsg != "" {
 		err := errors.New(emsg)
 		if logmsg {
@@ -496,10 +591,15 @@ func (pt *Path) Build() error {
 			rci++
 		}
 	}
-	pt.Syns = make([]Synapse, len(pt.SConIndex))
-	pt.GInc = make([]float32, rlen)
-	pt.CtxtGeInc = make([]float32, rlen)
-	pt.GeRaw = make([]float32, rlen)
+	pt.Syns.SetLen(len(pt.SConIndex))
+	pt.sconDense = make([]bool, slen)
+	for si := range slen {
+		st := pt.SConIndexSt[si]
+		pt.sconDense[si] = isDenseIndexes(pt.SConIndex[st : st+pt.SConN[si]])
+	}
+	pt.GInc = make([]Float, rlen)
+	pt.CtxtGeInc = make([]Float, rlen)
+	pt.GeRaw = make([]Float, rlen)
 	pt.WbRecv = make([]WtBalRecvPath, rlen)
 	return nil
 }
@@ -0,0 +1,194 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"reflect"
+	"slices"
+
+	"cogentcore.org/core/math32/minmax"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// PathStatsBins is the number of bins of the PathStats WtHist
+// weight histogram, over the [0, 1] range.
+var PathStatsBins = 10
+
+// PathStats are summary statistics of the current weights of a pathway,
+// computed on demand by Path.Stats, for diagnosing learning problems
+// without having to export the full weights, e.g., weights that are
+// stuck at their initial values or saturated at a bound.
+// The weight change norms are those of the LearnProgress, computed over
+// the weight updates up to its last ComputeLearnProgress.
+type PathStats struct {
+
+	// WtMean is the mean of the effective weights Wt.
+	WtMean Float
+
+	// WtVar is the variance of the effective weights Wt.
+	WtVar Float
+
+	// WtMin is the minimum effective weight Wt.
+	WtMin Float
+
+	// WtMax is the maximum effective weight Wt.
+	WtMax Float
+
+	// WtHist is the histogram of the effective weights Wt, as the
+	// fraction of synapses in each of PathStatsBins bins over the
+	// [0, 1] range, with values outside the range in the end bins.
+	WtHist []Float
+
+	// SatMin is the fraction of synapses whose linear weight LWt is
+	// within LearnSatTol of its 0 lower bound.
+	SatMin Float
+
+	// SatMax is the fraction of synapses whose linear weight LWt is
+	// within LearnSatTol of its 1 upper bound.
+	SatMax Float
+
+	// DWtNorm is the LearnProgress DWtNorm: the root-mean-square DWt
+	// weight change per synapse and update.
+	DWtNorm Float
+
+	// WtDeltaNorm is the LearnProgress WtDeltaNorm: the root-mean-square
+	// net change in Wt per synapse.
+	WtDeltaNorm Float
+
+	// WtBalAvg is the mean over receiving units of the average weight
+	// used for weight balance (WtBalRecvPath.Avg), if WtBal is on.
+	WtBalAvg Float
+
+	// WtBalFact is the mean over receiving units of the weight balance
+	// factor (WtBalRecvPath.Fact), which is 0 when the average weights
+	// are within the balanced range, if WtBal is on.
+	WtBalFact Float
+
+	// WtBalInc is the mean over receiving units of the weight balance
+	// increment factor (WtBalRecvPath.Inc), which is > 1 when weight
+	// increases are boosted (weights too low) and < 1 when decreases
+	// are (weights too high), and 1 if WtBal is off.
+	WtBalInc Float
+}
+
+// Stats returns the PathStats summary statistics of the current weights.
+func (pt *Path) Stats() PathStats {
+	ps := PathStats{WtHist: make([]Float, PathStatsBins), DWtNorm: pt.LearnProg.DWtNorm, WtDeltaNorm: pt.LearnProg.WtDeltaNorm, WtBalInc: 1}
+	ns := pt.Syns.Len()
+	if ns == 0 {
+		return ps
+	}
+	sy := &pt.Syns
+	ps.WtMin, ps.WtMax = sy.Wt[0], sy.Wt[0]
+	sum, ss := 0.0, 0.0
+	for _, wt := range sy.Wt {
+		sum += float64(wt)
+		ss += float64(wt) * float64(wt)
+		ps.WtMin = min(ps.WtMin, wt)
+		ps.WtMax = max(ps.WtMax, wt)
+		bi := min(max(int(math.Floor(float64(wt)*float64(PathStatsBins))), 0), PathStatsBins-1)
+		ps.WtHist[bi]++
+	}
+	mean := sum / float64(ns)
+	ps.WtMean = Float(mean)
+	ps.WtVar = Float(max(ss/float64(ns)-mean*mean, 0))
+	for bi := range ps.WtHist {
+		ps.WtHist[bi] /= Float(ns)
+	}
+	nmin, nmax := 0, 0
+	for _, lwt := range sy.LWt {
+		switch {
+		case lwt <= LearnSatTol:
+			nmin++
+		case lwt >= 1-LearnSatTol:
+			nmax++
+		}
+	}
+	ps.SatMin = Float(nmin) / Float(ns)
+	ps.SatMax = Float(nmax) / Float(ns)
+	if pt.Learn.WtBal.On && len(pt.WbRecv) > 0 {
+		ps.WtBalInc = 0
+		for _, wb := range pt.WbRecv {
+			ps.WtBalAvg += wb.Avg
+			ps.WtBalFact += wb.Fact
+			ps.WtBalInc += wb.Inc
+		}
+		nr := Float(len(pt.WbRecv))
+		ps.WtBalAvg /= nr
+		ps.WtBalFact /= nr
+		ps.WtBalInc /= nr
+	}
+	return ps
+}
+
+// LogAddPathStatsItems adds the PathStats of the given pathways (all the
+// learning pathways if none) to the given logs at the given mode, across
+// the given time levels, in higher to lower order, e.g., Run, Epoch, with
+// names <path>_WtMean, _WtVar, _SatMin, _SatMax and _WtBalInc, which are
+// computed at the lowest time level and aggregated over the higher times,
+// and the <path>_WtHist weight histogram, at the lowest time level only.
+// The DWtNorm and WtDeltaNorm are logged by LogAddLearnProgressItems,
+// which is called for the same mode and times if not already done.
+func LogAddPathStatsItems(lg *elog.Logs, net *Network, pathNames []string, mode etime.Modes, times ...etime.Times) {
+	if !net.RecLearnProgress {
+		LogAddLearnProgressItems(lg, net, mode, times...)
+	}
+	ntimes := len(times)
+	sk := etime.Scope(mode, times[ntimes-1])
+	for _, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			if len(pathNames) == 0 && !pt.Learn.Learn {
+				continue
+			}
+			if len(pathNames) > 0 && !slices.Contains(pathNames, pt.Name) {
+				continue
+			}
+			cpt := pt
+			var ps PathStats
+			stats := []struct {
+				name string
+				val  func() Float
+				max  float32
+			}{ // WtMean is written first, so it computes the stats
+				{"WtMean", func() Float { ps = cpt.Stats(); return ps.WtMean }, 1},
+				{"WtVar", func() Float { return ps.WtVar }, 0.1},
+				{"SatMin", func() Float { return ps.SatMin }, 1},
+				{"SatMax", func() Float { return ps.SatMax }, 1},
+				{"WtBalInc", func() Float { return ps.WtBalInc }, 2},
+			}
+			for _, st := range stats {
+				val := st.val
+				itm := lg.AddItem(&elog.Item{
+					Name:  cpt.Name + "_" + st.name,
+					Type:  reflect.Float64,
+					Range: minmax.F32{Max: st.max},
+					Write: elog.WriteMap{
+						sk: func(ctx *elog.Context) {
+							ctx.SetFloat64(float64(val()))
+						}}})
+				lg.AddStdAggs(itm, mode, times...)
+			}
+			hist := tensor.NewFloat64([]int{PathStatsBins})
+			lg.AddItem(&elog.Item{
+				Name:      cpt.Name + "_WtHist",
+				Type:      reflect.Float64,
+				CellShape: []int{PathStatsBins},
+				DimNames:  []string{"Bin"},
+				FixMin:    true,
+				FixMax:    true,
+				Range:     minmax.F32{Max: 1},
+				Write: elog.WriteMap{
+					sk: func(ctx *elog.Context) {
+						for bi, f := range ps.WtHist {
+							hist.Values[bi] = float64(f)
+						}
+						ctx.SetTensor(hist)
+					}}})
+		}
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"github.com/emer/emergent/v2/elog"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+)
+
+func TestPathStats(t *testing.T) {
+	net := MakeTestNet(t)
+	hid := net.LayerByName("Hidden")
+	pti, _ := hid.RecvPathBySendName("Input")
+	pt := pti.(*Path)
+	copy(pt.Syns.Wt, []Float{0.05, 0.25, 0.25, 0.95})
+	copy(pt.Syns.LWt, []Float{0, 0.5, 0.5, 1})
+	ps := pt.Stats()
+	if math.Abs(float64(ps.WtMean-0.375)) > 1e-6 || math.Abs(float64(ps.WtVar-0.116875)) > 1e-6 || ps.WtMin != 0.05 || ps.WtMax != 0.95 {
+		t.Errorf("weight stats wrong: %+v", ps)
+	}
+	if len(ps.WtHist) != PathStatsBins || ps.WtHist[0] != 0.25 || ps.WtHist[2] != 0.5 || ps.WtHist[9] != 0.25 {
+		t.Errorf("weight histogram wrong: %v", ps.WtHist)
+	}
+	if ps.SatMin != 0.25 || ps.SatMax != 0.25 {
+		t.Errorf("saturation = %g, %g, not 0.25, 0.25", ps.SatMin, ps.SatMax)
+	}
+	if ps.WtBalInc != 1 || pt.Learn.WtBal.On {
+		t.Errorf("WtBalInc = %g with WtBal off, not 1", ps.WtBalInc)
+	}
+
+	var lg elog.Logs
+	var st estats.Stats
+	st.Init()
+	LogAddPathStatsItems(&lg, net, []string{pt.Name}, etime.Train, etime.Run, etime.Epoch)
+	if !net.RecLearnProgress {
+		t.Errorf("LearnProgress items not added")
+	}
+	lg.CreateTables()
+	lg.SetContext(&st, net)
+	lg.LogRow(etime.Train, etime.Epoch, 0)
+	dt := lg.Table(etime.Train, etime.Epoch)
+	if v := dt.Float(pt.Name+"_WtMean", 0); math.Abs(v-0.375) > 1e-6 {
+		t.Errorf("logged WtMean = %g, not 0.375", v)
+	}
+	if v := dt.Float(pt.Name+"_SatMax", 0); v != 0.25 {
+		t.Errorf("logged SatMax = %g, not 0.25", v)
+	}
+	hc, err := dt.ColumnByName(pt.Name + "_WtHist")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := hc.Float([]int{0, 2}); v != 0.5 {
+		t.Errorf("logged histogram bin 2 = %g, not 0.5", v)
+	}
+	if _, err := dt.ColumnIndex("OutputToHidden_WtMean"); err == nil {
+		t.Errorf("stats logged for a path not listed")
+	}
+}
@@ -74,4 +74,9 @@ const (
 	// DaHebbPath does dopamine-modulated Hebbian learning -- i.e., the 3-factor
 	// learning rule: Da * Recv.Act * Send.Act
 	DaHebbPath
+
+	// ValuePath does dopamine-modulated learning for a [ValueLayer]:
+	// DWt = Da * Send.ActQ0 (or Send.Act), with the sign of Da reversed
+	// for EffortCost layers.  Has no weight bounds or limits on sign etc.
+	ValuePath
 )
@@ -0,0 +1,270 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"strconv"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/stats/metric"
+	"cogentcore.org/core/tensor/table"
+)
+
+// The pattern separation and completion analyses operate on tensors with
+// one pattern per row (outer-most dimension), such as the layer activity
+// columns of a trial log (e.g., ECin_ActM and DG_ActM), with the pattern
+// names from a string column (e.g., TrialName), and return tidy tables
+// with one row per pattern pair or pattern, for plotting and aggregation.
+// The Layer column identifies the layer, so the results for multiple
+// layers can be combined in one table.  Similarity is measured by
+// correlation, which is 0 for patterns with no variance (e.g., no activity).
+
+// PatternSeparation returns a table with the similarity of each pair of
+// patterns in the in tensor (e.g., ECin activity) and the corresponding
+// pair in the out tensor (e.g., DG activity), for the given layer name.
+// See AddPatternSeparation for the columns.
+func PatternSeparation(layer string, in, out, names tensor.Tensor) *table.Table {
+	dt := table.NewTable()
+	AddPatternSeparation(dt, layer, in, out, names)
+	return dt
+}
+
+// AddPatternSeparation adds rows to the given table for each pair of
+// patterns, with the columns (added if not already present):
+//   - Layer: the given layer name.
+//   - A, B: the names of the patterns (row indexes if names is nil).
+//   - InSim: the similarity of the input patterns.
+//   - OutSim: the similarity of the output patterns.
+//   - Separation: InSim - OutSim, which is positive when the output
+//     patterns are more separated than the inputs.
+func AddPatternSeparation(dt *table.Table, layer string, in, out, names tensor.Tensor) {
+	configPatTable(dt, []string{"Layer", "A", "B"}, []string{"InSim", "OutSim", "Separation"})
+	ins := patRows(in)
+	outs := patRows(out)
+	np := min(len(ins), len(outs))
+	for a := range np {
+		for b := a + 1; b < np; b++ {
+			isim := patSim(ins[a], ins[b])
+			osim := patSim(outs[a], outs[b])
+			row := dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetString("Layer", row, layer)
+			dt.SetString("A", row, patName(names, a))
+			dt.SetString("B", row, patName(names, b))
+			dt.SetFloat("InSim", row, isim)
+			dt.SetFloat("OutSim", row, osim)
+			dt.SetFloat("Separation", row, isim-osim)
+		}
+	}
+}
+
+// OrthogStats are summary orthogonalization indices for the pattern
+// pairs in a PatternSeparation table.
+type OrthogStats struct {
+
+	// NPairs is the number of pattern pairs.
+	NPairs int
+
+	// InSim is the mean similarity of the input pattern pairs.
+	InSim float64
+
+	// OutSim is the mean similarity of the output pattern pairs.
+	OutSim float64
+
+	// Index is the orthogonalization index: 1 - OutSim / InSim,
+	// which is 0 when the outputs are as similar as the inputs,
+	// and 1 when they are completely orthogonal.
+	// NaN if InSim <= 0.
+	Index float64
+
+	// Slope is the least-squares slope of OutSim as a function of InSim
+	// across pairs, which is less than 1 when more similar inputs are
+	// separated more.  NaN if there is no variance in InSim.
+	Slope float64
+}
+
+// OrthogIndex returns the OrthogStats for the rows of the given
+// PatternSeparation table for the given layer (all rows if empty).
+func OrthogIndex(sep *table.Table, layer string) OrthogStats {
+	var ot OrthogStats
+	var ins, outs []float64
+	for ri := range sep.Rows {
+		if layer != "" && sep.StringValue("Layer", ri) != layer {
+			continue
+		}
+		ins = append(ins, sep.Float("InSim", ri))
+		outs = append(outs, sep.Float("OutSim", ri))
+	}
+	ot.NPairs = len(ins)
+	if ot.NPairs == 0 {
+		ot.InSim, ot.OutSim, ot.Index, ot.Slope = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		return ot
+	}
+	n := float64(ot.NPairs)
+	for i := range ins {
+		ot.InSim += ins[i]
+		ot.OutSim += outs[i]
+	}
+	ot.InSim /= n
+	ot.OutSim /= n
+	ot.Index = math.NaN()
+	if ot.InSim > 0 {
+		ot.Index = 1 - ot.OutSim/ot.InSim
+	}
+	var cov, vr float64
+	for i := range ins {
+		d := ins[i] - ot.InSim
+		cov += d * (outs[i] - ot.OutSim)
+		vr += d * d
+	}
+	ot.Slope = math.NaN()
+	if vr > 0 {
+		ot.Slope = cov / vr
+	}
+	return ot
+}
+
+// PatternCompletion returns a table with the similarity of the cue
+// patterns (e.g., ECin activity from a partial cue) and the recalled
+// patterns (e.g., ECout activity) to the target patterns (the full
+// patterns to be recalled), for the given layer name.
+// See AddPatternCompletion for the columns.
+func PatternCompletion(layer string, cue, recall, target, names tensor.Tensor) *table.Table {
+	dt := table.NewTable()
+	AddPatternCompletion(dt, layer, cue, recall, target, names)
+	return dt
+}
+
+// AddPatternCompletion adds rows to the given table for each pattern,
+// with the columns (added if not already present):
+//   - Layer: the given layer name.
+//   - Name: the name of the pattern (row index if names is nil).
+//   - CueSim: the similarity of the cue to the target.
+//   - RecallSim: the similarity of the recalled pattern to the target.
+//   - Completion: (RecallSim - CueSim) / (1 - CueSim), the proportion
+//     of the missing similarity that was filled in by recall,
+//     which is 1 for perfect completion, 0 for none, and negative
+//     if recall is worse than the cue.  NaN if CueSim >= 1.
+func AddPatternCompletion(dt *table.Table, layer string, cue, recall, target, names tensor.Tensor) {
+	configPatTable(dt, []string{"Layer", "Name"}, []string{"CueSim", "RecallSim", "Completion"})
+	cues := patRows(cue)
+	recs := patRows(recall)
+	trgs := patRows(target)
+	np := min(len(cues), len(recs), len(trgs))
+	for i := range np {
+		csim := patSim(cues[i], trgs[i])
+		rsim := patSim(recs[i], trgs[i])
+		comp := math.NaN()
+		if csim < 1 {
+			comp = (rsim - csim) / (1 - csim)
+		}
+		row := dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetString("Layer", row, layer)
+		dt.SetString("Name", row, patName(names, i))
+		dt.SetFloat("CueSim", row, csim)
+		dt.SetFloat("RecallSim", row, rsim)
+		dt.SetFloat("Completion", row, comp)
+	}
+}
+
+// CompletionStats are the mean values of the columns in a
+// PatternCompletion table.
+type CompletionStats struct {
+
+	// N is the number of patterns.
+	N int
+
+	// CueSim is the mean similarity of the cues to the targets.
+	CueSim float64
+
+	// RecallSim is the mean similarity of the recalled patterns to the targets.
+	RecallSim float64
+
+	// Completion is the mean completion, over the patterns where it is defined.
+	Completion float64
+}
+
+// PatternCompletionStats returns the CompletionStats for the rows of the
+// given PatternCompletion table for the given layer (all rows if empty).
+func PatternCompletionStats(comp *table.Table, layer string) CompletionStats {
+	var cs CompletionStats
+	nc := 0
+	for ri := range comp.Rows {
+		if layer != "" && comp.StringValue("Layer", ri) != layer {
+			continue
+		}
+		cs.N++
+		cs.CueSim += comp.Float("CueSim", ri)
+		cs.RecallSim += comp.Float("RecallSim", ri)
+		if c := comp.Float("Completion", ri); !math.IsNaN(c) {
+			cs.Completion += c
+			nc++
+		}
+	}
+	if cs.N == 0 {
+		return CompletionStats{CueSim: math.NaN(), RecallSim: math.NaN(), Completion: math.NaN()}
+	}
+	cs.CueSim /= float64(cs.N)
+	cs.RecallSim /= float64(cs.N)
+	if nc > 0 {
+		cs.Completion /= float64(nc)
+	} else {
+		cs.Completion = math.NaN()
+	}
+	return cs
+}
+
+// configPatTable adds the given string and float columns to the table,
+// if not already present.
+func configPatTable(dt *table.Table, strs, flts []string) {
+	for _, nm := range strs {
+		if _, err := dt.ColumnByName(nm); err != nil {
+			dt.AddStringColumn(nm)
+		}
+	}
+	for _, nm := range flts {
+		if _, err := dt.ColumnByName(nm); err != nil {
+			dt.AddFloat64Column(nm)
+		}
+	}
+}
+
+// patRows returns the values of each row of the given tensor.
+func patRows(tsr tensor.Tensor) [][]float64 {
+	if tsr == nil {
+		return nil
+	}
+	rows, cells := tsr.RowCellSize()
+	pats := make([][]float64, rows)
+	for ri := range rows {
+		pat := make([]float64, cells)
+		for ci := range cells {
+			pat[ci] = tsr.Float1D(ri*cells + ci)
+		}
+		pats[ri] = pat
+	}
+	return pats
+}
+
+// patSim returns the correlation between the two patterns,
+// or 0 if either has no variance.
+func patSim(a, b []float64) float64 {
+	r := metric.Correlation64(a, b)
+	if math.IsNaN(r) {
+		return 0
+	}
+	return r
+}
+
+// patName returns the name for the given row from the names tensor,
+// or the row index if names is nil.
+func patName(names tensor.Tensor, row int) string {
+	if names == nil || row >= names.Len() {
+		return strconv.Itoa(row)
+	}
+	return names.String1D(row)
+}
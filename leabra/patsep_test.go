@@ -0,0 +1,46 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+)
+
+func TestPatternSeparation(t *testing.T) {
+	// in: pats a, b overlap, c is distinct; out: all distinct
+	in := tensor.NewFloat32([]int{3, 8})
+	out := tensor.NewFloat32([]int{3, 8})
+	for ri, on := range [][]int{{0, 1, 2}, {0, 1, 3}, {6, 7}} {
+		for _, i := range on {
+			in.Set([]int{ri, i}, 1)
+		}
+		out.Set([]int{ri, ri}, 1)
+	}
+	names := tensor.NewString([]int{3})
+	names.Values = []string{"a", "b", "c"}
+	sep := PatternSeparation("DG", in, out, names)
+	if sep.Rows != 3 {
+		t.Fatalf("PatternSeparation: %d rows, not 3", sep.Rows)
+	}
+	if a, b := sep.StringValue("A", 0), sep.StringValue("B", 0); a != "a" || b != "b" {
+		t.Errorf("PatternSeparation: first pair %s %s, not a b", a, b)
+	}
+	if sep.Float("InSim", 0) <= sep.Float("OutSim", 0) {
+		t.Errorf("PatternSeparation: overlapping inputs not separated")
+	}
+	ot := OrthogIndex(sep, "DG")
+	if ot.NPairs != 3 || ot.Slope >= 1 {
+		t.Errorf("OrthogIndex: %+v", ot)
+	}
+
+	comp := PatternCompletion("ECout", out, in, in, names)
+	cs := PatternCompletionStats(comp, "")
+	if cs.N != 3 || math32.Abs(float32(cs.Completion-1)) > difTol || cs.CueSim >= 1 {
+		t.Errorf("PatternCompletionStats: %+v", cs)
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// PavlovSchedules are the standard Pavlovian conditioning schedules
+// generated by PavlovParams, with conditioned stimuli (CS) labeled
+// A, B, C, D, and + for trials with the reward (US) and - without.
+type PavlovSchedules int32 //enums:enum
+
+const (
+	// PavlovAcquisition is AcqTrials of A+, followed by a test of A.
+	PavlovAcquisition PavlovSchedules = iota
+
+	// PavlovExtinction is AcqTrials of A+, followed by ExtTrials of A-,
+	// and a test of A.
+	PavlovExtinction
+
+	// PavlovBlocking is AcqTrials of A+, followed by BlockTrials each of
+	// AB+ and CD+ (alternating), and tests of B and D, where learning
+	// about B is blocked by the prediction of the reward by A, relative
+	// to the control D, which is learned together with the novel C.
+	PavlovBlocking
+)
+
+// PavlovParams are the parameters for a standard Pavlovian
+// conditioning schedule (see PavlovEnv).
+type PavlovParams struct {
+
+	// Schedule is the conditioning schedule.
+	Schedule PavlovSchedules
+
+	// NCS is the number of conditioned stimulus units in the CS pattern,
+	// which must be at least 4 for PavlovBlocking.
+	NCS int `default:"4" min:"1"`
+
+	// PRew is the probability of the reward on + trials,
+	// for partial reinforcement.
+	PRew float32 `default:"1" min:"0" max:"1"`
+
+	// AcqTrials is the number of acquisition trials of A+.
+	AcqTrials int `default:"20" min:"0"`
+
+	// ExtTrials is the number of extinction trials of A-.
+	ExtTrials int `default:"20" min:"0"`
+
+	// BlockTrials is the number of trials of each of the AB+ and CD+
+	// compounds in the second phase of blocking.
+	BlockTrials int `default:"20" min:"0"`
+
+	// TestTrials is the number of test trials of each tested CS,
+	// at the end of the schedule, which have no reward.
+	TestTrials int `default:"1" min:"0"`
+
+	// File is an optional TOML or JSON file of schedules (see
+	// SavePavlovSchedules), which is opened by OpenFile, and then
+	// replaces the built-in Schedule.
+	File string
+
+	// FileSchedule is the name of the schedule to use from the File,
+	// or the first one if empty.
+	FileSchedule string
+
+	// Groups are the trial groups of the schedule from the File,
+	// set by OpenFile, which are used instead of the built-in Schedule
+	// if non-empty.
+	Groups []PavlovGroup `display:"-"`
+}
+
+func (pp *PavlovParams) Update() {
+}
+
+func (pp *PavlovParams) Defaults() {
+	pp.NCS = 4
+	pp.PRew = 1
+	pp.AcqTrials = 20
+	pp.ExtTrials = 20
+	pp.BlockTrials = 20
+	pp.TestTrials = 1
+}
+
+func (pp *PavlovParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "ExtTrials":
+		return pp.Schedule == PavlovExtinction
+	case "BlockTrials":
+		return pp.Schedule == PavlovBlocking
+	case "FileSchedule":
+		return pp.File != ""
+	default:
+		return true
+	}
+}
+
+// PavlovTrial is one trial of a Pavlovian conditioning schedule.
+type PavlovTrial struct {
+
+	// Name is the name of the trial, e.g., AB+, with the CS letters,
+	// and + for reinforced or - for non-reinforced trials, or no
+	// suffix for test trials.
+	Name string
+
+	// Phase is the phase of the schedule: Acquisition, Extinction,
+	// Blocking or Test.
+	Phase string
+
+	// CS are the indexes of the active conditioned stimuli.
+	CS []int
+
+	// PRew is the probability of the reward.
+	PRew float32
+
+	// Test is true for test trials, which have no reward,
+	// and are typically run without learning.
+	Test bool
+}
+
+// pavlovTrial returns a trial for the given CS letters.
+func pavlovTrial(phase, cs string, pRew float32, test bool) PavlovTrial {
+	tr := PavlovTrial{Name: cs, Phase: phase, PRew: pRew, Test: test}
+	for _, c := range cs {
+		tr.CS = append(tr.CS, int(c-'A'))
+	}
+	switch {
+	case test:
+	case pRew > 0:
+		tr.Name += "+"
+	default:
+		tr.Name += "-"
+	}
+	return tr
+}
+
+// Validate returns an error if NCS is too small for the Schedule,
+// or for any invalid Groups from the File.
+func (pp *PavlovParams) Validate() error {
+	if len(pp.Groups) > 0 {
+		return ValidatePavlovGroups(pp.Groups, pp.NCS)
+	}
+	ncs := 1
+	if pp.Schedule == PavlovBlocking {
+		ncs = 4
+	}
+	if pp.NCS < ncs {
+		return fmt.Errorf("leabra.PavlovParams: %s requires NCS >= %d, not %d", pp.Schedule, ncs, pp.NCS)
+	}
+	return nil
+}
+
+// Trials returns the list of trials of the schedule, in order,
+// from the Groups if set, or else from the built-in Schedule.
+func (pp *PavlovParams) Trials() []PavlovTrial {
+	gps := pp.Groups
+	if len(gps) == 0 {
+		gps = pp.BuiltinGroups(pp.Schedule)
+	}
+	var trs []PavlovTrial
+	for _, gp := range gps {
+		for range gp.Trials {
+			for _, cs := range gp.CS {
+				trs = append(trs, pavlovTrial(gp.Phase, cs, gp.PRew, gp.Test))
+			}
+		}
+	}
+	return trs
+}
+
+// PavlovEnv is an env.Env that presents the trials of a standard
+// Pavlovian conditioning schedule, as given by the Params, with the
+// conditioned stimuli as a localist CS pattern, and the reward as
+// the Rew state, for use with the RW or TD reward layers.
+// Each epoch is one pass through the schedule.
+type PavlovEnv struct {
+
+	// name of this environment, usually Train.
+	Name string
+
+	// Params are the parameters of the schedule.
+	Params PavlovParams `display:"inline"`
+
+	// Seed is the seed for the Rand stream of this environment,
+	// which is seeded with Seed + run in Init.
+	Seed int64
+
+	// Rand is the random number stream for the partial reinforcement.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// Trials is the list of trials of the schedule, from Params in Init.
+	Trials []PavlovTrial `display:"-"`
+
+	// Trial is the current trial within the schedule.
+	Trial env.Counter `display:"inline"`
+
+	// Epoch is the number of complete passes through the schedule.
+	Epoch env.Counter `display:"inline"`
+
+	// TrialName is the name of the current trial.
+	TrialName env.CurPrvString
+
+	// Phase is the phase of the schedule of the current trial.
+	Phase env.CurPrvString
+
+	// Rewarded is true if the current trial is rewarded.
+	Rewarded bool `edit:"-"`
+
+	// CS is the localist conditioned stimulus pattern.
+	CS tensor.Float32 `display:"-"`
+
+	// Rew is the reward as a 1x1 tensor.
+	Rew tensor.Float32 `display:"-"`
+}
+
+func (ev *PavlovEnv) Label() string { return ev.Name }
+
+func (ev *PavlovEnv) EnvDesc() string {
+	pp := &ev.Params
+	sched := pp.Schedule.String()
+	if len(pp.Groups) > 0 {
+		sched = pp.File + ":" + pp.FileSchedule
+	}
+	return fmt.Sprintf("Pavlov: Schedule: %s\tTrials: %d\tNCS: %d\tPRew: %g", sched, len(pp.Trials()), pp.NCS, pp.PRew)
+}
+
+func (ev *PavlovEnv) Defaults() {
+	ev.Params.Defaults()
+}
+
+// Init starts a new run, generating the Trials from the Params,
+// with the Rand stream seeded by Seed + run.
+func (ev *PavlovEnv) Init(run int) {
+	ev.Rand.NewRand(ev.Seed + int64(run))
+	ev.Trials = ev.Params.Trials()
+	ev.CS.SetShape([]int{1, max(ev.Params.NCS, 1)})
+	ev.Rew.SetShape([]int{1, 1})
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Max = len(ev.Trials)
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Epoch.Scale = etime.Epoch
+	ev.Epoch.Init()
+}
+
+// Cur returns the current trial.
+func (ev *PavlovEnv) Cur() *PavlovTrial {
+	return &ev.Trials[ev.Trial.Cur]
+}
+
+// IsTest returns true if the current trial is a test trial.
+func (ev *PavlovEnv) IsTest() bool {
+	return ev.Cur().Test
+}
+
+func (ev *PavlovEnv) Step() bool {
+	if len(ev.Trials) == 0 {
+		return false
+	}
+	ev.Epoch.Same()
+	if ev.Trial.Incr() { // if true, hit max, reset to 0
+		ev.Epoch.Incr()
+	}
+	tr := ev.Cur()
+	ev.TrialName.Set(tr.Name)
+	ev.Phase.Set(tr.Phase)
+	ev.CS.SetZeros()
+	for _, cs := range tr.CS {
+		if cs < ev.CS.Len() {
+			ev.CS.Values[cs] = 1
+		}
+	}
+	ev.Rewarded = tr.PRew > 0 && randx.BoolP32(tr.PRew, &ev.Rand)
+	ev.Rew.Values[0] = 0
+	if ev.Rewarded {
+		ev.Rew.Values[0] = 1
+	}
+	return true
+}
+
+// State returns the given state: CS or Rew.
+func (ev *PavlovEnv) State(element string) tensor.Tensor {
+	switch element {
+	case "CS":
+		return &ev.CS
+	case "Rew":
+		return &ev.Rew
+	}
+	return nil
+}
+
+func (ev *PavlovEnv) Action(element string, input tensor.Tensor) {
+	// nop
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*PavlovEnv)(nil)
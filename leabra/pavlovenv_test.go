@@ -0,0 +1,84 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestPavlovEnv(t *testing.T) {
+	net := NewNetwork("Pavlov")
+	cs := net.AddLayer2D("CS", 1, 4, InputLayer)
+	rew, rp, da := net.AddRWLayers("", 2)
+	net.ConnectLayers(cs, rp, paths.NewFull(), RWPath)
+	da.AddSendTo(rp.Name)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	for _, pt := range rp.RecvPaths {
+		pt.WtInit.Mean = 0
+		pt.WtInit.Var = 0
+		pt.Learn.Lrate = 0.1
+	}
+	rp.RW.PredRange.Set(0, 1)
+
+	ev := &PavlovEnv{Name: "Train"}
+	ev.Defaults()
+	// run runs the schedule, and returns the predictions on the
+	// last trial of each phase, and on the test trials by name
+	run := func(sched PavlovSchedules) map[string]float32 {
+		ev.Params.Schedule = sched
+		if err := ev.Params.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		ev.Init(0)
+		net.InitWeights()
+		ctx := NewContext()
+		preds := map[string]float32{}
+		for range ev.Trials {
+			ev.Step()
+			net.InitExt()
+			cs.ApplyExt(ev.State("CS"))
+			if !ev.IsTest() {
+				rew.ApplyExt(ev.State("Rew"))
+			}
+			net.AlphaCycle(ctx, !ev.IsTest())
+			pred := float32(rp.Neurons[0].ActM)
+			if ev.IsTest() {
+				preds[ev.TrialName.Cur] = pred
+			} else {
+				preds[ev.Phase.Cur] = pred
+			}
+		}
+		if ev.Epoch.Cur != 0 {
+			t.Errorf("%s: epoch incremented within the schedule", sched)
+		}
+		return preds
+	}
+
+	acq := run(PavlovAcquisition)
+	if acq["Acquisition"] < 0.7 || acq["A"] < 0.7 {
+		t.Errorf("acquisition: prediction should be high: %v", acq)
+	}
+	ext := run(PavlovExtinction)
+	if ext["A"] > 0.3 {
+		t.Errorf("extinction: prediction should be low: %v", ext)
+	}
+	blk := run(PavlovBlocking)
+	if blk["B"] >= blk["D"] || blk["D"] < 0.2 {
+		t.Errorf("blocking: B should be less than D: %v", blk)
+	}
+
+	ev.Params.NCS = 2
+	if err := ev.Params.Validate(); err == nil {
+		t.Errorf("expected error for blocking with 2 CS")
+	}
+	if n := len(ev.Trials); n != 20+40+2 {
+		t.Errorf("blocking trials: %d", n)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/base/iox/jsonx"
+	"cogentcore.org/core/base/iox/tomlx"
+)
+
+// PavlovGroup is a group of trials in a Pavlovian conditioning schedule,
+// which presents each of the CS compounds in turn, Trials times.
+type PavlovGroup struct {
+
+	// Phase is the phase of the schedule, e.g., Acquisition or Test.
+	Phase string
+
+	// CS are the conditioned stimulus compounds that are presented in
+	// turn, as letters A, B, C, ... for the CS units, e.g., ["AB", "CD"].
+	CS []string
+
+	// PRew is the probability of the reward, which must be 0 for Test groups.
+	PRew float32
+
+	// Trials is the number of times the CS compounds are presented.
+	Trials int
+
+	// Test is true for test trials, which have no reward,
+	// and are typically run without learning.
+	Test bool
+}
+
+// PavlovSchedule is a named list of trial groups for PavlovEnv,
+// which can be saved and loaded with SavePavlovSchedules and
+// OpenPavlovSchedules, to define new conditioning protocols
+// without recompiling.
+type PavlovSchedule struct {
+
+	// Name is the name of the schedule.
+	Name string
+
+	// Groups are the trial groups, in order.
+	Groups []PavlovGroup
+}
+
+// pavlovScheduleFile is the top-level structure of a schedule file.
+type pavlovScheduleFile struct {
+	Schedules []PavlovSchedule
+}
+
+// BuiltinGroups returns the trial groups of the given built-in schedule,
+// with the numbers of trials and reward probability of the params.
+func (pp *PavlovParams) BuiltinGroups(sched PavlovSchedules) []PavlovGroup {
+	gps := []PavlovGroup{{Phase: "Acquisition", CS: []string{"A"}, PRew: pp.PRew, Trials: pp.AcqTrials}}
+	test := func(cs string) PavlovGroup {
+		return PavlovGroup{Phase: "Test", CS: []string{cs}, Trials: pp.TestTrials, Test: true}
+	}
+	switch sched {
+	case PavlovAcquisition:
+		gps = append(gps, test("A"))
+	case PavlovExtinction:
+		gps = append(gps, PavlovGroup{Phase: "Extinction", CS: []string{"A"}, Trials: pp.ExtTrials}, test("A"))
+	case PavlovBlocking:
+		gps = append(gps, PavlovGroup{Phase: "Blocking", CS: []string{"AB", "CD"}, PRew: pp.PRew, Trials: pp.BlockTrials}, test("B"), test("D"))
+	}
+	return gps
+}
+
+// BuiltinSchedules returns all of the built-in schedules, named by
+// their PavlovSchedules value, e.g., for saving as templates
+// with SavePavlovSchedules.
+func (pp *PavlovParams) BuiltinSchedules() []PavlovSchedule {
+	var scs []PavlovSchedule
+	for _, sc := range PavlovSchedulesValues() {
+		scs = append(scs, PavlovSchedule{Name: sc.String(), Groups: pp.BuiltinGroups(sc)})
+	}
+	return scs
+}
+
+// ValidatePavlovGroups returns an error for any invalid trial groups,
+// including CS letters beyond the given number of CS units.
+func ValidatePavlovGroups(gps []PavlovGroup, ncs int) error {
+	var errs []error
+	for gi, gp := range gps {
+		errf := func(format string, args ...any) {
+			errs = append(errs, fmt.Errorf("leabra.PavlovGroup %d (%s): %s", gi, gp.Phase, fmt.Sprintf(format, args...)))
+		}
+		if len(gp.CS) == 0 {
+			errf("no CS")
+		}
+		for _, cs := range gp.CS {
+			if cs == "" {
+				errf("empty CS")
+			}
+			for _, c := range cs {
+				if c < 'A' || int(c-'A') >= ncs {
+					errf("CS %q letter %q is not in A-%c for NCS = %d", cs, c, 'A'+rune(ncs-1), ncs)
+				}
+			}
+		}
+		if gp.Trials < 0 {
+			errf("Trials %d < 0", gp.Trials)
+		}
+		if gp.PRew < 0 || gp.PRew > 1 {
+			errf("PRew %g is not in 0-1", gp.PRew)
+		}
+		if gp.Test && gp.PRew != 0 {
+			errf("Test groups must have PRew = 0, not %g", gp.PRew)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SavePavlovSchedules saves the given schedules to the given TOML or
+// JSON file (according to the extension), e.g., the BuiltinSchedules
+// as templates for new schedules.
+func SavePavlovSchedules(scs []PavlovSchedule, filename string) error {
+	sf := &pavlovScheduleFile{Schedules: scs}
+	if isJSONFile(filename) {
+		return jsonx.Save(sf, filename)
+	}
+	return tomlx.Save(sf, filename)
+}
+
+// OpenPavlovSchedules opens the schedules from the given TOML or JSON
+// file (according to the extension), as saved by SavePavlovSchedules,
+// returning an error if the schedules are missing or have duplicate or
+// empty names.  The groups are validated by PavlovParams.OpenFile,
+// which knows the number of CS units.
+func OpenPavlovSchedules(filename string) ([]PavlovSchedule, error) {
+	sf := &pavlovScheduleFile{}
+	var err error
+	if isJSONFile(filename) {
+		err = jsonx.Open(sf, filename)
+	} else {
+		err = tomlx.Open(sf, filename)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(sf.Schedules) == 0 {
+		return nil, fmt.Errorf("leabra.OpenPavlovSchedules: no schedules in %s", filename)
+	}
+	names := map[string]bool{}
+	for _, sc := range sf.Schedules {
+		if sc.Name == "" {
+			return nil, fmt.Errorf("leabra.OpenPavlovSchedules: schedule without a Name in %s", filename)
+		}
+		if names[sc.Name] {
+			return nil, fmt.Errorf("leabra.OpenPavlovSchedules: duplicate schedule %q in %s", sc.Name, filename)
+		}
+		names[sc.Name] = true
+	}
+	return sf.Schedules, nil
+}
+
+// OpenFile sets the Groups from the FileSchedule in the File, if set,
+// validating them for the NCS.  Call at startup, before PavlovEnv.Init.
+func (pp *PavlovParams) OpenFile() error {
+	pp.Groups = nil
+	if pp.File == "" {
+		return nil
+	}
+	scs, err := OpenPavlovSchedules(pp.File)
+	if err != nil {
+		return err
+	}
+	sc := &scs[0]
+	if pp.FileSchedule != "" {
+		sc = nil
+		for i := range scs {
+			if scs[i].Name == pp.FileSchedule {
+				sc = &scs[i]
+				break
+			}
+		}
+		if sc == nil {
+			return fmt.Errorf("leabra.PavlovParams: schedule %q not found in %s", pp.FileSchedule, pp.File)
+		}
+	}
+	if err := ValidatePavlovGroups(sc.Groups, pp.NCS); err != nil {
+		return fmt.Errorf("leabra.PavlovParams: schedule %q in %s: %w", sc.Name, pp.File, err)
+	}
+	pp.Groups = sc.Groups
+	return nil
+}
+
+// isJSONFile returns true if the file has a .json extension.
+func isJSONFile(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".json"
+}
@@ -0,0 +1,92 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPavlovScheduleFile(t *testing.T) {
+	pp := &PavlovParams{}
+	pp.Defaults()
+	dir := t.TempDir()
+	for _, ext := range []string{".toml", ".json"} {
+		fnm := filepath.Join(dir, "pavlov"+ext)
+		if err := SavePavlovSchedules(pp.BuiltinSchedules(), fnm); err != nil {
+			t.Fatal(err)
+		}
+		for _, sc := range PavlovSchedulesValues() {
+			fp := *pp
+			fp.Schedule = sc
+			fp.File = fnm
+			fp.FileSchedule = sc.String()
+			if err := fp.OpenFile(); err != nil {
+				t.Fatal(err)
+			}
+			if err := fp.Validate(); err != nil {
+				t.Error(err)
+			}
+			want, got := fmt.Sprint(pp.BuiltinGroups(sc)), fmt.Sprint(fp.Groups)
+			if got != want {
+				t.Errorf("%s %s: got groups %s, want %s", ext, sc, got, want)
+			}
+			bp := *pp
+			bp.Schedule = sc
+			if got, want := fmt.Sprint(fp.Trials()), fmt.Sprint(bp.Trials()); got != want {
+				t.Errorf("%s %s: got trials %s, want %s", ext, sc, got, want)
+			}
+		}
+	}
+
+	custom := `[[Schedules]]
+Name = "Overexpectation"
+
+[[Schedules.Groups]]
+Phase = "Acquisition"
+CS = ["A", "B"]
+PRew = 1.0
+Trials = 2
+
+[[Schedules.Groups]]
+Phase = "Compound"
+CS = ["AB"]
+PRew = 1.0
+Trials = 1
+
+[[Schedules.Groups]]
+Phase = "Test"
+CS = ["A"]
+Trials = 1
+Test = true
+`
+	fnm := filepath.Join(dir, "custom.toml")
+	if err := os.WriteFile(fnm, []byte(custom), 0666); err != nil {
+		t.Fatal(err)
+	}
+	pp.File = fnm
+	if err := pp.OpenFile(); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tr := range pp.Trials() {
+		names = append(names, tr.Name)
+	}
+	if got := strings.Join(names, " "); got != "A+ B+ A+ B+ AB+ A" {
+		t.Errorf("custom trials: got %s", got)
+	}
+	pp.NCS = 1
+	if err := pp.OpenFile(); err == nil || !strings.Contains(err.Error(), `letter 'B'`) {
+		t.Errorf("expected CS letter error, got %v", err)
+	}
+	pp.NCS = 4
+	pp.FileSchedule = "Missing"
+	if err := pp.OpenFile(); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("expected missing schedule error, got %v", err)
+	}
+}
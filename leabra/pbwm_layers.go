@@ -7,7 +7,7 @@ package leabra
 import (
 	"fmt"
 
-	"cogentcore.org/core/math32"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 // MatrixParams has parameters for Dorsal Striatum Matrix computation.
@@ -18,19 +18,29 @@ type MatrixParams struct {
 	LearnQtr Quarters
 
 	// how much the patch shunt activation multiplies the dopamine values -- 0 = complete shunting, 1 = no shunting -- should be a factor < 1.0
-	PatchShunt float32 `default:"0.2,0.5" min:"0" max:"1"`
+	PatchShunt Float `default:"0.2,0.5" min:"0" max:"1"`
 
 	// also shunt the ACh value driven from CIN units -- this prevents clearing of MSNConSpec traces -- more plausibly the patch units directly interfere with the effects of CIN's rather than through ach, but it is easier to implement with ach shunting here.
 	ShuntACh bool `default:"true"`
 
 	// how much does the LACK of ACh from the CIN units drive extra inhibition to output-gating Matrix units -- gi += out_ach_inhib * (1-ach) -- provides a bias for output gating on reward trials -- do NOT apply to NoGo, only Go -- this is a key param -- between 0.1-0.3 usu good -- see how much output gating happening and change accordingly
-	OutAChInhib float32 `default:"0,0.3"`
+	OutAChInhib Float `default:"0,0.3"`
 
 	// multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)
-	BurstGain float32 `default:"1"`
+	BurstGain Float `default:"1"`
 
 	// multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)
-	DipGain float32 `default:"1"`
+	DipGain Float `default:"1"`
+
+	// ValueGain is the excitatory conductance bias per unit of the state
+	// value received from a StateValue [ValueLayer], which is positive for
+	// Go (D1R) and negative for NoGo (D2R) layers.
+	ValueGain Float `default:"0.3"`
+
+	// CostGain is the excitatory conductance bias per unit of the effort
+	// cost received from an EffortCost [ValueLayer], which is positive for
+	// NoGo (D2R) and negative for Go (D1R) layers.
+	CostGain Float `default:"0.3"`
 }
 
 func (mp *MatrixParams) Defaults() {
@@ -41,6 +51,8 @@ func (mp *MatrixParams) Defaults() {
 	mp.OutAChInhib = 0.3
 	mp.BurstGain = 1
 	mp.DipGain = 1
+	mp.ValueGain = 0.3
+	mp.CostGain = 0.3
 }
 
 func (mp *MatrixParams) Update() {
@@ -62,7 +74,7 @@ func (ly *Layer) MatrixDefaults() {
 
 // DALrnFromDA returns effective learning dopamine value from given raw DA value
 // applying Burst and Dip Gain factors, and then reversing sign for D2R.
-func (ly *Layer) DALrnFromDA(da float32) float32 {
+func (ly *Layer) DALrnFromDA(da Float) Float {
 	if da > 0 {
 		da *= ly.Matrix.BurstGain
 	} else {
@@ -85,9 +97,18 @@ func (ly *Layer) MatrixOutAChInhib(ctx *Context) {
 	ynN := ly.Shape.DimSize(2)
 	xnN := ly.Shape.DimSize(3)
 	maintN := ly.PBWM.MaintN
+	stripes := len(ly.PBWM.Stripes) > 0
+	if stripes {
+		maintN = 0
+	}
 	layAch := ly.NeuroMod.ACh // ACh comes from CIN neurons, represents reward time
 	for yp := 0; yp < ypN; yp++ {
 		for xp := maintN; xp < xpN; xp++ {
+			if stripes {
+				if si := ly.PBWM.StripeAt(xp); si < 0 || ly.PBWM.Stripes[si].Role != OutStripe {
+					continue
+				}
+			}
 			for yn := 0; yn < ynN; yn++ {
 				for xn := 0; xn < xnN; xn++ {
 					ni := ly.Shape.Offset([]int{yp, xp, yn, xn})
@@ -114,7 +135,7 @@ func (ly *Layer) DaAChFromLay(ctx *Context) {
 		if nrn.IsOff() {
 			continue
 		}
-		da := ly.NeuroMod.DA
+		da := ly.DaLearn()
 		if nrn.Shunt > 0 { // note: treating Shunt as binary variable -- could multiply
 			da *= ly.Matrix.PatchShunt
 		}
@@ -157,13 +178,64 @@ const (
 	MaintOut
 )
 
+// StripeRoles are the functional roles of a set of PFC stripes,
+// which determine the effects of BG gating on the PFC deep layer.
+type StripeRoles int32 //enums:enum
+
+const (
+	// MaintStripe stripes are gated into robust active maintenance,
+	// which persists until the next gating or up to MaxMaint.
+	MaintStripe StripeRoles = iota
+
+	// OutStripe stripes are output gated, driving transient deep layer
+	// activation in the first quarter only, as in the PFCout layer.
+	OutStripe
+
+	// InputStripe stripes are input gated, updating the deep layer from
+	// the current super layer activity for one gating interval only,
+	// without sustained maintenance.
+	InputStripe
+)
+
+// StripeSpec specifies one set of PFC stripes (pools along the X axis)
+// with a common gating role.  A list of StripeSpecs defines the full
+// X dimension of the gating layers (Matrix, GPiThal), in order,
+// with one PFC layer (super and deep) for each set.
+type StripeSpec struct {
+
+	// Name of the stripe set, appended to the PFC prefix for the
+	// PFC layer names (e.g., mnt for PFCmnt, PFCmntD).
+	Name string
+
+	// Role is the gating role of the stripes.
+	Role StripeRoles
+
+	// N is the number of stripes (pools) along the X axis.
+	N int
+
+	// MaxMaint overrides the PFCMaint.MaxMaint maximum duration of
+	// maintenance for the deep layer if > 0.  The default is 100
+	// for MaintStripe and 1 for OutStripe and InputStripe.
+	MaxMaint int
+}
+
 // SendToMatrixPFC adds standard SendTo layers for PBWM: MatrixGo, NoGo, PFCmntD, PFCoutD
-// with optional prefix -- excludes mnt, out cases if corresp shape = 0
+// with optional prefix -- excludes mnt, out cases if corresp shape = 0.
+// If Stripes are set, the PFC deep layer for each stripe set is used instead.
 func (ly *Layer) SendToMatrixPFC(prefix string) {
 	pfcprefix := "PFC"
 	if prefix != "" {
 		pfcprefix = prefix
 	}
+	if len(ly.PBWM.Stripes) > 0 {
+		ly.SendTo = []string{prefix + "MatrixGo", prefix + "MatrixNoGo"}
+		for _, ss := range ly.PBWM.Stripes {
+			if ss.N > 0 {
+				ly.SendTo = append(ly.SendTo, pfcprefix+ss.Name+"D")
+			}
+		}
+		return
+	}
 	std := []string{prefix + "MatrixGo", prefix + "MatrixNoGo", pfcprefix + "mntD", pfcprefix + "outD"}
 	ly.SendTo = make([]string, 2)
 	for i, s := range std {
@@ -184,11 +256,15 @@ func (ly *Layer) SendToMatrixPFC(prefix string) {
 }
 
 // SendPBWMParams send PBWMParams info to all SendTo layers -- convenient config-time
-// way to ensure all are consistent -- also checks validity of SendTo's
+// way to ensure all are consistent -- also checks validity of SendTo's.
+// Lower-level GPiThal layers (see ConnectPBWMLevels) have their own geometry.
 func (ly *Layer) SendPBWMParams() error {
 	var lasterr error
 	for _, lnm := range ly.SendTo {
 		tly := ly.Network.LayerByName(lnm)
+		if tly.Type == GPiThalLayer {
+			continue
+		}
 		tly.PBWM.CopyGeomFrom(&ly.PBWM)
 	}
 	return lasterr
@@ -225,7 +301,10 @@ func (ly *Layer) MatrixPaths() (goPath, nogoPath *Path, err error) {
 // to have this coordinated shape information to be able to share gating
 // state information.  Each layer represents gate state information in
 // their native geometry -- FullIndex1D provides access from a subset
-// to full set.
+// to full set.  Alternatively, the X axis can be organized into any
+// number of stripe sets with different roles, using SetStripes,
+// in which case StripeIndex1D provides access from a stripe set
+// to the full set.
 type PBWMParams struct {
 	// Type of gating layer
 	Type GateTypes
@@ -246,7 +325,20 @@ type PBWMParams struct {
 	// For the Matrix layers, this is the number of Maint Pools in X outer
 	// dimension of 4D shape -- Out gating after that. Note: it is unclear
 	// how this relates to MaintX, but it is different in SIR model.
+	// Not used if Stripes are set.
 	MaintN int
+
+	// Stripes, if set, are the stripe sets arrayed along the X axis in
+	// order, in place of the Maint and Out subsets (see SetStripes).
+	Stripes []StripeSpec
+
+	// Stripe is the index into Stripes of the stripe set represented
+	// by this layer, for PFC layers when Stripes are set.
+	Stripe int
+
+	// GateNoise configures independent random number streams for the
+	// Act.Noise in each stripe of Matrix and GPiThal layers.
+	GateNoise GateNoiseParams `display:"inline"`
 }
 
 func (pp *PBWMParams) Defaults() {
@@ -265,16 +357,103 @@ func (pp *PBWMParams) Set(nY, maintX, outX int) {
 	pp.OutX = outX
 }
 
-// TotX returns the total number of X-axis pools (Maint + Out)
+// SetStripes sets the shape parameters: number of Y dimension pools,
+// and the stripe sets arrayed along the X axis in order.
+// MaintX and OutX are set to the total number of MaintStripe and
+// OutStripe pools, for reference.
+func (pp *PBWMParams) SetStripes(nY int, stripes ...StripeSpec) {
+	pp.Y = nY
+	pp.Stripes = stripes
+	pp.MaintX = 0
+	pp.OutX = 0
+	for _, ss := range stripes {
+		switch ss.Role {
+		case MaintStripe:
+			pp.MaintX += ss.N
+		case OutStripe:
+			pp.OutX += ss.N
+		}
+	}
+}
+
+// TotX returns the total number of X-axis pools (Maint + Out,
+// or all of the Stripes if set).
 func (pp *PBWMParams) TotX() int {
+	if len(pp.Stripes) > 0 {
+		tx := 0
+		for _, ss := range pp.Stripes {
+			tx += ss.N
+		}
+		return tx
+	}
 	return pp.MaintX + pp.OutX
 }
 
 func (pp *PBWMParams) CopyGeomFrom(src *PBWMParams) {
-	pp.Set(src.Y, src.MaintX, src.OutX)
+	if len(src.Stripes) > 0 {
+		pp.SetStripes(src.Y, src.Stripes...)
+	} else {
+		pp.Set(src.Y, src.MaintX, src.OutX)
+	}
 	pp.Type = src.Type
 }
 
+// StripeSpec returns the StripeSpec of the stripe set represented
+// by this layer, or nil if Stripes are not set.
+func (pp *PBWMParams) StripeSpec() *StripeSpec {
+	if pp.Stripe < 0 || pp.Stripe >= len(pp.Stripes) {
+		return nil
+	}
+	return &pp.Stripes[pp.Stripe]
+}
+
+// StripeX returns the starting X-axis pool and number of pools
+// of the given stripe set.
+func (pp *PBWMParams) StripeX(stripe int) (stX, n int) {
+	for i, ss := range pp.Stripes {
+		if i == stripe {
+			return stX, ss.N
+		}
+		stX += ss.N
+	}
+	return stX, 0
+}
+
+// StripeAt returns the index of the stripe set for the given X-axis
+// pool in the full set, or -1 if out of range.
+func (pp *PBWMParams) StripeAt(pX int) int {
+	for i, ss := range pp.Stripes {
+		if pX < ss.N {
+			return i
+		}
+		pX -= ss.N
+	}
+	return -1
+}
+
+// IsMaintX returns true if the given X-axis pool in the full set
+// is a maintenance gating pool: in the Maint subset, or a MaintStripe
+// set if Stripes are set.
+func (pp *PBWMParams) IsMaintX(pX int) bool {
+	if len(pp.Stripes) > 0 {
+		si := pp.StripeAt(pX)
+		return si >= 0 && pp.Stripes[si].Role == MaintStripe
+	}
+	return pX < pp.MaintX
+}
+
+// StripeIndex1D returns the index into full GateStates for given
+// 1D pool idx (0-based) *from given stripe set*.
+func (pp *PBWMParams) StripeIndex1D(idx, stripe int) int {
+	stX, n := pp.StripeX(stripe)
+	if n == 0 {
+		return 0
+	}
+	pY := idx / n
+	pX := idx%n + stX
+	return pp.Index(pY, pX, MaintOut)
+}
+
 // Index returns the index into GateStates for given 2D pool coords
 // for given GateType.  Each type stores gate info in its "native" 2D format.
 func (pp *PBWMParams) Index(pY, pX int, typ GateTypes) int {
@@ -328,7 +507,7 @@ func (pp *PBWMParams) FullIndex1D(idx int, fmTyp GateTypes) int {
 type GateState struct {
 
 	// gating activation value, reflecting thalamic gating layer activation at time of gating (when Now = true) -- will be 0 if gating below threshold for this pool, and prior to first Now for AlphaCycle
-	Act float32
+	Act Float
 
 	// gating timing signal -- true if this is the moment when gating takes place
 	Now bool
@@ -381,6 +560,9 @@ func (ly *Layer) SetGateStates(src *Layer, typ GateTypes) {
 		for i := 1; i < mx; i++ {
 			gs := &ly.Pool(i).Gate
 			si := 1 + ly.PBWM.FullIndex1D(i-1, myt)
+			if len(ly.PBWM.Stripes) > 0 {
+				si = 1 + ly.PBWM.StripeIndex1D(i-1, ly.PBWM.Stripe)
+			}
 			sgs := &src.Pool(si).Gate
 			gs.CopyFrom(sgs)
 		}
@@ -402,16 +584,23 @@ type GPiGateParams struct {
 	Cycle int `default:"18"`
 
 	// extra netinput gain factor to compensate for reduction in Ge from subtracting away NoGo -- this is *IN ADDITION* to adding the NoGo factor as an extra gain: Ge = (GeGain + NoGo) * (GoIn - NoGo * NoGoIn)
-	GeGain float32 `default:"3"`
+	GeGain Float `default:"3"`
 
 	// how much to weight NoGo inputs relative to Go inputs (which have an implied weight of 1 -- this also up-scales overall Ge to compensate for subtraction
-	NoGo float32 `min:"0" default:"1,0.1"`
+	NoGo Float `min:"0" default:"1,0.1"`
 
 	// threshold for gating, applied to activation -- when any GPiThal unit activation gets above this threshold, it counts as having gated, driving updating of GateState which is broadcast to other layers that use the gating signal
-	Thr float32 `default:"0.2"`
+	Thr Float `default:"0.2"`
 
 	// Act value of GPiThal unit reflects gating threshold: if below threshold, it is zeroed -- see ActLrn for underlying non-thresholded activation
 	ThrAct bool `default:"true"`
+
+	// UpperClear clears the established maintenance in the PFC maintenance
+	// layers gated by this layer, when a higher-level GPiThal layer that
+	// includes this layer in its SendTo does maintenance gating, for
+	// hierarchical PBWM where updating the higher-level context resets
+	// the lower-level working memory (see ConnectPBWMLevels).
+	UpperClear bool
 }
 
 func (gp *GPiGateParams) Defaults() {
@@ -428,7 +617,7 @@ func (gp *GPiGateParams) Update() {
 }
 
 // GeRaw returns the net GeRaw from go, nogo specific values
-func (gp *GPiGateParams) GeRaw(goRaw, nogoRaw float32) float32 {
+func (gp *GPiGateParams) GeRaw(goRaw, nogoRaw Float) Float {
 	return (gp.GeGain + gp.NoGo) * (goRaw - gp.NoGo*nogoRaw)
 }
 
@@ -453,7 +642,7 @@ func (ly *Layer) GPiGFromInc(ctx *Context) {
 		goRaw := goPath.GeRaw[ni]
 		nogoRaw := nogoPath.GeRaw[ni]
 		nrn.GeRaw = ly.GPiGate.GeRaw(goRaw, nogoRaw)
-		ly.Act.GeFromRaw(nrn, nrn.GeRaw)
+		ly.Act.GeFromRawRand(nrn, nrn.GeRaw, ly.noiseRand(nrn))
 		ly.Act.GiFromRaw(nrn, nrn.GiRaw)
 	}
 }
@@ -499,15 +688,53 @@ func (ly *Layer) GPiGateFromAct(ctx *Context) {
 	}
 }
 
-// GPiSendGateStates sends GateStates to other layers
+// GPiSendGateStates sends GateStates to other layers.
+// Lower-level GPiThal layers in SendTo receive UpperGateFrom instead.
 func (ly *Layer) GPiSendGateStates() {
 	myt := MaintOut // always
 	for _, lnm := range ly.SendTo {
 		gl := ly.Network.LayerByName(lnm)
+		if gl.Type == GPiThalLayer {
+			gl.UpperGateFrom(ly)
+			continue
+		}
 		gl.SetGateStates(ly, myt)
 	}
 }
 
+// UpperGateFrom receives the gating state of given higher-level GPiThal
+// layer, for hierarchical PBWM.  The layer-level Pools[0].Gate records the
+// maintenance gating of the upper layer: Now when it is gating, with Act the
+// max gating activation over its maintenance stripes (0 if none gated).
+// If UpperClear, maintenance gating clears the established maintenance
+// in the PFC maintenance layers gated by this layer.
+func (ly *Layer) UpperGateFrom(upper *Layer) {
+	ugs := &ly.Pools[0].Gate
+	ugs.Now = false
+	ugs.Act = 0
+	tX := upper.PBWM.TotX()
+	if tX == 0 {
+		return
+	}
+	for pi := 1; pi < len(upper.Pools); pi++ {
+		gs := &upper.Pools[pi].Gate
+		if !gs.Now || !upper.PBWM.IsMaintX((pi-1)%tX) {
+			continue
+		}
+		ugs.Now = true
+		ugs.Act = max(ugs.Act, gs.Act)
+	}
+	if !ugs.Now || ugs.Act == 0 || !ly.GPiGate.UpperClear {
+		return
+	}
+	for _, lnm := range ly.SendTo {
+		gl := ly.Network.LayerByName(lnm)
+		if gl.Type == PFCDeepLayer && !gl.PFCGate.OutGate {
+			gl.ClearAllMaint()
+		}
+	}
+}
+
 //////// CINLayer
 
 // CINParams (cholinergic interneuron) reads reward signals from named source layer(s)
@@ -520,7 +747,7 @@ type CINParams struct {
 	// RewThr is the threshold on reward values from RewLays,
 	// to count as a significant reward event, which then drives maximal ACh.
 	// Set to 0 to disable this nonlinear behavior.
-	RewThr float32 `default:"0.1"`
+	RewThr Float `default:"0.1"`
 
 	// Reward-representing layer(s) from which this computes ACh as Max absolute value
 	RewLays LayerNames
@@ -534,15 +761,15 @@ func (ly *CINParams) Update() {
 }
 
 // CINMaxAbsRew returns the maximum absolute value of reward layer activations.
-func (ly *Layer) CINMaxAbsRew() float32 {
-	mx := float32(0)
+func (ly *Layer) CINMaxAbsRew() Float {
+	mx := Float(0)
 	for _, nm := range ly.CIN.RewLays {
 		ly := ly.Network.LayerByName(nm)
 		if ly == nil {
 			continue
 		}
-		act := math32.Abs(ly.Pools[0].Inhib.Act.Max)
-		mx = math32.Max(mx, act)
+		act := fmath.Abs(ly.Pools[0].Inhib.Act.Max)
+		mx = fmath.Max(mx, act)
 	}
 	return mx
 }
@@ -602,14 +829,14 @@ type PFCMaintParams struct {
 	UseDyn bool
 
 	// multiplier on maint current
-	MaintGain float32 `min:"0" default:"0.8"`
+	MaintGain Float `min:"0" default:"0.8"`
 
 	// on output gating, clear corresponding maint pool.  theoretically this should be on, but actually it works better off in most cases..
 	OutClearMaint bool `default:"false"`
 
 	// how much to clear out (decay) super activations when the stripe itself gates and was previously maintaining something, or for maint pfc stripes, when output go fires and clears.
-	Clear    float32 `min:"0" max:"1" default:"0"`
-	MaxMaint int     `"min:"1" default:"1:100" maximum duration of maintenance for any stripe -- beyond this limit, the maintenance is just automatically cleared -- typically 1 for output gating and 100 for maintenance gating"`
+	Clear    Float `min:"0" max:"1" default:"0"`
+	MaxMaint int   `"min:"1" default:"1:100" maximum duration of maintenance for any stripe -- beyond this limit, the maintenance is just automatically cleared -- typically 1 for output gating and 100 for maintenance gating"`
 }
 
 func (mp *PFCMaintParams) Defaults() {
@@ -626,13 +853,13 @@ func (mp *PFCMaintParams) Update() {
 type PFCDyn struct {
 
 	// initial value at point when gating starts -- MUST be > 0 when used.
-	Init float32
+	Init Float
 
 	// time constant for linear rise in maintenance activation (per quarter when deep is updated) -- use integers -- if both rise and decay then rise comes first
-	RiseTau float32
+	RiseTau Float
 
 	// time constant for linear decay in maintenance activation (per quarter when deep is updated) -- use integers -- if both rise and decay then rise comes first
-	DecayTau float32
+	DecayTau Float
 
 	// description of this factor
 	Desc string
@@ -642,7 +869,7 @@ func (pd *PFCDyn) Defaults() {
 	pd.Init = 1
 }
 
-func (pd *PFCDyn) Set(init, rise, decay float32, desc string) {
+func (pd *PFCDyn) Set(init, rise, decay Float, desc string) {
 	pd.Init = init
 	pd.RiseTau = rise
 	pd.DecayTau = decay
@@ -650,7 +877,7 @@ func (pd *PFCDyn) Set(init, rise, decay float32, desc string) {
 }
 
 // Value returns dynamic value at given time point
-func (pd *PFCDyn) Value(time float32) float32 {
+func (pd *PFCDyn) Value(time Float) Float {
 	val := pd.Init
 	if time <= 0 {
 		return val
@@ -685,7 +912,7 @@ func (pd *PFCDyn) Value(time float32) float32 {
 type PFCDyns []*PFCDyn
 
 // SetDyn sets given dynamic maint element to given parameters (must be allocated in list first)
-func (pd *PFCDyns) SetDyn(dyn int, init, rise, decay float32, desc string) *PFCDyn {
+func (pd *PFCDyns) SetDyn(dyn int, init, rise, decay Float, desc string) *PFCDyn {
 	dy := &PFCDyn{}
 	dy.Set(init, rise, decay, desc)
 	(*pd)[dyn] = dy
@@ -703,7 +930,7 @@ func (pd *PFCDyns) MaintOnly() {
 // FullDyn creates full dynamic Dyn configuration, with 5 different
 // dynamic profiles: stable maint, phasic, rising maint, decaying maint,
 // and up / down maint.  tau is the rise / decay base time constant.
-func (pd *PFCDyns) FullDyn(tau float32) {
+func (pd *PFCDyns) FullDyn(tau Float) {
 	ndyn := 5
 	*pd = make([]*PFCDyn, ndyn)
 
@@ -715,7 +942,7 @@ func (pd *PFCDyns) FullDyn(tau float32) {
 }
 
 // Value returns value for given dyn item at given time step
-func (pd *PFCDyns) Value(dyn int, time float32) float32 {
+func (pd *PFCDyns) Value(dyn int, time Float) Float {
 	sz := len(*pd)
 	if sz == 0 {
 		return 1
@@ -730,6 +957,14 @@ func (ly *Layer) PFCDeepDefaults() {
 		ly.PFCGate.GateQtr = 0
 		ly.PFCGate.GateQtr.SetFlag(true, Q1)
 	}
+	if ss := ly.PBWM.StripeSpec(); ss != nil {
+		if ss.Role == InputStripe {
+			ly.PFCMaint.MaxMaint = 1
+		}
+		if ss.MaxMaint > 0 {
+			ly.PFCMaint.MaxMaint = ss.MaxMaint
+		}
+	}
 	if len(ly.PFCDyns) > 0 {
 		ly.PFCMaint.UseDyn = true
 	} else {
@@ -738,8 +973,18 @@ func (ly *Layer) PFCDeepDefaults() {
 }
 
 // MaintPFC returns corresponding PFCDeep maintenance layer
-// with same name but outD -> mntD; could be nil
+// with same name but outD -> mntD; could be nil.
+// If Stripes are set, it is the deep layer of the first MaintStripe set.
 func (ly *Layer) MaintPFC() *Layer {
+	if ss := ly.PBWM.StripeSpec(); ss != nil {
+		prefix := ly.Name[:len(ly.Name)-len(ss.Name)-1]
+		for _, ms := range ly.PBWM.Stripes {
+			if ms.Role == MaintStripe && ms.N > 0 {
+				return ly.Network.LayerByName(prefix + ms.Name + "D")
+			}
+		}
+		return nil
+	}
 	sz := len(ly.Name)
 	mnm := ly.Name[:sz-4] + "mntD"
 	li := ly.Network.LayerByName(mnm)
@@ -805,7 +1050,7 @@ func (ly *Layer) PFCDeepGating(ctx *Context) {
 // ClearMaint resets maintenance in corresponding pool (0 based) in maintenance layer
 func (ly *Layer) ClearMaint(pool int) {
 	pfcm := ly.MaintPFC()
-	if pfcm == nil {
+	if pfcm == nil || pool >= len(pfcm.Pools) {
 		return
 	}
 	gs := &pfcm.Pools[pool].Gate
@@ -816,6 +1061,23 @@ func (ly *Layer) ClearMaint(pool int) {
 	}
 }
 
+// ClearAllMaint resets established maintenance in all pools of this
+// PFCDeep maintenance layer, as in ClearMaint, e.g., when a higher-level
+// loop gates (see GPiGateParams.UpperClear).
+func (ly *Layer) ClearAllMaint() {
+	pfcs := ly.SuperPFC()
+	for pi := 1; pi < len(ly.Pools); pi++ {
+		gs := &ly.Pools[pi].Gate
+		if gs.Cnt < 1 { // only established maint, not just gated
+			continue
+		}
+		gs.Cnt = -1
+		if pfcs != nil {
+			pfcs.DecayStatePool(pi, ly.PFCMaint.Clear)
+		}
+	}
+}
+
 // DeepMaint updates deep maintenance activations
 func (ly *Layer) DeepMaint(ctx *Context) {
 	if !ly.PFCGate.GateQtr.HasFlag(ctx.Quarter) {
@@ -858,7 +1120,7 @@ func (ly *Layer) DeepMaint(ctx *Context) {
 			nrn.Maint = ly.PFCMaint.MaintGain * snr.Act
 		}
 		if ly.PFCMaint.UseDyn {
-			nrn.MaintGe = nrn.Maint * ly.PFCDyns.Value(dtyp, float32(gs.Cnt-1))
+			nrn.MaintGe = nrn.Maint * ly.PFCDyns.Value(dtyp, Float(gs.Cnt-1))
 		} else {
 			nrn.MaintGe = nrn.Maint
 		}
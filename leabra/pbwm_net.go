@@ -5,6 +5,8 @@
 package leabra
 
 import (
+	"fmt"
+
 	"github.com/emer/emergent/v2/paths"
 )
 
@@ -155,3 +157,101 @@ func (nt *Network) AddPBWM(prefix string, nY, nMaint, nOut, nNeurBgY, nNeurBgX,
 	gpi.SendToMatrixPFC(prefix) // sends gating to all these layers
 	return
 }
+
+// AddDorsalBGStripes adds MatrixGo, NoGo, GPe, GPiThal, and CIN layers,
+// as in AddDorsalBG, with given optional prefix, where the pools along the
+// X dimension are organized into the given stripe sets, in order.
+// nY = number of pools in Y dimension, and each pool has nNeurY, nNeurX neurons.
+func (nt *Network) AddDorsalBGStripes(prefix string, nY int, stripes []StripeSpec, nNeurY, nNeurX int) (mtxGo, mtxNoGo, gpe, gpi, cin *Layer) {
+	tX := 0
+	for _, ss := range stripes {
+		tX += ss.N
+	}
+	mtxGo, mtxNoGo, gpe, gpi, cin = nt.AddDorsalBG(prefix, nY, tX, 0, nNeurY, nNeurX)
+	mtxGo.PBWM.SetStripes(nY, stripes...)
+	mtxNoGo.PBWM.SetStripes(nY, stripes...)
+	gpi.PBWM.SetStripes(nY, stripes...)
+	return
+}
+
+// AddPFCStripes adds a PFC super and deep layer for each of the given
+// stripe sets with N > 0, named prefix + Name (and + "D" for deep),
+// with given optional prefix (defaults to PFC), returned in order.
+// nY = number of pools in Y dimension, and each pool has nNeurY, nNeurX neurons.
+// dynMaint is true for maintenance-only dyn, else full set of 5 dynamic maintenance types.
+// Each OutStripe set receives a OneToOne pathway from the deep layer of the
+// closest preceding MaintStripe set with the same number of stripes,
+// as for PFCmntD -> PFCout in AddPFC.
+func (nt *Network) AddPFCStripes(prefix string, nY int, stripes []StripeSpec, nNeurY, nNeurX int, dynMaint bool) (pfc, pfcD []*Layer) {
+	if prefix == "" {
+		prefix = "PFC"
+	}
+	var mntD *Layer
+	for si, ss := range stripes {
+		if ss.N == 0 {
+			continue
+		}
+		sp, dp := nt.AddPFCLayer(prefix+ss.Name, nY, ss.N, nNeurY, nNeurX, ss.Role == OutStripe, dynMaint)
+		dp.PBWM.SetStripes(nY, stripes...)
+		dp.PBWM.Stripe = si
+		if len(pfc) > 0 {
+			sp.PlaceRightOf(pfc[len(pfc)-1], 2)
+		}
+		switch ss.Role {
+		case MaintStripe:
+			mntD = dp
+		case OutStripe:
+			if mntD != nil && mntD.PBWM.StripeSpec().N == ss.N {
+				pt := nt.ConnectLayers(mntD, sp, paths.NewOneToOne(), ForwardPath)
+				pt.AddClass("PFCMntDToOut")
+			}
+		}
+		pfc = append(pfc, sp)
+		pfcD = append(pfcD, dp)
+	}
+	return
+}
+
+// AddPBWMStripes adds a DorsalBG and PFC with given params, as in AddPBWM,
+// with the given stripe sets, using AddDorsalBGStripes and AddPFCStripes.
+func (nt *Network) AddPBWMStripes(prefix string, nY int, stripes []StripeSpec, nNeurBgY, nNeurBgX, nNeurPfcY, nNeurPfcX int) (mtxGo, mtxNoGo, gpe, gpi, cin *Layer, pfc, pfcD []*Layer) {
+	mtxGo, mtxNoGo, gpe, gpi, cin = nt.AddDorsalBGStripes(prefix, nY, stripes, nNeurBgY, nNeurBgX)
+	pfc, pfcD = nt.AddPFCStripes(prefix, nY, stripes, nNeurPfcY, nNeurPfcX, true) // default dynmaint
+	if len(pfc) > 0 {
+		pfc[0].PlaceAbove(mtxGo)
+	}
+	gpi.SendToMatrixPFC(prefix) // sends gating to all these layers
+	return
+}
+
+// ConnectPBWMLevels connects two PBWM loops made with AddPBWM (or AddPBWMStripes)
+// with given prefixes into a hierarchy, where the upper loop gates the context
+// for the lower loop: the PFC deep layers gated by the upper GPiThal project
+// to the lower MatrixGo and MatrixNoGo layers (MatrixPath, Full, with class
+// "PFCToLowerMatrix"), and the upper GPiThal sends its gating state to the
+// lower GPiThal (see UpperGateFrom), with UpperClear set, so that maintenance
+// gating in the upper loop clears the maintenance in the lower loop.
+// Call after the SendTo of the upper GPiThal is set (as in AddPBWM).
+func (nt *Network) ConnectPBWMLevels(upperPrefix, lowerPrefix string) error {
+	ugpi := nt.LayerByName(upperPrefix + "GPiThal")
+	lgpi := nt.LayerByName(lowerPrefix + "GPiThal")
+	mtxGo := nt.LayerByName(lowerPrefix + "MatrixGo")
+	mtxNoGo := nt.LayerByName(lowerPrefix + "MatrixNoGo")
+	if ugpi == nil || lgpi == nil || mtxGo == nil || mtxNoGo == nil {
+		return fmt.Errorf("ConnectPBWMLevels: GPiThal and Matrix layers not found for prefixes: %q, %q", upperPrefix, lowerPrefix)
+	}
+	full := paths.NewFull()
+	for _, lnm := range ugpi.SendTo {
+		pfc := nt.LayerByName(lnm)
+		if pfc == nil || pfc.Type != PFCDeepLayer {
+			continue
+		}
+		pt := nt.ConnectLayers(pfc, mtxGo, full, MatrixPath)
+		pt.AddClass("PFCToLowerMatrix")
+		pt = nt.ConnectLayers(pfc, mtxNoGo, full, MatrixPath)
+		pt.AddClass("PFCToLowerMatrix")
+	}
+	ugpi.AddSendTo(lgpi.Name)
+	lgpi.GPiGate.UpperClear = true
+	return nil
+}
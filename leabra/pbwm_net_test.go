@@ -0,0 +1,44 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPBWMStripes(t *testing.T) {
+	stripes := []StripeSpec{{Name: "in", Role: InputStripe, N: 1}, {Name: "mnt", Role: MaintStripe, N: 2}, {Name: "out", Role: OutStripe, N: 2}}
+	net := NewNetwork("PBWMStripes")
+	_, _, _, gpi, _, pfc, pfcD := net.AddPBWMStripes("", 2, stripes, 1, 2, 2, 2)
+	if len(pfc) != 3 || pfcD[2].Name != "PFCoutD" || !pfcD[2].PFCGate.OutGate {
+		t.Fatalf("AddPBWMStripes: wrong PFC layers")
+	}
+	sendTo := []string{"MatrixGo", "MatrixNoGo", "PFCinD", "PFCmntD", "PFCoutD"}
+	if fmt.Sprint(gpi.SendTo) != fmt.Sprint(sendTo) {
+		t.Errorf("SendToMatrixPFC: got %v, not %v", gpi.SendTo, sendTo)
+	}
+	pp := &pfcD[2].PBWM
+	if pp.TotX() != 5 || pp.MaintX != 2 || pp.OutX != 2 {
+		t.Errorf("SetStripes: TotX %d MaintX %d OutX %d", pp.TotX(), pp.MaintX, pp.OutX)
+	}
+	// out stripe pools (Y=1, X=1) -> full (Y=1, X=4)
+	if fi := pp.StripeIndex1D(3, 2); fi != 9 {
+		t.Errorf("StripeIndex1D: got %d, not 9", fi)
+	}
+	if si := pp.StripeAt(2); si != 1 {
+		t.Errorf("StripeAt: got %d, not 1", si)
+	}
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	if pfcD[0].PFCMaint.MaxMaint != 1 || pfcD[1].PFCMaint.MaxMaint != 100 {
+		t.Errorf("MaxMaint: input %d maint %d", pfcD[0].PFCMaint.MaxMaint, pfcD[1].PFCMaint.MaxMaint)
+	}
+	if pfcD[2].MaintPFC() != pfcD[1] {
+		t.Errorf("MaintPFC: not PFCmntD")
+	}
+}
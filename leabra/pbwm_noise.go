@@ -0,0 +1,168 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/core"
+)
+
+// GateNoiseParams configure independent, seeded random number streams
+// for the Act.Noise in each stripe (pool) of the Matrix and GPiThal
+// gating layers, so that the gating noise in each stripe does not depend
+// on any other use of random numbers, and specific gating outcomes can be
+// reproduced from the recorded GateSeeds (see Network.AllGateSeeds).
+type GateNoiseParams struct {
+
+	// On uses a separate random number stream for the noise in each
+	// stripe, instead of the layer Rand stream.
+	On bool
+
+	// Seed is the base seed from which the seed for each stripe is
+	// derived, along with the layer name and pool index.  If 0, a new
+	// base seed is drawn from the network Rand in InitWeights,
+	// so the streams differ across runs.
+	Seed int64
+}
+
+// gateNoiseOn returns true if the layer uses per-stripe gating noise.
+func (ly *Layer) gateNoiseOn() bool {
+	return ly.PBWM.GateNoise.On && (ly.Type == MatrixLayer || ly.Type == GPiThalLayer)
+}
+
+// InitGateNoise initializes the GateRands for each pool, with GateSeeds
+// derived from the given base seed, the layer name and the pool index,
+// if PBWM.GateNoise.On for a Matrix or GPiThal layer.
+func (ly *Layer) InitGateNoise(seed int64) {
+	if !ly.gateNoiseOn() {
+		ly.GateRands = nil
+		ly.GateSeeds = nil
+		return
+	}
+	seeds := make([]int64, len(ly.Pools))
+	for pi := range seeds {
+		seeds[pi] = randStreamSeed(seed, ly.Name, pi)
+	}
+	ly.SetGateSeeds(seeds)
+}
+
+// SetGateSeeds sets the GateRands for each pool to start from the given
+// seeds, e.g., as recorded in GateSeeds in a previous run, to reproduce
+// its gating noise.
+func (ly *Layer) SetGateSeeds(seeds []int64) {
+	ly.GateSeeds = slices.Clone(seeds)
+	ly.GateRands = make([]randx.SysRand, len(seeds))
+	for pi, sd := range seeds {
+		ly.GateRands[pi].NewRand(sd)
+	}
+}
+
+// noiseRand returns the random number stream for the noise of the given
+// neuron: the GateRands stream for its pool if using per-stripe gating
+// noise, or else the layer Rand.
+func (ly *Layer) noiseRand(nrn *Neuron) randx.Rand {
+	if int(nrn.SubPool) >= len(ly.GateRands) {
+		return &ly.Rand
+	}
+	return &ly.GateRands[nrn.SubPool]
+}
+
+// InitGateNoise initializes the per-stripe gating noise streams of all
+// layers with PBWM.GateNoise.On (see Layer.InitGateNoise), using their
+// GateNoise.Seed if set, or else a base seed drawn from the network Rand.
+// Called in InitWeights.
+func (nt *Network) InitGateNoise() {
+	var seed int64
+	for _, ly := range nt.Layers {
+		if ly.Off || !ly.gateNoiseOn() {
+			continue
+		}
+		sd := ly.PBWM.GateNoise.Seed
+		if sd == 0 {
+			if seed == 0 {
+				seed = nt.Rand.Int63()
+			}
+			sd = seed
+		}
+		ly.InitGateNoise(sd)
+	}
+}
+
+// AllGateSeeds returns a listing of the GateSeeds of all layers using
+// per-stripe gating noise, one line per layer with the seeds in pool
+// order, which is saved in the params snapshot (see SaveParamsSnapshot),
+// or "" if there are none.  See SetAllGateSeeds to restore them.
+func (nt *Network) AllGateSeeds() string {
+	var b strings.Builder
+	for _, ly := range nt.Layers {
+		if ly.Off || len(ly.GateSeeds) == 0 {
+			continue
+		}
+		b.WriteString(ly.Name + "\t\tSeeds:")
+		for _, sd := range ly.GateSeeds {
+			b.WriteString(" " + strconv.FormatInt(sd, 10))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SetAllGateSeeds sets the GateSeeds of the layers from the given listing,
+// in the format of AllGateSeeds, e.g., after InitWeights, to reproduce
+// the gating noise of a previous run.
+func (nt *Network) SetAllGateSeeds(seeds string) error {
+	for _, ln := range strings.Split(seeds, "\n") {
+		if strings.TrimSpace(ln) == "" {
+			continue
+		}
+		lnm, sds, ok := strings.Cut(ln, "Seeds:")
+		if !ok {
+			return fmt.Errorf("SetAllGateSeeds: no Seeds: in line: %s", ln)
+		}
+		ly := nt.LayerByName(strings.TrimSpace(lnm))
+		if ly == nil {
+			return fmt.Errorf("SetAllGateSeeds: layer not found: %s", strings.TrimSpace(lnm))
+		}
+		var seeds []int64
+		for _, fs := range strings.Fields(sds) {
+			sd, err := strconv.ParseInt(fs, 10, 64)
+			if err != nil {
+				return fmt.Errorf("SetAllGateSeeds: %w", err)
+			}
+			seeds = append(seeds, sd)
+		}
+		if len(seeds) != len(ly.Pools) {
+			return fmt.Errorf("SetAllGateSeeds: layer %s has %d pools, not %d seeds", ly.Name, len(ly.Pools), len(seeds))
+		}
+		ly.SetGateSeeds(seeds)
+	}
+	return nil
+}
+
+// SaveAllGateSeeds saves the AllGateSeeds listing to given file,
+// if there are any.
+func (nt *Network) SaveAllGateSeeds(filename core.Filename) error {
+	str := nt.AllGateSeeds()
+	if str == "" {
+		return nil
+	}
+	return os.WriteFile(string(filename), []byte(str), 0666)
+}
+
+// OpenAllGateSeeds sets the GateSeeds of the layers from the given file,
+// as saved by SaveAllGateSeeds.  See SetAllGateSeeds.
+func (nt *Network) OpenAllGateSeeds(filename core.Filename) error {
+	b, err := os.ReadFile(string(filename))
+	if err != nil {
+		return err
+	}
+	return nt.SetAllGateSeeds(string(b))
+}
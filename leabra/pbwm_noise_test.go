@@ -0,0 +1,37 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestGateNoiseSeeds(t *testing.T) {
+	net := NewNetwork("GateNoise")
+	mtxGo, _, _, gpi, _ := net.AddDorsalBG("", 1, 2, 1, 1, 2)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	mtxGo.PBWM.GateNoise.On = true
+	gpi.PBWM.GateNoise.On = true
+	gpi.PBWM.GateNoise.Seed = 42
+	net.InitWeights()
+	if len(mtxGo.GateSeeds) != len(mtxGo.Pools) || mtxGo.GateSeeds[1] == mtxGo.GateSeeds[2] {
+		t.Errorf("GateSeeds: %v", mtxGo.GateSeeds)
+	}
+	if gpi.GateSeeds[1] != randStreamSeed(42, gpi.Name, 1) {
+		t.Errorf("GateSeeds: Seed not used: %v", gpi.GateSeeds)
+	}
+	r1 := mtxGo.GateRands[1].Int63()
+	seeds := net.AllGateSeeds()
+	net.InitWeights()
+	if err := net.SetAllGateSeeds(seeds); err != nil {
+		t.Fatal(err)
+	}
+	if r := mtxGo.GateRands[1].Int63(); r != r1 {
+		t.Errorf("SetAllGateSeeds: stream not reproduced: %d != %d", r, r1)
+	}
+}
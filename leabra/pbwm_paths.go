@@ -4,24 +4,24 @@
 
 package leabra
 
-import (
-	"cogentcore.org/core/math32"
-)
+import "github.com/emer/leabra/v2/fmath"
+
+import ()
 
 // Params for for trace-based learning in the MatrixTracePath
 type TraceParams struct {
 
 	// learning rate for all not-gated stripes, which learn in the opposite direction to the gated stripes, and typically with a slightly lower learning rate -- although there are different learning logics associated with each of these different not-gated cases, in practice the same learning rate for all works best, and is simplest
-	NotGatedLR float32 `default:"0.7" min:"0"`
+	NotGatedLR Float `default:"0.7" min:"0"`
 
 	// learning rate for gated, NoGo (D2), positive dopamine (weights decrease) -- this is the single most important learning parameter here -- by making this relatively small (but non-zero), an asymmetry in the role of Go vs. NoGo is established, whereby the NoGo pathway focuses largely on punishing and preventing actions associated with negative outcomes, while those assoicated with positive outcomes only very slowly get relief from this NoGo pressure -- this is critical for causing the model to explore other possible actions even when a given action SOMETIMES produces good results -- NoGo demands a very high, consistent level of good outcomes in order to have a net decrease in these avoidance weights.  Note that the gating signal applies to both Go and NoGo MSN's for gated stripes, ensuring learning is about the action that was actually selected (see not_ cases for logic for actions that were close but not taken)
-	GateNoGoPosLR float32 `default:"0.1" min:"0"`
+	GateNoGoPosLR Float `default:"0.1" min:"0"`
 
 	// decay driven by receiving unit ACh value, sent by CIN units, for reseting the trace
-	AChDecay float32 `min:"0" default:"0"`
+	AChDecay Float `min:"0" default:"0"`
 
 	// multiplier on trace activation for decaying prior traces -- new trace magnitude drives decay of prior trace -- if gating activation is low, then new trace can be low and decay is slow, so increasing this factor causes learning to be more targeted on recent gating changes
-	Decay float32 `min:"0" default:"1"`
+	Decay Float `min:"0" default:"1"`
 
 	// use the sigmoid derivative factor 2 * act * (1-act) in modulating learning -- otherwise just multiply by msn activation directly -- this is generally beneficial for learning to prevent weights from continuing to increase when activations are already strong (and vice-versa for decreases)
 	Deriv bool `default:"true"`
@@ -41,7 +41,7 @@ func (tp *TraceParams) Update() {
 // LrnFactor resturns multiplicative factor for level of msn activation.  If Deriv
 // is 2 * act * (1-act) -- the factor of 2 compensates for otherwise reduction in
 // learning from these factors.  Otherwise is just act.
-func (tp *TraceParams) LrnFactor(act float32) float32 {
+func (tp *TraceParams) LrnFactor(act Float) Float {
 	if !tp.Deriv {
 		return act
 	}
@@ -49,7 +49,7 @@ func (tp *TraceParams) LrnFactor(act float32) float32 {
 }
 
 // LrateMod returns the learning rate modulator based on gating, d2r, and posDa factors
-func (tp *TraceParams) LrateMod(gated, d2r, posDa bool) float32 {
+func (tp *TraceParams) LrateMod(gated, d2r, posDa bool) Float {
 	if !gated {
 		return tp.NotGatedLR
 	}
@@ -67,11 +67,8 @@ func (pt *Path) MatrixDefaults() {
 }
 
 func (pt *Path) ClearTrace() {
-	for si := range pt.Syns {
-		sy := &pt.Syns[si]
-		sy.NTr = 0
-		sy.Tr = 0
-	}
+	setValue(pt.Syns.NTr, 0)
+	setValue(pt.Syns.Tr, 0)
 }
 
 // DWtMatrix computes the weight change (learning) for MatrixPath.
@@ -79,28 +76,29 @@ func (pt *Path) DWtMatrix() {
 	slay := pt.Send
 	rlay := pt.Recv
 	d2r := (rlay.PBWM.DaR == D2R)
-	da := rlay.NeuroMod.DA
+	da := rlay.DaLearn()
 	ach := rlay.NeuroMod.ACh
 	gateActIdx, _ := NeuronVarIndexByName("GateAct")
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
+		trs := pt.Syns.Tr[st : st+nc]
+		ntrs := pt.Syns.NTr[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			ri := scons[ci]
 			rn := &rlay.Neurons[ri]
 			daLrn := rn.DALrn
 			// da := rlay.UnitValueByIndex(DA, int(ri)) // note: more efficient to just assume same for all units
 			// ach := rlay.UnitValueByIndex(ACh, int(ri))
 			gateAct := rlay.UnitValue1D(gateActIdx, int(ri), 0)
-			achDk := math32.Min(1, ach*pt.Trace.AChDecay)
-			tr := sy.Tr
+			achDk := fmath.Min(1, ach*pt.Trace.AChDecay)
+			tr := trs[ci]
 
-			dwt := float32(0)
+			dwt := Float(0)
 			if da != 0 {
 				dwt = daLrn * tr
 				if d2r && da > 0 && tr < 0 {
@@ -111,22 +109,22 @@ func (pt *Path) DWtMatrix() {
 			tr -= achDk * tr
 
 			newNTr := pt.Trace.LrnFactor(rn.Act) * sn.Act
-			ntr := float32(0)
+			ntr := Float(0)
 			if gateAct > 0 { // gated
 				ntr = newNTr
 			} else { // not-gated
 				ntr = -pt.Trace.NotGatedLR * newNTr // opposite sign for non-gated
 			}
 
-			decay := pt.Trace.Decay * math32.Abs(ntr) // decay is function of new trace
+			decay := pt.Trace.Decay * fmath.Abs(ntr) // decay is function of new trace
 			if decay > 1 {
 				decay = 1
 			}
 			tr += ntr - decay*tr
-			sy.Tr = tr
-			sy.NTr = ntr
+			trs[ci] = tr
+			ntrs[ci] = ntr
 
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 	}
 }
@@ -148,16 +146,15 @@ func (pt *Path) DWtDaHebb() {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			ri := scons[ci]
 			rn := &rlay.Neurons[ri]
 			da := rn.DALrn
 			dwt := da * rn.Act * sn.Act
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 	}
 }
@@ -5,8 +5,8 @@
 package leabra
 
 import (
-	"cogentcore.org/core/math32/minmax"
 	"github.com/emer/leabra/v2/fffb"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 // Pool contains computed values for FFFB inhibition, and various other state values for layers
@@ -22,32 +22,44 @@ type Pool struct {
 	Inhib fffb.Inhib
 
 	// minus phase average and max Act activation values, for ActAvg updt
-	ActM minmax.AvgMax32
+	ActM fmath.AvgMax
 
 	// plus phase average and max Act activation values, for ActAvg updt
-	ActP minmax.AvgMax32
+	ActP fmath.AvgMax
 
 	// running-average activation levels used for netinput scaling and adaptive inhibition
 	ActAvg ActAvg
 
 	//	Gate is gating state for PBWM layers
 	Gate GateState
+
+	// PredErr is the prediction error of Pulvinar layers with SendAttn
+	// layers: the mean absolute difference between plus and minus phase
+	// activations, integrated over trials by Attn.Tau.
+	PredErr Float
+
+	// Attn is the attentional modulation of the gain of the excitatory
+	// input of the units in this pool, which is multiplied by 1 + Attn,
+	// from the PredErr of Pulvinar layers with this layer in SendAttn.
+	Attn Float
 }
 
 func (pl *Pool) Init() {
 	pl.Inhib.Init()
 	pl.Gate.Init()
+	pl.PredErr = 0
+	pl.Attn = 0
 }
 
 // ActAvg are running-average activation levels used for netinput scaling and adaptive inhibition
 type ActAvg struct {
 
 	// running-average minus-phase activity -- used for adapting inhibition -- see ActAvgParams.Tau for time constant etc
-	ActMAvg float32
+	ActMAvg Float
 
 	// running-average plus-phase activity -- used for synaptic input scaling -- see ActAvgParams.Tau for time constant etc
-	ActPAvg float32
+	ActPAvg Float
 
 	// ActPAvg * ActAvgParams.Adjust -- adjusted effective layer activity directly used in synaptic input scaling
-	ActPAvgEff float32
+	ActPAvgEff Float
 }
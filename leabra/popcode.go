@@ -0,0 +1,209 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/popcode"
+)
+
+// PopCodeParams are parameters for encoding continuous values as
+// population codes over the units of a layer, and decoding them back
+// from the layer activity, using the emergent popcode package.
+// Each pool of a 4D layer encodes one value, so a vector of values
+// can be presented in one layer, and a 2D layer encodes one value over
+// all of its units.  With Is2D, each value is a 2D (X, Y) value encoded
+// over the Y, X units of the pool, otherwise it is a scalar encoded
+// over the units of the pool in order.  See Layer.ApplyValues and
+// DecodeValues, which replace the ad hoc mixing of binary patterns
+// for magnitudes.  A Rew layer with PopCode.On provides a graded
+// reward magnitude to the RW and TD reward layers, via RewValue.
+type PopCodeParams struct {
+
+	// use population coding of values in this layer
+	On bool
+
+	// encode 2D (X, Y) values over the Y, X units of each pool using TwoD, instead of scalar values using OneD
+	Is2D bool
+
+	// population code for scalar values
+	OneD popcode.OneD `display:"inline"`
+
+	// population code for 2D values
+	TwoD popcode.TwoD `display:"inline"`
+}
+
+func (pc *PopCodeParams) Update() {
+}
+
+func (pc *PopCodeParams) Defaults() {
+	pc.OneD.Defaults()
+	pc.TwoD.Defaults()
+}
+
+func (pc *PopCodeParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	case "OneD":
+		return pc.On && !pc.Is2D
+	case "TwoD":
+		return pc.On && pc.Is2D
+	default:
+		return pc.On
+	}
+}
+
+// popCodePools returns the pools that each encode one value:
+// the sub-pools of a 4D layer, or the layer pool otherwise.
+func (ly *Layer) popCodePools() []*Pool {
+	if len(ly.Pools) == 1 {
+		return []*Pool{&ly.Pools[0]}
+	}
+	pls := make([]*Pool, len(ly.Pools)-1)
+	for pi := range pls {
+		pls[pi] = &ly.Pools[pi+1]
+	}
+	return pls
+}
+
+// popCodePoolShape returns the Y, X shape of the units
+// within each pool encoding one value.
+func (ly *Layer) popCodePoolShape() []int {
+	if ly.Shape.NumDims() == 4 {
+		return []int{ly.Shape.DimSize(2), ly.Shape.DimSize(3)}
+	}
+	ny, nx, _, _ := tensor.Projection2DShape(&ly.Shape, false)
+	return []int{ny, nx}
+}
+
+// NumPopCodeValues returns the number of values encoded by the layer
+// with PopCode: the number of pools for a 4D layer, else 1.
+func (ly *Layer) NumPopCodeValues() int {
+	return len(ly.popCodePools())
+}
+
+// EncodeValues returns a pattern with the shape of the layer that encodes
+// the given scalar values using PopCode.OneD, one value per pool.
+// Returns an error if the number of values does not match NumPopCodeValues.
+func (ly *Layer) EncodeValues(vals ...float32) (*tensor.Float32, error) {
+	pls := ly.popCodePools()
+	if len(vals) != len(pls) {
+		return nil, fmt.Errorf("leabra.EncodeValues: layer %s encodes %d values, but %d were given", ly.Name, len(pls), len(vals))
+	}
+	pat := tensor.NewFloat32(ly.Shape.Sizes)
+	var vpat []float32
+	for pi, pl := range pls {
+		n := pl.EdIndex - pl.StIndex
+		ly.PopCode.OneD.Encode(&vpat, vals[pi], n, popcode.Set)
+		copy(pat.Values[pl.StIndex:pl.EdIndex], vpat)
+	}
+	return pat, nil
+}
+
+// EncodeValues2D returns a pattern with the shape of the layer that encodes
+// the given 2D values using PopCode.TwoD, one value per pool.
+// Returns an error if the number of values does not match NumPopCodeValues.
+func (ly *Layer) EncodeValues2D(vals ...math32.Vector2) (*tensor.Float32, error) {
+	pls := ly.popCodePools()
+	if len(vals) != len(pls) {
+		return nil, fmt.Errorf("leabra.EncodeValues2D: layer %s encodes %d values, but %d were given", ly.Name, len(pls), len(vals))
+	}
+	pat := tensor.NewFloat32(ly.Shape.Sizes)
+	vpat := tensor.NewFloat32(ly.popCodePoolShape())
+	for pi, pl := range pls {
+		if err := ly.PopCode.TwoD.Encode(vpat, vals[pi], popcode.Set); err != nil {
+			return nil, err
+		}
+		copy(pat.Values[pl.StIndex:pl.EdIndex], vpat.Values)
+	}
+	return pat, nil
+}
+
+// ApplyValues applies the population code of the given scalar values,
+// one per pool (see EncodeValues), as the external input to the layer
+// using ApplyExt, for Input and Target layers with PopCode.On.
+func (ly *Layer) ApplyValues(vals ...float32) error {
+	if !ly.PopCode.On || ly.PopCode.Is2D {
+		return fmt.Errorf("leabra.ApplyValues: layer %s does not have a scalar PopCode On", ly.Name)
+	}
+	pat, err := ly.EncodeValues(vals...)
+	if err != nil {
+		return err
+	}
+	ly.ApplyExt(pat)
+	return nil
+}
+
+// ApplyValues2D applies the population code of the given 2D values,
+// one per pool (see EncodeValues2D), as the external input to the layer
+// using ApplyExt, for Input and Target layers with PopCode.On and Is2D.
+func (ly *Layer) ApplyValues2D(vals ...math32.Vector2) error {
+	if !ly.PopCode.On || !ly.PopCode.Is2D {
+		return fmt.Errorf("leabra.ApplyValues2D: layer %s does not have a 2D PopCode On", ly.Name)
+	}
+	pat, err := ly.EncodeValues2D(vals...)
+	if err != nil {
+		return err
+	}
+	ly.ApplyExt(pat)
+	return nil
+}
+
+// DecodeValues decodes the scalar values encoded by the given neuron
+// variable (e.g., Act, ActM) using PopCode.OneD, one value per pool.
+func (ly *Layer) DecodeValues(varNm string) ([]float32, error) {
+	vidx, err := ly.UnitVarIndex(varNm)
+	if err != nil {
+		return nil, err
+	}
+	pls := ly.popCodePools()
+	vals := make([]float32, len(pls))
+	var vpat []float32
+	for pi, pl := range pls {
+		vpat = vpat[:0]
+		for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
+			vpat = append(vpat, ly.UnitValue1D(vidx, ni, 0))
+		}
+		vals[pi] = ly.PopCode.OneD.Decode(vpat)
+	}
+	return vals, nil
+}
+
+// DecodeValues2D decodes the 2D values encoded by the given neuron
+// variable (e.g., Act, ActM) using PopCode.TwoD, one value per pool.
+func (ly *Layer) DecodeValues2D(varNm string) ([]math32.Vector2, error) {
+	vidx, err := ly.UnitVarIndex(varNm)
+	if err != nil {
+		return nil, err
+	}
+	pls := ly.popCodePools()
+	vals := make([]math32.Vector2, len(pls))
+	vpat := tensor.NewFloat32(ly.popCodePoolShape())
+	for pi, pl := range pls {
+		for ni := pl.StIndex; ni < pl.EdIndex; ni++ {
+			vpat.Values[ni-pl.StIndex] = ly.UnitValue1D(vidx, ni, 0)
+		}
+		if vals[pi], err = ly.PopCode.TwoD.Decode(vpat); err != nil {
+			return nil, err
+		}
+	}
+	return vals, nil
+}
+
+// RewValue returns the reward magnitude represented by this reward
+// layer (e.g., Rew), for the RW and TD reward layers: the value decoded
+// from the activity of the first pool if it has a scalar PopCode.On,
+// else the activity of its first neuron.
+func (ly *Layer) RewValue() Float {
+	if !ly.PopCode.On || ly.PopCode.Is2D {
+		return ly.Neurons[0].Act
+	}
+	vals, _ := ly.DecodeValues("Act")
+	return Float(vals[0])
+}
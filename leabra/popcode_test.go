@@ -0,0 +1,72 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestPopCode(t *testing.T) {
+	net := NewNetwork("PopCode")
+	in := net.AddLayer2D("Input", 1, 12, InputLayer)
+	pos := net.AddLayer4D("Pos", 2, 1, 6, 6, InputLayer)
+	rew, rp, da := net.AddRWLayers("", 2)
+	rew.SetShape([]int{1, 12})
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	in.PopCode.On = true
+	pos.PopCode.On = true
+	pos.PopCode.Is2D = true
+	rew.PopCode.On = true
+	net.InitWeights()
+
+	if err := in.ApplyValues(0.2, 0.5); err == nil {
+		t.Errorf("ApplyValues should error for 2 values in a 2D layer")
+	}
+	if err := in.ApplyValues(0.7); err != nil {
+		t.Fatal(err)
+	}
+	vals := []math32.Vector2{{X: 0.2, Y: 0.8}, {X: 0.6, Y: 0.3}}
+	if err := pos.ApplyValues2D(vals...); err != nil {
+		t.Fatal(err)
+	}
+	if err := rew.ApplyValues(0.8); err != nil {
+		t.Fatal(err)
+	}
+	ctx := NewContext()
+	net.AlphaCycInit(true)
+	ctx.AlphaCycStart()
+	for range 25 {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	dec, err := in.DecodeValues("Act")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math32.Abs(dec[0]-0.7) > 0.05 {
+		t.Errorf("decoded value: got %g, want 0.7", dec[0])
+	}
+	dec2, err := pos.DecodeValues2D("Act")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range vals {
+		if math32.Abs(dec2[i].X-v.X) > 0.05 || math32.Abs(dec2[i].Y-v.Y) > 0.05 {
+			t.Errorf("decoded 2D value %d: got %v, want %v", i, dec2[i], v)
+		}
+	}
+	rv := rew.RewValue()
+	if math32.Abs(float32(rv)-0.8) > 0.05 {
+		t.Errorf("reward value: got %g, want 0.8", rv)
+	}
+	if dv := da.Neurons[0].Act; math32.Abs(float32(dv-(rv-rp.Neurons[0].Act))) > 1e-5 {
+		t.Errorf("RW DA should be the decoded reward minus the prediction: %g vs. %g - %g", dv, rv, rp.Neurons[0].Act)
+	}
+}
@@ -0,0 +1,194 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/params"
+)
+
+// Provenance records what actually ran in a run of a sim, to be able to
+// reconstruct it long after the fact: the version of the sim code, the
+// command-line args, the random seeds, the param sets applied and the
+// final parameter values, the config, and the network architecture.
+// Create with Network.NewProvenance at the start of the run, and Save
+// as a JSON sidecar file next to the log files (see ProvenanceFilename).
+type Provenance struct {
+
+	// Name is the name of the network.
+	Name string
+
+	// RunName is the name of the run, used for the log files
+	// (see emer.NetParams.RunName).
+	RunName string
+
+	// Start is the time at which the run started.
+	Start time.Time
+
+	// End is the time at which the run ended, or zero if not finished.
+	End time.Time
+
+	// Args are the command-line args of the sim (os.Args).
+	Args []string
+
+	// Dir is the working directory of the sim.
+	Dir string
+
+	// Host is the host name of the machine.
+	Host string
+
+	// GoVersion is the version of Go that the sim was built with.
+	GoVersion string
+
+	// Module is the module path of the sim main package.
+	Module string
+
+	// Version is the module version of the sim main package,
+	// which is (devel) if not built from a tagged module.
+	Version string
+
+	// Revision is the git commit of the sim code, from the build info,
+	// or from git in the working directory if not recorded in the build
+	// (e.g., with go run).
+	Revision string
+
+	// RevisionTime is the time of the Revision commit, if known.
+	RevisionTime string
+
+	// Modified is true if the sim code had uncommitted changes
+	// relative to the Revision.
+	Modified bool
+
+	// Deps are the versions of the module dependencies,
+	// as "path version", e.g., of leabra and emergent.
+	Deps []string
+
+	// NetSeed is the random seed of the network (Network.RandSeed).
+	NetSeed int64
+
+	// Seeds are the random seeds of the sim, e.g., of each run.
+	Seeds []int64
+
+	// GateSeeds are the seeds of the PBWM gating noise, if any
+	// (see Network.AllGateSeeds).
+	GateSeeds string `json:",omitempty"`
+
+	// Sheets are the names of the param sheets applied, in order.
+	Sheets []string
+
+	// Tag is the additional tag of the param sets (emer.NetParams.Tag).
+	Tag string `json:",omitempty"`
+
+	// Params are the full param sets of the sim.
+	Params params.Sets
+
+	// AllParams are the final values of all the parameters of the layers
+	// and pathways in the network, after applying the Params (see AllParams).
+	AllParams string
+
+	// Config is the config of the sim.
+	Config any
+
+	// Arch is the listing of the network architecture (see ArchReport).
+	Arch string
+}
+
+// NewProvenance returns a new Provenance for a run with the given
+// param sets (after they have been applied to the network), config, and
+// random seeds, starting now, with the version of the sim code from the
+// build info of the executable.
+func (nt *Network) NewProvenance(pars *emer.NetParams, cfg any, run int, seeds ...int64) *Provenance {
+	pv := &Provenance{Name: nt.Name, RunName: pars.RunName(run), Start: time.Now(), Args: os.Args}
+	pv.Dir, _ = os.Getwd()
+	pv.Host, _ = os.Hostname()
+	pv.GoVersion = runtime.Version()
+	pv.SetBuildInfo()
+	pv.NetSeed = nt.RandSeed
+	pv.Seeds = seeds
+	pv.GateSeeds = nt.AllGateSeeds()
+	pv.Sheets = append([]string{"Base"}, strings.Fields(pars.ExtraSheets)...)
+	pv.Tag = pars.Tag
+	pv.Params = pars.Params
+	pv.AllParams = nt.AllParams()
+	pv.Config = cfg
+	pv.Arch = nt.ArchReport()
+	return pv
+}
+
+// SetBuildInfo sets the module, version, git revision and dependencies
+// of the sim from the build info of the executable, getting the git
+// revision from git in the working directory if not in the build info.
+func (pv *Provenance) SetBuildInfo() {
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		pv.Module = bi.Main.Path
+		pv.Version = bi.Main.Version
+		for _, st := range bi.Settings {
+			switch st.Key {
+			case "vcs.revision":
+				pv.Revision = st.Value
+			case "vcs.time":
+				pv.RevisionTime = st.Value
+			case "vcs.modified":
+				pv.Modified = st.Value == "true"
+			}
+		}
+		pv.Deps = nil
+		for _, dp := range bi.Deps {
+			pv.Deps = append(pv.Deps, dp.Path+" "+dp.Version)
+		}
+	}
+	if pv.Revision != "" {
+		return
+	}
+	rev, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return
+	}
+	pv.Revision = strings.TrimSpace(string(rev))
+	if tm, err := exec.Command("git", "log", "-1", "--format=%cI").Output(); err == nil {
+		pv.RevisionTime = strings.TrimSpace(string(tm))
+	}
+	if st, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output(); err == nil {
+		pv.Modified = len(strings.TrimSpace(string(st))) > 0
+	}
+}
+
+// ProvenanceFilename returns the standard name of the provenance file
+// for the given network and run names, next to the log files.
+func ProvenanceFilename(netName, runName string) string {
+	return netName + "_" + runName + "_provenance.json"
+}
+
+// Save saves the provenance to the given file, as indented JSON.
+func (pv *Provenance) Save(filename string) error {
+	b, err := json.MarshalIndent(pv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, b, 0666)
+}
+
+// OpenProvenance opens a provenance file saved by Provenance.Save.
+// The Config is opened as a generic map.
+func OpenProvenance(filename string) (*Provenance, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	pv := &Provenance{}
+	err = json.Unmarshal(b, pv)
+	if err != nil {
+		return nil, err
+	}
+	return pv, nil
+}
@@ -0,0 +1,41 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/emer/emergent/v2/emer"
+)
+
+func TestProvenance(t *testing.T) {
+	net := MakeTestNet(t)
+	pars := &emer.NetParams{Params: ParamSets, ExtraSheets: "Fast Slow", Tag: "test"}
+	cfg := map[string]any{"NEpochs": 10}
+	pv := net.NewProvenance(pars, cfg, 2, 42, 43)
+	if pv.RunName != "test_Fast Slow_002" || len(pv.Sheets) != 3 || pv.Sheets[2] != "Slow" || pv.GoVersion == "" {
+		t.Errorf("NewProvenance: got RunName %q Sheets %v GoVersion %q", pv.RunName, pv.Sheets, pv.GoVersion)
+	}
+	fnm := filepath.Join(t.TempDir(), ProvenanceFilename(net.Name, pv.RunName))
+	if err := pv.Save(fnm); err != nil {
+		t.Fatal(err)
+	}
+	op, err := OpenProvenance(fnm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if op.Name != "TestNet" || !op.Start.Equal(pv.Start) || !slices.Equal(op.Seeds, []int64{42, 43}) || op.Arch != pv.Arch || op.AllParams != pv.AllParams || len(*op.Params["Base"]) != len(*ParamSets["Base"]) {
+		t.Errorf("OpenProvenance: got %+v", op)
+	}
+	if ne, _ := op.Config.(map[string]any)["NEpochs"].(float64); ne != 10 {
+		t.Errorf("Config: got %v", op.Config)
+	}
+	if !strings.Contains(op.AllParams, "Hidden") {
+		t.Errorf("AllParams: got %q", op.AllParams)
+	}
+}
@@ -0,0 +1,316 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+// Pruning removes the units of a trained network that make a negligible
+// contribution to the rest of the network, which have a low variance of
+// activity across inputs, and / or weak outgoing weights, to compress the
+// network for deployment, or to analyze the redundancy of the learned
+// representations (e.g., in CA3 / CA1).  Pruned units are removed from all
+// computation by setting the Off flag, as in LesionUnits, so they can be
+// restored with UnLesionUnits, and the operation is recorded in the
+// LesionLog.  The input that pruned units contributed on average to the
+// receiving units can be compensated by adjusting the remaining weights.
+
+// UnitActStats accumulates the mean and variance of the minus phase
+// activity (ActM) of each unit in a layer across trials, for
+// identifying units to prune.  Call Record at the end of each trial,
+// typically over a full epoch of testing trials.
+type UnitActStats struct {
+
+	// N is the number of trials recorded.
+	N int
+
+	// Sum is the sum of activity for each unit.
+	Sum []float64
+
+	// SumSq is the sum of squared activity for each unit.
+	SumSq []float64
+}
+
+// Reset resets the recorded stats.
+func (us *UnitActStats) Reset() {
+	us.N = 0
+	us.Sum = nil
+	us.SumSq = nil
+}
+
+// Record records the current ActM activity of the units in the layer.
+func (us *UnitActStats) Record(ly *Layer) {
+	nn := len(ly.Neurons)
+	if len(us.Sum) != nn {
+		us.N = 0
+		us.Sum = make([]float64, nn)
+		us.SumSq = make([]float64, nn)
+	}
+	for ni := range ly.Neurons {
+		act := float64(ly.Neurons[ni].ActM)
+		us.Sum[ni] += act
+		us.SumSq[ni] += act * act
+	}
+	us.N++
+}
+
+// Mean returns the mean activity of the given unit.
+func (us *UnitActStats) Mean(ni int) float64 {
+	if us.N == 0 {
+		return 0
+	}
+	return us.Sum[ni] / float64(us.N)
+}
+
+// Var returns the variance of the activity of the given unit.
+func (us *UnitActStats) Var(ni int) float64 {
+	if us.N == 0 {
+		return 0
+	}
+	mn := us.Mean(ni)
+	return max(us.SumSq[ni]/float64(us.N)-mn*mn, 0)
+}
+
+// PruneParams are the parameters for pruning units.
+type PruneParams struct {
+
+	// Thr is the threshold on the contribution of a unit, relative to the
+	// mean contribution across the units of the layer, below which units
+	// are pruned.  The contribution is the standard deviation of the unit's
+	// activity times the norm of its outgoing effective weights, which
+	// estimates how much it drives variation in the receiving units.
+	// For a layer with no sending pathways, only the activity is used.
+	Thr float64 `default:"0.1"`
+
+	// MaxProp is the maximum proportion of the units in the layer
+	// that can be pruned, with the lowest contribution units pruned first.
+	MaxProp float64 `default:"0.5" min:"0" max:"1"`
+
+	// Compensate adjusts the weights of the remaining sending units to
+	// each receiving unit to preserve the mean input from the pruned units,
+	// in proportion to the mean activity of the remaining units.
+	Compensate bool `default:"true"`
+}
+
+func (pp *PruneParams) Defaults() {
+	pp.Thr = 0.1
+	pp.MaxProp = 0.5
+	pp.Compensate = true
+}
+
+// UnitOutWtNorms returns the L2 norm of the outgoing effective weights
+// (Wt * Scale) of each unit in the layer, across all of its sending pathways.
+func (ly *Layer) UnitOutWtNorms() []float64 {
+	norms := make([]float64, len(ly.Neurons))
+	for _, pt := range ly.SendPaths {
+		if pt.Off {
+			continue
+		}
+		for si := range ly.Neurons {
+			nc := int(pt.SConN[si])
+			st := int(pt.SConIndexSt[si])
+			for ci := st; ci < st+nc; ci++ {
+				w := float64(pt.Syns.Wt[ci] * pt.Syns.Scale[ci])
+				norms[si] += w * w
+			}
+		}
+	}
+	for i, n := range norms {
+		norms[i] = math.Sqrt(n)
+	}
+	return norms
+}
+
+// UnitContribs returns the contribution of each unit in the layer, as the
+// standard deviation of its activity in the given stats times the norm of
+// its outgoing weights (see PruneParams.Thr).  Lesioned units have a
+// contribution of -1.
+func (ly *Layer) UnitContribs(st *UnitActStats) []float64 {
+	norms := ly.UnitOutWtNorms()
+	hasSend := false
+	for _, pt := range ly.SendPaths {
+		if !pt.Off {
+			hasSend = true
+		}
+	}
+	cons := make([]float64, len(ly.Neurons))
+	for ni := range ly.Neurons {
+		if ly.Neurons[ni].IsOff() {
+			cons[ni] = -1
+			continue
+		}
+		cons[ni] = math.Sqrt(st.Var(ni))
+		if hasSend {
+			cons[ni] *= norms[ni]
+		}
+	}
+	return cons
+}
+
+// PruneUnits prunes the units of the layer whose contribution (see
+// UnitContribs) is below PruneParams.Thr times the mean contribution,
+// given the activity stats recorded over a representative set of trials,
+// up to PruneParams.MaxProp of the units.  Returns the indexes of the
+// pruned units, which can be restored with UnLesionUnits, and records
+// the operation in the network LesionLog.
+func (ly *Layer) PruneUnits(st *UnitActStats, pp *PruneParams) ([]int, error) {
+	if len(st.Sum) != len(ly.Neurons) || st.N == 0 {
+		return nil, fmt.Errorf("leabra.PruneUnits: layer %s has no recorded activity stats", ly.Name)
+	}
+	cons := ly.UnitContribs(st)
+	var on []int
+	var mean float64
+	for ni, c := range cons {
+		if c >= 0 {
+			on = append(on, ni)
+			mean += c
+		}
+	}
+	if len(on) == 0 {
+		return nil, nil
+	}
+	mean /= float64(len(on))
+	sort.SliceStable(on, func(i, j int) bool { return cons[on[i]] < cons[on[j]] })
+	maxN := int(pp.MaxProp * float64(len(ly.Neurons)))
+	var idxs []int
+	for _, ni := range on {
+		if len(idxs) >= maxN || cons[ni] >= pp.Thr*mean {
+			break
+		}
+		idxs = append(idxs, ni)
+	}
+	if len(idxs) == 0 {
+		return nil, nil
+	}
+	slices.Sort(idxs)
+	if pp.Compensate {
+		ly.pruneCompensate(st, idxs)
+	}
+	ly.lesionUnits(idxs)
+	ly.addLesionRecord("PruneUnits", Float(pp.Thr), len(idxs), fmt.Sprintf("Indexes: %v", idxs))
+	return idxs, nil
+}
+
+// pruneCompensate adjusts the weights from the remaining units to each
+// receiving unit, to preserve the mean input from the pruned units,
+// in proportion to the mean activity of each remaining unit.
+func (ly *Layer) pruneCompensate(st *UnitActStats, idxs []int) {
+	pruned := make([]bool, len(ly.Neurons))
+	for _, ni := range idxs {
+		pruned[ni] = true
+	}
+	for _, pt := range ly.SendPaths {
+		if pt.Off {
+			continue
+		}
+		for ri := range pt.RConN {
+			nc := int(pt.RConN[ri])
+			st0 := int(pt.RConIndexSt[ri])
+			var lost, ssq float64
+			for ci := st0; ci < st0+nc; ci++ {
+				si := int(pt.RConIndex[ci])
+				if ly.Neurons[si].IsOff() {
+					continue
+				}
+				mn := st.Mean(si)
+				if pruned[si] {
+					syi := pt.RSynIndex[ci]
+					lost += mn * float64(pt.Syns.Wt[syi]*pt.Syns.Scale[syi])
+				} else {
+					ssq += mn * mn
+				}
+			}
+			if lost == 0 || ssq == 0 {
+				continue
+			}
+			for ci := st0; ci < st0+nc; ci++ {
+				si := int(pt.RConIndex[ci])
+				if pruned[si] || ly.Neurons[si].IsOff() {
+					continue
+				}
+				syi := int(pt.RSynIndex[ci])
+				sc := pt.Syns.Scale[syi]
+				if sc == 0 {
+					continue
+				}
+				dw := Float(lost * st.Mean(si) / ssq)
+				pt.Syns.Wt[syi] = fmath.Clamp(pt.Syns.Wt[syi]+dw/sc, 0, 1)
+				pt.LWtFromWt(syi)
+			}
+		}
+	}
+}
+
+// PruneReport is the result of pruning a set of layers,
+// with the performance before and after pruning.
+type PruneReport struct {
+
+	// Layers are the names of the pruned layers.
+	Layers []string
+
+	// NUnits is the number of units in each layer.
+	NUnits []int
+
+	// Pruned are the indexes of the pruned units in each layer.
+	Pruned [][]int
+
+	// Before is the performance prior to pruning.
+	Before float64
+
+	// After is the performance after pruning.
+	After float64
+}
+
+// String returns a report of the pruning.
+func (pr *PruneReport) String() string {
+	var b strings.Builder
+	tot, totPr := 0, 0
+	for li, lnm := range pr.Layers {
+		np := len(pr.Pruned[li])
+		fmt.Fprintf(&b, "%s:\tpruned %d of %d units (%.3g%%)\n", lnm, np, pr.NUnits[li], 100*float64(np)/float64(max(pr.NUnits[li], 1)))
+		tot += pr.NUnits[li]
+		totPr += np
+	}
+	fmt.Fprintf(&b, "Total:\tpruned %d of %d units  Performance before: %g  after: %g  change: %g\n", totPr, tot, pr.Before, pr.After, pr.After-pr.Before)
+	return b.String()
+}
+
+// PruneNetwork prunes the units of the given layers (see Layer.PruneUnits)
+// and reports the impact on performance, using the given record function
+// to run a representative set of trials (e.g., all testing trials) with
+// the stats Record method called at the end of each trial for each of
+// the layers, and the eval function to run the trials and return a
+// performance measure (e.g., percent correct).  The record function is
+// called first, followed by eval before and after pruning.
+func PruneNetwork(net *Network, layers []string, pp *PruneParams, record func(stats map[string]*UnitActStats), eval func(net *Network) float64) (*PruneReport, error) {
+	stats := make(map[string]*UnitActStats, len(layers))
+	for _, lnm := range layers {
+		if net.LayerByName(lnm) == nil {
+			return nil, fmt.Errorf("leabra.PruneNetwork: layer named %s not found", lnm)
+		}
+		stats[lnm] = &UnitActStats{}
+	}
+	record(stats)
+	pr := &PruneReport{Layers: layers}
+	pr.Before = eval(net)
+	for _, lnm := range layers {
+		ly := net.LayerByName(lnm)
+		idxs, err := ly.PruneUnits(stats[lnm], pp)
+		if err != nil {
+			return nil, err
+		}
+		pr.NUnits = append(pr.NUnits, len(ly.Neurons))
+		pr.Pruned = append(pr.Pruned, idxs)
+	}
+	pr.After = eval(net)
+	return pr, nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestPruneUnits(t *testing.T) {
+	net := NewNetwork("Prune")
+	in := net.AddLayer2D("Input", 1, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 1, 2, SuperLayer)
+	pt := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	for si := range pt.Syns.Wt {
+		pt.Syns.Wt[si] = 0.5
+		pt.Syns.Scale[si] = 1
+	}
+	// units 0-2 alternate between 1 and 0, unit 3 is constant at 0.5
+	st := &UnitActStats{N: 2, Sum: []float64{1, 1, 1, 1}, SumSq: []float64{1, 1, 1, 0.5}}
+	pp := &PruneParams{}
+	pp.Defaults()
+	pp.Thr = 0.5
+	idxs, err := in.PruneUnits(st, pp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(idxs) != 1 || idxs[0] != 3 || !in.Neurons[3].IsOff() {
+		t.Errorf("PruneUnits: pruned %v, not [3]", idxs)
+	}
+	// lost input 0.5 * 0.5 is spread over the 3 remaining units: 0.5 + 1/6
+	CmprFloats([]float32{pt.SynValue("Wt", 0, 0), pt.SynValue("Wt", 3, 0)}, []float32{0.6666667, 0.5}, "PruneUnits Compensate", t)
+	if len(net.LesionLog) == 0 || net.LesionLog[len(net.LesionLog)-1].Op != "PruneUnits" {
+		t.Errorf("PruneUnits: not recorded in LesionLog")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+// Random number streams: all of the random numbers used by the network
+// come from separate streams, derived from the network Rand (seeded with
+// SetRandSeed, or randx.Seeds.Set(run, &net.Rand) at the start of each run),
+// so that a given run reproduces identical results regardless of
+// what else is running in the same process, and in what order:
+//   - each layer has its own Rand stream (see InitRand), used for the
+//     initial weights of its sending pathways, noise and lesions.
+//   - paths.UniformRand and GaussTopo pathways get a RandSeed derived from
+//     the network RandSeed and pathway name, if not already set
+//     (see SetPathRandSeeds).
+//   - Matrix and GPiThal layers can have per-stripe gating noise streams
+//     (see GateNoiseParams).
+//   - the probabilistic bursting of deep layers uses a number per trial
+//     hashed from the BurstSeed (see BurstRand).
+// Environments and pattern generation must likewise use their own streams,
+// e.g., SpatialEnv.Rand, UniquePatterns.Rand and patgen.NewRand.
+
+// InitRand seeds the Rand stream of each layer from a base seed drawn
+// from the network Rand, hashed with the layer name, so that the stream
+// of each layer is independent of the number and order of layers,
+// and of threading.  Called at the start of InitWeights.
+func (nt *Network) InitRand() {
+	if nt.Rand.Rand == nil {
+		nt.ResetRandSeed()
+	}
+	seed := nt.Rand.Int63()
+	nt.BurstSeed = randStreamSeed(seed, "Burst", 0)
+	for _, ly := range nt.Layers {
+		ly.Rand.NewRand(randStreamSeed(seed, ly.Name, 0))
+	}
+}
+
+// SetPathRandSeeds sets the RandSeed of paths.UniformRand and GaussTopo
+// pathway patterns that do not have one set, from the network RandSeed hashed
+// with the pathway name, instead of the global random stream,
+// so that the connectivity is determined by the network RandSeed.
+// Called in Build.
+func (nt *Network) SetPathRandSeeds() {
+	for _, ly := range nt.Layers {
+		for _, pt := range ly.RecvPaths {
+			switch pat := pt.Pattern.(type) {
+			case *paths.UniformRand:
+				if pat.RandSeed == 0 {
+					pat.RandSeed = randStreamSeed(nt.RandSeed, pt.Name, 0)
+				}
+			case *GaussTopo:
+				if pat.RandSeed == 0 {
+					pat.RandSeed = randStreamSeed(nt.RandSeed, pt.Name, 0)
+				}
+			}
+		}
+	}
+}
+
+// randStreamSeed returns the seed for a random number stream with given
+// name and index (e.g., pool), as a hash of the base seed, name and index.
+func randStreamSeed(seed int64, name string, idx int) int64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], uint64(seed))
+	h.Write(b[:])
+	h.Write([]byte(name))
+	binary.LittleEndian.PutUint64(b[:], uint64(idx))
+	h.Write(b[:])
+	return int64(h.Sum64() >> 1)
+}
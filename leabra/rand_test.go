@@ -0,0 +1,62 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestRandStreams(t *testing.T) {
+	makeNet := func(seed int64, order []string) *Network {
+		net := NewNetwork("RandStreams")
+		net.SetRandSeed(seed)
+		lays := map[string]*Layer{}
+		for _, nm := range order {
+			lays[nm] = net.AddLayer2D(nm, 2, 3, SuperLayer)
+		}
+		net.ConnectLayers(lays["In"], lays["Hid"], paths.NewUniformRand(), ForwardPath)
+		net.ConnectLayers(lays["Hid"], lays["Out"], paths.NewFull(), ForwardPath)
+		if err := net.Build(); err != nil {
+			t.Error(err)
+		}
+		net.Defaults()
+		net.InitWeights()
+		return net
+	}
+	wts := func(net *Network) []float32 {
+		var all []float32
+		for _, nm := range []string{"InToHid", "HidToOut"} {
+			var vals []float32
+			pt := net.LayerByName(nm[len(nm)-3:]).RecvPaths[0]
+			pt.SynValues(&vals, "Wt")
+			all = append(all, vals...)
+		}
+		return all
+	}
+	want := wts(makeNet(3, []string{"In", "Hid", "Out"}))
+	// other random number use, layer order and concurrent runs must not matter
+	res := make([][]float32, 2)
+	done := make(chan bool)
+	for i := range res {
+		go func() {
+			res[i] = wts(makeNet(3, []string{"Out", "Hid", "In"}))
+			done <- true
+		}()
+	}
+	for range res {
+		<-done
+	}
+	for i, got := range res {
+		if !slices.Equal(got, want) {
+			t.Errorf("run %d weights differ:\n%v\n%v", i, got, want)
+		}
+	}
+	if slices.Equal(wts(makeNet(4, []string{"In", "Hid", "Out"})), want) {
+		t.Error("weights do not depend on the seed")
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"math"
+	"os"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// The raster format records the activity of a set of layers at every
+// cycle (or every Every cycles), as a compact gzip compressed binary file,
+// using the same encoding as the binary weights format (see weightsbin.go):
+//
+//	"LRST" uint32(version) uint32(bits) uint32(every) string(var) uint32(n layers)
+//	per layer: string(name) uint32(n dims) n * uint32(dim)
+//	per record: string(label) uint32(cycle) per layer: values
+//
+// where the values of each layer are the product of its dims, as uint8
+// quantized values of the [0, 1] range for 8 bits, or float32 values
+// for 32 bits.  Records continue until the end of the stream.
+
+// rasterMagic is the identifier at the start of raster files.
+const rasterMagic = "LRST"
+
+// rasterVersion is the current version of the raster format.
+const rasterVersion = 1
+
+// RasterRecorder records the activity of selected layers at every cycle
+// during headless (nogui) runs, as a spike raster / activity heatmap that
+// can be visualized after the fact (see OpenRaster), as the NetView does
+// live.  The records are streamed to a compact gzip compressed binary
+// file, with options to downsample in time (Every), space (PoolAvg)
+// and precision (Bits), so whole runs can be recorded.
+type RasterRecorder struct {
+
+	// Layers are the names of the layers to record.
+	Layers []string
+
+	// Var is the neuron variable to record.
+	Var string `default:"Act"`
+
+	// Every records only every this many cycles, downsampling in time.
+	Every int `default:"1" min:"1"`
+
+	// PoolAvg records the average value over the units of each pool of
+	// 4D layers, instead of each unit, downsampling in space.
+	PoolAvg bool
+
+	// Bits is the precision of the recorded values: 8 for values
+	// quantized to 256 levels of the [0, 1] range (clipped), which is
+	// sufficient for activations, or 32 for float32 values.
+	Bits int `default:"8"`
+
+	// File is the name of the raster file.
+	File string `edit:"-"`
+
+	// NRecs is the number of records written so far.
+	NRecs int `edit:"-"`
+
+	// net is the network being recorded.
+	net *Network
+
+	// fp is the open file.
+	fp *os.File
+
+	// gw is the gzip compressor of the file.
+	gw *gzip.Writer
+
+	// bw writes the values to the compressor.
+	bw *weightsBinaryWriter
+
+	// vals are the unit values of the current layer.
+	vals []float32
+
+	// qvals are the quantized values of the current layer, for 8 bits.
+	qvals []byte
+}
+
+func (rr *RasterRecorder) Defaults() {
+	rr.Var = "Act"
+	rr.Every = 1
+	rr.Bits = 8
+}
+
+// Init initializes the recorder for the given network, creating the given
+// raster file (e.g., ending in .raster.gz) and writing the header.
+// Any previously open file is closed first.
+func (rr *RasterRecorder) Init(net *Network, file string) error {
+	if rr.Var == "" {
+		rr.Defaults()
+	}
+	rr.Every = max(rr.Every, 1)
+	if rr.Bits != 32 {
+		rr.Bits = 8
+	}
+	if err := rr.Close(); err != nil {
+		return err
+	}
+	for _, lnm := range rr.Layers {
+		if net.LayerByName(lnm) == nil {
+			return fmt.Errorf("leabra.RasterRecorder: layer not found: %s", lnm)
+		}
+	}
+	fp, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	rr.net = net
+	rr.File = file
+	rr.NRecs = 0
+	rr.fp = fp
+	rr.gw = gzip.NewWriter(fp)
+	rr.bw = &weightsBinaryWriter{w: bufio.NewWriter(rr.gw), size: 4}
+	rr.bw.raw([]byte(rasterMagic))
+	rr.bw.uint32(rasterVersion)
+	rr.bw.uint32(uint32(rr.Bits))
+	rr.bw.uint32(uint32(rr.Every))
+	rr.bw.string(rr.Var)
+	rr.bw.uint32(uint32(len(rr.Layers)))
+	for _, lnm := range rr.Layers {
+		shp := rr.shape(net.LayerByName(lnm))
+		rr.bw.string(lnm)
+		rr.bw.uint32(uint32(len(shp)))
+		for _, d := range shp {
+			rr.bw.uint32(uint32(d))
+		}
+	}
+	return rr.bw.err
+}
+
+// shape returns the shape of the recorded values of the given layer.
+func (rr *RasterRecorder) shape(ly *Layer) []int {
+	if rr.PoolAvg && ly.Is4D() {
+		return []int{ly.Shape.DimSize(0), ly.Shape.DimSize(1)}
+	}
+	return ly.Shape.Sizes
+}
+
+// Record records the current values of the layers, with the given label
+// (e.g., the trial name) and cycle within the trial, if the cycle is a
+// multiple of Every.
+func (rr *RasterRecorder) Record(label string, cycle int) error {
+	if rr.bw == nil {
+		return errors.New("leabra.RasterRecorder: Init has not been called")
+	}
+	if cycle%rr.Every != 0 {
+		return nil
+	}
+	rr.bw.string(label)
+	rr.bw.uint32(uint32(cycle))
+	for _, lnm := range rr.Layers {
+		ly := rr.net.LayerByName(lnm)
+		ly.UnitValues(&rr.vals, rr.Var, 0)
+		vals := rr.vals[:len(ly.Neurons)]
+		if rr.PoolAvg && ly.Is4D() {
+			np := ly.Shape.DimSize(0) * ly.Shape.DimSize(1)
+			nu := len(vals) / np
+			for pi := range np {
+				sum := float32(0)
+				for _, v := range vals[pi*nu : (pi+1)*nu] {
+					sum += v
+				}
+				vals[pi] = sum / float32(nu)
+			}
+			vals = vals[:np]
+		}
+		if rr.Bits == 32 {
+			for _, v := range vals {
+				rr.bw.float(float64(v))
+			}
+			continue
+		}
+		rr.qvals = rr.qvals[:0]
+		for _, v := range vals {
+			q := byte(0)
+			if v > 0 {
+				q = byte(math.Round(float64(min(v, 1)) * 255))
+			}
+			rr.qvals = append(rr.qvals, q)
+		}
+		rr.bw.raw(rr.qvals)
+	}
+	rr.NRecs++
+	return rr.bw.err
+}
+
+// Close writes any buffered records and closes the file, if open.
+// It must be called at the end of the run.
+func (rr *RasterRecorder) Close() error {
+	if rr.fp == nil {
+		return nil
+	}
+	err := rr.bw.err
+	if ferr := rr.bw.w.Flush(); err == nil {
+		err = ferr
+	}
+	if gerr := rr.gw.Close(); err == nil {
+		err = gerr
+	}
+	if cerr := rr.fp.Close(); err == nil {
+		err = cerr
+	}
+	rr.fp, rr.gw, rr.bw = nil, nil, nil
+	return err
+}
+
+// LooperRecordRaster adds a function at the end of the Cycle loops in all
+// modes, to record the layers with the given RasterRecorder, with the label
+// returned by the given function (e.g., the trial name).
+func LooperRecordRaster(ls *looper.Stacks, rr *RasterRecorder, label func() string) {
+	for m := range ls.Stacks {
+		lp := ls.Loop(m, etime.Cycle)
+		if lp == nil {
+			continue
+		}
+		lp.OnEnd.Add("RasterRecord", func() {
+			errors.Log(rr.Record(label(), lp.Counter.Cur))
+		})
+	}
+}
+
+// Raster is the activity of a set of layers recorded by a RasterRecorder,
+// read from a raster file by OpenRaster.
+type Raster struct {
+
+	// Var is the neuron variable recorded.
+	Var string
+
+	// Every is the number of cycles between records.
+	Every int
+
+	// Bits is the precision of the recorded values: 8 or 32.
+	Bits int
+
+	// Layers are the names of the recorded layers.
+	Layers []string
+
+	// Shapes are the shapes of the recorded values of each layer,
+	// i.e., the pools for PoolAvg.
+	Shapes map[string][]int
+
+	// Labels are the labels of each record (e.g., the trial name).
+	Labels []string
+
+	// Cycles are the cycles of each record.
+	Cycles []int
+
+	// Data are the recorded values of each layer, as a [Rec][Unit]
+	// tensor, i.e., a heatmap of the activity of the units over time,
+	// with the units in the order of the Shapes.
+	Data map[string]*tensor.Float32
+}
+
+// OpenRaster opens a raster file saved by a RasterRecorder.
+func OpenRaster(filename core.Filename) (*Raster, error) {
+	fp, err := os.Open(string(filename))
+	if err != nil {
+		return nil, err
+	}
+	defer fp.Close()
+	return ReadRaster(fp)
+}
+
+// ReadRaster reads a raster saved by a RasterRecorder from the given reader.
+// A partial last record, e.g., of a run that crashed, is ignored.
+func ReadRaster(r io.Reader) (*Raster, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	br := &weightsBinaryReader{r: bufio.NewReader(gr), size: 4}
+	magic := br.raw(len(rasterMagic))
+	if br.err == nil && string(magic) != rasterMagic {
+		return nil, errors.New("leabra.ReadRaster: not a raster file")
+	}
+	ver := br.uint32()
+	if br.err == nil && ver > rasterVersion {
+		return nil, fmt.Errorf("leabra.ReadRaster: unsupported version: %d", ver)
+	}
+	rs := &Raster{Bits: int(br.uint32()), Every: int(br.uint32()), Var: br.string(), Shapes: make(map[string][]int), Data: make(map[string]*tensor.Float32)}
+	nlay := int(br.uint32())
+	nvals := make([]int, nlay)
+	for li := 0; li < nlay && br.err == nil; li++ {
+		lnm := br.string()
+		shp := make([]int, br.uint32())
+		nvals[li] = 1
+		for di := range shp {
+			shp[di] = int(br.uint32())
+			nvals[li] *= shp[di]
+		}
+		rs.Layers = append(rs.Layers, lnm)
+		rs.Shapes[lnm] = shp
+	}
+	if br.err != nil {
+		return nil, fmt.Errorf("leabra.ReadRaster: %w", br.err)
+	}
+	vals := make([][]float32, nlay)
+	for {
+		label := br.string()
+		cyc := int(br.uint32())
+		for li, nv := range nvals {
+			if rs.Bits == 32 {
+				for range nv {
+					vals[li] = append(vals[li], float32(br.float()))
+				}
+				continue
+			}
+			for _, q := range br.raw(nv) {
+				vals[li] = append(vals[li], float32(q)/255)
+			}
+		}
+		if br.err != nil {
+			break
+		}
+		rs.Labels = append(rs.Labels, label)
+		rs.Cycles = append(rs.Cycles, cyc)
+	}
+	if br.err != io.EOF && br.err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("leabra.ReadRaster: %w", br.err)
+	}
+	nrec := len(rs.Labels)
+	for li, lnm := range rs.Layers {
+		tsr := tensor.NewFloat32([]int{nrec, nvals[li]}, "Rec", "Unit")
+		copy(tsr.Values, vals[li])
+		rs.Data[lnm] = tsr
+	}
+	return rs, nil
+}
@@ -0,0 +1,76 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/core"
+)
+
+func TestRasterRecorder(t *testing.T) {
+	net := NewNetwork("Raster")
+	pools := net.AddLayer4D("Pools", 2, 1, 2, 1, InputLayer)
+	units := net.AddLayer2D("Units", 3, 1, InputLayer)
+	net.Build()
+	fnm := filepath.Join(t.TempDir(), "test.raster.gz")
+	for _, bits := range []int{8, 32} {
+		rr := RasterRecorder{Layers: []string{"Pools", "Units"}, Var: "Act", Every: 2, PoolAvg: true, Bits: bits}
+		if err := rr.Init(net, fnm); err != nil {
+			t.Fatal(err)
+		}
+		for cyc := range 5 {
+			for i := range pools.Neurons {
+				pools.Neurons[i].Act = Float(i) * 0.25
+			}
+			for i := range units.Neurons {
+				units.Neurons[i].Act = Float(cyc) * 0.3
+			}
+			if err := rr.Record("ab_0", cyc); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if err := rr.Close(); err != nil {
+			t.Fatal(err)
+		}
+		rs, err := OpenRaster(core.Filename(fnm))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rs.Bits != bits || rs.Every != 2 || rs.Var != "Act" || !slices.Equal(rs.Cycles, []int{0, 2, 4}) || rs.Labels[2] != "ab_0" {
+			t.Fatalf("raster header / records wrong: %d bits, every %d, %s, cycles %v", rs.Bits, rs.Every, rs.Var, rs.Cycles)
+		}
+		if !slices.Equal(rs.Shapes["Pools"], []int{2, 1}) || !slices.Equal(rs.Shapes["Units"], []int{3, 1}) {
+			t.Errorf("shapes wrong: %v", rs.Shapes)
+		}
+		tol := 1e-6
+		if bits == 8 {
+			tol = 0.5 / 255
+		}
+		pd := rs.Data["Pools"]
+		if pd.DimSize(0) != 3 || pd.DimSize(1) != 2 || math.Abs(pd.Float([]int{1, 0})-0.125) > tol || math.Abs(pd.Float([]int{1, 1})-0.625) > tol {
+			t.Errorf("%d bits: pool averages wrong: %v", bits, pd.Values)
+		}
+		ud := rs.Data["Units"]
+		if math.Abs(ud.Float([]int{1, 2})-0.6) > tol {
+			t.Errorf("%d bits: cycle 2 = %g, not 0.6", bits, ud.Float([]int{1, 2}))
+		}
+		clip := 1.0 // 1.2 clipped to 1 for 8 bits
+		if bits == 32 {
+			clip = 1.2
+		}
+		if math.Abs(ud.Float([]int{2, 0})-clip) > tol {
+			t.Errorf("%d bits: cycle 4 = %g, not %g", bits, ud.Float([]int{2, 0}), clip)
+		}
+	}
+
+	rr := RasterRecorder{Layers: []string{"Nope"}}
+	if err := rr.Init(net, fnm); err == nil {
+		t.Error("no error for missing layer")
+	}
+}
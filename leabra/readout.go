@@ -0,0 +1,222 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"sort"
+
+	"cogentcore.org/core/math32"
+)
+
+// ReadoutTypes are the types of readout transforms that can be applied
+// to unit values, e.g., for computing memory or decoding statistics.
+type ReadoutTypes int32 //enums:enum
+
+const (
+	// RawReadout returns the unit values without any transform.
+	RawReadout ReadoutTypes = iota
+
+	// ThreshReadout binarizes the unit values: 1 if the value is > Thr, else 0.
+	ThreshReadout
+
+	// TopKReadout binarizes the unit values by setting the K units with the
+	// highest values to 1, and the rest to 0.  Only units with values > Thr
+	// can be set to 1.
+	TopKReadout
+
+	// MaxNormReadout normalizes the unit values by dividing by the max value,
+	// so the max is 1.  Values <= Thr are set to 0 prior to normalizing.
+	MaxNormReadout
+
+	// SumNormReadout normalizes the unit values by dividing by their sum,
+	// so they sum to 1.  Values <= Thr are set to 0 prior to normalizing.
+	SumNormReadout
+)
+
+// ReadoutParams specify a readout transform of unit values,
+// such as thresholding, top-k binarization or normalization,
+// which is applied using Layer.UnitValuesReadout.
+type ReadoutParams struct {
+
+	// type of readout transform to apply
+	Type ReadoutTypes `default:"ThreshReadout"`
+
+	// threshold: for ThreshReadout, values above this are 1 and the rest 0;
+	// for the other types, values at or below this are set to 0
+	Thr float32 `default:"0.5"`
+
+	// number of units to set to 1 for TopKReadout (per pool if Pool is set)
+	K int `default:"1" min:"1"`
+
+	// apply the transform separately within each sub-pool of a 4D layer,
+	// instead of across the layer as a whole
+	Pool bool
+}
+
+func (ro *ReadoutParams) Update() {
+}
+
+func (ro *ReadoutParams) Defaults() {
+	ro.Type = ThreshReadout
+	ro.Thr = 0.5
+	ro.K = 1
+	ro.Update()
+}
+
+// Transform applies the readout transform to the given values, in place.
+func (ro *ReadoutParams) Transform(vals []float32) {
+	switch ro.Type {
+	case RawReadout:
+		return
+	case ThreshReadout:
+		for i, v := range vals {
+			vals[i] = ro.Binary(v)
+		}
+		return
+	case TopKReadout:
+		ro.topK(vals)
+		return
+	}
+	norm := float32(0)
+	for i, v := range vals {
+		if !(v > ro.Thr) {
+			vals[i] = 0
+			continue
+		}
+		if ro.Type == MaxNormReadout {
+			norm = math32.Max(norm, v)
+		} else {
+			norm += v
+		}
+	}
+	if norm == 0 {
+		return
+	}
+	for i := range vals {
+		vals[i] /= norm
+	}
+}
+
+// Binary returns 1 if the given value is > Thr, else 0.
+func (ro *ReadoutParams) Binary(v float32) float32 {
+	if v > ro.Thr {
+		return 1
+	}
+	return 0
+}
+
+// topK sets the K highest values above Thr to 1, and the rest to 0.
+func (ro *ReadoutParams) topK(vals []float32) {
+	idxs := make([]int, len(vals))
+	for i := range idxs {
+		idxs[i] = i
+	}
+	sort.SliceStable(idxs, func(i, j int) bool {
+		return vals[idxs[i]] > vals[idxs[j]]
+	})
+	for r, i := range idxs {
+		if r < ro.K && vals[i] > ro.Thr {
+			vals[i] = 1
+		} else {
+			vals[i] = 0
+		}
+	}
+}
+
+// UnitValuesReadout fills in values of given variable name on unit
+// for each unit in the layer, into given float32 slice (only resized if not big enough),
+// and then applies the given readout transform, separately for each sub-pool
+// if ro.Pool is set and the layer is 4D.
+// Returns error on invalid var name.
+func (ly *Layer) UnitValuesReadout(vals *[]float32, varNm string, ro *ReadoutParams, di int) error {
+	if err := ly.UnitValues(vals, varNm, di); err != nil {
+		return err
+	}
+	nn := len(ly.Neurons)
+	if !ro.Pool || !ly.Is4D() {
+		ro.Transform((*vals)[:nn])
+		return nil
+	}
+	for pi := 1; pi < len(ly.Pools); pi++ {
+		pl := &ly.Pools[pi]
+		ro.Transform((*vals)[pl.StIndex:pl.EdIndex])
+	}
+	return nil
+}
+
+// ReadoutMemStats are binary memory statistics comparing a readout
+// of the actual activity pattern against a target pattern, as used for
+// measuring pattern completion in the hippocampus, where a cue pattern
+// has some of the target units missing, and these must be completed.
+// The patterns are typically obtained using Layer.UnitValuesReadout,
+// and values > 0 are counted as on.
+type ReadoutMemStats struct {
+
+	// proportion of target-on units that were off in the activity pattern, for all units
+	TrgOnWasOffAll float32
+
+	// proportion of target-on units that were off in the activity pattern,
+	// only for those that required completion because they were off in the cue
+	TrgOnWasOffCmp float32
+
+	// proportion of target-off units that were on in the activity pattern
+	TrgOffWasOn float32
+
+	// number of target-on units that were off in the cue, requiring completion
+	CmpN int
+}
+
+// Compute computes the memory statistics based on given activity, target
+// and cue values, where values > 0 are on.
+// cue can be nil, in which case all target-on units require completion.
+func (ms *ReadoutMemStats) Compute(act, trg, cue []float32) {
+	*ms = ReadoutMemStats{}
+	trgOnN, trgOffN := 0, 0
+	for i, t := range trg {
+		on := act[i] > 0
+		if !(t > 0) {
+			trgOffN++
+			if on {
+				ms.TrgOffWasOn++
+			}
+			continue
+		}
+		trgOnN++
+		cmp := cue == nil || !(cue[i] > 0)
+		if cmp {
+			ms.CmpN++
+		}
+		if !on {
+			ms.TrgOnWasOffAll++
+			if cmp {
+				ms.TrgOnWasOffCmp++
+			}
+		}
+	}
+	if trgOnN > 0 {
+		ms.TrgOnWasOffAll /= float32(trgOnN)
+	}
+	if trgOffN > 0 {
+		ms.TrgOffWasOn /= float32(trgOffN)
+	}
+	if ms.CmpN > 0 {
+		ms.TrgOnWasOffCmp /= float32(ms.CmpN)
+	}
+}
+
+// Mem returns 1 if both the target-on-was-off proportion (for completion
+// units if cmp is true, otherwise all units) and the target-off-was-on
+// proportion are below the given threshold, indicating successful recall,
+// and 0 otherwise.
+func (ms *ReadoutMemStats) Mem(thr float32, cmp bool) float32 {
+	onOff := ms.TrgOnWasOffAll
+	if cmp {
+		onOff = ms.TrgOnWasOffCmp
+	}
+	if onOff < thr && ms.TrgOffWasOn < thr {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestReadoutTransform(t *testing.T) {
+	tests := []struct {
+		name string
+		ro   ReadoutParams
+		vals []float32
+		want []float32
+	}{
+		{"raw", ReadoutParams{Type: RawReadout, Thr: 0.5}, []float32{0.2, 0.9}, []float32{0.2, 0.9}},
+		{"thresh", ReadoutParams{Type: ThreshReadout, Thr: 0.5}, []float32{0.2, 0.5, 0.6, 1}, []float32{0, 0, 1, 1}},
+		{"topk", ReadoutParams{Type: TopKReadout, K: 2}, []float32{0.1, 0.9, 0.5, 0.8}, []float32{0, 1, 0, 1}},
+		{"topk ties first", ReadoutParams{Type: TopKReadout, K: 1}, []float32{0.2, 0.7, 0.7}, []float32{0, 1, 0}},
+		{"topk ties at K", ReadoutParams{Type: TopKReadout, K: 2}, []float32{0.5, 0.9, 0.5, 0.5}, []float32{1, 1, 0, 0}},
+		{"topk thr", ReadoutParams{Type: TopKReadout, Thr: 0.5, K: 3}, []float32{0.9, 0.4, 0.6, 0.5}, []float32{1, 0, 1, 0}},
+		{"topk none", ReadoutParams{Type: TopKReadout, Thr: 0.5, K: 2}, []float32{0.1, 0.2}, []float32{0, 0}},
+		{"maxnorm", ReadoutParams{Type: MaxNormReadout, Thr: 0.2}, []float32{0.1, 0.4, 0.8, 0.2}, []float32{0, 0.5, 1, 0}},
+		{"maxnorm none", ReadoutParams{Type: MaxNormReadout, Thr: 0.5}, []float32{0.1, 0.5}, []float32{0, 0}},
+		{"sumnorm", ReadoutParams{Type: SumNormReadout, Thr: 0.2}, []float32{0.1, 0.3, 0.5}, []float32{0, 0.375, 0.625}},
+		{"sumnorm none", ReadoutParams{Type: SumNormReadout}, []float32{0, 0}, []float32{0, 0}},
+	}
+	for _, tt := range tests {
+		vals := slices.Clone(tt.vals)
+		tt.ro.Transform(vals)
+		CmprFloats(vals, tt.want, tt.name, t)
+	}
+}
+
+func TestUnitValuesReadout(t *testing.T) {
+	net := NewNetwork("Readout")
+	ly := net.AddLayer4D("Layer", 1, 2, 1, 2, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	for ni, act := range []float32{0.3, 0.8, 0.6, 0.2} {
+		ly.Neurons[ni].Act = act
+	}
+	tests := []struct {
+		name string
+		ro   ReadoutParams
+		want []float32
+	}{
+		{"layer", ReadoutParams{Type: TopKReadout, K: 1}, []float32{0, 1, 0, 0}},
+		{"pool", ReadoutParams{Type: TopKReadout, K: 1, Pool: true}, []float32{0, 1, 1, 0}},
+		{"pool maxnorm", ReadoutParams{Type: MaxNormReadout, Pool: true}, []float32{0.375, 1, 1, 0.33333334}},
+	}
+	var vals []float32
+	for _, tt := range tests {
+		if err := ly.UnitValuesReadout(&vals, "Act", &tt.ro, 0); err != nil {
+			t.Fatal(err)
+		}
+		CmprFloats(vals, tt.want, tt.name, t)
+	}
+	ro := &ReadoutParams{}
+	if err := ly.UnitValuesReadout(&vals, "Nope", ro, 0); err == nil {
+		t.Error("UnitValuesReadout: expected error for invalid var name")
+	}
+}
+
+func TestReadoutMemStats(t *testing.T) {
+	tests := []struct {
+		name         string
+		act, trg     []float32
+		cue          []float32
+		want         ReadoutMemStats
+		memAll, memC float32
+	}{
+		{"perfect", []float32{1, 1, 0, 0}, []float32{1, 1, 0, 0}, []float32{1, 0, 0, 0},
+			ReadoutMemStats{CmpN: 1}, 1, 1},
+		{"missed cued", []float32{0, 1, 0, 0}, []float32{1, 1, 0, 0}, []float32{1, 0, 0, 0},
+			ReadoutMemStats{TrgOnWasOffAll: 0.5, CmpN: 1}, 0, 1},
+		{"missed completion", []float32{1, 0, 0, 0}, []float32{1, 1, 0, 0}, []float32{1, 0, 0, 0},
+			ReadoutMemStats{TrgOnWasOffAll: 0.5, TrgOnWasOffCmp: 1, CmpN: 1}, 0, 0},
+		{"intrusion", []float32{1, 1, 1, 0}, []float32{1, 1, 0, 0}, []float32{1, 0, 0, 0},
+			ReadoutMemStats{TrgOffWasOn: 0.5, CmpN: 1}, 0, 0},
+		{"no cue", []float32{1, 0, 0, 0}, []float32{1, 1, 0, 0}, nil,
+			ReadoutMemStats{TrgOnWasOffAll: 0.5, TrgOnWasOffCmp: 0.5, CmpN: 2}, 0, 0},
+		{"no target", []float32{0, 0}, []float32{0, 0}, nil,
+			ReadoutMemStats{}, 1, 1},
+	}
+	for _, tt := range tests {
+		var ms ReadoutMemStats
+		ms.Compute(tt.act, tt.trg, tt.cue)
+		if ms != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, ms, tt.want)
+		}
+		if m := ms.Mem(0.2, false); m != tt.memAll {
+			t.Errorf("%s: Mem all = %g, want %g", tt.name, m, tt.memAll)
+		}
+		if m := ms.Mem(0.2, true); m != tt.memC {
+			t.Errorf("%s: Mem cmp = %g, want %g", tt.name, m, tt.memC)
+		}
+	}
+}
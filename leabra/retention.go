@@ -0,0 +1,202 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+// RetentionTypes are the kinds of delay epochs run between training and
+// the delayed test sessions of a retention interval (see RetentionParams).
+type RetentionTypes int32 //enums:enum
+
+const (
+	// RetainNoInput runs the delay trials with no external input, so that
+	// only the spontaneous activity of the network drives any learning.
+	RetainNoInput RetentionTypes = iota
+
+	// RetainNoise runs the delay trials with random binary noise patterns
+	// as the input and target patterns, with PctAct active units.
+	RetainNoise
+
+	// RetainInterfere runs the delay trials on the rows of an interfering
+	// list of patterns, i.e., retroactive interference from new learning.
+	RetainInterfere
+)
+
+// RetentionParams are parameters for testing the retention of learned
+// items over a series of retention intervals after training, to measure
+// forgetting curves within one run: after training, the items are tested
+// immediately (delay 0), and then again after each of the cumulative
+// numbers of Delays epochs, during which the network is run with the
+// given Type of delay trials, with learning if Learn.  The test results
+// at each delay are recorded in a RetentionCurve.
+type RetentionParams struct {
+
+	// run retention interval tests after training
+	On bool
+
+	// kind of delay trials: no input, noise input, or an interfering list
+	Type RetentionTypes
+
+	// cumulative numbers of delay epochs after training at which the items are tested again, in increasing order; if empty, 1, 2, 5, 10
+	Delays []int
+
+	// proportion of active units in the RetainNoise patterns
+	PctAct float32 `default:"0.2" min:"0" max:"1"`
+
+	// learn during the delay trials
+	Learn bool `default:"true"`
+}
+
+func (rp *RetentionParams) Update() {
+}
+
+func (rp *RetentionParams) Defaults() {
+	rp.PctAct = 0.2
+	rp.Learn = true
+}
+
+func (rp *RetentionParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	case "PctAct":
+		return rp.On && rp.Type == RetainNoise
+	default:
+		return rp.On
+	}
+}
+
+// DelayEpochs returns the cumulative numbers of delay epochs at
+// which to test, using the default 1, 2, 5, 10 if Delays is empty.
+func (rp *RetentionParams) DelayEpochs() []int {
+	if len(rp.Delays) == 0 {
+		return []int{1, 2, 5, 10}
+	}
+	return rp.Delays
+}
+
+// Run runs the retention intervals after training: it calls test for
+// the immediate test at delay 0, and then for each of the DelayEpochs,
+// runs the additional delay epochs of nTrials trials each (see RunDelay)
+// and calls test again, with the cumulative delay in epochs.  interfere
+// is the table of interfering patterns for RetainInterfere.
+// The delay trials use Network.AlphaCycle with a separate Context,
+// so they are not logged, and should only be used for networks
+// that do not depend on special looper events for their trials.
+func (rp *RetentionParams) Run(net *Network, nTrials int, interfere *table.Table, rnd randx.Rand, test func(delay int)) error {
+	test(0)
+	prv := 0
+	for _, d := range rp.DelayEpochs() {
+		if d < prv {
+			return fmt.Errorf("leabra.RetentionParams: Delays must be increasing: %v", rp.Delays)
+		}
+		if err := rp.RunDelay(net, d-prv, nTrials, interfere, rnd); err != nil {
+			return err
+		}
+		prv = d
+		test(d)
+	}
+	return nil
+}
+
+// RunDelay runs the given number of delay epochs of nTrials trials each,
+// with the inputs given by Type, learning if Learn.  For RetainNoise,
+// each Input and Target layer gets a new random pattern on each trial,
+// and for RetainInterfere, the rows of the interfering table are applied
+// in a new random order in each epoch (see Network.ApplyTableRow).
+func (rp *RetentionParams) RunDelay(net *Network, epochs, nTrials int, interfere *table.Table, rnd randx.Rand) error {
+	if rp.Type == RetainInterfere && (interfere == nil || interfere.Rows == 0) {
+		return fmt.Errorf("leabra.RetentionParams: RetainInterfere requires an interfering table")
+	}
+	ctx := NewContext()
+	ctx.Mode = etime.Train
+	lays := net.LayersByType(InputLayer, TargetLayer)
+	var pat []float32
+	var order []int
+	for range epochs {
+		if rp.Type == RetainInterfere {
+			order = rnd.Perm(interfere.Rows)
+		}
+		for trl := range nTrials {
+			switch rp.Type {
+			case RetainNoInput:
+				net.InitExt()
+			case RetainNoise:
+				net.InitExt()
+				for _, lnm := range lays {
+					ly := net.LayerByName(lnm)
+					n := len(ly.Neurons)
+					pat = slices.Grow(pat[:0], n)[:n]
+					clear(pat)
+					for _, i := range rnd.Perm(n)[:patgen.NFromPct(rp.PctAct, n)] {
+						pat[i] = 1
+					}
+					ly.ApplyExt1D32(pat)
+				}
+			case RetainInterfere:
+				if err := net.ApplyTableRow(interfere, order[trl%len(order)], nil); err != nil {
+					return err
+				}
+			}
+			net.AlphaCycle(ctx, rp.Learn)
+		}
+	}
+	return nil
+}
+
+// RetentionCurve records the forgetting curves measured with
+// RetentionParams, in a Table with a row for each test, with the Run,
+// the cumulative Delay in epochs, and the recorded test stats.
+type RetentionCurve struct {
+
+	// Table is the forgetting curve table.
+	Table *table.Table
+}
+
+// Reset resets the curve table, with columns for the given stats.
+func (rc *RetentionCurve) Reset(stats ...string) {
+	dt := table.NewTable()
+	dt.SetMetaData("name", "Retention")
+	dt.AddIntColumn("Run")
+	dt.AddIntColumn("Delay")
+	for _, st := range stats {
+		dt.AddFloat64Column(st)
+	}
+	rc.Table = dt
+}
+
+// Record adds a row for the test at the given run and delay, with the
+// values of the stats of the curve from the last row of the given log
+// table (e.g., the test epoch log).
+func (rc *RetentionCurve) Record(run, delay int, log *table.Table) error {
+	dt := rc.Table
+	if dt == nil {
+		return fmt.Errorf("leabra.RetentionCurve: Reset has not been called")
+	}
+	if log.Rows == 0 {
+		return fmt.Errorf("leabra.RetentionCurve: log table %s has no rows", log.MetaData["name"])
+	}
+	lrow := log.Rows - 1
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetFloat("Run", row, float64(run))
+	dt.SetFloat("Delay", row, float64(delay))
+	for _, st := range dt.ColumnNames[2:] {
+		cl, err := log.ColumnByName(st)
+		if err != nil {
+			return err
+		}
+		dt.SetFloat(st, row, cl.Float1D(lrow))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestRetention(t *testing.T) {
+	net := NewNetwork("Retention")
+	in := net.AddLayer2D("Input", 5, 5, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	out := net.AddLayer2D("Output", 5, 5, TargetLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.BidirConnectLayers(hid, out, paths.NewFull())
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	pt := hid.RecvPaths[0]
+	sumWt := func() float64 {
+		var sum float64
+		for si := range pt.Syns.Wt {
+			sum += float64(pt.Syns.Wt[si])
+		}
+		return sum
+	}
+
+	lt := table.NewTable()
+	lt.AddFloat64Column("SumWt")
+	test := func(delay int) {
+		lt.SetNumRows(lt.Rows + 1)
+		lt.SetFloat("SumWt", lt.Rows-1, sumWt())
+	}
+	rp := &RetentionParams{}
+	rp.Defaults()
+	rp.Type = RetainNoise
+	rp.Delays = []int{1, 3}
+	rc := &RetentionCurve{}
+	rc.Reset("SumWt")
+	rnd := randx.NewSysRand(1)
+	err := rp.Run(net, 4, nil, rnd, func(delay int) {
+		test(delay)
+		if err := rc.Record(0, delay, lt); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dt := rc.Table
+	if dt.Rows != 3 {
+		t.Fatalf("curve rows: %d, want 3", dt.Rows)
+	}
+	for row, d := range []int{0, 1, 3} {
+		if int(dt.Float("Delay", row)) != d {
+			t.Errorf("row %d Delay: %g, want %d", row, dt.Float("Delay", row), d)
+		}
+	}
+	if dt.Float("SumWt", 0) == dt.Float("SumWt", 2) {
+		t.Errorf("weights did not change with noise delay learning")
+	}
+
+	// no learning leaves the weights unchanged
+	rp.Learn = false
+	wt := sumWt()
+	if err := rp.RunDelay(net, 2, 4, nil, rnd); err != nil {
+		t.Fatal(err)
+	}
+	if sumWt() != wt {
+		t.Errorf("weights changed without learning")
+	}
+
+	rp.Type = RetainInterfere
+	if err := rp.RunDelay(net, 1, 4, nil, rnd); err == nil {
+		t.Errorf("expected error for RetainInterfere without interfering table")
+	}
+	rp.Delays = []int{2, 1}
+	rp.Type = RetainNoInput
+	if err := rp.Run(net, 1, nil, rnd, func(delay int) {}); err == nil {
+		t.Errorf("expected error for decreasing Delays")
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"cogentcore.org/core/tensor/table"
+)
+
+// RetrievalDynamics decodes the time course of memory retrieval within
+// a trial, by comparing the activity of a layer at every cycle with a
+// set of stored reference patterns (e.g., the layer's activity on each
+// training trial), using cosine similarity.  For each cycle it records
+// the similarity to the target reference (the one for the current trial),
+// the maximum similarity to any other reference (the strongest
+// competitor, e.g., the paired item from the other list), and whether
+// the target is the nearest.  These are averaged across trials of the
+// same type (e.g., ab, ac, lure) to produce retrieval dynamics curves.
+// Call StartTrial at the start of each trial, RecordCycle after each
+// cycle, and EndTrial at the end of the trial.
+type RetrievalDynamics struct {
+
+	// Layer is the name of the layer to decode.
+	Layer string
+
+	// Var is the neuron variable to decode (Act by default).
+	Var string
+
+	// Refs are the stored reference patterns.
+	Refs *NearestPatterns
+
+	// Target is the name of the target reference for the current trial.
+	Target string
+
+	// TargetCos is the cosine similarity to the Target for each cycle
+	// of the current trial (0 if the target is not in Refs).
+	TargetCos []float32
+
+	// OtherCos is the maximum cosine similarity to any reference other
+	// than the Target for each cycle of the current trial.
+	OtherCos []float32
+
+	// Nearest is the name of the nearest reference for each cycle
+	// of the current trial.
+	Nearest []string
+
+	// Types are the trial types in the order first recorded by EndTrial.
+	Types []string
+
+	// Curves are the accumulated curves for each trial type.
+	Curves map[string]*RetrievalCurve
+
+	// cos are the cosines to each reference for the current cycle.
+	cos []float32
+
+	// acts are the unit values for the current cycle.
+	acts []float32
+}
+
+// RetrievalCurve has the sums of the per-cycle retrieval stats
+// over trials of a given type, for computing the mean curves.
+type RetrievalCurve struct {
+
+	// N is the number of trials for each cycle.
+	N []int
+
+	// TargetCos is the sum of the target cosine for each cycle.
+	TargetCos []float64
+
+	// OtherCos is the sum of the maximum other cosine for each cycle.
+	OtherCos []float64
+
+	// Correct is the number of trials where the target was the nearest, for each cycle.
+	Correct []float64
+}
+
+// NewRetrievalDynamics returns a new RetrievalDynamics for given layer,
+// decoding the Act variable using the given reference patterns.
+func NewRetrievalDynamics(layer string, refs *NearestPatterns) *RetrievalDynamics {
+	return &RetrievalDynamics{Layer: layer, Var: "Act", Refs: refs}
+}
+
+// Reset resets the accumulated curves, e.g., at the start of a test epoch.
+func (rd *RetrievalDynamics) Reset() {
+	rd.Types = nil
+	rd.Curves = nil
+}
+
+// StartTrial starts recording a new trial with given target reference name.
+func (rd *RetrievalDynamics) StartTrial(target string) {
+	rd.Target = target
+	rd.TargetCos = rd.TargetCos[:0]
+	rd.OtherCos = rd.OtherCos[:0]
+	rd.Nearest = rd.Nearest[:0]
+}
+
+// RecordCycle records the retrieval stats for the current state of the
+// layer in the given network.
+func (rd *RetrievalDynamics) RecordCycle(net *Network) {
+	ly := net.LayerByName(rd.Layer)
+	if ly == nil || rd.Refs == nil {
+		return
+	}
+	ly.UnitValues(&rd.acts, rd.Var, 0)
+	rd.Refs.CosAll(rd.acts, &rd.cos)
+	ti := rd.Refs.Index(rd.Target)
+	tcos := float32(0)
+	ocos := float32(0)
+	near := ""
+	ncos := float32(-1)
+	for pi, c := range rd.cos {
+		if pi == ti {
+			tcos = c
+		} else {
+			ocos = max(ocos, c)
+		}
+		if c > ncos {
+			ncos = c
+			near = rd.Refs.Names[pi]
+		}
+	}
+	rd.TargetCos = append(rd.TargetCos, tcos)
+	rd.OtherCos = append(rd.OtherCos, ocos)
+	rd.Nearest = append(rd.Nearest, near)
+}
+
+// SettleCycle returns the first cycle of the current trial from which
+// the nearest reference stays the same through the last recorded cycle,
+// i.e., the cycle when retrieval settled, and the name of that reference,
+// or -1 and "" if no cycles have been recorded.
+func (rd *RetrievalDynamics) SettleCycle() (int, string) {
+	n := len(rd.Nearest)
+	if n == 0 {
+		return -1, ""
+	}
+	near := rd.Nearest[n-1]
+	cyc := n - 1
+	for cyc > 0 && rd.Nearest[cyc-1] == near {
+		cyc--
+	}
+	return cyc, near
+}
+
+// EndTrial accumulates the current trial into the curves for given trial type.
+func (rd *RetrievalDynamics) EndTrial(trialType string) {
+	if rd.Curves == nil {
+		rd.Curves = make(map[string]*RetrievalCurve)
+	}
+	rc, has := rd.Curves[trialType]
+	if !has {
+		rc = &RetrievalCurve{}
+		rd.Curves[trialType] = rc
+		rd.Types = append(rd.Types, trialType)
+	}
+	for cyc, tc := range rd.TargetCos {
+		if cyc >= len(rc.N) {
+			rc.N = append(rc.N, 0)
+			rc.TargetCos = append(rc.TargetCos, 0)
+			rc.OtherCos = append(rc.OtherCos, 0)
+			rc.Correct = append(rc.Correct, 0)
+		}
+		rc.N[cyc]++
+		rc.TargetCos[cyc] += float64(tc)
+		rc.OtherCos[cyc] += float64(rd.OtherCos[cyc])
+		if rd.Nearest[cyc] == rd.Target {
+			rc.Correct[cyc]++
+		}
+	}
+}
+
+// CurvesTable returns a table with the mean retrieval curves for each
+// trial type, with a Cycle column, and <type>_TargetCos, <type>_OtherCos
+// and <type>_PctCor columns for each type, with the layer name as a
+// prefix if prefix is true, e.g., CA3_ab_TargetCos.
+func (rd *RetrievalDynamics) CurvesTable(prefix bool) *table.Table {
+	dt := table.NewTable()
+	rd.AddToTable(dt, prefix)
+	return dt
+}
+
+// AddToTable adds the mean retrieval curves for each trial type to
+// the given table, as in CurvesTable, adding the Cycle column and rows
+// if not already present, so that curves for multiple layers can be
+// combined in one table.
+func (rd *RetrievalDynamics) AddToTable(dt *table.Table, prefix bool) {
+	ncyc := 0
+	for _, rc := range rd.Curves {
+		ncyc = max(ncyc, len(rc.N))
+	}
+	if _, err := dt.ColumnByName("Cycle"); err != nil {
+		dt.AddIntColumn("Cycle")
+	}
+	if dt.Rows < ncyc {
+		dt.SetNumRows(ncyc)
+	}
+	for cyc := range dt.Rows {
+		dt.SetFloat("Cycle", cyc, float64(cyc))
+	}
+	pfx := ""
+	if prefix {
+		pfx = rd.Layer + "_"
+	}
+	for _, tt := range rd.Types {
+		rc := rd.Curves[tt]
+		nms := []string{"TargetCos", "OtherCos", "PctCor"}
+		vals := [][]float64{rc.TargetCos, rc.OtherCos, rc.Correct}
+		for i, nm := range nms {
+			cnm := pfx + tt + "_" + nm
+			if _, err := dt.ColumnByName(cnm); err != nil {
+				dt.AddFloat64Column(cnm)
+			}
+			for cyc := range dt.Rows {
+				v := 0.0
+				if cyc < len(rc.N) && rc.N[cyc] > 0 {
+					v = vals[i][cyc] / float64(rc.N[cyc])
+				}
+				dt.SetFloat(cnm, cyc, v)
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestRetrievalDynamics(t *testing.T) {
+	net := NewNetwork("Retrieval")
+	ly := net.AddLayer2D("CA3", 1, 4, SuperLayer)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	refs := &NearestPatterns{}
+	refs.Set("a", []float32{1, 0, 0, 0})
+	refs.Set("b", []float32{0, 1, 0, 0})
+	rd := NewRetrievalDynamics("CA3", refs)
+	if cyc, near := rd.SettleCycle(); cyc != -1 || near != "" {
+		t.Errorf("SettleCycle with no cycles: %d %q", cyc, near)
+	}
+
+	// trial records the given activity states as cycles of a trial.
+	trial := func(target string, acts ...[]float32) {
+		rd.StartTrial(target)
+		for _, act := range acts {
+			for ni, v := range act {
+				ly.Neurons[ni].Act = v
+			}
+			rd.RecordCycle(net)
+		}
+	}
+	trial("a", []float32{0, 0, 0, 0}, []float32{0, 1, 0, 0}, []float32{1, 0.5, 0, 0}, []float32{1, 0, 0, 0})
+	CmprFloats(rd.TargetCos, []float32{0, 0, 0.8944272, 1}, "TargetCos", t)
+	CmprFloats(rd.OtherCos, []float32{0, 1, 0.4472136, 0}, "OtherCos", t)
+	if want := []string{"a", "b", "a", "a"}; !slices.Equal(rd.Nearest, want) {
+		t.Errorf("Nearest: got %v, want %v", rd.Nearest, want)
+	}
+	if cyc, near := rd.SettleCycle(); cyc != 2 || near != "a" {
+		t.Errorf("SettleCycle: got %d %q, want 2 a", cyc, near)
+	}
+	rd.EndTrial("ab")
+
+	trial("a", []float32{0, 1, 0, 0}, []float32{0, 1, 0, 0})
+	if cyc, near := rd.SettleCycle(); cyc != 0 || near != "b" {
+		t.Errorf("SettleCycle: got %d %q, want 0 b", cyc, near)
+	}
+	rd.EndTrial("ab")
+	trial("b", []float32{0, 0, 1, 0})
+	rd.EndTrial("lure")
+
+	if !slices.Equal(rd.Types, []string{"ab", "lure"}) {
+		t.Errorf("Types: %v", rd.Types)
+	}
+	ab := rd.Curves["ab"]
+	if !slices.Equal(ab.N, []int{2, 2, 1, 1}) || !slices.Equal(ab.Correct, []float64{1, 0, 1, 1}) {
+		t.Errorf("ab curve: N %v Correct %v", ab.N, ab.Correct)
+	}
+	dt := rd.CurvesTable(true)
+	if dt.Rows != 4 {
+		t.Fatalf("CurvesTable rows: %d", dt.Rows)
+	}
+	for cyc, want := range []float64{0.5, 0, 1, 1} {
+		if v := dt.Float("CA3_ab_PctCor", cyc); v != want {
+			t.Errorf("CA3_ab_PctCor cycle %d: got %g, want %g", cyc, v, want)
+		}
+	}
+	if v := dt.Float("CA3_ab_OtherCos", 1); v != 1 {
+		t.Errorf("CA3_ab_OtherCos cycle 1: got %g, want 1", v)
+	}
+	if v := dt.Float("CA3_lure_TargetCos", 1); v != 0 {
+		t.Errorf("CA3_lure_TargetCos past end of trials: got %g, want 0", v)
+	}
+	rd.Reset()
+	if rd.Curves != nil || rd.Types != nil {
+		t.Error("Reset did not reset the curves")
+	}
+}
+
+func TestRetrievalDynamicsSettle(t *testing.T) {
+	net := NewNetwork("Retrieval")
+	in := net.AddLayer2D("Input", 2, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 4, SuperLayer)
+	net.ConnectLayers(in, hid, paths.NewOneToOne(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	hid.Act.Init.Decay = 0 // carry activity over from the previous trial
+	net.InitWeights()
+	pats := [][]float32{{1, 1, 0, 0, 0, 0, 0, 0}, {0, 0, 0, 0, 1, 1, 0, 0}}
+
+	// settle runs a trial with given input pattern, recording each cycle.
+	rd := NewRetrievalDynamics("Hidden", &NearestPatterns{})
+	settle := func(pi int, target string) {
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt1D32(pats[pi])
+		net.AlphaCycInit(false)
+		ctx.AlphaCycStart()
+		rd.StartTrial(target)
+		for range 3 * ctx.CycPerQtr {
+			net.Cycle(ctx)
+			ctx.CycleInc()
+			rd.RecordCycle(net)
+		}
+	}
+	// the settled states are the references
+	var act []float32
+	refs := &NearestPatterns{}
+	for pi, nm := range []string{"a", "b"} {
+		settle(pi, "")
+		hid.UnitValues(&act, "Act", 0)
+		refs.Set(nm, act)
+	}
+	rd.Refs = refs
+	// activity starts out near b, carried over from the previous trial
+	settle(0, "a")
+	n := len(rd.TargetCos)
+	if rd.Nearest[0] != "b" {
+		t.Errorf("first Nearest: got %s, want b: %v", rd.Nearest[0], rd.Nearest)
+	}
+	if cos := rd.TargetCos[n-1]; cos < 0.999 {
+		t.Errorf("final TargetCos: got %g, want 1", cos)
+	}
+	if rd.TargetCos[0] >= rd.TargetCos[n-1] {
+		t.Errorf("TargetCos should increase over settling: %v", rd.TargetCos)
+	}
+	cyc, near := rd.SettleCycle()
+	if near != "a" || cyc <= 0 || cyc >= n-1 {
+		t.Fatalf("SettleCycle: got %d %q of %d cycles", cyc, near, n)
+	}
+	for c := cyc; c < n; c++ {
+		if rd.Nearest[c] != "a" {
+			t.Fatalf("Nearest not a after SettleCycle %d: %v", cyc, rd.Nearest)
+		}
+	}
+	if rd.Nearest[cyc-1] == "a" {
+		t.Errorf("Nearest already a before SettleCycle %d: %v", cyc, rd.Nearest)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"strings"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/actrf"
+)
+
+// Receptive fields of the units of a layer, for analyzing what the units
+// encode, can be computed in two ways:
+//
+//   - Activity-based receptive fields (actRF), with estats.Stats.InitActRFs
+//     and UpdateActRFs, which average the activity of a source layer (or
+//     any other pattern) weighted by the activity of each unit, in the
+//     Stats.ActRFs, e.g., "DG:Input".
+//   - Weight projections, with Network.WeightProjection and WeightRFs,
+//     which project the weights of each unit back onto the units of a
+//     source layer, through any number of layers in between (multi-hop),
+//     e.g., "CA3:DG:ECin".
+//
+// Both are [RecvY, RecvX, SendY, SendX] tensors, with the units of 4D
+// layers in their 2D projection, which are displayed as grids of the
+// source layer with egui.GUI.AddActRFGridTabs.
+
+// WeightMatrix returns the matrix of the weights from all the units of
+// the send layer to all the units of the recv layer, as [recv][send]
+// flat unit indexes, summed over all the pathways from send to recv
+// (zero for unconnected units).  Returns an error if there are none.
+func (nt *Network) WeightMatrix(recv, send string) ([][]float32, error) {
+	rl := nt.LayerByName(recv)
+	sl := nt.LayerByName(send)
+	if rl == nil || sl == nil {
+		return nil, fmt.Errorf("leabra.WeightMatrix: layer %s or %s not found", recv, send)
+	}
+	mat := make([][]float32, len(rl.Neurons))
+	for ri := range mat {
+		mat[ri] = make([]float32, len(sl.Neurons))
+	}
+	npt := 0
+	for _, pt := range rl.RecvPaths {
+		if pt.Send != sl || pt.Off {
+			continue
+		}
+		npt++
+		wts := pt.Syns.Wt
+		for si := range sl.Neurons {
+			nc := int(pt.SConN[si])
+			st := int(pt.SConIndexSt[si])
+			for ci := range nc {
+				ri := int(pt.SConIndex[st+ci])
+				mat[ri][si] += float32(wts[st+ci])
+			}
+		}
+	}
+	if npt == 0 {
+		return nil, fmt.Errorf("leabra.WeightMatrix: no pathway from %s to %s", send, recv)
+	}
+	return mat, nil
+}
+
+// WeightProjection returns the projection of the weights of the units of
+// the first of the given layers back onto the units of the last one,
+// through the layers in between, for multi-hop projections
+// (e.g., "CA3", "DG", "ECin"), as the product of the WeightMatrix of
+// each pair of successive layers.  The result is a [RecvY, RecvX,
+// SendY, SendX] tensor, as for actrf.RF, suitable for grid display.
+func (nt *Network) WeightProjection(layers ...string) (*tensor.Float32, error) {
+	if len(layers) < 2 {
+		return nil, fmt.Errorf("leabra.WeightProjection: need at least 2 layers, have %v", layers)
+	}
+	proj, err := nt.WeightMatrix(layers[0], layers[1])
+	if err != nil {
+		return nil, err
+	}
+	for li := 2; li < len(layers); li++ {
+		wm, err := nt.WeightMatrix(layers[li-1], layers[li])
+		if err != nil {
+			return nil, err
+		}
+		ns := len(wm[0])
+		for ri, pr := range proj {
+			np := make([]float32, ns)
+			for mi, pw := range pr {
+				if pw == 0 {
+					continue
+				}
+				for si, w := range wm[mi] {
+					np[si] += pw * w
+				}
+			}
+			proj[ri] = np
+		}
+	}
+	rl := nt.LayerByName(layers[0])
+	sl := nt.LayerByName(layers[len(layers)-1])
+	rNy, rNx, _, _ := tensor.Projection2DShape(&rl.Shape, false)
+	sNy, sNx, _, _ := tensor.Projection2DShape(&sl.Shape, false)
+	tsr := tensor.NewFloat32([]int{rNy, rNx, sNy, sNx}, "RecvY", "RecvX", "SendY", "SendX")
+	for ry := range rNy {
+		for rx := range rNx {
+			ri := tensor.Projection2DIndex(&rl.Shape, false, ry, rx)
+			for sy := range sNy {
+				for sx := range sNx {
+					si := tensor.Projection2DIndex(&sl.Shape, false, sy, sx)
+					tsr.Set([]int{ry, rx, sy, sx}, proj[ri][si])
+				}
+			}
+		}
+	}
+	return tsr, nil
+}
+
+// WeightRFs sets the weight projection receptive fields in the given
+// RFs for each of the given specs, as colon-separated layer names from
+// the receiving layer back to the source layer (see WeightProjection),
+// e.g., "CA3:DG:ECin", which is the name of the RF.  The projection is
+// in the RF, and its unit normalized version in the NormRF, for display
+// with egui.GUI.AddActRFGridTabs.  The RFs are added if not already
+// present, and otherwise updated for the current weights.  These must
+// be separate RFs from the activity-based ones (estats.Stats.ActRFs),
+// which are computed from accumulated activity by Avg.
+func (nt *Network) WeightRFs(rfs *actrf.RFs, specs ...string) error {
+	for _, spec := range specs {
+		lays := strings.Split(spec, ":")
+		proj, err := nt.WeightProjection(lays...)
+		if err != nil {
+			return err
+		}
+		rf, err := rfs.RFByName(spec)
+		if err != nil {
+			rl := nt.LayerByName(lays[0])
+			sl := nt.LayerByName(lays[len(lays)-1])
+			rf = rfs.AddRF(spec, tensor.NewFloat32(rl.Shape.Sizes), tensor.NewFloat32(sl.Shape.Sizes))
+		}
+		rf.RF.CopyFrom(proj)
+		rf.Norm()
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/emer/emergent/v2/actrf"
+)
+
+func TestWeightProjection(t *testing.T) {
+	net := MakeTestNet(t)
+	hid := net.LayerByName("Hidden")
+	out := net.LayerByName("Output")
+	inHidi, _ := hid.RecvPathBySendName("Input")
+	inHid := inHidi.(*Path)
+	hidOuti, _ := out.RecvPathBySendName("Hidden")
+	hidOut := hidOuti.(*Path)
+
+	wm, err := net.WeightMatrix("Hidden", "Input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ri := range 4 {
+		for si := range 4 {
+			trg := float32(0)
+			if ri == si {
+				trg = inHid.SynValue("Wt", si, ri)
+			}
+			if wm[ri][si] != trg {
+				t.Errorf("WeightMatrix[%d][%d] = %g, not %g", ri, si, wm[ri][si], trg)
+			}
+		}
+	}
+
+	proj, err := net.WeightProjection("Output", "Hidden", "Input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(proj.Shape().Sizes, []int{4, 1, 4, 1}) {
+		t.Fatalf("projection shape = %v, not [4 1 4 1]", proj.Shape().Sizes)
+	}
+	for ui := range 4 {
+		trg := hidOut.SynValue("Wt", ui, ui) * inHid.SynValue("Wt", ui, ui)
+		if v := proj.Value([]int{ui, 0, ui, 0}); math.Abs(float64(v-trg)) > 1e-6 {
+			t.Errorf("projection of unit %d = %g, not %g", ui, v, trg)
+		}
+		if v := proj.Value([]int{ui, 0, (ui + 1) % 4, 0}); v != 0 {
+			t.Errorf("projection of unit %d off-diagonal = %g, not 0", ui, v)
+		}
+	}
+
+	var rfs actrf.RFs
+	if err := net.WeightRFs(&rfs, "Output:Hidden:Input"); err != nil {
+		t.Fatal(err)
+	}
+	rf, err := rfs.RFByName("Output:Hidden:Input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := rf.NormRF.Value([]int{1, 0, 1, 0}); v != 1 {
+		t.Errorf("normalized RF = %g, not 1", v)
+	}
+
+	if _, err := net.WeightMatrix("Input", "Hidden"); err == nil {
+		t.Error("no error for missing pathway")
+	}
+	if _, err := net.WeightProjection("Output", "Nope"); err == nil {
+		t.Error("no error for missing layer")
+	}
+}
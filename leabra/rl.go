@@ -8,8 +8,8 @@ import (
 	"fmt"
 
 	"cogentcore.org/core/base/errors"
-	"cogentcore.org/core/math32/minmax"
 	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
 )
 
 ////////  RW
@@ -18,7 +18,7 @@ type RWParams struct {
 	// PredRange is the range of predictions that can be represented by the [RWRewPredLayer].
 	// Having a truncated range preserves some sensitivity in dopamine at the extremes
 	// of good or poor performance.
-	PredRange minmax.F32
+	PredRange fmath.Range
 
 	// RewLay is the reward layer name, for [RWDaLayer], from which DA is obtained.
 	// If nothing clamped, no dopamine computed.
@@ -75,7 +75,7 @@ func (ly *Layer) ActFromGRWDa(ctx *Context) {
 	if rnrn.HasFlag(NeurHasExt) {
 		hasRew = true
 	}
-	ract := rnrn.Act
+	ract := rly.RewValue()
 	pnrn := &(ply.Neurons[0])
 	pact := pnrn.Act
 	for ni := range ly.Neurons {
@@ -95,6 +95,9 @@ func (ly *Layer) ActFromGRWDa(ctx *Context) {
 // AddRWLayers adds simple Rescorla-Wagner (PV only) dopamine system, with a primary
 // Reward layer, a RWPred prediction layer, and a dopamine layer that computes diff.
 // Only generates DA when Rew layer has external input -- otherwise zero.
+// For graded reward magnitudes, the Rew layer can instead be a row of
+// units (SetShape before Build) with PopCode.On, with rewards applied
+// using ApplyValues.
 func (nt *Network) AddRWLayers(prefix string, space float32) (rew, rp, da *Layer) {
 	rew = nt.AddLayer2D(prefix+"Rew", 1, 1, InputLayer)
 	rp = nt.AddLayer2D(prefix+"RWPred", 1, 1, RWPredLayer)
@@ -121,16 +124,15 @@ func (pt *Path) RWDefaults() {
 func (pt *Path) DWtRW() {
 	slay := pt.Send
 	rlay := pt.Recv
-	lda := rlay.NeuroMod.DA
+	lda := rlay.DaLearn()
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
 		scons := pt.SConIndex[st : st+nc]
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			ri := scons[ci]
 			rn := &rlay.Neurons[ri]
 
@@ -143,7 +145,7 @@ func (pt *Path) DWtRW() {
 			}
 
 			dwt := da * sn.Act // no recv unit activation
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 	}
 }
@@ -154,7 +156,7 @@ func (pt *Path) DWtRW() {
 type TDParams struct {
 
 	// discount factor -- how much to discount the future prediction from RewPred.
-	Discount float32
+	Discount Float
 
 	// name of [TDPredLayer] to get reward prediction from.
 	PredLay string
@@ -204,13 +206,17 @@ func (ly *Layer) ActFromGTDInteg(ctx *Context) {
 	}
 	rpActP := rply.Neurons[0].ActP
 	rpAct := rply.Neurons[0].Act
+	rew, popRew := ly.TDIntegRew()
 	for ni := range ly.Neurons {
 		nrn := &ly.Neurons[ni]
 		if nrn.IsOff() {
 			continue
 		}
 		if ctx.Quarter == 3 { // plus phase
-			nrn.Act = nrn.Ge + ly.TD.Discount*rpAct
+			if !popRew {
+				rew = nrn.Ge
+			}
+			nrn.Act = rew + ly.TD.Discount*rpAct
 		} else {
 			nrn.Act = rpActP // previous actP
 		}
@@ -218,6 +224,24 @@ func (ly *Layer) ActFromGTDInteg(ctx *Context) {
 	}
 }
 
+// TDIntegRew returns the reward r(t) from a sending reward layer with
+// a scalar PopCode.On, as decoded by RewValue (0 if it has no external
+// input), and true if there is such a layer, in which case it is used
+// instead of the Ge from the reward pathway.
+func (ly *Layer) TDIntegRew() (Float, bool) {
+	for _, pt := range ly.RecvPaths {
+		sl := pt.Send
+		if pt.Off || !sl.PopCode.On || sl.PopCode.Is2D {
+			continue
+		}
+		if !sl.Neurons[0].HasFlag(NeurHasExt) {
+			return 0, true
+		}
+		return sl.RewValue(), true
+	}
+	return 0, false
+}
+
 func (ly *Layer) TDIntegLayer() (*Layer, error) {
 	tly := ly.Network.LayerByName(ly.TD.IntegLay)
 	if tly == nil {
@@ -263,26 +287,28 @@ func (pt *Path) TDPredDefaults() {
 func (pt *Path) DWtTDPred() {
 	slay := pt.Send
 	rlay := pt.Recv
-	da := rlay.NeuroMod.DA
+	da := rlay.DaLearn()
 	for si := range slay.Neurons {
 		sn := &slay.Neurons[si]
 		nc := int(pt.SConN[si])
 		st := int(pt.SConIndexSt[si])
-		syns := pt.Syns[st : st+nc]
+		dwts := pt.Syns.DWt[st : st+nc]
 		// scons := pj.SConIndex[st : st+nc]
 
-		for ci := range syns {
-			sy := &syns[ci]
+		for ci := range dwts {
 			// ri := scons[ci]
 			dwt := da * sn.ActQ0 // no recv unit activation, prior trial act
-			sy.DWt += pt.Learn.Lrate * dwt
+			dwts[ci] += pt.Learn.Lrate * dwt
 		}
 	}
 }
 
 // AddTDLayers adds the standard TD temporal differences layers, generating a DA signal.
 // Pathway from Rew to RewInteg is given class TDToInteg -- should
-// have no learning and 1 weight.
+// have no learning and 1 weight.  For graded reward magnitudes, the Rew
+// layer can instead be a row of units (SetShape before Build) with
+// PopCode.On, with rewards applied using ApplyValues, which are decoded
+// by the Integ layer.
 func (nt *Network) AddTDLayers(prefix string, space float32) (rew, rp, ri, td *Layer) {
 	rew = nt.AddLayer2D(prefix+"Rew", 1, 1, InputLayer)
 	rp = nt.AddLayer2D(prefix+"Pred", 1, 1, TDPredLayer)
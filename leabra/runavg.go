@@ -0,0 +1,128 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import "github.com/emer/leabra/v2/fmath"
+
+import ()
+
+// TinyValue is the magnitude below which decaying running-average values
+// are flushed to 0.  Exponential decay toward 0 in float32 otherwise
+// ends up in the subnormal (denormal) range, which is very slow to
+// compute with on most CPUs, and gets stuck there indefinitely because
+// the decrement rounds to 0.  Over long runs, this affects the activation
+// averages of inactive neurons, and the momentum and DWt normalization
+// values of synapses that are not learning.
+const TinyValue = 1e-30
+
+// flushTiny returns 0 if x is smaller in magnitude than TinyValue, else x.
+func flushTiny(x Float) Float {
+	if x < TinyValue && x > -TinyValue {
+		return 0
+	}
+	return x
+}
+
+// isBad returns true if x is NaN or infinite.
+func isBad(x Float) bool {
+	return fmath.IsNaN(x) || fmath.IsInf(x, 0)
+}
+
+// renormValue resets x to init if it is NaN or infinite, else flushes
+// tiny values to 0, returning 1 if x was changed, else 0.
+func renormValue(x *Float, init Float) int {
+	switch {
+	case isBad(*x):
+		*x = init
+		return 1
+	case *x != 0 && flushTiny(*x) == 0:
+		*x = 0
+		return 1
+	}
+	return 0
+}
+
+// RenormRunningAvgs sanitizes the running-average values that persist
+// across trials in the layer: the neuron learning averages (AvgSS, AvgS,
+// AvgM, AvgSLrn, AvgL, AvgLLrn, ActAvg), the pool ActAvg values, and the
+// CosDiff averages.  Values that are NaN or infinite are reset to their
+// initial values, tiny values (see TinyValue) are flushed to 0,
+// and AvgL and the pool averages are restored to their valid ranges.
+// Returns the number of values that were changed.
+func (ly *Layer) RenormRunningAvgs() int {
+	n := 0
+	la := &ly.Learn.ActAvg
+	al := &ly.Learn.AvgL
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		n += renormValue(&nrn.AvgSS, la.Init)
+		n += renormValue(&nrn.AvgS, la.Init)
+		n += renormValue(&nrn.AvgM, la.Init)
+		n += renormValue(&nrn.AvgSLrn, 0)
+		n += renormValue(&nrn.ActAvg, la.Init)
+		n += renormValue(&nrn.AvgL, al.Init)
+		if nrn.AvgL < al.Min {
+			nrn.AvgL = al.Min
+			n++
+		}
+		if isBad(nrn.AvgLLrn) {
+			nrn.AvgLLrn = al.LrnFact * (nrn.AvgL - al.Min)
+			n++
+		}
+	}
+	ia := &ly.Inhib.ActAvg
+	for pi := range ly.Pools {
+		pa := &ly.Pools[pi].ActAvg
+		for _, v := range []*Float{&pa.ActMAvg, &pa.ActPAvg} {
+			if isBad(*v) || *v <= 0 {
+				*v = ia.Init
+				n++
+			}
+		}
+		if isBad(pa.ActPAvgEff) || pa.ActPAvgEff <= 0 {
+			ia.EffFromAvg(&pa.ActPAvgEff, pa.ActPAvg)
+			n++
+		}
+	}
+	cd := &ly.CosDiff
+	n += renormValue(&cd.Avg, 0)
+	n += renormValue(&cd.Var, 0)
+	if cd.Var < 0 {
+		cd.Var = 0
+		n++
+	}
+	return n
+}
+
+// RenormRunningAvgs sanitizes the synaptic running-average values
+// (Moment and Norm) in the pathway: values that are NaN or infinite
+// are reset to 0, and tiny values (see TinyValue) are flushed to 0.
+// Returns the number of values that were changed.
+func (pt *Path) RenormRunningAvgs() int {
+	n := 0
+	for i := range pt.Syns.Moment {
+		n += renormValue(&pt.Syns.Moment[i], 0)
+		n += renormValue(&pt.Syns.Norm[i], 0)
+	}
+	return n
+}
+
+// RenormRunningAvgs sanitizes the running-average values in all layers
+// and pathways in the network, which can be called periodically
+// (e.g., every epoch) in very long continual-learning runs, to ensure
+// that they remain numerically sound.  See Layer.RenormRunningAvgs and
+// Path.RenormRunningAvgs.  Returns the number of values that were changed,
+// which should normally be 0 as the running-average updates flush tiny
+// values themselves: non-zero values indicate numerical problems.
+func (nt *Network) RenormRunningAvgs() int {
+	n := 0
+	for _, ly := range nt.Layers {
+		n += ly.RenormRunningAvgs()
+		for _, pt := range ly.RecvPaths {
+			n += pt.RenormRunningAvgs()
+		}
+	}
+	return n
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+func TestRenormRunningAvgs(t *testing.T) {
+	testNet := MakeTestNet(t)
+	if n := testNet.RenormRunningAvgs(); n != 0 {
+		t.Errorf("RenormRunningAvgs changed %d values in initialized network", n)
+	}
+	hidLay := testNet.LayerByName("Hidden")
+	hidLay.Neurons[0].AvgL = fmath.NaN()
+	hidLay.Neurons[1].AvgM = 1.0e-40
+	hidLay.Pools[0].ActAvg.ActPAvg = fmath.Inf(1)
+	hidLay.CosDiff.Var = -0.1
+	fmIn := hidLay.RecvPaths[0]
+	fmIn.Syns.Moment[0] = fmath.NaN()
+	fmIn.Syns.Norm[1] = 1.0e-35
+	if n := testNet.RenormRunningAvgs(); n != 6 {
+		t.Errorf("RenormRunningAvgs changed %d values, not 6", n)
+	}
+	got := []Float{hidLay.Neurons[0].AvgL, hidLay.Neurons[1].AvgM, hidLay.Pools[0].ActAvg.ActPAvg, hidLay.CosDiff.Var, fmIn.Syns.Moment[0], fmIn.Syns.Norm[1]}
+	trg := []Float{hidLay.Learn.AvgL.Init, 0, hidLay.Inhib.ActAvg.Init, 0, 0, 0}
+	for i := range got {
+		if fmath.Abs(got[i]-trg[i]) > difTol {
+			t.Errorf("renorm err: got: %v, trg: %v", got[i], trg[i])
+		}
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// SaliencyModes are the ways of perturbing input units for [SaliencyParams].
+type SaliencyModes int32 //enums:enum
+
+const (
+	// SaliencyOcclude sets the input value to 0, so only active
+	// inputs can have an effect.
+	SaliencyOcclude SaliencyModes = iota
+
+	// SaliencyFlip sets the input value v to 1 - v, so that turning
+	// on inactive inputs can also have an effect.
+	SaliencyFlip
+)
+
+// SaliencyParams are the parameters for a gradient-free saliency map of
+// the input units for a given test item, computed by perturbing each input
+// unit (or pool of units) in turn, and measuring the change in an output
+// stat (e.g., the cosine of the output with the target, or the memory
+// score), without requiring any gradients.
+type SaliencyParams struct {
+
+	// Mode is how the input units are perturbed.
+	Mode SaliencyModes
+
+	// ByPool perturbs all of the units in each pool together, for 4D inputs,
+	// to measure the saliency of each pool (e.g., the item vs. context pools
+	// of the hippocampus EC input), which is the same for all units in the pool.
+	ByPool bool
+}
+
+func (sp *SaliencyParams) Defaults() {
+	sp.Mode = SaliencyOcclude
+}
+
+// perturb perturbs the given input value.
+func (sp *SaliencyParams) perturb(v float64) float64 {
+	if sp.Mode == SaliencyFlip {
+		return 1 - v
+	}
+	return 0
+}
+
+// Map returns the saliency map for the given input pattern, with the same
+// shape, along with the stat for the intact pattern.  The run function
+// applies the given (possibly perturbed) input pattern, runs the trial, and
+// returns the output stat for the item, which should be larger for better
+// performance.  The saliency of each unit is the decrease in the stat when
+// it is perturbed, relative to the intact pattern, so units that drive
+// correct performance have positive saliency.  Units that are not changed
+// by the perturbation (e.g., inactive units for SaliencyOcclude) have 0
+// saliency without running a trial.
+func (sp *SaliencyParams) Map(pat tensor.Tensor, run func(pat tensor.Tensor) float64) (*tensor.Float32, float64) {
+	sal := tensor.NewFloat32(pat.Shape().Sizes, pat.Shape().Names...)
+	base := run(pat)
+	pert := pat.Clone()
+	groups := sp.groups(pat)
+	for _, grp := range groups {
+		changed := false
+		for _, i := range grp {
+			v := pat.Float1D(i)
+			pv := sp.perturb(v)
+			if pv != v {
+				changed = true
+			}
+			pert.SetFloat1D(i, pv)
+		}
+		if changed {
+			d := base - run(pert)
+			for _, i := range grp {
+				sal.SetFloat1D(i, d)
+			}
+		}
+		for _, i := range grp {
+			pert.SetFloat1D(i, pat.Float1D(i))
+		}
+	}
+	return sal, base
+}
+
+// groups returns the groups of unit indexes that are perturbed together.
+func (sp *SaliencyParams) groups(pat tensor.Tensor) [][]int {
+	n := pat.Len()
+	if !sp.ByPool || pat.NumDims() != 4 {
+		grps := make([][]int, n)
+		for i := range n {
+			grps[i] = []int{i}
+		}
+		return grps
+	}
+	npool := pat.DimSize(0) * pat.DimSize(1)
+	nu := n / npool
+	grps := make([][]int, npool)
+	for pi := range npool {
+		grps[pi] = make([]int, nu)
+		for ui := range nu {
+			grps[pi][ui] = pi*nu + ui
+		}
+	}
+	return grps
+}
+
+// SaliencyMaps collects the saliency maps for a set of test items,
+// in a table with a Name column and a Saliency tensor column with
+// the shape of the input, for visualization in a table view (grid),
+// along with the Stat for the intact input.
+type SaliencyMaps struct {
+	SaliencyParams
+
+	// Table has the saliency maps, one row per item.
+	Table *table.Table
+}
+
+// Add adds the saliency map for the given named item, with the given
+// input pattern and run function (see SaliencyParams.Map).
+func (sm *SaliencyMaps) Add(name string, pat tensor.Tensor, run func(pat tensor.Tensor) float64) *tensor.Float32 {
+	if sm.Table == nil {
+		dt := table.NewTable()
+		dt.AddStringColumn("Name")
+		dt.AddFloat64Column("Stat")
+		dt.AddFloat32TensorColumn("Saliency", pat.Shape().Sizes, pat.Shape().Names...)
+		sm.Table = dt
+	}
+	sal, stat := sm.Map(pat, run)
+	dt := sm.Table
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetString("Name", row, name)
+	dt.SetFloat("Stat", row, stat)
+	dt.SetTensor("Saliency", row, sal)
+	return sal
+}
+
+// Mean returns the mean saliency map across the items in the Table.
+func (sm *SaliencyMaps) Mean() *tensor.Float32 {
+	if sm.Table == nil || sm.Table.Rows == 0 {
+		return nil
+	}
+	col, err := sm.Table.ColumnByName("Saliency")
+	if err != nil {
+		return nil
+	}
+	mn := col.SubSpace([]int{0}).Clone().(*tensor.Float32)
+	for row := 1; row < sm.Table.Rows; row++ {
+		rs := col.SubSpace([]int{row})
+		for i := range mn.Values {
+			mn.Values[i] += float32(rs.Float1D(i))
+		}
+	}
+	for i := range mn.Values {
+		mn.Values[i] /= float32(sm.Table.Rows)
+	}
+	return mn
+}
+
+// SaliencyMinusRun returns a run function for saliency maps
+// (see SaliencyParams.Map), which applies the pattern to the input layer,
+// runs the minus phase of a trial (the first 3 quarters), without learning,
+// and returns the cosine between the minus phase activity of the output
+// layer (ActM) and the given target pattern.
+func (nt *Network) SaliencyMinusRun(ctx *Context, in, out *Layer, target tensor.Tensor) func(pat tensor.Tensor) float64 {
+	return func(pat tensor.Tensor) float64 {
+		nt.InitExt()
+		in.ApplyExt(pat)
+		nt.AlphaCycInit(false)
+		ctx.AlphaCycStart()
+		for qtr := 0; qtr < 3; qtr++ {
+			for range ctx.CycPerQtr {
+				nt.Cycle(ctx)
+				ctx.CycleInc()
+			}
+			nt.QuarterFinal(ctx)
+			ctx.QuarterInc()
+		}
+		var ab, aa, bb float64
+		for ni := range out.Neurons {
+			a := float64(out.Neurons[ni].ActM)
+			b := 0.0
+			if ni < target.Len() {
+				b = target.Float1D(ni)
+			}
+			ab += a * b
+			aa += a * a
+			bb += b * b
+		}
+		if aa == 0 || bb == 0 {
+			return 0
+		}
+		return ab / math.Sqrt(aa*bb)
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/tensor"
+)
+
+func TestSaliency(t *testing.T) {
+	pat := tensor.NewFloat32([]int{1, 2, 1, 2})
+	copy(pat.Values, []float32{1, 1, 0, 1})
+	// stat weights the units differently
+	run := func(p tensor.Tensor) float64 {
+		return 2*p.Float1D(0) + p.Float1D(1) + p.Float1D(2) + 0.5*p.Float1D(3)
+	}
+	sm := &SaliencyMaps{}
+	sm.Defaults()
+	sal := sm.Add("a", pat, run)
+	CmprFloats(sal.Values, []float32{2, 1, 0, 0.5}, "Saliency Occlude", t)
+
+	sm.Mode = SaliencyFlip
+	sal, base := sm.Map(pat, run)
+	CmprFloats(append(sal.Values, float32(base)), []float32{2, 1, -1, 0.5, 3.5}, "Saliency Flip", t)
+
+	sm.Mode = SaliencyOcclude
+	sm.ByPool = true
+	sm.Add("b", pat, run)
+	CmprFloats(sm.Mean().Values, []float32{2.5, 2, 0.25, 0.5}, "Saliency ByPool Mean", t)
+}
@@ -0,0 +1,234 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// SensitivityProbe measures the contribution of each layer to the outputs
+// of the network for the current test item, by perturbing the settled
+// activity of each layer in turn, within the same trial and without any
+// learning, and measuring the resulting change in the output layers.
+// It is a cheap, gradient-like complement to the full AblationSweep,
+// which does not require rerunning the test battery for each layer.
+//
+// Each layer is held at its settled activity scaled by 1 +/- Delta for
+// NCycles cycles of further settling of the rest of the network, and the
+// contribution score is the central difference of the output measure
+// between the two perturbations, divided by 2 * Delta, i.e., the
+// derivative of the output with respect to the gain of the layer.
+// Without a stat function, the output measure is the activity of the
+// output units, and the score is the mean absolute change in their
+// activity; with a stat function (e.g., the cosine of the output with
+// its target), the score is the signed change in the stat.
+// The network state is restored after each perturbation, so the trial
+// can continue as if the probe had not been run.
+type SensitivityProbe struct {
+
+	// Layers are the names of the layers to perturb.  If empty, all
+	// layers that are not Off or Outputs are perturbed.
+	Layers []string
+
+	// Outputs are the names of the output layers whose activity is
+	// measured.  If empty, all Target and Compare layers are used.
+	Outputs []string
+
+	// Delta is the proportional change in the settled activity of
+	// the perturbed layer, which should be small.
+	Delta float32 `default:"0.1" min:"0"`
+
+	// NCycles is the number of cycles of settling with each
+	// perturbation before measuring the outputs.
+	NCycles int `default:"10" min:"1"`
+
+	// Results is the contribution table, with a Name column for the
+	// test item and a column for the score of each perturbed layer,
+	// one row per probe.
+	Results *table.Table
+}
+
+func (sp *SensitivityProbe) Defaults() {
+	sp.Delta = 0.1
+	sp.NCycles = 10
+}
+
+// Probe measures the contribution scores of the layers for the current
+// settled state of the network, for the named test item, which are
+// returned by layer name and added as a row to the Results.
+// It must be called at the end of the minus phase settling, prior to the
+// final QuarterFinal (which clamps the targets for the plus phase),
+// e.g., in an event at the plus phase start cycle.  If stat is non-nil,
+// it returns the output stat to measure, computed from the current Act
+// of the output layers (e.g., the cosine with the target), otherwise
+// the output activity is used.
+func (sp *SensitivityProbe) Probe(net *Network, ctx *Context, name string, stat func(net *Network) float64) map[string]float64 {
+	if sp.Delta == 0 {
+		sp.Defaults()
+	}
+	outs := sp.outputs(net)
+	lys := sp.layers(net, outs)
+	if sp.Results == nil {
+		dt := table.NewTable()
+		dt.AddStringColumn("Name")
+		for _, ly := range lys {
+			dt.AddFloat64Column(ly.Name)
+		}
+		sp.Results = dt
+	}
+	st := newTestCacheState(net, ctx)
+	scores := make(map[string]float64, len(lys))
+	var plus, minus []float64
+	for _, ly := range lys {
+		plus = sp.perturbed(net, ctx, st, ly, sp.Delta, outs, stat, plus[:0])
+		minus = sp.perturbed(net, ctx, st, ly, -sp.Delta, outs, stat, minus[:0])
+		var d float64
+		if stat != nil {
+			d = plus[0] - minus[0]
+		} else {
+			for i := range plus {
+				d += math.Abs(plus[i] - minus[i])
+			}
+			d /= float64(max(len(plus), 1))
+		}
+		scores[ly.Name] = d / float64(2*sp.Delta)
+	}
+	st.restore(net, ctx)
+
+	dt := sp.Results
+	row := dt.Rows
+	dt.SetNumRows(row + 1)
+	dt.SetString("Name", row, name)
+	for _, ly := range lys {
+		dt.SetFloat(ly.Name, row, scores[ly.Name])
+	}
+	return scores
+}
+
+// perturbed restores the given state, holds the settled activity of the
+// given layer scaled by 1 + delta for NCycles cycles, and appends the
+// resulting stat, or the activity of the output layers, to vals.
+func (sp *SensitivityProbe) perturbed(net *Network, ctx *Context, st *TestCacheState, ly *Layer, delta float32, outs []*Layer, stat func(net *Network) float64, vals []float64) []float64 {
+	st.restore(net, ctx)
+	settled := st.Neurons[ly.Index]
+	gain := 1 + Float(delta)
+	for range sp.NCycles {
+		for ni := range ly.Neurons {
+			nrn := &ly.Neurons[ni]
+			if nrn.IsOff() {
+				continue
+			}
+			nrn.Act = min(settled[ni].Act*gain, 1)
+		}
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	if stat != nil {
+		return append(vals, stat(net))
+	}
+	for _, ol := range outs {
+		for ni := range ol.Neurons {
+			vals = append(vals, float64(ol.Neurons[ni].Act))
+		}
+	}
+	return vals
+}
+
+// outputs returns the output layers.
+func (sp *SensitivityProbe) outputs(net *Network) []*Layer {
+	var lys []*Layer
+	if len(sp.Outputs) == 0 {
+		for _, ly := range net.Layers {
+			if !ly.Off && (ly.Type == TargetLayer || ly.Type == CompareLayer) {
+				lys = append(lys, ly)
+			}
+		}
+		return lys
+	}
+	for _, lnm := range sp.Outputs {
+		ly := net.LayerByName(lnm)
+		if ly == nil {
+			fmt.Printf("leabra.SensitivityProbe: output layer named %s not found\n", lnm)
+			continue
+		}
+		lys = append(lys, ly)
+	}
+	return lys
+}
+
+// layers returns the layers to perturb.
+func (sp *SensitivityProbe) layers(net *Network, outs []*Layer) []*Layer {
+	var lys []*Layer
+	if len(sp.Layers) == 0 {
+		for _, ly := range net.Layers {
+			if !ly.Off && !slices.Contains(outs, ly) {
+				lys = append(lys, ly)
+			}
+		}
+		return lys
+	}
+	for _, lnm := range sp.Layers {
+		ly := net.LayerByName(lnm)
+		if ly == nil {
+			fmt.Printf("leabra.SensitivityProbe: layer named %s not found\n", lnm)
+			continue
+		}
+		lys = append(lys, ly)
+	}
+	return lys
+}
+
+// Means returns the mean contribution score of each layer
+// across the items in the Results.
+func (sp *SensitivityProbe) Means() map[string]float64 {
+	dt := sp.Results
+	if dt == nil || dt.Rows == 0 {
+		return nil
+	}
+	means := make(map[string]float64)
+	for ci, col := range dt.Columns {
+		lnm := dt.ColumnNames[ci]
+		if lnm == "Name" {
+			continue
+		}
+		var sum float64
+		for row := range dt.Rows {
+			sum += col.Float1D(row)
+		}
+		means[lnm] = sum / float64(dt.Rows)
+	}
+	return means
+}
+
+// String returns the Results as a text table, one row per item,
+// followed by the Mean across items.
+func (sp *SensitivityProbe) String() string {
+	dt := sp.Results
+	if dt == nil {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(strings.Join(dt.ColumnNames, "\t"))
+	b.WriteString("\n")
+	for row := range dt.Rows {
+		b.WriteString(dt.StringValue("Name", row))
+		for _, lnm := range dt.ColumnNames[1:] {
+			fmt.Fprintf(&b, "\t%.4g", dt.Float(lnm, row))
+		}
+		b.WriteString("\n")
+	}
+	means := sp.Means()
+	b.WriteString("Mean")
+	for _, lnm := range dt.ColumnNames[1:] {
+		fmt.Fprintf(&b, "\t%.4g", means[lnm])
+	}
+	b.WriteString("\n")
+	return b.String()
+}
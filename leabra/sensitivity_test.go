@@ -0,0 +1,78 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestSensitivityProbe(t *testing.T) {
+	net := NewNetwork("Sensitivity")
+	in := net.AddLayer2D("Input", 2, 2, InputLayer)
+	hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+	iso := net.AddLayer2D("Isolated", 2, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 2, TargetLayer)
+	net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.ConnectLayers(hid, out, paths.NewFull(), ForwardPath)
+	net.ConnectLayers(in, iso, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	ctx := NewContext()
+	pat := tensor.NewFloat32([]int{2, 2})
+	copy(pat.Values, []float32{1, 0, 0, 1})
+	net.InitExt()
+	in.ApplyExt(pat)
+	net.AlphaCycInit(false)
+	ctx.AlphaCycStart()
+	for range 75 {
+		net.Cycle(ctx)
+		ctx.CycleInc()
+	}
+	var acts []float32
+	out.UnitValues(&acts, "Act", 0)
+	cyc := ctx.Cycle
+
+	sp := &SensitivityProbe{}
+	sp.Defaults()
+	scores := sp.Probe(net, ctx, "a", nil)
+	if len(scores) != 3 || sp.Results.Rows != 1 || sp.Results.NumColumns() != 4 {
+		t.Fatalf("SensitivityProbe: scores %v, Results %d rows %d cols", scores, sp.Results.Rows, sp.Results.NumColumns())
+	}
+	if scores["Input"] <= 0 || scores["Hidden"] <= 0 || scores["Isolated"] != 0 {
+		t.Errorf("SensitivityProbe: unexpected scores %v", scores)
+	}
+	var racts []float32
+	out.UnitValues(&racts, "Act", 0)
+	CmprFloats(racts, acts, "SensitivityProbe restored Output Act", t)
+	if ctx.Cycle != cyc {
+		t.Errorf("SensitivityProbe: Cycle %d not restored to %d", ctx.Cycle, cyc)
+	}
+
+	sum := func(net *Network) float64 {
+		var s float64
+		for ni := range out.Neurons {
+			s += float64(out.Neurons[ni].Act)
+		}
+		return s
+	}
+	scores = sp.Probe(net, ctx, "b", sum)
+	if scores["Hidden"] <= 0 || scores["Isolated"] != 0 {
+		t.Errorf("SensitivityProbe stat: unexpected scores %v", scores)
+	}
+	if sp.Results.Rows != 2 || sp.Results.StringValue("Name", 1) != "b" {
+		t.Errorf("SensitivityProbe: Results rows not added")
+	}
+	mn := sp.Means()
+	if math.Abs(mn["Hidden"]-(sp.Results.Float("Hidden", 0)+sp.Results.Float("Hidden", 1))/2) > 1e-6 {
+		t.Errorf("SensitivityProbe: Means %v", mn)
+	}
+}
@@ -0,0 +1,320 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/params"
+)
+
+// Server is an optional HTTP server for the remote control of a sim
+// running headlessly, e.g., from a Python or Julia notebook, or from
+// cluster orchestration, with a small REST API using JSON:
+//
+//	POST /init                          initialize the sim (Init)
+//	POST /params?sheet=Name             apply a params sheet by name,
+//	POST /params  {"Sel:Path": "val"}   or the params in the body, live
+//	POST /step?mode=Train&time=Trial&n=1  step the loops
+//	GET  /counters                      current mode and loop counters
+//	GET  /stats?names=SSE,TrlErr        stats, all if no names
+//	GET  /layer?name=Hidden&var=Act     layer unit values and shape
+//	POST /weights/save?file=net.wts.gz  save weights (.wtb for binary)
+//	POST /weights/open?file=net.wts.gz  open weights (.wtb for binary)
+//
+// The weights files are relative to WeightsDir, and names that are
+// absolute or escape it (e.g., with ..) are rejected.  There is no
+// authentication, so the server should only listen on localhost
+// (e.g., "localhost:8080"), unless the network is trusted.
+// Errors are returned with a 400 status and an {"error": message} body.
+// Requests are handled one at a time, as the network is not safe for
+// concurrent use.  For example, with curl:
+//
+//	curl -X POST 'localhost:8080/step?mode=Train&time=Epoch&n=5'
+//	curl 'localhost:8080/stats?names=Epoch,Trial,SSE'
+type Server struct {
+
+	// Net is the network.
+	Net *Network
+
+	// Params are the params of the sim, for the /params endpoint.
+	Params *emer.NetParams
+
+	// Ctx is the context of the sim, for applying the params during
+	// the run with ApplyParamsLive.
+	Ctx *Context
+
+	// Loops are the loops of the sim, for the /step and /counters endpoints.
+	Loops *looper.Stacks
+
+	// Stats are the stats of the sim, for the /stats endpoint.
+	Stats *estats.Stats
+
+	// Init initializes the sim, for the /init endpoint (e.g., Sim.Init).
+	Init func()
+
+	// Update, if set, is called prior to returning the stats,
+	// e.g., to update the counter stats (Sim.StatCounters).
+	Update func()
+
+	// WeightsDir is the directory that the /weights endpoints save and
+	// open files in, which is the current directory if empty.
+	WeightsDir string
+
+	// mu serializes the requests.
+	mu sync.Mutex
+}
+
+// Handler returns the http.Handler for the API.
+func (sv *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /init", sv.handle(sv.init))
+	mux.HandleFunc("POST /params", sv.handle(sv.params))
+	mux.HandleFunc("POST /step", sv.handle(sv.step))
+	mux.HandleFunc("GET /counters", sv.handle(sv.counters))
+	mux.HandleFunc("GET /stats", sv.handle(sv.stats))
+	mux.HandleFunc("GET /layer", sv.handle(sv.layer))
+	mux.HandleFunc("POST /weights/save", sv.handle(sv.saveWeights))
+	mux.HandleFunc("POST /weights/open", sv.handle(sv.openWeights))
+	return mux
+}
+
+// ListenAndServe serves the API on the given address, e.g., "localhost:8080",
+// until the server fails.
+func (sv *Server) ListenAndServe(addr string) error {
+	fmt.Printf("Serving remote control API on: %s\n", addr)
+	return http.ListenAndServe(addr, sv.Handler())
+}
+
+// handle returns a handler function calling the given function with
+// the request serialized, and writing its result or error as JSON.
+func (sv *Server) handle(fun func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sv.mu.Lock()
+		res, err := fun(r)
+		sv.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			res = map[string]string{"error": err.Error()}
+		}
+		json.NewEncoder(w).Encode(res)
+	}
+}
+
+// query returns the value of the given query parameter in the URL.
+func query(r *http.Request, name string) string {
+	return r.URL.Query().Get(name)
+}
+
+// jsonFloat returns the given value for encoding as JSON,
+// which does not support NaN or Inf values, encoded as null.
+func jsonFloat(v float64) any {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return v
+}
+
+func (sv *Server) init(r *http.Request) (any, error) {
+	if sv.Init == nil {
+		return nil, errors.New("init not supported")
+	}
+	sv.Init()
+	return sv.counters(r)
+}
+
+func (sv *Server) params(r *http.Request) (any, error) {
+	if sv.Params == nil || sv.Ctx == nil {
+		return nil, errors.New("params not supported")
+	}
+	if sheet := query(r, "sheet"); sheet != "" {
+		sh, err := sv.Params.Params.SheetByName(sheet)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := sv.Net.ApplyParamsLive(sv.Ctx, sh, sv.Params.SetMsg); err != nil {
+			return nil, err
+		}
+		return map[string]string{"applied": sheet}, nil
+	}
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var vals map[string]any
+	if err := json.Unmarshal(b, &vals); err != nil {
+		return nil, fmt.Errorf("params: %w", err)
+	}
+	sh, err := params.MapToSheet(vals)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := sv.Net.ApplyParamsLive(sv.Ctx, sh, sv.Params.SetMsg); err != nil {
+		return nil, err
+	}
+	return map[string]int{"applied": len(vals)}, nil
+}
+
+func (sv *Server) step(r *http.Request) (any, error) {
+	if sv.Loops == nil {
+		return nil, errors.New("step not supported")
+	}
+	mode := etime.Train
+	if ms := query(r, "mode"); ms != "" {
+		if err := mode.SetString(ms); err != nil {
+			return nil, err
+		}
+	}
+	if sv.Loops.Stacks[mode] == nil {
+		return nil, fmt.Errorf("no loops for mode: %s", mode)
+	}
+	tm := etime.Trial
+	if ts := query(r, "time"); ts != "" {
+		if err := tm.SetString(ts); err != nil {
+			return nil, err
+		}
+	}
+	if sv.Loops.Loop(mode, tm) == nil {
+		return nil, fmt.Errorf("no %s loop for mode: %s", tm, mode)
+	}
+	n := 1
+	if ns := query(r, "n"); ns != "" {
+		var err error
+		if n, err = strconv.Atoi(ns); err != nil {
+			return nil, err
+		}
+	}
+	sv.Loops.Step(mode, n, tm)
+	return sv.counters(r)
+}
+
+// counters returns the current mode and the counters of its loops.
+func (sv *Server) counters(r *http.Request) (any, error) {
+	if sv.Loops == nil {
+		return nil, errors.New("counters not supported")
+	}
+	res := map[string]any{}
+	if sv.Loops.Mode == nil {
+		return res, nil
+	}
+	res["Mode"] = sv.Loops.Mode.String()
+	st := sv.Loops.Stacks[sv.Loops.Mode]
+	for _, tm := range st.Order {
+		res[tm.String()] = st.Loops[tm].Counter.Cur
+	}
+	return res, nil
+}
+
+func (sv *Server) stats(r *http.Request) (any, error) {
+	if sv.Stats == nil {
+		return nil, errors.New("stats not supported")
+	}
+	if sv.Update != nil {
+		sv.Update()
+	}
+	st := sv.Stats
+	res := map[string]any{}
+	names := query(r, "names")
+	if names == "" {
+		for nm, v := range st.Floats {
+			res[nm] = jsonFloat(v)
+		}
+		for nm, v := range st.Ints {
+			res[nm] = v
+		}
+		for nm, v := range st.Strings {
+			res[nm] = v
+		}
+		return res, nil
+	}
+	for _, nm := range strings.Split(names, ",") {
+		if v, ok := st.Floats[nm]; ok {
+			res[nm] = jsonFloat(v)
+		} else if v, ok := st.Ints[nm]; ok {
+			res[nm] = v
+		} else if v, ok := st.Strings[nm]; ok {
+			res[nm] = v
+		} else {
+			return nil, fmt.Errorf("stat not found: %s", nm)
+		}
+	}
+	return res, nil
+}
+
+func (sv *Server) layer(r *http.Request) (any, error) {
+	ly := sv.Net.LayerByName(query(r, "name"))
+	if ly == nil {
+		return nil, fmt.Errorf("layer not found: %s", query(r, "name"))
+	}
+	varNm := query(r, "var")
+	if varNm == "" {
+		varNm = "Act"
+	}
+	var vals []float32
+	if err := ly.UnitValues(&vals, varNm, 0); err != nil {
+		return nil, err
+	}
+	return map[string]any{"Shape": ly.Shape.Sizes, "Values": vals}, nil
+}
+
+// weightsFile returns the path of the weights file in the file query
+// parameter, within WeightsDir, returning an error if it is not local to it.
+func (sv *Server) weightsFile(r *http.Request) (string, error) {
+	fnm := query(r, "file")
+	if fnm == "" {
+		return "", errors.New("no file")
+	}
+	if !filepath.IsLocal(fnm) {
+		return "", fmt.Errorf("file must be a relative path within the weights directory: %s", fnm)
+	}
+	return filepath.Join(sv.WeightsDir, fnm), nil
+}
+
+func (sv *Server) saveWeights(r *http.Request) (any, error) {
+	path, err := sv.weightsFile(r)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".wtb" {
+		err = sv.Net.SaveWeightsBinary(core.Filename(path))
+	} else {
+		err = sv.Net.SaveWeightsJSON(core.Filename(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"saved": query(r, "file")}, nil
+}
+
+func (sv *Server) openWeights(r *http.Request) (any, error) {
+	path, err := sv.weightsFile(r)
+	if err != nil {
+		return nil, err
+	}
+	if filepath.Ext(path) == ".wtb" {
+		err = sv.Net.OpenWeightsBinary(core.Filename(path))
+	} else {
+		err = sv.Net.OpenWeightsJSON(core.Filename(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{"opened": query(r, "file")}, nil
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emer/emergent/v2/emer"
+	"github.com/emer/emergent/v2/estats"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+func TestServer(t *testing.T) {
+	net := MakeTestNet(t)
+	pars := &emer.NetParams{Params: ParamSets, Network: net}
+	ls := looper.NewStacks()
+	ls.AddStack(etime.Train).AddTime(etime.Epoch, 3).AddTime(etime.Trial, 4)
+	var st estats.Stats
+	st.Init()
+	trials := 0
+	ls.Loop(etime.Train, etime.Trial).OnStart.Add("Count", func() { trials++ })
+	inits := 0
+	sv := &Server{Net: net, Params: pars, Ctx: NewContext(), Loops: ls, Stats: &st, Init: func() { inits++; ls.Init(); trials = 0 },
+		Update: func() { st.SetInt("Trials", trials); st.SetFloat("SSE", math.NaN()) }}
+	ts := httptest.NewServer(sv.Handler())
+	defer ts.Close()
+
+	call := func(method, path, body string, wantStatus int) map[string]any {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var res map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatalf("%s %s: %v", method, path, err)
+		}
+		if resp.StatusCode != wantStatus {
+			t.Errorf("%s %s: status %d, want %d: %v", method, path, resp.StatusCode, wantStatus, res)
+		}
+		return res
+	}
+
+	call("POST", "/init", "", 200)
+	if inits != 1 {
+		t.Error("init not called")
+	}
+	res := call("POST", "/step?time=Trial&n=6", "", 200)
+	if trials != 6 || res["Mode"] != "Train" || res["Epoch"] != 1.0 || res["Trial"] != 2.0 {
+		t.Errorf("step: trials %d, %v", trials, res)
+	}
+	res = call("GET", "/stats", "", 200)
+	if v, ok := res["SSE"]; !ok || v != nil || res["Trials"] != 6.0 {
+		t.Errorf("stats: %v", res)
+	}
+	res = call("GET", "/stats?names=Trials", "", 200)
+	if len(res) != 1 || res["Trials"] != 6.0 {
+		t.Errorf("stats names: %v", res)
+	}
+	call("GET", "/stats?names=Nope", "", 400)
+	call("POST", "/step?mode=Test", "", 400)
+
+	hid := net.LayerByName("Hidden")
+	for i := range hid.Neurons {
+		hid.Neurons[i].Act = Float(i) * 0.25
+	}
+	res = call("GET", "/layer?name=Hidden", "", 200)
+	if fmt.Sprint(res["Shape"], res["Values"]) != "[4 1] [0 0.25 0.5 0.75]" {
+		t.Errorf("layer: %v", res)
+	}
+	if res = call("GET", "/layer?name=Nope", "", 400); res["error"] != "layer not found: Nope" {
+		t.Errorf("layer error: %v", res)
+	}
+
+	call("POST", "/params?sheet=NormOn", "", 200)
+	pt := hid.RecvPaths[0]
+	if !pt.Learn.Norm.On {
+		t.Error("params sheet not applied")
+	}
+	call("POST", "/params", `{"Layer:Layer.Inhib.Layer.Gi": 2.5}`, 200)
+	if hid.Inhib.Layer.Gi != 2.5 {
+		t.Errorf("params map not applied: Gi = %g", hid.Inhib.Layer.Gi)
+	}
+	call("POST", "/params", `{"Layer": 1}`, 400)
+
+	sv.WeightsDir = t.TempDir()
+	for _, ext := range []string{".wts.gz", ".wtb"} {
+		fnm := "net" + ext
+		call("POST", "/weights/save?file="+fnm, "", 200)
+		if _, err := os.Stat(filepath.Join(sv.WeightsDir, fnm)); err != nil {
+			t.Errorf("%s weights not saved in WeightsDir: %v", ext, err)
+		}
+		wt := pt.Syns.Wt[0]
+		pt.Syns.Wt[0] = 0
+		call("POST", "/weights/open?file="+fnm, "", 200)
+		if pt.Syns.Wt[0] != wt {
+			t.Errorf("%s weights not opened: %g != %g", ext, pt.Syns.Wt[0], wt)
+		}
+	}
+	for _, fnm := range []string{filepath.Join(t.TempDir(), "net.wts.gz"), "../net.wts.gz", "sub/../../net.wts.gz"} {
+		call("POST", "/weights/save?file="+fnm, "", 400)
+		call("POST", "/weights/open?file="+fnm, "", 400)
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// ShardEnv is an env.Env that presents the rows of an in-memory table
+// (like env.FixedTable), sharded across NShards data-parallel procs
+// (e.g., MPI ranks, see MPI.ConfigShardEnv), where this env presents
+// the trials of shard Shard.  In each epoch, all of the procs compute
+// the same permuted order of all the rows from the shared Seed, and each
+// proc gets a disjoint contiguous slice of that order, so each proc sees
+// a different random subset of the rows in each epoch, and together they
+// cover the table (except for any remainder, see NTrials).  All the
+// procs run the same number of trials per epoch, so that the DWt
+// weight changes summed across procs (MPI.WtFromDWt) stay in step.
+// With 1 shard (e.g., MPI not on), it is equivalent to a FixedTable.
+type ShardEnv struct {
+
+	// name of this environment, usually Train.
+	Name string
+
+	// Table is an indexed view of the table with the patterns to present.
+	Table *table.IndexView
+
+	// Sequential presents the rows in the order of the Table view,
+	// instead of a new permuted order in each epoch.
+	Sequential bool
+
+	// Shard is the index of the shard of the rows presented by this env,
+	// e.g., the MPI rank.
+	Shard int
+
+	// NShards is the number of shards, e.g., the number of MPI procs.
+	NShards int `default:"1" min:"1"`
+
+	// Seed is the seed for the permuted order of the rows, which is
+	// seeded with Seed + run in Init.  It MUST be the same on all procs,
+	// so that they compute the same order.
+	Seed int64
+
+	// Rand is the random number stream for the order, which is
+	// only used for the order, so that it stays in sync across procs.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// Order is the permuted order of all the rows for the current epoch,
+	// as indexes into the Table view.
+	Order []int `display:"-"`
+
+	// Trial is the current trial within the shard.
+	// Max is the number of trials per epoch, NTrials.
+	Trial env.Counter `display:"inline"`
+
+	// Epoch is the number of complete passes through the shards.
+	Epoch env.Counter `display:"inline"`
+
+	// TrialName is the contents of the Name column of the current row,
+	// if present.
+	TrialName env.CurPrvString
+
+	// GroupName is the contents of the Group column of the current row,
+	// if present.
+	GroupName env.CurPrvString
+
+	// NameCol is the name of the Name column -- defaults to 'Name'.
+	NameCol string
+
+	// GroupCol is the name of the Group column -- defaults to 'Group'.
+	GroupCol string
+}
+
+func (ev *ShardEnv) Label() string { return ev.Name }
+
+func (ev *ShardEnv) EnvDesc() string {
+	return fmt.Sprintf("%s\tShards: %d", tableDesc(ev.Table, ev.Sequential), max(ev.NShards, 1))
+}
+
+func (ev *ShardEnv) Validate() error {
+	if ev.Table == nil || ev.Table.Table == nil {
+		return fmt.Errorf("leabra.ShardEnv: %v has no Table set", ev.Name)
+	}
+	if ev.NShards < 1 || ev.Shard < 0 || ev.Shard >= ev.NShards {
+		return fmt.Errorf("leabra.ShardEnv: %v Shard %d is not in the %d NShards", ev.Name, ev.Shard, ev.NShards)
+	}
+	if ev.NTrials() == 0 {
+		return fmt.Errorf("leabra.ShardEnv: %v has fewer rows (%d) than NShards (%d)", ev.Name, ev.Table.Len(), ev.NShards)
+	}
+	return nil
+}
+
+// Config configures the environment to present the rows of the given
+// table view, for given shard of nShards.
+func (ev *ShardEnv) Config(tbl *table.IndexView, shard, nShards int) {
+	ev.Table = tbl
+	ev.Shard = shard
+	ev.NShards = nShards
+	ev.Init(0)
+}
+
+// NTrials returns the number of trials per epoch for each shard, which
+// is the number of rows divided by the number of shards, rounded down:
+// the remaining rows are left out of each epoch, which are different rows
+// in each epoch unless Sequential.
+func (ev *ShardEnv) NTrials() int {
+	if ev.Table == nil {
+		return 0
+	}
+	return ev.Table.Len() / max(ev.NShards, 1)
+}
+
+// Init starts a new run, with the order of the rows seeded by Seed + run.
+func (ev *ShardEnv) Init(run int) {
+	if ev.NameCol == "" {
+		ev.NameCol = "Name"
+	}
+	if ev.GroupCol == "" {
+		ev.GroupCol = "Group"
+	}
+	ev.NShards = max(ev.NShards, 1)
+	ev.Rand.NewRand(ev.Seed + int64(run))
+	ev.Order = make([]int, ev.Table.Len())
+	for i := range ev.Order {
+		ev.Order[i] = i
+	}
+	ev.NewOrder()
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Max = ev.NTrials()
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Epoch.Scale = etime.Epoch
+	ev.Epoch.Init()
+}
+
+// NewOrder permutes the order of the rows for a new epoch,
+// if not Sequential.  The Rand stream is used in either case,
+// so that switching Sequential keeps the procs in sync.
+func (ev *ShardEnv) NewOrder() {
+	randx.PermuteInts(ev.Order, &ev.Rand)
+	if ev.Sequential {
+		for i := range ev.Order {
+			ev.Order[i] = i
+		}
+	}
+}
+
+// Row returns the current row number in the table, for the current
+// trial of this shard, dereferenced through the Table view indexes.
+func (ev *ShardEnv) Row() int {
+	return ev.Table.Indexes[ev.Order[ev.Shard*ev.NTrials()+ev.Trial.Cur]]
+}
+
+func (ev *ShardEnv) Step() bool {
+	ev.Epoch.Same()
+	if ev.Trial.Incr() { // if true, hit max, reset to 0
+		ev.NewOrder()
+		ev.Epoch.Incr()
+	}
+	if nms := errors.Ignore1(ev.Table.Table.ColumnByName(ev.NameCol)); nms != nil {
+		ev.TrialName.Set(nms.String1D(ev.Row()))
+	}
+	if gps := errors.Ignore1(ev.Table.Table.ColumnByName(ev.GroupCol)); gps != nil {
+		ev.GroupName.Set(gps.String1D(ev.Row()))
+	}
+	return true
+}
+
+func (ev *ShardEnv) State(element string) tensor.Tensor {
+	return ev.Table.Table.Tensor(element, ev.Row())
+}
+
+func (ev *ShardEnv) Action(element string, input tensor.Tensor) {
+	// nop
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*ShardEnv)(nil)
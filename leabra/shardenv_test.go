@@ -0,0 +1,67 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestShardEnv(t *testing.T) {
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{2, 2})
+	dt.SetNumRows(10)
+	for row := range 10 {
+		dt.SetString("Name", row, fmt.Sprintf("p%d", row))
+	}
+	const nShards = 3
+	evs := make([]*ShardEnv, nShards)
+	for sh := range evs {
+		ev := &ShardEnv{Name: "Train", Seed: 5}
+		ev.Config(table.NewIndexView(dt), sh, nShards)
+		if err := ev.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if ev.NTrials() != 3 || ev.Trial.Max != 3 {
+			t.Fatalf("NTrials: got %d, want 3", ev.NTrials())
+		}
+		evs[sh] = ev
+	}
+	var epochs []string
+	for epc := range 3 {
+		seen := map[string]bool{}
+		var order []string
+		for sh, ev := range evs {
+			for trl := range 3 {
+				ev.Step()
+				if ev.Trial.Cur != trl || ev.Epoch.Cur != epc {
+					t.Fatalf("shard %d: Trial %d Epoch %d, want %d %d", sh, ev.Trial.Cur, ev.Epoch.Cur, trl, epc)
+				}
+				nm := ev.TrialName.Cur
+				if seen[nm] {
+					t.Errorf("epoch %d: row %s presented by more than one shard", epc, nm)
+				}
+				seen[nm] = true
+				order = append(order, nm)
+				if st := ev.State("Input"); st == nil || st.Len() != 4 {
+					t.Errorf("State: %v", st)
+				}
+			}
+		}
+		epochs = append(epochs, strings.Join(order, " "))
+	}
+	if epochs[0] == epochs[1] && epochs[1] == epochs[2] {
+		t.Errorf("order should be permuted across epochs: %v", epochs)
+	}
+	ev := &ShardEnv{Name: "Train", Seed: 5}
+	ev.Config(table.NewIndexView(dt), 0, 11)
+	if err := ev.Validate(); err == nil {
+		t.Error("expected error for more shards than rows")
+	}
+}
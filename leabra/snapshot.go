@@ -0,0 +1,438 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/base/errors"
+	"cogentcore.org/core/colors"
+	"cogentcore.org/core/colors/colormap"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+	"github.com/emer/emergent/v2/netview"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// NetSnapshot renders snapshots of the network state, as shown in the
+// NetView, to PNG or SVG image files during headless (nogui) runs, e.g.,
+// on a cluster, offscreen without a GPU or window.  The layers are drawn
+// as flat grids of units, at the positions of the NetView layout (the
+// layer Pos, with the layers at each height (Z) in a row, from the bottom
+// up, seen from above), colored by the unit values with the NetView color
+// map, ranges and opacity.  The values are those of a neuron variable
+// (e.g., Act), or, as for the selected unit in the NetView, of a synaptic
+// variable of the pathways of a Unit (e.g., r.Wt for the weights that it
+// receives from each sending unit).
+type NetSnapshot struct {
+
+	// Var is the variable to render: a neuron variable, e.g., Act,
+	// or a synaptic variable of the pathways of the Unit, with an r.
+	// prefix for the receiving pathways of the Unit (e.g., r.Wt) or
+	// s. for its sending pathways (e.g., s.Wt), as in the NetView.
+	Var string `default:"Act"`
+
+	// Unit is the unit whose pathways are rendered for synaptic Vars,
+	// as the layer name and 1D unit index, e.g., Hidden1:12.
+	Unit string
+
+	// Format is the image file format: png or svg.
+	Format string `default:"png"`
+
+	// UnitSize is the size of each unit, in pixels.
+	UnitSize int `default:"8" min:"2"`
+
+	// ColorMap is the name of the color map, as in the NetView.
+	ColorMap string `default:"ColdHot"`
+
+	// Dir is the directory where the image files are saved.
+	Dir string `edit:"-"`
+
+	// NFiles is the number of image files saved so far.
+	NFiles int `edit:"-"`
+
+	// net is the network being rendered.
+	net *Network
+
+	// unitLay is the layer of the Unit, for synaptic Vars.
+	unitLay *Layer
+
+	// unitIndex is the 1D index of the Unit in unitLay.
+	unitIndex int
+
+	// vals are the values of each layer.
+	vals map[string][]float32
+}
+
+func (ns *NetSnapshot) Defaults() {
+	ns.Var = "Act"
+	ns.Format = "png"
+	ns.UnitSize = 8
+	ns.ColorMap = "ColdHot"
+}
+
+// Init initializes the snapshots of the given network, saved in the
+// given directory, which is created if it does not exist.
+func (ns *NetSnapshot) Init(net *Network, dir string) error {
+	if ns.Var == "" {
+		ns.Defaults()
+	}
+	ns.UnitSize = max(ns.UnitSize, 2)
+	if ns.Format != "svg" {
+		ns.Format = "png"
+	}
+	if _, ok := colormap.AvailableMaps[ns.ColorMap]; !ok {
+		return fmt.Errorf("leabra.NetSnapshot: color map not found: %s", ns.ColorMap)
+	}
+	ns.net = net
+	ns.unitLay = nil
+	if ns.isSyn() {
+		if _, err := SynapseVarByName(ns.Var[2:]); err != nil {
+			return fmt.Errorf("leabra.NetSnapshot: %w", err)
+		}
+		lnm, idx, ok := strings.Cut(ns.Unit, ":")
+		ui, err := strconv.Atoi(idx)
+		if !ok || err != nil {
+			return fmt.Errorf("leabra.NetSnapshot: Unit must be Layer:index for Var %s: %q", ns.Var, ns.Unit)
+		}
+		ly := net.LayerByName(lnm)
+		if ly == nil {
+			return fmt.Errorf("leabra.NetSnapshot: Unit layer not found: %s", lnm)
+		}
+		if ui < 0 || ui >= len(ly.Neurons) {
+			return fmt.Errorf("leabra.NetSnapshot: Unit index out of range: %s", ns.Unit)
+		}
+		ns.unitLay, ns.unitIndex = ly, ui
+	} else if _, err := NeuronVarIndexByName(ns.Var); err != nil {
+		return fmt.Errorf("leabra.NetSnapshot: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	ns.Dir = dir
+	ns.NFiles = 0
+	return nil
+}
+
+// isSyn returns true if the Var is a synaptic variable.
+func (ns *NetSnapshot) isSyn() bool {
+	return strings.HasPrefix(ns.Var, "r.") || strings.HasPrefix(ns.Var, "s.")
+}
+
+// Save saves a snapshot of the current network state to the file with
+// the given name (without the extension of the Format) in the Dir, with
+// the given label (e.g., the counters) at the top.
+// Returns the name of the file.
+func (ns *NetSnapshot) Save(name, label string) (string, error) {
+	if ns.net == nil {
+		return "", errors.New("leabra.NetSnapshot: Init has not been called")
+	}
+	fnm := filepath.Join(ns.Dir, name+"."+ns.Format)
+	fp, err := os.Create(fnm)
+	if err != nil {
+		return "", err
+	}
+	bw := bufio.NewWriter(fp)
+	if ns.Format == "svg" {
+		err = ns.WriteSVG(bw, label)
+	} else {
+		err = png.Encode(bw, ns.Image(label))
+	}
+	if ferr := bw.Flush(); err == nil {
+		err = ferr
+	}
+	if cerr := fp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		ns.NFiles++
+	}
+	return fnm, err
+}
+
+// Image returns a snapshot of the current network state as an image,
+// with the given label at the top.
+func (ns *NetSnapshot) Image(label string) *image.RGBA {
+	cv := &snapImage{}
+	ns.render(cv, label)
+	return cv.img
+}
+
+// WriteSVG writes a snapshot of the current network state as an SVG
+// image, with the given label at the top.
+func (ns *NetSnapshot) WriteSVG(w io.Writer, label string) error {
+	cv := &snapSVG{}
+	ns.render(cv, label)
+	cv.sb.WriteString("</svg>\n")
+	_, err := io.WriteString(w, cv.sb.String())
+	return err
+}
+
+// snapLabelHt is the height of the text of the labels, in pixels.
+const snapLabelHt = 13
+
+// snapLayer is the position of a layer in a snapshot, in pixels,
+// of the bottom left corner of its grid of units.
+type snapLayer struct {
+	ly   *Layer
+	x, y float32
+}
+
+// layout returns the positions of the layers that are not Off,
+// and the size of the image, at least wide enough for the given label.
+func (ns *NetSnapshot) layout(label string) ([]snapLayer, int, int) {
+	us := float32(ns.UnitSize)
+	margin := us
+	var lays []*Layer
+	var zs []float32
+	minX, minY := float32(math.MaxFloat32), float32(math.MaxFloat32)
+	maxX := float32(0)
+	for _, ly := range ns.net.Layers {
+		if ly.Off {
+			continue
+		}
+		lays = append(lays, ly)
+		pos, sz := ly.Pos.Pos, ly.DisplaySize()
+		minX, minY = min(minX, pos.X), min(minY, pos.Y)
+		maxX = max(maxX, pos.X+sz.X)
+		if !slices.Contains(zs, pos.Z) {
+			zs = append(zs, pos.Z)
+		}
+	}
+	slices.Sort(zs)
+	// each height is a row, as deep as its deepest layer, with the layer names below
+	base := make([]float32, len(zs))
+	ht := margin
+	for zi, z := range zs {
+		base[zi] = ht + snapLabelHt
+		depth := float32(0)
+		for _, ly := range lays {
+			if ly.Pos.Pos.Z == z {
+				depth = max(depth, ly.Pos.Pos.Y+ly.DisplaySize().Y-minY)
+			}
+		}
+		ht = base[zi] + depth*us + margin
+	}
+	ht += snapLabelHt
+	wd := 2*margin + max((maxX-minX)*us, float32(basicfont.Face7x13.Advance*len(label)))
+	sls := make([]snapLayer, len(lays))
+	for li, ly := range lays {
+		zi := slices.Index(zs, ly.Pos.Pos.Z)
+		sls[li] = snapLayer{ly: ly, x: margin + (ly.Pos.Pos.X-minX)*us, y: ht - base[zi] - (ly.Pos.Pos.Y-minY)*us}
+	}
+	return sls, int(math.Ceil(float64(wd))), int(math.Ceil(float64(ht)))
+}
+
+// values gets the values of the Var for all the units of the given
+// layer into vals, with NaN for units without a value.
+func (ns *NetSnapshot) values(ly *Layer, vals *[]float32) {
+	switch {
+	case strings.HasPrefix(ns.Var, "r."):
+		ly.SendPathValues(vals, ns.Var[2:], ns.unitLay, ns.unitIndex, "")
+	case strings.HasPrefix(ns.Var, "s."):
+		ly.RecvPathValues(vals, ns.Var[2:], ns.unitLay, ns.unitIndex, "")
+	default:
+		ly.UnitValues(vals, ns.Var, 0)
+	}
+}
+
+// varOptions returns the display options of the Var, as in the NetView,
+// with the range set from the given values if it is not fixed.
+func (ns *NetSnapshot) varOptions(vals map[string][]float32) *netview.VarOptions {
+	vo := &netview.VarOptions{Var: ns.Var}
+	if ns.isSyn() {
+		vo.SetProps(SynapseVarProps[ns.Var[2:]])
+	} else {
+		vo.SetProps(NeuronVarProps[ns.Var])
+	}
+	vo.Defaults()
+	if vo.Range.FixMin && vo.Range.FixMax {
+		return vo
+	}
+	mn, mx := float32(math.MaxFloat32), -float32(math.MaxFloat32)
+	for _, vs := range vals {
+		for _, v := range vs {
+			if !math.IsNaN(float64(v)) {
+				mn, mx = min(mn, v), max(mx, v)
+			}
+		}
+	}
+	if mn > mx {
+		return vo
+	}
+	if vo.ZeroCtr {
+		mx = max(float32(math.Abs(float64(mn))), float32(math.Abs(float64(mx))), 1e-6)
+		mn = -mx
+	}
+	if !vo.Range.FixMin {
+		vo.Range.Min = mn
+	}
+	if !vo.Range.FixMax {
+		vo.Range.Max = max(mx, vo.Range.Min+1e-6)
+	}
+	return vo
+}
+
+// snapCanvas is the drawing target of a snapshot.
+type snapCanvas interface {
+
+	// init starts a new image of the given size, with a white background.
+	init(wd, ht int)
+
+	// rect draws a filled rectangle with top left at x, y.
+	rect(x, y, w, h float32, clr color.RGBA)
+
+	// text draws the given text with its baseline at y.
+	text(x, y float32, s string)
+}
+
+// render renders the snapshot to the given canvas.
+func (ns *NetSnapshot) render(cv snapCanvas, label string) {
+	label = strings.Join(strings.Fields(ns.Var+" "+label), " ")
+	sls, wd, ht := ns.layout(label)
+	if ns.vals == nil {
+		ns.vals = make(map[string][]float32)
+	}
+	for _, sl := range sls {
+		vs := ns.vals[sl.ly.Name]
+		ns.values(sl.ly, &vs)
+		ns.vals[sl.ly.Name] = vs[:len(sl.ly.Neurons)]
+	}
+	vo := ns.varOptions(ns.vals)
+	cmap := colormap.AvailableMaps[ns.ColorMap]
+	white := color.RGBA{255, 255, 255, 255}
+	plane := color.RGBA{235, 235, 235, 255}
+	zeroAlpha := float32(0.5)
+
+	cv.init(wd, ht)
+	us := float32(ns.UnitSize)
+	cv.text(us, snapLabelHt, label)
+	for _, sl := range sls {
+		ly := sl.ly
+		sz := ly.DisplaySize().MulScalar(us)
+		cv.rect(sl.x, sl.y-sz.Y, sz.X, sz.Y, plane)
+		cv.text(sl.x, sl.y+snapLabelHt, ly.Name)
+		// unit pitch and offsets, with space between the pools of 4D layers
+		// as in the NetView, within the same overall size
+		c := us * ly.Pos.Scale
+		npy, npx, nuy, nux := 1, 1, 1, len(ly.Neurons)
+		switch {
+		case ly.Is4D():
+			npy, npx, nuy, nux = ly.Shape.DimSize(0), ly.Shape.DimSize(1), ly.Shape.DimSize(2), ly.Shape.DimSize(3)
+		case ly.Is2D():
+			nuy, nux = ly.Shape.DimSize(0), ly.Shape.DimSize(1)
+		}
+		gap := float32(0.5)
+		xsc := float32(npx*nux) / (float32(npx-1)*gap + float32(npx*nux))
+		ysc := float32(npy*nuy) / (float32(npy-1)*gap + float32(npy*nuy))
+		uw, uh := c*xsc*0.9, c*ysc*0.9
+		vals := ns.vals[ly.Name]
+		for ni, v := range vals {
+			pi, ui := ni/(nuy*nux), ni%(nuy*nux)
+			py, px, uy, ux := pi/npx, pi%npx, ui/nux, ui%nux
+			x := sl.x + c*xsc*(float32(px)*(gap+float32(nux))+float32(ux))
+			y := sl.y - c*ysc*(float32(py)*(gap+float32(nuy))+float32(uy)+1)
+			clr := netview.NilColor
+			if ly == ns.unitLay && ni == ns.unitIndex {
+				clr = color.RGBA{0x20, 0x80, 0x20, 0x80} // the selected unit, as in the NetView
+			} else if !math.IsNaN(float64(v)) {
+				norm := vo.Range.NormValue(vo.Range.ClipValue(v))
+				op := zeroAlpha + (1-zeroAlpha)*0.8
+				if vo.ZeroCtr {
+					op = zeroAlpha + (1-zeroAlpha)*float32(math.Abs(2*float64(norm)-1))
+				}
+				clr = colors.WithAF32(cmap.Map(float32(norm)), op)
+			}
+			cv.rect(x, y, uw, uh, colors.AlphaBlend(white, clr))
+		}
+	}
+}
+
+// snapImage renders a snapshot to an image.
+type snapImage struct {
+	img *image.RGBA
+}
+
+func (si *snapImage) init(wd, ht int) {
+	si.img = image.NewRGBA(image.Rect(0, 0, wd, ht))
+	draw.Draw(si.img, si.img.Bounds(), image.White, image.Point{}, draw.Src)
+}
+
+func (si *snapImage) rect(x, y, w, h float32, clr color.RGBA) {
+	r := image.Rect(int(math.Round(float64(x))), int(math.Round(float64(y))), int(math.Round(float64(x+w))), int(math.Round(float64(y+h))))
+	if r.Dx() == 0 {
+		r.Max.X++
+	}
+	if r.Dy() == 0 {
+		r.Max.Y++
+	}
+	draw.Draw(si.img, r, image.NewUniform(clr), image.Point{}, draw.Src)
+}
+
+func (si *snapImage) text(x, y float32, s string) {
+	d := &font.Drawer{Dst: si.img, Src: image.Black, Face: basicfont.Face7x13, Dot: fixed.P(int(x), int(y)-2)}
+	d.DrawString(s)
+}
+
+// snapSVG renders a snapshot as SVG.
+type snapSVG struct {
+	sb strings.Builder
+}
+
+func (ss *snapSVG) init(wd, ht int) {
+	fmt.Fprintf(&ss.sb, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%d\" height=\"%d\" viewBox=\"0 0 %d %d\">\n", wd, ht, wd, ht)
+	ss.sb.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"white\"/>\n")
+}
+
+func (ss *snapSVG) rect(x, y, w, h float32, clr color.RGBA) {
+	fmt.Fprintf(&ss.sb, "<rect x=\"%.4g\" y=\"%.4g\" width=\"%.4g\" height=\"%.4g\" fill=\"#%02x%02x%02x\"/>\n", x, y, w, h, clr.R, clr.G, clr.B)
+}
+
+func (ss *snapSVG) text(x, y float32, s string) {
+	s = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+	fmt.Fprintf(&ss.sb, "<text x=\"%.4g\" y=\"%.4g\" font-family=\"monospace\" font-size=\"11\">%s</text>\n", x, y-2, s)
+}
+
+// LooperNetSnapshot adds a function at the end of the loop of the given
+// mode and time (e.g., Train Epoch), to save a snapshot of the network
+// with the given NetSnapshot every given number of iterations, with the
+// label returned by the given function (e.g., the counters).  The files
+// are named by the mode and the counters of the loops down to the time,
+// e.g., Train_Run000_Epoch0009.png.
+func LooperNetSnapshot(ls *looper.Stacks, ns *NetSnapshot, mode etime.Modes, time etime.Times, every int, label func() string) {
+	st := ls.Stacks[mode]
+	lp := ls.Loop(mode, time)
+	if st == nil || lp == nil {
+		return
+	}
+	every = max(every, 1)
+	lp.OnEnd.Add("NetSnapshot", func() {
+		if (lp.Counter.Cur+1)%every != 0 {
+			return
+		}
+		name := mode.String()
+		for _, tm := range st.Order {
+			name += fmt.Sprintf("_%s%04d", tm.String(), st.Loops[tm].Counter.Cur)
+			if tm == time {
+				break
+			}
+		}
+		errors.Log1(ns.Save(name, label()))
+	})
+}
@@ -0,0 +1,75 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestNetSnapshot(t *testing.T) {
+	net := NewNetwork("Snapshot")
+	inp := net.AddLayer2D("Input", 2, 3, InputLayer)
+	hid := net.AddLayer4D("Hidden", 2, 2, 1, 2, SuperLayer)
+	net.ConnectLayers(inp, hid, paths.NewFull(), ForwardPath)
+	net.Build()
+	net.InitWeights()
+	inp.Neurons[0].Act = 1
+	dir := filepath.Join(t.TempDir(), "snaps")
+
+	ns := &NetSnapshot{}
+	ns.Defaults()
+	if err := ns.Init(net, dir); err != nil {
+		t.Fatal(err)
+	}
+	fnm, err := ns.Save("Train_Epoch0000", "Epoch: 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fp, err := os.Open(fnm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(fp)
+	fp.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first input unit is active, so drawn in the hot color, unlike the others
+	us := float32(ns.UnitSize)
+	sls, wd, ht := ns.layout("Act Epoch: 0")
+	if img.Bounds().Dx() != wd || img.Bounds().Dy() != ht || len(sls) != 2 || sls[0].y <= sls[1].y {
+		t.Fatalf("layout: %dx%d %v", wd, ht, sls)
+	}
+	r1, _, b1, _ := img.At(int(sls[0].x+us/2), int(sls[0].y-us/2)).RGBA()
+	r2, _, b2, _ := img.At(int(sls[0].x+us*1.5), int(sls[0].y-us/2)).RGBA()
+	if r1 <= b1 || r1 == r2 && b1 == b2 {
+		t.Errorf("active unit color: %d %d vs %d %d", r1, b1, r2, b2)
+	}
+
+	ns.Var = "r.Wt"
+	ns.Unit = "Hidden:3"
+	ns.Format = "svg"
+	if err := ns.Init(net, dir); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	if err := ns.WriteSVG(&sb, "Epoch: 0"); err != nil {
+		t.Fatal(err)
+	}
+	svg := sb.String()
+	if !strings.HasPrefix(svg, "<svg") || !strings.Contains(svg, ">Hidden</text>") || !strings.Contains(svg, "r.Wt Epoch: 0") {
+		t.Errorf("svg: %s", svg)
+	}
+	ns.Unit = "Hidden:8"
+	if err := ns.Init(net, dir); err == nil {
+		t.Error("no error for unit out of range")
+	}
+}
@@ -0,0 +1,425 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/math32"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// SpatialEnv generates entorhinal cortex (EC) input patterns from a
+// simulated trajectory through a square 2D arena, for training
+// hippocampus models on spatial memory tasks.  The EC pattern has
+// the 4D pool shape of the hippocampus EC layers: the first pools are
+// grid cell modules, and the last PlacePools pools are place cells.
+//
+// Each grid module has a hexagonal grid of a given spacing and
+// orientation, with the units in the module tiling the spatial phases
+// of the grid, and the spacing increasing geometrically across modules.
+// The grid cells are driven by the path-integrated estimate of the
+// position (EstPos), which accumulates PINoise on each step, and is
+// only corrected to the true position every PIReset steps, as by a
+// landmark.  The place cells are Gaussian bumps around random centers,
+// driven by the true position (Pos).
+//
+// RemapPlace and RemapGrid draw new place centers and grid phases,
+// for a different context in the same arena, or a novel arena.
+type SpatialEnv struct {
+
+	// name of this environment
+	Name string
+
+	// Size is the length of each side of the square arena.
+	Size float32 `default:"1"`
+
+	// Speed is the distance moved on each step.
+	Speed float32 `default:"0.05"`
+
+	// TurnSD is the standard deviation of the change in heading
+	// on each step, in radians.
+	TurnSD float32 `default:"0.5"`
+
+	// PINoise is the standard deviation of the noise added to the
+	// path-integrated position estimate on each step, in each dimension.
+	PINoise float32 `default:"0.005"`
+
+	// PIReset is the interval in steps at which the path-integrated
+	// position estimate is reset to the true position.  0 = never.
+	PIReset int `default:"20"`
+
+	// PoolsY is the number of pools in the Y dimension of the EC pattern.
+	PoolsY int `default:"6"`
+
+	// PoolsX is the number of pools in the X dimension of the EC pattern.
+	PoolsX int `default:"2"`
+
+	// UnitsY is the number of units per pool in the Y dimension.
+	UnitsY int `default:"3"`
+
+	// UnitsX is the number of units per pool in the X dimension.
+	UnitsX int `default:"4"`
+
+	// PlacePools is the number of pools, at the end, with place cells.
+	// The remaining pools are grid cell modules.
+	PlacePools int `default:"2"`
+
+	// GridSpacing is the spacing of the first (smallest) grid module.
+	GridSpacing float32 `default:"0.3"`
+
+	// GridRatio is the ratio of the spacing of each grid module
+	// to the previous one.
+	GridRatio float32 `default:"1.2"`
+
+	// PlaceSigma is the width (standard deviation) of the place fields.
+	PlaceSigma float32 `default:"0.1"`
+
+	// KPerPool is the number of most active units per pool that are
+	// set to 1, with the rest 0, for binary patterns.  0 = graded rates.
+	KPerPool int `default:"3"`
+
+	// Seed, if non-zero, is the seed for the Rand stream of this
+	// environment, which is seeded with Seed in Config, and Seed + run
+	// in Init, so that a given run is reproduced regardless of any other
+	// use of random numbers.  If 0, the global random stream is used.
+	Seed int64
+
+	// Rand is the random number stream for this environment.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// Pos is the current true position.
+	Pos math32.Vector2 `edit:"-"`
+
+	// EstPos is the current path-integrated estimate of the position.
+	EstPos math32.Vector2 `edit:"-"`
+
+	// Heading is the current direction of movement, in radians.
+	Heading float32 `edit:"-"`
+
+	// GridOrient is the orientation of each grid module, in radians.
+	GridOrient []float32 `display:"-"`
+
+	// GridPhase is the spatial phase offset of each grid module.
+	GridPhase []math32.Vector2 `display:"-"`
+
+	// PlaceCenters are the centers of the place fields.
+	PlaceCenters []math32.Vector2 `display:"-"`
+
+	// EC is the current EC pattern.
+	EC tensor.Float32 `display:"no-inline"`
+
+	// Cue is the current EC pattern with the place pools empty, i.e.,
+	// the grid cell code alone, as a cue for recalling the place cells.
+	Cue tensor.Float32 `display:"no-inline"`
+
+	// PosState is the current true position, as a tensor.
+	PosState tensor.Float32 `display:"-"`
+
+	// trial is the step counter within epoch
+	Trial env.Counter `display:"inline"`
+}
+
+// SpatialSample is a position on a trajectory through the arena.
+type SpatialSample struct {
+
+	// Pos is the true position.
+	Pos math32.Vector2
+
+	// EstPos is the path-integrated estimate of the position.
+	EstPos math32.Vector2
+}
+
+func (ev *SpatialEnv) Label() string { return ev.Name }
+
+func (ev *SpatialEnv) Defaults() {
+	ev.Size = 1
+	ev.Speed = 0.05
+	ev.TurnSD = 0.5
+	ev.PINoise = 0.005
+	ev.PIReset = 20
+	ev.PoolsY, ev.PoolsX = 6, 2
+	ev.UnitsY, ev.UnitsX = 3, 4
+	ev.PlacePools = 2
+	ev.GridSpacing = 0.3
+	ev.GridRatio = 1.2
+	ev.PlaceSigma = 0.1
+	ev.KPerPool = 3
+}
+
+// NGridModules returns the number of grid cell modules.
+func (ev *SpatialEnv) NGridModules() int {
+	return max(ev.PoolsY*ev.PoolsX-ev.PlacePools, 0)
+}
+
+// NPlaceCells returns the number of place cells.
+func (ev *SpatialEnv) NPlaceCells() int {
+	return ev.PlacePools * ev.UnitsY * ev.UnitsX
+}
+
+// ModuleSpacing returns the grid spacing of given module.
+func (ev *SpatialEnv) ModuleSpacing(mod int) float32 {
+	return ev.GridSpacing * math32.Pow(ev.GridRatio, float32(mod))
+}
+
+// Config configures the state tensors for the current shape parameters,
+// and draws new grid orientations and phases and place centers.
+// Must be called after changing the shape parameters.
+func (ev *SpatialEnv) Config() {
+	ev.seedRand(0)
+	shp := []int{ev.PoolsY, ev.PoolsX, ev.UnitsY, ev.UnitsX}
+	ev.EC.SetShape(shp, "PoolY", "PoolX", "UnitY", "UnitX")
+	ev.Cue.SetShape(shp, "PoolY", "PoolX", "UnitY", "UnitX")
+	ev.PosState.SetShape([]int{2}, "XY")
+	nmod := ev.NGridModules()
+	ev.GridOrient = make([]float32, nmod)
+	for mi := range ev.GridOrient {
+		ev.GridOrient[mi] = ev.Rand.Float32() * math.Pi / 3
+	}
+	ev.GridPhase = make([]math32.Vector2, nmod)
+	ev.PlaceCenters = make([]math32.Vector2, ev.NPlaceCells())
+	ev.RemapGrid()
+	ev.RemapPlace()
+}
+
+// RemapGrid draws new random spatial phases for the grid modules,
+// keeping their spacing and orientation, as in the global remapping
+// of grid cells in a novel arena.
+func (ev *SpatialEnv) RemapGrid() {
+	for mi := range ev.GridPhase {
+		ev.GridPhase[mi] = math32.Vec2(ev.Rand.Float32()*ev.Size, ev.Rand.Float32()*ev.Size)
+	}
+}
+
+// RemapPlace draws new random place field centers, as in the
+// remapping of place cells across contexts.
+func (ev *SpatialEnv) RemapPlace() {
+	for pi := range ev.PlaceCenters {
+		ev.PlaceCenters[pi] = math32.Vec2(ev.Rand.Float32()*ev.Size, ev.Rand.Float32()*ev.Size)
+	}
+}
+
+func (ev *SpatialEnv) State(element string) tensor.Tensor {
+	switch element {
+	case "EC", "Input", "ECout":
+		return &ev.EC
+	case "Cue":
+		return &ev.Cue
+	case "Pos":
+		return &ev.PosState
+	}
+	return nil
+}
+
+// String returns the current true position as a string.
+func (ev *SpatialEnv) String() string {
+	return fmt.Sprintf("x_%.2f_y_%.2f", ev.Pos.X, ev.Pos.Y)
+}
+
+// Init starts a new trajectory at a random position and heading.
+// Config must have been called.
+func (ev *SpatialEnv) Init(run int) {
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.seedRand(run)
+	ev.Heading = ev.Rand.Float32() * 2 * math.Pi
+	pos := math32.Vec2(ev.Rand.Float32()*ev.Size, ev.Rand.Float32()*ev.Size)
+	ev.SetPos(pos, pos)
+}
+
+// seedRand seeds the Rand stream with Seed + run, if Seed is set.
+func (ev *SpatialEnv) seedRand(run int) {
+	if ev.Seed != 0 {
+		ev.Rand.NewRand(ev.Seed + int64(run))
+	}
+}
+
+// Step moves one step along the trajectory and renders the EC pattern.
+func (ev *SpatialEnv) Step() bool {
+	ev.Trial.Incr()
+	ev.Move()
+	return true
+}
+
+func (ev *SpatialEnv) Action(element string, input tensor.Tensor) {
+	// nop
+}
+
+// Move moves one step in the current heading, after a random turn,
+// reflecting off the walls of the arena, and updates the
+// path-integrated estimate of the position, then renders the EC pattern.
+func (ev *SpatialEnv) Move() {
+	ev.Heading += float32(ev.Rand.NormFloat64()) * ev.TurnSD
+	dp := math32.Vec2(math32.Cos(ev.Heading), math32.Sin(ev.Heading)).MulScalar(ev.Speed)
+	pos := ev.Pos.Add(dp)
+	if pos.X < 0 || pos.X > ev.Size {
+		pos.X = ev.reflect(pos.X)
+		ev.Heading = math.Pi - ev.Heading
+	}
+	if pos.Y < 0 || pos.Y > ev.Size {
+		pos.Y = ev.reflect(pos.Y)
+		ev.Heading = -ev.Heading
+	}
+	est := ev.EstPos.Add(pos.Sub(ev.Pos))
+	if ev.PINoise > 0 {
+		est.X += float32(ev.Rand.NormFloat64()) * ev.PINoise
+		est.Y += float32(ev.Rand.NormFloat64()) * ev.PINoise
+	}
+	if ev.PIReset > 0 && (ev.Trial.Cur+1)%ev.PIReset == 0 {
+		est = pos
+	}
+	ev.SetPos(pos, est)
+}
+
+// reflect returns the coordinate reflected back inside the arena.
+func (ev *SpatialEnv) reflect(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	if v > ev.Size {
+		return 2*ev.Size - v
+	}
+	return v
+}
+
+// SetPos sets the true and path-integrated positions,
+// and renders the EC pattern.
+func (ev *SpatialEnv) SetPos(pos, est math32.Vector2) {
+	ev.Pos = pos
+	ev.EstPos = est
+	ev.PosState.Values[0] = pos.X
+	ev.PosState.Values[1] = pos.Y
+	ev.Render()
+}
+
+// GridRate returns the firing rate, between 0 and 1, of given unit
+// in given grid module, at given position: the sum of 3 cosine
+// gratings 60 degrees apart, with the peaks of the unit offset
+// within the grid by its index in the module.
+func (ev *SpatialEnv) GridRate(mod, unit int, pos math32.Vector2) float32 {
+	lam := ev.ModuleSpacing(mod)
+	th := ev.GridOrient[mod]
+	uy, ux := unit/ev.UnitsX, unit%ev.UnitsX
+	fx := (float32(ux) + 0.5) / float32(ev.UnitsX)
+	fy := (float32(uy) + 0.5) / float32(ev.UnitsY)
+	a1 := math32.Vec2(math32.Cos(th), math32.Sin(th)).MulScalar(lam)
+	a2 := math32.Vec2(math32.Cos(th+math.Pi/3), math32.Sin(th+math.Pi/3)).MulScalar(lam)
+	off := ev.GridPhase[mod].Add(a1.MulScalar(fx)).Add(a2.MulScalar(fy))
+	d := pos.Sub(off)
+	kmag := 4 * math.Pi / (math32.Sqrt(3) * lam)
+	sum := float32(0)
+	for k := 0; k < 3; k++ {
+		ang := th + math.Pi/6 + float32(k)*math.Pi/3
+		sum += math32.Cos(kmag * (math32.Cos(ang)*d.X + math32.Sin(ang)*d.Y))
+	}
+	return (sum + 1.5) / 4.5
+}
+
+// PlaceRate returns the firing rate, between 0 and 1,
+// of given place cell at given position.
+func (ev *SpatialEnv) PlaceRate(cell int, pos math32.Vector2) float32 {
+	d := pos.Sub(ev.PlaceCenters[cell])
+	return math32.Exp(-(d.X*d.X + d.Y*d.Y) / (2 * ev.PlaceSigma * ev.PlaceSigma))
+}
+
+// Render computes the EC and Cue patterns for the current positions.
+func (ev *SpatialEnv) Render() {
+	nmod := ev.NGridModules()
+	nu := ev.UnitsY * ev.UnitsX
+	for pi := range ev.PoolsY * ev.PoolsX {
+		vals := ev.EC.Values[pi*nu : (pi+1)*nu]
+		cue := ev.Cue.Values[pi*nu : (pi+1)*nu]
+		if pi < nmod {
+			for ui := range vals {
+				vals[ui] = ev.GridRate(pi, ui, ev.EstPos)
+			}
+			ev.kWTA(vals)
+			copy(cue, vals)
+			continue
+		}
+		for ui := range vals {
+			vals[ui] = ev.PlaceRate((pi-nmod)*nu+ui, ev.Pos)
+		}
+		ev.kWTA(vals)
+		clear(cue)
+	}
+}
+
+// kWTA sets the KPerPool most active of the given pool values to 1,
+// and the rest to 0, if KPerPool > 0.
+func (ev *SpatialEnv) kWTA(vals []float32) {
+	if ev.KPerPool <= 0 {
+		return
+	}
+	idx := make([]int, len(vals))
+	for i := range idx {
+		idx[i] = i
+	}
+	slices.SortStableFunc(idx, func(a, b int) int {
+		switch {
+		case vals[a] > vals[b]:
+			return -1
+		case vals[a] < vals[b]:
+			return 1
+		}
+		return 0
+	})
+	for i, ui := range idx {
+		if i < ev.KPerPool {
+			vals[ui] = 1
+		} else {
+			vals[ui] = 0
+		}
+	}
+}
+
+// Walk moves n steps along the trajectory, returning the positions.
+func (ev *SpatialEnv) Walk(n int) []SpatialSample {
+	samps := make([]SpatialSample, n)
+	for i := range samps {
+		ev.Step()
+		samps[i] = SpatialSample{Pos: ev.Pos, EstPos: ev.EstPos}
+	}
+	return samps
+}
+
+// PatsTable configures the given table with the EC patterns for the
+// given trajectory positions, under the current grid phases and place
+// centers, in the standard hippocampus pattern format: Name (prefix_N),
+// Input, ECout, and the true X and Y positions.  ECout is the full EC
+// pattern, and Input is also, unless cue is true, in which case it has
+// the Cue pattern, for testing recall of the place cells.
+func (ev *SpatialEnv) PatsTable(dt *table.Table, prefix string, samps []SpatialSample, cue bool) {
+	dt.DeleteAll()
+	shp := []int{ev.PoolsY, ev.PoolsX, ev.UnitsY, ev.UnitsX}
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", shp, "PoolY", "PoolX", "UnitY", "UnitX")
+	dt.AddFloat32TensorColumn("ECout", shp, "PoolY", "PoolX", "UnitY", "UnitX")
+	dt.AddFloat32Column("X")
+	dt.AddFloat32Column("Y")
+	dt.SetNumRows(len(samps))
+	for i, sm := range samps {
+		ev.SetPos(sm.Pos, sm.EstPos)
+		dt.SetString("Name", i, fmt.Sprintf("%s_%d", prefix, i))
+		if cue {
+			dt.SetTensor("Input", i, &ev.Cue)
+		} else {
+			dt.SetTensor("Input", i, &ev.EC)
+		}
+		dt.SetTensor("ECout", i, &ev.EC)
+		dt.SetFloat("X", i, float64(sm.Pos.X))
+		dt.SetFloat("Y", i, float64(sm.Pos.Y))
+	}
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*SpatialEnv)(nil)
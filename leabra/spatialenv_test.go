@@ -0,0 +1,57 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/math32"
+)
+
+func TestSpatialEnv(t *testing.T) {
+	ev := &SpatialEnv{}
+	ev.Defaults()
+	ev.Config()
+	ev.Init(0)
+	nmod := ev.NGridModules()
+	nu := ev.UnitsY * ev.UnitsX
+	// grid rates are periodic in the grid lattice
+	for mi := 0; mi < nmod; mi++ {
+		lam := ev.ModuleSpacing(mi)
+		th := ev.GridOrient[mi]
+		a1 := math32.Vec2(math32.Cos(th), math32.Sin(th)).MulScalar(lam)
+		pos := math32.Vec2(0.37, 0.61)
+		for ui := 0; ui < nu; ui++ {
+			r0 := ev.GridRate(mi, ui, pos)
+			r1 := ev.GridRate(mi, ui, pos.Add(a1))
+			if r0 < 0 || r0 > 1 || math32.Abs(r0-r1) > 1.0e-3 {
+				t.Errorf("module %d unit %d: rate %g not periodic: %g", mi, ui, r0, r1)
+			}
+		}
+	}
+	for range 50 {
+		ev.Step()
+		if ev.Pos.X < 0 || ev.Pos.X > ev.Size || ev.Pos.Y < 0 || ev.Pos.Y > ev.Size {
+			t.Errorf("position outside of arena: %v", ev.Pos)
+		}
+		for pi := 0; pi < ev.PoolsY*ev.PoolsX; pi++ {
+			non, ncue := 0, 0
+			for ui := pi * nu; ui < (pi+1)*nu; ui++ {
+				if ev.EC.Values[ui] > 0 {
+					non++
+				}
+				if ev.Cue.Values[ui] > 0 {
+					ncue++
+				}
+			}
+			if non != ev.KPerPool {
+				t.Errorf("pool %d: %d active, not %d", pi, non, ev.KPerPool)
+			}
+			if pi < nmod && ncue != non || pi >= nmod && ncue != 0 {
+				t.Errorf("pool %d: %d active in cue", pi, ncue)
+			}
+		}
+	}
+}
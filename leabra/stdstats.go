@@ -0,0 +1,163 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"strings"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/estats"
+)
+
+// StatValues are named stat values computed by the standard stats
+// functions (ErrStats, MemStats), which are set in the estats.Stats of
+// a sim with SetStats, to be logged under the same names.
+type StatValues map[string]float64
+
+// SetStats sets the stat values as float stats in the given stats.
+func (sv StatValues) SetStats(st *estats.Stats) {
+	for nm, v := range sv {
+		st.SetFloat(nm, v)
+	}
+}
+
+// ErrStats returns the standard error stats of the given target or
+// compare layer, at the end of the plus phase:
+//   - CorSim: the cosine between minus and plus phase activity (CosDiff.Cos).
+//   - SSE, AvgSSE: the sum and mean squared error (see MSE), with the
+//     given per-unit tolerance (e.g., 0.5).
+//   - TrlErr: 1 if SSE > 0, else 0.
+func ErrStats(ly *Layer, tol Float) StatValues {
+	sse, avgsse := ly.MSE(tol)
+	trlErr := 0.0
+	if sse > 0 {
+		trlErr = 1
+	}
+	return StatValues{"CorSim": float64(ly.CosDiff.Cos), "SSE": sse, "AvgSSE": avgsse, "TrlErr": trlErr}
+}
+
+// MemStats returns the standard memory stats of a hippocampus sim,
+// comparing the ActM activity of the out layer (ECout) against its
+// full Targ pattern, both with the given readout (see ReadoutMemStats),
+// where the cue layer (the Input, not ECin which can be active) has
+// the partial cue pattern.  Must be called at the end of the 3rd quarter,
+// so that the Targ values are the full pattern, as opposed to the plus
+// phase target values clamped from the ECin activity.
+//   - TrgOnWasOffAll, TrgOnWasOffCmp, TrgOffWasOn: see ReadoutMemStats.
+//   - Mem: 1 if remembered at the given threshold, with completion
+//     units (Cmp) for test trials.
+//   - MemCorrel: see MemCorrel.
+//   - ABMem, ACMem, LureMem: Mem for test trials of the AB or AC list,
+//     if the trial name contains ab or ac, or else of the lure list,
+//     and NaN for the other lists and for training trials, so that
+//     they aggregate separately.
+func MemStats(out, cue *Layer, ro *ReadoutParams, thr float32, trialName string, test bool) StatValues {
+	var actm, trg, cueAct []float32
+	out.UnitValuesReadout(&actm, "ActM", ro, 0)
+	out.UnitValuesReadout(&trg, "Targ", ro, 0) // full pattern target
+	cue.UnitValuesReadout(&cueAct, "ActM", ro, 0)
+	var ms ReadoutMemStats
+	ms.Compute(actm, trg, cueAct)
+	sv := StatValues{"TrgOnWasOffAll": float64(ms.TrgOnWasOffAll), "TrgOnWasOffCmp": float64(ms.TrgOnWasOffCmp), "TrgOffWasOn": float64(ms.TrgOffWasOn)}
+	mem := float64(ms.Mem(thr, test)) // no completion in training
+	sv["Mem"] = mem
+	sv["ABMem"], sv["ACMem"], sv["LureMem"] = math.NaN(), math.NaN(), math.NaN()
+	if test {
+		switch {
+		case strings.Contains(trialName, "ab"):
+			sv["ABMem"] = mem
+		case strings.Contains(trialName, "ac"):
+			sv["ACMem"] = mem
+		default:
+			sv["LureMem"] = mem
+		}
+	}
+	out.UnitValues(&actm, "ActM", 0)
+	out.UnitValues(&trg, "Targ", 0)
+	sv["MemCorrel"] = float64(MemCorrel(actm, trg))
+	return sv
+}
+
+// MemDiscrim returns the discriminability of the MemCorrel scores for
+// the trials in the given test trial log table with TrialName starting
+// with the given prefix (e.g., ab), vs. the lure trials, as d-prime
+// (MemDPrime) or the ROC area if roc (MemROCArea).
+func MemDiscrim(dt *table.Table, prefix string, roc bool) float64 {
+	var tgt, lure []float64
+	for ri := range dt.Rows {
+		sc := dt.Float("MemCorrel", ri)
+		switch nm := dt.StringValue("TrialName", ri); {
+		case strings.HasPrefix(nm, prefix):
+			tgt = append(tgt, sc)
+		case strings.HasPrefix(nm, "lure"):
+			lure = append(lure, sc)
+		}
+	}
+	if roc {
+		return MemROCArea(tgt, lure)
+	}
+	return MemDPrime(tgt, lure)
+}
+
+// SimMatStats are the within and between list similarities of a
+// similarity matrix of the patterns of a set of lists of items,
+// e.g., the AB, AC and lure lists of the hippocampus sims.
+type SimMatStats struct {
+
+	// Within is the mean similarity between the different items within
+	// each list, over the lower triangle of the matrix.
+	Within []float64
+
+	// Between is the mean similarity between the corresponding items
+	// (same index) of different lists, e.g., the AB and AC items with
+	// the same A.
+	Between float64
+}
+
+// SimMatWithinBetween returns the within and between list similarities
+// of the given square similarity matrix (e.g., simat.SimMat.Mat) of
+// patterns ordered by list, with listSize items per list, for the first
+// nLists lists (e.g., 2 for the AB and AC lists, excluding lures),
+// or all of them if 0.  Values are 0 if there are no pairs of items to
+// average over (e.g., a listSize of 1 for Within, or 1 list for Between).
+func SimMatWithinBetween(smat tensor.Tensor, listSize, nLists int) SimMatStats {
+	nitm := smat.DimSize(0)
+	if listSize <= 0 {
+		listSize = nitm
+	}
+	if listSize == 0 {
+		return SimMatStats{}
+	}
+	if nLists <= 0 || nLists*listSize > nitm {
+		nLists = nitm / listSize
+	}
+	sms := SimMatStats{Within: make([]float64, nLists)}
+	winN := make([]int, nLists)
+	btwN := 0
+	for y := range nLists * listSize {
+		for x := range y {
+			v := smat.Float([]int{y, x})
+			switch {
+			case y/listSize == x/listSize:
+				sms.Within[y/listSize] += v
+				winN[y/listSize]++
+			case y%listSize == x%listSize:
+				sms.Between += v
+				btwN++
+			}
+		}
+	}
+	for li, n := range winN {
+		if n > 0 {
+			sms.Within[li] /= float64(n)
+		}
+	}
+	if btwN > 0 {
+		sms.Between /= float64(btwN)
+	}
+	return sms
+}
@@ -0,0 +1,112 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/estats"
+)
+
+func TestStdStats(t *testing.T) {
+	net := MakeTestNet(t)
+	out := net.LayerByName("Output")
+	inp := net.LayerByName("Input")
+	var ro ReadoutParams
+	ro.Defaults()
+	setPats := func(act, trg, cue []Float) {
+		for i := range out.Neurons {
+			out.Neurons[i].ActM, out.Neurons[i].ActP, out.Neurons[i].Targ = act[i], trg[i], trg[i]
+			inp.Neurons[i].ActM = cue[i]
+		}
+	}
+	setPats([]Float{1, 0, 0, 1}, []Float{1, 1, 0, 0}, []Float{1, 0, 0, 0})
+	sv := MemStats(out, inp, &ro, 0.34, "ab_1", true)
+	if sv["TrgOnWasOffAll"] != 0.5 || sv["TrgOnWasOffCmp"] != 1 || sv["TrgOffWasOn"] != 0.5 || sv["Mem"] != 0 || sv["ABMem"] != 0 || !math.IsNaN(sv["ACMem"]) || !math.IsNaN(sv["LureMem"]) {
+		t.Errorf("MemStats wrong: %v", sv)
+	}
+	es := ErrStats(out, 0.5)
+	if es["SSE"] != 2 || es["AvgSSE"] != 0.5 || es["TrlErr"] != 1 {
+		t.Errorf("ErrStats wrong: %v", es)
+	}
+	setPats([]Float{1, 1, 0, 0}, []Float{1, 1, 0, 0}, []Float{1, 0, 0, 0})
+	sv = MemStats(out, inp, &ro, 0.34, "lure_1", true)
+	if sv["Mem"] != 1 || sv["LureMem"] != 1 || !math.IsNaN(sv["ABMem"]) || math.Abs(sv["MemCorrel"]-1) > 1e-6 {
+		t.Errorf("MemStats wrong: %v", sv)
+	}
+	if sv = MemStats(out, inp, &ro, 0.34, "ab_1", false); !math.IsNaN(sv["ABMem"]) || sv["Mem"] != 1 {
+		t.Errorf("MemStats training wrong: %v", sv)
+	}
+	if es = ErrStats(out, 0.5); es["SSE"] != 0 || es["TrlErr"] != 0 {
+		t.Errorf("ErrStats wrong: %v", es)
+	}
+	var st estats.Stats
+	st.Init()
+	es.SetStats(&st)
+	if st.Float("TrlErr") != 0 || st.Float("SSE") != 0 {
+		t.Errorf("SetStats wrong")
+	}
+
+	dt := table.NewTable()
+	dt.AddStringColumn("TrialName")
+	dt.AddFloat64Column("MemCorrel")
+	for i, nm := range []string{"ab_0", "ab_1", "ac_0", "ac_1", "lure_0", "lure_1"} {
+		dt.AddRows(1)
+		dt.SetString("TrialName", i, nm)
+		dt.SetFloat("MemCorrel", i, []float64{0.9, 0.8, 0.1, 0.3, 0.2, 0.4}[i])
+	}
+	if v := MemDiscrim(dt, "ab", true); v != 1 {
+		t.Errorf("MemDiscrim ab ROC = %g, not 1", v)
+	}
+	if v := MemDiscrim(dt, "ac", true); v != 0.25 {
+		t.Errorf("MemDiscrim ac ROC = %g, not 0.25", v)
+	}
+	if v := MemDiscrim(dt, "ab", false); v <= 0 {
+		t.Errorf("MemDiscrim ab d-prime = %g, not > 0", v)
+	}
+
+	// 3 lists of 2 items: within list = 1+list, between same item = 0.5, else 0
+	smat := tensor.NewFloat64([]int{6, 6})
+	for y := range 6 {
+		for x := range 6 {
+			switch {
+			case y/2 == x/2:
+				smat.Set([]int{y, x}, float64(1+y/2))
+			case y%2 == x%2:
+				smat.Set([]int{y, x}, 0.5)
+			}
+		}
+	}
+	sms := SimMatWithinBetween(smat, 2, 2)
+	if !slices.Equal(sms.Within, []float64{1, 2}) || sms.Between != 0.5 {
+		t.Errorf("SimMatWithinBetween wrong: %+v", sms)
+	}
+	if sms = SimMatWithinBetween(smat, 2, 0); len(sms.Within) != 3 || sms.Within[2] != 3 {
+		t.Errorf("SimMatWithinBetween all lists wrong: %+v", sms)
+	}
+}
+
+func TestSimMatWithinBetweenEmpty(t *testing.T) {
+	smat := tensor.NewFloat64([]int{0, 0})
+	if sms := SimMatWithinBetween(smat, 0, 0); len(sms.Within) != 0 || sms.Between != 0 {
+		t.Errorf("empty: %+v", sms)
+	}
+	smat = tensor.NewFloat64([]int{4, 4})
+	for i := range smat.Values {
+		smat.Values[i] = 1
+	}
+	// 4 lists of 1 item: no pairs within lists
+	if sms := SimMatWithinBetween(smat, 1, 0); !slices.Equal(sms.Within, []float64{0, 0, 0, 0}) || sms.Between != 1 {
+		t.Errorf("list size 1: %+v", sms)
+	}
+	// 1 list of 4 items: no pairs between lists
+	if sms := SimMatWithinBetween(smat, 4, 0); !slices.Equal(sms.Within, []float64{1}) || sms.Between != 0 {
+		t.Errorf("1 list: %+v", sms)
+	}
+}
@@ -0,0 +1,249 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"slices"
+
+	"cogentcore.org/core/icons"
+	"cogentcore.org/core/tree"
+	"github.com/emer/emergent/v2/egui"
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// StepGrains are the standard grains of stepping for a Stepper,
+// from a single cycle up to a whole run.
+type StepGrains int32 //enums:enum
+
+const (
+	// StepCycle steps one cycle.
+	StepCycle StepGrains = iota
+
+	// StepQuarter steps to the end of the current quarter
+	// (Context.CycPerQtr cycles).
+	StepQuarter
+
+	// StepPhase steps to the end of the current minus or plus phase,
+	// which ends at the Stepper PlusStart cycle, or at the end of the
+	// alpha cycle, respectively.
+	StepPhase
+
+	// StepAlphaCycle steps to the end of the current alpha cycle,
+	// i.e., the end of the Cycle loop.
+	StepAlphaCycle
+
+	// StepTrial steps to the end of the current trial.
+	StepTrial
+
+	// StepEpoch steps to the end of the current epoch.
+	StepEpoch
+
+	// StepRun steps to the end of the current run.
+	StepRun
+)
+
+// StopCond is a named conditional-stop predicate for a Stepper,
+// which is checked at the end of each Grain in all modes (Func can
+// check the Mode of the Loops), and stops the running when it returns true.
+type StopCond struct {
+
+	// Name of the condition, recorded in Stepper.StoppedBy when it stops.
+	Name string
+
+	// Grain at the end of which the condition is checked.
+	Grain StepGrains
+
+	// Func returns true to stop.
+	Func func() bool
+}
+
+// Stepper steps the looper.Stacks of a sim at the standard StepGrains,
+// including the quarters and phases within the Cycle loop, and stops
+// running when any of its StopConds is true, e.g., when a stat reaches
+// a criterion, instead of checking StopNow flags in the sim code.
+// Create with NewStepper, which adds the stop condition checks to the
+// loops, and add it to the toolbar with AddToolbar.  The sub-trial
+// grains stop at the start of the next cycle (before any quarter
+// events at that cycle), and the last quarter, the plus phase and the
+// alpha cycle stop after the end of the trial.
+type Stepper struct {
+
+	// Mode is the mode (stack of loops) to step with Step and Run.
+	Mode etime.Modes
+
+	// Grain is the grain of stepping for Step.
+	Grain StepGrains
+
+	// N is the number of Grains per Step.
+	N int `min:"1"`
+
+	// PlusStart is the cycle at which the plus phase starts,
+	// for StepPhase.
+	PlusStart int `default:"75"`
+
+	// StoppedBy is the name of the StopCond that stopped the last
+	// Step or Run, or empty if none.
+	StoppedBy string `edit:"-"`
+
+	// Conds are the conditional-stop predicates.
+	Conds []StopCond `display:"-"`
+
+	// Loops are the looper stacks that are stepped.
+	Loops *looper.Stacks `display:"-"`
+
+	// Ctx is the context, for the cycles per quarter.
+	Ctx *Context `display:"-"`
+
+	// trial is the trial-level time scale.
+	trial etime.Times
+}
+
+// NewStepper returns a new Stepper for the given looper stacks, context,
+// and cycle at which the plus phase starts (typically 75), stepping
+// the Train mode one Trial at a time, and adds the checks of the stop
+// conditions to the loops of all modes.  Can pass a trial-level time
+// scale to use instead of the default etime.Trial.
+func NewStepper(ls *looper.Stacks, ctx *Context, plusStart int, trial ...etime.Times) *Stepper {
+	st := &Stepper{Mode: etime.Train, Grain: StepTrial, N: 1, PlusStart: plusStart, Loops: ls, Ctx: ctx, trial: etime.Trial}
+	if len(trial) > 0 {
+		st.trial = trial[0]
+	}
+	for _, stack := range ls.Stacks {
+		if cyc := stack.Loops[etime.Cycle]; cyc != nil {
+			cyc.OnEnd.Add("Stepper:StopConds", func() {
+				cur := cyc.Counter.Cur + 1 // number of cycles done
+				for gr := StepCycle; gr <= StepAlphaCycle; gr++ {
+					if st.atBoundary(gr, cur, cyc.Counter.Max) {
+						st.checkConds(gr)
+					}
+				}
+			})
+		}
+		for gr, tm := range map[StepGrains]etime.Times{StepTrial: st.trial, StepEpoch: etime.Epoch, StepRun: etime.Run} {
+			if lp := stack.Loops[tm]; lp != nil {
+				lp.OnEnd.Add("Stepper:StopConds", func() {
+					st.checkConds(gr)
+				})
+			}
+		}
+	}
+	return st
+}
+
+// AddStopCond adds a conditional stop with the given name, checked at
+// the end of each grain, replacing any existing one with the same name.
+func (st *Stepper) AddStopCond(name string, grain StepGrains, fun func() bool) {
+	st.RemoveStopCond(name)
+	st.Conds = append(st.Conds, StopCond{Name: name, Grain: grain, Func: fun})
+}
+
+// RemoveStopCond removes the conditional stop with the given name.
+func (st *Stepper) RemoveStopCond(name string) {
+	st.Conds = slices.DeleteFunc(st.Conds, func(sc StopCond) bool { return sc.Name == name })
+}
+
+// checkConds checks the stop conditions for the given grain,
+// stopping the loops if any is true.
+func (st *Stepper) checkConds(grain StepGrains) {
+	for _, sc := range st.Conds {
+		if sc.Grain == grain && sc.Func() {
+			st.StoppedBy = sc.Name
+			st.Loops.Stop(etime.Cycle)
+			return
+		}
+	}
+}
+
+// atBoundary returns true if the given number of cycles done in the
+// Cycle loop, of max cycles, is at the end of the given grain.
+func (st *Stepper) atBoundary(grain StepGrains, cyc, maxCyc int) bool {
+	switch grain {
+	case StepCycle:
+		return true
+	case StepQuarter:
+		return cyc%st.Ctx.CycPerQtr == 0
+	case StepPhase:
+		return cyc == st.PlusStart || cyc == maxCyc
+	case StepAlphaCycle:
+		return cyc == maxCyc
+	}
+	return false
+}
+
+// Step steps the Mode by N of the Grain, stopping earlier if any
+// of the stop conditions is true.
+func (st *Stepper) Step() {
+	st.StoppedBy = ""
+	n := max(st.N, 1)
+	switch st.Grain {
+	case StepTrial:
+		st.Loops.Step(st.Mode, n, st.trial)
+	case StepEpoch:
+		st.Loops.Step(st.Mode, n, etime.Epoch)
+	case StepRun:
+		st.Loops.Step(st.Mode, n, etime.Run)
+	default:
+		st.Loops.Step(st.Mode, st.stepCycles(n), etime.Cycle)
+	}
+}
+
+// stepCycles returns the number of cycles to step for n sub-trial
+// Grains, from the current position in the Cycle loop.
+func (st *Stepper) stepCycles(n int) int {
+	if st.Grain == StepCycle {
+		return n
+	}
+	cyc := st.Loops.Loop(st.Mode, etime.Cycle)
+	maxCyc := cyc.Counter.Max
+	cur := cyc.Counter.Cur
+	if cur >= maxCyc {
+		cur = 0
+	}
+	ncyc := 0
+	for range n {
+		for {
+			cur++
+			ncyc++
+			if st.atBoundary(st.Grain, cur, maxCyc) {
+				break
+			}
+		}
+		if cur >= maxCyc {
+			cur = 0
+		}
+	}
+	return ncyc
+}
+
+// Run runs the Mode to completion, stopping earlier if any
+// of the stop conditions is true.
+func (st *Stepper) Run() {
+	st.StoppedBy = ""
+	st.Loops.Run(st.Mode)
+}
+
+// AddToolbar adds a Grain Step button to the given toolbar plan, which
+// steps in a separate goroutine while the GUI is not running, using the
+// Mode, Grain and N settings of the Stepper (e.g., shown in the sim
+// struct editor).
+func (st *Stepper) AddToolbar(p *tree.Plan, gui *egui.GUI) {
+	gui.AddToolbarItem(p, egui.ToolbarItem{Label: "Grain Step",
+		Icon:    icons.SkipNext,
+		Tooltip: "Steps the Stepper Mode by N of its Grain (Cycle, Quarter, Phase, AlphaCycle, Trial, Epoch or Run), or until one of its stop conditions is true.",
+		Active:  egui.ActiveStopped,
+		Func: func() {
+			if gui.IsRunning {
+				return
+			}
+			gui.IsRunning = true
+			gui.Toolbar.Restyle()
+			go func() {
+				st.Step()
+				gui.Stopped()
+			}()
+		},
+	})
+}
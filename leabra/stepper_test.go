@@ -0,0 +1,64 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+func TestStepper(t *testing.T) {
+	ls := looper.NewStacks()
+	ls.AddStack(etime.Train).AddTime(etime.Run, 1).AddTime(etime.Epoch, 3).AddTime(etime.Trial, 4).AddTime(etime.Cycle, 100)
+	ctx := NewContext()
+	cycles := 0
+	ls.Loop(etime.Train, etime.Cycle).OnStart.Add("Cycle", func() { cycles++ })
+	trl := ls.Loop(etime.Train, etime.Trial)
+	epc := ls.Loop(etime.Train, etime.Epoch)
+	cyc := ls.Loop(etime.Train, etime.Cycle)
+	ls.Init()
+	st := NewStepper(ls, ctx, 75)
+
+	step := func(grain StepGrains, n, wantCycles, wantTrl, wantCyc int) {
+		t.Helper()
+		st.Grain = grain
+		st.N = n
+		cycles = 0
+		st.Step()
+		if cycles != wantCycles || trl.Counter.Cur != wantTrl || cyc.Counter.Cur != wantCyc {
+			t.Errorf("%s x %d: got cycles %d trial %d cycle %d, want %d %d %d", grain, n, cycles, trl.Counter.Cur, cyc.Counter.Cur, wantCycles, wantTrl, wantCyc)
+		}
+	}
+	step(StepQuarter, 1, 25, 0, 25)
+	step(StepPhase, 1, 50, 0, 75)
+	step(StepPhase, 1, 25, 1, 0)
+	step(StepCycle, 3, 3, 1, 3)
+	step(StepAlphaCycle, 1, 97, 2, 0)
+	step(StepQuarter, 2, 50, 2, 50)
+	step(StepTrial, 1, 50, 3, 0)
+	step(StepEpoch, 1, 100, 0, 0)
+	if epc.Counter.Cur != 1 {
+		t.Errorf("StepEpoch: got epoch %d, want 1", epc.Counter.Cur)
+	}
+
+	st.AddStopCond("Trial2", StepTrial, func() bool { return trl.Counter.Cur == 2 })
+	st.AddStopCond("Quarter", StepQuarter, func() bool { return false })
+	cycles = 0
+	st.Run()
+	if st.StoppedBy != "Trial2" || epc.Counter.Cur != 1 || cycles != 300 {
+		t.Errorf("stop cond: got StoppedBy %q epoch %d cycles %d", st.StoppedBy, epc.Counter.Cur, cycles)
+	}
+	st.RemoveStopCond("Trial2")
+	if len(st.Conds) != 1 {
+		t.Errorf("RemoveStopCond: got %d conds", len(st.Conds))
+	}
+	cycles = 0
+	st.Run()
+	if st.StoppedBy != "" || cycles != 500 {
+		t.Errorf("run to end: got StoppedBy %q cycles %d", st.StoppedBy, cycles)
+	}
+}
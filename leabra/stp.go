@@ -0,0 +1,189 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"sort"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+// STPParams are the parameters for Tsodyks-Markram style short-term
+// synaptic plasticity (depression and facilitation), which modulates the
+// efficacy of the synapses of each sending neuron on a pathway on every
+// cycle, as a function of its recent activity, e.g., for the strongly
+// facilitating mossy fiber synapses onto CA3, or depressing recurrent
+// collaterals.  The rate-code activation is treated as a spike rate
+// (Act * Rate spikes per cycle), which drives the release probability U
+// up (facilitation) and depletes the available resources X (depression),
+// each of which recovers toward its resting value with its own time
+// constant.  The sending efficacy is U * X / U0, which is 1 at rest.
+// The state is shared by all of the synapses of each sending neuron,
+// so it is stored per sending neuron (Path.STPu, STPx), and shown
+// in the STPu and STPx synapse variables of each of its synapses.
+type STPParams struct {
+
+	// use short-term plasticity on this pathway
+	On bool
+
+	// baseline (resting) release probability U0: the proportion of the available resources used by each spike -- lower values produce facilitation and higher ones depression
+	U0 Float `default:"0.5" min:"0" max:"1"`
+
+	// time constant in cycles (msec) for the recovery of the depleted resources X back to 1 -- larger = more lasting depression
+	TauD Float `default:"200" min:"1"`
+
+	// time constant in cycles (msec) for the decay of the facilitated release probability U back to U0 -- larger = more lasting facilitation
+	TauF Float `default:"50" min:"1"`
+
+	// spike rate per cycle for a fully active (Act = 1) sending neuron, e.g., 0.1 = 100 Hz
+	Rate Float `default:"0.1" min:"0" max:"1"`
+
+	// rate = 1 / tau
+	DtD Float `display:"-" json:"-" xml:"-"`
+
+	// rate = 1 / tau
+	DtF Float `display:"-" json:"-" xml:"-"`
+}
+
+func (sp *STPParams) Update() {
+	sp.DtD = 1 / sp.TauD
+	sp.DtF = 1 / sp.TauF
+}
+
+func (sp *STPParams) Defaults() {
+	sp.On = false
+	sp.U0 = 0.5
+	sp.TauD = 200
+	sp.TauF = 50
+	sp.Rate = 0.1
+	sp.Update()
+}
+
+func (sp *STPParams) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return sp.On
+	}
+}
+
+// Step updates the release probability u and available resources x for
+// one cycle with the given sending activation, returning the new values.
+func (sp *STPParams) Step(act, u, x Float) (Float, Float) {
+	r := act * sp.Rate
+	u += sp.DtF*(sp.U0-u) + sp.U0*(1-u)*r
+	u = min(max(u, 0), 1)
+	x += sp.DtD*(1-x) - u*x*r
+	x = min(max(x, 0), 1)
+	return u, x
+}
+
+// Efficacy returns the sending efficacy for the given u and x,
+// relative to the resting efficacy U0.
+func (sp *STPParams) Efficacy(u, x Float) Float {
+	if sp.U0 <= 0 {
+		return 0
+	}
+	return u * x / sp.U0
+}
+
+// InitSTP sets the short-term plasticity state of each sending neuron
+// to the resting values (STPu = U0, STPx = 1), along with the
+// STPSent values, which are used for delta-coding the sending
+// activation times efficacy.  The state is only allocated if STP.On.
+func (pt *Path) InitSTP() {
+	if !pt.STP.On {
+		pt.STPu, pt.STPx, pt.STPSent = nil, nil, nil
+		return
+	}
+	ns := len(pt.Send.Neurons)
+	if len(pt.STPu) != ns {
+		pt.STPu = make([]Float, ns)
+		pt.STPx = make([]Float, ns)
+		pt.STPSent = make([]Float, ns)
+	}
+	for si := range pt.STPu {
+		pt.STPu[si] = pt.STP.U0
+		pt.STPx[si] = 1
+		pt.STPSent[si] = 0
+	}
+}
+
+// DecaySTP decays the short-term plasticity state toward the resting
+// values by given proportion, e.g., at the start of a trial
+// (see Layer.DecayState).
+func (pt *Path) DecaySTP(decay Float) {
+	if decay <= 0 {
+		return
+	}
+	for si := range pt.STPu {
+		pt.STPu[si] += decay * (pt.STP.U0 - pt.STPu[si])
+		pt.STPx[si] += decay * (1 - pt.STPx[si])
+	}
+}
+
+// SendGDeltaSTP updates the short-term plasticity state for each sending
+// neuron for one cycle, and sends the change in its activation times
+// the sending efficacy, to integrate synaptic conductances on receivers.
+// This is used instead of SendGDelta when STP.On, with the same
+// OptThresh values of the sending layer.
+func (pt *Path) SendGDeltaSTP() {
+	sly := pt.Send
+	if len(pt.STPu) != len(sly.Neurons) { // turned on after InitWeights
+		pt.InitSTP()
+	}
+	ot := &sly.Act.OptThresh
+	for si := range sly.Neurons {
+		if pt.SConN[si] == 0 {
+			continue
+		}
+		nrn := &sly.Neurons[si]
+		act := nrn.Act
+		if nrn.IsOff() || act <= ot.Send {
+			act = 0
+		}
+		u, x := pt.STP.Step(act, pt.STPu[si], pt.STPx[si])
+		pt.STPu[si], pt.STPx[si] = u, x
+		send := act * pt.STP.Efficacy(u, x)
+		delta := send - pt.STPSent[si]
+		if (send == 0 && pt.STPSent[si] != 0) || fmath.Abs(delta) > ot.Delta {
+			pt.SendGDelta(si, delta)
+			pt.STPSent[si] = send
+		}
+	}
+}
+
+// stpValues returns the per sending neuron values of the STPu or STPx
+// synapse variable with the given index, which are shared by all of the
+// synapses of each sending neuron, with ok = false for other variables.
+// The values are nil if STP is not On, in which case the resting value
+// is returned by stpRest.
+func (pt *Path) stpValues(vidx int) (vals []Float, ok bool) {
+	switch vidx {
+	case SynapseVarsMap["STPu"]:
+		return pt.STPu, true
+	case SynapseVarsMap["STPx"]:
+		return pt.STPx, true
+	}
+	return nil, false
+}
+
+// stpRest returns the resting value of the STPu or STPx synapse
+// variable with the given index.
+func (pt *Path) stpRest(vidx int) Float {
+	if vidx == SynapseVarsMap["STPu"] {
+		return pt.STP.U0
+	}
+	return 1
+}
+
+// synSendIndex returns the index of the sending neuron of the given
+// synapse index, which are in sending neuron order.
+func (pt *Path) synSendIndex(synIndex int) int {
+	return sort.Search(len(pt.SConIndexSt), func(si int) bool {
+		return int(pt.SConIndexSt[si]) > synIndex
+	}) - 1
+}
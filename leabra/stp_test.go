@@ -0,0 +1,88 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestSTP(t *testing.T) {
+	// geRaw returns the GeRaw of the first hidden unit after each of the given cycles
+	// of constant input, with the given STP params on the input pathway.
+	geRaw := func(on bool, u0, tauF, tauD Float, cycs ...int) ([]float64, *Path) {
+		net := NewNetwork("STP")
+		in := net.AddLayer2D("Input", 4, 4, InputLayer)
+		hid := net.AddLayer2D("Hidden", 2, 2, SuperLayer)
+		pt := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+		if err := net.Build(); err != nil {
+			t.Fatal(err)
+		}
+		net.Defaults()
+		pt.STP.On = on
+		pt.STP.U0, pt.STP.TauF, pt.STP.TauD = u0, tauF, tauD
+		net.UpdateParams()
+		net.InitWeights()
+		inpat := tensor.NewFloat32([]int{4, 4})
+		for i := range inpat.Len() {
+			inpat.SetFloat1D(i, 1)
+		}
+		ctx := NewContext()
+		net.InitExt()
+		in.ApplyExt(inpat)
+		net.AlphaCycInit(true)
+		ctx.AlphaCycStart()
+		var ges []float64
+		for cyc := range slices.Max(cycs) + 1 {
+			net.Cycle(ctx)
+			ctx.CycleInc()
+			if slices.Contains(cycs, cyc) {
+				ges = append(ges, float64(hid.Neurons[0].GeRaw))
+			}
+		}
+		return ges, pt
+	}
+	base, _ := geRaw(false, 0.5, 50, 200, 5, 60)
+	if math.Abs(base[1]-base[0]) > 1e-5 {
+		t.Errorf("GeRaw changed without STP: %v", base)
+	}
+	dep, pt := geRaw(true, 0.5, 20, 200, 0, 5, 60)
+	if dep[0] < 0.9*base[0] || dep[2] > 0.8*dep[1] {
+		t.Errorf("depression should reduce GeRaw from its initial value: %v vs. %v", dep, base)
+	}
+	if x := pt.SynValue("STPx", 0, 0); x >= 1 || x <= 0 || x != float32(pt.STPx[0]) {
+		t.Errorf("STPx synapse var: %g, sender %g", x, pt.STPx[0])
+	}
+	var us []float32
+	pt.SynValues(&us, "STPu")
+	for si := range pt.SConN {
+		st := int(pt.SConIndexSt[si])
+		for ci := range int(pt.SConN[si]) {
+			if us[st+ci] != float32(pt.STPu[si]) {
+				t.Fatalf("STPu synapse %d of sender %d: %g, sender %g", ci, si, us[st+ci], pt.STPu[si])
+			}
+		}
+	}
+	_, off := geRaw(false, 0.5, 20, 200, 5)
+	if off.STPu != nil || off.STPSent != nil {
+		t.Errorf("STP state allocated without STP.On")
+	}
+	if u, x := off.SynValue("STPu", 1, 0), off.SynValue("STPx", 1, 0); u != 0.5 || x != 1 {
+		t.Errorf("STP synapse vars without STP.On: %g %g, want resting values", u, x)
+	}
+	fac, _ := geRaw(true, 0.1, 400, 20, 5, 60)
+	if fac[1] < 1.2*fac[0] {
+		t.Errorf("facilitation should increase GeRaw: %v", fac)
+	}
+	_, pt = geRaw(true, 0.5, 20, 200, 60)
+	pt.Send.DecayState(1)
+	if pt.STPx[0] != 1 || pt.STPu[0] != 0.5 {
+		t.Errorf("DecayState should reset STP state: %g %g", pt.STPu[0], pt.STPx[0])
+	}
+}
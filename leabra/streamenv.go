@@ -0,0 +1,320 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/env"
+	"github.com/emer/emergent/v2/etime"
+)
+
+// StreamEnv is an env.Env that streams the patterns of a pattern table
+// file from disk in chunks of ChunkRows rows, instead of loading the whole
+// table into memory as env.FixedTable does, so that datasets much larger
+// than memory can be used, e.g., for pretraining cortical models.
+// The next Prefetch chunks are read and parsed in the background while
+// the current one is used.  Each epoch is one pass through the file,
+// after which it is read again from the start.  The rows are presented
+// in file order, or, if Shuffle, in a random order within each chunk.
+//
+// The file is in the tab-separated (or comma-separated for .csv) format
+// written by table.SaveCSV with headers, optionally gzip compressed (.gz),
+// and the State of an element is the current row of the column of the
+// same name.  Without table headers, the column types are inferred from
+// the first chunk.  Parquet files are not supported: convert them to TSV.
+// Call Close when done, to stop the background reading.
+type StreamEnv struct {
+
+	// name of this environment, usually Train vs. Test.
+	Name string
+
+	// Filename is the pattern table file to stream.
+	Filename core.Filename
+
+	// ChunkRows is the number of rows read into memory at a time.
+	ChunkRows int `default:"1024" min:"1"`
+
+	// Prefetch is the number of chunks read ahead in the background.
+	Prefetch int `default:"2" min:"1"`
+
+	// Shuffle presents the rows of each chunk in a random order.
+	Shuffle bool
+
+	// NameCol is the name of the Name column -- defaults to 'Name'.
+	NameCol string
+
+	// Seed, if non-zero, is the seed for the Rand stream of this
+	// environment, which is seeded with Seed + run in Init.
+	// If 0, the global random stream is used.
+	Seed int64
+
+	// Rand is the random number stream for this environment.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// Trial is the current row within the epoch (pass through the file).
+	// Max is set to NRows at the end of the first epoch.
+	Trial env.Counter `display:"inline"`
+
+	// Epoch is the number of complete passes through the file.
+	Epoch env.Counter `display:"inline"`
+
+	// TrialName is the contents of the Name column of the current row,
+	// if present.
+	TrialName env.CurPrvString
+
+	// NRows is the number of rows in the file, which is known at the
+	// end of the first epoch, and 0 before that.
+	NRows int `edit:"-"`
+
+	// Chunk is the current chunk of the table.
+	Chunk *table.Table `display:"-"`
+
+	// Err is the error, if any, from reading the file, after
+	// which Step returns false.
+	Err error `display:"-"`
+
+	// order is the order of the rows of the Chunk
+	order []int
+
+	// row is the index of the current row in the order
+	row int
+
+	// chunks are the chunks read by the background reader
+	chunks chan streamChunk
+
+	// stop stops the background reader
+	stop chan struct{}
+}
+
+// streamChunk is a chunk of a StreamEnv table sent by the background
+// reader, or the end of a pass through the file, or an error.
+type streamChunk struct {
+	table *table.Table
+	end   bool
+	err   error
+}
+
+func (ev *StreamEnv) Label() string { return ev.Name }
+
+func (ev *StreamEnv) Defaults() {
+	ev.ChunkRows = 1024
+	ev.Prefetch = 2
+}
+
+// Init (re)starts streaming the file from the start.
+func (ev *StreamEnv) Init(run int) {
+	ev.Close()
+	if ev.NameCol == "" {
+		ev.NameCol = "Name"
+	}
+	if ev.Seed != 0 {
+		ev.Rand.NewRand(ev.Seed + int64(run))
+	}
+	ev.Trial.Scale = etime.Trial
+	ev.Trial.Init()
+	ev.Trial.Max = 0
+	ev.Trial.Cur = -1 // init state -- key so that first Step() = 0
+	ev.Epoch.Scale = etime.Epoch
+	ev.Epoch.Init()
+	ev.NRows = 0
+	ev.Chunk = nil
+	ev.Err = nil
+	ev.row = 0
+	ev.stop = make(chan struct{})
+	ev.chunks = make(chan streamChunk, max(ev.Prefetch, 1))
+	go streamTable(ev.Filename, max(ev.ChunkRows, 1), ev.chunks, ev.stop)
+}
+
+// Close stops the background reading of the file.
+func (ev *StreamEnv) Close() {
+	if ev.stop == nil {
+		return
+	}
+	close(ev.stop)
+	for range ev.chunks { // wait for reader to finish
+	}
+	ev.stop = nil
+	ev.chunks = nil
+}
+
+// Step advances to the next row, which starts a new epoch
+// after the last row of the file.  Returns false if there is an error
+// reading the file (see Err).
+func (ev *StreamEnv) Step() bool {
+	if ev.Err != nil || ev.chunks == nil {
+		return false
+	}
+	ev.Epoch.Same()
+	ev.row++
+	for ev.Chunk == nil || ev.row >= len(ev.order) {
+		ck, ok := <-ev.chunks
+		switch {
+		case !ok:
+			ev.Err = errors.New("leabra.StreamEnv: stream closed")
+			return false
+		case ck.err != nil:
+			ev.Err = ck.err
+			return false
+		case ck.end:
+			if ev.NRows == 0 {
+				ev.NRows = ev.Trial.Cur + 1
+				ev.Trial.Max = ev.NRows
+			}
+			ev.Epoch.Incr()
+			ev.Trial.Cur = -1
+		default:
+			ev.Chunk = ck.table
+			ev.order = ev.order[:0]
+			for i := range ev.Chunk.Rows {
+				ev.order = append(ev.order, i)
+			}
+			if ev.Shuffle {
+				randx.PermuteInts(ev.order, &ev.Rand)
+			}
+			ev.row = 0
+		}
+	}
+	ev.Trial.Set(ev.Trial.Cur + 1)
+	if nms, err := ev.Chunk.ColumnByName(ev.NameCol); err == nil {
+		ev.TrialName.Set(nms.String1D(ev.Row()))
+	}
+	return true
+}
+
+// Row returns the current row in the Chunk.
+func (ev *StreamEnv) Row() int {
+	return ev.order[ev.row]
+}
+
+func (ev *StreamEnv) State(element string) tensor.Tensor {
+	if ev.Chunk == nil {
+		return nil
+	}
+	return ev.Chunk.Tensor(element, ev.Row())
+}
+
+func (ev *StreamEnv) Action(element string, input tensor.Tensor) {
+	// nop
+}
+
+// Compile-time check that implements Env interface
+var _ env.Env = (*StreamEnv)(nil)
+
+// errStreamStopped is returned when the background reader is stopped.
+var errStreamStopped = errors.New("stream stopped")
+
+// streamTable reads the given table file in chunks of given rows,
+// sending them and the end of each pass through the file on chunks,
+// repeatedly, until stopped or an error occurs.  It closes chunks
+// when it returns.
+func streamTable(filename core.Filename, rows int, chunks chan<- streamChunk, stop <-chan struct{}) {
+	defer close(chunks)
+	send := func(ck streamChunk) bool {
+		select {
+		case <-stop:
+			return false
+		default:
+		}
+		select {
+		case chunks <- ck:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	var tmpl *table.Table
+	for {
+		n, err := streamTablePass(filename, rows, &tmpl, send)
+		if err == errStreamStopped {
+			return
+		}
+		if err == nil && n == 0 {
+			err = errors.New("no rows")
+		}
+		if err != nil {
+			send(streamChunk{err: fmt.Errorf("leabra.StreamEnv: %s: %w", filename, err)})
+			return
+		}
+		if !send(streamChunk{end: true}) {
+			return
+		}
+	}
+}
+
+// streamTablePass makes one pass through the given table file, sending
+// chunks of given rows, and returns the number of rows.  The table template
+// for the chunks is configured from the headers and first chunk if nil.
+func streamTablePass(filename core.Filename, rows int, tmpl **table.Table, send func(streamChunk) bool) (int, error) {
+	fp, err := os.Open(string(filename))
+	if err != nil {
+		return 0, err
+	}
+	defer fp.Close()
+	var r io.Reader = bufio.NewReader(fp)
+	fn := strings.TrimSuffix(string(filename), ".gz")
+	if fn != string(filename) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return 0, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+	cr := csv.NewReader(r)
+	cr.Comma = table.Tab.Rune()
+	if strings.HasSuffix(fn, ".csv") {
+		cr.Comma = table.Comma.Rune()
+	}
+	cr.FieldsPerRecord = -1
+	hdrs, err := cr.Read()
+	if err != nil {
+		return 0, err
+	}
+	nrows := 0
+	recs := make([][]string, 0, rows)
+	for {
+		recs = recs[:0]
+		for len(recs) < rows {
+			rec, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nrows, err
+			}
+			recs = append(recs, rec)
+		}
+		if len(recs) == 0 {
+			return nrows, nil
+		}
+		if *tmpl == nil {
+			*tmpl = table.NewTable()
+			if err := table.ConfigFromHeaders(*tmpl, hdrs, append([][]string{hdrs}, recs...)); err != nil {
+				return nrows, err
+			}
+		}
+		ct := (*tmpl).Clone()
+		ct.SetNumRows(len(recs))
+		for ri, rec := range recs {
+			ct.ReadCSVRow(rec, ri)
+		}
+		nrows += len(recs)
+		if !send(streamChunk{table: ct}) {
+			return nrows, errStreamStopped
+		}
+	}
+}
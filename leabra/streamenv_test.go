@@ -0,0 +1,92 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestStreamEnv(t *testing.T) {
+	dt := table.NewTable()
+	dt.AddStringColumn("Name")
+	dt.AddFloat32TensorColumn("Input", []int{2, 2})
+	dt.SetNumRows(10)
+	for row := range 10 {
+		dt.SetString("Name", row, fmt.Sprintf("p%d", row))
+		dt.SetTensorFloat1D("Input", row, row%4, 1)
+	}
+	dir := t.TempDir()
+	fnm := filepath.Join(dir, "pats.tsv")
+	if err := dt.SaveCSV(core.Filename(fnm), table.Tab, true); err != nil {
+		t.Fatal(err)
+	}
+	ev := &StreamEnv{Name: "Train", Filename: core.Filename(fnm)}
+	ev.Defaults()
+	ev.ChunkRows = 3
+	ev.Init(0)
+	defer ev.Close()
+	for i := range 25 {
+		if !ev.Step() {
+			t.Fatal(ev.Err)
+		}
+		row := i % 10
+		if ev.Trial.Cur != row || ev.Epoch.Cur != i/10 {
+			t.Fatalf("step %d: Trial %d Epoch %d, want %d %d", i, ev.Trial.Cur, ev.Epoch.Cur, row, i/10)
+		}
+		if want := fmt.Sprintf("p%d", row); ev.TrialName.Cur != want {
+			t.Errorf("step %d: TrialName %s, want %s", i, ev.TrialName.Cur, want)
+		}
+		if st := ev.State("Input"); st == nil || st.Float1D(row%4) != 1 || st.Len() != 4 {
+			t.Errorf("step %d: State %v", i, st)
+		}
+	}
+	if ev.NRows != 10 || ev.Trial.Max != 10 {
+		t.Errorf("NRows %d Trial.Max %d, want 10", ev.NRows, ev.Trial.Max)
+	}
+
+	// gzipped and shuffled within chunks
+	var buf bytes.Buffer
+	if err := dt.WriteCSV(&buf, table.Tab, true); err != nil {
+		t.Fatal(err)
+	}
+	gfnm := filepath.Join(dir, "pats.tsv.gz")
+	fp, err := os.Create(gfnm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gz := gzip.NewWriter(fp)
+	gz.Write(buf.Bytes())
+	gz.Close()
+	fp.Close()
+	ev.Filename = core.Filename(gfnm)
+	ev.Shuffle = true
+	ev.Seed = 1
+	ev.Init(0)
+	seen := map[string]bool{}
+	for i := range 10 {
+		if !ev.Step() {
+			t.Fatal(ev.Err)
+		}
+		nm := ev.TrialName.Cur
+		if seen[nm] || (nm[1]-'0')/3 != byte(i/3) {
+			t.Errorf("step %d: got %s out of chunk order", i, nm)
+		}
+		seen[nm] = true
+	}
+
+	ev.Filename = core.Filename(filepath.Join(dir, "missing.tsv"))
+	ev.Init(0)
+	if ev.Step() || ev.Err == nil {
+		t.Error("expected error for missing file")
+	}
+}
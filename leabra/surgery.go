@@ -0,0 +1,252 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"slices"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// Network surgery methods modify the structure of a network after Build,
+// without rebuilding the entire network, so that the learned weights of
+// the rest of the network are preserved.  This supports lesion studies and
+// growth experiments in the middle of training.  Any params for new or
+// resized elements must be applied after the surgery (e.g., ApplyParams),
+// and any references to layers by name (e.g., SendTo, CIN.RewLays)
+// must be updated by the caller when deleting layers.
+
+// AddLayerPostBuild adds a new layer with given name and shape to
+// an already-built network, building the layer and initializing its
+// state with Defaults params.  Use ConnectLayersPostBuild to connect it.
+func (nt *Network) AddLayerPostBuild(name string, shape []int, typ LayerTypes) (*Layer, error) {
+	if nt.LayerByName(name) != nil {
+		return nil, fmt.Errorf("leabra.AddLayerPostBuild: layer named %s already exists", name)
+	}
+	ly := nt.AddLayer(name, shape, typ)
+	ly.Index = len(nt.Layers) - 1
+	ly.Network = nt
+	ly.Defaults()
+	if err := ly.Build(); err != nil {
+		nt.deleteLayerIndex(ly.Index)
+		return nil, err
+	}
+	nt.LayoutLayers()
+	ly.InitWeights()
+	return ly, nil
+}
+
+// ConnectLayersPostBuild establishes a pathway between two layers in an
+// already-built network, building the pathway, and initializing its
+// weights with Defaults params.  The GScale values of the pathways into
+// the receiving layer are updated to reflect the new pathway.
+func (nt *Network) ConnectLayersPostBuild(send, recv *Layer, pat paths.Pattern, typ PathTypes) (*Path, error) {
+	pt := nt.ConnectLayers(send, recv, pat, typ)
+	pt.Defaults()
+	if err := pt.Build(); err != nil {
+		nt.DeletePath(pt)
+		return nil, err
+	}
+	pt.InitWeights()
+	recv.GScaleFromAvgAct()
+	return pt, nil
+}
+
+// DeletePath removes the given pathway from the network.
+// The GScale values of the remaining pathways into the receiving layer
+// are updated to reflect the removal.
+func (nt *Network) DeletePath(pt *Path) {
+	rlay, slay := pt.Recv, pt.Send
+	rlay.RecvPaths = slices.DeleteFunc(rlay.RecvPaths, func(p *Path) bool { return p == pt })
+	slay.SendPaths = slices.DeleteFunc(slay.SendPaths, func(p *Path) bool { return p == pt })
+	if len(rlay.Pools) > 0 {
+		rlay.GScaleFromAvgAct()
+	}
+}
+
+// DeleteLayer removes the named layer from the network, along with
+// all of its sending and receiving pathways.  Layers positioned relative
+// to the deleted layer take over its relative position.
+func (nt *Network) DeleteLayer(name string) error {
+	ly := nt.LayerByName(name)
+	if ly == nil {
+		return fmt.Errorf("leabra.DeleteLayer: layer named %s not found", name)
+	}
+	for _, oly := range nt.Layers {
+		if oly != ly && oly.Pos.Other == ly.Name {
+			oly.Pos = ly.Pos
+		}
+	}
+	for _, pt := range slices.Clone(ly.RecvPaths) {
+		nt.DeletePath(pt)
+	}
+	for _, pt := range slices.Clone(ly.SendPaths) {
+		nt.DeletePath(pt)
+	}
+	nt.deleteLayerIndex(ly.Index)
+	nt.LayoutLayers()
+	return nil
+}
+
+// deleteLayerIndex removes the layer at given index from the Layers list,
+// and updates the layer indexes and maps.
+func (nt *Network) deleteLayerIndex(idx int) {
+	nt.Layers = slices.Delete(nt.Layers, idx, idx+1)
+	for li, ly := range nt.Layers {
+		ly.Index = li
+	}
+	nt.MakeLayerMaps()
+}
+
+// Resize changes the shape of this layer in an already-built
+// network, rebuilding the layer and all of its sending and receiving
+// pathways.  The neuron state and the synaptic weights are preserved for
+// all neurons whose shape index (e.g., Y, X) is present in both the old
+// and new shapes, and for the synapses connecting such neurons that are
+// present in both the old and new connectivity.  All other neurons and
+// synapses are initialized as in InitWeights.  The shape must have the
+// same number of dimensions as the existing shape.
+func (ly *Layer) Resize(shape []int) error {
+	if len(shape) != ly.Shape.NumDims() {
+		return fmt.Errorf("leabra.Resize: layer %s new shape %v must have the same number of dimensions as existing shape %v", ly.Name, shape, ly.Shape.Sizes)
+	}
+	oldShape := &tensor.Shape{}
+	oldShape.CopyShape(&ly.Shape)
+	oldNeurons := ly.Neurons
+	oldActAvg := ly.Pools[0].ActAvg
+	pts := slices.Clone(ly.RecvPaths)
+	for _, pt := range ly.SendPaths {
+		if pt.Send != pt.Recv {
+			pts = append(pts, pt)
+		}
+	}
+	olds := make([]*resizeSyns, len(pts))
+	for i, pt := range pts {
+		olds[i] = newResizeSyns(pt)
+	}
+
+	ly.SetShape(shape)
+	if err := ly.Build(); err != nil { // recv paths
+		return err
+	}
+	for _, pt := range ly.SendPaths {
+		if pt.Off || pt.Send == pt.Recv {
+			continue
+		}
+		if err := pt.Build(); err != nil {
+			return err
+		}
+	}
+	ly.InitWeights() // send paths and neurons
+	for _, pt := range ly.RecvPaths {
+		if !pt.Off && pt.Send != pt.Recv {
+			pt.InitWeights()
+		}
+	}
+
+	nmap := resizeIndexMap(oldShape, &ly.Shape)
+	for ni, oi := range nmap {
+		if oi < 0 {
+			continue
+		}
+		nrn := &ly.Neurons[ni]
+		spi := nrn.SubPool
+		*nrn = oldNeurons[oi]
+		nrn.SubPool = spi
+	}
+	for pi := range ly.Pools {
+		ly.Pools[pi].ActAvg = oldActAvg
+	}
+	for i, pt := range pts {
+		if pt.Off {
+			continue
+		}
+		olds[i].restore(pt, ly, nmap)
+	}
+	for _, pt := range ly.SendPaths {
+		pt.Recv.GScaleFromAvgAct()
+	}
+	ly.GScaleFromAvgAct()
+	ly.Network.LayoutLayers()
+	return nil
+}
+
+// ResizeLayer changes the shape of the named layer, preserving the
+// state and weights of existing neurons.  See Layer.Resize for details.
+func (nt *Network) ResizeLayer(name string, shape []int) error {
+	ly := nt.LayerByName(name)
+	if ly == nil {
+		return fmt.Errorf("leabra.ResizeLayer: layer named %s not found", name)
+	}
+	return ly.Resize(shape)
+}
+
+// resizeIndexMap returns, for each neuron index in the new shape, the
+// corresponding neuron index in the old shape, or -1 if not present.
+func resizeIndexMap(oldShape, newShape *tensor.Shape) []int {
+	n := newShape.Len()
+	nmap := make([]int, n)
+	for ni := 0; ni < n; ni++ {
+		idx := newShape.Index(ni)
+		if oldShape.IndexIsValid(idx) {
+			nmap[ni] = oldShape.Offset(idx)
+		} else {
+			nmap[ni] = -1
+		}
+	}
+	return nmap
+}
+
+// resizeSyns records the synapses of a pathway prior to resizing.
+type resizeSyns struct {
+	syns map[[2]int32]Synapse
+}
+
+// newResizeSyns records the synapses of given pathway, by recv, send index.
+func newResizeSyns(pt *Path) *resizeSyns {
+	rs := &resizeSyns{syns: make(map[[2]int32]Synapse, pt.Syns.Len())}
+	if pt.Off {
+		return rs
+	}
+	for ri := range pt.RConN {
+		nc := int(pt.RConN[ri])
+		st := int(pt.RConIndexSt[ri])
+		for ci := 0; ci < nc; ci++ {
+			si := pt.RConIndex[st+ci]
+			rs.syns[[2]int32{int32(ri), si}] = pt.Syns.Synapse(int(pt.RSynIndex[st+ci]))
+		}
+	}
+	return rs
+}
+
+// restore restores the recorded synapses into the rebuilt pathway, where
+// nmap maps the new neuron indexes of the resized layer to the old ones.
+func (rs *resizeSyns) restore(pt *Path, ly *Layer, nmap []int) {
+	oldIndex := func(lay *Layer, ni int32) int32 {
+		if lay != ly {
+			return ni
+		}
+		return int32(nmap[ni])
+	}
+	for ri := range pt.RConN {
+		ori := oldIndex(pt.Recv, int32(ri))
+		if ori < 0 {
+			continue
+		}
+		nc := int(pt.RConN[ri])
+		st := int(pt.RConIndexSt[ri])
+		for ci := 0; ci < nc; ci++ {
+			osi := oldIndex(pt.Send, pt.RConIndex[st+ci])
+			if osi < 0 {
+				continue
+			}
+			if sy, ok := rs.syns[[2]int32{ori, osi}]; ok {
+				pt.Syns.SetSynapse(int(pt.RSynIndex[st+ci]), &sy)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestSurgery(t *testing.T) {
+	net := NewNetwork("Surgery")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	ext := net.AddLayer2D("Extra", 2, 2, SuperLayer)
+	out := net.AddLayer2D("Output", 2, 4, TargetLayer)
+	fmIn := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	net.BidirConnectLayers(hid, out, paths.NewFull())
+	net.BidirConnectLayers(hid, ext, paths.NewFull())
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+
+	inpat := tensor.NewFloat32([]int{4, 4})
+	for i := range 4 {
+		inpat.SetFloat1D(i*5, 1)
+	}
+	ctx := NewContext()
+	// trial runs one training trial with the given output pattern.
+	trial := func(outpat *tensor.Float32) {
+		net.InitExt()
+		in.ApplyExt(inpat)
+		out.ApplyExt(outpat)
+		net.AlphaCycle(ctx, true)
+	}
+	trial(tensor.NewFloat32([]int{2, 4}))
+	inWts := slices.Clone(fmIn.Syns.Wt)
+
+	nw, err := net.AddLayerPostBuild("New", []int{3, 3}, SuperLayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := net.ConnectLayersPostBuild(hid, nw, paths.NewFull(), ForwardPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.ResizeLayer("Output", []int{3, 4}); err != nil {
+		t.Fatal(err)
+	}
+	if err := net.DeleteLayer("Extra"); err != nil {
+		t.Fatal(err)
+	}
+	if net.LayerByName("Extra") != nil || slices.Contains(net.Layers, ext) || len(hid.RecvPaths) != 2 || len(hid.SendPaths) != 2 {
+		t.Fatalf("Extra layer and its pathways not deleted")
+	}
+	for li, ly := range net.Layers {
+		if ly.Index != li {
+			t.Errorf("layer %s Index %d != %d", ly.Name, ly.Index, li)
+		}
+	}
+	if err := net.ValidateLayerRefs(); err != nil {
+		t.Error(err)
+	}
+	if !slices.Equal(fmIn.Syns.Wt, inWts) {
+		t.Errorf("weights of the untouched Input pathway changed by surgery")
+	}
+
+	outpat := tensor.NewFloat32([]int{3, 4})
+	outpat.SetFloat1D(11, 1)
+	trial(outpat)
+	for _, ly := range net.Layers {
+		for ni := range ly.Neurons {
+			if act := ly.Neurons[ni].Act; math.IsNaN(float64(act)) {
+				t.Fatalf("layer %s neuron %d Act is NaN", ly.Name, ni)
+			}
+		}
+	}
+	if act := out.Neurons[11].ActP; act < 0.5 {
+		t.Errorf("new Output neuron not clamped in the plus phase: ActP = %g", act)
+	}
+	if act := nw.Pools[0].ActM.Max; act == 0 {
+		t.Errorf("new layer not activated by its new pathway")
+	}
+
+	var buf bytes.Buffer
+	if err := net.WriteWeightsJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	inWts = slices.Clone(fmIn.Syns.Wt)
+	net.InitWeights()
+	if err := net.ReadWeightsJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	for si, wt := range fmIn.Syns.Wt {
+		if math.Abs(float64(wt-inWts[si])) > 1e-4 { // saved with weights.Prec digits
+			t.Fatalf("weight %d of the Input pathway not restored from the saved weights: %g vs. %g", si, wt, inWts[si])
+		}
+	}
+}
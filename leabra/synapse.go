@@ -10,33 +10,37 @@ import (
 	"unsafe"
 
 	"cogentcore.org/core/types"
+	"github.com/emer/leabra/v2/fmath"
 )
 
-// leabra.Synapse holds state for the synaptic connection between neurons
+// leabra.Synapse holds state for the synaptic connection between neurons.
+// The synapses for a pathway are stored in the structure-of-arrays
+// [Synapses] form, and this struct is used to get and set all of the
+// values for a given synapse, and for the variable names and docs.
 type Synapse struct {
 
 	// synaptic weight value, sigmoid contrast-enhanced version
 	// of the linear weight LWt.
-	Wt float32
+	Wt Float
 
 	// linear (underlying) weight value, which learns according
 	// to the lrate specified in the connection spec.
 	// This is converted into the effective weight value, Wt,
 	// via sigmoidal contrast enhancement (see WtSigParams).
-	LWt float32
+	LWt Float
 
 	// change in synaptic weight, driven by learning algorithm.
-	DWt float32
+	DWt Float
 
 	// DWt normalization factor, reset to max of abs value of DWt,
 	// decays slowly down over time. Serves as an estimate of variance
 	// in weight changes over time.
-	Norm float32
+	Norm Float
 
 	// momentum, as time-integrated DWt changes, to accumulate a
 	// consistent direction of weight change and cancel out
 	// dithering contradictory changes.
-	Moment float32
+	Moment Float
 
 	// scaling parameter for this connection: effective weight value
 	// is scaled by this factor in computing G conductance.
@@ -46,33 +50,63 @@ type Synapse struct {
 	// otherwise is automatically reset to 1; use a very small number to
 	// approximate 0). Typically set by using the paths.Pattern Weights()
 	// values where appropriate.
-	Scale float32
+	Scale Float
 
 	// NTr is the new trace, which drives updates to trace value.
 	// su * (1-ru_msn) for gated, or su * ru_msn for not-gated (or for non-thalamic cases).
-	NTr float32
+	NTr Float
 
 	// Tr is the current ongoing trace of activations, which drive learning.
 	// Adds NTr and clears after learning on current values, and includes both
 	// thal gated (+ and other nongated, - inputs).
-	Tr float32
+	Tr Float
+
+	// STPu is the short-term plasticity release probability, which is
+	// increased by sending activity (facilitation), and decays back to
+	// STP.U0 -- only updated when STP.On (see STPParams).  It is shared
+	// by all the synapses of the sending neuron (see Path.STPu).
+	STPu Float
+
+	// STPx is the short-term plasticity proportion of available synaptic
+	// resources, which are depleted by sending activity (depression), and
+	// recover back to 1 -- only updated when STP.On (see STPParams).
+	// It is shared by all the synapses of the sending neuron.
+	STPx Float
+
+	// Imp is the consolidated importance of the synapse for elastic
+	// weight consolidation, which scales the pull of LWt back toward
+	// LWtCons -- only updated when EWC.On (see EWCParams).
+	Imp Float
+
+	// ImpAcc is the importance of the synapse accumulated from its weight
+	// changes since the last consolidation -- only updated when EWC.On.
+	ImpAcc Float
+
+	// LWtCons is the consolidated linear weight, recorded at the last
+	// consolidation -- only used when EWC.On.
+	LWtCons Float
 }
 
 func (sy *Synapse) VarNames() []string {
 	return SynapseVars
 }
 
-var SynapseVars = []string{"Wt", "LWt", "DWt", "Norm", "Moment", "Scale", "NTr", "Tr"}
+var SynapseVars = []string{"Wt", "LWt", "DWt", "Norm", "Moment", "Scale", "NTr", "Tr", "STPu", "STPx", "Imp", "ImpAcc", "LWtCons"}
 
 var SynapseVarProps = map[string]string{
-	"Wt":     `cat:"Wts"`,
-	"LWt":    `cat:"Wts"`,
-	"DWt":    `cat:"Wts" auto-scale:"+"`,
-	"Norm":   `cat:"Wts"`,
-	"Moment": `cat:"Wts" auto-scale:"+"`,
-	"Scale":  `cat:"Wts"`,
-	"NTr":    `cat:"Wts"`,
-	"Tr":     `cat:"Wts"`,
+	"Wt":      `cat:"Wts"`,
+	"LWt":     `cat:"Wts"`,
+	"DWt":     `cat:"Wts" auto-scale:"+"`,
+	"Norm":    `cat:"Wts"`,
+	"Moment":  `cat:"Wts" auto-scale:"+"`,
+	"Scale":   `cat:"Wts"`,
+	"NTr":     `cat:"Wts"`,
+	"Tr":      `cat:"Wts"`,
+	"STPu":    `cat:"Wts"`,
+	"STPx":    `cat:"Wts"`,
+	"Imp":     `cat:"Wts"`,
+	"ImpAcc":  `cat:"Wts" auto-scale:"+"`,
+	"LWtCons": `cat:"Wts"`,
 }
 
 var SynapseVarsMap map[string]int
@@ -99,13 +133,13 @@ func SynapseVarByName(varNm string) (int, error) {
 }
 
 // VarByIndex returns variable using index (0 = first variable in SynapseVars list)
-func (sy *Synapse) VarByIndex(idx int) float32 {
-	fv := (*float32)(unsafe.Pointer(uintptr(unsafe.Pointer(sy)) + uintptr(4*idx)))
+func (sy *Synapse) VarByIndex(idx int) Float {
+	fv := (*Float)(unsafe.Pointer(uintptr(unsafe.Pointer(sy)) + uintptr(fmath.Size*idx)))
 	return *fv
 }
 
 // VarByName returns variable by name, or error
-func (sy *Synapse) VarByName(varNm string) (float32, error) {
+func (sy *Synapse) VarByName(varNm string) (Float, error) {
 	i, err := SynapseVarByName(varNm)
 	if err != nil {
 		return 0, err
@@ -113,13 +147,13 @@ func (sy *Synapse) VarByName(varNm string) (float32, error) {
 	return sy.VarByIndex(i), nil
 }
 
-func (sy *Synapse) SetVarByIndex(idx int, val float32) {
-	fv := (*float32)(unsafe.Pointer(uintptr(unsafe.Pointer(sy)) + uintptr(4*idx)))
+func (sy *Synapse) SetVarByIndex(idx int, val Float) {
+	fv := (*Float)(unsafe.Pointer(uintptr(unsafe.Pointer(sy)) + uintptr(fmath.Size*idx)))
 	*fv = val
 }
 
 // SetVarByName sets synapse variable to given value
-func (sy *Synapse) SetVarByName(varNm string, val float32) error {
+func (sy *Synapse) SetVarByName(varNm string, val Float) error {
 	i, err := SynapseVarByName(varNm)
 	if err != nil {
 		return err
@@ -127,3 +161,111 @@ func (sy *Synapse) SetVarByName(varNm string, val float32) error {
 	sy.SetVarByIndex(i, val)
 	return nil
 }
+
+// Synapses holds the state for all of the synapses in a pathway, in a
+// structure-of-arrays (SoA) layout, with a separate slice of values for
+// each synaptic variable (with the same names and meaning as in
+// [Synapse]), all indexed by the synapse index, in sending neuron order.
+// The inner loops over synapses (e.g., SendGDelta, DWt) only access the
+// variables they need, contiguously in memory, which is much more cache
+// efficient than an array of Synapse structs, and amenable to
+// vectorization.  The STPu and STPx variables are shared by all the
+// synapses of each sending neuron, so they are stored per sending neuron
+// in the Path (Path.STPu, STPx) instead.  The elastic weight consolidation
+// variables (Imp, ImpAcc, LWtCons) are only allocated when EWC.On, and
+// are empty (0 values) otherwise, to save memory.
+type Synapses struct {
+	Wt      []Float
+	LWt     []Float
+	DWt     []Float
+	Norm    []Float
+	Moment  []Float
+	Scale   []Float
+	NTr     []Float
+	Tr      []Float
+	Imp     []Float
+	ImpAcc  []Float
+	LWtCons []Float
+}
+
+// SetLen allocates the variables for n synapses, with 0 values,
+// except for the EWC variables, which are allocated by setEWCLen.
+func (ss *Synapses) SetLen(n int) {
+	for _, vp := range [...]*[]Float{&ss.Wt, &ss.LWt, &ss.DWt, &ss.Norm, &ss.Moment, &ss.Scale, &ss.NTr, &ss.Tr} {
+		*vp = make([]Float, n)
+	}
+	ss.setEWCLen(0)
+}
+
+// setEWCLen allocates the EWC variables (Imp, ImpAcc, LWtCons)
+// for n synapses, with 0 values, or frees them for n = 0.
+func (ss *Synapses) setEWCLen(n int) {
+	for _, vp := range [...]*[]Float{&ss.Imp, &ss.ImpAcc, &ss.LWtCons} {
+		*vp = nil
+		if n > 0 {
+			*vp = make([]Float, n)
+		}
+	}
+}
+
+// Len returns the number of synapses.
+func (ss *Synapses) Len() int {
+	return len(ss.Wt)
+}
+
+// vars returns pointers to the variable slices, in SynapseVars order,
+// which are nil for the STPu and STPx variables that are not stored here.
+func (ss *Synapses) vars() [13]*[]Float {
+	return [13]*[]Float{&ss.Wt, &ss.LWt, &ss.DWt, &ss.Norm, &ss.Moment, &ss.Scale, &ss.NTr, &ss.Tr, nil, nil, &ss.Imp, &ss.ImpAcc, &ss.LWtCons}
+}
+
+// Values returns the slice of values for the given variable index
+// (0 = first variable in SynapseVars list), or nil if out of range,
+// or for the STPu and STPx variables (see Path.STPu).  It is empty
+// for the EWC variables when they are not allocated.
+func (ss *Synapses) Values(idx int) []Float {
+	vs := ss.vars()
+	if idx < 0 || idx >= len(vs) || vs[idx] == nil {
+		return nil
+	}
+	return *vs[idx]
+}
+
+// VarByIndex returns variable using index (0 = first variable in
+// SynapseVars list) for given synapse index, which is 0 if the
+// variable is not allocated.
+func (ss *Synapses) VarByIndex(idx, syni int) Float {
+	vals := ss.Values(idx)
+	if len(vals) == 0 {
+		return 0
+	}
+	return vals[syni]
+}
+
+// SetVarByIndex sets variable using index (0 = first variable in
+// SynapseVars list) for given synapse index, if it is allocated.
+func (ss *Synapses) SetVarByIndex(idx, syni int, val Float) {
+	vals := ss.Values(idx)
+	if len(vals) == 0 {
+		return
+	}
+	vals[syni] = val
+}
+
+// Synapse returns all of the values for given synapse index,
+// except for STPu and STPx.
+func (ss *Synapses) Synapse(syni int) Synapse {
+	var sy Synapse
+	for vi := range SynapseVars {
+		sy.SetVarByIndex(vi, ss.VarByIndex(vi, syni))
+	}
+	return sy
+}
+
+// SetSynapse sets all of the allocated values for given synapse index,
+// except for STPu and STPx.
+func (ss *Synapses) SetSynapse(syni int, sy *Synapse) {
+	for vi := range SynapseVars {
+		ss.SetVarByIndex(vi, syni, sy.VarByIndex(vi))
+	}
+}
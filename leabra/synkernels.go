@@ -0,0 +1,113 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+// These are the inner-loop kernels over synapses, operating on the
+// structure-of-arrays [Synapses] values.  They are unrolled by 4 with
+// bounds checks hoisted out of the loops, so the compiler generates
+// tight, pipelined code.  The dense versions are used when the receiving
+// neuron indexes of a sending neuron's synapses are contiguous, as in
+// full and pool-based pathways, so that the receiving values are also
+// accessed contiguously, with no indexing through SConIndex.
+
+// axpy adds a * x[i] to y[i], for i < len(x).
+func axpy(y []Float, a Float, x []Float) {
+	n := len(x)
+	y = y[:n]
+	i := 0
+	for ; i <= n-4; i += 4 {
+		x4 := x[i : i+4 : i+4]
+		y4 := y[i : i+4 : i+4]
+		y4[0] += a * x4[0]
+		y4[1] += a * x4[1]
+		y4[2] += a * x4[2]
+		y4[3] += a * x4[3]
+	}
+	for ; i < n; i++ {
+		y[i] += a * x[i]
+	}
+}
+
+// axpyIndex adds a * x[i] to y[idx[i]], for i < len(x).
+func axpyIndex(y []Float, idx []int32, a Float, x []Float) {
+	n := len(x)
+	idx = idx[:n]
+	i := 0
+	for ; i <= n-4; i += 4 {
+		x4 := x[i : i+4 : i+4]
+		i4 := idx[i : i+4 : i+4]
+		y[i4[0]] += a * x4[0]
+		y[i4[1]] += a * x4[1]
+		y[i4[2]] += a * x4[2]
+		y[i4[3]] += a * x4[3]
+	}
+	for ; i < n; i++ {
+		y[idx[i]] += a * x[i]
+	}
+}
+
+// maxValue returns the max of the values in x (0 if empty or all < 0).
+func maxValue(x []Float) Float {
+	mx := Float(0)
+	for _, v := range x {
+		if v > mx {
+			mx = v
+		}
+	}
+	return mx
+}
+
+// setValue sets all of the values in x to v.
+func setValue(x []Float, v Float) {
+	for i := range x {
+		x[i] = v
+	}
+}
+
+// isDenseIndexes returns true if the indexes are contiguous,
+// i.e., idx[i] = idx[0] + i, and there is at least one.
+func isDenseIndexes(idx []int32) bool {
+	if len(idx) == 0 {
+		return false
+	}
+	st := idx[0]
+	for i, ix := range idx {
+		if ix != st+int32(i) {
+			return false
+		}
+	}
+	return true
+}
+
+// recvLearnAvgs holds the receiving neuron learning running averages
+// for a pathway in structure-of-arrays form, gathered once prior to
+// computing DWt so that the inner synapse loop accesses them contiguously.
+type recvLearnAvgs struct {
+	AvgSLrn []Float
+	AvgM    []Float
+	AvgL    []Float
+
+	// LongLrate is the XCal.LongLrate value from AvgLLrn
+	LongLrate []Float
+}
+
+// gather sets the values from the given receiving layer neurons.
+func (ra *recvLearnAvgs) gather(pt *Path) {
+	rlay := pt.Recv
+	nr := len(rlay.Neurons)
+	if len(ra.AvgSLrn) != nr {
+		ra.AvgSLrn = make([]Float, nr)
+		ra.AvgM = make([]Float, nr)
+		ra.AvgL = make([]Float, nr)
+		ra.LongLrate = make([]Float, nr)
+	}
+	for ri := range rlay.Neurons {
+		rn := &rlay.Neurons[ri]
+		ra.AvgSLrn[ri] = rn.AvgSLrn
+		ra.AvgM[ri] = rn.AvgM
+		ra.AvgL[ri] = rn.AvgL
+		ra.LongLrate[ri] = pt.Learn.XCal.LongLrate(rn.AvgLLrn)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
+)
+
+// refSendGDelta is a straightforward per-synapse reference for SendGDelta.
+func refSendGDelta(pt *Path, ginc []Float, si int, delta Float) {
+	st := int(pt.SConIndexSt[si])
+	for ci := range int(pt.SConN[si]) {
+		ginc[pt.SConIndex[st+ci]] += delta * pt.GScale * pt.Syns.Wt[st+ci]
+	}
+}
+
+// refDWt is a straightforward per-synapse reference for DWtStd,
+// updating the given dwt, norm and moment values.
+func refDWt(pt *Path, dwts, norms, moments []Float) {
+	ls := &pt.Learn
+	for si := range pt.Send.Neurons {
+		sn := &pt.Send.Neurons[si]
+		if sn.AvgS < ls.XCal.LrnThr && sn.AvgM < ls.XCal.LrnThr {
+			continue
+		}
+		st := int(pt.SConIndexSt[si])
+		nc := int(pt.SConN[si])
+		maxNorm := Float(0)
+		for ci := range nc {
+			i := st + ci
+			rn := &pt.Recv.Neurons[pt.SConIndex[i]]
+			err, bcm := ls.CHLdWt(sn.AvgSLrn, sn.AvgM, rn.AvgSLrn, rn.AvgM, rn.AvgL)
+			bcm *= ls.XCal.LongLrate(rn.AvgLLrn)
+			err *= ls.XCal.MLrn
+			dwt := bcm + err
+			norm := Float(1)
+			if ls.Norm.On {
+				norm = ls.Norm.NormFromAbsDWt(&norms[i], fmath.Abs(dwt))
+				maxNorm = max(maxNorm, norms[i])
+			}
+			if ls.Momentum.On {
+				dwt = norm * ls.Momentum.MomentFromDWt(&moments[i], dwt)
+			} else {
+				dwt *= norm
+			}
+			dwts[i] += ls.Lrate * dwt
+		}
+		if ls.Norm.On {
+			for ci := range nc {
+				norms[st+ci] = maxNorm
+			}
+		}
+	}
+}
+
+func TestSynKernels(t *testing.T) {
+	net := NewNetwork("SynKernels")
+	in := net.AddLayer2D("Input", 5, 5, InputLayer)
+	full := net.AddLayer2D("Full", 5, 7, SuperLayer)
+	sparse := net.AddLayer2D("Sparse", 5, 7, SuperLayer)
+	net.ConnectLayers(in, full, paths.NewFull(), ForwardPath)
+	rnd := paths.NewUniformRand()
+	rnd.PCon = 0.4
+	net.ConnectLayers(in, sparse, rnd, ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+
+	rn := rand.New(rand.NewSource(1))
+	for _, ly := range net.Layers {
+		for ni := range ly.Neurons {
+			nrn := &ly.Neurons[ni]
+			nrn.AvgSLrn = Float(rn.Float64())
+			nrn.AvgS = Float(rn.Float64())
+			nrn.AvgM = Float(rn.Float64())
+			nrn.AvgL = Float(0.1 + rn.Float64())
+			nrn.AvgLLrn = Float(rn.Float64() * 0.01)
+		}
+	}
+	in.Neurons[3].AvgS, in.Neurons[3].AvgM = 0, 0 // below learning threshold
+
+	fpt, spt := full.RecvPaths[0], sparse.RecvPaths[0]
+	nSparse := 0
+	for _, d := range spt.sconDense {
+		if !d {
+			nSparse++
+		}
+	}
+	if !slices.Contains(fpt.sconDense, true) || slices.Contains(fpt.sconDense, false) || nSparse == 0 {
+		t.Fatalf("full pathway must be all dense, and uniform random pathway sparse")
+	}
+	sparseFull := func() { // full pathway with dense kernels turned off
+		for si := range fpt.sconDense {
+			fpt.sconDense[si] = false
+		}
+	}
+
+	// tolerance for differences in floating point rounding
+	const tol = 1e-6
+	cmp := func(what string, got, want []Float) {
+		t.Helper()
+		for i := range want {
+			if fmath.Abs(got[i]-want[i]) > tol {
+				t.Errorf("%s: value %d: %g != reference %g", what, i, got[i], want[i])
+				return
+			}
+		}
+	}
+	for _, cs := range []struct {
+		name  string
+		pt    *Path
+		setup func()
+	}{{"dense", fpt, nil}, {"sparse", spt, nil}, {"full sparse", fpt, sparseFull}} {
+		pt := cs.pt
+		if cs.setup != nil {
+			cs.setup()
+		}
+		for _, mom := range []bool{false, true} {
+			pt.Learn.Norm.On = mom
+			pt.Learn.Momentum.On = mom
+
+			for ri := range pt.GInc {
+				pt.GInc[ri] = 0
+			}
+			ginc := make([]Float, len(pt.GInc))
+			for si := range in.Neurons {
+				delta := Float(rn.Float64() - 0.5)
+				pt.SendGDelta(si, delta)
+				refSendGDelta(pt, ginc, si, delta)
+			}
+			cmp(cs.name+" GInc", pt.GInc, ginc)
+
+			sy := &pt.Syns
+			for i := range sy.DWt {
+				sy.DWt[i] = Float(rn.Float64() * 0.01)
+				sy.Norm[i] = Float(rn.Float64() * 0.01)
+				sy.Moment[i] = Float(rn.Float64()*0.01 - 0.005)
+			}
+			dwts, norms, moments := slices.Clone(sy.DWt), slices.Clone(sy.Norm), slices.Clone(sy.Moment)
+			pt.DWt()
+			refDWt(pt, dwts, norms, moments)
+			cmp(cs.name+" DWt", sy.DWt, dwts)
+			cmp(cs.name+" Norm", sy.Norm, norms)
+			cmp(cs.name+" Moment", sy.Moment, moments)
+		}
+	}
+}
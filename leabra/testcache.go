@@ -0,0 +1,322 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+
+	"github.com/emer/emergent/v2/etime"
+	"github.com/emer/emergent/v2/looper"
+)
+
+// TestCache caches the final settled state of the network for each
+// distinct testing trial, keyed by a fingerprint of the external inputs
+// applied to the network.  When the network weights and parameters are
+// unchanged since the state was cached (as determined by a fingerprint of
+// all the weights, learned excitabilities and parameters, see NetKey),
+// the settling process can be skipped and the cached state restored instead,
+// which substantially speeds up frequent-interval testing on the same patterns.
+// Caching is only valid if settling is deterministic and independent of
+// the prior trial, so it is automatically disabled for networks with any
+// random or carried-over state (see Valid).
+// Use LooperTestCache to add to the looper Test stack.
+type TestCache struct {
+
+	// if true, use the cache
+	On bool
+
+	// fingerprint of the network weights and parameters
+	// for the current cached states (see NetKey)
+	WtsKey uint64 `edit:"-"`
+
+	// number of trials that were restored from the cache
+	Hits int `edit:"-"`
+
+	// number of trials that had to be computed
+	Misses int `edit:"-"`
+
+	// cached states, keyed by input fingerprint
+	States map[uint64]*TestCacheState `display:"-"`
+
+	// input fingerprint of the current trial
+	curKey uint64
+
+	// true if current trial was restored from the cache
+	curHit bool
+}
+
+// TestCacheState is the settled state of the network for one testing trial.
+type TestCacheState struct {
+
+	// neuron state for each layer
+	Neurons [][]Neuron
+
+	// pool state for each layer
+	Pools [][]Pool
+
+	// CosDiff state for each layer
+	CosDiff []CosDiffStats
+
+	// GeRaw for each path, in RecvPaths order by layer
+	GeRaw [][]Float
+
+	// Context cycle at end of settling
+	Cycle int
+
+	// Context quarter at end of settling
+	Quarter Quarters
+
+	// Context PlusPhase state at end of settling
+	PlusPhase bool
+}
+
+// Init initializes the cache for a new set of testing trials,
+// resetting the cached states if the network weights or parameters
+// have changed, as determined by NetKey.
+func (tc *TestCache) Init(net *Network) {
+	if !tc.On {
+		return
+	}
+	wk := tc.NetKey(net)
+	if tc.States == nil || wk != tc.WtsKey {
+		tc.WtsKey = wk
+		tc.States = make(map[uint64]*TestCacheState)
+	}
+}
+
+// Valid returns true if the network settling is deterministic and
+// independent of the prior trial, so that the cache can be used.
+// It is not if any layer has:
+//   - activation noise, noise injection (NoiseInject) or active dropout,
+//     which are random.
+//   - Act.Init.Decay < 1, KNa or AHP adaptation, or short-term plasticity
+//     in its pathways (STP), which carry state over from prior trials.
+//   - a CT layer, whose context (CtxtGe) comes from prior trials.
+func (tc *TestCache) Valid(net *Network) bool {
+	for _, ly := range net.Layers {
+		if ly.Off {
+			continue
+		}
+		if ly.Act.Noise.Type != NoNoise || ly.NoiseInject.On || ly.Dropout.Active {
+			return false
+		}
+		if ly.Act.Init.Decay != 1 || ly.Act.KNa.On || ly.Act.AHP.On || ly.Type == CTLayer {
+			return false
+		}
+		for _, pt := range ly.RecvPaths {
+			if !pt.Off && pt.STP.On {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NetKey returns the fingerprint of the network that determines whether
+// the cached states are still valid, which combines the weights
+// (WtsFingerprint), the learned intrinsic excitability of the neurons,
+// and the parameters (ParamsFingerprint), so that a change in any of them,
+// e.g., by Network.UpdateParamsLive, resets the cache.
+func (tc *TestCache) NetKey(net *Network) uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], net.WtsFingerprint())
+	h.Write(b[:])
+	binary.LittleEndian.PutUint64(b[:], net.ParamsFingerprint())
+	h.Write(b[:])
+	for _, ly := range net.Layers {
+		if !ly.HasExcit() {
+			continue
+		}
+		for ni := range ly.Neurons {
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(float64(ly.Neurons[ni].Excit)))
+			h.Write(b[:])
+		}
+	}
+	return h.Sum64()
+}
+
+// Restore restores the settled state for the current external inputs,
+// if present in the cache, returning true if so, in which case
+// settling should be skipped.  Must be called after inputs are applied.
+func (tc *TestCache) Restore(net *Network, ctx *Context) bool {
+	tc.curHit = false
+	if !tc.On || tc.States == nil || !tc.Valid(net) {
+		return false
+	}
+	tc.curKey = net.InputsFingerprint()
+	st, ok := tc.States[tc.curKey]
+	if !ok {
+		tc.Misses++
+		return false
+	}
+	tc.Hits++
+	tc.curHit = true
+	st.restore(net, ctx)
+	return true
+}
+
+// Save saves the settled state for the current external inputs,
+// if it was not restored from the cache.
+// Must be called at the end of settling, prior to the final QuarterFinal.
+func (tc *TestCache) Save(net *Network, ctx *Context) {
+	if !tc.On || tc.States == nil || tc.curHit || !tc.Valid(net) {
+		return
+	}
+	tc.States[tc.curKey] = newTestCacheState(net, ctx)
+}
+
+// newTestCacheState returns the current state of the network and context.
+func newTestCacheState(net *Network, ctx *Context) *TestCacheState {
+	st := &TestCacheState{Cycle: ctx.Cycle, Quarter: ctx.Quarter, PlusPhase: ctx.PlusPhase}
+	nl := len(net.Layers)
+	st.Neurons = make([][]Neuron, nl)
+	st.Pools = make([][]Pool, nl)
+	st.CosDiff = make([]CosDiffStats, nl)
+	for li, ly := range net.Layers {
+		st.Neurons[li] = append([]Neuron(nil), ly.Neurons...)
+		st.Pools[li] = append([]Pool(nil), ly.Pools...)
+		st.CosDiff[li] = ly.CosDiff
+		for _, pt := range ly.RecvPaths {
+			st.GeRaw = append(st.GeRaw, append([]Float(nil), pt.GeRaw...))
+		}
+	}
+	return st
+}
+
+// restore restores the state to the network and context,
+// keeping the running averages and other values carried over across trials
+// (see restoreNeuron), which do not depend on the current inputs.
+func (st *TestCacheState) restore(net *Network, ctx *Context) {
+	for li, ly := range net.Layers {
+		for ni := range ly.Neurons {
+			restoreNeuron(&ly.Neurons[ni], &st.Neurons[li][ni])
+		}
+		for pi := range ly.Pools {
+			pl := &ly.Pools[pi]
+			avg := pl.ActAvg
+			*pl = st.Pools[li][pi]
+			pl.ActAvg = avg
+		}
+		ly.CosDiff = st.CosDiff[li]
+	}
+	pi := 0
+	for _, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			copy(pt.GeRaw, st.GeRaw[pi])
+			pi++
+		}
+	}
+	ncyc := st.Cycle - ctx.Cycle
+	ctx.CycleTot += ncyc
+	ctx.Time += Float(ncyc) * ctx.TimePerCyc
+	ctx.Cycle = st.Cycle
+	ctx.Quarter = st.Quarter
+	ctx.PlusPhase = st.PlusPhase
+}
+
+// restoreNeuron restores the cached neuron state to the given neuron,
+// except for the values that are carried over from prior trials:
+// the prior plus phase activation (ActQ0), the running averages
+// ActAvg and AvgL, and the learned Excit and CtxtLag values.
+func restoreNeuron(nrn, cn *Neuron) {
+	q0, avg, avgL, avgLLrn := nrn.ActQ0, nrn.ActAvg, nrn.AvgL, nrn.AvgLLrn
+	excit, lag, dlag := nrn.Excit, nrn.CtxtLag, nrn.CtxtDLag
+	*nrn = *cn
+	nrn.ActQ0, nrn.ActAvg, nrn.AvgL, nrn.AvgLLrn = q0, avg, avgL, avgLLrn
+	nrn.Excit, nrn.CtxtLag, nrn.CtxtDLag = excit, lag, dlag
+}
+
+// LooperTestCache adds TestCache functions to the Test stack of the looper:
+// Init at the start of the Test Epoch, Restore at the start of each trial
+// (after ApplyInputs, which must have already been added), which skips
+// the cycle loop when the state is restored, and Save at the end of each trial.
+func LooperTestCache(ls *looper.Stacks, net *Network, ctx *Context, tc *TestCache) {
+	st := ls.Stacks[etime.Test]
+	if st == nil {
+		return
+	}
+	st.Loops[etime.Epoch].OnStart.Add("TestCache:Init", func() {
+		tc.Init(net)
+	})
+	trl := st.Loops[etime.Trial]
+	cyc := st.Loops[etime.Cycle]
+	trl.OnStart.Add("TestCache:Restore", func() {
+		if tc.Restore(net, ctx) {
+			cyc.Counter.SkipToMax()
+		}
+	})
+	trl.OnEnd.Prepend("TestCache:Save", func() bool {
+		tc.Save(net, ctx)
+		return true
+	})
+}
+
+// WtsFingerprint returns a hash fingerprint of all the synaptic weights
+// in the network, which can be used to detect changes in the weights.
+func (nt *Network) WtsFingerprint() uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	for _, ly := range nt.Layers {
+		for _, pt := range ly.SendPaths {
+			for _, wt := range pt.Syns.Wt {
+				binary.LittleEndian.PutUint64(b[:], math.Float64bits(float64(wt)))
+				h.Write(b[:])
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+// ParamsFingerprint returns a hash fingerprint of all the parameters
+// of the layers and pathways in the network (as listed by AllParams),
+// along with the Off status of each layer and pathway,
+// which can be used to detect changes in the parameters.
+func (nt *Network) ParamsFingerprint() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(nt.AllParams()))
+	for _, ly := range nt.Layers {
+		h.Write([]byte{boolByte(ly.Off)})
+		for _, pt := range ly.RecvPaths {
+			h.Write([]byte{boolByte(pt.Off)})
+		}
+	}
+	return h.Sum64()
+}
+
+// boolByte returns 1 for true and 0 for false.
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// InputsFingerprint returns a hash fingerprint of the external inputs
+// (Ext, Targ, and flags) applied to all the layers in the network,
+// along with the Off status of each layer.
+func (nt *Network) InputsFingerprint() uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			h.Write([]byte{0})
+			continue
+		}
+		h.Write([]byte{1})
+		for ni := range ly.Neurons {
+			nrn := &ly.Neurons[ni]
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(float64(nrn.Ext)))
+			h.Write(b[:])
+			binary.LittleEndian.PutUint64(b[:], math.Float64bits(float64(nrn.Targ)))
+			h.Write(b[:])
+			binary.LittleEndian.PutUint32(b[:], uint32(nrn.Flags))
+			h.Write(b[:4])
+		}
+	}
+	return h.Sum64()
+}
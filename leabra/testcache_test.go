@@ -0,0 +1,155 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// testCacheNet is a small network for testing the TestCache.
+type testCacheNet struct {
+	net          *Network
+	in, hid, out *Layer
+	tc           TestCache
+}
+
+func newTestCacheNet(t *testing.T) *testCacheNet {
+	tn := &testCacheNet{net: NewNetwork("TestCache")}
+	tn.in = tn.net.AddLayer2D("Input", 4, 4, InputLayer)
+	tn.hid = tn.net.AddLayer2D("Hidden", 4, 4, SuperLayer)
+	tn.out = tn.net.AddLayer2D("Output", 2, 4, TargetLayer)
+	tn.net.ConnectLayers(tn.in, tn.hid, paths.NewFull(), ForwardPath)
+	tn.net.BidirConnectLayers(tn.hid, tn.out, paths.NewFull())
+	if err := tn.net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	tn.net.Defaults()
+	tn.net.InitWeights()
+	tn.tc.On = true
+	tn.tc.Init(tn.net)
+	return tn
+}
+
+// settle runs one testing trial on the given input pattern, using the cache,
+// and returns the resulting neuron state of the hidden and output layers.
+func (tn *testCacheNet) settle(pat *tensor.Float32) []Float {
+	net, tc := tn.net, &tn.tc
+	ctx := NewContext()
+	net.InitExt()
+	tn.in.ApplyExt(pat)
+	net.AlphaCycInit(false)
+	ctx.AlphaCycStart()
+	if !tc.Restore(net, ctx) {
+		for qtr := range 4 {
+			ctx.PlusPhase = qtr == 3
+			for range ctx.CycPerQtr {
+				net.Cycle(ctx)
+				ctx.CycleInc()
+			}
+			if qtr < 3 {
+				net.QuarterFinal(ctx)
+				ctx.QuarterInc()
+			}
+		}
+		tc.Save(net, ctx)
+	}
+	net.QuarterFinal(ctx)
+	var vals []Float
+	for _, ly := range []*Layer{tn.hid, tn.out} {
+		for ni := range ly.Neurons {
+			nrn := &ly.Neurons[ni]
+			vals = append(vals, nrn.Act, nrn.ActM, nrn.ActP, nrn.ActQ0, nrn.ActAvg, nrn.AvgL)
+		}
+		vals = append(vals, ly.Pools[0].ActP.Avg, ly.Pools[0].ActAvg.ActPAvg, ly.CosDiff.Cos)
+	}
+	return vals
+}
+
+func TestTestCache(t *testing.T) {
+	pats := make([]*tensor.Float32, 2)
+	for pi := range pats {
+		pats[pi] = tensor.NewFloat32([]int{4, 4})
+		for i := range 4 {
+			pats[pi].SetFloat1D(pi*8+i*2, 1)
+		}
+	}
+	same := func(a, b []Float) bool {
+		for i := range a {
+			if a[i] != b[i] {
+				return false
+			}
+		}
+		return len(a) == len(b)
+	}
+
+	tn := newTestCacheNet(t)
+	ref := newTestCacheNet(t) // fresh settling on the same sequence
+	ref.tc.On = false
+	if !tn.tc.Valid(tn.net) {
+		t.Fatal("cache should be valid for a default network")
+	}
+	for i, pi := range []int{0, 1, 0, 1, 0} {
+		got := tn.settle(pats[pi])
+		want := ref.settle(pats[pi])
+		if !same(got, want) {
+			t.Errorf("trial %d: cached settle does not equal a fresh settle:\n%v\n%v", i, got, want)
+		}
+	}
+	tc := &tn.tc
+	if tc.Hits != 3 || tc.Misses != 2 {
+		t.Errorf("hits, misses: %d, %d, want 3, 2", tc.Hits, tc.Misses)
+	}
+
+	// a param change must miss the cache
+	net, hid := tn.net, tn.hid
+	hid.Inhib.Layer.Gi = 2.2
+	ref.hid.Inhib.Layer.Gi = 2.2
+	tc.Init(net)
+	misses := tc.Misses
+	got := tn.settle(pats[0])
+	if tc.Misses != misses+1 {
+		t.Errorf("param change should miss the cache: misses %d, want %d", tc.Misses, misses+1)
+	}
+	if want := ref.settle(pats[0]); !same(got, want) {
+		t.Errorf("settle after param change does not equal a fresh settle:\n%v\n%v", got, want)
+	}
+	tc.Init(net)
+	tn.settle(pats[0])
+	if tc.Misses != misses+1 {
+		t.Errorf("unchanged params should hit the cache: misses %d, want %d", tc.Misses, misses+1)
+	}
+
+	// state carried over or random is not valid for caching
+	invalid := map[string]func(on bool){
+		"KNa":         func(on bool) { hid.Act.KNa.On = on },
+		"AHP":         func(on bool) { hid.Act.AHP.On = on },
+		"NoiseInject": func(on bool) { hid.NoiseInject.On = on },
+		"Dropout":     func(on bool) { hid.Dropout.Active = on },
+		"Decay": func(on bool) {
+			hid.Act.Init.Decay = 1
+			if on {
+				hid.Act.Init.Decay = 0.5
+			}
+		},
+	}
+	for nm, set := range invalid {
+		set(true)
+		if tc.Valid(net) {
+			t.Errorf("cache should not be valid with %s", nm)
+		}
+		set(false)
+	}
+	if !tc.Valid(net) {
+		t.Errorf("cache should be valid after resetting params")
+	}
+	hid.Type = CTLayer
+	if tc.Valid(net) {
+		t.Errorf("cache should not be valid with a CT layer")
+	}
+	hid.Type = SuperLayer
+}
@@ -0,0 +1,239 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Parallel computation of the Cycle-level updates is turned on by setting
+// NThreads > 1 with SetNThreads (it is 1, serial, by default): each
+// step of the Cycle is split into tasks that are dynamically distributed
+// across NThreads goroutines, with each goroutine taking the next most
+// expensive remaining task as soon as it finishes its current one, so no
+// manual assignment of layers to threads is needed.  The tasks are:
+//   - SendGDelta: each sending pathway, which only writes to its own
+//     conductance increments, so the synapses of a large layer are split
+//     across its pathways.
+//   - AvgMaxGe, AvgMaxAct: each pool of each layer.
+//   - GFromInc, InhibFromGeAct, ActFromG: ranges of ParallelChunk neurons
+//     within each layer, so large layers are split across threads.
+// Layers that depend on the state of other layers (e.g., the RL, PBWM and
+// Pulvinar layers), and layers with random noise (which must be generated
+// in a consistent order) are computed serially in the neuron-level steps,
+// in their order in the network.  Each neuron and synapse is updated in the
+// same order as in serial computation, so the results are identical.
+
+// ParallelMinNeurons is the minimum number of neurons in a set of layers
+// that are computed in parallel, below which they are computed serially,
+// because the overhead of the goroutines exceeds the benefit.
+var ParallelMinNeurons = 1024
+
+// ParallelChunk is the number of neurons in each range of neurons
+// that is computed as one parallel task in the neuron-level steps.
+var ParallelChunk = 256
+
+// SetNThreads sets the number of parallel threads (goroutines) to use
+// for computing the Cycle-level updates: 0 = runtime.GOMAXPROCS(0),
+// i.e., the number of available processors, and 1 = no parallelism.
+func (nt *Network) SetNThreads(nthr int) {
+	if nthr <= 0 {
+		nthr = runtime.GOMAXPROCS(0)
+	}
+	nt.NThreads = nthr
+}
+
+// parallelSafe returns true if the neurons of the layer can be computed
+// in parallel, with other layers, in all steps of the Cycle, which is true
+// for the standard layer types that only depend on their own state and
+// inputs, if they do not have any random noise.
+func (ly *Layer) parallelSafe() bool {
+	switch ly.Type {
+	case SuperLayer, InputLayer, TargetLayer, CompareLayer, CTLayer:
+	default:
+		return false
+	}
+	return ly.Act.Noise.Type == NoNoise && !ly.NoiseInject.On
+}
+
+// parTask is one task of parallel computation: a sending pathway,
+// a pool, or a range of neurons [St, Ed) of a layer.
+type parTask struct {
+	Layer *Layer
+	Path  *Path
+	St    int
+	Ed    int
+
+	// Cost is the estimated relative computational cost of the task,
+	// for starting the most expensive tasks first.
+	Cost int
+}
+
+// sendGDelta does the SendGDelta step of the Cycle, in parallel across
+// the sending pathways of all layers.
+func (nt *Network) sendGDelta(ctx *Context) {
+	if nt.NThreads <= 1 {
+		for _, ly := range nt.Layers {
+			if !ly.Off {
+				ly.SendGDelta(ctx)
+			}
+		}
+		return
+	}
+	tasks := nt.parTasks[:0]
+	nn := 0
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		nn += len(ly.Neurons)
+		for _, sp := range ly.SendPaths {
+			if sp.Off {
+				continue
+			}
+			tasks = append(tasks, parTask{Layer: ly, Path: sp, Cost: sp.Syns.Len()})
+		}
+	}
+	if nn < ParallelMinNeurons {
+		for _, ly := range nt.Layers {
+			if !ly.Off {
+				ly.SendGDelta(ctx)
+			}
+		}
+		nt.parTasks = tasks[:0]
+		return
+	}
+	nt.runTasks(tasks, func(t *parTask) { t.Layer.sendGDeltaPath(t.Path) })
+	tasks = tasks[:0]
+	for _, ly := range nt.Layers {
+		if !ly.Off {
+			tasks = ly.appendRangeTasks(tasks)
+		}
+	}
+	nt.runTasks(tasks, func(t *parTask) { t.Layer.sendGDeltaActSent(t.St, t.Ed) })
+	nt.parTasks = tasks[:0]
+}
+
+// poolFun calls the given function on all pools of all layers that are
+// not Off, in parallel.  The function must only depend on the state of
+// the neurons in the pool (e.g., AvgMaxGe).
+func (nt *Network) poolFun(fun func(ly *Layer, pi int)) {
+	if nt.NThreads <= 1 {
+		for _, ly := range nt.Layers {
+			if ly.Off {
+				continue
+			}
+			for pi := range ly.Pools {
+				fun(ly, pi)
+			}
+		}
+		return
+	}
+	tasks := nt.parTasks[:0]
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		for pi := range ly.Pools {
+			pl := &ly.Pools[pi]
+			tasks = append(tasks, parTask{Layer: ly, St: pi, Cost: pl.EdIndex - pl.StIndex})
+		}
+	}
+	nt.runTasks(tasks, func(t *parTask) { fun(t.Layer, t.St) })
+	nt.parTasks = tasks[:0]
+}
+
+// neuronFun calls rfun on ranges of neurons of the parallelSafe layers
+// that are not Off, in parallel, with large layers split into ranges of
+// ParallelChunk neurons, after calling pre (if non-nil) on each of these
+// layers for any layer-level computation that rfun depends on.
+// The other layers are computed by calling fun serially in their order
+// in the network, with parallel computation of the safe layers in between.
+// fun must do the same as pre and rfun for the parallelSafe layers,
+// as it is used for all layers when NThreads is 1.
+func (nt *Network) neuronFun(fun, pre func(ly *Layer), rfun func(ly *Layer, st, ed int)) {
+	if nt.NThreads <= 1 {
+		for _, ly := range nt.Layers {
+			if !ly.Off {
+				fun(ly)
+			}
+		}
+		return
+	}
+	tasks := nt.parTasks[:0]
+	for _, ly := range nt.Layers {
+		if ly.Off {
+			continue
+		}
+		if ly.parallelSafe() && !ly.DaInjectActive() {
+			if pre != nil {
+				pre(ly)
+			}
+			tasks = ly.appendRangeTasks(tasks)
+			continue
+		}
+		nt.runTasks(tasks, func(t *parTask) { rfun(t.Layer, t.St, t.Ed) })
+		tasks = tasks[:0]
+		fun(ly)
+	}
+	nt.runTasks(tasks, func(t *parTask) { rfun(t.Layer, t.St, t.Ed) })
+	nt.parTasks = tasks[:0]
+}
+
+// appendRangeTasks appends tasks for the ranges of ParallelChunk
+// neurons in the layer to the given tasks.
+func (ly *Layer) appendRangeTasks(tasks []parTask) []parTask {
+	nn := len(ly.Neurons)
+	chunk := max(ParallelChunk, 1)
+	for st := 0; st < nn; st += chunk {
+		ed := min(st+chunk, nn)
+		tasks = append(tasks, parTask{Layer: ly, St: st, Ed: ed, Cost: ed - st})
+	}
+	return tasks
+}
+
+// runTasks calls the given function on the given tasks in parallel
+// across NThreads goroutines, with the most expensive tasks started first.
+// The tasks are computed serially if their total cost (in neurons
+// or synapses) is below ParallelMinNeurons.
+func (nt *Network) runTasks(tasks []parTask, fun func(t *parTask)) {
+	cost := 0
+	for ti := range tasks {
+		cost += tasks[ti].Cost
+	}
+	nthr := min(nt.NThreads, len(tasks))
+	if nthr <= 1 || cost < ParallelMinNeurons {
+		for ti := range tasks {
+			fun(&tasks[ti])
+		}
+		return
+	}
+	slices.SortStableFunc(tasks, func(a, b parTask) int {
+		return b.Cost - a.Cost
+	})
+	var next atomic.Int32
+	work := func() {
+		for {
+			i := int(next.Add(1)) - 1
+			if i >= len(tasks) {
+				return
+			}
+			fun(&tasks[i])
+		}
+	}
+	var wg sync.WaitGroup
+	wg.Add(nthr - 1)
+	for range nthr - 1 {
+		go func() {
+			work()
+			wg.Done()
+		}()
+	}
+	work()
+	wg.Wait()
+}
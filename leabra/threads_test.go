@@ -0,0 +1,132 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestParallelCycle(t *testing.T) {
+	minN, chunk := ParallelMinNeurons, ParallelChunk
+	ParallelMinNeurons, ParallelChunk = 0, 2
+	defer func() { ParallelMinNeurons, ParallelChunk = minN, chunk }()
+
+	inPats := MakeInPats(t)
+	serNet := MakeTestNet(t)
+	serNet.SetNThreads(1)
+	parNet := MakeTestNet(t)
+	parNet.SetNThreads(4)
+
+	var serActs, parActs []float32
+	for _, net := range []*Network{serNet, parNet} {
+		ctx := NewContext()
+		inLay := net.LayerByName("Input")
+		outLay := net.LayerByName("Output")
+		for pi := 0; pi < 4; pi++ {
+			inpat := inPats.SubSpace([]int{pi})
+			inLay.ApplyExt(inpat)
+			outLay.ApplyExt(inpat)
+			net.AlphaCycInit(true)
+			ctx.AlphaCycStart()
+			for qtr := 0; qtr < 4; qtr++ {
+				for cyc := 0; cyc < ctx.CycPerQtr; cyc++ {
+					net.Cycle(ctx)
+					ctx.CycleInc()
+				}
+				net.QuarterFinal(ctx)
+				ctx.QuarterInc()
+			}
+			net.DWt()
+			net.WtFromDWt()
+		}
+	}
+	for _, lnm := range []string{"Hidden", "Output"} {
+		serNet.LayerByName(lnm).UnitValues(&serActs, "Act", 0)
+		parNet.LayerByName(lnm).UnitValues(&parActs, "Act", 0)
+		CmprFloats(parActs, serActs, "parallel "+lnm+" Act", t)
+	}
+}
+
+// makeHipNet returns the hippocampus network of the hip example,
+// with the given number of threads.
+func makeHipNet(nthr int) *Network {
+	net := NewNetwork("Hip")
+	net.SetRandSeed(1)
+	in := net.AddLayer4D("Input", 6, 2, 3, 4, InputLayer)
+	ecin := net.AddLayer4D("ECin", 6, 2, 3, 4, SuperLayer)
+	ecout := net.AddLayer4D("ECout", 6, 2, 3, 4, TargetLayer)
+	ca1 := net.AddLayer4D("CA1", 6, 2, 4, 10, SuperLayer)
+	dg := net.AddLayer2D("DG", 25, 25, SuperLayer)
+	ca3 := net.AddLayer2D("CA3", 30, 10, SuperLayer)
+	net.AddNoveltyLayer("Novelty", "ECin", "ECout")
+
+	onetoone := paths.NewOneToOne()
+	pool1to1 := paths.NewPoolOneToOne()
+	full := paths.NewFull()
+	ppath := paths.NewUniformRand()
+	ppath.PCon = 0.25
+	mossy := paths.NewUniformRand()
+	mossy.PCon = 0.02
+
+	net.ConnectLayers(in, ecin, onetoone, ForwardPath)
+	net.ConnectLayers(ecout, ecin, onetoone, BackPath)
+	net.ConnectLayers(ecin, ca1, pool1to1, EcCa1Path)
+	net.ConnectLayers(ca1, ecout, pool1to1, EcCa1Path)
+	net.ConnectLayers(ecout, ca1, pool1to1, EcCa1Path)
+	net.ConnectLayers(ecin, dg, ppath, CHLPath)
+	net.ConnectLayers(ecin, ca3, ppath, EcCa1Path)
+	net.ConnectLayers(ca3, ca3, full, EcCa1Path)
+	net.ConnectLayers(dg, ca3, mossy, CHLPath)
+	net.ConnectLayers(ca3, ca1, full, CHLPath)
+
+	net.Build()
+	net.Defaults()
+	net.SetNThreads(nthr)
+	net.InitWeights()
+	pat := make([]float32, len(in.Neurons))
+	for ni := range pat {
+		if ni%3 == 0 {
+			pat[ni] = 1
+		}
+	}
+	in.ApplyExt1D32(pat)
+	return net
+}
+
+func TestParallelHip(t *testing.T) {
+	serNet := makeHipNet(1)
+	parNet := makeHipNet(4)
+	for _, net := range []*Network{serNet, parNet} {
+		ctx := NewContext()
+		for range 2 {
+			net.AlphaCycle(ctx, true)
+		}
+	}
+	var serActs, parActs []float32
+	for _, ly := range serNet.Layers {
+		ly.UnitValues(&serActs, "Act", 0)
+		parNet.LayerByName(ly.Name).UnitValues(&parActs, "Act", 0)
+		CmprFloats(parActs, serActs, "parallel hip "+ly.Name+" Act", t)
+	}
+}
+
+// BenchmarkCycleHip benchmarks the Cycle of the hip example network
+// with different numbers of threads (see examples/bench/bench_results.md).
+func BenchmarkCycleHip(b *testing.B) {
+	for _, nthr := range []int{1, 2, 4} {
+		b.Run(fmt.Sprintf("threads=%d", nthr), func(b *testing.B) {
+			net := makeHipNet(nthr)
+			ctx := NewContext()
+			net.AlphaCycle(ctx, false)
+			b.ResetTimer()
+			for range b.N {
+				net.Cycle(ctx)
+			}
+		})
+	}
+}
@@ -0,0 +1,289 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+	"github.com/emer/emergent/v2/paths"
+)
+
+// GaussTopo is a topographic pathway pattern where the probability of
+// a connection, and optionally the synaptic Scale (see TopoWeights), fall
+// off as a Gaussian function of the distance between the sending unit and
+// the position of the receiving unit mapped into the sending layer, with
+// both layers in normalized 0-1 coordinates, so that layers of different
+// sizes are mapped onto each other, e.g., for the topographic EC -> DG
+// perforant path, or cortical maps.  4D layers are flattened to 2D,
+// as in the NetView display.
+type GaussTopo struct {
+
+	// Gaussian sigma (width) of the fall-off, as a proportion of the
+	// size of the sending layer in each dimension.
+	Sigma float32 `default:"0.2"`
+
+	// probability of connection at distance 0, with the probability
+	// at other distances being PCon times the Gaussian.
+	PCon float32 `default:"1"`
+
+	// maximum distance for a connection, in units of Sigma.
+	Radius float32 `default:"3"`
+
+	// if true, distances wrap around the edges of the layer, as on a torus.
+	Wrap bool
+
+	// if true, set the synaptic Scale values to the Gaussian of the distance
+	// times MaxWt, with a minimum of MinWt, in Network.InitTopoScales.
+	TopoWeights bool
+
+	// maximum Scale value, for TopoWeights.
+	MaxWt float32 `default:"1"`
+
+	// minimum Scale value, for TopoWeights.
+	MinWt float32 `default:"0.1"`
+
+	// if true, and connecting a layer to itself, connect each unit to itself.
+	SelfCon bool
+
+	// random seed for the connections: set from the network RandSeed
+	// in Build if 0 (see Network.SetPathRandSeeds).
+	RandSeed int64 `display:"-"`
+}
+
+func NewGaussTopo() *GaussTopo {
+	gt := &GaussTopo{}
+	gt.Defaults()
+	return gt
+}
+
+func (gt *GaussTopo) Defaults() {
+	gt.Sigma = 0.2
+	gt.PCon = 1
+	gt.Radius = 3
+	gt.MaxWt = 1
+	gt.MinWt = 0.1
+}
+
+func (gt *GaussTopo) Name() string {
+	return "GaussTopo"
+}
+
+// gauss returns the Gaussian of the distance between the given
+// sending and receiving units, and whether they are within Radius.
+func (gt *GaussTopo) gauss(si, ri int, send, recv *tensor.Shape) (float32, bool) {
+	sy, sx := topoPos(send, si)
+	ry, rx := topoPos(recv, ri)
+	dy := topoDist(sy, ry, gt.Wrap) / gt.Sigma
+	dx := topoDist(sx, rx, gt.Wrap) / gt.Sigma
+	d2 := dy*dy + dx*dx
+	return float32(math.Exp(float64(-0.5 * d2))), d2 <= gt.Radius*gt.Radius
+}
+
+func (gt *GaussTopo) Connect(send, recv *tensor.Shape, same bool) (sendn, recvn *tensor.Int32, cons *tensor.Bits) {
+	sendn, recvn, cons = paths.NewTensors(send, recv)
+	rnd := randx.NewSysRand(gt.RandSeed)
+	nsend, nrecv := send.Len(), recv.Len()
+	for ri := range nrecv {
+		for si := range nsend {
+			if same && ri == si && !gt.SelfCon {
+				continue
+			}
+			g, in := gt.gauss(si, ri, send, recv)
+			if !in || (gt.PCon*g < 1 && rnd.Float32() >= gt.PCon*g) {
+				continue
+			}
+			cons.Values.Set(ri*nsend+si, true)
+			recvn.Values[ri]++
+			sendn.Values[si]++
+		}
+	}
+	return
+}
+
+// GaussWts returns the Gaussian topographic Scale value for given unit
+// indexes in given send and recv layers, for Path.SetScalesFunc.
+func (gt *GaussTopo) GaussWts(si, ri int, send, recv *tensor.Shape) float32 {
+	g, _ := gt.gauss(si, ri, send, recv)
+	return max(gt.MinWt, gt.MaxWt*g)
+}
+
+// Ring is a pathway pattern for layers whose units are arranged on a ring,
+// in order of their unit index (e.g., head direction or orientation
+// columns), where each receiving unit connects to the sending units within
+// Radius of its corresponding position on the sending ring, always wrapping
+// around, with optional Gaussian TopoWeights as a function of the distance
+// around the ring.  Layers of different sizes are mapped onto each other.
+type Ring struct {
+
+	// radius of the connections around the ring, as a proportion
+	// of the ring circumference (0.5 = all units).
+	Radius float32 `default:"0.125"`
+
+	// Gaussian sigma (width) of the TopoWeights fall-off, as a proportion
+	// of the ring circumference.
+	Sigma float32 `default:"0.05"`
+
+	// if true, set the synaptic Scale values to the Gaussian of the distance
+	// times MaxWt, with a minimum of MinWt, in Network.InitTopoScales.
+	TopoWeights bool
+
+	// maximum Scale value, for TopoWeights.
+	MaxWt float32 `default:"1"`
+
+	// minimum Scale value, for TopoWeights.
+	MinWt float32 `default:"0.1"`
+
+	// if true, and connecting a layer to itself, connect each unit to itself.
+	SelfCon bool
+}
+
+func NewRing() *Ring {
+	rg := &Ring{}
+	rg.Defaults()
+	return rg
+}
+
+func (rg *Ring) Defaults() {
+	rg.Radius = 0.125
+	rg.Sigma = 0.05
+	rg.MaxWt = 1
+	rg.MinWt = 0.1
+}
+
+func (rg *Ring) Name() string {
+	return "Ring"
+}
+
+// dist returns the distance around the ring between the given
+// sending and receiving units.
+func (rg *Ring) dist(si, ri int, send, recv *tensor.Shape) float32 {
+	sp := (float32(si) + 0.5) / float32(send.Len())
+	rp := (float32(ri) + 0.5) / float32(recv.Len())
+	return topoDist(sp, rp, true)
+}
+
+func (rg *Ring) Connect(send, recv *tensor.Shape, same bool) (sendn, recvn *tensor.Int32, cons *tensor.Bits) {
+	sendn, recvn, cons = paths.NewTensors(send, recv)
+	nsend, nrecv := send.Len(), recv.Len()
+	// tolerance for the float rounding of distances that are exactly Radius
+	const tol = 1.0e-5
+	for ri := range nrecv {
+		for si := range nsend {
+			if same && ri == si && !rg.SelfCon {
+				continue
+			}
+			if rg.dist(si, ri, send, recv) > rg.Radius+tol {
+				continue
+			}
+			cons.Values.Set(ri*nsend+si, true)
+			recvn.Values[ri]++
+			sendn.Values[si]++
+		}
+	}
+	return
+}
+
+// GaussWts returns the Gaussian topographic Scale value for given unit
+// indexes in given send and recv layers, for Path.SetScalesFunc.
+func (rg *Ring) GaussWts(si, ri int, send, recv *tensor.Shape) float32 {
+	d := rg.dist(si, ri, send, recv) / rg.Sigma
+	return max(rg.MinWt, rg.MaxWt*float32(math.Exp(float64(-0.5*d*d))))
+}
+
+// topoPos returns the normalized 0-1 position of the center of the
+// given unit in the 2D projection of the given layer shape.
+func topoPos(shp *tensor.Shape, idx int) (y, x float32) {
+	ny, nx, _, _ := tensor.Projection2DShape(shp, false)
+	var row, col int
+	ix := shp.Index(idx)
+	switch len(ix) {
+	case 1:
+		row, col = 0, ix[0]
+	case 2:
+		row, col = ix[0], ix[1]
+	case 4:
+		row, col = ix[0]*shp.DimSize(2)+ix[2], ix[1]*shp.DimSize(3)+ix[3]
+	default:
+		row, col = idx/nx, idx%nx
+	}
+	return (float32(row) + 0.5) / float32(ny), (float32(col) + 0.5) / float32(nx)
+}
+
+// topoDist returns the distance between normalized 0-1 positions,
+// wrapping around if wrap.
+func topoDist(a, b float32, wrap bool) float32 {
+	d := a - b
+	if d < 0 {
+		d = -d
+	}
+	if wrap && d > 0.5 {
+		d = 1 - d
+	}
+	return d
+}
+
+// PathPatterns are the pathway patterns available by name in
+// ConnectLayersPath, as functions returning a new pattern with
+// default parameters.  Additional patterns can be added with
+// RegisterPathPattern.
+var PathPatterns = map[string]func() paths.Pattern{
+	"Full":            func() paths.Pattern { return paths.NewFull() },
+	"OneToOne":        func() paths.Pattern { return paths.NewOneToOne() },
+	"PoolOneToOne":    func() paths.Pattern { return paths.NewPoolOneToOne() },
+	"PoolSameUnit":    func() paths.Pattern { return paths.NewPoolSameUnit() },
+	"PoolTile":        func() paths.Pattern { return paths.NewPoolTile() },
+	"PoolRect":        func() paths.Pattern { return paths.NewPoolRect() },
+	"PoolUniformRand": func() paths.Pattern { return paths.NewPoolUniformRand() },
+	"UniformRand":     func() paths.Pattern { return paths.NewUniformRand() },
+	"Circle":          func() paths.Pattern { return paths.NewCircle() },
+	"Rect":            func() paths.Pattern { return paths.NewRect() },
+	"GaussTopo":       func() paths.Pattern { return NewGaussTopo() },
+	"GaussTopoWrap":   func() paths.Pattern { gt := NewGaussTopo(); gt.Wrap = true; return gt },
+	"Ring":            func() paths.Pattern { return NewRing() },
+}
+
+// RegisterPathPattern registers a new pathway pattern with the given
+// name, for use in ConnectLayersPath.
+func RegisterPathPattern(name string, fun func() paths.Pattern) {
+	PathPatterns[name] = fun
+}
+
+// NewPathPattern returns a new pathway pattern of the given registered
+// name (see PathPatterns), or an error if it is not registered.
+func NewPathPattern(name string) (paths.Pattern, error) {
+	fun, ok := PathPatterns[name]
+	if !ok {
+		nms := make([]string, 0, len(PathPatterns))
+		for nm := range PathPatterns {
+			nms = append(nms, nm)
+		}
+		slices.Sort(nms)
+		return nil, fmt.Errorf("leabra.NewPathPattern: pattern %q is not registered, must be one of: %v", name, nms)
+	}
+	return fun(), nil
+}
+
+// ConnectLayersPath establishes a pathway between the layers with the
+// given names, using a new pathway pattern of the given registered name
+// (see PathPatterns), which is available as the Pattern of the returned
+// pathway, for setting its parameters prior to Build.
+func (nt *Network) ConnectLayersPath(send, recv, pattern string, typ PathTypes) (*Path, error) {
+	pat, err := NewPathPattern(pattern)
+	if err != nil {
+		return nil, err
+	}
+	_, _, pt, err := nt.ConnectLayerNames(send, recv, pat, typ)
+	if err != nil {
+		return nil, err
+	}
+	if pt == nil {
+		return nil, fmt.Errorf("leabra.ConnectLayersPath: layer not found: %s or %s", send, recv)
+	}
+	return pt, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTopoPaths(t *testing.T) {
+	net := NewNetwork("Topo")
+	in := net.AddLayer2D("Input", 10, 10, InputLayer)
+	hid := net.AddLayer2D("Hidden", 5, 5, SuperLayer)
+	ring := net.AddLayer2D("Ring", 1, 16, SuperLayer)
+	gt := NewGaussTopo()
+	gt.Sigma = 0.15
+	gt.TopoWeights = true
+	gpt := net.ConnectLayers(in, hid, gt, ForwardPath)
+	rpt, err := net.ConnectLayersPath("Ring", "Ring", "Ring", LateralPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rg := rpt.Pattern.(*Ring)
+	rg.TopoWeights = true
+	if _, err := net.ConnectLayersPath("Input", "Hidden", "NoSuch", ForwardPath); err == nil {
+		t.Error("ConnectLayersPath: expected error for unregistered pattern")
+	}
+	if _, err := net.ConnectLayersPath("Input", "NoSuch", "Full", ForwardPath); err == nil {
+		t.Error("ConnectLayersPath: expected error for missing layer")
+	}
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	net.InitTopoScales()
+	if gt.RandSeed == 0 {
+		t.Error("GaussTopo RandSeed not set in Build")
+	}
+
+	// hidden unit 12 is in the center: the probability of connection
+	// and the weight scales fall off with distance.
+	ri := 12
+	st, nc := int(gpt.RConIndexSt[ri]), int(gpt.RConN[ri])
+	dist := func(si int) float64 {
+		sy, sx := topoPos(&in.Shape, si)
+		return math.Hypot(float64(sy-0.5), float64(sx-0.5))
+	}
+	var nNear, nFar int
+	for si := range 100 {
+		if d := dist(si); d < 0.15 {
+			nNear++
+		} else if d > 0.3 && d < 0.45 {
+			nFar++
+		}
+	}
+	near, far := 0, 0
+	for ci := range nc {
+		si := int(gpt.RConIndex[st+ci])
+		d := dist(si)
+		sc := gpt.Syns.Scale[gpt.RSynIndex[st+ci]]
+		if d > 3*0.15+1e-6 {
+			t.Errorf("connection beyond Radius: %g", d)
+		}
+		if d < 0.15 {
+			near++
+			if sc < 0.6 {
+				t.Errorf("near scale: %g at %g", sc, d)
+			}
+		} else if d > 0.3 {
+			far++
+			if sc > 0.14 {
+				t.Errorf("far scale: %g at %g", sc, d)
+			}
+		}
+	}
+	if pn, pf := float64(near)/float64(nNear), float64(far)/float64(nFar); pn < 0.6 || pf > 0.3 {
+		t.Errorf("proportion connected near: %g, far: %g", pn, pf)
+	}
+
+	// ring: radius 0.125 of 16 units = 2 on each side, wrapping around.
+	for ri := range 16 {
+		if n := ring.RecvPaths[0].RConN[ri]; n != 4 {
+			t.Errorf("ring unit %d: %d connections, not 4", ri, n)
+		}
+	}
+	st = int(rpt.RConIndexSt[0])
+	var sis []int
+	for ci := range 4 {
+		sis = append(sis, int(rpt.RConIndex[st+ci]))
+	}
+	slices.Sort(sis)
+	if !slices.Equal(sis, []int{1, 2, 14, 15}) {
+		t.Errorf("ring unit 0 senders: %v", sis)
+	}
+	if s1, s2 := rpt.Syns.Scale[rpt.RSynIndex[st]], rpt.Syns.Scale[rpt.RSynIndex[st+1]]; s1 <= s2 {
+		t.Errorf("ring scales should fall off with distance: %g, %g", s1, s2)
+	}
+}
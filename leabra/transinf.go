@@ -0,0 +1,387 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/stats/metric"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+// TransInf generates the training and testing pattern tables for the
+// transitive inference paradigm, for relational integration on the
+// hippocampus model.  Each list is a hierarchy of NItems items, e.g.,
+// A > B > C > D > E > F, where each item wins over all the items after
+// it.  The training table has the premise pairs of adjacent items
+// (A-B, B-C, ...), in both orders, and the testing table has all the
+// pairs, including the inference probes between non-adjacent items
+// (e.g., B-D), whose relation must be inferred from the premises.
+// Each pattern has the left and right items of the pair in ItemPools
+// pools each of the EC layout, followed by the winning item in the next
+// ItemPools pools, and the context of the list in the remaining pools.
+// The test Input has the winner pools empty, to be recalled in ECout.
+// The rows are named <list>_<left>_<right>, so the symbolic distance
+// of each pair can be recovered from the trial logs (see Dist and
+// SymbolicDistance).
+type TransInf struct {
+
+	// use transitive inference patterns
+	On bool
+
+	// number of items in the hierarchy of each list
+	NItems int `default:"6" min:"3"`
+
+	// number of pools of the EC layout for each item
+	ItemPools int `default:"2" min:"1"`
+
+	// proportion of active units in each item pool
+	PctAct float32 `default:"0.25" min:"0" max:"1"`
+
+	// minimum proportion of different active units between the patterns of the items
+	MinDiff float32 `default:"0.3" min:"0" max:"1"`
+
+	// proportion of the active units of the context prototype flipped for each pair
+	CtxtFlip float32 `default:"0.2" min:"0" max:"1"`
+
+	// exclude the pairs with the end items (the first and last in the hierarchy) from the Inner accuracy of SymbolicDistance, as they can be solved without inference, from the end items always winning or losing
+	ExclEnds bool `default:"true"`
+
+	// items of each list in hierarchy order, recorded by AddVocab
+	lists map[string][]string
+}
+
+func (ti *TransInf) Update() {
+}
+
+func (ti *TransInf) Defaults() {
+	ti.NItems = 6
+	ti.ItemPools = 2
+	ti.PctAct = 0.25
+	ti.MinDiff = 0.3
+	ti.CtxtFlip = 0.2
+	ti.ExclEnds = true
+}
+
+func (ti *TransInf) ShouldDisplay(field string) bool {
+	switch field {
+	case "On":
+		return true
+	default:
+		return ti.On
+	}
+}
+
+// Items returns NItems item names from the given letter onward,
+// e.g., A, B, C, D, E, F for 'A'.
+func (ti *TransInf) Items(first rune) []string {
+	items := make([]string, ti.NItems)
+	for i := range items {
+		items[i] = string(first + rune(i))
+	}
+	return items
+}
+
+// NPairs returns the number of ordered pairs of items in the test table.
+func (ti *TransInf) NPairs() int {
+	return ti.NItems * (ti.NItems - 1)
+}
+
+// AddVocab adds the patterns of the given items of a list, in hierarchy
+// order (highest first), to the vocabulary, with the given pool shape,
+// as <list>Item<pool> entries with one row per item, along with an
+// "empty" entry.  The patterns are added even if the MinDiff constraint
+// cannot be met, in which case the errors are returned.
+func (ti *TransInf) AddVocab(voc patgen.Vocab, list string, items []string, poolY, poolX int) error {
+	if len(items) != ti.NItems {
+		return fmt.Errorf("leabra.TransInf: list %s has %d items, not NItems = %d", list, len(items), ti.NItems)
+	}
+	if _, ok := voc["empty"]; !ok {
+		if _, err := patgen.AddVocabEmpty(voc, "empty", 1, poolY, poolX); err != nil {
+			return err
+		}
+	}
+	var errs []error
+	for pi := range ti.ItemPools {
+		nm := fmt.Sprintf("%sItem%d", list, pi)
+		if _, err := patgen.AddVocabPermutedBinary(voc, nm, len(items), poolY, poolX, ti.PctAct, ti.MinDiff); err != nil {
+			errs = append(errs, fmt.Errorf("leabra.TransInf: %s: %w", nm, err))
+		}
+	}
+	if ti.lists == nil {
+		ti.lists = make(map[string][]string)
+	}
+	ti.lists[list] = items
+	return errors.Join(errs...)
+}
+
+// AddCtxtVocab adds the context patterns for a list to the vocabulary,
+// for the given number of pools with the given shape, as <ctxt><pool>
+// entries, each of which has a random prototype with CtxtFlip of its
+// active units flipped independently for each of the NPairs rows.
+func (ti *TransInf) AddCtxtVocab(voc patgen.Vocab, ctxt string, nPools, poolY, poolX int) error {
+	if nPools <= 0 {
+		return nil
+	}
+	proto := ctxt + "Proto"
+	_, err := patgen.AddVocabPermutedBinary(voc, proto, nPools, poolY, poolX, ti.PctAct, ti.MinDiff)
+	flip := patgen.NFromPct(ti.CtxtFlip, patgen.NFromPct(ti.PctAct, poolY*poolX))
+	for pi := range nPools {
+		tsr, rerr := patgen.AddVocabRepeat(voc, fmt.Sprintf("%s%d", ctxt, pi), ti.NPairs(), proto, pi)
+		if rerr != nil {
+			return rerr
+		}
+		patgen.FlipBitsRows(tsr, flip, flip, 1, 0)
+	}
+	return err
+}
+
+// Tables configures the given training and testing tables for the given
+// list, with the given context, from the vocabulary (see AddVocab and
+// AddCtxtVocab), with Input and ECout columns of the given 4D shape
+// (pools Y, X, units Y, X).  The training table has the premise pairs,
+// and the testing table has all the ordered pairs, with a Dist column
+// with their symbolic distance.  If train or test is nil, that table
+// is not configured.
+func (ti *TransInf) Tables(voc patgen.Vocab, list, ctxt string, shape []int, train, test *table.Table) error {
+	items, ok := ti.lists[list]
+	if !ok {
+		return fmt.Errorf("leabra.TransInf: list %s is not in the vocabulary", list)
+	}
+	npools := shape[0] * shape[1]
+	nctxt := npools - 3*ti.ItemPools
+	if nctxt < 0 {
+		return fmt.Errorf("leabra.TransInf: 3 items of %d pools do not fit in the %d pools", ti.ItemPools, npools)
+	}
+	var prems, pairs [][2]int
+	for i := range ti.NItems {
+		for j := range ti.NItems {
+			if i == j {
+				continue
+			}
+			pairs = append(pairs, [2]int{i, j})
+			if j == i+1 || i == j+1 {
+				prems = append(prems, [2]int{i, j})
+			}
+		}
+	}
+	fill := func(dt *table.Table, pairs [][2]int, cue bool) error {
+		patgen.InitPats(dt, dt.MetaData["name"], dt.MetaData["desc"], "Input", "ECout", len(pairs), shape[0], shape[1], shape[2], shape[3])
+		for row, pr := range pairs {
+			dt.SetString("Name", row, fmt.Sprintf("%s_%s_%s", list, items[pr[0]], items[pr[1]]))
+			for _, col := range []string{"Input", "ECout"} {
+				noWin := col == "Input" && cue
+				if err := ti.setRow(voc, dt.Tensor(col, row).(*tensor.Float32), list, ctxt, nctxt, pr, row, noWin); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if train != nil {
+		if err := fill(train, prems, false); err != nil {
+			return err
+		}
+	}
+	if test == nil {
+		return nil
+	}
+	if err := fill(test, pairs, true); err != nil {
+		return err
+	}
+	test.AddIntColumn("Dist")
+	test.SetNumRows(len(pairs))
+	for row, pr := range pairs {
+		test.SetFloat("Dist", row, float64(max(pr[0]-pr[1], pr[1]-pr[0])))
+	}
+	return nil
+}
+
+// setRow sets the pools of the given row pattern for the given pair of
+// item indexes, with the winner pools empty if noWin.
+func (ti *TransInf) setRow(voc patgen.Vocab, pat *tensor.Float32, list, ctxt string, nctxt int, pr [2]int, row int, noWin bool) error {
+	win := min(pr[0], pr[1])
+	np := pat.DimSize(2) * pat.DimSize(3)
+	set := func(pool int, name string, vrow int) error {
+		src, err := voc.ByName(name)
+		if err != nil {
+			return err
+		}
+		copy(pat.Values[pool*np:(pool+1)*np], src.Values[vrow*np:(vrow+1)*np])
+		return nil
+	}
+	for pi := range ti.ItemPools {
+		nm := fmt.Sprintf("%sItem%d", list, pi)
+		if err := set(pi, nm, pr[0]); err != nil {
+			return err
+		}
+		if err := set(ti.ItemPools+pi, nm, pr[1]); err != nil {
+			return err
+		}
+		var err error
+		if noWin {
+			err = set(2*ti.ItemPools+pi, "empty", 0)
+		} else {
+			err = set(2*ti.ItemPools+pi, nm, win)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for pi := range nctxt {
+		if err := set(3*ti.ItemPools+pi, fmt.Sprintf("%s%d", ctxt, pi), row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pair returns the list and the indexes in its hierarchy of the left
+// and right items of the given trial name (<list>_<left>_<right>),
+// with ok = false if it is not a pair of a list in the vocabulary.
+func (ti *TransInf) Pair(name string) (list string, left, right int, ok bool) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 3 {
+		return
+	}
+	list = parts[0]
+	items := ti.lists[list]
+	left = slices.Index(items, parts[1])
+	right = slices.Index(items, parts[2])
+	ok = left >= 0 && right >= 0
+	return
+}
+
+// Dist returns the symbolic distance between the items of the given
+// trial name, which is 1 for the premise pairs, or 0 if it is not a pair.
+func (ti *TransInf) Dist(name string) int {
+	_, left, right, ok := ti.Pair(name)
+	if !ok {
+		return 0
+	}
+	return max(left-right, right-left)
+}
+
+// IsEnd returns true if the given trial name is a pair with one of the
+// end items of the hierarchy, which always win or lose.
+func (ti *TransInf) IsEnd(name string) bool {
+	_, left, right, ok := ti.Pair(name)
+	last := ti.NItems - 1
+	return ok && (left == 0 || right == 0 || left == last || right == last)
+}
+
+// Correct returns 1 if the recalled (binarized) activity of the winner
+// pools for the given test trial is more similar to the pattern of the
+// winning item of the pair than to the losing one, in the left and
+// right pools of the target, else 0 (ties count as 0.5).
+// poolUnits is the number of units per pool.
+func (ti *TransInf) Correct(name string, act, trg []float32, poolUnits int) float32 {
+	_, left, right, ok := ti.Pair(name)
+	nu := ti.ItemPools * poolUnits
+	if !ok || 3*nu > len(act) || 3*nu > len(trg) {
+		return 0
+	}
+	rec := act[2*nu : 3*nu]
+	lc := metric.Cosine32(rec, trg[:nu])
+	rc := metric.Cosine32(rec, trg[nu:2*nu])
+	switch {
+	case lc == rc:
+		return 0.5
+	case (lc > rc) == (left < right):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// SymbolicDistance computes the symbolic distance effect from a log of
+// test trials, with trial names in nameCol and the Correct score of each
+// trial in corCol, returning a table with a row for each distance:
+// Dist, N (number of trials), Correct (mean correct), and NInner and
+// Inner (the same for the inner pairs, without the end items if
+// ExclEnds).  Rows with names that are not pairs, or with a NaN score,
+// are skipped.  See DistSlope for the size of the effect.
+func (ti *TransInf) SymbolicDistance(dt *table.Table, nameCol, corCol string) (*table.Table, error) {
+	nms, err := dt.ColumnByName(nameCol)
+	if err != nil {
+		return nil, err
+	}
+	cors, err := dt.ColumnByName(corCol)
+	if err != nil {
+		return nil, err
+	}
+	sd := table.NewTable()
+	sd.SetMetaData("name", "SymbolicDistance")
+	sd.AddIntColumn("Dist")
+	sd.AddIntColumn("N")
+	sd.AddFloat64Column("Correct")
+	sd.AddIntColumn("NInner")
+	sd.AddFloat64Column("Inner")
+	sd.SetNumRows(ti.NItems - 1)
+	sum := make([]float64, ti.NItems)
+	isum := make([]float64, ti.NItems)
+	for row := range dt.Rows {
+		nm := nms.String1D(row)
+		d := ti.Dist(nm)
+		cor := cors.Float1D(row)
+		if d == 0 || d >= ti.NItems || cor != cor { // NaN
+			continue
+		}
+		r := d - 1
+		sd.SetFloat("N", r, sd.Float("N", r)+1)
+		sum[d] += cor
+		if ti.ExclEnds && ti.IsEnd(nm) {
+			continue
+		}
+		sd.SetFloat("NInner", r, sd.Float("NInner", r)+1)
+		isum[d] += cor
+	}
+	for r := range sd.Rows {
+		d := r + 1
+		sd.SetFloat("Dist", r, float64(d))
+		if n := sd.Float("N", r); n > 0 {
+			sd.SetFloat("Correct", r, sum[d]/n)
+		}
+		if n := sd.Float("NInner", r); n > 0 {
+			sd.SetFloat("Inner", r, isum[d]/n)
+		}
+	}
+	return sd, nil
+}
+
+// DistSlope returns the least-squares slope of the given accuracy column
+// (e.g., Inner) of a SymbolicDistance table as a function of distance,
+// over the inference probes (distance 2 and up) with any trials: a
+// positive slope is the symbolic distance effect, where more distant
+// pairs are more accurate.
+func DistSlope(sd *table.Table, col string) float64 {
+	ncol := "N"
+	if col == "Inner" {
+		ncol = "NInner"
+	}
+	var n, sx, sy, sxx, sxy float64
+	for r := range sd.Rows {
+		x := sd.Float("Dist", r)
+		if x < 2 || sd.Float(ncol, r) == 0 {
+			continue
+		}
+		y := sd.Float(col, r)
+		n++
+		sx += x
+		sy += y
+		sxx += x * x
+		sxy += x * y
+	}
+	den := n*sxx - sx*sx
+	if n < 2 || den == 0 {
+		return 0
+	}
+	return (n*sxy - sx*sy) / den
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/emergent/v2/patgen"
+)
+
+func TestTransInf(t *testing.T) {
+	patgen.NewRand(1)
+	ti := &TransInf{}
+	ti.Defaults()
+	ti.NItems = 5
+	shape := []int{4, 2, 3, 4}
+	voc := patgen.Vocab{}
+	items := ti.Items('A')
+	if err := ti.AddVocab(voc, "ab", items, shape[2], shape[3]); err != nil {
+		t.Fatal(err)
+	}
+	if err := ti.AddCtxtVocab(voc, "ctxt", 2, shape[2], shape[3]); err != nil {
+		t.Fatal(err)
+	}
+	train, test := table.NewTable(), table.NewTable()
+	if err := ti.Tables(voc, "ab", "ctxt", shape, train, test); err != nil {
+		t.Fatal(err)
+	}
+	if train.Rows != 8 || test.Rows != 20 {
+		t.Fatalf("rows: got %d train, %d test, want 8, 20", train.Rows, test.Rows)
+	}
+	if nm := train.StringValue("Name", 0); nm != "ab_A_B" || ti.Dist(nm) != 1 {
+		t.Errorf("first premise: %s", nm)
+	}
+	np := shape[2] * shape[3]
+	nu := ti.ItemPools * np
+	for row := range test.Rows {
+		nm := test.StringValue("Name", row)
+		if int(test.Float("Dist", row)) != ti.Dist(nm) {
+			t.Errorf("Dist of %s: %g vs. %d", nm, test.Float("Dist", row), ti.Dist(nm))
+		}
+		in := test.Tensor("Input", row).(*tensor.Float32).Values
+		out := test.Tensor("ECout", row).(*tensor.Float32).Values
+		for i := 2 * nu; i < 3*nu; i++ {
+			if in[i] != 0 {
+				t.Fatalf("winner pools of test Input should be empty: %s", nm)
+			}
+		}
+		// the winner in ECout is recalled as the correct choice
+		if cor := ti.Correct(nm, out, out, np); cor != 1 {
+			t.Errorf("Correct for the target of %s: %g", nm, cor)
+		}
+		// the loser is recalled as the wrong choice
+		_, left, right, _ := ti.Pair(nm)
+		lose := slices.Clone(out)
+		lst := 0
+		if left < right {
+			lst = nu
+		}
+		copy(lose[2*nu:3*nu], out[lst:lst+nu])
+		if cor := ti.Correct(nm, lose, out, np); cor != 0 {
+			t.Errorf("Correct for the loser of %s: %g", nm, cor)
+		}
+	}
+
+	// log of the test trials, correct for distances > 1 only
+	log := table.NewTable()
+	log.AddStringColumn("TrialName")
+	log.AddFloat64Column("Correct")
+	log.SetNumRows(test.Rows)
+	for row := range test.Rows {
+		nm := test.StringValue("Name", row)
+		log.SetString("TrialName", row, nm)
+		log.SetFloat("Correct", row, float64(min(ti.Dist(nm)-1, 1)))
+	}
+	sd, err := ti.SymbolicDistance(log, "TrialName", "Correct")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sd.Rows != 4 || sd.Float("N", 0) != 8 || sd.Float("Correct", 0) != 0 || sd.Float("Correct", 1) != 1 {
+		t.Errorf("SymbolicDistance table:\n%v", sd)
+	}
+	// inner items B, C, D: B-C, C-D at distance 1, B-D at distance 2
+	if sd.Float("NInner", 0) != 4 || sd.Float("NInner", 1) != 2 || sd.Float("NInner", 2) != 0 {
+		t.Errorf("SymbolicDistance inner counts:\n%v", sd)
+	}
+	sd.SetFloat("Correct", 1, 0.5)
+	sd.SetFloat("Correct", 2, 0.75)
+	sd.SetFloat("Correct", 3, 1)
+	if sl := DistSlope(sd, "Correct"); math.Abs(sl-0.25) > 1e-6 {
+		t.Errorf("DistSlope: got %g, want 0.25", sl)
+	}
+}
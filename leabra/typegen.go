@@ -6,7 +6,9 @@ import (
 	"cogentcore.org/core/types"
 )
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ActParams", IDName: "act-params", Doc: "leabra.ActParams contains all the activation computation params and functions\nfor basic Leabra, at the neuron level .\nThis is included in leabra.Layer to drive the computation.", Fields: []types.Field{{Name: "XX1", Doc: "Noisy X/X+1 rate code activation function parameters"}, {Name: "OptThresh", Doc: "optimization thresholds for faster processing"}, {Name: "Init", Doc: "initial values for key network state variables -- initialized at start of trial with InitActs or DecayActs"}, {Name: "Dt", Doc: "time and rate constants for temporal derivatives / updating of activation state"}, {Name: "Gbar", Doc: "maximal conductances levels for channels"}, {Name: "Erev", Doc: "reversal potentials for each channel"}, {Name: "Clamp", Doc: "how external inputs drive neural activations"}, {Name: "Noise", Doc: "how, where, when, and how much noise to add to activations"}, {Name: "VmRange", Doc: "range for Vm membrane potential -- by default"}, {Name: "KNa", Doc: "sodium-gated potassium channel adaptation parameters -- activates an inhibitory leak-like current as a function of neural activity (firing = Na influx) at three different time-scales (M-type = fast, Slick = medium, Slack = slow)"}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.AblationSweep", IDName: "ablation-sweep", Doc: "AblationSweep systematically repeats a test battery with each layer\nand each pathway of the network ablated in turn, using the lesion API\n(LesionUnits and LesionSyns), to determine the contribution of each\nto the test stats, i.e., \"what breaks when X is off\".\nEach ablation is reversed before the next one, so the network is left\nin its original state.", Fields: []types.Field{{Name: "Layers", Doc: "Layers are the names of the layers to ablate.\nIf empty, all layers that are not Off are ablated."}, {Name: "Paths", Doc: "Paths are the names of the pathways to ablate.\nIf empty, all pathways that are not Off are ablated."}, {Name: "NoLayers", Doc: "NoLayers skips the ablation of layers."}, {Name: "NoPaths", Doc: "NoPaths skips the ablation of pathways."}, {Name: "Stats", Doc: "Stats are the names of the stats returned by the test function,\nin order, set from the stats of the intact network if empty."}, {Name: "Results", Doc: "Results is the contribution table, with one row for the intact\nnetwork followed by one row per ablation, with Ablation (name of\nthe layer or pathway, or Intact) and Kind (Layer, Path or Intact)\ncolumns, a column for each stat, and a <stat>_Diff column with the\ndifference from the intact network."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ActParams", IDName: "act-params", Doc: "leabra.ActParams contains all the activation computation params and functions\nfor basic Leabra, at the neuron level .\nThis is included in leabra.Layer to drive the computation.", Fields: []types.Field{{Name: "XX1", Doc: "Noisy X/X+1 rate code activation function parameters"}, {Name: "OptThresh", Doc: "optimization thresholds for faster processing"}, {Name: "Init", Doc: "initial values for key network state variables -- initialized at start of trial with InitActs or DecayActs"}, {Name: "Dt", Doc: "time and rate constants for temporal derivatives / updating of activation state"}, {Name: "Gbar", Doc: "maximal conductances levels for channels"}, {Name: "Erev", Doc: "reversal potentials for each channel"}, {Name: "Clamp", Doc: "how external inputs drive neural activations"}, {Name: "ClampSched", Doc: "curriculum over training epochs from hard to increasingly weak soft clamping, typically for the plus phase targets of a TargetLayer"}, {Name: "Noise", Doc: "how, where, when, and how much noise to add to activations"}, {Name: "VmRange", Doc: "range for Vm membrane potential -- by default"}, {Name: "KNa", Doc: "sodium-gated potassium channel adaptation parameters -- activates an inhibitory leak-like current as a function of neural activity (firing = Na influx) at three different time-scales (M-type = fast, Slick = medium, Slack = slow)"}, {Name: "AHP", Doc: "slow after-hyperpolarization (sAHP) adaptation parameters -- activates a slowly decaying potassium current as a function of neural activity, which persists across trials, producing firing-rate adaptation with repeated presentations"}, {Name: "ErevSubThr", Doc: "Erev - Act.Thr for each channel -- used in computing GeThrFromG among others"}, {Name: "ThrSubErev", Doc: "Act.Thr - Erev for each channel -- used in computing GeThrFromG among others"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.OptThreshParams", IDName: "opt-thresh-params", Doc: "OptThreshParams provides optimization thresholds for faster processing", Fields: []types.Field{{Name: "Send", Doc: "don't send activation when act <= send -- greatly speeds processing"}, {Name: "Delta", Doc: "don't send activation changes until they exceed this threshold: only for when LeabraNetwork::send_delta is on!"}}})
 
@@ -20,15 +22,55 @@ var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ActNoi
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ClampParams", IDName: "clamp-params", Doc: "ClampParams are for specifying how external inputs are clamped onto network activation values", Fields: []types.Field{{Name: "Hard", Doc: "whether to hard clamp inputs where activation is directly set to external input value (Act = Ext) or do soft clamping where Ext is added into Ge excitatory current (Ge += Gain * Ext)"}, {Name: "Range", Doc: "range of external input activation values allowed -- Max is .95 by default due to saturating nature of rate code activation function"}, {Name: "Gain", Doc: "soft clamp gain factor (Ge += Gain * Ext)"}, {Name: "Avg", Doc: "compute soft clamp as the average of current and target netins, not the sum -- prevents some of the main effect problems associated with adding external inputs"}, {Name: "AvgGain", Doc: "gain factor for averaging the Ge -- clamp value Ext contributes with AvgGain and current Ge as (1-AvgGain)"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtInitParams", IDName: "wt-init-params", Doc: "WtInitParams are weight initialization parameters -- basically the\nrandom distribution parameters but also Symmetry flag", Embeds: []types.Field{{Name: "RandParams"}}, Fields: []types.Field{{Name: "Sym", Doc: "symmetrize the weight values with those in reciprocal pathway -- typically true for bidirectional excitatory connections"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtInitParams", IDName: "wt-init-params", Doc: "WtInitParams are weight initialization parameters -- basically the\nrandom distribution parameters but also Symmetry flag, and the\nweight initialization Scheme (see WtInitSchemes).", Embeds: []types.Field{{Name: "RandParams"}}, Fields: []types.Field{{Name: "Sym", Doc: "symmetrize the weight values with those in reciprocal pathway -- typically true for bidirectional excitatory connections"}, {Name: "Scheme", Doc: "name of the weight initialization scheme, in WtInitSchemes: Rand (default if empty), LogNormal, Sparse, Orthogonal, or any registered with RegisterWtInit"}, {Name: "SparseP", Doc: "for the Sparse scheme, the proportion of synapses with non-zero weights"}, {Name: "SparseTopo", Doc: "for the Sparse scheme, multiply SparseP by the synapse Scale, e.g., from the topographic weights of the pathway pattern (see Network.InitTopoScales)"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtScaleParams", IDName: "wt-scale-params", Doc: "/ WtScaleParams are weight scaling parameters: modulates overall strength of pathway,\nusing both absolute and relative factors", Fields: []types.Field{{Name: "Abs", Doc: "absolute scaling, which is not subject to normalization: directly multiplies weight values"}, {Name: "Rel", Doc: "relative scaling that shifts balance between different pathways -- this is subject to normalization across all other pathways into unit"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Context", IDName: "context", Doc: "leabra.Context contains all the timing state and parameter information for running a model", Fields: []types.Field{{Name: "Time", Doc: "accumulated amount of time the network has been running,\nin simulation-time (not real world time), in seconds."}, {Name: "Cycle", Doc: "cycle counter: number of iterations of activation updating\n(settling) on the current alpha-cycle (100 msec / 10 Hz) trial.\nThis counts time sequentially through the entire trial,\ntypically from 0 to 99 cycles."}, {Name: "CycleTot", Doc: "total cycle count. this increments continuously from whenever\nit was last reset, typically this is number of milliseconds\nin simulation time."}, {Name: "Quarter", Doc: "current gamma-frequency (25 msec / 40 Hz) quarter of alpha-cycle\n(100 msec / 10 Hz) trial being processed.\nDue to 0-based indexing, the first quarter is 0, second is 1, etc.\nThe plus phase final quarter is 3."}, {Name: "PlusPhase", Doc: "true if this is the plus phase (final quarter = 3), else minus phase."}, {Name: "TimePerCyc", Doc: "amount of time to increment per cycle."}, {Name: "CycPerQtr", Doc: "number of cycles per quarter to run: 25 = standard 100 msec alpha-cycle."}, {Name: "Mode", Doc: "current evaluation mode, e.g., Train, Test, etc"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.AHPParams", IDName: "ahp-params", Doc: "AHPParams are the parameters for a slow after-hyperpolarization (sAHP)\npotassium conductance, which builds up with the activity of the neuron\nand decays slowly, over many trials, producing firing-rate adaptation\n(accommodation) with repeated presentations of the same input, e.g.,\nfor repetition suppression in recognition memory and priming.\nThe Gahp neuron variable is the proportion of open channels, and\nGbar * Gahp is added to the Gk potassium conductance, along with any\nKNa adaptation.  Unlike the rest of the activation state, Gahp is not\ndecayed by Init.Decay at the start of each trial, so adaptation carries\nover from one trial to the next: instead, the ITI sets the number of\ncycles of decay for an inter-trial interval, and Network.DecayAHP\ncan be called for longer delays.  It is reset by InitActs.", Fields: []types.Field{{Name: "On", Doc: "use the slow AHP adaptation conductance"}, {Name: "Gbar", Doc: "maximal conductance of the sAHP channels, added to Gk (which is multiplied by Gbar.K), with Gahp being the proportion of open channels"}, {Name: "Rise", Doc: "rate of opening of the sAHP channels per cycle as a function of the rate-code activation (or 1 for a spike in spiking neurons): Gahp += Rise * Act * (1 - Gahp)"}, {Name: "Tau", Doc: "time constant in cycles (msec) for the closing of the sAHP channels -- values of seconds produce adaptation that lasts across many trials"}, {Name: "ITI", Doc: "number of cycles of decay of Gahp at the start of each trial (in AlphaCycInit), for an inter-trial interval between presentations, in addition to the decay during the trial"}, {Name: "Dt", Doc: "rate = 1 / tau"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ArrayView", IDName: "array-view", Doc: "ArrayView describes a contiguous array of values in Go memory, for\naccess without copying from other languages through the Python\nbindings, e.g., as a NumPy array view of the layer unit values\n(Layer.UnitValuesView, with one copy into the view buffer) or of the\nsynapse values (Path.SynValuesView, with no copy):\n\n\timport ctypes, numpy as np\n\tdef as_numpy(v):\n\t    buf = (ctypes.c_char * (v.Len * v.Size)).from_address(v.Addr)\n\t    return np.frombuffer(buf, dtype=v.DType).reshape(tuple(v.Shape))\n\nThe NumPy array is only valid while the view and the network exist,\nand for synapses, until the network is rebuilt.  Updating the view\nagain (e.g., after each trial) updates the values in place, at the\nsame address, so the NumPy array does not need to be re-created.", Fields: []types.Field{{Name: "Addr", Doc: "Addr is the memory address of the first value."}, {Name: "Len", Doc: "Len is the number of values."}, {Name: "Size", Doc: "Size is the number of bytes per value: 4 or 8."}, {Name: "DType", Doc: "DType is the NumPy dtype of the values: float32 or float64."}, {Name: "Shape", Doc: "Shape is the shape of the values, e.g., the layer shape."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BanditParams", IDName: "bandit-params", Doc: "BanditParams are the parameters for an n-armed bandit task\n(see BanditEnv), where each arm is rewarded with its own probability,\nwhich can be stationary, drift over time, or be reversed at regular\nintervals, as in probabilistic reversal learning.", Fields: []types.Field{{Name: "NArms", Doc: "NArms is the number of arms."}, {Name: "Probs", Doc: "Probs are the initial reward probabilities of the arms.\nIf empty, they are evenly spaced between 0 and 1 (exclusive),\nin a new random order for each run."}, {Name: "Drift", Doc: "Drift is the standard deviation of the Gaussian random walk of\nthe reward probability of each arm after each pull, clipped to\nthe 0-1 range.  0 = stationary probabilities."}, {Name: "ReverseInterval", Doc: "ReverseInterval is the number of pulls after which the reward\nprobabilities are reversed (see BanditEnv.Reverse), for\nprobabilistic reversal learning.  0 = no reversals."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BanditEnv", IDName: "bandit-env", Doc: "BanditEnv is an n-armed bandit GymStepper, to be wrapped by GymEnv:\neach episode is a single step, in which the action pulls one of the\narms, which is rewarded with the current probability for that arm.\nThe observation is a single constant unit.  The probabilities are\nset by Config for each run, and then change according to the Drift\nand ReverseInterval of the Params.", Fields: []types.Field{{Name: "Params", Doc: "Params are the parameters of the task."}, {Name: "Probs", Doc: "Probs are the current reward probabilities of the arms."}, {Name: "Pulls", Doc: "Pulls is the number of pulls since Config."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BreakAggs", IDName: "break-aggs", Doc: "BreakAggs are the ways of aggregating a variable across the units\nor synapses of a layer or pathway, for a Breakpoint."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BreakOps", IDName: "break-ops", Doc: "BreakOps are the comparison operators of a Breakpoint."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Breakpoint", IDName: "breakpoint", Doc: "Breakpoint is a condition on the state of the network, for stopping\nthe training at a given grain with Stepper.AddBreakpoint, e.g., when\nany weight becomes NaN, or the average activity of a layer exceeds\na threshold.  It is specified as a string (see ParseBreakpoint):\n\n\t<Layer or Path or *>.<Var>[.<Agg>] <Op> [<Value>]\n\nwhere Var is a neuron variable (NeuronVars) of the units of a layer,\nor a synapse variable (SynapseVars) of the synapses of a pathway\n(or of all the Recv pathways of a layer), * is all layers, Agg is\nAvg (default), Max, Min or Sum, and Op is >, >=, <, <= or NaN,\ne.g., \"Hidden.Act.Avg > 0.9\", \"DA.Act.Min < -0.5\", \"*.Wt NaN\".", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the layer or pathway, or * for all layers."}, {Name: "Var", Doc: "Var is the neuron or synapse variable."}, {Name: "Agg", Doc: "Agg is the aggregation of the variable across units or synapses."}, {Name: "Op", Doc: "Op is the comparison operator."}, {Name: "Value", Doc: "Value is the threshold for the comparison."}, {Name: "Last", Doc: "Last is the last aggregated value, for reporting."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Checkpoint", IDName: "checkpoint", Doc: "Checkpoint has the state of a simulation run, other than the network\nweights, state and log tables, that is needed to resume the run after an\ninterruption.  It is saved as checkpoint.json in the checkpoint archive\nwritten by SaveCheckpoint, along with the weights, the full state of the\nneurons, pools and synapses (see checkpointNetState), and the logs.", Fields: []types.Field{{Name: "Time", Doc: "time when the checkpoint was saved"}, {Name: "Mode", Doc: "looper stack mode that was running when saved, e.g., Train"}, {Name: "Counters", Doc: "looper counter values for each time scale in the Mode stack,\nat the point where the run is to be resumed"}, {Name: "Envs", Doc: "state of each environment, by env name: the values of all\nenv.Counter fields, the Order permutation, if present,\nand the state of all randx.SysRand random number streams"}, {Name: "Ints", Doc: "estats Ints values"}, {Name: "Floats", Doc: "estats Floats values"}, {Name: "Strings", Doc: "estats Strings values"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ClampSchedParams", IDName: "clamp-sched-params", Doc: "ClampSchedParams are the parameters for a clamping strength curriculum,\ntypically for the plus phase targets of a TargetLayer, which starts\nwith full hard clamping (teacher forcing) for HardEpochs, and then\nswitches to soft clamping, with a clamp Gain that anneals linearly\nfrom Start to Min over the following Epochs, so that the layer\nactivity is increasingly driven by the network itself.\nWhen On, it sets the Act.Clamp Hard and Gain params of the layer\nas a function of the training epoch, which is advanced along with\nthe learning rate schedules by Network.EpochInc or SetLrateEpoch,\nwhich can be called automatically by LooperLrateSched.", Fields: []types.Field{{Name: "On", Doc: "whether to use the clamping schedule, which then determines Act.Clamp.Hard and Gain"}, {Name: "HardEpochs", Doc: "number of epochs of full hard clamping at the start of training, before switching to soft clamping"}, {Name: "Epochs", Doc: "number of epochs over which the soft clamp Gain anneals from Start to Min, after the HardEpochs"}, {Name: "Start", Doc: "soft clamp Gain at the start of soft clamping, after the HardEpochs"}, {Name: "Min", Doc: "soft clamp Gain at the end of the Epochs, which is used from then on"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CLSParams", IDName: "cls-params", Doc: "CLSParams are the params for complementary learning systems (CLS)\ntraining over simulated days, where a fast hippocampal network learns\nthe new items of each day in an awake phase, and a slow neocortical\nnetwork learns from the offline replay of all the items stored in the\nhippocampus so far, interleaved, in the sleep phase at the end of the\nday (see Network.ReplayToCortex).  The consolidation of the items of\neach day is then measured over the following days by ConsolMetrics.", Fields: []types.Field{{Name: "Days", Doc: "Days is the number of simulated days, each with new items."}, {Name: "AwakeEpochs", Doc: "AwakeEpochs is the number of epochs of hippocampal training\non the new items in the awake phase of each day."}, {Name: "Replays", Doc: "Replays is the number of replay trials in the sleep phase\nof each day, each training the cortex."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ConsolMetrics", IDName: "consol-metrics", Doc: "ConsolMetrics records the systems consolidation of memories over the\ndays of a CLS simulation (see CLSParams): at the end of each day, the\nmemory of each set of items (e.g., the items learned on each day) in\nthe hippocampus (HipMem) and in the cortex (CortexMem) is added, e.g.,\nas the mean MemCorrel of their recall, from which it computes:\n  - Age: the number of days since the set was first added.\n  - Consol: the consolidation index, CortexMem / max(HipMem, CortexMem),\n    i.e., the proportion of the memory that no longer depends on the\n    hippocampus (NaN if there is no memory).\n  - Retention: the memory of the system, max(HipMem, CortexMem),\n    relative to that when the set was first added.\n\nPlotting CortexMem by Age on the last day gives the temporal gradient\nof the retrograde amnesia that would follow a hippocampal lesion.", Fields: []types.Field{{Name: "Table", Doc: "Table has a row for each set on each day, with the Day, Set, Age,\nHipMem, CortexMem, Consol and Retention columns."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ConsolSchedule", IDName: "consol-schedule", Doc: "ConsolSchedule is a systems consolidation schedule, for simulations\nwhere new (e.g., AC) items are learned after old (e.g., AB) items:\nit specifies when offline hippocampal replay trials are run\n(see Network.HipReplay), the mix of old and new items that cue the\nreplay, and the proportion of old training items interleaved with the\nnew items during their training, all within a Budget of extra trials\nper run, so that different schedules can be compared (and searched,\ne.g., with the consolopt command of the hip example) for how well they\nprotect the old items from retroactive interference.  The number of\nreplay trials per replay epoch is set separately by the sim.", Fields: []types.Field{{Name: "Start", Doc: "Start is the number of epochs after the switch to the new items\nat which replay starts, or -1 to replay from the start of training."}, {Name: "Every", Doc: "Every is the interval in epochs between replay epochs,\ncounting from the Start."}, {Name: "OldFrac", Doc: "OldFrac is the proportion of replay trials that are cued by old\nitems, with the others cued by new items, when both have been\nstored.  If < 0, the cues are chosen uniformly over all the stored\nitems."}, {Name: "Interleave", Doc: "Interleave is the proportion of the old training items that are\ninterleaved with the new items in each epoch of their training,\nchosen at random in each epoch."}, {Name: "Budget", Doc: "Budget is the maximum total number of extra trials per run,\ncounting both the replay trials and the interleaved old training\ntrials, after which there is no more replay or interleaving.\n0 = no limit."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Context", IDName: "context", Doc: "leabra.Context contains all the timing state and parameter information for running a model", Fields: []types.Field{{Name: "Time", Doc: "accumulated amount of time the network has been running,\nin simulation-time (not real world time), in seconds."}, {Name: "Cycle", Doc: "cycle counter: number of iterations of activation updating\n(settling) on the current alpha-cycle (100 msec / 10 Hz) trial.\nThis counts time sequentially through the entire trial,\ntypically from 0 to 99 cycles."}, {Name: "CycleTot", Doc: "total cycle count. this increments continuously from whenever\nit was last reset, typically this is number of milliseconds\nin simulation time."}, {Name: "AlphaCycTot", Doc: "total number of alpha-cycle trials started since it was last\nreset, incremented by AlphaCycStart, so the current trial is\nAlphaCycTot - 1 (see AlphaCyc).  Used for the burst schedules\nacross trials (see BurstParams)."}, {Name: "Quarter", Doc: "current gamma-frequency (25 msec / 40 Hz) quarter of alpha-cycle\n(100 msec / 10 Hz) trial being processed.\nDue to 0-based indexing, the first quarter is 0, second is 1, etc.\nThe plus phase final quarter is 3."}, {Name: "PlusPhase", Doc: "true if this is the plus phase (final quarter = 3), else minus phase."}, {Name: "TimePerCyc", Doc: "amount of time to increment per cycle."}, {Name: "CycPerQtr", Doc: "number of cycles per quarter to run: 25 = standard 100 msec alpha-cycle."}, {Name: "Mode", Doc: "current evaluation mode, e.g., Train, Test, etc"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Quarters", IDName: "quarters", Doc: "Quarters are the different alpha trial quarters, as a bitflag,\nfor use in relevant timing parameters where quarters need to be specified.\nThe Q1..4 defined values are integer *bit positions* -- use Set, Has etc methods\nto set bits from these bit positions."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BurstParams", IDName: "burst-params", Doc: "BurstParams determine how the 5IB Burst activation is computed from\nstandard Act activation values in SuperLayer. It is thresholded.", Fields: []types.Field{{Name: "BurstQtr", Doc: "Quarter(s) when bursting occurs -- typically Q4 but can also be Q2 and Q4 for beta-frequency updating.  Note: this is a bitflag and must be accessed using its Set / Has etc routines, 32 bit versions."}, {Name: "ThrRel", Doc: "Relative component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  This is the distance between the average and maximum activation values within layer (e.g., 0 = average, 1 = max).  Overall effective threshold is MAX of relative and absolute thresholds."}, {Name: "ThrAbs", Doc: "Absolute component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  Overall effective threshold is MAX of relative and absolute thresholds."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CurriculumStage", IDName: "curriculum-stage", Doc: "CurriculumStage is a stage of training in a Curriculum, on the items of\none or more tables, which lasts until a criterion is reached on a stat,\nor for a number of epochs, e.g., AB training until the AB items are\nremembered.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the stage, e.g., AB, which is logged."}, {Name: "Tables", Doc: "Tables are the names of the tables of training items of the stage,\nas added to the Curriculum by AddTable: all the items of the first\ntable are trained in every epoch, interleaved with a random\nInterleave proportion of the items of the other tables, which are\nresampled in every epoch."}, {Name: "Interleave", Doc: "Interleave is the proportion of the items of the other Tables\n(after the first) that are interleaved in each epoch."}, {Name: "Stat", Doc: "Stat is the name of the stat compared with the Criterion at the end\nof each epoch, advancing to the next stage when it is >= Criterion.\nIf empty, the stage lasts for its Epochs."}, {Name: "Criterion", Doc: "Criterion is the value of the Stat at which the stage is done."}, {Name: "Epochs", Doc: "Epochs is the maximum number of epochs of the stage, after which\nit is done even if the Criterion has not been reached.\n0 = no maximum, so the last stage continues until the end of the run."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Curriculum", IDName: "curriculum", Doc: "Curriculum is a sequence of training stages, each on the items of one\nor more tables, e.g., AB then AC training for the classic AB-AC\ninterference paradigm, or interleaved (several tables per stage) and\nspaced (alternating stages) schedules.  Each stage advances to the next\nwhen its criterion is reached on a stat, or after its number of epochs.\nThe sim adds the tables with AddTable, calls Init at the start of each\nrun, EpochEnd at the end of each training epoch, and ConfigEnv to set\nthe training environment to the items of the current stage.", Fields: []types.Field{{Name: "Stages", Doc: "Stages are the stages of training, in order."}, {Name: "Stage", Doc: "Stage is the index of the current stage."}, {Name: "StageEpochs", Doc: "StageEpochs is the number of epochs trained in the current stage."}, {Name: "EpochStage", Doc: "EpochStage is the name of the stage of the last epoch counted by\nEpochEnd, i.e., the stage trained in that epoch, for logging."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CycleTrajectory", IDName: "cycle-trajectory", Doc: "CycleTrajectory records the representational trajectory of a set of\nlayers within a trial, for temporal representational similarity\nanalysis (RSA): the state of each layer at every cycle is recorded\ninto a circular buffer tensor, and the time-resolved similarity of\neach cycle's state to a reference state is computed, by default the\nstate at the last cycle recorded (i.e., the final settled state), or\na given reference pattern (e.g., the stored training pattern, see\nSetRef).  Call StartTrial at the start of each trial, RecordCycle\nafter each cycle, and then Similarity, AddToTable or SimMat at the\nend of the trial.", Fields: []types.Field{{Name: "Layers", Doc: "Layers are the names of the layers to record."}, {Name: "Var", Doc: "Var is the neuron variable to record (Act by default)."}, {Name: "Metric", Doc: "Metric is the similarity metric (Correlation by default)."}, {Name: "Cycles", Doc: "Cycles is the size of the circular buffer, i.e., the number of\nmost recent cycles that are kept, 100 (a trial) by default."}, {Name: "States", Doc: "States are the recorded states of each layer, as a\n[Cycles][units] circular buffer tensor."}, {Name: "Refs", Doc: "Refs are the reference states of each layer set by SetRef,\nwhich are used instead of the last cycle state if present."}, {Name: "N", Doc: "N is the number of cycles in the buffer,\nwhich is at most Cycles."}, {Name: "Total", Doc: "Total is the total number of cycles recorded since StartTrial."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaDipParams", IDName: "da-dip-params", Doc: "DaDipParams are the parameters for the negative (dip) component of the\nphasic dopamine sent by a dopamine layer, which can have different\ndynamics than bursts, as in the pauses in VTA / SNc firing driven by\nthe lateral habenula (LHb) and RMTg for aversive and disappointing\noutcomes.  Dips have their own gain, a floor reflecting the limited\nrange of firing rate pauses below the tonic baseline, and adaptation\nover repeated dips.  Dips can also be sent as a separate channel\n(like a VTAn negative-valence population) to distinct layers,\nfor modeling asymmetries in aversive vs. appetitive learning.", Fields: []types.Field{{Name: "On", Doc: "On enables the dip-specific dynamics.  Otherwise, the\nnegative dopamine is sent as computed."}, {Name: "Gain", Doc: "Gain is the multiplier on negative dopamine values."}, {Name: "Floor", Doc: "Floor is the minimum (most negative) dopamine value for dips,\nafter applying Gain and adaptation."}, {Name: "AdaptRate", Doc: "AdaptRate is the rate at which repeated dips adapt (habituate):\nafter each trial, the adaptation increases by AdaptRate times the\ndip magnitude (times 1 - adaptation), and dips are multiplied by\n1 - adaptation.  0 = no adaptation."}, {Name: "AdaptDecay", Doc: "AdaptDecay is the rate at which the adaptation decays\nback toward 0 after each trial."}, {Name: "SendTo", Doc: "SendTo is a list of layers that receive the dips as a separate\nnegative dopamine channel.  If non-empty, the dips are only sent\nto these layers, and the layer's SendTo layers receive only the\npositive bursts (0 for dips)."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaTrace", IDName: "da-trace", Doc: "DaTrace is a set of externally supplied dopamine (DA) time courses,\none per trial, e.g., derived from fiber photometry recordings,\nwhich can be injected as the output of a dopamine layer\n(ClampDaLayer, RWDaLayer, TDDaLayer) in place of its computed value,\nso that the components downstream of DA (e.g., Matrix, RWPred)\nlearn from the empirical DA signal.  See [DaInjectParams].", Fields: []types.Field{{Name: "Names", Doc: "Names are the names of the trials, which can be used to select\nthe trace for a trial (see TrialIndex)."}, {Name: "Values", Doc: "Values are the DA time course samples for each trial, which are\nevenly spaced across the injection window (see DaInjectParams),\nand linearly interpolated between samples.  A trial with a single\nsample has a constant DA value across the window."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaInjectParams", IDName: "da-inject-params", Doc: "DaInjectParams control the injection of an empirical [DaTrace] as the\nactivity of a dopamine layer (ClampDaLayer, RWDaLayer, TDDaLayer),\nwhich is then sent as DA to its SendTo layers, instead of the DA\nvalue computed by the layer.  The trace is set by SetDaTrace, and the\ntrial within the trace by SetDaTraceTrial, typically at the start of\neach trial.", Fields: []types.Field{{Name: "On", Doc: "On injects the DaTrace DA values for the current trial,\nif the layer has a DaTrace and the trial is within it."}, {Name: "StartCyc", Doc: "StartCyc is the cycle within the trial at which the trace starts,\nprior to which DA is 0."}, {Name: "NCyc", Doc: "NCyc is the number of cycles spanned by the trace samples,\nafter which DA is 0.  If 0, the trace extends to the end of the\ntrial (4 quarters)."}, {Name: "Gain", Doc: "Gain multiplies the trace values, e.g., to convert\nphotometry units into the range of model DA values."}, {Name: "Offset", Doc: "Offset is added to the trace values after Gain,\ne.g., to subtract a baseline."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.AttnParams", IDName: "attn-params", Doc: "AttnParams are the parameters for the attentional modulation of the\ngain of other layers (typically SuperLayers) by the magnitude of the\nprediction error in a Pulvinar (TRC) layer, i.e., the mean absolute\ndifference between its plus phase (outcome) and minus phase\n(prediction) activations, as in predictive attention models where\nsurprising (poorly predicted) inputs attract attention.  At the end of\neach trial, the prediction error of the Pulvinar layer is sent to its\nSendAttn layers, where it multiplies the excitatory input of their units\nby the gain 1 + Gain * PredErr (up to Max) on the next trial.\nIf several Pulvinar layers send to the same layer, the largest gain is used.", Fields: []types.Field{{Name: "Gain", Doc: "Gain is the multiplier on the prediction error for the increase in\nthe gain of the SendAttn layers, i.e., gain = 1 + Gain * PredErr."}, {Name: "Max", Doc: "Max is the maximum gain."}, {Name: "Tau", Doc: "Tau is the time constant, in trials, for integrating the prediction\nerror over trials, so that attention persists after a surprise.\n1 = the error of the last trial only."}, {Name: "Pools", Doc: "Pools modulates the gain of each pool of the SendAttn layers by\nthe prediction error of the corresponding pool of this layer,\nfor topographic (e.g., spatial) attention, if both are 4D with\nthe same number of pools.  Otherwise, all the units of the SendAttn\nlayers are modulated by the prediction error of the whole layer."}, {Name: "Dt", Doc: "Dt is the rate = 1 / Tau."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CtxtParams", IDName: "ctxt-params", Doc: "CtxtParams are the parameters for the integration of the temporal context\nCtxtGe in CT layers, at the end of each burst (see CtxtFromGe).\nBy default, the new context fully replaces the prior one, so the context\nonly spans one alpha trial (or burst period).  With a lag, the context\nis a leaky integration of the bursts over trials:\nCtxtGe = lag * CtxtGe + (1 - lag) * new, so that it spans about\n1 / (1 - lag) bursts, as needed for sequence learning (e.g., serial recall).\nThe lag can be learned for each neuron (the CtxtLag neuron variable),\nby a delta rule on the error of the neuron (ActP - ActM) with respect to\nthe lag, which is the difference between the prior and the new context\n(CtxtDif), so that neurons develop a range of temporal context spans.\nThe learned lags are saved and loaded with the weights.", Fields: []types.Field{{Name: "Lag", Doc: "Lag is the proportion of the prior context that is retained at each\ncontext update, 0 = full replacement by the new context.\nIt is the initial value of the lag of each neuron if Learn."}, {Name: "Learn", Doc: "Learn learns the lag of each neuron (CtxtLag), in DWt."}, {Name: "Lrate", Doc: "Lrate is the learning rate for the lag."}, {Name: "Max", Doc: "Max is the maximum learned lag."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.BurstParams", IDName: "burst-params", Doc: "BurstParams determine how the 5IB Burst activation is computed from\nstandard Act activation values in SuperLayer. It is thresholded.", Fields: []types.Field{{Name: "BurstQtr", Doc: "Quarter(s) when bursting occurs -- typically Q4 but can also be Q2 and Q4 for beta-frequency updating.  Note: this is a bitflag and must be accessed using its Set / Has etc routines, 32 bit versions."}, {Name: "ThrRel", Doc: "Relative component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  This is the distance between the average and maximum activation values within layer (e.g., 0 = average, 1 = max).  Overall effective threshold is MAX of relative and absolute thresholds."}, {Name: "ThrAbs", Doc: "Absolute component of threshold on superficial activation value, below which it does not drive Burst (and above which, Burst = Act).  Overall effective threshold is MAX of relative and absolute thresholds."}, {Name: "Trials", Doc: "Trials is the period of the burst schedule across alpha-cycle trials: bursting in the BurstQtr quarters only occurs on one out of every Trials trials (at TrialOff), e.g., 2 = every other trial, for slower predictive learning timescales.  On the other trials, there is no Burst updating, no CtxtGe updating in CT layers, and no driver inputs to Pulvinar layers.  The Super, CT and Pulvinar layers of a deep circuit must all use the same schedule."}, {Name: "TrialOff", Doc: "TrialOff is the trial within each period of Trials on which bursting occurs (0 to Trials-1), based on the Context AlphaCyc trial counter, to align the schedule with other processes over multiple trials."}, {Name: "Prob", Doc: "Prob is the probability of bursting on each trial of the schedule, for probabilistic bursting.  The random draw for each trial is shared by all the layers of the network (see Network.BurstRand), so that layers with the same Prob burst on the same trials."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Driver", IDName: "driver", Doc: "Driver describes the source of driver inputs from cortex into Pulvinar.", Fields: []types.Field{{Name: "Driver", Doc: "driver layer"}, {Name: "Off", Doc: "offset into Pulvinar pool"}}})
 
@@ -36,21 +78,65 @@ var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Driver
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PulvinarParams", IDName: "pulvinar-params", Doc: "PulvinarParams provides parameters for how the plus-phase (outcome) state\nof thalamic relay cell (e.g., Pulvinar) neurons is computed from the\ncorresponding driver neuron Burst activation.", Fields: []types.Field{{Name: "DriversOff", Doc: "Turn off the driver inputs, in which case this layer behaves like a standard layer"}, {Name: "BurstQtr", Doc: "Quarter(s) when bursting occurs -- typically Q4 but can also be Q2 and Q4 for beta-frequency updating.  Note: this is a bitflag and must be accessed using its Set / Has etc routines"}, {Name: "DriveScale", Doc: "multiplier on driver input strength, multiplies activation of driver layer"}, {Name: "MaxInhib", Doc: "Level of Max driver layer activation at which the predictive non-burst inputs are fully inhibited.  Computationally, it is essential that driver inputs inhibit effect of predictive non-driver (CTLayer) inputs, so that the plus phase is not always just the minus phase plus something extra (the error will never go to zero then).  When max driver act input exceeds this value, predictive non-driver inputs are fully suppressed.  If there is only weak burst input however, then the predictive inputs remain and this critically prevents the network from learning to turn activation off, which is difficult and severely degrades learning."}, {Name: "NoTopo", Doc: "Do not treat the pools in this layer as topographically organized relative to driver inputs -- all drivers compress down to give same input to all pools"}, {Name: "AvgMix", Doc: "proportion of average across driver pools that is combined with Max to provide some graded tie-breaker signal -- especially important for large pool downsampling, e.g., when doing NoTopo"}, {Name: "Binarize", Doc: "Apply threshold to driver burst input for computing plus-phase activations -- above BinThr, then Act = BinOn, below = BinOff.  This is beneficial for layers with weaker graded activations, such as V1 or other perceptual inputs."}, {Name: "BinThr", Doc: "Threshold for binarizing in terms of sending Burst activation"}, {Name: "BinOn", Doc: "Resulting driver Ge value for units above threshold -- lower value around 0.3 or so seems best (DriveScale is NOT applied -- generally same range as that)."}, {Name: "BinOff", Doc: "Resulting driver Ge value for units below threshold -- typically 0."}}})
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DriftingContext", IDName: "drifting-context", Doc: "DriftingContext generates context patterns that drift gradually across\ntrials, for hippocampus models of temporal context, where items learned\nclose together in time share more of their context than items learned\nfar apart.  Each context is a named stream, starting from a pattern in a\npatgen.Vocab, and on each trial a proportion Rate of its active bits are\nturned off and the same number of inactive bits turned on, so the number\nof active bits stays constant.  Unlike patgen.AddVocabDrift, the state of\neach stream carries over across the vocabulary items generated from it,\nso that a later list (e.g., AC) continues drifting from the last trial of\nan earlier one (e.g., AB).  Because the drift is based on the last trial,\nthe patterns must be presented sequentially in training (e.g., with\nenv.FixedTable Sequential), to preserve the temporal order.", Fields: []types.Field{{Name: "Rate", Doc: "Rate is the proportion of active bits that are flipped on\neach trial, relative to the previous trial.  Fractional numbers\nof bits are carried over to subsequent trials."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DropoutParams", IDName: "dropout-params", Doc: "DropoutParams are parameters for training-time dropout of units, which\nsilences a random subset of the units in the layer for each alpha\ntrial, as a regularizer that prevents the network from relying on any\nspecific set of units.  The dropped units are chosen in AlphaCycInit,\nonly when it is called for training (updtActAvg = true), so that\ndropout is automatically disabled during testing, and they are flagged\nwith NeurDropout and have their activity held at 0 for the trial.\nIf Rescale, the activity sent by the remaining units is scaled up by\n1 / (1 - P) (inverted dropout), so that the expected net input to the\nreceiving layers is the same as without dropout.", Fields: []types.Field{{Name: "On", Doc: "use dropout of units in this layer during training"}, {Name: "P", Doc: "probability of dropping each unit for an alpha trial"}, {Name: "Rescale", Doc: "scale the sending activity of the units that are not dropped by 1 / (1 - P), to preserve the expected net input to the receiving layers"}, {Name: "Active", Doc: "Active is true when units have been dropped for the current trial,\ni.e., On and the trial is a training trial."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EmbedNorms", IDName: "embed-norms", Doc: "EmbedNorms are the ways of normalizing the values of an external\nembedding vector (e.g., word embeddings or CNN features), which can have\nany range, into the 0-1 range of activations, for EmbedParams."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EmbedFits", IDName: "embed-fits", Doc: "EmbedFits are the ways of fitting an external embedding vector\nto the number of units in a layer, for EmbedParams."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EmbedParams", IDName: "embed-params", Doc: "EmbedParams are the parameters for adapting external embedding vectors,\nsuch as word embeddings or CNN features, to the activations of a layer,\nso that they can serve as fixed, precomputed representations for\na leabra model (see Layer.ApplyEmbedding and Embeddings.AddColumn).\nThe vector is first fit to the size of the layer, and then normalized\ninto the 0-1 range.  The layer should be an InputLayer with hard clamping\n(the default), so that its activity is set directly from the embedding,\nand it has no receiving pathways to learn.", Fields: []types.Field{{Name: "Norm", Doc: "how to normalize the vector values into the 0-1 range of activations"}, {Name: "Fit", Doc: "how to fit the vector to the number of units in the layer"}, {Name: "Gain", Doc: "multiplier on the vector values for the EmbedSigmoid and EmbedClip norms"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Embeddings", IDName: "embeddings", Doc: "Embeddings is a set of named external embedding vectors of the same\ndimensionality, such as word embeddings (word2vec, GloVe) or the\nfeatures of a CNN for a set of images, to use as fixed input\nrepresentations (see EmbedParams).", Fields: []types.Field{{Name: "Names", Doc: "Names are the names of the items, e.g., the words."}, {Name: "Vectors", Doc: "Vectors are the embedding vectors of the items, in the same order as Names."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EnvDescriber", IDName: "env-describer", Doc: "EnvDescriber is an optional interface for environments that can\ndescribe their training data, for EnvsReport."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EWCParams", IDName: "ewc-params", Doc: "EWCParams are the parameters for elastic weight consolidation (EWC),\nwhich protects the synapses that were important for previously learned\nitems from catastrophic interference when learning new items (e.g., AC\nafter AB), as a cortical alternative to the hippocampal solution.\nWhile learning, each synapse accumulates an estimate of its importance\nin the ImpAcc synapse variable, from the magnitude (or squared magnitude,\nfor a Fisher-like estimate) of its weight changes.  ConsolidateEWC\n(e.g., at the end of learning the old items) adds the accumulated\nimportance to the Imp synapse variable, and records the current linear\nweights as the consolidated values LWtCons.  From then on, the weight\nof each synapse is pulled back toward its consolidated value in\nproportion to Lambda * Imp, penalizing changes to the important ones.", Fields: []types.Field{{Name: "On", Doc: "use elastic weight consolidation on this pathway"}, {Name: "Lambda", Doc: "strength of the penalty on the change of the linear weights from their consolidated values, as a multiplier on the learning rate times the importance Imp: the pull per weight update is at most the full difference"}, {Name: "Fisher", Doc: "accumulate the squared weight changes as the importance (Fisher-like), instead of their absolute values"}, {Name: "Norm", Doc: "normalize the importance accumulated since the last consolidation by its maximum over the synapses of the pathway when it is consolidated, so Imp is 0-1 per consolidation, independent of the amount of learning"}, {Name: "Gamma", Doc: "proportion of the previously consolidated importance retained at each consolidation (online EWC): 1 = sum over all consolidations, 0 = only the last one"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ExcitParams", IDName: "excit-params", Doc: "ExcitParams are the parameters for intrinsic excitability plasticity:\na learned per-neuron excitability bias (the Excit neuron variable),\nwhich is added to the raw excitatory conductance of the neuron, and is\nadjusted at the end of each trial (in DWt) as a function of the\nplus-phase activation ActP relative to a target activity Targ.\nIf Homeo, the changes are homeostatic, increasing the excitability of\nneurons that are less active than Targ, and decreasing it for those that\nare more active.  Otherwise, the excitability of the neurons that are\nmore active than Targ is increased, as in the CREB-dependent excitability\nthought to allocate memories to recently active neurons (engrams), with\nDecay returning it to 0 over trials.  Excit is reset by InitWeights,\nand saved and loaded with the weights.  It continues to be applied when\nOn is false, which only turns off its learning.", Fields: []types.Field{{Name: "On", Doc: "learn the intrinsic excitability of each neuron"}, {Name: "Homeo", Doc: "homeostatic changes: increase the excitability of neurons that are less active than Targ, and decrease it for those that are more active -- otherwise, increase the excitability of neurons that are more active than Targ (activity-dependent memory allocation)"}, {Name: "Lrate", Doc: "learning rate for the change in excitability per trial, in units of raw excitatory conductance"}, {Name: "Targ", Doc: "target plus-phase activation (ActP), relative to which excitability is changed"}, {Name: "Decay", Doc: "proportion of the excitability decayed back toward 0 per trial, for transient activity-dependent excitability"}, {Name: "Min", Doc: "minimum excitability value"}, {Name: "Max", Doc: "maximum excitability value"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.FiniteLevels", IDName: "finite-levels", Doc: "FiniteLevels are the levels of checking for non-finite (NaN or Inf)\nvalues in the state of the network, with Network.CheckFinite."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.FiniteParams", IDName: "finite-params", Doc: "FiniteParams are the parameters for the automatic checking for\nnon-finite (NaN or Inf) values in the state of the network, which\notherwise silently propagate through the network, e.g., wasting\nlong cluster runs.  The first one detected is logged, or it panics.", Fields: []types.Field{{Name: "Level", Doc: "Level is the level of checking at the end of each WtFromDWt,\ni.e., at the end of each training trial."}, {Name: "Quarter", Doc: "Quarter also checks the neuron variables at the end of each\nquarter (QuarterFinal), to determine more precisely where a\nnon-finite value first appears, if Level is not FiniteOff."}, {Name: "Panic", Doc: "Panic panics with the non-finite value detected, to stop the\nrun as early as possible, instead of logging the first one."}, {Name: "First", Doc: "First is the first non-finite value detected since InitWeights,\nor nil if none."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NonFinite", IDName: "non-finite", Doc: "NonFinite records a non-finite (NaN or Inf) value in the state of\nthe network, as detected by Network.CheckFinite.", Fields: []types.Field{{Name: "Layer", Doc: "Layer is the name of the layer of the neuron, or the receiving\nlayer of the pathway."}, {Name: "Path", Doc: "Path is the name of the pathway of the synapse,\nempty for a neuron variable."}, {Name: "Var", Doc: "Var is the name of the neuron or synapse variable."}, {Name: "Index", Doc: "Index is the index of the neuron in the layer,\nor of the synapse in the pathway."}, {Name: "Value", Doc: "Value is the non-finite value."}, {Name: "Where", Doc: "Where is the point of the computation at which it was detected,\ne.g., \"QuarterFinal: quarter 1 cycle 49\"."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NetGraph", IDName: "net-graph", Doc: "NetGraph is a machine-readable description of the architecture of a\nnetwork, as a graph of its layers and pathways, for documenting\narchitectures and diffing them automatically (see Network.Graph).\nIt is written as JSON by WriteJSON, or as a GraphViz DOT digraph by\nWriteDOT, e.g., for rendering with: dot -Tsvg net.dot > net.svg.\nLayers and pathways that are Off are not included.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the network."}, {Name: "Layers", Doc: "Layers are the layers, in the order of the network."}, {Name: "Paths", Doc: "Paths are the pathways, in the order of the receiving layers\nand of their receiving pathways."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GraphLayer", IDName: "graph-layer", Doc: "GraphLayer describes a layer in a NetGraph.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the layer."}, {Name: "Type", Doc: "Type is the layer type, e.g., SuperLayer."}, {Name: "Class", Doc: "Class are the space-separated classes of the layer,\nused for selecting parameters."}, {Name: "Shape", Doc: "Shape is the shape of the layer."}, {Name: "NNeurons", Doc: "NNeurons is the number of neurons."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GraphPath", IDName: "graph-path", Doc: "GraphPath describes a pathway in a NetGraph.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the pathway."}, {Name: "Send", Doc: "Send is the name of the sending layer."}, {Name: "Recv", Doc: "Recv is the name of the receiving layer."}, {Name: "Type", Doc: "Type is the pathway type, e.g., ForwardPath."}, {Name: "Class", Doc: "Class are the space-separated classes of the pathway,\nused for selecting parameters."}, {Name: "Pattern", Doc: "Pattern is the name of the connectivity pattern, e.g., Full."}, {Name: "Abs", Doc: "Abs is the absolute weight scale (WtScale.Abs)."}, {Name: "Rel", Doc: "Rel is the relative weight scale (WtScale.Rel)."}, {Name: "NSyns", Doc: "NSyns is the number of synapses."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GymStepper", IDName: "gym-stepper", Doc: "GymStepper is the interface for a stepping reinforcement learning\nenvironment in the style of OpenAI Gym, with discrete actions,\nwhich can be wrapped as an env.Env with GymEnv."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GymEnv", IDName: "gym-env", Doc: "GymEnv is an env.Env that wraps a GymStepper, providing its\nobservations, rewards and actions as tensor states, so that it can\nbe used with the RW and TD reward layers, with the action chosen\nby the network on each trial.  On each Step, the current observation\nbecomes the Obs state, or a new episode is started if the last action\nended the episode.  The network then chooses an action, which is\nperformed with Action(\"Action\", ...) or TakeAction, after which the\nreward for the action is the Rew state, and the resulting observation\nis the NextObs state (all zeros at the end of an episode), which can\nbe presented in the plus phase for TD learning.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train vs. Test."}, {Name: "Gym", Doc: "Gym is the wrapped stepping environment."}, {Name: "Seed", Doc: "Seed is the seed for the Rand stream of this environment,\nwhich is seeded with Seed + run in Init."}, {Name: "Rand", Doc: "Rand is the random number stream for this environment,\nwhich is passed to the Gym Reset for each episode."}, {Name: "Trial", Doc: "Trial is the total number of steps in the run."}, {Name: "EpisodeStep", Doc: "EpisodeStep is the step within the current episode."}, {Name: "Episode", Doc: "Episode is the number of completed episodes."}, {Name: "Act", Doc: "Act is the action taken on the current step, -1 if none yet."}, {Name: "Reward", Doc: "Reward is the reward for the action taken on the current step."}, {Name: "Done", Doc: "Done is true if the action taken on the current step ended the episode."}, {Name: "EpisodeReward", Doc: "EpisodeReward is the total reward accumulated in the current episode."}, {Name: "LastEpisodeReward", Doc: "LastEpisodeReward is the total reward of the last completed episode."}, {Name: "LastEpisodeSteps", Doc: "LastEpisodeSteps is the number of steps of the last completed episode."}, {Name: "Obs", Doc: "Obs is the observation for the current step."}, {Name: "NextObs", Doc: "NextObs is the observation resulting from the action."}, {Name: "Rew", Doc: "Rew is the Reward as a 1x1 tensor."}, {Name: "ActionPat", Doc: "ActionPat is a localist pattern of the Act."}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CHLParams", IDName: "chl-params", Doc: "Contrastive Hebbian Learning (CHL) parameters", Fields: []types.Field{{Name: "On", Doc: "if true, use CHL learning instead of standard XCAL learning -- allows easy exploration of CHL vs. XCAL"}, {Name: "Hebb", Doc: "amount of hebbian learning (should be relatively small, can be effective at .0001)"}, {Name: "Err", Doc: "amount of error driven learning, automatically computed to be 1-Hebb"}, {Name: "MinusQ1", Doc: "if true, use ActQ1 as the minus phase -- otherwise ActM"}, {Name: "SAvgCor", Doc: "proportion of correction to apply to sending average activation for hebbian learning component (0=none, 1=all, .5=half, etc)"}, {Name: "SAvgThr", Doc: "threshold of sending average activation below which learning does not occur (prevents learning when there is no input)"}}})
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ThetaPhaseParams", IDName: "theta-phase-params", Doc: "ThetaPhaseParams are the theta-phase modulation parameters for the\nhippocampus, which switch the drive of CA1 between ECin (encoding)\nand CA3 (recall) across the quarters of the alpha cycle, and reduce\nthe strength of the DG -> CA3 mossy fibers during the first quarter.\nThese are applied by HipThetaPhase, which is called at the start\nof the relevant quarters by ConfigLoopsHip.", Fields: []types.Field{{Name: "ThetaLow", Doc: "ThetaLow is the WtScale.Abs of the CA1 pathway that is not\ncurrently driving CA1: CA3 -> CA1 in the first and fourth quarters,\nand ECin -> CA1 in the second and third quarters."}, {Name: "MossyDel", Doc: "MossyDel is the amount subtracted from the MossyRel WtScale.Rel\nof the DG -> CA3 mossy fiber pathway during the first quarter,\nso CA3 is driven more by ECin (floored at 0)."}, {Name: "MossyDelTest", Doc: "MossyDelTest is the amount subtracted from the MossyRel WtScale.Rel\nof the DG -> CA3 mossy fiber pathway after the first quarter during\ntesting, so that recall is less driven by the DG (floored at 0)."}, {Name: "MossyRel", Doc: "MossyRel is the base WtScale.Rel of the DG -> CA3 mossy fiber\npathway, as set by params, which is recorded by ConfigLoopsHip,\nor at the start of the first alpha cycle if not set."}, {Name: "Lrates", Doc: "Lrates are the learning rate multipliers for the encoding vs.\nretrieval quarters of the theta cycle, for each named pathway,\nfor testing encoding / retrieval tradeoffs in learning.\nPathways that are not listed learn normally.  Both Encode and\nRecall must be set for each entry, as there are no defaults."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ThetaLrateParams", IDName: "theta-lrate-params", Doc: "ThetaLrateParams are the learning rate multipliers for one pathway\nduring the encoding vs. retrieval quarters of the theta cycle.\nThe error-driven learning contrasts the activity coproducts at the\nend of different quarters, so each term is multiplied according to\nthe quarter it reflects: the plus phase (fourth quarter) and the\nActQ1 minus phase of EcCa1Path and CHL MinusQ1 are encoding, where CA1\nis driven by ECin, while the ActM (or AvgM) minus phase at the end of\nthe third quarter is retrieval, where CA1 is driven by CA3.  Thus,\nEncode > Recall favors strengthening of the encoded patterns, and\nRecall > Encode favors weakening of the recalled patterns.", Fields: []types.Field{{Name: "Path", Doc: "Path is the name of the pathway, e.g., CA3ToCA1."}, {Name: "Encode", Doc: "Encode multiplies the encoding quarter activity coproducts."}, {Name: "Recall", Doc: "Recall multiplies the retrieval quarter activity coproducts."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ReplayParams", IDName: "replay-params", Doc: "ReplayParams are the parameters for offline hippocampal replay,\nwhere CA3 is driven by a partial cue from a stored CA3 pattern,\nor by spontaneous random activity, with no cortical (ECin) input,\nand the resulting pattern completion in CA3 drives recall in ECout\nvia CA1.  The ECout activity can then be used as a training signal\nfor a neocortical network, for systems consolidation simulations.\nSee Network.HipReplay.", Fields: []types.Field{{Name: "CueFrac", Doc: "CueFrac is the proportion of the active units in the cue pattern\nthat are clamped on CA3, as a partial cue for pattern completion."}, {Name: "CueThr", Doc: "CueThr is the threshold, relative to the maximum value in the cue\npattern, for units to be considered active in the cue."}, {Name: "SpontPct", Doc: "SpontPct is the proportion of CA3 units that are randomly clamped\non for spontaneous replay, when there is no cue pattern."}, {Name: "CueCycles", Doc: "CueCycles is the number of cycles that the cue is clamped on CA3,\nafter which CA3 is free to settle into a completed pattern.\nWith the standard hippocampus params, CA3 activity dies out if the\ncue is released too early, once many items have been stored."}, {Name: "Cycles", Doc: "Cycles is the total number of cycles to settle for each replay."}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.InhibParams", IDName: "inhib-params", Doc: "leabra.InhibParams contains all the inhibition computation params and functions for basic Leabra\nThis is included in leabra.Layer to support computation.\nThis also includes other misc layer-level params such as running-average activation in the layer\nwhich is used for netinput rescaling and potentially for adapting inhibition over time", Fields: []types.Field{{Name: "Layer", Doc: "inhibition across the entire layer"}, {Name: "Pool", Doc: "inhibition across sub-pools of units, for layers with 4D shape"}, {Name: "Self", Doc: "neuron self-inhibition parameters -- can be beneficial for producing more graded, linear response -- not typically used in cortical networks"}, {Name: "ActAvg", Doc: "running-average activation computation values -- for overall estimates of layer activation levels, used in netinput scaling"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SelfInhibParams", IDName: "self-inhib-params", Doc: "SelfInhibParams defines parameters for Neuron self-inhibition -- activation of the neuron directly feeds back\nto produce a proportional additional contribution to Gi", Fields: []types.Field{{Name: "On", Doc: "enable neuron self-inhibition"}, {Name: "Gi", Doc: "strength of individual neuron self feedback inhibition -- can produce proportional activation behavior in individual units for specialized cases (e.g., scalar val or BG units), but not so good for typical hidden layers"}, {Name: "Tau", Doc: "time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life) for integrating unit self feedback inhibitory values -- prevents oscillations that otherwise occur -- relatively rapid 1.4 typically works, but may need to go longer if oscillations are a problem"}, {Name: "Dt", Doc: "rate = 1 / tau"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ActAvgParams", IDName: "act-avg-params", Doc: "ActAvgParams represents expected average activity levels in the layer.\nUsed for computing running-average computation that is then used for netinput scaling.\nAlso specifies time constant for updating average\nand for the target value for adapting inhibition in inhib_adapt.", Fields: []types.Field{{Name: "Init", Doc: "initial estimated average activity level in the layer (see also UseFirst option -- if that is off then it is used as a starting point for running average actual activity level, ActMAvg and ActPAvg) -- ActPAvg is used primarily for automatic netinput scaling, to balance out layers that have different activity levels -- thus it is important that init be relatively accurate -- good idea to update from recorded ActPAvg levels"}, {Name: "Fixed", Doc: "if true, then the Init value is used as a constant for ActPAvgEff (the effective value used for netinput rescaling), instead of using the actual running average activation"}, {Name: "UseExtAct", Doc: "if true, then use the activation level computed from the external inputs to this layer (avg of targ or ext unit vars) -- this will only be applied to layers with Input or Target / Compare layer types, and falls back on the targ_init value if external inputs are not available or have a zero average -- implies fixed behavior"}, {Name: "UseFirst", Doc: "use the first actual average value to override targ_init value -- actual value is likely to be a better estimate than our guess"}, {Name: "Tau", Doc: "time constant in trials for integrating time-average values at the layer level -- used for computing Pool.ActAvg.ActsMAvg, ActsPAvg"}, {Name: "Adjust", Doc: "adjustment multiplier on the computed ActPAvg value that is used to compute ActPAvgEff, which is actually used for netinput rescaling -- if based on connectivity patterns or other factors the actual running-average value is resulting in netinputs that are too high or low, then this can be used to adjust the effective average activity value -- reducing the average activity with a factor < 1 will increase netinput scaling (stronger net inputs from layers that receive from this layer), and vice-versa for increasing (decreases net inputs)"}, {Name: "Dt", Doc: "rate = 1 / tau"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Layer", IDName: "layer", Doc: "Layer implements the Leabra algorithm at the layer level,\nmanaging neurons and pathways.", Embeds: []types.Field{{Name: "LayerBase"}}, Fields: []types.Field{{Name: "Network", Doc: "our parent network, in case we need to use it to\nfind other layers etc; set when added by network."}, {Name: "Type", Doc: "type of layer."}, {Name: "RecvPaths", Doc: "list of receiving pathways into this layer from other layers."}, {Name: "SendPaths", Doc: "list of sending pathways from this layer to other layers."}, {Name: "Act", Doc: "Activation parameters and methods for computing activations."}, {Name: "Inhib", Doc: "Inhibition parameters and methods for computing layer-level inhibition."}, {Name: "Learn", Doc: "Learning parameters and methods that operate at the neuron level."}, {Name: "Burst", Doc: "Burst has parameters for computing Burst from act, in Superficial layers\n(but also needed in Deep layers for deep self connections)."}, {Name: "Pulvinar", Doc: "Pulvinar has parameters for computing Pulvinar plus-phase (outcome)\nactivations based on Burst activation from corresponding driver neuron."}, {Name: "Drivers", Doc: "Drivers are names of SuperLayer(s) that sends 5IB Burst driver\ninputs to this layer."}, {Name: "RW", Doc: "RW are Rescorla-Wagner RL learning parameters."}, {Name: "TD", Doc: "TD are Temporal Differences RL learning parameters."}, {Name: "Matrix", Doc: "Matrix BG gating parameters"}, {Name: "PBWM", Doc: "PBWM has general PBWM parameters, including the shape\nof overall Maint + Out gating system that this layer is part of."}, {Name: "GPiGate", Doc: "GPiGate are gating parameters determining threshold for gating etc."}, {Name: "CIN", Doc: "CIN cholinergic interneuron parameters."}, {Name: "PFCGate", Doc: "PFC Gating parameters"}, {Name: "PFCMaint", Doc: "PFC Maintenance parameters"}, {Name: "PFCDyns", Doc: "PFCDyns dynamic behavior parameters -- provides deterministic control over PFC maintenance dynamics -- the rows of PFC units (along Y axis) behave according to corresponding index of Dyns (inner loop is Super Y axis, outer is Dyn types) -- ensure Y dim has even multiple of len(Dyns)"}, {Name: "Neurons", Doc: "slice of neurons for this layer, as a flat list of len = Shape.Len().\nMust iterate over index and use pointer to modify values."}, {Name: "Pools", Doc: "inhibition and other pooled, aggregate state variables.\nflat list has at least of 1 for layer, and one for each sub-pool\nif shape supports that (4D).\nMust iterate over index and use pointer to modify values."}, {Name: "CosDiff", Doc: "cosine difference between ActM, ActP stats."}, {Name: "NeuroMod", Doc: "NeuroMod is the neuromodulatory neurotransmitter state for this layer."}, {Name: "SendTo", Doc: "SendTo is a list of layers that this layer sends special signals to,\nwhich could be dopamine, gating signals, depending on the layer type."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Layer", IDName: "layer", Doc: "Layer implements the Leabra algorithm at the layer level,\nmanaging neurons and pathways.", Embeds: []types.Field{{Name: "LayerBase"}}, Fields: []types.Field{{Name: "Network", Doc: "our parent network, in case we need to use it to\nfind other layers etc; set when added by network."}, {Name: "Type", Doc: "type of layer."}, {Name: "RecvPaths", Doc: "list of receiving pathways into this layer from other layers."}, {Name: "SendPaths", Doc: "list of sending pathways from this layer to other layers."}, {Name: "Act", Doc: "Activation parameters and methods for computing activations."}, {Name: "Inhib", Doc: "Inhibition parameters and methods for computing layer-level inhibition."}, {Name: "Learn", Doc: "Learning parameters and methods that operate at the neuron level."}, {Name: "NoiseInject", Doc: "NoiseInject has parameters for injecting noise into activity during\nspecified quarters, for lesion experiments; see InjectNoise."}, {Name: "Dropout", Doc: "Dropout has parameters for randomly silencing units during\ntraining trials, as a regularizer; see DropoutInit."}, {Name: "PopCode", Doc: "PopCode has parameters for encoding continuous values as population\ncodes over the units; see ApplyValues and DecodeValues."}, {Name: "Burst", Doc: "Burst has parameters for computing Burst from act, in Superficial layers\n(but also needed in Deep layers for deep self connections), and the\nburst schedule across trials, also used by Pulvinar layers."}, {Name: "Ctxt", Doc: "Ctxt has parameters for the integration of the temporal context\nover bursts in CT layers, with a configurable or learned lag."}, {Name: "Pulvinar", Doc: "Pulvinar has parameters for computing Pulvinar plus-phase (outcome)\nactivations based on Burst activation from corresponding driver neuron."}, {Name: "Drivers", Doc: "Drivers are names of SuperLayer(s) that sends 5IB Burst driver\ninputs to this layer."}, {Name: "Attn", Doc: "Attn has parameters for the attentional modulation of the gain of\nthe SendAttn layers by the prediction error of a Pulvinar layer."}, {Name: "RW", Doc: "RW are Rescorla-Wagner RL learning parameters."}, {Name: "TD", Doc: "TD are Temporal Differences RL learning parameters."}, {Name: "Matrix", Doc: "Matrix BG gating parameters"}, {Name: "PBWM", Doc: "PBWM has general PBWM parameters, including the shape\nof overall Maint + Out gating system that this layer is part of."}, {Name: "GPiGate", Doc: "GPiGate are gating parameters determining threshold for gating etc."}, {Name: "CIN", Doc: "CIN cholinergic interneuron parameters."}, {Name: "PFCGate", Doc: "PFC Gating parameters"}, {Name: "PFCMaint", Doc: "PFC Maintenance parameters"}, {Name: "Novelty", Doc: "Novelty has the parameters for the NoveltyLayer mismatch signal."}, {Name: "DaInject", Doc: "DaInject has the parameters for injecting an empirical DaTrace\nas the DA output of a dopamine layer."}, {Name: "DaMod", Doc: "DaMod has the parameters for the tonic vs. phasic components of\ndopamine, sent by dopamine layers and used by receiving layers."}, {Name: "DaDip", Doc: "DaDip has the parameters for the negative (dip) component of\ndopamine sent by a dopamine layer."}, {Name: "Value", Doc: "Value has the parameters for a ValueLayer."}, {Name: "PFCDyns", Doc: "PFCDyns dynamic behavior parameters -- provides deterministic control over PFC maintenance dynamics -- the rows of PFC units (along Y axis) behave according to corresponding index of Dyns (inner loop is Super Y axis, outer is Dyn types) -- ensure Y dim has even multiple of len(Dyns)"}, {Name: "Neurons", Doc: "slice of neurons for this layer, as a flat list of len = Shape.Len().\nMust iterate over index and use pointer to modify values."}, {Name: "Pools", Doc: "inhibition and other pooled, aggregate state variables.\nflat list has at least of 1 for layer, and one for each sub-pool\nif shape supports that (4D).\nMust iterate over index and use pointer to modify values."}, {Name: "CosDiff", Doc: "cosine difference between ActM, ActP stats."}, {Name: "NeuroMod", Doc: "NeuroMod is the neuromodulatory neurotransmitter state for this layer."}, {Name: "ValueIn", Doc: "ValueIn are the value and cost estimates received from ValueLayers."}, {Name: "SendTo", Doc: "SendTo is a list of layers that this layer sends special signals to,\nwhich could be dopamine, gating signals, depending on the layer type."}, {Name: "SendAttn", Doc: "SendAttn is a list of layers (typically SuperLayers) whose gain is\nmodulated by the prediction error of this Pulvinar layer (see Attn)."}, {Name: "GateRands", Doc: "GateRands are the random number streams for the gating noise in\neach pool of Matrix and GPiThal layers, if PBWM.GateNoise.On."}, {Name: "GateSeeds", Doc: "GateSeeds are the seeds that GateRands started from, in pool order."}, {Name: "Rand", Doc: "Rand is the random number stream for this layer, used for the\ninitial weights of its sending pathways, activation noise (other\nthan GateRands), noise injection, dropout and lesions.  It is seeded in\nNetwork.InitWeights from the network Rand and the layer name,\nso it does not depend on any other use of random numbers."}, {Name: "DaTrace", Doc: "DaTrace is an empirical DA trace injected as the activity of\na dopamine layer, if DaInject.On.  See SetDaTrace."}, {Name: "DaTraceTrial", Doc: "DaTraceTrial is the trial within DaTrace that is injected,\nor -1 (or any other trial outside the trace) to use the computed DA.  See SetDaTraceTrial."}, {Name: "DaDipAdapt", Doc: "DaDipAdapt is the adaptation of dopamine dips over trials,\nfor a dopamine layer with DaDip.AdaptRate > 0."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.HipLayers", IDName: "hip-layers", Doc: "HipLayers are references to the layers of a standard hippocampus\nmodel, as used in the hip examples, which can be obtained from a\nnetwork using its standard layer names with Network.LayerRefs.", Fields: []types.Field{{Name: "Input"}, {Name: "ECin"}, {Name: "ECout"}, {Name: "CA1"}, {Name: "DG"}, {Name: "CA3"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PBWMLayers", IDName: "pbwm-layers", Doc: "PBWMLayers are references to the layers created by AddPBWMLayers.\nThe PFC out layers are nil if there are no output stripes.", Fields: []types.Field{{Name: "MatrixGo"}, {Name: "MatrixNoGo"}, {Name: "GPe"}, {Name: "GPiThal"}, {Name: "CIN"}, {Name: "PFCmnt"}, {Name: "PFCmntD"}, {Name: "PFCout"}, {Name: "PFCoutD"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LayerTypes", IDName: "layer-types", Doc: "LayerTypes enumerates all the different types of layers,\nfor the different algorithm types supported.\nClass parameter styles automatically key off of these types."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LearnNeurParams", IDName: "learn-neur-params", Doc: "leabra.LearnNeurParams manages learning-related parameters at the neuron-level.\nThis is mainly the running average activations that drive learning.", Fields: []types.Field{{Name: "ActAvg", Doc: "parameters for computing running average activations that drive learning"}, {Name: "AvgL", Doc: "parameters for computing AvgL long-term running average"}, {Name: "CosDiff", Doc: "parameters for computing cosine diff between minus and plus phase"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LearnNeurParams", IDName: "learn-neur-params", Doc: "leabra.LearnNeurParams manages learning-related parameters at the neuron-level.\nThis is mainly the running average activations that drive learning.", Fields: []types.Field{{Name: "ActAvg", Doc: "parameters for computing running average activations that drive learning"}, {Name: "AvgL", Doc: "parameters for computing AvgL long-term running average"}, {Name: "CosDiff", Doc: "parameters for computing cosine diff between minus and plus phase"}, {Name: "Excit", Doc: "parameters for learning the intrinsic excitability of each neuron"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LearnSynParams", IDName: "learn-syn-params", Doc: "leabra.LearnSynParams manages learning-related parameters at the synapse-level.", Fields: []types.Field{{Name: "Learn", Doc: "enable learning for this pathway"}, {Name: "Lrate", Doc: "current effective learning rate (multiplies DWt values, determining rate of change of weights)"}, {Name: "LrateInit", Doc: "initial learning rate -- this is set from Lrate in UpdateParams, which is called when Params are updated, and used in LrateMult to compute a new learning rate for learning rate schedules."}, {Name: "XCal", Doc: "parameters for the XCal learning rule"}, {Name: "WtSig", Doc: "parameters for the sigmoidal contrast weight enhancement"}, {Name: "Norm", Doc: "parameters for normalizing weight changes by abs max dwt"}, {Name: "Momentum", Doc: "parameters for momentum across weight changes"}, {Name: "WtBal", Doc: "parameters for balancing strength of weight increases vs. decreases"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LearnSynParams", IDName: "learn-syn-params", Doc: "leabra.LearnSynParams manages learning-related parameters at the synapse-level.", Fields: []types.Field{{Name: "Learn", Doc: "enable learning for this pathway"}, {Name: "Lrate", Doc: "current effective learning rate (multiplies DWt values, determining rate of change of weights)"}, {Name: "LrateInit", Doc: "initial learning rate -- this is set from Lrate in UpdateParams, which is called when Params are updated (unless Lrate was set by LrateMult), and used in LrateMult to compute a new learning rate for learning rate schedules."}, {Name: "LrateSched", Doc: "learning rate schedule, which sets Lrate from LrateInit as a function of the training epoch"}, {Name: "XCal", Doc: "parameters for the XCal learning rule"}, {Name: "WtSig", Doc: "parameters for the sigmoidal contrast weight enhancement"}, {Name: "Norm", Doc: "parameters for normalizing weight changes by abs max dwt"}, {Name: "Momentum", Doc: "parameters for momentum across weight changes"}, {Name: "WtBal", Doc: "parameters for balancing strength of weight increases vs. decreases"}, {Name: "SynScale", Doc: "parameters for slow homeostatic synaptic scaling of the weights\nof each receiving neuron toward a target average weight or activity"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LrnActAvgParams", IDName: "lrn-act-avg-params", Doc: "LrnActAvgParams has rate constants for averaging over activations at different time scales,\nto produce the running average activation values that then drive learning in the XCAL learning rules", Fields: []types.Field{{Name: "SSTau", Doc: "time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the super-short time-scale avg_ss value -- this is provides a pre-integration step before integrating into the avg_s short time scale -- it is particularly important for spiking -- in general 4 is the largest value without starting to impair learning, but a value of 7 can be combined with m_in_s = 0 with somewhat worse results"}, {Name: "STau", Doc: "time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the short time-scale avg_s value from the super-short avg_ss value (cascade mode) -- avg_s represents the plus phase learning signal that reflects the most recent past information"}, {Name: "MTau", Doc: "time constant in cycles, which should be milliseconds typically (roughly, how long it takes for value to change significantly -- 1.4x the half-life), for continuously updating the medium time-scale avg_m value from the short avg_s value (cascade mode) -- avg_m represents the minus phase learning signal that reflects the expectation representation prior to experiencing the outcome (in addition to the outcome) -- the default value of 10 generally cannot be exceeded without impairing learning"}, {Name: "LrnM", Doc: "how much of the medium term average activation to mix in with the short (plus phase) to compute the Neuron AvgSLrn variable that is used for the unit's short-term average in learning. This is important to ensure that when unit turns off in plus phase (short time scale), enough medium-phase trace remains so that learning signal doesn't just go all the way to 0, at which point no learning would take place -- typically need faster time constant for updating S such that this trace of the M signal is lost -- can set SSTau=7 and set this to 0 but learning is generally somewhat worse"}, {Name: "Init", Doc: "initial value for average"}, {Name: "SSDt", Doc: "rate = 1 / tau"}, {Name: "SDt", Doc: "rate = 1 / tau"}, {Name: "MDt", Doc: "rate = 1 / tau"}, {Name: "LrnS", Doc: "1-LrnM"}}})
 
@@ -70,35 +156,109 @@ var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Moment
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtBalParams", IDName: "wt-bal-params", Doc: "WtBalParams are weight balance soft renormalization params:\nmaintains overall weight balance by progressively penalizing weight increases as a function of\nhow strong the weights are overall (subject to thresholding) and long time-averaged activation.\nPlugs into soft bounding function.", Fields: []types.Field{{Name: "On", Doc: "perform weight balance soft normalization?  if so, maintains overall weight balance across units by progressively penalizing weight increases as a function of amount of averaged receiver weight above a high threshold (hi_thr) and long time-average activation above an act_thr -- this is generally very beneficial for larger models where hog units are a problem, but not as much for smaller models where the additional constraints are not beneficial -- uses a sigmoidal function: WbInc = 1 / (1 + HiGain*(WbAvg - HiThr) + ActGain * (nrn.ActAvg - ActThr)))"}, {Name: "Targs", Doc: "apply soft bounding to target layers -- appears to be beneficial but still testing"}, {Name: "AvgThr", Doc: "threshold on weight value for inclusion into the weight average that is then subject to the further HiThr threshold for then driving a change in weight balance -- this AvgThr allows only stronger weights to contribute so that weakening of lower weights does not dilute sensitivity to number and strength of strong weights"}, {Name: "HiThr", Doc: "high threshold on weight average (subject to AvgThr) before it drives changes in weight increase vs. decrease factors"}, {Name: "HiGain", Doc: "gain multiplier applied to above-HiThr thresholded weight averages -- higher values turn weight increases down more rapidly as the weights become more imbalanced"}, {Name: "LoThr", Doc: "low threshold on weight average (subject to AvgThr) before it drives changes in weight increase vs. decrease factors"}, {Name: "LoGain", Doc: "gain multiplier applied to below-lo_thr thresholded weight averages -- higher values turn weight increases up more rapidly as the weights become more imbalanced -- generally beneficial but sometimes not -- worth experimenting with either 6 or 0"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Network", IDName: "network", Doc: "leabra.Network implements the Leabra algorithm, managing the Layers.", Embeds: []types.Field{{Name: "NetworkBase"}}, Fields: []types.Field{{Name: "Layers", Doc: "list of layers"}, {Name: "NThreads", Doc: "number of parallel threads (go routines) to use."}, {Name: "WtBalInterval", Doc: "how frequently to update the weight balance average\nweight factor -- relatively expensive."}, {Name: "WtBalCtr", Doc: "counter for how long it has been since last WtBal."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SynScaleParams", IDName: "syn-scale-params", Doc: "SynScaleParams are homeostatic synaptic scaling params: slowly scales\nall the linear weights (LWt) of each receiving neuron multiplicatively,\ntoward a target average weight across its connections, or a target\nlong-term average activity (ActAvg) of the neuron, which preserves the\nrelative weights learned by XCAL / CHL while preventing the weights from\nsaturating over long developmental or continual learning runs.\nThe scaling is applied every Interval weight updates, or when\nNetwork.SynScale is called, e.g., at the end of each epoch.", Fields: []types.Field{{Name: "On", Doc: "perform homeostatic synaptic scaling"}, {Name: "ByAct", Doc: "scale toward a target long-term average activity (ActAvg) of the receiving neuron, instead of a target average weight: weights are scaled down for neurons that are more active than the target, and up for less active ones"}, {Name: "WtTarg", Doc: "target average linear weight (LWt) across the connections of each receiving neuron, if not ByAct"}, {Name: "ActTarg", Doc: "target long-term average activity (ActAvg) of each receiving neuron, if ByAct -- 0 = use the expected layer activity, Inhib.ActAvg.Init"}, {Name: "Rate", Doc: "rate of scaling: proportion of the relative deviation from target by which the weights are scaled on each update, which is also the maximum proportional change per update"}, {Name: "Interval", Doc: "number of weight updates (trials) between scaling updates -- 0 = only when Network.SynScale is called, e.g., at the end of each epoch"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LearnProgress", IDName: "learn-progress", Doc: "LearnProgress has learning progress statistics for a pathway, computed\nover the weight updates since the last call to ComputeLearnProgress\n(e.g., over an epoch), for detecting stalled or runaway learning.\nThe norms are root-mean-square values per synapse, so that pathways of\ndifferent sizes are comparable.  Accumulation happens in\nNetwork.WtFromDWt when Network.RecLearnProgress is set, which is done\nautomatically by LogAddLearnProgressItems.", Fields: []types.Field{{Name: "DWtNorm", Doc: "DWtNorm is the L2 norm of the DWt weight changes per update, averaged\nover updates as root-mean-square, divided by sqrt(number of synapses)."}, {Name: "WtDeltaNorm", Doc: "WtDeltaNorm is the L2 norm of the net change in Wt over the updates,\nfrom the weights prior to the first update, divided by\nsqrt(number of synapses)."}, {Name: "SatFrac", Doc: "SatFrac is the fraction of synapses whose linear weight LWt is\nwithin LearnSatTol of its 0 or 1 bound, at the time of computing."}, {Name: "NUpdates", Doc: "NUpdates is the number of weight updates the stats were computed over."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LesionRecord", IDName: "lesion-record", Doc: "LesionRecord is a record of one lesion, noise injection, or reversal\noperation performed on a network, stored in order in Network.LesionLog,\nto document the manipulations done in a given experiment.", Fields: []types.Field{{Name: "Op", Doc: "the operation, e.g., LesionUnits, UnLesionUnits, LesionSyns,\nUnLesionSyns, InjectNoise, ClearNoise, PruneUnits"}, {Name: "Name", Doc: "name of the layer, or the pathway for synapse operations"}, {Name: "Prop", Doc: "proportion of units or synapses, for random lesions,\nor the noise variance for InjectNoise, or the threshold for PruneUnits"}, {Name: "N", Doc: "number of units or synapses affected"}, {Name: "Desc", Doc: "additional description of the operation"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NoiseInjectParams", IDName: "noise-inject-params", Doc: "NoiseInjectParams are parameters for injecting random noise into\nneural activity, on top of any standard Act.Noise, during specified\nquarters of the alpha cycle (e.g., only the minus or plus phase),\nto simulate graded damage or neuromodulatory disruption.\nNoise is generated anew on every cycle for each neuron.\nUse Layer.InjectNoise and ClearNoise to record in the LesionLog.", Embeds: []types.Field{{Name: "RandParams"}}, Fields: []types.Field{{Name: "On", Doc: "whether noise injection is active"}, {Name: "Type", Doc: "where to add the noise: VmNoise, GeNoise, or ActNoise"}, {Name: "Qtrs", Doc: "quarters in which noise is injected: Q1, Q2, Q3 for the minus phase\nand Q4 for the plus phase. Note: this is a bitflag and must be\naccessed using its Set / Has etc routines."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LogWriter", IDName: "log-writer", Doc: "LogWriter streams the rows of a log table to a file, as each row is\nlogged, as an alternative to the TSV files written by elog.Logs."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LogFormats", IDName: "log-formats", Doc: "LogFormats are the file formats for the log files of LogFiles."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LogFiles", IDName: "log-files", Doc: "LogFiles manages the files that the log tables of elog.Logs are\nsaved to, in the given Format, with the same file names as\nelog.SetLogFile other than the extension.  For the TSV format,\nelog.Logs writes the files itself, while for other formats the\nrows are written to a LogWriter by WriteLastRow, which must be\ncalled after each Logs.LogRow (e.g., in the Log method of the sim).", Fields: []types.Field{{Name: "Format", Doc: "Format is the file format."}, {Name: "RowGroup", Doc: "RowGroup is the number of rows per row group for the Parquet\nformat, which are buffered before writing them to the file,\nso that at most this many rows are lost if the run crashes.\nUses 100 if 0."}, {Name: "Writers", Doc: "Writers are the writers for each scope of the log tables."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LogSpec", IDName: "log-spec", Doc: "LogSpec is a declarative specification of a statistic to log,\nwhich AddLogSpecs turns into elog items that compute the stat at\nthe lowest time scale, and aggregate it at each higher time scale,\nso that the log tables and plots do not need to be wired by hand.\nParseLogSpec parses a LogSpec from a one-line text form, e.g.:\n\n\tCorSim at Trial,Epoch,Run agg Mean plot\n\tActMAvg at Trial,Epoch for Hidden1,Hidden2 in Train", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the stat.  If Layers is empty, it is the name\nof a float stat in the estats.Stats of the log context, set by the\nsim at the lowest time scale, and is the item name.  Otherwise,\nit is a layer stat (see LogLayerStats), or the name of a unit\nvariable, which is averaged over the units of each layer, and\nthe item name is Layer_Name."}, {Name: "Layers", Doc: "Layers are the layers to log the layer stat for."}, {Name: "Mode", Doc: "Mode is the eval mode to log in, AllModes for all of them."}, {Name: "Times", Doc: "Times are the time scales to log at, in higher to lower order\n(e.g., Run, Epoch, Trial), as for the other log item functions.\nThe stat is computed at the lowest one."}, {Name: "Agg", Doc: "Agg is the stat used to aggregate over the rows of the next lower\ntime scale.  For the Run and Condition scales, it is computed over\nthe last 5 rows, as in elog.AddStdAggs."}, {Name: "Plot", Doc: "Plot turns on plotting of the items."}, {Name: "Range", Doc: "Range is the fixed plot range, if Max > Min; otherwise the\nplot min is fixed at 0 and the max floats."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LogFileSpec", IDName: "log-file-spec", Doc: "LogFileSpec specifies a standard log file, for LogFiles.SetStdLogFiles.", Fields: []types.Field{{Name: "Flag", Doc: "Flag is the name of the bool field in the log config of the sim\nthat turns on saving this log to a file."}, {Name: "Mode", Doc: "Mode is the eval mode of the log table."}, {Name: "Time", Doc: "Time is the time scale of the log table."}, {Name: "Name", Doc: "Name is the name of the log, which is the file extension\nbefore .tsv, e.g., epc for _epc.tsv."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LrateScheds", IDName: "lrate-scheds", Doc: "LrateScheds are the types of learning rate schedules,\nfor LrateSchedParams."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LrateSchedParams", IDName: "lrate-sched-params", Doc: "LrateSchedParams are the parameters for a learning rate schedule,\nwhich sets the learning rate Lrate of a pathway as a function of the\ntraining epoch, as a multiplier on the initial learning rate LrateInit\n(set from the Lrate params), including an optional linear warmup from\nWarmupStart times the initial rate over the first Warmup epochs.\nIt is set per pathway, or network-wide with a Path params selector,\nand is advanced by Network.EpochInc or SetLrateEpoch, which can be\ncalled automatically by LooperLrateSched.  Pathways with a LrateConstant\nschedule and no Warmup are not affected, so that sims can still set\ntheir learning rates directly, e.g., with LrateMult.", Fields: []types.Field{{Name: "Type", Doc: "type of schedule"}, {Name: "Warmup", Doc: "number of epochs of linear warmup at the start of training, from WarmupStart times the initial learning rate up to the initial learning rate -- 0 = no warmup"}, {Name: "WarmupStart", Doc: "multiplier on the initial learning rate at the start of the Warmup"}, {Name: "Steps", Doc: "epochs at which the learning rate is multiplied by Factor, for LrateStep, e.g., [50, 100] in params"}, {Name: "Factor", Doc: "multiplier on the learning rate at each step for LrateStep, or every epoch for LrateExp"}, {Name: "Epochs", Doc: "last epoch of the LrateCosine schedule, after which the learning rate stays at Min times the initial rate"}, {Name: "Min", Doc: "minimum multiplier on the initial learning rate at the end of the LrateCosine schedule"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.MemScoreParams", IDName: "mem-score-params", Doc: "MemScoreParams select the memory scoring functions to compute,\nin addition to the standard binary memory criterion,\nwhich is based on ReadoutMemStats with the Thr threshold.\nThe alternative scores are based on the continuous correlation\nbetween the actual activity and the target pattern on each trial\n(see MemCorrel), which is then compared between studied items and\nnovel lure items over a set of test trials, in terms of d-prime\n(MemDPrime) and the area under the ROC curve (MemROCArea).", Fields: []types.Field{{Name: "Thr", Doc: "threshold on the proportion of incorrect units (target on but was off,\nor target off but was on) for a trial to count as remembered,\nfor the binary memory criterion"}, {Name: "Correl", Doc: "compute the correlation between the actual activity and target\npattern on each trial, as a continuous measure of memory"}, {Name: "DPrime", Doc: "compute the d-prime discriminability of correlation scores for\nstudied items vs. lures, over the test trials"}, {Name: "ROC", Doc: "compute the area under the ROC curve of correlation scores for\nstudied items vs. lures, over the test trials"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.MPI", IDName: "mpi", Doc: "MPI manages data-parallel training over MPI (message passing interface),\nwhere each proc runs an identical copy of the same sim on a different\nshard of the training trials, and the DWt weight changes are summed\nacross all procs (AllReduce) prior to updating the weights, so that\nthe weights remain synchronized.  All procs must start with the\nsame random seed.  MPI is only actually available when built with\nthe mpi or mpich build tag, and otherwise On remains false and all\nof the methods are no-ops, so sims can use it unconditionally.\nTypical usage in a sim:\n  - MPI.Init(ss.Config.Run.MPI) at the start of main, and\n    MPI.Finalize() at the end.\n  - MPI.ConfigShardEnv on a ShardEnv training env, in ConfigEnv,\n    which presents a different random shard of the trials to each\n    proc in each epoch (or MPI.ShardIndexView on the training env\n    table, for a fixed shard).\n  - MPI.ConfigLoops after LooperSimCycleAndLearn, in ConfigLoops.\n  - MPI.GatherTableRows on the trial log prior to computing epoch stats.", Fields: []types.Field{{Name: "On", Doc: "whether MPI is on: set by Init when MPI is available and requested"}, {Name: "Comm", Doc: "communicator for all of the procs"}, {Name: "AllDWts", Doc: "buffer of all the DWt values for this proc"}, {Name: "SumDWts", Doc: "buffer of the DWt values summed across all procs"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NearestPatterns", IDName: "nearest-patterns", Doc: "NearestPatterns is a set of named stored patterns, e.g., the training\ntarget patterns, or the layer activity states recorded during training,\nwhich can be searched for the patterns nearest to a given pattern\nin terms of cosine similarity, e.g., to identify which stored pattern\na network actually recalled on a test trial, and thus classify\nintrusion errors.  The patterns are stored normalized to unit length,\nso each comparison is a single dot product.", Fields: []types.Field{{Name: "Names", Doc: "Names are the names of the stored patterns."}, {Name: "Pats", Doc: "Pats are the stored patterns, normalized to unit length\n(all zeros if the pattern has no non-zero values)."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PatternMatch", IDName: "pattern-match", Doc: "PatternMatch is one result of a NearestPatterns search.", Fields: []types.Field{{Name: "Name", Doc: "Name of the matching stored pattern."}, {Name: "Index", Doc: "Index of the matching stored pattern."}, {Name: "Cos", Doc: "Cos is the cosine similarity between the pattern and the stored pattern."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NetRecorder", IDName: "net-recorder", Doc: "NetRecorder records the NetView data (the values of all the unit\nvariables, per update) during headless (nogui) runs, e.g., on a cluster,\nsaving it to files for playback in the NetView later (see OpenNetRecord).\nUnlike the fixed-size ring buffer of the NetView, which only has the\nmost recent records, the recording is saved in segment files of MaxRecs\nrecords each, so an entire session can be recorded with bounded memory.\nSynaptic values are not recorded.", Fields: []types.Field{{Name: "File", Doc: "File is the base file name for the segment files, which are saved\nas File_<seg>.netdata.json.gz, with seg starting at 000."}, {Name: "MaxRecs", Doc: "MaxRecs is the maximum number of records per segment file."}, {Name: "Data", Doc: "Data is the NetView data for the current segment."}, {Name: "Files", Doc: "Files are the names of the segment files saved so far."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Network", IDName: "network", Doc: "leabra.Network implements the Leabra algorithm, managing the Layers.", Embeds: []types.Field{{Name: "NetworkBase"}}, Fields: []types.Field{{Name: "Layers", Doc: "list of layers"}, {Name: "NThreads", Doc: "number of parallel threads (go routines) to use for computing\nthe Cycle-level updates, which are automatically distributed across\nthem: defaults to 1 (serial). Use SetNThreads to set."}, {Name: "WtBalInterval", Doc: "how frequently to update the weight balance average\nweight factor -- relatively expensive."}, {Name: "WtBalCtr", Doc: "counter for how long it has been since last WtBal."}, {Name: "LesionLog", Doc: "LesionLog records all the lesion, noise injection and reversal\noperations performed on the network, in order.  See LesionReport."}, {Name: "LayerAliases", Doc: "LayerAliases maps alternative names to layer names,\nfor use in LayerByName.  See AddLayerAlias."}, {Name: "Theta", Doc: "Theta has the hippocampal theta-phase modulation parameters,\nused by HipThetaPhase and ConfigLoopsHip."}, {Name: "Finite", Doc: "Finite has the parameters for the automatic checking for\nnon-finite (NaN or Inf) values in the state of the network."}, {Name: "RecLearnProgress", Doc: "RecLearnProgress accumulates the per-pathway LearnProgress stats\nin WtFromDWt, which is turned on by LogAddLearnProgressItems."}, {Name: "LrateEpoch", Doc: "LrateEpoch is the current training epoch for the learning rate\nschedules of the pathways (Learn.LrateSched), which is advanced by\nEpochInc or SetLrateEpoch, and reset to 0 by InitWeights."}, {Name: "BurstSeed", Doc: "BurstSeed is the seed for the random numbers of the probabilistic\nbursting of deep layers (see BurstRand), set from the network Rand\nin InitWeights."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.LayerNames", IDName: "layer-names", Doc: "LayerNames is a list of layer names, with methods to add and validate."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NeuroMod", IDName: "neuro-mod", Doc: "NeuroMod are the neuromodulatory neurotransmitters, at the layer level.", Fields: []types.Field{{Name: "DA", Doc: "DA is dopamine, which primarily modulates learning, and also excitability,\nand reflects the reward prediction error (RPE)."}, {Name: "ACh", Doc: "ACh is acetylcholine, which modulates excitability and also learning,\nand reflects salience, i.e., reward (without discount by prediction) and\nlearned CS onset."}, {Name: "SE", Doc: "SE is serotonin, which is a longer timescale neuromodulator with many\ndifferent effects. Currently not implemented, but here for future expansion."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NeuroMod", IDName: "neuro-mod", Doc: "NeuroMod are the neuromodulatory neurotransmitters, at the layer level.", Fields: []types.Field{{Name: "DA", Doc: "DA is phasic dopamine, which primarily modulates learning, and also excitability,\nand reflects the reward prediction error (RPE)."}, {Name: "DATonic", Doc: "DATonic is the tonic dopamine level, relative to the normal level of 0,\nwhich changes slowly and reflects e.g., dopamine depletion (negative)\nor dopaminergic medication (positive).  It is sent along with the\nphasic DA from the DaMod.Tonic of the sending dopamine layer."}, {Name: "ACh", Doc: "ACh is acetylcholine, which modulates excitability and also learning,\nand reflects salience, i.e., reward (without discount by prediction) and\nlearned CS onset."}, {Name: "SE", Doc: "SE is serotonin, which is a longer timescale neuromodulator with many\ndifferent effects. Currently not implemented, but here for future expansion."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaModParams", IDName: "da-mod-params", Doc: "DaModParams are the parameters for the tonic and phasic components\nof dopamine, including the tonic level sent by a dopamine layer, and\nwhich component modulates the learning and the excitability (gain)\nof a receiving layer.  The defaults reproduce the standard behavior,\nwhere only phasic DA drives learning and there is no gain effect.\nTonic DA manipulations (e.g., medication on vs. off states) can be\nsimulated by setting the Tonic level on the dopamine layer, and\nGain and GainFactor on the receiving layers.", Fields: []types.Field{{Name: "Tonic", Doc: "Tonic is the tonic dopamine level sent by this layer, if it is a\ndopamine layer, along with the phasic DA computed by the layer.\n0 is the normal level, negative is depleted (e.g., Parkinson's off\nmedication), and positive is elevated (e.g., on medication)."}, {Name: "Learn", Doc: "Learn is the component of the received dopamine that drives learning,\ne.g., in the Matrix, RW and TD pathways."}, {Name: "Gain", Doc: "Gain is the component of the received dopamine that modulates the\nexcitability of the layer, by multiplying the excitatory net input."}, {Name: "GainFactor", Doc: "GainFactor is the strength of the modulation of excitability by\nthe Gain component of dopamine, with the net input multiplied by\n1 + GainFactor * DA for D1R, and 1 - GainFactor * DA for D2R\nlayers (see PBWM.DaR), clipped to be non-negative."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaComps", IDName: "da-comps", Doc: "DaComps are the components of the dopamine signal, used to select\nwhich modulates learning vs. excitability in DaModParams."})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.DaReceptors", IDName: "da-receptors", Doc: "DaReceptors for D1R and D2R dopamine receptors"})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Valences", IDName: "valences", Doc: "Valences for Appetitive and Aversive valence coding"})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Neuron", IDName: "neuron", Doc: "leabra.Neuron holds all of the neuron (unit) level variables -- this is the most basic version with\nrate-code only and no optional features at all.\nAll variables accessible via Unit interface must be float32 and start at the top, in contiguous order", Fields: []types.Field{{Name: "Flags", Doc: "bit flags for binary state variables"}, {Name: "SubPool", Doc: "index of the sub-level inhibitory pool that this neuron is in (only for 4D shapes, the pool (unit-group / hypercolumn) structure level) -- indicies start at 1 -- 0 is layer-level pool (is 0 if no sub-pools)."}, {Name: "Act", Doc: "rate-coded activation value reflecting final output of neuron communicated to other neurons, typically in range 0-1.  This value includes adaptation and synaptic depression / facilitation effects which produce temporal contrast (see ActLrn for version without this).  For rate-code activation, this is noisy-x-over-x-plus-one (NXX1) function; for discrete spiking it is computed from the inverse of the inter-spike interval (ISI), and Spike reflects the discrete spikes."}, {Name: "Ge", Doc: "total excitatory synaptic conductance -- the net excitatory input to the neuron -- does *not* include Gbar.E"}, {Name: "Gi", Doc: "total inhibitory synaptic conductance -- the net inhibitory input to the neuron -- does *not* include Gbar.I"}, {Name: "Gk", Doc: "total potassium conductance, typically reflecting sodium-gated potassium currents involved in adaptation effects -- does *not* include Gbar.K"}, {Name: "Inet", Doc: "net current produced by all channels -- drives update of Vm"}, {Name: "Vm", Doc: "membrane potential -- integrates Inet current over time"}, {Name: "Noise", Doc: "noise value added to unit (ActNoiseParams determines distribution, and when / where it is added)"}, {Name: "Spike", Doc: "whether neuron has spiked or not (0 or 1), for discrete spiking neurons."}, {Name: "Targ", Doc: "target value: drives learning to produce this activation value"}, {Name: "Ext", Doc: "external input: drives activation of unit from outside influences (e.g., sensory input)"}, {Name: "AvgSS", Doc: "super-short time-scale average of ActLrn activation -- provides the lowest-level time integration -- for spiking this integrates over spikes before subsequent averaging, and it is also useful for rate-code to provide a longer time integral overall"}, {Name: "AvgS", Doc: "short time-scale average of ActLrn activation -- tracks the most recent activation states (integrates over AvgSS values), and represents the plus phase for learning in XCAL algorithms"}, {Name: "AvgM", Doc: "medium time-scale average of ActLrn activation -- integrates over AvgS values, and represents the minus phase for learning in XCAL algorithms"}, {Name: "AvgL", Doc: "long time-scale average of medium-time scale (trial level) activation, used for the BCM-style floating threshold in XCAL"}, {Name: "AvgLLrn", Doc: "how much to learn based on the long-term floating threshold (AvgL) for BCM-style Hebbian learning -- is modulated by level of AvgL itself (stronger Hebbian as average activation goes higher) and optionally the average amount of error experienced in the layer (to retain a common proportionality with the level of error-driven learning across layers)"}, {Name: "AvgSLrn", Doc: "short time-scale activation average that is actually used for learning -- typically includes a small contribution from AvgM in addition to mostly AvgS, as determined by LrnActAvgParams.LrnM -- important to ensure that when unit turns off in plus phase (short time scale), enough medium-phase trace remains so that learning signal doesn't just go all the way to 0, at which point no learning would take place"}, {Name: "ActLrn", Doc: "learning activation value, reflecting *dendritic* activity that is not affected by synaptic depression or adapdation channels which are located near the axon hillock.  This is the what drives the Avg* values that drive learning. Computationally, neurons strongly discount the signals sent to other neurons to provide temporal contrast, but need to learn based on a more stable reflection of their overall inputs in the dendrites."}, {Name: "ActM", Doc: "the activation state at end of third quarter, which is the traditional posterior-cortical minus phase activation"}, {Name: "ActP", Doc: "the activation state at end of fourth quarter, which is the traditional posterior-cortical plus_phase activation"}, {Name: "ActDif", Doc: "ActP - ActM -- difference between plus and minus phase acts -- reflects the individual error gradient for this neuron in standard error-driven learning terms"}, {Name: "ActDel", Doc: "delta activation: change in Act from one cycle to next -- can be useful to track where changes are taking place"}, {Name: "ActQ0", Doc: "the activation state at start of current alpha cycle (same as the state at end of previous cycle)"}, {Name: "ActQ1", Doc: "the activation state at end of first quarter of current alpha cycle"}, {Name: "ActQ2", Doc: "the activation state at end of second quarter of current alpha cycle"}, {Name: "ActAvg", Doc: "average activation (of final plus phase activation state) over long time intervals (time constant = DtPars.AvgTau -- typically 200) -- useful for finding hog units and seeing overall distribution of activation"}, {Name: "Burst", Doc: "5IB bursting activation value, computed by thresholding regular activation"}, {Name: "BurstPrv", Doc: "previous bursting activation -- used for context-based learning"}, {Name: "GiSyn", Doc: "aggregated synaptic inhibition (from Inhib pathways) -- time integral of GiRaw -- this is added with computed FFFB inhibition to get the full inhibition in Gi"}, {Name: "GiSelf", Doc: "total amount of self-inhibition -- time-integrated to avoid oscillations"}, {Name: "ActSent", Doc: "last activation value sent (only send when diff is over threshold)"}, {Name: "GeRaw", Doc: "raw excitatory conductance (net input) received from sending units (send delta's are added to this value)"}, {Name: "GiRaw", Doc: "raw inhibitory conductance (net input) received from sending units (send delta's are added to this value)"}, {Name: "GknaFast", Doc: "conductance of sodium-gated potassium channel (KNa) fast dynamics (M-type) -- produces accommodation / adaptation of firing"}, {Name: "GknaMed", Doc: "conductance of sodium-gated potassium channel (KNa) medium dynamics (Slick) -- produces accommodation / adaptation of firing"}, {Name: "GknaSlow", Doc: "conductance of sodium-gated potassium channel (KNa) slow dynamics (Slack) -- produces accommodation / adaptation of firing"}, {Name: "ISI", Doc: "current inter-spike-interval -- counts up since last spike.  Starts at -1 when initialized."}, {Name: "ISIAvg", Doc: "average inter-spike-interval -- average time interval between spikes.  Starts at -1 when initialized, and goes to -2 after first spike, and is only valid after the second spike post-initialization."}, {Name: "CtxtGe", Doc: "CtxtGe is context (temporally delayed) excitatory conducances."}, {Name: "ActG", Doc: "gating activation -- the activity value when gating occurred in this pool."}, {Name: "DALrn", Doc: "per-neuron effective learning dopamine value -- gain modulated and sign reversed for D2R"}, {Name: "Shunt", Doc: "shunting input received from Patch neurons (in reality flows through SNc DA pathways)"}, {Name: "Maint", Doc: "maintenance value for Deep layers = sending act at time of gating"}, {Name: "MaintGe", Doc: "maintenance excitatory conductance value for Deep layers"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Float", IDName: "float", Doc: "Float is the floating point type of all the state and parameters,\nwhich is float32 by default, and float64 when built with the\nleabra64 build tag, for verification (see the fmath package)."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Neuron", IDName: "neuron", Doc: "leabra.Neuron holds all of the neuron (unit) level variables -- this is the most basic version with\nrate-code only and no optional features at all.\nAll variables accessible via Unit interface must be Float and start at the top, in contiguous order", Fields: []types.Field{{Name: "Flags", Doc: "bit flags for binary state variables"}, {Name: "SubPool", Doc: "index of the sub-level inhibitory pool that this neuron is in (only for 4D shapes, the pool (unit-group / hypercolumn) structure level) -- indicies start at 1 -- 0 is layer-level pool (is 0 if no sub-pools)."}, {Name: "Act", Doc: "rate-coded activation value reflecting final output of neuron communicated to other neurons, typically in range 0-1.  This value includes adaptation and synaptic depression / facilitation effects which produce temporal contrast (see ActLrn for version without this).  For rate-code activation, this is noisy-x-over-x-plus-one (NXX1) function; for discrete spiking it is computed from the inverse of the inter-spike interval (ISI), and Spike reflects the discrete spikes."}, {Name: "Ge", Doc: "total excitatory synaptic conductance -- the net excitatory input to the neuron -- does *not* include Gbar.E"}, {Name: "Gi", Doc: "total inhibitory synaptic conductance -- the net inhibitory input to the neuron -- does *not* include Gbar.I"}, {Name: "Gk", Doc: "total potassium conductance, typically reflecting sodium-gated potassium currents involved in adaptation effects -- does *not* include Gbar.K"}, {Name: "Inet", Doc: "net current produced by all channels -- drives update of Vm"}, {Name: "Vm", Doc: "membrane potential -- integrates Inet current over time"}, {Name: "Noise", Doc: "noise value added to unit (ActNoiseParams determines distribution, and when / where it is added)"}, {Name: "Spike", Doc: "whether neuron has spiked or not (0 or 1), for discrete spiking neurons."}, {Name: "Targ", Doc: "target value: drives learning to produce this activation value"}, {Name: "Ext", Doc: "external input: drives activation of unit from outside influences (e.g., sensory input)"}, {Name: "AvgSS", Doc: "super-short time-scale average of ActLrn activation -- provides the lowest-level time integration -- for spiking this integrates over spikes before subsequent averaging, and it is also useful for rate-code to provide a longer time integral overall"}, {Name: "AvgS", Doc: "short time-scale average of ActLrn activation -- tracks the most recent activation states (integrates over AvgSS values), and represents the plus phase for learning in XCAL algorithms"}, {Name: "AvgM", Doc: "medium time-scale average of ActLrn activation -- integrates over AvgS values, and represents the minus phase for learning in XCAL algorithms"}, {Name: "AvgL", Doc: "long time-scale average of medium-time scale (trial level) activation, used for the BCM-style floating threshold in XCAL"}, {Name: "AvgLLrn", Doc: "how much to learn based on the long-term floating threshold (AvgL) for BCM-style Hebbian learning -- is modulated by level of AvgL itself (stronger Hebbian as average activation goes higher) and optionally the average amount of error experienced in the layer (to retain a common proportionality with the level of error-driven learning across layers)"}, {Name: "AvgSLrn", Doc: "short time-scale activation average that is actually used for learning -- typically includes a small contribution from AvgM in addition to mostly AvgS, as determined by LrnActAvgParams.LrnM -- important to ensure that when unit turns off in plus phase (short time scale), enough medium-phase trace remains so that learning signal doesn't just go all the way to 0, at which point no learning would take place"}, {Name: "ActLrn", Doc: "learning activation value, reflecting *dendritic* activity that is not affected by synaptic depression or adapdation channels which are located near the axon hillock.  This is the what drives the Avg* values that drive learning. Computationally, neurons strongly discount the signals sent to other neurons to provide temporal contrast, but need to learn based on a more stable reflection of their overall inputs in the dendrites."}, {Name: "ActM", Doc: "the activation state at end of third quarter, which is the traditional posterior-cortical minus phase activation"}, {Name: "ActP", Doc: "the activation state at end of fourth quarter, which is the traditional posterior-cortical plus_phase activation"}, {Name: "ActDif", Doc: "ActP - ActM -- difference between plus and minus phase acts -- reflects the individual error gradient for this neuron in standard error-driven learning terms"}, {Name: "ActDel", Doc: "delta activation: change in Act from one cycle to next -- can be useful to track where changes are taking place"}, {Name: "ActQ0", Doc: "the activation state at start of current alpha cycle (same as the state at end of previous cycle)"}, {Name: "ActQ1", Doc: "the activation state at end of first quarter of current alpha cycle"}, {Name: "ActQ2", Doc: "the activation state at end of second quarter of current alpha cycle"}, {Name: "ActAvg", Doc: "average activation (of final plus phase activation state) over long time intervals (time constant = DtPars.AvgTau -- typically 200) -- useful for finding hog units and seeing overall distribution of activation"}, {Name: "Burst", Doc: "5IB bursting activation value, computed by thresholding regular activation"}, {Name: "BurstPrv", Doc: "previous bursting activation -- used for context-based learning"}, {Name: "GiSyn", Doc: "aggregated synaptic inhibition (from Inhib pathways) -- time integral of GiRaw -- this is added with computed FFFB inhibition to get the full inhibition in Gi"}, {Name: "GiSelf", Doc: "total amount of self-inhibition -- time-integrated to avoid oscillations"}, {Name: "ActSent", Doc: "last activation value sent (only send when diff is over threshold)"}, {Name: "GeRaw", Doc: "raw excitatory conductance (net input) received from sending units (send delta's are added to this value)"}, {Name: "GiRaw", Doc: "raw inhibitory conductance (net input) received from sending units (send delta's are added to this value)"}, {Name: "GknaFast", Doc: "conductance of sodium-gated potassium channel (KNa) fast dynamics (M-type) -- produces accommodation / adaptation of firing"}, {Name: "GknaMed", Doc: "conductance of sodium-gated potassium channel (KNa) medium dynamics (Slick) -- produces accommodation / adaptation of firing"}, {Name: "GknaSlow", Doc: "conductance of sodium-gated potassium channel (KNa) slow dynamics (Slack) -- produces accommodation / adaptation of firing"}, {Name: "Gahp", Doc: "proportion of open slow after-hyperpolarization (sAHP) potassium channels, which builds up with activity and decays slowly across trials -- produces firing-rate adaptation with repeated presentations (see Act.AHP)"}, {Name: "Excit", Doc: "learned intrinsic excitability of the neuron, added to the raw excitatory conductance -- adapted as a function of activity by Learn.Excit, and saved with the weights"}, {Name: "ISI", Doc: "current inter-spike-interval -- counts up since last spike.  Starts at -1 when initialized."}, {Name: "ISIAvg", Doc: "average inter-spike-interval -- average time interval between spikes.  Starts at -1 when initialized, and goes to -2 after first spike, and is only valid after the second spike post-initialization."}, {Name: "CtxtGe", Doc: "CtxtGe is context (temporally delayed) excitatory conducances."}, {Name: "CtxtLag", Doc: "CtxtLag is the learned proportion of the prior context retained at each context update in CT layers -- see Ctxt params, saved with the weights"}, {Name: "CtxtDif", Doc: "CtxtDif is the difference between the prior context and the new context input at the last context update, which is the derivative of CtxtGe with respect to the lag"}, {Name: "CtxtDLag", Doc: "CtxtDLag is the error of the neuron with respect to its context lag, for learning CtxtLag in DWt"}, {Name: "ActG", Doc: "gating activation -- the activity value when gating occurred in this pool."}, {Name: "DALrn", Doc: "per-neuron effective learning dopamine value -- gain modulated and sign reversed for D2R"}, {Name: "Shunt", Doc: "shunting input received from Patch neurons (in reality flows through SNc DA pathways)"}, {Name: "Maint", Doc: "maintenance value for Deep layers = sending act at time of gating"}, {Name: "MaintGe", Doc: "maintenance excitatory conductance value for Deep layers"}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NeurFlags", IDName: "neur-flags", Doc: "NeurFlags are bit-flags encoding relevant binary state for neurons"})
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NoveltyParams", IDName: "novelty-params", Doc: "NoveltyParams are the parameters for a [NoveltyLayer], which computes\na novelty (mismatch) signal from the divergence between the activity\nof a hippocampal input layer (e.g., ECin) and the reconstruction of\nthat input driven by CA3 -> CA1 recall (e.g., ECout), as\n1 - cosine of the two activity patterns.  Familiar inputs are\naccurately reconstructed and produce low novelty, while novel inputs\nproduce a large mismatch.  The novelty value is the activation of the\nlayer, which is sent as ACh and / or DA to the SendTo layers, and\ncan also drive a [CINLayer] by including it in the CIN.RewLays.", Fields: []types.Field{{Name: "InLay", Doc: "InLay is the name of the input layer, e.g., ECin,\nwhose activity is compared with the reconstruction."}, {Name: "ReconLay", Doc: "ReconLay is the name of the layer with the reconstructed input\ndriven by hippocampal recall, e.g., ECout, which must have the same\nnumber of units as InLay."}, {Name: "StartCyc", Doc: "StartCyc is the cycle within the trial at which to start computing\nnovelty, prior to which it is 0.  The default of 25 starts after\nthe first quarter, when the CA3 -> CA1 recall drives ECout in the\nstandard hippocampal model.  Novelty is held at its final minus\nphase value during the plus phase, when the reconstruction layer\nis typically clamped to the input."}, {Name: "Thr", Doc: "Thr is the threshold on 1 - cosine below which the input is\nconsidered familiar, with the novelty value renormalized\nto the 0-1 range above this threshold."}, {Name: "Gain", Doc: "Gain is the multiplier on the novelty value, which is\nthen clipped to the 0-1 range."}, {Name: "SendACh", Doc: "SendACh sends the novelty value as ACh to the SendTo layers."}, {Name: "SendDA", Doc: "SendDA sends the novelty value as DA to the SendTo layers."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NWayAssoc", IDName: "n-way-assoc", Doc: "NWayAssoc generates the training and testing pattern tables for lists\nof N-way associations, e.g., A-B-C triplets, generalizing the paired\nassociates of the AB-AC paradigm, for relational memory paradigms on\nthe hippocampus model.  Each element of an association has its own\nrandom pattern in ElemPools pools of the EC layout, in order, and the\nremaining pools have the context of the list.  The training table has\nthe full associations, and the testing table has each association cued\nby each combination of NCue of its elements (any-cue testing), with the\nother elements empty in the Input, and the full association in ECout.\nThe test table has Cue and Resp columns with the names of the cue and\nresponse elements of each row, joined by +, for the cue/response\naccounting of the recall of each response element (see Recall and\nNWayRecall).  Lists can share elements, e.g., A-B-C and A-D-E,\nfor interference paradigms analogous to AB-AC.", Fields: []types.Field{{Name: "On", Doc: "use N-way associations"}, {Name: "NWay", Doc: "number of elements in each association"}, {Name: "NCue", Doc: "number of elements given as the cue on test trials: each association is tested with every combination of NCue of its elements"}, {Name: "NPats", Doc: "number of associations in each list"}, {Name: "ElemPools", Doc: "number of pools of the EC layout for each element"}, {Name: "PctAct", Doc: "proportion of active units in each element pool"}, {Name: "MinDiff", Doc: "minimum proportion of different active units between the patterns of an element"}, {Name: "CtxtFlip", Doc: "proportion of the active units of the context prototype flipped for each association"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NWayRecall", IDName: "n-way-recall", Doc: "NWayRecall accumulates the cue/response accounting of the recall of\neach response element over the test trials of N-way associations\n(see NWayAssoc.Recall), in a Table with one row per cue and response\nelement: Cue, Resp, N (number of trials), NRecall (number recalled),\nand Recall (proportion recalled).", Fields: []types.Field{{Name: "Table", Doc: "Table is the accounting table."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ParamSchedStep", IDName: "param-sched-step", Doc: "ParamSchedStep is a scheduled change of params in a ParamSched:\na params sheet that is applied from the given epoch of each run,\nstarting at the given run.", Fields: []types.Field{{Name: "Run", Doc: "Run is the first run in which the step applies."}, {Name: "Epoch", Doc: "Epoch is the training epoch of each run from which the step applies."}, {Name: "Sheet", Doc: "Sheet is the name of the params sheet to apply,\nin the params sets of the sim."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ParamSched", IDName: "param-sched", Doc: "ParamSched is a schedule of params changes over training (a curriculum\nof params), e.g., reducing the mossy fiber strength after epoch 10,\nwhich are applied automatically at the start of the training epochs\nby LooperParamSched, instead of editing the sim code for each such\nmanipulation.  When the steps in effect change, the base params\n(Base and the ExtraSheets) are applied again, followed by the sheets\nof the steps in effect, in order, using ApplyParamsLive, so the params\nare always those of the steps in effect, including at the start of\neach run, and when resuming from a checkpoint.", Fields: []types.Field{{Name: "Steps", Doc: "Steps are the scheduled params changes."}, {Name: "Current", Doc: "Current are the sheets of the steps in effect, space separated,\nas last applied by Update, for logging."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ParquetWriter", IDName: "parquet-writer", Doc: "ParquetWriter streams the rows of a table to an Apache Parquet file,\nwhich is much smaller and faster to load in analysis tools (pandas,\npolars, arrow, duckdb, R) than a TSV file for large logs.  The rows are\nbuffered and written as a row group every RowGroup rows, after which\nthe file footer is rewritten, so that the file is always a valid Parquet\nfile with all the rows up to the last row group, in case the run crashes.\nThe schema is determined from the columns of the table at the first row:\nfloat64 columns are DOUBLE, float32 are FLOAT, integer and bool columns\nare INT64, and string columns are UTF8 strings, and the cells of\ntensor columns are written as separate columns named Name[i], in row-major\norder.  Values are PLAIN encoded, and compressed with gzip by default.", Fields: []types.Field{{Name: "RowGroup", Doc: "RowGroup is the number of rows per row group, i.e., the number of\nrows that are buffered before writing them to the file."}, {Name: "Uncompressed", Doc: "Uncompressed does not compress the data, which is faster to write."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PartialCue", IDName: "partial-cue", Doc: "PartialCue generates degraded test cues from full patterns, for\nparametric pattern completion curves, by removing a proportion of the\nactive bits chosen at random over the whole pattern, instead of deleting\nwhole pools, and optionally corrupting the cue with the same number of\nrandomly chosen inactive bits turned on (noise).  A bit is active if its\nvalue is > 0, and keeps its value if retained.", Fields: []types.Field{{Name: "Frac", Doc: "Frac is the proportion of the active bits of the full pattern that\nare retained in the cue: 1 = the full pattern."}, {Name: "Corrupt", Doc: "Corrupt turns on randomly chosen inactive bits in place of the\nremoved ones, keeping the number of active bits the same,\nso that the cue is noise-corrupted instead of just partial."}, {Name: "OnVal", Doc: "OnVal is the value for the bits turned on by Corrupt."}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtBalRecvPath", IDName: "wt-bal-recv-path", Doc: "WtBalRecvPath are state variables used in computing the WtBal weight balance function\nThere is one of these for each Recv Neuron participating in the pathway.", Fields: []types.Field{{Name: "Avg", Doc: "average of effective weight values that exceed WtBal.AvgThr across given Recv Neuron's connections for given Path"}, {Name: "Fact", Doc: "overall weight balance factor that drives changes in WbInc vs. WbDec via a sigmoidal function -- this is the net strength of weight balance changes"}, {Name: "Inc", Doc: "weight balance increment factor -- extra multiplier to add to weight increases to maintain overall weight balance"}, {Name: "Dec", Doc: "weight balance decrement factor -- extra multiplier to add to weight decreases to maintain overall weight balance"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Path", IDName: "path", Doc: "Path implements the Leabra algorithm at the synaptic level,\nin terms of a pathway connecting two layers.", Embeds: []types.Field{{Name: "PathBase"}}, Fields: []types.Field{{Name: "Send", Doc: "sending layer for this pathway."}, {Name: "Recv", Doc: "receiving layer for this pathway."}, {Name: "Type", Doc: "type of pathway."}, {Name: "WtInit", Doc: "initial random weight distribution"}, {Name: "WtScale", Doc: "weight scaling parameters: modulates overall strength of pathway,\nusing both absolute and relative factors."}, {Name: "Learn", Doc: "synaptic-level learning parameters"}, {Name: "FromSuper", Doc: "For CTCtxtPath if true, this is the pathway from corresponding\nSuperficial layer.  Should be OneToOne path, with Learn.Learn = false,\nWtInit.Var = 0, Mean = 0.8. These defaults are set if FromSuper = true."}, {Name: "CHL", Doc: "CHL are the parameters for CHL learning. if CHL is On then\nWtSig.SoftBound is automatically turned off, as it is incompatible."}, {Name: "Trace", Doc: "special parameters for matrix trace learning"}, {Name: "Syns", Doc: "synaptic state values, ordered by the sending layer\nunits which owns them -- one-to-one with SConIndex array."}, {Name: "GScale", Doc: "scaling factor for integrating synaptic input conductances (G's).\ncomputed in AlphaCycInit, incorporates running-average activity levels."}, {Name: "GInc", Doc: "local per-recv unit increment accumulator for synaptic\nconductance from sending units. goes to either GeRaw or GiRaw\non neuron depending on pathway type."}, {Name: "CtxtGeInc", Doc: "CtxtGeInc is local per-recv unit accumulator for Ctxt excitatory\nconductance from sending units, Not a delta, the full value."}, {Name: "GeRaw", Doc: "per-recv, per-path raw excitatory input, for GPiThalPath."}, {Name: "WbRecv", Doc: "weight balance state variables for this pathway, one per recv neuron."}, {Name: "RConN", Doc: "number of recv connections for each neuron in the receiving layer,\nas a flat list."}, {Name: "RConNAvgMax", Doc: "average and maximum number of recv connections in the receiving layer."}, {Name: "RConIndexSt", Doc: "starting index into ConIndex list for each neuron in\nreceiving layer; list incremented by ConN."}, {Name: "RConIndex", Doc: "index of other neuron on sending side of pathway,\nordered by the receiving layer's order of units as the\nouter loop (each start is in ConIndexSt),\nand then by the sending layer's units within that."}, {Name: "RSynIndex", Doc: "index of synaptic state values for each recv unit x connection,\nfor the receiver pathway which does not own the synapses,\nand instead indexes into sender-ordered list."}, {Name: "SConN", Doc: "number of sending connections for each neuron in the\nsending layer, as a flat list."}, {Name: "SConNAvgMax", Doc: "average and maximum number of sending connections\nin the sending layer."}, {Name: "SConIndexSt", Doc: "starting index into ConIndex list for each neuron in\nsending layer; list incremented by ConN."}, {Name: "SConIndex", Doc: "index of other neuron on receiving side of pathway,\nordered by the sending layer's order of units as the\nouter loop (each start is in ConIndexSt), and then\nby the sending layer's units within that."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Path", IDName: "path", Doc: "Path implements the Leabra algorithm at the synaptic level,\nin terms of a pathway connecting two layers.", Embeds: []types.Field{{Name: "PathBase"}}, Fields: []types.Field{{Name: "Send", Doc: "sending layer for this pathway."}, {Name: "Recv", Doc: "receiving layer for this pathway."}, {Name: "Type", Doc: "type of pathway."}, {Name: "WtInit", Doc: "initial random weight distribution"}, {Name: "WtScale", Doc: "weight scaling parameters: modulates overall strength of pathway,\nusing both absolute and relative factors."}, {Name: "Learn", Doc: "synaptic-level learning parameters"}, {Name: "FromSuper", Doc: "For CTCtxtPath if true, this is the pathway from corresponding\nSuperficial layer.  Should be OneToOne path, with Learn.Learn = false,\nWtInit.Var = 0, Mean = 0.8. These defaults are set if FromSuper = true."}, {Name: "CHL", Doc: "CHL are the parameters for CHL learning. if CHL is On then\nWtSig.SoftBound is automatically turned off, as it is incompatible."}, {Name: "Trace", Doc: "special parameters for matrix trace learning"}, {Name: "STP", Doc: "STP has the parameters for short-term synaptic plasticity\n(depression and facilitation) of the sending efficacy."}, {Name: "EWC", Doc: "EWC has the parameters for elastic weight consolidation,\nprotecting the synapses important for previous learning."}, {Name: "Syns", Doc: "synaptic state values, ordered by the sending layer\nunits which owns them -- one-to-one with SConIndex array.\nStored in structure-of-arrays form, with a slice per variable."}, {Name: "GScale", Doc: "scaling factor for integrating synaptic input conductances (G's).\ncomputed in AlphaCycInit, incorporates running-average activity levels."}, {Name: "GInc", Doc: "local per-recv unit increment accumulator for synaptic\nconductance from sending units. goes to either GeRaw or GiRaw\non neuron depending on pathway type."}, {Name: "CtxtGeInc", Doc: "CtxtGeInc is local per-recv unit accumulator for Ctxt excitatory\nconductance from sending units, Not a delta, the full value."}, {Name: "GeRaw", Doc: "per-recv, per-path raw excitatory input, for GPiThalPath."}, {Name: "STPu", Doc: "STPu is the short-term plasticity release probability of each\nsending neuron, shared by all of its synapses, only when STP.On."}, {Name: "STPx", Doc: "STPx is the short-term plasticity proportion of available resources\nof each sending neuron, shared by all of its synapses, only when STP.On."}, {Name: "STPSent", Doc: "STPSent is the last activation times STP efficacy sent by each\nsending neuron, for delta-coding the sending when STP.On."}, {Name: "LearnProg", Doc: "LearnProg has learning progress statistics, when\nNetwork.RecLearnProgress is on.  See LogAddLearnProgressItems."}, {Name: "WbRecv", Doc: "weight balance state variables for this pathway, one per recv neuron."}, {Name: "RConN", Doc: "number of recv connections for each neuron in the receiving layer,\nas a flat list."}, {Name: "RConNAvgMax", Doc: "average and maximum number of recv connections in the receiving layer."}, {Name: "RConIndexSt", Doc: "starting index into ConIndex list for each neuron in\nreceiving layer; list incremented by ConN."}, {Name: "RConIndex", Doc: "index of other neuron on sending side of pathway,\nordered by the receiving layer's order of units as the\nouter loop (each start is in ConIndexSt),\nand then by the sending layer's units within that."}, {Name: "RSynIndex", Doc: "index of synaptic state values for each recv unit x connection,\nfor the receiver pathway which does not own the synapses,\nand instead indexes into sender-ordered list."}, {Name: "SConN", Doc: "number of sending connections for each neuron in the\nsending layer, as a flat list."}, {Name: "SConNAvgMax", Doc: "average and maximum number of sending connections\nin the sending layer."}, {Name: "SConIndexSt", Doc: "starting index into ConIndex list for each neuron in\nsending layer; list incremented by ConN."}, {Name: "SConIndex", Doc: "index of other neuron on receiving side of pathway,\nordered by the sending layer's order of units as the\nouter loop (each start is in ConIndexSt), and then\nby the sending layer's units within that."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PathStats", IDName: "path-stats", Doc: "PathStats are summary statistics of the current weights of a pathway,\ncomputed on demand by Path.Stats, for diagnosing learning problems\nwithout having to export the full weights, e.g., weights that are\nstuck at their initial values or saturated at a bound.\nThe weight change norms are those of the LearnProgress, computed over\nthe weight updates up to its last ComputeLearnProgress.", Fields: []types.Field{{Name: "WtMean", Doc: "WtMean is the mean of the effective weights Wt."}, {Name: "WtVar", Doc: "WtVar is the variance of the effective weights Wt."}, {Name: "WtMin", Doc: "WtMin is the minimum effective weight Wt."}, {Name: "WtMax", Doc: "WtMax is the maximum effective weight Wt."}, {Name: "WtHist", Doc: "WtHist is the histogram of the effective weights Wt, as the\nfraction of synapses in each of PathStatsBins bins over the\n[0, 1] range, with values outside the range in the end bins."}, {Name: "SatMin", Doc: "SatMin is the fraction of synapses whose linear weight LWt is\nwithin LearnSatTol of its 0 lower bound."}, {Name: "SatMax", Doc: "SatMax is the fraction of synapses whose linear weight LWt is\nwithin LearnSatTol of its 1 upper bound."}, {Name: "DWtNorm", Doc: "DWtNorm is the LearnProgress DWtNorm: the root-mean-square DWt\nweight change per synapse and update."}, {Name: "WtDeltaNorm", Doc: "WtDeltaNorm is the LearnProgress WtDeltaNorm: the root-mean-square\nnet change in Wt per synapse."}, {Name: "WtBalAvg", Doc: "WtBalAvg is the mean over receiving units of the average weight\nused for weight balance (WtBalRecvPath.Avg), if WtBal is on."}, {Name: "WtBalFact", Doc: "WtBalFact is the mean over receiving units of the weight balance\nfactor (WtBalRecvPath.Fact), which is 0 when the average weights\nare within the balanced range, if WtBal is on."}, {Name: "WtBalInc", Doc: "WtBalInc is the mean over receiving units of the weight balance\nincrement factor (WtBalRecvPath.Inc), which is > 1 when weight\nincreases are boosted (weights too low) and < 1 when decreases\nare (weights too high), and 1 if WtBal is off."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PathTypes", IDName: "path-types", Doc: "PathTypes enumerates all the different types of leabra pathways,\nfor the different algorithm types supported.\nClass parameter styles automatically key off of these types."})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.MatrixParams", IDName: "matrix-params", Doc: "MatrixParams has parameters for Dorsal Striatum Matrix computation.\nThese are the main Go / NoGo gating units in BG driving updating of PFC WM in PBWM.", Fields: []types.Field{{Name: "LearnQtr", Doc: "Quarter(s) when learning takes place, typically Q2 and Q4, corresponding to the PFC GateQtr. Note: this is a bitflag and must be accessed using bitflag.Set / Has etc routines, 32 bit versions."}, {Name: "PatchShunt", Doc: "how much the patch shunt activation multiplies the dopamine values -- 0 = complete shunting, 1 = no shunting -- should be a factor < 1.0"}, {Name: "ShuntACh", Doc: "also shunt the ACh value driven from CIN units -- this prevents clearing of MSNConSpec traces -- more plausibly the patch units directly interfere with the effects of CIN's rather than through ach, but it is easier to implement with ach shunting here."}, {Name: "OutAChInhib", Doc: "how much does the LACK of ACh from the CIN units drive extra inhibition to output-gating Matrix units -- gi += out_ach_inhib * (1-ach) -- provides a bias for output gating on reward trials -- do NOT apply to NoGo, only Go -- this is a key param -- between 0.1-0.3 usu good -- see how much output gating happening and change accordingly"}, {Name: "BurstGain", Doc: "multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)"}, {Name: "DipGain", Doc: "multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.OrthogStats", IDName: "orthog-stats", Doc: "OrthogStats are summary orthogonalization indices for the pattern\npairs in a PatternSeparation table.", Fields: []types.Field{{Name: "NPairs", Doc: "NPairs is the number of pattern pairs."}, {Name: "InSim", Doc: "InSim is the mean similarity of the input pattern pairs."}, {Name: "OutSim", Doc: "OutSim is the mean similarity of the output pattern pairs."}, {Name: "Index", Doc: "Index is the orthogonalization index: 1 - OutSim / InSim,\nwhich is 0 when the outputs are as similar as the inputs,\nand 1 when they are completely orthogonal.\nNaN if InSim <= 0."}, {Name: "Slope", Doc: "Slope is the least-squares slope of OutSim as a function of InSim\nacross pairs, which is less than 1 when more similar inputs are\nseparated more.  NaN if there is no variance in InSim."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CompletionStats", IDName: "completion-stats", Doc: "CompletionStats are the mean values of the columns in a\nPatternCompletion table.", Fields: []types.Field{{Name: "N", Doc: "N is the number of patterns."}, {Name: "CueSim", Doc: "CueSim is the mean similarity of the cues to the targets."}, {Name: "RecallSim", Doc: "RecallSim is the mean similarity of the recalled patterns to the targets."}, {Name: "Completion", Doc: "Completion is the mean completion, over the patterns where it is defined."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovSchedules", IDName: "pavlov-schedules", Doc: "PavlovSchedules are the standard Pavlovian conditioning schedules\ngenerated by PavlovParams, with conditioned stimuli (CS) labeled\nA, B, C, D, and + for trials with the reward (US) and - without."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovParams", IDName: "pavlov-params", Doc: "PavlovParams are the parameters for a standard Pavlovian\nconditioning schedule (see PavlovEnv).", Fields: []types.Field{{Name: "Schedule", Doc: "Schedule is the conditioning schedule."}, {Name: "NCS", Doc: "NCS is the number of conditioned stimulus units in the CS pattern,\nwhich must be at least 4 for PavlovBlocking."}, {Name: "PRew", Doc: "PRew is the probability of the reward on + trials,\nfor partial reinforcement."}, {Name: "AcqTrials", Doc: "AcqTrials is the number of acquisition trials of A+."}, {Name: "ExtTrials", Doc: "ExtTrials is the number of extinction trials of A-."}, {Name: "BlockTrials", Doc: "BlockTrials is the number of trials of each of the AB+ and CD+\ncompounds in the second phase of blocking."}, {Name: "TestTrials", Doc: "TestTrials is the number of test trials of each tested CS,\nat the end of the schedule, which have no reward."}, {Name: "File", Doc: "File is an optional TOML or JSON file of schedules (see\nSavePavlovSchedules), which is opened by OpenFile, and then\nreplaces the built-in Schedule."}, {Name: "FileSchedule", Doc: "FileSchedule is the name of the schedule to use from the File,\nor the first one if empty."}, {Name: "Groups", Doc: "Groups are the trial groups of the schedule from the File,\nset by OpenFile, which are used instead of the built-in Schedule\nif non-empty."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovTrial", IDName: "pavlov-trial", Doc: "PavlovTrial is one trial of a Pavlovian conditioning schedule.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the trial, e.g., AB+, with the CS letters,\nand + for reinforced or - for non-reinforced trials, or no\nsuffix for test trials."}, {Name: "Phase", Doc: "Phase is the phase of the schedule: Acquisition, Extinction,\nBlocking or Test."}, {Name: "CS", Doc: "CS are the indexes of the active conditioned stimuli."}, {Name: "PRew", Doc: "PRew is the probability of the reward."}, {Name: "Test", Doc: "Test is true for test trials, which have no reward,\nand are typically run without learning."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovEnv", IDName: "pavlov-env", Doc: "PavlovEnv is an env.Env that presents the trials of a standard\nPavlovian conditioning schedule, as given by the Params, with the\nconditioned stimuli as a localist CS pattern, and the reward as\nthe Rew state, for use with the RW or TD reward layers.\nEach epoch is one pass through the schedule.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train."}, {Name: "Params", Doc: "Params are the parameters of the schedule."}, {Name: "Seed", Doc: "Seed is the seed for the Rand stream of this environment,\nwhich is seeded with Seed + run in Init."}, {Name: "Rand", Doc: "Rand is the random number stream for the partial reinforcement."}, {Name: "Trials", Doc: "Trials is the list of trials of the schedule, from Params in Init."}, {Name: "Trial", Doc: "Trial is the current trial within the schedule."}, {Name: "Epoch", Doc: "Epoch is the number of complete passes through the schedule."}, {Name: "TrialName", Doc: "TrialName is the name of the current trial."}, {Name: "Phase", Doc: "Phase is the phase of the schedule of the current trial."}, {Name: "Rewarded", Doc: "Rewarded is true if the current trial is rewarded."}, {Name: "CS", Doc: "CS is the localist conditioned stimulus pattern."}, {Name: "Rew", Doc: "Rew is the reward as a 1x1 tensor."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovGroup", IDName: "pavlov-group", Doc: "PavlovGroup is a group of trials in a Pavlovian conditioning schedule,\nwhich presents each of the CS compounds in turn, Trials times.", Fields: []types.Field{{Name: "Phase", Doc: "Phase is the phase of the schedule, e.g., Acquisition or Test."}, {Name: "CS", Doc: "CS are the conditioned stimulus compounds that are presented in\nturn, as letters A, B, C, ... for the CS units, e.g., [\"AB\", \"CD\"]."}, {Name: "PRew", Doc: "PRew is the probability of the reward, which must be 0 for Test groups."}, {Name: "Trials", Doc: "Trials is the number of times the CS compounds are presented."}, {Name: "Test", Doc: "Test is true for test trials, which have no reward,\nand are typically run without learning."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PavlovSchedule", IDName: "pavlov-schedule", Doc: "PavlovSchedule is a named list of trial groups for PavlovEnv,\nwhich can be saved and loaded with SavePavlovSchedules and\nOpenPavlovSchedules, to define new conditioning protocols\nwithout recompiling.", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the schedule."}, {Name: "Groups", Doc: "Groups are the trial groups, in order."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.MatrixParams", IDName: "matrix-params", Doc: "MatrixParams has parameters for Dorsal Striatum Matrix computation.\nThese are the main Go / NoGo gating units in BG driving updating of PFC WM in PBWM.", Fields: []types.Field{{Name: "LearnQtr", Doc: "Quarter(s) when learning takes place, typically Q2 and Q4, corresponding to the PFC GateQtr. Note: this is a bitflag and must be accessed using bitflag.Set / Has etc routines, 32 bit versions."}, {Name: "PatchShunt", Doc: "how much the patch shunt activation multiplies the dopamine values -- 0 = complete shunting, 1 = no shunting -- should be a factor < 1.0"}, {Name: "ShuntACh", Doc: "also shunt the ACh value driven from CIN units -- this prevents clearing of MSNConSpec traces -- more plausibly the patch units directly interfere with the effects of CIN's rather than through ach, but it is easier to implement with ach shunting here."}, {Name: "OutAChInhib", Doc: "how much does the LACK of ACh from the CIN units drive extra inhibition to output-gating Matrix units -- gi += out_ach_inhib * (1-ach) -- provides a bias for output gating on reward trials -- do NOT apply to NoGo, only Go -- this is a key param -- between 0.1-0.3 usu good -- see how much output gating happening and change accordingly"}, {Name: "BurstGain", Doc: "multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)"}, {Name: "DipGain", Doc: "multiplicative gain factor applied to positive (burst) dopamine signals in computing DALrn effect learning dopamine value based on raw DA that we receive (D2R reversal occurs *after* applying Burst based on sign of raw DA)"}, {Name: "ValueGain", Doc: "ValueGain is the excitatory conductance bias per unit of the state\nvalue received from a StateValue [ValueLayer], which is positive for\nGo (D1R) and negative for NoGo (D2R) layers."}, {Name: "CostGain", Doc: "CostGain is the excitatory conductance bias per unit of the effort\ncost received from an EffortCost [ValueLayer], which is positive for\nNoGo (D2R) and negative for Go (D1R) layers."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GateTypes", IDName: "gate-types", Doc: "GateTypes for region of striatum"})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PBWMParams", IDName: "pbwm-params", Doc: "PBWMParams defines the shape of the outer pool dimensions of gating layers,\norganized into Maint and Out subsets which are arrayed along the X axis\nwith Maint first (to the left) then Out.  Individual layers may only\nrepresent Maint or Out subsets of this overall shape, but all need\nto have this coordinated shape information to be able to share gating\nstate information.  Each layer represents gate state information in\ntheir native geometry -- FullIndex1D provides access from a subset\nto full set.", Fields: []types.Field{{Name: "Type", Doc: "Type of gating layer"}, {Name: "DaR", Doc: "dominant type of dopamine receptor -- D1R for Go pathway, D2R for NoGo"}, {Name: "Y", Doc: "overall shape dimensions for the full set of gating pools,\ne.g., as present in the Matrix and GPiThal levels"}, {Name: "MaintX", Doc: "how many pools in the X dimension are Maint gating pools -- rest are Out"}, {Name: "OutX", Doc: "how many pools in the X dimension are Out gating pools -- comes after Maint"}, {Name: "MaintN", Doc: "For the Matrix layers, this is the number of Maint Pools in X outer\ndimension of 4D shape -- Out gating after that. Note: it is unclear\nhow this relates to MaintX, but it is different in SIR model."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StripeRoles", IDName: "stripe-roles", Doc: "StripeRoles are the functional roles of a set of PFC stripes,\nwhich determine the effects of BG gating on the PFC deep layer."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StripeSpec", IDName: "stripe-spec", Doc: "StripeSpec specifies one set of PFC stripes (pools along the X axis)\nwith a common gating role.  A list of StripeSpecs defines the full\nX dimension of the gating layers (Matrix, GPiThal), in order,\nwith one PFC layer (super and deep) for each set.", Fields: []types.Field{{Name: "Name", Doc: "Name of the stripe set, appended to the PFC prefix for the\nPFC layer names (e.g., mnt for PFCmnt, PFCmntD)."}, {Name: "Role", Doc: "Role is the gating role of the stripes."}, {Name: "N", Doc: "N is the number of stripes (pools) along the X axis."}, {Name: "MaxMaint", Doc: "MaxMaint overrides the PFCMaint.MaxMaint maximum duration of\nmaintenance for the deep layer if > 0.  The default is 100\nfor MaintStripe and 1 for OutStripe and InputStripe."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PBWMParams", IDName: "pbwm-params", Doc: "PBWMParams defines the shape of the outer pool dimensions of gating layers,\norganized into Maint and Out subsets which are arrayed along the X axis\nwith Maint first (to the left) then Out.  Individual layers may only\nrepresent Maint or Out subsets of this overall shape, but all need\nto have this coordinated shape information to be able to share gating\nstate information.  Each layer represents gate state information in\ntheir native geometry -- FullIndex1D provides access from a subset\nto full set.  Alternatively, the X axis can be organized into any\nnumber of stripe sets with different roles, using SetStripes,\nin which case StripeIndex1D provides access from a stripe set\nto the full set.", Fields: []types.Field{{Name: "Type", Doc: "Type of gating layer"}, {Name: "DaR", Doc: "dominant type of dopamine receptor -- D1R for Go pathway, D2R for NoGo"}, {Name: "Y", Doc: "overall shape dimensions for the full set of gating pools,\ne.g., as present in the Matrix and GPiThal levels"}, {Name: "MaintX", Doc: "how many pools in the X dimension are Maint gating pools -- rest are Out"}, {Name: "OutX", Doc: "how many pools in the X dimension are Out gating pools -- comes after Maint"}, {Name: "MaintN", Doc: "For the Matrix layers, this is the number of Maint Pools in X outer\ndimension of 4D shape -- Out gating after that. Note: it is unclear\nhow this relates to MaintX, but it is different in SIR model.\nNot used if Stripes are set."}, {Name: "Stripes", Doc: "Stripes, if set, are the stripe sets arrayed along the X axis in\norder, in place of the Maint and Out subsets (see SetStripes)."}, {Name: "Stripe", Doc: "Stripe is the index into Stripes of the stripe set represented\nby this layer, for PFC layers when Stripes are set."}, {Name: "GateNoise", Doc: "GateNoise configures independent random number streams for the\nAct.Noise in each stripe of Matrix and GPiThal layers."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GateState", IDName: "gate-state", Doc: "GateState is gating state values stored in layers that receive thalamic gating signals\nincluding MatrixLayer, PFCLayer, GPiThal layer, etc -- use GateLayer as base layer to include.", Fields: []types.Field{{Name: "Act", Doc: "gating activation value, reflecting thalamic gating layer activation at time of gating (when Now = true) -- will be 0 if gating below threshold for this pool, and prior to first Now for AlphaCycle"}, {Name: "Now", Doc: "gating timing signal -- true if this is the moment when gating takes place"}, {Name: "Cnt", Doc: "unique to each layer -- not copied.  Generally is a counter for interval between gating signals -- starts at -1, goes to 0 at first gating, counts up from there for subsequent gating.  Can be reset back to -1 when gate is reset (e.g., output gating) and counts down from -1 while not gating."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GPiGateParams", IDName: "g-pi-gate-params", Doc: "GPiGateParams has gating parameters for gating in GPiThal layer, including threshold.", Fields: []types.Field{{Name: "GateQtr", Doc: "GateQtr is the Quarter(s) when gating takes place, typically Q1 and Q3,\nwhich is the quarter prior to the PFC GateQtr when deep layer updating\ntakes place. Note: this is a bitflag and must be accessed using bitflag.\nSet / Has etc routines, 32 bit versions."}, {Name: "Cycle", Doc: "Cycle within Qtr to determine if activation over threshold for gating.\nWe send GateState updates on this cycle either way."}, {Name: "GeGain", Doc: "extra netinput gain factor to compensate for reduction in Ge from subtracting away NoGo -- this is *IN ADDITION* to adding the NoGo factor as an extra gain: Ge = (GeGain + NoGo) * (GoIn - NoGo * NoGoIn)"}, {Name: "NoGo", Doc: "how much to weight NoGo inputs relative to Go inputs (which have an implied weight of 1 -- this also up-scales overall Ge to compensate for subtraction"}, {Name: "Thr", Doc: "threshold for gating, applied to activation -- when any GPiThal unit activation gets above this threshold, it counts as having gated, driving updating of GateState which is broadcast to other layers that use the gating signal"}, {Name: "ThrAct", Doc: "Act value of GPiThal unit reflects gating threshold: if below threshold, it is zeroed -- see ActLrn for underlying non-thresholded activation"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GPiGateParams", IDName: "g-pi-gate-params", Doc: "GPiGateParams has gating parameters for gating in GPiThal layer, including threshold.", Fields: []types.Field{{Name: "GateQtr", Doc: "GateQtr is the Quarter(s) when gating takes place, typically Q1 and Q3,\nwhich is the quarter prior to the PFC GateQtr when deep layer updating\ntakes place. Note: this is a bitflag and must be accessed using bitflag.\nSet / Has etc routines, 32 bit versions."}, {Name: "Cycle", Doc: "Cycle within Qtr to determine if activation over threshold for gating.\nWe send GateState updates on this cycle either way."}, {Name: "GeGain", Doc: "extra netinput gain factor to compensate for reduction in Ge from subtracting away NoGo -- this is *IN ADDITION* to adding the NoGo factor as an extra gain: Ge = (GeGain + NoGo) * (GoIn - NoGo * NoGoIn)"}, {Name: "NoGo", Doc: "how much to weight NoGo inputs relative to Go inputs (which have an implied weight of 1 -- this also up-scales overall Ge to compensate for subtraction"}, {Name: "Thr", Doc: "threshold for gating, applied to activation -- when any GPiThal unit activation gets above this threshold, it counts as having gated, driving updating of GateState which is broadcast to other layers that use the gating signal"}, {Name: "ThrAct", Doc: "Act value of GPiThal unit reflects gating threshold: if below threshold, it is zeroed -- see ActLrn for underlying non-thresholded activation"}, {Name: "UpperClear", Doc: "UpperClear clears the established maintenance in the PFC maintenance\nlayers gated by this layer, when a higher-level GPiThal layer that\nincludes this layer in its SendTo does maintenance gating, for\nhierarchical PBWM where updating the higher-level context resets\nthe lower-level working memory (see ConnectPBWMLevels)."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.CINParams", IDName: "cin-params", Doc: "CINParams (cholinergic interneuron) reads reward signals from named source layer(s)\nand sends the Max absolute value of that activity as the positively rectified\nnon-prediction-discounted reward signal computed by CINs, and sent as\nan acetylcholine (ACh) signal.\nTo handle positive-only reward signals, need to include both a reward prediction\nand reward outcome layer.", Fields: []types.Field{{Name: "RewThr", Doc: "RewThr is the threshold on reward values from RewLays,\nto count as a significant reward event, which then drives maximal ACh.\nSet to 0 to disable this nonlinear behavior."}, {Name: "RewLays", Doc: "Reward-representing layer(s) from which this computes ACh as Max absolute value"}}})
 
@@ -110,14 +270,114 @@ var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PFCDyn
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PFCDyns", IDName: "pfc-dyns", Doc: "PFCDyns is a slice of dyns. Provides deterministic control over PFC\nmaintenance dynamics -- the rows of PFC units (along Y axis) behave\naccording to corresponding index of Dyns.\nensure layer Y dim has even multiple of len(Dyns)."})
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GateNoiseParams", IDName: "gate-noise-params", Doc: "GateNoiseParams configure independent, seeded random number streams\nfor the Act.Noise in each stripe (pool) of the Matrix and GPiThal\ngating layers, so that the gating noise in each stripe does not depend\non any other use of random numbers, and specific gating outcomes can be\nreproduced from the recorded GateSeeds (see Network.AllGateSeeds).", Fields: []types.Field{{Name: "On", Doc: "On uses a separate random number stream for the noise in each\nstripe, instead of the layer Rand stream."}, {Name: "Seed", Doc: "Seed is the base seed from which the seed for each stripe is\nderived, along with the layer name and pool index.  If 0, a new\nbase seed is drawn from the network Rand in InitWeights,\nso the streams differ across runs."}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.TraceParams", IDName: "trace-params", Doc: "Params for for trace-based learning in the MatrixTracePath", Fields: []types.Field{{Name: "NotGatedLR", Doc: "learning rate for all not-gated stripes, which learn in the opposite direction to the gated stripes, and typically with a slightly lower learning rate -- although there are different learning logics associated with each of these different not-gated cases, in practice the same learning rate for all works best, and is simplest"}, {Name: "GateNoGoPosLR", Doc: "learning rate for gated, NoGo (D2), positive dopamine (weights decrease) -- this is the single most important learning parameter here -- by making this relatively small (but non-zero), an asymmetry in the role of Go vs. NoGo is established, whereby the NoGo pathway focuses largely on punishing and preventing actions associated with negative outcomes, while those assoicated with positive outcomes only very slowly get relief from this NoGo pressure -- this is critical for causing the model to explore other possible actions even when a given action SOMETIMES produces good results -- NoGo demands a very high, consistent level of good outcomes in order to have a net decrease in these avoidance weights.  Note that the gating signal applies to both Go and NoGo MSN's for gated stripes, ensuring learning is about the action that was actually selected (see not_ cases for logic for actions that were close but not taken)"}, {Name: "AChDecay", Doc: "decay driven by receiving unit ACh value, sent by CIN units, for reseting the trace"}, {Name: "Decay", Doc: "multiplier on trace activation for decaying prior traces -- new trace magnitude drives decay of prior trace -- if gating activation is low, then new trace can be low and decay is slow, so increasing this factor causes learning to be more targeted on recent gating changes"}, {Name: "Deriv", Doc: "use the sigmoid derivative factor 2 * act * (1-act) in modulating learning -- otherwise just multiply by msn activation directly -- this is generally beneficial for learning to prevent weights from continuing to increase when activations are already strong (and vice-versa for decreases)"}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Pool", IDName: "pool", Doc: "Pool contains computed values for FFFB inhibition, and various other state values for layers\nand pools (unit groups) that can be subject to inhibition, including:\n* average / max stats on Ge and Act that drive inhibition\n* average activity overall that is used for normalizing netin (at layer level)", Fields: []types.Field{{Name: "StIndex", Doc: "starting and ending (exlusive) indexes for the list of neurons in this pool"}, {Name: "EdIndex", Doc: "starting and ending (exlusive) indexes for the list of neurons in this pool"}, {Name: "Inhib", Doc: "FFFB inhibition computed values, including Ge and Act AvgMax which drive inhibition"}, {Name: "ActM", Doc: "minus phase average and max Act activation values, for ActAvg updt"}, {Name: "ActP", Doc: "plus phase average and max Act activation values, for ActAvg updt"}, {Name: "ActAvg", Doc: "running-average activation levels used for netinput scaling and adaptive inhibition"}, {Name: "Gate", Doc: "\tGate is gating state for PBWM layers"}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Pool", IDName: "pool", Doc: "Pool contains computed values for FFFB inhibition, and various other state values for layers\nand pools (unit groups) that can be subject to inhibition, including:\n* average / max stats on Ge and Act that drive inhibition\n* average activity overall that is used for normalizing netin (at layer level)", Fields: []types.Field{{Name: "StIndex", Doc: "starting and ending (exlusive) indexes for the list of neurons in this pool"}, {Name: "EdIndex", Doc: "starting and ending (exlusive) indexes for the list of neurons in this pool"}, {Name: "Inhib", Doc: "FFFB inhibition computed values, including Ge and Act AvgMax which drive inhibition"}, {Name: "ActM", Doc: "minus phase average and max Act activation values, for ActAvg updt"}, {Name: "ActP", Doc: "plus phase average and max Act activation values, for ActAvg updt"}, {Name: "ActAvg", Doc: "running-average activation levels used for netinput scaling and adaptive inhibition"}, {Name: "Gate", Doc: "\tGate is gating state for PBWM layers"}, {Name: "PredErr", Doc: "PredErr is the prediction error of Pulvinar layers with SendAttn\nlayers: the mean absolute difference between plus and minus phase\nactivations, integrated over trials by Attn.Tau."}, {Name: "Attn", Doc: "Attn is the attentional modulation of the gain of the excitatory\ninput of the units in this pool, which is multiplied by 1 + Attn,\nfrom the PredErr of Pulvinar layers with this layer in SendAttn."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ActAvg", IDName: "act-avg", Doc: "ActAvg are running-average activation levels used for netinput scaling and adaptive inhibition", Fields: []types.Field{{Name: "ActMAvg", Doc: "running-average minus-phase activity -- used for adapting inhibition -- see ActAvgParams.Tau for time constant etc"}, {Name: "ActPAvg", Doc: "running-average plus-phase activity -- used for synaptic input scaling -- see ActAvgParams.Tau for time constant etc"}, {Name: "ActPAvgEff", Doc: "ActPAvg * ActAvgParams.Adjust -- adjusted effective layer activity directly used in synaptic input scaling"}}})
 
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PopCodeParams", IDName: "pop-code-params", Doc: "PopCodeParams are parameters for encoding continuous values as\npopulation codes over the units of a layer, and decoding them back\nfrom the layer activity, using the emergent popcode package.\nEach pool of a 4D layer encodes one value, so a vector of values\ncan be presented in one layer, and a 2D layer encodes one value over\nall of its units.  With Is2D, each value is a 2D (X, Y) value encoded\nover the Y, X units of the pool, otherwise it is a scalar encoded\nover the units of the pool in order.  See Layer.ApplyValues and\nDecodeValues, which replace the ad hoc mixing of binary patterns\nfor magnitudes.  A Rew layer with PopCode.On provides a graded\nreward magnitude to the RW and TD reward layers, via RewValue.", Fields: []types.Field{{Name: "On", Doc: "use population coding of values in this layer"}, {Name: "Is2D", Doc: "encode 2D (X, Y) values over the Y, X units of each pool using TwoD, instead of scalar values using OneD"}, {Name: "OneD", Doc: "population code for scalar values"}, {Name: "TwoD", Doc: "population code for 2D values"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Provenance", IDName: "provenance", Doc: "Provenance records what actually ran in a run of a sim, to be able to\nreconstruct it long after the fact: the version of the sim code, the\ncommand-line args, the random seeds, the param sets applied and the\nfinal parameter values, the config, and the network architecture.\nCreate with Network.NewProvenance at the start of the run, and Save\nas a JSON sidecar file next to the log files (see ProvenanceFilename).", Fields: []types.Field{{Name: "Name", Doc: "Name is the name of the network."}, {Name: "RunName", Doc: "RunName is the name of the run, used for the log files\n(see emer.NetParams.RunName)."}, {Name: "Start", Doc: "Start is the time at which the run started."}, {Name: "End", Doc: "End is the time at which the run ended, or zero if not finished."}, {Name: "Args", Doc: "Args are the command-line args of the sim (os.Args)."}, {Name: "Dir", Doc: "Dir is the working directory of the sim."}, {Name: "Host", Doc: "Host is the host name of the machine."}, {Name: "GoVersion", Doc: "GoVersion is the version of Go that the sim was built with."}, {Name: "Module", Doc: "Module is the module path of the sim main package."}, {Name: "Version", Doc: "Version is the module version of the sim main package,\nwhich is (devel) if not built from a tagged module."}, {Name: "Revision", Doc: "Revision is the git commit of the sim code, from the build info,\nor from git in the working directory if not recorded in the build\n(e.g., with go run)."}, {Name: "RevisionTime", Doc: "RevisionTime is the time of the Revision commit, if known."}, {Name: "Modified", Doc: "Modified is true if the sim code had uncommitted changes\nrelative to the Revision."}, {Name: "Deps", Doc: "Deps are the versions of the module dependencies,\nas \"path version\", e.g., of leabra and emergent."}, {Name: "NetSeed", Doc: "NetSeed is the random seed of the network (Network.RandSeed)."}, {Name: "Seeds", Doc: "Seeds are the random seeds of the sim, e.g., of each run."}, {Name: "GateSeeds", Doc: "GateSeeds are the seeds of the PBWM gating noise, if any\n(see Network.AllGateSeeds)."}, {Name: "Sheets", Doc: "Sheets are the names of the param sheets applied, in order."}, {Name: "Tag", Doc: "Tag is the additional tag of the param sets (emer.NetParams.Tag)."}, {Name: "Params", Doc: "Params are the full param sets of the sim."}, {Name: "AllParams", Doc: "AllParams are the final values of all the parameters of the layers\nand pathways in the network, after applying the Params (see AllParams)."}, {Name: "Config", Doc: "Config is the config of the sim."}, {Name: "Arch", Doc: "Arch is the listing of the network architecture (see ArchReport)."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.UnitActStats", IDName: "unit-act-stats", Doc: "UnitActStats accumulates the mean and variance of the minus phase\nactivity (ActM) of each unit in a layer across trials, for\nidentifying units to prune.  Call Record at the end of each trial,\ntypically over a full epoch of testing trials.", Fields: []types.Field{{Name: "N", Doc: "N is the number of trials recorded."}, {Name: "Sum", Doc: "Sum is the sum of activity for each unit."}, {Name: "SumSq", Doc: "SumSq is the sum of squared activity for each unit."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PruneParams", IDName: "prune-params", Doc: "PruneParams are the parameters for pruning units.", Fields: []types.Field{{Name: "Thr", Doc: "Thr is the threshold on the contribution of a unit, relative to the\nmean contribution across the units of the layer, below which units\nare pruned.  The contribution is the standard deviation of the unit's\nactivity times the norm of its outgoing effective weights, which\nestimates how much it drives variation in the receiving units.\nFor a layer with no sending pathways, only the activity is used."}, {Name: "MaxProp", Doc: "MaxProp is the maximum proportion of the units in the layer\nthat can be pruned, with the lowest contribution units pruned first."}, {Name: "Compensate", Doc: "Compensate adjusts the weights of the remaining sending units to\neach receiving unit to preserve the mean input from the pruned units,\nin proportion to the mean activity of the remaining units."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.PruneReport", IDName: "prune-report", Doc: "PruneReport is the result of pruning a set of layers,\nwith the performance before and after pruning.", Fields: []types.Field{{Name: "Layers", Doc: "Layers are the names of the pruned layers."}, {Name: "NUnits", Doc: "NUnits is the number of units in each layer."}, {Name: "Pruned", Doc: "Pruned are the indexes of the pruned units in each layer."}, {Name: "Before", Doc: "Before is the performance prior to pruning."}, {Name: "After", Doc: "After is the performance after pruning."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RasterRecorder", IDName: "raster-recorder", Doc: "RasterRecorder records the activity of selected layers at every cycle\nduring headless (nogui) runs, as a spike raster / activity heatmap that\ncan be visualized after the fact (see OpenRaster), as the NetView does\nlive.  The records are streamed to a compact gzip compressed binary\nfile, with options to downsample in time (Every), space (PoolAvg)\nand precision (Bits), so whole runs can be recorded.", Fields: []types.Field{{Name: "Layers", Doc: "Layers are the names of the layers to record."}, {Name: "Var", Doc: "Var is the neuron variable to record."}, {Name: "Every", Doc: "Every records only every this many cycles, downsampling in time."}, {Name: "PoolAvg", Doc: "PoolAvg records the average value over the units of each pool of\n4D layers, instead of each unit, downsampling in space."}, {Name: "Bits", Doc: "Bits is the precision of the recorded values: 8 for values\nquantized to 256 levels of the [0, 1] range (clipped), which is\nsufficient for activations, or 32 for float32 values."}, {Name: "File", Doc: "File is the name of the raster file."}, {Name: "NRecs", Doc: "NRecs is the number of records written so far."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Raster", IDName: "raster", Doc: "Raster is the activity of a set of layers recorded by a RasterRecorder,\nread from a raster file by OpenRaster.", Fields: []types.Field{{Name: "Var", Doc: "Var is the neuron variable recorded."}, {Name: "Every", Doc: "Every is the number of cycles between records."}, {Name: "Bits", Doc: "Bits is the precision of the recorded values: 8 or 32."}, {Name: "Layers", Doc: "Layers are the names of the recorded layers."}, {Name: "Shapes", Doc: "Shapes are the shapes of the recorded values of each layer,\ni.e., the pools for PoolAvg."}, {Name: "Labels", Doc: "Labels are the labels of each record (e.g., the trial name)."}, {Name: "Cycles", Doc: "Cycles are the cycles of each record."}, {Name: "Data", Doc: "Data are the recorded values of each layer, as a [Rec][Unit]\ntensor, i.e., a heatmap of the activity of the units over time,\nwith the units in the order of the Shapes."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ReadoutTypes", IDName: "readout-types", Doc: "ReadoutTypes are the types of readout transforms that can be applied\nto unit values, e.g., for computing memory or decoding statistics."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ReadoutParams", IDName: "readout-params", Doc: "ReadoutParams specify a readout transform of unit values,\nsuch as thresholding, top-k binarization or normalization,\nwhich is applied using Layer.UnitValuesReadout.", Fields: []types.Field{{Name: "Type", Doc: "type of readout transform to apply"}, {Name: "Thr", Doc: "threshold: for ThreshReadout, values above this are 1 and the rest 0;\nfor the other types, values at or below this are set to 0"}, {Name: "K", Doc: "number of units to set to 1 for TopKReadout (per pool if Pool is set)"}, {Name: "Pool", Doc: "apply the transform separately within each sub-pool of a 4D layer,\ninstead of across the layer as a whole"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ReadoutMemStats", IDName: "readout-mem-stats", Doc: "ReadoutMemStats are binary memory statistics comparing a readout\nof the actual activity pattern against a target pattern, as used for\nmeasuring pattern completion in the hippocampus, where a cue pattern\nhas some of the target units missing, and these must be completed.\nThe patterns are typically obtained using Layer.UnitValuesReadout,\nand values > 0 are counted as on.", Fields: []types.Field{{Name: "TrgOnWasOffAll", Doc: "proportion of target-on units that were off in the activity pattern, for all units"}, {Name: "TrgOnWasOffCmp", Doc: "proportion of target-on units that were off in the activity pattern,\nonly for those that required completion because they were off in the cue"}, {Name: "TrgOffWasOn", Doc: "proportion of target-off units that were on in the activity pattern"}, {Name: "CmpN", Doc: "number of target-on units that were off in the cue, requiring completion"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RetentionTypes", IDName: "retention-types", Doc: "RetentionTypes are the kinds of delay epochs run between training and\nthe delayed test sessions of a retention interval (see RetentionParams)."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RetentionParams", IDName: "retention-params", Doc: "RetentionParams are parameters for testing the retention of learned\nitems over a series of retention intervals after training, to measure\nforgetting curves within one run: after training, the items are tested\nimmediately (delay 0), and then again after each of the cumulative\nnumbers of Delays epochs, during which the network is run with the\ngiven Type of delay trials, with learning if Learn.  The test results\nat each delay are recorded in a RetentionCurve.", Fields: []types.Field{{Name: "On", Doc: "run retention interval tests after training"}, {Name: "Type", Doc: "kind of delay trials: no input, noise input, or an interfering list"}, {Name: "Delays", Doc: "cumulative numbers of delay epochs after training at which the items are tested again, in increasing order; if empty, 1, 2, 5, 10"}, {Name: "PctAct", Doc: "proportion of active units in the RetainNoise patterns"}, {Name: "Learn", Doc: "learn during the delay trials"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RetentionCurve", IDName: "retention-curve", Doc: "RetentionCurve records the forgetting curves measured with\nRetentionParams, in a Table with a row for each test, with the Run,\nthe cumulative Delay in epochs, and the recorded test stats.", Fields: []types.Field{{Name: "Table", Doc: "Table is the forgetting curve table."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RetrievalDynamics", IDName: "retrieval-dynamics", Doc: "RetrievalDynamics decodes the time course of memory retrieval within\na trial, by comparing the activity of a layer at every cycle with a\nset of stored reference patterns (e.g., the layer's activity on each\ntraining trial), using cosine similarity.  For each cycle it records\nthe similarity to the target reference (the one for the current trial),\nthe maximum similarity to any other reference (the strongest\ncompetitor, e.g., the paired item from the other list), and whether\nthe target is the nearest.  These are averaged across trials of the\nsame type (e.g., ab, ac, lure) to produce retrieval dynamics curves.\nCall StartTrial at the start of each trial, RecordCycle after each\ncycle, and EndTrial at the end of the trial.", Fields: []types.Field{{Name: "Layer", Doc: "Layer is the name of the layer to decode."}, {Name: "Var", Doc: "Var is the neuron variable to decode (Act by default)."}, {Name: "Refs", Doc: "Refs are the stored reference patterns."}, {Name: "Target", Doc: "Target is the name of the target reference for the current trial."}, {Name: "TargetCos", Doc: "TargetCos is the cosine similarity to the Target for each cycle\nof the current trial (0 if the target is not in Refs)."}, {Name: "OtherCos", Doc: "OtherCos is the maximum cosine similarity to any reference other\nthan the Target for each cycle of the current trial."}, {Name: "Nearest", Doc: "Nearest is the name of the nearest reference for each cycle\nof the current trial."}, {Name: "Types", Doc: "Types are the trial types in the order first recorded by EndTrial."}, {Name: "Curves", Doc: "Curves are the accumulated curves for each trial type."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RetrievalCurve", IDName: "retrieval-curve", Doc: "RetrievalCurve has the sums of the per-cycle retrieval stats\nover trials of a given type, for computing the mean curves.", Fields: []types.Field{{Name: "N", Doc: "N is the number of trials for each cycle."}, {Name: "TargetCos", Doc: "TargetCos is the sum of the target cosine for each cycle."}, {Name: "OtherCos", Doc: "OtherCos is the sum of the maximum other cosine for each cycle."}, {Name: "Correct", Doc: "Correct is the number of trials where the target was the nearest, for each cycle."}}})
+
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.RWParams", IDName: "rw-params", Fields: []types.Field{{Name: "PredRange", Doc: "PredRange is the range of predictions that can be represented by the [RWRewPredLayer].\nHaving a truncated range preserves some sensitivity in dopamine at the extremes\nof good or poor performance."}, {Name: "RewLay", Doc: "RewLay is the reward layer name, for [RWDaLayer], from which DA is obtained.\nIf nothing clamped, no dopamine computed."}, {Name: "PredLay", Doc: "PredLay is the name of [RWPredLayer] layer, for [RWDaLayer], that is used for\nsubtracting prediction from the reward value."}}})
 
 var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.TDParams", IDName: "td-params", Doc: "TDParams are params for TD temporal differences computation.", Fields: []types.Field{{Name: "Discount", Doc: "discount factor -- how much to discount the future prediction from RewPred."}, {Name: "PredLay", Doc: "name of [TDPredLayer] to get reward prediction from."}, {Name: "IntegLay", Doc: "name of [TDIntegLayer] from which this computes the temporal derivative."}}})
 
-var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Synapse", IDName: "synapse", Doc: "leabra.Synapse holds state for the synaptic connection between neurons", Fields: []types.Field{{Name: "Wt", Doc: "synaptic weight value, sigmoid contrast-enhanced version\nof the linear weight LWt."}, {Name: "LWt", Doc: "linear (underlying) weight value, which learns according\nto the lrate specified in the connection spec.\nThis is converted into the effective weight value, Wt,\nvia sigmoidal contrast enhancement (see WtSigParams)."}, {Name: "DWt", Doc: "change in synaptic weight, driven by learning algorithm."}, {Name: "Norm", Doc: "DWt normalization factor, reset to max of abs value of DWt,\ndecays slowly down over time. Serves as an estimate of variance\nin weight changes over time."}, {Name: "Moment", Doc: "momentum, as time-integrated DWt changes, to accumulate a\nconsistent direction of weight change and cancel out\ndithering contradictory changes."}, {Name: "Scale", Doc: "scaling parameter for this connection: effective weight value\nis scaled by this factor in computing G conductance.\nThis is useful for topographic connectivity patterns e.g.,\nto enforce more distant connections to always be lower in magnitude\nthan closer connections.  Value defaults to 1 (cannot be exactly 0,\notherwise is automatically reset to 1; use a very small number to\napproximate 0). Typically set by using the paths.Pattern Weights()\nvalues where appropriate."}, {Name: "NTr", Doc: "NTr is the new trace, which drives updates to trace value.\nsu * (1-ru_msn) for gated, or su * ru_msn for not-gated (or for non-thalamic cases)."}, {Name: "Tr", Doc: "Tr is the current ongoing trace of activations, which drive learning.\nAdds NTr and clears after learning on current values, and includes both\nthal gated (+ and other nongated, - inputs)."}}})
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SaliencyModes", IDName: "saliency-modes", Doc: "SaliencyModes are the ways of perturbing input units for [SaliencyParams]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SaliencyParams", IDName: "saliency-params", Doc: "SaliencyParams are the parameters for a gradient-free saliency map of\nthe input units for a given test item, computed by perturbing each input\nunit (or pool of units) in turn, and measuring the change in an output\nstat (e.g., the cosine of the output with the target, or the memory\nscore), without requiring any gradients.", Fields: []types.Field{{Name: "Mode", Doc: "Mode is how the input units are perturbed."}, {Name: "ByPool", Doc: "ByPool perturbs all of the units in each pool together, for 4D inputs,\nto measure the saliency of each pool (e.g., the item vs. context pools\nof the hippocampus EC input), which is the same for all units in the pool."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SaliencyMaps", IDName: "saliency-maps", Doc: "SaliencyMaps collects the saliency maps for a set of test items,\nin a table with a Name column and a Saliency tensor column with\nthe shape of the input, for visualization in a table view (grid),\nalong with the Stat for the intact input.", Embeds: []types.Field{{Name: "SaliencyParams"}}, Fields: []types.Field{{Name: "Table", Doc: "Table has the saliency maps, one row per item."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SensitivityProbe", IDName: "sensitivity-probe", Doc: "SensitivityProbe measures the contribution of each layer to the outputs\nof the network for the current test item, by perturbing the settled\nactivity of each layer in turn, within the same trial and without any\nlearning, and measuring the resulting change in the output layers.\nIt is a cheap, gradient-like complement to the full AblationSweep,\nwhich does not require rerunning the test battery for each layer.\n\nEach layer is held at its settled activity scaled by 1 +/- Delta for\nNCycles cycles of further settling of the rest of the network, and the\ncontribution score is the central difference of the output measure\nbetween the two perturbations, divided by 2 * Delta, i.e., the\nderivative of the output with respect to the gain of the layer.\nWithout a stat function, the output measure is the activity of the\noutput units, and the score is the mean absolute change in their\nactivity; with a stat function (e.g., the cosine of the output with\nits target), the score is the signed change in the stat.\nThe network state is restored after each perturbation, so the trial\ncan continue as if the probe had not been run.", Fields: []types.Field{{Name: "Layers", Doc: "Layers are the names of the layers to perturb.  If empty, all\nlayers that are not Off or Outputs are perturbed."}, {Name: "Outputs", Doc: "Outputs are the names of the output layers whose activity is\nmeasured.  If empty, all Target and Compare layers are used."}, {Name: "Delta", Doc: "Delta is the proportional change in the settled activity of\nthe perturbed layer, which should be small."}, {Name: "NCycles", Doc: "NCycles is the number of cycles of settling with each\nperturbation before measuring the outputs."}, {Name: "Results", Doc: "Results is the contribution table, with a Name column for the\ntest item and a column for the score of each perturbed layer,\none row per probe."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Server", IDName: "server", Doc: "Server is an optional HTTP server for the remote control of a sim\nrunning headlessly, e.g., from a Python or Julia notebook, or from\ncluster orchestration, with a small REST API using JSON:\n\n\tPOST /init                          initialize the sim (Init)\n\tPOST /params?sheet=Name             apply a params sheet by name,\n\tPOST /params  {\"Sel:Path\": \"val\"}   or the params in the body, live\n\tPOST /step?mode=Train&time=Trial&n=1  step the loops\n\tGET  /counters                      current mode and loop counters\n\tGET  /stats?names=SSE,TrlErr        stats, all if no names\n\tGET  /layer?name=Hidden&var=Act     layer unit values and shape\n\tPOST /weights/save?file=net.wts.gz  save weights (.wtb for binary)\n\tPOST /weights/open?file=net.wts.gz  open weights (.wtb for binary)\n\nThe weights files are relative to WeightsDir, and names that are\nabsolute or escape it (e.g., with ..) are rejected.  There is no\nauthentication, so the server should only listen on localhost\n(e.g., \"localhost:8080\"), unless the network is trusted.\nErrors are returned with a 400 status and an {\"error\": message} body.\nRequests are handled one at a time, as the network is not safe for\nconcurrent use.  For example, with curl:\n\n\tcurl -X POST 'localhost:8080/step?mode=Train&time=Epoch&n=5'\n\tcurl 'localhost:8080/stats?names=Epoch,Trial,SSE'", Fields: []types.Field{{Name: "Net", Doc: "Net is the network."}, {Name: "Params", Doc: "Params are the params of the sim, for the /params endpoint."}, {Name: "Ctx", Doc: "Ctx is the context of the sim, for applying the params during\nthe run with ApplyParamsLive."}, {Name: "Loops", Doc: "Loops are the loops of the sim, for the /step and /counters endpoints."}, {Name: "Stats", Doc: "Stats are the stats of the sim, for the /stats endpoint."}, {Name: "Init", Doc: "Init initializes the sim, for the /init endpoint (e.g., Sim.Init)."}, {Name: "Update", Doc: "Update, if set, is called prior to returning the stats,\ne.g., to update the counter stats (Sim.StatCounters)."}, {Name: "WeightsDir", Doc: "WeightsDir is the directory that the /weights endpoints save and\nopen files in, which is the current directory if empty."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ShardEnv", IDName: "shard-env", Doc: "ShardEnv is an env.Env that presents the rows of an in-memory table\n(like env.FixedTable), sharded across NShards data-parallel procs\n(e.g., MPI ranks, see MPI.ConfigShardEnv), where this env presents\nthe trials of shard Shard.  In each epoch, all of the procs compute\nthe same permuted order of all the rows from the shared Seed, and each\nproc gets a disjoint contiguous slice of that order, so each proc sees\na different random subset of the rows in each epoch, and together they\ncover the table (except for any remainder, see NTrials).  All the\nprocs run the same number of trials per epoch, so that the DWt\nweight changes summed across procs (MPI.WtFromDWt) stay in step.\nWith 1 shard (e.g., MPI not on), it is equivalent to a FixedTable.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train."}, {Name: "Table", Doc: "Table is an indexed view of the table with the patterns to present."}, {Name: "Sequential", Doc: "Sequential presents the rows in the order of the Table view,\ninstead of a new permuted order in each epoch."}, {Name: "Shard", Doc: "Shard is the index of the shard of the rows presented by this env,\ne.g., the MPI rank."}, {Name: "NShards", Doc: "NShards is the number of shards, e.g., the number of MPI procs."}, {Name: "Seed", Doc: "Seed is the seed for the permuted order of the rows, which is\nseeded with Seed + run in Init.  It MUST be the same on all procs,\nso that they compute the same order."}, {Name: "Rand", Doc: "Rand is the random number stream for the order, which is\nonly used for the order, so that it stays in sync across procs."}, {Name: "Order", Doc: "Order is the permuted order of all the rows for the current epoch,\nas indexes into the Table view."}, {Name: "Trial", Doc: "Trial is the current trial within the shard.\nMax is the number of trials per epoch, NTrials."}, {Name: "Epoch", Doc: "Epoch is the number of complete passes through the shards."}, {Name: "TrialName", Doc: "TrialName is the contents of the Name column of the current row,\nif present."}, {Name: "GroupName", Doc: "GroupName is the contents of the Group column of the current row,\nif present."}, {Name: "NameCol", Doc: "NameCol is the name of the Name column -- defaults to 'Name'."}, {Name: "GroupCol", Doc: "GroupCol is the name of the Group column -- defaults to 'Group'."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.NetSnapshot", IDName: "net-snapshot", Doc: "NetSnapshot renders snapshots of the network state, as shown in the\nNetView, to PNG or SVG image files during headless (nogui) runs, e.g.,\non a cluster, offscreen without a GPU or window.  The layers are drawn\nas flat grids of units, at the positions of the NetView layout (the\nlayer Pos, with the layers at each height (Z) in a row, from the bottom\nup, seen from above), colored by the unit values with the NetView color\nmap, ranges and opacity.  The values are those of a neuron variable\n(e.g., Act), or, as for the selected unit in the NetView, of a synaptic\nvariable of the pathways of a Unit (e.g., r.Wt for the weights that it\nreceives from each sending unit).", Fields: []types.Field{{Name: "Var", Doc: "Var is the variable to render: a neuron variable, e.g., Act,\nor a synaptic variable of the pathways of the Unit, with an r.\nprefix for the receiving pathways of the Unit (e.g., r.Wt) or\ns. for its sending pathways (e.g., s.Wt), as in the NetView."}, {Name: "Unit", Doc: "Unit is the unit whose pathways are rendered for synaptic Vars,\nas the layer name and 1D unit index, e.g., Hidden1:12."}, {Name: "Format", Doc: "Format is the image file format: png or svg."}, {Name: "UnitSize", Doc: "UnitSize is the size of each unit, in pixels."}, {Name: "ColorMap", Doc: "ColorMap is the name of the color map, as in the NetView."}, {Name: "Dir", Doc: "Dir is the directory where the image files are saved."}, {Name: "NFiles", Doc: "NFiles is the number of image files saved so far."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SpatialEnv", IDName: "spatial-env", Doc: "SpatialEnv generates entorhinal cortex (EC) input patterns from a\nsimulated trajectory through a square 2D arena, for training\nhippocampus models on spatial memory tasks.  The EC pattern has\nthe 4D pool shape of the hippocampus EC layers: the first pools are\ngrid cell modules, and the last PlacePools pools are place cells.\n\nEach grid module has a hexagonal grid of a given spacing and\norientation, with the units in the module tiling the spatial phases\nof the grid, and the spacing increasing geometrically across modules.\nThe grid cells are driven by the path-integrated estimate of the\nposition (EstPos), which accumulates PINoise on each step, and is\nonly corrected to the true position every PIReset steps, as by a\nlandmark.  The place cells are Gaussian bumps around random centers,\ndriven by the true position (Pos).\n\nRemapPlace and RemapGrid draw new place centers and grid phases,\nfor a different context in the same arena, or a novel arena.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment"}, {Name: "Size", Doc: "Size is the length of each side of the square arena."}, {Name: "Speed", Doc: "Speed is the distance moved on each step."}, {Name: "TurnSD", Doc: "TurnSD is the standard deviation of the change in heading\non each step, in radians."}, {Name: "PINoise", Doc: "PINoise is the standard deviation of the noise added to the\npath-integrated position estimate on each step, in each dimension."}, {Name: "PIReset", Doc: "PIReset is the interval in steps at which the path-integrated\nposition estimate is reset to the true position.  0 = never."}, {Name: "PoolsY", Doc: "PoolsY is the number of pools in the Y dimension of the EC pattern."}, {Name: "PoolsX", Doc: "PoolsX is the number of pools in the X dimension of the EC pattern."}, {Name: "UnitsY", Doc: "UnitsY is the number of units per pool in the Y dimension."}, {Name: "UnitsX", Doc: "UnitsX is the number of units per pool in the X dimension."}, {Name: "PlacePools", Doc: "PlacePools is the number of pools, at the end, with place cells.\nThe remaining pools are grid cell modules."}, {Name: "GridSpacing", Doc: "GridSpacing is the spacing of the first (smallest) grid module."}, {Name: "GridRatio", Doc: "GridRatio is the ratio of the spacing of each grid module\nto the previous one."}, {Name: "PlaceSigma", Doc: "PlaceSigma is the width (standard deviation) of the place fields."}, {Name: "KPerPool", Doc: "KPerPool is the number of most active units per pool that are\nset to 1, with the rest 0, for binary patterns.  0 = graded rates."}, {Name: "Seed", Doc: "Seed, if non-zero, is the seed for the Rand stream of this\nenvironment, which is seeded with Seed in Config, and Seed + run\nin Init, so that a given run is reproduced regardless of any other\nuse of random numbers.  If 0, the global random stream is used."}, {Name: "Rand", Doc: "Rand is the random number stream for this environment."}, {Name: "Pos", Doc: "Pos is the current true position."}, {Name: "EstPos", Doc: "EstPos is the current path-integrated estimate of the position."}, {Name: "Heading", Doc: "Heading is the current direction of movement, in radians."}, {Name: "GridOrient", Doc: "GridOrient is the orientation of each grid module, in radians."}, {Name: "GridPhase", Doc: "GridPhase is the spatial phase offset of each grid module."}, {Name: "PlaceCenters", Doc: "PlaceCenters are the centers of the place fields."}, {Name: "EC", Doc: "EC is the current EC pattern."}, {Name: "Cue", Doc: "Cue is the current EC pattern with the place pools empty, i.e.,\nthe grid cell code alone, as a cue for recalling the place cells."}, {Name: "PosState", Doc: "PosState is the current true position, as a tensor."}, {Name: "Trial", Doc: "trial is the step counter within epoch"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SpatialSample", IDName: "spatial-sample", Doc: "SpatialSample is a position on a trajectory through the arena.", Fields: []types.Field{{Name: "Pos", Doc: "Pos is the true position."}, {Name: "EstPos", Doc: "EstPos is the path-integrated estimate of the position."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StatValues", IDName: "stat-values", Doc: "StatValues are named stat values computed by the standard stats\nfunctions (ErrStats, MemStats), which are set in the estats.Stats of\na sim with SetStats, to be logged under the same names."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.SimMatStats", IDName: "sim-mat-stats", Doc: "SimMatStats are the within and between list similarities of a\nsimilarity matrix of the patterns of a set of lists of items,\ne.g., the AB, AC and lure lists of the hippocampus sims.", Fields: []types.Field{{Name: "Within", Doc: "Within is the mean similarity between the different items within\neach list, over the lower triangle of the matrix."}, {Name: "Between", Doc: "Between is the mean similarity between the corresponding items\n(same index) of different lists, e.g., the AB and AC items with\nthe same A."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StepGrains", IDName: "step-grains", Doc: "StepGrains are the standard grains of stepping for a Stepper,\nfrom a single cycle up to a whole run."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StopCond", IDName: "stop-cond", Doc: "StopCond is a named conditional-stop predicate for a Stepper,\nwhich is checked at the end of each Grain in all modes (Func can\ncheck the Mode of the Loops), and stops the running when it returns true.", Fields: []types.Field{{Name: "Name", Doc: "Name of the condition, recorded in Stepper.StoppedBy when it stops."}, {Name: "Grain", Doc: "Grain at the end of which the condition is checked."}, {Name: "Func", Doc: "Func returns true to stop."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Stepper", IDName: "stepper", Doc: "Stepper steps the looper.Stacks of a sim at the standard StepGrains,\nincluding the quarters and phases within the Cycle loop, and stops\nrunning when any of its StopConds is true, e.g., when a stat reaches\na criterion, instead of checking StopNow flags in the sim code.\nCreate with NewStepper, which adds the stop condition checks to the\nloops, and add it to the toolbar with AddToolbar.  The sub-trial\ngrains stop at the start of the next cycle (before any quarter\nevents at that cycle), and the last quarter, the plus phase and the\nalpha cycle stop after the end of the trial.", Fields: []types.Field{{Name: "Mode", Doc: "Mode is the mode (stack of loops) to step with Step and Run."}, {Name: "Grain", Doc: "Grain is the grain of stepping for Step."}, {Name: "N", Doc: "N is the number of Grains per Step."}, {Name: "PlusStart", Doc: "PlusStart is the cycle at which the plus phase starts,\nfor StepPhase."}, {Name: "StoppedBy", Doc: "StoppedBy is the name of the StopCond that stopped the last\nStep or Run, or empty if none."}, {Name: "Conds", Doc: "Conds are the conditional-stop predicates."}, {Name: "Loops", Doc: "Loops are the looper stacks that are stepped."}, {Name: "Ctx", Doc: "Ctx is the context, for the cycles per quarter."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.STPParams", IDName: "stp-params", Doc: "STPParams are the parameters for Tsodyks-Markram style short-term\nsynaptic plasticity (depression and facilitation), which modulates the\nefficacy of the synapses of each sending neuron on a pathway on every\ncycle, as a function of its recent activity, e.g., for the strongly\nfacilitating mossy fiber synapses onto CA3, or depressing recurrent\ncollaterals.  The rate-code activation is treated as a spike rate\n(Act * Rate spikes per cycle), which drives the release probability U\nup (facilitation) and depletes the available resources X (depression),\neach of which recovers toward its resting value with its own time\nconstant.  The sending efficacy is U * X / U0, which is 1 at rest.\nThe state is shared by all of the synapses of each sending neuron,\nso it is stored per sending neuron (Path.STPu, STPx), and shown\nin the STPu and STPx synapse variables of each of its synapses.", Fields: []types.Field{{Name: "On", Doc: "use short-term plasticity on this pathway"}, {Name: "U0", Doc: "baseline (resting) release probability U0: the proportion of the available resources used by each spike -- lower values produce facilitation and higher ones depression"}, {Name: "TauD", Doc: "time constant in cycles (msec) for the recovery of the depleted resources X back to 1 -- larger = more lasting depression"}, {Name: "TauF", Doc: "time constant in cycles (msec) for the decay of the facilitated release probability U back to U0 -- larger = more lasting facilitation"}, {Name: "Rate", Doc: "spike rate per cycle for a fully active (Act = 1) sending neuron, e.g., 0.1 = 100 Hz"}, {Name: "DtD", Doc: "rate = 1 / tau"}, {Name: "DtF", Doc: "rate = 1 / tau"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.StreamEnv", IDName: "stream-env", Doc: "StreamEnv is an env.Env that streams the patterns of a pattern table\nfile from disk in chunks of ChunkRows rows, instead of loading the whole\ntable into memory as env.FixedTable does, so that datasets much larger\nthan memory can be used, e.g., for pretraining cortical models.\nThe next Prefetch chunks are read and parsed in the background while\nthe current one is used.  Each epoch is one pass through the file,\nafter which it is read again from the start.  The rows are presented\nin file order, or, if Shuffle, in a random order within each chunk.\n\nThe file is in the tab-separated (or comma-separated for .csv) format\nwritten by table.SaveCSV with headers, optionally gzip compressed (.gz),\nand the State of an element is the current row of the column of the\nsame name.  Without table headers, the column types are inferred from\nthe first chunk.  Parquet files are not supported: convert them to TSV.\nCall Close when done, to stop the background reading.", Fields: []types.Field{{Name: "Name", Doc: "name of this environment, usually Train vs. Test."}, {Name: "Filename", Doc: "Filename is the pattern table file to stream."}, {Name: "ChunkRows", Doc: "ChunkRows is the number of rows read into memory at a time."}, {Name: "Prefetch", Doc: "Prefetch is the number of chunks read ahead in the background."}, {Name: "Shuffle", Doc: "Shuffle presents the rows of each chunk in a random order."}, {Name: "NameCol", Doc: "NameCol is the name of the Name column -- defaults to 'Name'."}, {Name: "Seed", Doc: "Seed, if non-zero, is the seed for the Rand stream of this\nenvironment, which is seeded with Seed + run in Init.\nIf 0, the global random stream is used."}, {Name: "Rand", Doc: "Rand is the random number stream for this environment."}, {Name: "Trial", Doc: "Trial is the current row within the epoch (pass through the file).\nMax is set to NRows at the end of the first epoch."}, {Name: "Epoch", Doc: "Epoch is the number of complete passes through the file."}, {Name: "TrialName", Doc: "TrialName is the contents of the Name column of the current row,\nif present."}, {Name: "NRows", Doc: "NRows is the number of rows in the file, which is known at the\nend of the first epoch, and 0 before that."}, {Name: "Chunk", Doc: "Chunk is the current chunk of the table."}, {Name: "Err", Doc: "Err is the error, if any, from reading the file, after\nwhich Step returns false."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Synapse", IDName: "synapse", Doc: "leabra.Synapse holds state for the synaptic connection between neurons.\nThe synapses for a pathway are stored in the structure-of-arrays\n[Synapses] form, and this struct is used to get and set all of the\nvalues for a given synapse, and for the variable names and docs.", Fields: []types.Field{{Name: "Wt", Doc: "synaptic weight value, sigmoid contrast-enhanced version\nof the linear weight LWt."}, {Name: "LWt", Doc: "linear (underlying) weight value, which learns according\nto the lrate specified in the connection spec.\nThis is converted into the effective weight value, Wt,\nvia sigmoidal contrast enhancement (see WtSigParams)."}, {Name: "DWt", Doc: "change in synaptic weight, driven by learning algorithm."}, {Name: "Norm", Doc: "DWt normalization factor, reset to max of abs value of DWt,\ndecays slowly down over time. Serves as an estimate of variance\nin weight changes over time."}, {Name: "Moment", Doc: "momentum, as time-integrated DWt changes, to accumulate a\nconsistent direction of weight change and cancel out\ndithering contradictory changes."}, {Name: "Scale", Doc: "scaling parameter for this connection: effective weight value\nis scaled by this factor in computing G conductance.\nThis is useful for topographic connectivity patterns e.g.,\nto enforce more distant connections to always be lower in magnitude\nthan closer connections.  Value defaults to 1 (cannot be exactly 0,\notherwise is automatically reset to 1; use a very small number to\napproximate 0). Typically set by using the paths.Pattern Weights()\nvalues where appropriate."}, {Name: "NTr", Doc: "NTr is the new trace, which drives updates to trace value.\nsu * (1-ru_msn) for gated, or su * ru_msn for not-gated (or for non-thalamic cases)."}, {Name: "Tr", Doc: "Tr is the current ongoing trace of activations, which drive learning.\nAdds NTr and clears after learning on current values, and includes both\nthal gated (+ and other nongated, - inputs)."}, {Name: "STPu", Doc: "STPu is the short-term plasticity release probability, which is\nincreased by sending activity (facilitation), and decays back to\nSTP.U0 -- only updated when STP.On (see STPParams).  It is shared\nby all the synapses of the sending neuron (see Path.STPu)."}, {Name: "STPx", Doc: "STPx is the short-term plasticity proportion of available synaptic\nresources, which are depleted by sending activity (depression), and\nrecover back to 1 -- only updated when STP.On (see STPParams).\nIt is shared by all the synapses of the sending neuron."}, {Name: "Imp", Doc: "Imp is the consolidated importance of the synapse for elastic\nweight consolidation, which scales the pull of LWt back toward\nLWtCons -- only updated when EWC.On (see EWCParams)."}, {Name: "ImpAcc", Doc: "ImpAcc is the importance of the synapse accumulated from its weight\nchanges since the last consolidation -- only updated when EWC.On."}, {Name: "LWtCons", Doc: "LWtCons is the consolidated linear weight, recorded at the last\nconsolidation -- only used when EWC.On."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Synapses", IDName: "synapses", Doc: "Synapses holds the state for all of the synapses in a pathway, in a\nstructure-of-arrays (SoA) layout, with a separate slice of values for\neach synaptic variable (with the same names and meaning as in\n[Synapse]), all indexed by the synapse index, in sending neuron order.\nThe inner loops over synapses (e.g., SendGDelta, DWt) only access the\nvariables they need, contiguously in memory, which is much more cache\nefficient than an array of Synapse structs, and amenable to\nvectorization.  The STPu and STPx variables are shared by all the\nsynapses of each sending neuron, so they are stored per sending neuron\nin the Path (Path.STPu, STPx) instead.  The elastic weight consolidation\nvariables (Imp, ImpAcc, LWtCons) are only allocated when EWC.On, and\nare empty (0 values) otherwise, to save memory.", Fields: []types.Field{{Name: "Wt"}, {Name: "LWt"}, {Name: "DWt"}, {Name: "Norm"}, {Name: "Moment"}, {Name: "Scale"}, {Name: "NTr"}, {Name: "Tr"}, {Name: "Imp"}, {Name: "ImpAcc"}, {Name: "LWtCons"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.TestCache", IDName: "test-cache", Doc: "TestCache caches the final settled state of the network for each\ndistinct testing trial, keyed by a fingerprint of the external inputs\napplied to the network.  When the network weights and parameters are\nunchanged since the state was cached (as determined by a fingerprint of\nall the weights, learned excitabilities and parameters, see NetKey),\nthe settling process can be skipped and the cached state restored instead,\nwhich substantially speeds up frequent-interval testing on the same patterns.\nCaching is only valid if settling is deterministic and independent of\nthe prior trial, so it is automatically disabled for networks with any\nrandom or carried-over state (see Valid).\nUse LooperTestCache to add to the looper Test stack.", Fields: []types.Field{{Name: "On", Doc: "if true, use the cache"}, {Name: "WtsKey", Doc: "fingerprint of the network weights and parameters\nfor the current cached states (see NetKey)"}, {Name: "Hits", Doc: "number of trials that were restored from the cache"}, {Name: "Misses", Doc: "number of trials that had to be computed"}, {Name: "States", Doc: "cached states, keyed by input fingerprint"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.TestCacheState", IDName: "test-cache-state", Doc: "TestCacheState is the settled state of the network for one testing trial.", Fields: []types.Field{{Name: "Neurons", Doc: "neuron state for each layer"}, {Name: "Pools", Doc: "pool state for each layer"}, {Name: "CosDiff", Doc: "CosDiff state for each layer"}, {Name: "GeRaw", Doc: "GeRaw for each path, in RecvPaths order by layer"}, {Name: "Cycle", Doc: "Context cycle at end of settling"}, {Name: "Quarter", Doc: "Context quarter at end of settling"}, {Name: "PlusPhase", Doc: "Context PlusPhase state at end of settling"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.GaussTopo", IDName: "gauss-topo", Doc: "GaussTopo is a topographic pathway pattern where the probability of\na connection, and optionally the synaptic Scale (see TopoWeights), fall\noff as a Gaussian function of the distance between the sending unit and\nthe position of the receiving unit mapped into the sending layer, with\nboth layers in normalized 0-1 coordinates, so that layers of different\nsizes are mapped onto each other, e.g., for the topographic EC -> DG\nperforant path, or cortical maps.  4D layers are flattened to 2D,\nas in the NetView display.", Fields: []types.Field{{Name: "Sigma", Doc: "Gaussian sigma (width) of the fall-off, as a proportion of the\nsize of the sending layer in each dimension."}, {Name: "PCon", Doc: "probability of connection at distance 0, with the probability\nat other distances being PCon times the Gaussian."}, {Name: "Radius", Doc: "maximum distance for a connection, in units of Sigma."}, {Name: "Wrap", Doc: "if true, distances wrap around the edges of the layer, as on a torus."}, {Name: "TopoWeights", Doc: "if true, set the synaptic Scale values to the Gaussian of the distance\ntimes MaxWt, with a minimum of MinWt, in Network.InitTopoScales."}, {Name: "MaxWt", Doc: "maximum Scale value, for TopoWeights."}, {Name: "MinWt", Doc: "minimum Scale value, for TopoWeights."}, {Name: "SelfCon", Doc: "if true, and connecting a layer to itself, connect each unit to itself."}, {Name: "RandSeed", Doc: "random seed for the connections: set from the network RandSeed\nin Build if 0 (see Network.SetPathRandSeeds)."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.Ring", IDName: "ring", Doc: "Ring is a pathway pattern for layers whose units are arranged on a ring,\nin order of their unit index (e.g., head direction or orientation\ncolumns), where each receiving unit connects to the sending units within\nRadius of its corresponding position on the sending ring, always wrapping\naround, with optional Gaussian TopoWeights as a function of the distance\naround the ring.  Layers of different sizes are mapped onto each other.", Fields: []types.Field{{Name: "Radius", Doc: "radius of the connections around the ring, as a proportion\nof the ring circumference (0.5 = all units)."}, {Name: "Sigma", Doc: "Gaussian sigma (width) of the TopoWeights fall-off, as a proportion\nof the ring circumference."}, {Name: "TopoWeights", Doc: "if true, set the synaptic Scale values to the Gaussian of the distance\ntimes MaxWt, with a minimum of MinWt, in Network.InitTopoScales."}, {Name: "MaxWt", Doc: "maximum Scale value, for TopoWeights."}, {Name: "MinWt", Doc: "minimum Scale value, for TopoWeights."}, {Name: "SelfCon", Doc: "if true, and connecting a layer to itself, connect each unit to itself."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.TransInf", IDName: "trans-inf", Doc: "TransInf generates the training and testing pattern tables for the\ntransitive inference paradigm, for relational integration on the\nhippocampus model.  Each list is a hierarchy of NItems items, e.g.,\nA > B > C > D > E > F, where each item wins over all the items after\nit.  The training table has the premise pairs of adjacent items\n(A-B, B-C, ...), in both orders, and the testing table has all the\npairs, including the inference probes between non-adjacent items\n(e.g., B-D), whose relation must be inferred from the premises.\nEach pattern has the left and right items of the pair in ItemPools\npools each of the EC layout, followed by the winning item in the next\nItemPools pools, and the context of the list in the remaining pools.\nThe test Input has the winner pools empty, to be recalled in ECout.\nThe rows are named <list>_<left>_<right>, so the symbolic distance\nof each pair can be recovered from the trial logs (see Dist and\nSymbolicDistance).", Fields: []types.Field{{Name: "On", Doc: "use transitive inference patterns"}, {Name: "NItems", Doc: "number of items in the hierarchy of each list"}, {Name: "ItemPools", Doc: "number of pools of the EC layout for each item"}, {Name: "PctAct", Doc: "proportion of active units in each item pool"}, {Name: "MinDiff", Doc: "minimum proportion of different active units between the patterns of the items"}, {Name: "CtxtFlip", Doc: "proportion of the active units of the context prototype flipped for each pair"}, {Name: "ExclEnds", Doc: "exclude the pairs with the end items (the first and last in the hierarchy) from the Inner accuracy of SymbolicDistance, as they can be solved without inference, from the end items always winning or losing"}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.UniquePatterns", IDName: "unique-patterns", Doc: "UniquePatterns generates trial-unique random binary patterns online,\neach with a fixed number of active units, and with a guaranteed minimum\ndistance to all previously generated patterns in the run, as needed for\ndelayed-non-match-to-sample and novelty paradigms, where each trial\nmust present a novel item.  The distance is the number of units that\ndiffer (Hamming distance), so two patterns with NOn active units each\nthat share k active units have a distance of 2 * (NOn - k).\nPatterns are stored compactly as bitsets, so that the distances to\nall previous patterns can be computed efficiently using bit counts.\nCall Config with a seed for the Rand stream, and Reset at the start of each run.", Fields: []types.Field{{Name: "NUnits", Doc: "total number of units in each pattern"}, {Name: "NOn", Doc: "number of active (1) units in each pattern"}, {Name: "MinDist", Doc: "minimum distance (number of differing units) between each new\npattern and all previous patterns"}, {Name: "MaxTries", Doc: "maximum number of random candidate patterns to try for each new\npattern, before giving up with an error"}, {Name: "Rand", Doc: "Rand is the random number stream for generating the patterns,\nwhich is seeded in Config."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.UpsampleModes", IDName: "upsample-modes", Doc: "UpsampleModes are the ways of mapping the units of a larger layer\nonto those of a smaller layer, for UpsampleWeights."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ValueKinds", IDName: "value-kinds", Doc: "ValueKinds are the kinds of estimates learned by a [ValueLayer]."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ValueParams", IDName: "value-params", Doc: "ValueParams are the parameters for a [ValueLayer], which learns\nan estimate of the state value (OFC-like) or effort cost (ACC-like)\nfrom the dopamine it receives (typically the TD error from a\n[TDDaLayer]), via [ValuePath] pathways from the state representation.\nThe estimate is sent to the SendTo Matrix layers, where it biases\nGo vs. NoGo gating (see MatrixParams.ValueGain, CostGain), to\nsupport cost-benefit gating models.", Fields: []types.Field{{Name: "Kind", Doc: "Kind is the kind of estimate: state value or effort cost."}, {Name: "Range", Doc: "Range is the range of estimates that can be represented by the layer."}, {Name: "PrevAct", Doc: "PrevAct uses the sending activity on the previous trial (ActQ0)\nfor learning, as in TD learning, where the dopamine at time t\nreflects the error in the estimate at t-1.  Otherwise, the current\nsending activity is used, as in Rescorla-Wagner learning."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.ValueInputs", IDName: "value-inputs", Doc: "ValueInputs are the value and cost estimates received from [ValueLayer]s\nvia their SendTo lists, which bias gating in Matrix layers.", Fields: []types.Field{{Name: "Value", Doc: "Value is the state value estimate, from a StateValue layer."}, {Name: "Cost", Doc: "Cost is the effort cost estimate, from an EffortCost layer."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WiringStats", IDName: "wiring-stats", Doc: "WiringStats are statistics of the effective connectivity of a pathway,\ntypically after training, for comparison with anatomical data, e.g.,\nthe number of mossy fiber inputs onto each CA3 neuron.  Structural\nconnectivity is the number of synapses, and effective connectivity\ncounts only synapses with an effective weight (Wt * Scale) at or above\na threshold, which reflects the activity-dependent refinement of the\nconnections by learning.", Fields: []types.Field{{Name: "Path", Doc: "Path is the name of the pathway."}, {Name: "NSyns", Doc: "NSyns is the total number of synapses."}, {Name: "Conv", Doc: "Conv is the mean convergence: the number of synapses\nper receiving neuron."}, {Name: "Div", Doc: "Div is the mean divergence: the number of synapses\nper sending neuron."}, {Name: "EffConv", Doc: "EffConv is the mean effective convergence: the number of synapses per\nreceiving neuron with an effective weight at or above the threshold."}, {Name: "EffConvSD", Doc: "EffConvSD is the standard deviation of the effective convergence\nacross receiving neurons."}, {Name: "EffDiv", Doc: "EffDiv is the mean effective divergence: the number of synapses per\nsending neuron with an effective weight at or above the threshold."}, {Name: "EffProp", Doc: "EffProp is the proportion of synapses with an effective weight\nat or above the threshold."}, {Name: "WtMean", Doc: "WtMean is the mean effective weight."}, {Name: "WtSD", Doc: "WtSD is the standard deviation of the effective weights."}, {Name: "WtHist", Doc: "WtHist is the histogram of effective weights, in WiringHistBins\nequal bins from 0 to 1 (with weights above 1 in the last bin),\nas the proportion of synapses in each bin."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WiringTarget", IDName: "wiring-target", Doc: "WiringTarget is an anatomical target distribution for a wiring stat\nof a pathway, e.g., the number of mossy fiber inputs per CA3 neuron\n(EffConv of DGToCA3), as a mean and standard deviation.", Fields: []types.Field{{Name: "Path", Doc: "Path is the name of the pathway."}, {Name: "Stat", Doc: "Stat is the name of the stat (see WiringStats.Stat)."}, {Name: "Mean", Doc: "Mean is the target mean value."}, {Name: "SD", Doc: "SD is the standard deviation of the target value, which determines\nthe tolerance for discrepancies.  If 0, 10% of the Mean is used."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WiringDiscrepancy", IDName: "wiring-discrepancy", Doc: "WiringDiscrepancy is the comparison of a wiring stat with its target.", Embeds: []types.Field{{Name: "WiringTarget"}}, Fields: []types.Field{{Name: "Value", Doc: "Value is the value of the stat in the network."}, {Name: "Z", Doc: "Z is the difference of the Value from the target Mean,\nin units of the target SD."}, {Name: "Ratio", Doc: "Ratio is the ratio of the Value to the target Mean, which is\nuseful when the network is a scaled-down model of the anatomy."}, {Name: "Mismatch", Doc: "Mismatch is true if the absolute value of Z exceeds the tolerance."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WeightsAverage", IDName: "weights-average", Doc: "WeightsAverage accumulates the weights of networks with identical\narchitecture, e.g., checkpoints at different points in training, or the\nfinal weights of different runs, to compute the average \"consensus\"\nweights, which are more robust to the noise of any one set of weights.\nThe average activity levels (ActAvg) of each layer, which are saved\nwith the weights and determine the netinput scaling, are also averaged.\nNote that averaging the weights of runs that learned different\nrepresentations (e.g., hidden units in a different order) blurs them\ntogether, so averaging is most meaningful for checkpoints from the same\nrun, or runs from the same initial weights.", Fields: []types.Field{{Name: "N", Doc: "N is the number of networks that have been added."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.EnsembleEval", IDName: "ensemble-eval", Doc: "EnsembleEval is the evaluation of an ensemble of weights files,\nwith the performance of each member, and of the average weights.", Fields: []types.Field{{Name: "Files", Doc: "Files are the weights files of the ensemble members."}, {Name: "Members", Doc: "Members are the evaluated performance of each member."}, {Name: "Mean", Doc: "Mean is the mean performance across the members."}, {Name: "SD", Doc: "SD is the standard deviation of the performance across members."}, {Name: "Average", Doc: "Average is the performance of the network with the average\nweights across the members."}}})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtIniter", IDName: "wt-initer", Doc: "WtIniter is a weight initialization scheme, which generates the initial\nweights of all the synapses of a pathway, in the 0-1 range prior to the\nsynapse Scale, in the order of the Syns, using the given random number\ngenerator.  Schemes are registered by name with RegisterWtInit,\nand selected with WtInit.Scheme, so that sims can define their own\ninitial weight distributions."})
+
+var _ = types.AddType(&types.Type{Name: "github.com/emer/leabra/v2/leabra.WtInitFunc", IDName: "wt-init-func", Doc: "WtInitFunc is a function that implements the WtIniter interface."})
@@ -0,0 +1,175 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math/bits"
+
+	"cogentcore.org/core/base/randx"
+	"cogentcore.org/core/tensor"
+)
+
+// UniquePatterns generates trial-unique random binary patterns online,
+// each with a fixed number of active units, and with a guaranteed minimum
+// distance to all previously generated patterns in the run, as needed for
+// delayed-non-match-to-sample and novelty paradigms, where each trial
+// must present a novel item.  The distance is the number of units that
+// differ (Hamming distance), so two patterns with NOn active units each
+// that share k active units have a distance of 2 * (NOn - k).
+// Patterns are stored compactly as bitsets, so that the distances to
+// all previous patterns can be computed efficiently using bit counts.
+// Call Config with a seed for the Rand stream, and Reset at the start of each run.
+type UniquePatterns struct {
+
+	// total number of units in each pattern
+	NUnits int
+
+	// number of active (1) units in each pattern
+	NOn int
+
+	// minimum distance (number of differing units) between each new
+	// pattern and all previous patterns
+	MinDist int
+
+	// maximum number of random candidate patterns to try for each new
+	// pattern, before giving up with an error
+	MaxTries int `default:"1000"`
+
+	// Rand is the random number stream for generating the patterns,
+	// which is seeded in Config.
+	Rand randx.SysRand `display:"-" json:"-"`
+
+	// bitsets for all the patterns generated so far
+	pats [][]uint64
+
+	// scratch list of unit indexes, for random selection
+	idxs []int
+}
+
+// Config configures the pattern parameters, seeds the Rand stream
+// with the given seed, and resets any existing patterns.
+func (up *UniquePatterns) Config(nUnits, nOn, minDist int, seed int64) {
+	up.NUnits = nUnits
+	up.NOn = nOn
+	up.MinDist = minDist
+	up.Rand.NewRand(seed)
+	if up.MaxTries == 0 {
+		up.MaxTries = 1000
+	}
+	up.Reset()
+}
+
+// Reset removes all of the previous patterns, e.g., at the start of a run.
+func (up *UniquePatterns) Reset() {
+	up.pats = nil
+}
+
+// Len returns the number of patterns generated (or added) so far.
+func (up *UniquePatterns) Len() int {
+	return len(up.pats)
+}
+
+// Next generates a new pattern with at least MinDist distance from all
+// the previous patterns, and sets it into the given tensor, which must
+// have NUnits values, with 1 for active units and 0 otherwise.
+// The new pattern is recorded for checking future patterns.
+// Returns an error if no such pattern was found in MaxTries attempts,
+// which happens as the space of possible patterns fills up,
+// or if the Rand stream has not been seeded (see Config).
+func (up *UniquePatterns) Next(pat tensor.Tensor) error {
+	if pat.Len() != up.NUnits {
+		return fmt.Errorf("leabra.UniquePatterns.Next: pattern has %d values, not NUnits = %d", pat.Len(), up.NUnits)
+	}
+	if up.NOn > up.NUnits {
+		return fmt.Errorf("leabra.UniquePatterns.Next: NOn = %d is greater than NUnits = %d", up.NOn, up.NUnits)
+	}
+	if up.Rand.Rand == nil {
+		return fmt.Errorf("leabra.UniquePatterns.Next: Rand has not been seeded: call Config")
+	}
+	if len(up.idxs) != up.NUnits {
+		up.idxs = make([]int, up.NUnits)
+		randx.SequentialInts(up.idxs, 0)
+	}
+	maxTries := max(up.MaxTries, 1)
+	for range maxTries {
+		bs := up.randBits(&up.Rand)
+		if up.MinDistBits(bs) >= up.MinDist {
+			up.pats = append(up.pats, bs)
+			up.setTensor(bs, pat)
+			return nil
+		}
+	}
+	return fmt.Errorf("leabra.UniquePatterns.Next: could not find a pattern with distance >= %d from the %d previous patterns in %d tries", up.MinDist, len(up.pats), maxTries)
+}
+
+// Add records the given pattern (values > 0.5 are active) as a previous
+// pattern, so that new patterns are at least MinDist from it,
+// e.g., for patterns from another source.
+func (up *UniquePatterns) Add(pat tensor.Tensor) {
+	up.pats = append(up.pats, up.tensorBits(pat))
+}
+
+// MinDistTo returns the minimum distance between the given pattern
+// (values > 0.5 are active) and all of the previous patterns,
+// which is a measure of novelty (NUnits + 1 if there are none).
+func (up *UniquePatterns) MinDistTo(pat tensor.Tensor) int {
+	return up.MinDistBits(up.tensorBits(pat))
+}
+
+// MinDistBits returns the minimum distance between the given pattern
+// bitset and all of the previous patterns (NUnits + 1 if there are none).
+func (up *UniquePatterns) MinDistBits(bs []uint64) int {
+	md := up.NUnits + 1
+	for _, pb := range up.pats {
+		d := 0
+		for i, w := range pb {
+			d += bits.OnesCount64(w ^ bs[i])
+			if d >= md {
+				break
+			}
+		}
+		md = min(md, d)
+	}
+	return md
+}
+
+// randBits returns a bitset with NOn randomly selected active units,
+// using a partial Fisher-Yates shuffle of the unit indexes.
+func (up *UniquePatterns) randBits(rnd randx.Rand) []uint64 {
+	bs := make([]uint64, (up.NUnits+63)/64)
+	n := len(up.idxs)
+	for i := range up.NOn {
+		j := i + rnd.Intn(n-i)
+		up.idxs[i], up.idxs[j] = up.idxs[j], up.idxs[i]
+		ui := up.idxs[i]
+		bs[ui/64] |= 1 << (ui % 64)
+	}
+	return bs
+}
+
+// tensorBits returns a bitset for the given tensor values,
+// where values > 0.5 are active.
+func (up *UniquePatterns) tensorBits(pat tensor.Tensor) []uint64 {
+	bs := make([]uint64, (up.NUnits+63)/64)
+	n := min(pat.Len(), up.NUnits)
+	for ui := range n {
+		if pat.Float1D(ui) > 0.5 {
+			bs[ui/64] |= 1 << (ui % 64)
+		}
+	}
+	return bs
+}
+
+// setTensor sets the tensor values from the given bitset.
+func (up *UniquePatterns) setTensor(bs []uint64, pat tensor.Tensor) {
+	for ui := range up.NUnits {
+		if bs[ui/64]&(1<<(ui%64)) != 0 {
+			pat.SetFloat1D(ui, 1)
+		} else {
+			pat.SetFloat1D(ui, 0)
+		}
+	}
+}
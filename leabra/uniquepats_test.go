@@ -0,0 +1,111 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor"
+)
+
+// hammingDist returns the number of differing units between a and b.
+func hammingDist(a, b *tensor.Float32) int {
+	d := 0
+	for i, v := range a.Values {
+		if v != b.Values[i] {
+			d++
+		}
+	}
+	return d
+}
+
+func TestUniquePatterns(t *testing.T) {
+	up := &UniquePatterns{}
+	pat := tensor.NewFloat32([]int{5, 5})
+	if err := up.Next(pat); err == nil {
+		t.Error("Next: expected error for unseeded Rand")
+	}
+	up.Config(25, 5, 6, 1)
+	var pats []*tensor.Float32
+	for range 20 {
+		if err := up.Next(pat); err != nil {
+			t.Fatal(err)
+		}
+		non := 0
+		for _, v := range pat.Values {
+			non += int(v)
+		}
+		if non != 5 {
+			t.Errorf("pattern has %d active units, want 5", non)
+		}
+		for pi, prv := range pats {
+			if d := hammingDist(pat, prv); d < 6 {
+				t.Errorf("pattern %d has distance %d < MinDist to pattern %d", len(pats), d, pi)
+			}
+		}
+		pats = append(pats, pat.Clone().(*tensor.Float32))
+	}
+	if up.Len() != 20 {
+		t.Errorf("Len = %d, want 20", up.Len())
+	}
+	if d := up.MinDistTo(pats[3]); d != 0 {
+		t.Errorf("MinDistTo previous pattern = %d, want 0", d)
+	}
+
+	// same seed gives the same patterns
+	up2 := &UniquePatterns{}
+	up2.Config(25, 5, 6, 1)
+	pat2 := tensor.NewFloat32([]int{5, 5})
+	up2.Next(pat2)
+	if hammingDist(pat2, pats[0]) != 0 {
+		t.Error("same seed gave a different first pattern")
+	}
+
+	// Add and MinDistTo
+	up.Reset()
+	if d := up.MinDistTo(pats[0]); d != 26 {
+		t.Errorf("MinDistTo with no patterns = %d, want NUnits + 1", d)
+	}
+	ext := tensor.NewFloat32([]int{5, 5})
+	for i := range 5 {
+		ext.Values[i] = 1
+	}
+	up.Add(ext)
+	probe := ext.Clone().(*tensor.Float32)
+	probe.Values[0] = 0
+	probe.Values[5] = 1
+	if d := up.MinDistTo(probe); d != 2 {
+		t.Errorf("MinDistTo added pattern = %d, want 2", d)
+	}
+	for range 10 {
+		if err := up.Next(pat); err != nil {
+			t.Fatal(err)
+		}
+		if d := hammingDist(pat, ext); d < 6 {
+			t.Errorf("pattern has distance %d < MinDist to added pattern", d)
+		}
+	}
+
+	// exhausting the space: 4 units with 2 on gives 6 patterns,
+	// each at distance 2 or 4 from the others
+	small := &UniquePatterns{MaxTries: 100}
+	small.Config(4, 2, 2, 1)
+	spat := tensor.NewFloat32([]int{4})
+	for range 6 {
+		if err := small.Next(spat); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := small.Next(spat); err == nil || !strings.Contains(err.Error(), "in 100 tries") {
+		t.Errorf("Next: expected MaxTries error when exhausted, got %v", err)
+	}
+	if small.Len() != 6 {
+		t.Errorf("failed Next added a pattern: Len = %d", small.Len())
+	}
+	if err := small.Next(tensor.NewFloat32([]int{5})); err == nil {
+		t.Error("Next: expected size mismatch error")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+
+	"cogentcore.org/core/tensor"
+)
+
+// UpsampleModes are the ways of mapping the units of a larger layer
+// onto those of a smaller layer, for UpsampleWeights.
+type UpsampleModes int32 //enums:enum
+
+const (
+	// UpsampleScale maps each unit to the unit at the proportionally
+	// corresponding position along each dimension of the smaller layer,
+	// which stretches the small layer over the large one (nearest neighbor
+	// interpolation), so that topographic organization is preserved.
+	UpsampleScale UpsampleModes = iota
+
+	// UpsampleTile maps each unit to the unit at its position modulo
+	// the size of the smaller layer along each dimension, which tiles
+	// copies of the small layer over the large one, e.g., to replicate
+	// the pools of a trained hippocampal model into more pools.
+	UpsampleTile
+)
+
+// UpsampleWeights sets the weights of this network from those of a
+// smaller (or same size) trained network with the same layer names and
+// pathways, so that a large network can be warm-started from the results
+// of cheaper runs of a small one.  Each unit of a layer in this network
+// is mapped onto a unit of the same-named layer in the small network,
+// according to the mode, and each synapse gets the weight of the synapse
+// between the corresponding units in the small network, if it exists.
+// Other synapses, and layers and pathways not present in the small network,
+// keep their current (e.g., initial) weights.  Pathways are matched by
+// receiving and sending layer names, in order for multiple pathways between
+// the same layers.  Layers must have the same number of dimensions.
+// The average activity levels (ActAvg) are also copied, and the netinput
+// scaling is updated.  Because the netinput scaling normalizes by the
+// expected number of active sending units, the copied weights produce
+// similar netinputs in the larger network, as long as the relative
+// activity levels are similar.
+func (nt *Network) UpsampleWeights(small *Network, mode UpsampleModes) error {
+	var errs []error
+	nmaps := make(map[*Layer][]int)
+	for _, ly := range nt.Layers {
+		sly := small.LayerByName(ly.Name)
+		if sly == nil {
+			continue
+		}
+		nmap, err := upsampleIndexMap(&sly.Shape, &ly.Shape, mode)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("leabra.UpsampleWeights: layer %s: %w", ly.Name, err))
+			continue
+		}
+		nmaps[ly] = nmap
+		sact := sly.Pools[0].ActAvg
+		for pi := range ly.Pools {
+			ly.Pools[pi].ActAvg = sact
+		}
+	}
+	for _, ly := range nt.Layers {
+		rmap, ok := nmaps[ly]
+		if !ok {
+			continue
+		}
+		sly := small.LayerByName(ly.Name)
+		for _, pt := range ly.RecvPaths {
+			smap, ok := nmaps[pt.Send]
+			if !ok || pt.Off {
+				continue
+			}
+			spt := upsampleMatchPath(pt, sly)
+			if spt == nil {
+				continue
+			}
+			pt.upsampleWeights(spt, rmap, smap)
+		}
+	}
+	for _, ly := range nt.Layers {
+		ly.GScaleFromAvgAct()
+	}
+	return errors.Join(errs...)
+}
+
+// upsampleMatchPath returns the pathway in the small recv layer that
+// corresponds to the given pathway: the one from the same-named sending
+// layer, at the same position among multiple such pathways.
+func upsampleMatchPath(pt *Path, sly *Layer) *Path {
+	n := 0
+	for _, p := range pt.Recv.RecvPaths {
+		if p == pt {
+			break
+		}
+		if p.Send.Name == pt.Send.Name {
+			n++
+		}
+	}
+	for _, sp := range sly.RecvPaths {
+		if sp.Send.Name != pt.Send.Name || sp.Off {
+			continue
+		}
+		if n == 0 {
+			return sp
+		}
+		n--
+	}
+	return nil
+}
+
+// upsampleWeights sets the weights of this pathway from the small one,
+// with rmap and smap mapping the recv and send neuron indexes onto
+// the small ones.
+func (pt *Path) upsampleWeights(spt *Path, rmap, smap []int) {
+	for ri := range pt.RConN {
+		sri := rmap[ri]
+		nc := int(pt.RConN[ri])
+		st := int(pt.RConIndexSt[ri])
+		for ci := 0; ci < nc; ci++ {
+			ssi := smap[pt.RConIndex[st+ci]]
+			ssyi := spt.SynIndex(ssi, sri)
+			if ssyi < 0 {
+				continue
+			}
+			syi := int(pt.RSynIndex[st+ci])
+			pt.Syns.Wt[syi] = spt.Syns.Wt[ssyi]
+			pt.Syns.LWt[syi] = spt.Syns.LWt[ssyi]
+		}
+	}
+}
+
+// upsampleIndexMap returns, for each neuron index in the large shape,
+// the corresponding neuron index in the small shape, according to mode.
+func upsampleIndexMap(small, large *tensor.Shape, mode UpsampleModes) ([]int, error) {
+	nd := large.NumDims()
+	if small.NumDims() != nd {
+		return nil, fmt.Errorf("shape %v has a different number of dimensions than %v", large.Sizes, small.Sizes)
+	}
+	n := large.Len()
+	nmap := make([]int, n)
+	sidx := make([]int, nd)
+	for ni := 0; ni < n; ni++ {
+		idx := large.Index(ni)
+		for d := range nd {
+			ssz := small.DimSize(d)
+			if mode == UpsampleTile {
+				sidx[d] = idx[d] % ssz
+			} else {
+				sidx[d] = min(idx[d]*ssz/large.DimSize(d), ssz-1)
+			}
+		}
+		nmap[ni] = small.Offset(sidx)
+	}
+	return nmap, nil
+}
@@ -0,0 +1,43 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestUpsampleWeights(t *testing.T) {
+	makeNet := func(n int) *Network {
+		net := NewNetwork("Upsample")
+		in := net.AddLayer2D("Input", n, n, InputLayer)
+		hid := net.AddLayer2D("Hidden", n, n, SuperLayer)
+		net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+		if err := net.Build(); err != nil {
+			t.Fatal(err)
+		}
+		net.Defaults()
+		net.InitWeights()
+		return net
+	}
+	small := makeNet(2)
+	small.LayerByName("Hidden").RecvPaths[0].SetSynValue("Wt", 1, 2, .9)
+	large := makeNet(4)
+	if err := large.UpsampleWeights(small, UpsampleScale); err != nil {
+		t.Fatal(err)
+	}
+	lpt := large.LayerByName("Hidden").RecvPaths[0]
+	// send (0,2) -> (0,1) = 1, recv (2,0) -> (1,0) = 2
+	CmprFloats([]float32{lpt.SynValue("Wt", 2, 8)}, []float32{.9}, "UpsampleScale", t)
+
+	large = makeNet(4)
+	if err := large.UpsampleWeights(small, UpsampleTile); err != nil {
+		t.Fatal(err)
+	}
+	lpt = large.LayerByName("Hidden").RecvPaths[0]
+	// send (0,3) -> (0,1) = 1, recv (3,2) -> (1,0) = 2
+	CmprFloats([]float32{lpt.SynValue("Wt", 3, 14)}, []float32{.9}, "UpsampleTile", t)
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"github.com/emer/emergent/v2/paths"
+	"github.com/emer/leabra/v2/fmath"
+)
+
+////////  Value
+
+// ValueKinds are the kinds of estimates learned by a [ValueLayer].
+type ValueKinds int32 //enums:enum
+
+const (
+	// StateValue is the expected reward value of the current state,
+	// as in the orbitofrontal cortex (OFC), which is learned from
+	// positive dopamine, and biases Matrix gating toward Go.
+	StateValue ValueKinds = iota
+
+	// EffortCost is the expected effort or other cost of the current state,
+	// as in the anterior cingulate cortex (ACC), which is learned from
+	// negative dopamine (i.e., dopamine with the sign reversed), and
+	// biases Matrix gating toward NoGo.
+	EffortCost
+)
+
+// ValueParams are the parameters for a [ValueLayer], which learns
+// an estimate of the state value (OFC-like) or effort cost (ACC-like)
+// from the dopamine it receives (typically the TD error from a
+// [TDDaLayer]), via [ValuePath] pathways from the state representation.
+// The estimate is sent to the SendTo Matrix layers, where it biases
+// Go vs. NoGo gating (see MatrixParams.ValueGain, CostGain), to
+// support cost-benefit gating models.
+type ValueParams struct {
+
+	// Kind is the kind of estimate: state value or effort cost.
+	Kind ValueKinds
+
+	// Range is the range of estimates that can be represented by the layer.
+	Range fmath.Range
+
+	// PrevAct uses the sending activity on the previous trial (ActQ0)
+	// for learning, as in TD learning, where the dopamine at time t
+	// reflects the error in the estimate at t-1.  Otherwise, the current
+	// sending activity is used, as in Rescorla-Wagner learning.
+	PrevAct bool `default:"true"`
+}
+
+func (vp *ValueParams) Defaults() {
+	vp.Range.Set(0, 1)
+	vp.PrevAct = true
+}
+
+func (vp *ValueParams) Update() {
+}
+
+// DaSign returns the sign of the dopamine for learning:
+// 1 for StateValue and -1 for EffortCost.
+func (vp *ValueParams) DaSign() Float {
+	if vp.Kind == EffortCost {
+		return -1
+	}
+	return 1
+}
+
+// ValueInputs are the value and cost estimates received from [ValueLayer]s
+// via their SendTo lists, which bias gating in Matrix layers.
+type ValueInputs struct {
+
+	// Value is the state value estimate, from a StateValue layer.
+	Value Float
+
+	// Cost is the effort cost estimate, from an EffortCost layer.
+	Cost Float
+}
+
+func (vi *ValueInputs) Init() {
+	vi.Value = 0
+	vi.Cost = 0
+}
+
+// ActFromGValue computes the clipped linear activation for [ValueLayer].
+func (ly *Layer) ActFromGValue(ctx *Context) {
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		nrn.Act = ly.Value.Range.ClipValue(nrn.Ge)
+		ly.Learn.AvgsFromAct(nrn)
+	}
+}
+
+// SendValueFromAct sends the average activity of the [ValueLayer]
+// as the Value or Cost input to the SendTo layers, according to Value.Kind.
+func (ly *Layer) SendValueFromAct(ctx *Context) {
+	var sum Float
+	n := 0
+	for ni := range ly.Neurons {
+		nrn := &ly.Neurons[ni]
+		if nrn.IsOff() {
+			continue
+		}
+		sum += nrn.Act
+		n++
+	}
+	if n > 0 {
+		sum /= Float(n)
+	}
+	for _, lnm := range ly.SendTo {
+		tly := ly.Network.LayerByName(lnm)
+		if tly == nil {
+			continue
+		}
+		if ly.Value.Kind == EffortCost {
+			tly.ValueIn.Cost = sum
+		} else {
+			tly.ValueIn.Value = sum
+		}
+	}
+}
+
+// MatrixValueGe returns the excitatory conductance bias for a Matrix
+// layer from the received Value and Cost (ValueIn), which favors Go
+// for value and NoGo for cost, with the sign reversed for D2R (NoGo) layers.
+func (ly *Layer) MatrixValueGe() Float {
+	if ly.Type != MatrixLayer {
+		return 0
+	}
+	ge := ly.Matrix.ValueGain*ly.ValueIn.Value - ly.Matrix.CostGain*ly.ValueIn.Cost
+	if ly.PBWM.DaR == D2R {
+		ge = -ge
+	}
+	return ge
+}
+
+func (pt *Path) ValueDefaults() {
+	pt.Learn.WtSig.Gain = 1
+	pt.Learn.Norm.On = false
+	pt.Learn.Momentum.On = false
+	pt.Learn.WtBal.On = false
+}
+
+// DWtValue computes the weight change (learning) for [ValuePath],
+// from the dopamine received by the [ValueLayer], with the sign
+// reversed for EffortCost layers.
+func (pt *Path) DWtValue() {
+	slay := pt.Send
+	rlay := pt.Recv
+	da := rlay.Value.DaSign() * rlay.DaLearn()
+	for si := range slay.Neurons {
+		sn := &slay.Neurons[si]
+		nc := int(pt.SConN[si])
+		st := int(pt.SConIndexSt[si])
+		dwts := pt.Syns.DWt[st : st+nc]
+		sact := sn.Act
+		if rlay.Value.PrevAct {
+			sact = sn.ActQ0
+		}
+		for ci := range dwts {
+			dwts[ci] += pt.Learn.Lrate * da * sact
+		}
+	}
+}
+
+// AddValueLayer adds a [ValueLayer] with one unit, of the given kind,
+// which learns from the dopamine sent by the da layer (e.g., a [TDDaLayer]),
+// which has the value layer added to its SendTo list, and sends its
+// estimate to the given Matrix layers.  Connect the state representation
+// to the value layer with ConnectToValue.
+func (nt *Network) AddValueLayer(name string, kind ValueKinds, da *Layer, matrix ...*Layer) *Layer {
+	vl := nt.AddLayer2D(name, 1, 1, ValueLayer)
+	vl.Value.Kind = kind
+	if da != nil {
+		da.SendTo.Add(vl.Name)
+	}
+	for _, mt := range matrix {
+		vl.SendTo.Add(mt.Name)
+	}
+	if kind == EffortCost {
+		vl.Doc = "Effort cost estimate (ACC-like), learned from negative dopamine, biasing Matrix gating toward NoGo"
+	} else {
+		vl.Doc = "State value estimate (OFC-like), learned from dopamine, biasing Matrix gating toward Go"
+	}
+	return vl
+}
+
+// AddOFCACCLayers adds StateValue "OFC" and EffortCost "ACC" [ValueLayer]s,
+// learning from the given da layer and sending to the given Matrix layers,
+// positioned behind the da layer.
+func (nt *Network) AddOFCACCLayers(prefix string, da *Layer, space float32, matrix ...*Layer) (ofc, acc *Layer) {
+	ofc = nt.AddValueLayer(prefix+"OFC", StateValue, da, matrix...)
+	acc = nt.AddValueLayer(prefix+"ACC", EffortCost, da, matrix...)
+	if da != nil {
+		ofc.PlaceBehind(da, space)
+	}
+	acc.PlaceRightOf(ofc, space)
+	return
+}
+
+// ConnectToValue connects the send layer to a [ValueLayer] with a
+// full [ValuePath], which learns the estimate from dopamine.
+func (nt *Network) ConnectToValue(send, recv *Layer) *Path {
+	return nt.ConnectLayers(send, recv, paths.NewFull(), ValuePath)
+}
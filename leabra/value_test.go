@@ -0,0 +1,41 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestValueLayers(t *testing.T) {
+	net := NewNetwork("Value")
+	in := net.AddLayer2D("Input", 2, 1, InputLayer)
+	da := net.AddClampDaLayer("DA")
+	mtxGo := net.AddMatrixLayer("MatrixGo", 1, 1, 1, 2, 2, D1R)
+	mtxNoGo := net.AddMatrixLayer("MatrixNoGo", 1, 1, 1, 2, 2, D2R)
+	ofc, acc := net.AddOFCACCLayers("", da, 2, mtxGo, mtxNoGo)
+	ofcPt := net.ConnectToValue(in, ofc)
+	accPt := net.ConnectToValue(in, acc)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	ctx := NewContext()
+
+	ofc.Neurons[0].Act = 0.6
+	acc.Neurons[0].Act = 0.2
+	ofc.SendValueFromAct(ctx)
+	acc.SendValueFromAct(ctx)
+	CmprFloats([]float32{float32(mtxGo.ValueIn.Value), float32(mtxNoGo.ValueIn.Cost)}, []float32{0.6, 0.2}, "ValueIn", t)
+	CmprFloats([]float32{float32(mtxGo.MatrixValueGe()), float32(mtxNoGo.MatrixValueGe())}, []float32{0.12, -0.12}, "MatrixValueGe", t)
+
+	in.Neurons[0].ActQ0 = 1
+	in.Neurons[0].Act = 1
+	da.SendDA(0.5)
+	ofcPt.DWtValue()
+	accPt.DWtValue()
+	lr := float32(ofcPt.Learn.Lrate)
+	CmprFloats([]float32{ofcPt.SynValue("DWt", 0, 0), accPt.SynValue("DWt", 0, 0), ofcPt.SynValue("DWt", 1, 0)}, []float32{lr * 0.5, -lr * 0.5, 0}, "DWtValue", t)
+}
@@ -0,0 +1,548 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"sort"
+
+	"cogentcore.org/core/core"
+	"github.com/emer/emergent/v2/weights"
+	"github.com/emer/leabra/v2/fmath"
+	"golang.org/x/exp/maps"
+)
+
+// The binary weights format is a much more compact and faster alternative
+// to the JSON weights format, for large networks.  It has the same structure
+// as the JSON format (see the emergent weights package), with each layer
+// written in turn from the receiver-side perspective, and is always gzip
+// compressed.  Within the compressed stream, all numbers are little-endian,
+// and strings are written as a uint32 length followed by the bytes:
+//
+//	"LWTB" uint32(version) uint32(value size) string(network name) uint32(n layers)
+//	per layer: string(name) meta uint32(n paths)
+//	per path: string(from) meta uint32(n recv)
+//	per recv: uint32(ri) uint32(n) n * uint32(si) n * float(wt)
+//
+// where meta is a uint32(n) followed by n key, value string pairs
+// (for layers: ActMAvg, ActPAvg, and the space-separated Excit and CtxtLag
+// values of the neurons if they are learned, see LearnNeurParams.Excit
+// and CtxtParams),
+// and the weight values are float32 or float64 according to the value size
+// (4 or 8), which is the size of Float for the build that wrote them
+// (version 1 files have no value size, and are always float32).
+// Either size can be read by either build, and full float64 precision
+// is preserved when reading float64 weights in a leabra64 build.
+// ConvertWeightsBinary converts between the two sizes.
+// Because layers are written in sequence, a single layer or path can be read
+// from the stream without decoding the rest of the weights, which supports
+// transplanting pretrained sub-circuits between models (see ReadWtsLayer).
+
+// weightsBinaryMagic is the identifier at the start of binary weights.
+const weightsBinaryMagic = "LWTB"
+
+// weightsBinaryVersion is the current version of the binary weights format.
+const weightsBinaryVersion = 2
+
+// WeightsBinaryFilename returns default current binary weights file name,
+// using train run and epoch counters from looper
+// and the RunName string identifying tag, parameters and starting run.
+func WeightsBinaryFilename(net *Network, ctrString, runName string) string {
+	return net.Name + "_" + runName + "_" + ctrString + ".wtb"
+}
+
+// SaveWeightsBinary saves network weights (and any other state that adapts
+// with learning) to a gzip-compressed binary file.
+func (nt *Network) SaveWeightsBinary(filename core.Filename) error {
+	fp, err := os.Create(string(filename))
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return nt.WriteWeightsBinary(fp)
+}
+
+// OpenWeightsBinary opens network weights (and any other state that adapts
+// with learning) from a gzip-compressed binary file.
+func (nt *Network) OpenWeightsBinary(filename core.Filename) error {
+	fp, err := os.Open(string(filename))
+	if err != nil {
+		return err
+	}
+	defer fp.Close()
+	return nt.ReadWeightsBinary(fp)
+}
+
+// WriteWeightsBinary writes the weights from this network from the
+// receiver-side perspective in the gzip-compressed binary format.
+func (nt *Network) WriteWeightsBinary(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	bw := &weightsBinaryWriter{w: bufio.NewWriter(gw), size: fmath.Size}
+	bw.header(nt.Name)
+	var onls []*Layer
+	for _, ly := range nt.Layers {
+		if !ly.Off {
+			onls = append(onls, ly)
+		}
+	}
+	bw.uint32(uint32(len(onls)))
+	for _, ly := range onls {
+		ly.writeWeightsBinary(bw)
+	}
+	if bw.err != nil {
+		return bw.err
+	}
+	if err := bw.w.Flush(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ReadWeightsBinary reads network weights from the receiver-side perspective
+// in the gzip-compressed binary format, for all layers in the weights.
+// Returns an error for any layers that are not found in the network.
+func (nt *Network) ReadWeightsBinary(r io.Reader) error {
+	_, err := nt.readWeightsBinary(r, "", "")
+	return err
+}
+
+// ReadWtsLayer reads the weights for the receiving pathways of the given
+// layer, from a gzip-compressed binary weights stream, skipping the weights
+// for all other layers.  This can be used to transplant the weights of a
+// pretrained layer into a different model, which must have a layer of the
+// same name and size, receiving from layers with the same names.
+func (nt *Network) ReadWtsLayer(name string, r io.Reader) error {
+	n, err := nt.readWeightsBinary(r, name, "")
+	if err == nil && n == 0 {
+		err = fmt.Errorf("leabra.ReadWtsLayer: layer %s not found in weights", name)
+	}
+	return err
+}
+
+// ReadWtsPath reads the weights for the single pathway from the send layer
+// to the recv layer, from a gzip-compressed binary weights stream,
+// skipping all other weights.  See ReadWtsLayer for details.
+func (nt *Network) ReadWtsPath(recv, send string, r io.Reader) error {
+	n, err := nt.readWeightsBinary(r, recv, send)
+	if err == nil && n == 0 {
+		err = fmt.Errorf("leabra.ReadWtsPath: pathway from %s to %s not found in weights", send, recv)
+	}
+	return err
+}
+
+// readWeightsBinary reads binary weights, applying those for the given
+// layer (all if empty), and the pathway from the given sending layer
+// (all if empty), returning the number of matching layers (or pathways,
+// if from is set) that were found.
+// When reading a subset of weights, the pathways are set by sending
+// layer name, and the number of receiving units must match.
+func (nt *Network) readWeightsBinary(r io.Reader, lay, from string) (int, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return 0, err
+	}
+	defer gr.Close()
+	br := &weightsBinaryReader{r: bufio.NewReader(gr)}
+	if _, err := br.header(); err != nil {
+		return 0, fmt.Errorf("leabra.ReadWeightsBinary: %w", err)
+	}
+	nl := int(br.uint32())
+	nfound := 0
+	var errs []error
+	for li := 0; li < nl && br.err == nil; li++ {
+		lw, exact := br.layer(func(lnm, fnm string) bool {
+			return (lay == "" || lnm == lay) && (from == "" || fnm == from)
+		})
+		if br.err != nil || (lay != "" && lw.Layer != lay) {
+			continue
+		}
+		ly := nt.LayerByName(lw.Layer)
+		if ly == nil {
+			errs = append(errs, fmt.Errorf("leabra.ReadWeightsBinary: layer %s not found", lw.Layer))
+			continue
+		}
+		if lay == "" {
+			nfound++
+			errs = append(errs, ly.SetWeights(lw))
+			for pi := range exact {
+				if pt, err := ly.RecvPathBySendName(lw.Paths[pi].From); err == nil {
+					pt.(*Path).setWeightsExact(&lw.Paths[pi], exact[pi])
+				}
+			}
+			continue
+		}
+		if from == "" {
+			nfound++
+			errs = append(errs, ly.SetWeights(&weights.Layer{MetaData: lw.MetaData})) // ActAvg, Excit, CtxtLag
+		}
+		for pi := range lw.Paths {
+			pw := &lw.Paths[pi]
+			if from != "" {
+				nfound++
+			}
+			pt, err := ly.RecvPathBySendName(pw.From)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			err = pt.(*Path).setWeightsChecked(pw)
+			if err == nil && exact != nil {
+				pt.(*Path).setWeightsExact(pw, exact[pi])
+			}
+			errs = append(errs, err)
+		}
+	}
+	if br.err != nil {
+		errs = append(errs, fmt.Errorf("leabra.ReadWeightsBinary: %w", br.err))
+	}
+	return nfound, errors.Join(errs...)
+}
+
+// writeWeightsBinary writes the weights for this layer in the binary format.
+func (ly *Layer) writeWeightsBinary(bw *weightsBinaryWriter) {
+	bw.string(ly.Name)
+	md := map[string]string{
+		"ActMAvg": fmt.Sprintf("%g", ly.Pools[0].ActAvg.ActMAvg),
+		"ActPAvg": fmt.Sprintf("%g", ly.Pools[0].ActAvg.ActPAvg),
+	}
+	if ly.HasExcit() {
+		md["Excit"] = ly.neuronValuesString(func(nrn *Neuron) Float { return nrn.Excit })
+	}
+	if ly.HasCtxtLag() {
+		md["CtxtLag"] = ly.neuronValuesString(func(nrn *Neuron) Float { return nrn.CtxtLag })
+	}
+	bw.meta(md)
+	var onps []*Path
+	for _, pt := range ly.RecvPaths {
+		if !pt.Off {
+			onps = append(onps, pt)
+		}
+	}
+	bw.uint32(uint32(len(onps)))
+	for _, pt := range onps {
+		pt.writeWeightsBinary(bw)
+	}
+}
+
+// writeWeightsBinary writes the weights for this pathway in the binary format.
+func (pt *Path) writeWeightsBinary(bw *weightsBinaryWriter) {
+	bw.string(pt.Send.Name)
+	bw.meta(map[string]string{"GScale": fmt.Sprintf("%g", pt.GScale)})
+	nr := len(pt.Recv.Neurons)
+	bw.uint32(uint32(nr))
+	for ri := 0; ri < nr; ri++ {
+		nc := int(pt.RConN[ri])
+		st := int(pt.RConIndexSt[ri])
+		bw.uint32(uint32(ri))
+		bw.uint32(uint32(nc))
+		for ci := 0; ci < nc; ci++ {
+			bw.uint32(uint32(pt.RConIndex[st+ci]))
+		}
+		for ci := 0; ci < nc; ci++ {
+			bw.float(float64(pt.Syns.Wt[pt.RSynIndex[st+ci]]))
+		}
+	}
+}
+
+// setWeightsChecked calls SetWeights after checking that the number of
+// receiving units in the weights matches the receiving layer.
+func (pt *Path) setWeightsChecked(pw *weights.Path) error {
+	if nr := len(pt.Recv.Neurons); len(pw.Rs) != nr {
+		return fmt.Errorf("leabra.SetWeights: pathway %s has %d receiving units, but weights have %d", pt.Name, nr, len(pw.Rs))
+	}
+	return pt.SetWeights(pw)
+}
+
+// setWeightsExact sets the weights to the given full precision values,
+// for each of the connections in the weights in order, which are
+// otherwise rounded to float32 by SetWeights.
+// Only has an effect in a leabra64 build.
+func (pt *Path) setWeightsExact(pw *weights.Path, wts []float64) {
+	if fmath.Size != 8 {
+		return
+	}
+	i := 0
+	for ri := range pw.Rs {
+		pr := &pw.Rs[ri]
+		for _, si := range pr.Si {
+			syi := pt.SynIndex(si, pr.Ri)
+			if syi >= 0 && i < len(wts) {
+				pt.Syns.Wt[syi] = Float(wts[i])
+				pt.LWtFromWt(syi)
+			}
+			i++
+		}
+	}
+}
+
+// ConvertWeightsBinary converts binary weights read from r into weights
+// with float32 (bits = 32) or float64 (bits = 64) values, written to w.
+// This allows weights saved by a leabra64 verification build to be used
+// in a standard build, and vice-versa.  Converting float32 to float64
+// values is exact, while the reverse rounds to the nearest float32.
+func ConvertWeightsBinary(r io.Reader, w io.Writer, bits int) error {
+	if bits != 32 && bits != 64 {
+		return fmt.Errorf("leabra.ConvertWeightsBinary: bits must be 32 or 64, not: %d", bits)
+	}
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+	br := &weightsBinaryReader{r: bufio.NewReader(gr)}
+	name, err := br.header()
+	if err != nil {
+		return fmt.Errorf("leabra.ConvertWeightsBinary: %w", err)
+	}
+	gw := gzip.NewWriter(w)
+	bw := &weightsBinaryWriter{w: bufio.NewWriter(gw), size: bits / 8}
+	bw.header(name)
+	nl := br.uint32()
+	bw.uint32(nl)
+	for li := 0; li < int(nl) && br.err == nil; li++ {
+		bw.string(br.string())
+		bw.meta(br.meta())
+		np := br.uint32()
+		bw.uint32(np)
+		for pi := 0; pi < int(np) && br.err == nil; pi++ {
+			bw.string(br.string())
+			bw.meta(br.meta())
+			nr := br.uint32()
+			bw.uint32(nr)
+			for ri := 0; ri < int(nr) && br.err == nil; ri++ {
+				bw.uint32(br.uint32())
+				nc := br.uint32()
+				bw.uint32(nc)
+				for ci := 0; ci < int(nc); ci++ {
+					bw.uint32(br.uint32())
+				}
+				for ci := 0; ci < int(nc); ci++ {
+					bw.float(br.float())
+				}
+			}
+		}
+	}
+	if br.err != nil {
+		return fmt.Errorf("leabra.ConvertWeightsBinary: %w", br.err)
+	}
+	if bw.err != nil {
+		return bw.err
+	}
+	if err := bw.w.Flush(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// ConvertWeightsBinaryFile converts the binary weights file from
+// into a file with float32 (bits = 32) or float64 (bits = 64) values.
+// See ConvertWeightsBinary.
+func ConvertWeightsBinaryFile(from, to core.Filename, bits int) error {
+	rf, err := os.Open(string(from))
+	if err != nil {
+		return err
+	}
+	defer rf.Close()
+	wf, err := os.Create(string(to))
+	if err != nil {
+		return err
+	}
+	if err := ConvertWeightsBinary(rf, wf, bits); err != nil {
+		wf.Close()
+		return err
+	}
+	return wf.Close()
+}
+
+// weightsBinaryWriter writes binary weights values, recording the first error.
+type weightsBinaryWriter struct {
+	w *bufio.Writer
+
+	// size of the weight values in bytes: 4 or 8.
+	size int
+	buf  [8]byte
+	err  error
+}
+
+// header writes the format identifier, version, value size and
+// network name.
+func (bw *weightsBinaryWriter) header(name string) {
+	bw.raw([]byte(weightsBinaryMagic))
+	bw.uint32(weightsBinaryVersion)
+	bw.uint32(uint32(bw.size))
+	bw.string(name)
+}
+
+func (bw *weightsBinaryWriter) raw(b []byte) {
+	if bw.err == nil {
+		_, bw.err = bw.w.Write(b)
+	}
+}
+
+func (bw *weightsBinaryWriter) uint32(v uint32) {
+	binary.LittleEndian.PutUint32(bw.buf[:], v)
+	bw.raw(bw.buf[:4])
+}
+
+// float writes a weight value at the value size.
+func (bw *weightsBinaryWriter) float(v float64) {
+	if bw.size == 8 {
+		binary.LittleEndian.PutUint64(bw.buf[:], math.Float64bits(v))
+		bw.raw(bw.buf[:])
+		return
+	}
+	bw.uint32(math.Float32bits(float32(v)))
+}
+
+func (bw *weightsBinaryWriter) string(s string) {
+	bw.uint32(uint32(len(s)))
+	bw.raw([]byte(s))
+}
+
+func (bw *weightsBinaryWriter) meta(md map[string]string) {
+	kys := maps.Keys(md)
+	sort.Strings(kys)
+	bw.uint32(uint32(len(kys)))
+	for _, k := range kys {
+		bw.string(k)
+		bw.string(md[k])
+	}
+}
+
+// weightsBinaryReader reads binary weights values, recording the first error,
+// after which all values are returned as zero.
+type weightsBinaryReader struct {
+	r *bufio.Reader
+
+	// size of the weight values in bytes: 4 or 8, from the header.
+	size int
+	buf  []byte
+	err  error
+}
+
+// header reads and checks the format identifier, version and value size,
+// returning the network name.
+func (br *weightsBinaryReader) header() (string, error) {
+	magic := br.raw(len(weightsBinaryMagic))
+	if br.err == nil && string(magic) != weightsBinaryMagic {
+		return "", errors.New("not a binary weights file")
+	}
+	ver := br.uint32()
+	if br.err == nil && ver > weightsBinaryVersion {
+		return "", fmt.Errorf("unsupported version: %d", ver)
+	}
+	br.size = 4
+	if ver >= 2 {
+		br.size = int(br.uint32())
+		if br.err == nil && br.size != 4 && br.size != 8 {
+			return "", fmt.Errorf("invalid value size: %d", br.size)
+		}
+	}
+	name := br.string()
+	return name, br.err
+}
+
+func (br *weightsBinaryReader) raw(n int) []byte {
+	if br.err != nil {
+		return nil
+	}
+	br.buf = slices.Grow(br.buf[:0], n)[:n]
+	_, br.err = io.ReadFull(br.r, br.buf)
+	return br.buf
+}
+
+func (br *weightsBinaryReader) uint32() uint32 {
+	b := br.raw(4)
+	if br.err != nil {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b)
+}
+
+// float reads a weight value at the value size.
+func (br *weightsBinaryReader) float() float64 {
+	if br.size == 8 {
+		b := br.raw(8)
+		if br.err != nil {
+			return 0
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(b))
+	}
+	return float64(math.Float32frombits(br.uint32()))
+}
+
+func (br *weightsBinaryReader) string() string {
+	return string(br.raw(int(br.uint32())))
+}
+
+func (br *weightsBinaryReader) meta() map[string]string {
+	n := int(br.uint32())
+	if n == 0 {
+		return nil
+	}
+	md := make(map[string]string, n)
+	for i := 0; i < n && br.err == nil; i++ {
+		k := br.string()
+		md[k] = br.string()
+	}
+	return md
+}
+
+// layer reads the weights for one layer, only decoding the pathways for
+// which sel returns true, and skipping the rest.
+// For float64 values, it also returns the full precision weights
+// for each decoded pathway, in order, for setWeightsExact.
+func (br *weightsBinaryReader) layer(sel func(lnm, from string) bool) (*weights.Layer, [][]float64) {
+	lw := &weights.Layer{Layer: br.string(), MetaData: br.meta()}
+	var exact [][]float64
+	np := int(br.uint32())
+	for pi := 0; pi < np && br.err == nil; pi++ {
+		pw := weights.Path{From: br.string(), MetaData: br.meta()}
+		use := sel(lw.Layer, pw.From)
+		nr := int(br.uint32())
+		var wts []float64
+		if use {
+			pw.Rs = make([]weights.Recv, nr)
+		}
+		for ri := 0; ri < nr && br.err == nil; ri++ {
+			rix := int(br.uint32())
+			nc := int(br.uint32())
+			if !use {
+				br.raw((4 + br.size) * nc)
+				continue
+			}
+			rw := &pw.Rs[ri]
+			rw.Ri = rix
+			rw.N = nc
+			rw.Si = make([]int, nc)
+			rw.Wt = make([]float32, nc)
+			for ci := range rw.Si {
+				rw.Si[ci] = int(br.uint32())
+			}
+			for ci := range rw.Wt {
+				wt := br.float()
+				rw.Wt[ci] = float32(wt)
+				if br.size == 8 {
+					wts = append(wts, wt)
+				}
+			}
+		}
+		if use {
+			lw.Paths = append(lw.Paths, pw)
+			if br.size == 8 {
+				exact = append(exact, wts)
+			}
+		}
+	}
+	return lw, exact
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/emer/leabra/v2/fmath"
+)
+
+func TestWeightsBinary(t *testing.T) {
+	srcNet := MakeTestNet(t)
+	hidLay := srcNet.LayerByName("Hidden")
+	outLay := srcNet.LayerByName("Output")
+	hidLay.RecvPaths[0].SetSynValue("Wt", 1, 1, .15)
+	outLay.RecvPaths[0].SetSynValue("Wt", 2, 2, .85)
+
+	var buf bytes.Buffer
+	if err := srcNet.WriteWeightsBinary(&buf); err != nil {
+		t.Error(err)
+	}
+	wb := buf.Bytes()
+
+	testNet := MakeTestNet(t)
+	if err := testNet.ReadWeightsBinary(bytes.NewReader(wb)); err != nil {
+		t.Error(err)
+	}
+	hidWt := testNet.LayerByName("Hidden").RecvPaths[0].SynValue("Wt", 1, 1)
+	outWt := testNet.LayerByName("Output").RecvPaths[0].SynValue("Wt", 2, 2)
+	CmprFloats([]float32{hidWt, outWt}, []float32{.15, .85}, "binary weights", t)
+
+	testNet = MakeTestNet(t)
+	if err := testNet.ReadWtsLayer("Hidden", bytes.NewReader(wb)); err != nil {
+		t.Error(err)
+	}
+	hidWt = testNet.LayerByName("Hidden").RecvPaths[0].SynValue("Wt", 1, 1)
+	outWt = testNet.LayerByName("Output").RecvPaths[0].SynValue("Wt", 2, 2)
+	CmprFloats([]float32{hidWt, outWt}, []float32{.15, .5}, "binary weights ReadWtsLayer", t)
+
+	if err := testNet.ReadWtsLayer("CA3", bytes.NewReader(wb)); err == nil {
+		t.Errorf("ReadWtsLayer should return error for missing layer")
+	}
+}
+
+func TestConvertWeightsBinary(t *testing.T) {
+	srcNet := MakeTestNet(t)
+	srcNet.LayerByName("Hidden").RecvPaths[0].SetSynValue("Wt", 1, 1, .15)
+	var buf bytes.Buffer
+	if err := srcNet.WriteWeightsBinary(&buf); err != nil {
+		t.Error(err)
+	}
+	for _, bits := range []int{64, 32} {
+		var cb bytes.Buffer
+		if err := ConvertWeightsBinary(bytes.NewReader(buf.Bytes()), &cb, bits); err != nil {
+			t.Error(err)
+		}
+		testNet := MakeTestNet(t)
+		if err := testNet.ReadWeightsBinary(bytes.NewReader(cb.Bytes())); err != nil {
+			t.Error(err)
+		}
+		hidWt := testNet.LayerByName("Hidden").RecvPaths[0].SynValue("Wt", 1, 1)
+		CmprFloats([]float32{hidWt}, []float32{.15}, "converted binary weights", t)
+		if bits >= fmath.Bits && testNet.WtsFingerprint() != srcNet.WtsFingerprint() {
+			t.Errorf("weights converted to %d bits do not match exactly", bits)
+		}
+	}
+	if err := ConvertWeightsBinary(bytes.NewReader(buf.Bytes()), io.Discard, 16); err == nil {
+		t.Errorf("ConvertWeightsBinary should return error for 16 bits")
+	}
+}
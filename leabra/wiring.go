@@ -0,0 +1,308 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+// WiringStats are statistics of the effective connectivity of a pathway,
+// typically after training, for comparison with anatomical data, e.g.,
+// the number of mossy fiber inputs onto each CA3 neuron.  Structural
+// connectivity is the number of synapses, and effective connectivity
+// counts only synapses with an effective weight (Wt * Scale) at or above
+// a threshold, which reflects the activity-dependent refinement of the
+// connections by learning.
+type WiringStats struct {
+
+	// Path is the name of the pathway.
+	Path string
+
+	// NSyns is the total number of synapses.
+	NSyns int
+
+	// Conv is the mean convergence: the number of synapses
+	// per receiving neuron.
+	Conv float64
+
+	// Div is the mean divergence: the number of synapses
+	// per sending neuron.
+	Div float64
+
+	// EffConv is the mean effective convergence: the number of synapses per
+	// receiving neuron with an effective weight at or above the threshold.
+	EffConv float64
+
+	// EffConvSD is the standard deviation of the effective convergence
+	// across receiving neurons.
+	EffConvSD float64
+
+	// EffDiv is the mean effective divergence: the number of synapses per
+	// sending neuron with an effective weight at or above the threshold.
+	EffDiv float64
+
+	// EffProp is the proportion of synapses with an effective weight
+	// at or above the threshold.
+	EffProp float64
+
+	// WtMean is the mean effective weight.
+	WtMean float64
+
+	// WtSD is the standard deviation of the effective weights.
+	WtSD float64
+
+	// WtHist is the histogram of effective weights, in WiringHistBins
+	// equal bins from 0 to 1 (with weights above 1 in the last bin),
+	// as the proportion of synapses in each bin.
+	WtHist []float64
+}
+
+// WiringHistBins is the number of bins in the WiringStats WtHist.
+var WiringHistBins = 10
+
+// Stat returns the value of the named stat: NSyns, Conv, Div, EffConv,
+// EffConvSD, EffDiv, EffProp, WtMean, or WtSD.
+func (ws *WiringStats) Stat(name string) (float64, error) {
+	switch name {
+	case "NSyns":
+		return float64(ws.NSyns), nil
+	case "Conv":
+		return ws.Conv, nil
+	case "Div":
+		return ws.Div, nil
+	case "EffConv":
+		return ws.EffConv, nil
+	case "EffConvSD":
+		return ws.EffConvSD, nil
+	case "EffDiv":
+		return ws.EffDiv, nil
+	case "EffProp":
+		return ws.EffProp, nil
+	case "WtMean":
+		return ws.WtMean, nil
+	case "WtSD":
+		return ws.WtSD, nil
+	}
+	return 0, fmt.Errorf("leabra.WiringStats: stat %s not found", name)
+}
+
+// WiringStats computes the wiring stats for the pathway, with thr as the
+// effective weight threshold for effective connectivity.
+func (pt *Path) WiringStats(thr Float) *WiringStats {
+	ws := &WiringStats{Path: pt.Name, WtHist: make([]float64, WiringHistBins)}
+	nr := len(pt.RConN)
+	ns := len(pt.SConN)
+	ws.NSyns = pt.Syns.Len()
+	if ws.NSyns == 0 {
+		return ws
+	}
+	var sumConv, sumConvSq float64
+	var wsum, wsumSq float64
+	nEff := 0
+	for ri := range nr {
+		nc := int(pt.RConN[ri])
+		st := int(pt.RConIndexSt[ri])
+		neff := 0
+		for ci := st; ci < st+nc; ci++ {
+			syi := pt.RSynIndex[ci]
+			w := pt.Syns.Wt[syi] * pt.Syns.Scale[syi]
+			wsum += float64(w)
+			wsumSq += float64(w * w)
+			bin := min(max(int(w*Float(WiringHistBins)), 0), WiringHistBins-1)
+			ws.WtHist[bin]++
+			if w >= thr {
+				neff++
+			}
+		}
+		nEff += neff
+		sumConv += float64(neff)
+		sumConvSq += float64(neff * neff)
+	}
+	n := float64(ws.NSyns)
+	ws.Conv = n / float64(max(nr, 1))
+	ws.Div = n / float64(max(ns, 1))
+	ws.EffConv = sumConv / float64(max(nr, 1))
+	ws.EffConvSD = math.Sqrt(max(sumConvSq/float64(max(nr, 1))-ws.EffConv*ws.EffConv, 0))
+	ws.EffDiv = float64(nEff) / float64(max(ns, 1))
+	ws.EffProp = float64(nEff) / n
+	ws.WtMean = wsum / n
+	ws.WtSD = math.Sqrt(max(wsumSq/n-ws.WtMean*ws.WtMean, 0))
+	for i := range ws.WtHist {
+		ws.WtHist[i] /= n
+	}
+	return ws
+}
+
+// WiringStats computes the wiring stats for all the pathways in the
+// network that are not Off, with thr as the effective weight threshold.
+func (nt *Network) WiringStats(thr Float) []*WiringStats {
+	var wss []*WiringStats
+	for _, ly := range nt.Layers {
+		for _, pt := range ly.RecvPaths {
+			if pt.Off {
+				continue
+			}
+			wss = append(wss, pt.WiringStats(thr))
+		}
+	}
+	return wss
+}
+
+// WiringTable returns a table of the given wiring stats, one row per pathway,
+// with a column for each stat, and a WtHist tensor column.
+func WiringTable(wss []*WiringStats) *table.Table {
+	dt := table.NewTable()
+	dt.AddStringColumn("Path")
+	stats := []string{"NSyns", "Conv", "Div", "EffConv", "EffConvSD", "EffDiv", "EffProp", "WtMean", "WtSD"}
+	for _, st := range stats {
+		dt.AddFloat64Column(st)
+	}
+	dt.AddFloat64TensorColumn("WtHist", []int{WiringHistBins}, "Bin")
+	dt.SetNumRows(len(wss))
+	for row, ws := range wss {
+		dt.SetString("Path", row, ws.Path)
+		for _, st := range stats {
+			v, _ := ws.Stat(st)
+			dt.SetFloat(st, row, v)
+		}
+		for i, h := range ws.WtHist {
+			dt.SetTensorFloat1D("WtHist", row, i, h)
+		}
+	}
+	return dt
+}
+
+// WiringTarget is an anatomical target distribution for a wiring stat
+// of a pathway, e.g., the number of mossy fiber inputs per CA3 neuron
+// (EffConv of DGToCA3), as a mean and standard deviation.
+type WiringTarget struct {
+
+	// Path is the name of the pathway.
+	Path string
+
+	// Stat is the name of the stat (see WiringStats.Stat).
+	Stat string
+
+	// Mean is the target mean value.
+	Mean float64
+
+	// SD is the standard deviation of the target value, which determines
+	// the tolerance for discrepancies.  If 0, 10% of the Mean is used.
+	SD float64
+}
+
+// WiringDiscrepancy is the comparison of a wiring stat with its target.
+type WiringDiscrepancy struct {
+	WiringTarget
+
+	// Value is the value of the stat in the network.
+	Value float64
+
+	// Z is the difference of the Value from the target Mean,
+	// in units of the target SD.
+	Z float64
+
+	// Ratio is the ratio of the Value to the target Mean, which is
+	// useful when the network is a scaled-down model of the anatomy.
+	Ratio float64
+
+	// Mismatch is true if the absolute value of Z exceeds the tolerance.
+	Mismatch bool
+}
+
+// String returns a one-line report of the discrepancy.
+func (wd *WiringDiscrepancy) String() string {
+	flag := ""
+	if wd.Mismatch {
+		flag = "\tMISMATCH"
+	}
+	return fmt.Sprintf("%s\t%s:\t%.4g vs. target %.4g ± %.4g\tz: %.3g\tratio: %.3g%s", wd.Path, wd.Stat, wd.Value, wd.Mean, wd.SD, wd.Z, wd.Ratio, flag)
+}
+
+// CompareWiring compares the given wiring stats with the given targets,
+// flagging as mismatches those with an absolute z-score above the given
+// tolerance (e.g., 2).  Targets for pathways not in the stats are
+// reported as errors.
+func CompareWiring(wss []*WiringStats, targets []WiringTarget, tol float64) ([]WiringDiscrepancy, error) {
+	byName := make(map[string]*WiringStats, len(wss))
+	for _, ws := range wss {
+		byName[ws.Path] = ws
+	}
+	var errs []error
+	var wds []WiringDiscrepancy
+	for _, tg := range targets {
+		ws, ok := byName[tg.Path]
+		if !ok {
+			errs = append(errs, fmt.Errorf("leabra.CompareWiring: pathway %s not found", tg.Path))
+			continue
+		}
+		v, err := ws.Stat(tg.Stat)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		wd := WiringDiscrepancy{WiringTarget: tg, Value: v}
+		if wd.SD == 0 {
+			wd.SD = 0.1 * math.Abs(wd.Mean)
+		}
+		if wd.SD > 0 {
+			wd.Z = (v - wd.Mean) / wd.SD
+		}
+		if wd.Mean != 0 {
+			wd.Ratio = v / wd.Mean
+		}
+		wd.Mismatch = math.Abs(wd.Z) > tol
+		wds = append(wds, wd)
+	}
+	return wds, errors.Join(errs...)
+}
+
+// WiringReport returns a report of the discrepancies, one per line,
+// with the number of mismatches at the end.
+func WiringReport(wds []WiringDiscrepancy) string {
+	var b strings.Builder
+	nmis := 0
+	for i := range wds {
+		b.WriteString(wds[i].String())
+		b.WriteString("\n")
+		if wds[i].Mismatch {
+			nmis++
+		}
+	}
+	fmt.Fprintf(&b, "%d of %d wiring stats mismatch their targets\n", nmis, len(wds))
+	return b.String()
+}
+
+// OpenWiringTargets opens wiring targets from a tab-separated file
+// with a header row and Path, Stat, Mean, and SD columns.
+func OpenWiringTargets(filename core.Filename) ([]WiringTarget, error) {
+	dt := table.NewTable()
+	if err := dt.OpenCSV(filename, table.Tab); err != nil {
+		return nil, err
+	}
+	for _, col := range []string{"Path", "Stat", "Mean"} {
+		if _, err := dt.ColumnByName(col); err != nil {
+			return nil, fmt.Errorf("leabra.OpenWiringTargets: %w", err)
+		}
+	}
+	_, sdErr := dt.ColumnByName("SD")
+	targets := make([]WiringTarget, dt.Rows)
+	for row := range dt.Rows {
+		tg := &targets[row]
+		tg.Path = dt.StringValue("Path", row)
+		tg.Stat = dt.StringValue("Stat", row)
+		tg.Mean = dt.Float("Mean", row)
+		if sdErr == nil {
+			tg.SD = dt.Float("SD", row)
+		}
+	}
+	return targets, nil
+}
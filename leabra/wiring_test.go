@@ -0,0 +1,44 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestWiringStats(t *testing.T) {
+	net := NewNetwork("Wiring")
+	in := net.AddLayer2D("Input", 1, 4, InputLayer)
+	hid := net.AddLayer2D("Hidden", 1, 2, SuperLayer)
+	pt := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	net.InitWeights()
+	for si := range pt.Syns.Wt {
+		pt.Syns.Wt[si] = 0.15
+		pt.Syns.Scale[si] = 1
+	}
+	// 3 strong weights to receiver 0, 1 to receiver 1
+	for _, s := range []int{0, 1, 2} {
+		pt.SetSynValue("Wt", s, 0, 0.95)
+	}
+	pt.SetSynValue("Wt", 3, 1, 0.95)
+	ws := pt.WiringStats(0.5)
+	CmprFloats([]float32{float32(ws.Conv), float32(ws.Div), float32(ws.EffConv), float32(ws.EffConvSD), float32(ws.EffDiv), float32(ws.EffProp), float32(ws.WtMean)},
+		[]float32{4, 2, 2, 1, 1, 0.5, 0.55}, "WiringStats", t)
+	CmprFloats([]float32{float32(ws.WtHist[1]), float32(ws.WtHist[9])}, []float32{0.5, 0.5}, "WiringStats WtHist", t)
+
+	wds, err := CompareWiring(net.WiringStats(0.5), []WiringTarget{{Path: pt.Name, Stat: "EffConv", Mean: 2, SD: 0.5}, {Path: pt.Name, Stat: "EffDiv", Mean: 4, SD: 1}}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wds[0].Mismatch || !wds[1].Mismatch || wds[1].Z != -3 {
+		t.Errorf("CompareWiring: %s", WiringReport(wds))
+	}
+}
@@ -0,0 +1,217 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"cogentcore.org/core/core"
+)
+
+// OpenWeights opens network weights from the given file, according to
+// its extension: .wtb for binary weights (see OpenWeightsBinary), .zip
+// for the weights in a checkpoint archive (see SaveCheckpoint), and
+// otherwise JSON weights (.wts or .wts.gz, see OpenWeightsJSON).
+func (nt *Network) OpenWeights(filename core.Filename) error {
+	fn := string(filename)
+	switch {
+	case strings.HasSuffix(fn, ".wtb"):
+		return nt.OpenWeightsBinary(filename)
+	case strings.HasSuffix(fn, ".zip"):
+		zr, err := zip.OpenReader(fn)
+		if err != nil {
+			return err
+		}
+		defer zr.Close()
+		return checkpointReadFile(&zr.Reader, checkpointWeightsFile, nt.ReadWeightsJSON)
+	}
+	return nt.OpenWeightsJSON(filename)
+}
+
+// WeightsAverage accumulates the weights of networks with identical
+// architecture, e.g., checkpoints at different points in training, or the
+// final weights of different runs, to compute the average "consensus"
+// weights, which are more robust to the noise of any one set of weights.
+// The average activity levels (ActAvg) of each layer, which are saved
+// with the weights and determine the netinput scaling, are also averaged.
+// Note that averaging the weights of runs that learned different
+// representations (e.g., hidden units in a different order) blurs them
+// together, so averaging is most meaningful for checkpoints from the same
+// run, or runs from the same initial weights.
+type WeightsAverage struct {
+
+	// N is the number of networks that have been added.
+	N int
+
+	// wts are the sums of weights for each pathway, in the order of
+	// the layers and their RecvPaths, by synapse index.
+	wts [][]float64
+
+	// acts are the sums of ActMAvg, ActPAvg for each layer.
+	acts [][2]float64
+}
+
+// Reset resets the accumulated weights.
+func (wa *WeightsAverage) Reset() {
+	wa.N = 0
+	wa.wts = nil
+	wa.acts = nil
+}
+
+// Add adds the current weights of the given network, returning an error
+// if its architecture differs from the previously added networks.
+func (wa *WeightsAverage) Add(net *Network) error {
+	if wa.N == 0 {
+		wa.wts = nil
+		wa.acts = make([][2]float64, len(net.Layers))
+		for _, ly := range net.Layers {
+			for _, pt := range ly.RecvPaths {
+				wa.wts = append(wa.wts, make([]float64, pt.Syns.Len()))
+			}
+		}
+	}
+	if len(net.Layers) != len(wa.acts) {
+		return fmt.Errorf("leabra.WeightsAverage: network %s has %d layers, not %d", net.Name, len(net.Layers), len(wa.acts))
+	}
+	pi := 0
+	for li, ly := range net.Layers {
+		wa.acts[li][0] += float64(ly.Pools[0].ActAvg.ActMAvg)
+		wa.acts[li][1] += float64(ly.Pools[0].ActAvg.ActPAvg)
+		for _, pt := range ly.RecvPaths {
+			if pi >= len(wa.wts) || len(wa.wts[pi]) != pt.Syns.Len() {
+				return fmt.Errorf("leabra.WeightsAverage: pathway %s does not match the previous networks", pt.Name)
+			}
+			sum := wa.wts[pi]
+			for si, wt := range pt.Syns.Wt {
+				sum[si] += float64(wt)
+			}
+			pi++
+		}
+	}
+	if pi != len(wa.wts) {
+		return fmt.Errorf("leabra.WeightsAverage: network %s has %d pathways, not %d", net.Name, pi, len(wa.wts))
+	}
+	wa.N++
+	return nil
+}
+
+// AddFiles opens each of the given weights files into the network
+// (see OpenWeights), and adds them.
+func (wa *WeightsAverage) AddFiles(net *Network, files ...core.Filename) error {
+	var errs []error
+	for _, fn := range files {
+		if err := net.OpenWeights(fn); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := wa.Add(net); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", fn, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// SetWeights sets the weights of the given network to the average of
+// the added networks, which must have the same architecture.
+func (wa *WeightsAverage) SetWeights(net *Network) error {
+	if wa.N == 0 {
+		return errors.New("leabra.WeightsAverage: no networks have been added")
+	}
+	if len(net.Layers) != len(wa.acts) {
+		return fmt.Errorf("leabra.WeightsAverage: network %s has %d layers, not %d", net.Name, len(net.Layers), len(wa.acts))
+	}
+	n := float64(wa.N)
+	pi := 0
+	for li, ly := range net.Layers {
+		for _, pt := range ly.RecvPaths {
+			if pi >= len(wa.wts) || len(wa.wts[pi]) != pt.Syns.Len() {
+				return fmt.Errorf("leabra.WeightsAverage: pathway %s does not match the added networks", pt.Name)
+			}
+			for si, sum := range wa.wts[pi] {
+				pt.Syns.Wt[si] = Float(sum / n)
+				pt.LWtFromWt(si)
+			}
+			pi++
+		}
+		pl := &ly.Pools[0]
+		pl.ActAvg.ActMAvg = Float(wa.acts[li][0] / n)
+		pl.ActAvg.ActPAvg = Float(wa.acts[li][1] / n)
+		ly.Inhib.ActAvg.EffFromAvg(&pl.ActAvg.ActPAvgEff, pl.ActAvg.ActPAvg)
+	}
+	return nil
+}
+
+// AverageWeightsFiles sets the weights of the network to the average
+// of the weights in the given files (see WeightsAverage and OpenWeights).
+func AverageWeightsFiles(net *Network, files ...core.Filename) error {
+	wa := &WeightsAverage{}
+	if err := wa.AddFiles(net, files...); err != nil {
+		return err
+	}
+	return wa.SetWeights(net)
+}
+
+// EnsembleEval is the evaluation of an ensemble of weights files,
+// with the performance of each member, and of the average weights.
+type EnsembleEval struct {
+
+	// Files are the weights files of the ensemble members.
+	Files []core.Filename
+
+	// Members are the evaluated performance of each member.
+	Members []float64
+
+	// Mean is the mean performance across the members.
+	Mean float64
+
+	// SD is the standard deviation of the performance across members.
+	SD float64
+
+	// Average is the performance of the network with the average
+	// weights across the members.
+	Average float64
+}
+
+// String returns a summary of the evaluation.
+func (ee *EnsembleEval) String() string {
+	return fmt.Sprintf("Ensemble of %d: Members Mean: %g SD: %g  Average Weights: %g", len(ee.Members), ee.Mean, ee.SD, ee.Average)
+}
+
+// EvalWeightsEnsemble evaluates each of the given weights files, loaded
+// into the network, and the average of the weights, using the given eval
+// function, which typically runs the test trials and returns a performance
+// measure (e.g., percent correct).  The network is left with the average
+// weights.
+func EvalWeightsEnsemble(net *Network, files []core.Filename, eval func(net *Network) float64) (*EnsembleEval, error) {
+	ee := &EnsembleEval{Files: files}
+	wa := &WeightsAverage{}
+	for _, fn := range files {
+		if err := net.OpenWeights(fn); err != nil {
+			return nil, err
+		}
+		if err := wa.Add(net); err != nil {
+			return nil, fmt.Errorf("%s: %w", fn, err)
+		}
+		ee.Members = append(ee.Members, eval(net))
+	}
+	if err := wa.SetWeights(net); err != nil {
+		return nil, err
+	}
+	ee.Average = eval(net)
+	n := float64(len(ee.Members))
+	for _, v := range ee.Members {
+		ee.Mean += v
+	}
+	ee.Mean /= n
+	for _, v := range ee.Members {
+		ee.SD += (v - ee.Mean) * (v - ee.Mean)
+	}
+	ee.SD = math.Sqrt(ee.SD / n)
+	return ee, nil
+}
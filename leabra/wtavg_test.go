@@ -0,0 +1,43 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"testing"
+)
+
+func TestWeightsAverage(t *testing.T) {
+	net1 := MakeTestNet(t)
+	net2 := MakeTestNet(t)
+	net1.LayerByName("Hidden").RecvPaths[0].SetSynValue("Wt", 1, 1, .2)
+	net2.LayerByName("Hidden").RecvPaths[0].SetSynValue("Wt", 1, 1, .6)
+	net2.LayerByName("Output").Pools[0].ActAvg.ActPAvg = 0.5
+	wa := &WeightsAverage{}
+	if err := wa.Add(net1); err != nil {
+		t.Fatal(err)
+	}
+	if err := wa.Add(net2); err != nil {
+		t.Fatal(err)
+	}
+	avgNet := MakeTestNet(t)
+	if err := wa.SetWeights(avgNet); err != nil {
+		t.Fatal(err)
+	}
+	hidWt := avgNet.LayerByName("Hidden").RecvPaths[0].SynValue("Wt", 1, 1)
+	outWt := avgNet.LayerByName("Output").RecvPaths[0].SynValue("Wt", 2, 2)
+	actP := avgNet.LayerByName("Output").Pools[0].ActAvg.ActPAvg
+	exActP := (net1.LayerByName("Output").Pools[0].ActAvg.ActPAvg + 0.5) / 2
+	exOutWt := (net1.LayerByName("Output").RecvPaths[0].SynValue("Wt", 2, 2) + net2.LayerByName("Output").RecvPaths[0].SynValue("Wt", 2, 2)) / 2
+	CmprFloats([]float32{hidWt, outWt, float32(actP)}, []float32{.4, exOutWt, float32(exActP)}, "weights average", t)
+
+	other := NewNetwork("Other")
+	other.AddLayer2D("Input", 4, 1, InputLayer)
+	if err := other.Build(); err != nil {
+		t.Fatal(err)
+	}
+	if err := wa.Add(other); err == nil {
+		t.Errorf("WeightsAverage: different architecture should be an error")
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"cogentcore.org/core/base/randx"
+)
+
+// WtIniter is a weight initialization scheme, which generates the initial
+// weights of all the synapses of a pathway, in the 0-1 range prior to the
+// synapse Scale, in the order of the Syns, using the given random number
+// generator.  Schemes are registered by name with RegisterWtInit,
+// and selected with WtInit.Scheme, so that sims can define their own
+// initial weight distributions.
+type WtIniter interface {
+	InitWeights(pt *Path, wts []Float, rnd randx.Rand)
+}
+
+// WtInitFunc is a function that implements the WtIniter interface.
+type WtInitFunc func(pt *Path, wts []Float, rnd randx.Rand)
+
+func (wf WtInitFunc) InitWeights(pt *Path, wts []Float, rnd randx.Rand) {
+	wf(pt, wts, rnd)
+}
+
+// WtInitSchemes are the registered weight initialization schemes,
+// by name.  The standard ones are:
+//   - Rand: each weight drawn from the WtInit random distribution
+//     (Dist, Mean, Var, Par): the default, also used if Scheme is empty.
+//   - LogNormal: Mean * exp(Var * N(0,1) - Var^2 / 2), which has the
+//     given Mean, and a long upper tail for larger Var.
+//   - Sparse: a SparseP proportion of the synapses have weights drawn
+//     from the WtInit random distribution, and the others are 0.
+//     If SparseTopo, the proportion is multiplied by the synapse Scale,
+//     e.g., as set by the topographic weights of the pathway pattern.
+//   - Orthogonal: the weight vectors of the receiving units in each pool
+//     are mutually orthogonal (up to the number of sending units),
+//     with Mean + Var * N(0,1)-scaled values.
+var WtInitSchemes = map[string]WtIniter{
+	"Rand":       WtInitFunc(wtInitRand),
+	"LogNormal":  WtInitFunc(wtInitLogNormal),
+	"Sparse":     WtInitFunc(wtInitSparse),
+	"Orthogonal": WtInitFunc(wtInitOrthogonal),
+}
+
+// RegisterWtInit registers a new weight initialization scheme
+// with the given name, for use in WtInit.Scheme.
+func RegisterWtInit(name string, wi WtIniter) {
+	WtInitSchemes[name] = wi
+}
+
+// WtInitSchemeNames returns the sorted names of the registered
+// weight initialization schemes.
+func WtInitSchemeNames() []string {
+	nms := make([]string, 0, len(WtInitSchemes))
+	for nm := range WtInitSchemes {
+		nms = append(nms, nm)
+	}
+	sort.Strings(nms)
+	return nms
+}
+
+// Initer returns the WtIniter for the Scheme, or an error if
+// it is not registered.  An empty Scheme is Rand.
+func (wp *WtInitParams) Initer() (WtIniter, error) {
+	nm := wp.Scheme
+	if nm == "" {
+		nm = "Rand"
+	}
+	wi, ok := WtInitSchemes[nm]
+	if !ok {
+		return nil, fmt.Errorf("WtInit.Scheme %q is not registered, must be one of: %v", wp.Scheme, WtInitSchemeNames())
+	}
+	return wi, nil
+}
+
+// wtInitRand draws each weight from the WtInit random distribution.
+func wtInitRand(pt *Path, wts []Float, rnd randx.Rand) {
+	for si := range wts {
+		wts[si] = Float(pt.WtInit.Gen(rnd))
+	}
+}
+
+func wtInitLogNormal(pt *Path, wts []Float, rnd randx.Rand) {
+	sd := pt.WtInit.Var
+	for si := range wts {
+		wts[si] = Float(pt.WtInit.Mean * math.Exp(sd*rnd.NormFloat64()-0.5*sd*sd))
+	}
+}
+
+func wtInitSparse(pt *Path, wts []Float, rnd randx.Rand) {
+	for si := range wts {
+		p := pt.WtInit.SparseP
+		if pt.WtInit.SparseTopo && pt.Syns.Scale[si] > 0 {
+			p *= min(pt.Syns.Scale[si], 1)
+		}
+		if Float(rnd.Float64()) < p {
+			wts[si] = Float(pt.WtInit.Gen(rnd))
+		} else {
+			wts[si] = 0
+		}
+	}
+}
+
+// wtInitOrthogonal makes the weight vectors of the receiving units in each
+// pool orthogonal, by Gram-Schmidt orthogonalization of Gaussian random
+// vectors, starting a new orthogonal set after every n units, for n
+// sending connections, or when the number of connections changes.
+func wtInitOrthogonal(pt *Path, wts []Float, rnd randx.Rand) {
+	pools := map[int32][]int{}
+	var order []int32
+	for ri := range pt.Recv.Neurons {
+		pi := pt.Recv.Neurons[ri].SubPool
+		if _, has := pools[pi]; !has {
+			order = append(order, pi)
+		}
+		pools[pi] = append(pools[pi], ri)
+	}
+	for _, pi := range order {
+		var basis [][]float64
+		for _, ri := range pools[pi] {
+			nc := int(pt.RConN[ri])
+			if nc == 0 {
+				continue
+			}
+			if len(basis) >= nc || (len(basis) > 0 && len(basis[0]) != nc) {
+				basis = basis[:0]
+			}
+			v := orthoVector(basis, nc, rnd)
+			basis = append(basis, v)
+			st := int(pt.RConIndexSt[ri])
+			sc := pt.WtInit.Var * math.Sqrt(float64(nc))
+			for ci := range nc {
+				wts[pt.RSynIndex[st+ci]] = Float(pt.WtInit.Mean + sc*v[ci])
+			}
+		}
+	}
+}
+
+// orthoVector returns a random unit vector of length n that is
+// orthogonal to the given orthonormal basis vectors (fewer than n).
+func orthoVector(basis [][]float64, n int, rnd randx.Rand) []float64 {
+	v := make([]float64, n)
+	for range 10 { // retry in the very unlikely case of a degenerate draw
+		for i := range v {
+			v[i] = rnd.NormFloat64()
+		}
+		for _, b := range basis {
+			var d float64
+			for i := range v {
+				d += v[i] * b[i]
+			}
+			for i := range v {
+				v[i] -= d * b[i]
+			}
+		}
+		var nrm float64
+		for _, x := range v {
+			nrm += x * x
+		}
+		nrm = math.Sqrt(nrm)
+		if nrm > 1e-6 {
+			for i := range v {
+				v[i] /= nrm
+			}
+			return v
+		}
+	}
+	return make([]float64, n)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package leabra
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"cogentcore.org/core/base/randx"
+	"github.com/emer/emergent/v2/paths"
+)
+
+func TestWtInitSchemes(t *testing.T) {
+	net := NewNetwork("WtInit")
+	in := net.AddLayer2D("Input", 4, 4, InputLayer)
+	hid := net.AddLayer4D("Hidden", 1, 2, 2, 2, SuperLayer)
+	pt := net.ConnectLayers(in, hid, paths.NewFull(), ForwardPath)
+	if err := net.Build(); err != nil {
+		t.Fatal(err)
+	}
+	net.Defaults()
+	wts := func(scheme string, mean, vr float64) []Float {
+		pt.WtInit.Scheme = scheme
+		pt.WtInit.Mean, pt.WtInit.Var = mean, vr
+		net.InitWeights()
+		return slices.Clone(pt.Syns.Wt)
+	}
+
+	ws := wts("Orthogonal", 0.5, 0.02)
+	for ri := range 8 {
+		for rj := ri + 1; rj < 8; rj++ {
+			if hid.Neurons[ri].SubPool != hid.Neurons[rj].SubPool {
+				continue
+			}
+			var d Float
+			for si := range 16 {
+				d += (ws[si*8+ri] - 0.5) * (ws[si*8+rj] - 0.5)
+			}
+			if math.Abs(float64(d)) > 1.0e-5 {
+				t.Errorf("Orthogonal: units %d, %d dot product: %g", ri, rj, d)
+			}
+		}
+	}
+
+	ws = wts("LogNormal", 0.2, 0.3)
+	var sum Float
+	for _, w := range ws {
+		if w <= 0 {
+			t.Fatalf("LogNormal weight <= 0: %g", w)
+		}
+		sum += w
+	}
+	if mn := sum / Float(len(ws)); math.Abs(float64(mn)-0.2) > 0.03 {
+		t.Errorf("LogNormal mean: %g, not 0.2", mn)
+	}
+
+	pt.WtInit.SparseP = 0.25
+	nz := 0
+	for _, w := range wts("Sparse", 0.5, 0.25) {
+		if w > 0 {
+			nz++
+		}
+	}
+	if p := float64(nz) / 128; math.Abs(p-0.25) > 0.1 {
+		t.Errorf("Sparse: proportion non-zero: %g, not 0.25", p)
+	}
+
+	RegisterWtInit("Const", WtInitFunc(func(pt *Path, wts []Float, rnd randx.Rand) {
+		for i := range wts {
+			wts[i] = 0.7
+		}
+	}))
+	defer delete(WtInitSchemes, "Const")
+	for _, w := range wts("Const", 0.5, 0.25) {
+		if math.Abs(float64(w)-0.7) > 1.0e-6 {
+			t.Fatalf("Const: weight %g, not 0.7", w)
+		}
+	}
+	pt.WtInit.Scheme = "NoSuch"
+	if err := pt.Validate(false); err == nil {
+		t.Error("Validate: expected error for unregistered scheme")
+	}
+}
@@ -21,7 +21,7 @@ package nxx1
 
 //go:generate core generate -add-types
 
-import "cogentcore.org/core/math32"
+import "github.com/emer/leabra/v2/fmath"
 
 // Params are the Noisy X/(X+1) rate-coded activation function parameters.
 // This function well-characterizes the neural response function empirically,
@@ -37,51 +37,51 @@ import "cogentcore.org/core/math32"
 type Params struct {
 
 	// threshold value Theta (Q) for firing output activation (.5 is more accurate value based on AdEx biological parameters and normalization
-	Thr float32 `default:"0.5"`
+	Thr fmath.Float `default:"0.5"`
 
 	// gain (gamma) of the rate-coded activation functions -- 100 is default, 80 works better for larger models, and 20 is closer to the actual spiking behavior of the AdEx model -- use lower values for more graded signals, generally in lower input/sensory layers of the network
-	Gain float32 `default:"80,100,40,20" min:"0"`
+	Gain fmath.Float `default:"80,100,40,20" min:"0"`
 
 	// variance of the Gaussian noise kernel for convolving with XX1 in NOISY_XX1 and NOISY_LINEAR -- determines the level of curvature of the activation function near the threshold -- increase for more graded responding there -- note that this is not actual stochastic noise, just constant convolved gaussian smoothness to the activation function
-	NVar float32 `default:"0.005,0.01" min:"0"`
+	NVar fmath.Float `default:"0.005,0.01" min:"0"`
 
 	// threshold on activation below which the direct vm - act.thr is used -- this should be low -- once it gets active should use net - g_e_thr ge-linear dynamics (gelin)
-	VmActThr float32 `default:"0.01"`
+	VmActThr fmath.Float `default:"0.01"`
 
 	// multiplier on sigmoid used for computing values for net < thr
-	SigMult float32 `default:"0.33" display:"-" json:"-" xml:"-"`
+	SigMult fmath.Float `default:"0.33" display:"-" json:"-" xml:"-"`
 
 	// power for computing sig_mult_eff as function of gain * nvar
-	SigMultPow float32 `default:"0.8" display:"-" json:"-" xml:"-"`
+	SigMultPow fmath.Float `default:"0.8" display:"-" json:"-" xml:"-"`
 
 	// gain multipler on (net - thr) for sigmoid used for computing values for net < thr
-	SigGain float32 `default:"3" display:"-" json:"-" xml:"-"`
+	SigGain fmath.Float `default:"3" display:"-" json:"-" xml:"-"`
 
 	// interpolation range above zero to use interpolation
-	InterpRange float32 `default:"0.01" display:"-" json:"-" xml:"-"`
+	InterpRange fmath.Float `default:"0.01" display:"-" json:"-" xml:"-"`
 
 	// range in units of nvar over which to apply gain correction to compensate for convolution
-	GainCorRange float32 `default:"10" display:"-" json:"-" xml:"-"`
+	GainCorRange fmath.Float `default:"10" display:"-" json:"-" xml:"-"`
 
 	// gain correction multiplier -- how much to correct gains
-	GainCor float32 `default:"0.1" display:"-" json:"-" xml:"-"`
+	GainCor fmath.Float `default:"0.1" display:"-" json:"-" xml:"-"`
 
 	// sig_gain / nvar
-	SigGainNVar float32 `display:"-" json:"-" xml:"-"`
+	SigGainNVar fmath.Float `display:"-" json:"-" xml:"-"`
 
 	// overall multiplier on sigmoidal component for values below threshold = sig_mult * pow(gain * nvar, sig_mult_pow)
-	SigMultEff float32 `display:"-" json:"-" xml:"-"`
+	SigMultEff fmath.Float `display:"-" json:"-" xml:"-"`
 
 	// 0.5 * sig_mult_eff -- used for interpolation portion
-	SigValAt0 float32 `display:"-" json:"-" xml:"-"`
+	SigValAt0 fmath.Float `display:"-" json:"-" xml:"-"`
 
 	// function value at interp_range - sig_val_at_0 -- for interpolation
-	InterpVal float32 `display:"-" json:"-" xml:"-"`
+	InterpVal fmath.Float `display:"-" json:"-" xml:"-"`
 }
 
 func (xp *Params) Update() {
 	xp.SigGainNVar = xp.SigGain / xp.NVar
-	xp.SigMultEff = xp.SigMult * math32.Pow(xp.Gain*xp.NVar, xp.SigMultPow)
+	xp.SigMultEff = xp.SigMult * fmath.Pow(xp.Gain*xp.NVar, xp.SigMultPow)
 	xp.SigValAt0 = 0.5 * xp.SigMultEff
 	xp.InterpVal = xp.XX1GainCor(xp.InterpRange) - xp.SigValAt0
 }
@@ -101,11 +101,11 @@ func (xp *Params) Defaults() {
 }
 
 // XX1 computes the basic x/(x+1) function
-func (xp *Params) XX1(x float32) float32 { return x / (x + 1) }
+func (xp *Params) XX1(x fmath.Float) fmath.Float { return x / (x + 1) }
 
 // XX1GainCor computes x/(x+1) with gain correction within GainCorRange
 // to compensate for convolution effects
-func (xp *Params) XX1GainCor(x float32) float32 {
+func (xp *Params) XX1GainCor(x fmath.Float) fmath.Float {
 	gainCorFact := (xp.GainCorRange - (x / xp.NVar)) / xp.GainCorRange
 	if gainCorFact < 0 {
 		return xp.XX1(xp.Gain * x)
@@ -119,13 +119,13 @@ func (xp *Params) XX1GainCor(x float32) float32 {
 // No need for a lookup table -- very reasonable approximation for standard range of parameters
 // (nvar = .01 or less -- higher values of nvar are less accurate with large gains,
 // but ok for lower gains)
-func (xp *Params) NoisyXX1(x float32) float32 {
+func (xp *Params) NoisyXX1(x fmath.Float) fmath.Float {
 	if x < 0 { // sigmoidal for < 0
 		ex := -(x * xp.SigGainNVar)
 		if ex > 50 {
 			return 0
 		}
-		return xp.SigMultEff / (1 + math32.FastExp(ex))
+		return xp.SigMultEff / (1 + fmath.FastExp(ex))
 	} else if x < xp.InterpRange {
 		interp := 1 - ((xp.InterpRange - x) / xp.InterpRange)
 		return xp.SigValAt0 + interp*xp.InterpVal
@@ -136,7 +136,7 @@ func (xp *Params) NoisyXX1(x float32) float32 {
 
 // X11GainCorGain computes x/(x+1) with gain correction within GainCorRange
 // to compensate for convolution effects -- using external gain factor
-func (xp *Params) XX1GainCorGain(x, gain float32) float32 {
+func (xp *Params) XX1GainCorGain(x, gain fmath.Float) fmath.Float {
 	gainCorFact := (xp.GainCorRange - (x / xp.NVar)) / xp.GainCorRange
 	if gainCorFact < 0 {
 		return xp.XX1(gain * x)
@@ -150,9 +150,9 @@ func (xp *Params) XX1GainCorGain(x, gain float32) float32 {
 // No need for a lookup table -- very reasonable approximation for standard range of parameters
 // (nvar = .01 or less -- higher values of nvar are less accurate with large gains,
 // but ok for lower gains).  Using external gain factor.
-func (xp *Params) NoisyXX1Gain(x, gain float32) float32 {
+func (xp *Params) NoisyXX1Gain(x, gain fmath.Float) fmath.Float {
 	if x < xp.InterpRange {
-		sigMultEffArg := xp.SigMult * math32.Pow(gain*xp.NVar, xp.SigMultPow)
+		sigMultEffArg := xp.SigMult * fmath.Pow(gain*xp.NVar, xp.SigMultPow)
 		sigValAt0Arg := 0.5 * sigMultEffArg
 
 		if x < 0 { // sigmoidal for < 0
@@ -160,7 +160,7 @@ func (xp *Params) NoisyXX1Gain(x, gain float32) float32 {
 			if ex > 50 {
 				return 0
 			}
-			return sigMultEffArg / (1 + math32.FastExp(ex))
+			return sigMultEffArg / (1 + fmath.FastExp(ex))
 		} else { // else x < interp_range
 			interp := 1 - ((xp.InterpRange - x) / xp.InterpRange)
 			return sigValAt0Arg + interp*xp.InterpVal
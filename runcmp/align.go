@@ -0,0 +1,340 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"cogentcore.org/core/tensor"
+	"cogentcore.org/core/tensor/table"
+)
+
+// Event finds the alignment event in the epoch log rows of one run,
+// in order, returning the index into rows of the epoch at which
+// the event occurred, and false if it did not occur in the run.
+type Event func(dt *table.Table, rows []int) (int, bool)
+
+// SwitchEvent returns an Event at the first epoch in which the value of
+// the given column differs from the previous epoch, e.g., a column with
+// the name of the training environment, for the switch from AB to AC
+// training, or an epoch stat that is set at the switch (e.g., FirstPerfect
+// in the hip example).
+func SwitchEvent(col string) Event {
+	return func(dt *table.Table, rows []int) (int, bool) {
+		cl, err := dt.ColumnByName(col)
+		if err != nil {
+			return 0, false
+		}
+		for i := 1; i < len(rows); i++ {
+			if cl.String1D(rows[i]) != cl.String1D(rows[i-1]) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+}
+
+// CriterionEvent returns an Event at the first epoch in which the value of
+// the given column is >= thr, or <= thr if below is true, e.g., the first
+// epoch with perfect memory, or with zero errors.
+func CriterionEvent(col string, thr float64, below bool) Event {
+	return func(dt *table.Table, rows []int) (int, bool) {
+		cl, err := dt.ColumnByName(col)
+		if err != nil {
+			return 0, false
+		}
+		for i, ri := range rows {
+			v := cl.Float1D(ri)
+			if math.IsNaN(v) {
+				continue
+			}
+			if (below && v <= thr) || (!below && v >= thr) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+}
+
+// ParseEvent parses an Event from the text form used by the runcmp
+// command: switch:<Col> for SwitchEvent, or crit:<Col>>=<thr> or
+// crit:<Col><=<thr> for CriterionEvent.
+func ParseEvent(s string) (Event, error) {
+	kind, arg, ok := strings.Cut(s, ":")
+	if !ok || arg == "" {
+		return nil, fmt.Errorf("runcmp.ParseEvent: %q: must be switch:<Col> or crit:<Col>>=<thr> or crit:<Col><=<thr>", s)
+	}
+	switch kind {
+	case "switch":
+		return SwitchEvent(arg), nil
+	case "crit":
+		below := false
+		col, val, ok := strings.Cut(arg, ">=")
+		if !ok {
+			col, val, ok = strings.Cut(arg, "<=")
+			below = true
+		}
+		if !ok {
+			return nil, fmt.Errorf("runcmp.ParseEvent: %q: criterion must be <Col>>=<thr> or <Col><=<thr>", s)
+		}
+		thr, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("runcmp.ParseEvent: %q: %w", s, err)
+		}
+		return CriterionEvent(col, thr, below), nil
+	}
+	return nil, fmt.Errorf("runcmp.ParseEvent: %q: unknown event type %q, must be switch or crit", s, kind)
+}
+
+// AlignedCurve is the learning curve for a statistic in one condition,
+// with the epochs of each run aligned to an Event before averaging
+// across runs, so that the curve is not smeared by the variability of the
+// event epoch across runs (e.g., the switch from AB to AC training).
+type AlignedCurve struct {
+
+	// Stat is the name of the statistic.
+	Stat string
+
+	// Condition is the value of the condition column for the runs
+	// in this curve, or empty if not grouped by condition.
+	Condition string
+
+	// Epochs are the epochs relative to the event epoch (0),
+	// present in any of the aligned runs, in order.
+	Epochs []int
+
+	// Mean is the mean value across the aligned runs at each epoch.
+	Mean []float64
+
+	// SE is the standard error of the mean at each epoch.
+	SE []float64
+
+	// CILo and CIHi are the lower and upper bounds of the confidence
+	// interval of the mean at each epoch, from the Student's t distribution.
+	CILo, CIHi []float64
+
+	// N is the number of runs with a value at each epoch.
+	N []int
+
+	// EventEpochs are the (absolute) epochs of the event in each aligned run.
+	EventEpochs []int
+
+	// NoEvent is the number of runs in which the event did not occur,
+	// which are excluded from the curve.
+	NoEvent int
+}
+
+// AlignCurves returns the event-aligned learning curves for the given stat
+// in the given epoch log, one for each value of the condition column
+// (e.g., Expt or RunName), in order of first occurrence, or one for all
+// the runs if condition is empty.  The runs are identified by the Run
+// column, within each condition, and the epochs of each run are numbered
+// relative to the epoch of the event in that run.  ci is the confidence
+// level for CILo and CIHi, e.g., 0.95.
+func AlignCurves(dt *table.Table, stat, condition string, ev Event, ci float64) ([]*AlignedCurve, error) {
+	if dt == nil {
+		return nil, fmt.Errorf("runcmp.AlignCurves: no epoch log")
+	}
+	epcCol, err := dt.ColumnByName("Epoch")
+	if err != nil {
+		return nil, fmt.Errorf("runcmp.AlignCurves: %w", err)
+	}
+	statCol, err := dt.ColumnByName(stat)
+	if err != nil {
+		return nil, fmt.Errorf("runcmp.AlignCurves: %w", err)
+	}
+	runCol, _ := dt.ColumnByName("Run")
+	var condCol tensor.Tensor
+	if condition != "" {
+		condCol, err = dt.ColumnByName(condition)
+		if err != nil {
+			return nil, fmt.Errorf("runcmp.AlignCurves: %w", err)
+		}
+	}
+
+	type runKey struct{ cond, run string }
+	var conds []string
+	var keys []runKey
+	runRows := map[runKey][]int{}
+	for ri := range dt.Rows {
+		var k runKey
+		if condCol != nil {
+			k.cond = condCol.String1D(ri)
+		}
+		if runCol != nil {
+			k.run = runCol.String1D(ri)
+		}
+		if _, ok := runRows[k]; !ok {
+			keys = append(keys, k)
+			if !slices.Contains(conds, k.cond) {
+				conds = append(conds, k.cond)
+			}
+		}
+		runRows[k] = append(runRows[k], ri)
+	}
+
+	var acs []*AlignedCurve
+	for _, cond := range conds {
+		ac := &AlignedCurve{Stat: stat, Condition: cond}
+		vals := map[int][]float64{}
+		for _, k := range keys {
+			if k.cond != cond {
+				continue
+			}
+			rows := runRows[k]
+			ei, ok := ev(dt, rows)
+			if !ok {
+				ac.NoEvent++
+				continue
+			}
+			e0 := int(epcCol.Float1D(rows[ei]))
+			ac.EventEpochs = append(ac.EventEpochs, e0)
+			for _, ri := range rows {
+				v := statCol.Float1D(ri)
+				if math.IsNaN(v) {
+					continue
+				}
+				rel := int(epcCol.Float1D(ri)) - e0
+				vals[rel] = append(vals[rel], v)
+			}
+		}
+		for ep := range vals {
+			ac.Epochs = append(ac.Epochs, ep)
+		}
+		slices.Sort(ac.Epochs)
+		for _, ep := range ac.Epochs {
+			vs := vals[ep]
+			n := len(vs)
+			m, vr := meanVar(vs)
+			se := math.Sqrt(vr / float64(n))
+			hw := math.NaN()
+			if n > 1 {
+				hw = StudentTCrit(1-ci, float64(n-1)) * se
+			}
+			ac.Mean = append(ac.Mean, m)
+			ac.SE = append(ac.SE, se)
+			ac.CILo = append(ac.CILo, m-hw)
+			ac.CIHi = append(ac.CIHi, m+hw)
+			ac.N = append(ac.N, n)
+		}
+		acs = append(acs, ac)
+	}
+	return acs, nil
+}
+
+// StudentTCrit returns the critical value of the Student's t statistic with
+// df degrees of freedom for the given two-tailed p value, i.e., the t at
+// which StudentTP(t, df) = p, e.g., 2.262 for p = 0.05 with df = 9.
+func StudentTCrit(p, df float64) float64 {
+	if p <= 0 || p >= 1 || df <= 0 {
+		return math.NaN()
+	}
+	lo, hi := 0.0, 1.0
+	for StudentTP(hi, df) > p {
+		lo = hi
+		hi *= 2
+	}
+	for range 100 {
+		mid := 0.5 * (lo + hi)
+		if StudentTP(mid, df) > p {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return 0.5 * (lo + hi)
+}
+
+// AlignedTable returns a table with the given aligned curves, with a
+// Condition column if any of them has one, an Epoch column relative to
+// the event, and Mean, SE, CILo, CIHi and N columns for each stat,
+// named Stat:Mean etc, with one row per condition and epoch.
+func AlignedTable(acs []*AlignedCurve) *table.Table {
+	dt := table.NewTable("Aligned")
+	var conds, stats []string
+	hasCond := false
+	for _, ac := range acs {
+		if !slices.Contains(conds, ac.Condition) {
+			conds = append(conds, ac.Condition)
+		}
+		if !slices.Contains(stats, ac.Stat) {
+			stats = append(stats, ac.Stat)
+		}
+		hasCond = hasCond || ac.Condition != ""
+	}
+	off := 0
+	if hasCond {
+		dt.AddStringColumn("Condition")
+		off = 1
+	}
+	dt.AddIntColumn("Epoch")
+	for _, st := range stats {
+		dt.AddFloat64Column(st + ":Mean")
+		dt.AddFloat64Column(st + ":SE")
+		dt.AddFloat64Column(st + ":CILo")
+		dt.AddFloat64Column(st + ":CIHi")
+		dt.AddIntColumn(st + ":N")
+	}
+	for _, cond := range conds {
+		var epochs []int
+		for _, ac := range acs {
+			if ac.Condition != cond {
+				continue
+			}
+			for _, ep := range ac.Epochs {
+				if !slices.Contains(epochs, ep) {
+					epochs = append(epochs, ep)
+				}
+			}
+		}
+		slices.Sort(epochs)
+		for _, ep := range epochs {
+			ri := dt.Rows
+			dt.SetNumRows(ri + 1)
+			if hasCond {
+				dt.Columns[0].SetString1D(ri, cond)
+			}
+			dt.Columns[off].SetFloat1D(ri, float64(ep))
+			for si, st := range stats {
+				vals := []float64{math.NaN(), math.NaN(), math.NaN(), math.NaN(), 0}
+				for _, ac := range acs {
+					if ac.Condition != cond || ac.Stat != st {
+						continue
+					}
+					if ei := slices.Index(ac.Epochs, ep); ei >= 0 {
+						vals = []float64{ac.Mean[ei], ac.SE[ei], ac.CILo[ei], ac.CIHi[ei], float64(ac.N[ei])}
+					}
+				}
+				for i, v := range vals {
+					dt.Columns[off+1+5*si+i].SetFloat1D(ri, v)
+				}
+			}
+		}
+	}
+	return dt
+}
+
+// WriteText writes a summary of the aligned curve: the number of runs
+// and the range of the event epochs, and the mean and confidence
+// interval at each epoch relative to the event.
+func (ac *AlignedCurve) WriteText(w io.Writer) {
+	cond := ""
+	if ac.Condition != "" {
+		cond = " " + ac.Condition
+	}
+	fmt.Fprintf(w, "%s%s: %d runs aligned", ac.Stat, cond, len(ac.EventEpochs))
+	if len(ac.EventEpochs) > 0 {
+		fmt.Fprintf(w, " at epochs %d-%d", slices.Min(ac.EventEpochs), slices.Max(ac.EventEpochs))
+	}
+	fmt.Fprintf(w, ", %d without the event\n", ac.NoEvent)
+	for ei, ep := range ac.Epochs {
+		fmt.Fprintf(w, "%6d %12.4g [%.4g, %.4g] n=%d\n", ep, ac.Mean[ei], ac.CILo[ei], ac.CIHi[ei], ac.N[ei])
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// modelcard generates a Markdown model card for a simulation run from
+// its artifacts: the architecture, training data, non-default params,
+// final result stats, and the provenance of each file.
+// See runcmp.ModelCard for details.
+//
+// Usage:
+//
+//	modelcard [flags] <run dir>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/emer/leabra/v2/runcmp"
+)
+
+func main() {
+	var title, out string
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] <run dir>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.StringVar(&title, "title", "", "title of the model card -- the run directory name if empty")
+	flag.StringVar(&out, "out", "", "file to save the model card to, e.g., MODEL_CARD.md -- standard output if empty")
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	dir := flag.Arg(0)
+	if title == "" {
+		abs, _ := filepath.Abs(dir)
+		title = filepath.Base(abs)
+	}
+	rn, err := runcmp.OpenRun(dir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	mc, err := runcmp.NewModelCard(rn, title)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if out == "" {
+		mc.WriteMarkdown(os.Stdout)
+		return
+	}
+	if err := mc.Save(out); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
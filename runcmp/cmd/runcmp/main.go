@@ -0,0 +1,109 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// runcmp compares the artifacts of two simulation runs: the params
+// differences, the final stat differences with significance tests,
+// and the overlaid learning curves, optionally aligned to an event in
+// each run (-align).  See package runcmp for details.
+//
+// Usage:
+//
+//	runcmp [flags] <run A dir> <run B dir>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/runcmp"
+)
+
+func main() {
+	var alpha, ci float64
+	var out, align, alignStats, cond string
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] <run A dir> <run B dir>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Float64Var(&alpha, "alpha", 0.05, "significance level for marking stat differences")
+	flag.StringVar(&out, "out", "", "directory to save the learning curves table (curves.tsv) and plots (<stat>.svg) -- none if empty")
+	flag.StringVar(&align, "align", "", "event to align the learning curves of each run to: switch:<Col> for the first change in the column, or crit:<Col>>=<thr> or crit:<Col><=<thr> for the first epoch at criterion")
+	flag.StringVar(&alignStats, "align-stats", "", "comma-separated epoch stats to align with -align")
+	flag.StringVar(&cond, "cond", "", "column grouping the runs into conditions for -align, e.g., Expt -- none if empty")
+	flag.Float64Var(&ci, "ci", 0.95, "confidence level for the aligned curves")
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	a, err := runcmp.OpenRun(flag.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	b, err := runcmp.OpenRun(flag.Arg(1))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	rp := runcmp.Compare(a, b)
+	rp.WriteText(os.Stdout, alpha)
+	if out != "" {
+		if err := rp.SaveCurves(out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Printf("\nlearning curves saved in: %s\n", out)
+	}
+	if align != "" {
+		if err := alignCurves(a, b, align, alignStats, cond, ci, out); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// alignCurves writes the event-aligned learning curves of each run,
+// and saves them as aligned_A.tsv and aligned_B.tsv in out, if set.
+func alignCurves(a, b *runcmp.Run, align, alignStats, cond string, ci float64, out string) error {
+	ev, err := runcmp.ParseEvent(align)
+	if err != nil {
+		return err
+	}
+	if alignStats == "" {
+		return fmt.Errorf("runcmp: -align requires -align-stats")
+	}
+	fmt.Printf("\n//////// Aligned Curves (%s, %g CI)\n", align, ci)
+	for i, rn := range []*runcmp.Run{a, b} {
+		nm := string(rune('A' + i))
+		fmt.Printf("\n%s: %s\n", nm, rn.Dir)
+		var acs []*runcmp.AlignedCurve
+		for _, st := range strings.Split(alignStats, ",") {
+			sacs, err := runcmp.AlignCurves(rn.EpochLog, st, cond, ev, ci)
+			if err != nil {
+				return err
+			}
+			for _, ac := range sacs {
+				ac.WriteText(os.Stdout)
+			}
+			acs = append(acs, sacs...)
+		}
+		if out == "" {
+			continue
+		}
+		fnm := filepath.Join(out, "aligned_"+nm+".tsv")
+		if err := runcmp.AlignedTable(acs).SaveCSV(core.Filename(fnm), table.Tab, table.Headers); err != nil {
+			return err
+		}
+	}
+	if out != "" {
+		fmt.Printf("\naligned curves saved in: %s\n", out)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"strings"
+)
+
+// MaxDiffCells is the maximum size of the table used for computing the
+// minimal line differences (number of differing lines in A times B),
+// above which the lines are just compared as sets.
+var MaxDiffCells = 4_000_000
+
+// DiffLine is one line that differs between two files.
+type DiffLine struct {
+
+	// Op is '-' for a line only in A, and '+' for a line only in B.
+	Op byte
+
+	// Line is the text of the line.
+	Line string
+
+	// Context is the enclosing section of the line, e.g., the
+	// Layer or [table] header, for locating the line in the file.
+	Context string
+}
+
+func (dl DiffLine) String() string {
+	return string(dl.Op) + " " + dl.Line
+}
+
+// ParamDiff has the differences for one params file.
+type ParamDiff struct {
+
+	// File is the name of the params file.
+	File string
+
+	// OnlyIn is "A" or "B" if the file is only present in one run.
+	OnlyIn string
+
+	// Lines are the differing lines.
+	Lines []DiffLine
+}
+
+// DiffLines returns the lines that differ between a and b, computed as
+// the minimal set of lines to remove from a and add to get b (the
+// longest common subsequence), in order.
+func DiffLines(a, b []string) []DiffLine {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	am := a[pre : len(a)-suf]
+	bm := b[pre : len(b)-suf]
+	actx := contexts(a)[pre:]
+	bctx := contexts(b)[pre:]
+	var dls []DiffLine
+	if len(am)*len(bm) > MaxDiffCells {
+		return diffSets(am, bm, actx, bctx)
+	}
+	// lcs[i][j] = length of longest common subsequence of am[i:], bm[j:]
+	n, m := len(am), len(bm)
+	lcs := make([]int32, (n+1)*(m+1))
+	at := func(i, j int) int32 { return lcs[i*(m+1)+j] }
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if am[i] == bm[j] {
+				lcs[i*(m+1)+j] = at(i+1, j+1) + 1
+			} else {
+				lcs[i*(m+1)+j] = max(at(i+1, j), at(i, j+1))
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && am[i] == bm[j]:
+			i++
+			j++
+		case i < n && (j == m || at(i+1, j) >= at(i, j+1)):
+			dls = append(dls, DiffLine{Op: '-', Line: am[i], Context: actx[i]})
+			i++
+		default:
+			dls = append(dls, DiffLine{Op: '+', Line: bm[j], Context: bctx[j]})
+			j++
+		}
+	}
+	return dls
+}
+
+// diffSets returns the lines in a that are not in b and vice-versa,
+// counting duplicate lines, for when the files are too different
+// to compute the minimal differences.
+func diffSets(a, b, actx, bctx []string) []DiffLine {
+	na := map[string]int{}
+	nb := map[string]int{}
+	for _, l := range a {
+		na[l]++
+	}
+	for _, l := range b {
+		nb[l]++
+	}
+	var dls []DiffLine
+	for i, l := range a {
+		if nb[l] > 0 {
+			nb[l]--
+			continue
+		}
+		dls = append(dls, DiffLine{Op: '-', Line: l, Context: actx[i]})
+	}
+	for i, l := range b {
+		if na[l] > 0 {
+			na[l]--
+			continue
+		}
+		dls = append(dls, DiffLine{Op: '+', Line: l, Context: bctx[i]})
+	}
+	return dls
+}
+
+// contexts returns the enclosing section for each line, which is the
+// most recent Layer: or Path: line, or [table] header in TOML files.
+func contexts(lines []string) []string {
+	ctx := make([]string, len(lines))
+	cur := ""
+	for i, l := range lines {
+		tl := strings.TrimSpace(l)
+		if strings.HasPrefix(tl, "Layer:") || strings.HasPrefix(tl, "Path:") || (strings.HasPrefix(tl, "[") && strings.HasSuffix(tl, "]")) {
+			cur = tl
+		}
+		ctx[i] = cur
+	}
+	return ctx
+}
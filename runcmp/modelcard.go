@@ -0,0 +1,248 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ModelCard is a structured record of a simulation run, generated from
+// the run artifacts by NewModelCard, which can be written as a Markdown
+// document with WriteMarkdown, for an automatic, consistent record of
+// each published simulation.  The architecture and training data are
+// from the params_network.txt and params_envs.txt files saved by
+// leabra Network.SaveParamsSnapshot and SaveEnvsReport.
+type ModelCard struct {
+
+	// Title is the title of the card, e.g., the name of the model.
+	Title string
+
+	// Date is the date the card was generated.
+	Date time.Time
+
+	// Dir is the run directory.
+	Dir string
+
+	// Arch is the network architecture (params_network.txt).
+	Arch []string
+
+	// Envs is the description of the environments (params_envs.txt).
+	Envs []string
+
+	// NonDefParams are the params that differ from their defaults
+	// (params_nondef.txt).
+	NonDefParams []string
+
+	// Config is the sim config (config.toml).
+	Config []string
+
+	// Results are the summary statistics of the final values of each
+	// statistic across runs.
+	Results []StatSummary
+
+	// Epochs are the number of training epochs of each run,
+	// from the epoch log.
+	Epochs []int
+
+	// Artifacts are the files in the run directory,
+	// including the params snapshot subdirectories.
+	Artifacts []Artifact
+
+	// Notes are any issues encountered in loading the run.
+	Notes []string
+}
+
+// StatSummary summarizes the final values of a statistic across runs.
+type StatSummary struct {
+
+	// Name of the statistic.
+	Name string
+
+	// N is the number of runs, excluding NaN values.
+	N int
+
+	// mean and standard deviation across runs.
+	Mean, SD float64
+
+	// minimum and maximum across runs.
+	Min, Max float64
+}
+
+// Artifact records the provenance of one run artifact file.
+type Artifact struct {
+
+	// File is the path relative to the run directory.
+	File string
+
+	// Size is the size in bytes.
+	Size int64
+
+	// ModTime is the modification time.
+	ModTime time.Time
+
+	// SHA256 is the hex SHA-256 checksum of the contents.
+	SHA256 string
+}
+
+// NewModelCard returns a new model card with the given title for the
+// given run, returning an error if the run artifacts cannot be read.
+func NewModelCard(rn *Run, title string) (*ModelCard, error) {
+	mc := &ModelCard{Title: title, Date: time.Now(), Dir: rn.Dir, Notes: rn.Notes}
+	mc.Arch = rn.Params["params_network.txt"]
+	mc.Envs = rn.Params["params_envs.txt"]
+	mc.NonDefParams = rn.Params["params_nondef.txt"]
+	mc.Config = rn.Params["config.toml"]
+	vals, nms := rn.FinalValues()
+	for _, nm := range nms {
+		mc.Results = append(mc.Results, NewStatSummary(nm, vals[nm]))
+	}
+	mc.Epochs = rn.runEpochs()
+	arts, err := Artifacts(rn.Dir)
+	if err != nil {
+		return nil, err
+	}
+	mc.Artifacts = arts
+	return mc, nil
+}
+
+// NewStatSummary returns the summary of the given values,
+// ignoring NaN values.
+func NewStatSummary(name string, x []float64) StatSummary {
+	x = slices.DeleteFunc(slices.Clone(x), math.IsNaN)
+	ss := StatSummary{Name: name, N: len(x), Min: math.NaN(), Max: math.NaN()}
+	mean, vr := meanVar(x)
+	ss.Mean, ss.SD = mean, math.Sqrt(vr)
+	if len(x) > 0 {
+		ss.Min, ss.Max = slices.Min(x), slices.Max(x)
+	}
+	return ss
+}
+
+// runEpochs returns the number of epochs of each run in the epoch log.
+func (rn *Run) runEpochs() []int {
+	dt := rn.EpochLog
+	if dt == nil || dt.Rows == 0 {
+		return nil
+	}
+	runCol, err := dt.ColumnByName("Run")
+	if err != nil {
+		return []int{dt.Rows}
+	}
+	var runs []float64
+	n := map[float64]int{}
+	for ri := range dt.Rows {
+		run := runCol.Float1D(ri)
+		if _, ok := n[run]; !ok {
+			runs = append(runs, run)
+		}
+		n[run]++
+	}
+	eps := make([]int, len(runs))
+	for i, run := range runs {
+		eps[i] = n[run]
+	}
+	return eps
+}
+
+// Artifacts returns the provenance of all the files in the given
+// run directory and its params snapshot subdirectories, sorted by name.
+func Artifacts(dir string) ([]Artifact, error) {
+	var arts []Artifact
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !strings.HasPrefix(d.Name(), "params_") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		sum := sha256.Sum256(b)
+		arts = append(arts, Artifact{File: filepath.ToSlash(rel), Size: info.Size(), ModTime: info.ModTime(), SHA256: hex.EncodeToString(sum[:])})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("runcmp.Artifacts: %w", err)
+	}
+	return arts, nil
+}
+
+// WriteMarkdown writes the model card as a Markdown document, with
+// sections for the architecture, training data, parameters, results
+// and provenance.
+func (mc *ModelCard) WriteMarkdown(w io.Writer) {
+	fmt.Fprintf(w, "# %s\n\n", mc.Title)
+	fmt.Fprintf(w, "Generated %s from the run artifacts in `%s`.\n", mc.Date.Format("2006-01-02 15:04"), mc.Dir)
+
+	block := func(title, lang, file string, lines []string) {
+		fmt.Fprintf(w, "\n## %s\n\n", title)
+		if len(lines) == 0 {
+			fmt.Fprintf(w, "Not recorded (%s).\n", file)
+			return
+		}
+		fmt.Fprintf(w, "From `%s`:\n\n```%s\n%s\n```\n", file, lang, strings.Join(lines, "\n"))
+	}
+	block("Architecture", "text", "params_network.txt", mc.Arch)
+	block("Training Data", "text", "params_envs.txt", mc.Envs)
+	block("Parameters", "text", "params_nondef.txt", mc.NonDefParams)
+	if len(mc.Config) > 0 {
+		fmt.Fprintf(w, "\nConfig, from `config.toml`:\n\n```toml\n%s\n```\n", strings.Join(mc.Config, "\n"))
+	}
+
+	fmt.Fprintf(w, "\n## Results\n\n")
+	if len(mc.Epochs) > 0 {
+		fmt.Fprintf(w, "Runs: %d, training epochs per run: %d-%d.\n\n", len(mc.Epochs), slices.Min(mc.Epochs), slices.Max(mc.Epochs))
+	}
+	if len(mc.Results) == 0 {
+		fmt.Fprintf(w, "No run or epoch log stats.\n")
+	} else {
+		fmt.Fprintf(w, "Final values across runs:\n\n| Stat | N | Mean | SD | Min | Max |\n|---|---|---|---|---|---|\n")
+	}
+	for _, ss := range mc.Results {
+		fmt.Fprintf(w, "| %s | %d | %.4g | %.4g | %.4g | %.4g |\n", ss.Name, ss.N, ss.Mean, ss.SD, ss.Min, ss.Max)
+	}
+
+	fmt.Fprintf(w, "\n## Provenance\n\n| File | Size | Modified | SHA-256 |\n|---|---|---|---|\n")
+	for _, ar := range mc.Artifacts {
+		fmt.Fprintf(w, "| %s | %d | %s | `%s` |\n", ar.File, ar.Size, ar.ModTime.Format("2006-01-02 15:04:05"), ar.SHA256)
+	}
+	if len(mc.Notes) > 0 {
+		fmt.Fprintf(w, "\n## Notes\n\n")
+		for _, nt := range mc.Notes {
+			fmt.Fprintf(w, "* %s\n", nt)
+		}
+	}
+}
+
+// Save saves the model card as a Markdown document to the given file.
+func (mc *ModelCard) Save(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	mc.WriteMarkdown(f)
+	return nil
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+// Curve is the learning curve for a statistic in runs A and B,
+// as the mean across runs at each epoch.
+type Curve struct {
+
+	// Stat is the name of the statistic.
+	Stat string
+
+	// Epochs are the epochs present in either A or B, in order.
+	Epochs []int
+
+	// A and B are the mean values at each epoch, NaN if not present.
+	A, B []float64
+}
+
+// Report is the comparison between two runs, A and B.
+type Report struct {
+
+	// A and B are the runs being compared.
+	A, B *Run
+
+	// Params are the differences for each params file, only for files
+	// that differ, sorted by file name.
+	Params []ParamDiff
+
+	// Stats are the differences in the final values of the statistics
+	// present in both runs.
+	Stats []StatDiff
+
+	// Curves are the learning curves for the statistics present in the
+	// epoch logs of both runs.
+	Curves []Curve
+}
+
+// Compare returns the comparison Report between runs a and b.
+func Compare(a, b *Run) *Report {
+	rp := &Report{A: a, B: b}
+	rp.compareParams()
+	rp.compareStats()
+	rp.compareCurves()
+	return rp
+}
+
+func (rp *Report) compareParams() {
+	var files []string
+	for fn := range rp.A.Params {
+		files = append(files, fn)
+	}
+	for fn := range rp.B.Params {
+		if _, ok := rp.A.Params[fn]; !ok {
+			files = append(files, fn)
+		}
+	}
+	slices.Sort(files)
+	for _, fn := range files {
+		al, aok := rp.A.Params[fn]
+		bl, bok := rp.B.Params[fn]
+		switch {
+		case !bok:
+			rp.Params = append(rp.Params, ParamDiff{File: fn, OnlyIn: "A"})
+		case !aok:
+			rp.Params = append(rp.Params, ParamDiff{File: fn, OnlyIn: "B"})
+		default:
+			if dls := DiffLines(al, bl); len(dls) > 0 {
+				rp.Params = append(rp.Params, ParamDiff{File: fn, Lines: dls})
+			}
+		}
+	}
+}
+
+func (rp *Report) compareStats() {
+	av, anms := rp.A.FinalValues()
+	bv, _ := rp.B.FinalValues()
+	for _, nm := range anms {
+		if _, ok := bv[nm]; !ok {
+			continue
+		}
+		rp.Stats = append(rp.Stats, NewStatDiff(nm, av[nm], bv[nm]))
+	}
+}
+
+func (rp *Report) compareCurves() {
+	ac := epochMeans(rp.A.EpochLog)
+	bc := epochMeans(rp.B.EpochLog)
+	for _, nm := range statColumns(rp.A.EpochLog) {
+		bm, ok := bc[nm]
+		if !ok {
+			continue
+		}
+		am := ac[nm]
+		cv := Curve{Stat: nm}
+		for ep := range am {
+			cv.Epochs = append(cv.Epochs, ep)
+		}
+		for ep := range bm {
+			if _, ok := am[ep]; !ok {
+				cv.Epochs = append(cv.Epochs, ep)
+			}
+		}
+		slices.Sort(cv.Epochs)
+		for _, ep := range cv.Epochs {
+			cv.A = append(cv.A, meanOrNaN(am[ep]))
+			cv.B = append(cv.B, meanOrNaN(bm[ep]))
+		}
+		rp.Curves = append(rp.Curves, cv)
+	}
+}
+
+// epochMeans returns the values of each statistic at each epoch,
+// across runs, from the given epoch log.
+func epochMeans(dt *table.Table) map[string]map[int][]float64 {
+	ms := map[string]map[int][]float64{}
+	if dt == nil {
+		return ms
+	}
+	epcCol, err := dt.ColumnByName("Epoch")
+	if err != nil {
+		return ms
+	}
+	for _, nm := range statColumns(dt) {
+		col, _ := dt.ColumnByName(nm)
+		em := map[int][]float64{}
+		for ri := range dt.Rows {
+			ep := int(epcCol.Float1D(ri))
+			em[ep] = append(em[ep], col.Float1D(ri))
+		}
+		ms[nm] = em
+	}
+	return ms
+}
+
+func meanOrNaN(x []float64) float64 {
+	if len(x) == 0 {
+		return math.NaN()
+	}
+	m, _ := meanVar(x)
+	return m
+}
+
+// Curve returns the learning curve for the given statistic, or nil.
+func (rp *Report) Curve(stat string) *Curve {
+	for i := range rp.Curves {
+		if rp.Curves[i].Stat == stat {
+			return &rp.Curves[i]
+		}
+	}
+	return nil
+}
+
+// WriteText writes the report in text form, marking the statistics
+// that differ significantly at the given alpha level with a *.
+func (rp *Report) WriteText(w io.Writer, alpha float64) {
+	fmt.Fprintf(w, "A: %s\nB: %s\n", rp.A.Dir, rp.B.Dir)
+	for _, rn := range []*Run{rp.A, rp.B} {
+		for _, nt := range rn.Notes {
+			fmt.Fprintf(w, "note: %s: %s\n", rn.Dir, nt)
+		}
+	}
+
+	fmt.Fprintf(w, "\n//////// Params\n\n")
+	if len(rp.Params) == 0 {
+		fmt.Fprintf(w, "no differences\n")
+	}
+	for _, pd := range rp.Params {
+		if pd.OnlyIn != "" {
+			fmt.Fprintf(w, "%s: only in %s\n", pd.File, pd.OnlyIn)
+			continue
+		}
+		fmt.Fprintf(w, "%s:\n", pd.File)
+		ctx := ""
+		for i, dl := range pd.Lines {
+			if i == 0 || dl.Context != ctx {
+				ctx = dl.Context
+				fmt.Fprintf(w, "@@ %s\n", ctx)
+			}
+			fmt.Fprintf(w, "%s\n", dl.String())
+		}
+		fmt.Fprintln(w)
+	}
+
+	fmt.Fprintf(w, "\n//////// Final Stats (* = p < %g)\n\n", alpha)
+	if len(rp.Stats) == 0 {
+		fmt.Fprintf(w, "no common stats\n")
+	} else {
+		fmt.Fprintf(w, "%-20s %4s %12s %12s %4s %12s %12s %12s %8s %10s\n", "Stat", "NA", "MeanA", "SDA", "NB", "MeanB", "SDB", "B-A", "t", "p")
+	}
+	for i := range rp.Stats {
+		sd := &rp.Stats[i]
+		sig := ""
+		if sd.Significant(alpha) {
+			sig = " *"
+		}
+		fmt.Fprintf(w, "%-20s %4d %12.4g %12.4g %4d %12.4g %12.4g %12.4g %8.3g %10.3g%s\n", sd.Name, sd.NA, sd.MeanA, sd.SDA, sd.NB, sd.MeanB, sd.SDB, sd.Diff, sd.T, sd.P, sig)
+	}
+
+	fmt.Fprintf(w, "\n//////// Learning Curves\n\n")
+	if len(rp.Curves) == 0 {
+		fmt.Fprintf(w, "no common epoch stats\n")
+	}
+	for i := range rp.Curves {
+		cv := &rp.Curves[i]
+		n := len(cv.Epochs)
+		if n == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%-20s epochs: %d-%d  final A: %.4g  final B: %.4g\n", cv.Stat, cv.Epochs[0], cv.Epochs[n-1], lastValue(cv.A), lastValue(cv.B))
+	}
+}
+
+// lastValue returns the last non-NaN value.
+func lastValue(x []float64) float64 {
+	for i := len(x) - 1; i >= 0; i-- {
+		if !math.IsNaN(x[i]) {
+			return x[i]
+		}
+	}
+	return math.NaN()
+}
+
+// CurvesTable returns a table with all the learning curves, with an
+// Epoch column and A:Stat and B:Stat columns for each statistic.
+func (rp *Report) CurvesTable() *table.Table {
+	dt := table.NewTable("Curves")
+	var epochs []int
+	for i := range rp.Curves {
+		for _, ep := range rp.Curves[i].Epochs {
+			if !slices.Contains(epochs, ep) {
+				epochs = append(epochs, ep)
+			}
+		}
+	}
+	slices.Sort(epochs)
+	dt.AddIntColumn("Epoch")
+	for i := range rp.Curves {
+		dt.AddFloat64Column("A:" + rp.Curves[i].Stat)
+		dt.AddFloat64Column("B:" + rp.Curves[i].Stat)
+	}
+	dt.SetNumRows(len(epochs))
+	for ri, ep := range epochs {
+		dt.Columns[0].SetFloat1D(ri, float64(ep))
+		for i := range rp.Curves {
+			cv := &rp.Curves[i]
+			a, b := math.NaN(), math.NaN()
+			if ei := slices.Index(cv.Epochs, ep); ei >= 0 {
+				a, b = cv.A[ei], cv.B[ei]
+			}
+			dt.Columns[1+2*i].SetFloat1D(ri, a)
+			dt.Columns[2+2*i].SetFloat1D(ri, b)
+		}
+	}
+	return dt
+}
+
+// SaveCurves saves the CurvesTable as curves.tsv in the given directory,
+// and an overlaid plot of each learning curve as <stat>.svg.
+func (rp *Report) SaveCurves(dir string) error {
+	if err := os.MkdirAll(dir, 0775); err != nil {
+		return err
+	}
+	var errs []error
+	errs = append(errs, rp.CurvesTable().SaveCSV(core.Filename(filepath.Join(dir, "curves.tsv")), table.Tab, table.Headers))
+	for i := range rp.Curves {
+		cv := &rp.Curves[i]
+		f, err := os.Create(filepath.Join(dir, cv.Stat+".svg"))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		cv.WriteSVG(f)
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// WriteSVG writes an SVG plot of the curve, with A and B overlaid.
+func (cv *Curve) WriteSVG(w io.Writer) {
+	const wd, ht, mg = 640.0, 400.0, 50.0
+	mn, mx := math.Inf(1), math.Inf(-1)
+	for _, vs := range [][]float64{cv.A, cv.B} {
+		for _, v := range vs {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				mn = min(mn, v)
+				mx = max(mx, v)
+			}
+		}
+	}
+	if mn > mx {
+		mn, mx = 0, 1
+	}
+	if mx == mn {
+		mx = mn + 1
+	}
+	n := len(cv.Epochs)
+	e0, e1 := 0.0, 1.0
+	if n > 0 {
+		e0, e1 = float64(cv.Epochs[0]), float64(cv.Epochs[n-1])
+	}
+	if e1 == e0 {
+		e1 = e0 + 1
+	}
+	px := func(ep int) float64 { return mg + (float64(ep)-e0)/(e1-e0)*(wd-2*mg) }
+	py := func(v float64) float64 { return ht - mg - (v-mn)/(mx-mn)*(ht-2*mg) }
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" font-family="sans-serif" font-size="12">`+"\n", wd, ht)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(w, `<text x="%g" y="20" text-anchor="middle" font-size="14">%s</text>`+"\n", wd/2, cv.Stat)
+	fmt.Fprintf(w, `<polyline points="%g,%g %g,%g %g,%g" fill="none" stroke="black"/>`+"\n", mg, mg, mg, ht-mg, wd-mg, ht-mg)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="end">%.4g</text>`+"\n", mg-4, mg+4, mx)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="end">%.4g</text>`+"\n", mg-4, ht-mg+4, mn)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">%g</text>`+"\n", mg, ht-mg+16, e0)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">%g</text>`+"\n", wd-mg, ht-mg+16, e1)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">Epoch</text>`+"\n", wd/2, ht-mg+16)
+	for i, vs := range [][]float64{cv.A, cv.B} {
+		color, name := "#1f77b4", "A"
+		if i == 1 {
+			color, name = "#d62728", "B"
+		}
+		var pts []string
+		for ei, v := range vs {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			pts = append(pts, fmt.Sprintf("%.2f,%.2f", px(cv.Epochs[ei]), py(v)))
+		}
+		fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2"/>`+"\n", strings.Join(pts, " "), color)
+		fmt.Fprintf(w, `<text x="%g" y="%g" fill="%s">%s</text>`+"\n", wd-mg+6, mg+float64(i)*16, color, name)
+	}
+	fmt.Fprintf(w, "</svg>\n")
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package runcmp compares the artifacts of two simulation runs, to answer
+the question: "what changed?".  A run directory contains the log files
+saved by the sim (e.g., Net_Base_epc.tsv and Net_Base_run.tsv, see
+elog.SetLogFile) and the params snapshot files saved by
+Network.SaveParamsSnapshot (config.toml, params.toml, params_all.txt,
+params_nondef.txt etc), either directly or in a params_* subdirectory.
+
+The comparison Report has:
+  - the line differences between the params files in each run.
+  - the differences in the final values of all the statistics, from the
+    run log if present, or else the final epoch of each run in the epoch
+    log, with a Welch's t-test for the significance of the difference
+    across runs.
+  - the learning curves for each statistic from the epoch logs, averaged
+    across runs, which can be saved as a table or as overlaid plots.
+
+AlignCurves aligns the learning curves of each run to an Event (e.g.,
+the switch from AB to AC training, or the first epoch at criterion)
+before averaging across runs, with confidence intervals, per condition.
+
+NewModelCard generates a ModelCard for a single run, a structured record
+of the architecture, training data, non-default params, final result
+stats, and the provenance of each artifact file, written as a Markdown
+document, using the params_network.txt file saved by the snapshot and
+the params_envs.txt file saved by leabra.SaveEnvsReport.
+
+The runcmp command in cmd/runcmp provides a command-line interface,
+and the modelcard command in cmd/modelcard generates model cards.
+*/
+package runcmp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+// Run has the artifacts of one simulation run, loaded from a directory.
+type Run struct {
+
+	// Dir is the directory the run was loaded from.
+	Dir string
+
+	// Params are the lines of each of the params files, by file name.
+	Params map[string][]string
+
+	// RunLog is the training run log (*_run.tsv), or nil if not present.
+	RunLog *table.Table
+
+	// EpochLog is the training epoch log (*_epc.tsv), or nil if not present.
+	EpochLog *table.Table
+
+	// Notes are any issues encountered in loading the run.
+	Notes []string
+}
+
+// OpenRun loads the run artifacts from the given directory.
+// Returns an error if the directory cannot be read or has
+// no params or log files.
+func OpenRun(dir string) (*Run, error) {
+	rn := &Run{Dir: dir, Params: map[string][]string{}}
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("runcmp.OpenRun: %w", err)
+	}
+	var runs, epcs []string
+	for _, ent := range ents {
+		nm := ent.Name()
+		fnm := filepath.Join(dir, nm)
+		switch {
+		case ent.IsDir():
+			if strings.HasPrefix(nm, "params_") {
+				rn.openParamsDir(fnm)
+			}
+		case strings.HasSuffix(nm, "_tst_epc.tsv"):
+		case strings.HasSuffix(nm, "_epc.tsv"):
+			epcs = append(epcs, fnm)
+		case strings.HasSuffix(nm, "_run.tsv"):
+			runs = append(runs, fnm)
+		case isParamsFile(nm):
+			rn.openParamsFile(fnm)
+		}
+	}
+	rn.RunLog = rn.openLog(runs)
+	rn.EpochLog = rn.openLog(epcs)
+	if len(rn.Params) == 0 && rn.RunLog == nil && rn.EpochLog == nil {
+		return nil, fmt.Errorf("runcmp.OpenRun: no params or log files found in: %s", dir)
+	}
+	return rn, nil
+}
+
+// isParamsFile returns true if the given file name is a params file.
+func isParamsFile(nm string) bool {
+	return nm == "config.toml" || (strings.HasPrefix(nm, "params") && (strings.HasSuffix(nm, ".toml") || strings.HasSuffix(nm, ".txt")))
+}
+
+// openParamsDir opens the params files in the given params snapshot directory.
+func (rn *Run) openParamsDir(dir string) {
+	ents, err := os.ReadDir(dir)
+	if err != nil {
+		rn.Notes = append(rn.Notes, err.Error())
+		return
+	}
+	for _, ent := range ents {
+		if !ent.IsDir() && isParamsFile(ent.Name()) {
+			rn.openParamsFile(filepath.Join(dir, ent.Name()))
+		}
+	}
+}
+
+// openParamsFile opens the given params file into Params.
+func (rn *Run) openParamsFile(fnm string) {
+	b, err := os.ReadFile(fnm)
+	if err != nil {
+		rn.Notes = append(rn.Notes, err.Error())
+		return
+	}
+	rn.Params[filepath.Base(fnm)] = strings.Split(strings.TrimRight(string(b), "\n"), "\n")
+}
+
+// openLog opens the first of the given log files, noting if there
+// are multiple files, returning nil if none.
+func (rn *Run) openLog(fnms []string) *table.Table {
+	if len(fnms) == 0 {
+		return nil
+	}
+	slices.Sort(fnms)
+	if len(fnms) > 1 {
+		rn.Notes = append(rn.Notes, fmt.Sprintf("multiple log files, using: %s", fnms[0]))
+	}
+	dt := table.NewTable()
+	if err := dt.OpenCSV(core.Filename(fnms[0]), table.Tab); err != nil {
+		rn.Notes = append(rn.Notes, err.Error())
+		return nil
+	}
+	return dt
+}
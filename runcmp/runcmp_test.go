@@ -0,0 +1,183 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestWelchTTest(t *testing.T) {
+	tv, df, p := WelchTTest([]float64{1, 2, 3, 4, 5}, []float64{2, 4, 6, 8, 11})
+	if math.Abs(tv-1.8663) > 1e-4 || math.Abs(df-5.5733) > 1e-4 {
+		t.Errorf("t = %g, df = %g", tv, df)
+	}
+	if p < 0.1 || p > 0.13 {
+		t.Errorf("p = %g", p)
+	}
+	if p := StudentTP(2, 10); math.Abs(p-0.07339) > 1e-4 {
+		t.Errorf("StudentTP(2, 10) = %g, not 0.07339", p)
+	}
+	if _, _, p := WelchTTest([]float64{1}, []float64{2, 3}); !math.IsNaN(p) {
+		t.Errorf("p should be NaN for n < 2: %g", p)
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	a := []string{"Layer: A", "Act: {", " Gain: 1", "}", "Layer: B", " Gi: 1.8", "x"}
+	b := []string{"Layer: A", "Act: {", " Gain: 2", "}", "Layer: B", " Gi: 1.8", "y", "z"}
+	want := []string{"-  Gain: 1", "+  Gain: 2", "- x", "+ y", "+ z"}
+	ctxs := []string{"Layer: A", "Layer: A", "Layer: B", "Layer: B", "Layer: B"}
+	dls := DiffLines(a, b)
+	if len(dls) != len(want) {
+		t.Fatalf("got %d diff lines, not %d: %v", len(dls), len(want), dls)
+	}
+	for i, dl := range dls {
+		if dl.String() != want[i] || dl.Context != ctxs[i] {
+			t.Errorf("line %d: got %q in %q, not %q in %q", i, dl.String(), dl.Context, want[i], ctxs[i])
+		}
+	}
+	if dls := DiffLines(a, a); len(dls) != 0 {
+		t.Errorf("identical lines should have no diffs: %v", dls)
+	}
+}
+
+func TestAlignCurves(t *testing.T) {
+	dt := table.NewTable()
+	dt.AddStringColumn("Expt")
+	dt.AddIntColumn("Run")
+	dt.AddIntColumn("Epoch")
+	dt.AddStringColumn("Env")
+	dt.AddFloat64Column("Mem")
+	// runs switch from AB to AC at epochs 2, 4, 3, and never in run 3,
+	// and Mem drops at the switch and recovers by 0.25 per epoch.
+	for run, sw := range []int{2, 4, 3, 100} {
+		for epc := range 7 {
+			env, mem := "AB", 1.0
+			if epc >= sw {
+				env, mem = "AC", 0.25*float64(epc-sw)
+			}
+			dt.AddRows(1)
+			ri := dt.Rows - 1
+			dt.Columns[0].SetString1D(ri, "Base")
+			dt.Columns[1].SetFloat1D(ri, float64(run))
+			dt.Columns[2].SetFloat1D(ri, float64(epc))
+			dt.Columns[3].SetString1D(ri, env)
+			dt.Columns[4].SetFloat1D(ri, mem+0.01*float64(run))
+		}
+	}
+	ev, err := ParseEvent("switch:Env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	acs, err := AlignCurves(dt, "Mem", "Expt", ev, 0.95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(acs) != 1 || acs[0].Condition != "Base" {
+		t.Fatalf("expected 1 curve for Base: %v", acs)
+	}
+	ac := acs[0]
+	if ac.NoEvent != 1 || !slices.Equal(ac.EventEpochs, []int{2, 4, 3}) {
+		t.Errorf("NoEvent: %d, EventEpochs: %v", ac.NoEvent, ac.EventEpochs)
+	}
+	if ac.Epochs[0] != -4 || ac.Epochs[len(ac.Epochs)-1] != 4 {
+		t.Errorf("Epochs: %v", ac.Epochs)
+	}
+	for ei, ep := range ac.Epochs {
+		if ep < 0 || ep > 2 {
+			continue
+		}
+		if want := 0.25*float64(ep) + 0.01; math.Abs(ac.Mean[ei]-want) > 1e-9 || ac.N[ei] != 3 {
+			t.Errorf("epoch %d: mean %g, not %g, n = %d", ep, ac.Mean[ei], want, ac.N[ei])
+		}
+		if !(ac.CILo[ei] < ac.Mean[ei] && ac.Mean[ei] < ac.CIHi[ei]) {
+			t.Errorf("epoch %d: CI [%g, %g] does not contain %g", ep, ac.CILo[ei], ac.CIHi[ei], ac.Mean[ei])
+		}
+	}
+	if tc := StudentTCrit(0.05, 9); math.Abs(tc-2.2622) > 1e-3 {
+		t.Errorf("StudentTCrit(0.05, 9) = %g, not 2.2622", tc)
+	}
+
+	ev, _ = ParseEvent("crit:Mem<=0.1")
+	acs, _ = AlignCurves(dt, "Mem", "", ev, 0.95)
+	if ac := acs[0]; !slices.Equal(ac.EventEpochs, []int{2, 4, 3}) || ac.NoEvent != 1 {
+		t.Errorf("criterion EventEpochs: %v, NoEvent: %d", ac.EventEpochs, ac.NoEvent)
+	}
+	at := AlignedTable(acs)
+	if at.NumColumns() != 6 || at.Rows != len(acs[0].Epochs) {
+		t.Errorf("AlignedTable: %d columns, %d rows", at.NumColumns(), at.Rows)
+	}
+	if _, err := ParseEvent("crit:Mem=1"); err == nil {
+		t.Error("ParseEvent: expected error")
+	}
+}
+
+func TestModelCard(t *testing.T) {
+	dir := t.TempDir()
+	pdir := filepath.Join(dir, "params_good")
+	if err := os.Mkdir(pdir, 0775); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"params_network.txt": "Input\tInputLayer\tShape: [5 5]\tNeurons: 25\n",
+		"params_envs.txt":    "Train\t*leabra.ShardEnv\tPatterns: 25\n",
+		"params_nondef.txt":  "Layer: Hidden\n  Gi: 2\n",
+		"config.toml":        "NRuns = 2\n",
+	}
+	for fn, s := range files {
+		if err := os.WriteFile(filepath.Join(pdir, fn), []byte(s), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	dt := table.NewTable()
+	dt.AddIntColumn("Run")
+	dt.AddIntColumn("Epoch")
+	dt.AddFloat64Column("PctErr")
+	for run, neps := range []int{3, 5} {
+		for epc := range neps {
+			dt.AddRows(1)
+			ri := dt.Rows - 1
+			dt.Columns[0].SetFloat1D(ri, float64(run))
+			dt.Columns[1].SetFloat1D(ri, float64(epc))
+			dt.Columns[2].SetFloat1D(ri, 1/float64(1+epc+run))
+		}
+	}
+	if err := dt.SaveCSV(core.Filename(filepath.Join(dir, "Net_Base_epc.tsv")), table.Tab, true); err != nil {
+		t.Fatal(err)
+	}
+	rn, err := OpenRun(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mc, err := NewModelCard(rn, "Test Model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(mc.Epochs, []int{3, 5}) {
+		t.Errorf("Epochs: %v", mc.Epochs)
+	}
+	if len(mc.Results) != 1 || mc.Results[0].N != 2 || math.Abs(mc.Results[0].Mean-(1.0/3+1.0/6)/2) > 1e-6 {
+		t.Errorf("Results: %v", mc.Results)
+	}
+	if len(mc.Artifacts) != 5 || mc.Artifacts[0].File != "Net_Base_epc.tsv" || len(mc.Artifacts[0].SHA256) != 64 {
+		t.Errorf("Artifacts: %v", mc.Artifacts)
+	}
+	var b strings.Builder
+	mc.WriteMarkdown(&b)
+	md := b.String()
+	for _, want := range []string{"# Test Model", "## Architecture", "InputLayer", "*leabra.ShardEnv", "Gi: 2", "NRuns = 2", "| PctErr | 2 |", "training epochs per run: 3-5", "params_good/config.toml"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("model card missing %q:\n%s", want, md)
+		}
+	}
+}
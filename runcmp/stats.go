@@ -0,0 +1,249 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package runcmp
+
+import (
+	"math"
+	"slices"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// CounterColumns are the names of the log columns that are counters,
+// not statistics, and are excluded from the comparisons.
+var CounterColumns = []string{"Run", "Epoch", "Trial", "Cycle", "Expt"}
+
+// StatDiff is the difference in the final values of a statistic,
+// across the runs in A vs. B.
+type StatDiff struct {
+
+	// Name of the statistic.
+	Name string
+
+	// number of runs in A and B.
+	NA, NB int
+
+	// mean across runs in A and B.
+	MeanA, MeanB float64
+
+	// standard deviation across runs in A and B.
+	SDA, SDB float64
+
+	// Diff is MeanB - MeanA.
+	Diff float64
+
+	// T is the Welch's t statistic for the difference.
+	T float64
+
+	// DF is the Welch-Satterthwaite degrees of freedom.
+	DF float64
+
+	// P is the two-tailed probability of a difference at least this large
+	// if the means were the same (NaN if there are fewer than 2 runs).
+	P float64
+}
+
+// Significant returns true if the difference is significant at given alpha level.
+func (sd *StatDiff) Significant(alpha float64) bool {
+	return sd.P < alpha
+}
+
+// NewStatDiff returns the StatDiff for given values in A and B.
+func NewStatDiff(name string, a, b []float64) StatDiff {
+	sd := StatDiff{Name: name, NA: len(a), NB: len(b)}
+	var va, vb float64
+	sd.MeanA, va = meanVar(a)
+	sd.MeanB, vb = meanVar(b)
+	sd.SDA = math.Sqrt(va)
+	sd.SDB = math.Sqrt(vb)
+	sd.Diff = sd.MeanB - sd.MeanA
+	sd.T, sd.DF, sd.P = WelchTTest(a, b)
+	return sd
+}
+
+// WelchTTest returns the Welch's unequal variances t-test statistic,
+// degrees of freedom, and two-tailed p value for the difference in means
+// between a and b.  Returns NaN values if either has fewer than 2 values.
+func WelchTTest(a, b []float64) (t, df, p float64) {
+	na, nb := float64(len(a)), float64(len(b))
+	if na < 2 || nb < 2 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	ma, va := meanVar(a)
+	mb, vb := meanVar(b)
+	sa, sb := va/na, vb/nb
+	se := sa + sb
+	if se == 0 {
+		if ma == mb {
+			return 0, na + nb - 2, 1
+		}
+		return math.Copysign(math.Inf(1), mb-ma), na + nb - 2, 0
+	}
+	t = (mb - ma) / math.Sqrt(se)
+	df = se * se / (sa*sa/(na-1) + sb*sb/(nb-1))
+	p = StudentTP(t, df)
+	return
+}
+
+// StudentTP returns the two-tailed p value for the given t statistic
+// with df degrees of freedom, from the Student's t distribution.
+func StudentTP(t, df float64) float64 {
+	return IncBeta(df/2, 0.5, df/(df+t*t))
+}
+
+// meanVar returns the mean and unbiased (n-1) variance of the values.
+func meanVar(x []float64) (mean, vr float64) {
+	n := float64(len(x))
+	if n == 0 {
+		return math.NaN(), math.NaN()
+	}
+	for _, v := range x {
+		mean += v
+	}
+	mean /= n
+	if n < 2 {
+		return mean, 0
+	}
+	for _, v := range x {
+		d := v - mean
+		vr += d * d
+	}
+	vr /= n - 1
+	return
+}
+
+// IncBeta returns the regularized incomplete beta function I_x(a, b).
+func IncBeta(a, b, x float64) float64 {
+	switch {
+	case x <= 0:
+		return 0
+	case x >= 1:
+		return 1
+	}
+	lbt, _ := math.Lgamma(a + b)
+	lga, _ := math.Lgamma(a)
+	lgb, _ := math.Lgamma(b)
+	bt := math.Exp(lbt - lga - lgb + a*math.Log(x) + b*math.Log(1-x))
+	if x < (a+1)/(a+b+2) {
+		return bt * betaCF(a, b, x) / a
+	}
+	return 1 - bt*betaCF(b, a, 1-x)/b
+}
+
+// betaCF evaluates the continued fraction for the incomplete beta
+// function, using the modified Lentz's method.
+func betaCF(a, b, x float64) float64 {
+	const maxIter = 200
+	const eps = 3e-14
+	const tiny = 1e-300
+	qab, qap, qam := a+b, a+1, a-1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIter; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+		if math.Abs(del-1) < eps {
+			break
+		}
+	}
+	return h
+}
+
+// statColumns returns the names of the numeric, scalar statistic
+// columns in the given log table, excluding the CounterColumns.
+func statColumns(dt *table.Table) []string {
+	var nms []string
+	if dt == nil {
+		return nms
+	}
+	for ci, col := range dt.Columns {
+		nm := dt.ColumnNames[ci]
+		if col.IsString() || col.Len() != dt.Rows || slices.Contains(CounterColumns, nm) {
+			continue
+		}
+		nms = append(nms, nm)
+	}
+	return nms
+}
+
+// finalRows returns the row indexes with the final values of the
+// statistics for each run: all rows of the run log if present,
+// otherwise the last row for each Run in the epoch log.
+func (rn *Run) finalRows() (*table.Table, []int) {
+	if rn.RunLog != nil && rn.RunLog.Rows > 0 {
+		rows := make([]int, rn.RunLog.Rows)
+		for i := range rows {
+			rows[i] = i
+		}
+		return rn.RunLog, rows
+	}
+	dt := rn.EpochLog
+	if dt == nil || dt.Rows == 0 {
+		return nil, nil
+	}
+	runCol, err := dt.ColumnByName("Run")
+	if err != nil {
+		return dt, []int{dt.Rows - 1}
+	}
+	last := map[float64]int{}
+	var runs []float64
+	for ri := range dt.Rows {
+		run := runCol.Float1D(ri)
+		if _, ok := last[run]; !ok {
+			runs = append(runs, run)
+		}
+		last[run] = ri
+	}
+	rows := make([]int, len(runs))
+	for i, run := range runs {
+		rows[i] = last[run]
+	}
+	return dt, rows
+}
+
+// FinalValues returns the final values of each statistic
+// across runs, by statistic name, along with the names in order.
+func (rn *Run) FinalValues() (map[string][]float64, []string) {
+	dt, rows := rn.finalRows()
+	vals := map[string][]float64{}
+	nms := statColumns(dt)
+	for _, nm := range nms {
+		col, _ := dt.ColumnByName(nm)
+		vs := make([]float64, len(rows))
+		for i, ri := range rows {
+			vs[i] = col.Float1D(ri)
+		}
+		vals[nm] = vs
+	}
+	return vals, nms
+}
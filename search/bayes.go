@@ -0,0 +1,316 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"math"
+	"sort"
+)
+
+// BayesParams are the parameters for Bayesian optimization.
+type BayesParams struct {
+
+	// NInit is the number of initial configurations sampled
+	// quasi-randomly across the space (Latin hypercube), before
+	// the surrogate model is used.  0 = 2 * number of parameters + 1.
+	NInit int
+
+	// NCand is the number of candidate configurations for which the
+	// expected improvement is computed in choosing the next one.
+	NCand int `default:"2000"`
+
+	// Xi is the minimum improvement, in units of the standard
+	// deviation of the losses, for the expected improvement, where
+	// larger values favor exploration of uncertain regions over
+	// exploitation of the best region found so far.
+	Xi float64 `default:"0.01"`
+
+	// Noise is the variance of the evaluation noise, in units of the
+	// variance of the losses, which allows the surrogate to not exactly
+	// fit noisy evaluations (e.g., from different random seeds).
+	Noise float64 `default:"0.01"`
+}
+
+func (bp *BayesParams) Defaults() {
+	bp.NCand = 2000
+	bp.Xi = 0.01
+	bp.Noise = 0.01
+}
+
+// gpLengthScales are the length scales of the Gaussian process kernel,
+// in the normalized parameter space, from which the one with the
+// highest marginal likelihood is used.
+var gpLengthScales = []float64{0.05, 0.1, 0.2, 0.35, 0.6, 1}
+
+// gp is a Gaussian process regression model with a squared-exponential
+// kernel, on standardized losses.
+type gp struct {
+	x     [][]float64
+	alpha []float64
+	chol  [][]float64
+	ls    float64
+	noise float64
+	mean  float64
+	sd    float64
+}
+
+func (g *gp) kernel(a, b []float64) float64 {
+	var d2 float64
+	for i := range a {
+		d := a[i] - b[i]
+		d2 += d * d
+	}
+	return math.Exp(-d2 / (2 * g.ls * g.ls))
+}
+
+// fit fits the model to the given points and losses, choosing the
+// length scale with the highest marginal likelihood.
+func (g *gp) fit(x [][]float64, y []float64, noise float64) {
+	n := len(y)
+	g.x = x
+	g.noise = noise
+	g.mean, g.sd = 0, 0
+	for _, v := range y {
+		g.mean += v
+	}
+	g.mean /= float64(n)
+	for _, v := range y {
+		g.sd += (v - g.mean) * (v - g.mean)
+	}
+	g.sd = math.Sqrt(g.sd / float64(n))
+	if g.sd == 0 {
+		g.sd = 1
+	}
+	ys := make([]float64, n)
+	for i, v := range y {
+		ys[i] = (v - g.mean) / g.sd
+	}
+	bestLL := math.Inf(-1)
+	var best gp
+	for _, ls := range gpLengthScales {
+		g.ls = ls
+		chol, ok := cholesky(g.covariance())
+		if !ok {
+			continue
+		}
+		alpha := cholSolve(chol, ys)
+		ll := 0.0
+		for i := range ys {
+			ll -= 0.5*ys[i]*alpha[i] + math.Log(chol[i][i])
+		}
+		if ll > bestLL {
+			bestLL = ll
+			best = *g
+			best.chol = chol
+			best.alpha = alpha
+		}
+	}
+	*g = best
+}
+
+// covariance returns the kernel matrix plus noise on the diagonal.
+func (g *gp) covariance() [][]float64 {
+	n := len(g.x)
+	k := make([][]float64, n)
+	for i := range n {
+		k[i] = make([]float64, n)
+		for j := range i + 1 {
+			k[i][j] = g.kernel(g.x[i], g.x[j])
+			k[j][i] = k[i][j]
+		}
+		k[i][i] += g.noise + 1e-9
+	}
+	return k
+}
+
+// predict returns the predicted mean and standard deviation
+// of the standardized loss at x.
+func (g *gp) predict(x []float64) (mu, sd float64) {
+	n := len(g.x)
+	ks := make([]float64, n)
+	for i := range n {
+		ks[i] = g.kernel(x, g.x[i])
+		mu += ks[i] * g.alpha[i]
+	}
+	v := forwardSub(g.chol, ks)
+	vr := 1.0
+	for _, vi := range v {
+		vr -= vi * vi
+	}
+	return mu, math.Sqrt(max(vr, 1e-12))
+}
+
+// cholesky returns the lower triangular Cholesky factor of a,
+// and false if it is not positive definite.
+func cholesky(a [][]float64) ([][]float64, bool) {
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range n {
+		l[i] = make([]float64, n)
+		for j := range i + 1 {
+			s := a[i][j]
+			for k := range j {
+				s -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if s <= 0 {
+					return nil, false
+				}
+				l[i][i] = math.Sqrt(s)
+			} else {
+				l[i][j] = s / l[j][j]
+			}
+		}
+	}
+	return l, true
+}
+
+// forwardSub solves L v = b for lower triangular L.
+func forwardSub(l [][]float64, b []float64) []float64 {
+	v := make([]float64, len(b))
+	for i := range b {
+		s := b[i]
+		for k := range i {
+			s -= l[i][k] * v[k]
+		}
+		v[i] = s / l[i][i]
+	}
+	return v
+}
+
+// cholSolve solves L L^T x = b.
+func cholSolve(l [][]float64, b []float64) []float64 {
+	v := forwardSub(l, b)
+	n := len(b)
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		s := v[i]
+		for k := i + 1; k < n; k++ {
+			s -= l[k][i] * x[k]
+		}
+		x[i] = s / l[i][i]
+	}
+	return x
+}
+
+// expectedImprovement returns the expected improvement below the best
+// standardized loss, for the given predicted mean and sd, with minimum
+// improvement xi.
+func expectedImprovement(best, mu, sd, xi float64) float64 {
+	imp := best - mu - xi
+	z := imp / sd
+	cdf := 0.5 * math.Erfc(-z/math.Sqrt2)
+	pdf := math.Exp(-0.5*z*z) / math.Sqrt(2*math.Pi)
+	return imp*cdf + sd*pdf
+}
+
+// proposeBayes returns the parameter values for the next n configurations
+// to run by Bayesian optimization, given the Evals so far (see Tell).
+// The first Bayes.NInit configurations are spread across the space,
+// and subsequent ones maximize the expected improvement under a
+// Gaussian process model of the losses.  Multiple configurations for
+// running in parallel are chosen sequentially, treating each chosen
+// one as having its predicted loss.
+func (sr *Search) proposeBayes(n int) [][]float64 {
+	if sr.Bayes.NCand <= 0 {
+		sr.Bayes.Defaults()
+	}
+	dim := len(sr.Space)
+	ninit := sr.Bayes.NInit
+	if ninit <= 0 {
+		ninit = 2*dim + 1
+	}
+	var xs [][]float64
+	var ys []float64
+	maxFinite := math.Inf(-1)
+	for _, ev := range sr.Evals {
+		if !math.IsInf(ev.Loss, 1) {
+			maxFinite = max(maxFinite, ev.Loss)
+		}
+	}
+	for _, ev := range sr.Evals {
+		loss := ev.Loss
+		if math.IsInf(loss, 1) { // failed runs are treated as the worst
+			if math.IsInf(maxFinite, -1) {
+				continue
+			}
+			loss = maxFinite
+		}
+		xs = append(xs, sr.Space.Units(ev.Values))
+		ys = append(ys, loss)
+	}
+	nev := len(xs)
+	props := make([][]float64, n)
+	for pi := range n {
+		if nev+pi < ninit || len(xs) < 2 {
+			props[pi] = sr.Space.Values(sr.initPoint(len(sr.Evals)+pi, ninit))
+			continue
+		}
+		var g gp
+		g.fit(xs, ys, sr.Bayes.Noise)
+		u := sr.maxEI(&g, xs, ys)
+		mu, _ := g.predict(u)
+		xs = append(xs, u)
+		ys = append(ys, g.mean+g.sd*mu) // "kriging believer"
+		props[pi] = sr.Space.Values(u)
+	}
+	return props
+}
+
+// initPoint returns the i-th of the ninit initial points, as a
+// Latin hypercube sample, with each dimension stratified into ninit
+// intervals, using a random permutation of the intervals for each
+// dimension, regenerated for each set of ninit points.
+func (sr *Search) initPoint(i, ninit int) []float64 {
+	if i%ninit == 0 || len(sr.lhs) != len(sr.Space) {
+		sr.lhs = make([][]int, len(sr.Space))
+		for d := range sr.lhs {
+			sr.lhs[d] = sr.rand.Perm(ninit)
+		}
+	}
+	u := make([]float64, len(sr.Space))
+	for d := range u {
+		u[d] = (float64(sr.lhs[d][i%ninit]) + sr.rand.Float64()) / float64(ninit)
+	}
+	return u
+}
+
+// maxEI returns the candidate point with the maximum expected
+// improvement, from uniform random candidates and local perturbations
+// of the best points so far.
+func (sr *Search) maxEI(g *gp, xs [][]float64, ys []float64) []float64 {
+	dim := len(sr.Space)
+	best := math.Inf(1)
+	for _, y := range ys {
+		best = min(best, (y-g.mean)/g.sd)
+	}
+	order := make([]int, len(ys))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(a, b int) bool { return ys[order[a]] < ys[order[b]] })
+	nbest := min(5, len(order))
+	var bu []float64
+	bei := math.Inf(-1)
+	for ci := range sr.Bayes.NCand {
+		u := make([]float64, dim)
+		if ci%2 == 1 { // local perturbation of one of the best points
+			x := xs[order[(ci/2)%nbest]]
+			for d := range u {
+				u[d] = min(max(x[d]+0.05*sr.rand.NormFloat64(), 0), 1)
+			}
+		} else {
+			for d := range u {
+				u[d] = sr.rand.Float64()
+			}
+		}
+		mu, sd := g.predict(u)
+		if ei := expectedImprovement(best, mu, sd, sr.Bayes.Xi); ei > bei {
+			bei = ei
+			bu = u
+		}
+	}
+	return bu
+}
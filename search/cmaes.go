@@ -0,0 +1,283 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// CMAESParams are the parameters for the CMA-ES evolution strategy.
+type CMAESParams struct {
+
+	// PopSize is the number of configurations in each generation,
+	// which are the ones to run in parallel.  0 = 4 + 3 ln(number of
+	// parameters), which is the standard default.  Larger populations
+	// are more robust to noise and local minima.
+	PopSize int
+}
+
+// cmaes is the state of the CMA-ES evolution strategy (Hansen, 2016,
+// The CMA Evolution Strategy: A Tutorial), in the normalized parameter
+// space, with sampled points clipped to the 0-1 range.
+type cmaes struct {
+	n      int
+	lambda int
+	mu     int
+	w      []float64
+	mueff  float64
+	cc     float64
+	cs     float64
+	c1     float64
+	cmu    float64
+	damps  float64
+	chiN   float64
+	mean   []float64
+	sigma  float64
+	pc     []float64
+	ps     []float64
+	c      [][]float64
+	b      [][]float64
+	d      []float64
+	gen    int
+	pop    [][]float64
+	losses []float64
+}
+
+// initCMAES initializes the CMA-ES state, if not already done.
+func (sr *Search) initCMAES() {
+	if sr.cma != nil {
+		return
+	}
+	start := make([]float64, len(sr.Space))
+	for i := range start {
+		start[i] = 0.5
+	}
+	if sr.Start != nil {
+		start = sr.Space.Units(sr.Start)
+	}
+	step := sr.Step
+	if step <= 0 {
+		step = 0.25
+	}
+	sr.cma = newCMAES(start, step, sr.CMAES.PopSize)
+}
+
+// newCMAES returns a new CMA-ES state with the given initial mean,
+// step size, and population size (0 for the default).
+func newCMAES(mean []float64, sigma float64, popSize int) *cmaes {
+	n := len(mean)
+	nf := float64(n)
+	c := &cmaes{n: n, sigma: sigma}
+	c.lambda = popSize
+	if c.lambda <= 0 {
+		c.lambda = 4 + int(3*math.Log(nf))
+	}
+	c.lambda = max(c.lambda, 2)
+	c.mu = c.lambda / 2
+	c.w = make([]float64, c.mu)
+	var sw, sw2 float64
+	for i := range c.w {
+		c.w[i] = math.Log(float64(c.mu)+0.5) - math.Log(float64(i+1))
+		sw += c.w[i]
+	}
+	for i := range c.w {
+		c.w[i] /= sw
+		sw2 += c.w[i] * c.w[i]
+	}
+	c.mueff = 1 / sw2
+	c.cc = (4 + c.mueff/nf) / (nf + 4 + 2*c.mueff/nf)
+	c.cs = (c.mueff + 2) / (nf + c.mueff + 5)
+	c.c1 = 2 / ((nf+1.3)*(nf+1.3) + c.mueff)
+	c.cmu = min(1-c.c1, 2*(c.mueff-2+1/c.mueff)/((nf+2)*(nf+2)+c.mueff))
+	c.damps = 1 + 2*max(0, math.Sqrt((c.mueff-1)/(nf+1))-1) + c.cs
+	c.chiN = math.Sqrt(nf) * (1 - 1/(4*nf) + 1/(21*nf*nf))
+	c.mean = clipUnits(append([]float64(nil), mean...))
+	c.pc = make([]float64, n)
+	c.ps = make([]float64, n)
+	c.c = identity(n)
+	c.b = identity(n)
+	c.d = make([]float64, n)
+	for i := range c.d {
+		c.d[i] = 1
+	}
+	return c
+}
+
+// sample returns a new point sampled from the current distribution,
+// clipped to the 0-1 range.
+func (c *cmaes) sample(rnd *rand.Rand) []float64 {
+	z := make([]float64, c.n)
+	for i := range z {
+		z[i] = c.d[i] * rnd.NormFloat64()
+	}
+	x := make([]float64, c.n)
+	for i := range x {
+		var y float64
+		for j := range z {
+			y += c.b[i][j] * z[j]
+		}
+		x[i] = c.mean[i] + c.sigma*y
+	}
+	return clipUnits(x)
+}
+
+// tell records the loss for the given point, updating the distribution
+// when a full generation (population) has been recorded.
+func (c *cmaes) tell(x []float64, loss float64) {
+	c.pop = append(c.pop, clipUnits(append([]float64(nil), x...)))
+	c.losses = append(c.losses, loss)
+	if len(c.pop) < c.lambda {
+		return
+	}
+	c.update()
+	c.pop = c.pop[:0]
+	c.losses = c.losses[:0]
+}
+
+// update updates the mean, evolution paths, covariance and step size
+// from the current population.
+func (c *cmaes) update() {
+	n := c.n
+	order := make([]int, len(c.pop))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return c.losses[order[a]] < c.losses[order[b]] })
+	old := c.mean
+	c.mean = make([]float64, n)
+	for k := range c.mu {
+		x := c.pop[order[k]]
+		for i := range c.mean {
+			c.mean[i] += c.w[k] * x[i]
+		}
+	}
+	step := make([]float64, n)
+	for i := range step {
+		step[i] = (c.mean[i] - old[i]) / c.sigma
+	}
+	// C^-1/2 step = B D^-1 B^T step
+	bts := make([]float64, n)
+	for j := range bts {
+		for i := range step {
+			bts[j] += c.b[i][j] * step[i]
+		}
+		bts[j] /= c.d[j]
+	}
+	csn := math.Sqrt(c.cs * (2 - c.cs) * c.mueff)
+	var psn float64
+	for i := range c.ps {
+		var v float64
+		for j := range bts {
+			v += c.b[i][j] * bts[j]
+		}
+		c.ps[i] = (1-c.cs)*c.ps[i] + csn*v
+		psn += c.ps[i] * c.ps[i]
+	}
+	psn = math.Sqrt(psn)
+	c.gen++
+	hsig := 0.0
+	if psn/math.Sqrt(1-math.Pow(1-c.cs, float64(2*c.gen)))/c.chiN < 1.4+2/float64(n+1) {
+		hsig = 1
+	}
+	ccn := math.Sqrt(c.cc * (2 - c.cc) * c.mueff)
+	for i := range c.pc {
+		c.pc[i] = (1-c.cc)*c.pc[i] + hsig*ccn*step[i]
+	}
+	for i := range n {
+		for j := range n {
+			rank1 := c.pc[i]*c.pc[j] + (1-hsig)*c.cc*(2-c.cc)*c.c[i][j]
+			var rankmu float64
+			for k := range c.mu {
+				x := c.pop[order[k]]
+				rankmu += c.w[k] * (x[i] - old[i]) * (x[j] - old[j]) / (c.sigma * c.sigma)
+			}
+			c.c[i][j] = (1-c.c1-c.cmu)*c.c[i][j] + c.c1*rank1 + c.cmu*rankmu
+		}
+	}
+	c.sigma *= math.Exp((c.cs / c.damps) * (psn/c.chiN - 1))
+	c.sigma = min(c.sigma, 1) // the space is only 0-1
+	evals, evecs := symEigen(c.c)
+	for i := range evals {
+		c.d[i] = math.Sqrt(max(evals[i], 1e-20))
+	}
+	c.b = evecs
+}
+
+// clipUnits clips the values to the 0-1 range, returning them.
+func clipUnits(x []float64) []float64 {
+	for i := range x {
+		x[i] = min(max(x[i], 0), 1)
+	}
+	return x
+}
+
+// identity returns an n x n identity matrix.
+func identity(n int) [][]float64 {
+	m := make([][]float64, n)
+	for i := range m {
+		m[i] = make([]float64, n)
+		m[i][i] = 1
+	}
+	return m
+}
+
+// symEigen returns the eigenvalues and eigenvectors (as the columns of
+// the returned matrix) of the given symmetric matrix, using the cyclic
+// Jacobi method, which is accurate and simple for small matrices.
+func symEigen(m [][]float64) ([]float64, [][]float64) {
+	n := len(m)
+	a := make([][]float64, n)
+	for i := range a {
+		a[i] = append([]float64(nil), m[i]...)
+	}
+	v := identity(n)
+	for sweep := 0; sweep < 50; sweep++ {
+		var off float64
+		for i := range n {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < 1e-30 {
+			break
+		}
+		for p := range n {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := 1 / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				if theta < 0 {
+					t = -t
+				}
+				cs := 1 / math.Sqrt(t*t+1)
+				sn := t * cs
+				for k := range n {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = cs*akp - sn*akq
+					a[k][q] = sn*akp + cs*akq
+				}
+				for k := range n {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = cs*apk - sn*aqk
+					a[q][k] = sn*apk + cs*aqk
+				}
+				for k := range n {
+					vkp, vkq := v[k][p], v[k][q]
+					v[k][p] = cs*vkp - sn*vkq
+					v[k][q] = sn*vkp + cs*vkq
+				}
+			}
+		}
+	}
+	evals := make([]float64, n)
+	for i := range evals {
+		evals[i] = a[i][i]
+	}
+	return evals, v
+}
@@ -0,0 +1,106 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// optimize searches for the sim parameters that optimize a column of the
+// run log (e.g., the mean memory score across runs), running the sim as a
+// separate process for each evaluation, optionally in parallel, using
+// random or grid search, Bayesian optimization, or CMA-ES.
+// See package search for details.
+//
+// Usage:
+//
+//	optimize [flags] -- <sim command and args>
+//
+// For example:
+//
+//	optimize -param '#Hidden1:Layer.Inhib.Layer.Gi=1.2,2.4' -param 'Path:Path.Learn.Lrate=0.01,0.1,log' \
+//	  -mode CMAES -par 6 -col FirstZero -- ./ra25 -nogui -Run.NRuns=2 -Params.Tag={tag} -Params.Network={params}
+//
+// Config arg parameters (starting with -) are passed to the sim in place of
+// an {args} argument of its command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// listFlag is a repeatable string flag.
+type listFlag []string
+
+func (lf *listFlag) String() string     { return strings.Join(*lf, " ") }
+func (lf *listFlag) Set(s string) error { *lf = append(*lf, s); return nil }
+
+func main() {
+	var params listFlag
+	sr := &search.Search{}
+	sr.Defaults()
+	var col, dir, out, mode string
+	var maximize, keep bool
+	var par int
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] -- <sim command and args, with {tag} and {params}>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Var(&params, "param", "parameter to optimize, as Selector:Path=Min,Max[,log|int] or -Config.Arg=Min,Max[,log|int] -- repeat for each parameter")
+	flag.StringVar(&col, "col", "", "run log column to optimize, averaged across the rows (runs) of the log")
+	flag.BoolVar(&maximize, "max", false, "maximize the column, instead of minimizing it")
+	flag.StringVar(&mode, "mode", search.Bayes.String(), "search mode: NelderMead, Bayes, Random, Grid or CMAES")
+	flag.IntVar(&sr.MaxEvals, "evals", sr.MaxEvals, "maximum number of sim runs")
+	flag.IntVar(&par, "par", 1, "number of sim processes to run in parallel")
+	flag.IntVar(&sr.GridN, "gridn", sr.GridN, "number of values of each parameter for Grid mode")
+	flag.IntVar(&sr.CMAES.PopSize, "popsize", 0, "population size for CMAES mode -- 0 for the default")
+	flag.Int64Var(&sr.Seed, "seed", 1, "random seed for the search")
+	flag.StringVar(&dir, "dir", "optimize_runs", "directory for the run directories")
+	flag.BoolVar(&keep, "keep", false, "keep the run directories")
+	flag.StringVar(&out, "out", "optimize_evals.tsv", "file to save the table of all the evaluations -- none if empty")
+	flag.Parse()
+	if flag.NArg() < 1 || len(params) == 0 || col == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+	md, err := search.ParseMode(mode)
+	if err != nil {
+		fail(err)
+	}
+	sr.Mode = md
+	for _, ps := range params {
+		pr, err := search.ParseParam(ps)
+		if err != nil {
+			fail(err)
+		}
+		sr.Space = append(sr.Space, pr)
+	}
+	sc := search.NewSweepCommand(dir, flag.Args()...)
+	sc.Keep = keep
+	best, err := sr.MinimizeParallel(sc.Objective(sr.Space, col, maximize), par)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	if out != "" {
+		if err := sr.EvalsTable().SaveCSV(core.Filename(out), table.Tab, table.Headers); err != nil {
+			fail(err)
+		}
+		fmt.Printf("evaluations saved in: %s\n", out)
+	}
+	if len(sr.Evals) == 0 {
+		os.Exit(1)
+	}
+	val := best.Loss
+	if maximize {
+		val = -val
+	}
+	fmt.Printf("best %s: %g in %d evaluations, with params:\n%s\n", col, val, len(sr.Evals), sr.Space.TOML(best.Values))
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
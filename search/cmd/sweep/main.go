@@ -0,0 +1,193 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// sweep runs a sim for combinations of crossed parameter factors, as
+// separate processes (optionally in parallel), and aggregates the run logs
+// of all the combinations into one table, with a summary of the mean and
+// SEM of each stat per combination.  See search.Sweep for details.
+//
+// With two or more -goal flags, the combinations that are Pareto-optimal
+// on those summary stats (e.g., memory score, epochs to criterion and
+// wall-clock time, see search.Goal) are reported and saved, instead of
+// combining them into one score, with a scatter plot of the first two goals.
+//
+// With -seedvar, the variance of each stat across the runs is decomposed
+// into the variance due to the parameters and to the random seeds of the
+// runs, with the number of seeds (runs) needed per combination for a target
+// precision of its mean (see search.Sweep.SeedVariance).
+//
+// Usage:
+//
+//	sweep [flags] -- <sim command and args>
+//
+// For example:
+//
+//	sweep -factor '#Hidden1:Layer.Inhib.Layer.Gi=1.6,1.8,2.0' -factor '-Run.NZero=1,2' \
+//	  -par 4 -- ./ra25 -nogui -Params.Tag={tag} -Params.Network={params} {args}
+//
+// or, for the Pareto front of the hip example on memory, speed and time:
+//
+//	sweep -factor '-NReplays=0,5,10,20' -factor '-Sheet=Base,STP' -goal TstABMem:max \
+//	  -goal FirstPerfect:min -goal WallSec:min -par 4 -- ./hip -nogui -Tag={tag} {args}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+	"github.com/emer/leabra/v2/search"
+)
+
+// listFlag is a repeatable string flag.
+type listFlag []string
+
+func (lf *listFlag) String() string     { return strings.Join(*lf, " ") }
+func (lf *listFlag) Set(s string) error { *lf = append(*lf, s); return nil }
+
+func main() {
+	var factors, stats, goalStrs listFlag
+	sw := &search.Sweep{}
+	var dir, out string
+	var keep, seedVar bool
+	var seedSEM, seedRelSEM float64
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s: [flags] -- <sim command and args, with {tag}, {params} and {args}>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Var(&factors, "factor", "factor to cross, as Selector:Path=Value1,Value2,... or -Config.Arg=Value1,Value2,... -- repeat for each factor")
+	flag.Var(&stats, "stat", "run log column to aggregate -- repeat for each stat -- default is all numerical columns")
+	flag.Var(&goalStrs, "goal", "summary stat to report the Pareto front on, as Column[:max|:min] (default min) -- repeat for each goal")
+	flag.BoolVar(&seedVar, "seedvar", false, "report the decomposition of the variance of each stat into parameter and seed variance, and the seeds needed per combination (_seeds.tsv)")
+	flag.Float64Var(&seedSEM, "seed-sem", 0, "target SEM of the mean of each combination for -seedvar, in the units of each stat -- 0 to use -seed-relsem")
+	flag.Float64Var(&seedRelSEM, "seed-relsem", 0.25, "target SEM of the mean of each combination for -seedvar, relative to the SD due to the parameters")
+	flag.IntVar(&sw.NSamples, "samples", 0, "number of combinations to sample at random -- 0 for all")
+	flag.Int64Var(&sw.Seed, "seed", 1, "random seed for sampling combinations")
+	flag.IntVar(&sw.Parallel, "par", 1, "number of sim processes to run in parallel")
+	flag.StringVar(&dir, "dir", "sweep_runs", "directory for the run directories")
+	flag.BoolVar(&keep, "keep", false, "keep the run directories")
+	flag.StringVar(&out, "out", "sweep", "prefix of the files to save the results (_runs.tsv), summary (_summary.tsv), Pareto front (_pareto.tsv, _pareto.svg) and seed variance (_seeds.tsv)")
+	flag.Parse()
+	if flag.NArg() < 1 || len(factors) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	for _, fs := range factors {
+		fc, err := search.ParseFactor(fs)
+		if err != nil {
+			fail(err)
+		}
+		sw.Factors = append(sw.Factors, fc)
+	}
+	var goals []search.Goal
+	for _, gs := range goalStrs {
+		gl, err := search.ParseGoal(gs)
+		if err != nil {
+			fail(err)
+		}
+		goals = append(goals, gl)
+	}
+	sw.Stats = stats
+	sc := search.NewSweepCommand(dir, flag.Args()...)
+	sc.Keep = keep
+	fmt.Printf("running %d of %d combinations\n", len(sw.Combos()), sw.NCombos())
+	rs, err := sw.Run(sc.Run)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		if rs == nil {
+			os.Exit(1)
+		}
+	}
+	if err := rs.SaveCSV(core.Filename(out+"_runs.tsv"), table.Tab, table.Headers); err != nil {
+		fail(err)
+	}
+	sm := sw.Summary()
+	if err := sm.SaveCSV(core.Filename(out+"_summary.tsv"), table.Tab, table.Headers); err != nil {
+		fail(err)
+	}
+	fmt.Printf("results saved in: %s_runs.tsv and %s_summary.tsv\n", out, out)
+	if len(goals) > 0 {
+		if err := pareto(sm, goals, out); err != nil {
+			fail(err)
+		}
+	}
+	if seedVar {
+		if err := seedVariance(sw, seedSEM, seedRelSEM, out); err != nil {
+			fail(err)
+		}
+	}
+}
+
+// seedVariance prints and saves the seed variance decomposition.
+func seedVariance(sw *search.Sweep, sem, relSEM float64, out string) error {
+	if err := sw.SeedVarianceTable(sem, relSEM).SaveCSV(core.Filename(out+"_seeds.tsv"), table.Tab, table.Headers); err != nil {
+		return err
+	}
+	fmt.Printf("\nVariance due to parameters vs. seeds:\n")
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(tw, "Stat\tMean\tVarParams\tVarSeeds\tFracSeeds\tSEM\tTargetSEM\tSeedsNeeded")
+	for _, sv := range sw.SeedVariance(sem, relSEM) {
+		fmt.Fprintf(tw, "%s\t%.4g\t%.4g\t%.4g\t%.3f\t%.4g\t%.4g\t%g\n", sv.Stat, sv.Mean, sv.VarParams, sv.VarSeeds, sv.FracSeeds, sv.SEM, sv.TargetSEM, sv.SeedsNeeded)
+	}
+	tw.Flush()
+	fmt.Printf("seed variance saved in: %s_seeds.tsv\n", out)
+	return nil
+}
+
+// pareto prints and saves the Pareto front of the summary on the goals,
+// and its plot if there are at least two goals.
+func pareto(sm *table.Table, goals []search.Goal, out string) error {
+	pt, err := search.ParetoTable(sm, goals, false)
+	if err != nil {
+		return err
+	}
+	if err := pt.SaveCSV(core.Filename(out+"_pareto.tsv"), table.Tab, table.Headers); err != nil {
+		return err
+	}
+	fmt.Printf("\nPareto front on %v:\n", goals)
+	tw := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "Tag")
+	for _, gl := range goals {
+		fmt.Fprintf(tw, "\t%s", gl.Column)
+	}
+	fmt.Fprintln(tw)
+	for row := range pt.Rows {
+		if pt.Float("ParetoRank", row) != 0 {
+			break
+		}
+		fmt.Fprintf(tw, "%s", pt.StringValue("Tag", row))
+		for _, gl := range goals {
+			fmt.Fprintf(tw, "\t%.4g", pt.Float(gl.Column, row))
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	fmt.Printf("Pareto ranks saved in: %s_pareto.tsv\n", out)
+	if len(goals) < 2 {
+		return nil
+	}
+	ranks, err := search.ParetoRanks(sm, goals)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(out + "_pareto.svg")
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := search.WriteParetoSVG(f, sm, goals[0], goals[1], ranks); err != nil {
+		return err
+	}
+	fmt.Printf("Pareto plot saved in: %s_pareto.svg\n", out)
+	return nil
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,227 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// Goal is one of the objectives of a multi-objective comparison of
+// configurations, e.g., the rows of a Sweep Summary: a numerical column
+// to minimize or maximize, such as a memory score (max), the number of
+// epochs to criterion (min), or the run wall-clock time (min).
+type Goal struct {
+
+	// Column is the name of the column.
+	Column string
+
+	// Maximize is true if larger values are better.
+	Maximize bool
+}
+
+// ParseGoal parses a Goal from the string Column[:max|:min],
+// where the default is to minimize, e.g., TstABMem:max.
+func ParseGoal(s string) (Goal, error) {
+	gl := Goal{Column: s}
+	if col, dir, ok := strings.Cut(s, ":"); ok {
+		gl.Column = col
+		switch dir {
+		case "max":
+			gl.Maximize = true
+		case "min":
+		default:
+			return gl, fmt.Errorf("search.ParseGoal: %q must be Column[:max|:min]", s)
+		}
+	}
+	if gl.Column == "" {
+		return gl, fmt.Errorf("search.ParseGoal: %q has no Column", s)
+	}
+	return gl, nil
+}
+
+func (gl Goal) String() string {
+	if gl.Maximize {
+		return gl.Column + ":max"
+	}
+	return gl.Column + ":min"
+}
+
+// loss returns the given value of the column oriented so that
+// smaller is better.
+func (gl Goal) loss(v float64) float64 {
+	if gl.Maximize {
+		return -v
+	}
+	return v
+}
+
+// ParetoRanks returns the Pareto rank of each row of the given table for
+// the given goals, by non-dominated sorting, instead of combining the goals
+// into one score: a row dominates another if it is at least as good on all
+// of the goals and better on at least one, and rank 0 is the Pareto front
+// of the rows that are not dominated by any other row, rank 1 is the front
+// of the remaining rows, and so on.  Rows with a NaN value for any goal
+// have rank -1.
+func ParetoRanks(dt *table.Table, goals []Goal) ([]int, error) {
+	if len(goals) == 0 {
+		return nil, fmt.Errorf("search.ParetoRanks: no goals")
+	}
+	for _, gl := range goals {
+		if _, err := dt.ColumnByName(gl.Column); err != nil {
+			return nil, fmt.Errorf("search.ParetoRanks: %w", err)
+		}
+	}
+	n := dt.Rows
+	ls := make([][]float64, n)
+	ranks := make([]int, n)
+	var rest []int
+	for row := range n {
+		ls[row] = make([]float64, len(goals))
+		ranks[row] = -1
+		valid := true
+		for gi, gl := range goals {
+			v := dt.Float(gl.Column, row)
+			if math.IsNaN(v) {
+				valid = false
+			}
+			ls[row][gi] = gl.loss(v)
+		}
+		if valid {
+			rest = append(rest, row)
+		}
+	}
+	dominates := func(a, b []float64) bool {
+		better := false
+		for i := range a {
+			if a[i] > b[i] {
+				return false
+			}
+			if a[i] < b[i] {
+				better = true
+			}
+		}
+		return better
+	}
+	for rank := 0; len(rest) > 0; rank++ {
+		var next []int
+		for _, r := range rest {
+			dom := false
+			for _, o := range rest {
+				if dominates(ls[o], ls[r]) {
+					dom = true
+					break
+				}
+			}
+			if dom {
+				next = append(next, r)
+			} else {
+				ranks[r] = rank
+			}
+		}
+		rest = next
+	}
+	return ranks, nil
+}
+
+// ParetoTable returns a copy of the given table with a ParetoRank column
+// (see ParetoRanks) added, sorted by rank and then by the first goal,
+// with the rows with a NaN goal value (rank -1) at the end.
+// If front is true, only the rows on the Pareto front (rank 0) are included.
+func ParetoTable(dt *table.Table, goals []Goal, front bool) (*table.Table, error) {
+	ranks, err := ParetoRanks(dt, goals)
+	if err != nil {
+		return nil, err
+	}
+	pt := dt.Clone()
+	rc := pt.AddIntColumn("ParetoRank")
+	for row, rk := range ranks {
+		rc.SetFloat1D(row, float64(rk))
+	}
+	ix := table.NewIndexView(pt)
+	if front {
+		ix.Filter(func(et *table.Table, row int) bool { return ranks[row] == 0 })
+	}
+	g0 := goals[0]
+	ix.SortStable(func(et *table.Table, i, j int) bool {
+		ri, rj := ranks[i], ranks[j]
+		if ri != rj {
+			return rj < 0 || (ri >= 0 && ri < rj)
+		}
+		return g0.loss(et.Float(g0.Column, i)) < g0.loss(et.Float(g0.Column, j))
+	})
+	return ix.NewTable(), nil
+}
+
+// WriteParetoSVG writes an SVG scatter plot of the rows of the given table
+// on the x and y goals, with the given Pareto ranks (see ParetoRanks),
+// highlighting the rows on the Pareto front (rank 0), which are labeled
+// by their Tag column, if present.  The ranks can be for more goals than
+// the two that are plotted.
+func WriteParetoSVG(w io.Writer, dt *table.Table, x, y Goal, ranks []int) error {
+	for _, gl := range []Goal{x, y} {
+		if _, err := dt.ColumnByName(gl.Column); err != nil {
+			return fmt.Errorf("search.WriteParetoSVG: %w", err)
+		}
+	}
+	const wd, ht, mg = 640.0, 400.0, 50.0
+	rng := func(col string) (float64, float64) {
+		mn, mx := math.Inf(1), math.Inf(-1)
+		for row := range dt.Rows {
+			v := dt.Float(col, row)
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				mn = min(mn, v)
+				mx = max(mx, v)
+			}
+		}
+		if mn > mx {
+			mn, mx = 0, 1
+		}
+		if mx == mn {
+			mx = mn + 1
+		}
+		return mn, mx
+	}
+	x0, x1 := rng(x.Column)
+	y0, y1 := rng(y.Column)
+	px := func(v float64) float64 { return mg + (v-x0)/(x1-x0)*(wd-2*mg) }
+	py := func(v float64) float64 { return ht - mg - (v-y0)/(y1-y0)*(ht-2*mg) }
+	_, tagErr := dt.ColumnByName("Tag")
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" font-family="sans-serif" font-size="12">`+"\n", wd, ht)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(w, `<text x="%g" y="20" text-anchor="middle" font-size="14">Pareto front: %s vs. %s</text>`+"\n", wd/2, y, x)
+	fmt.Fprintf(w, `<polyline points="%g,%g %g,%g %g,%g" fill="none" stroke="black"/>`+"\n", mg, mg, mg, ht-mg, wd-mg, ht-mg)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="end">%.4g</text>`+"\n", mg-4, mg+4, y1)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="end">%.4g</text>`+"\n", mg-4, ht-mg+4, y0)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">%.4g</text>`+"\n", mg, ht-mg+16, x0)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">%.4g</text>`+"\n", wd-mg, ht-mg+16, x1)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle">%s</text>`+"\n", wd/2, ht-mg+16, x.Column)
+	fmt.Fprintf(w, `<text x="%g" y="%g" text-anchor="middle" transform="rotate(-90 %g %g)">%s</text>`+"\n", mg-8, ht/2, mg-8, ht/2, y.Column)
+	for _, onFront := range []bool{false, true} { // front drawn on top
+		for row := range dt.Rows {
+			if row >= len(ranks) || (ranks[row] == 0) != onFront {
+				continue
+			}
+			xv, yv := dt.Float(x.Column, row), dt.Float(y.Column, row)
+			if math.IsNaN(xv) || math.IsNaN(yv) || math.IsInf(xv, 0) || math.IsInf(yv, 0) {
+				continue
+			}
+			if !onFront {
+				fmt.Fprintf(w, `<circle cx="%.2f" cy="%.2f" r="3" fill="#999999"/>`+"\n", px(xv), py(yv))
+				continue
+			}
+			fmt.Fprintf(w, `<circle cx="%.2f" cy="%.2f" r="5" fill="#d62728"/>`+"\n", px(xv), py(yv))
+			if tagErr == nil {
+				fmt.Fprintf(w, `<text x="%.2f" y="%.2f" font-size="10" fill="#d62728">%s</text>`+"\n", px(xv)+7, py(yv)-7, dt.StringValue("Tag", row))
+			}
+		}
+	}
+	fmt.Fprintf(w, "</svg>\n")
+	return nil
+}
@@ -0,0 +1,664 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+Package search provides parameter search over a Space of network
+parameters, each specified by a params Selector:Path key (as used in the
+Params.Network map of the example sims, see emer.NetParams.SetNetworkMap)
+or a sim config arg (e.g., -NReplays), and a range of values, optionally
+on a log scale or rounded to integers.
+
+A Search minimizes an objective (loss) function of the parameter values,
+using one of the derivative-free Modes on the parameter values normalized
+to the 0-1 range, which are suitable for the small number of parameters
+and the expensive, somewhat noisy, objectives obtained from running
+simulations: the Nelder-Mead simplex method, Bayesian optimization
+with a Gaussian process surrogate model of the objective, random or grid
+search, or the CMA-ES evolution strategy.  All of the evaluations are
+recorded, so they can be saved and inspected.
+
+For running the simulations separately, e.g., as parallel cluster jobs,
+Propose returns the next configurations to run, and Tell records their
+results, as in the Optimizer interface.  MinimizeParallel runs the
+proposed configurations in parallel, e.g., using a SweepCommand
+Objective, which runs the sim as a separate process and returns
+the mean of a column of its run log.
+
+A Sweep runs a sim for all (or a random sample) of the combinations of
+the values of any number of crossed Factors, which are either parameter
+keys or config args (e.g., -Params.Sheet), optionally as parallel
+processes using a SweepCommand, and aggregates the resulting run stats
+into one table.  The sweep command provides a command-line interface.
+
+Instead of combining multiple objectives into one score, the rows of a
+table of results (e.g., a Sweep Summary) can be compared on several Goals,
+such as a memory score, the number of epochs to criterion, and the
+wall-clock time per run, with ParetoRanks and ParetoTable returning the
+configurations on the Pareto front, which are not dominated by any other
+configuration on all of the goals, and WriteParetoSVG plotting them.
+Sweep.SeedVariance decomposes the variance of each stat into the variance
+due to the parameters and to the random seeds of the runs, and reports
+the number of seeds needed per combination for a target precision.
+*/
+package search
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// Modes are the search methods.
+type Modes int32
+
+const (
+	// NelderMead uses the Nelder-Mead simplex method, which is
+	// efficient for smooth objectives with few parameters.
+	// It is inherently sequential, so Propose uses Bayes instead.
+	NelderMead Modes = iota
+
+	// Bayes uses Bayesian optimization, with a Gaussian process
+	// surrogate model of the objective fit to all of the evaluations,
+	// and the next configuration to run chosen to maximize the expected
+	// improvement over the best loss.  This requires many fewer runs than
+	// grid search, and explores the whole space, so it is best for
+	// expensive simulations.  See BayesParams.
+	Bayes
+
+	// Random samples the parameters uniformly at random within their
+	// ranges (on a log scale for Log parameters), which is a good
+	// baseline, and better than a grid when only some of the parameters
+	// matter.
+	Random
+
+	// Grid evaluates a grid of GridN values of each parameter, spanning
+	// their ranges, in order, up to MaxEvals evaluations.
+	Grid
+
+	// CMAES uses the covariance matrix adaptation evolution strategy,
+	// which samples a population of configurations from a multivariate
+	// normal distribution, and adapts its mean, step size and covariance
+	// toward the best ones in each generation.  It is robust to noise and
+	// parameter interactions, and the population can be run in parallel.
+	// See CMAESParams.
+	CMAES
+)
+
+func (md Modes) String() string {
+	switch md {
+	case NelderMead:
+		return "NelderMead"
+	case Bayes:
+		return "Bayes"
+	case Random:
+		return "Random"
+	case Grid:
+		return "Grid"
+	case CMAES:
+		return "CMAES"
+	}
+	return "Modes(?)"
+}
+
+// ParseMode returns the Modes value for the given name.
+func ParseMode(s string) (Modes, error) {
+	for md := NelderMead; md <= CMAES; md++ {
+		if md.String() == s {
+			return md, nil
+		}
+	}
+	return NelderMead, errors.New("search.ParseMode: unknown mode: " + s + " (NelderMead, Bayes, Random, Grid or CMAES)")
+}
+
+// Optimizer proposes parameter values to evaluate, and is told the
+// resulting losses, so that the evaluations can be run separately,
+// e.g., in parallel.  Search is an Optimizer.
+type Optimizer interface {
+
+	// Propose returns the parameter values for the next n
+	// configurations to evaluate.
+	Propose(n int) [][]float64
+
+	// Tell records the loss for an evaluation of the given values.
+	Tell(vals []float64, loss float64)
+}
+
+// Param is one parameter dimension of a search Space.
+type Param struct {
+
+	// Key is the params Selector:Path of the parameter,
+	// e.g., #Hidden:Layer.Inhib.Layer.Gi, as used in the
+	// Params.Network map of the sim config, or a config arg
+	// starting with -, e.g., -NReplays, for sim config values.
+	Key string
+
+	// Min is the minimum value to search.
+	Min float64
+
+	// Max is the maximum value to search.
+	Max float64
+
+	// Log searches the values on a log scale, for parameters that
+	// vary over orders of magnitude, e.g., learning rates.
+	// Min and Max must be > 0.
+	Log bool
+
+	// Int rounds the values to integers, for integer parameters,
+	// e.g., a number of trials.
+	Int bool
+}
+
+// ParseParam parses a Param from a string of the form
+// Key=Min,Max[,log|int], e.g., #Hidden:Layer.Inhib.Layer.Gi=1.2,2.4
+// or -NReplays=0,20,int
+func ParseParam(s string) (Param, error) {
+	var pr Param
+	key, rng, ok := strings.Cut(s, "=")
+	if !ok || key == "" {
+		return pr, fmt.Errorf("search.ParseParam: not of form Key=Min,Max[,log|int]: %s", s)
+	}
+	pr.Key = strings.TrimSpace(key)
+	fs := strings.Split(rng, ",")
+	if len(fs) < 2 || len(fs) > 3 {
+		return pr, fmt.Errorf("search.ParseParam: not of form Key=Min,Max[,log|int]: %s", s)
+	}
+	var err error
+	if pr.Min, err = strconv.ParseFloat(strings.TrimSpace(fs[0]), 64); err != nil {
+		return pr, fmt.Errorf("search.ParseParam: %s: %w", s, err)
+	}
+	if pr.Max, err = strconv.ParseFloat(strings.TrimSpace(fs[1]), 64); err != nil {
+		return pr, fmt.Errorf("search.ParseParam: %s: %w", s, err)
+	}
+	if len(fs) == 3 {
+		switch strings.TrimSpace(fs[2]) {
+		case "log":
+			pr.Log = true
+		case "int":
+			pr.Int = true
+		default:
+			return pr, fmt.Errorf("search.ParseParam: only log or int is supported after Min,Max: %s", s)
+		}
+	}
+	return pr, pr.Check()
+}
+
+// IsArg returns true if the parameter is a config arg, starting with -,
+// rather than a params Selector:Path.
+func (pr *Param) IsArg() bool {
+	return strings.HasPrefix(pr.Key, "-")
+}
+
+// Check returns an error if the range of the parameter is not valid.
+func (pr *Param) Check() error {
+	if !(pr.Max > pr.Min) {
+		return fmt.Errorf("search.Param %s: Max %g must be > Min %g", pr.Key, pr.Max, pr.Min)
+	}
+	if pr.Log && pr.Min <= 0 {
+		return fmt.Errorf("search.Param %s: Min %g must be > 0 for Log", pr.Key, pr.Min)
+	}
+	return nil
+}
+
+// Value returns the parameter value for the given normalized
+// value u in the 0-1 range (clipped to the range).
+func (pr *Param) Value(u float64) float64 {
+	u = min(max(u, 0), 1)
+	var v float64
+	if pr.Log {
+		v = math.Exp(math.Log(pr.Min) + u*(math.Log(pr.Max)-math.Log(pr.Min)))
+	} else {
+		v = pr.Min + u*(pr.Max-pr.Min)
+	}
+	if pr.Int {
+		v = math.Round(v)
+	}
+	return v
+}
+
+// Unit returns the normalized 0-1 value for the given parameter value.
+func (pr *Param) Unit(v float64) float64 {
+	if pr.Log {
+		if v <= 0 {
+			return 0
+		}
+		return (math.Log(v) - math.Log(pr.Min)) / (math.Log(pr.Max) - math.Log(pr.Min))
+	}
+	return (v - pr.Min) / (pr.Max - pr.Min)
+}
+
+// Space is the set of parameters to search over.
+type Space []Param
+
+// Check returns an error if any of the parameter ranges are not valid,
+// or there are no parameters.
+func (sp Space) Check() error {
+	if len(sp) == 0 {
+		return errors.New("search.Space: no parameters")
+	}
+	var errs []error
+	for i := range sp {
+		if err := sp[i].Check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Keys returns the parameter keys.
+func (sp Space) Keys() []string {
+	keys := make([]string, len(sp))
+	for i := range sp {
+		keys[i] = sp[i].Key
+	}
+	return keys
+}
+
+// Values returns the parameter values for the given normalized values.
+func (sp Space) Values(us []float64) []float64 {
+	vals := make([]float64, len(sp))
+	for i := range sp {
+		vals[i] = sp[i].Value(us[i])
+	}
+	return vals
+}
+
+// Units returns the normalized values for the given parameter values.
+func (sp Space) Units(vals []float64) []float64 {
+	us := make([]float64, len(sp))
+	for i := range sp {
+		us[i] = sp[i].Unit(vals[i])
+	}
+	return us
+}
+
+// Map returns a map from the params Selector:Path keys to the given
+// values, excluding the config args, which can be applied to the network
+// with emer.NetParams.SetNetworkMap, or passed as the Params.Network
+// config of a sim.
+func (sp Space) Map(vals []float64) map[string]any {
+	mp := make(map[string]any, len(sp))
+	for i := range sp {
+		if !sp[i].IsArg() {
+			mp[sp[i].Key] = vals[i]
+		}
+	}
+	return mp
+}
+
+// Args returns the config args for the config arg parameters
+// with the given values, e.g., -NReplays=10
+func (sp Space) Args(vals []float64) []string {
+	var args []string
+	for i := range sp {
+		if sp[i].IsArg() {
+			args = append(args, sp[i].Key+"="+strconv.FormatFloat(vals[i], 'g', -1, 64))
+		}
+	}
+	return args
+}
+
+// TOML returns the given values as a TOML inline table of the parameter
+// keys, e.g., for passing as a -Params.Network command-line argument.
+func (sp Space) TOML(vals []float64) string {
+	var b strings.Builder
+	b.WriteString("{")
+	for i := range sp {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(strconv.Quote(sp[i].Key) + " = " + strconv.FormatFloat(vals[i], 'g', -1, 64))
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// Objective is an objective (loss) function of the parameter values
+// to minimize.
+type Objective func(vals []float64) (float64, error)
+
+// Eval is one evaluation of the objective.
+type Eval struct {
+
+	// Values are the parameter values.
+	Values []float64
+
+	// Loss is the value of the objective.
+	Loss float64
+}
+
+// Search minimizes an Objective over the parameters in a Space.
+type Search struct {
+
+	// Space is the parameters to search over.
+	Space Space
+
+	// Mode is the search method.
+	Mode Modes
+
+	// Bayes has the parameters for the Bayes Mode.
+	Bayes BayesParams `display:"inline"`
+
+	// CMAES has the parameters for the CMAES Mode.
+	CMAES CMAESParams `display:"inline"`
+
+	// GridN is the number of values of each parameter for the Grid Mode.
+	GridN int `default:"5" min:"2"`
+
+	// Seed is the random seed for the Bayes, Random and CMAES Modes.
+	Seed int64
+
+	// MaxEvals is the maximum number of evaluations of the objective.
+	MaxEvals int `default:"50"`
+
+	// Tol is the tolerance on the range of losses across the simplex,
+	// below which the search stops.
+	Tol float64 `default:"1e-4"`
+
+	// Step is the size of the initial simplex in the normalized 0-1
+	// parameter space, and the initial step size (standard deviation)
+	// for the CMAES Mode.
+	Step float64 `default:"0.25"`
+
+	// Start are the starting parameter values, or the middle of the
+	// range of each parameter if nil.
+	Start []float64
+
+	// Evals are all of the evaluations in the last Minimize, in order.
+	Evals []Eval
+
+	// Best is the evaluation with the lowest loss in the last Minimize.
+	Best Eval
+
+	// rand is the random number generator.
+	rand *rand.Rand
+
+	// lhs are the Latin hypercube interval permutations
+	// for the initial points in the Bayes Mode.
+	lhs [][]int
+
+	// gridNext is the index of the next grid point for the Grid Mode.
+	gridNext int
+
+	// cma is the CMA-ES state for the CMAES Mode.
+	cma *cmaes
+}
+
+func (sr *Search) Defaults() {
+	sr.MaxEvals = 50
+	sr.Tol = 1e-4
+	sr.Step = 0.25
+	sr.GridN = 5
+	sr.Bayes.Defaults()
+}
+
+// Tell records an evaluation of the objective at the given parameter
+// values, e.g., for a configuration returned by Propose that was run
+// separately.  A NaN loss is recorded as infinite.
+func (sr *Search) Tell(vals []float64, loss float64) {
+	if math.IsNaN(loss) {
+		loss = math.Inf(1)
+	}
+	ev := Eval{Values: slices.Clone(vals), Loss: loss}
+	sr.Evals = append(sr.Evals, ev)
+	if len(sr.Evals) == 1 || loss < sr.Best.Loss {
+		sr.Best = ev
+	}
+	if sr.Mode == CMAES {
+		sr.initCMAES()
+		sr.cma.tell(sr.Space.Units(vals), loss)
+	}
+}
+
+// Propose returns the parameter values for the next n configurations
+// to run, according to the Mode, given the Evals so far (see Tell),
+// e.g., for running them in parallel.  For the Grid Mode, fewer than n
+// are returned when the grid is exhausted.  The NelderMead Mode is
+// sequential, so Bayes is used instead.
+func (sr *Search) Propose(n int) [][]float64 {
+	if sr.rand == nil {
+		sr.rand = rand.New(rand.NewPCG(uint64(sr.Seed), 0x5ea7c4))
+	}
+	switch sr.Mode {
+	case Random:
+		props := make([][]float64, n)
+		for pi := range props {
+			u := make([]float64, len(sr.Space))
+			for d := range u {
+				u[d] = sr.rand.Float64()
+			}
+			props[pi] = sr.Space.Values(u)
+		}
+		return props
+	case Grid:
+		return sr.proposeGrid(n)
+	case CMAES:
+		sr.initCMAES()
+		props := make([][]float64, n)
+		for pi := range props {
+			props[pi] = sr.Space.Values(sr.cma.sample(sr.rand))
+		}
+		return props
+	}
+	return sr.proposeBayes(n)
+}
+
+// proposeGrid returns the next n points of the grid of GridN values
+// of each parameter, with the last parameter varying fastest.
+func (sr *Search) proposeGrid(n int) [][]float64 {
+	gn := max(sr.GridN, 2)
+	total := 1
+	for range sr.Space {
+		total *= gn
+	}
+	var props [][]float64
+	for ; sr.gridNext < total && len(props) < n; sr.gridNext++ {
+		u := make([]float64, len(sr.Space))
+		rem := sr.gridNext
+		for d := len(u) - 1; d >= 0; d-- {
+			u[d] = float64(rem%gn) / float64(gn-1)
+			rem /= gn
+		}
+		props = append(props, sr.Space.Values(u))
+	}
+	return props
+}
+
+// EvalsTable returns a table of the Evals, with a column for each
+// parameter (by Key) and a Loss column, one row per evaluation.
+func (sr *Search) EvalsTable() *table.Table {
+	dt := table.NewTable()
+	for _, k := range sr.Space.Keys() {
+		dt.AddFloat64Column(k)
+	}
+	dt.AddFloat64Column("Loss")
+	dt.SetNumRows(len(sr.Evals))
+	for row, ev := range sr.Evals {
+		for i, k := range sr.Space.Keys() {
+			dt.SetFloat(k, row, ev.Values[i])
+		}
+		dt.SetFloat("Loss", row, ev.Loss)
+	}
+	return dt
+}
+
+// evalValues returns the objective at the given parameter values,
+// with errors returning an infinite loss, accumulated in errs.
+func (sr *Search) evalValues(obj Objective, vals []float64, errs *[]error) float64 {
+	loss, err := obj(vals)
+	if err != nil {
+		*errs = append(*errs, err)
+		loss = math.Inf(1)
+	}
+	return loss
+}
+
+// eval evaluates the objective at the given normalized values,
+// recording the evaluation.
+func (sr *Search) eval(obj Objective, us []float64, errs *[]error) float64 {
+	vals := sr.Space.Values(us)
+	sr.Tell(vals, sr.evalValues(obj, vals, errs))
+	return sr.Evals[len(sr.Evals)-1].Loss
+}
+
+// Minimize searches for the parameter values that minimize the objective,
+// using the search Mode, in at most MaxEvals evaluations, returning the
+// best evaluation.  Evaluations that return an error are given an
+// infinite loss, and the errors are returned along with the best
+// evaluation.
+func (sr *Search) Minimize(obj Objective) (Eval, error) {
+	return sr.MinimizeParallel(obj, 1)
+}
+
+// MinimizeParallel is Minimize with up to par evaluations of the objective
+// run in parallel, from the configurations returned by Propose, so the
+// objective must be safe to call concurrently.  The NelderMead Mode is
+// sequential, so it is always run one evaluation at a time.
+func (sr *Search) MinimizeParallel(obj Objective, par int) (Eval, error) {
+	if err := sr.Space.Check(); err != nil {
+		return Eval{}, err
+	}
+	if sr.MaxEvals <= 0 {
+		sr.Defaults()
+	}
+	sr.Evals = nil
+	sr.Best = Eval{}
+	sr.rand = nil
+	sr.gridNext = 0
+	sr.cma = nil
+	if sr.Mode == NelderMead {
+		return sr.minimizeNelderMead(obj)
+	}
+	var errs []error
+	if sr.Start != nil && sr.Mode != Grid {
+		sr.Tell(sr.Start, sr.evalValues(obj, sr.Start, &errs))
+	}
+	for len(sr.Evals) < sr.MaxEvals {
+		props := sr.Propose(min(max(par, 1), sr.MaxEvals-len(sr.Evals)))
+		if len(props) == 0 {
+			break
+		}
+		losses := make([]float64, len(props))
+		perrs := make([][]error, len(props))
+		var wg sync.WaitGroup
+		for i := range props {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				losses[i] = sr.evalValues(obj, props[i], &perrs[i])
+			}()
+		}
+		wg.Wait()
+		for i := range props {
+			sr.Tell(props[i], losses[i])
+			errs = append(errs, perrs[i]...)
+		}
+	}
+	return sr.Best, errors.Join(errs...)
+}
+
+// minimizeNelderMead minimizes the objective using the Nelder-Mead
+// simplex method in the normalized parameter space, with values
+// clipped to the parameter ranges.
+func (sr *Search) minimizeNelderMead(obj Objective) (Eval, error) {
+	var errs []error
+	n := len(sr.Space)
+	x0 := make([]float64, n)
+	for i := range x0 {
+		x0[i] = 0.5
+	}
+	if sr.Start != nil {
+		x0 = sr.Space.Units(sr.Start)
+	}
+	clip := func(x []float64) {
+		for i := range x {
+			x[i] = min(max(x[i], 0), 1)
+		}
+	}
+	clip(x0)
+	// initial simplex: step along each dimension, away from the nearest bound
+	simp := make([][]float64, n+1)
+	loss := make([]float64, n+1)
+	simp[0] = x0
+	for i := range n {
+		x := slices.Clone(x0)
+		if x[i]+sr.Step <= 1 {
+			x[i] += sr.Step
+		} else {
+			x[i] -= sr.Step
+		}
+		simp[i+1] = x
+	}
+	for i := range simp {
+		loss[i] = sr.eval(obj, simp[i], &errs)
+	}
+	// standard coefficients: reflection, expansion, contraction, shrink
+	const alpha, gamma, rho, sigma = 1.0, 2.0, 0.5, 0.5
+	idx := make([]int, n+1)
+	point := func(c, d []float64, f float64) []float64 {
+		x := make([]float64, n)
+		for i := range x {
+			x[i] = c[i] + f*(d[i]-c[i])
+		}
+		clip(x)
+		return x
+	}
+	for len(sr.Evals) < sr.MaxEvals {
+		for i := range idx {
+			idx[i] = i
+		}
+		sort.SliceStable(idx, func(a, b int) bool { return loss[idx[a]] < loss[idx[b]] })
+		best, worst, second := idx[0], idx[n], idx[max(n-1, 0)]
+		if loss[worst]-loss[best] <= sr.Tol {
+			break
+		}
+		cent := make([]float64, n)
+		for _, si := range idx[:n] {
+			for i := range cent {
+				cent[i] += simp[si][i] / float64(n)
+			}
+		}
+		xr := point(cent, simp[worst], -alpha)
+		lr := sr.eval(obj, xr, &errs)
+		switch {
+		case lr < loss[best]:
+			if len(sr.Evals) >= sr.MaxEvals {
+				simp[worst], loss[worst] = xr, lr
+				break
+			}
+			xe := point(cent, simp[worst], -gamma)
+			if le := sr.eval(obj, xe, &errs); le < lr {
+				simp[worst], loss[worst] = xe, le
+			} else {
+				simp[worst], loss[worst] = xr, lr
+			}
+		case lr < loss[second]:
+			simp[worst], loss[worst] = xr, lr
+		default:
+			if len(sr.Evals) >= sr.MaxEvals {
+				break
+			}
+			xc := point(cent, simp[worst], rho)
+			if lc := sr.eval(obj, xc, &errs); lc < loss[worst] {
+				simp[worst], loss[worst] = xc, lc
+				break
+			}
+			for _, si := range idx[1:] {
+				if len(sr.Evals) >= sr.MaxEvals {
+					break
+				}
+				simp[si] = point(simp[best], simp[si], sigma)
+				loss[si] = sr.eval(obj, simp[si], &errs)
+			}
+		}
+	}
+	return sr.Best, errors.Join(errs...)
+}
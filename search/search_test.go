@@ -0,0 +1,253 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+func TestMinimize(t *testing.T) {
+	sp := Space{{Key: "#Hidden:Layer.Inhib.Layer.Gi", Min: 1, Max: 3}, {Key: "Path:Path.Learn.Lrate", Min: 0.001, Max: 0.1, Log: true}}
+	sr := &Search{Space: sp}
+	sr.Defaults()
+	sr.MaxEvals = 200
+	sr.Tol = 1e-10
+	best, err := sr.Minimize(func(vals []float64) (float64, error) {
+		d0 := vals[0] - 1.8
+		d1 := math.Log10(vals[1]) + 2 // 0.01
+		return d0*d0 + d1*d1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(best.Values[0]-1.8) > 1e-3 || math.Abs(best.Values[1]-0.01) > 1e-4 {
+		t.Errorf("Minimize: got %v, not [1.8 0.01]", best.Values)
+	}
+	if len(sr.Evals) > sr.MaxEvals {
+		t.Errorf("Minimize: %d evals > MaxEvals", len(sr.Evals))
+	}
+	pr, err := ParseParam("#Hidden:Layer.Inhib.Layer.Gi=1.2,2.4")
+	if err != nil || pr.Min != 1.2 || pr.Max != 2.4 || pr.Log {
+		t.Errorf("ParseParam: %+v %v", pr, err)
+	}
+	if _, err := ParseParam("Path:Path.Learn.Lrate=0,0.1,log"); err == nil {
+		t.Errorf("ParseParam: Min 0 with log should be an error")
+	}
+	nr, err := ParseParam("-NReplays=0,20,int")
+	if err != nil || !nr.Int || !nr.IsArg() || nr.Value(0.51) != 10 {
+		t.Errorf("ParseParam int: %+v %v", nr, err)
+	}
+	isp := Space{sp[0], nr}
+	vals := isp.Values([]float64{0.5, 0.33})
+	if mp := isp.Map(vals); len(mp) != 1 || mp[sp[0].Key] != 2.0 {
+		t.Errorf("Map: %v", mp)
+	}
+	if args := isp.Args(vals); len(args) != 1 || args[0] != "-NReplays=7" {
+		t.Errorf("Args: %v", args)
+	}
+}
+
+func TestBayes(t *testing.T) {
+	sp := Space{{Key: "#Hidden:Layer.Inhib.Layer.Gi", Min: 1, Max: 3}, {Key: "Path:Path.Learn.Lrate", Min: 0.001, Max: 0.1, Log: true}}
+	sr := &Search{Space: sp, Mode: Bayes, Seed: 3}
+	sr.Defaults()
+	sr.MaxEvals = 30
+	best, err := sr.Minimize(func(vals []float64) (float64, error) {
+		d0 := vals[0] - 1.8
+		d1 := math.Log10(vals[1]) + 2
+		return d0*d0 + d1*d1, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sr.Evals) != 30 || best.Loss > 0.01 {
+		t.Errorf("Bayes: %d evals, best: %v", len(sr.Evals), best)
+	}
+	props := sr.Propose(3)
+	if len(props) != 3 || props[0][0] == props[1][0] {
+		t.Errorf("Propose: %v", props)
+	}
+}
+
+func TestOptimizers(t *testing.T) {
+	sp := Space{{Key: "#Hidden:Layer.Inhib.Layer.Gi", Min: 1, Max: 3}, {Key: "Path:Path.Learn.Lrate", Min: 0.001, Max: 0.1, Log: true}}
+	obj := func(vals []float64) (float64, error) {
+		d0 := vals[0] - 1.8
+		d1 := math.Log10(vals[1]) + 2
+		return d0*d0 + d1*d1, nil
+	}
+	sr := &Search{Space: sp, Mode: Grid}
+	sr.Defaults()
+	best, err := sr.Minimize(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sr.Evals) != 25 || best.Values[0] != 2 || math.Abs(best.Values[1]-0.01) > 1e-9 {
+		t.Errorf("Grid: %d evals, best: %v", len(sr.Evals), best)
+	}
+	sr = &Search{Space: sp, Mode: Random, Seed: 1}
+	sr.Defaults()
+	if best, _ := sr.MinimizeParallel(obj, 4); len(sr.Evals) != 50 || best.Loss > 0.1 {
+		t.Errorf("Random: %d evals, best: %v", len(sr.Evals), best)
+	}
+	sr = &Search{Space: sp, Mode: CMAES, Seed: 1}
+	sr.Defaults()
+	sr.MaxEvals = 300
+	if best, _ := sr.MinimizeParallel(obj, 6); math.Abs(best.Values[0]-1.8) > 1e-2 || math.Abs(best.Values[1]-0.01) > 1e-3 {
+		t.Errorf("CMAES: best: %v", best)
+	}
+	evals, evecs := symEigen([][]float64{{2, 1}, {1, 2}})
+	if math.Abs(min(evals[0], evals[1])-1) > 1e-9 || math.Abs(max(evals[0], evals[1])-3) > 1e-9 || math.Abs(math.Abs(evecs[0][0])-math.Sqrt(0.5)) > 1e-9 {
+		t.Errorf("symEigen: %v %v", evals, evecs)
+	}
+	if md, err := ParseMode("CMAES"); err != nil || md != CMAES {
+		t.Errorf("ParseMode: %v %v", md, err)
+	}
+}
+
+func TestSweep(t *testing.T) {
+	gi, err := ParseFactor("#Hidden:Layer.Inhib.Layer.Gi=1.6,1.8,2.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet, err := ParseFactor("-Params.Sheet=SmallHip,BigHip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseFactor("Path:Path.Learn.Lrate=0.01,fast"); err == nil {
+		t.Errorf("ParseFactor: non-numerical value should be an error")
+	}
+	sw := &Sweep{Factors: []Factor{gi, sheet}, Parallel: 3}
+	cbs := sw.Combos()
+	if len(cbs) != 6 || cbs[1].Tag != "Gi1.6_SheetBigHip" || cbs[1].Args[0] != "-Params.Sheet=BigHip" || cbs[1].Params[gi.Key] != 1.6 {
+		t.Fatalf("Combos: %d %+v", len(cbs), cbs[1])
+	}
+	if tm := cbs[5].ParamsTOML(); tm != `{"#Hidden:Layer.Inhib.Layer.Gi" = 2}` {
+		t.Errorf("ParamsTOML: %s", tm)
+	}
+	rs, err := sw.Run(func(cb *Combo) (*table.Table, error) {
+		dt := table.NewTable()
+		dt.AddFloat64Column("PctCor")
+		dt.SetNumRows(2)
+		g := cb.Params[gi.Key].(float64)
+		dt.SetFloat("PctCor", 0, g)
+		dt.SetFloat("PctCor", 1, g+0.2)
+		return dt, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rs.Rows != 12 || rs.StringValue("Tag", 11) != "Gi2.0_SheetBigHip" || rs.StringValue("-Params.Sheet", 8) != "SmallHip" {
+		t.Errorf("Run: %d rows", rs.Rows)
+	}
+	sm := sw.Summary()
+	if sm.Rows != 6 || math.Abs(sm.Float("PctCor", 2)-1.9) > 1e-9 || math.Abs(sm.Float("PctCor_SEM", 2)-0.1) > 1e-9 {
+		t.Errorf("Summary: %d rows, %g %g", sm.Rows, sm.Float("PctCor", 2), sm.Float("PctCor_SEM", 2))
+	}
+	sw.NSamples = 4
+	if cbs := sw.Combos(); len(cbs) != 4 || cbs[0].Index >= cbs[1].Index {
+		t.Errorf("Combos sampled: %d", len(cbs))
+	}
+}
+
+func TestPareto(t *testing.T) {
+	goals := make([]Goal, 2)
+	for i, s := range []string{"Mem:max", "Epochs"} {
+		gl, err := ParseGoal(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		goals[i] = gl
+	}
+	if !goals[0].Maximize || goals[1].Maximize || goals[1].String() != "Epochs:min" {
+		t.Errorf("ParseGoal: %v", goals)
+	}
+	if _, err := ParseGoal("Mem:best"); err == nil {
+		t.Errorf("ParseGoal: bad direction should be an error")
+	}
+	dt := table.NewTable()
+	dt.AddStringColumn("Tag")
+	dt.AddFloat64Column("Mem")
+	dt.AddFloat64Column("Epochs")
+	rows := []struct {
+		tag         string
+		mem, epochs float64
+	}{
+		{"a", 0.9, 10}, {"b", 0.8, 5}, {"c", 0.7, 8}, {"d", 0.95, 20}, {"e", 0.6, 12}, {"f", math.NaN(), 1},
+	}
+	dt.SetNumRows(len(rows))
+	for i, r := range rows {
+		dt.SetString("Tag", i, r.tag)
+		dt.SetFloat("Mem", i, r.mem)
+		dt.SetFloat("Epochs", i, r.epochs)
+	}
+	ranks, err := ParetoRanks(dt, goals)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{0, 0, 1, 0, 2, -1}; !slices.Equal(ranks, want) {
+		t.Errorf("ParetoRanks: %v, want %v", ranks, want)
+	}
+	ft, err := ParetoTable(dt, goals, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ft.Rows != 3 || ft.StringValue("Tag", 0) != "d" || ft.StringValue("Tag", 2) != "b" {
+		t.Errorf("ParetoTable front: %d rows, first %s", ft.Rows, ft.StringValue("Tag", 0))
+	}
+	pt, _ := ParetoTable(dt, goals, false)
+	if pt.Rows != 6 || pt.StringValue("Tag", 4) != "e" || pt.Float("ParetoRank", 5) != -1 {
+		t.Errorf("ParetoTable: %d rows", pt.Rows)
+	}
+	if _, err := ParetoRanks(dt, []Goal{{Column: "Time"}}); err == nil {
+		t.Errorf("ParetoRanks: missing column should be an error")
+	}
+	var b strings.Builder
+	if err := WriteParetoSVG(&b, dt, goals[0], goals[1], ranks); err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(b.String(), `r="5"`); n != 3 {
+		t.Errorf("WriteParetoSVG: %d front points", n)
+	}
+}
+
+func TestSeedVariance(t *testing.T) {
+	rs := table.NewTable()
+	rs.AddStringColumn("Tag")
+	rs.AddFloat64Column("X")
+	rs.AddFloat64Column("Y")
+	rs.SetNumRows(12)
+	for row := range 12 {
+		c := row / 4
+		rs.SetString("Tag", row, fmt.Sprintf("C%d", c))
+		rs.SetFloat("X", row, float64(c)+0.5-float64(row%2)) // seed noise +- 0.5
+		rs.SetFloat("Y", row, float64(c))                    // no seed noise
+	}
+	sw := &Sweep{Stats: []string{"X", "Y"}, Results: rs}
+	svs := sw.SeedVariance(0, 0.25)
+	x := svs[0]
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-4 }
+	if x.NCombos != 3 || x.NRuns != 12 || !near(x.Mean, 1) || !near(x.VarSeeds, 1.0/3) || !near(x.VarParams, 0.91667) || !near(x.FracSeeds, 0.26667) {
+		t.Errorf("SeedVariance X: %+v", x)
+	}
+	if !near(x.SEM, 0.288675) || x.SeedsNeeded != 6 {
+		t.Errorf("SeedVariance X SeedsNeeded: %+v", x)
+	}
+	if y := svs[1]; y.VarSeeds != 0 || y.FracSeeds != 0 || y.SeedsNeeded != 1 {
+		t.Errorf("SeedVariance Y: %+v", y)
+	}
+	if x := sw.SeedVariance(0.1, 0)[0]; x.SeedsNeeded != 34 {
+		t.Errorf("SeedVariance absolute SEM: %g", x.SeedsNeeded)
+	}
+	if dt := sw.SeedVarianceTable(0, 0.25); dt.Rows != 2 || dt.Float("SeedsNeeded", 0) != 6 {
+		t.Errorf("SeedVarianceTable: %d rows", dt.Rows)
+	}
+}
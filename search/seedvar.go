@@ -0,0 +1,179 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"math"
+
+	"cogentcore.org/core/tensor/table"
+)
+
+// SeedVar is the decomposition of the variance of a stat across the runs
+// of a Sweep into the variance due to the parameter settings (between the
+// combinations) and the variance due to the random seeds (between the runs
+// of each combination), using the variance components of a one-way random
+// effects analysis of variance, which allows a different number of runs
+// per combination.  It also reports the number of seeds (runs) needed per
+// combination for the mean of each combination to have a target precision.
+type SeedVar struct {
+
+	// Stat is the name of the stat.
+	Stat string
+
+	// NCombos is the number of combinations with at least one valid
+	// (non-NaN) value of the stat.
+	NCombos int
+
+	// NRuns is the total number of runs with a valid value.
+	NRuns int
+
+	// Mean is the grand mean of the stat across all the runs.
+	Mean float64
+
+	// VarParams is the variance of the stat due to the parameters:
+	// the variance of the true means of the combinations.
+	VarParams float64
+
+	// VarSeeds is the variance of the stat due to the random seeds:
+	// the pooled variance of the runs within each combination.
+	VarSeeds float64
+
+	// FracSeeds is the proportion of the total variance due to the seeds:
+	// VarSeeds / (VarParams + VarSeeds).
+	FracSeeds float64
+
+	// SEM is the standard error of the mean of a combination with
+	// the mean number of runs per combination in the sweep.
+	SEM float64
+
+	// TargetSEM is the target standard error of the mean of each
+	// combination used for SeedsNeeded.
+	TargetSEM float64
+
+	// SeedsNeeded is the number of seeds (runs) needed per combination for
+	// the SEM of its mean to be at most the TargetSEM, which is NaN if
+	// the TargetSEM is 0.
+	SeedsNeeded float64
+}
+
+// SeedVariance returns the decomposition of the variance of each of
+// the Stats across the Results runs into parameter and seed variance
+// (see SeedVar), and the number of seeds needed per combination for the
+// standard error of its mean to be at most the given sem, or if sem is 0,
+// relSEM times the standard deviation due to the parameters, so that
+// the differences between the combinations are resolved relative to the
+// seed noise, e.g., 0.25.  Runs are grouped into combinations by Tag.
+func (sw *Sweep) SeedVariance(sem, relSEM float64) []SeedVar {
+	rs := sw.Results
+	if rs == nil {
+		return nil
+	}
+	var groups [][2]int
+	for rrow := 0; rrow < rs.Rows; {
+		tag := rs.StringValue("Tag", rrow)
+		end := rrow + 1
+		for end < rs.Rows && rs.StringValue("Tag", end) == tag {
+			end++
+		}
+		groups = append(groups, [2]int{rrow, end})
+		rrow = end
+	}
+	svs := make([]SeedVar, len(sw.Stats))
+	for si, st := range sw.Stats {
+		sv := &svs[si]
+		sv.Stat = st
+		var ns, means []float64
+		var sum, ssw, sumN2 float64
+		for _, gr := range groups {
+			var n, gsum float64
+			for r := gr[0]; r < gr[1]; r++ {
+				if v := rs.Float(st, r); !math.IsNaN(v) {
+					n++
+					gsum += v
+				}
+			}
+			if n == 0 {
+				continue
+			}
+			m := gsum / n
+			for r := gr[0]; r < gr[1]; r++ {
+				if v := rs.Float(st, r); !math.IsNaN(v) {
+					ssw += (v - m) * (v - m)
+				}
+			}
+			ns = append(ns, n)
+			means = append(means, m)
+			sum += gsum
+			sumN2 += n * n
+		}
+		k := len(ns)
+		sv.NCombos = k
+		nTot := 0.0
+		for _, n := range ns {
+			nTot += n
+		}
+		sv.NRuns = int(nTot)
+		sv.Mean, sv.VarParams, sv.VarSeeds, sv.FracSeeds = math.NaN(), math.NaN(), math.NaN(), math.NaN()
+		sv.SEM, sv.TargetSEM, sv.SeedsNeeded = math.NaN(), math.NaN(), math.NaN()
+		if k == 0 {
+			continue
+		}
+		sv.Mean = sum / nTot
+		if nTot > float64(k) {
+			sv.VarSeeds = ssw / (nTot - float64(k))
+		}
+		if k > 1 && !math.IsNaN(sv.VarSeeds) { // needs multiple runs to separate
+			var ssb float64
+			for i, m := range means {
+				ssb += ns[i] * (m - sv.Mean) * (m - sv.Mean)
+			}
+			msb := ssb / float64(k-1)
+			n0 := (nTot - sumN2/nTot) / float64(k-1) // effective runs per combination
+			sv.VarParams = max((msb-sv.VarSeeds)/n0, 0)
+		}
+		if tot := sv.VarParams + sv.VarSeeds; tot > 0 {
+			sv.FracSeeds = sv.VarSeeds / tot
+		}
+		sv.SEM = math.Sqrt(sv.VarSeeds / (nTot / float64(k)))
+		switch {
+		case sem > 0:
+			sv.TargetSEM = sem
+		case relSEM > 0 && sv.VarParams > 0:
+			sv.TargetSEM = relSEM * math.Sqrt(sv.VarParams)
+		}
+		if sv.TargetSEM > 0 && !math.IsNaN(sv.VarSeeds) {
+			sv.SeedsNeeded = max(math.Ceil(sv.VarSeeds/(sv.TargetSEM*sv.TargetSEM)), 1)
+		}
+	}
+	return svs
+}
+
+// SeedVarianceTable returns the SeedVariance results as a table,
+// with one row per stat.
+func (sw *Sweep) SeedVarianceTable(sem, relSEM float64) *table.Table {
+	svs := sw.SeedVariance(sem, relSEM)
+	dt := table.NewTable()
+	dt.AddStringColumn("Stat")
+	for _, col := range []string{"NCombos", "NRuns"} {
+		dt.AddIntColumn(col)
+	}
+	for _, col := range []string{"Mean", "VarParams", "VarSeeds", "FracSeeds", "SEM", "TargetSEM", "SeedsNeeded"} {
+		dt.AddFloat64Column(col)
+	}
+	dt.SetNumRows(len(svs))
+	for row, sv := range svs {
+		dt.SetString("Stat", row, sv.Stat)
+		dt.SetFloat("NCombos", row, float64(sv.NCombos))
+		dt.SetFloat("NRuns", row, float64(sv.NRuns))
+		dt.SetFloat("Mean", row, sv.Mean)
+		dt.SetFloat("VarParams", row, sv.VarParams)
+		dt.SetFloat("VarSeeds", row, sv.VarSeeds)
+		dt.SetFloat("FracSeeds", row, sv.FracSeeds)
+		dt.SetFloat("SEM", row, sv.SEM)
+		dt.SetFloat("TargetSEM", row, sv.TargetSEM)
+		dt.SetFloat("SeedsNeeded", row, sv.SeedsNeeded)
+	}
+	return dt
+}
@@ -0,0 +1,552 @@
+// Copyright (c) 2024, The Emergent Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"cogentcore.org/core/core"
+	"cogentcore.org/core/tensor/table"
+)
+
+// Factor is one factor of a parameter Sweep, with a list of values
+// to cross with the values of the other factors.
+type Factor struct {
+
+	// Key is either the params Selector:Path of a parameter,
+	// e.g., #Hidden:Layer.Inhib.Layer.Gi, as used in the Params.Network
+	// map of the sim config, for numerical values, or a config arg
+	// starting with -, e.g., -Params.Sheet, for any values,
+	// such as the names of param sheets.
+	Key string
+
+	// Values are the values of the factor.
+	Values []string
+}
+
+// ParseFactor parses a Factor from a string of the form
+// Key=Value1,Value2,..., e.g., #Hidden:Layer.Inhib.Layer.Gi=1.6,1.8,2.0
+// or -Params.Sheet=SmallHip,BigHip
+func ParseFactor(s string) (Factor, error) {
+	var fc Factor
+	key, vals, ok := strings.Cut(s, "=")
+	if !ok || key == "" || vals == "" {
+		return fc, fmt.Errorf("search.ParseFactor: not of form Key=Value1,Value2,...: %s", s)
+	}
+	fc.Key = strings.TrimSpace(key)
+	for _, v := range strings.Split(vals, ",") {
+		fc.Values = append(fc.Values, strings.TrimSpace(v))
+	}
+	return fc, fc.Check()
+}
+
+// IsArg returns true if the factor is a config arg, starting with -,
+// rather than a params Selector:Path.
+func (fc *Factor) IsArg() bool {
+	return strings.HasPrefix(fc.Key, "-")
+}
+
+// Name returns the short name of the factor, used in tags: the last
+// element of the params path or config arg, e.g., Gi or Sheet.
+func (fc *Factor) Name() string {
+	nm := fc.Key
+	if i := strings.LastIndex(nm, "."); i >= 0 {
+		nm = nm[i+1:]
+	}
+	return nm
+}
+
+// Check returns an error if the factor has no values,
+// or non-numerical values for a params path.
+func (fc *Factor) Check() error {
+	if len(fc.Values) == 0 {
+		return fmt.Errorf("search.Factor %s: no values", fc.Key)
+	}
+	if fc.IsArg() {
+		return nil
+	}
+	for _, v := range fc.Values {
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("search.Factor %s: value %q is not a number", fc.Key, v)
+		}
+	}
+	return nil
+}
+
+// Combo is one combination of the values of the Factors of a Sweep.
+type Combo struct {
+
+	// Index is the index of the combination in the full grid
+	// of combinations, in which the last factor varies fastest.
+	Index int
+
+	// Values are the values of each factor.
+	Values []string
+
+	// Tag identifies the combination in file names and logs, as the short
+	// name and value of each factor, separated by _, e.g., Gi1.8_SheetBigHip.
+	Tag string
+
+	// Params are the values of the params path factors, which can be
+	// applied to the network with emer.NetParams.SetNetworkMap,
+	// or passed as the Params.Network config of a sim.
+	Params map[string]any
+
+	// Args are the config args for the config arg factors,
+	// e.g., -Params.Sheet=BigHip
+	Args []string
+}
+
+// ParamsTOML returns the Params as a TOML inline table, in sorted key
+// order, e.g., for passing as a -Params.Network command-line argument.
+func (cb *Combo) ParamsTOML() string {
+	var sp Space
+	var vals []float64
+	keys := make([]string, 0, len(cb.Params))
+	for k := range cb.Params {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		sp = append(sp, Param{Key: k})
+		vals = append(vals, cb.Params[k].(float64))
+	}
+	return sp.TOML(vals)
+}
+
+// SweepFunc runs the sim for one combination of a Sweep, returning the
+// RunStats table, with one row per run (e.g., the run log of the sim).
+// It must be safe to call concurrently if Parallel > 1.
+type SweepFunc func(cb *Combo) (*table.Table, error)
+
+// Sweep runs a sim for combinations of the values of any number of
+// crossed Factors, either all of the combinations in the full grid, or
+// a random sample of them, and aggregates the RunStats across the sweep
+// into one Results table, generalizing the two crossed factors of param
+// sets of the hip_bench TwoFactorRun.
+type Sweep struct {
+
+	// Factors are the factors to cross.
+	Factors []Factor
+
+	// NSamples is the number of combinations to sample at random, without
+	// replacement, from the full grid, or all of them if 0 (or more than
+	// the number of combinations).
+	NSamples int
+
+	// Seed is the random seed for sampling the combinations.
+	Seed int64
+
+	// Parallel is the number of combinations to run in parallel.
+	// If <= 1, they are run in order.
+	Parallel int
+
+	// Stats are the names of the RunStats columns to aggregate,
+	// or all of the numerical columns if empty.
+	Stats []string
+
+	// Results has the RunStats of all the combinations, with one row
+	// per run, with the Combo index, Tag, and each of the Factors
+	// (by Key) as columns, followed by the Stats columns.
+	Results *table.Table
+}
+
+// NCombos returns the number of combinations in the full grid.
+func (sw *Sweep) NCombos() int {
+	n := 1
+	for i := range sw.Factors {
+		n *= len(sw.Factors[i].Values)
+	}
+	return n
+}
+
+// Check returns an error if any of the factors are not valid,
+// or there are no factors.
+func (sw *Sweep) Check() error {
+	if len(sw.Factors) == 0 {
+		return errors.New("search.Sweep: no factors")
+	}
+	var errs []error
+	for i := range sw.Factors {
+		if err := sw.Factors[i].Check(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Combo returns the combination at the given index in the full grid.
+func (sw *Sweep) Combo(index int) *Combo {
+	nf := len(sw.Factors)
+	cb := &Combo{Index: index, Values: make([]string, nf), Params: map[string]any{}}
+	tags := make([]string, nf)
+	rem := index
+	for i := nf - 1; i >= 0; i-- {
+		fc := &sw.Factors[i]
+		v := fc.Values[rem%len(fc.Values)]
+		rem /= len(fc.Values)
+		cb.Values[i] = v
+		tags[i] = strings.NewReplacer(" ", "-", "/", "-").Replace(fc.Name() + v)
+	}
+	for i := range sw.Factors {
+		fc := &sw.Factors[i]
+		if fc.IsArg() {
+			cb.Args = append(cb.Args, fc.Key+"="+cb.Values[i])
+		} else {
+			cb.Params[fc.Key], _ = strconv.ParseFloat(cb.Values[i], 64)
+		}
+	}
+	cb.Tag = strings.Join(tags, "_")
+	return cb
+}
+
+// Combos returns the combinations to run: all of them in the full grid,
+// or a random sample of NSamples of them, in grid order.
+func (sw *Sweep) Combos() []*Combo {
+	n := sw.NCombos()
+	var idxs []int
+	if sw.NSamples <= 0 || sw.NSamples >= n {
+		idxs = make([]int, n)
+		for i := range idxs {
+			idxs[i] = i
+		}
+	} else {
+		rnd := rand.New(rand.NewPCG(uint64(sw.Seed), 0x5eed))
+		used := make(map[int]bool, sw.NSamples)
+		for len(idxs) < sw.NSamples {
+			i := rnd.IntN(n)
+			if !used[i] {
+				used[i] = true
+				idxs = append(idxs, i)
+			}
+		}
+		slices.Sort(idxs)
+	}
+	cbs := make([]*Combo, len(idxs))
+	for i, idx := range idxs {
+		cbs[i] = sw.Combo(idx)
+	}
+	return cbs
+}
+
+// Run runs the sim for each of the Combos, using the given function,
+// up to Parallel at a time, and returns the Results table.  Combinations
+// that return an error are left out of the Results, and the errors
+// are returned.
+func (sw *Sweep) Run(run SweepFunc) (*table.Table, error) {
+	if err := sw.Check(); err != nil {
+		return nil, err
+	}
+	cbs := sw.Combos()
+	rsts := make([]*table.Table, len(cbs))
+	errs := make([]error, len(cbs))
+	par := max(sw.Parallel, 1)
+	sem := make(chan struct{}, par)
+	var wg sync.WaitGroup
+	for i, cb := range cbs {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			rsts[i], errs[i] = run(cb)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("search.Sweep: %s: %w", cb.Tag, errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+	if len(sw.Stats) == 0 {
+		for i := range rsts {
+			if errs[i] == nil && rsts[i] != nil {
+				sw.Stats = numericColumns(rsts[i])
+				break
+			}
+		}
+	}
+	dt := table.NewTable()
+	dt.AddIntColumn("Combo")
+	dt.AddStringColumn("Tag")
+	for i := range sw.Factors {
+		if sw.Factors[i].IsArg() {
+			dt.AddStringColumn(sw.Factors[i].Key)
+		} else {
+			dt.AddFloat64Column(sw.Factors[i].Key)
+		}
+	}
+	for _, st := range sw.Stats {
+		dt.AddFloat64Column(st)
+	}
+	sw.Results = dt
+	for i, cb := range cbs {
+		rs := rsts[i]
+		if errs[i] != nil || rs == nil {
+			continue
+		}
+		for rrow := range rs.Rows {
+			row := dt.Rows
+			dt.SetNumRows(row + 1)
+			dt.SetFloat("Combo", row, float64(cb.Index))
+			dt.SetString("Tag", row, cb.Tag)
+			for fi := range sw.Factors {
+				fc := &sw.Factors[fi]
+				if fc.IsArg() {
+					dt.SetString(fc.Key, row, cb.Values[fi])
+				} else {
+					dt.SetFloat(fc.Key, row, cb.Params[fc.Key].(float64))
+				}
+			}
+			for _, st := range sw.Stats {
+				v := math.NaN()
+				if _, err := rs.ColumnByName(st); err == nil {
+					v = rs.Float(st, rrow)
+				}
+				dt.SetFloat(st, row, v)
+			}
+		}
+	}
+	return dt, errors.Join(errs...)
+}
+
+// Summary returns a table of the mean and standard error of the mean
+// (SEM) of each of the Stats across the runs of each combination in the
+// Results, with the Combo, Tag, and Factors columns, an N column with the
+// number of runs, and <stat> and <stat>_SEM columns.  NaN values are
+// excluded from the stats.
+func (sw *Sweep) Summary() *table.Table {
+	rs := sw.Results
+	if rs == nil {
+		return nil
+	}
+	dt := table.NewTable()
+	dt.AddIntColumn("Combo")
+	dt.AddStringColumn("Tag")
+	for i := range sw.Factors {
+		if sw.Factors[i].IsArg() {
+			dt.AddStringColumn(sw.Factors[i].Key)
+		} else {
+			dt.AddFloat64Column(sw.Factors[i].Key)
+		}
+	}
+	dt.AddIntColumn("N")
+	for _, st := range sw.Stats {
+		dt.AddFloat64Column(st)
+		dt.AddFloat64Column(st + "_SEM")
+	}
+	for rrow := 0; rrow < rs.Rows; {
+		tag := rs.StringValue("Tag", rrow)
+		end := rrow + 1
+		for end < rs.Rows && rs.StringValue("Tag", end) == tag {
+			end++
+		}
+		row := dt.Rows
+		dt.SetNumRows(row + 1)
+		dt.SetFloat("Combo", row, rs.Float("Combo", rrow))
+		dt.SetString("Tag", row, tag)
+		for i := range sw.Factors {
+			key := sw.Factors[i].Key
+			if sw.Factors[i].IsArg() {
+				dt.SetString(key, row, rs.StringValue(key, rrow))
+			} else {
+				dt.SetFloat(key, row, rs.Float(key, rrow))
+			}
+		}
+		dt.SetFloat("N", row, float64(end-rrow))
+		for _, st := range sw.Stats {
+			var n, sum, sumSq float64
+			for r := rrow; r < end; r++ {
+				v := rs.Float(st, r)
+				if math.IsNaN(v) {
+					continue
+				}
+				n++
+				sum += v
+				sumSq += v * v
+			}
+			mean, sem := math.NaN(), math.NaN()
+			if n > 0 {
+				mean = sum / n
+				sem = 0
+			}
+			if n > 1 {
+				vr := max((sumSq-n*mean*mean)/(n-1), 0)
+				sem = math.Sqrt(vr / n)
+			}
+			dt.SetFloat(st, row, mean)
+			dt.SetFloat(st+"_SEM", row, sem)
+		}
+		rrow = end
+	}
+	return dt
+}
+
+// numericColumns returns the names of the numerical columns of the table.
+func numericColumns(dt *table.Table) []string {
+	var cols []string
+	for i, col := range dt.Columns {
+		if !col.IsString() {
+			cols = append(cols, dt.ColumnNames[i])
+		}
+	}
+	return cols
+}
+
+// SweepCommand runs a sim as a separate process for each combination
+// of a Sweep, in its own directory named by the combination Tag,
+// and reads the resulting run log, providing a SweepFunc.
+// The sim must run without the GUI and save its run log, e.g.,
+// for the ra25 example:
+//
+//	ra25 -nogui -Params.Tag={tag} -Params.Network={params} {args}
+//
+// A WallSec column is added to the run log (unless already present), with
+// the wall-clock time of the process divided by the number of runs, e.g.,
+// for comparing configurations on speed as well as accuracy (see Goal).
+type SweepCommand struct {
+
+	// Command is the sim executable and its arguments, where the string
+	// {tag} is replaced by the combination Tag, {params} by the params
+	// path factors as a TOML inline table (see Combo.ParamsTOML),
+	// and an {args} argument by the config args of the combination.
+	Command []string
+
+	// Dir is the directory in which the run directories are made.
+	Dir string
+
+	// LogSuffix is the suffix of the log file to read from the run
+	// directory, excluding testing logs (*_tst<suffix>).
+	LogSuffix string
+
+	// Keep keeps the run directories, which are otherwise removed
+	// after reading the log.
+	Keep bool
+
+	// nEvals is the number of Objective evaluations so far,
+	// for naming their run directories.
+	nEvals atomic.Int64
+}
+
+// NewSweepCommand returns a new SweepCommand for the given command,
+// making the run directories in given dir.
+func NewSweepCommand(dir string, command ...string) *SweepCommand {
+	return &SweepCommand{Command: command, Dir: dir, LogSuffix: "_run.tsv"}
+}
+
+// Objective returns an Objective for a Search over the given Space, which
+// runs the command with the parameter values (named with an eval_<n> tag)
+// and returns the mean of the given column of the run log across its rows
+// (e.g., runs), negated if maximize is true (e.g., for a memory score),
+// so that it is minimized.  It is safe to call concurrently, for
+// Search.MinimizeParallel.
+func (sc *SweepCommand) Objective(sp Space, column string, maximize bool) Objective {
+	return sc.LogObjective(sp, func(dt *table.Table) (float64, error) {
+		if _, err := dt.ColumnByName(column); err != nil {
+			return 0, fmt.Errorf("search.SweepCommand: %w", err)
+		}
+		var sum float64
+		for row := range dt.Rows {
+			sum += dt.Float(column, row)
+		}
+		mean := sum / float64(dt.Rows)
+		if maximize {
+			return -mean, nil
+		}
+		return mean, nil
+	})
+}
+
+// LogObjective returns an Objective for a Search over the given Space,
+// which runs the command with the parameter values (named with an
+// eval_<n> tag, with the config arg parameters as its {args}), and
+// returns the loss computed by the given function from the run log,
+// e.g., combining several of its columns.  It is safe to call
+// concurrently, for Search.MinimizeParallel.
+func (sc *SweepCommand) LogObjective(sp Space, loss func(dt *table.Table) (float64, error)) Objective {
+	return func(vals []float64) (float64, error) {
+		cb := &Combo{Index: -1, Params: sp.Map(vals), Args: sp.Args(vals)}
+		cb.Tag = fmt.Sprintf("eval_%03d", sc.nEvals.Add(1)-1)
+		dt, err := sc.Run(cb)
+		if err != nil {
+			return 0, err
+		}
+		if dt.Rows == 0 {
+			return 0, fmt.Errorf("search.SweepCommand: no rows in the log for %s", cb.Tag)
+		}
+		return loss(dt)
+	}
+}
+
+// Run runs the command for the given combination, returning the run log.
+// It is a SweepFunc.
+func (sc *SweepCommand) Run(cb *Combo) (*table.Table, error) {
+	if len(sc.Command) == 0 {
+		return nil, errors.New("search.SweepCommand: no Command")
+	}
+	rdir := filepath.Join(sc.Dir, cb.Tag)
+	if err := os.MkdirAll(rdir, 0755); err != nil {
+		return nil, err
+	}
+	if !sc.Keep {
+		defer os.RemoveAll(rdir)
+	}
+	ptoml := cb.ParamsTOML()
+	var args []string
+	for _, a := range sc.Command {
+		if a == "{args}" {
+			args = append(args, cb.Args...)
+			continue
+		}
+		a = strings.ReplaceAll(a, "{tag}", cb.Tag)
+		args = append(args, strings.ReplaceAll(a, "{params}", ptoml))
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = rdir
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	st := time.Now()
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("search.SweepCommand: %s: %w\n%s", strings.Join(args, " "), err, out.String())
+	}
+	ents, err := os.ReadDir(rdir)
+	if err != nil {
+		return nil, err
+	}
+	var logs []string
+	for _, ent := range ents {
+		nm := ent.Name()
+		if !ent.IsDir() && strings.HasSuffix(nm, sc.LogSuffix) && !strings.HasSuffix(nm, "_tst"+sc.LogSuffix) {
+			logs = append(logs, filepath.Join(rdir, nm))
+		}
+	}
+	if len(logs) == 0 {
+		return nil, fmt.Errorf("search.SweepCommand: no *%s log file saved by: %s", sc.LogSuffix, strings.Join(args, " "))
+	}
+	slices.Sort(logs)
+	dt := table.NewTable()
+	if err := dt.OpenCSV(core.Filename(logs[0]), table.Tab); err != nil {
+		return nil, err
+	}
+	if _, err := dt.ColumnByName("WallSec"); err != nil && dt.Rows > 0 {
+		wc := dt.AddFloat64Column("WallSec")
+		sec := time.Since(st).Seconds() / float64(dt.Rows)
+		for row := range dt.Rows {
+			wc.SetFloat1D(row, sec)
+		}
+	}
+	return dt, nil
+}
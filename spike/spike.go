@@ -7,7 +7,7 @@
 package spike
 
 import (
-	"cogentcore.org/core/math32"
+	"github.com/emer/leabra/v2/fmath"
 	"github.com/emer/leabra/v2/leabra"
 )
 
@@ -38,7 +38,7 @@ func (sk *ActParams) CopyFromAct(act *leabra.ActParams) {
 
 func (sk *ActParams) SpikeVmFromG(nrn *leabra.Neuron) {
 	updtVm := true
-	if sk.Spike.Tr > 0 && nrn.ISI >= 0 && nrn.ISI < float32(sk.Spike.Tr) {
+	if sk.Spike.Tr > 0 && nrn.ISI >= 0 && nrn.ISI < fmath.Float(sk.Spike.Tr) {
 		updtVm = false // don't update the spiking vm during refract
 	}
 
@@ -46,7 +46,7 @@ func (sk *ActParams) SpikeVmFromG(nrn *leabra.Neuron) {
 	if updtVm {
 		ge := nrn.Ge * sk.Gbar.E
 		gi := nrn.Gi * sk.Gbar.I
-		gk := sk.Gbar.K * (nrn.GknaFast + nrn.GknaMed + nrn.GknaSlow)
+		gk := sk.Gbar.K * (nrn.GknaFast + nrn.GknaMed + nrn.GknaSlow + sk.AHP.Gk(nrn.Gahp))
 		nrn.Gk = gk
 		vmEff := nrn.Vm
 		// midpoint method: take a half-step in vmEff
@@ -56,7 +56,7 @@ func (sk *ActParams) SpikeVmFromG(nrn *leabra.Neuron) {
 		// add spike current if relevant
 		if sk.Spike.Exp {
 			inet2 += sk.Gbar.L * sk.Spike.ExpSlope *
-				math32.Exp((vmEff-sk.XX1.Thr)/sk.Spike.ExpSlope)
+				fmath.Exp((vmEff-sk.XX1.Thr)/sk.Spike.ExpSlope)
 		}
 		nwVm += sk.Dt.VmDt * inet2
 		nrn.Inet = inet2
@@ -71,7 +71,7 @@ func (sk *ActParams) SpikeVmFromG(nrn *leabra.Neuron) {
 // SpikeActFromVm computes the discrete spiking activation
 // from membrane potential Vm
 func (sk *ActParams) SpikeActFromVm(nrn *leabra.Neuron) {
-	var thr float32
+	var thr fmath.Float
 	if sk.Spike.Exp {
 		thr = sk.Spike.ExpThr
 	} else {
@@ -107,6 +107,9 @@ func (sk *ActParams) SpikeActFromVm(nrn *leabra.Neuron) {
 	if sk.KNa.On {
 		sk.KNa.GcFromSpike(&nrn.GknaFast, &nrn.GknaMed, &nrn.GknaSlow, nrn.Spike > .5)
 	}
+	if sk.AHP.On {
+		sk.AHP.GFromSpike(&nrn.Gahp, nrn.Spike > .5)
+	}
 }
 
 // SpikeParams contains spiking activation function params.
@@ -117,25 +120,25 @@ type SpikeParams struct {
 	Exp bool `default:"false"`
 
 	// slope in Vm (2 mV = .02 in normalized units) for extra exponential excitatory current that drives Vm rapidly upward for spiking as it gets past its nominal firing threshold (Thr) -- nicely captures the Hodgkin Huxley dynamics of Na and K channels -- uses Brette & Gurstner 2005 AdEx formulation -- a value of 0 disables this mechanism
-	ExpSlope float32 `default:"0.02"`
+	ExpSlope fmath.Float `default:"0.02"`
 
 	// membrane potential threshold for actually triggering a spike when using the exponential mechanism
-	ExpThr float32 `default:"1.2"`
+	ExpThr fmath.Float `default:"1.2"`
 
 	// post-spiking membrane potential to reset to, produces refractory effect if lower than VmInit -- 0.30 is appropriate biologically based value for AdEx (Brette & Gurstner, 2005) parameters
-	VmR float32 `default:"0.3,0,0.15"`
+	VmR fmath.Float `default:"0.3,0,0.15"`
 
 	// post-spiking explicit refractory period, in cycles -- prevents Vm updating for this number of cycles post firing
 	Tr int `default:"3"`
 
 	// for translating spiking interval (rate) into rate-code activation equivalent (and vice-versa, for clamped layers), what is the maximum firing rate associated with a maximum activation value (max act is typically 1.0 -- depends on act_range)
-	MaxHz float32 `default:"180" min:"1"`
+	MaxHz fmath.Float `default:"180" min:"1"`
 
 	// constant for integrating the spiking interval in estimating spiking rate
-	RateTau float32 `default:"5" min:"1"`
+	RateTau fmath.Float `default:"5" min:"1"`
 
 	// rate = 1 / tau
-	RateDt float32 `display:"-"`
+	RateDt fmath.Float `display:"-"`
 }
 
 func (sk *SpikeParams) Defaults() {
@@ -164,7 +167,7 @@ func (sk *SpikeParams) ShouldDisplay(field string) bool {
 
 // ActToISI compute spiking interval from a given rate-coded activation,
 // based on time increment (.001 = 1msec default), Act.Dt.Integ
-func (sk *SpikeParams) ActToISI(act, timeInc, integ float32) float32 {
+func (sk *SpikeParams) ActToISI(act, timeInc, integ fmath.Float) fmath.Float {
 	if act == 0 {
 		return 0
 	}
@@ -172,7 +175,7 @@ func (sk *SpikeParams) ActToISI(act, timeInc, integ float32) float32 {
 }
 
 // ActFromISI computes rate-code activation from estimated spiking interval
-func (sk *SpikeParams) ActFromISI(isi, timeInc, integ float32) float32 {
+func (sk *SpikeParams) ActFromISI(isi, timeInc, integ fmath.Float) fmath.Float {
 	if isi <= 0 {
 		return 0
 	}
@@ -181,7 +184,7 @@ func (sk *SpikeParams) ActFromISI(isi, timeInc, integ float32) float32 {
 }
 
 // AvgFromISI updates spiking ISI from current isi interval value
-func (sk *SpikeParams) AvgFromISI(avg *float32, isi float32) {
+func (sk *SpikeParams) AvgFromISI(avg *fmath.Float, isi fmath.Float) {
 	if *avg <= 0 {
 		*avg = isi
 	} else if isi < 0.8**avg {